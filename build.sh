@@ -8,6 +8,7 @@ declare -r -a GO_PACKAGES_TEST=(
 
 declare -r -a GO_PACKAGES_ALL=(
     "./cmd/..."
+    "./hack/..."
     "./pkg/..."
     "${GO_PACKAGES_TEST[@]}"
 )
@@ -49,6 +50,9 @@ function main() {
     banner1 "go build"
     go build "${GO_PACKAGES_ALL[@]}" || die "" "FAILED"
 
+    banner1 "verify API docs"
+    go run ./hack/apidocs-gen -verify || die "" "API docs outdated, run 'hack/update-codegen.sh --gen-apidocs'"
+
     banner1 "go vet"
     (
         local rc=0
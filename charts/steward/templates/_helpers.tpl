@@ -46,6 +46,34 @@ The component label for the tenant controller.
 app.kubernetes.io/component: tenant-controller
 {{- end -}}
 
+{{/*
+The component label for the admission webhook.
+*/}}
+{{- define "steward.webhook.componentLabel" -}}
+app.kubernetes.io/component: webhook
+{{- end -}}
+
+{{/*
+The component label for the trigger server.
+*/}}
+{{- define "steward.trigger.componentLabel" -}}
+app.kubernetes.io/component: trigger
+{{- end -}}
+
+{{/*
+The component label for the API gateway.
+*/}}
+{{- define "steward.gateway.componentLabel" -}}
+app.kubernetes.io/component: gateway
+{{- end -}}
+
+{{/*
+The name of the service of the admission webhook.
+*/}}
+{{- define "steward.webhook.serviceName" -}}
+steward-webhook
+{{- end -}}
+
 {{/*
 The additional labels for the service monitors.
 */}}
@@ -55,6 +83,91 @@ The additional labels for the service monitors.
 {{- end -}}
 {{- end -}}
 
+{{/*
+The URL scheme of the metrics servers of the controllers.
+*/}}
+{{- define "steward.metrics.scheme" -}}
+{{- if .Values.metrics.tls.secretName -}}
+https
+{{- else -}}
+http
+{{- end -}}
+{{- end -}}
+
+{{/*
+The command line arguments of the controllers configuring the metrics
+server.
+*/}}
+{{- define "steward.metrics.args" -}}
+- {{ printf "-metrics-port=%d" ( .Values.metrics.port | int ) | quote }}
+{{- if .Values.metrics.tls.secretName }}
+- "-metrics-tls-cert-file=/etc/steward/metrics-tls/tls.crt"
+- "-metrics-tls-key-file=/etc/steward/metrics-tls/tls.key"
+{{- end }}
+{{- if .Values.metrics.auth.bearerTokenSecret.name }}
+- "-metrics-bearer-token-file=/etc/steward/metrics-auth/token"
+{{- end }}
+{{- if .Values.metrics.profiling }}
+- "-metrics-enable-profiling=true"
+{{- end }}
+{{- end -}}
+
+{{/*
+The volume mounts of the controller containers for the metrics server.
+*/}}
+{{- define "steward.metrics.volumeMounts" -}}
+{{- if .Values.metrics.tls.secretName }}
+- name: metrics-tls
+  mountPath: /etc/steward/metrics-tls
+  readOnly: true
+{{- end }}
+{{- if .Values.metrics.auth.bearerTokenSecret.name }}
+- name: metrics-auth
+  mountPath: /etc/steward/metrics-auth
+  readOnly: true
+{{- end }}
+{{- end -}}
+
+{{/*
+The volumes of the controller pods for the metrics server.
+*/}}
+{{- define "steward.metrics.volumes" -}}
+{{- with .Values.metrics.tls.secretName }}
+- name: metrics-tls
+  secret:
+    secretName: {{ . | quote }}
+{{- end }}
+{{- with .Values.metrics.auth.bearerTokenSecret }}
+{{- if .name }}
+- name: metrics-auth
+  secret:
+    secretName: {{ .name | quote }}
+    items:
+    - key: {{ .key | quote }}
+      path: token
+{{- end }}
+{{- end }}
+{{- end -}}
+
+{{/*
+The endpoint of the service monitors of the controllers.
+*/}}
+{{- define "steward.serviceMonitors.endpoint" -}}
+- port: http-metrics
+  scheme: {{ include "steward.metrics.scheme" . }}
+  {{- with .Values.metrics.serviceMonitors.tlsConfig }}
+  tlsConfig:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+  {{- with .Values.metrics.auth.bearerTokenSecret }}
+  {{- if .name }}
+  bearerTokenSecret:
+    name: {{ .name | quote }}
+    key: {{ .key | quote }}
+  {{- end }}
+  {{- end }}
+{{- end -}}
+
 {{/*
 The name of the pod security policy for the run controller.
 */}}
@@ -66,6 +179,39 @@ The name of the pod security policy for the run controller.
 {{- end -}}
 {{- end -}}
 
+{{/*
+The name of the pod security policy for the admission webhook.
+*/}}
+{{- define "steward.webhook.podSecurityPolicyName" -}}
+{{- if .Values.webhook.podSecurityPolicyName -}}
+{{- .Values.webhook.podSecurityPolicyName -}}
+{{- else -}}
+{{- include "steward.controllers.podSecurityPolicyName.builtin" . -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+The name of the pod security policy for the trigger server.
+*/}}
+{{- define "steward.trigger.podSecurityPolicyName" -}}
+{{- if .Values.trigger.podSecurityPolicyName -}}
+{{- .Values.trigger.podSecurityPolicyName -}}
+{{- else -}}
+{{- include "steward.controllers.podSecurityPolicyName.builtin" . -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+The name of the pod security policy for the API gateway.
+*/}}
+{{- define "steward.gateway.podSecurityPolicyName" -}}
+{{- if .Values.gateway.podSecurityPolicyName -}}
+{{- .Values.gateway.podSecurityPolicyName -}}
+{{- else -}}
+{{- include "steward.controllers.podSecurityPolicyName.builtin" . -}}
+{{- end -}}
+{{- end -}}
+
 {{/*
 The name of the pod security policy for the tenant controller.
 */}}
@@ -110,7 +256,7 @@ pod security policy for Steward controllers, otherwise resolves
 to the empty string.
 */}}
 {{- define "steward.controllers.generatePodSecurityPolicy" -}}
-{{- if not (and .Values.tenantController.podSecurityPolicyName .Values.runController.podSecurityPolicyName) -}}
+{{- if or (not (and .Values.tenantController.podSecurityPolicyName .Values.runController.podSecurityPolicyName .Values.webhook.podSecurityPolicyName)) (and .Values.trigger.enabled (not .Values.trigger.podSecurityPolicyName)) (and .Values.gateway.enabled (not .Values.gateway.podSecurityPolicyName)) -}}
 true
 {{- end -}}
 {{- end -}}
@@ -125,3 +271,35 @@ to the empty string.
 true
 {{- end -}}
 {{- end -}}
+
+{{/*
+The reference of a Steward component image.
+
+Expects dot to be a list with two entries:
+
+1. the original dot providing .Values and so on
+2. the image values with fields `repository` and `tag`
+
+In an air-gapped installation the registry host of the repository is
+replaced by `airGapped.mirrorRegistry` and the tag must contain a digest.
+*/}}
+{{- define "steward.image" -}}
+{{- $root := first . -}}
+{{- $image := last . -}}
+{{- if $root.Values.airGapped.enabled -}}
+{{- if not ( contains "@" $image.tag ) -}}
+{{- fail ( printf "airGapped.enabled requires images to be referenced by digest, but image %q has tag %q" $image.repository $image.tag ) -}}
+{{- end -}}
+{{- $repository := regexReplaceAll "^([^/]*[.:][^/]*|localhost)/" $image.repository "" -}}
+{{- printf "%s/%s:%s" ( trimSuffix "/" ( include "steward.airGapped.mirrorRegistry" $root ) ) $repository $image.tag -}}
+{{- else -}}
+{{- printf "%s:%s" $image.repository $image.tag -}}
+{{- end -}}
+{{- end -}}
+
+{{/*
+The mirror registry of an air-gapped installation.
+*/}}
+{{- define "steward.airGapped.mirrorRegistry" -}}
+{{- required "airGapped.mirrorRegistry must be set if airGapped.enabled is true" .Values.airGapped.mirrorRegistry -}}
+{{- end -}}
@@ -74,7 +74,7 @@ spec:
       containers:
       - name: kubectl
         {{- with .Values.hooks.images.kubectl }}
-        image: {{ printf "%s:%s" .repository .tag | quote }}
+        image: {{ include "steward.image" ( list ( first $ ) . ) | quote }}
         imagePullPolicy: {{ .pullPolicy | quote }}
         {{- end }}
         env:
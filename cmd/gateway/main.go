@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/gateway"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// readHeaderTimeout is the maximum time to read the header of
+	// an HTTP request.
+	readHeaderTimeout = 10 * time.Second
+)
+
+var (
+	port        int
+	tokensFile  string
+	tlsCertFile string
+	tlsKeyFile  string
+	kubeconfig  string
+	instanceID  string
+	burst, qps  int
+)
+
+func init() {
+	klog.InitFlags(nil)
+
+	flag.IntVar(
+		&port,
+		"port",
+		8080,
+		"The TCP port number the gateway listens on.",
+	)
+	flag.StringVar(
+		&tokensFile,
+		"tokens-file",
+		"/etc/steward-gateway/tokens/tokens.yaml",
+		"The path to the file defining the API tokens of clients and the namespaces they may access.",
+	)
+	flag.StringVar(
+		&tlsCertFile,
+		"tls-cert-file",
+		"",
+		"The path to the file containing the PEM-encoded TLS server certificate."+
+			" If not specified or empty, the gateway serves plain HTTP, e.g. behind an ingress terminating TLS.",
+	)
+	flag.StringVar(
+		&tlsKeyFile,
+		"tls-key-file",
+		"",
+		"The path to the file containing the PEM-encoded TLS server private key.",
+	)
+	flag.StringVar(
+		&kubeconfig,
+		"kubeconfig",
+		"",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, assume running in-cluster.",
+	)
+	flag.StringVar(
+		&instanceID,
+		"instance-id",
+		"",
+		"The identifier of the Steward instance. If set, created pipeline runs are labelled with"+
+			" 'steward.sap.com/instance-id=<instance-id>'.",
+	)
+	flag.IntVar(
+		&burst,
+		"burst",
+		10,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting.",
+	)
+	flag.IntVar(
+		&qps,
+		"qps",
+		5,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting.",
+	)
+
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	// prevent credentials from leaking into logs
+	klog.SetLogFilter(sanitize.LogFilter{})
+
+	var config *rest.Config
+	var err error
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		klog.Exitf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.QPS = float32(qps)
+	config.Burst = burst
+	config.UserAgent = k8s.UserAgent("gateway")
+	factory := k8s.NewClientFactoryWithoutResync(config, k8s.ClientFactoryOpts{InstanceID: instanceID})
+	if factory == nil {
+		klog.Exitf("failed to create Kubernetes clients")
+	}
+
+	file, err := os.Open(tokensFile)
+	if err != nil {
+		klog.Exitf("failed to open tokens file: %s", err.Error())
+	}
+	tokens, err := gateway.LoadTokens(file)
+	file.Close()
+	if err != nil {
+		klog.Exitf("failed to load tokens file %q: %s", tokensFile, err.Error())
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.Handle(gateway.APIPath, gateway.NewServer(factory, tokens))
+	serveMux.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           serveMux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	if tlsCertFile != "" {
+		klog.V(2).Infof("Serving API gateway on https://0.0.0.0:%d", port)
+		err = server.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+	} else {
+		klog.V(2).Infof("Serving API gateway on http://0.0.0.0:%d", port)
+		err = server.ListenAndServe()
+	}
+	if err != nil {
+		klog.Fatalf("Error running API gateway: %s", err.Error())
+	}
+}
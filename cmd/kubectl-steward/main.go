@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runcli"
+	"github.com/SAP/stewardci-core/pkg/signals"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	usage = `Usage: kubectl steward <command> [options]
+
+Manages Steward pipeline runs. Installed in the PATH, the binary can be
+used as kubectl plugin.
+
+Commands:
+  submit   Create a pipeline run from a YAML file or a local Jenkinsfile
+  status   Print the state of a pipeline run, optionally until it finishes
+  logs     Print the log of the Jenkinsfile Runner of a pipeline run
+  abort    Abort a pipeline run
+  list     List the pipeline runs of a namespace or tenant
+
+Run 'kubectl steward <command> -h' for the options of a command.
+`
+)
+
+// commonFlags are the flags of all commands.
+type commonFlags struct {
+	kubeconfig string
+	namespace  string
+	tenant     string
+}
+
+func (f *commonFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.kubeconfig, "kubeconfig", "",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, the default loading rules of kubectl apply.")
+	flags.StringVar(&f.namespace, "namespace", "",
+		"The namespace of the pipeline runs. If not specified or empty, the namespace of the current kubeconfig context is used.")
+	flags.StringVar(&f.namespace, "n", "", "Shorthand for -namespace.")
+	flags.StringVar(&f.tenant, "tenant", "",
+		"The name of a tenant in the namespace. If set, the tenant namespace of the tenant is used instead of the namespace.")
+}
+
+// connect creates the client factory and resolves the namespace to use.
+func (f *commonFlags) connect(ctx context.Context) (k8s.ClientFactory, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = f.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.UserAgent = k8s.UserAgent("kubectl-steward")
+	factory := k8s.NewClientFactoryWithoutResync(config, k8s.ClientFactoryOpts{})
+	if factory == nil {
+		return nil, "", fmt.Errorf("failed to create Kubernetes clients")
+	}
+
+	namespace := f.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+	if f.tenant != "" {
+		if namespace, err = runcli.TenantNamespace(ctx, factory, namespace, f.tenant); err != nil {
+			return nil, "", err
+		}
+	}
+	return factory, namespace, nil
+}
+
+// argsFlag collects repeated `-arg name=value` flags.
+type argsFlag map[string]string
+
+func (a argsFlag) String() string {
+	return ""
+}
+
+func (a argsFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("expected <name>=<value>")
+	}
+	a[parts[0]] = parts[1]
+	return nil
+}
+
+func main() {
+	defer klog.Flush()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	commands := map[string]func(context.Context, []string, io.Writer) error{
+		"submit": submit,
+		"status": status,
+		"logs":   logs,
+		"abort":  abort,
+		"list":   list,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopCh := signals.SetupShutdownSignalHandler()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	if err := command(ctx, os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage+"\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// submit implements command `submit`.
+func submit(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlagSet("submit", "Usage: kubectl steward submit [options] (-f <file> | -jenkinsfile <file>)\n\n"+
+		"Creates a pipeline run defined in a YAML or JSON file (-f) or executing a local\n"+
+		"Jenkinsfile (-jenkinsfile). For a local Jenkinsfile, the pipeline repository is\n"+
+		"the remote of the Git checkout containing the file and the revision is the commit\n"+
+		"checked out, which must have been pushed.\n")
+	var common commonFlags
+	common.register(flags)
+	file := flags.String("f", "", "The path to a file containing the pipeline run.")
+	jenkinsfile := flags.String("jenkinsfile", "", "The path to a Jenkinsfile in a local Git checkout.")
+	remote := flags.String("remote", "origin", "The Git remote of the checkout used with -jenkinsfile.")
+	repoAuthSecret := flags.String("repo-auth-secret", "",
+		"The name of the secret for authentication when cloning the pipeline repository (spec.jenkinsFile.repoAuthSecret).")
+	pipelineArgs := argsFlag{}
+	flags.Var(pipelineArgs, "arg", "A pipeline argument <name>=<value> (spec.args). Can be repeated.")
+	watch := flags.Bool("watch", false, "Print the state of the pipeline run until it finishes.")
+	flags.Parse(args)
+	if (*file == "") == (*jenkinsfile == "") || flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var run *api.PipelineRun
+	if *file != "" {
+		input, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+		if run, err = runcli.LoadPipelineRun(input); err != nil {
+			return err
+		}
+	} else {
+		checkout, err := runcli.LocateInGitCheckout(*jenkinsfile, *remote)
+		if err != nil {
+			return err
+		}
+		run = checkout.PipelineRun()
+	}
+	if *repoAuthSecret != "" {
+		run.Spec.JenkinsFile.RepoAuthSecret = *repoAuthSecret
+	}
+	if len(pipelineArgs) > 0 && run.Spec.Args == nil {
+		run.Spec.Args = map[string]string{}
+	}
+	for name, value := range pipelineArgs {
+		run.Spec.Args[name] = value
+	}
+
+	factory, namespace, err := common.connect(ctx)
+	if err != nil {
+		return err
+	}
+	created, err := runcli.Submit(ctx, factory, namespace, run)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "pipelinerun %s/%s created\n", created.GetNamespace(), created.GetName())
+	if *watch {
+		return runcli.Status(ctx, factory, namespace, created.GetName(), true, out)
+	}
+	return nil
+}
+
+// status implements command `status`.
+func status(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlagSet("status", "Usage: kubectl steward status [options] <name>\n\n"+
+		"Prints the state of a pipeline run. With -watch, a line is printed for each\n"+
+		"change of the state until the pipeline run is finished.\n")
+	var common commonFlags
+	common.register(flags)
+	watch := flags.Bool("watch", false, "Print the state of the pipeline run until it finishes.")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	factory, namespace, err := common.connect(ctx)
+	if err != nil {
+		return err
+	}
+	return runcli.Status(ctx, factory, namespace, flags.Arg(0), *watch, out)
+}
+
+// logs implements command `logs`.
+func logs(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlagSet("logs", "Usage: kubectl steward logs [options] <name>\n\n"+
+		"Prints the log of the Jenkinsfile Runner of a pipeline run. The log is only\n"+
+		"available while the run namespace of the pipeline run exists. For pipeline runs\n"+
+		"cleaned up already, the location of the archived log is printed, if any.\n")
+	var common commonFlags
+	common.register(flags)
+	follow := flags.Bool("follow", false, "Stream the log until the Jenkinsfile Runner terminates.")
+	flags.BoolVar(follow, "f", false, "Shorthand for -follow.")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	factory, namespace, err := common.connect(ctx)
+	if err != nil {
+		return err
+	}
+	return runcli.Logs(ctx, factory, namespace, flags.Arg(0), *follow, out)
+}
+
+// abort implements command `abort`.
+func abort(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlagSet("abort", "Usage: kubectl steward abort [options] <name>\n\n"+
+		"Requests the abortion of a pipeline run.\n")
+	var common commonFlags
+	common.register(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	factory, namespace, err := common.connect(ctx)
+	if err != nil {
+		return err
+	}
+	if err := runcli.Abort(ctx, factory, namespace, flags.Arg(0)); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "pipelinerun %s/%s abort requested\n", namespace, flags.Arg(0))
+	return nil
+}
+
+// list implements command `list`.
+func list(ctx context.Context, args []string, out io.Writer) error {
+	flags := newFlagSet("list", "Usage: kubectl steward list [options]\n\n"+
+		"Lists the pipeline runs of a namespace or tenant, oldest first.\n")
+	var common commonFlags
+	common.register(flags)
+	selector := flags.String("selector", "", "A label selector restricting the listed pipeline runs.")
+	flags.StringVar(selector, "l", "", "Shorthand for -selector.")
+	allNamespaces := flags.Bool("all-namespaces", false, "List the pipeline runs of all namespaces.")
+	flags.Parse(args)
+	if flags.NArg() != 0 || (*allNamespaces && common.tenant != "") {
+		flags.Usage()
+		os.Exit(2)
+	}
+	factory, namespace, err := common.connect(ctx)
+	if err != nil {
+		return err
+	}
+	if *allNamespaces {
+		namespace = ""
+	}
+	return runcli.List(ctx, factory, namespace, *selector, time.Now(), out)
+}
@@ -1,13 +1,42 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"net/http"
+	"os"
+	"strings"
 	"time"
+	_ "time/tzdata" // time zones of quiet hours
 
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	secretproviders "github.com/SAP/stewardci-core/pkg/k8s/secrets/registry"
+	"github.com/SAP/stewardci-core/pkg/leaderelection"
 	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/SAP/stewardci-core/pkg/migration"
+	"github.com/SAP/stewardci-core/pkg/monitoringserver"
 	"github.com/SAP/stewardci-core/pkg/runctl"
+	"github.com/SAP/stewardci-core/pkg/runctl/aborthandler"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
+	"github.com/SAP/stewardci-core/pkg/runctl/preflight"
+	"github.com/SAP/stewardci-core/pkg/runctl/prepull"
+	"github.com/SAP/stewardci-core/pkg/runctl/quiethours"
+	"github.com/SAP/stewardci-core/pkg/runctl/runstats"
+	"github.com/SAP/stewardci-core/pkg/runreport"
+	"github.com/SAP/stewardci-core/pkg/schedulectl"
+	"github.com/SAP/stewardci-core/pkg/sealedsecrets"
 	"github.com/SAP/stewardci-core/pkg/signals"
+	"github.com/SAP/stewardci-core/pkg/throttle"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -20,20 +49,124 @@ const (
 	// by the controller.
 	resyncPeriod = 30 * time.Second
 
-	// metricsPort is the TCP port number to be used by the metrics
-	// HTTP server.
-	metricsPort = 9090
+	// gitHubAPIRequestTimeout is the timeout for requests to the GitHub
+	// REST API minting GitHub App installation tokens.
+	gitHubAPIRequestTimeout = 30 * time.Second
+
+	// logArchiveRequestTimeout is the timeout for single requests to the
+	// log archive.
+	logArchiveRequestTimeout = 1 * time.Minute
+
+	// watchNamespacesEnvVar is the name of the environment variable
+	// providing the default value for flag `-watch-namespaces`.
+	watchNamespacesEnvVar = "STEWARD_WATCH_NAMESPACES"
+
+	// leaseName is the name of the Lease object in the system namespace
+	// used for leader election. If an instance ID is set, it is appended.
+	leaseName = "steward-run-controller"
+
+	// scheduleLeaseNameSuffix is appended to the lease name for the
+	// leader election of the pipeline schedule controller.
+	scheduleLeaseNameSuffix = "-schedules"
+
+	// scheduleThreadiness is the number of workers of the pipeline
+	// schedule controller.
+	scheduleThreadiness = 1
+
+	// runReportLeaseNameSuffix is appended to the lease name for the
+	// leader election of the run report aggregator.
+	runReportLeaseNameSuffix = "-run-reports"
 )
 
 var (
+	monitoringOpts monitoringserver.Options
+
 	kubeconfig              string
 	burst, qps, threadiness int
+	maxThreadiness          int
 
 	heartbeatInterval time.Duration
 	heartbeatLogging  bool
 	heartbeatLogLevel int
 
 	k8sAPIRequestTimeout time.Duration
+
+	readQPS, readBurst int
+
+	k8sAPIProtobuf bool
+
+	adaptiveThrottling bool
+
+	watchNamespaces   string
+	instanceID        string
+	namespaceMetadata string
+
+	statusMessageMaxLength        int
+	statusMessageTruncation       string
+	statusMessageTruncationMarker string
+
+	provenanceBuilderID string
+	exportRunTimeline   bool
+	gitHubAppTokens     bool
+	gitHubAppAPIURLs    string
+	secretResync        bool
+	migrate             bool
+
+	runAPITokenExpiration time.Duration
+
+	statusUpdateInterval time.Duration
+
+	abortHandlers       string
+	abortHandlerWorkers int
+
+	runnerHeartbeatMaxSilence time.Duration
+
+	pipelineRunTTLAfterFinished time.Duration
+	pipelineRunTTLDeletionQPS   float64
+	pipelineRunTTLDeletionBurst int
+
+	maxConcurrentRuns int
+	maxParallelRuns   int
+
+	quietHours              string
+	quietHoursTimeZone      string
+	quietHoursExemptClients string
+
+	runStatsBackend    string
+	runStatsConfigMap  string
+	runStatsMaxEntries int
+
+	runReportInterval time.Duration
+	runReportWindow   time.Duration
+
+	logArchiveBackend string
+	logArchiveURL     string
+	logArchiveRegion  string
+	logArchiveSecret  string
+
+	secretProvider             string
+	secretProviderConfigSecret string
+
+	statusOffloadThresholdBytes int
+
+	imagePrepull             bool
+	imagePrepullNodeSelector string
+	imagePrepullTolerations  string
+	imagePrepullPauseImage   string
+
+	preflightChecks         string
+	preflightMaxPendingPods int
+	preflightDialTimeout    time.Duration
+
+	workqueueBaseDelay  time.Duration
+	workqueueMaxDelay   time.Duration
+	workqueueQPS        float64
+	workqueueBucketSize int
+	maxRetries          int
+
+	leaderElect              bool
+	leaderElectLeaseDuration time.Duration
+	leaderElectIdentity      string
 )
 
 func init() {
@@ -62,7 +195,25 @@ func init() {
 		&threadiness,
 		"threadiness",
 		2,
-		"The maximum number of reconciliations performed by the controller in parallel.",
+		"The maximum number of reconciliations performed by the controller in parallel."+
+			" If worker autoscaling is enabled (see -max-threadiness), this is the minimum number.",
+	)
+	flag.IntVar(
+		&maxThreadiness,
+		"max-threadiness",
+		0,
+		"The maximum number of reconciliations performed by the controller in parallel if worker autoscaling is enabled."+
+			" If greater than -threadiness, the number of workers is adjusted dynamically between -threadiness and"+
+			" -max-threadiness based on the workqueue depth and the average reconciliation latency."+
+			" Otherwise worker autoscaling is disabled.",
+	)
+	flag.DurationVar(
+		&statusUpdateInterval,
+		"status-update-interval",
+		0,
+		"The minimum interval between two status updates of the same pipeline run which do not change the state."+
+			" Rapid successive updates, e.g. of the container state, are batched into a single update per interval."+
+			" State changes are always stored immediately. A value of zero disables batching.",
 	)
 	flag.DurationVar(
 		&heartbeatInterval,
@@ -88,6 +239,391 @@ func init() {
 		15*time.Minute,
 		"The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.",
 	)
+	flag.IntVar(
+		&readQPS,
+		"read-qps",
+		0,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting of list and watch requests"+
+			" of informers. If zero, informers share the rate limit configured by '-qps' and '-burst'.",
+	)
+	flag.IntVar(
+		&readBurst,
+		"read-burst",
+		10,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting of list and watch requests"+
+			" of informers. Only effective if '-read-qps' is set.",
+	)
+	flag.BoolVar(
+		&k8sAPIProtobuf,
+		"k8s-api-protobuf",
+		false,
+		"Whether requests for Kubernetes built-in resources like secrets and namespaces are encoded as protobuf"+
+			" instead of JSON. Requests for custom resources always use JSON.",
+	)
+	flag.BoolVar(
+		&adaptiveThrottling,
+		"adaptive-throttling",
+		true,
+		"Whether the controller throttles itself under Kubernetes API server pressure, i.e. if requests"+
+			" wait long in the client-side rate limiter or are rejected with status 429 (Too Many Requests).",
+	)
+	flag.StringVar(
+		&watchNamespaces,
+		"watch-namespaces",
+		os.Getenv(watchNamespacesEnvVar),
+		"A comma-separated list of namespaces to watch for Steward resources. If empty, all namespaces are watched."+
+			" Defaults to the value of environment variable "+watchNamespacesEnvVar+".",
+	)
+	flag.StringVar(
+		&instanceID,
+		"instance-id",
+		"",
+		"The identifier of this Steward instance. If set, only Steward resource objects labelled with"+
+			" 'steward.sap.com/instance-id=<instance-id>' are processed and all created objects get this label."+
+			" If empty, only Steward resource objects without this label are processed.",
+	)
+	flag.StringVar(
+		&namespaceMetadata,
+		"namespace-metadata",
+		"",
+		"A JSON object defining labels and annotations to be set on all namespaces created by this controller,"+
+			` e.g. '{"labels":{"example.com/cost-center":"cc1"},"requiredLabels":["example.com/cost-center"]}'.`+
+			" Required labels and annotations must be defined with non-empty values."+
+			" Label and annotation keys with prefix 'steward.sap.com/' are not allowed.",
+	)
+	flag.IntVar(
+		&statusMessageMaxLength,
+		"status-message-max-length",
+		10*1024,
+		"The maximum length in bytes of messages stored in the status of pipeline runs. Longer messages get truncated. A value of zero means no limit.",
+	)
+	flag.StringVar(
+		&statusMessageTruncation,
+		"status-message-truncation",
+		string(utils.TruncationKeepHeadAndTail),
+		"The part of too long status messages to be kept: 'head', 'tail' or 'headAndTail'.",
+	)
+	flag.StringVar(
+		&statusMessageTruncationMarker,
+		"status-message-truncation-marker",
+		"\n[... truncated, see pipeline log for the full message ...]\n",
+		"The text replacing the removed part of truncated status messages.",
+	)
+	flag.StringVar(
+		&provenanceBuilderID,
+		"provenance-builder-id",
+		"",
+		"The SLSA builder ID stated in signed provenance attestations created for successful pipeline runs."+
+			" If empty, no provenance attestations are created.",
+	)
+	flag.BoolVar(
+		&exportRunTimeline,
+		"export-run-timeline",
+		false,
+		"Whether the run event timeline of finished pipeline runs logging to Elasticsearch is sent to the same index as the pipeline log.",
+	)
+	flag.BoolVar(
+		&gitHubAppTokens,
+		"github-app-tokens",
+		false,
+		"Whether installation tokens are minted for repository auth secrets of type 'steward.sap.com/github-app'"+
+			" and refreshed while pipeline runs are running.",
+	)
+	flag.StringVar(
+		&gitHubAppAPIURLs,
+		"github-app-api-urls",
+		"",
+		"A comma-separated list of GitHub REST API URLs (e.g. of GitHub Enterprise Server installations) GitHub App"+
+			" secrets may refer to in addition to '"+githubapp.DefaultAPIURL+"'.",
+	)
+	flag.DurationVar(
+		&runAPITokenExpiration,
+		"run-api-token-expiration",
+		0,
+		"The expiration of per-run tokens allowing pipeline runs to report their progress via annotations"+
+			" of their PipelineRun object. Tokens are refreshed while pipeline runs are running. Must be at least "+
+			runctl.MinRunAPITokenExpiration.String()+". If zero, no per-run tokens are created.",
+	)
+	flag.BoolVar(
+		&secretResync,
+		"secret-resync",
+		false,
+		"Whether secrets copied to the run namespaces of pipeline runs waiting to be started are refreshed when their"+
+			" source secrets in the tenant namespace change. Requires watching secrets in the tenant namespaces.",
+	)
+	flag.BoolVar(
+		&migrate,
+		"migrate",
+		false,
+		"Whether migrations of existing Steward objects not applied yet are applied at startup, e.g. setting new defaults"+
+			" or rewriting objects in the current storage version of their custom resource definition. Completed migrations"+
+			" are recorded in config map '"+migration.DefaultStateConfigMap+"' in the system namespace. Requires cluster-wide"+
+			" permissions to update Steward objects and the status of custom resource definitions.",
+	)
+	flag.DurationVar(
+		&runnerHeartbeatMaxSilence,
+		"runner-heartbeat-max-silence",
+		0,
+		"The maximum time without heartbeat from the Jenkinsfile Runner step of a running pipeline run. Pipeline runs"+
+			" exceeding it are aborted with result 'timeout'. Until the first heartbeat, the time since the pipeline run"+
+			" started running counts. A value of zero disables the check.",
+	)
+	flag.DurationVar(
+		&pipelineRunTTLAfterFinished,
+		"pipeline-run-ttl-after-finished",
+		0,
+		"The time after which finished pipeline runs are deleted unless they define spec.ttlSecondsAfterFinished."+
+			" A value of zero disables the deletion of pipeline runs without spec.ttlSecondsAfterFinished.",
+	)
+	flag.Float64Var(
+		&pipelineRunTTLDeletionQPS,
+		"pipeline-run-ttl-deletion-qps",
+		10,
+		"The maximum rate of deletions of finished pipeline runs after their time to live per second. Further"+
+			" deletions are deferred. A value of zero disables the limit.",
+	)
+	flag.IntVar(
+		&pipelineRunTTLDeletionBurst,
+		"pipeline-run-ttl-deletion-burst",
+		50,
+		"The maximum number of deletions of finished pipeline runs after their time to live at once.",
+	)
+	flag.StringVar(
+		&abortHandlers,
+		"abort-handlers",
+		"",
+		"A comma-separated list of exec hooks invoked when pipeline runs are cleaned up to tear down external resources"+
+			" created by the pipelines. Each element has the format '<name>[:<timeout>]=<path>', e.g."+
+			" 'delete-vms:5m=/opt/hooks/delete-vms'. The default timeout is "+aborthandler.DefaultTimeout.String()+".",
+	)
+	flag.IntVar(
+		&abortHandlerWorkers,
+		"abort-handler-workers",
+		4,
+		"The maximum number of pipeline runs for which abort handlers are invoked in parallel.",
+	)
+	flag.IntVar(
+		&maxConcurrentRuns,
+		"max-concurrent-runs",
+		0,
+		"The default maximum number of pipeline runs per tenant namespace that are executed concurrently."+
+			" Further pipeline runs are queued and started in FIFO order. Tenant namespaces can override it via annotation"+
+			" 'steward.sap.com/max-concurrent-runs', tenants via spec.maxConcurrentRuns. A value of zero means no limit.",
+	)
+	flag.IntVar(
+		&maxParallelRuns,
+		"max-parallel-runs",
+		0,
+		"The maximum number of pipeline runs in all watched namespaces that are executed concurrently."+
+			" Further pipeline runs are queued and started as running pipeline runs finish, preferring tenant"+
+			" namespaces with fewer running pipeline runs. A value of zero means no limit.",
+	)
+	flag.StringVar(
+		&quietHours,
+		"quiet-hours",
+		"",
+		"The quiet hours during which new pipeline runs are queued instead of being started, e.g. to keep load off"+
+			" the cluster during business hours. A semicolon-separated list of windows, each given as five cron"+
+			" fields (minute, hour, day of month, month, day of week) followed by a duration, e.g. '0 8 * * 1-5 10h'"+
+			" for 8am to 6pm on weekdays. Pipeline runs already started are not affected. If empty, there are no quiet hours.",
+	)
+	flag.StringVar(
+		&quietHoursTimeZone,
+		"quiet-hours-time-zone",
+		"UTC",
+		"The IANA time zone in which the cron fields of '-quiet-hours' are evaluated, e.g. 'Europe/Berlin'.",
+	)
+	flag.StringVar(
+		&quietHoursExemptClients,
+		"quiet-hours-exempt-clients",
+		"",
+		"A comma-separated list of client namespaces whose pipeline runs are started during quiet hours, too.",
+	)
+	flag.StringVar(
+		&runStatsBackend,
+		"run-stats-backend",
+		"",
+		"The backend storing statistics of finished pipeline runs, used to set the estimated duration of new pipeline runs."+
+			" Supported backends: 'memory' (not persisted across restarts) and 'configmap'. If empty, no statistics are recorded.",
+	)
+	flag.StringVar(
+		&runStatsConfigMap,
+		"run-stats-config-map",
+		"steward-run-stats",
+		"The name of the config map in the system namespace storing run statistics. Only relevant for backend 'configmap'.",
+	)
+	flag.IntVar(
+		&runStatsMaxEntries,
+		"run-stats-max-entries",
+		runstats.DefaultMaxEntries,
+		"The maximum number of pipelines to keep run statistics for. Statistics of pipelines not run for the longest time are dropped first.",
+	)
+	flag.DurationVar(
+		&runReportInterval,
+		"run-report-interval",
+		0,
+		"The interval in which a RunReport object summarizing the recently finished pipeline runs is written to each client"+
+			" namespace. A value of zero disables run reports.",
+	)
+	flag.DurationVar(
+		&runReportWindow,
+		"run-report-window",
+		runreport.DefaultWindow,
+		"The time window summarized by run reports, ending at the time of the update.",
+	)
+	flag.StringVar(
+		&logArchiveBackend,
+		"log-archive-backend",
+		"",
+		"The backend storing the log of the Jenkinsfile Runner of finished pipeline runs before their run namespace is deleted."+
+			" Supported backends: 'elasticsearch', 'loki' and 's3'. If empty, logs are not archived.",
+	)
+	flag.StringVar(
+		&logArchiveURL,
+		"log-archive-url",
+		"",
+		"The endpoint of the log archive: the URL of an Elasticsearch index, the base URL of a Loki server or the"+
+			" path-style URL of an S3 bucket, optionally followed by a key prefix.",
+	)
+	flag.StringVar(
+		&logArchiveRegion,
+		"log-archive-region",
+		"",
+		"The region of the S3 bucket of the log archive. Only relevant for backend 's3'. If empty, 'us-east-1' is used.",
+	)
+	flag.StringVar(
+		&logArchiveSecret,
+		"log-archive-secret",
+		"",
+		"The name of a secret in the system namespace with entries 'username' and 'password' to authenticate to the"+
+			" log archive. For backend 's3', these are the access key ID and the secret access key. If empty, requests"+
+			" are not authenticated.",
+	)
+	flag.StringVar(
+		&secretProvider,
+		"secret-provider",
+		stewardv1alpha1.SecretProviderKubernetes,
+		"The secret provider used for pipeline runs that do not select one via annotation"+
+			" 'steward.sap.com/secret-provider' of the pipeline run or its tenant namespace."+
+			" Available providers: '"+strings.Join(secretproviders.Names(), "', '")+"'.",
+	)
+	flag.StringVar(
+		&secretProviderConfigSecret,
+		"secret-provider-config-secret",
+		"",
+		"The name of a secret in the system namespace configuring secret providers. Entry '<provider>.<key>' defines"+
+			" configuration entry '<key>' of provider '<provider>', e.g. 'vault.address'. If empty, secret providers"+
+			" requiring an installation-wide configuration cannot be used.",
+	)
+	flag.IntVar(
+		&statusOffloadThresholdBytes,
+		"status-offload-threshold-bytes",
+		0,
+		"The size in bytes of the status fields 'message', 'history' and 'stages' of a finished pipeline run above"+
+			" which these fields are moved to a config map referenced via 'status.offloadedStatus', keeping pipeline"+
+			" run objects small. A value of zero disables offloading.",
+	)
+	flag.BoolVar(
+		&imagePrepull,
+		"image-prepull",
+		false,
+		"Whether the default Jenkinsfile Runner image is pulled in advance on the nodes running pipeline runs by a"+
+			" daemon set in the system namespace, so that pipeline runs do not wait for the image pull after image updates.",
+	)
+	flag.StringVar(
+		&imagePrepullNodeSelector,
+		"image-prepull-node-selector",
+		"",
+		"A comma-separated list of node labels 'key=value' selecting the nodes to prepull images on. If empty, images"+
+			" are prepulled on all nodes. Only relevant if '-image-prepull' is set.",
+	)
+	flag.StringVar(
+		&imagePrepullTolerations,
+		"image-prepull-tolerations",
+		"",
+		"The tolerations of the image prepull pods as JSON array of Kubernetes tolerations, e.g. for the taints of a"+
+			" dedicated node pool. Only relevant if '-image-prepull' is set.",
+	)
+	flag.StringVar(
+		&imagePrepullPauseImage,
+		"image-prepull-pause-image",
+		prepull.DefaultPauseImage,
+		"The image of the container keeping the image prepull pods alive. Only relevant if '-image-prepull' is set.",
+	)
+	flag.StringVar(
+		&preflightChecks,
+		"preflight-checks",
+		"",
+		"A comma-separated list of checks performed before new pipeline runs are started. Pipeline runs failing a check"+
+			" stay queued until all checks pass. Supported checks: '"+preflight.CheckCapacity+"', '"+preflight.CheckQuota+"',"+
+			" '"+preflight.CheckImagePullSecrets+"' and '"+preflight.CheckRepository+"'.",
+	)
+	flag.IntVar(
+		&preflightMaxPendingPods,
+		"preflight-max-pending-pods",
+		preflight.DefaultMaxPendingPods,
+		"The maximum number of pending pods in the cluster accepted by pre-flight check '"+preflight.CheckCapacity+"'.",
+	)
+	flag.DurationVar(
+		&preflightDialTimeout,
+		"preflight-dial-timeout",
+		preflight.DefaultDialTimeout,
+		"The timeout for connecting to the pipeline repository host in pre-flight check '"+preflight.CheckRepository+"'.",
+	)
+	flag.DurationVar(
+		&workqueueBaseDelay,
+		"workqueue-base-delay",
+		k8s.DefaultWorkqueueBaseDelay,
+		"The delay of the first retry of a failed reconciliation. The delay doubles with each further retry"+
+			" of the same object up to '-workqueue-max-delay'.",
+	)
+	flag.DurationVar(
+		&workqueueMaxDelay,
+		"workqueue-max-delay",
+		k8s.DefaultWorkqueueMaxDelay,
+		"The maximum delay of retries of a failed reconciliation.",
+	)
+	flag.Float64Var(
+		&workqueueQPS,
+		"workqueue-qps",
+		k8s.DefaultWorkqueueQPS,
+		"The overall rate of retried reconciliations per second.",
+	)
+	flag.IntVar(
+		&workqueueBucketSize,
+		"workqueue-bucket-size",
+		k8s.DefaultWorkqueueBucketSize,
+		"The burst size of the overall rate of retried reconciliations.",
+	)
+	flag.IntVar(
+		&maxRetries,
+		"max-retries",
+		0,
+		"The maximum number of retries of a failed reconciliation of a pipeline run. If exceeded, the pipeline run is not"+
+			" retried until it changes or the next resync. A value of zero means no limit.",
+	)
+	flag.BoolVar(
+		&leaderElect,
+		"leader-elect",
+		false,
+		"Whether to elect a leader among multiple replicas of the controller. Only the leader processes"+
+			" work items, the other replicas take over if the leader fails.",
+	)
+	flag.DurationVar(
+		&leaderElectLeaseDuration,
+		"leader-elect-lease-duration",
+		leaderelection.DefaultLeaseDuration,
+		"The duration non-leader replicas wait after the last renewal of the leader lease before they"+
+			" try to become leader. Only relevant if '-leader-elect' is set.",
+	)
+	flag.StringVar(
+		&leaderElectIdentity,
+		"leader-elect-identity",
+		"",
+		"The unique identity of this replica in the leader election. If empty, the host name is used."+
+			" Only relevant if '-leader-elect' is set.",
+	)
+	monitoringOpts.AddFlags(flag.CommandLine)
 
 	flag.Parse()
 }
@@ -95,6 +631,9 @@ func init() {
 func main() {
 	defer klog.Flush()
 
+	// prevent credentials from leaking into logs
+	klog.SetLogFilter(sanitize.LogFilter{})
+
 	system.Namespace() // ensure that namespace is set in environment
 
 	var config *rest.Config
@@ -118,31 +657,314 @@ func main() {
 	config.QPS = float32(qps)
 	config.Burst = burst
 	config.Timeout = k8sAPIRequestTimeout
-	factory := k8s.NewClientFactory(config, resyncPeriod)
+	config.UserAgent = k8s.UserAgent("run-controller")
+	var readConfig *rest.Config
+	if readQPS > 0 {
+		klog.V(3).Infof("Separate informer clients (read QPS: %d, read burst: %d)", readQPS, readBurst)
+		readConfig = k8s.NewReadConfig(config, float32(readQPS), readBurst)
+	}
+	var apiThrottle *throttle.Throttle
+	if adaptiveThrottling {
+		apiThrottle = throttle.New(throttle.DefaultOpts())
+		apiThrottle.InstrumentClientConfig(config)
+		if readConfig != nil {
+			apiThrottle.InstrumentClientConfig(readConfig)
+		}
+	} else {
+		klog.V(2).Infof("Adaptive throttling is disabled")
+	}
+	parsedNamespaceMetadata, err := namespacemetadata.Parse(namespaceMetadata)
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-namespace-metadata': %s", err.Error())
+	}
+	factoryOpts := k8s.ClientFactoryOpts{
+		WatchNamespaces:   utils.SplitList(watchNamespaces),
+		InstanceID:        instanceID,
+		NamespaceMetadata: parsedNamespaceMetadata,
+		ReadConfig:        readConfig,
+		Protobuf:          k8sAPIProtobuf,
+	}
+	if len(factoryOpts.WatchNamespaces) > 0 {
+		klog.V(2).Infof("Namespace-scoped mode: watching namespaces %v", factoryOpts.WatchNamespaces)
+	}
+	if factoryOpts.InstanceID != "" {
+		klog.V(2).Infof("Steward instance ID: %q", factoryOpts.InstanceID)
+	}
+	factory := k8s.NewClientFactoryWithOpts(config, resyncPeriod, factoryOpts)
+
+	monitoringServer, err := monitoringserver.NewServer(monitoringOpts, metrics.Gatherer())
+	if err != nil {
+		klog.Exitf("invalid metrics server configuration: %s", err.Error())
+	}
+	monitoringServer.Start()
 
-	klog.V(2).Infof("Provide metrics on http://0.0.0.0:%d/metrics", metricsPort)
-	metrics.StartServer(metricsPort)
+	klog.V(3).Infof("Load key pair for sealed secrets")
+	sealedSecretsKey, err := sealedsecrets.LoadOrCreateKey(context.Background(), factory.CoreV1().Secrets(system.Namespace()))
+	if err != nil {
+		klog.Exitf("failed to load key pair for sealed secrets: %s", err.Error())
+	}
+
+	if !utils.TruncationStrategy(statusMessageTruncation).IsValid() {
+		klog.Exitf("invalid value for parameter '-status-message-truncation': %q", statusMessageTruncation)
+	}
+
+	execAbortHandlers, err := aborthandler.ParseExecRegistrations(utils.SplitList(abortHandlers))
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-abort-handlers': %s", err.Error())
+	}
+	allAbortHandlers := append(aborthandler.Registered(), execAbortHandlers...)
+	for _, handler := range allAbortHandlers {
+		klog.V(2).Infof("Abort handler %q enabled", handler.Name)
+	}
+
+	checks, err := preflight.NewChecks(utils.SplitList(preflightChecks), factory.CoreV1(), preflight.Options{
+		MaxPendingPods: preflightMaxPendingPods,
+		DialTimeout:    preflightDialTimeout,
+		RepositoryAllowedHosts: func(ctx context.Context) ([]string, error) {
+			pipelineRunsConfig, err := cfg.LoadPipelineRunsConfig(ctx, factory)
+			if err != nil {
+				return nil, err
+			}
+			return pipelineRunsConfig.ResolveRevisionAllowedHosts, nil
+		},
+	})
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-preflight-checks': %s", err.Error())
+	}
+	for _, check := range checks {
+		klog.V(2).Infof("Pre-flight check %q enabled", check.Name())
+	}
+
+	quietHoursLocation, err := time.LoadLocation(quietHoursTimeZone)
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-quiet-hours-time-zone': %s", err.Error())
+	}
+	quietHoursSchedule, err := quiethours.Parse(quietHours, quietHoursLocation)
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-quiet-hours': %s", err.Error())
+	}
+	if quietHoursSchedule != nil {
+		klog.V(2).Infof("Quiet hours %q (time zone %s) are enabled", quietHours, quietHoursLocation)
+	}
+
+	var runStats runstats.Store
+	if runStatsBackend != "" {
+		runStats, err = runstats.NewStore(runStatsBackend, runstats.Options{
+			ConfigMaps: factory.CoreV1(),
+			Namespace:  system.Namespace(),
+			Name:       runStatsConfigMap,
+			MaxEntries: runStatsMaxEntries,
+		})
+		if err != nil {
+			klog.Exitf("invalid value for parameter '-run-stats-backend': %s", err.Error())
+		}
+		klog.V(2).Infof("Run statistics are enabled (backend: %q)", runStatsBackend)
+	}
+
+	var logArchive logarchive.Sink
+	if logArchiveBackend != "" {
+		logArchiveOpts := logarchive.Options{
+			URL:        logArchiveURL,
+			Region:     logArchiveRegion,
+			HTTPClient: &http.Client{Timeout: logArchiveRequestTimeout},
+		}
+		if logArchiveSecret != "" {
+			secret, err := factory.CoreV1().Secrets(system.Namespace()).Get(context.Background(), logArchiveSecret, metav1.GetOptions{})
+			if err != nil {
+				klog.Exitf("failed to load log archive secret %q: %s", logArchiveSecret, err.Error())
+			}
+			logArchiveOpts.Username = string(secret.Data[corev1.BasicAuthUsernameKey])
+			logArchiveOpts.Password = string(secret.Data[corev1.BasicAuthPasswordKey])
+		}
+		logArchive, err = logarchive.NewSink(logArchiveBackend, logArchiveOpts)
+		if err != nil {
+			klog.Exitf("invalid log archive configuration: %s", err.Error())
+		}
+		klog.V(2).Infof("Log archiving is enabled (backend: %q)", logArchiveBackend)
+	}
+
+	if !secretproviders.IsRegistered(secretProvider) {
+		klog.Exitf("invalid value for parameter '-secret-provider': unknown secret provider %q", secretProvider)
+	}
+	var secretProviderConfigs map[string]map[string][]byte
+	if secretProviderConfigSecret != "" {
+		secret, err := factory.CoreV1().Secrets(system.Namespace()).Get(context.Background(), secretProviderConfigSecret, metav1.GetOptions{})
+		if err != nil {
+			klog.Exitf("failed to load secret provider configuration secret %q: %s", secretProviderConfigSecret, err.Error())
+		}
+		secretProviderConfigs = secretproviders.SplitConfig(secret.Data)
+	}
+	klog.V(2).Infof("Default secret provider: %q", secretProvider)
+
+	var imagePrepullManager *prepull.Manager
+	if imagePrepull {
+		prepullOpts := prepull.Options{
+			Namespace:  system.Namespace(),
+			PauseImage: imagePrepullPauseImage,
+		}
+		if imagePrepullNodeSelector != "" {
+			prepullOpts.NodeSelector, err = labels.ConvertSelectorToLabelsMap(imagePrepullNodeSelector)
+			if err != nil {
+				klog.Exitf("invalid value for parameter '-image-prepull-node-selector': %s", err.Error())
+			}
+		}
+		if imagePrepullTolerations != "" {
+			if err := json.Unmarshal([]byte(imagePrepullTolerations), &prepullOpts.Tolerations); err != nil {
+				klog.Exitf("invalid value for parameter '-image-prepull-tolerations': %s", err.Error())
+			}
+		}
+		imagePrepullManager = prepull.NewManager(factory.AppsV1(), prepullOpts)
+		klog.V(2).Infof("Image prepull is enabled")
+	}
 
 	klog.V(3).Infof("Create Controller")
 	controllerOpts := runctl.ControllerOpts{
-		HeartbeatInterval: heartbeatInterval,
+		StatusMessageTruncation: k8s.MessageTruncation{
+			MaxLength: statusMessageMaxLength,
+			Strategy:  utils.TruncationStrategy(statusMessageTruncation),
+			Marker:    statusMessageTruncationMarker,
+		},
+		HeartbeatInterval:           heartbeatInterval,
+		SealedSecretsDecrypter:      sealedsecrets.NewDecrypter(sealedSecretsKey),
+		ProvenanceBuilderID:         provenanceBuilderID,
+		ExportRunTimeline:           exportRunTimeline,
+		Throttle:                    apiThrottle,
+		MaxThreadiness:              maxThreadiness,
+		StatusUpdateInterval:        statusUpdateInterval,
+		AbortHandlers:               allAbortHandlers,
+		AbortHandlerWorkers:         abortHandlerWorkers,
+		RunnerHeartbeatMaxSilence:   runnerHeartbeatMaxSilence,
+		DefaultTTLAfterFinished:     pipelineRunTTLAfterFinished,
+		TTLDeletionLimiter:          throttle.NewDeletionLimiter(pipelineRunTTLDeletionQPS, pipelineRunTTLDeletionBurst),
+		PreflightChecks:             checks,
+		DefaultMaxConcurrentRuns:    maxConcurrentRuns,
+		MaxParallelRuns:             maxParallelRuns,
+		QuietHours:                  quietHoursSchedule,
+		QuietHoursExemptClients:     utils.SplitList(quietHoursExemptClients),
+		RunStats:                    runStats,
+		LogArchive:                  logArchive,
+		ImagePrepull:                imagePrepullManager,
+		DefaultSecretProvider:       secretProvider,
+		SecretProviderConfigs:       secretProviderConfigs,
+		StatusOffloadThresholdBytes: statusOffloadThresholdBytes,
+		SecretResync:                secretResync,
 	}
+	if runAPITokenExpiration != 0 {
+		if runAPITokenExpiration < runctl.MinRunAPITokenExpiration {
+			klog.Exitf("invalid value for parameter '-run-api-token-expiration': must be zero or at least %s", runctl.MinRunAPITokenExpiration)
+		}
+		klog.V(2).Infof("Per-run API tokens are enabled (expiration: %s)", runAPITokenExpiration)
+		controllerOpts.RunAPITokenExpiration = runAPITokenExpiration
+	}
+	controllerOpts.WorkqueueRateLimiter = k8s.NewWorkqueueRateLimiter(k8s.WorkqueueRateLimiterOpts{
+		BaseDelay:  workqueueBaseDelay,
+		MaxDelay:   workqueueMaxDelay,
+		QPS:        workqueueQPS,
+		BucketSize: workqueueBucketSize,
+	})
+	controllerOpts.MaxRetries = maxRetries
 	if heartbeatLogging {
 		tmp := klog.Level(heartbeatLogLevel)
 		controllerOpts.HeartbeatLogLevel = &tmp
 	}
+	if gitHubAppTokens {
+		klog.V(2).Infof("GitHub App token minting is enabled")
+		controllerOpts.GitHubAppTokenMinter = githubapp.NewMinter(
+			&http.Client{Timeout: gitHubAPIRequestTimeout},
+			utils.SplitList(gitHubAppAPIURLs),
+		)
+	}
+	if leaderElect {
+		leaderElectionConfig := leaderelection.Config{
+			LeaseName:     leaseName,
+			Identity:      leaderElectIdentity,
+			LeaseDuration: leaderElectLeaseDuration,
+		}
+		if instanceID != "" {
+			leaderElectionConfig.LeaseName += "-" + instanceID
+		}
+		klog.V(2).Infof("Leader election is enabled (lease: %q)", leaderElectionConfig.LeaseName)
+		controllerOpts.LeaderElection = &leaderElectionConfig
+	}
 	controller := runctl.NewController(factory, controllerOpts)
 
+	scheduleControllerOpts := schedulectl.ControllerOpts{
+		WorkqueueRateLimiter: k8s.NewWorkqueueRateLimiter(k8s.WorkqueueRateLimiterOpts{
+			BaseDelay: workqueueBaseDelay,
+			MaxDelay:  workqueueMaxDelay,
+		}),
+		MaxRetries: maxRetries,
+	}
+	if controllerOpts.LeaderElection != nil {
+		leaderElectionConfig := *controllerOpts.LeaderElection
+		leaderElectionConfig.LeaseName += scheduleLeaseNameSuffix
+		scheduleControllerOpts.LeaderElection = &leaderElectionConfig
+	}
+	scheduleController := schedulectl.NewController(factory, scheduleControllerOpts)
+
+	var runReportAggregator *runreport.Aggregator
+	if runReportInterval > 0 {
+		runReportOpts := runreport.AggregatorOpts{
+			Interval: runReportInterval,
+			Window:   runReportWindow,
+		}
+		if controllerOpts.LeaderElection != nil {
+			leaderElectionConfig := *controllerOpts.LeaderElection
+			leaderElectionConfig.LeaseName += runReportLeaseNameSuffix
+			runReportOpts.LeaderElection = &leaderElectionConfig
+		}
+		runReportAggregator = runreport.NewAggregator(factory, runReportOpts)
+		klog.V(2).Infof("Run reports are enabled (interval: %s, window: %s)", runReportInterval, runReportWindow)
+	}
+
 	klog.V(3).Infof("Create Signal Handlers")
 	stopCh := signals.SetupShutdownSignalHandler()
 	signals.SetupThreadDumpSignalHandler()
 
+	if migrate {
+		runMigrations(factory)
+	}
+
 	klog.V(2).Infof("Start Informer")
-	factory.StewardInformerFactory().Start(stopCh)
-	factory.TektonInformerFactory().Start(stopCh)
+	for _, informerFactory := range factory.StewardInformerFactories() {
+		informerFactory.Start(stopCh)
+	}
+	if len(factory.WatchNamespaces()) == 0 {
+		factory.TektonInformerFactory().Start(stopCh)
+	}
 
-	klog.V(2).Infof("Run controller (threadiness=%d)", threadiness)
+	go func() {
+		klog.V(2).Infof("Run pipeline schedule controller")
+		if err := scheduleController.Run(scheduleThreadiness, stopCh); err != nil {
+			klog.Fatalf("Error running pipeline schedule controller: %s", err.Error())
+		}
+	}()
+
+	if runReportAggregator != nil {
+		go func() {
+			klog.V(2).Infof("Run run report aggregator")
+			if err := runReportAggregator.Run(stopCh); err != nil {
+				klog.Fatalf("Error running run report aggregator: %s", err.Error())
+			}
+		}()
+	}
+
+	klog.V(2).Infof("Run controller (threadiness=%d, max-threadiness=%d)", threadiness, maxThreadiness)
 	if err = controller.Run(threadiness, stopCh); err != nil {
 		klog.Fatalf("Error running controller: %s", err.Error())
 	}
 }
+
+// runMigrations applies the migrations of existing Steward objects not
+// applied yet. Failures are logged only, as the controllers can process
+// objects not migrated yet. Failed migrations are retried on the next
+// start.
+func runMigrations(factory k8s.ClientFactory) {
+	klog.V(2).Infof("Apply migrations")
+	migrator := migration.NewMigrator(factory, system.Namespace(), migration.DefaultStateConfigMap, migration.Migrations())
+	applied, err := migrator.Run(context.Background())
+	if err != nil {
+		klog.Errorf("Failed to apply migrations: %s", err.Error())
+	}
+	klog.V(2).Infof("Applied migrations: %v", applied)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/simulate"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// defaultSystemNamespace is the default value of flag
+	// `-system-namespace`.
+	defaultSystemNamespace = "steward-system"
+)
+
+func main() {
+	defer klog.Flush()
+
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: simulate [options]\n\n"+
+			"Reconciles each tenant and pipeline run once with fake clients and prints\n"+
+			"the modifications the Steward controllers would make. The cluster is never\n"+
+			"modified.\n\n"+
+			"The objects are read from a dump file (option -dump) in YAML or JSON format,\n"+
+			"e.g. the output of `kubectl get -o yaml`, or from the cluster configured by\n"+
+			"the kubeconfig (option -live).\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	dump := flags.String("dump", "",
+		"The path to a file containing the objects to simulate the reconciliation for.")
+	live := flags.Bool("live", false,
+		"Read the objects from the cluster instead of a dump file. Only read access is required.")
+	kubeconfig := flags.String("kubeconfig", "",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster if -live is set."+
+			" If not specified or empty, the default loading rules of kubectl apply.")
+	systemNamespace := flags.String("system-namespace", defaultSystemNamespace,
+		"The namespace Steward is installed in.")
+	instanceID := flags.String("instance-id", "",
+		"The identifier of the Steward instance. Only objects labelled with this instance ID are reconciled.")
+	flags.Parse(args)
+	if (*dump == "") == !*live || flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err := os.Setenv(system.NamespaceEnvKey, *systemNamespace); err != nil {
+		return err
+	}
+
+	var objects []runtime.Object
+	var err error
+	if *live {
+		objects, err = snapshot(*kubeconfig, *systemNamespace)
+	} else {
+		objects, err = load(*dump)
+	}
+	if err != nil {
+		return err
+	}
+
+	results, err := simulate.Simulate(objects, simulate.Opts{InstanceID: *instanceID})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		fmt.Fprintf(out, "%s %s:\n", result.Kind, result.Key)
+		if result.Err != nil {
+			fmt.Fprintf(out, "  error (would be retried): %s\n", result.Err.Error())
+		}
+		if len(result.Actions) == 0 && result.Err == nil {
+			fmt.Fprintln(out, "  no changes")
+		}
+		for _, action := range result.Actions {
+			fmt.Fprintf(out, "  %s\n", action.String())
+		}
+	}
+	return nil
+}
+
+func load(fileName string) ([]runtime.Object, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	objects, err := simulate.LoadObjects(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid file %q: %s", fileName, err.Error())
+	}
+	return objects, nil
+}
+
+func snapshot(kubeconfig, systemNamespace string) ([]runtime.Object, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.UserAgent = k8s.UserAgent("simulate")
+	factory := k8s.NewClientFactoryWithoutResync(config, k8s.ClientFactoryOpts{})
+	if factory == nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clients")
+	}
+	return simulate.Snapshot(context.Background(), factory, systemNamespace)
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/lint"
+	"github.com/SAP/stewardci-core/pkg/migration"
+	"github.com/SAP/stewardci-core/pkg/signals"
+	"github.com/SAP/stewardci-core/pkg/tenantimport"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// defaultSystemNamespace is the default value of flag
+	// `-system-namespace` of command `migrate`.
+	defaultSystemNamespace = "steward-system"
+
+	usage = `Usage: stewardctl <command> [options]
+
+Commands:
+  tenants import   Create tenants in bulk from a CSV or JSON file
+  migrate          Migrate existing Steward objects after an upgrade
+  lint             Check pipeline run manifests for likely mistakes
+`
+)
+
+func main() {
+	defer klog.Flush()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch {
+	case len(os.Args) >= 3 && os.Args[1] == "tenants" && os.Args[2] == "import":
+		err = tenantsImport(os.Args[3:], os.Stdout)
+	case os.Args[1] == "migrate":
+		err = migrate(os.Args[2:], os.Stdout)
+	case os.Args[1] == "lint":
+		err = lintManifests(os.Args[2:], os.Stdin, os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// tenantsImport implements command `tenants import`.
+func tenantsImport(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("tenants import", flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: stewardctl tenants import [options] <file>\n\n"+
+			"Creates the tenants listed in <file> and waits until each of them is ready.\n"+
+			"Existing tenants are not modified, so an interrupted import can be repeated.\n\n"+
+			"The format is derived from the file extension ('.csv' or '.json'). CSV files\n"+
+			"must have a header line with columns 'namespace' and 'name' and optionally\n"+
+			"'maxConcurrentRuns'. JSON files must contain an array of objects with these\n"+
+			"fields.\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, the default loading rules of kubectl apply.")
+	concurrency := flags.Int("concurrency", tenantimport.DefaultConcurrency,
+		"The maximum number of tenants imported concurrently.")
+	readyTimeout := flags.Duration("ready-timeout", tenantimport.DefaultReadyTimeout,
+		"The maximum time to wait for each tenant to become ready.")
+	qps := flags.Int("qps", 20,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting.")
+	burst := flags.Int("burst", 40,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting.")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	fileName := flags.Arg(0)
+
+	format, err := tenantimport.FormatFromFileName(fileName)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	entries, err := tenantimport.ParseEntries(file, format)
+	if err != nil {
+		return fmt.Errorf("invalid file %q: %s", fileName, err.Error())
+	}
+
+	factory, err := newClientFactory(*kubeconfig, *qps, *burst)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newSignalContext()
+	defer cancel()
+
+	fmt.Fprintf(out, "Importing %d tenants...\n", len(entries))
+	importer := tenantimport.NewImporter(factory, tenantimport.Opts{
+		Concurrency:  *concurrency,
+		ReadyTimeout: *readyTimeout,
+		Progress: func(result tenantimport.Result, done, total int) {
+			line := fmt.Sprintf("[%d/%d] %s: %s", done, total, result.Entry.Key(), result.Outcome)
+			if result.Err != nil {
+				line = fmt.Sprintf("%s: %s", line, result.Err.Error())
+			}
+			fmt.Fprintln(out, line)
+		},
+	})
+	results := importer.Import(ctx, entries)
+
+	summary := tenantimport.Summarize(results)
+	fmt.Fprintf(out, "Done: %d created, %d existing, %d failed\n",
+		summary[tenantimport.OutcomeCreated],
+		summary[tenantimport.OutcomeExisting],
+		summary[tenantimport.OutcomeFailed],
+	)
+	if failed := summary[tenantimport.OutcomeFailed]; failed > 0 {
+		return fmt.Errorf("%d of %d tenants failed", failed, len(results))
+	}
+	return nil
+}
+
+// migrate implements command `migrate`.
+func migrate(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	klog.InitFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: stewardctl migrate [options]\n\n"+
+			"Applies the migrations of existing Steward objects not applied yet, e.g. setting\n"+
+			"new defaults or rewriting objects in the current storage version of their custom\n"+
+			"resource definition. Completed migrations are recorded in a config map in the\n"+
+			"system namespace, so the command can be repeated.\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	kubeconfig := flags.String("kubeconfig", "",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, the default loading rules of kubectl apply.")
+	systemNamespace := flags.String("system-namespace", defaultSystemNamespace,
+		"The namespace Steward is installed in.")
+	stateConfigMap := flags.String("state-config-map", migration.DefaultStateConfigMap,
+		"The name of the config map in the system namespace recording the completed migrations.")
+	qps := flags.Int("qps", 20,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting.")
+	burst := flags.Int("burst", 40,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting.")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	factory, err := newClientFactory(*kubeconfig, *qps, *burst)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newSignalContext()
+	defer cancel()
+
+	migrator := migration.NewMigrator(factory, *systemNamespace, *stateConfigMap, migration.Migrations())
+	applied, err := migrator.Run(ctx)
+	for _, id := range applied {
+		fmt.Fprintf(out, "Applied migration %s\n", id)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Done: %d migrations applied\n", len(applied))
+	return nil
+}
+
+// lintManifests implements command `lint`.
+func lintManifests(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "Usage: stewardctl lint [options] <file>...\n\n"+
+			"Checks the pipeline runs, pipeline run templates and pipeline schedules in the\n"+
+			"given YAML or JSON files for likely mistakes, e.g. a missing timeout, deprecated\n"+
+			"fields or credentials not made available to the pipeline. Use '-' to read from\n"+
+			"standard input. Objects of other kinds are skipped. Exits with status 1 if there\n"+
+			"are findings.\n\nOptions:\n")
+		flags.PrintDefaults()
+	}
+	broadNetworkProfiles := flags.String("broad-network-profiles", "",
+		"The comma-separated list of network profiles considered overly broad."+
+			" If empty, network profiles are not checked.")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	opts := lint.Options{BroadNetworkProfiles: utils.SplitList(*broadNetworkProfiles)}
+
+	findings := 0
+	for _, fileName := range flags.Args() {
+		results, err := lintFile(fileName, in, opts)
+		if err != nil {
+			return fmt.Errorf("invalid file %q: %s", fileName, err.Error())
+		}
+		for _, result := range results {
+			for _, finding := range result.Findings {
+				fmt.Fprintf(out, "%s: %s %q: %s\n", fileName, result.Kind, result.Name, finding.String())
+			}
+			findings += len(result.Findings)
+		}
+	}
+	if findings > 0 {
+		return fmt.Errorf("%d findings", findings)
+	}
+	return nil
+}
+
+// lintFile lints the manifests in the file with the given name, or in the
+// given reader if the name is `-`.
+func lintFile(fileName string, in io.Reader, opts lint.Options) ([]lint.Result, error) {
+	if fileName == "-" {
+		return lint.Manifests(in, opts)
+	}
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return lint.Manifests(file, opts)
+}
+
+// newClientFactory returns a client factory for the cluster configured
+// by the given kubeconfig file or the default loading rules of kubectl.
+func newClientFactory(kubeconfig string, qps, burst int) (k8s.ClientFactory, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.QPS = float32(qps)
+	config.Burst = burst
+	config.UserAgent = k8s.UserAgent("stewardctl")
+	factory := k8s.NewClientFactoryWithoutResync(config, k8s.ClientFactoryOpts{})
+	if factory == nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clients")
+	}
+	return factory, nil
+}
+
+// newSignalContext returns a context cancelled on shutdown signals.
+func newSignalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopCh := signals.SetupShutdownSignalHandler()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	return ctx, cancel
+}
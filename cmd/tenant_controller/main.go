@@ -2,12 +2,19 @@ package main
 
 import (
 	"flag"
+	"os"
 	"time"
 
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	"github.com/SAP/stewardci-core/pkg/leaderelection"
 	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/SAP/stewardci-core/pkg/monitoringserver"
 	"github.com/SAP/stewardci-core/pkg/signals"
 	tenantctl "github.com/SAP/stewardci-core/pkg/tenantctl"
+	"github.com/SAP/stewardci-core/pkg/throttle"
+	"github.com/SAP/stewardci-core/pkg/utils"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -20,12 +27,18 @@ const (
 	// by the controller.
 	resyncPeriod = 1 * time.Minute
 
-	// metricsPort is the TCP port number to be used by the metrics
-	// HTTP server.
-	metricsPort = 9090
+	// watchNamespacesEnvVar is the name of the environment variable
+	// providing the default value for flag `-watch-namespaces`.
+	watchNamespacesEnvVar = "STEWARD_WATCH_NAMESPACES"
+
+	// leaseName is the name of the Lease object in the system namespace
+	// used for leader election. If an instance ID is set, it is appended.
+	leaseName = "steward-tenant-controller"
 )
 
 var (
+	monitoringOpts monitoringserver.Options
+
 	kubeconfig              string
 	burst, qps, threadiness int
 
@@ -34,6 +47,33 @@ var (
 	heartbeatLogLevel int
 
 	k8sAPIRequestTimeout time.Duration
+
+	readQPS, readBurst int
+
+	k8sAPIProtobuf bool
+
+	adaptiveThrottling bool
+
+	watchNamespaces   string
+	instanceID        string
+	namespaceMetadata string
+
+	runRollupWindow time.Duration
+
+	namespaceDeletionQPS   float64
+	namespaceDeletionBurst int
+
+	networkPoliciesConfigMap string
+
+	workqueueBaseDelay  time.Duration
+	workqueueMaxDelay   time.Duration
+	workqueueQPS        float64
+	workqueueBucketSize int
+	maxRetries          int
+
+	leaderElect              bool
+	leaderElectLeaseDuration time.Duration
+	leaderElectIdentity      string
 )
 
 func init() {
@@ -88,6 +128,141 @@ func init() {
 		15*time.Minute,
 		"The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.",
 	)
+	flag.IntVar(
+		&readQPS,
+		"read-qps",
+		0,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting of list and watch requests"+
+			" of informers. If zero, informers share the rate limit configured by '-qps' and '-burst'.",
+	)
+	flag.IntVar(
+		&readBurst,
+		"read-burst",
+		10,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting of list and watch requests"+
+			" of informers. Only effective if '-read-qps' is set.",
+	)
+	flag.BoolVar(
+		&k8sAPIProtobuf,
+		"k8s-api-protobuf",
+		false,
+		"Whether requests for Kubernetes built-in resources like secrets and namespaces are encoded as protobuf"+
+			" instead of JSON. Requests for custom resources always use JSON.",
+	)
+	flag.BoolVar(
+		&adaptiveThrottling,
+		"adaptive-throttling",
+		true,
+		"Whether the controller throttles itself under Kubernetes API server pressure, i.e. if requests"+
+			" wait long in the client-side rate limiter or are rejected with status 429 (Too Many Requests).",
+	)
+	flag.StringVar(
+		&watchNamespaces,
+		"watch-namespaces",
+		os.Getenv(watchNamespacesEnvVar),
+		"A comma-separated list of namespaces to watch for Steward resources. If empty, all namespaces are watched."+
+			" Defaults to the value of environment variable "+watchNamespacesEnvVar+".",
+	)
+	flag.StringVar(
+		&instanceID,
+		"instance-id",
+		"",
+		"The identifier of this Steward instance. If set, only Steward resource objects labelled with"+
+			" 'steward.sap.com/instance-id=<instance-id>' are processed and all created objects get this label."+
+			" If empty, only Steward resource objects without this label are processed.",
+	)
+	flag.StringVar(
+		&namespaceMetadata,
+		"namespace-metadata",
+		"",
+		"A JSON object defining labels and annotations to be set on all namespaces created by this controller,"+
+			` e.g. '{"labels":{"example.com/cost-center":"cc1"},"requiredLabels":["example.com/cost-center"]}'.`+
+			" Required labels and annotations must be defined with non-empty values."+
+			" Label and annotation keys with prefix 'steward.sap.com/' are not allowed.",
+	)
+	flag.DurationVar(
+		&runRollupWindow,
+		"run-rollup-window",
+		0,
+		"The length of the time window for which finished pipeline runs are counted in the run rollup"+
+			" in the status of tenants. A value of zero disables the run rollup."+
+			" Cannot be combined with '-watch-namespaces'.",
+	)
+	flag.StringVar(
+		&networkPoliciesConfigMap,
+		"network-policies-configmap",
+		"",
+		"The name of a ConfigMap in the system namespace containing NetworkPolicy manifests to be created"+
+			" in every tenant namespace. If empty, network policies in tenant namespaces are not managed.",
+	)
+	flag.DurationVar(
+		&workqueueBaseDelay,
+		"workqueue-base-delay",
+		k8s.DefaultWorkqueueBaseDelay,
+		"The delay of the first retry of a failed reconciliation. The delay doubles with each further retry"+
+			" of the same object up to '-workqueue-max-delay'.",
+	)
+	flag.DurationVar(
+		&workqueueMaxDelay,
+		"workqueue-max-delay",
+		k8s.DefaultWorkqueueMaxDelay,
+		"The maximum delay of retries of a failed reconciliation.",
+	)
+	flag.Float64Var(
+		&workqueueQPS,
+		"workqueue-qps",
+		k8s.DefaultWorkqueueQPS,
+		"The overall rate of retried reconciliations per second.",
+	)
+	flag.IntVar(
+		&workqueueBucketSize,
+		"workqueue-bucket-size",
+		k8s.DefaultWorkqueueBucketSize,
+		"The burst size of the overall rate of retried reconciliations.",
+	)
+	flag.IntVar(
+		&maxRetries,
+		"max-retries",
+		0,
+		"The maximum number of retries of a failed reconciliation of a tenant. If exceeded, the tenant is not"+
+			" retried until it changes or the next resync. A value of zero means no limit.",
+	)
+	flag.Float64Var(
+		&namespaceDeletionQPS,
+		"namespace-deletion-qps",
+		1,
+		"The maximum rate of deletions of tenant namespaces of inactive tenants and former tenant namespaces"+
+			" of relocated tenants per second. Further deletions are deferred. A value of zero disables the limit.",
+	)
+	flag.IntVar(
+		&namespaceDeletionBurst,
+		"namespace-deletion-burst",
+		10,
+		"The maximum number of deletions of tenant namespaces of inactive tenants and former tenant namespaces"+
+			" of relocated tenants at once.",
+	)
+	flag.BoolVar(
+		&leaderElect,
+		"leader-elect",
+		false,
+		"Whether to elect a leader among multiple replicas of the controller. Only the leader processes"+
+			" work items, the other replicas take over if the leader fails.",
+	)
+	flag.DurationVar(
+		&leaderElectLeaseDuration,
+		"leader-elect-lease-duration",
+		leaderelection.DefaultLeaseDuration,
+		"The duration non-leader replicas wait after the last renewal of the leader lease before they"+
+			" try to become leader. Only relevant if '-leader-elect' is set.",
+	)
+	flag.StringVar(
+		&leaderElectIdentity,
+		"leader-elect-identity",
+		"",
+		"The unique identity of this replica in the leader election. If empty, the host name is used."+
+			" Only relevant if '-leader-elect' is set.",
+	)
+	monitoringOpts.AddFlags(flag.CommandLine)
 
 	flag.Parse()
 }
@@ -95,6 +270,9 @@ func init() {
 func main() {
 	defer klog.Flush()
 
+	// prevent credentials from leaking into logs
+	klog.SetLogFilter(sanitize.LogFilter{})
+
 	system.Namespace() // ensure that namespace is set in environment
 
 	var config *rest.Config
@@ -118,19 +296,84 @@ func main() {
 	config.QPS = float32(qps)
 	config.Burst = burst
 	config.Timeout = k8sAPIRequestTimeout
-	factory := k8s.NewClientFactory(config, resyncPeriod)
+	config.UserAgent = k8s.UserAgent("tenant-controller")
+	var readConfig *rest.Config
+	if readQPS > 0 {
+		klog.V(3).Infof("Separate informer clients (read QPS: %d, read burst: %d)", readQPS, readBurst)
+		readConfig = k8s.NewReadConfig(config, float32(readQPS), readBurst)
+	}
+	var apiThrottle *throttle.Throttle
+	if adaptiveThrottling {
+		apiThrottle = throttle.New(throttle.DefaultOpts())
+		apiThrottle.InstrumentClientConfig(config)
+		if readConfig != nil {
+			apiThrottle.InstrumentClientConfig(readConfig)
+		}
+	} else {
+		klog.V(2).Infof("Adaptive throttling is disabled")
+	}
+	parsedNamespaceMetadata, err := namespacemetadata.Parse(namespaceMetadata)
+	if err != nil {
+		klog.Exitf("invalid value for parameter '-namespace-metadata': %s", err.Error())
+	}
+	factoryOpts := k8s.ClientFactoryOpts{
+		WatchNamespaces:   utils.SplitList(watchNamespaces),
+		InstanceID:        instanceID,
+		NamespaceMetadata: parsedNamespaceMetadata,
+		ReadConfig:        readConfig,
+		Protobuf:          k8sAPIProtobuf,
+	}
+	if len(factoryOpts.WatchNamespaces) > 0 {
+		klog.V(2).Infof("Namespace-scoped mode: watching namespaces %v", factoryOpts.WatchNamespaces)
+		if runRollupWindow > 0 {
+			// tenant namespaces are not watched in namespace-scoped mode
+			klog.Exitf("'-run-rollup-window' cannot be combined with '-watch-namespaces'")
+		}
+	}
+	if factoryOpts.InstanceID != "" {
+		klog.V(2).Infof("Steward instance ID: %q", factoryOpts.InstanceID)
+	}
+	factory := k8s.NewClientFactoryWithOpts(config, resyncPeriod, factoryOpts)
 
-	klog.V(2).Infof("Provide metrics on http://0.0.0.0:%d/metrics", metricsPort)
-	metrics.StartServer(metricsPort)
+	monitoringServer, err := monitoringserver.NewServer(monitoringOpts, metrics.Gatherer())
+	if err != nil {
+		klog.Exitf("invalid metrics server configuration: %s", err.Error())
+	}
+	monitoringServer.Start()
 
 	klog.V(3).Infof("Create Controller")
 	controllerOpts := tenantctl.ControllerOpts{
 		HeartbeatInterval: heartbeatInterval,
+		Throttle:          apiThrottle,
+		RunRollupWindow:   runRollupWindow,
+
+		NamespaceDeletionLimiter: throttle.NewDeletionLimiter(namespaceDeletionQPS, namespaceDeletionBurst),
+
+		NetworkPoliciesConfigMapName: networkPoliciesConfigMap,
 	}
+	controllerOpts.WorkqueueRateLimiter = k8s.NewWorkqueueRateLimiter(k8s.WorkqueueRateLimiterOpts{
+		BaseDelay:  workqueueBaseDelay,
+		MaxDelay:   workqueueMaxDelay,
+		QPS:        workqueueQPS,
+		BucketSize: workqueueBucketSize,
+	})
+	controllerOpts.MaxRetries = maxRetries
 	if heartbeatLogging {
 		tmp := klog.Level(heartbeatLogLevel)
 		controllerOpts.HeartbeatLogLevel = &tmp
 	}
+	if leaderElect {
+		leaderElectionConfig := leaderelection.Config{
+			LeaseName:     leaseName,
+			Identity:      leaderElectIdentity,
+			LeaseDuration: leaderElectLeaseDuration,
+		}
+		if instanceID != "" {
+			leaderElectionConfig.LeaseName += "-" + instanceID
+		}
+		klog.V(2).Infof("Leader election is enabled (lease: %q)", leaderElectionConfig.LeaseName)
+		controllerOpts.LeaderElection = &leaderElectionConfig
+	}
 	controller := tenantctl.NewController(factory, controllerOpts)
 
 	klog.V(3).Infof("Create Signal Handlers")
@@ -138,7 +381,9 @@ func main() {
 	signals.SetupThreadDumpSignalHandler()
 
 	klog.V(2).Infof("Start Informer")
-	factory.StewardInformerFactory().Start(stopCh)
+	for _, informerFactory := range factory.StewardInformerFactories() {
+		informerFactory.Start(stopCh)
+	}
 
 	klog.V(2).Infof("Run controller (threadiness=%d)", threadiness)
 	if err = controller.Run(threadiness, stopCh); err != nil {
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	"github.com/SAP/stewardci-core/pkg/trigger"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// readHeaderTimeout is the maximum time to read the header of
+	// an HTTP request.
+	readHeaderTimeout = 10 * time.Second
+)
+
+var (
+	port       int
+	kubeconfig string
+	instanceID string
+	burst, qps int
+)
+
+func init() {
+	klog.InitFlags(nil)
+
+	flag.IntVar(
+		&port,
+		"port",
+		8080,
+		"The TCP port number the trigger server listens on.",
+	)
+	flag.StringVar(
+		&kubeconfig,
+		"kubeconfig",
+		"",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, assume running in-cluster.",
+	)
+	flag.StringVar(
+		&instanceID,
+		"instance-id",
+		"",
+		"The identifier of the Steward instance. If set, created pipeline runs are labelled with"+
+			" 'steward.sap.com/instance-id=<instance-id>'.",
+	)
+	flag.IntVar(
+		&burst,
+		"burst",
+		10,
+		"The size of the burst bucket for Kubernetes API client-side rate limiting.",
+	)
+	flag.IntVar(
+		&qps,
+		"qps",
+		5,
+		"The queries per seconds (QPS) for Kubernetes API client-side rate limiting.",
+	)
+
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	// prevent credentials from leaking into logs
+	klog.SetLogFilter(sanitize.LogFilter{})
+
+	var config *rest.Config
+	var err error
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		klog.Exitf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.QPS = float32(qps)
+	config.Burst = burst
+	config.UserAgent = k8s.UserAgent("trigger")
+	factory := k8s.NewClientFactoryWithoutResync(config, k8s.ClientFactoryOpts{InstanceID: instanceID})
+	if factory == nil {
+		klog.Exitf("failed to create Kubernetes clients")
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.Handle(trigger.HooksPath, trigger.NewServer(factory))
+	serveMux.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           serveMux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	klog.V(2).Infof("Serving trigger endpoints on http://0.0.0.0:%d%s<namespace>/<trigger>", port, trigger.HooksPath)
+	if err := server.ListenAndServe(); err != nil {
+		klog.Fatalf("Error running trigger server: %s", err.Error())
+	}
+}
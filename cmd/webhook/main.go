@@ -0,0 +1,313 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/admission"
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardclients "github.com/SAP/stewardci-core/pkg/client/clientset/versioned"
+	stewardinformers "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	"github.com/SAP/stewardci-core/pkg/lint"
+	"github.com/SAP/stewardci-core/pkg/signals"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// readHeaderTimeout is the maximum time to read the header of
+	// an HTTP request.
+	readHeaderTimeout = 10 * time.Second
+
+	// resyncPeriod is the period between full resyncs of the tenant
+	// informer.
+	resyncPeriod = 10 * time.Minute
+)
+
+var (
+	port        int
+	tlsCertFile string
+	tlsKeyFile  string
+
+	maxArgs     int
+	maxArgBytes int
+	maxSecrets  int
+
+	runIDSchemaFile string
+
+	defaultTimeout             time.Duration
+	defaultNetworkProfile      string
+	defaultJenkinsfileRevision string
+	defaultLoggingRunID        bool
+
+	jaasCompat bool
+
+	lintWarnings             bool
+	lintBroadNetworkProfiles string
+
+	kubeconfig                   string
+	detectTenantNamespaceClashes bool
+
+	runControllerUsername string
+	gatewayUsername       string
+)
+
+func init() {
+	klog.InitFlags(nil)
+
+	flag.IntVar(
+		&port,
+		"port",
+		8443,
+		"The TCP port number the webhook server listens on.",
+	)
+	flag.StringVar(
+		&tlsCertFile,
+		"tls-cert-file",
+		"/etc/steward-webhook/tls/tls.crt",
+		"The path to the file containing the PEM-encoded TLS server certificate.",
+	)
+	flag.StringVar(
+		&tlsKeyFile,
+		"tls-key-file",
+		"/etc/steward-webhook/tls/tls.key",
+		"The path to the file containing the PEM-encoded TLS server private key.",
+	)
+	flag.IntVar(
+		&maxArgs,
+		"max-args",
+		0,
+		"The maximum number of pipeline arguments in the spec of pipeline runs. Zero means no limit.",
+	)
+	flag.IntVar(
+		&maxArgBytes,
+		"max-arg-bytes",
+		0,
+		"The maximum total size in bytes of all pipeline argument names and values in the spec of pipeline runs. Zero means no limit.",
+	)
+	flag.IntVar(
+		&maxSecrets,
+		"max-secrets",
+		0,
+		"The maximum total number of secrets, sealed secrets and image pull secrets in the spec of pipeline runs. Zero means no limit.",
+	)
+	flag.StringVar(
+		&runIDSchemaFile,
+		"run-id-schema-file",
+		"",
+		"The path to a file containing a JSON schema that field spec.logging.elasticsearch.runID of pipeline runs"+
+			" logging to Elasticsearch must match. If not specified or empty, any run ID is accepted.",
+	)
+	flag.DurationVar(
+		&defaultTimeout,
+		"default-timeout",
+		0,
+		"The default for field spec.timeout of created pipeline runs. Zero means no default.",
+	)
+	flag.StringVar(
+		&defaultNetworkProfile,
+		"default-network-profile",
+		"",
+		"The default for field spec.profiles.network of created pipeline runs. If empty, there is no default.",
+	)
+	flag.StringVar(
+		&defaultJenkinsfileRevision,
+		"default-jenkinsfile-revision",
+		"",
+		"The default for field spec.jenkinsFile.revision of created pipeline runs, e.g. the name of the default branch."+
+			" If empty, there is no default.",
+	)
+	flag.BoolVar(
+		&defaultLoggingRunID,
+		"default-logging-run-id",
+		false,
+		"Whether field spec.logging.elasticsearch.runID of created pipeline runs defaults to an object identifying the"+
+			" pipeline run by namespace and name.",
+	)
+	flag.BoolVar(
+		&jaasCompat,
+		"jaas-compat",
+		false,
+		"Whether the Jenkins-as-a-Service job metadata annotations of created pipeline runs are mapped to field"+
+			" spec.runDetails and vice versa.",
+	)
+	flag.BoolVar(
+		&lintWarnings,
+		"lint-warnings",
+		false,
+		"Whether the lint findings of created pipeline runs (e.g. a missing timeout or deprecated fields) are returned"+
+			" to clients as admission warnings. Pipeline runs with findings are not denied.",
+	)
+	flag.StringVar(
+		&lintBroadNetworkProfiles,
+		"lint-broad-network-profiles",
+		"",
+		"The comma-separated list of network profiles considered overly broad by the lint warnings. If empty, network"+
+			" profiles are not linted.",
+	)
+	flag.BoolVar(
+		&detectTenantNamespaceClashes,
+		"detect-tenant-namespace-clashes",
+		false,
+		"Whether the creation of tenants is denied if their tenant namespace name would clash with an existing namespace or the"+
+			" tenant namespace of another tenant. Requires access to the Kubernetes API server.",
+	)
+	flag.StringVar(
+		&runControllerUsername,
+		"run-controller-username",
+		"",
+		"The username of the run controller, e.g. system:serviceaccount:steward-system:steward-run-controller."+
+			" Pipeline runs it moves from client namespaces into tenant namespaces keep the creator annotations of the"+
+			" moved pipeline run. If empty, moved pipeline runs are recorded as created by the run controller.",
+	)
+	flag.StringVar(
+		&gatewayUsername,
+		"gateway-username",
+		"",
+		"The username of the API gateway, e.g. system:serviceaccount:steward-system:steward-gateway."+
+			" Only pipeline runs created by this user may have annotation '"+api.AnnotationGatewayClient+"'."+
+			" If empty, the annotation is removed from all pipeline runs created by clients.",
+	)
+	flag.StringVar(
+		&kubeconfig,
+		"kubeconfig",
+		"",
+		"The path to a kubeconfig file configuring access to the Kubernetes cluster."+
+			" If not specified or empty, assume running in-cluster.",
+	)
+
+	flag.Parse()
+}
+
+func main() {
+	defer klog.Flush()
+
+	// prevent credentials from leaking into logs
+	klog.SetLogFilter(sanitize.LogFilter{})
+
+	serveMux := http.NewServeMux()
+	mutators := []admission.Mutator{
+		admission.CreatedBy{RunControllerUsername: runControllerUsername}.Mutate,
+		admission.TriggeredByMutator,
+		admission.GatewayClient{
+			GatewayUsername:       gatewayUsername,
+			RunControllerUsername: runControllerUsername,
+		}.Mutate,
+	}
+	specDefaults := admission.SpecDefaults{
+		NetworkProfile:      defaultNetworkProfile,
+		JenkinsfileRevision: defaultJenkinsfileRevision,
+		LoggingRunID:        defaultLoggingRunID,
+	}
+	if defaultTimeout > 0 {
+		specDefaults.Timeout = &metav1.Duration{Duration: defaultTimeout}
+	}
+	if !specDefaults.IsEmpty() {
+		mutators = append(mutators, specDefaults.Mutate)
+	}
+	if jaasCompat {
+		mutators = append(mutators, admission.JaaSCompatMutator)
+	}
+	serveMux.Handle("/mutate", admission.NewMutatingWebhook(mutators...))
+	serveMux.Handle("/mutate-pods", admission.NewMutatingWebhook(
+		admission.HostUsersMutator,
+		admission.TopologySpreadMutator,
+		admission.TerminationGracePeriodMutator,
+	))
+	specLimits := admission.SpecLimits{
+		MaxArgs:     maxArgs,
+		MaxArgBytes: maxArgBytes,
+		MaxSecrets:  maxSecrets,
+	}
+	validators := []admission.Validator{
+		specLimits.Validate,
+		admission.TriggeredByValidator,
+		admission.RunAPITokenValidator,
+	}
+	if runIDSchemaFile != "" {
+		validators = append(validators, newRunIDSchemaValidator().Validate)
+	}
+	if detectTenantNamespaceClashes {
+		validators = append(validators, newTenantNamespaceClashDetector().Validate)
+	}
+	validatingWebhook := admission.NewValidatingWebhook(validators...)
+	if lintWarnings {
+		validatingWebhook.AddWarners(admission.LintWarnings{
+			Options: lint.Options{BroadNetworkProfiles: utils.SplitList(lintBroadNetworkProfiles)},
+		}.Warn)
+	}
+	serveMux.Handle("/validate", validatingWebhook)
+	serveMux.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           serveMux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	klog.V(2).Infof("Serving admission webhook on https://0.0.0.0:%d", port)
+	if err := server.ListenAndServeTLS(tlsCertFile, tlsKeyFile); err != nil {
+		klog.Fatalf("Error running webhook server: %s", err.Error())
+	}
+}
+
+func newRunIDSchemaValidator() *admission.RunIDSchemaValidator {
+	schema, err := ioutil.ReadFile(runIDSchemaFile)
+	if err != nil {
+		klog.Exitf("failed to read run ID schema file: %s", err.Error())
+	}
+	validator, err := admission.NewRunIDSchemaValidator(schema)
+	if err != nil {
+		klog.Exitf("failed to load run ID schema file %q: %s", runIDSchemaFile, err.Error())
+	}
+	return validator
+}
+
+func newTenantNamespaceClashDetector() *admission.TenantNamespaceClashDetector {
+	var config *rest.Config
+	var err error
+	if kubeconfig == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		klog.Exitf("failed to load kubeconfig: %s", err.Error())
+	}
+	config.UserAgent = k8s.UserAgent("webhook")
+
+	kubernetesClientset := kubernetes.NewForConfigOrDie(config)
+	stewardClientset := stewardclients.NewForConfigOrDie(config)
+
+	// all tenants are watched, including those of other Steward instances,
+	// as namespace names are unique cluster-wide
+	informerFactory := stewardinformers.NewSharedInformerFactory(stewardClientset, resyncPeriod)
+	tenantInformer := informerFactory.Steward().V1alpha1().Tenants().Informer()
+	if err := tenantInformer.AddIndexers(admission.TenantIndexers()); err != nil {
+		klog.Exitf("failed to add tenant indexers: %s", err.Error())
+	}
+
+	stopCh := signals.SetupShutdownSignalHandler()
+	informerFactory.Start(stopCh)
+	klog.V(2).Infof("Sync tenant cache")
+	if ok := cache.WaitForCacheSync(stopCh, tenantInformer.HasSynced); !ok {
+		klog.Exitf("failed to wait for tenant cache to sync")
+	}
+
+	return admission.NewTenantNamespaceClashDetector(
+		kubernetesClientset.CoreV1().Namespaces(),
+		tenantInformer.GetIndexer(),
+	)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// docComments holds the doc comments of types and struct fields.
+type docComments struct {
+	types    map[string]string
+	fields   map[string]map[string]string
+	optional map[string]map[string]bool
+}
+
+// loadDocComments parses the Go source files in the given directory
+// and collects the doc comments of all type declarations and their
+// struct fields.
+func loadDocComments(dir string) (*docComments, error) {
+	fset := token.NewFileSet()
+	notTest := func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, notTest, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &docComments{
+		types:    map[string]string{},
+		fields:   map[string]map[string]string{},
+		optional: map[string]map[string]bool{},
+	}
+	for _, pkg := range pkgs {
+		for _, t := range doc.New(pkg, "", doc.AllDecls).Types {
+			result.types[t.Name] = cleanComment(t.Doc)
+			for _, spec := range t.Decl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				fields := map[string]string{}
+				optional := map[string]bool{}
+				for _, field := range structType.Fields.List {
+					text := field.Doc.Text()
+					for _, name := range fieldNames(field) {
+						fields[name] = cleanComment(text)
+						optional[name] = hasMarker(text, "+optional")
+					}
+				}
+				result.fields[t.Name] = fields
+				result.optional[t.Name] = optional
+			}
+		}
+	}
+	return result, nil
+}
+
+// typeDoc returns the doc comment of the given type.
+func (d *docComments) typeDoc(typeName string) string {
+	return d.types[typeName]
+}
+
+// fieldDoc returns the doc comment of the given struct field.
+func (d *docComments) fieldDoc(typeName, fieldName string) string {
+	return d.fields[typeName][fieldName]
+}
+
+// isOptional returns whether the given struct field is marked with
+// the `+optional` marker.
+func (d *docComments) isOptional(typeName, fieldName string) bool {
+	return d.optional[typeName][fieldName]
+}
+
+// fieldNames returns the names of a struct field declaration. For
+// embedded fields the name of the embedded type is returned.
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) == 0 {
+		switch t := field.Type.(type) {
+		case *ast.Ident:
+			return []string{t.Name}
+		case *ast.SelectorExpr:
+			return []string{t.Sel.Name}
+		case *ast.StarExpr:
+			if sel, ok := t.X.(*ast.SelectorExpr); ok {
+				return []string{sel.Sel.Name}
+			}
+		}
+		return nil
+	}
+	var names []string
+	for _, name := range field.Names {
+		names = append(names, name.Name)
+	}
+	return names
+}
+
+// hasMarker returns whether the comment contains a line consisting of
+// the given marker.
+func hasMarker(text, marker string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanComment removes marker lines (starting with `+`) and
+// TODO notes from a comment and joins the remaining lines.
+func cleanComment(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "+") || strings.HasPrefix(line, "TODO") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
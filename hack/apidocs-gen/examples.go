@@ -0,0 +1,251 @@
+package main
+
+import (
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func typeMeta(kind string) metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: api.SchemeGroupVersion.String(),
+		Kind:       kind,
+	}
+}
+
+// examplePipelineRun returns an example PipelineRun object as it would
+// be created by a client.
+func examplePipelineRun() runtime.Object {
+	return &api.PipelineRun{
+		TypeMeta: typeMeta("PipelineRun"),
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "example-",
+			Namespace:    "steward-c-client1-t-tenant1",
+		},
+		Spec: api.PipelineSpec{
+			JenkinsfileRunner: &api.JenkinsfileRunnerSpec{
+				Image:           "stewardci/stewardci-jenkinsfile-runner:220215_5d89c43",
+				ImagePullPolicy: "IfNotPresent",
+				Resources: &api.JenkinsfileRunnerResources{
+					Limits: corev1.ResourceList{
+						"nvidia.com/gpu": resource.MustParse("1"),
+					},
+				},
+			},
+			JenkinsFile: api.JenkinsFile{
+				URL:            "https://github.com/SAP-samples/stewardci-example-pipelines",
+				Revision:       "master",
+				Path:           "success/Jenkinsfile",
+				RepoAuthSecret: "repo-auth",
+			},
+			Sources: []api.SourceRepository{
+				{
+					URL:            "https://github.com/SAP-samples/stewardci-example-config",
+					Revision:       "main",
+					TargetPath:     "config",
+					RepoAuthSecret: "repo-auth",
+				},
+			},
+			Args: map[string]string{
+				"PARAM1": "value1",
+			},
+			Secrets: []string{
+				"deploy-credentials",
+			},
+			SealedSecrets: []api.SealedSecret{
+				{
+					Name: "sealed-credentials",
+					Type: corev1.SecretTypeBasicAuth,
+					SealedValue: map[string]string{
+						"username": "<encrypted value>",
+						"password": "<encrypted value>",
+					},
+				},
+			},
+			ImagePullSecrets: []string{
+				"registry-credentials",
+			},
+			Intent: api.IntentRun,
+			Logging: &api.Logging{
+				Elasticsearch: &api.Elasticsearch{
+					RunID: &api.CustomJSON{
+						Value: map[string]interface{}{
+							"jobId":    "job1",
+							"runId":    "run1",
+							"tenantId": "tenant1",
+						},
+					},
+					IndexURL:   "https://elasticsearch.example.com/jenkins-logs/_doc",
+					AuthSecret: "elasticsearch-auth",
+				},
+			},
+			Timeout: &metav1.Duration{Duration: 30 * time.Minute},
+			RunDetails: &api.PipelineRunDetails{
+				JobName:        "example-job",
+				SequenceNumber: 42,
+				Cause:          "Commit 0123abc",
+			},
+			Profiles: &api.Profiles{
+				Network:         "default",
+				SecurityContext: "default",
+				Execution:       api.ExecutionProfileUserNamespace,
+				Scheduling:      "default",
+			},
+			TestResults: &api.TestResultsSpec{
+				Path: "**/target/surefire-reports/*.xml",
+			},
+			TriggeredBy: &api.TriggeredBy{
+				Type: "github-pull-request",
+				ID:   "SAP/stewardci-core#42",
+				URL:  "https://github.com/SAP/stewardci-core/pull/42",
+			},
+		},
+	}
+}
+
+// exampleTenant returns an example Tenant object as it would be created
+// by a client.
+func exampleTenant() runtime.Object {
+	return &api.Tenant{
+		TypeMeta: typeMeta("Tenant"),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tenant1",
+			Namespace: "steward-c-client1",
+		},
+		Spec: api.TenantSpec{
+			MaxConcurrentRuns: 5,
+		},
+	}
+}
+
+// examplePipelineRunTemplate returns an example PipelineRunTemplate
+// object as it would be created by a client.
+func examplePipelineRunTemplate() runtime.Object {
+	return &api.PipelineRunTemplate{
+		TypeMeta: typeMeta("PipelineRunTemplate"),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "build",
+			Namespace: "steward-c-client1-t-tenant1",
+		},
+		Spec: api.PipelineSpec{
+			JenkinsFile: api.JenkinsFile{
+				URL:            "https://github.com/SAP-samples/stewardci-example-pipelines",
+				Revision:       "master",
+				Path:           "success/Jenkinsfile",
+				RepoAuthSecret: "repo-auth",
+			},
+			Args: map[string]string{
+				"PARAM1": "value1",
+			},
+			Secrets: []string{
+				"deploy-credentials",
+			},
+			Intent:  api.IntentRun,
+			Timeout: &metav1.Duration{Duration: 30 * time.Minute},
+		},
+	}
+}
+
+// examplePipelineSchedule returns an example PipelineSchedule object as
+// it would be created by a client.
+func examplePipelineSchedule() runtime.Object {
+	startingDeadlineSeconds := int64(300)
+	return &api.PipelineSchedule{
+		TypeMeta: typeMeta("PipelineSchedule"),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nightly",
+			Namespace: "steward-c-client1-t-tenant1",
+		},
+		Spec: api.PipelineScheduleSpec{
+			Schedule:                "0 2 * * *",
+			TimeZone:                "Europe/Berlin",
+			ConcurrencyPolicy:       api.ConcurrencyPolicyForbid,
+			StartingDeadlineSeconds: &startingDeadlineSeconds,
+			Template: api.PipelineRunTemplateSpec{
+				Metadata: api.PipelineRunTemplateMetadata{
+					Labels: map[string]string{
+						"example.com/build": "nightly",
+					},
+				},
+				Spec: api.PipelineSpec{
+					JenkinsFile: api.JenkinsFile{
+						URL:            "https://github.com/SAP-samples/stewardci-example-pipelines",
+						Revision:       "master",
+						Path:           "success/Jenkinsfile",
+						RepoAuthSecret: "repo-auth",
+					},
+					Intent: api.IntentRun,
+				},
+			},
+		},
+	}
+}
+
+// exampleTrigger returns an example Trigger object as it would be
+// created by a client.
+func exampleTrigger() runtime.Object {
+	return &api.Trigger{
+		TypeMeta: typeMeta("Trigger"),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "github-push",
+			Namespace: "steward-c-client1-t-tenant1",
+		},
+		Spec: api.TriggerSpec{
+			TemplateRef: api.PipelineRunTemplateRef{
+				Name: "build",
+			},
+			SecretName: "webhook-secret",
+			Events: []api.TriggerEventType{
+				api.TriggerEventPush,
+				api.TriggerEventPullRequest,
+			},
+			Repository: "https://github.com/SAP-samples/stewardci-example-pipelines",
+			Branches: []string{
+				"master",
+				"release/*",
+			},
+			Args: map[string]string{
+				"PARAM1": "value1",
+			},
+		},
+	}
+}
+
+// exampleRunReport returns an example RunReport object as it would be
+// written by the run report aggregator of the run controller.
+func exampleRunReport() runtime.Object {
+	windowEnd := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	return &api.RunReport{
+		TypeMeta: typeMeta("RunReport"),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pipeline-runs",
+			Namespace: "steward-c-client1",
+		},
+		Summary: api.RunReportSummary{
+			WindowStart: metav1.NewTime(windowEnd.Add(-24 * time.Hour)),
+			WindowEnd:   metav1.NewTime(windowEnd),
+			Total:       120,
+			Failed:      7,
+			Results: map[api.Result]int{
+				api.ResultSuccess:      110,
+				api.ResultErrorContent: 5,
+				api.ResultTimeout:      2,
+				api.ResultAborted:      3,
+			},
+			DurationP50: &metav1.Duration{Duration: 4 * time.Minute},
+			DurationP95: &metav1.Duration{Duration: 18 * time.Minute},
+			TopFailingPipelines: []api.FailingPipeline{
+				{
+					RepoURL:       "https://github.com/SAP-samples/stewardci-example-pipelines",
+					RelativePath:  "error/Jenkinsfile",
+					Failures:      5,
+					LastFailedRun: "example-x7k2p",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Command apidocs-gen generates API documentation artifacts for the Steward
+custom resource types from the Go types in package
+github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1:
+
+	openapi.json              An OpenAPI v3 document containing the schemas
+	                          of all resource types.
+	<kind>.schema.json        A JSON schema per resource type.
+	examples/<kind>.yaml      A typed example manifest per resource type.
+
+Descriptions are taken from the doc comments of the Go types and fields.
+
+Usage:
+
+	go run ./hack/apidocs-gen [-source-dir <dir>] [-output-dir <dir>] [-verify]
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	sourceDir string
+	outputDir string
+	verify    bool
+)
+
+// resourceType describes a resource type to generate artifacts for.
+type resourceType struct {
+	kind    string
+	object  interface{}
+	example runtime.Object
+}
+
+var resourceTypes = []resourceType{
+	{kind: "PipelineRun", object: api.PipelineRun{}, example: examplePipelineRun()},
+	{kind: "Tenant", object: api.Tenant{}, example: exampleTenant()},
+	{kind: "PipelineRunTemplate", object: api.PipelineRunTemplate{}, example: examplePipelineRunTemplate()},
+	{kind: "PipelineSchedule", object: api.PipelineSchedule{}, example: examplePipelineSchedule()},
+	{kind: "Trigger", object: api.Trigger{}, example: exampleTrigger()},
+	{kind: "RunReport", object: api.RunReport{}, example: exampleRunReport()},
+}
+
+func init() {
+	flag.StringVar(
+		&sourceDir,
+		"source-dir",
+		"pkg/apis/steward/v1alpha1",
+		"The directory containing the Go source files of the API types.",
+	)
+	flag.StringVar(
+		&outputDir,
+		"output-dir",
+		"pkg/apis/steward/v1alpha1/openapi",
+		"The directory to write the generated artifacts to.",
+	)
+	flag.BoolVar(
+		&verify,
+		"verify",
+		false,
+		"Verify that the existing artifacts are up-to-date instead of writing them.",
+	)
+}
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	docs, err := loadDocComments(sourceDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load doc comments from %q", sourceDir)
+	}
+
+	files := map[string][]byte{}
+	schemas := map[string]*schema{}
+	for _, t := range resourceTypes {
+		gen := newSchemaGenerator(docs)
+		s := gen.schemaFor(reflect.TypeOf(t.object))
+		schemas[t.kind] = s
+
+		content, err := marshalJSON(s)
+		if err != nil {
+			return err
+		}
+		files[strings.ToLower(t.kind)+".schema.json"] = content
+
+		content, err = yaml.Marshal(t.example)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal example for %s", t.kind)
+		}
+		files[filepath.Join("examples", strings.ToLower(t.kind)+".yaml")] = content
+	}
+
+	content, err := marshalJSON(newOpenAPIDocument(schemas))
+	if err != nil {
+		return err
+	}
+	files["openapi.json"] = content
+
+	if verify {
+		return verifyFiles(outputDir, files)
+	}
+	return writeFiles(outputDir, files)
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON")
+	}
+	return append(content, '\n'), nil
+}
+
+func writeFiles(dir string, files map[string][]byte) error {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("generated %s\n", path)
+	}
+	return nil
+}
+
+func verifyFiles(dir string, files map[string][]byte) error {
+	var outdated []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		existing, err := ioutil.ReadFile(path)
+		if err != nil || !bytes.Equal(existing, content) {
+			outdated = append(outdated, path)
+		}
+	}
+	if len(outdated) > 0 {
+		return fmt.Errorf("regeneration required for: %s", strings.Join(outdated, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// schema is a subset of an OpenAPI v3 schema object as supported by
+// Kubernetes custom resource definitions.
+type schema struct {
+	Type                  string             `json:"type,omitempty"`
+	Format                string             `json:"format,omitempty"`
+	Description           string             `json:"description,omitempty"`
+	Properties            map[string]*schema `json:"properties,omitempty"`
+	Required              []string           `json:"required,omitempty"`
+	Items                 *schema            `json:"items,omitempty"`
+	AdditionalProperties  *schema            `json:"additionalProperties,omitempty"`
+	PreserveUnknownFields bool               `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+}
+
+var (
+	jsonMarshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	stewardAPIPackagePath = reflect.TypeOf(api.PipelineRun{}).PkgPath()
+)
+
+// wellKnownTypes maps types with custom JSON serialization to their
+// schema.
+var wellKnownTypes = map[reflect.Type]func() *schema{
+	reflect.TypeOf(metav1.Time{}): func() *schema {
+		return &schema{Type: "string", Format: "date-time"}
+	},
+	reflect.TypeOf(metav1.MicroTime{}): func() *schema {
+		return &schema{Type: "string", Format: "date-time"}
+	},
+	reflect.TypeOf(metav1.Duration{}): func() *schema {
+		return &schema{Type: "string"}
+	},
+	reflect.TypeOf(knativeapis.VolatileTime{}): func() *schema {
+		return &schema{Type: "string", Format: "date-time"}
+	},
+	reflect.TypeOf(metav1.ObjectMeta{}): func() *schema {
+		return &schema{Type: "object"}
+	},
+	reflect.TypeOf(metav1.ListMeta{}): func() *schema {
+		return &schema{Type: "object"}
+	},
+}
+
+// schemaGenerator derives schemas from Go types via reflection.
+type schemaGenerator struct {
+	docs *docComments
+
+	// inProgress contains the types currently being processed to
+	// detect recursive types.
+	inProgress map[reflect.Type]bool
+}
+
+func newSchemaGenerator(docs *docComments) *schemaGenerator {
+	return &schemaGenerator{
+		docs:       docs,
+		inProgress: map[reflect.Type]bool{},
+	}
+}
+
+// schemaFor returns the schema for the given type.
+func (g *schemaGenerator) schemaFor(t reflect.Type) *schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if f, ok := wellKnownTypes[t]; ok {
+		return f()
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		// custom serialization, any JSON value possible
+		return &schema{PreserveUnknownFields: true}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if g.inProgress[t] {
+			return &schema{Type: "object", PreserveUnknownFields: true}
+		}
+		g.inProgress[t] = true
+		defer delete(g.inProgress, t)
+
+		s := &schema{
+			Type:        "object",
+			Description: g.typeDoc(t),
+			Properties:  map[string]*schema{},
+		}
+		g.addStructFields(s, t)
+		if len(s.Properties) == 0 {
+			s.Properties = nil
+		}
+		sort.Strings(s.Required)
+		return s
+	case reflect.Interface:
+		return &schema{PreserveUnknownFields: true}
+	}
+	return &schema{}
+}
+
+// addStructFields adds the fields of the given struct type to the
+// properties of s. Fields of embedded structs without JSON name are
+// added inline.
+func (g *schemaGenerator) addStructFields(s *schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+		name, omitEmpty, inline, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		if inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			g.addStructFields(s, fieldType)
+			continue
+		}
+
+		fieldSchema := g.schemaFor(field.Type)
+		if doc := g.fieldDoc(t, field.Name); doc != "" {
+			fieldSchema.Description = doc
+		}
+		s.Properties[name] = fieldSchema
+		if !omitEmpty && !g.isOptional(t, field.Name) && field.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func (g *schemaGenerator) typeDoc(t reflect.Type) string {
+	if !g.isAPIType(t) {
+		return ""
+	}
+	return g.docs.typeDoc(t.Name())
+}
+
+func (g *schemaGenerator) fieldDoc(t reflect.Type, fieldName string) string {
+	if !g.isAPIType(t) {
+		return ""
+	}
+	return g.docs.fieldDoc(t.Name(), fieldName)
+}
+
+func (g *schemaGenerator) isOptional(t reflect.Type, fieldName string) bool {
+	if !g.isAPIType(t) {
+		// types of other packages are used in status only and
+		// therefore treated as optional
+		return true
+	}
+	return g.docs.isOptional(t.Name(), fieldName)
+}
+
+// isAPIType returns whether the type is declared in the Steward API
+// package, for which doc comments are available.
+func (g *schemaGenerator) isAPIType(t reflect.Type) bool {
+	return t.PkgPath() == stewardAPIPackagePath
+}
+
+// parseJSONTag evaluates the `json` struct tag of the given field.
+func parseJSONTag(field reflect.StructField) (name string, omitEmpty, inline, skip bool) {
+	tag, hasTag := field.Tag.Lookup("json")
+	if tag == "-" {
+		return "", false, false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitempty":
+			omitEmpty = true
+		case "inline":
+			inline = true
+		}
+	}
+	if field.Anonymous && (name == "" || !hasTag) {
+		inline = true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return
+}
+
+// openAPIDocument is an OpenAPI v3 document containing schema
+// components only.
+type openAPIDocument struct {
+	OpenAPI    string            `json:"openapi"`
+	Info       openAPIInfo       `json:"info"`
+	Paths      map[string]string `json:"paths"`
+	Components openAPIComponents `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+// newOpenAPIDocument returns an OpenAPI document containing the given
+// schemas keyed by the fully qualified kind of the resource type.
+func newOpenAPIDocument(schemas map[string]*schema) *openAPIDocument {
+	components := map[string]*schema{}
+	for kind, s := range schemas {
+		components[api.SchemeGroupVersion.Group+"."+api.SchemeGroupVersion.Version+"."+kind] = s
+	}
+	return &openAPIDocument{
+		OpenAPI: "3.0.0",
+		Info: openAPIInfo{
+			Title:   "Steward API",
+			Version: api.SchemeGroupVersion.Version,
+		},
+		Paths:      map[string]string{},
+		Components: openAPIComponents{Schemas: components},
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_parseJSONTag(t *testing.T) {
+	t.Parallel()
+
+	type testStruct struct {
+		metav1.TypeMeta `json:",inline"`
+		Plain           string
+		Named           string `json:"named"`
+		OmitEmpty       string `json:"omitEmpty,omitempty"`
+		Skipped         string `json:"-"`
+	}
+	structType := reflect.TypeOf(testStruct{})
+
+	for _, tc := range []struct {
+		field             string
+		expectedName      string
+		expectedOmitEmpty bool
+		expectedInline    bool
+		expectedSkip      bool
+	}{
+		{"TypeMeta", "TypeMeta", false, true, false},
+		{"Plain", "Plain", false, false, false},
+		{"Named", "named", false, false, false},
+		{"OmitEmpty", "omitEmpty", true, false, false},
+		{"Skipped", "", false, false, true},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			field, _ := structType.FieldByName(tc.field)
+
+			// EXERCISE
+			name, omitEmpty, inline, skip := parseJSONTag(field)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedOmitEmpty, omitEmpty)
+			assert.Equal(t, tc.expectedInline, inline)
+			assert.Equal(t, tc.expectedSkip, skip)
+		})
+	}
+}
+
+func Test_schemaGenerator_schemaFor_PipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	docs, err := loadDocComments("../../pkg/apis/steward/v1alpha1")
+	assert.NilError(t, err)
+	examinee := newSchemaGenerator(docs)
+
+	// EXERCISE
+	result := examinee.schemaFor(reflect.TypeOf(api.PipelineRun{}))
+
+	// VERIFY
+	assert.Equal(t, "object", result.Type)
+	assert.Assert(t, is.Contains(result.Properties, "apiVersion"))
+	assert.Assert(t, is.Contains(result.Properties, "kind"))
+	assert.Assert(t, is.Contains(result.Properties, "metadata"))
+	assert.Assert(t, is.Contains(result.Properties, "status"))
+	assert.DeepEqual(t, []string{"spec"}, result.Required)
+
+	spec := result.Properties["spec"]
+	assert.Equal(t, "PipelineSpec is the spec of a PipelineRun", spec.Description)
+	assert.Assert(t, is.Len(spec.Required, 0)) // jenkinsFile is optional if templateRef is set
+	assert.DeepEqual(t, &schema{Type: "string"}, spec.Properties["args"].AdditionalProperties)
+	assert.DeepEqual(t, &schema{Type: "string"}, spec.Properties["secrets"].Items)
+	assert.Equal(t, true, spec.Properties["logging"].Properties["elasticsearch"].Properties["runID"].PreserveUnknownFields)
+
+	jenkinsFile := spec.Properties["jenkinsFile"]
+	assert.DeepEqual(t, []string{"relativePath", "repoUrl", "revision"}, jenkinsFile.Required)
+	assert.Assert(t, is.Contains(jenkinsFile.Properties["revision"].Description, "`master`"))
+
+	startedAt := result.Properties["status"].Properties["startedAt"]
+	assert.Equal(t, "string", startedAt.Type)
+	assert.Equal(t, "date-time", startedAt.Format)
+}
+
+func Test_cleanComment(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := cleanComment("First line\nsecond line.\n+optional\nTODO: something\n")
+
+	// VERIFY
+	assert.Equal(t, "First line second line.", result)
+}
@@ -6,6 +6,7 @@ unset \
     VERIFY \
     GENERATE_CLIENTS \
     GENERATE_MOCKS \
+    GENERATE_APIDOCS \
 #
 
 HERE=$(cd "$(dirname "$BASH_SOURCE")" && pwd) || exit 1
@@ -34,6 +35,9 @@ function read_args() {
             "--gen-mocks" )
                 GENERATE_MOCKS=1
                 ;;
+            "--gen-apidocs" )
+                GENERATE_APIDOCS=1
+                ;;
             "--verify" )
                 VERIFY=1
                 ;;
@@ -44,9 +48,10 @@ function read_args() {
         shift
     done
 
-    if [[ ! ( ${GENERATE_MOCKS-} || ${GENERATE_CLIENTS-} ) ]]; then
+    if [[ ! ( ${GENERATE_MOCKS-} || ${GENERATE_CLIENTS-} || ${GENERATE_APIDOCS-} ) ]]; then
         GENERATE_MOCKS=1
         GENERATE_CLIENTS=1
+        GENERATE_APIDOCS=1
     fi
 }
 
@@ -75,6 +80,10 @@ function print_usage() {
     echo "      Enable generation of mocks. This is enabled implicitly if no --gen-*"
     echo "      option is specified."
     echo ""
+    echo "   --gen-apidocs"
+    echo "      Enable generation of API docs (OpenAPI/JSON schemas and examples)."
+    echo "      This is enabled implicitly if no --gen-* option is specified."
+    echo ""
 }
 
 function is_verify_mode() {
@@ -89,6 +98,10 @@ function is_generate_mocks() {
     [[ ${GENERATE_MOCKS-} ]]
 }
 
+function is_generate_apidocs() {
+    [[ ${GENERATE_APIDOCS-} ]]
+}
+
 function generate_mocks() {
     local pkg="$1" interfaces="$2" dest="$3"
     local destPkgName
@@ -185,7 +198,7 @@ echo "MOCK_ROOT:    $MOCK_ROOT"
 echo "CODEGEN_PKG:  $CODEGEN_PKG"
 echo "GOPATH:       $GOPATH_1"
 echo "VERIFY:       $(if is_verify_mode; then echo "true"; else echo "false"; fi)"
-echo "Generators:   $(is_generate_clients && echo "clients") $(is_generate_mocks && echo "mocks")"
+echo "Generators:   $(is_generate_clients && echo "clients") $(is_generate_mocks && echo "mocks") $(is_generate_apidocs && echo "apidocs")"
 echo "GO version:   $(go version)"
 
 echo
@@ -293,4 +306,17 @@ if is_generate_mocks; then
         "pkg/runctl/metrics/testing/mocks.go"
 fi
 
+if is_generate_apidocs; then
+    echo
+    echo "## ${ACTION} API docs ###############"
+    apidocs_args=()
+    if is_verify_mode; then
+        apidocs_args+=("-verify")
+    fi
+    set -x
+    ( cd "$PROJECT_ROOT" && go run ./hack/apidocs-gen "${apidocs_args[@]}" ) \
+        || die "$(if is_verify_mode; then echo "Regeneration required for API docs"; else echo "API docs generation failed"; fi)"
+    { set +x; } 2>/dev/null
+fi
+
 echo "${ACTION} successful"
@@ -0,0 +1,98 @@
+package admission
+
+import (
+	"encoding/json"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// objectWithMeta is used to decode the metadata of arbitrary objects.
+type objectWithMeta struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// CreatedBy records the user who creates an object in annotations
+// api.AnnotationCreatedBy and api.AnnotationCreatedByUID.
+// On updates the annotations are reset to the values of the old object,
+// so that clients cannot tamper with them.
+type CreatedBy struct {
+	// RunControllerUsername is the username of the run controller.
+	// Pipeline runs the run controller creates with annotation
+	// api.AnnotationMovedFromUID keep the given annotations, which the
+	// run controller copies from the moved pipeline run. If empty, the
+	// annotations are always set to the requesting user.
+	RunControllerUsername string
+}
+
+// CreatedByMutator records the user who creates an object like
+// CreatedBy without a run controller username.
+func CreatedByMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	return CreatedBy{}.Mutate(req)
+}
+
+// Mutate is a Mutator setting the annotations.
+func (m CreatedBy) Mutate(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return nil, nil
+	}
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+
+	var wantBy, wantUID string
+	switch req.Operation {
+	case admissionv1.Create:
+		if m.isMovedByRunController(req, obj) {
+			return nil, nil
+		}
+		wantBy = req.UserInfo.Username
+		wantUID = req.UserInfo.UID
+	case admissionv1.Update:
+		oldObj, err := decodeObjectMeta(req.OldObject)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode old object")
+		}
+		wantBy = oldObj.Annotations[api.AnnotationCreatedBy]
+		wantUID = oldObj.Annotations[api.AnnotationCreatedByUID]
+	}
+
+	return annotationsPatch(obj.Annotations, map[string]*string{
+		api.AnnotationCreatedBy:    stringOrNil(wantBy),
+		api.AnnotationCreatedByUID: stringOrNil(wantUID),
+	}), nil
+}
+
+// isMovedByRunController returns whether the given object is a pipeline
+// run the run controller creates to move a pipeline run of a pending
+// tenant into the tenant namespace.
+func (m CreatedBy) isMovedByRunController(req *admissionv1.AdmissionRequest, obj *objectWithMeta) bool {
+	if m.RunControllerUsername == "" || req.UserInfo.Username != m.RunControllerUsername {
+		return false
+	}
+	if req.Resource.Resource != "pipelineruns" {
+		return false
+	}
+	_, moved := obj.Annotations[api.AnnotationMovedFromUID]
+	return moved
+}
+
+func decodeObjectMeta(raw runtime.RawExtension) (*objectWithMeta, error) {
+	obj := &objectWithMeta{}
+	if err := json.Unmarshal(raw.Raw, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// stringOrNil returns a pointer to the given string or nil if it is empty.
+func stringOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
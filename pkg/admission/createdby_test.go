@@ -0,0 +1,252 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRawObject(t *testing.T, annotations map[string]string) runtime.RawExtension {
+	t.Helper()
+	obj := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "run1",
+			Namespace:   "ns1",
+			Annotations: annotations,
+		},
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func Test_CreatedByMutator_Create(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "user1", UID: "uid1"},
+		Object:    newRawObject(t, nil),
+	}
+
+	// EXERCISE
+	ops, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []PatchOperation{
+		{
+			Op:   "add",
+			Path: "/metadata/annotations",
+			Value: map[string]string{
+				api.AnnotationCreatedBy:    "user1",
+				api.AnnotationCreatedByUID: "uid1",
+			},
+		},
+	}, ops)
+}
+
+func Test_CreatedByMutator_Create_OverwritesClientValues(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "user1"},
+		Object: newRawObject(t, map[string]string{
+			api.AnnotationCreatedBy:    "fake",
+			api.AnnotationCreatedByUID: "fake",
+		}),
+	}
+
+	// EXERCISE
+	ops, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []PatchOperation{
+		{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: "user1"},
+		{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1created-by-uid"},
+	}, ops)
+}
+
+func Test_CreatedByMutator_Update_RestoresOldValues(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		UserInfo:  authenticationv1.UserInfo{Username: "user2"},
+		OldObject: newRawObject(t, map[string]string{
+			api.AnnotationCreatedBy: "user1",
+		}),
+		Object: newRawObject(t, map[string]string{
+			api.AnnotationCreatedBy:    "user2",
+			api.AnnotationCreatedByUID: "uid2",
+		}),
+	}
+
+	// EXERCISE
+	ops, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []PatchOperation{
+		{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: "user1"},
+		{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1created-by-uid"},
+	}, ops)
+}
+
+func Test_CreatedByMutator_Update_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	annotations := map[string]string{
+		api.AnnotationCreatedBy: "user1",
+	}
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		UserInfo:  authenticationv1.UserInfo{Username: "user2"},
+		OldObject: newRawObject(t, annotations),
+		Object:    newRawObject(t, annotations),
+	}
+
+	// EXERCISE
+	ops, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(ops))
+}
+
+func Test_CreatedByMutator_Delete(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Delete,
+	}
+
+	// EXERCISE
+	ops, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(ops))
+}
+
+func Test_CreatedByMutator_InvalidObject(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte("{")},
+	}
+
+	// EXERCISE
+	_, err := CreatedByMutator(req)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to decode object")
+}
+
+func Test_CreatedBy_Create_MovedByRunController(t *testing.T) {
+	t.Parallel()
+
+	const runController = "system:serviceaccount:steward-system:steward-run-controller"
+	movedAnnotations := map[string]string{
+		api.AnnotationCreatedBy:    "user1",
+		api.AnnotationCreatedByUID: "uid1",
+		api.AnnotationMovedFromUID: "sourceUID1",
+	}
+
+	for _, tc := range []struct {
+		name          string
+		runController string
+		username      string
+		resource      string
+		annotations   map[string]string
+		expectedOps   []PatchOperation
+	}{
+		{
+			name:          "moved_by_run_controller",
+			runController: runController,
+			username:      runController,
+			resource:      "pipelineruns",
+			annotations:   movedAnnotations,
+			expectedOps:   nil,
+		},
+		{
+			name:          "moved_by_other_user",
+			runController: runController,
+			username:      "user2",
+			resource:      "pipelineruns",
+			annotations:   movedAnnotations,
+			expectedOps: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: "user2"},
+				{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1created-by-uid"},
+			},
+		},
+		{
+			name:          "not_moved",
+			runController: runController,
+			username:      runController,
+			resource:      "pipelineruns",
+			annotations:   map[string]string{api.AnnotationCreatedBy: "user1"},
+			expectedOps: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: runController},
+			},
+		},
+		{
+			name:          "other_resource",
+			runController: runController,
+			username:      runController,
+			resource:      "tenants",
+			annotations:   movedAnnotations,
+			expectedOps: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: runController},
+				{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1created-by-uid"},
+			},
+		},
+		{
+			name:          "run_controller_not_configured",
+			runController: "",
+			username:      runController,
+			resource:      "pipelineruns",
+			annotations:   movedAnnotations,
+			expectedOps: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1created-by", Value: runController},
+				{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1created-by-uid"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := CreatedBy{RunControllerUsername: tc.runController}
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: tc.resource},
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: tc.username},
+				Object:    newRawObject(t, tc.annotations),
+			}
+
+			// EXERCISE
+			ops, err := examinee.Mutate(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedOps, ops)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+/*
+Package admission implements the Steward admission webhook that is called
+by the Kubernetes API server when Steward resource objects get created or
+updated.
+*/
+package admission
@@ -0,0 +1,61 @@
+package admission
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// GatewayClient protects annotation api.AnnotationGatewayClient of
+// pipeline runs. The annotation is only accepted on creation if the
+// pipeline run is created by the API gateway or moved by the run
+// controller, and removed otherwise. On updates the annotation is reset
+// to the value of the old object, so that clients cannot tamper with it.
+// Requests for other resources are ignored.
+type GatewayClient struct {
+	// GatewayUsername is the username of the API gateway. If empty, the
+	// annotation is removed from all created pipeline runs except those
+	// moved by the run controller.
+	GatewayUsername string
+
+	// RunControllerUsername is the username of the run controller.
+	// Pipeline runs the run controller creates with annotation
+	// api.AnnotationMovedFromUID keep the annotation, which the run
+	// controller copies from the moved pipeline run.
+	RunControllerUsername string
+}
+
+// Mutate is a Mutator protecting the annotation.
+func (m GatewayClient) Mutate(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Resource.Resource != "pipelineruns" {
+		return nil, nil
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return nil, nil
+	}
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+
+	var want string
+	switch req.Operation {
+	case admissionv1.Create:
+		if m.GatewayUsername != "" && req.UserInfo.Username == m.GatewayUsername {
+			return nil, nil
+		}
+		if (CreatedBy{RunControllerUsername: m.RunControllerUsername}).isMovedByRunController(req, obj) {
+			return nil, nil
+		}
+	case admissionv1.Update:
+		oldObj, err := decodeObjectMeta(req.OldObject)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode old object")
+		}
+		want = oldObj.Annotations[api.AnnotationGatewayClient]
+	}
+
+	return annotationsPatch(obj.Annotations, map[string]*string{
+		api.AnnotationGatewayClient: stringOrNil(want),
+	}), nil
+}
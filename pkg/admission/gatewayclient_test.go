@@ -0,0 +1,127 @@
+package admission
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_GatewayClient_Mutate(t *testing.T) {
+	t.Parallel()
+
+	const (
+		gateway       = "system:serviceaccount:steward-system:steward-gateway"
+		runController = "system:serviceaccount:steward-system:steward-run-controller"
+	)
+	removeOp := PatchOperation{Op: "remove", Path: "/metadata/annotations/steward.sap.com~1gateway-client"}
+
+	for _, tc := range []struct {
+		name           string
+		operation      admissionv1.Operation
+		resource       string
+		username       string
+		oldAnnotations map[string]string
+		annotations    map[string]string
+		expectedOps    []PatchOperation
+	}{
+		{
+			name:        "create_by_gateway",
+			operation:   admissionv1.Create,
+			resource:    "pipelineruns",
+			username:    gateway,
+			annotations: map[string]string{api.AnnotationGatewayClient: "client1"},
+			expectedOps: nil,
+		},
+		{
+			name:        "create_by_other_user",
+			operation:   admissionv1.Create,
+			resource:    "pipelineruns",
+			username:    "user1",
+			annotations: map[string]string{api.AnnotationGatewayClient: "client1"},
+			expectedOps: []PatchOperation{removeOp},
+		},
+		{
+			name:        "create_by_other_user_without_annotation",
+			operation:   admissionv1.Create,
+			resource:    "pipelineruns",
+			username:    "user1",
+			annotations: map[string]string{"foo": "bar"},
+			expectedOps: nil,
+		},
+		{
+			name:      "moved_by_run_controller",
+			operation: admissionv1.Create,
+			resource:  "pipelineruns",
+			username:  runController,
+			annotations: map[string]string{
+				api.AnnotationGatewayClient: "client1",
+				api.AnnotationMovedFromUID:  "sourceUID1",
+			},
+			expectedOps: nil,
+		},
+		{
+			name:        "created_by_run_controller_not_moved",
+			operation:   admissionv1.Create,
+			resource:    "pipelineruns",
+			username:    runController,
+			annotations: map[string]string{api.AnnotationGatewayClient: "client1"},
+			expectedOps: []PatchOperation{removeOp},
+		},
+		{
+			name:           "update_restores_old_value",
+			operation:      admissionv1.Update,
+			resource:       "pipelineruns",
+			username:       gateway,
+			oldAnnotations: map[string]string{api.AnnotationGatewayClient: "client1"},
+			annotations:    map[string]string{api.AnnotationGatewayClient: "client2"},
+			expectedOps: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/steward.sap.com~1gateway-client", Value: "client1"},
+			},
+		},
+		{
+			name:           "update_removes_added_value",
+			operation:      admissionv1.Update,
+			resource:       "pipelineruns",
+			username:       gateway,
+			oldAnnotations: map[string]string{"foo": "bar"},
+			annotations:    map[string]string{api.AnnotationGatewayClient: "client1"},
+			expectedOps:    []PatchOperation{removeOp},
+		},
+		{
+			name:        "other_resource",
+			operation:   admissionv1.Create,
+			resource:    "tenants",
+			username:    "user1",
+			annotations: map[string]string{api.AnnotationGatewayClient: "client1"},
+			expectedOps: nil,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := GatewayClient{GatewayUsername: gateway, RunControllerUsername: runController}
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: tc.resource},
+				Operation: tc.operation,
+				UserInfo:  authenticationv1.UserInfo{Username: tc.username},
+				Object:    newRawObject(t, tc.annotations),
+			}
+			if tc.operation == admissionv1.Update {
+				req.OldObject = newRawObject(t, tc.oldAnnotations)
+			}
+
+			// EXERCISE
+			ops, err := examinee.Mutate(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedOps, ops)
+		})
+	}
+}
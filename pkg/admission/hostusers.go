@@ -0,0 +1,35 @@
+package admission
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// HostUsersMutator sets field `spec.hostUsers` of pods labelled with
+// api.LabelHostUsers to `false`, so that the pods run in a user namespace.
+// The Kubernetes API version Steward is built against does not know this
+// field, therefore it cannot be set via the Tekton pod template.
+// Other objects and pods without the label are not modified.
+func HostUsersMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Operation != admissionv1.Create || req.Kind.Kind != "Pod" || req.Kind.Group != "" {
+		return nil, nil
+	}
+
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	value, exists := obj.Labels[api.LabelHostUsers]
+	if !exists {
+		return nil, nil
+	}
+	if value != "false" {
+		return nil, errors.Errorf(
+			"label %q: unsupported value %q: the only supported value is \"false\"",
+			api.LabelHostUsers, value,
+		)
+	}
+	// "add" replaces an existing value
+	return []PatchOperation{{Op: "add", Path: "/spec/hostUsers", Value: false}}, nil
+}
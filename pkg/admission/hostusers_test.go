@@ -0,0 +1,98 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRawPod(t *testing.T, labels map[string]string) runtime.RawExtension {
+	t.Helper()
+	obj := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod1",
+			Namespace: "ns1",
+			Labels:    labels,
+		},
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func Test_HostUsersMutator(t *testing.T) {
+	t.Parallel()
+
+	podKind := metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	for _, tc := range []struct {
+		name        string
+		operation   admissionv1.Operation
+		kind        metav1.GroupVersionKind
+		labels      map[string]string
+		expectedOps []PatchOperation
+		expectedErr string
+	}{
+		{
+			name:        "labelled_pod",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelHostUsers: "false"},
+			expectedOps: []PatchOperation{{Op: "add", Path: "/spec/hostUsers", Value: false}},
+		},
+		{
+			name:      "unlabelled_pod",
+			operation: admissionv1.Create,
+			kind:      podKind,
+			labels:    map[string]string{"foo": "bar"},
+		},
+		{
+			name:        "unsupported_label_value",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelHostUsers: "true"},
+			expectedErr: `label "steward.sap.com/host-users": unsupported value "true": the only supported value is "false"`,
+		},
+		{
+			name:      "update",
+			operation: admissionv1.Update,
+			kind:      podKind,
+			labels:    map[string]string{api.LabelHostUsers: "false"},
+		},
+		{
+			name:      "other_kind",
+			operation: admissionv1.Create,
+			kind:      metav1.GroupVersionKind{Group: "steward.sap.com", Version: "v1alpha1", Kind: "PipelineRun"},
+			labels:    map[string]string{api.LabelHostUsers: "false"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Kind:      tc.kind,
+				Object:    newRawPod(t, tc.labels),
+			}
+
+			// EXERCISE
+			ops, err := HostUsersMutator(req)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expectedOps, ops)
+		})
+	}
+}
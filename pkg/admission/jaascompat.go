@@ -0,0 +1,134 @@
+package admission
+
+import (
+	"encoding/json"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// JaaSCompatMutator maps the Jenkins-as-a-Service (JaaS) job metadata
+// annotations of pipeline runs to `spec.runDetails` and vice versa on
+// creation, so that clients migrating from JaaS keep their traceability
+// information without changes:
+//
+// - api.AnnotationJaaSJobName corresponds to `spec.runDetails.jobName`
+//
+// - api.AnnotationJaaSBuildNumber corresponds to
+// `spec.runDetails.sequenceNumber`
+//
+// - api.AnnotationJaaSBuildCause corresponds to `spec.runDetails.cause`
+//
+// Fields of `spec.runDetails` which are empty are set from the respective
+// annotation. Afterwards the annotations are set to the values of
+// `spec.runDetails`, i.e. the spec takes precedence over conflicting
+// annotations. Pipeline runs with an invalid build number annotation are
+// denied.
+// Requests for other resources and other operations are ignored.
+func JaaSCompatMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Resource.Resource != "pipelineruns" || req.Operation != admissionv1.Create {
+		return nil, nil
+	}
+
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	// the spec is decoded as pointer to detect an omitted spec
+	objWithSpec := struct {
+		Spec *api.PipelineSpec `json:"spec"`
+	}{}
+	if err := json.Unmarshal(req.Object.Raw, &objWithSpec); err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+
+	var current api.PipelineRunDetails
+	if objWithSpec.Spec != nil && objWithSpec.Spec.RunDetails != nil {
+		current = *objWithSpec.Spec.RunDetails
+	}
+
+	imported := api.PipelineRunDetails{}
+	if current.JobName == "" {
+		imported.JobName = obj.Annotations[api.AnnotationJaaSJobName]
+	}
+	if current.SequenceNumber == 0 {
+		if value, exists := obj.Annotations[api.AnnotationJaaSBuildNumber]; exists {
+			imported.SequenceNumber, err = parseJaaSBuildNumber(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if current.Cause == "" {
+		imported.Cause = obj.Annotations[api.AnnotationJaaSBuildCause]
+	}
+
+	ops := runDetailsPatch(objWithSpec.Spec, imported)
+
+	result := current
+	if imported.JobName != "" {
+		result.JobName = imported.JobName
+	}
+	if imported.SequenceNumber != 0 {
+		result.SequenceNumber = imported.SequenceNumber
+	}
+	if imported.Cause != "" {
+		result.Cause = imported.Cause
+	}
+	var buildNumber string
+	if result.SequenceNumber != 0 {
+		buildNumber = strconv.FormatInt(int64(result.SequenceNumber), 10)
+	}
+	ops = append(ops, annotationsPatch(obj.Annotations, map[string]*string{
+		api.AnnotationJaaSJobName:     stringOrNil(result.JobName),
+		api.AnnotationJaaSBuildNumber: stringOrNil(buildNumber),
+		api.AnnotationJaaSBuildCause:  stringOrNil(result.Cause),
+	})...)
+	return ops, nil
+}
+
+// parseJaaSBuildNumber parses the value of annotation
+// api.AnnotationJaaSBuildNumber.
+func parseJaaSBuildNumber(value string) (int32, error) {
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || number <= 0 {
+		return 0, errors.Errorf(
+			"annotation %q: invalid value %q: must be a positive integer",
+			api.AnnotationJaaSBuildNumber, value,
+		)
+	}
+	return int32(number), nil
+}
+
+// runDetailsPatch returns the patch operations required to set the
+// non-empty fields of the given run details in the given spec.
+func runDetailsPatch(spec *api.PipelineSpec, details api.PipelineRunDetails) []PatchOperation {
+	if details == (api.PipelineRunDetails{}) {
+		return nil
+	}
+	if spec == nil {
+		return []PatchOperation{{
+			Op:    "add",
+			Path:  "/spec",
+			Value: map[string]interface{}{"runDetails": details},
+		}}
+	}
+	if spec.RunDetails == nil {
+		return []PatchOperation{{Op: "add", Path: "/spec/runDetails", Value: details}}
+	}
+
+	// "add" replaces an existing value
+	var ops []PatchOperation
+	if details.JobName != "" {
+		ops = append(ops, PatchOperation{Op: "add", Path: "/spec/runDetails/jobName", Value: details.JobName})
+	}
+	if details.SequenceNumber != 0 {
+		ops = append(ops, PatchOperation{Op: "add", Path: "/spec/runDetails/sequenceNumber", Value: details.SequenceNumber})
+	}
+	if details.Cause != "" {
+		ops = append(ops, PatchOperation{Op: "add", Path: "/spec/runDetails/cause", Value: details.Cause})
+	}
+	return ops
+}
@@ -0,0 +1,245 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRawPipelineRunWithAnnotations(t *testing.T, annotations map[string]string, spec api.PipelineSpec) runtime.RawExtension {
+	t.Helper()
+	obj := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "run1",
+			Namespace:   "ns1",
+			Annotations: annotations,
+		},
+		Spec: spec,
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func Test_JaaSCompatMutator_Create(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		runDetails  *api.PipelineRunDetails
+		expected    []PatchOperation
+	}{
+		{
+			name: "nothing set",
+		},
+		{
+			name: "import into missing run details",
+			annotations: map[string]string{
+				api.AnnotationJaaSJobName:     "job1",
+				api.AnnotationJaaSBuildNumber: "42",
+				api.AnnotationJaaSBuildCause:  "SCM change",
+			},
+			expected: []PatchOperation{
+				{
+					Op:   "add",
+					Path: "/spec/runDetails",
+					Value: api.PipelineRunDetails{
+						JobName:        "job1",
+						SequenceNumber: 42,
+						Cause:          "SCM change",
+					},
+				},
+			},
+		},
+		{
+			name: "import into empty fields of run details",
+			annotations: map[string]string{
+				api.AnnotationJaaSBuildNumber: "42",
+				api.AnnotationJaaSBuildCause:  "SCM change",
+			},
+			runDetails: &api.PipelineRunDetails{JobName: "job1"},
+			expected: []PatchOperation{
+				{Op: "add", Path: "/spec/runDetails/sequenceNumber", Value: int32(42)},
+				{Op: "add", Path: "/spec/runDetails/cause", Value: "SCM change"},
+				{Op: "add", Path: "/metadata/annotations/jaas.sap.com~1job-name", Value: "job1"},
+			},
+		},
+		{
+			name:       "export into missing annotations",
+			runDetails: &api.PipelineRunDetails{JobName: "job1", SequenceNumber: 7, Cause: "manual"},
+			expected: []PatchOperation{
+				{
+					Op:   "add",
+					Path: "/metadata/annotations",
+					Value: map[string]string{
+						api.AnnotationJaaSJobName:     "job1",
+						api.AnnotationJaaSBuildNumber: "7",
+						api.AnnotationJaaSBuildCause:  "manual",
+					},
+				},
+			},
+		},
+		{
+			name: "spec takes precedence",
+			annotations: map[string]string{
+				api.AnnotationJaaSJobName:     "other",
+				api.AnnotationJaaSBuildNumber: "1",
+				"foo":                         "bar",
+			},
+			runDetails: &api.PipelineRunDetails{JobName: "job1", SequenceNumber: 7},
+			expected: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/jaas.sap.com~1build-number", Value: "7"},
+				{Op: "replace", Path: "/metadata/annotations/jaas.sap.com~1job-name", Value: "job1"},
+			},
+		},
+		{
+			name: "consistent",
+			annotations: map[string]string{
+				api.AnnotationJaaSJobName: "job1",
+			},
+			runDetails: &api.PipelineRunDetails{JobName: "job1"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+				Operation: admissionv1.Create,
+				Object:    newRawPipelineRunWithAnnotations(t, tc.annotations, api.PipelineSpec{RunDetails: tc.runDetails}),
+			}
+
+			// EXERCISE
+			ops, err := JaaSCompatMutator(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, ops)
+		})
+	}
+}
+
+func Test_JaaSCompatMutator_Create_InvalidBuildNumber(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"", "0", "-1", "abc", "1.5", "2147483648"} {
+		value := value
+		t.Run(value, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+				Operation: admissionv1.Create,
+				Object: newRawPipelineRunWithAnnotations(t, map[string]string{
+					api.AnnotationJaaSBuildNumber: value,
+				}, api.PipelineSpec{}),
+			}
+
+			// EXERCISE
+			ops, err := JaaSCompatMutator(req)
+
+			// VERIFY
+			assert.ErrorContains(t, err, `annotation "jaas.sap.com/build-number": invalid value`)
+			assert.Assert(t, ops == nil)
+		})
+	}
+}
+
+func Test_JaaSCompatMutator_Create_InvalidBuildNumberIgnoredIfSpecSet(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+		Operation: admissionv1.Create,
+		Object: newRawPipelineRunWithAnnotations(t, map[string]string{
+			api.AnnotationJaaSBuildNumber: "abc",
+		}, api.PipelineSpec{RunDetails: &api.PipelineRunDetails{SequenceNumber: 3}}),
+	}
+
+	// EXERCISE
+	ops, err := JaaSCompatMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []PatchOperation{
+		{Op: "replace", Path: "/metadata/annotations/jaas.sap.com~1build-number", Value: "3"},
+	}, ops)
+}
+
+func Test_JaaSCompatMutator_CombinedWithCreatedByMutator(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	// both mutators add the missing annotations map
+	req := &admissionv1.AdmissionRequest{
+		Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: "user1"},
+		Object: newRawPipelineRunWithAnnotations(t, nil, api.PipelineSpec{
+			RunDetails: &api.PipelineRunDetails{JobName: "job1"},
+		}),
+	}
+	examinee := NewMutatingWebhook(CreatedByMutator, JaaSCompatMutator)
+
+	// EXERCISE
+	response := examinee.mutate(req)
+
+	// VERIFY
+	assert.Assert(t, response.Allowed)
+	patch, err := jsonpatch.DecodePatch(response.Patch)
+	assert.NilError(t, err)
+	mutatedRaw, err := patch.Apply(req.Object.Raw)
+	assert.NilError(t, err)
+	mutated := &api.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(mutatedRaw, mutated))
+	assert.DeepEqual(t, map[string]string{
+		api.AnnotationCreatedBy:   "user1",
+		api.AnnotationJaaSJobName: "job1",
+	}, mutated.GetAnnotations())
+}
+
+func Test_JaaSCompatMutator_IgnoresOtherRequests(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		resource  string
+		operation admissionv1.Operation
+	}{
+		{"update", "pipelineruns", admissionv1.Update},
+		{"other resource", "tenants", admissionv1.Create},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: tc.resource},
+				Operation: tc.operation,
+				Object: newRawPipelineRunWithAnnotations(t, map[string]string{
+					api.AnnotationJaaSJobName: "job1",
+				}, api.PipelineSpec{}),
+			}
+
+			// EXERCISE
+			ops, err := JaaSCompatMutator(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, ops == nil)
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// jsonSchema is a JSON schema supporting a subset of the validation
+// keywords of JSON Schema draft 7:
+//
+//	type, enum, const,
+//	properties, required, additionalProperties, minProperties, maxProperties,
+//	items, minItems, maxItems,
+//	minLength, maxLength, pattern,
+//	minimum, maximum
+//
+// Annotation keywords like `title` or `description` are ignored. Other
+// keywords are rejected, so that a schema is never applied partially.
+type jsonSchema struct {
+	// never is true for the boolean schema `false`, which no value matches
+	never bool
+
+	types    []string
+	enum     []interface{}
+	hasConst bool
+	constVal interface{}
+
+	properties           map[string]*jsonSchema
+	required             []string
+	additionalProperties *jsonSchema
+	minProperties        *int
+	maxProperties        *int
+	items                *jsonSchema
+	minItems             *int
+	maxItems             *int
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	minimum              *float64
+	maximum              *float64
+}
+
+// jsonSchemaAnnotationKeywords are the keywords that are accepted but
+// have no effect on validation.
+var jsonSchemaAnnotationKeywords = map[string]bool{
+	"$schema":     true,
+	"$id":         true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+}
+
+var jsonSchemaTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// parseJSONSchema parses the given JSON document as JSON schema.
+func parseJSONSchema(data []byte) (*jsonSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+	return newJSONSchema(raw, "#")
+}
+
+func newJSONSchema(raw interface{}, path string) (*jsonSchema, error) {
+	switch value := raw.(type) {
+	case bool:
+		return &jsonSchema{never: !value}, nil
+	case map[string]interface{}:
+		return newJSONSchemaFromObject(value, path)
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+	}
+}
+
+func newJSONSchemaFromObject(raw map[string]interface{}, path string) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+
+	keywords := make([]string, 0, len(raw))
+	for keyword := range raw {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		value := raw[keyword]
+		keywordPath := path + "/" + keyword
+		var err error
+		switch keyword {
+		case "type":
+			schema.types, err = parseJSONSchemaTypes(value, keywordPath)
+		case "enum":
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array", keywordPath)
+			}
+			schema.enum = list
+		case "const":
+			schema.hasConst = true
+			schema.constVal = value
+		case "properties":
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an object", keywordPath)
+			}
+			schema.properties = map[string]*jsonSchema{}
+			for name, propertyRaw := range object {
+				if schema.properties[name], err = newJSONSchema(propertyRaw, keywordPath+"/"+name); err != nil {
+					return nil, err
+				}
+			}
+		case "required":
+			list, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: must be an array of strings", keywordPath)
+			}
+			for _, item := range list {
+				name, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s: must be an array of strings", keywordPath)
+				}
+				schema.required = append(schema.required, name)
+			}
+		case "additionalProperties":
+			schema.additionalProperties, err = newJSONSchema(value, keywordPath)
+		case "items":
+			schema.items, err = newJSONSchema(value, keywordPath)
+		case "minProperties":
+			schema.minProperties, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "maxProperties":
+			schema.maxProperties, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "minItems":
+			schema.minItems, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "maxItems":
+			schema.maxItems, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "minLength":
+			schema.minLength, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "maxLength":
+			schema.maxLength, err = parseJSONSchemaNonNegativeInt(value, keywordPath)
+		case "pattern":
+			pattern, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: must be a string", keywordPath)
+			}
+			if schema.pattern, err = regexp.Compile(pattern); err != nil {
+				return nil, errors.Wrapf(err, "%s: invalid regular expression", keywordPath)
+			}
+		case "minimum":
+			schema.minimum, err = parseJSONSchemaNumber(value, keywordPath)
+		case "maximum":
+			schema.maximum, err = parseJSONSchemaNumber(value, keywordPath)
+		default:
+			if !jsonSchemaAnnotationKeywords[keyword] {
+				return nil, fmt.Errorf("%s: unsupported keyword", keywordPath)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+func parseJSONSchemaTypes(value interface{}, path string) ([]string, error) {
+	var list []interface{}
+	switch v := value.(type) {
+	case string:
+		list = []interface{}{v}
+	case []interface{}:
+		list = v
+	default:
+		return nil, fmt.Errorf("%s: must be a string or an array of strings", path)
+	}
+	var types []string
+	for _, item := range list {
+		name, ok := item.(string)
+		if !ok || !jsonSchemaTypes[name] {
+			return nil, fmt.Errorf("%s: invalid type %v", path, item)
+		}
+		types = append(types, name)
+	}
+	return types, nil
+}
+
+func parseJSONSchemaNonNegativeInt(value interface{}, path string) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) {
+		return nil, fmt.Errorf("%s: must be a non-negative integer", path)
+	}
+	result := int(number)
+	return &result, nil
+}
+
+func parseJSONSchemaNumber(value interface{}, path string) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: must be a number", path)
+	}
+	return &number, nil
+}
+
+// validate returns an error describing the first violation of the schema
+// by the given value, which must be the result of decoding JSON into an
+// empty interface. The path is the location of the value used in error
+// messages.
+func (s *jsonSchema) validate(value interface{}, path string) error {
+	if s.never {
+		return fmt.Errorf("%s: no value is allowed", path)
+	}
+	if len(s.types) > 0 && !s.matchesType(value) {
+		return fmt.Errorf("%s: must be of type %s", path, strings.Join(s.types, " or "))
+	}
+	if s.enum != nil && !containsJSONValue(s.enum, value) {
+		return fmt.Errorf("%s: must be one of the values of the enumeration", path)
+	}
+	if s.hasConst && !reflect.DeepEqual(s.constVal, value) {
+		return fmt.Errorf("%s: must be equal to the constant value", path)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return s.validateObject(v, path)
+	case []interface{}:
+		return s.validateArray(v, path)
+	case string:
+		return s.validateString(v, path)
+	case float64:
+		return s.validateNumber(v, path)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateObject(object map[string]interface{}, path string) error {
+	if s.minProperties != nil && len(object) < *s.minProperties {
+		return fmt.Errorf("%s: must have at least %d properties", path, *s.minProperties)
+	}
+	if s.maxProperties != nil && len(object) > *s.maxProperties {
+		return fmt.Errorf("%s: must have at most %d properties", path, *s.maxProperties)
+	}
+	for _, name := range s.required {
+		if _, found := object[name]; !found {
+			return fmt.Errorf("%s: property %q is required", path, name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertySchema, found := s.properties[name]
+		if !found {
+			propertySchema = s.additionalProperties
+		}
+		if propertySchema == nil {
+			continue
+		}
+		if err := propertySchema.validate(object[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateArray(array []interface{}, path string) error {
+	if s.minItems != nil && len(array) < *s.minItems {
+		return fmt.Errorf("%s: must have at least %d items", path, *s.minItems)
+	}
+	if s.maxItems != nil && len(array) > *s.maxItems {
+		return fmt.Errorf("%s: must have at most %d items", path, *s.maxItems)
+	}
+	if s.items != nil {
+		for i, item := range array {
+			if err := s.items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateString(str string, path string) error {
+	length := utf8.RuneCountInString(str)
+	if s.minLength != nil && length < *s.minLength {
+		return fmt.Errorf("%s: must have at least %d characters", path, *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		return fmt.Errorf("%s: must have at most %d characters", path, *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		return fmt.Errorf("%s: must match pattern %q", path, s.pattern.String())
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateNumber(number float64, path string) error {
+	if s.minimum != nil && number < *s.minimum {
+		return fmt.Errorf("%s: must be greater than or equal to %v", path, *s.minimum)
+	}
+	if s.maximum != nil && number > *s.maximum {
+		return fmt.Errorf("%s: must be less than or equal to %v", path, *s.maximum)
+	}
+	return nil
+}
+
+func (s *jsonSchema) matchesType(value interface{}) bool {
+	for _, name := range s.types {
+		switch v := value.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case map[string]interface{}:
+			if name == "object" {
+				return true
+			}
+		case []interface{}:
+			if name == "array" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case float64:
+			if name == "number" || (name == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsJSONValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
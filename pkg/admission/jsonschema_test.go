@@ -0,0 +1,223 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_parseJSONSchema_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		schema        string
+		expectedError string
+	}{
+		{"invalid JSON", `{"type": }`, "invalid JSON: invalid character '}' looking for beginning of value"},
+		{"not a schema", `[]`, "#: schema must be an object or a boolean"},
+		{"unsupported keyword", `{"anyOf": []}`, "#/anyOf: unsupported keyword"},
+		{"type not a string", `{"type": 1}`, "#/type: must be a string or an array of strings"},
+		{"type unknown", `{"type": "int"}`, "#/type: invalid type int"},
+		{"type list with unknown", `{"type": ["string", "float"]}`, "#/type: invalid type float"},
+		{"type list with non-string", `{"type": ["string", true]}`, "#/type: invalid type true"},
+		{"enum not an array", `{"enum": "a"}`, "#/enum: must be an array"},
+		{"properties not an object", `{"properties": []}`, "#/properties: must be an object"},
+		{"property schema invalid", `{"properties": {"a": 1}}`, "#/properties/a: schema must be an object or a boolean"},
+		{"required not an array", `{"required": "a"}`, "#/required: must be an array of strings"},
+		{"required with non-string", `{"required": ["a", 1]}`, "#/required: must be an array of strings"},
+		{"additionalProperties invalid", `{"additionalProperties": "no"}`, "#/additionalProperties: schema must be an object or a boolean"},
+		{"items invalid", `{"items": [{"type": "string"}]}`, "#/items: schema must be an object or a boolean"},
+		{"minProperties negative", `{"minProperties": -1}`, "#/minProperties: must be a non-negative integer"},
+		{"maxProperties fraction", `{"maxProperties": 1.5}`, "#/maxProperties: must be a non-negative integer"},
+		{"minItems string", `{"minItems": "1"}`, "#/minItems: must be a non-negative integer"},
+		{"maxItems negative", `{"maxItems": -2}`, "#/maxItems: must be a non-negative integer"},
+		{"minLength fraction", `{"minLength": 0.5}`, "#/minLength: must be a non-negative integer"},
+		{"maxLength null", `{"maxLength": null}`, "#/maxLength: must be a non-negative integer"},
+		{"pattern not a string", `{"pattern": 1}`, "#/pattern: must be a string"},
+		{"pattern invalid", `{"pattern": "[a-"}`, "#/pattern: invalid regular expression: error parsing regexp: missing closing ]: `[a-`"},
+		{"minimum not a number", `{"minimum": "1"}`, "#/minimum: must be a number"},
+		{"maximum not a number", `{"maximum": true}`, "#/maximum: must be a number"},
+		{"nested invalid", `{"items": {"properties": {"a": {"foo": 1}}}}`, "#/items/properties/a/foo: unsupported keyword"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			schema, err := parseJSONSchema([]byte(tc.schema))
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Assert(t, schema == nil)
+		})
+	}
+}
+
+func Test_parseJSONSchema_AnnotationKeywords(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	data := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "https://example.com/run-id.json",
+		"$comment": "comment",
+		"title": "title",
+		"description": "description",
+		"default": {},
+		"examples": [{}]
+	}`
+
+	// EXERCISE
+	schema, err := parseJSONSchema([]byte(data))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.NilError(t, schema.validate("anything", "#"))
+}
+
+func Test_jsonSchema_validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		schema        string
+		value         string
+		expectedError string
+	}{
+		// boolean schemas
+		{"true schema", `true`, `{"a": [1]}`, ""},
+		{"false schema", `false`, `null`, "#: no value is allowed"},
+		{"empty schema", `{}`, `"a"`, ""},
+
+		// type
+		{"type null", `{"type": "null"}`, `null`, ""},
+		{"type null mismatch", `{"type": "null"}`, `false`, "#: must be of type null"},
+		{"type boolean", `{"type": "boolean"}`, `true`, ""},
+		{"type boolean mismatch", `{"type": "boolean"}`, `"true"`, "#: must be of type boolean"},
+		{"type object", `{"type": "object"}`, `{}`, ""},
+		{"type object mismatch", `{"type": "object"}`, `[]`, "#: must be of type object"},
+		{"type array", `{"type": "array"}`, `[]`, ""},
+		{"type array mismatch", `{"type": "array"}`, `{}`, "#: must be of type array"},
+		{"type string", `{"type": "string"}`, `""`, ""},
+		{"type string mismatch", `{"type": "string"}`, `1`, "#: must be of type string"},
+		{"type number", `{"type": "number"}`, `1.5`, ""},
+		{"type number mismatch", `{"type": "number"}`, `"1"`, "#: must be of type number"},
+		{"type integer", `{"type": "integer"}`, `2`, ""},
+		{"type integer with zero fraction", `{"type": "integer"}`, `2.0`, ""},
+		{"type integer mismatch", `{"type": "integer"}`, `1.5`, "#: must be of type integer"},
+		{"type list", `{"type": ["string", "null"]}`, `null`, ""},
+		{"type list mismatch", `{"type": ["string", "null"]}`, `1`, "#: must be of type string or null"},
+
+		// enum
+		{"enum", `{"enum": ["a", 1, null, {"b": [true]}]}`, `{"b": [true]}`, ""},
+		{"enum number", `{"enum": ["a", 1]}`, `1.0`, ""},
+		{"enum mismatch", `{"enum": ["a", 1]}`, `"1"`, "#: must be one of the values of the enumeration"},
+		{"enum empty", `{"enum": []}`, `"a"`, "#: must be one of the values of the enumeration"},
+
+		// const
+		{"const", `{"const": {"a": [1, "b"]}}`, `{"a": [1, "b"]}`, ""},
+		{"const null", `{"const": null}`, `null`, ""},
+		{"const mismatch", `{"const": {"a": [1, "b"]}}`, `{"a": ["b", 1]}`, "#: must be equal to the constant value"},
+		{"const null mismatch", `{"const": null}`, `false`, "#: must be equal to the constant value"},
+
+		// properties
+		{"properties", `{"properties": {"a": {"type": "string"}}}`, `{"a": "x", "b": 1}`, ""},
+		{"properties mismatch", `{"properties": {"a": {"type": "string"}}}`, `{"a": 1}`, "#.a: must be of type string"},
+		{"properties false", `{"properties": {"a": false}}`, `{"a": 1}`, "#.a: no value is allowed"},
+		{"properties ignored for non-objects", `{"properties": {"a": false}}`, `["a"]`, ""},
+
+		// required
+		{"required", `{"required": ["a", "b"]}`, `{"a": null, "b": 0}`, ""},
+		{"required missing", `{"required": ["a", "b"]}`, `{"a": null}`, `#: property "b" is required`},
+		{"required ignored for non-objects", `{"required": ["a"]}`, `"a"`, ""},
+
+		// additionalProperties
+		{"additionalProperties false", `{"properties": {"a": true}, "additionalProperties": false}`, `{"a": 1}`, ""},
+		{"additionalProperties false mismatch", `{"properties": {"a": true}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, "#.b: no value is allowed"},
+		{"additionalProperties schema", `{"additionalProperties": {"type": "integer"}}`, `{"a": 1, "b": 2}`, ""},
+		{"additionalProperties schema mismatch", `{"additionalProperties": {"type": "integer"}}`, `{"a": 1, "b": "2"}`, "#.b: must be of type integer"},
+
+		// minProperties, maxProperties
+		{"minProperties", `{"minProperties": 1}`, `{"a": 1}`, ""},
+		{"minProperties mismatch", `{"minProperties": 1}`, `{}`, "#: must have at least 1 properties"},
+		{"maxProperties", `{"maxProperties": 1}`, `{"a": 1}`, ""},
+		{"maxProperties mismatch", `{"maxProperties": 1}`, `{"a": 1, "b": 2}`, "#: must have at most 1 properties"},
+
+		// items
+		{"items", `{"items": {"type": "string"}}`, `["a", "b"]`, ""},
+		{"items mismatch", `{"items": {"type": "string"}}`, `["a", 2]`, "#[1]: must be of type string"},
+		{"items false", `{"items": false}`, `[]`, ""},
+		{"items false mismatch", `{"items": false}`, `[1]`, "#[0]: no value is allowed"},
+		{"items ignored for non-arrays", `{"items": false}`, `{"a": 1}`, ""},
+
+		// minItems, maxItems
+		{"minItems", `{"minItems": 2}`, `[1, 2]`, ""},
+		{"minItems mismatch", `{"minItems": 2}`, `[1]`, "#: must have at least 2 items"},
+		{"maxItems", `{"maxItems": 2}`, `[1, 2]`, ""},
+		{"maxItems mismatch", `{"maxItems": 2}`, `[1, 2, 3]`, "#: must have at most 2 items"},
+
+		// minLength, maxLength
+		{"minLength", `{"minLength": 2}`, `"ab"`, ""},
+		{"minLength mismatch", `{"minLength": 2}`, `"a"`, "#: must have at least 2 characters"},
+		{"maxLength", `{"maxLength": 2}`, `"ab"`, ""},
+		{"maxLength counts characters not bytes", `{"maxLength": 2}`, `"äö"`, ""},
+		{"maxLength mismatch", `{"maxLength": 2}`, `"abc"`, "#: must have at most 2 characters"},
+		{"length ignored for non-strings", `{"maxLength": 0}`, `12345`, ""},
+
+		// pattern
+		{"pattern", `{"pattern": "^[a-z]+$"}`, `"abc"`, ""},
+		{"pattern is not anchored", `{"pattern": "b"}`, `"abc"`, ""},
+		{"pattern mismatch", `{"pattern": "^[a-z]+$"}`, `"aBc"`, `#: must match pattern "^[a-z]+$"`},
+		{"pattern ignored for non-strings", `{"pattern": "^a$"}`, `1`, ""},
+
+		// minimum, maximum
+		{"minimum", `{"minimum": 1.5}`, `1.5`, ""},
+		{"minimum mismatch", `{"minimum": 1.5}`, `1`, "#: must be greater than or equal to 1.5"},
+		{"maximum", `{"maximum": 10}`, `10`, ""},
+		{"maximum mismatch", `{"maximum": 10}`, `10.01`, "#: must be less than or equal to 10"},
+		{"minimum ignored for non-numbers", `{"minimum": 1}`, `"0"`, ""},
+
+		// nesting
+		{
+			name:          "nested path",
+			schema:        `{"properties": {"a": {"items": {"properties": {"b": {"type": "integer"}}}}}}`,
+			value:         `{"a": [{"b": 1}, {"b": "x"}]}`,
+			expectedError: "#.a[1].b: must be of type integer",
+		},
+		{
+			name:          "type checked before other keywords",
+			schema:        `{"type": "string", "enum": ["a"], "maxLength": 0}`,
+			value:         `1`,
+			expectedError: "#: must be of type string",
+		},
+		{
+			name:          "properties checked in alphabetical order",
+			schema:        `{"additionalProperties": false}`,
+			value:         `{"z": 1, "a": 2}`,
+			expectedError: "#.a: no value is allowed",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schema, err := parseJSONSchema([]byte(tc.schema))
+			assert.NilError(t, err)
+			var value interface{}
+			assert.NilError(t, json.Unmarshal([]byte(tc.value), &value))
+
+			// EXERCISE
+			err = schema.validate(value, "#")
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package admission
+
+import (
+	"github.com/SAP/stewardci-core/pkg/lint"
+	admissionv1 "k8s.io/api/admission/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// LintWarnings returns the findings of package lint for created pipeline
+// runs as admission warnings, so that clients like kubectl show them to
+// users.
+type LintWarnings struct {
+	// Options configures the lint rules.
+	Options lint.Options
+}
+
+// Warn returns the lint findings of pipeline runs to be created.
+// Updates and requests for other resources get no warnings.
+func (l LintWarnings) Warn(req *admissionv1.AdmissionRequest) []string {
+	if req.Resource.Resource != "pipelineruns" || req.Operation != admissionv1.Create {
+		return nil
+	}
+	spec, err := decodePipelineSpec(req.Object)
+	if err != nil {
+		klog.V(3).Infof("skipping lint of pipeline run %s/%s: %s", req.Namespace, req.Name, err.Error())
+		return nil
+	}
+	var warnings []string
+	for _, finding := range lint.Spec(spec, "spec", l.Options) {
+		warnings = append(warnings, finding.String())
+	}
+	return warnings
+}
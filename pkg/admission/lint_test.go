@@ -0,0 +1,73 @@
+package admission
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/lint"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_LintWarnings_Warn(t *testing.T) {
+	t.Parallel()
+
+	examinee := LintWarnings{Options: lint.Options{BroadNetworkProfiles: []string{"internet"}}}
+	lintedSpec := api.PipelineSpec{Profiles: &api.Profiles{Network: "internet"}}
+	cleanSpec := api.PipelineSpec{Timeout: &metav1.Duration{Duration: time.Minute}}
+
+	for _, tc := range []struct {
+		name             string
+		resource         string
+		operation        admissionv1.Operation
+		spec             api.PipelineSpec
+		expectedWarnings int
+	}{
+		{"create with findings", "pipelineruns", admissionv1.Create, lintedSpec, 2},
+		{"create without findings", "pipelineruns", admissionv1.Create, cleanSpec, 0},
+		{"update with findings", "pipelineruns", admissionv1.Update, lintedSpec, 0},
+		{"other resource", "tenants", admissionv1.Create, lintedSpec, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			resource := pipelineRunsResource
+			resource.Resource = tc.resource
+			req := &admissionv1.AdmissionRequest{
+				Resource:  resource,
+				Operation: tc.operation,
+				Object:    newRawPipelineRun(t, tc.spec),
+			}
+
+			// EXERCISE
+			result := examinee.Warn(req)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedWarnings, len(result))
+		})
+	}
+}
+
+func Test_LintWarnings_Warn_Message(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := LintWarnings{}
+	req := &admissionv1.AdmissionRequest{
+		Resource:  pipelineRunsResource,
+		Operation: admissionv1.Create,
+		Object:    newRawPipelineRun(t, api.PipelineSpec{}),
+	}
+
+	// EXERCISE
+	result := examinee.Warn(req)
+
+	// VERIFY
+	assert.Equal(t, 1, len(result))
+	assert.Assert(t, strings.HasPrefix(result[0], "spec.timeout: "), result[0])
+}
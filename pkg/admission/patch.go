@@ -0,0 +1,73 @@
+package admission
+
+import (
+	"sort"
+	"strings"
+)
+
+// PatchOperation is a single JSON patch operation as defined by RFC 6902.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// escapeJSONPointer escapes a reference token of a JSON pointer
+// as defined by RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// annotationsPatch returns the patch operations required to change the
+// given current annotations of an object.
+// Each entry of changes maps an annotation key to the desired value.
+// A nil value means that the annotation should be removed.
+func annotationsPatch(current map[string]string, changes map[string]*string) []PatchOperation {
+	return metadataMapPatch("annotations", current, changes)
+}
+
+// labelsPatch returns the patch operations required to change the given
+// current labels of an object.
+// Each entry of changes maps a label key to the desired value.
+// A nil value means that the label should be removed.
+func labelsPatch(current map[string]string, changes map[string]*string) []PatchOperation {
+	return metadataMapPatch("labels", current, changes)
+}
+
+// metadataMapPatch returns the patch operations required to change the
+// given current entries of the map field `metadata.<field>` of an object.
+func metadataMapPatch(field string, current map[string]string, changes map[string]*string) []PatchOperation {
+	keys := make([]string, 0, len(changes))
+	for key := range changes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if current == nil {
+		added := map[string]string{}
+		for _, key := range keys {
+			if value := changes[key]; value != nil {
+				added[key] = *value
+			}
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		return []PatchOperation{{Op: "add", Path: "/metadata/" + field, Value: added}}
+	}
+
+	var ops []PatchOperation
+	for _, key := range keys {
+		path := "/metadata/" + field + "/" + escapeJSONPointer(key)
+		currentValue, exists := current[key]
+		switch value := changes[key]; {
+		case value == nil && exists:
+			ops = append(ops, PatchOperation{Op: "remove", Path: path})
+		case value != nil && !exists:
+			ops = append(ops, PatchOperation{Op: "add", Path: path, Value: *value})
+		case value != nil && currentValue != *value:
+			ops = append(ops, PatchOperation{Op: "replace", Path: path, Value: *value})
+		}
+	}
+	return ops
+}
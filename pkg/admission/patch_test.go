@@ -0,0 +1,104 @@
+package admission
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_escapeJSONPointer(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "steward.sap.com~1created-by", escapeJSONPointer("steward.sap.com/created-by"))
+	assert.Equal(t, "a~0b~1c", escapeJSONPointer("a~b/c"))
+}
+
+func Test_annotationsPatch(t *testing.T) {
+	t.Parallel()
+
+	value1 := "value1"
+	value2 := "value2"
+
+	for _, tc := range []struct {
+		name     string
+		current  map[string]string
+		changes  map[string]*string
+		expected []PatchOperation
+	}{
+		{
+			name:     "nil annotations, nothing to add",
+			current:  nil,
+			changes:  map[string]*string{"a/b": nil},
+			expected: nil,
+		},
+		{
+			name:    "nil annotations, add",
+			current: nil,
+			changes: map[string]*string{"a/b": &value1, "c": nil},
+			expected: []PatchOperation{
+				{Op: "add", Path: "/metadata/annotations", Value: map[string]string{"a/b": "value1"}},
+			},
+		},
+		{
+			name:    "add",
+			current: map[string]string{"other": "x"},
+			changes: map[string]*string{"a/b": &value1},
+			expected: []PatchOperation{
+				{Op: "add", Path: "/metadata/annotations/a~1b", Value: "value1"},
+			},
+		},
+		{
+			name:    "replace",
+			current: map[string]string{"a/b": "value2"},
+			changes: map[string]*string{"a/b": &value1},
+			expected: []PatchOperation{
+				{Op: "replace", Path: "/metadata/annotations/a~1b", Value: "value1"},
+			},
+		},
+		{
+			name:     "unchanged",
+			current:  map[string]string{"a/b": "value2"},
+			changes:  map[string]*string{"a/b": &value2},
+			expected: nil,
+		},
+		{
+			name:    "remove",
+			current: map[string]string{"a/b": "value2"},
+			changes: map[string]*string{"a/b": nil},
+			expected: []PatchOperation{
+				{Op: "remove", Path: "/metadata/annotations/a~1b"},
+			},
+		},
+		{
+			name:     "remove non-existing",
+			current:  map[string]string{},
+			changes:  map[string]*string{"a/b": nil},
+			expected: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// EXERCISE
+			result := annotationsPatch(tc.current, tc.changes)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_labelsPatch(t *testing.T) {
+	t.Parallel()
+
+	value1 := "value1"
+
+	// EXERCISE
+	result := labelsPatch(map[string]string{"a/b": "value2"}, map[string]*string{"a/b": &value1, "c": nil})
+
+	// VERIFY
+	assert.DeepEqual(t, []PatchOperation{
+		{Op: "replace", Path: "/metadata/labels/a~1b", Value: "value1"},
+	}, result)
+}
@@ -0,0 +1,204 @@
+package admission
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	proptest "github.com/SAP/stewardci-core/pkg/utils/proptest"
+	jsonpatch "github.com/evanphx/json-patch"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// pipelineRunMutators are all mutators applicable to pipeline runs with
+// all options enabled.
+var pipelineRunMutators = []Mutator{
+	CreatedByMutator,
+	TriggeredByMutator,
+	SpecDefaults{JenkinsfileRevision: "main", LoggingRunID: true}.Mutate,
+	JaaSCompatMutator,
+}
+
+// randomMetadataMap returns nil or a map with random entries and randomly
+// chosen entries of the given candidates.
+func randomMetadataMap(r *rand.Rand, candidates map[string]string) map[string]string {
+	if r.Intn(4) == 0 {
+		return nil
+	}
+	result := proptest.StringMap(r, 3)
+	if result == nil {
+		result = map[string]string{}
+	}
+	for key, value := range candidates {
+		if proptest.Bool(r) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// randomPipelineRunForAdmission returns a random pipeline run as sent by
+// clients, including metadata set by clients that mutators must correct.
+func randomPipelineRunForAdmission(r *rand.Rand) *api.PipelineRun {
+	run := k8sfake.RandomPipelineRun(r, proptest.DNSLabel(r, 30), "ns1")
+	run.Labels = randomMetadataMap(r, map[string]string{
+		api.LabelTriggeredByType: "spoofed",
+		api.LabelTriggeredByID:   "spoofed",
+	})
+	run.Annotations = randomMetadataMap(r, map[string]string{
+		api.AnnotationCreatedBy:       "spoofed",
+		api.AnnotationCreatedByUID:    "spoofed",
+		api.AnnotationJaaSJobName:     proptest.String(r, 20),
+		api.AnnotationJaaSBuildNumber: strconv.Itoa(1 + r.Intn(10000)),
+		api.AnnotationJaaSBuildCause:  proptest.String(r, 20),
+	})
+	return run
+}
+
+func newCreateRequest(t *testing.T, run *api.PipelineRun, username string) *admissionv1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(run)
+	assert.NilError(t, err)
+	return &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  pipelineRunsResource,
+		Namespace: run.GetNamespace(),
+		Name:      run.GetName(),
+		UserInfo:  authenticationv1.UserInfo{Username: username, UID: "uid-" + username},
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func applyPatch(t *testing.T, raw []byte, patchBytes []byte) []byte {
+	t.Helper()
+	if patchBytes == nil {
+		return raw
+	}
+	patch, err := jsonpatch.DecodePatch(patchBytes)
+	assert.NilError(t, err)
+	result, err := patch.Apply(raw)
+	assert.NilError(t, err, "patch: %s", string(patchBytes))
+	return result
+}
+
+func Test_MutatingWebhook_Property_PatchAppliesAndIsStable(t *testing.T) {
+	t.Parallel()
+
+	examinee := NewMutatingWebhook(pipelineRunMutators...)
+
+	proptest.Check(t, func(t *testing.T, r *rand.Rand) {
+		// SETUP
+		run := randomPipelineRunForAdmission(r)
+		username := proptest.DNSLabel(r, 20)
+		req := newCreateRequest(t, run, username)
+
+		// EXERCISE
+		response := examinee.mutate(req)
+
+		// VERIFY
+		assert.Assert(t, response.Allowed, "%+v", response.Result)
+		mutatedRaw := applyPatch(t, req.Object.Raw, response.Patch)
+		mutated := &api.PipelineRun{}
+		assert.NilError(t, json.Unmarshal(mutatedRaw, mutated))
+
+		// the spec is only extended by defaults
+		assert.Equal(t, run.Spec.JenkinsFile.URL, mutated.Spec.JenkinsFile.URL)
+		assert.DeepEqual(t, run.Spec.Args, mutated.Spec.Args)
+		assert.DeepEqual(t, run.Spec.Secrets, mutated.Spec.Secrets)
+		assert.Assert(t, mutated.Spec.JenkinsFile.Revision != "")
+
+		// metadata set by clients is corrected
+		assert.Equal(t, username, mutated.Annotations[api.AnnotationCreatedBy])
+		assert.Equal(t, "uid-"+username, mutated.Annotations[api.AnnotationCreatedByUID])
+		if run.Spec.TriggeredBy != nil {
+			assert.Equal(t, run.Spec.TriggeredBy.Type, mutated.Labels[api.LabelTriggeredByType])
+		} else {
+			_, exists := mutated.Labels[api.LabelTriggeredByType]
+			assert.Assert(t, !exists)
+		}
+
+		// entries set by clients which are not corrected are retained
+		for key, value := range run.Labels {
+			if key != api.LabelTriggeredByType && key != api.LabelTriggeredByID {
+				assert.Equal(t, value, mutated.Labels[key], key)
+			}
+		}
+
+		// the mutated object is not mutated again
+		req.Object.Raw = mutatedRaw
+		response = examinee.mutate(req)
+		assert.Assert(t, response.Allowed, "%+v", response.Result)
+		assert.Assert(t, response.Patch == nil, "unexpected patch of mutated object: %s", string(response.Patch))
+	})
+}
+
+func Test_SpecLimits_Property_DeniesExactlyExceedingSpecs(t *testing.T) {
+	t.Parallel()
+
+	proptest.Check(t, func(t *testing.T, r *rand.Rand) {
+		// SETUP
+		spec := k8sfake.RandomPipelineSpec(r)
+		argBytes := 0
+		for key, value := range spec.Args {
+			argBytes += len(key) + len(value)
+		}
+		secrets := len(spec.Secrets) + len(spec.SealedSecrets) + len(spec.ImagePullSecrets)
+		// limits around the actual values, zero disables a limit
+		examinee := SpecLimits{
+			MaxArgs:     len(spec.Args) + r.Intn(3) - 1,
+			MaxArgBytes: argBytes + r.Intn(3) - 1,
+			MaxSecrets:  secrets + r.Intn(3) - 1,
+		}
+		exceeds := func(value, limit int) bool { return limit > 0 && value > limit }
+		expectDenial := exceeds(len(spec.Args), examinee.MaxArgs) ||
+			exceeds(argBytes, examinee.MaxArgBytes) ||
+			exceeds(secrets, examinee.MaxSecrets)
+		req := &admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Resource:  pipelineRunsResource,
+			Object:    newRawPipelineRun(t, spec),
+		}
+
+		// EXERCISE
+		err := examinee.Validate(req)
+
+		// VERIFY
+		assert.Equal(t, expectDenial, err != nil, "limits: %+v, error: %v", examinee, err)
+
+		// updates not changing the spec are always allowed
+		req.Operation = admissionv1.Update
+		req.OldObject = newRawPipelineRun(t, spec)
+		assert.NilError(t, examinee.Validate(req))
+	})
+}
+
+func Test_TriggeredByValidator_Property_AllowsValidSpecs(t *testing.T) {
+	t.Parallel()
+
+	proptest.Check(t, func(t *testing.T, r *rand.Rand) {
+		// SETUP
+		spec := k8sfake.RandomPipelineSpec(r)
+		req := &admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Resource:  pipelineRunsResource,
+			Object:    newRawPipelineRun(t, spec),
+		}
+
+		// EXERCISE + VERIFY
+		assert.NilError(t, TriggeredByValidator(req))
+
+		// changing the trigger after creation is denied
+		changed := spec
+		changed.TriggeredBy = &api.TriggeredBy{Type: "other", ID: "other"}
+		req.Operation = admissionv1.Update
+		req.OldObject = newRawPipelineRun(t, spec)
+		req.Object = newRawPipelineRun(t, changed)
+		assert.ErrorContains(t, TriggeredByValidator(req), "spec.triggeredBy cannot be changed")
+	})
+}
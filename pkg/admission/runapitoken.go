@@ -0,0 +1,76 @@
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// RunAPITokenValidator denies updates of pipeline runs by the per-run
+// service accounts of the run controller that change anything else than
+// annotations with prefix api.AnnotationProgressPrefix. RBAC already
+// restricts these service accounts to their own pipeline run, but cannot
+// restrict the fields being changed.
+// Requests of other users and for other resources are allowed.
+func RunAPITokenValidator(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Resource != "pipelineruns" || !isRunAPIServiceAccount(req) {
+		return nil
+	}
+	if req.Operation != admissionv1.Update {
+		return errors.New("per-run service accounts may only update pipeline runs")
+	}
+
+	obj, err := decodeWithoutProgress(req.Object)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode object")
+	}
+	oldObj, err := decodeWithoutProgress(req.OldObject)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode old object")
+	}
+	if !reflect.DeepEqual(obj, oldObj) {
+		return errors.Errorf("per-run service accounts may only change annotations with prefix %q", api.AnnotationProgressPrefix)
+	}
+	return nil
+}
+
+// isRunAPIServiceAccount returns whether the request has been issued by
+// a per-run service account in the namespace of the requested object.
+func isRunAPIServiceAccount(req *admissionv1.AdmissionRequest) bool {
+	prefix := "system:serviceaccount:" + req.Namespace + ":" + naming.RunAPIServiceAccountPrefix + "-"
+	return strings.HasPrefix(req.UserInfo.Username, prefix)
+}
+
+// decodeWithoutProgress decodes the given pipeline run into a generic
+// map without the status, the progress annotations and the metadata
+// fields maintained by the API server.
+func decodeWithoutProgress(raw runtime.RawExtension) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(raw.Raw, &obj); err != nil {
+		return nil, err
+	}
+	delete(obj, "status")
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata == nil {
+		return obj, nil
+	}
+	for _, field := range []string{"resourceVersion", "generation", "managedFields"} {
+		delete(metadata, field)
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	for key := range annotations {
+		if strings.HasPrefix(key, api.AnnotationProgressPrefix) {
+			delete(annotations, key)
+		}
+	}
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+	return obj, nil
+}
@@ -0,0 +1,142 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRawPipelineRunForRunAPIToken(t *testing.T, annotations map[string]string, labels map[string]string, resourceVersion string, state api.State) runtime.RawExtension {
+	t.Helper()
+	obj := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "run1",
+			Namespace:       "ns1",
+			Annotations:     annotations,
+			Labels:          labels,
+			ResourceVersion: resourceVersion,
+		},
+		Spec:   api.PipelineSpec{},
+		Status: api.PipelineStatus{State: state},
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func Test_RunAPITokenValidator(t *testing.T) {
+	t.Parallel()
+
+	const runAPIUser = "system:serviceaccount:ns1:steward-run-api-run1"
+	progressStage := api.AnnotationProgressPrefix + "stage"
+
+	for _, tc := range []struct {
+		name        string
+		operation   admissionv1.Operation
+		resource    string
+		username    string
+		oldObject   runtime.RawExtension
+		object      runtime.RawExtension
+		expectedErr string
+	}{
+		{
+			name:      "progress annotation added",
+			operation: admissionv1.Update,
+			username:  runAPIUser,
+			oldObject: newRawPipelineRunForRunAPIToken(t, nil, nil, "1", api.StateRunning),
+			object:    newRawPipelineRunForRunAPIToken(t, map[string]string{progressStage: "build"}, nil, "2", api.StateRunning),
+		},
+		{
+			name:      "progress annotation changed, other annotations kept",
+			operation: admissionv1.Update,
+			username:  runAPIUser,
+			oldObject: newRawPipelineRunForRunAPIToken(t, map[string]string{progressStage: "build", "a": "1"}, nil, "1", api.StateRunning),
+			object:    newRawPipelineRunForRunAPIToken(t, map[string]string{progressStage: "test", "a": "1"}, nil, "2", api.StateRunning),
+		},
+		{
+			name:        "other annotation changed",
+			operation:   admissionv1.Update,
+			username:    runAPIUser,
+			oldObject:   newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "1"}, nil, "1", api.StateRunning),
+			object:      newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "2"}, nil, "2", api.StateRunning),
+			expectedErr: `per-run service accounts may only change annotations with prefix "progress.steward.sap.com/"`,
+		},
+		{
+			name:        "label added",
+			operation:   admissionv1.Update,
+			username:    runAPIUser,
+			oldObject:   newRawPipelineRunForRunAPIToken(t, nil, nil, "1", api.StateRunning),
+			object:      newRawPipelineRunForRunAPIToken(t, nil, map[string]string{"a": "1"}, "2", api.StateRunning),
+			expectedErr: `per-run service accounts may only change annotations with prefix "progress.steward.sap.com/"`,
+		},
+		{
+			name:      "status ignored",
+			operation: admissionv1.Update,
+			username:  runAPIUser,
+			oldObject: newRawPipelineRunForRunAPIToken(t, nil, nil, "1", api.StateRunning),
+			object:    newRawPipelineRunForRunAPIToken(t, nil, nil, "2", api.StateCleaning),
+		},
+		{
+			name:        "delete denied",
+			operation:   admissionv1.Delete,
+			username:    runAPIUser,
+			oldObject:   newRawPipelineRunForRunAPIToken(t, nil, nil, "1", api.StateRunning),
+			expectedErr: "per-run service accounts may only update pipeline runs",
+		},
+		{
+			name:      "other service account",
+			operation: admissionv1.Update,
+			username:  "system:serviceaccount:ns1:default",
+			oldObject: newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "1"}, nil, "1", api.StateRunning),
+			object:    newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "2"}, nil, "2", api.StateRunning),
+		},
+		{
+			name:      "per-run service account of other namespace",
+			operation: admissionv1.Update,
+			username:  "system:serviceaccount:ns2:steward-run-api-run1",
+			oldObject: newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "1"}, nil, "1", api.StateRunning),
+			object:    newRawPipelineRunForRunAPIToken(t, map[string]string{"a": "2"}, nil, "2", api.StateRunning),
+		},
+		{
+			name:      "other resource",
+			operation: admissionv1.Update,
+			resource:  "tenants",
+			username:  runAPIUser,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			resource := tc.resource
+			if resource == "" {
+				resource = "pipelineruns"
+			}
+			req := &admissionv1.AdmissionRequest{
+				Resource:  metav1.GroupVersionResource{Resource: resource},
+				Operation: tc.operation,
+				Namespace: "ns1",
+				UserInfo:  authenticationv1.UserInfo{Username: tc.username},
+				OldObject: tc.oldObject,
+				Object:    tc.object,
+			}
+
+			// EXERCISE
+			err := RunAPITokenValidator(req)
+
+			// VERIFY
+			if tc.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
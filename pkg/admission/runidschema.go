@@ -0,0 +1,95 @@
+package admission
+
+import (
+	"encoding/json"
+	"reflect"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// RunIDSchemaValidator denies pipeline runs whose run ID does not match a
+// JSON schema, so that malformed run IDs do not break the indexing of
+// pipeline logs. The run ID is the value of field `runid` of log entries,
+// i.e. the one defined by `spec.logging.sink` or
+// `spec.logging.elasticsearch.runID`.
+type RunIDSchemaValidator struct {
+	schema *jsonSchema
+}
+
+// NewRunIDSchemaValidator creates a new RunIDSchemaValidator for the given
+// JSON schema. See type jsonSchema for the supported keywords.
+func NewRunIDSchemaValidator(schemaJSON []byte) (*RunIDSchemaValidator, error) {
+	schema, err := parseJSONSchema(schemaJSON)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid run ID schema")
+	}
+	return &RunIDSchemaValidator{schema: schema}, nil
+}
+
+// Validate denies the creation of pipeline runs logging to Elasticsearch
+// whose run ID does not match the schema. Updates are denied only if the
+// run ID gets changed, so that existing pipeline runs can still be
+// processed after the schema has been changed.
+// Requests for other resources are allowed.
+func (v *RunIDSchemaValidator) Validate(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Resource != "pipelineruns" {
+		return nil
+	}
+
+	switch req.Operation {
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return nil
+	}
+
+	spec, err := decodePipelineSpec(req.Object)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode object")
+	}
+	runID, path, ok := logRunID(spec.Logging)
+	if !ok {
+		return nil
+	}
+	if req.Operation == admissionv1.Update {
+		oldSpec, err := decodePipelineSpec(req.OldObject)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode old object")
+		}
+		if oldRunID, _, ok := logRunID(oldSpec.Logging); ok && reflect.DeepEqual(runID, oldRunID) {
+			return nil
+		}
+	}
+	return v.check(runID, path)
+}
+
+// logRunID returns the run ID attached to log entries, the path of the
+// field defining it and whether a log sink is configured at all.
+func logRunID(logging *api.Logging) (*api.CustomJSON, string, bool) {
+	switch {
+	case logging == nil:
+		return nil, "", false
+	case logging.Sink != nil:
+		return logging.Sink.RunIDJSON(), "spec.logging.sink", true
+	case logging.Elasticsearch != nil:
+		return logging.Elasticsearch.RunID, "spec.logging.elasticsearch.runID", true
+	}
+	return nil, "", false
+}
+
+func (v *RunIDSchemaValidator) check(runID *api.CustomJSON, path string) error {
+	// normalize the value to the representation used by the schema
+	runIDJSON, err := json.Marshal(runID)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode run ID")
+	}
+	var value interface{}
+	if err := json.Unmarshal(runIDJSON, &value); err != nil {
+		return errors.Wrap(err, "failed to decode run ID")
+	}
+	if err := v.schema.validate(value, path); err != nil {
+		return errors.WithMessage(err, "run ID does not match the schema")
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package admission
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const testRunIDSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"required": ["jobId", "buildNumber"],
+	"properties": {
+		"jobId": {"type": "string", "pattern": "^[a-z0-9-]+$", "maxLength": 10},
+		"buildNumber": {"type": "integer", "minimum": 1},
+		"tags": {"type": "array", "items": {"enum": ["a", "b"]}, "maxItems": 2}
+	},
+	"additionalProperties": false
+}`
+
+func newRunIDSpec(runID interface{}) api.PipelineSpec {
+	return api.PipelineSpec{
+		Logging: &api.Logging{
+			Elasticsearch: &api.Elasticsearch{
+				RunID: &api.CustomJSON{Value: runID},
+			},
+		},
+	}
+}
+
+func Test_NewRunIDSchemaValidator_InvalidSchema(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		schema        string
+		expectedError string
+	}{
+		{"invalid JSON", `{`, "invalid run ID schema: invalid JSON: unexpected end of JSON input"},
+		{"not an object", `"foo"`, "invalid run ID schema: #: schema must be an object or a boolean"},
+		{"unsupported keyword", `{"oneOf": []}`, "invalid run ID schema: #/oneOf: unsupported keyword"},
+		{"invalid type", `{"type": "foo"}`, "invalid run ID schema: #/type: invalid type foo"},
+		{"invalid nested schema", `{"properties": {"p1": {"maxLength": -1}}}`, "invalid run ID schema: #/properties/p1/maxLength: must be a non-negative integer"},
+		{"invalid pattern", `{"pattern": "("}`, "invalid run ID schema: #/pattern: invalid regular expression: error parsing regexp: missing closing ): `(`"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			examinee, err := NewRunIDSchemaValidator([]byte(tc.schema))
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Assert(t, examinee == nil)
+		})
+	}
+}
+
+func Test_RunIDSchemaValidator_Validate_Create(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		spec          api.PipelineSpec
+		expectedError string
+	}{
+		{
+			name: "no logging",
+			spec: api.PipelineSpec{},
+		},
+		{
+			name: "valid",
+			spec: newRunIDSpec(map[string]interface{}{"jobId": "job-1", "buildNumber": 3, "tags": []interface{}{"a"}}),
+		},
+		{
+			name:          "no run ID",
+			spec:          api.PipelineSpec{Logging: &api.Logging{Elasticsearch: &api.Elasticsearch{}}},
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID: must be of type object",
+		},
+		{
+			name:          "wrong type",
+			spec:          newRunIDSpec("job1"),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID: must be of type object",
+		},
+		{
+			name:          "missing property",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1"}),
+			expectedError: `run ID does not match the schema: spec.logging.elasticsearch.runID: property "buildNumber" is required`,
+		},
+		{
+			name:          "additional property",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1", "buildNumber": 1, "foo": "bar"}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.foo: no value is allowed",
+		},
+		{
+			name:          "pattern mismatch",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "Job1", "buildNumber": 1}),
+			expectedError: `run ID does not match the schema: spec.logging.elasticsearch.runID.jobId: must match pattern "^[a-z0-9-]+$"`,
+		},
+		{
+			name:          "string too long",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1234567890", "buildNumber": 1}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.jobId: must have at most 10 characters",
+		},
+		{
+			name:          "not an integer",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1", "buildNumber": 1.5}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.buildNumber: must be of type integer",
+		},
+		{
+			name:          "below minimum",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1", "buildNumber": 0}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.buildNumber: must be greater than or equal to 1",
+		},
+		{
+			name:          "invalid item",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1", "buildNumber": 1, "tags": []interface{}{"a", "c"}}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.tags[1]: must be one of the values of the enumeration",
+		},
+		{
+			name:          "too many items",
+			spec:          newRunIDSpec(map[string]interface{}{"jobId": "job1", "buildNumber": 1, "tags": []interface{}{"a", "b", "a"}}),
+			expectedError: "run ID does not match the schema: spec.logging.elasticsearch.runID.tags: must have at most 2 items",
+		},
+		{
+			name: "sink",
+			spec: api.PipelineSpec{
+				Logging: &api.Logging{
+					Sink: &api.LogSink{Type: api.LogSinkTypeElasticsearch, Fields: map[string]string{"jobId": "job1"}},
+				},
+			},
+			expectedError: `run ID does not match the schema: spec.logging.sink: property "buildNumber" is required`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee, err := NewRunIDSchemaValidator([]byte(testRunIDSchema))
+			assert.NilError(t, err)
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  pipelineRunsResource,
+				Object:    newRawPipelineRun(t, tc.spec),
+			}
+
+			// EXERCISE
+			err = examinee.Validate(req)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_RunIDSchemaValidator_Validate_Update_UnchangedRunID(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, err := NewRunIDSchemaValidator([]byte(testRunIDSchema))
+	assert.NilError(t, err)
+	oldSpec := newRunIDSpec("job1")
+	newSpec := newRunIDSpec("job1")
+	newSpec.Intent = api.IntentAbort
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Resource:  pipelineRunsResource,
+		Object:    newRawPipelineRun(t, newSpec),
+		OldObject: newRawPipelineRun(t, oldSpec),
+	}
+
+	// EXERCISE
+	err = examinee.Validate(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_RunIDSchemaValidator_Validate_Update_ChangedRunID(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, err := NewRunIDSchemaValidator([]byte(testRunIDSchema))
+	assert.NilError(t, err)
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Resource:  pipelineRunsResource,
+		Object:    newRawPipelineRun(t, newRunIDSpec("job2")),
+		OldObject: newRawPipelineRun(t, newRunIDSpec("job1")),
+	}
+
+	// EXERCISE
+	err = examinee.Validate(req)
+
+	// VERIFY
+	assert.Error(t, err, "run ID does not match the schema: spec.logging.elasticsearch.runID: must be of type object")
+}
+
+func Test_RunIDSchemaValidator_Validate_IgnoresOtherResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, err := NewRunIDSchemaValidator([]byte(testRunIDSchema))
+	assert.NilError(t, err)
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource: metav1.GroupVersionResource{
+			Group:    "steward.sap.com",
+			Version:  "v1alpha1",
+			Resource: "tenants",
+		},
+		Object: runtime.RawExtension{Raw: []byte(`{"spec":{"logging":{"elasticsearch":{"runID":"foo"}}}}`)},
+	}
+
+	// EXERCISE
+	err = examinee.Validate(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
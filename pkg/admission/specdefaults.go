@@ -0,0 +1,122 @@
+package admission
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SpecDefaults defines default values for omitted fields of the spec of
+// pipeline runs. A zero value disables the respective default.
+type SpecDefaults struct {
+	// Timeout is the default for `spec.timeout`.
+	Timeout *metav1.Duration
+
+	// NetworkProfile is the default for `spec.profiles.network`.
+	NetworkProfile string
+
+	// JenkinsfileRevision is the default for `spec.jenkinsFile.revision`,
+	// typically the name of the default branch.
+	JenkinsfileRevision string
+
+	// LoggingRunID defines whether the run ID of the log sink defaults
+	// to an object identifying the pipeline run by namespace and name,
+	// i.e. `spec.logging.elasticsearch.runID` or
+	// `spec.logging.sink.fields`, if `spec.logging.sink.runID` is not
+	// set either.
+	LoggingRunID bool
+}
+
+// defaultLoggingRunID is the value of `spec.logging.elasticsearch.runID`
+// and `spec.logging.sink.fields` set by SpecDefaults.
+type defaultLoggingRunID struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// IsEmpty returns whether no default is defined.
+func (d SpecDefaults) IsEmpty() bool {
+	return d.Timeout == nil && d.NetworkProfile == "" && d.JenkinsfileRevision == "" && !d.LoggingRunID
+}
+
+// Mutate sets omitted fields of the spec of pipeline runs to their
+// default values on creation, so that clients do not need to
+// duplicate the defaults of the Steward installation.
+// Fields set to an empty value count as omitted. Parent objects which
+// are omitted entirely are not added, except `spec.profiles`.
+// The default of the run ID of the log sink is only set if the name of
+// the pipeline run is known, i.e. it is not generated.
+// Requests for other resources and other operations are ignored.
+func (d SpecDefaults) Mutate(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Resource.Resource != "pipelineruns" || req.Operation != admissionv1.Create {
+		return nil, nil
+	}
+
+	// The spec is decoded generically to distinguish omitted fields
+	// from fields with empty values.
+	obj := struct {
+		Spec map[string]interface{} `json:"spec"`
+	}{}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	spec := obj.Spec
+	if spec == nil {
+		return nil, nil
+	}
+
+	var ops []PatchOperation
+
+	// fields of pipeline runs referring to a template default to the
+	// fields of the template, which is resolved by the run controller
+	templated := spec["templateRef"] != nil
+
+	if d.Timeout != nil && !templated && isEmptyValue(spec["timeout"]) {
+		ops = append(ops, PatchOperation{Op: "add", Path: "/spec/timeout", Value: d.Timeout.Duration.String()})
+	}
+
+	if d.NetworkProfile != "" && !templated {
+		if profiles, ok := spec["profiles"].(map[string]interface{}); ok {
+			if isEmptyValue(profiles["network"]) {
+				ops = append(ops, PatchOperation{Op: "add", Path: "/spec/profiles/network", Value: d.NetworkProfile})
+			}
+		} else {
+			ops = append(ops, PatchOperation{Op: "add", Path: "/spec/profiles", Value: map[string]string{"network": d.NetworkProfile}})
+		}
+	}
+
+	if d.JenkinsfileRevision != "" && !templated {
+		if jenkinsFile, ok := spec["jenkinsFile"].(map[string]interface{}); ok && isEmptyValue(jenkinsFile["revision"]) {
+			ops = append(ops, PatchOperation{Op: "add", Path: "/spec/jenkinsFile/revision", Value: d.JenkinsfileRevision})
+		}
+	}
+
+	if d.LoggingRunID && req.Name != "" {
+		if logging, ok := spec["logging"].(map[string]interface{}); ok {
+			if elasticsearch, ok := logging["elasticsearch"].(map[string]interface{}); ok && elasticsearch["runID"] == nil {
+				ops = append(ops, PatchOperation{
+					Op:    "add",
+					Path:  "/spec/logging/elasticsearch/runID",
+					Value: defaultLoggingRunID{Namespace: req.Namespace, Name: req.Name},
+				})
+			}
+			if sink, ok := logging["sink"].(map[string]interface{}); ok && isEmptyValue(sink["runID"]) && sink["fields"] == nil {
+				ops = append(ops, PatchOperation{
+					Op:    "add",
+					Path:  "/spec/logging/sink/fields",
+					Value: defaultLoggingRunID{Namespace: req.Namespace, Name: req.Name},
+				})
+			}
+		}
+	}
+
+	return ops, nil
+}
+
+// isEmptyValue returns whether a generically decoded JSON value is
+// null or an empty string.
+func isEmptyValue(value interface{}) bool {
+	return value == nil || value == ""
+}
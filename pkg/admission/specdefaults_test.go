@@ -0,0 +1,200 @@
+package admission
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newSpecDefaultsRequest(operation admissionv1.Operation, name, spec string) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		Operation: operation,
+		Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+		Namespace: "ns1",
+		Name:      name,
+		Object:    runtime.RawExtension{Raw: []byte(`{"spec":` + spec + `}`)},
+	}
+}
+
+func Test_SpecDefaults_Mutate(t *testing.T) {
+	t.Parallel()
+
+	allDefaults := SpecDefaults{
+		Timeout:             &metav1.Duration{Duration: 90 * time.Minute},
+		NetworkProfile:      "profile1",
+		JenkinsfileRevision: "main",
+		LoggingRunID:        true,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		defaults SpecDefaults
+		runName  string
+		spec     string
+		expected []PatchOperation
+	}{
+		{
+			name:     "no defaults",
+			defaults: SpecDefaults{},
+			runName:  "run1",
+			spec:     `{"jenkinsFile":{"repoUrl":"url1"},"logging":{"elasticsearch":{}}}`,
+		},
+		{
+			name:     "all omitted",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"jenkinsFile":{"repoUrl":"url1"},"logging":{"elasticsearch":{}}}`,
+			expected: []PatchOperation{
+				{Op: "add", Path: "/spec/timeout", Value: "1h30m0s"},
+				{Op: "add", Path: "/spec/profiles", Value: map[string]string{"network": "profile1"}},
+				{Op: "add", Path: "/spec/jenkinsFile/revision", Value: "main"},
+				{Op: "add", Path: "/spec/logging/elasticsearch/runID", Value: defaultLoggingRunID{Namespace: "ns1", Name: "run1"}},
+			},
+		},
+		{
+			name:     "all empty",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"","profiles":{"network":""},"jenkinsFile":{"revision":""},"logging":{"elasticsearch":{"runID":null}}}`,
+			expected: []PatchOperation{
+				{Op: "add", Path: "/spec/timeout", Value: "1h30m0s"},
+				{Op: "add", Path: "/spec/profiles/network", Value: "profile1"},
+				{Op: "add", Path: "/spec/jenkinsFile/revision", Value: "main"},
+				{Op: "add", Path: "/spec/logging/elasticsearch/runID", Value: defaultLoggingRunID{Namespace: "ns1", Name: "run1"}},
+			},
+		},
+		{
+			name:     "all set",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"5m","profiles":{"network":"other"},"jenkinsFile":{"revision":"dev"},"logging":{"elasticsearch":{"runID":{"id":1}}}}`,
+		},
+		{
+			name:     "sink run ID omitted",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"5m","profiles":{"network":"other"},"jenkinsFile":{"revision":"dev"},"logging":{"sink":{"type":"elasticsearch","runID":""}}}`,
+			expected: []PatchOperation{
+				{Op: "add", Path: "/spec/logging/sink/fields", Value: defaultLoggingRunID{Namespace: "ns1", Name: "run1"}},
+			},
+		},
+		{
+			name:     "sink run ID set",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"5m","profiles":{"network":"other"},"jenkinsFile":{"revision":"dev"},"logging":{"sink":{"type":"elasticsearch","runID":"run1"}}}`,
+		},
+		{
+			name:     "sink fields set",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"5m","profiles":{"network":"other"},"jenkinsFile":{"revision":"dev"},"logging":{"sink":{"type":"elasticsearch","fields":{}}}}`,
+		},
+		{
+			name:     "template referenced",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"templateRef":{"name":"template1"},"jenkinsFile":{"revision":""}}`,
+		},
+		{
+			name:     "parents omitted",
+			defaults: allDefaults,
+			runName:  "run1",
+			spec:     `{"timeout":"5m","profiles":{"network":"other"}}`,
+		},
+		{
+			name:     "generated name",
+			defaults: SpecDefaults{LoggingRunID: true},
+			runName:  "",
+			spec:     `{"logging":{"elasticsearch":{}}}`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := newSpecDefaultsRequest(admissionv1.Create, tc.runName, tc.spec)
+
+			// EXERCISE
+			result, err := tc.defaults.Mutate(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_SpecDefaults_Mutate_Ignored(t *testing.T) {
+	t.Parallel()
+
+	defaults := SpecDefaults{NetworkProfile: "profile1"}
+
+	for _, tc := range []struct {
+		name string
+		req  *admissionv1.AdmissionRequest
+	}{
+		{
+			name: "update",
+			req:  newSpecDefaultsRequest(admissionv1.Update, "run1", `{}`),
+		},
+		{
+			name: "other resource",
+			req: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  metav1.GroupVersionResource{Resource: "tenants"},
+				Object:    runtime.RawExtension{Raw: []byte(`{"spec":{}}`)},
+			},
+		},
+		{
+			name: "no spec",
+			req: &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+				Object:    runtime.RawExtension{Raw: []byte(`{}`)},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := defaults.Mutate(tc.req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
+
+func Test_SpecDefaults_Mutate_InvalidObject(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  metav1.GroupVersionResource{Resource: "pipelineruns"},
+		Object:    runtime.RawExtension{Raw: []byte(`{"spec":[]}`)},
+	}
+
+	// EXERCISE
+	_, err := SpecDefaults{NetworkProfile: "profile1"}.Mutate(req)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to decode object")
+}
+
+func Test_SpecDefaults_IsEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert.Assert(t, SpecDefaults{}.IsEmpty())
+	assert.Assert(t, !SpecDefaults{LoggingRunID: true}.IsEmpty())
+	assert.Assert(t, !SpecDefaults{JenkinsfileRevision: "main"}.IsEmpty())
+}
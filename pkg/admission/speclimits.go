@@ -0,0 +1,103 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SpecLimits defines upper bounds for the spec of pipeline runs.
+// A zero or negative value disables the respective limit.
+type SpecLimits struct {
+	// MaxArgs is the maximum number of entries in `spec.args`.
+	MaxArgs int
+
+	// MaxArgBytes is the maximum total size in bytes of all keys and
+	// values in `spec.args`.
+	MaxArgBytes int
+
+	// MaxSecrets is the maximum total number of entries in `spec.secrets`,
+	// `spec.sealedSecrets` and `spec.imagePullSecrets`.
+	MaxSecrets int
+}
+
+// pipelineRunWithSpec is used to decode the spec of pipeline runs.
+type pipelineRunWithSpec struct {
+	Spec api.PipelineSpec `json:"spec"`
+}
+
+// Validate denies the creation of pipeline runs whose spec exceeds the
+// limits. Updates are denied only if the spec gets changed, so that
+// existing pipeline runs can still be processed after limits have been
+// lowered.
+// Requests for other resources are allowed.
+func (l SpecLimits) Validate(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Resource != "pipelineruns" {
+		return nil
+	}
+
+	switch req.Operation {
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return nil
+	}
+
+	spec, err := decodePipelineSpec(req.Object)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode object")
+	}
+	if req.Operation == admissionv1.Update {
+		oldSpec, err := decodePipelineSpec(req.OldObject)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode old object")
+		}
+		if reflect.DeepEqual(spec, oldSpec) {
+			return nil
+		}
+	}
+	return l.check(spec)
+}
+
+func (l SpecLimits) check(spec *api.PipelineSpec) error {
+	if l.MaxArgs > 0 && len(spec.Args) > l.MaxArgs {
+		return fmt.Errorf(
+			"spec.args has %d entries, which exceeds the maximum of %d",
+			len(spec.Args), l.MaxArgs,
+		)
+	}
+	if l.MaxArgBytes > 0 {
+		size := 0
+		for key, value := range spec.Args {
+			size += len(key) + len(value)
+		}
+		if size > l.MaxArgBytes {
+			return fmt.Errorf(
+				"spec.args has a total size of %d bytes, which exceeds the maximum of %d bytes",
+				size, l.MaxArgBytes,
+			)
+		}
+	}
+	if l.MaxSecrets > 0 {
+		count := len(spec.Secrets) + len(spec.SealedSecrets) + len(spec.ImagePullSecrets)
+		if count > l.MaxSecrets {
+			return fmt.Errorf(
+				"spec.secrets, spec.sealedSecrets and spec.imagePullSecrets have %d entries in total, which exceeds the maximum of %d",
+				count, l.MaxSecrets,
+			)
+		}
+	}
+	return nil
+}
+
+func decodePipelineSpec(raw runtime.RawExtension) (*api.PipelineSpec, error) {
+	obj := &pipelineRunWithSpec{}
+	if err := json.Unmarshal(raw.Raw, obj); err != nil {
+		return nil, err
+	}
+	return &obj.Spec, nil
+}
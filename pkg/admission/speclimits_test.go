@@ -0,0 +1,184 @@
+package admission
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var pipelineRunsResource = metav1.GroupVersionResource{
+	Group:    "steward.sap.com",
+	Version:  "v1alpha1",
+	Resource: "pipelineruns",
+}
+
+func newRawPipelineRun(t *testing.T, spec api.PipelineSpec) runtime.RawExtension {
+	t.Helper()
+	obj := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "run1",
+			Namespace: "ns1",
+		},
+		Spec: spec,
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func Test_SpecLimits_Validate_Create(t *testing.T) {
+	t.Parallel()
+
+	limits := SpecLimits{MaxArgs: 2, MaxArgBytes: 10, MaxSecrets: 2}
+
+	for _, tc := range []struct {
+		name          string
+		limits        SpecLimits
+		spec          api.PipelineSpec
+		expectedError string
+	}{
+		{
+			name:   "empty spec",
+			limits: limits,
+			spec:   api.PipelineSpec{},
+		},
+		{
+			name:   "at limits",
+			limits: limits,
+			spec: api.PipelineSpec{
+				Args:    map[string]string{"k1": "v1", "k2": "v2xx"},
+				Secrets: []string{"s1"},
+				SealedSecrets: []api.SealedSecret{
+					{Name: "s2"},
+				},
+			},
+		},
+		{
+			name:   "too many args",
+			limits: limits,
+			spec: api.PipelineSpec{
+				Args: map[string]string{"k1": "", "k2": "", "k3": ""},
+			},
+			expectedError: "spec.args has 3 entries, which exceeds the maximum of 2",
+		},
+		{
+			name:   "args too large",
+			limits: limits,
+			spec: api.PipelineSpec{
+				Args: map[string]string{"k1": "v1xxxxxxx"},
+			},
+			expectedError: "spec.args has a total size of 11 bytes, which exceeds the maximum of 10 bytes",
+		},
+		{
+			name:   "too many secrets",
+			limits: limits,
+			spec: api.PipelineSpec{
+				Secrets:          []string{"s1"},
+				SealedSecrets:    []api.SealedSecret{{Name: "s2"}},
+				ImagePullSecrets: []string{"s3"},
+			},
+			expectedError: "spec.secrets, spec.sealedSecrets and spec.imagePullSecrets have 3 entries in total, which exceeds the maximum of 2",
+		},
+		{
+			name:   "limits disabled",
+			limits: SpecLimits{},
+			spec: api.PipelineSpec{
+				Args:    map[string]string{"k1": strings.Repeat("x", 1000), "k2": "", "k3": ""},
+				Secrets: []string{"s1", "s2", "s3"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  pipelineRunsResource,
+				Object:    newRawPipelineRun(t, tc.spec),
+			}
+
+			// EXERCISE
+			err := tc.limits.Validate(req)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_SpecLimits_Validate_Update_UnchangedSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := SpecLimits{MaxArgs: 1}
+	spec := api.PipelineSpec{
+		Args: map[string]string{"k1": "", "k2": ""},
+	}
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Resource:  pipelineRunsResource,
+		Object:    newRawPipelineRun(t, spec),
+		OldObject: newRawPipelineRun(t, spec),
+	}
+
+	// EXERCISE
+	err := examinee.Validate(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_SpecLimits_Validate_Update_ChangedSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := SpecLimits{MaxArgs: 1}
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Resource:  pipelineRunsResource,
+		Object: newRawPipelineRun(t, api.PipelineSpec{
+			Args: map[string]string{"k1": "", "k2": ""},
+		}),
+		OldObject: newRawPipelineRun(t, api.PipelineSpec{}),
+	}
+
+	// EXERCISE
+	err := examinee.Validate(req)
+
+	// VERIFY
+	assert.Error(t, err, "spec.args has 2 entries, which exceeds the maximum of 1")
+}
+
+func Test_SpecLimits_Validate_IgnoresOtherResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := SpecLimits{MaxArgs: 1}
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource: metav1.GroupVersionResource{
+			Group:    "steward.sap.com",
+			Version:  "v1alpha1",
+			Resource: "tenants",
+		},
+		Object: runtime.RawExtension{Raw: []byte(`{"spec":{"args":{"k1":"","k2":""}}}`)},
+	}
+
+	// EXERCISE
+	err := examinee.Validate(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
@@ -0,0 +1,169 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// tenantNameIndex is the name of the tenant index by tenant name.
+	tenantNameIndex = "tenantName"
+
+	// tenantNamespaceNameIndex is the name of the tenant index by
+	// assigned tenant namespace name.
+	tenantNamespaceNameIndex = "tenantNamespaceName"
+)
+
+// TenantIndexers returns the indexers that must be added to the tenant
+// informer whose indexer is passed to NewTenantNamespaceClashDetector.
+func TenantIndexers() cache.Indexers {
+	return cache.Indexers{
+		tenantNameIndex: func(obj interface{}) ([]string, error) {
+			tenant, ok := obj.(*api.Tenant)
+			if !ok {
+				return nil, nil
+			}
+			return []string{tenant.GetName()}, nil
+		},
+		tenantNamespaceNameIndex: func(obj interface{}) ([]string, error) {
+			tenant, ok := obj.(*api.Tenant)
+			if !ok || tenant.Status.TenantNamespaceName == "" {
+				return nil, nil
+			}
+			return []string{tenant.Status.TenantNamespaceName}, nil
+		},
+	}
+}
+
+// TenantNamespaceClashDetector denies the creation of tenants whose tenant
+// namespace would get the same name as the tenant namespace of another
+// tenant or another existing namespace.
+// Such clashes are only possible if the client namespace configures a
+// tenant namespace suffix length of zero, i.e. the tenant namespace name
+// consists of the client's prefix and the tenant name only. Without this
+// check the tenant controller fails later when creating the namespace.
+type TenantNamespaceClashDetector struct {
+	namespaces corev1client.NamespaceInterface
+	tenants    cache.Indexer
+}
+
+// NewTenantNamespaceClashDetector creates a new TenantNamespaceClashDetector.
+// `tenants` must be the indexer of a tenant informer with the indexers
+// returned by TenantIndexers.
+func NewTenantNamespaceClashDetector(namespaces corev1client.NamespaceInterface, tenants cache.Indexer) *TenantNamespaceClashDetector {
+	return &TenantNamespaceClashDetector{
+		namespaces: namespaces,
+		tenants:    tenants,
+	}
+}
+
+// Validate denies the creation of tenants with clashing tenant namespace
+// names.
+// Requests for other resources or operations are allowed.
+func (d *TenantNamespaceClashDetector) Validate(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Resource != "tenants" || req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	tenant := &api.Tenant{}
+	if err := json.Unmarshal(req.Object.Raw, tenant); err != nil {
+		return errors.Wrap(err, "failed to decode object")
+	}
+	tenant.SetNamespace(req.Namespace)
+	if tenant.GetAnnotations()[api.AnnotationAdoptNamespace] != "" {
+		// an existing namespace is requested to be adopted
+		return nil
+	}
+
+	ctx := context.Background()
+	name, err := d.plannedTenantNamespaceName(ctx, tenant)
+	if err != nil || name == "" {
+		return err
+	}
+
+	if _, err := d.namespaces.Get(ctx, name, metav1.GetOptions{}); err == nil {
+		return fmt.Errorf("the tenant namespace %q would clash with an existing namespace", name)
+	} else if !k8serrors.IsNotFound(err) {
+		return errors.WithMessagef(err, "failed to check existence of namespace %q", name)
+	}
+
+	assigned, err := d.tenants.ByIndex(tenantNamespaceNameIndex, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to look up tenants")
+	}
+	if len(assigned) > 0 {
+		return clashError(name, assigned[0].(*api.Tenant))
+	}
+
+	// uninitialized tenants with the same name in other client namespaces
+	sameName, err := d.tenants.ByIndex(tenantNameIndex, tenant.GetName())
+	if err != nil {
+		return errors.Wrap(err, "failed to look up tenants")
+	}
+	for _, obj := range sameName {
+		other := obj.(*api.Tenant)
+		if other.GetNamespace() == tenant.GetNamespace() ||
+			other.Status.TenantNamespaceName != "" ||
+			other.GetAnnotations()[api.AnnotationAdoptNamespace] != "" {
+			continue
+		}
+		otherName, err := d.plannedTenantNamespaceName(ctx, other)
+		if err != nil {
+			return err
+		}
+		if otherName == name {
+			return clashError(name, other)
+		}
+	}
+	return nil
+}
+
+// plannedTenantNamespaceName returns the name the tenant namespace of the
+// given tenant will get or an empty string if the name is not
+// predictable, i.e. it contains a random suffix or the client namespace
+// is not configured properly.
+func (d *TenantNamespaceClashDetector) plannedTenantNamespaceName(ctx context.Context, tenant *api.Tenant) (string, error) {
+	clientNamespace, err := d.namespaces.Get(ctx, tenant.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.WithMessagef(err, "failed to get client namespace %q", tenant.GetNamespace())
+	}
+	if !hasZeroTenantNamespaceSuffixLength(clientNamespace) {
+		return "", nil
+	}
+	prefix := clientNamespace.GetAnnotations()[api.AnnotationTenantNamespacePrefix]
+	if prefix == "" {
+		return "", nil
+	}
+	return k8s.JoinNamespaceNameParts(prefix, tenant.GetName(), ""), nil
+}
+
+func hasZeroTenantNamespaceSuffixLength(clientNamespace *corev1.Namespace) bool {
+	value, ok := clientNamespace.GetAnnotations()[api.AnnotationTenantNamespaceSuffixLength]
+	if !ok {
+		return false
+	}
+	length, err := strconv.ParseInt(value, 10, 8)
+	return err == nil && length == 0
+}
+
+func clashError(name string, other *api.Tenant) error {
+	return fmt.Errorf(
+		"the tenant namespace %q would clash with the tenant namespace of tenant %q in client namespace %q",
+		name, other.GetName(), other.GetNamespace(),
+	)
+}
@@ -0,0 +1,204 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+var tenantsResource = metav1.GroupVersionResource{
+	Group:    "steward.sap.com",
+	Version:  "v1alpha1",
+	Resource: "tenants",
+}
+
+func newClientNamespace(name, prefix, suffixLength string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				api.AnnotationTenantNamespacePrefix:       prefix,
+				api.AnnotationTenantNamespaceSuffixLength: suffixLength,
+			},
+		},
+	}
+}
+
+func newTenant(name, namespace, tenantNamespaceName string) *api.Tenant {
+	return &api.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: api.TenantStatus{
+			TenantNamespaceName: tenantNamespaceName,
+		},
+	}
+}
+
+func newTenantCreateRequest(t *testing.T, name, namespace string) *admissionv1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(newTenant(name, namespace, ""))
+	assert.NilError(t, err)
+	return &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  tenantsResource,
+		Namespace: namespace,
+		Name:      name,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func Test_TenantNamespaceClashDetector_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		objects       []runtime.Object
+		tenants       []*api.Tenant
+		expectedError string
+	}{
+		{
+			name: "no clash",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "0"),
+				newClientNamespace("client2", "p", "0"),
+			},
+			tenants: []*api.Tenant{
+				newTenant("tenant2", "client2", "p-tenant2"),
+			},
+		},
+		{
+			name: "clash with initialized tenant",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "0"),
+				newClientNamespace("client2", "p", "0"),
+			},
+			tenants: []*api.Tenant{
+				newTenant("tenant1", "client2", "p-tenant1"),
+			},
+			expectedError: `the tenant namespace "p-tenant1" would clash with the tenant namespace of tenant "tenant1" in client namespace "client2"`,
+		},
+		{
+			name: "clash with uninitialized tenant",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "0"),
+				newClientNamespace("client2", "p", "0"),
+			},
+			tenants: []*api.Tenant{
+				newTenant("tenant1", "client2", ""),
+			},
+			expectedError: `the tenant namespace "p-tenant1" would clash with the tenant namespace of tenant "tenant1" in client namespace "client2"`,
+		},
+		{
+			name: "uninitialized tenant with different prefix",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "0"),
+				newClientNamespace("client2", "q", "0"),
+			},
+			tenants: []*api.Tenant{
+				newTenant("tenant1", "client2", ""),
+			},
+		},
+		{
+			name: "clash with existing namespace",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "0"),
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "p-tenant1"}},
+			},
+			expectedError: `the tenant namespace "p-tenant1" would clash with an existing namespace`,
+		},
+		{
+			name: "random suffix",
+			objects: []runtime.Object{
+				newClientNamespace("client1", "p", "6"),
+				newClientNamespace("client2", "p", "0"),
+			},
+			tenants: []*api.Tenant{
+				newTenant("tenant1", "client2", ""),
+			},
+		},
+		{
+			name: "client namespace not found",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			clientset := kubefake.NewSimpleClientset(tc.objects...)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, TenantIndexers())
+			for _, tenant := range tc.tenants {
+				assert.NilError(t, indexer.Add(tenant))
+			}
+			examinee := NewTenantNamespaceClashDetector(clientset.CoreV1().Namespaces(), indexer)
+
+			// EXERCISE
+			err := examinee.Validate(newTenantCreateRequest(t, "tenant1", "client1"))
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_TenantNamespaceClashDetector_Validate_AllowsAdoption(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	clientset := kubefake.NewSimpleClientset(
+		newClientNamespace("client1", "p", "0"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "p-tenant1"}},
+	)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, TenantIndexers())
+	examinee := NewTenantNamespaceClashDetector(clientset.CoreV1().Namespaces(), indexer)
+	tenant := newTenant("tenant1", "client1", "")
+	tenant.SetAnnotations(map[string]string{api.AnnotationAdoptNamespace: "p-tenant1"})
+	raw, err := json.Marshal(tenant)
+	assert.NilError(t, err)
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  tenantsResource,
+		Namespace: "client1",
+		Name:      "tenant1",
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+
+	// EXERCISE
+	err = examinee.Validate(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_TenantNamespaceClashDetector_Validate_IgnoresOtherRequests(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewTenantNamespaceClashDetector(nil, nil)
+
+	for _, req := range []*admissionv1.AdmissionRequest{
+		{Operation: admissionv1.Create, Resource: pipelineRunsResource},
+		{Operation: admissionv1.Update, Resource: tenantsResource},
+		{Operation: admissionv1.Delete, Resource: tenantsResource},
+	} {
+		// EXERCISE
+		err := examinee.Validate(req)
+
+		// VERIFY
+		assert.NilError(t, err)
+	}
+}
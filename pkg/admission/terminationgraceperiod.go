@@ -0,0 +1,39 @@
+package admission
+
+import (
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// TerminationGracePeriodMutator sets field
+// `spec.terminationGracePeriodSeconds` of pods labelled with
+// api.LabelTerminationGracePeriod to the value of the label.
+// Tekton does not allow to set the termination grace period via the pod
+// template.
+// Other objects and pods without the label are not modified.
+func TerminationGracePeriodMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Operation != admissionv1.Create || req.Kind.Kind != "Pod" || req.Kind.Group != "" {
+		return nil, nil
+	}
+
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	value, exists := obj.Labels[api.LabelTerminationGracePeriod]
+	if !exists {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return nil, errors.Errorf(
+			"label %q: invalid value %q: must be a non-negative integer",
+			api.LabelTerminationGracePeriod, value,
+		)
+	}
+	// "add" replaces an existing value
+	return []PatchOperation{{Op: "add", Path: "/spec/terminationGracePeriodSeconds", Value: seconds}}, nil
+}
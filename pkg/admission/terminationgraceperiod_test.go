@@ -0,0 +1,106 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_TerminationGracePeriodMutator(t *testing.T) {
+	t.Parallel()
+
+	podKind := metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+	for _, tc := range []struct {
+		name        string
+		operation   admissionv1.Operation
+		kind        metav1.GroupVersionKind
+		labels      map[string]string
+		expectedOps []PatchOperation
+		expectedErr string
+	}{
+		{
+			name:        "labelled_pod",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelTerminationGracePeriod: "300"},
+			expectedOps: []PatchOperation{{Op: "add", Path: "/spec/terminationGracePeriodSeconds", Value: int64(300)}},
+		},
+		{
+			name:        "zero",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelTerminationGracePeriod: "0"},
+			expectedOps: []PatchOperation{{Op: "add", Path: "/spec/terminationGracePeriodSeconds", Value: int64(0)}},
+		},
+		{
+			name:      "unlabelled_pod",
+			operation: admissionv1.Create,
+			kind:      podKind,
+		},
+		{
+			name:        "invalid_value",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelTerminationGracePeriod: "5m"},
+			expectedErr: `label "steward.sap.com/termination-grace-period-seconds": invalid value "5m": must be a non-negative integer`,
+		},
+		{
+			name:        "negative_value",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      map[string]string{api.LabelTerminationGracePeriod: "-1"},
+			expectedErr: `label "steward.sap.com/termination-grace-period-seconds": invalid value "-1": must be a non-negative integer`,
+		},
+		{
+			name:      "update",
+			operation: admissionv1.Update,
+			kind:      podKind,
+			labels:    map[string]string{api.LabelTerminationGracePeriod: "300"},
+		},
+		{
+			name:      "other_kind",
+			operation: admissionv1.Create,
+			kind:      metav1.GroupVersionKind{Group: "steward.sap.com", Version: "v1alpha1", Kind: "PipelineRun"},
+			labels:    map[string]string{api.LabelTerminationGracePeriod: "300"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					Labels:    tc.labels,
+				},
+			}
+			raw, err := json.Marshal(pod)
+			assert.NilError(t, err)
+			req := &admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Kind:      tc.kind,
+				Object:    runtime.RawExtension{Raw: raw},
+			}
+
+			// EXERCISE
+			ops, err := TerminationGracePeriodMutator(req)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expectedOps, ops)
+		})
+	}
+}
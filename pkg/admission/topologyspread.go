@@ -0,0 +1,50 @@
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TopologySpreadMutator sets field `spec.topologySpreadConstraints` of
+// pods labelled with api.LabelTopologySpread to the constraints defined
+// by annotation api.AnnotationTopologySpreadConstraints.
+// Tekton does not allow to set topology spread constraints via the pod
+// template.
+// Other objects and pods without the label are not modified.
+func TopologySpreadMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Operation != admissionv1.Create || req.Kind.Kind != "Pod" || req.Kind.Group != "" {
+		return nil, nil
+	}
+
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	if _, exists := obj.Labels[api.LabelTopologySpread]; !exists {
+		return nil, nil
+	}
+	value, exists := obj.Annotations[api.AnnotationTopologySpreadConstraints]
+	if !exists {
+		return nil, nil
+	}
+
+	var constraints []corev1.TopologySpreadConstraint
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&constraints); err != nil {
+		return nil, errors.Wrapf(err,
+			"annotation %q: cannot parse topology spread constraints",
+			api.AnnotationTopologySpreadConstraints,
+		)
+	}
+	if len(constraints) == 0 {
+		return nil, nil
+	}
+	// "add" replaces an existing value
+	return []PatchOperation{{Op: "add", Path: "/spec/topologySpreadConstraints", Value: constraints}}, nil
+}
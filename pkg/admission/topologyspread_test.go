@@ -0,0 +1,117 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_TopologySpreadMutator(t *testing.T) {
+	t.Parallel()
+
+	podKind := metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	label := map[string]string{api.LabelTopologySpread: ""}
+	constraintsJSON := `[{"maxSkew":1,"topologyKey":"topology.kubernetes.io/zone","whenUnsatisfiable":"ScheduleAnyway"}]`
+	constraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		operation   admissionv1.Operation
+		kind        metav1.GroupVersionKind
+		labels      map[string]string
+		annotations map[string]string
+		expectedOps []PatchOperation
+		expectedErr string
+	}{
+		{
+			name:        "labelled_pod",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      label,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: constraintsJSON},
+			expectedOps: []PatchOperation{{Op: "add", Path: "/spec/topologySpreadConstraints", Value: constraints}},
+		},
+		{
+			name:        "unlabelled_pod",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: constraintsJSON},
+		},
+		{
+			name:      "no_annotation",
+			operation: admissionv1.Create,
+			kind:      podKind,
+			labels:    label,
+		},
+		{
+			name:        "empty_constraints",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      label,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: "[]"},
+		},
+		{
+			name:        "invalid_annotation",
+			operation:   admissionv1.Create,
+			kind:        podKind,
+			labels:      label,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: `[{"maxSkw":1}]`},
+			expectedErr: `annotation "steward.sap.com/topology-spread-constraints": cannot parse topology spread constraints: json: unknown field "maxSkw"`,
+		},
+		{
+			name:        "update",
+			operation:   admissionv1.Update,
+			kind:        podKind,
+			labels:      label,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: constraintsJSON},
+		},
+		{
+			name:        "other_kind",
+			operation:   admissionv1.Create,
+			kind:        metav1.GroupVersionKind{Group: "steward.sap.com", Version: "v1alpha1", Kind: "PipelineRun"},
+			labels:      label,
+			annotations: map[string]string{api.AnnotationTopologySpreadConstraints: constraintsJSON},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pod1",
+					Namespace:   "ns1",
+					Labels:      tc.labels,
+					Annotations: tc.annotations,
+				},
+			}
+			raw, err := json.Marshal(pod)
+			assert.NilError(t, err)
+			req := &admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Kind:      tc.kind,
+				Object:    runtime.RawExtension{Raw: raw},
+			}
+
+			// EXERCISE
+			ops, err := TopologySpreadMutator(req)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expectedOps, ops)
+		})
+	}
+}
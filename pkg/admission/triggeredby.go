@@ -0,0 +1,123 @@
+package admission
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// TriggeredByMutator sets labels api.LabelTriggeredByType and
+// api.LabelTriggeredByID of pipeline runs to the values of
+// `spec.triggeredBy`, so that pipeline runs can be selected by their
+// trigger. IDs that are not valid label values are converted with
+// api.TriggeredByIDLabelValue.
+// On updates the labels are reset to the values of the old object,
+// so that clients cannot tamper with them.
+// Requests for other resources are ignored.
+func TriggeredByMutator(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+	if req.Resource.Resource != "pipelineruns" {
+		return nil, nil
+	}
+
+	var triggeredBy *api.TriggeredBy
+	switch req.Operation {
+	case admissionv1.Create:
+		spec, err := decodePipelineSpec(req.Object)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode object")
+		}
+		triggeredBy = spec.TriggeredBy
+	case admissionv1.Update:
+		oldSpec, err := decodePipelineSpec(req.OldObject)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode old object")
+		}
+		triggeredBy = oldSpec.TriggeredBy
+	default:
+		return nil, nil
+	}
+
+	var wantType, wantID string
+	if triggeredBy != nil {
+		wantType = triggeredBy.Type
+		wantID = triggeredBy.ID
+	}
+
+	obj, err := decodeObjectMeta(req.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode object")
+	}
+	return labelsPatch(obj.Labels, map[string]*string{
+		api.LabelTriggeredByType: labelValueOrNil(wantType),
+		api.LabelTriggeredByID:   labelValueOrNil(api.TriggeredByIDLabelValue(wantID)),
+	}), nil
+}
+
+// TriggeredByValidator denies the creation of pipeline runs with an
+// invalid `spec.triggeredBy` and any change of `spec.triggeredBy` after
+// creation.
+// Requests for other resources are allowed.
+func TriggeredByValidator(req *admissionv1.AdmissionRequest) error {
+	if req.Resource.Resource != "pipelineruns" {
+		return nil
+	}
+
+	switch req.Operation {
+	case admissionv1.Create:
+		spec, err := decodePipelineSpec(req.Object)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode object")
+		}
+		return validateTriggeredBy(spec.TriggeredBy)
+	case admissionv1.Update:
+		spec, err := decodePipelineSpec(req.Object)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode object")
+		}
+		oldSpec, err := decodePipelineSpec(req.OldObject)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode old object")
+		}
+		if !reflect.DeepEqual(spec.TriggeredBy, oldSpec.TriggeredBy) {
+			return errors.New("spec.triggeredBy cannot be changed")
+		}
+	}
+	return nil
+}
+
+func validateTriggeredBy(triggeredBy *api.TriggeredBy) error {
+	if triggeredBy == nil {
+		return nil
+	}
+	if triggeredBy.Type == "" {
+		return errors.New("spec.triggeredBy.type must not be empty")
+	}
+	if msgs := validation.IsValidLabelValue(triggeredBy.Type); len(msgs) > 0 {
+		return fmt.Errorf("spec.triggeredBy.type is invalid: %s", strings.Join(msgs, "; "))
+	}
+	if triggeredBy.ID == "" {
+		return errors.New("spec.triggeredBy.id must not be empty")
+	}
+	if triggeredBy.URL != "" {
+		u, err := url.Parse(triggeredBy.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("spec.triggeredBy.url is not a valid HTTP(S) URL: %q", triggeredBy.URL)
+		}
+	}
+	return nil
+}
+
+// labelValueOrNil returns a pointer to the given string or nil if it is
+// empty or not a valid label value.
+func labelValueOrNil(s string) *string {
+	if s == "" || len(validation.IsValidLabelValue(s)) > 0 {
+		return nil
+	}
+	return &s
+}
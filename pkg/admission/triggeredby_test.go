@@ -0,0 +1,276 @@
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newRawPipelineRunWithLabels(t *testing.T, labels map[string]string, spec api.PipelineSpec) runtime.RawExtension {
+	t.Helper()
+	obj := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "run1",
+			Namespace: "ns1",
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+	raw, err := json.Marshal(obj)
+	assert.NilError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+func newTriggeredBySpec(triggerType, id, url string) api.PipelineSpec {
+	return api.PipelineSpec{
+		TriggeredBy: &api.TriggeredBy{Type: triggerType, ID: id, URL: url},
+	}
+}
+
+func Test_TriggeredByMutator_Create(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		labels   map[string]string
+		spec     api.PipelineSpec
+		expected []PatchOperation
+	}{
+		{
+			name: "no trigger",
+			spec: api.PipelineSpec{},
+		},
+		{
+			name: "no trigger, removes client labels",
+			labels: map[string]string{
+				api.LabelTriggeredByType: "fake",
+				"other":                  "x",
+			},
+			spec: api.PipelineSpec{},
+			expected: []PatchOperation{
+				{Op: "remove", Path: "/metadata/labels/steward.sap.com~1triggered-by-type"},
+			},
+		},
+		{
+			name: "valid label values",
+			spec: newTriggeredBySpec("pipelinerun", "upstream-run1", ""),
+			expected: []PatchOperation{
+				{
+					Op:   "add",
+					Path: "/metadata/labels",
+					Value: map[string]string{
+						api.LabelTriggeredByType: "pipelinerun",
+						api.LabelTriggeredByID:   "upstream-run1",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid ID label value",
+			spec: newTriggeredBySpec("github-pull-request", "org1/repo1#42", ""),
+			expected: []PatchOperation{
+				{
+					Op:   "add",
+					Path: "/metadata/labels",
+					Value: map[string]string{
+						api.LabelTriggeredByType: "github-pull-request",
+						api.LabelTriggeredByID:   "org1-repo1-42-e21d654079bb193b",
+					},
+				},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  pipelineRunsResource,
+				Object:    newRawPipelineRunWithLabels(t, tc.labels, tc.spec),
+			}
+
+			// EXERCISE
+			ops, err := TriggeredByMutator(req)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, ops)
+		})
+	}
+}
+
+func Test_TriggeredByMutator_Update_RestoresOldValues(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	spec := newTriggeredBySpec("pipelinerun", "upstream-run1", "")
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Update,
+		Resource:  pipelineRunsResource,
+		Object: newRawPipelineRunWithLabels(t, map[string]string{
+			api.LabelTriggeredByType: "fake",
+		}, spec),
+		OldObject: newRawPipelineRunWithLabels(t, map[string]string{
+			api.LabelTriggeredByType: "pipelinerun",
+			api.LabelTriggeredByID:   "upstream-run1",
+		}, spec),
+	}
+
+	// EXERCISE
+	ops, err := TriggeredByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []PatchOperation{
+		{Op: "add", Path: "/metadata/labels/steward.sap.com~1triggered-by-id", Value: "upstream-run1"},
+		{Op: "replace", Path: "/metadata/labels/steward.sap.com~1triggered-by-type", Value: "pipelinerun"},
+	}, ops)
+}
+
+func Test_TriggeredByMutator_IgnoresOtherResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	req := &admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource: metav1.GroupVersionResource{
+			Group:    "steward.sap.com",
+			Version:  "v1alpha1",
+			Resource: "tenants",
+		},
+		Object: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"steward.sap.com/triggered-by-type":"x"}}}`)},
+	}
+
+	// EXERCISE
+	ops, err := TriggeredByMutator(req)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, ops == nil)
+}
+
+func Test_TriggeredByValidator_Create(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		spec          api.PipelineSpec
+		expectedError string
+	}{
+		{
+			name: "no trigger",
+			spec: api.PipelineSpec{},
+		},
+		{
+			name: "valid",
+			spec: newTriggeredBySpec("github-pull-request", "org1/repo1#42", "https://github.com/org1/repo1/pull/42"),
+		},
+		{
+			name:          "empty type",
+			spec:          newTriggeredBySpec("", "id1", ""),
+			expectedError: "spec.triggeredBy.type must not be empty",
+		},
+		{
+			name:          "invalid type",
+			spec:          newTriggeredBySpec("github pull request", "id1", ""),
+			expectedError: "spec.triggeredBy.type is invalid: a valid label must be an empty string or consist of alphanumeric characters",
+		},
+		{
+			name:          "empty ID",
+			spec:          newTriggeredBySpec("pipelinerun", "", ""),
+			expectedError: "spec.triggeredBy.id must not be empty",
+		},
+		{
+			name:          "invalid URL",
+			spec:          newTriggeredBySpec("pipelinerun", "id1", "ftp://host1/run1"),
+			expectedError: `spec.triggeredBy.url is not a valid HTTP(S) URL: "ftp://host1/run1"`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource:  pipelineRunsResource,
+				Object:    newRawPipelineRun(t, tc.spec),
+			}
+
+			// EXERCISE
+			err := TriggeredByValidator(req)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_TriggeredByValidator_Update(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		oldSpec       api.PipelineSpec
+		spec          api.PipelineSpec
+		expectedError string
+	}{
+		{
+			name:    "unchanged",
+			oldSpec: newTriggeredBySpec("pipelinerun", "id1", ""),
+			spec:    newTriggeredBySpec("pipelinerun", "id1", ""),
+		},
+		{
+			name:          "changed",
+			oldSpec:       newTriggeredBySpec("pipelinerun", "id1", ""),
+			spec:          newTriggeredBySpec("pipelinerun", "id2", ""),
+			expectedError: "spec.triggeredBy cannot be changed",
+		},
+		{
+			name:          "added",
+			oldSpec:       api.PipelineSpec{},
+			spec:          newTriggeredBySpec("pipelinerun", "id1", ""),
+			expectedError: "spec.triggeredBy cannot be changed",
+		},
+		{
+			name:          "removed",
+			oldSpec:       newTriggeredBySpec("pipelinerun", "id1", ""),
+			spec:          api.PipelineSpec{},
+			expectedError: "spec.triggeredBy cannot be changed",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			req := &admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Resource:  pipelineRunsResource,
+				Object:    newRawPipelineRun(t, tc.spec),
+				OldObject: newRawPipelineRun(t, tc.oldSpec),
+			}
+
+			// EXERCISE
+			err := TriggeredByValidator(req)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
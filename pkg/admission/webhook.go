@@ -0,0 +1,216 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// maxRequestBodySize is the maximum size in bytes of admission
+	// review requests accepted by the webhook.
+	maxRequestBodySize = 8 * 1024 * 1024
+)
+
+// Mutator inspects an admission request and returns the JSON patch
+// operations to be applied to the object.
+// If an error is returned, the request gets denied.
+type Mutator func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error)
+
+// Validator inspects an admission request and returns an error if the
+// request must be denied.
+type Validator func(req *admissionv1.AdmissionRequest) error
+
+// Warner inspects an admission request and returns warnings to be
+// returned to the client. Warnings never deny a request.
+type Warner func(req *admissionv1.AdmissionRequest) []string
+
+// MutatingWebhook is an HTTP handler serving admission review requests
+// by applying a sequence of mutators.
+type MutatingWebhook struct {
+	mutators []Mutator
+}
+
+// NewMutatingWebhook creates a new mutating webhook applying the given
+// mutators in order.
+func NewMutatingWebhook(mutators ...Mutator) *MutatingWebhook {
+	return &MutatingWebhook{
+		mutators: mutators,
+	}
+}
+
+// ServeHTTP implements interface http.Handler.
+func (w *MutatingWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(rw, r, w.mutate)
+}
+
+func (w *MutatingWebhook) mutate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	var patch []PatchOperation
+	for _, mutator := range w.mutators {
+		ops, err := mutator(req)
+		if err != nil {
+			klog.V(3).Infof(
+				"denying %s of %s %s/%s: %s",
+				req.Operation, req.Resource.Resource, req.Namespace, req.Name, err.Error(),
+			)
+			return deny(err)
+		}
+		patch = appendPatch(patch, ops)
+	}
+
+	response := &admissionv1.AdmissionResponse{Allowed: true}
+	if len(patch) > 0 {
+		patchBytes, err := json.Marshal(patch)
+		if err != nil {
+			return deny(errors.Wrap(err, "failed to marshal JSON patch"))
+		}
+		patchType := admissionv1.PatchTypeJSONPatch
+		response.PatchType = &patchType
+		response.Patch = patchBytes
+	}
+	return response
+}
+
+// appendPatch appends the given operations to the given patch.
+// Mutators compute their operations for the original object, so several
+// mutators adding entries to a map missing in the object, e.g.
+// `metadata.annotations`, each add the whole map. As the second of these
+// operations would replace the map added by the first one, operations
+// adding a map to a path the patch adds a map to already are merged into
+// the existing operation instead.
+func appendPatch(patch, ops []PatchOperation) []PatchOperation {
+	for _, op := range ops {
+		if added, ok := op.Value.(map[string]string); ok && op.Op == "add" {
+			if existing := findMapAddition(patch, op.Path); existing != nil {
+				for key, value := range added {
+					existing[key] = value
+				}
+				continue
+			}
+		}
+		patch = append(patch, op)
+	}
+	return patch
+}
+
+// findMapAddition returns the map added to the given path by an operation
+// of the given patch or nil if there is none.
+func findMapAddition(patch []PatchOperation, path string) map[string]string {
+	for _, op := range patch {
+		if added, ok := op.Value.(map[string]string); ok && op.Op == "add" && op.Path == path {
+			return added
+		}
+	}
+	return nil
+}
+
+// ValidatingWebhook is an HTTP handler serving admission review requests
+// by applying a sequence of validators.
+type ValidatingWebhook struct {
+	validators []Validator
+	warners    []Warner
+}
+
+// NewValidatingWebhook creates a new validating webhook applying the given
+// validators in order.
+func NewValidatingWebhook(validators ...Validator) *ValidatingWebhook {
+	return &ValidatingWebhook{
+		validators: validators,
+	}
+}
+
+// AddWarners adds the given warners, which are applied in order to
+// requests allowed by all validators.
+func (w *ValidatingWebhook) AddWarners(warners ...Warner) *ValidatingWebhook {
+	w.warners = append(w.warners, warners...)
+	return w
+}
+
+// ServeHTTP implements interface http.Handler.
+func (w *ValidatingWebhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(rw, r, w.validate)
+}
+
+func (w *ValidatingWebhook) validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	for _, validator := range w.validators {
+		if err := validator(req); err != nil {
+			klog.V(3).Infof(
+				"denying %s of %s %s/%s: %s",
+				req.Operation, req.Resource.Resource, req.Namespace, req.Name, err.Error(),
+			)
+			return deny(err)
+		}
+	}
+	response := &admissionv1.AdmissionResponse{Allowed: true}
+	for _, warner := range w.warners {
+		response.Warnings = append(response.Warnings, warner(req)...)
+	}
+	return response
+}
+
+func serveAdmissionReview(rw http.ResponseWriter, r *http.Request, admit func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) {
+	review, err := readAdmissionReview(rw, r)
+	if err != nil {
+		klog.V(3).Infof("rejecting invalid admission review request: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = admit(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	writeAdmissionReview(rw, review)
+}
+
+func deny(err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonBadRequest,
+			Code:    http.StatusBadRequest,
+		},
+	}
+}
+
+func readAdmissionReview(rw http.ResponseWriter, r *http.Request) (*admissionv1.AdmissionReview, error) {
+	if r.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method %q", r.Method)
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil {
+		return nil, errors.Wrap(err, "failed to decode admission review")
+	}
+	if review.Request == nil {
+		return nil, errors.New("admission review does not contain a request")
+	}
+	return review, nil
+}
+
+func writeAdmissionReview(rw http.ResponseWriter, review *admissionv1.AdmissionReview) {
+	body, err := json.Marshal(review)
+	if err != nil {
+		klog.Errorf("failed to encode admission review response: %s", err.Error())
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(body); err != nil {
+		klog.Errorf("failed to write admission review response: %s", err.Error())
+	}
+}
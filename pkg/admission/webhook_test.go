@@ -0,0 +1,265 @@
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newAdmissionReviewRequest(t *testing.T, req *admissionv1.AdmissionRequest) *http.Request {
+	t.Helper()
+	review := &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admission.k8s.io/v1",
+			Kind:       "AdmissionReview",
+		},
+		Request: req,
+	}
+	body, err := json.Marshal(review)
+	assert.NilError(t, err)
+	httpReq := httptest.NewRequest(http.MethodPost, "/mutate", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	return httpReq
+}
+
+func serve(t *testing.T, handler http.Handler, httpReq *http.Request) (*httptest.ResponseRecorder, *admissionv1.AdmissionReview) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httpReq)
+	if recorder.Code != http.StatusOK {
+		return recorder, nil
+	}
+	review := &admissionv1.AdmissionReview{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), review))
+	return recorder, review
+}
+
+func Test_MutatingWebhook_AppliesAllMutators(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mutator1 := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return []PatchOperation{{Op: "add", Path: "/a", Value: "1"}}, nil
+	}
+	mutator2 := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return []PatchOperation{{Op: "remove", Path: "/b"}}, nil
+	}
+	examinee := NewMutatingWebhook(mutator1, mutator2)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Assert(t, review.Request == nil)
+	response := review.Response
+	assert.Equal(t, types.UID("uid1"), response.UID)
+	assert.Assert(t, response.Allowed)
+	assert.Equal(t, admissionv1.PatchTypeJSONPatch, *response.PatchType)
+	assert.Equal(t, `[{"op":"add","path":"/a","value":"1"},{"op":"remove","path":"/b"}]`, string(response.Patch))
+}
+
+func Test_MutatingWebhook_MergesMapAdditionsOfDifferentMutators(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mutator1 := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return []PatchOperation{{Op: "add", Path: "/metadata/annotations", Value: map[string]string{"a": "1", "b": "1"}}}, nil
+	}
+	mutator2 := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return []PatchOperation{
+			{Op: "add", Path: "/metadata/annotations", Value: map[string]string{"b": "2", "c": "2"}},
+			{Op: "add", Path: "/metadata/labels", Value: map[string]string{"d": "2"}},
+		}, nil
+	}
+	examinee := NewMutatingWebhook(mutator1, mutator2)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Assert(t, review.Response.Allowed)
+	assert.Equal(t,
+		`[{"op":"add","path":"/metadata/annotations","value":{"a":"1","b":"2","c":"2"}},{"op":"add","path":"/metadata/labels","value":{"d":"2"}}]`,
+		string(review.Response.Patch),
+	)
+}
+
+func Test_MutatingWebhook_NoPatch(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mutator := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return nil, nil
+	}
+	examinee := NewMutatingWebhook(mutator)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Assert(t, review.Response.Allowed)
+	assert.Assert(t, review.Response.PatchType == nil)
+	assert.Assert(t, review.Response.Patch == nil)
+}
+
+func Test_MutatingWebhook_DeniesOnMutatorError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mutator := func(req *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
+		return nil, fmt.Errorf("err1")
+	}
+	examinee := NewMutatingWebhook(mutator)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Equal(t, types.UID("uid1"), review.Response.UID)
+	assert.Assert(t, !review.Response.Allowed)
+	assert.Equal(t, "err1", review.Response.Result.Message)
+	assert.Equal(t, int32(http.StatusBadRequest), review.Response.Result.Code)
+}
+
+func Test_MutatingWebhook_RejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+	}{
+		{"wrong method", http.MethodGet, "application/json", "{}"},
+		{"wrong content type", http.MethodPost, "text/plain", "{}"},
+		{"invalid JSON", http.MethodPost, "application/json", "{"},
+		{"no request", http.MethodPost, "application/json", "{}"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			examinee := NewMutatingWebhook()
+			httpReq := httptest.NewRequest(tc.method, "/mutate", bytes.NewReader([]byte(tc.body)))
+			httpReq.Header.Set("Content-Type", tc.contentType)
+
+			// EXERCISE
+			recorder, _ := serve(t, examinee, httpReq)
+
+			// VERIFY
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
+
+func Test_ValidatingWebhook_Allows(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	called := 0
+	validator := func(req *admissionv1.AdmissionRequest) error {
+		called++
+		return nil
+	}
+	examinee := NewValidatingWebhook(validator, validator)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Equal(t, types.UID("uid1"), review.Response.UID)
+	assert.Assert(t, review.Response.Allowed)
+	assert.Assert(t, review.Response.Patch == nil)
+	assert.Equal(t, 2, called)
+}
+
+func Test_ValidatingWebhook_DeniesOnValidatorError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	validator1 := func(req *admissionv1.AdmissionRequest) error {
+		return fmt.Errorf("err1")
+	}
+	validator2 := func(req *admissionv1.AdmissionRequest) error {
+		t.Fatal("unexpected call of validator2")
+		return nil
+	}
+	examinee := NewValidatingWebhook(validator1, validator2)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Equal(t, types.UID("uid1"), review.Response.UID)
+	assert.Assert(t, !review.Response.Allowed)
+	assert.Equal(t, "err1", review.Response.Result.Message)
+}
+
+func Test_ValidatingWebhook_ReturnsWarnings(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	validator := func(req *admissionv1.AdmissionRequest) error {
+		return nil
+	}
+	warner1 := func(req *admissionv1.AdmissionRequest) []string {
+		return []string{"w1"}
+	}
+	warner2 := func(req *admissionv1.AdmissionRequest) []string {
+		return []string{"w2", "w3"}
+	}
+	examinee := NewValidatingWebhook(validator).AddWarners(warner1, warner2)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Assert(t, review.Response.Allowed)
+	assert.DeepEqual(t, []string{"w1", "w2", "w3"}, review.Response.Warnings)
+}
+
+func Test_ValidatingWebhook_NoWarningsOnDenial(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	validator := func(req *admissionv1.AdmissionRequest) error {
+		return fmt.Errorf("err1")
+	}
+	warner := func(req *admissionv1.AdmissionRequest) []string {
+		t.Fatal("unexpected call of warner")
+		return nil
+	}
+	examinee := NewValidatingWebhook(validator).AddWarners(warner)
+	httpReq := newAdmissionReviewRequest(t, &admissionv1.AdmissionRequest{UID: types.UID("uid1")})
+
+	// EXERCISE
+	_, review := serve(t, examinee, httpReq)
+
+	// VERIFY
+	assert.Assert(t, review != nil)
+	assert.Assert(t, !review.Response.Allowed)
+	assert.Assert(t, review.Response.Warnings == nil)
+}
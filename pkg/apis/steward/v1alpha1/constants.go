@@ -21,10 +21,236 @@ const (
 	// default service account of a tenant namespace.
 	AnnotationTenantRole = steward.GroupName + "/tenant-role"
 
+	// AnnotationLogForwardingSecret is the key of the annotation of a
+	// Steward client namespace defining the name of a secret in the client
+	// namespace that configures the Elasticsearch endpoint and credentials
+	// used to forward the logs of pipeline runs of this client.
+	// If not set, the installation-wide defaults are used.
+	AnnotationLogForwardingSecret = steward.GroupName + "/log-forwarding-secret"
+
+	// AnnotationSecretProvider is the key of the annotation of a pipeline
+	// run or a tenant namespace selecting the provider of the secrets
+	// referenced by pipeline runs (see SecretProvider* constants for the
+	// built-in providers). The annotation of the pipeline run takes
+	// precedence. If not set, the default provider configured for the
+	// run controller is used.
+	AnnotationSecretProvider = steward.GroupName + "/secret-provider"
+
+	// AnnotationVaultSecret is the key of the annotation of a Steward
+	// client namespace defining the name of a secret in the client
+	// namespace that configures the HashiCorp Vault server, secret path
+	// and authentication used by pipeline runs of this client selecting
+	// secret provider SecretProviderVault.
+	AnnotationVaultSecret = steward.GroupName + "/vault-secret"
+
+	// AnnotationTenantNamespaceCreation is the key of the annotation of a
+	// Steward client namespace defining when tenant namespaces are created.
+	// Possible values are TenantNamespaceCreationEager (default) and
+	// TenantNamespaceCreationOnDemand.
+	AnnotationTenantNamespaceCreation = steward.GroupName + "/tenant-namespace-creation"
+
+	// AnnotationTenantNamespaceRequested is the key of the annotation of a
+	// Steward tenant whose presence requests the creation of the tenant
+	// namespace if the client namespace is configured to create tenant
+	// namespaces on demand or if the tenant is hibernated.
+	// The value of the annotation is ignored.
+	AnnotationTenantNamespaceRequested = steward.GroupName + "/tenant-namespace-requested"
+
+	// AnnotationTenantHibernationPeriod is the key of the annotation of a
+	// Steward client namespace defining the period without pipeline run
+	// activity after which the tenant namespace of a tenant gets deleted
+	// (the tenant is hibernated).
+	// The value must be a duration as accepted by Go's time.ParseDuration().
+	// If not set or zero, tenants are never hibernated.
+	AnnotationTenantHibernationPeriod = steward.GroupName + "/tenant-hibernation-period"
+
+	// AnnotationTenantExpirationPolicy is the key of the annotation of a
+	// Steward client namespace defining what happens to tenants whose
+	// `spec.expirationDate` has passed.
+	// Possible values are TenantExpirationPolicyHibernate (default) and
+	// TenantExpirationPolicyDelete.
+	AnnotationTenantExpirationPolicy = steward.GroupName + "/tenant-expiration-policy"
+
+	// AnnotationTenantExpirationWarningPeriod is the key of the annotation
+	// of a Steward client namespace defining how long before their
+	// expiration date tenants get the `Expiring` condition set.
+	// The value must be a duration as accepted by Go's time.ParseDuration().
+	// If not set, a default of seven days applies.
+	AnnotationTenantExpirationWarningPeriod = steward.GroupName + "/tenant-expiration-warning-period"
+
 	// AnnotationSecretRename is the key of the annotation used to rename a secret.
 	// If this annotation is set on a secret it will be created in the run namespace
 	// with this name if it is listed in the pipelineRuns spec.secrets list.
 	AnnotationSecretRename = steward.GroupName + "/secret-rename-to"
+
+	// AnnotationGitHubAppSecret is the key of the annotation of secrets
+	// in run namespaces containing a GitHub App installation token. The
+	// value is the name of the GitHub App secret in the tenant namespace
+	// the token has been minted with.
+	AnnotationGitHubAppSecret = steward.GroupName + "/github-app-secret"
+
+	// AnnotationGitHubAppTokenExpiresAt is the key of the annotation of
+	// secrets containing a GitHub App installation token. The value is
+	// the expiration time of the token in RFC 3339 format.
+	AnnotationGitHubAppTokenExpiresAt = steward.GroupName + "/github-app-token-expires-at"
+
+	// AnnotationRunAPITokenExpiresAt is the key of the annotation of the
+	// secret in run namespaces containing the per-run token for the
+	// Steward API. The value is the expiration time of the token in
+	// RFC 3339 format.
+	AnnotationRunAPITokenExpiresAt = steward.GroupName + "/run-api-token-expires-at"
+
+	// AnnotationSecretSource is the key of the annotation of secrets in
+	// run namespaces copied from a source secret. The value is the name
+	// of the source secret.
+	AnnotationSecretSource = steward.GroupName + "/secret-source"
+
+	// AnnotationSecretSourceHash is the key of the annotation of secrets
+	// in run namespaces copied from a source secret. The value is a hash
+	// of the type and data of the source secret at the time it has been
+	// copied, which allows to detect changes of the source secret.
+	AnnotationSecretSourceHash = steward.GroupName + "/secret-source-hash"
+
+	// AnnotationCreatedBy is the key of the annotation of pipeline runs and
+	// tenants that records the name of the user who created the object.
+	// It is set by the Steward admission webhook and cannot be modified by
+	// clients.
+	AnnotationCreatedBy = steward.GroupName + "/created-by"
+
+	// AnnotationCreatedByUID is the key of the annotation of pipeline runs
+	// and tenants that records the UID of the user who created the object,
+	// if the authenticator provides one.
+	// It is set by the Steward admission webhook and cannot be modified by
+	// clients.
+	AnnotationCreatedByUID = steward.GroupName + "/created-by-uid"
+
+	// AnnotationGatewayClient is the key of the annotation of pipeline
+	// runs created via the Steward API gateway that records the name of
+	// the gateway API token used.
+	// It is set by the gateway and protected by the Steward admission
+	// webhook, i.e. it cannot be set or modified by other clients.
+	AnnotationGatewayClient = steward.GroupName + "/gateway-client"
+
+	// AnnotationAdoptNamespace is the key of the annotation of a Steward
+	// tenant that requests the tenant controller to adopt an existing
+	// namespace as tenant namespace instead of creating a new one. The
+	// value is the name of the namespace.
+	// It is used to recover orphaned tenant namespaces, e.g. after Tenant
+	// resource objects have been lost. The annotation is only taken into
+	// account as long as the tenant is not initialized.
+	AnnotationAdoptNamespace = steward.GroupName + "/adopt-namespace"
+
+	// AnnotationRelocateTenantNamespace is the key of the annotation of a
+	// Steward tenant whose presence requests the tenant controller to move
+	// the tenant to a new tenant namespace, e.g. to apply a changed tenant
+	// namespace prefix of the client. The secrets of the tenant namespace
+	// are copied to the new one and the former tenant namespace gets
+	// deleted as soon as it does not contain unfinished pipeline runs
+	// anymore. The annotation is removed by the tenant controller.
+	// The value of the annotation is ignored.
+	AnnotationRelocateTenantNamespace = steward.GroupName + "/relocate-tenant-namespace"
+
+	// AnnotationTopologySpreadConstraints is the key of the annotation of
+	// Tekton task runs and pods that defines the topology spread
+	// constraints of the pods as JSON array. It is only taken into account
+	// for pods labelled with LabelTopologySpread.
+	// Tekton propagates the annotation from task runs to their pods.
+	AnnotationTopologySpreadConstraints = steward.GroupName + "/topology-spread-constraints"
+
+	// AnnotationMovedFromUID is the key of the annotation of pipeline runs
+	// that the run controller moved from a client namespace into a tenant
+	// namespace (see LabelPendingTenant). The value is the UID of the
+	// pipeline run in the client namespace.
+	AnnotationMovedFromUID = steward.GroupName + "/moved-from-uid"
+
+	// AnnotationMaxConcurrentRuns is the key of the annotation of a
+	// tenant namespace that defines the maximum number of pipeline runs in
+	// the namespace that are executed concurrently. The value is a
+	// non-negative integer, where zero means no limit.
+	// It takes precedence over `spec.maxConcurrentRuns` of the tenant and
+	// the default of the Steward installation.
+	AnnotationMaxConcurrentRuns = steward.GroupName + "/max-concurrent-runs"
+
+	// AnnotationDefaultImagePullSecrets is the key of the annotation of a
+	// tenant namespace that defines a comma-separated list of names of
+	// image pull secrets in the tenant namespace. They are used by all
+	// pipeline runs in the namespace in addition to those in
+	// `spec.imagePullSecrets`, e.g. to pull a custom Jenkinsfile Runner
+	// image from a private registry.
+	AnnotationDefaultImagePullSecrets = steward.GroupName + "/default-image-pull-secrets"
+
+	// AnnotationProgressPrefix is the prefix of the keys of annotations
+	// of pipeline runs through which the pipeline reports its progress
+	// using the per-run token for the Steward API. The remainder of the
+	// key is the key of the corresponding entry of the progress config
+	// map in the run namespace, e.g. `stage`. Progress annotations take
+	// precedence over entries of the progress config map.
+	// They are the only fields the per-run token permits to change.
+	AnnotationProgressPrefix = "progress." + steward.GroupName + "/"
+
+	// AnnotationJaaSJobName is the key of the Jenkins-as-a-Service (JaaS)
+	// annotation of pipeline runs that holds the name of the Jenkins job.
+	// If the JaaS compatibility mode of the Steward admission webhook is
+	// enabled, it is mapped to and from `spec.runDetails.jobName`.
+	AnnotationJaaSJobName = "jaas.sap.com/job-name"
+
+	// AnnotationJaaSBuildNumber is the key of the Jenkins-as-a-Service
+	// (JaaS) annotation of pipeline runs that holds the build number of the
+	// Jenkins job as positive decimal integer.
+	// If the JaaS compatibility mode of the Steward admission webhook is
+	// enabled, it is mapped to and from `spec.runDetails.sequenceNumber`.
+	AnnotationJaaSBuildNumber = "jaas.sap.com/build-number"
+
+	// AnnotationJaaSBuildCause is the key of the Jenkins-as-a-Service
+	// (JaaS) annotation of pipeline runs that holds a textual description
+	// of the cause of the build.
+	// If the JaaS compatibility mode of the Steward admission webhook is
+	// enabled, it is mapped to and from `spec.runDetails.cause`.
+	AnnotationJaaSBuildCause = "jaas.sap.com/build-cause"
+)
+
+// values of annotation AnnotationSecretProvider
+const (
+	// SecretProviderKubernetes reads secrets from the tenant namespace.
+	SecretProviderKubernetes = "kubernetes"
+
+	// SecretProviderVault reads secrets from HashiCorp Vault as
+	// configured by the secret referenced by annotation
+	// AnnotationVaultSecret of the client namespace or, if not set, as
+	// configured for the run controller.
+	SecretProviderVault = "vault"
+
+	// SecretProviderAWSSecretsManager reads secrets from AWS Secrets
+	// Manager as configured for the run controller.
+	SecretProviderAWSSecretsManager = "aws-secrets-manager"
+
+	// SecretProviderFile reads secrets from files in a directory
+	// configured for the run controller. It is meant for tests.
+	SecretProviderFile = "file"
+)
+
+// values of annotation AnnotationTenantNamespaceCreation
+const (
+	// TenantNamespaceCreationEager makes the tenant controller create the
+	// tenant namespace as soon as the tenant is created.
+	TenantNamespaceCreationEager = "eager"
+
+	// TenantNamespaceCreationOnDemand makes the tenant controller create
+	// the tenant namespace only if requested via annotation
+	// AnnotationTenantNamespaceRequested at the tenant.
+	TenantNamespaceCreationOnDemand = "onDemand"
+)
+
+// values of annotation AnnotationTenantExpirationPolicy
+const (
+	// TenantExpirationPolicyHibernate makes the tenant controller
+	// hibernate expired tenants, i.e. delete their tenant namespace but
+	// keep their secrets, until the expiration date gets extended.
+	TenantExpirationPolicyHibernate = "hibernate"
+
+	// TenantExpirationPolicyDelete makes the tenant controller delete
+	// expired tenants.
+	TenantExpirationPolicyDelete = "delete"
 )
 
 // labels
@@ -67,6 +293,76 @@ const (
 	// Steward _pipeline run_ that the labelled object is owned by.
 	// The label value is the name of the PipelineRun custom resource.
 	LabelOwnerPipelineRunName = steward.GroupName + "/owner-pipelinerun-name"
+
+	// LabelPipelineSchedule is the key of the label of pipeline runs
+	// created by a PipelineSchedule. The label value is the name of the
+	// PipelineSchedule.
+	LabelPipelineSchedule = steward.GroupName + "/pipeline-schedule"
+
+	// LabelTrigger is the key of the label of pipeline runs created by
+	// the trigger server. The label value is the name of the Trigger,
+	// converted with TriggerLabelValue if it is not a valid label value.
+	LabelTrigger = steward.GroupName + "/trigger"
+
+	// LabelInstanceID is the key of the label that identifies the Steward
+	// instance a resource object belongs to. It allows running multiple
+	// independent Steward instances in one cluster.
+	// Controllers configured with an instance ID only process Steward
+	// resource objects with a matching label value and set the label at
+	// all objects they create. Controllers without instance ID only
+	// process Steward resource objects without this label.
+	LabelInstanceID = steward.GroupName + "/instance-id"
+
+	// LabelHostUsers is the key of the label of Tekton task runs and pods
+	// which requests that the pods run in a user namespace. The only
+	// supported value is `false`, which makes the Steward admission
+	// webhook set field `spec.hostUsers` of pods to `false`.
+	// Tekton propagates the label from task runs to their pods.
+	LabelHostUsers = steward.GroupName + "/host-users"
+
+	// LabelTopologySpread is the key of the label of Tekton task runs and
+	// pods whose presence makes the Steward admission webhook set field
+	// `spec.topologySpreadConstraints` of pods to the value of annotation
+	// AnnotationTopologySpreadConstraints.
+	// The value of the label is ignored and should be empty.
+	// Tekton propagates the label from task runs to their pods.
+	LabelTopologySpread = steward.GroupName + "/topology-spread"
+
+	// LabelTerminationGracePeriod is the key of the label of Tekton task
+	// runs and pods whose value is the termination grace period of the
+	// pods in seconds. The Steward admission webhook sets field
+	// `spec.terminationGracePeriodSeconds` of labelled pods accordingly.
+	// Tekton propagates the label from task runs to their pods.
+	LabelTerminationGracePeriod = steward.GroupName + "/termination-grace-period-seconds"
+
+	// LabelTriggeredByType is the key of the label of pipeline runs that
+	// records the type of the entity that triggered the pipeline run
+	// (`spec.triggeredBy.type`).
+	// It is set by the Steward admission webhook and cannot be modified by
+	// clients.
+	LabelTriggeredByType = steward.GroupName + "/triggered-by-type"
+
+	// LabelTriggeredByID is the key of the label of pipeline runs that
+	// records the ID of the entity that triggered the pipeline run
+	// (`spec.triggeredBy.id`). IDs that are not valid label values are
+	// converted with TriggeredByIDLabelValue.
+	// It is set by the Steward admission webhook and cannot be modified by
+	// clients.
+	LabelTriggeredByID = steward.GroupName + "/triggered-by-id"
+
+	// LabelPendingTenant is the key of the label of pipeline runs created
+	// in a client namespace for a tenant without tenant namespace, e.g.
+	// because the tenant namespace is created on demand. The label value
+	// is the name of the Tenant resource object.
+	// The run controller requests the creation of the tenant namespace and
+	// then moves the pipeline run into it.
+	LabelPendingTenant = steward.GroupName + "/pending-tenant"
+
+	// LabelGitHubAppToken is the key of the label of secrets containing a
+	// GitHub App installation token, which the run controller refreshes
+	// before the token expires.
+	// The value of the label is ignored and should be empty.
+	LabelGitHubAppToken = steward.GroupName + "/github-app-token"
 )
 
 // K8s events
@@ -95,6 +391,91 @@ const (
 	// run is not started due to maintenance mode
 	EventReasonMaintenanceMode = "MaintenanceMode"
 
+	// EventReasonMaxConcurrentRunsReached is the reason for an event occuring when a
+	// pipeline run is queued because the maximum number of concurrent pipeline runs
+	// in its namespace is reached
+	EventReasonMaxConcurrentRunsReached = "MaxConcurrentRunsReached"
+
+	// EventReasonMaxParallelRunsReached is the reason for an event occuring when a
+	// pipeline run is queued because the maximum number of parallel pipeline runs
+	// of the Steward installation is reached
+	EventReasonMaxParallelRunsReached = "MaxParallelRunsReached"
+
+	// EventReasonConcurrencyGroupBusy is the reason for an event occuring when a
+	// pipeline run is queued because another pipeline run of the same concurrency
+	// group is in progress
+	EventReasonConcurrencyGroupBusy = "ConcurrencyGroupBusy"
+
+	// EventReasonQuietHours is the reason for an event occuring when a
+	// pipeline run is queued because of the quiet hours of the Steward
+	// installation
+	EventReasonQuietHours = "QuietHours"
+
+	// EventReasonSuperseded is the reason for an event occuring when a pipeline
+	// run is aborted because a newer pipeline run of the same concurrency group
+	// has been created
+	EventReasonSuperseded = "Superseded"
+
+	// EventReasonPreflightCheckFailed is the reason for an event occuring
+	// when a pipeline run is not started because a pre-flight check failed
+	EventReasonPreflightCheckFailed = "PreflightCheckFailed"
+
+	// EventReasonProvenanceFailed is the reason for an event occuring when the
+	// provenance attestation of a successful pipeline run cannot be created
+	EventReasonProvenanceFailed = "ProvenanceFailed"
+
+	// EventReasonTimelineExportFailed is the reason for an event occuring
+	// when the run event timeline of a finished pipeline run cannot be
+	// exported to Elasticsearch
+	EventReasonTimelineExportFailed = "TimelineExportFailed"
+
+	// EventReasonAbortHandlerFailed is the reason for an event occuring
+	// when an abort handler invoked during the cleanup of a pipeline run
+	// failed or timed out
+	EventReasonAbortHandlerFailed = "AbortHandlerFailed"
+
+	// EventReasonRunnerSilent is the reason for an event occuring when a
+	// running pipeline run is aborted because the Jenkinsfile Runner did
+	// not send a heartbeat for longer than the configured maximum
+	EventReasonRunnerSilent = "RunnerSilent"
+
+	// EventReasonGitHubAppTokenRefreshFailed is the reason for an event
+	// occuring when a GitHub App installation token in the run namespace
+	// of a running pipeline run could not be refreshed
+	EventReasonGitHubAppTokenRefreshFailed = "GitHubAppTokenRefreshFailed"
+
+	// EventReasonRunAPITokenRefreshFailed is the reason for an event
+	// occuring when the per-run token for the Steward API in the run
+	// namespace of a pipeline run could not be refreshed
+	EventReasonRunAPITokenRefreshFailed = "RunAPITokenRefreshFailed"
+
+	// EventReasonSecretResyncFailed is the reason for an event occuring
+	// when the copies of changed secrets in the run namespace of a
+	// waiting pipeline run could not be refreshed
+	EventReasonSecretResyncFailed = "SecretResyncFailed"
+
+	// EventReasonLogArchiveFailed is the reason for an event occuring when
+	// the log of a finished pipeline run cannot be stored in the log
+	// archive before the run namespace is deleted
+	EventReasonLogArchiveFailed = "LogArchiveFailed"
+
+	// EventReasonStatusOffloadFailed is the reason for an event occuring
+	// when the bulky status fields of a finished pipeline run cannot be
+	// moved to a config map. The status is kept as it is in this case.
+	EventReasonStatusOffloadFailed = "StatusOffloadFailed"
+
+	// EventReasonStateChanged is the reason for an event occuring when a
+	// pipeline run changes its state
+	EventReasonStateChanged = "StateChanged"
+
+	// EventReasonAborted is the reason for an event occuring when a
+	// pipeline run is aborted on request of the client
+	EventReasonAborted = "Aborted"
+
+	// EventReasonTimeout is the reason for an event occuring when a
+	// pipeline run is terminated with result `timeout`
+	EventReasonTimeout = "Timeout"
+
 	// MaintenanceModeConfigMapName is the name of the config map to enable the maintenance mode
 	MaintenanceModeConfigMapName = "steward-maintenance-mode"
 
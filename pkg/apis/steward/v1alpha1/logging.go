@@ -0,0 +1,217 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NewCustomJSON returns a CustomJSON holding the JSON representation of the
+// given value. The value can be anything that can be marshalled to JSON,
+// e.g. a struct with JSON field tags. It is converted to the generic
+// representation (maps, slices, strings, float64 numbers, bools and nil)
+// which CustomJSON values have after decoding, so that the result does not
+// change when being sent to and read back from the API server.
+func NewCustomJSON(value interface{}) (*CustomJSON, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "value cannot be represented as JSON")
+	}
+	result := &CustomJSON{}
+	if err := result.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// NewElasticsearchLogging returns a logging configuration that enables
+// logging to Elasticsearch with the given run ID. The run ID can be any
+// value accepted by NewCustomJSON.
+//
+// Deprecated: Use a logging configuration with a LogSink instead.
+func NewElasticsearchLogging(runID interface{}) (*Logging, error) {
+	runIDJSON, err := NewCustomJSON(runID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid run ID")
+	}
+	return &Logging{
+		Elasticsearch: &Elasticsearch{
+			RunID: runIDJSON,
+		},
+	}, nil
+}
+
+// SetElasticsearchRunID enables logging to Elasticsearch for the pipeline
+// run and sets the given run ID. Other settings of the logging
+// configuration are retained. The run ID can be any value accepted by
+// NewCustomJSON.
+//
+// Deprecated: Use a logging configuration with a LogSink instead.
+func (s *PipelineSpec) SetElasticsearchRunID(runID interface{}) error {
+	runIDJSON, err := NewCustomJSON(runID)
+	if err != nil {
+		return errors.WithMessage(err, "invalid run ID")
+	}
+	if s.Logging == nil {
+		s.Logging = &Logging{}
+	}
+	if s.Logging.Elasticsearch == nil {
+		s.Logging.Elasticsearch = &Elasticsearch{}
+	}
+	s.Logging.Elasticsearch.RunID = runIDJSON
+	return nil
+}
+
+// HasSink returns whether a log sink is configured, either via `sink` or
+// via the deprecated `elasticsearch` configuration.
+func (l *Logging) HasSink() bool {
+	return l != nil && (l.Sink != nil || l.Elasticsearch != nil)
+}
+
+// EffectiveSink returns the log sink of the logging configuration, i.e.
+// `sink` or the conversion of the deprecated `elasticsearch`
+// configuration. It returns nil if no log sink is configured and an error
+// if both are set.
+// The result is not validated. See LogSink.Validate.
+// Use RunIDJSON to get the run ID, as the conversion of the deprecated
+// configuration may not retain it.
+func (l *Logging) EffectiveSink() (*LogSink, error) {
+	if l == nil {
+		return nil, nil
+	}
+	if l.Sink != nil {
+		if l.Elasticsearch != nil {
+			return nil, errors.New("fields spec.logging.sink and spec.logging.elasticsearch must not be combined")
+		}
+		return l.Sink, nil
+	}
+	if l.Elasticsearch != nil {
+		return ConvertElasticsearchToLogSink(l.Elasticsearch), nil
+	}
+	return nil, nil
+}
+
+// RunIDJSON returns the value of field `runid` attached to each log entry
+// or nil if no log sink is configured.
+// For the deprecated `elasticsearch` configuration this is the run ID
+// as specified, which can be any JSON value.
+func (l *Logging) RunIDJSON() *CustomJSON {
+	switch {
+	case l == nil:
+		return nil
+	case l.Sink != nil:
+		return l.Sink.RunIDJSON()
+	case l.Elasticsearch != nil:
+		if l.Elasticsearch.RunID == nil {
+			return &CustomJSON{}
+		}
+		return l.Elasticsearch.RunID
+	}
+	return nil
+}
+
+// ConvertElasticsearchToLogSink converts the deprecated Elasticsearch
+// logging configuration to a log sink of type elasticsearch.
+// A run ID being a string is converted to LogSink.RunID, a run ID being
+// an object with string values to LogSink.Fields. Other run IDs cannot be
+// represented by a log sink and are not converted. They are still sent
+// to the log sink unchanged (see Logging.RunIDJSON).
+// `indexURL` and `authSecret` are not converted as they have never been
+// evaluated.
+func ConvertElasticsearchToLogSink(elasticsearch *Elasticsearch) *LogSink {
+	sink := &LogSink{Type: LogSinkTypeElasticsearch}
+	if elasticsearch.RunID == nil {
+		return sink
+	}
+	switch runID := elasticsearch.RunID.Value.(type) {
+	case string:
+		sink.RunID = runID
+	case map[string]interface{}:
+		fields := make(map[string]string, len(runID))
+		for key, value := range runID {
+			stringValue, ok := value.(string)
+			if !ok {
+				return sink
+			}
+			fields[key] = stringValue
+		}
+		sink.Fields = fields
+	}
+	return sink
+}
+
+// Validate checks that the log sink is well-formed.
+func (s *LogSink) Validate() error {
+	if s.Type != LogSinkTypeElasticsearch {
+		return errors.Errorf("field spec.logging.sink.type has unsupported value %q", s.Type)
+	}
+	if s.EndpointRef != nil {
+		if errs := validation.IsDNS1123Subdomain(s.EndpointRef.SecretName); len(errs) > 0 {
+			return errors.Errorf(
+				"field spec.logging.sink.endpointRef.secretName has invalid value %q: %s",
+				s.EndpointRef.SecretName, strings.Join(errs, "; "),
+			)
+		}
+	}
+	if s.Index != "" {
+		if err := validateElasticsearchIndexName(s.Index); err != nil {
+			return errors.Wrapf(err, "field spec.logging.sink.index has invalid value %q", s.Index)
+		}
+	}
+	for key := range s.Fields {
+		if key == "" {
+			return errors.New("field spec.logging.sink.fields must not contain an empty key")
+		}
+		if key == logSinkFieldID && s.RunID != "" {
+			return errors.Errorf("field spec.logging.sink.fields must not contain key %q if spec.logging.sink.runID is set", logSinkFieldID)
+		}
+	}
+	return nil
+}
+
+// logSinkFieldID is the member of the run ID object holding
+// LogSink.RunID if LogSink.Fields is set.
+const logSinkFieldID = "id"
+
+// RunIDJSON returns the value of field `runid` attached to each log entry:
+// null if neither RunID nor Fields is set, RunID as string if Fields is not
+// set, otherwise an object containing Fields and RunID as member `id`.
+func (s *LogSink) RunIDJSON() *CustomJSON {
+	if len(s.Fields) == 0 {
+		if s.RunID == "" {
+			return &CustomJSON{}
+		}
+		return &CustomJSON{Value: s.RunID}
+	}
+	value := make(map[string]interface{}, len(s.Fields)+1)
+	for key, fieldValue := range s.Fields {
+		value[key] = fieldValue
+	}
+	if s.RunID != "" {
+		value[logSinkFieldID] = s.RunID
+	}
+	return &CustomJSON{Value: value}
+}
+
+// validateElasticsearchIndexName checks the restrictions Elasticsearch
+// imposes on index names.
+func validateElasticsearchIndexName(name string) error {
+	if len(name) > 255 {
+		return errors.New("must not be longer than 255 bytes")
+	}
+	if name == "." || name == ".." {
+		return errors.New("must not be '.' or '..'")
+	}
+	if strings.ToLower(name) != name {
+		return errors.New("must be lowercase")
+	}
+	if strings.ContainsAny(name, `\/*?"<>| ,#:`) {
+		return errors.New(`must not contain any of \, /, *, ?, ", <, >, |, space, comma, # or :`)
+	}
+	if strings.IndexAny(name[:1], "-_+") == 0 {
+		return errors.New("must not start with -, _ or +")
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package v1alpha1_test
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+type testRunID struct {
+	JobID       string   `json:"jobId"`
+	BuildNumber int      `json:"buildNumber"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+func Test_NewCustomJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"nil", nil, nil},
+		{"string", "run1", "run1"},
+		{"int", 42, 42.0},
+		{"map", map[string]string{"k1": "v1"}, map[string]interface{}{"k1": "v1"}},
+		{
+			"struct",
+			testRunID{JobID: "job1", BuildNumber: 3, Tags: []string{"a"}},
+			map[string]interface{}{"jobId": "job1", "buildNumber": 3.0, "tags": []interface{}{"a"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := v1alpha1.NewCustomJSON(tc.value)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result.Value)
+			assert.DeepEqual(t, result, result.DeepCopy())
+		})
+	}
+}
+
+func Test_NewCustomJSON_InvalidValue(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, err := v1alpha1.NewCustomJSON(make(chan int))
+
+	// VERIFY
+	assert.Error(t, err, "value cannot be represented as JSON: json: unsupported type: chan int")
+	assert.Assert(t, result == nil)
+}
+
+func Test_NewElasticsearchLogging(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, err := v1alpha1.NewElasticsearchLogging(testRunID{JobID: "job1", BuildNumber: 3})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &v1alpha1.Logging{
+		Elasticsearch: &v1alpha1.Elasticsearch{
+			RunID: &v1alpha1.CustomJSON{
+				Value: map[string]interface{}{"jobId": "job1", "buildNumber": 3.0},
+			},
+		},
+	}, result)
+}
+
+func Test_NewElasticsearchLogging_InvalidRunID(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, err := v1alpha1.NewElasticsearchLogging(func() {})
+
+	// VERIFY
+	assert.Error(t, err, "invalid run ID: value cannot be represented as JSON: json: unsupported type: func()")
+	assert.Assert(t, result == nil)
+}
+
+func Test_PipelineSpec_SetElasticsearchRunID(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		logging  *v1alpha1.Logging
+		expected *v1alpha1.Elasticsearch
+	}{
+		{
+			name:     "no logging",
+			expected: &v1alpha1.Elasticsearch{RunID: &v1alpha1.CustomJSON{Value: "run1"}},
+		},
+		{
+			name:     "no elasticsearch",
+			logging:  &v1alpha1.Logging{},
+			expected: &v1alpha1.Elasticsearch{RunID: &v1alpha1.CustomJSON{Value: "run1"}},
+		},
+		{
+			name: "existing elasticsearch",
+			logging: &v1alpha1.Logging{
+				Elasticsearch: &v1alpha1.Elasticsearch{
+					RunID:    &v1alpha1.CustomJSON{Value: "run0"},
+					IndexURL: "https://es.example.com/index1",
+				},
+			},
+			expected: &v1alpha1.Elasticsearch{
+				RunID:    &v1alpha1.CustomJSON{Value: "run1"},
+				IndexURL: "https://es.example.com/index1",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &v1alpha1.PipelineSpec{Logging: tc.logging}
+
+			// EXERCISE
+			err := examinee.SetElasticsearchRunID("run1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, examinee.Logging.Elasticsearch)
+		})
+	}
+}
+
+func Test_Logging_EffectiveSink(t *testing.T) {
+	t.Parallel()
+
+	sink := &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch, RunID: "run1"}
+
+	for _, tc := range []struct {
+		name          string
+		logging       *v1alpha1.Logging
+		expected      *v1alpha1.LogSink
+		expectedError string
+	}{
+		{"nil", nil, nil, ""},
+		{"empty", &v1alpha1.Logging{}, nil, ""},
+		{"sink", &v1alpha1.Logging{Sink: sink}, sink, ""},
+		{
+			name:     "elasticsearch",
+			logging:  &v1alpha1.Logging{Elasticsearch: &v1alpha1.Elasticsearch{RunID: &v1alpha1.CustomJSON{Value: "run1"}}},
+			expected: sink,
+		},
+		{
+			name:          "both",
+			logging:       &v1alpha1.Logging{Sink: sink, Elasticsearch: &v1alpha1.Elasticsearch{}},
+			expectedError: "fields spec.logging.sink and spec.logging.elasticsearch must not be combined",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := tc.logging.EffectiveSink()
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result)
+			assert.Equal(t, tc.expected != nil, tc.logging.HasSink())
+		})
+	}
+}
+
+func Test_ConvertElasticsearchToLogSink(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		runID    *v1alpha1.CustomJSON
+		expected *v1alpha1.LogSink
+	}{
+		{"no run ID", nil, &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch}},
+		{"null", &v1alpha1.CustomJSON{}, &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch}},
+		{"string", &v1alpha1.CustomJSON{Value: "run1"}, &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch, RunID: "run1"}},
+		{
+			name:     "object",
+			runID:    &v1alpha1.CustomJSON{Value: map[string]interface{}{"jobId": "job1", "id": "1"}},
+			expected: &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch, Fields: map[string]string{"jobId": "job1", "id": "1"}},
+		},
+		{
+			name:     "object with number",
+			runID:    &v1alpha1.CustomJSON{Value: map[string]interface{}{"jobId": "job1", "buildNumber": 3.0}},
+			expected: &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch},
+		},
+		{
+			name:     "number",
+			runID:    &v1alpha1.CustomJSON{Value: 3.0},
+			expected: &v1alpha1.LogSink{Type: v1alpha1.LogSinkTypeElasticsearch},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := v1alpha1.ConvertElasticsearchToLogSink(&v1alpha1.Elasticsearch{RunID: tc.runID})
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Logging_RunIDJSON(t *testing.T) {
+	t.Parallel()
+
+	deepRunID := &v1alpha1.CustomJSON{Value: map[string]interface{}{"key1": map[string]interface{}{"key2": 3.0}}}
+
+	for _, tc := range []struct {
+		name     string
+		logging  *v1alpha1.Logging
+		expected *v1alpha1.CustomJSON
+	}{
+		{"nil", nil, nil},
+		{"empty", &v1alpha1.Logging{}, nil},
+		{"sink", &v1alpha1.Logging{Sink: &v1alpha1.LogSink{RunID: "run1"}}, &v1alpha1.CustomJSON{Value: "run1"}},
+		{"elasticsearch_no_run_id", &v1alpha1.Logging{Elasticsearch: &v1alpha1.Elasticsearch{}}, &v1alpha1.CustomJSON{}},
+		{"elasticsearch_unconvertible", &v1alpha1.Logging{Elasticsearch: &v1alpha1.Elasticsearch{RunID: deepRunID}}, deepRunID},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := tc.logging.RunIDJSON()
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_LogSink_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		sink          v1alpha1.LogSink
+		expectedError string
+	}{
+		{"minimal", v1alpha1.LogSink{Type: "elasticsearch"}, ""},
+		{
+			name: "complete",
+			sink: v1alpha1.LogSink{
+				Type:        "elasticsearch",
+				EndpointRef: &v1alpha1.LogSinkEndpointRef{SecretName: "secret1"},
+				Index:       "logs.v1",
+				RunID:       "run1",
+				Fields:      map[string]string{"jobId": "job1"},
+			},
+		},
+		{"no type", v1alpha1.LogSink{}, `field spec.logging.sink.type has unsupported value ""`},
+		{"unknown type", v1alpha1.LogSink{Type: "foo"}, `field spec.logging.sink.type has unsupported value "foo"`},
+		{
+			name:          "invalid secret name",
+			sink:          v1alpha1.LogSink{Type: "elasticsearch", EndpointRef: &v1alpha1.LogSinkEndpointRef{SecretName: "Secret1"}},
+			expectedError: `field spec.logging.sink.endpointRef.secretName has invalid value "Secret1"`,
+		},
+		{"uppercase index", v1alpha1.LogSink{Type: "elasticsearch", Index: "Logs"}, "must be lowercase"},
+		{"index with slash", v1alpha1.LogSink{Type: "elasticsearch", Index: "logs/_doc"}, "must not contain any of"},
+		{"index with leading dash", v1alpha1.LogSink{Type: "elasticsearch", Index: "-logs"}, "must not start with -, _ or +"},
+		{"index dot", v1alpha1.LogSink{Type: "elasticsearch", Index: ".."}, "must not be '.' or '..'"},
+		{"empty field key", v1alpha1.LogSink{Type: "elasticsearch", Fields: map[string]string{"": "v"}}, "field spec.logging.sink.fields must not contain an empty key"},
+		{
+			name:          "field id with run ID",
+			sink:          v1alpha1.LogSink{Type: "elasticsearch", RunID: "run1", Fields: map[string]string{"id": "v"}},
+			expectedError: `field spec.logging.sink.fields must not contain key "id" if spec.logging.sink.runID is set`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			err := tc.sink.Validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_LogSink_RunIDJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		sink     v1alpha1.LogSink
+		expected interface{}
+	}{
+		{"empty", v1alpha1.LogSink{}, nil},
+		{"run ID", v1alpha1.LogSink{RunID: "run1"}, "run1"},
+		{"fields", v1alpha1.LogSink{Fields: map[string]string{"k1": "v1"}}, map[string]interface{}{"k1": "v1"}},
+		{"both", v1alpha1.LogSink{RunID: "run1", Fields: map[string]string{"k1": "v1"}}, map[string]interface{}{"k1": "v1", "id": "run1"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := tc.sink.RunIDJSON()
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result.Value)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package v1alpha1
+
+import (
+	"sort"
+	"strings"
+)
+
+// MessageCode identifies a user-facing status message independent of its
+// wording. Clients should match on message codes instead of messages
+// and may use them to display localized messages.
+type MessageCode string
+
+const (
+	// MessageCodeTenantNamespaceCreationFailed indicates that the tenant
+	// namespace could not be created.
+	MessageCodeTenantNamespaceCreationFailed MessageCode = "TenantNamespaceCreationFailed"
+
+	// MessageCodeTenantRoleBindingCreationFailed indicates that the
+	// role binding in a new tenant namespace could not be created.
+	MessageCodeTenantRoleBindingCreationFailed MessageCode = "TenantRoleBindingCreationFailed"
+
+	// MessageCodeTenantNamespaceMissing indicates that the tenant
+	// namespace does not exist anymore.
+	// Message argument `namespace` is the name of the tenant namespace.
+	MessageCodeTenantNamespaceMissing MessageCode = "TenantNamespaceMissing"
+
+	// MessageCodeTenantRoleBindingUpdateFailed indicates that the role
+	// binding in the tenant namespace is outdated but could not be updated.
+	// Message argument `namespace` is the name of the tenant namespace.
+	MessageCodeTenantRoleBindingUpdateFailed MessageCode = "TenantRoleBindingUpdateFailed"
+
+	// MessageCodeTenantNamespaceAdoptionFailed indicates that the namespace
+	// requested to be adopted as tenant namespace could not be adopted.
+	// Message argument `namespace` is the name of the namespace.
+	MessageCodeTenantNamespaceAdoptionFailed MessageCode = "TenantNamespaceAdoptionFailed"
+
+	// MessageCodeTenantNamespaceOnDemand indicates that the tenant
+	// namespace has not been created yet because it is created on demand
+	// only.
+	MessageCodeTenantNamespaceOnDemand MessageCode = "TenantNamespaceOnDemand"
+
+	// MessageCodeTenantHibernated indicates that the tenant namespace
+	// has been deleted because there was no pipeline run activity for
+	// the configured hibernation period.
+	// Message argument `namespace` is the name of the deleted tenant
+	// namespace.
+	MessageCodeTenantHibernated MessageCode = "TenantHibernated"
+
+	// MessageCodeTenantHibernationRestoreFailed indicates that the secrets
+	// of a hibernated tenant could not be restored into a new tenant
+	// namespace.
+	MessageCodeTenantHibernationRestoreFailed MessageCode = "TenantHibernationRestoreFailed"
+
+	// MessageCodeTenantNetworkPoliciesFailed indicates that the configured
+	// network policies could not be set up in the tenant namespace.
+	// Message argument `namespace` is the name of the tenant namespace.
+	MessageCodeTenantNetworkPoliciesFailed MessageCode = "TenantNetworkPoliciesFailed"
+
+	// MessageCodeTenantRelocating indicates that the tenant is being moved
+	// to a new tenant namespace.
+	// Message argument `namespace` is the name of the current tenant
+	// namespace, `newNamespace` the name of the new one.
+	MessageCodeTenantRelocating MessageCode = "TenantRelocating"
+
+	// MessageCodeTenantRelocationPending indicates that the tenant has
+	// been moved to a new tenant namespace and the former tenant namespace
+	// gets deleted when all pipeline runs in there have finished.
+	// Message argument `namespace` is the name of the former tenant
+	// namespace, `newNamespace` the name of the new one.
+	MessageCodeTenantRelocationPending MessageCode = "TenantRelocationPending"
+
+	// MessageCodeTenantRelocated indicates that the tenant has been moved
+	// to a new tenant namespace and the former one has been deleted.
+	// Message argument `namespace` is the name of the former tenant
+	// namespace, `newNamespace` the name of the new one.
+	MessageCodeTenantRelocated MessageCode = "TenantRelocated"
+
+	// MessageCodeTenantRelocationFailed indicates that the tenant could not
+	// be moved to a new tenant namespace.
+	// Message argument `namespace` is the name of the current tenant
+	// namespace, `newNamespace` the name of the new one.
+	MessageCodeTenantRelocationFailed MessageCode = "TenantRelocationFailed"
+
+	// MessageCodeTenantRelocationRefused indicates that the relocation of
+	// the tenant has been requested, but the tenant namespace has been
+	// adopted and therefore cannot be replaced.
+	// Message argument `namespace` is the name of the tenant namespace.
+	MessageCodeTenantRelocationRefused MessageCode = "TenantRelocationRefused"
+
+	// MessageCodeTenantExpiring indicates that the expiration date of the
+	// tenant is within the expiration warning period of the client.
+	// Message argument `expirationDate` is the expiration date in RFC 3339
+	// format.
+	MessageCodeTenantExpiring MessageCode = "TenantExpiring"
+
+	// MessageCodeTenantExpired indicates that the expiration date of the
+	// tenant has passed.
+	// Message argument `expirationDate` is the expiration date in RFC 3339
+	// format.
+	MessageCodeTenantExpired MessageCode = "TenantExpired"
+)
+
+// messageCatalog maps message codes to English message templates.
+// Placeholders of the form `{name}` get replaced by message arguments.
+var messageCatalog = map[MessageCode]string{
+	MessageCodeTenantNamespaceCreationFailed: "Failed to create a new tenant namespace.",
+
+	MessageCodeTenantRoleBindingCreationFailed: "Failed to initialize a new tenant namespace because the RoleBinding could not be created.",
+
+	MessageCodeTenantNamespaceMissing: "The tenant namespace \"{namespace}\" does not exist anymore." +
+		" This issue must be analyzed and fixed by an operator.",
+
+	MessageCodeTenantRoleBindingUpdateFailed: "The RoleBinding in tenant namespace \"{namespace}\" is outdated but could not be updated.",
+
+	MessageCodeTenantNamespaceAdoptionFailed: "Failed to adopt namespace \"{namespace}\" as tenant namespace." +
+		" Check that the namespace is an orphaned tenant namespace of this tenant.",
+
+	MessageCodeTenantNamespaceOnDemand: "The tenant namespace will be created on demand." +
+		" It gets created with the first pipeline run labelled with \"steward.sap.com/pending-tenant\"" +
+		" or if annotation \"steward.sap.com/tenant-namespace-requested\" is set.",
+
+	MessageCodeTenantHibernated: "The tenant namespace \"{namespace}\" has been deleted due to inactivity." +
+		" A new tenant namespace with the secrets of the deleted one gets created" +
+		" with the first pipeline run labelled with \"steward.sap.com/pending-tenant\"" +
+		" or if annotation \"steward.sap.com/tenant-namespace-requested\" is set.",
+
+	MessageCodeTenantHibernationRestoreFailed: "Failed to restore the secrets of the hibernated tenant into a new tenant namespace.",
+
+	MessageCodeTenantNetworkPoliciesFailed: "The network policies in tenant namespace \"{namespace}\" could not be set up." +
+		" This issue must be analyzed and fixed by an operator.",
+
+	MessageCodeTenantRelocating: "The tenant is being moved from tenant namespace \"{namespace}\" to \"{newNamespace}\".",
+
+	MessageCodeTenantRelocationPending: "The tenant has been moved to tenant namespace \"{newNamespace}\"." +
+		" The former tenant namespace \"{namespace}\" gets deleted when all pipeline runs in there have finished.",
+
+	MessageCodeTenantRelocated: "The tenant has been moved from tenant namespace \"{namespace}\" to \"{newNamespace}\".",
+
+	MessageCodeTenantRelocationFailed: "Failed to move the tenant from tenant namespace \"{namespace}\" to \"{newNamespace}\"." +
+		" The tenant keeps using tenant namespace \"{namespace}\" until the relocation succeeds.",
+
+	MessageCodeTenantRelocationRefused: "The tenant cannot be moved to a new tenant namespace because tenant namespace \"{namespace}\" has been adopted.",
+
+	MessageCodeTenantExpiring: "The tenant expires at {expirationDate}." +
+		" Extend \"spec.expirationDate\" to keep using the tenant afterwards.",
+
+	MessageCodeTenantExpired: "The tenant expired at {expirationDate}." +
+		" Extend \"spec.expirationDate\" to reactivate the tenant.",
+}
+
+// Template returns the English message template for the message code.
+// If the code is unknown, the code itself is returned.
+func (c MessageCode) Template() string {
+	if template, ok := messageCatalog[c]; ok {
+		return template
+	}
+	return string(c)
+}
+
+// Format returns the English message for the message code with all
+// placeholders replaced by the given arguments.
+func (c MessageCode) Format(args map[string]string) string {
+	message := c.Template()
+	if len(args) == 0 {
+		return message
+	}
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	oldnew := make([]string, 0, 2*len(args))
+	for _, key := range keys {
+		oldnew = append(oldnew, "{"+key+"}", args[key])
+	}
+	return strings.NewReplacer(oldnew...).Replace(message)
+}
@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_MessageCode_Template_AllCodesHaveTemplates(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []MessageCode{
+		MessageCodeTenantNamespaceCreationFailed,
+		MessageCodeTenantRoleBindingCreationFailed,
+		MessageCodeTenantNamespaceMissing,
+		MessageCodeTenantRoleBindingUpdateFailed,
+		MessageCodeTenantNamespaceOnDemand,
+		MessageCodeTenantHibernated,
+		MessageCodeTenantHibernationRestoreFailed,
+		MessageCodeTenantNetworkPoliciesFailed,
+		MessageCodeTenantRelocating,
+		MessageCodeTenantRelocationPending,
+		MessageCodeTenantRelocated,
+		MessageCodeTenantRelocationFailed,
+		MessageCodeTenantRelocationRefused,
+		MessageCodeTenantExpiring,
+		MessageCodeTenantExpired,
+	} {
+		assert.Assert(t, code.Template() != string(code), "no template for %q", code)
+	}
+}
+
+func Test_MessageCode_Template_UnknownCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Unknown1", MessageCode("Unknown1").Template())
+}
+
+func Test_MessageCode_Format(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := MessageCodeTenantNamespaceMissing.Format(map[string]string{
+		"namespace": "ns1",
+		"unused":    "foo",
+	})
+
+	// VERIFY
+	assert.Equal(t,
+		`The tenant namespace "ns1" does not exist anymore. This issue must be analyzed and fixed by an operator.`,
+		result,
+	)
+}
+
+func Test_MessageCode_Format_NoArgs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		`The tenant namespace "{namespace}" does not exist anymore. This issue must be analyzed and fixed by an operator.`,
+		MessageCodeTenantNamespaceMissing.Format(nil),
+	)
+}
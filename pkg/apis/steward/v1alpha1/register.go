@@ -29,6 +29,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&PipelineRun{},
 		&PipelineRunList{},
+		&PipelineRunTemplate{},
+		&PipelineRunTemplateList{},
+		&Trigger{},
+		&TriggerList{},
+		&PipelineSchedule{},
+		&PipelineScheduleList{},
+		&RunReport{},
+		&RunReportList{},
 		&Tenant{},
 		&TenantList{},
 	)
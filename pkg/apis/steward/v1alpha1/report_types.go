@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunReport is a Kubernetes custom resource type summarizing the pipeline
+// runs of a client namespace finished within a recent time window.
+// Run reports are written by the run report aggregator of the run
+// controller, if enabled, and must not be modified by clients.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RunReport struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Summary RunReportSummary `json:"summary"`
+}
+
+// RunReportSummary is the summary of the pipeline runs finished within
+// the time window of a run report.
+type RunReportSummary struct {
+	// WindowStart is the begin of the time window, inclusive.
+	WindowStart metav1.Time `json:"windowStart"`
+
+	// WindowEnd is the end of the time window, exclusive. It is the time
+	// the report has been computed.
+	WindowEnd metav1.Time `json:"windowEnd"`
+
+	// Total is the number of pipeline runs finished within the time
+	// window.
+	Total int `json:"total"`
+
+	// Failed is the number of pipeline runs finished within the time
+	// window with a failure result, i.e. `error_infra`, `error_content`,
+	// `error_config` or `timeout`.
+	Failed int `json:"failed"`
+
+	// Results maps each result to the number of pipeline runs finished
+	// within the time window with this result.
+	// +optional
+	Results map[Result]int `json:"results,omitempty"`
+
+	// DurationP50 is the median duration of the pipeline runs finished
+	// within the time window, from start to finish.
+	// +optional
+	DurationP50 *metav1.Duration `json:"durationP50,omitempty"`
+
+	// DurationP95 is the 95th percentile of the durations of the
+	// pipeline runs finished within the time window, from start to
+	// finish.
+	// +optional
+	DurationP95 *metav1.Duration `json:"durationP95,omitempty"`
+
+	// TopFailingPipelines are the pipelines with the most failed
+	// pipeline runs within the time window, most failures first.
+	// +optional
+	TopFailingPipelines []FailingPipeline `json:"topFailingPipelines,omitempty"`
+}
+
+// FailingPipeline is a pipeline with failed pipeline runs.
+type FailingPipeline struct {
+	// RepoURL is the URL of the pipeline repository.
+	RepoURL string `json:"repoUrl"`
+
+	// RelativePath is the path of the pipeline definition file within
+	// the pipeline repository.
+	RelativePath string `json:"relativePath"`
+
+	// Failures is the number of failed pipeline runs of the pipeline
+	// within the time window.
+	Failures int `json:"failures"`
+
+	// LastFailedRun is the name of the most recently finished failed
+	// pipeline run of the pipeline.
+	LastFailedRun string `json:"lastFailedRun"`
+}
+
+// RunReportList is a list of RunReport objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RunReportList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RunReport `json:"items"`
+}
@@ -35,10 +35,25 @@ type PipelineSpec struct {
 	// JenkinsfileRunner configures the Jenkinsfile Runner container.
 	JenkinsfileRunner *JenkinsfileRunnerSpec `json:"jenkinsfileRunner,omitempty"`
 
+	// Resources are the compute resource requests and limits (`cpu`,
+	// `memory` and `ephemeral-storage`) of the Jenkinsfile Runner
+	// container. Resources set here replace the defaults of the Steward
+	// installation and must be within the bounds configured for it.
+	// Extended resources are requested via JenkinsfileRunner.Resources.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
 	// JenkinsFile contains the configuration of the Jenkins pipeline definition
-	// to be executed.
+	// to be executed. It is optional if TemplateRef is set.
+	// +optional
 	JenkinsFile JenkinsFile `json:"jenkinsFile"`
 
+	// Sources is the list of additional source repositories to be made
+	// available to the pipeline, e.g. a configuration repository in
+	// addition to the code repository.
+	// +optional
+	Sources []SourceRepository `json:"sources,omitempty"`
+
 	// Args contains the key-value parameters to pass to the pipeline.
 	// +optional
 	Args map[string]string `json:"args,omitempty"`
@@ -49,6 +64,13 @@ type PipelineSpec struct {
 	// +optional
 	Secrets []string `json:"secrets,omitempty"`
 
+	// SealedSecrets is the list of secrets to be made available to the
+	// pipeline execution, whose values are passed inline in encrypted form.
+	// Unlike `Secrets`, no Kubernetes `v1/Secret` resource objects need to
+	// be created in advance.
+	// +optional
+	SealedSecrets []SealedSecret `json:"sealedSecrets,omitempty"`
+
 	// ImagePullSecrets is the list of image pull secrets required by the
 	// pipeline run to pull images of custom containers from private registries.
 	// Each entry in the list is the name of a Kubernetes `v1/Secret` resource
@@ -66,16 +88,145 @@ type PipelineSpec struct {
 	// +optional
 	Intent Intent `json:"intent,omitempty"`
 
+	// ConcurrencyGroup is the name of the concurrency group of the
+	// pipeline run. Pipeline runs of the same concurrency group in a
+	// namespace are executed serially: a pipeline run is not started
+	// before all other pipeline runs of the group have finished. Older
+	// pipeline runs of the group waiting to be started are aborted when
+	// a newer one is created.
+	// If not set, the pipeline run does not belong to a concurrency group.
+	// +optional
+	ConcurrencyGroup string `json:"concurrencyGroup,omitempty"`
+
+	// ConcurrencyCancelInProgress defines whether pipeline runs of the
+	// same concurrency group which are already in progress are aborted
+	// when this pipeline run is created, instead of waiting for them to
+	// finish. It is only relevant if ConcurrencyGroup is set.
+	// +optional
+	ConcurrencyCancelInProgress bool `json:"concurrencyCancelInProgress,omitempty"`
+
 	// Logging contains the logging configuration.
 	// +optional
 	Logging *Logging `json:"logging,omitempty"`
 
+	// Timeout is the maximum execution time of the pipeline run.
+	// It can only shorten the timeout configured for the Steward
+	// installation. If not set, the configured timeout is used.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TTLSecondsAfterFinished is the time in seconds after which the
+	// pipeline run gets deleted once it has finished. If not set, the
+	// default of the Steward installation applies.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
 	// RunDetails provides metadata for a pipeline run which is evaluated by the
 	// Jenkinsfile Runner.
 	// +optional
 	RunDetails *PipelineRunDetails `json:"runDetails,omitempty"`
 
 	Profiles *Profiles `json:"profiles,omitempty"`
+
+	// TestResults configures the extraction of test results from the
+	// pipeline workspace after the pipeline has been executed.
+	// If not set, no test results are extracted.
+	// +optional
+	TestResults *TestResultsSpec `json:"testResults,omitempty"`
+
+	// Artifacts configures the upload of files from the pipeline
+	// workspace to an S3-compatible object storage after the pipeline has
+	// been executed.
+	// If not set, no artifacts are uploaded.
+	// +optional
+	Artifacts *ArtifactsSpec `json:"artifacts,omitempty"`
+
+	// TriggeredBy identifies the entity that triggered the pipeline run,
+	// e.g. a pull request or an upstream pipeline run. It is copied to the
+	// status and cannot be changed after the pipeline run has been created.
+	// +optional
+	TriggeredBy *TriggeredBy `json:"triggeredBy,omitempty"`
+
+	// TemplateRef refers to a PipelineRunTemplate in the same namespace
+	// providing the defaults of this spec. Fields set in this spec take
+	// precedence over the template, `args` are merged per key and
+	// `secrets` and `imagePullSecrets` are added to those of the
+	// template. The template is resolved when the pipeline run is
+	// started, i.e. later changes of the template do not affect the
+	// pipeline run.
+	// +optional
+	TemplateRef *PipelineRunTemplateRef `json:"templateRef,omitempty"`
+}
+
+// TriggeredBy identifies the entity that triggered a pipeline run.
+type TriggeredBy struct {
+	// Type is the type of the triggering entity, e.g.
+	// `github-pull-request` or `pipelinerun`. It must be a valid label
+	// value.
+	Type string `json:"type"`
+
+	// ID identifies the triggering entity among all entities of its
+	// type, e.g. `SAP/stewardci-core#42` or the name of the upstream
+	// PipelineRun object.
+	ID string `json:"id"`
+
+	// URL is an HTTP(S) URL referring to the triggering entity, which
+	// can be used to link to it.
+	// +optional
+	URL string `json:"url,omitempty"`
+}
+
+// TestResultsSpec configures the extraction of test results.
+type TestResultsSpec struct {
+	// Path is a file pattern relative to the pipeline workspace matching
+	// the JUnit XML report files, e.g. "**/target/surefire-reports/*.xml".
+	Path string `json:"path"`
+}
+
+// ArtifactsSpec configures the upload of artifacts.
+type ArtifactsSpec struct {
+	// Paths are file patterns relative to the pipeline workspace matching
+	// the files to upload, e.g. "target/*.jar".
+	Paths []string `json:"paths"`
+
+	// Destination is the object storage the artifacts are uploaded to.
+	Destination ArtifactsDestination `json:"destination"`
+}
+
+// ArtifactsDestination is an S3-compatible object storage bucket.
+type ArtifactsDestination struct {
+	// URL is the path-style URL of the bucket, optionally followed by a
+	// key prefix, e.g. "https://s3.eu-central-1.amazonaws.com/bucket1/builds".
+	URL string `json:"url"`
+
+	// Region is the region of the bucket used to sign requests.
+	// If not set, "us-east-1" is used.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Secret is the name of a secret in the namespace of the pipeline run
+	// with the entries "accessKeyId" and "secretAccessKey" used to
+	// authenticate to the object storage.
+	Secret string `json:"secret"`
+}
+
+// SealedSecret is a secret whose values are encrypted with the public key
+// of the Steward installation. Only the run controller is able to decrypt
+// the values, which it does when creating the secret in the run namespace.
+type SealedSecret struct {
+	// Name is the name of the secret to be created in the run namespace.
+	Name string `json:"name"`
+
+	// Type is the type of the secret to be created in the run namespace.
+	// If not set, type `Opaque` is used.
+	// +optional
+	Type corev1.SecretType `json:"type,omitempty"`
+
+	// SealedValue maps the data keys of the secret to the respective
+	// encrypted values. Each value must have been encrypted for the
+	// namespace of the PipelineRun object, so that it cannot be
+	// replayed in other namespaces.
+	SealedValue map[string]string `json:"sealedValue"`
 }
 
 // JenkinsfileRunnerSpec carries configuration options for the Jenkinsfile Runner container.
@@ -85,6 +236,21 @@ type JenkinsfileRunnerSpec struct {
 
 	// ImagePullPolicy is the pull policy for the image
 	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
+
+	// Resources are additional compute resources for the Jenkinsfile
+	// Runner container.
+	// +optional
+	Resources *JenkinsfileRunnerResources `json:"resources,omitempty"`
+}
+
+// JenkinsfileRunnerResources are additional compute resources for the
+// Jenkinsfile Runner container.
+type JenkinsfileRunnerResources struct {
+	// Limits are the amounts of extended resources (e.g. `nvidia.com/gpu`)
+	// required by the Jenkinsfile Runner container. For extended resources
+	// the requests are always equal to the limits.
+	// +optional
+	Limits corev1.ResourceList `json:"limits,omitempty"`
 }
 
 // JenkinsFile represents the location from where to get the pipeline
@@ -105,6 +271,37 @@ type JenkinsFile struct {
 	// RepoAuthSecret is the name of the Kubernetes `v1/Secret` resource object
 	// of type `kubernetes.io/basic-auth` that contains the username and
 	// password for authentication when cloning from `spec.jenkinsFile.repoUrl`.
+	// Alternatively it can be a secret with key `token` and optionally
+	// `username` (default `x-access-token`), e.g. containing a personal
+	// access token or a GitHub App installation token.
+	// For SSH URLs, i.e. with scheme `ssh` or scp-like, it must be a secret
+	// of type `kubernetes.io/ssh-auth` containing the private key and
+	// optionally the known hosts.
+	// +optional
+	RepoAuthSecret string `json:"repoAuthSecret,omitempty"`
+}
+
+// SourceRepository represents an additional Git repository to be made
+// available to the pipeline.
+type SourceRepository struct {
+
+	// URL is the URL of the Git repository.
+	URL string `json:"repoUrl"`
+
+	// Revision is the revision of the Git repository to be used, e.g.
+	// `master`.
+	Revision string `json:"revision"`
+
+	// TargetPath is the relative pathname of the directory in the workspace
+	// of the pipeline where the repository gets checked out to.
+	TargetPath string `json:"targetPath"`
+
+	// RepoAuthSecret is the name of the Kubernetes `v1/Secret` resource object
+	// of type `kubernetes.io/basic-auth` that contains the username and
+	// password for authentication when cloning from `repoUrl`.
+	// Alternatively it can be a secret with key `token` and optionally
+	// `username` (default `x-access-token`), e.g. containing a personal
+	// access token or a GitHub App installation token.
 	// +optional
 	RepoAuthSecret string `json:"repoAuthSecret,omitempty"`
 }
@@ -112,14 +309,75 @@ type JenkinsFile struct {
 // Logging contains all logging-specific configuration.
 type Logging struct {
 
+	// Sink is the configuration of the log sink pipeline logs are sent
+	// to. It must not be combined with `elasticsearch`.
+	// If neither is specified, the default Jenkins log implementation is
+	// used (stdout of Jenkinsfile Runner container).
+	// +optional
+	Sink *LogSink `json:"sink,omitempty"`
+
 	// Elasticsearch is the configuration for pipeline logging to Elasticsearch.
 	// If not specified, logging to Elasticsearch is disabled and the default
 	// Jenkins log implementation is used (stdout of Jenkinsfile Runner
 	// container).
+	//
+	// Deprecated: Use `sink` instead. The controller converts this
+	// configuration to a sink of type `elasticsearch`. The field will be
+	// removed in the next API version.
 	// +optional
 	Elasticsearch *Elasticsearch `json:"elasticsearch"`
 }
 
+// LogSinkType is the type of a log sink.
+type LogSinkType string
+
+const (
+	// LogSinkTypeElasticsearch is the type of log sinks sending pipeline
+	// logs to an Elasticsearch index.
+	LogSinkTypeElasticsearch LogSinkType = "elasticsearch"
+)
+
+// LogSink is the typed configuration of the log sink of a pipeline run.
+type LogSink struct {
+	// Type is the type of the log sink. The only supported type is
+	// `elasticsearch`.
+	Type LogSinkType `json:"type"`
+
+	// EndpointRef references the endpoint and credentials of the log
+	// sink. If not set, the endpoint configured for the client or the
+	// Steward installation is used.
+	// +optional
+	EndpointRef *LogSinkEndpointRef `json:"endpointRef,omitempty"`
+
+	// Index is the name of the Elasticsearch index to write logs to.
+	// It replaces the index of the index URL of the endpoint.
+	// If not set, the index of the endpoint is used.
+	// +optional
+	Index string `json:"index,omitempty"`
+
+	// RunID is the identifier of this pipeline run attached to each
+	// log entry as field `runid`.
+	// If `fields` is set, `runid` is an object containing the fields,
+	// and RunID is added as field `id`.
+	// +optional
+	RunID string `json:"runID,omitempty"`
+
+	// Fields are additional identifying fields of this pipeline run
+	// attached to each log entry as members of field `runid`.
+	// Field `id` is reserved if `runID` is set.
+	// +optional
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// LogSinkEndpointRef references the endpoint configuration of a log sink.
+type LogSinkEndpointRef struct {
+	// SecretName is the name of the Kubernetes `v1/Secret` resource object
+	// in the namespace of the pipeline run that configures the endpoint and
+	// credentials of the log sink. It has the same format as the log
+	// forwarding secret of client namespaces and overrides it.
+	SecretName string `json:"secretName"`
+}
+
 // Elasticsearch contains logging configuration for the
 // Elasticsearch log implementation.
 type Elasticsearch struct {
@@ -154,6 +412,14 @@ type PipelineStatus struct {
 	// +optional
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
 
+	// EstimatedDuration is the expected duration of the pipeline run
+	// from its start to its end, based on the statistics of former
+	// pipeline runs of the same pipeline. It is set when the pipeline run
+	// is started if run statistics are enabled in the Steward
+	// installation and former pipeline runs have been recorded.
+	// +optional
+	EstimatedDuration *metav1.Duration `json:"estimatedDuration,omitempty"`
+
 	State              State                 `json:"state"`
 	StateDetails       StateItem             `json:"stateDetails"`
 	StateHistory       []StateItem           `json:"stateHistory"`
@@ -164,6 +430,288 @@ type PipelineStatus struct {
 	History            []string              `json:"history"`
 	Namespace          string                `json:"namespace"`
 	AuxiliaryNamespace string                `json:"auxiliaryNamespace"`
+
+	// CreatedBy is the name of the user who created the pipeline run as
+	// recorded by the Steward admission webhook.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// TriggeredBy identifies the entity that triggered the pipeline run
+	// as specified in `spec.triggeredBy`.
+	// +optional
+	TriggeredBy *TriggeredBy `json:"triggeredBy,omitempty"`
+
+	// Warnings is a list of non-fatal issues detected by the controller,
+	// e.g. the usage of deprecated features.
+	// +optional
+	Warnings []Warning `json:"warnings,omitempty"`
+
+	// ResolvedRevision is the commit SHA that `spec.jenkinsFile.revision`
+	// pointed to when the pipeline run was started. It is only set if
+	// revision resolution is enabled in the Steward installation.
+	// +optional
+	ResolvedRevision string `json:"resolvedRevision,omitempty"`
+
+	// Progress is the stage-level progress of the pipeline as reported
+	// by the Jenkinsfile Runner while the pipeline run is running.
+	// +optional
+	Progress *Progress `json:"progress,omitempty"`
+
+	// Stages is a summary of the executed pipeline stages as reported by
+	// the Jenkinsfile Runner. It is set when the pipeline run completes.
+	// +optional
+	Stages []StageSummary `json:"stages,omitempty"`
+
+	// TestResults are the totals of the test results extracted from the
+	// JUnit XML reports of the pipeline. It is set when the pipeline run
+	// completes if test result extraction is configured in the spec.
+	// +optional
+	TestResults *TestResults `json:"testResults,omitempty"`
+
+	// Artifacts are the artifacts uploaded to the object storage. It is
+	// set when the pipeline run completes if artifact upload is
+	// configured in the spec.
+	// +optional
+	Artifacts *ArtifactsStatus `json:"artifacts,omitempty"`
+
+	// AbortHandlers are the outcomes of the abort handlers invoked when
+	// the pipeline run was cleaned up. It is only set if abort handlers
+	// are configured in the Steward installation.
+	// +optional
+	AbortHandlers []AbortHandlerStatus `json:"abortHandlers,omitempty"`
+
+	// TimelineExported indicates whether the run event timeline of the
+	// finished pipeline run has been exported to Elasticsearch. It is only
+	// set if timeline export is enabled in the Steward installation.
+	// +optional
+	TimelineExported bool `json:"timelineExported,omitempty"`
+
+	// LogURL is the location of the log of the Jenkinsfile Runner of the
+	// finished pipeline run in the log archive of the Steward
+	// installation. It is only set if log archiving is enabled in the
+	// Steward installation and the log has been archived successfully.
+	// +optional
+	LogURL string `json:"logUrl,omitempty"`
+
+	// Conditions are the latest observations of the controller regarding
+	// the pipeline run, e.g. why it has not been started yet.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// OffloadedStatus refers to the config map the bulky fields of the
+	// status have been moved to when the pipeline run finished. It is
+	// only set if status offloading is enabled in the Steward
+	// installation and the status exceeded the configured size.
+	// +optional
+	OffloadedStatus *OffloadedStatus `json:"offloadedStatus,omitempty"`
+}
+
+// OffloadedStatus refers to the config map containing status fields
+// moved out of the status of a finished pipeline run to keep the
+// pipeline run object small.
+type OffloadedStatus struct {
+	// ConfigMapName is the name of the config map in the namespace of the
+	// pipeline run. It is owned by the pipeline run, i.e. it gets deleted
+	// together with the pipeline run.
+	ConfigMapName string `json:"configMapName"`
+
+	// Fields are the names of the status fields that have been moved to
+	// the config map, which are also the keys of the config map entries.
+	// Entry `message` contains the message as plain text, all other
+	// entries contain the JSON representation of the field.
+	Fields []string `json:"fields"`
+}
+
+const (
+	// OffloadedStatusFieldMessage is the name of offloaded status field
+	// `message`.
+	OffloadedStatusFieldMessage = "message"
+
+	// OffloadedStatusFieldHistory is the name of offloaded status field
+	// `history`.
+	OffloadedStatusFieldHistory = "history"
+
+	// OffloadedStatusFieldStages is the name of offloaded status field
+	// `stages`.
+	OffloadedStatusFieldStages = "stages"
+)
+
+// AbortHandlerStatus is the outcome of an abort handler invoked when
+// a pipeline run was cleaned up.
+type AbortHandlerStatus struct {
+	// Name is the name of the abort handler.
+	Name string `json:"name"`
+
+	// Result is the result of the abort handler invocation.
+	Result AbortHandlerResult `json:"result"`
+
+	// Message describes why the abort handler did not succeed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Duration is the execution duration of the abort handler.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// AbortHandlerResult is the result of an abort handler invocation.
+type AbortHandlerResult string
+
+const (
+	// AbortHandlerResultSucceeded - the abort handler completed successfully
+	AbortHandlerResultSucceeded AbortHandlerResult = "succeeded"
+	// AbortHandlerResultFailed - the abort handler returned an error
+	AbortHandlerResultFailed AbortHandlerResult = "failed"
+	// AbortHandlerResultTimeout - the abort handler did not complete within its timeout
+	AbortHandlerResultTimeout AbortHandlerResult = "timeout"
+)
+
+const (
+	// PipelineRunConditionPreflightChecksPassed is the type of the
+	// pipeline run condition that indicates whether the pre-flight checks
+	// configured in the Steward installation passed. A pipeline run is
+	// not started as long as the condition is false.
+	PipelineRunConditionPreflightChecksPassed = "PreflightChecksPassed"
+
+	// PreflightChecksPassedReason is the reason of condition
+	// PipelineRunConditionPreflightChecksPassed if all checks passed.
+	PreflightChecksPassedReason = "Passed"
+
+	// PreflightChecksErrorReason is the reason of condition
+	// PipelineRunConditionPreflightChecksPassed if a check could not be
+	// performed.
+	PreflightChecksErrorReason = "CheckError"
+
+	// PipelineRunConditionQueued is the type of the pipeline run
+	// condition that indicates whether the pipeline run waits to be
+	// started because a limit of concurrently executed pipeline runs is
+	// reached. The condition is only set for pipeline runs that have been
+	// queued.
+	PipelineRunConditionQueued = "Queued"
+
+	// QueuedTenantLimitReason is the reason of condition
+	// PipelineRunConditionQueued if the maximum number of concurrent
+	// pipeline runs in the namespace of the pipeline run is reached.
+	QueuedTenantLimitReason = "TenantLimitReached"
+
+	// QueuedGlobalLimitReason is the reason of condition
+	// PipelineRunConditionQueued if the maximum number of parallel
+	// pipeline runs of the Steward installation is reached.
+	QueuedGlobalLimitReason = "GlobalLimitReached"
+
+	// QueuedConcurrencyGroupReason is the reason of condition
+	// PipelineRunConditionQueued if another pipeline run of the same
+	// concurrency group is in progress.
+	QueuedConcurrencyGroupReason = "ConcurrencyGroupBusy"
+
+	// QueuedQuietHoursReason is the reason of condition
+	// PipelineRunConditionQueued if pipeline runs are not started because
+	// of the quiet hours of the Steward installation.
+	QueuedQuietHoursReason = "QuietHours"
+
+	// QueuedStartedReason is the reason of condition
+	// PipelineRunConditionQueued if a queued pipeline run has been
+	// started.
+	QueuedStartedReason = "Started"
+
+	// PipelineRunConditionScheduling is the type of the pipeline run
+	// condition that indicates whether the start of the pipeline run is
+	// delayed by the run controller. While the pipeline run is delayed,
+	// the condition is true with a reason telling why, i.e. one of the
+	// reasons of conditions PipelineRunConditionQueued and
+	// PipelineRunConditionPreflightChecksPassed or
+	// SchedulingMaintenanceModeReason. Once the pipeline run has been
+	// started, the condition is false with reason SchedulingStartedReason.
+	// The condition is only set for pipeline runs that have been delayed.
+	PipelineRunConditionScheduling = "Scheduling"
+
+	// SchedulingMaintenanceModeReason is the reason of condition
+	// PipelineRunConditionScheduling if pipeline runs are not started
+	// because the Steward installation is in maintenance mode.
+	SchedulingMaintenanceModeReason = "MaintenanceMode"
+
+	// SchedulingStartedReason is the reason of condition
+	// PipelineRunConditionScheduling if a delayed pipeline run has been
+	// started.
+	SchedulingStartedReason = "Started"
+)
+
+// TestResults are the totals of the test cases of a pipeline run.
+type TestResults struct {
+	// Tests is the total number of test cases.
+	Tests int `json:"tests"`
+
+	// Failures is the number of failed test cases.
+	Failures int `json:"failures"`
+
+	// Errors is the number of test cases which terminated with an error.
+	Errors int `json:"errors"`
+
+	// Skipped is the number of skipped test cases.
+	Skipped int `json:"skipped"`
+}
+
+// ArtifactsStatus are the artifacts uploaded by a pipeline run.
+type ArtifactsStatus struct {
+	// Uploaded is the number of uploaded files.
+	Uploaded int `json:"uploaded"`
+
+	// Failed is the number of files which could not be uploaded.
+	Failed int `json:"failed"`
+
+	// URLs are the URLs of the uploaded files. For a large number of
+	// files only the first ones are listed.
+	// +optional
+	URLs []string `json:"urls,omitempty"`
+}
+
+// StageSummary is the summary of an executed pipeline stage.
+type StageSummary struct {
+	// Name is the name of the stage.
+	Name string `json:"name"`
+
+	// Result is the result of the stage as reported by the pipeline,
+	// e.g. "SUCCESS", "UNSTABLE", "FAILURE", "NOT_BUILT" or "ABORTED".
+	// +optional
+	Result string `json:"result,omitempty"`
+
+	// Duration is the execution duration of the stage.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+}
+
+// Progress is the stage-level progress of a running pipeline.
+type Progress struct {
+	// Stage is the name of the currently executed pipeline stage.
+	Stage string `json:"stage"`
+
+	// StageIndex is the 1-based index of the currently executed stage.
+	// Zero if unknown.
+	// +optional
+	StageIndex int `json:"stageIndex,omitempty"`
+
+	// StageCount is the total number of stages of the pipeline.
+	// Zero if unknown.
+	// +optional
+	StageCount int `json:"stageCount,omitempty"`
+
+	// Summary is a human-readable summary of the progress, e.g.
+	// "stage 3/7: integration tests".
+	Summary string `json:"summary"`
+
+	// UpdatedAt is the time the progress has been reported.
+	UpdatedAt metav1.Time `json:"updatedAt"`
+}
+
+// Warning is a non-fatal issue of a pipeline run that users should be
+// made aware of. Warnings do not affect the result of the pipeline run.
+type Warning struct {
+	// Reason is a unique, one-word, camel-case reason for the warning.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of the issue and how to
+	// resolve it.
+	Message string `json:"message"`
 }
 
 // StateItem holds start and end time of a state in the history
@@ -181,6 +729,10 @@ const (
 	StateUndefined State = ""
 	// StateNew - pipeline run is first checked by the controller
 	StateNew State = "new"
+	// StateQueued - the pipeline run waits until the number of concurrent
+	// pipeline runs in its namespace and in the Steward installation falls
+	// below the respective limit
+	StateQueued State = "queued"
 	// StatePreparing - the namespace for the execution is prepared
 	StatePreparing State = "preparing"
 	// StateWaiting - the pipeline run is waiting to be processed
@@ -209,6 +761,9 @@ const (
 	ResultErrorConfig Result = "error_config"
 	// ResultAborted - the pipeline run has been aborted
 	ResultAborted Result = "aborted"
+	// ResultAbortedSuperseded - the pipeline run has been aborted because it
+	// has been superseded by a newer pipeline run of its concurrency group
+	ResultAbortedSuperseded Result = "aborted_superseded"
 	// ResultTimeout - the pipeline run timed out
 	ResultTimeout Result = "timeout"
 	// ResultDeleted - the pipeline run was deleted
@@ -256,4 +811,37 @@ type Profiles struct {
 	// are allowed. The scope of the network profile might be extended in the future.
 	// If empty, a default profile will be used.
 	Network string `json:"network,omitempty"`
+
+	// SecurityContext selects the security context profile, which defines
+	// the pod security context of the Jenkinsfile Runner pod.
+	// If empty, a default profile will be used.
+	SecurityContext string `json:"securityContext,omitempty"`
+
+	// Execution selects the execution profile. It determines how the
+	// Jenkinsfile Runner pod is isolated from the node.
+	// If empty, the pod runs without additional isolation.
+	Execution ExecutionProfile `json:"execution,omitempty"`
+
+	// Scheduling selects the scheduling profile, which defines the
+	// topology spread constraints of the Jenkinsfile Runner pod.
+	// If empty, the constraints configured for the Steward installation
+	// will be used.
+	Scheduling string `json:"scheduling,omitempty"`
 }
+
+// ExecutionProfile is the name of an execution profile.
+type ExecutionProfile string
+
+const (
+	// ExecutionProfileDefault runs the Jenkinsfile Runner pod without
+	// additional isolation.
+	ExecutionProfileDefault ExecutionProfile = ""
+
+	// ExecutionProfileUserNamespace runs the Jenkinsfile Runner pod in a
+	// user namespace (`hostUsers: false`), i.e. user and group IDs of the
+	// pod are mapped to unprivileged IDs on the node. Root in the
+	// container is not root on the node.
+	// Must be enabled in the Steward installation and requires a
+	// Kubernetes cluster supporting user namespaces.
+	ExecutionProfileUserNamespace ExecutionProfile = "userNamespace"
+)
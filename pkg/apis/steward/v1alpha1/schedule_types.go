@@ -0,0 +1,131 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineSchedule is a Kubernetes custom resource type representing
+// pipeline runs to be created periodically according to a cron schedule.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PipelineScheduleSpec `json:"spec"`
+
+	// +optional
+	Status PipelineScheduleStatus `json:"status"`
+}
+
+// PipelineScheduleList is a list of PipelineSchedule objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PipelineSchedule `json:"items"`
+}
+
+// PipelineScheduleSpec is the spec of a PipelineSchedule.
+type PipelineScheduleSpec struct {
+	// Schedule is a cron expression with five fields (minute, hour, day
+	// of month, month, day of week) defining when pipeline runs are
+	// created, e.g. `0 2 * * *` for 2am every day.
+	Schedule string `json:"schedule"`
+
+	// TimeZone is the name of the time zone in the IANA time zone
+	// database the schedule is evaluated in, e.g. `Europe/Berlin`.
+	// If empty, UTC is used.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// ConcurrencyPolicy specifies how to treat a scheduled pipeline run
+	// while pipeline runs created by this schedule before are still
+	// active. Defaults to `Allow`.
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Suspend prevents the creation of further pipeline runs if set to
+	// true. Active pipeline runs are not affected.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// StartingDeadlineSeconds is the deadline in seconds for creating a
+	// pipeline run after its scheduled time. Scheduled times missed by
+	// more than this (e.g. because the controller was down or the
+	// schedule was suspended) are skipped. If not set, missed scheduled
+	// times are never skipped, but at most one pipeline run is created
+	// for all of them.
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// Template is the template of the pipeline runs to create.
+	Template PipelineRunTemplateSpec `json:"template"`
+}
+
+// ConcurrencyPolicy specifies how to treat a scheduled pipeline run
+// while former pipeline runs of the same schedule are still active.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow creates the pipeline run regardless of
+	// active pipeline runs.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "Allow"
+
+	// ConcurrencyPolicyForbid skips the pipeline run if there are active
+	// pipeline runs.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
+
+	// ConcurrencyPolicyReplace aborts active pipeline runs and creates the
+	// new pipeline run.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+)
+
+// PipelineRunTemplateSpec is the template of pipeline runs created by a
+// PipelineSchedule.
+type PipelineRunTemplateSpec struct {
+	// Metadata contains the labels and annotations of the pipeline runs.
+	// +optional
+	Metadata PipelineRunTemplateMetadata `json:"metadata,omitempty"`
+
+	// Spec is the spec of the pipeline runs.
+	Spec PipelineSpec `json:"spec"`
+}
+
+// PipelineRunTemplateMetadata contains the metadata of pipeline runs
+// created from a PipelineRunTemplateSpec.
+type PipelineRunTemplateMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// PipelineScheduleStatus is the status of a PipelineSchedule.
+type PipelineScheduleStatus struct {
+	// LastScheduleTime is the scheduled time of the last pipeline run
+	// handled by the schedule, whether it has been created or skipped.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// LastPipelineRunName is the name of the last pipeline run created by
+	// the schedule.
+	// +optional
+	LastPipelineRunName string `json:"lastPipelineRunName,omitempty"`
+
+	// Active contains the names of the pipeline runs created by the
+	// schedule that have not finished yet.
+	// +optional
+	Active []string `json:"active,omitempty"`
+
+	// Message is a human-readable message describing the last action of
+	// the schedule or why the schedule is not working.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
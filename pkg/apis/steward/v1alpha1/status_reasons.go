@@ -8,4 +8,40 @@ const (
 	// StatusReasonDependentResourceState indicates that the reason for the
 	// status is the state of another resource controlled by this resource.
 	StatusReasonDependentResourceState = "InvalidDependentResource"
+
+	// StatusReasonTenantNamespaceOnDemand indicates that the tenant is
+	// ready, but the tenant namespace will be created on demand only.
+	StatusReasonTenantNamespaceOnDemand = "TenantNamespaceOnDemand"
+
+	// StatusReasonTenantHibernated indicates that the tenant is not ready
+	// because the tenant namespace has been deleted due to inactivity and
+	// will be recreated on demand only.
+	StatusReasonTenantHibernated = "TenantHibernated"
+
+	// StatusReasonTenantRelocating indicates that the tenant is being
+	// moved to a new tenant namespace.
+	StatusReasonTenantRelocating = "TenantRelocating"
+
+	// StatusReasonTenantRelocated indicates that the tenant has been moved
+	// to a new tenant namespace.
+	StatusReasonTenantRelocated = "TenantRelocated"
+
+	// StatusReasonTenantExpiring indicates that the expiration date of the
+	// tenant is approaching.
+	StatusReasonTenantExpiring = "TenantExpiring"
+
+	// StatusReasonTenantExpired indicates that the expiration date of the
+	// tenant has passed.
+	StatusReasonTenantExpired = "TenantExpired"
+)
+
+const (
+	// WarningReasonIneffectiveField indicates that a field is set in the
+	// spec which currently has no effect.
+	WarningReasonIneffectiveField = "IneffectiveField"
+
+	// WarningReasonDeprecatedImageRegistry indicates that an image is
+	// pulled from a registry that is deprecated for this Steward
+	// installation.
+	WarningReasonDeprecatedImageRegistry = "DeprecatedImageRegistry"
 )
@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineRunTemplate is a Kubernetes custom resource type representing a
+// reusable pipeline run spec, which pipeline runs in the same namespace
+// can refer to via `spec.templateRef`.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRunTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec contains the defaults of the spec of pipeline runs referring
+	// to the template. Field `templateRef` is ignored.
+	Spec PipelineSpec `json:"spec"`
+}
+
+// PipelineRunTemplateList is a list of PipelineRunTemplate objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRunTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []PipelineRunTemplate `json:"items"`
+}
+
+// PipelineRunTemplateRef refers to a PipelineRunTemplate.
+type PipelineRunTemplateRef struct {
+	// Name is the name of the PipelineRunTemplate in the namespace of
+	// the pipeline run.
+	Name string `json:"name"`
+}
@@ -14,9 +14,30 @@ type Tenant struct {
 	// +optional
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +optional
+	Spec TenantSpec `json:"spec,omitempty"`
+	// +optional
 	Status TenantStatus `json:"status"`
 }
 
+// TenantSpec is the spec of a Tenant
+type TenantSpec struct {
+	// MaxConcurrentRuns is the maximum number of pipeline runs of this
+	// tenant that are executed concurrently. Further pipeline runs are
+	// queued until running ones have finished.
+	// Annotation `steward.sap.com/max-concurrent-runs` of the tenant
+	// namespace takes precedence. If zero, the default of the Steward
+	// installation applies.
+	// +optional
+	MaxConcurrentRuns int32 `json:"maxConcurrentRuns,omitempty"`
+
+	// ExpirationDate is the point in time after which the tenant expires.
+	// An expired tenant is hibernated or deleted according to the
+	// expiration policy of the client namespace. If not set, the tenant
+	// never expires.
+	// +optional
+	ExpirationDate *metav1.Time `json:"expirationDate,omitempty"`
+}
+
 // TenantList is a list of Tenants
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type TenantList struct {
@@ -31,8 +52,111 @@ type TenantStatus struct {
 	knativeduck.Status `json:",inline"`
 
 	TenantNamespaceName string `json:"tenantNamespaceName,omitempty"`
+
+	// PlannedTenantNamespaceName is the name of the tenant namespace
+	// determined by the tenant controller before the namespace gets
+	// created. It is set when the tenant is reconciled for the first time
+	// and only changes if the tenant gets relocated to a new tenant
+	// namespace.
+	// +optional
+	PlannedTenantNamespaceName string `json:"plannedTenantNamespaceName,omitempty"`
+
+	// FormerTenantNamespaceName is the name of the tenant namespace the
+	// tenant has been relocated from (see annotation
+	// `steward.sap.com/relocate-tenant-namespace`). It is set as long as
+	// the former tenant namespace waits for its deletion.
+	// +optional
+	FormerTenantNamespaceName string `json:"formerTenantNamespaceName,omitempty"`
+
+	// CreatedBy is the name of the user who created the tenant as
+	// recorded by the Steward admission webhook.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	// ConditionReasonCodes contains the message codes of conditions
+	// whose message has been taken from the message catalog. There is at
+	// most one entry per condition type.
+	// +optional
+	ConditionReasonCodes []ConditionReasonCode `json:"conditionReasonCodes,omitempty"`
+
+	// QueuedRuns is the number of pipeline runs of this tenant that are
+	// waiting to be started because the maximum number of concurrent
+	// pipeline runs (see spec.maxConcurrentRuns) or the maximum number of
+	// parallel pipeline runs of the Steward installation is reached.
+	// +optional
+	QueuedRuns int32 `json:"queuedRuns,omitempty"`
+
+	// LastRunActivity is the latest finish time of the pipeline runs of
+	// this tenant which have been deleted by the run controller after
+	// their time to live. It is considered for the hibernation of
+	// inactive tenants, as the deleted pipeline runs do not reflect the
+	// activity of the tenant anymore.
+	// +optional
+	LastRunActivity *metav1.Time `json:"lastRunActivity,omitempty"`
+
+	// RunRollup is a summary of the pipeline runs of this tenant.
+	// It is only maintained if enabled in the tenant controller.
+	// +optional
+	RunRollup *TenantRunRollup `json:"runRollup,omitempty"`
+}
+
+// TenantRunRollup is a summary of the pipeline runs of a tenant.
+type TenantRunRollup struct {
+	// LastRunTime is the creation time of the latest pipeline run of the
+	// tenant. It is retained if the pipeline run gets deleted.
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// RunningRuns is the number of pipeline runs that have been started
+	// but are not finished yet.
+	RunningRuns int32 `json:"runningRuns"`
+
+	// Window is the length of the sliding time window the finished
+	// pipeline runs are counted for.
+	Window metav1.Duration `json:"window"`
+
+	// FinishedRuns is the number of pipeline runs finished within the
+	// window.
+	FinishedRuns int32 `json:"finishedRuns"`
+
+	// SuccessfulRuns is the number of pipeline runs finished within the
+	// window with result `success`.
+	SuccessfulRuns int32 `json:"successfulRuns"`
+
+	// SuccessRatePercent is the percentage of successful pipeline runs
+	// among the pipeline runs finished within the window, rounded down.
+	// It is not set if no pipeline run has finished within the window.
+	// +optional
+	SuccessRatePercent *int32 `json:"successRatePercent,omitempty"`
 }
 
+// ConditionReasonCode identifies the message of a condition.
+type ConditionReasonCode struct {
+	// Type is the type of the condition.
+	Type knativeapis.ConditionType `json:"type"`
+
+	// ReasonCode is the message code of the condition's message.
+	ReasonCode MessageCode `json:"reasonCode"`
+
+	// MessageArgs are the arguments used to format the message.
+	// +optional
+	MessageArgs map[string]string `json:"messageArgs,omitempty"`
+}
+
+// TenantConditionHibernated is the type of the tenant condition that
+// indicates whether the tenant namespace has been deleted due to
+// inactivity.
+const TenantConditionHibernated knativeapis.ConditionType = "Hibernated"
+
+// TenantConditionRelocating is the type of the tenant condition that
+// indicates whether the tenant is being moved to a new tenant namespace.
+const TenantConditionRelocating knativeapis.ConditionType = "Relocating"
+
+// TenantConditionExpiring is the type of the tenant condition that
+// indicates whether the expiration date of the tenant is approaching or
+// has passed.
+const TenantConditionExpiring knativeapis.ConditionType = "Expiring"
+
 var tenantConditionSet = knativeapis.NewLivingConditionSet()
 
 // GetCondition returns the condition matching the given condition type.
@@ -41,8 +165,48 @@ func (s *TenantStatus) GetCondition(condType knativeapis.ConditionType) *knative
 }
 
 // SetCondition sets the given condition.
+// A reason code previously recorded for the condition type gets removed.
 func (s *TenantStatus) SetCondition(cond *knativeapis.Condition) {
 	if cond != nil {
 		tenantConditionSet.Manage(s).SetCondition(*cond)
+		s.removeConditionReasonCode(cond.Type)
+	}
+}
+
+// SetConditionWithReasonCode sets the given condition with the message
+// for the given message code and records the reason code.
+// The message of the given condition gets overwritten.
+func (s *TenantStatus) SetConditionWithReasonCode(cond *knativeapis.Condition, code MessageCode, args map[string]string) {
+	if cond == nil {
+		return
+	}
+	c := *cond
+	c.Message = code.Format(args)
+	s.SetCondition(&c)
+	s.ConditionReasonCodes = append(s.ConditionReasonCodes, ConditionReasonCode{
+		Type:        c.Type,
+		ReasonCode:  code,
+		MessageArgs: args,
+	})
+}
+
+// GetConditionReasonCode returns the reason code recorded for the given
+// condition type or nil if there is none.
+func (s *TenantStatus) GetConditionReasonCode(condType knativeapis.ConditionType) *ConditionReasonCode {
+	for i := range s.ConditionReasonCodes {
+		if s.ConditionReasonCodes[i].Type == condType {
+			return &s.ConditionReasonCodes[i]
+		}
+	}
+	return nil
+}
+
+func (s *TenantStatus) removeConditionReasonCode(condType knativeapis.ConditionType) {
+	var codes []ConditionReasonCode
+	for _, code := range s.ConditionReasonCodes {
+		if code.Type != condType {
+			codes = append(codes, code)
+		}
 	}
+	s.ConditionReasonCodes = codes
 }
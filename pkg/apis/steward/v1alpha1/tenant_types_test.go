@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func Test_TenantStatus_SetConditionWithReasonCode(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &TenantStatus{}
+	args := map[string]string{"namespace": "ns1"}
+
+	// EXERCISE
+	examinee.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:    knativeapis.ConditionReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  StatusReasonDependentResourceState,
+		Message: "ignored",
+	}, MessageCodeTenantNamespaceMissing, args)
+
+	// VERIFY
+	cond := examinee.GetCondition(knativeapis.ConditionReady)
+	assert.Equal(t, MessageCodeTenantNamespaceMissing.Format(args), cond.Message)
+	assert.Equal(t, StatusReasonDependentResourceState, cond.Reason)
+	assert.DeepEqual(t, []ConditionReasonCode{
+		{
+			Type:        knativeapis.ConditionReady,
+			ReasonCode:  MessageCodeTenantNamespaceMissing,
+			MessageArgs: args,
+		},
+	}, examinee.ConditionReasonCodes)
+}
+
+func Test_TenantStatus_SetConditionWithReasonCode_ReplacesExistingCode(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &TenantStatus{}
+	cond := &knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+	}
+	examinee.SetConditionWithReasonCode(cond, MessageCodeTenantNamespaceCreationFailed, nil)
+
+	// EXERCISE
+	examinee.SetConditionWithReasonCode(cond, MessageCodeTenantRoleBindingCreationFailed, nil)
+
+	// VERIFY
+	assert.Equal(t, 1, len(examinee.ConditionReasonCodes))
+	assert.Equal(t, MessageCodeTenantRoleBindingCreationFailed, examinee.GetConditionReasonCode(knativeapis.ConditionReady).ReasonCode)
+}
+
+func Test_TenantStatus_SetCondition_RemovesReasonCode(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &TenantStatus{}
+	examinee.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+	}, MessageCodeTenantNamespaceCreationFailed, nil)
+
+	// EXERCISE
+	examinee.SetCondition(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+
+	// VERIFY
+	assert.Assert(t, examinee.GetConditionReasonCode(knativeapis.ConditionReady) == nil)
+	assert.Equal(t, 0, len(examinee.ConditionReasonCodes))
+}
@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Trigger is a Kubernetes custom resource type representing the mapping
+// of Git webhook events to pipeline runs. For each matching event the
+// trigger server creates a pipeline run in the namespace of the trigger
+// referring to a PipelineRunTemplate.
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Trigger struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TriggerSpec `json:"spec"`
+}
+
+// TriggerSpec is the spec of a Trigger.
+type TriggerSpec struct {
+	// TemplateRef refers to the PipelineRunTemplate in the namespace of
+	// the trigger that created pipeline runs refer to.
+	TemplateRef PipelineRunTemplateRef `json:"templateRef"`
+
+	// SecretName is the name of the secret in the namespace of the
+	// trigger containing the webhook secret in key `secret`. Events are
+	// only accepted if they are signed (GitHub) or authenticated
+	// (GitLab) with the webhook secret.
+	SecretName string `json:"secretName"`
+
+	// Events is the list of event types triggering pipeline runs. Valid
+	// values are `push` and `pull_request`, which includes GitLab merge
+	// requests. If empty, all event types trigger pipeline runs.
+	// +optional
+	Events []TriggerEventType `json:"events,omitempty"`
+
+	// Repository is the name of the repository including its owner, e.g.
+	// `SAP/stewardci-core`. If set, only events of this repository
+	// trigger pipeline runs.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Branches is a list of patterns of branch names, e.g. `release/*`.
+	// If set, only events of matching branches trigger pipeline runs.
+	// For pull requests the target branch is matched. The pattern
+	// syntax is the one of Go function `path.Match`.
+	// +optional
+	Branches []string `json:"branches,omitempty"`
+
+	// Args are additional arguments of the created pipeline runs. The
+	// arguments derived from the event take precedence.
+	// +optional
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// TriggerEventType is the type of a Git webhook event.
+type TriggerEventType string
+
+const (
+	// TriggerEventPush is the type of events for pushed commits.
+	TriggerEventPush TriggerEventType = "push"
+
+	// TriggerEventPullRequest is the type of events for opened or
+	// updated pull requests (GitHub) and merge requests (GitLab).
+	TriggerEventPullRequest TriggerEventType = "pull_request"
+)
+
+// TriggerList is a list of Trigger objects.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Trigger `json:"items"`
+}
@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// labelValueHashLength is the number of hex digits of the hash suffix
+// of label values derived from strings that are not valid label values
+// themselves, e.g. trigger IDs.
+const labelValueHashLength = 16
+
+// TriggeredByIDLabelValue returns the value of label LabelTriggeredByID
+// for the given trigger ID (`spec.triggeredBy.id`).
+// An ID that is a valid label value is used as is. Otherwise the
+// characters not allowed in label values are replaced by `-`, the result
+// is shortened if necessary and a hash of the complete ID is appended,
+// e.g. `org1/repo1#42` becomes `org1-repo1-42-` followed by 16 hex digits.
+// Clients can use this function to select the pipeline runs of a trigger.
+// The empty ID yields the empty string.
+func TriggeredByIDLabelValue(id string) string {
+	return hashedLabelValue(id)
+}
+
+// TriggerLabelValue returns the value of label LabelTrigger for the
+// Trigger with the given name. Names which are not valid label values,
+// e.g. names longer than 63 characters, are converted like in
+// TriggeredByIDLabelValue. Other names are used as is.
+// Clients can use this function to select the pipeline runs created by
+// a Trigger.
+func TriggerLabelValue(name string) string {
+	return hashedLabelValue(name)
+}
+
+// hashedLabelValue returns the given value if it is a valid label value.
+// Otherwise it returns the sanitized and possibly shortened value with
+// a hash of the complete value appended.
+func hashedLabelValue(value string) string {
+	if value == "" || len(validation.IsValidLabelValue(value)) == 0 {
+		return value
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:])[:labelValueHashLength]
+
+	sanitized := strings.Map(func(r rune) rune {
+		if isLabelValueAlphanumeric(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '-'
+	}, value)
+	maxPrefixLength := validation.LabelValueMaxLength - len(hash) - 1
+	if len(sanitized) > maxPrefixLength {
+		sanitized = sanitized[:maxPrefixLength]
+	}
+	sanitized = strings.TrimFunc(sanitized, func(r rune) bool {
+		return !isLabelValueAlphanumeric(r)
+	})
+	if sanitized == "" {
+		return hash
+	}
+	return sanitized + "-" + hash
+}
+
+func isLabelValueAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
@@ -0,0 +1,77 @@
+package v1alpha1_test
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+func Test_TriggeredByIDLabelValue(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"valid", "upstream-run1", "upstream-run1"},
+		{"invalid characters", "org1/repo1#42", "org1-repo1-42-e21d654079bb193b"},
+		{"only invalid characters", "###", "56dc6d47737d155a"},
+		{"non-ASCII characters", "äöü/x", "x-279522ff66e06cd4"},
+		{"too long", strings.Repeat("a", 70), strings.Repeat("a", 46) + "-6bd5e5034855a112"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := v1alpha1.TriggeredByIDLabelValue(tc.id)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+			assert.Assert(t, len(validation.IsValidLabelValue(result)) == 0, result)
+		})
+	}
+}
+
+func Test_TriggeredByIDLabelValue_DistinguishesIDs(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	value1 := v1alpha1.TriggeredByIDLabelValue("org1/repo1#42")
+	value2 := v1alpha1.TriggeredByIDLabelValue("org1/repo1/42")
+
+	// VERIFY
+	assert.Assert(t, value1 != value2)
+}
+
+func Test_TriggerLabelValue(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		triggerName string
+		expected    string
+	}{
+		{"valid", "trigger1", "trigger1"},
+		{"dots", "github.push.main", "github.push.main"},
+		{"too long", strings.Repeat("a", 70), strings.Repeat("a", 46) + "-6bd5e5034855a112"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := v1alpha1.TriggerLabelValue(tc.triggerName)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+			assert.Assert(t, len(validation.IsValidLabelValue(result)) == 0, result)
+		})
+	}
+}
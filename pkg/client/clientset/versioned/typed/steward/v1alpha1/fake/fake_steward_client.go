@@ -38,10 +38,26 @@ func (c *FakeStewardV1alpha1) PipelineRuns(namespace string) v1alpha1.PipelineRu
 	return &FakePipelineRuns{c, namespace}
 }
 
+func (c *FakeStewardV1alpha1) PipelineRunTemplates(namespace string) v1alpha1.PipelineRunTemplateInterface {
+	return &FakePipelineRunTemplates{c, namespace}
+}
+
+func (c *FakeStewardV1alpha1) PipelineSchedules(namespace string) v1alpha1.PipelineScheduleInterface {
+	return &FakePipelineSchedules{c, namespace}
+}
+
+func (c *FakeStewardV1alpha1) RunReports(namespace string) v1alpha1.RunReportInterface {
+	return &FakeRunReports{c, namespace}
+}
+
 func (c *FakeStewardV1alpha1) Tenants(namespace string) v1alpha1.TenantInterface {
 	return &FakeTenants{c, namespace}
 }
 
+func (c *FakeStewardV1alpha1) Triggers(namespace string) v1alpha1.TriggerInterface {
+	return &FakeTriggers{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeStewardV1alpha1) RESTClient() rest.Interface {
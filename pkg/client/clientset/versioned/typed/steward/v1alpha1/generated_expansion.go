@@ -26,4 +26,12 @@ package v1alpha1
 
 type PipelineRunExpansion interface{}
 
+type PipelineRunTemplateExpansion interface{}
+
+type PipelineScheduleExpansion interface{}
+
+type RunReportExpansion interface{}
+
 type TenantExpansion interface{}
+
+type TriggerExpansion interface{}
@@ -33,7 +33,11 @@ import (
 type StewardV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	PipelineRunsGetter
+	PipelineRunTemplatesGetter
+	PipelineSchedulesGetter
+	RunReportsGetter
 	TenantsGetter
+	TriggersGetter
 }
 
 // StewardV1alpha1Client is used to interact with features provided by the steward.sap.com group.
@@ -45,10 +49,26 @@ func (c *StewardV1alpha1Client) PipelineRuns(namespace string) PipelineRunInterf
 	return newPipelineRuns(c, namespace)
 }
 
+func (c *StewardV1alpha1Client) PipelineRunTemplates(namespace string) PipelineRunTemplateInterface {
+	return newPipelineRunTemplates(c, namespace)
+}
+
+func (c *StewardV1alpha1Client) PipelineSchedules(namespace string) PipelineScheduleInterface {
+	return newPipelineSchedules(c, namespace)
+}
+
+func (c *StewardV1alpha1Client) RunReports(namespace string) RunReportInterface {
+	return newRunReports(c, namespace)
+}
+
 func (c *StewardV1alpha1Client) Tenants(namespace string) TenantInterface {
 	return newTenants(c, namespace)
 }
 
+func (c *StewardV1alpha1Client) Triggers(namespace string) TriggerInterface {
+	return newTriggers(c, namespace)
+}
+
 // NewForConfig creates a new StewardV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*StewardV1alpha1Client, error) {
 	config := *c
@@ -61,8 +61,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=steward.sap.com, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("pipelineruns"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().PipelineRuns().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("pipelineruntemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().PipelineRunTemplates().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("pipelineschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().PipelineSchedules().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("runreports"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().RunReports().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("tenants"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().Tenants().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("triggers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Steward().V1alpha1().Triggers().Informer()}, nil
 
 	}
 
@@ -32,8 +32,16 @@ import (
 type Interface interface {
 	// PipelineRuns returns a PipelineRunInformer.
 	PipelineRuns() PipelineRunInformer
+	// PipelineRunTemplates returns a PipelineRunTemplateInformer.
+	PipelineRunTemplates() PipelineRunTemplateInformer
+	// PipelineSchedules returns a PipelineScheduleInformer.
+	PipelineSchedules() PipelineScheduleInformer
+	// RunReports returns a RunReportInformer.
+	RunReports() RunReportInformer
 	// Tenants returns a TenantInformer.
 	Tenants() TenantInformer
+	// Triggers returns a TriggerInformer.
+	Triggers() TriggerInformer
 }
 
 type version struct {
@@ -52,7 +60,27 @@ func (v *version) PipelineRuns() PipelineRunInformer {
 	return &pipelineRunInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// PipelineRunTemplates returns a PipelineRunTemplateInformer.
+func (v *version) PipelineRunTemplates() PipelineRunTemplateInformer {
+	return &pipelineRunTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PipelineSchedules returns a PipelineScheduleInformer.
+func (v *version) PipelineSchedules() PipelineScheduleInformer {
+	return &pipelineScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// RunReports returns a RunReportInformer.
+func (v *version) RunReports() RunReportInformer {
+	return &runReportInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Tenants returns a TenantInformer.
 func (v *version) Tenants() TenantInformer {
 	return &tenantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// Triggers returns a TriggerInformer.
+func (v *version) Triggers() TriggerInformer {
+	return &triggerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -32,6 +32,30 @@ type PipelineRunListerExpansion interface{}
 // PipelineRunNamespaceLister.
 type PipelineRunNamespaceListerExpansion interface{}
 
+// PipelineRunTemplateListerExpansion allows custom methods to be added to
+// PipelineRunTemplateLister.
+type PipelineRunTemplateListerExpansion interface{}
+
+// PipelineRunTemplateNamespaceListerExpansion allows custom methods to be added to
+// PipelineRunTemplateNamespaceLister.
+type PipelineRunTemplateNamespaceListerExpansion interface{}
+
+// PipelineScheduleListerExpansion allows custom methods to be added to
+// PipelineScheduleLister.
+type PipelineScheduleListerExpansion interface{}
+
+// PipelineScheduleNamespaceListerExpansion allows custom methods to be added to
+// PipelineScheduleNamespaceLister.
+type PipelineScheduleNamespaceListerExpansion interface{}
+
+// RunReportListerExpansion allows custom methods to be added to
+// RunReportLister.
+type RunReportListerExpansion interface{}
+
+// RunReportNamespaceListerExpansion allows custom methods to be added to
+// RunReportNamespaceLister.
+type RunReportNamespaceListerExpansion interface{}
+
 // TenantListerExpansion allows custom methods to be added to
 // TenantLister.
 type TenantListerExpansion interface{}
@@ -39,3 +63,11 @@ type TenantListerExpansion interface{}
 // TenantNamespaceListerExpansion allows custom methods to be added to
 // TenantNamespaceLister.
 type TenantNamespaceListerExpansion interface{}
+
+// TriggerListerExpansion allows custom methods to be added to
+// TriggerLister.
+type TriggerListerExpansion interface{}
+
+// TriggerNamespaceListerExpansion allows custom methods to be added to
+// TriggerNamespaceLister.
+type TriggerNamespaceListerExpansion interface{}
@@ -0,0 +1,135 @@
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// searchLimit is how far Next searches for a matching time.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek field
+	dayOfMonthRestricted, dayOfWeekRestricted  bool
+}
+
+// field is the set of values matched by a field of a cron expression.
+type field map[int]bool
+
+// Parse parses a cron expression consisting of five fields: minute, hour,
+// day of month, month and day of week, e.g. `0 8 * * 1-5` for 8am on
+// weekdays.
+// The fields support `*`, single values, ranges (`a-b`), steps (`*/n`,
+// `a-b/n`, `a/n`) and lists separated by `,`. Day of week 0 and 7 are
+// Sunday. Like with cron, if both day of month and day of week are
+// restricted, a day matching either of them matches.
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, errors.Errorf("expected 5 cron fields, got %d fields", len(parts))
+	}
+	s := &Schedule{}
+	var err error
+	for i, f := range []struct {
+		name     string
+		dest     *field
+		min, max int
+	}{
+		{"minute", &s.minute, 0, 59},
+		{"hour", &s.hour, 0, 23},
+		{"day of month", &s.dayOfMonth, 1, 31},
+		{"month", &s.month, 1, 12},
+		{"day of week", &s.dayOfWeek, 0, 7},
+	} {
+		if *f.dest, err = parseField(parts[i], f.min, f.max); err != nil {
+			return nil, errors.WithMessagef(err, "%s", f.name)
+		}
+	}
+	if s.dayOfWeek[7] {
+		s.dayOfWeek[0] = true
+	}
+	s.dayOfMonthRestricted = len(s.dayOfMonth) < 31
+	s.dayOfWeekRestricted = !(s.dayOfWeek[0] && len(s.dayOfWeek) >= 7)
+	return s, nil
+}
+
+func parseField(spec string, min, max int) (field, error) {
+	result := field{}
+	for _, item := range strings.Split(spec, ",") {
+		rangeSpec, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangeSpec = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return nil, errors.Errorf("invalid step in %q", item)
+			}
+		}
+		first, last := min, max
+		if rangeSpec != "*" {
+			bounds := strings.SplitN(rangeSpec, "-", 2)
+			var err error
+			if first, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, errors.Errorf("invalid value in %q", item)
+			}
+			if len(bounds) == 1 && step == 1 {
+				last = first
+			}
+			if len(bounds) == 2 {
+				if last, err = strconv.Atoi(bounds[1]); err != nil {
+					return nil, errors.Errorf("invalid value in %q", item)
+				}
+			}
+		}
+		if first < min || last > max || first > last {
+			return nil, errors.Errorf("%q out of range %d-%d", item, min, max)
+		}
+		for value := first; value <= last; value += step {
+			result[value] = true
+		}
+	}
+	return result, nil
+}
+
+// Matches returns whether the minute of the given time matches the
+// schedule. The fields are evaluated in the location of the given time.
+func (s *Schedule) Matches(t time.Time) bool {
+	return s.minute[t.Minute()] && s.hour[t.Hour()] && s.matchesDay(t)
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	if !s.month[int(t.Month())] {
+		return false
+	}
+	dayOfMonth, dayOfWeek := s.dayOfMonth[t.Day()], s.dayOfWeek[int(t.Weekday())]
+	if s.dayOfMonthRestricted && s.dayOfWeekRestricted {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
+
+// Next returns the first time after the given time matching the
+// schedule, evaluated in the location of the given time. Returns the zero
+// time if there is no such time within the next five years, e.g. for
+// February 30.
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	limit := after.Add(searchLimit)
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
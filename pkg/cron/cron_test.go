@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_Parse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		expr          string
+		expectedError string
+	}{
+		{"0 8 * *", "expected 5 cron fields, got 4 fields"},
+		{"60 8 * * *", `minute: "60" out of range 0-59`},
+		{"0 8-6 * * *", `hour: "8-6" out of range 0-23`},
+		{"0 8 0 * *", `day of month: "0" out of range 1-31`},
+		{"0 8 * x *", `month: invalid value in "x"`},
+		{"0 8 * * */0", `day of week: invalid step in "*/0"`},
+	} {
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			schedule, err := Parse(tc.expr)
+
+			// VERIFY
+			assert.Assert(t, schedule == nil)
+			assert.Error(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_Schedule_Matches(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		time     time.Time
+		expected bool
+	}{
+		// 2022-03-07 is a Monday
+		{"match", "0 8 * * 1-5", time.Date(2022, 3, 7, 8, 0, 59, 0, time.UTC), true},
+		{"other_minute", "0 8 * * 1-5", time.Date(2022, 3, 7, 8, 1, 0, 0, time.UTC), false},
+		{"other_day_of_week", "0 8 * * 1-5", time.Date(2022, 3, 6, 8, 0, 0, 0, time.UTC), false},
+		{"sunday_as_7", "0 8 * * 7", time.Date(2022, 3, 6, 8, 0, 0, 0, time.UTC), true},
+		{"day_of_month_or_week", "0 0 1 * 1", time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), true},
+		{"day_of_month_and_unrestricted_week", "0 0 1 * *", time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC), false},
+		{"step", "*/15 * * * *", time.Date(2022, 3, 7, 8, 45, 0, 0, time.UTC), true},
+		{"value_with_step", "5/20 * * * *", time.Date(2022, 3, 7, 8, 45, 0, 0, time.UTC), true},
+		{"list", "0 8,12 * * *", time.Date(2022, 3, 7, 12, 0, 0, 0, time.UTC), true},
+		{"other_month", "0 0 * 1-2 *", time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC), false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule, err := Parse(tc.expr)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result := schedule.Matches(tc.time)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		after    time.Time
+		expected time.Time
+	}{
+		{"same_day", "0 8 * * *", time.Date(2022, 3, 7, 7, 30, 0, 0, time.UTC), time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)},
+		{"exactly_at_match", "0 8 * * *", time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC), time.Date(2022, 3, 8, 8, 0, 0, 0, time.UTC)},
+		{"next_weekday", "30 8 * * 1-5", time.Date(2022, 3, 11, 9, 0, 0, 0, time.UTC), time.Date(2022, 3, 14, 8, 30, 0, 0, time.UTC)},
+		{"every_minute", "* * * * *", time.Date(2022, 3, 7, 8, 0, 30, 0, time.UTC), time.Date(2022, 3, 7, 8, 1, 0, 0, time.UTC)},
+		{"next_year", "0 0 1 1 *", time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap_day", "0 0 29 2 *", time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC), time.Time{}},
+		{"location", "0 8 * * *", time.Date(2022, 3, 7, 7, 30, 0, 0, berlin), time.Date(2022, 3, 7, 8, 0, 0, 0, berlin)},
+		// 2022-03-27 02:00 does not exist in Europe/Berlin
+		{"dst_gap", "0 3 * * *", time.Date(2022, 3, 27, 1, 30, 0, 0, berlin), time.Date(2022, 3, 27, 3, 0, 0, 0, berlin)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule, err := Parse(tc.expr)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result := schedule.Next(tc.after)
+
+			// VERIFY
+			assert.Assert(t, tc.expected.Equal(result), "result: %v", result)
+		})
+	}
+}
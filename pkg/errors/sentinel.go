@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Sentinel errors denoting well-known failure causes. Errors returned by
+// the controllers are marked with them via Mark, so that callers can
+// check for a failure cause via `errors.Is()` independent of the error
+// message.
+var (
+	// ErrNamespaceMissing denotes that a namespace expected to exist
+	// does not exist (anymore).
+	ErrNamespaceMissing = errors.New("namespace missing")
+
+	// ErrRoleBindingOutdated denotes that a role binding is outdated and
+	// could not be updated.
+	ErrRoleBindingOutdated = errors.New("role binding outdated")
+
+	// ErrSecretMissing denotes that a secret expected to exist does not
+	// exist.
+	ErrSecretMissing = errors.New("secret missing")
+
+	// ErrQuotaExceeded denotes that an object could not be created
+	// because a resource quota would be exceeded.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+)
+
+type sentinelAnnotation struct {
+	wrapped  error
+	sentinel error
+}
+
+// let compiler verify interface compliance
+var _ error = (*sentinelAnnotation)(nil)
+
+func (a *sentinelAnnotation) Error() string {
+	return a.wrapped.Error()
+}
+
+func (a *sentinelAnnotation) Unwrap() error {
+	return a.wrapped
+}
+
+// Cause allows `github.com/pkg/errors.Cause()` to find the root cause
+// through the annotation.
+func (a *sentinelAnnotation) Cause() error {
+	return a.wrapped
+}
+
+func (a *sentinelAnnotation) Is(target error) bool {
+	return target == a.sentinel || errors.Is(a.wrapped, target)
+}
+
+// Mark annotates a given error with a sentinel error, so that
+// `errors.Is(result, sentinel)` returns true. The message of the
+// given error is retained.
+// If err is nil, the function returns nil.
+func Mark(err error, sentinel error) error {
+	if err == nil {
+		return nil
+	}
+	return &sentinelAnnotation{
+		wrapped:  err,
+		sentinel: sentinel,
+	}
+}
+
+// MarkIfQuotaExceeded marks the given error returned by the Kubernetes
+// API server with ErrQuotaExceeded if it denotes that a resource quota
+// would be exceeded. Otherwise err is returned unchanged.
+func MarkIfQuotaExceeded(err error) error {
+	if k8serrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota") {
+		return Mark(err, ErrQuotaExceeded)
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_Mark(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	err1 := fmt.Errorf("err1")
+
+	// EXERCISE
+	result := Mark(err1, ErrNamespaceMissing)
+
+	// VERIFY
+	assert.Equal(t, "err1", result.Error())
+	assert.Assert(t, errors.Is(result, ErrNamespaceMissing))
+	assert.Assert(t, errors.Is(result, err1))
+	assert.Assert(t, !errors.Is(result, ErrSecretMissing))
+	assert.Assert(t, errors.Unwrap(result) == err1)
+}
+
+func Test_Mark_Nil(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := Mark(nil, ErrNamespaceMissing)
+
+	// VERIFY
+	assert.NilError(t, result)
+}
+
+func Test_Mark_Wrapped(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	err1 := fmt.Errorf("err1")
+
+	// EXERCISE
+	result := pkgerrors.WithMessage(Mark(pkgerrors.WithMessage(err1, "msg1"), ErrRoleBindingOutdated), "msg2")
+
+	// VERIFY
+	assert.Equal(t, "msg2: msg1: err1", result.Error())
+	assert.Assert(t, errors.Is(result, ErrRoleBindingOutdated))
+	assert.Assert(t, pkgerrors.Cause(result) == err1)
+}
+
+func Test_MarkIfQuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Resource: "pods"}
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"quota exceeded", k8serrors.NewForbidden(gr, "pod1", errors.New("exceeded quota: quota1, requested: pods=1, used: pods=1, limited: pods=1")), true},
+		{"other forbidden", k8serrors.NewForbidden(gr, "pod1", errors.New("not allowed")), false},
+		{"other", errors.New("exceeded quota"), false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := MarkIfQuotaExceeded(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, errors.Is(result, ErrQuotaExceeded))
+			if !tc.expected {
+				assert.Equal(t, tc.err, result)
+			}
+		})
+	}
+}
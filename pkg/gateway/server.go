@@ -0,0 +1,313 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward"
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// APIPath is the path prefix of the API endpoints.
+	APIPath = "/api/v1/namespaces/"
+
+	// jenkinsfileRunnerContainer is the name of the container of the
+	// run pod executing the Jenkinsfile Runner.
+	jenkinsfileRunnerContainer = "step-jenkinsfile-runner"
+
+	// maxBodyBytes is the maximum size of request bodies.
+	maxBodyBytes = 1 << 20
+)
+
+// Server is an HTTP handler providing access to pipeline runs for
+// clients without access to the Kubernetes API server.
+//
+// Endpoints (relative to APIPath):
+//
+//	POST <namespace>/pipelineruns                creates a pipeline run
+//	GET  <namespace>/pipelineruns                lists pipeline runs
+//	GET  <namespace>/pipelineruns/<name>         gets a pipeline run
+//	GET  <namespace>/pipelineruns/<name>?watch=1 streams changes of a pipeline run
+//	GET  <namespace>/pipelineruns/<name>/logs    streams the pipeline log
+//	POST <namespace>/pipelineruns/<name>/abort   aborts a pipeline run
+//
+// Requests must authenticate with a bearer token granting access to the
+// namespace.
+type Server struct {
+	factory k8s.ClientFactory
+	tokens  *Tokens
+}
+
+// NewServer creates a new Server accepting the given tokens.
+func NewServer(factory k8s.ClientFactory, tokens *Tokens) *Server {
+	return &Server{factory: factory, tokens: tokens}
+}
+
+// ServeHTTP handles an API request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := s.tokens.Authenticate(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+	if token == nil {
+		writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, APIPath), "/")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] != "pipelineruns" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	namespace := parts[0]
+	if !token.Allows(namespace) {
+		klog.V(3).Infof("denied access of client %q to namespace %q", token.Name, namespace)
+		writeError(w, http.StatusForbidden, "access to namespace denied")
+		return
+	}
+
+	switch {
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		s.create(w, r, namespace, token)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.list(w, r, namespace)
+	case len(parts) == 3 && r.Method == http.MethodGet && r.URL.Query().Get("watch") != "":
+		s.watch(w, r, namespace, parts[2])
+	case len(parts) == 3 && r.Method == http.MethodGet:
+		s.get(w, r, namespace, parts[2])
+	case len(parts) == 4 && parts[3] == "logs" && r.Method == http.MethodGet:
+		s.logs(w, r, namespace, parts[2])
+	case len(parts) == 4 && parts[3] == "abort" && r.Method == http.MethodPost:
+		s.abort(w, r, namespace, parts[2])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// create creates the pipeline run contained in the request body. Only
+// the name, generated name, labels, annotations and the spec are taken
+// over. Labels and annotations of Steward are dropped, as they are
+// reserved for Steward components. The name of the token is recorded in
+// annotation api.AnnotationGatewayClient.
+func (s *Server) create(w http.ResponseWriter, r *http.Request, namespace string, token *Token) {
+	requested := &api.PipelineRun{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(requested); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid pipeline run: "+err.Error())
+		return
+	}
+	run := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         requested.GetName(),
+			GenerateName: requested.GetGenerateName(),
+			Namespace:    namespace,
+			Labels:       withoutStewardKeys(requested.GetLabels()),
+			Annotations:  withoutStewardKeys(requested.GetAnnotations()),
+		},
+		Spec: requested.Spec,
+	}
+	if run.Annotations == nil {
+		run.Annotations = map[string]string{}
+	}
+	run.Annotations[api.AnnotationGatewayClient] = token.Name
+	slabels.LabelWithInstanceID(run, s.factory.InstanceID())
+	created, err := s.factory.StewardV1alpha1().PipelineRuns(namespace).Create(r.Context(), run, metav1.CreateOptions{})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// withoutStewardKeys returns a copy of the given labels or annotations
+// without the keys with prefix `steward.sap.com/`.
+func withoutStewardKeys(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		if !strings.HasPrefix(key, steward.GroupName+"/") {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, namespace string) {
+	list, err := s.factory.StewardV1alpha1().PipelineRuns(namespace).List(r.Context(), metav1.ListOptions{
+		LabelSelector: r.URL.Query().Get("labelSelector"),
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	run, err := s.factory.StewardV1alpha1().PipelineRuns(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// watch streams the pipeline run as one JSON object per line whenever it
+// changes, until it is deleted or the client disconnects.
+func (s *Server) watch(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	watcher, err := s.factory.StewardV1alpha1().PipelineRuns(namespace).Watch(r.Context(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	defer watcher.Stop()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			run, isRun := event.Object.(*api.PipelineRun)
+			if !isRun {
+				continue
+			}
+			if err := encoder.Encode(map[string]interface{}{"type": event.Type, "object": run}); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// logs streams the log of the Jenkinsfile Runner of the pipeline run.
+// With query parameter `follow`, the log is streamed until the pipeline
+// run finishes.
+func (s *Server) logs(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	ctx := r.Context()
+	run, err := s.factory.StewardV1alpha1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	podName, err := s.runPodName(ctx, run)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if podName == "" {
+		writeError(w, http.StatusConflict, "pipeline run has no running pod")
+		return
+	}
+	stream, err := s.factory.CoreV1().Pods(run.Status.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: jenkinsfileRunnerContainer,
+		Follow:    r.URL.Query().Get("follow") != "",
+	}).Stream(ctx)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(flushWriter{w}, stream); err != nil {
+		klog.V(3).Infof("streaming log of pipeline run %s/%s aborted: %s", namespace, name, err.Error())
+	}
+}
+
+// runPodName returns the name of the pod executing the Jenkinsfile Runner
+// of the given pipeline run or an empty string if there is none.
+func (s *Server) runPodName(ctx context.Context, run *api.PipelineRun) (string, error) {
+	if run.Status.Namespace == "" {
+		return "", nil
+	}
+	pods, err := s.factory.CoreV1().Pods(run.Status.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == jenkinsfileRunnerContainer {
+				return pod.GetName(), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// abort requests the abortion of the pipeline run.
+func (s *Server) abort(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	ctx := r.Context()
+	client := s.factory.StewardV1alpha1().PipelineRuns(namespace)
+	run, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if run.Spec.Intent != api.IntentAbort {
+		run = run.DeepCopy()
+		run.Spec.Intent = api.IntentAbort
+		if run, err = client.Update(ctx, run, metav1.UpdateOptions{}); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// flushWriter flushes after each write, so that streamed logs reach the
+// client immediately.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		klog.V(3).Infof("failed to write response: %s", err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
+
+// writeAPIError responds with the status code of the given error of the
+// Kubernetes API server. Other errors result in status code 500.
+func writeAPIError(w http.ResponseWriter, err error) {
+	if status, ok := err.(kerrors.APIStatus); ok && status.Status().Code != 0 {
+		writeError(w, int(status.Status().Code), status.Status().Message)
+		return
+	}
+	klog.Errorf("gateway request failed: %s", err.Error())
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestServer(t *testing.T, objects ...runtime.Object) (*Server, *fake.ClientFactory) {
+	t.Helper()
+	tokens, err := LoadTokens(strings.NewReader("tokens: [{name: client1, sha256: " + token1Hash + ", namespaces: [ns1]}]"))
+	assert.NilError(t, err)
+	cf := fake.NewClientFactory(objects...)
+	return NewServer(cf, tokens), cf
+}
+
+func newRequest(method, path string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer token1")
+	return req
+}
+
+func Test_Server_Create(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server, cf := newTestServer(t)
+	body := `{"metadata": {"name": "run1", "namespace": "other",
+			"labels": {"label1": "value1", "steward.sap.com/pending-tenant": "tenant1"},
+			"annotations": {"annotation1": "value1", "steward.sap.com/gateway-client": "fake", "steward.sap.com/created-by": "fake"}},
+		"spec": {"intent": "run", "jenkinsFile": {"repoUrl": "https://git.example.com/repo1.git"}},
+		"status": {"state": "finished"}}`
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, newRequest(http.MethodPost, "/api/v1/namespaces/ns1/pipelineruns", bytes.NewBufferString(body)))
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusCreated, rec.Body.String())
+	run, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(context.Background(), "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, run.GetLabels(), map[string]string{"label1": "value1"})
+	assert.DeepEqual(t, run.GetAnnotations(), map[string]string{
+		"annotation1":               "value1",
+		api.AnnotationGatewayClient: "client1",
+	})
+	assert.Equal(t, run.Spec.JenkinsFile.URL, "https://git.example.com/repo1.git")
+	assert.Equal(t, run.Status.State, api.StateUndefined)
+}
+
+func Test_Server_Get(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server, _ := newTestServer(t, fake.PipelineRun("run1", "ns1", api.PipelineSpec{}))
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, newRequest(http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns/run1", nil))
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusOK)
+	run := &api.PipelineRun{}
+	assert.NilError(t, json.Unmarshal(rec.Body.Bytes(), run))
+	assert.Equal(t, run.GetName(), "run1")
+}
+
+func Test_Server_Abort(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server, cf := newTestServer(t, fake.PipelineRun("run1", "ns1", api.PipelineSpec{}))
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, newRequest(http.MethodPost, "/api/v1/namespaces/ns1/pipelineruns/run1/abort", nil))
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusOK)
+	run, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(context.Background(), "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, run.Spec.Intent, api.IntentAbort)
+}
+
+func Test_Server_Logs(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "run-ns1"
+	pod := &corev1.Pod{
+		ObjectMeta: fake.ObjectMeta("pod1", "run-ns1"),
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: jenkinsfileRunnerContainer}}},
+	}
+	server, _ := newTestServer(t, run, pod)
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, newRequest(http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns/run1/logs", nil))
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Equal(t, rec.Body.String(), "fake logs")
+}
+
+func Test_Server_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+		expectedCode  int
+	}{
+		{"no_token", http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns", "", http.StatusUnauthorized},
+		{"invalid_token", http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns", "Bearer token2", http.StatusUnauthorized},
+		{"namespace_denied", http.MethodGet, "/api/v1/namespaces/ns2/pipelineruns", "Bearer token1", http.StatusForbidden},
+		{"unknown_resource", http.MethodGet, "/api/v1/namespaces/ns1/tenants", "Bearer token1", http.StatusNotFound},
+		{"unknown_run", http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns/unknown", "Bearer token1", http.StatusNotFound},
+		{"logs_without_pod", http.MethodGet, "/api/v1/namespaces/ns1/pipelineruns/run1/logs", "Bearer token1", http.StatusConflict},
+		{"wrong_method", http.MethodDelete, "/api/v1/namespaces/ns1/pipelineruns/run1", "Bearer token1", http.StatusNotFound},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			server, _ := newTestServer(t, fake.PipelineRun("run1", "ns1", api.PipelineSpec{}))
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", tc.authorization)
+			rec := httptest.NewRecorder()
+
+			// EXERCISE
+			server.ServeHTTP(rec, req)
+
+			// VERIFY
+			assert.Equal(t, rec.Code, tc.expectedCode)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	errors "github.com/pkg/errors"
+)
+
+// Token is an API token of a client of the gateway.
+type Token struct {
+	// Name identifies the client, e.g. in logs.
+	Name string `json:"name"`
+
+	// SHA256 is the hex-encoded SHA-256 hash of the token. The token
+	// itself is not stored.
+	SHA256 string `json:"sha256"`
+
+	// Namespaces are the tenant namespaces the client may access.
+	Namespaces []string `json:"namespaces"`
+}
+
+// Allows returns whether the token grants access to the given namespace.
+func (t *Token) Allows(namespace string) bool {
+	for _, entry := range t.Namespaces {
+		if entry == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Tokens is the set of valid API tokens.
+type Tokens struct {
+	tokens []tokenEntry
+}
+
+type tokenEntry struct {
+	token Token
+	hash  []byte
+}
+
+// LoadTokens reads API tokens in YAML or JSON format. The document must
+// contain a field `tokens` with a list of Token objects.
+func LoadTokens(r io.Reader) (*Tokens, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	document := struct {
+		Tokens []Token `json:"tokens"`
+	}{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, errors.Wrap(err, "invalid tokens")
+	}
+	result := &Tokens{}
+	for i, token := range document.Tokens {
+		if token.Name == "" {
+			return nil, fmt.Errorf("tokens[%d]: name must not be empty", i)
+		}
+		hash, err := hex.DecodeString(strings.ToLower(token.SHA256))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("tokens[%d]: sha256 must be a hex-encoded SHA-256 hash", i)
+		}
+		result.tokens = append(result.tokens, tokenEntry{token: token, hash: hash})
+	}
+	return result, nil
+}
+
+// Authenticate returns the token matching the given secret token value
+// or nil if there is none.
+func (t *Tokens) Authenticate(value string) *Token {
+	if value == "" {
+		return nil
+	}
+	hash := sha256.Sum256([]byte(value))
+	var result *Token
+	for i := range t.tokens {
+		// compare all entries to not leak the position of a match
+		if subtle.ConstantTimeCompare(hash[:], t.tokens[i].hash) == 1 {
+			result = &t.tokens[i].token
+		}
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	assert "gotest.tools/assert"
+)
+
+// sha256 of "token1"
+const token1Hash = "df3e6b0bb66ceaadca4f84cbc371fd66e04d20fe51fc414da8d1b84d31d178de"
+
+func Test_LoadTokens(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	document := `
+tokens:
+- name: client1
+  sha256: ` + strings.ToUpper(token1Hash) + `
+  namespaces: [ns1, ns2]
+`
+
+	// EXERCISE
+	tokens, err := LoadTokens(strings.NewReader(document))
+
+	// VERIFY
+	assert.NilError(t, err)
+	token := tokens.Authenticate("token1")
+	assert.Assert(t, token != nil)
+	assert.Equal(t, token.Name, "client1")
+	assert.Assert(t, token.Allows("ns2"))
+	assert.Assert(t, !token.Allows("ns3"))
+	assert.Assert(t, tokens.Authenticate("token2") == nil)
+	assert.Assert(t, tokens.Authenticate("") == nil)
+}
+
+func Test_LoadTokens_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		document      string
+		expectedError string
+	}{
+		{"no_name", "tokens: [{sha256: " + token1Hash + "}]", "tokens[0]: name must not be empty"},
+		{"invalid_hash", "tokens: [{name: client1, sha256: abc}]", "tokens[0]: sha256 must be a hex-encoded SHA-256 hash"},
+		{"invalid_document", "tokens: {", "invalid tokens"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			_, err := LoadTokens(strings.NewReader(tc.document))
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
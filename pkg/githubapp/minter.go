@@ -0,0 +1,248 @@
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SecretType is the type of secrets containing the credentials of a
+	// GitHub App installation.
+	SecretType v1.SecretType = "steward.sap.com/github-app"
+
+	// AppIDKey is the key of the ID of the GitHub App in secrets of type
+	// SecretType.
+	AppIDKey = "appID"
+
+	// InstallationIDKey is the key of the ID of the installation of the
+	// GitHub App in secrets of type SecretType.
+	InstallationIDKey = "installationID"
+
+	// PrivateKeyKey is the key of the PEM-encoded private key of the
+	// GitHub App in secrets of type SecretType.
+	PrivateKeyKey = "privateKey"
+
+	// APIURLKey is the key of the optional URL of the GitHub REST API in
+	// secrets of type SecretType, e.g. `https://github.example.com/api/v3`
+	// for GitHub Enterprise Server. If not set, DefaultAPIURL is used.
+	// Other URLs than DefaultAPIURL must be allowed via NewMinter.
+	APIURLKey = "apiURL"
+
+	// DefaultAPIURL is the URL of the GitHub REST API of github.com.
+	DefaultAPIURL = "https://api.github.com"
+
+	// MinTokenLifetime is the minimum remaining lifetime of cached
+	// installation tokens. Tokens expiring earlier are replaced by newly
+	// minted ones.
+	MinTokenLifetime = 30 * time.Minute
+
+	// jwtLifetime is the lifetime of the JSON Web Tokens authenticating
+	// the GitHub App. GitHub accepts at most ten minutes.
+	jwtLifetime = 9 * time.Minute
+
+	// jwtClockSkew is subtracted from the issue time of JSON Web Tokens
+	// to allow for clock skew between Steward and GitHub.
+	jwtClockSkew = time.Minute
+)
+
+// ErrAPIURLNotAllowed is returned (wrapped) by Minter.MintToken if the
+// API URL of a secret is not allowed.
+var ErrAPIURLNotAllowed = errors.New("GitHub API URL not allowed")
+
+// Token is a GitHub App installation token.
+type Token struct {
+	// Value is the token to be used as password for Git operations.
+	Value string
+
+	// ExpiresAt is the expiration time of the token.
+	ExpiresAt time.Time
+}
+
+// Minter mints installation tokens of GitHub Apps.
+// Tokens are cached per secret and private key as long as they have a
+// remaining lifetime of at least MinTokenLifetime, so that starting many
+// pipeline runs does not exhaust the rate limit of the GitHub App.
+// It is safe for concurrent use.
+type Minter struct {
+	client         *http.Client
+	allowedAPIURLs map[string]bool
+	now            func() time.Time
+
+	mutex sync.Mutex
+	cache map[string]Token
+}
+
+// NewMinter creates a Minter sending requests with the given HTTP
+// client. If client is nil, http.DefaultClient is used.
+// Secrets may only refer to DefaultAPIURL or one of allowedAPIURLs, as
+// the run controller sends requests to that URL on behalf of tenants.
+func NewMinter(client *http.Client, allowedAPIURLs []string) *Minter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	allowed := map[string]bool{DefaultAPIURL: true}
+	for _, apiURL := range allowedAPIURLs {
+		allowed[normalizeAPIURL(apiURL)] = true
+	}
+	return &Minter{
+		client:         client,
+		allowedAPIURLs: allowed,
+		now:            time.Now,
+		cache:          map[string]Token{},
+	}
+}
+
+// MintToken returns an installation token for the GitHub App
+// installation defined by the given secret of type SecretType.
+func (m *Minter) MintToken(ctx context.Context, secret *v1.Secret) (*Token, error) {
+	appID := strings.TrimSpace(string(secret.Data[AppIDKey]))
+	installationID := strings.TrimSpace(string(secret.Data[InstallationIDKey]))
+	if appID == "" || installationID == "" {
+		return nil, fmt.Errorf("secret %q: keys %q and %q must not be empty", secret.GetName(), AppIDKey, InstallationIDKey)
+	}
+	apiURL := normalizeAPIURL(string(secret.Data[APIURLKey]))
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	if !m.allowedAPIURLs[apiURL] {
+		return nil, errors.Wrapf(ErrAPIURLNotAllowed, "secret %q: value of key %q", secret.GetName(), APIURLKey)
+	}
+
+	// The cache key contains the secret and its private key, so that
+	// cached tokens are only returned to the owner of the secret and
+	// never for a secret with the same IDs but a different key.
+	privateKeyHash := sha256.Sum256(secret.Data[PrivateKeyKey])
+	cacheKey := fmt.Sprintf("%s/%s %x %s %s %s",
+		secret.GetNamespace(), secret.GetName(), privateKeyHash, apiURL, appID, installationID)
+	now := m.now()
+	m.mutex.Lock()
+	cached, ok := m.cache[cacheKey]
+	m.mutex.Unlock()
+	if ok && cached.ExpiresAt.Sub(now) >= MinTokenLifetime {
+		return &cached, nil
+	}
+
+	key, err := parsePrivateKey(secret.Data[PrivateKeyKey])
+	if err != nil {
+		return nil, errors.WithMessagef(err, "secret %q: invalid value of key %q", secret.GetName(), PrivateKeyKey)
+	}
+	jwt, err := appJWT(appID, key, now)
+	if err != nil {
+		return nil, err
+	}
+	token, err := m.requestToken(ctx, apiURL, installationID, jwt)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to mint installation token of GitHub App %s", appID)
+	}
+
+	m.mutex.Lock()
+	m.cache[cacheKey] = *token
+	m.mutex.Unlock()
+	return token, nil
+}
+
+// requestToken requests a new installation token from the GitHub REST
+// API authenticated with the given JSON Web Token of the GitHub App.
+func (m *Minter) requestToken(ctx context.Context, apiURL, installationID, jwt string) (*Token, error) {
+	url := fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("POST %s: unexpected status %q: %s", url, resp.Status, bytes.TrimSpace(body))
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, errors.Wrapf(err, "POST %s: invalid response", url)
+	}
+	if result.Token == "" {
+		return nil, fmt.Errorf("POST %s: response does not contain a token", url)
+	}
+	return &Token{Value: result.Token, ExpiresAt: result.ExpiresAt}, nil
+}
+
+// normalizeAPIURL trims whitespace and trailing slashes from the given
+// GitHub REST API URL.
+func normalizeAPIURL(apiURL string) string {
+	return strings.TrimSuffix(strings.TrimSpace(apiURL), "/")
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key in PKCS #1 format,
+// as downloaded from GitHub, or in PKCS #8 format.
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return rsaKey, nil
+}
+
+// appJWT returns a JSON Web Token signed with RS256 authenticating the
+// GitHub App with the given ID.
+func appJWT(appID string, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign JSON Web Token")
+	}
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
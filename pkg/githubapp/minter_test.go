@@ -0,0 +1,247 @@
+package githubapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	v1 "k8s.io/api/core/v1"
+)
+
+var testKey = generateTestKey()
+
+func generateTestKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func newAppSecret(apiURL string) *v1.Secret {
+	secret := fake.SecretWithType("app1", "ns1", SecretType)
+	secret.Data = map[string][]byte{
+		AppIDKey:          []byte("123"),
+		InstallationIDKey: []byte("456"),
+		PrivateKeyKey:     pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testKey)}),
+		APIURLKey:         []byte(apiURL),
+	}
+	return secret
+}
+
+// newTokenServer returns a server imitating the endpoint of the GitHub
+// REST API creating installation tokens. It counts the requests and
+// returns tokens expiring the given time after now.
+func newTokenServer(t *testing.T, lifetime time.Duration, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/app/installations/456/access_tokens", r.URL.Path)
+		assertValidJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), "123")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"token%d","expires_at":%q}`, *requests, time.Now().Add(lifetime).UTC().Format(time.RFC3339))
+	}))
+}
+
+func assertValidJWT(t *testing.T, jwt, expectedIssuer string) {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	assert.Equal(t, 3, len(parts))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NilError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	assert.NilError(t, rsa.VerifyPKCS1v15(&testKey.PublicKey, crypto.SHA256, digest[:], signature))
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NilError(t, err)
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	assert.NilError(t, json.Unmarshal(claimsJSON, &claims))
+	assert.Equal(t, expectedIssuer, claims.Issuer)
+}
+
+func Test_Minter_MintToken(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	requests := 0
+	server := newTokenServer(t, time.Hour, &requests)
+	defer server.Close()
+	examinee := NewMinter(server.Client(), []string{server.URL})
+
+	// EXERCISE
+	token, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "token1", token.Value)
+	assert.Assert(t, token.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+}
+
+func Test_Minter_MintToken_Cached(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	requests := 0
+	server := newTokenServer(t, time.Hour, &requests)
+	defer server.Close()
+	examinee := NewMinter(server.Client(), []string{server.URL})
+	_, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	token, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "token1", token.Value)
+	assert.Equal(t, 1, requests)
+}
+
+func Test_Minter_MintToken_NotCachedForOtherSecrets(t *testing.T) {
+	t.Parallel()
+
+	otherKey := generateTestKey()
+	for _, tc := range []struct {
+		name   string
+		modify func(*v1.Secret)
+	}{
+		{"other_namespace", func(s *v1.Secret) { s.SetNamespace("ns2") }},
+		{"other_name", func(s *v1.Secret) { s.SetName("app2") }},
+		{"other_private_key", func(s *v1.Secret) {
+			s.Data[PrivateKeyKey] = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(otherKey)})
+		}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token":"token%d","expires_at":%q}`, requests, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+			}))
+			defer server.Close()
+			examinee := NewMinter(server.Client(), []string{server.URL})
+			_, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+			assert.NilError(t, err)
+			otherSecret := newAppSecret(server.URL)
+			tc.modify(otherSecret)
+
+			// EXERCISE
+			token, err := examinee.MintToken(context.Background(), otherSecret)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, "token2", token.Value)
+			assert.Equal(t, 2, requests)
+		})
+	}
+}
+
+func Test_Minter_MintToken_CachedTokenExpiring(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	requests := 0
+	server := newTokenServer(t, MinTokenLifetime-time.Minute, &requests)
+	defer server.Close()
+	examinee := NewMinter(server.Client(), []string{server.URL})
+	_, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	token, err := examinee.MintToken(context.Background(), newAppSecret(server.URL))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "token2", token.Value)
+	assert.Equal(t, 2, requests)
+}
+
+func Test_Minter_MintToken_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		modify        func(*v1.Secret)
+		status        int
+		expectedError string
+	}{
+		{
+			name:          "missing_installation_id",
+			modify:        func(s *v1.Secret) { delete(s.Data, InstallationIDKey) },
+			expectedError: `secret "app1": keys "appID" and "installationID" must not be empty`,
+		},
+		{
+			name:          "invalid_private_key",
+			modify:        func(s *v1.Secret) { s.Data[PrivateKeyKey] = []byte("foo") },
+			expectedError: `secret "app1": invalid value of key "privateKey": no PEM data found`,
+		},
+		{
+			name:          "api_url_not_allowed",
+			modify:        func(s *v1.Secret) { s.Data[APIURLKey] = []byte("https://internal.example.com/") },
+			expectedError: `secret "app1": value of key "apiURL": GitHub API URL not allowed`,
+		},
+		{
+			name:          "request_failed",
+			status:        http.StatusNotFound,
+			expectedError: "failed to mint installation token of GitHub App 123: POST",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+			secret := newAppSecret(server.URL)
+			if tc.modify != nil {
+				tc.modify(secret)
+			}
+			examinee := NewMinter(server.Client(), []string{server.URL})
+
+			// EXERCISE
+			_, err := examinee.MintToken(context.Background(), secret)
+
+			// VERIFY
+			assert.Assert(t, is.Contains(err.Error(), tc.expectedError))
+		})
+	}
+}
+
+func Test_parsePrivateKey_PKCS8(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	der, err := x509.MarshalPKCS8PrivateKey(testKey)
+	assert.NilError(t, err)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	// EXERCISE
+	key, err := parsePrivateKey(data)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, testKey.Equal(key))
+}
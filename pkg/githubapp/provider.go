@@ -0,0 +1,60 @@
+package githubapp
+
+import (
+	"context"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type provider struct {
+	provider secrets.SecretProvider
+	minter   *Minter
+}
+
+// NewSecretProvider returns a secret provider returning the secrets of
+// the given provider, except that secrets of type SecretType are replaced
+// by token secrets (see secrets.IsTokenSecret) containing an installation
+// token minted with the given minter.
+// The token secrets are labelled with LabelGitHubAppToken and annotated
+// with the name of the GitHub App secret and the expiration time of the
+// token, so that they can be refreshed.
+func NewSecretProvider(secretProvider secrets.SecretProvider, minter *Minter) secrets.SecretProvider {
+	return &provider{
+		provider: secretProvider,
+		minter:   minter,
+	}
+}
+
+// GetSecret implements secrets.SecretProvider.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	secret, err := p.provider.GetSecret(ctx, name)
+	if err != nil || secret == nil || secret.Type != SecretType {
+		return secret, err
+	}
+	token, err := p.minter.MintToken(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	return TokenSecret(secret, token), nil
+}
+
+// TokenSecret returns a token secret with the metadata of the given
+// GitHub App secret containing the given installation token.
+func TokenSecret(appSecret *v1.Secret, token *Token) *v1.Secret {
+	result := &v1.Secret{
+		ObjectMeta: *appSecret.ObjectMeta.DeepCopy(),
+		Type:       v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			v1.BasicAuthUsernameKey: []byte(secrets.DefaultTokenUsername),
+			secrets.TokenKey:        []byte(token.Value),
+		},
+	}
+	metav1.SetMetaDataLabel(&result.ObjectMeta, api.LabelGitHubAppToken, "")
+	metav1.SetMetaDataAnnotation(&result.ObjectMeta, api.AnnotationGitHubAppSecret, appSecret.GetName())
+	metav1.SetMetaDataAnnotation(&result.ObjectMeta, api.AnnotationGitHubAppTokenExpiresAt, token.ExpiresAt.UTC().Format(time.RFC3339))
+	return result
+}
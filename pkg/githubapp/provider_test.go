@@ -0,0 +1,74 @@
+package githubapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	secretproviderfakes "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_SecretProvider_GetSecret_GitHubAppSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	requests := 0
+	server := newTokenServer(t, time.Hour, &requests)
+	defer server.Close()
+	examinee := NewSecretProvider(
+		secretproviderfakes.NewProvider("ns1", newAppSecret(server.URL)),
+		NewMinter(server.Client(), []string{server.URL}),
+	)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(context.Background(), "app1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "app1", secret.GetName())
+	assert.Assert(t, secrets.IsTokenSecret(secret))
+	username, password := secrets.RepoCredentials(secret)
+	assert.Equal(t, secrets.DefaultTokenUsername, username)
+	assert.Equal(t, "token1", password)
+	_, labelled := secret.GetLabels()[api.LabelGitHubAppToken]
+	assert.Assert(t, labelled)
+	assert.Equal(t, "app1", secret.GetAnnotations()[api.AnnotationGitHubAppSecret])
+	expiresAt, err := time.Parse(time.RFC3339, secret.GetAnnotations()[api.AnnotationGitHubAppTokenExpiresAt])
+	assert.NilError(t, err)
+	assert.Assert(t, expiresAt.After(time.Now()))
+}
+
+func Test_SecretProvider_GetSecret_OtherSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	orig := fake.SecretWithType("secret1", "ns1", v1.SecretTypeBasicAuth)
+	examinee := NewSecretProvider(secretproviderfakes.NewProvider("ns1", orig), NewMinter(nil, nil))
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(context.Background(), "secret1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, v1.SecretTypeBasicAuth, secret.Type)
+	assert.Equal(t, 0, len(secret.GetLabels()))
+}
+
+func Test_SecretProvider_GetSecret_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewSecretProvider(secretproviderfakes.NewProvider("ns1"), NewMinter(nil, nil))
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(context.Background(), "secret1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, secret == nil)
+}
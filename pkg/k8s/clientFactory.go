@@ -1,16 +1,24 @@
 package k8s
 
 import (
+	"fmt"
+	"runtime"
 	"time"
 
 	stewardclients "github.com/SAP/stewardci-core/pkg/client/clientset/versioned"
 	stewardv1alpha1client "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	stewardinformers "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
+	"github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
 	tektonclients "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned"
 	tektonv1beta1client "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/typed/pipeline/v1beta1"
 	tektoninformers "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	dynamic "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
@@ -20,6 +28,12 @@ import (
 
 // ClientFactory is the interface for Kubernet client factories.
 type ClientFactory interface {
+	// AppsV1 returns the apps/v1 Kubernetes client
+	AppsV1() appsv1client.AppsV1Interface
+
+	// CoordinationV1 returns the coordination.k8s.io/v1 Kubernetes client
+	CoordinationV1() coordinationv1client.CoordinationV1Interface
+
 	// CoreV1 returns the core/v1 Kubernetes client
 	CoreV1() corev1client.CoreV1Interface
 
@@ -32,38 +46,175 @@ type ClientFactory interface {
 	// Dynamic returns the dynamic Kubernetes client
 	Dynamic() dynamic.Interface
 
+	// InstanceID returns the identifier of the Steward instance.
+	// The informers of the factory only watch objects labelled with
+	// this instance ID. If empty, only objects without instance ID
+	// label are watched.
+	InstanceID() string
+
+	// NamespaceMetadata returns the labels and annotations to be set
+	// on all namespaces created by the controllers. Might be nil.
+	NamespaceMetadata() *namespacemetadata.Metadata
+
 	// StewardV1alpha1 returns the steward.sap.com/v1alpha1 Kubernetes client
 	StewardV1alpha1() stewardv1alpha1client.StewardV1alpha1Interface
 
 	// StewardInformerFactory returns the informer factory for Steward
+	// watching all namespaces.
+	// Must not be used if WatchNamespaces returns a non-empty list.
 	StewardInformerFactory() stewardinformers.SharedInformerFactory
 
+	// StewardInformerFactories returns the informer factories for Steward
+	// keyed by the namespace they are restricted to.
+	// If the factory is not restricted to a list of namespaces, the
+	// result contains the cluster-wide informer factory only, keyed by
+	// `metav1.NamespaceAll`.
+	StewardInformerFactories() map[string]stewardinformers.SharedInformerFactory
+
 	// TektonV1beta1 returns the tekton.dev/v1beta1 Kubernetes client
 	TektonV1beta1() tektonv1beta1client.TektonV1beta1Interface
 
 	// TektonInformerFactory returns the informer factory for Tekton
 	TektonInformerFactory() tektoninformers.SharedInformerFactory
+
+	// NewTektonInformerFactoryForNamespace returns a new informer factory
+	// for Tekton restricted to the given namespace. It allows watching
+	// Tekton resources with namespace-scoped permissions.
+	NewTektonInformerFactoryForNamespace(namespace string) tektoninformers.SharedInformerFactory
+
+	// WatchNamespaces returns the list of namespaces the Steward
+	// informers are restricted to.
+	// An empty list means that all namespaces are watched.
+	WatchNamespaces() []string
+}
+
+// ClientFactoryOpts stores options for the construction of a
+// ClientFactory instance.
+type ClientFactoryOpts struct {
+	// WatchNamespaces is the list of namespaces the Steward informers
+	// are restricted to. This allows running controllers with
+	// namespace-scoped permissions for Steward resources.
+	// If empty, all namespaces are watched.
+	WatchNamespaces []string
+
+	// InstanceID is the identifier of the Steward instance, which allows
+	// running multiple independent Steward instances in one cluster.
+	// The informers only watch objects labelled with this instance ID.
+	// If empty, only objects without instance ID label are watched.
+	InstanceID string
+
+	// NamespaceMetadata defines labels and annotations to be set on all
+	// namespaces created by the controllers.
+	// If nil, no additional metadata is set.
+	NamespaceMetadata *namespacemetadata.Metadata
+
+	// ReadConfig is the rest config for the clients used by the informers
+	// to list and watch objects. A separate config allows to rate-limit
+	// reads independently from writes, so that relists do not delay
+	// status updates and vice versa. See NewReadConfig.
+	// If nil, the informers use the same clients as all other requests.
+	ReadConfig *rest.Config
+
+	// Protobuf enables protobuf encoding for requests of the Kubernetes
+	// clients (CoreV1, NetworkingV1 and RbacV1), which reduces
+	// serialization overhead compared to JSON.
+	// The clients for custom resources and the dynamic client always use
+	// JSON, as the API server does not support protobuf for them.
+	Protobuf bool
+}
+
+// UserAgent returns the HTTP user agent to be used by the given Steward
+// component for requests to the Kubernetes API server, e.g.
+// `steward-run-controller (linux/amd64)`. A distinct user agent allows
+// cluster administrators to identify requests of Steward components, e.g.
+// in audit logs and API server metrics.
+func UserAgent(component string) string {
+	return fmt.Sprintf("steward-%s (%s/%s)", component, runtime.GOOS, runtime.GOARCH)
+}
+
+// NewReadConfig returns a copy of the given rest config with the given
+// client-side rate limits, which is suitable as ClientFactoryOpts.ReadConfig.
+// A rate limiter set in the given config is not copied.
+// The user agent gets suffix ` informers` to distinguish read requests
+// from other requests.
+func NewReadConfig(config *rest.Config, qps float32, burst int) *rest.Config {
+	readConfig := rest.CopyConfig(config)
+	readConfig.QPS = qps
+	readConfig.Burst = burst
+	readConfig.RateLimiter = nil
+	if readConfig.UserAgent != "" {
+		readConfig.UserAgent += " informers"
+	}
+	return readConfig
 }
 
 type clientFactory struct {
-	kubernetesClientset    *kubernetes.Clientset
-	dynamicClient          dynamic.Interface
-	stewardClientset       *stewardclients.Clientset
-	stewardInformerFactory stewardinformers.SharedInformerFactory
-	tektonClientset        *tektonclients.Clientset
-	tektonInformerFactory  tektoninformers.SharedInformerFactory
+	kubernetesClientset      *kubernetes.Clientset
+	dynamicClient            dynamic.Interface
+	stewardClientset         *stewardclients.Clientset
+	stewardInformerFactories map[string]stewardinformers.SharedInformerFactory
+	tektonClientset          *tektonclients.Clientset
+	tektonInformerFactory    tektoninformers.SharedInformerFactory
+	tektonReadClientset      *tektonclients.Clientset
+	tektonListOptionsTweak   func(*metav1.ListOptions)
+	resyncPeriod             time.Duration
+	watchNamespaces          []string
+	instanceID               string
+	namespaceMetadata        *namespacemetadata.Metadata
 }
 
 // NewClientFactory creates new client factory based on rest config
 func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFactory {
+	return NewClientFactoryWithOpts(config, resyncPeriod, ClientFactoryOpts{})
+}
+
+// NewClientFactoryWithoutResync creates new client factory based on rest
+// config and the given options for programs which do not use the
+// informers of the factory, e.g. command line tools. The informers are
+// not resynced periodically.
+func NewClientFactoryWithoutResync(config *rest.Config, opts ClientFactoryOpts) ClientFactory {
+	return NewClientFactoryWithOpts(config, 0, opts)
+}
+
+// NewClientFactoryWithOpts creates new client factory based on rest config
+// and the given options.
+func NewClientFactoryWithOpts(config *rest.Config, resyncPeriod time.Duration, opts ClientFactoryOpts) ClientFactory {
 	stewardClientset, err := stewardclients.NewForConfig(config)
 	if err != nil {
 		klog.ErrorS(err, "could not create Steward clientset: %s")
 		return nil
 	}
-	stewardInformerFactory := stewardinformers.NewSharedInformerFactory(stewardClientset, resyncPeriod)
+	stewardReadClientset := stewardClientset
+	if opts.ReadConfig != nil {
+		stewardReadClientset, err = stewardclients.NewForConfig(opts.ReadConfig)
+		if err != nil {
+			klog.ErrorS(err, "could not create Steward read clientset: %s")
+			return nil
+		}
+	}
+	instanceIDSelector := func(options *metav1.ListOptions) {
+		options.LabelSelector = stewardlabels.InstanceIDSelector(opts.InstanceID)
+	}
+	stewardInformerFactories := map[string]stewardinformers.SharedInformerFactory{}
+	if len(opts.WatchNamespaces) == 0 {
+		stewardInformerFactories[metav1.NamespaceAll] = stewardinformers.NewSharedInformerFactoryWithOptions(
+			stewardReadClientset, resyncPeriod, stewardinformers.WithTweakListOptions(instanceIDSelector),
+		)
+	} else {
+		for _, namespace := range opts.WatchNamespaces {
+			stewardInformerFactories[namespace] = stewardinformers.NewSharedInformerFactoryWithOptions(
+				stewardReadClientset, resyncPeriod,
+				stewardinformers.WithNamespace(namespace),
+				stewardinformers.WithTweakListOptions(instanceIDSelector),
+			)
+		}
+	}
 
-	kubernetesClientset, err := kubernetes.NewForConfig(config)
+	kubernetesConfig := config
+	if opts.Protobuf {
+		kubernetesConfig = newProtobufConfig(config)
+	}
+	kubernetesClientset, err := kubernetes.NewForConfig(kubernetesConfig)
 	if err != nil {
 		klog.ErrorS(err, "could not create Kubernetes clientset: %s")
 		return nil
@@ -80,21 +231,52 @@ func NewClientFactory(config *rest.Config, resyncPeriod time.Duration) ClientFac
 		klog.ErrorS(err, "could not create Tekton clientset: %s")
 		return nil
 	}
-	tektonInformerFactory := tektoninformers.NewSharedInformerFactory(tektonClientset, resyncPeriod)
+	tektonReadClientset := tektonClientset
+	if opts.ReadConfig != nil {
+		tektonReadClientset, err = tektonclients.NewForConfig(opts.ReadConfig)
+		if err != nil {
+			klog.ErrorS(err, "could not create Tekton read clientset: %s")
+			return nil
+		}
+	}
+	tektonInformerFactory := tektoninformers.NewSharedInformerFactoryWithOptions(
+		tektonReadClientset, resyncPeriod, tektoninformers.WithTweakListOptions(instanceIDSelector),
+	)
 
 	return &clientFactory{
-		kubernetesClientset:    kubernetesClientset,
-		dynamicClient:          dynamicClient,
-		stewardClientset:       stewardClientset,
-		stewardInformerFactory: stewardInformerFactory,
-		tektonClientset:        tektonClientset,
-		tektonInformerFactory:  tektonInformerFactory,
+		kubernetesClientset:      kubernetesClientset,
+		dynamicClient:            dynamicClient,
+		stewardClientset:         stewardClientset,
+		stewardInformerFactories: stewardInformerFactories,
+		tektonClientset:          tektonClientset,
+		tektonInformerFactory:    tektonInformerFactory,
+		tektonReadClientset:      tektonReadClientset,
+		tektonListOptionsTweak:   instanceIDSelector,
+		resyncPeriod:             resyncPeriod,
+		watchNamespaces:          append([]string(nil), opts.WatchNamespaces...),
+		instanceID:               opts.InstanceID,
+		namespaceMetadata:        opts.NamespaceMetadata,
 	}
 }
 
+// newProtobufConfig returns a copy of the given rest config that
+// sends requests encoded as protobuf and accepts protobuf responses
+// with a fallback to JSON.
+func newProtobufConfig(config *rest.Config) *rest.Config {
+	result := rest.CopyConfig(config)
+	result.ContentType = k8sruntime.ContentTypeProtobuf
+	result.AcceptContentTypes = k8sruntime.ContentTypeProtobuf + "," + k8sruntime.ContentTypeJSON
+	return result
+}
+
 // StewardInformerFactory implements interface ClientFactory
 func (f *clientFactory) StewardInformerFactory() stewardinformers.SharedInformerFactory {
-	return f.stewardInformerFactory
+	return f.stewardInformerFactories[metav1.NamespaceAll]
+}
+
+// StewardInformerFactories implements interface ClientFactory
+func (f *clientFactory) StewardInformerFactories() map[string]stewardinformers.SharedInformerFactory {
+	return f.stewardInformerFactories
 }
 
 // StewardV1alpha1 implements interface ClientFactory
@@ -102,6 +284,16 @@ func (f *clientFactory) StewardV1alpha1() stewardv1alpha1client.StewardV1alpha1I
 	return f.stewardClientset.StewardV1alpha1()
 }
 
+// AppsV1 implements interface ClientFactory
+func (f *clientFactory) AppsV1() appsv1client.AppsV1Interface {
+	return f.kubernetesClientset.AppsV1()
+}
+
+// CoordinationV1 implements interface ClientFactory
+func (f *clientFactory) CoordinationV1() coordinationv1client.CoordinationV1Interface {
+	return f.kubernetesClientset.CoordinationV1()
+}
+
 // CoreV1 implements interface ClientFactory
 func (f *clientFactory) CoreV1() corev1client.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
@@ -112,6 +304,16 @@ func (f *clientFactory) Dynamic() dynamic.Interface {
 	return f.dynamicClient
 }
 
+// InstanceID implements interface ClientFactory
+func (f *clientFactory) InstanceID() string {
+	return f.instanceID
+}
+
+// NamespaceMetadata implements interface ClientFactory
+func (f *clientFactory) NamespaceMetadata() *namespacemetadata.Metadata {
+	return f.namespaceMetadata
+}
+
 // NetworkingV1 implements interface ClientFactory
 func (f *clientFactory) NetworkingV1() networkingv1client.NetworkingV1Interface {
 	return f.kubernetesClientset.NetworkingV1()
@@ -127,7 +329,21 @@ func (f *clientFactory) TektonInformerFactory() tektoninformers.SharedInformerFa
 	return f.tektonInformerFactory
 }
 
+// NewTektonInformerFactoryForNamespace implements interface ClientFactory
+func (f *clientFactory) NewTektonInformerFactoryForNamespace(namespace string) tektoninformers.SharedInformerFactory {
+	return tektoninformers.NewSharedInformerFactoryWithOptions(
+		f.tektonReadClientset, f.resyncPeriod,
+		tektoninformers.WithNamespace(namespace),
+		tektoninformers.WithTweakListOptions(f.tektonListOptionsTweak),
+	)
+}
+
 // TektonV1beta1 implements interface ClientFactory
 func (f *clientFactory) TektonV1beta1() tektonv1beta1client.TektonV1beta1Interface {
 	return f.tektonClientset.TektonV1beta1()
 }
+
+// WatchNamespaces implements interface ClientFactory
+func (f *clientFactory) WatchNamespaces() []string {
+	return f.watchNamespaces
+}
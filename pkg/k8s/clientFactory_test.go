@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"regexp"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func Test_UserAgent(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := UserAgent("run-controller")
+
+	// VERIFY
+	assert.Assert(t, is.Regexp("^"+regexp.QuoteMeta("steward-run-controller (")+"[a-z0-9]+/[a-z0-9]+\\)$", result))
+}
+
+func Test_NewReadConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := &rest.Config{
+		Host:        "https://host1",
+		QPS:         5,
+		Burst:       10,
+		UserAgent:   "agent1",
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(5, 10),
+	}
+
+	// EXERCISE
+	result := NewReadConfig(config, 20, 30)
+
+	// VERIFY
+	assert.Equal(t, "https://host1", result.Host)
+	assert.Equal(t, float32(20), result.QPS)
+	assert.Equal(t, 30, result.Burst)
+	assert.Equal(t, "agent1 informers", result.UserAgent)
+	assert.Assert(t, result.RateLimiter == nil)
+
+	// original config is unchanged
+	assert.Equal(t, float32(5), config.QPS)
+	assert.Equal(t, 10, config.Burst)
+	assert.Equal(t, "agent1", config.UserAgent)
+	assert.Assert(t, config.RateLimiter != nil)
+}
+
+func Test_NewReadConfig_EmptyUserAgent(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := NewReadConfig(&rest.Config{}, 20, 30)
+
+	// VERIFY
+	assert.Equal(t, "", result.UserAgent)
+}
+
+func Test_newProtobufConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := &rest.Config{
+		Host: "https://host1",
+	}
+
+	// EXERCISE
+	result := newProtobufConfig(config)
+
+	// VERIFY
+	assert.Equal(t, "https://host1", result.Host)
+	assert.Equal(t, "application/vnd.kubernetes.protobuf", result.ContentType)
+	assert.Equal(t, "application/vnd.kubernetes.protobuf,application/json", result.AcceptContentTypes)
+
+	// original config is unchanged
+	assert.Equal(t, "", config.ContentType)
+	assert.Equal(t, "", config.AcceptContentTypes)
+}
@@ -7,6 +7,7 @@ import (
 	stewardclientfake "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/fake"
 	stewardv1alpha1client "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	stewardinformer "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
+	namespacemetadata "github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
 	tektonclientfake "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/fake"
 	tektonv1beta1client "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/typed/pipeline/v1beta1"
 	tektoninformers "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
@@ -16,6 +17,8 @@ import (
 	dynamic "k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sclientfake "k8s.io/client-go/kubernetes/fake"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
@@ -31,6 +34,9 @@ type ClientFactory struct {
 	tektonClientset        *tektonclientfake.Clientset
 	tektonInformerFactory  tektoninformers.SharedInformerFactory
 	sleepDuration          time.Duration
+	instanceID             string
+	namespaceMetadata      *namespacemetadata.Metadata
+	watchNamespaces        []string
 }
 
 // NewClientFactory creates a new ClientFactory
@@ -88,11 +94,28 @@ func (f *ClientFactory) StewardInformerFactory() stewardinformer.SharedInformerF
 	return f.stewardInformerFactory
 }
 
+// StewardInformerFactories implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) StewardInformerFactories() map[string]stewardinformer.SharedInformerFactory {
+	return map[string]stewardinformer.SharedInformerFactory{
+		metav1.NamespaceAll: f.stewardInformerFactory,
+	}
+}
+
 // KubernetesClientset returns the Kubernetes fake clientset.
 func (f *ClientFactory) KubernetesClientset() *k8sclientfake.Clientset {
 	return f.kubernetesClientset
 }
 
+// AppsV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) AppsV1() appsv1client.AppsV1Interface {
+	return f.kubernetesClientset.AppsV1()
+}
+
+// CoordinationV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) CoordinationV1() coordinationv1client.CoordinationV1Interface {
+	return f.kubernetesClientset.CoordinationV1()
+}
+
 // CoreV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
 func (f *ClientFactory) CoreV1() corev1client.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
@@ -108,6 +131,26 @@ func (f *ClientFactory) DynamicFake() *dynamicfake.FakeDynamicClient {
 	return f.DynamicClient
 }
 
+// InstanceID implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) InstanceID() string {
+	return f.instanceID
+}
+
+// SetInstanceID sets the Steward instance ID returned by InstanceID.
+func (f *ClientFactory) SetInstanceID(instanceID string) {
+	f.instanceID = instanceID
+}
+
+// NamespaceMetadata implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) NamespaceMetadata() *namespacemetadata.Metadata {
+	return f.namespaceMetadata
+}
+
+// SetNamespaceMetadata sets the namespace metadata returned by NamespaceMetadata.
+func (f *ClientFactory) SetNamespaceMetadata(metadata *namespacemetadata.Metadata) {
+	f.namespaceMetadata = metadata
+}
+
 // NetworkingV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
 func (f *ClientFactory) NetworkingV1() networkingv1client.NetworkingV1Interface {
 	return f.kubernetesClientset.NetworkingV1()
@@ -123,6 +166,13 @@ func (f *ClientFactory) TektonInformerFactory() tektoninformers.SharedInformerFa
 	return f.tektonInformerFactory
 }
 
+// NewTektonInformerFactoryForNamespace implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) NewTektonInformerFactoryForNamespace(namespace string) tektoninformers.SharedInformerFactory {
+	return tektoninformers.NewSharedInformerFactoryWithOptions(
+		f.tektonClientset, 10*time.Minute, tektoninformers.WithNamespace(namespace),
+	)
+}
+
 // TektonClientset returns the Tekton fake clientset.
 func (f *ClientFactory) TektonClientset() *tektonclientfake.Clientset {
 	return f.tektonClientset
@@ -133,6 +183,17 @@ func (f *ClientFactory) TektonV1beta1() tektonv1beta1client.TektonV1beta1Interfa
 	return f.tektonClientset.TektonV1beta1()
 }
 
+// WatchNamespaces implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) WatchNamespaces() []string {
+	return f.watchNamespaces
+}
+
+// SetWatchNamespaces sets the namespaces returned by WatchNamespaces.
+// It does not restrict the informer factories.
+func (f *ClientFactory) SetWatchNamespaces(namespaces ...string) {
+	f.watchNamespaces = namespaces
+}
+
 // Sleep sleeps and logs the start and the end of the sleep.
 func (f *ClientFactory) Sleep(message string) {
 	klog.Infof("Sleep start: %s", message)
@@ -0,0 +1,18 @@
+package fake
+
+import (
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineRunTemplate creates a new fake pipeline run template object.
+func PipelineRunTemplate(name, namespace string, spec stewardv1alpha1.PipelineSpec) *stewardv1alpha1.PipelineRunTemplate {
+	return &stewardv1alpha1.PipelineRunTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: stewardv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "PipelineRunTemplate",
+		},
+		ObjectMeta: ObjectMeta(name, namespace),
+		Spec:       spec,
+	}
+}
@@ -0,0 +1,18 @@
+package fake
+
+import (
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineSchedule creates a new fake pipeline schedule object.
+func PipelineSchedule(name, namespace string, spec stewardv1alpha1.PipelineScheduleSpec) *stewardv1alpha1.PipelineSchedule {
+	return &stewardv1alpha1.PipelineSchedule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: stewardv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "PipelineSchedule",
+		},
+		ObjectMeta: ObjectMeta(name, namespace),
+		Spec:       spec,
+	}
+}
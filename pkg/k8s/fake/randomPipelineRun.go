@@ -0,0 +1,94 @@
+package fake
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	proptest "github.com/SAP/stewardci-core/pkg/utils/proptest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RandomPipelineRun creates a new pipeline run object with a random but
+// valid spec for property-based tests.
+func RandomPipelineRun(r *rand.Rand, name, namespace string) *stewardv1alpha1.PipelineRun {
+	return PipelineRun(name, namespace, RandomPipelineSpec(r))
+}
+
+// RandomPipelineSpec returns a random but valid pipeline run spec for
+// property-based tests. Optional fields are omitted randomly. The spec
+// does not refer to a template and has no intent.
+func RandomPipelineSpec(r *rand.Rand) stewardv1alpha1.PipelineSpec {
+	spec := stewardv1alpha1.PipelineSpec{
+		JenkinsFile: stewardv1alpha1.JenkinsFile{
+			URL:  randomRepoURL(r),
+			Path: proptest.OneOf(r, "Jenkinsfile", "ci/Jenkinsfile", "pipelines/build.groovy"),
+		},
+		Args:             proptest.StringMap(r, 5),
+		Secrets:          proptest.DNSLabels(r, 3),
+		ImagePullSecrets: proptest.DNSLabels(r, 2),
+	}
+	if proptest.Bool(r) {
+		spec.JenkinsFile.Revision = proptest.OneOf(r, "main", "master", "refs/heads/feature/x", "0123456789abcdef0123456789abcdef01234567")
+	}
+	if proptest.Bool(r) {
+		spec.JenkinsFile.RepoAuthSecret = proptest.DNSLabel(r, 20)
+	}
+	for i, n := 0, r.Intn(3); i < n; i++ {
+		spec.Sources = append(spec.Sources, stewardv1alpha1.SourceRepository{
+			URL:        randomRepoURL(r),
+			Revision:   proptest.OneOf(r, "main", "v1.0.0"),
+			TargetPath: fmt.Sprintf("src%d", i),
+		})
+	}
+	if proptest.Bool(r) {
+		spec.ConcurrencyGroup = proptest.DNSLabel(r, 20)
+		spec.ConcurrencyCancelInProgress = proptest.Bool(r)
+	}
+	if proptest.Bool(r) {
+		spec.Logging = &stewardv1alpha1.Logging{
+			Elasticsearch: &stewardv1alpha1.Elasticsearch{},
+		}
+		if proptest.Bool(r) {
+			spec.Logging.Elasticsearch.RunID = &stewardv1alpha1.CustomJSON{
+				Value: map[string]interface{}{"jobId": proptest.DNSLabel(r, 10)},
+			}
+		}
+	}
+	if proptest.Bool(r) {
+		spec.Timeout = &metav1.Duration{Duration: time.Duration(1+r.Intn(48)) * time.Hour}
+	}
+	if proptest.Bool(r) {
+		ttl := int32(r.Intn(7 * 24 * 3600))
+		spec.TTLSecondsAfterFinished = &ttl
+	}
+	if proptest.Bool(r) {
+		spec.RunDetails = &stewardv1alpha1.PipelineRunDetails{
+			JobName:        proptest.String(r, 20),
+			SequenceNumber: r.Int31n(1000),
+			Cause:          proptest.String(r, 20),
+		}
+	}
+	if proptest.Bool(r) {
+		spec.TestResults = &stewardv1alpha1.TestResultsSpec{Path: "target/surefire-reports/*.xml"}
+	}
+	if proptest.Bool(r) {
+		spec.TriggeredBy = &stewardv1alpha1.TriggeredBy{
+			Type: proptest.OneOf(r, "github-push", "schedule", "manual"),
+			ID:   proptest.String(r, 80) + "x",
+		}
+		if proptest.Bool(r) {
+			spec.TriggeredBy.URL = randomRepoURL(r) + "/commit/1"
+		}
+	}
+	return spec
+}
+
+func randomRepoURL(r *rand.Rand) string {
+	return fmt.Sprintf(
+		"https://%s/%s/%s",
+		proptest.OneOf(r, "github.com", "github.example.com", "git.example.com:8443"),
+		proptest.DNSLabel(r, 15), proptest.DNSLabel(r, 15),
+	)
+}
@@ -45,6 +45,7 @@ func NewErrorReactor(expectedErr error) testing.ReactionFunc {
 }
 
 // NewCreationTimestampReactor returns a new ReactorFunc setting the creation time
+// if it is not set already
 func NewCreationTimestampReactor() testing.ReactionFunc {
 	return func(action testing.Action) (handled bool, ret runtime.Object, err error) {
 		createAction := action.(testing.CreateAction)
@@ -52,7 +53,9 @@ func NewCreationTimestampReactor() testing.ReactionFunc {
 		if err != nil {
 			panic(err)
 		}
-		accessor.SetCreationTimestamp(metav1.Now())
+		if created := accessor.GetCreationTimestamp(); created.IsZero() {
+			accessor.SetCreationTimestamp(metav1.Now())
+		}
 		return false, createAction.GetObject(), nil
 	}
 }
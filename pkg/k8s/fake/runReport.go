@@ -0,0 +1,18 @@
+package fake
+
+import (
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RunReport creates a new fake run report object.
+func RunReport(name, namespace string, summary stewardv1alpha1.RunReportSummary) *stewardv1alpha1.RunReport {
+	return &stewardv1alpha1.RunReport{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: stewardv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "RunReport",
+		},
+		ObjectMeta: ObjectMeta(name, namespace),
+		Summary:    summary,
+	}
+}
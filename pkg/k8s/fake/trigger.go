@@ -0,0 +1,18 @@
+package fake
+
+import (
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Trigger creates a new fake trigger object.
+func Trigger(name, namespace string, spec stewardv1alpha1.TriggerSpec) *stewardv1alpha1.Trigger {
+	return &stewardv1alpha1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: stewardv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Trigger",
+		},
+		ObjectMeta: ObjectMeta(name, namespace),
+		Spec:       spec,
+	}
+}
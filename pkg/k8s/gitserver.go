@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// scpLikeGitURLPattern matches scp-like Git URLs `[<user>@]<host>:<path>`.
+var scpLikeGitURLPattern = regexp.MustCompile(`^(?:[^@/:]+@)?([a-zA-Z0-9][-a-zA-Z0-9.]*):(.*)$`)
+
+// GitCredentialServer returns the server of the Git repository with the
+// given URL in the format of Tekton Git credential annotations
+// `tekton.dev/git-<n>`: `<scheme>://<host>[:<port>]` for HTTP(S) URLs,
+// matched by secrets of type `kubernetes.io/basic-auth`, and
+// `<host>[:<port>]` for SSH URLs, matched by secrets of type
+// `kubernetes.io/ssh-auth`. SSH URLs are URLs with scheme `ssh` or
+// `git+ssh` and scp-like URLs `[<user>@]<host>:<path>`.
+func GitCredentialServer(repoURL string) (string, error) {
+	if !strings.Contains(repoURL, "://") {
+		if match := scpLikeGitURLPattern.FindStringSubmatch(repoURL); match != nil {
+			return match[1], nil
+		}
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", err
+	}
+	switch parsed.Scheme {
+	case "http", "https":
+		return fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host), nil
+	case "ssh", "git+ssh":
+		return parsed.Host, nil
+	}
+	return "", fmt.Errorf("scheme not supported: %q", parsed.Scheme)
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_GitCredentialServer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{"https://foo.com/org/repo", "https://foo.com"},
+		{"HTTP://foo.com:1234/org/repo", "http://foo.com:1234"},
+		{"https://user@foo.com/org/repo", "https://foo.com"},
+		{"ssh://git@foo.com/org/repo.git", "foo.com"},
+		{"ssh://git@foo.com:2222/org/repo.git", "foo.com:2222"},
+		{"git+ssh://foo.com/org/repo.git", "foo.com"},
+		{"git@foo.com:org/repo.git", "foo.com"},
+		{"foo.com:org/repo.git", "foo.com"},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			server, err := GitCredentialServer(tc.url)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expected, server)
+		})
+	}
+}
+
+func Test_GitCredentialServer_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url           string
+		expectedError string
+	}{
+		{"ftp://foo.com/org/repo", `scheme not supported: "ftp"`},
+		{"file:///repos/repo", `scheme not supported: "file"`},
+		{"/repos/repo", `scheme not supported: ""`},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			server, err := GitCredentialServer(tc.url)
+			assert.Error(t, err, tc.expectedError)
+			assert.Equal(t, "", server)
+		})
+	}
+}
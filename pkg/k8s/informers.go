@@ -0,0 +1,308 @@
+package k8s
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardinformers "github.com/SAP/stewardci-core/pkg/client/informers/externalversions/steward/v1alpha1"
+	stewardLister "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	namespaceinformers "github.com/SAP/stewardci-core/pkg/k8s/namespaceinformers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PipelineRunInformers combines the PipelineRun informers of all
+// Steward informer factories of a ClientFactory, i.e. one informer
+// per watched namespace or a single cluster-wide informer.
+type PipelineRunInformers struct {
+	informers map[string]stewardinformers.PipelineRunInformer
+}
+
+// NewPipelineRunInformers returns the PipelineRun informers of the given
+// client factory.
+func NewPipelineRunInformers(factory ClientFactory) *PipelineRunInformers {
+	informers := map[string]stewardinformers.PipelineRunInformer{}
+	for namespace, informerFactory := range factory.StewardInformerFactories() {
+		informers[namespace] = informerFactory.Steward().V1alpha1().PipelineRuns()
+	}
+	return &PipelineRunInformers{informers: informers}
+}
+
+// AddEventHandler adds the given event handler to all informers.
+func (i *PipelineRunInformers) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.Informer().AddEventHandler(handler)
+	}
+}
+
+// HasSynced returns true if all informers have synced.
+func (i *PipelineRunInformers) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.Informer().HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Lister returns a lister covering all informers.
+func (i *PipelineRunInformers) Lister() stewardLister.PipelineRunLister {
+	if informer, ok := i.informers[metav1.NamespaceAll]; ok {
+		return informer.Lister()
+	}
+	listers := map[string]stewardLister.PipelineRunLister{}
+	for namespace, informer := range i.informers {
+		listers[namespace] = informer.Lister()
+	}
+	return &multiNamespacePipelineRunLister{listers: listers}
+}
+
+// multiNamespacePipelineRunLister is a PipelineRunLister dispatching to
+// namespace-restricted listers.
+type multiNamespacePipelineRunLister struct {
+	listers map[string]stewardLister.PipelineRunLister
+}
+
+// List implements interface PipelineRunLister
+func (l *multiNamespacePipelineRunLister) List(selector labels.Selector) ([]*api.PipelineRun, error) {
+	var result []*api.PipelineRun
+	for _, lister := range l.listers {
+		runs, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, runs...)
+	}
+	return result, nil
+}
+
+// PipelineRuns implements interface PipelineRunLister
+func (l *multiNamespacePipelineRunLister) PipelineRuns(namespace string) stewardLister.PipelineRunNamespaceLister {
+	if lister, ok := l.listers[namespace]; ok {
+		return lister.PipelineRuns(namespace)
+	}
+	// not a watched namespace
+	return stewardLister.NewPipelineRunLister(newEmptyIndexer()).PipelineRuns(namespace)
+}
+
+// TenantNamespaceIndex is the name of an index of tenant informers mapping
+// tenant namespace names to tenants. The former tenant namespace of a
+// relocated tenant is mapped to the tenant until it has been deleted.
+// See TenantNamespaceIndexFunc.
+const TenantNamespaceIndex = "tenantNamespace"
+
+// TenantNamespaceIndexFunc is the index function for TenantNamespaceIndex.
+func TenantNamespaceIndexFunc(obj interface{}) ([]string, error) {
+	tenant, ok := obj.(*api.Tenant)
+	if !ok || tenant.Status.TenantNamespaceName == "" {
+		return nil, nil
+	}
+	if former := tenant.Status.FormerTenantNamespaceName; former != "" {
+		// pipeline runs in the former tenant namespace of a relocated
+		// tenant still belong to the tenant
+		return []string{tenant.Status.TenantNamespaceName, former}, nil
+	}
+	return []string{tenant.Status.TenantNamespaceName}, nil
+}
+
+// TenantInformers combines the Tenant informers of all Steward informer
+// factories of a ClientFactory, i.e. one informer per watched namespace
+// or a single cluster-wide informer.
+type TenantInformers struct {
+	informers map[string]stewardinformers.TenantInformer
+}
+
+// NewTenantInformers returns the Tenant informers of the given client
+// factory.
+func NewTenantInformers(factory ClientFactory) *TenantInformers {
+	informers := map[string]stewardinformers.TenantInformer{}
+	for namespace, informerFactory := range factory.StewardInformerFactories() {
+		informers[namespace] = informerFactory.Steward().V1alpha1().Tenants()
+	}
+	return &TenantInformers{informers: informers}
+}
+
+// AddEventHandler adds the given event handler to all informers.
+func (i *TenantInformers) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.Informer().AddEventHandler(handler)
+	}
+}
+
+// HasSynced returns true if all informers have synced.
+func (i *TenantInformers) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.Informer().HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// AddIndexers adds the given indexers to all informers.
+// It must be called before the informers are started.
+func (i *TenantInformers) AddIndexers(indexers cache.Indexers) error {
+	for _, informer := range i.informers {
+		if err := informer.Informer().AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ByIndex returns the tenants of all informers whose indexed values
+// for the given index include the given value.
+func (i *TenantInformers) ByIndex(indexName, indexedValue string) ([]*api.Tenant, error) {
+	var result []*api.Tenant
+	for _, informer := range i.informers {
+		objs, err := informer.Informer().GetIndexer().ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if tenant, ok := obj.(*api.Tenant); ok {
+				result = append(result, tenant)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Lister returns a lister covering all informers.
+func (i *TenantInformers) Lister() stewardLister.TenantLister {
+	if informer, ok := i.informers[metav1.NamespaceAll]; ok {
+		return informer.Lister()
+	}
+	listers := map[string]stewardLister.TenantLister{}
+	for namespace, informer := range i.informers {
+		listers[namespace] = informer.Lister()
+	}
+	return &multiNamespaceTenantLister{listers: listers}
+}
+
+// multiNamespaceTenantLister is a TenantLister dispatching to
+// namespace-restricted listers.
+type multiNamespaceTenantLister struct {
+	listers map[string]stewardLister.TenantLister
+}
+
+// List implements interface TenantLister
+func (l *multiNamespaceTenantLister) List(selector labels.Selector) ([]*api.Tenant, error) {
+	var result []*api.Tenant
+	for _, lister := range l.listers {
+		tenants, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tenants...)
+	}
+	return result, nil
+}
+
+// Tenants implements interface TenantLister
+func (l *multiNamespaceTenantLister) Tenants(namespace string) stewardLister.TenantNamespaceLister {
+	if lister, ok := l.listers[namespace]; ok {
+		return lister.Tenants(namespace)
+	}
+	// not a watched namespace
+	return stewardLister.NewTenantLister(newEmptyIndexer()).Tenants(namespace)
+}
+
+// RunNamespaceTaskRunInformers manages Tekton TaskRun informers restricted
+// to single run namespaces. It is used in namespace-scoped mode, where the
+// run controller is not permitted to watch task runs in all namespaces but
+// only in the run namespaces it has created.
+// The keys registered for a run namespace are the keys of the pipeline
+// runs using it.
+type RunNamespaceTaskRunInformers struct {
+	*namespaceinformers.Set
+}
+
+// NewRunNamespaceTaskRunInformers returns a new RunNamespaceTaskRunInformers
+// instance adding the given event handler to each informer.
+func NewRunNamespaceTaskRunInformers(factory ClientFactory, handler cache.ResourceEventHandler) *RunNamespaceTaskRunInformers {
+	return &RunNamespaceTaskRunInformers{
+		Set: namespaceinformers.NewSet(func(namespace string, stopCh <-chan struct{}) {
+			informerFactory := factory.NewTektonInformerFactoryForNamespace(namespace)
+			informerFactory.Tekton().V1beta1().TaskRuns().Informer().AddEventHandler(handler)
+			informerFactory.Start(stopCh)
+		}),
+	}
+}
+
+func newEmptyIndexer() cache.Indexer {
+	return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// PipelineScheduleInformers combines the PipelineSchedule informers of
+// all Steward informer factories of a ClientFactory, i.e. one informer
+// per watched namespace or a single cluster-wide informer.
+type PipelineScheduleInformers struct {
+	informers map[string]stewardinformers.PipelineScheduleInformer
+}
+
+// NewPipelineScheduleInformers returns the PipelineSchedule informers of
+// the given client factory.
+func NewPipelineScheduleInformers(factory ClientFactory) *PipelineScheduleInformers {
+	informers := map[string]stewardinformers.PipelineScheduleInformer{}
+	for namespace, informerFactory := range factory.StewardInformerFactories() {
+		informers[namespace] = informerFactory.Steward().V1alpha1().PipelineSchedules()
+	}
+	return &PipelineScheduleInformers{informers: informers}
+}
+
+// AddEventHandler adds the given event handler to all informers.
+func (i *PipelineScheduleInformers) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, informer := range i.informers {
+		informer.Informer().AddEventHandler(handler)
+	}
+}
+
+// HasSynced returns true if all informers have synced.
+func (i *PipelineScheduleInformers) HasSynced() bool {
+	for _, informer := range i.informers {
+		if !informer.Informer().HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Lister returns a lister covering all informers.
+func (i *PipelineScheduleInformers) Lister() stewardLister.PipelineScheduleLister {
+	if informer, ok := i.informers[metav1.NamespaceAll]; ok {
+		return informer.Lister()
+	}
+	listers := map[string]stewardLister.PipelineScheduleLister{}
+	for namespace, informer := range i.informers {
+		listers[namespace] = informer.Lister()
+	}
+	return &multiNamespacePipelineScheduleLister{listers: listers}
+}
+
+// multiNamespacePipelineScheduleLister is a PipelineScheduleLister
+// dispatching to namespace-restricted listers.
+type multiNamespacePipelineScheduleLister struct {
+	listers map[string]stewardLister.PipelineScheduleLister
+}
+
+// List implements interface PipelineScheduleLister
+func (l *multiNamespacePipelineScheduleLister) List(selector labels.Selector) ([]*api.PipelineSchedule, error) {
+	var result []*api.PipelineSchedule
+	for _, lister := range l.listers {
+		schedules, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, schedules...)
+	}
+	return result, nil
+}
+
+// PipelineSchedules implements interface PipelineScheduleLister
+func (l *multiNamespacePipelineScheduleLister) PipelineSchedules(namespace string) stewardLister.PipelineScheduleNamespaceLister {
+	if lister, ok := l.listers[namespace]; ok {
+		return lister.PipelineSchedules(namespace)
+	}
+	// not a watched namespace
+	return stewardLister.NewPipelineScheduleLister(newEmptyIndexer()).PipelineSchedules(namespace)
+}
@@ -0,0 +1,230 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardclientfake "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/fake"
+	stewardinformers "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	"github.com/golang/mock/gomock"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newNamespacedInformerFactories(namespaces ...string) map[string]stewardinformers.SharedInformerFactory {
+	clientset := stewardclientfake.NewSimpleClientset()
+	result := map[string]stewardinformers.SharedInformerFactory{}
+	for _, namespace := range namespaces {
+		result[namespace] = stewardinformers.NewSharedInformerFactoryWithOptions(
+			clientset, 10*time.Minute, stewardinformers.WithNamespace(namespace),
+		)
+	}
+	return result
+}
+
+func Test_PipelineRunInformers_Lister_MultipleNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	informerFactories := newNamespacedInformerFactories("ns1", "ns2")
+	factory := mocks.NewMockClientFactory(mockCtrl)
+	factory.EXPECT().StewardInformerFactories().Return(informerFactories).AnyTimes()
+
+	for _, namespace := range []string{"ns1", "ns2"} {
+		run := &api.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: namespace}}
+		store := informerFactories[namespace].Steward().V1alpha1().PipelineRuns().Informer().GetStore()
+		assert.NilError(t, store.Add(run))
+	}
+
+	// EXERCISE
+	examinee := NewPipelineRunInformers(factory).Lister()
+
+	// VERIFY
+	runs, err := examinee.List(labels.Everything())
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(runs))
+
+	run, err := examinee.PipelineRuns("ns2").Get("run1")
+	assert.NilError(t, err)
+	assert.Equal(t, "ns2", run.GetNamespace())
+
+	_, err = examinee.PipelineRuns("ns3").Get("run1")
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func Test_TenantInformers_Lister_MultipleNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	informerFactories := newNamespacedInformerFactories("ns1", "ns2")
+	factory := mocks.NewMockClientFactory(mockCtrl)
+	factory.EXPECT().StewardInformerFactories().Return(informerFactories).AnyTimes()
+
+	for _, namespace := range []string{"ns1", "ns2"} {
+		tenant := &api.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant1", Namespace: namespace}}
+		store := informerFactories[namespace].Steward().V1alpha1().Tenants().Informer().GetStore()
+		assert.NilError(t, store.Add(tenant))
+	}
+
+	// EXERCISE
+	examinee := NewTenantInformers(factory).Lister()
+
+	// VERIFY
+	tenants, err := examinee.List(labels.Everything())
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(tenants))
+
+	tenant, err := examinee.Tenants("ns1").Get("tenant1")
+	assert.NilError(t, err)
+	assert.Equal(t, "ns1", tenant.GetNamespace())
+
+	_, err = examinee.Tenants("ns3").Get("tenant1")
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func Test_PipelineScheduleInformers_Lister_MultipleNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	informerFactories := newNamespacedInformerFactories("ns1", "ns2")
+	factory := mocks.NewMockClientFactory(mockCtrl)
+	factory.EXPECT().StewardInformerFactories().Return(informerFactories).AnyTimes()
+
+	for _, namespace := range []string{"ns1", "ns2"} {
+		schedule := &api.PipelineSchedule{ObjectMeta: metav1.ObjectMeta{Name: "schedule1", Namespace: namespace}}
+		store := informerFactories[namespace].Steward().V1alpha1().PipelineSchedules().Informer().GetStore()
+		assert.NilError(t, store.Add(schedule))
+	}
+
+	// EXERCISE
+	examinee := NewPipelineScheduleInformers(factory).Lister()
+
+	// VERIFY
+	schedules, err := examinee.List(labels.Everything())
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(schedules))
+
+	schedule, err := examinee.PipelineSchedules("ns2").Get("schedule1")
+	assert.NilError(t, err)
+	assert.Equal(t, "ns2", schedule.GetNamespace())
+
+	_, err = examinee.PipelineSchedules("ns3").Get("schedule1")
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func Test_TenantInformers_ByIndex_MultipleNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	informerFactories := newNamespacedInformerFactories("ns1", "ns2")
+	factory := mocks.NewMockClientFactory(mockCtrl)
+	factory.EXPECT().StewardInformerFactories().Return(informerFactories).AnyTimes()
+	examinee := NewTenantInformers(factory)
+	err := examinee.AddIndexers(cache.Indexers{
+		"tenantNamespace": func(obj interface{}) ([]string, error) {
+			return []string{obj.(*api.Tenant).Status.TenantNamespaceName}, nil
+		},
+	})
+	assert.NilError(t, err)
+
+	for _, namespace := range []string{"ns1", "ns2"} {
+		tenant := &api.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant1", Namespace: namespace}}
+		tenant.Status.TenantNamespaceName = "tenant-ns-" + namespace
+		store := informerFactories[namespace].Steward().V1alpha1().Tenants().Informer().GetStore()
+		assert.NilError(t, store.Add(tenant))
+	}
+
+	// EXERCISE
+	tenants, err := examinee.ByIndex("tenantNamespace", "tenant-ns-ns2")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(tenants))
+	assert.Equal(t, "ns2", tenants[0].GetNamespace())
+}
+
+func Test_PipelineRunInformers_Lister_ClusterWide(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	informerFactories := map[string]stewardinformers.SharedInformerFactory{
+		metav1.NamespaceAll: stewardinformers.NewSharedInformerFactory(stewardclientfake.NewSimpleClientset(), 10*time.Minute),
+	}
+	factory := mocks.NewMockClientFactory(mockCtrl)
+	factory.EXPECT().StewardInformerFactories().Return(informerFactories).AnyTimes()
+	store := informerFactories[metav1.NamespaceAll].Steward().V1alpha1().PipelineRuns().Informer().GetStore()
+	run := &api.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: "ns1"}}
+	assert.NilError(t, store.Add(run))
+
+	// EXERCISE
+	examinee := NewPipelineRunInformers(factory).Lister()
+
+	// VERIFY
+	result, err := examinee.PipelineRuns("ns1").Get("run1")
+	assert.NilError(t, err)
+	assert.Equal(t, run, result)
+}
+
+func Test_RunNamespaceTaskRunInformers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	added := make(chan string, 10)
+	examinee := NewRunNamespaceTaskRunInformers(cf, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			added <- obj.(*tektonv1beta1.TaskRun).GetNamespace()
+		},
+	})
+	defer examinee.UnwatchAll()
+
+	// EXERCISE
+	examinee.Watch("ns1", "tenant1/run1")
+	examinee.Watch("ns1", "tenant1/run1")
+	examinee.Watch("ns2", "tenant1/run2")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, examinee.Namespaces())
+
+	for _, namespace := range []string{"ns3", "ns1"} {
+		taskRun := &tektonv1beta1.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "taskrun1", Namespace: namespace}}
+		_, err := cf.TektonV1beta1().TaskRuns(namespace).Create(ctx, taskRun, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	select {
+	case namespace := <-added:
+		assert.Equal(t, "ns1", namespace)
+	case <-time.After(10 * time.Second):
+		t.Fatal("task run in watched namespace not observed")
+	}
+
+	// EXERCISE
+	examinee.Unwatch("tenant1/run1")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns2"}, examinee.Namespaces())
+}
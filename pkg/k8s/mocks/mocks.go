@@ -32,16 +32,20 @@ import (
 	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	v1alpha10 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	externalversions "github.com/SAP/stewardci-core/pkg/client/informers/externalversions"
+	namespacemetadata "github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
 	v1beta1 "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/typed/pipeline/v1beta1"
 	externalversions0 "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	dynamic "k8s.io/client-go/dynamic"
+	v15 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	v14 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	v11 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v12 "k8s.io/client-go/kubernetes/typed/networking/v1"
 	v13 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	reflect "reflect"
+	time "time"
 )
 
 // MockClientFactory is a mock of ClientFactory interface
@@ -67,6 +71,34 @@ func (m *MockClientFactory) EXPECT() *MockClientFactoryMockRecorder {
 	return m.recorder
 }
 
+// AppsV1 mocks base method
+func (m *MockClientFactory) AppsV1() v15.AppsV1Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppsV1")
+	ret0, _ := ret[0].(v15.AppsV1Interface)
+	return ret0
+}
+
+// AppsV1 indicates an expected call of AppsV1
+func (mr *MockClientFactoryMockRecorder) AppsV1() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppsV1", reflect.TypeOf((*MockClientFactory)(nil).AppsV1))
+}
+
+// CoordinationV1 mocks base method
+func (m *MockClientFactory) CoordinationV1() v14.CoordinationV1Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CoordinationV1")
+	ret0, _ := ret[0].(v14.CoordinationV1Interface)
+	return ret0
+}
+
+// CoordinationV1 indicates an expected call of CoordinationV1
+func (mr *MockClientFactoryMockRecorder) CoordinationV1() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CoordinationV1", reflect.TypeOf((*MockClientFactory)(nil).CoordinationV1))
+}
+
 // CoreV1 mocks base method
 func (m *MockClientFactory) CoreV1() v11.CoreV1Interface {
 	m.ctrl.T.Helper()
@@ -95,6 +127,34 @@ func (mr *MockClientFactoryMockRecorder) Dynamic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dynamic", reflect.TypeOf((*MockClientFactory)(nil).Dynamic))
 }
 
+// InstanceID mocks base method
+func (m *MockClientFactory) InstanceID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstanceID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// InstanceID indicates an expected call of InstanceID
+func (mr *MockClientFactoryMockRecorder) InstanceID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstanceID", reflect.TypeOf((*MockClientFactory)(nil).InstanceID))
+}
+
+// NamespaceMetadata mocks base method
+func (m *MockClientFactory) NamespaceMetadata() *namespacemetadata.Metadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NamespaceMetadata")
+	ret0, _ := ret[0].(*namespacemetadata.Metadata)
+	return ret0
+}
+
+// NamespaceMetadata indicates an expected call of NamespaceMetadata
+func (mr *MockClientFactoryMockRecorder) NamespaceMetadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NamespaceMetadata", reflect.TypeOf((*MockClientFactory)(nil).NamespaceMetadata))
+}
+
 // NetworkingV1 mocks base method
 func (m *MockClientFactory) NetworkingV1() v12.NetworkingV1Interface {
 	m.ctrl.T.Helper()
@@ -109,6 +169,20 @@ func (mr *MockClientFactoryMockRecorder) NetworkingV1() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkingV1", reflect.TypeOf((*MockClientFactory)(nil).NetworkingV1))
 }
 
+// NewTektonInformerFactoryForNamespace mocks base method
+func (m *MockClientFactory) NewTektonInformerFactoryForNamespace(arg0 string) externalversions0.SharedInformerFactory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewTektonInformerFactoryForNamespace", arg0)
+	ret0, _ := ret[0].(externalversions0.SharedInformerFactory)
+	return ret0
+}
+
+// NewTektonInformerFactoryForNamespace indicates an expected call of NewTektonInformerFactoryForNamespace
+func (mr *MockClientFactoryMockRecorder) NewTektonInformerFactoryForNamespace(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewTektonInformerFactoryForNamespace", reflect.TypeOf((*MockClientFactory)(nil).NewTektonInformerFactoryForNamespace), arg0)
+}
+
 // RbacV1 mocks base method
 func (m *MockClientFactory) RbacV1() v13.RbacV1Interface {
 	m.ctrl.T.Helper()
@@ -123,6 +197,20 @@ func (mr *MockClientFactoryMockRecorder) RbacV1() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RbacV1", reflect.TypeOf((*MockClientFactory)(nil).RbacV1))
 }
 
+// StewardInformerFactories mocks base method
+func (m *MockClientFactory) StewardInformerFactories() map[string]externalversions.SharedInformerFactory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StewardInformerFactories")
+	ret0, _ := ret[0].(map[string]externalversions.SharedInformerFactory)
+	return ret0
+}
+
+// StewardInformerFactories indicates an expected call of StewardInformerFactories
+func (mr *MockClientFactoryMockRecorder) StewardInformerFactories() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StewardInformerFactories", reflect.TypeOf((*MockClientFactory)(nil).StewardInformerFactories))
+}
+
 // StewardInformerFactory mocks base method
 func (m *MockClientFactory) StewardInformerFactory() externalversions.SharedInformerFactory {
 	m.ctrl.T.Helper()
@@ -179,6 +267,20 @@ func (mr *MockClientFactoryMockRecorder) TektonV1beta1() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TektonV1beta1", reflect.TypeOf((*MockClientFactory)(nil).TektonV1beta1))
 }
 
+// WatchNamespaces mocks base method
+func (m *MockClientFactory) WatchNamespaces() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchNamespaces")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// WatchNamespaces indicates an expected call of WatchNamespaces
+func (mr *MockClientFactoryMockRecorder) WatchNamespaces() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchNamespaces", reflect.TypeOf((*MockClientFactory)(nil).WatchNamespaces))
+}
+
 // MockNamespaceManager is a mock of NamespaceManager interface
 type MockNamespaceManager struct {
 	ctrl     *gomock.Controller
@@ -202,6 +304,20 @@ func (m *MockNamespaceManager) EXPECT() *MockNamespaceManagerMockRecorder {
 	return m.recorder
 }
 
+// Adopt mocks base method
+func (m *MockNamespaceManager) Adopt(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adopt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adopt indicates an expected call of Adopt
+func (mr *MockNamespaceManagerMockRecorder) Adopt(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adopt", reflect.TypeOf((*MockNamespaceManager)(nil).Adopt), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *MockNamespaceManager) Create(arg0 context.Context, arg1 string, arg2 map[string]string) (string, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +333,20 @@ func (mr *MockNamespaceManagerMockRecorder) Create(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNamespaceManager)(nil).Create), arg0, arg1, arg2)
 }
 
+// CreateWithName mocks base method
+func (m *MockNamespaceManager) CreateWithName(arg0 context.Context, arg1, arg2 string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithName", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWithName indicates an expected call of CreateWithName
+func (mr *MockNamespaceManagerMockRecorder) CreateWithName(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithName", reflect.TypeOf((*MockNamespaceManager)(nil).CreateWithName), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method
 func (m *MockNamespaceManager) Delete(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -231,6 +361,21 @@ func (mr *MockNamespaceManagerMockRecorder) Delete(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockNamespaceManager)(nil).Delete), arg0, arg1)
 }
 
+// PlanName mocks base method
+func (m *MockNamespaceManager) PlanName(arg0 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanName", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanName indicates an expected call of PlanName
+func (mr *MockNamespaceManagerMockRecorder) PlanName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanName", reflect.TypeOf((*MockNamespaceManager)(nil).PlanName), arg0)
+}
+
 // MockPipelineRun is a mock of PipelineRun interface
 type MockPipelineRun struct {
 	ctrl     *gomock.Controller
@@ -268,6 +413,18 @@ func (mr *MockPipelineRunMockRecorder) AddFinalizer(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFinalizer", reflect.TypeOf((*MockPipelineRun)(nil).AddFinalizer), arg0)
 }
 
+// AddWarning mocks base method
+func (m *MockPipelineRun) AddWarning(arg0, arg1 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddWarning", arg0, arg1)
+}
+
+// AddWarning indicates an expected call of AddWarning
+func (mr *MockPipelineRunMockRecorder) AddWarning(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWarning", reflect.TypeOf((*MockPipelineRun)(nil).AddWarning), arg0, arg1)
+}
+
 // CommitStatus mocks base method
 func (m *MockPipelineRun) CommitStatus(arg0 context.Context) ([]*v1alpha1.StateItem, error) {
 	m.ctrl.T.Helper()
@@ -480,6 +637,18 @@ func (mr *MockPipelineRunMockRecorder) String() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "String", reflect.TypeOf((*MockPipelineRun)(nil).String))
 }
 
+// UpdateArtifacts mocks base method
+func (m *MockPipelineRun) UpdateArtifacts(arg0 *v1alpha1.ArtifactsStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateArtifacts", arg0)
+}
+
+// UpdateArtifacts indicates an expected call of UpdateArtifacts
+func (mr *MockPipelineRunMockRecorder) UpdateArtifacts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateArtifacts", reflect.TypeOf((*MockPipelineRun)(nil).UpdateArtifacts), arg0)
+}
+
 // UpdateAuxNamespace mocks base method
 func (m *MockPipelineRun) UpdateAuxNamespace(arg0 string) {
 	m.ctrl.T.Helper()
@@ -516,6 +685,30 @@ func (mr *MockPipelineRunMockRecorder) UpdateMessage(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockPipelineRun)(nil).UpdateMessage), arg0)
 }
 
+// UpdateProgress mocks base method
+func (m *MockPipelineRun) UpdateProgress(arg0 *v1alpha1.Progress) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateProgress", arg0)
+}
+
+// UpdateProgress indicates an expected call of UpdateProgress
+func (mr *MockPipelineRunMockRecorder) UpdateProgress(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProgress", reflect.TypeOf((*MockPipelineRun)(nil).UpdateProgress), arg0)
+}
+
+// UpdateResolvedRevision mocks base method
+func (m *MockPipelineRun) UpdateResolvedRevision(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateResolvedRevision", arg0)
+}
+
+// UpdateResolvedRevision indicates an expected call of UpdateResolvedRevision
+func (mr *MockPipelineRunMockRecorder) UpdateResolvedRevision(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResolvedRevision", reflect.TypeOf((*MockPipelineRun)(nil).UpdateResolvedRevision), arg0)
+}
+
 // UpdateResult mocks base method
 func (m *MockPipelineRun) UpdateResult(arg0 v1alpha1.Result, arg1 v10.Time) {
 	m.ctrl.T.Helper()
@@ -540,6 +733,18 @@ func (mr *MockPipelineRunMockRecorder) UpdateRunNamespace(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRunNamespace", reflect.TypeOf((*MockPipelineRun)(nil).UpdateRunNamespace), arg0)
 }
 
+// UpdateStages mocks base method
+func (m *MockPipelineRun) UpdateStages(arg0 []v1alpha1.StageSummary) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateStages", arg0)
+}
+
+// UpdateStages indicates an expected call of UpdateStages
+func (mr *MockPipelineRunMockRecorder) UpdateStages(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStages", reflect.TypeOf((*MockPipelineRun)(nil).UpdateStages), arg0)
+}
+
 // UpdateState mocks base method
 func (m *MockPipelineRun) UpdateState(arg0 v1alpha1.State, arg1 v10.Time) error {
 	m.ctrl.T.Helper()
@@ -554,6 +759,90 @@ func (mr *MockPipelineRunMockRecorder) UpdateState(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateState", reflect.TypeOf((*MockPipelineRun)(nil).UpdateState), arg0, arg1)
 }
 
+// UpdateTestResults mocks base method
+func (m *MockPipelineRun) UpdateTestResults(arg0 *v1alpha1.TestResults) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateTestResults", arg0)
+}
+
+// UpdateTestResults indicates an expected call of UpdateTestResults
+func (mr *MockPipelineRunMockRecorder) UpdateTestResults(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTestResults", reflect.TypeOf((*MockPipelineRun)(nil).UpdateTestResults), arg0)
+}
+
+// UpdateTimelineExported mocks base method
+func (m *MockPipelineRun) UpdateTimelineExported(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateTimelineExported", arg0)
+}
+
+// UpdateTimelineExported indicates an expected call of UpdateTimelineExported
+func (mr *MockPipelineRunMockRecorder) UpdateTimelineExported(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTimelineExported", reflect.TypeOf((*MockPipelineRun)(nil).UpdateTimelineExported), arg0)
+}
+
+// UpdateEstimatedDuration mocks base method
+func (m *MockPipelineRun) UpdateEstimatedDuration(arg0 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateEstimatedDuration", arg0)
+}
+
+// UpdateEstimatedDuration indicates an expected call of UpdateEstimatedDuration
+func (mr *MockPipelineRunMockRecorder) UpdateEstimatedDuration(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstimatedDuration", reflect.TypeOf((*MockPipelineRun)(nil).UpdateEstimatedDuration), arg0)
+}
+
+// UpdateLogURL mocks base method
+func (m *MockPipelineRun) UpdateLogURL(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateLogURL", arg0)
+}
+
+// UpdateLogURL indicates an expected call of UpdateLogURL
+func (mr *MockPipelineRunMockRecorder) UpdateLogURL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogURL", reflect.TypeOf((*MockPipelineRun)(nil).UpdateLogURL), arg0)
+}
+
+// OffloadStatus mocks base method
+func (m *MockPipelineRun) OffloadStatus(arg0 *v1alpha1.OffloadedStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OffloadStatus", arg0)
+}
+
+// OffloadStatus indicates an expected call of OffloadStatus
+func (mr *MockPipelineRunMockRecorder) OffloadStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffloadStatus", reflect.TypeOf((*MockPipelineRun)(nil).OffloadStatus), arg0)
+}
+
+// UpdateAbortHandlers mocks base method
+func (m *MockPipelineRun) UpdateAbortHandlers(arg0 []v1alpha1.AbortHandlerStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateAbortHandlers", arg0)
+}
+
+// UpdateAbortHandlers indicates an expected call of UpdateAbortHandlers
+func (mr *MockPipelineRunMockRecorder) UpdateAbortHandlers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAbortHandlers", reflect.TypeOf((*MockPipelineRun)(nil).UpdateAbortHandlers), arg0)
+}
+
+// UpdateCondition mocks base method
+func (m *MockPipelineRun) UpdateCondition(arg0 v10.Condition) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCondition", arg0)
+}
+
+// UpdateCondition indicates an expected call of UpdateCondition
+func (mr *MockPipelineRunMockRecorder) UpdateCondition(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCondition", reflect.TypeOf((*MockPipelineRun)(nil).UpdateCondition), arg0)
+}
+
 // MockPipelineRunFetcher is a mock of PipelineRunFetcher interface
 type MockPipelineRunFetcher struct {
 	ctrl     *gomock.Controller
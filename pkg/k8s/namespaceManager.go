@@ -4,6 +4,8 @@ import (
 	"context"
 	"strings"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -11,19 +13,27 @@ import (
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	klog "k8s.io/klog/v2"
 
+	namespacemetadata "github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	stewardlabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
 	utils "github.com/SAP/stewardci-core/pkg/utils"
+	retry "github.com/SAP/stewardci-core/pkg/utils/retry"
 )
 
 //NamespaceManager manages namespaces
 type NamespaceManager interface {
 	Create(ctx context.Context, name string, annotations map[string]string) (string, error)
+	PlanName(nameCustomPart string) (string, error)
+	CreateWithName(ctx context.Context, name string, nameCustomPart string, annotations map[string]string) error
 	Delete(ctx context.Context, name string) error
+	Adopt(ctx context.Context, name string, nameCustomPart string) error
 }
 
 type namespaceManager struct {
 	nsInterface  corev1.NamespaceInterface
 	prefix       string
 	suffixLength uint8
+	instanceID   string
+	metadata     *namespacemetadata.Metadata
 }
 
 // NewNamespaceManager creates a new NamespaceManager.
@@ -32,6 +42,8 @@ func NewNamespaceManager(factory ClientFactory, prefix string, suffixLength uint
 		nsInterface:  factory.CoreV1().Namespaces(),
 		prefix:       prefix,
 		suffixLength: suffixLength,
+		instanceID:   factory.InstanceID(),
+		metadata:     factory.NamespaceMetadata(),
 	}
 }
 
@@ -44,11 +56,34 @@ const (
 //    nameCustomPart	the namespace name will be <prefix>-<nameCustomPart>-<random>
 //    annotations       annotations to create on the namespace
 func (m *namespaceManager) Create(ctx context.Context, nameCustomPart string, annotations map[string]string) (string, error) {
+	name, err := m.PlanName(nameCustomPart)
+	if err != nil {
+		return "", err
+	}
+	if err := m.CreateWithName(ctx, name, nameCustomPart, annotations); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+//PlanName returns a new namespace name as used by Create without
+//creating the namespace. The namespace can be created later via
+//CreateWithName.
+//    nameCustomPart	the namespace name will be <prefix>-<nameCustomPart>-<random>
+func (m *namespaceManager) PlanName(nameCustomPart string) (string, error) {
 	name, err := m.generateName(nameCustomPart)
 	if err != nil {
 		klog.V(2).Infof("Namespace creation failed %s", err)
 		return "", err
 	}
+	return name, nil
+}
+
+//CreateWithName creates a new namespace with a name returned by PlanName.
+//    name              the name of the namespace
+//    nameCustomPart    the custom part the name has been planned with
+//    annotations       annotations to create on the namespace
+func (m *namespaceManager) CreateWithName(ctx context.Context, name string, nameCustomPart string, annotations map[string]string) error {
 	meta := metav1.ObjectMeta{
 		Name: name,
 		Labels: map[string]string{
@@ -59,13 +94,42 @@ func (m *namespaceManager) Create(ctx context.Context, nameCustomPart string, an
 	}
 
 	namespace := &v1.Namespace{ObjectMeta: meta}
+	stewardlabels.LabelWithInstanceID(namespace, m.instanceID)
+	if err := m.metadata.Apply(namespace); err != nil {
+		klog.V(2).Infof("Namespace creation failed: %s", err)
+		return err
+	}
 	createdNamespace, err := m.nsInterface.Create(ctx, namespace, metav1.CreateOptions{})
 	if err != nil {
 		klog.V(2).Infof("Namespace creation failed: %s", err)
-		return "", err
+		return serrors.MarkIfQuotaExceeded(err)
 	}
 	klog.V(2).Infof("Namespace '%s' created", createdNamespace.GetName())
-	return createdNamespace.GetName(), nil
+	return nil
+}
+
+//Adopt checks whether an existing namespace can be adopted.
+//    name              the name of the existing namespace
+//    nameCustomPart    the custom part of the name the namespace must have been created with
+// The namespace must have been created by a namespace manager with the same
+// prefix and instance ID and must not be marked as deleted.
+func (m *namespaceManager) Adopt(ctx context.Context, name string, nameCustomPart string) error {
+	namespace, err := m.nsInterface.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "error getting namespace '%s'", name)
+	}
+	if !namespace.GetDeletionTimestamp().IsZero() {
+		return errors.Errorf("refused to adopt namespace '%s': namespace is marked as deleted", name)
+	}
+	labels := namespace.GetLabels()
+	if labels[labelPrefix] != m.prefix || labels[labelID] != nameCustomPart {
+		return errors.Errorf("refused to adopt namespace '%s': not a Steward namespace for '%s' (label mismatch)", name, nameCustomPart)
+	}
+	if labels[api.LabelInstanceID] != m.instanceID {
+		return errors.Errorf("refused to adopt namespace '%s': belongs to another Steward instance", name)
+	}
+	klog.V(2).Infof("Namespace '%s' can be adopted", name)
+	return nil
 }
 
 // Delete removes a namespace if existing
@@ -85,8 +149,10 @@ func (m *namespaceManager) Delete(ctx context.Context, name string) error {
 		return errors.Errorf("refused to delete namespace '%s': not a Steward namespace (label mismatch)", name)
 	}
 	uid := namespace.GetObjectMeta().GetUID()
-	err = m.nsInterface.Delete(ctx, name, metav1.DeleteOptions{
-		Preconditions: &metav1.Preconditions{UID: &uid},
+	err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		return m.nsInterface.Delete(ctx, name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid},
+		})
 	})
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -99,19 +165,30 @@ func (m *namespaceManager) Delete(ctx context.Context, name string) error {
 }
 
 func (m *namespaceManager) generateName(customPart string) (string, error) {
-	parts := []string{}
-	if m.prefix != "" {
-		parts = append(parts, m.prefix)
-	}
-	if customPart != "" {
-		parts = append(parts, customPart)
-	}
 	suffix, err := utils.RandomAlphaNumString(int64(m.suffixLength))
 	if err != nil {
 		return "", err
 	}
-	if suffix != "" {
-		parts = append(parts, suffix)
+	return JoinNamespaceNameParts(m.prefix, customPart, suffix), nil
+}
+
+// JoinNamespaceNameParts returns the namespace name composed of the given
+// prefix, custom part and suffix as done by NamespaceManager.Create.
+// Empty parts are skipped.
+func JoinNamespaceNameParts(prefix, customPart, suffix string) string {
+	parts := []string{}
+	for _, part := range []string{prefix, customPart, suffix} {
+		if part != "" {
+			parts = append(parts, part)
+		}
 	}
-	return strings.Join(parts, "-"), nil
+	return strings.Join(parts, "-")
+}
+
+// NamespacePrefix returns the prefix the given namespace has been created
+// with by a NamespaceManager, or an empty string if the namespace has not
+// been created by a NamespaceManager.
+// A NamespaceManager with this prefix is required to delete the namespace.
+func NamespacePrefix(namespace *v1.Namespace) string {
+	return namespace.GetLabels()[labelPrefix]
 }
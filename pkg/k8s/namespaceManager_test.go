@@ -5,10 +5,15 @@ import (
 	"strconv"
 	"testing"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -91,6 +96,47 @@ func Test_namespaceManager_Create_uses_generateName(t *testing.T) {
 	assert.Assert(t, is.Regexp("^prefix1-customPart1-[0-9a-z]{17}$", result))
 }
 
+func Test_namespaceManager_PlanName_DoesNotCreateNamespace(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	examinee := NewNamespaceManager(cf, "prefix1", 5)
+
+	// EXERCISE
+	result, err := examinee.PlanName("customPart1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, is.Regexp("^prefix1-customPart1-[0-9a-z]{5}$", result))
+	namespaceList, err := listNamespaces(ctx, cf)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(namespaceList.Items))
+}
+
+func Test_namespaceManager_CreateWithName(t *testing.T) {
+	// SETUP
+	const namespaceName = "prefix1-customPart1-planned"
+
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	examinee := NewNamespaceManager(cf, "prefix1", 5)
+
+	// EXERCISE
+	err := examinee.CreateWithName(ctx, namespaceName, "customPart1", nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		labelPrefix: "prefix1",
+		labelID:     "customPart1",
+	}, namespace.GetLabels())
+
+	// the namespace can be adopted by the same manager
+	assert.NilError(t, examinee.Adopt(ctx, namespaceName, "customPart1"))
+}
+
 func Test_namespaceManager_Create_Success(t *testing.T) {
 	// SETUP
 	const namespaceName = "namespace1"
@@ -121,6 +167,66 @@ func Test_namespaceManager_Create_Success(t *testing.T) {
 	assert.DeepEqual(t, annotations, namespace.GetObjectMeta().GetAnnotations())
 }
 
+func Test_namespaceManager_Create_SetsInstanceIDLabel(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	cf.SetInstanceID("instance1")
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	namespaceName, err := examinee.Create(ctx, "foo", nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "instance1", namespace.GetLabels()[api.LabelInstanceID])
+}
+
+func Test_namespaceManager_Create_SetsNamespaceMetadata(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	cf.SetNamespaceMetadata(&namespacemetadata.Metadata{
+		Labels:              map[string]string{"example.com/cost-center": "cc1", labelID: "other"},
+		Annotations:         map[string]string{"example.com/classification": "internal"},
+		RequiredAnnotations: []string{"example.com/classification"},
+	})
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	namespaceName, err := examinee.Create(ctx, "foo", nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "cc1", namespace.GetLabels()["example.com/cost-center"])
+	assert.Equal(t, "foo", namespace.GetLabels()[labelID])
+	assert.Equal(t, "internal", namespace.GetAnnotations()["example.com/classification"])
+}
+
+func Test_namespaceManager_Create_FailsIfRequiredMetadataMissing(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	cf.SetNamespaceMetadata(&namespacemetadata.Metadata{
+		RequiredLabels: []string{"example.com/cost-center"},
+	})
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	result, err := examinee.Create(ctx, "foo", nil)
+
+	// VERIFY
+	assert.Error(t, err, `required label "example.com/cost-center" is missing on namespace to be created`)
+	assert.Equal(t, "", result)
+	namespaces, err := cf.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(namespaces.Items))
+}
+
 func Test_namespaceManager_Create_ExistsAlready(t *testing.T) {
 	// SETUP
 	const namespaceName = "namespace1"
@@ -139,6 +245,22 @@ func Test_namespaceManager_Create_ExistsAlready(t *testing.T) {
 	assert.Equal(t, "", result)
 }
 
+func Test_namespaceManager_Create_QuotaExceeded(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	quotaErr := k8serrors.NewForbidden(corev1.Resource("namespaces"), "", errors.New("exceeded quota: quota1"))
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", fake.NewErrorReactor(quotaErr))
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	result, err := examinee.Create(ctx, "foo", nil)
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, serrors.ErrQuotaExceeded))
+	assert.Equal(t, "", result)
+}
+
 func Test_namespaceManager_Delete_Success(t *testing.T) {
 	// SETUP
 	const namespaceName = "namespace1"
@@ -223,3 +345,78 @@ func countNamespaces(ctx context.Context, factory ClientFactory) int {
 	}
 	return len(namespace.Items)
 }
+
+func Test_namespaceManager_Adopt(t *testing.T) {
+	now := metav1.Now()
+	for _, tc := range []struct {
+		name          string
+		labels        map[string]string
+		deleted       bool
+		expectedError string
+	}{
+		{"good case", map[string]string{labelPrefix: "prefix1", labelID: "custom1"}, false, ""},
+		{"prefix mismatch", map[string]string{labelPrefix: "prefix2", labelID: "custom1"}, false,
+			"refused to adopt namespace 'ns1': not a Steward namespace for 'custom1' (label mismatch)"},
+		{"id mismatch", map[string]string{labelPrefix: "prefix1", labelID: "custom2"}, false,
+			"refused to adopt namespace 'ns1': not a Steward namespace for 'custom1' (label mismatch)"},
+		{"no labels", nil, false,
+			"refused to adopt namespace 'ns1': not a Steward namespace for 'custom1' (label mismatch)"},
+		{"other instance", map[string]string{labelPrefix: "prefix1", labelID: "custom1", api.LabelInstanceID: "other"}, false,
+			"refused to adopt namespace 'ns1': belongs to another Steward instance"},
+		{"deleted", map[string]string{labelPrefix: "prefix1", labelID: "custom1"}, true,
+			"refused to adopt namespace 'ns1': namespace is marked as deleted"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			namespace := fake.Namespace("ns1")
+			namespace.SetLabels(tc.labels)
+			if tc.deleted {
+				namespace.SetDeletionTimestamp(&now)
+			}
+			cf := fake.NewClientFactory(namespace)
+			examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+			// EXERCISE
+			err := examinee.Adopt(ctx, "ns1", "custom1")
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_namespaceManager_Adopt_NotFound(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	examinee := NewNamespaceManager(cf, "prefix1", 0)
+
+	// EXERCISE
+	err := examinee.Adopt(ctx, "ns1", "custom1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, "error getting namespace 'ns1'")
+}
+
+func Test_NamespacePrefix(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	name, err := NewNamespaceManager(cf, "prefix1", 0).Create(ctx, "custom1", nil)
+	assert.NilError(t, err)
+	namespace, err := cf.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result := NamespacePrefix(namespace)
+
+	// VERIFY
+	assert.Equal(t, "prefix1", result)
+	assert.Equal(t, "", NamespacePrefix(fake.Namespace("other1")))
+}
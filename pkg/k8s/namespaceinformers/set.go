@@ -0,0 +1,114 @@
+// Package namespaceinformers manages informers restricted to single
+// namespaces which run only as long as they are needed.
+package namespaceinformers
+
+import (
+	"sort"
+	"sync"
+)
+
+// StartFunc starts informers for the given namespace which run until the
+// given channel gets closed.
+type StartFunc func(namespace string, stopCh <-chan struct{})
+
+// Set runs informers per namespace on behalf of watchers identified by
+// keys, e.g. the keys of pipeline runs. The informers of a namespace are
+// running as long as at least one key is registered for it.
+type Set struct {
+	start StartFunc
+
+	mutex      sync.Mutex
+	keys       map[string]string
+	namespaces map[string]*watchedNamespace
+}
+
+type watchedNamespace struct {
+	stopCh chan struct{}
+	keys   map[string]struct{}
+}
+
+// NewSet returns a new Set starting the informers of a namespace with
+// the given function.
+func NewSet(start StartFunc) *Set {
+	return &Set{
+		start:      start,
+		keys:       map[string]string{},
+		namespaces: map[string]*watchedNamespace{},
+	}
+}
+
+// Watch registers the given key for the given namespace and starts the
+// informers for the namespace if they are not running already.
+// A key can be registered for a single namespace only. If it is
+// registered for another namespace already, it is unregistered from
+// there.
+func (s *Set) Watch(namespace, key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current, ok := s.keys[key]; ok {
+		if current == namespace {
+			return
+		}
+		s.unwatch(key)
+	}
+	ns, ok := s.namespaces[namespace]
+	if !ok {
+		ns = &watchedNamespace{
+			stopCh: make(chan struct{}),
+			keys:   map[string]struct{}{},
+		}
+		s.start(namespace, ns.stopCh)
+		s.namespaces[namespace] = ns
+	}
+	ns.keys[key] = struct{}{}
+	s.keys[key] = namespace
+}
+
+// Unwatch unregisters the given key and stops the informers for its
+// namespace if no other key is registered for it.
+func (s *Set) Unwatch(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unwatch(key)
+}
+
+func (s *Set) unwatch(key string) {
+	namespace, ok := s.keys[key]
+	if !ok {
+		return
+	}
+	delete(s.keys, key)
+	ns := s.namespaces[namespace]
+	delete(ns.keys, key)
+	if len(ns.keys) == 0 {
+		close(ns.stopCh)
+		delete(s.namespaces, namespace)
+	}
+}
+
+// UnwatchAll unregisters all keys and stops all informers.
+func (s *Set) UnwatchAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for namespace, ns := range s.namespaces {
+		close(ns.stopCh)
+		delete(s.namespaces, namespace)
+	}
+	s.keys = map[string]string{}
+}
+
+// Namespaces returns the sorted list of namespaces currently watched.
+func (s *Set) Namespaces() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]string, 0, len(s.namespaces))
+	for namespace := range s.namespaces {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,62 @@
+package namespaceinformers
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Set_Watch(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	running := map[string]<-chan struct{}{}
+	isRunning := func(namespace string) bool {
+		stopCh, ok := running[namespace]
+		if !ok {
+			return false
+		}
+		select {
+		case <-stopCh:
+			return false
+		default:
+			return true
+		}
+	}
+	examinee := NewSet(func(namespace string, stopCh <-chan struct{}) {
+		assert.Assert(t, !isRunning(namespace), "informers of namespace %q started twice", namespace)
+		running[namespace] = stopCh
+	})
+	defer examinee.UnwatchAll()
+
+	// EXERCISE
+	examinee.Watch("ns1", "ns1/run1")
+	examinee.Watch("ns1", "ns1/run2")
+	examinee.Watch("ns2", "ns2/run1")
+	examinee.Watch("ns2", "ns2/run1")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, examinee.Namespaces())
+	assert.Assert(t, isRunning("ns1"))
+	assert.Assert(t, isRunning("ns2"))
+
+	// EXERCISE + VERIFY
+	examinee.Unwatch("ns1/run1")
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, examinee.Namespaces())
+	assert.Assert(t, isRunning("ns1"))
+	examinee.Unwatch("ns1/run2")
+	assert.DeepEqual(t, []string{"ns2"}, examinee.Namespaces())
+	assert.Assert(t, !isRunning("ns1"))
+	examinee.Unwatch("unknown")
+	assert.DeepEqual(t, []string{"ns2"}, examinee.Namespaces())
+
+	// a key registered for another namespace is moved
+	examinee.Watch("ns3", "ns2/run1")
+	assert.DeepEqual(t, []string{"ns3"}, examinee.Namespaces())
+	assert.Assert(t, !isRunning("ns2"))
+	assert.Assert(t, isRunning("ns3"))
+
+	examinee.UnwatchAll()
+	assert.DeepEqual(t, []string{}, examinee.Namespaces())
+	assert.Assert(t, !isRunning("ns3"))
+}
@@ -0,0 +1,150 @@
+// Package namespacemetadata provides the labels and annotations the
+// Steward controllers set on all namespaces they create.
+package namespacemetadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Metadata defines labels and annotations the Steward controllers set on
+// all namespaces they create, e.g. a cost center or a data
+// classification required by enterprise governance.
+// A nil value is valid and does not define any metadata.
+type Metadata struct {
+	// Labels are set on created namespaces.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on created namespaces.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// RequiredLabels are the keys of labels that must be set with a
+	// non-empty value on created namespaces.
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+
+	// RequiredAnnotations are the keys of annotations that must be set
+	// with a non-empty value on created namespaces.
+	RequiredAnnotations []string `json:"requiredAnnotations,omitempty"`
+}
+
+// Parse parses the given JSON representation of a Metadata and validates
+// the result. An empty string results in nil.
+func Parse(data string) (*Metadata, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.DisallowUnknownFields()
+	result := &Metadata{}
+	if err := decoder.Decode(result); err != nil {
+		return nil, errors.WithMessage(err, "invalid namespace metadata")
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Validate checks that the keys and values of the labels and annotations
+// are valid and that all required labels and annotations are defined with
+// non-empty values. Keys of the Steward API group are reserved.
+func (m *Metadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+	for _, key := range sortedKeys(m.Labels) {
+		if err := validateMetadataKey("label", key); err != nil {
+			return err
+		}
+		if errs := validation.IsValidLabelValue(m.Labels[key]); len(errs) > 0 {
+			return fmt.Errorf("invalid value of namespace label %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	for _, key := range sortedKeys(m.Annotations) {
+		if err := validateMetadataKey("annotation", key); err != nil {
+			return err
+		}
+	}
+	for _, key := range m.RequiredLabels {
+		if m.Labels[key] == "" {
+			return fmt.Errorf("required namespace label %q is not defined", key)
+		}
+	}
+	for _, key := range m.RequiredAnnotations {
+		if m.Annotations[key] == "" {
+			return fmt.Errorf("required namespace annotation %q is not defined", key)
+		}
+	}
+	return nil
+}
+
+// Apply sets the labels and annotations on the given namespace, which is
+// about to be created. Labels and annotations already set on the
+// namespace are retained.
+// It fails if a required label or annotation is not set with a non-empty
+// value afterwards, so that no namespace violating the governance rules
+// gets created.
+func (m *Metadata) Apply(namespace *v1.Namespace) error {
+	if m == nil {
+		return nil
+	}
+	labels := namespace.GetLabels()
+	for key, value := range m.Labels {
+		if _, exists := labels[key]; !exists {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = value
+		}
+	}
+	namespace.SetLabels(labels)
+	annotations := namespace.GetAnnotations()
+	for key, value := range m.Annotations {
+		if _, exists := annotations[key]; !exists {
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			annotations[key] = value
+		}
+	}
+	namespace.SetAnnotations(annotations)
+
+	for _, key := range m.RequiredLabels {
+		if namespace.GetLabels()[key] == "" {
+			return fmt.Errorf("required label %q is missing on namespace to be created", key)
+		}
+	}
+	for _, key := range m.RequiredAnnotations {
+		if namespace.GetAnnotations()[key] == "" {
+			return fmt.Errorf("required annotation %q is missing on namespace to be created", key)
+		}
+	}
+	return nil
+}
+
+func validateMetadataKey(kind, key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid key of namespace %s %q: %s", kind, key, strings.Join(errs, "; "))
+	}
+	if strings.HasPrefix(key, steward.GroupName+"/") {
+		return fmt.Errorf("invalid key of namespace %s %q: prefix %q is reserved", kind, key, steward.GroupName)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
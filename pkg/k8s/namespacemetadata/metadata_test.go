@@ -0,0 +1,208 @@
+package namespacemetadata
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		data          string
+		expected      *Metadata
+		expectedError string
+	}{
+		{"empty", "", nil, ""},
+		{"blank", " \n", nil, ""},
+		{
+			name: "valid",
+			data: `{"labels":{"example.com/cost-center":"cc1"},"annotations":{"example.com/classification":"internal data"},"requiredLabels":["example.com/cost-center"],"requiredAnnotations":["example.com/classification"]}`,
+			expected: &Metadata{
+				Labels:              map[string]string{"example.com/cost-center": "cc1"},
+				Annotations:         map[string]string{"example.com/classification": "internal data"},
+				RequiredLabels:      []string{"example.com/cost-center"},
+				RequiredAnnotations: []string{"example.com/classification"},
+			},
+		},
+		{
+			name:          "malformed",
+			data:          `{"labels":`,
+			expectedError: "invalid namespace metadata: unexpected EOF",
+		},
+		{
+			name:          "unknown field",
+			data:          `{"label":{}}`,
+			expectedError: `invalid namespace metadata: json: unknown field "label"`,
+		},
+		{
+			name:          "invalid",
+			data:          `{"requiredLabels":["foo"]}`,
+			expectedError: `required namespace label "foo" is not defined`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := Parse(tc.data)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Assert(t, result == nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Metadata_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		metadata      *Metadata
+		expectedError string
+	}{
+		{"nil", nil, ""},
+		{"empty", &Metadata{}, ""},
+		{
+			name:          "invalid label key",
+			metadata:      &Metadata{Labels: map[string]string{"a b": "v"}},
+			expectedError: `invalid key of namespace label "a b"`,
+		},
+		{
+			name:          "invalid label value",
+			metadata:      &Metadata{Labels: map[string]string{"foo": "a b"}},
+			expectedError: `invalid value of namespace label "foo"`,
+		},
+		{
+			name:          "invalid annotation key",
+			metadata:      &Metadata{Annotations: map[string]string{"/foo": "v"}},
+			expectedError: `invalid key of namespace annotation "/foo"`,
+		},
+		{
+			name:          "reserved label key",
+			metadata:      &Metadata{Labels: map[string]string{"steward.sap.com/foo": "v"}},
+			expectedError: `invalid key of namespace label "steward.sap.com/foo": prefix "steward.sap.com" is reserved`,
+		},
+		{
+			name:          "reserved annotation key",
+			metadata:      &Metadata{Annotations: map[string]string{"steward.sap.com/foo": "v"}},
+			expectedError: `invalid key of namespace annotation "steward.sap.com/foo": prefix "steward.sap.com" is reserved`,
+		},
+		{
+			name:          "required label empty",
+			metadata:      &Metadata{Labels: map[string]string{"foo": ""}, RequiredLabels: []string{"foo"}},
+			expectedError: `required namespace label "foo" is not defined`,
+		},
+		{
+			name:          "required annotation missing",
+			metadata:      &Metadata{RequiredAnnotations: []string{"foo"}},
+			expectedError: `required namespace annotation "foo" is not defined`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			err := tc.metadata.Validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_Metadata_Apply(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &Metadata{
+		Labels:              map[string]string{"l1": "v1", "l2": "v2"},
+		Annotations:         map[string]string{"a1": "v1"},
+		RequiredLabels:      []string{"l1"},
+		RequiredAnnotations: []string{"a1"},
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"l2": "existing"},
+		},
+	}
+
+	// EXERCISE
+	err := examinee.Apply(namespace)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"l1": "v1", "l2": "existing"}, namespace.GetLabels())
+	assert.DeepEqual(t, map[string]string{"a1": "v1"}, namespace.GetAnnotations())
+}
+
+func Test_Metadata_Apply_RequiredMissing(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		metadata      *Metadata
+		expectedError string
+	}{
+		{
+			name:          "label",
+			metadata:      &Metadata{RequiredLabels: []string{"l1"}},
+			expectedError: `required label "l1" is missing on namespace to be created`,
+		},
+		{
+			name:          "annotation",
+			metadata:      &Metadata{Annotations: map[string]string{"a1": "v1"}, RequiredAnnotations: []string{"a1"}},
+			expectedError: `required annotation "a1" is missing on namespace to be created`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			namespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					// existing empty annotation is retained
+					Annotations: map[string]string{"a1": ""},
+				},
+			}
+
+			// EXERCISE
+			err := tc.metadata.Apply(namespace)
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_Metadata_Apply_Nil(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var examinee *Metadata
+	namespace := &corev1.Namespace{}
+
+	// EXERCISE
+	err := examinee.Apply(namespace)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, namespace.GetLabels() == nil)
+}
@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/pager"
+)
+
+// ListPageSize is the number of objects requested per page when listing
+// potentially large numbers of objects via EachListItem.
+const ListPageSize = 500
+
+// ListFunc lists objects with the given options, e.g. the `List` method
+// of a typed client.
+type ListFunc func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error)
+
+// EachListItem lists the objects returned by the given list function in
+// pages of ListPageSize objects and calls fn for each object, which is
+// a pointer to the respective list item. Listing stops at the first
+// error returned by fn.
+// Paging limits the size of single responses of the API server and
+// the memory required for huge lists.
+func EachListItem(ctx context.Context, list ListFunc, opts metav1.ListOptions, fn func(obj runtime.Object) error) error {
+	p := pager.New(pager.ListPageFunc(list))
+	p.PageSize = ListPageSize
+	return p.EachListItem(ctx, opts, fn)
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_EachListItem(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	var pages []metav1.ListOptions
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		pages = append(pages, opts)
+		result := &api.PipelineRunList{}
+		for i := 0; i < 2; i++ {
+			result.Items = append(result.Items, *fake.PipelineRun(fmt.Sprintf("run%d-%d", len(pages), i), ns1, api.PipelineSpec{}))
+		}
+		if len(pages) < 3 {
+			result.Continue = fmt.Sprintf("continue%d", len(pages))
+		}
+		return result, nil
+	}
+	var names []string
+
+	// EXERCISE
+	err := EachListItem(ctx, list, metav1.ListOptions{LabelSelector: "label1"}, func(obj runtime.Object) error {
+		names = append(names, obj.(*api.PipelineRun).GetName())
+		return nil
+	})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"run1-0", "run1-1", "run2-0", "run2-1", "run3-0", "run3-1"}, names)
+	assert.Equal(t, 3, len(pages))
+	for i, opts := range pages {
+		assert.Equal(t, int64(ListPageSize), opts.Limit)
+		assert.Equal(t, "label1", opts.LabelSelector)
+		if i > 0 {
+			assert.Equal(t, fmt.Sprintf("continue%d", i), opts.Continue)
+		}
+	}
+}
+
+func Test_EachListItem_Error(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return nil, errors.New("error1")
+	}
+
+	// EXERCISE
+	err := EachListItem(ctx, list, metav1.ListOptions{}, func(obj runtime.Object) error {
+		return nil
+	})
+
+	// VERIFY
+	assert.Error(t, err, "error1")
+}
@@ -3,19 +3,19 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/metrics"
 	utils "github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
 )
 
@@ -42,15 +42,62 @@ type PipelineRun interface {
 	StoreErrorAsMessage(error, string) error
 	UpdateRunNamespace(string)
 	UpdateAuxNamespace(string)
+	UpdateResolvedRevision(string)
+	UpdateProgress(*api.Progress)
+	UpdateStages([]api.StageSummary)
+	UpdateTestResults(*api.TestResults)
+	UpdateArtifacts(*api.ArtifactsStatus)
+	UpdateAbortHandlers([]api.AbortHandlerStatus)
+	UpdateCondition(metav1.Condition)
+	UpdateTimelineExported(bool)
+	UpdateEstimatedDuration(time.Duration)
+	UpdateLogURL(string)
 	UpdateMessage(string)
+	AddWarning(reason, message string)
+	OffloadStatus(*api.OffloadedStatus)
+}
+
+// maxWarnings is the maximum number of warnings stored in the status
+// of a pipeline run. Further warnings are dropped.
+const maxWarnings = 10
+
+// MessageTruncation defines how messages exceeding a maximum length are
+// truncated when stored in the status of pipeline runs.
+type MessageTruncation struct {
+	// MaxLength is the maximum length in bytes of a message.
+	// If zero or negative, messages are not truncated.
+	MaxLength int
+
+	// Strategy defines which part of too long messages is kept.
+	Strategy utils.TruncationStrategy
+
+	// Marker replaces the removed part of truncated messages, e.g. a
+	// hint where to find the full message.
+	Marker string
+}
+
+// truncate shortens the given message according to the message
+// truncation.
+func (t MessageTruncation) truncate(message string) string {
+	return utils.TruncateMessage(message, t.MaxLength, t.Strategy, t.Marker)
+}
+
+// PipelineRunOpts stores options for the construction of a PipelineRun
+// instance.
+type PipelineRunOpts struct {
+	// MessageTruncation defines how messages stored in the status via
+	// UpdateMessage or StoreErrorAsMessage are truncated.
+	// By default messages are not truncated.
+	MessageTruncation MessageTruncation
 }
 
 type pipelineRun struct {
-	client          stewardv1alpha1.PipelineRunInterface
-	apiObj          *api.PipelineRun
-	copied          bool
-	changes         []changeFunc
-	commitRecorders []commitRecorderFunc
+	client            stewardv1alpha1.PipelineRunInterface
+	apiObj            *api.PipelineRun
+	copied            bool
+	changes           []changeFunc
+	commitRecorders   []commitRecorderFunc
+	messageTruncation MessageTruncation
 }
 
 type changeFunc func(*api.PipelineStatus) (commitRecorderFunc, error)
@@ -65,10 +112,17 @@ type commitRecorderFunc func() *api.StateItem
 // If you use functions changing the pipeline run without factroy set you will get an error.
 // The provided PipelineRun object is never modified and copied as late as possible.
 func NewPipelineRun(ctx context.Context, apiObj *api.PipelineRun, factory ClientFactory) (PipelineRun, error) {
+	return NewPipelineRunWithOpts(ctx, apiObj, factory, PipelineRunOpts{})
+}
+
+// NewPipelineRunWithOpts creates a managed pipeline run object like
+// NewPipelineRun with the given options.
+func NewPipelineRunWithOpts(ctx context.Context, apiObj *api.PipelineRun, factory ClientFactory, opts PipelineRunOpts) (PipelineRun, error) {
 	if factory == nil {
 		return &pipelineRun{
-			apiObj: apiObj,
-			copied: false,
+			apiObj:            apiObj,
+			copied:            false,
+			messageTruncation: opts.MessageTruncation,
 		}, nil
 	}
 	client := factory.StewardV1alpha1().PipelineRuns(apiObj.GetNamespace())
@@ -80,11 +134,12 @@ func NewPipelineRun(ctx context.Context, apiObj *api.PipelineRun, factory Client
 		return nil, err
 	}
 	return &pipelineRun{
-		apiObj:          obj,
-		copied:          true,
-		client:          client,
-		changes:         []changeFunc{},
-		commitRecorders: []commitRecorderFunc{},
+		apiObj:            obj,
+		copied:            true,
+		client:            client,
+		changes:           []changeFunc{},
+		commitRecorders:   []commitRecorderFunc{},
+		messageTruncation: opts.MessageTruncation,
 	}, nil
 }
 
@@ -116,16 +171,14 @@ func (r *pipelineRun) GetNamespace() string {
 }
 
 // GetPipelineRepoServerURL returns the server hosting the Jenkinsfile repository
+// in the format of Tekton Git credential annotations (see GitCredentialServer)
 func (r *pipelineRun) GetPipelineRepoServerURL() (string, error) {
 	urlString := r.GetSpec().JenkinsFile.URL
-	repoURL, err := url.Parse(urlString)
+	server, err := GitCredentialServer(urlString)
 	if err != nil {
 		return "", errors.Wrapf(err, "value %q of field spec.jenkinsFile.url is invalid [%s]", urlString, r.String())
 	}
-	if !(repoURL.Scheme == "http") && !(repoURL.Scheme == "https") {
-		return "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid [%s]: scheme not supported: %q", urlString, r.String(), repoURL.Scheme)
-	}
-	return fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host), nil
+	return server, nil
 }
 
 func (r *pipelineRun) GetName() string {
@@ -163,6 +216,8 @@ func (r *pipelineRun) InitState() error {
 		}
 		s.StateDetails = newStateDetails
 		s.State = api.StateNew
+		s.CreatedBy = r.apiObj.GetAnnotations()[api.AnnotationCreatedBy]
+		s.TriggeredBy = r.apiObj.Spec.TriggeredBy.DeepCopy()
 		return nil, nil
 	})
 }
@@ -244,9 +299,11 @@ func (r *pipelineRun) StoreErrorAsMessage(err error, message string) error {
 	return nil
 }
 
-// UpdateMessage stores string as message in the status
+// UpdateMessage stores string as message in the status.
+// Too long messages get truncated (see PipelineRunOpts).
 func (r *pipelineRun) UpdateMessage(message string) {
 	r.ensureCopy()
+	message = r.messageTruncation.truncate(utils.Trim(message))
 
 	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
 		old := s.Message
@@ -255,12 +312,33 @@ func (r *pipelineRun) UpdateMessage(message string) {
 			his = append(his, old)
 			s.History = his
 		}
-		s.Message = utils.Trim(message)
+		s.Message = message
 		s.MessageShort = utils.ShortenMessage(message, 100)
 		return nil, nil
 	})
 }
 
+// AddWarning adds a non-fatal warning to the status.
+// Warnings with the same reason and message are added only once.
+// If the maximum number of warnings is reached, the warning is dropped.
+func (r *pipelineRun) AddWarning(reason, message string) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		warning := api.Warning{Reason: reason, Message: message}
+		for _, w := range s.Warnings {
+			if w == warning {
+				return nil, nil
+			}
+		}
+		if len(s.Warnings) >= maxWarnings {
+			klog.V(4).Infof("Dropping warning %q as maximum number of warnings is reached [%s]", reason, r.String())
+			return nil, nil
+		}
+		s.Warnings = append(s.Warnings, warning)
+		return nil, nil
+	})
+}
+
 // UpdateRunNamespace overrides the namespace in which the builds happens
 func (r *pipelineRun) UpdateRunNamespace(ns string) {
 	r.ensureCopy()
@@ -280,6 +358,124 @@ func (r *pipelineRun) UpdateAuxNamespace(ns string) {
 	})
 }
 
+// UpdateResolvedRevision sets the commit SHA the revision of the
+// pipeline repository has been resolved to.
+func (r *pipelineRun) UpdateResolvedRevision(revision string) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.ResolvedRevision = revision
+		return nil, nil
+	})
+}
+
+// UpdateProgress sets the stage-level progress of the pipeline.
+func (r *pipelineRun) UpdateProgress(progress *api.Progress) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.Progress = progress.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateStages sets the summary of the executed pipeline stages.
+func (r *pipelineRun) UpdateStages(stages []api.StageSummary) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.Stages = append([]api.StageSummary(nil), stages...)
+		return nil, nil
+	})
+}
+
+// UpdateTestResults sets the totals of the test results.
+func (r *pipelineRun) UpdateTestResults(testResults *api.TestResults) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.TestResults = testResults.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateArtifacts sets the uploaded artifacts.
+func (r *pipelineRun) UpdateArtifacts(artifacts *api.ArtifactsStatus) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.Artifacts = artifacts.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateTimelineExported sets whether the run event timeline has been
+// exported.
+func (r *pipelineRun) UpdateTimelineExported(exported bool) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.TimelineExported = exported
+		return nil, nil
+	})
+}
+
+// UpdateEstimatedDuration sets the expected duration of the pipeline
+// run.
+func (r *pipelineRun) UpdateEstimatedDuration(duration time.Duration) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.EstimatedDuration = &metav1.Duration{Duration: duration}
+		return nil, nil
+	})
+}
+
+// UpdateLogURL sets the location of the archived log of the pipeline
+// run.
+func (r *pipelineRun) UpdateLogURL(logURL string) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.LogURL = logURL
+		return nil, nil
+	})
+}
+
+// OffloadStatus replaces the status fields listed in the given
+// reference by the reference to the config map they have been moved to.
+// `messageShort` is kept as summary of the offloaded message.
+func (r *pipelineRun) OffloadStatus(offloaded *api.OffloadedStatus) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		for _, field := range offloaded.Fields {
+			switch field {
+			case api.OffloadedStatusFieldMessage:
+				s.Message = ""
+			case api.OffloadedStatusFieldHistory:
+				s.History = nil
+			case api.OffloadedStatusFieldStages:
+				s.Stages = nil
+			}
+		}
+		s.OffloadedStatus = offloaded.DeepCopy()
+		return nil, nil
+	})
+}
+
+// UpdateAbortHandlers stores the outcomes of the abort handlers invoked
+// for the pipeline run.
+func (r *pipelineRun) UpdateAbortHandlers(statuses []api.AbortHandlerStatus) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.AbortHandlers = append([]api.AbortHandlerStatus(nil), statuses...)
+		return nil, nil
+	})
+}
+
+// UpdateCondition sets the given condition in the status of the
+// pipeline run. The last transition time is only changed if the status
+// of the condition changes.
+func (r *pipelineRun) UpdateCondition(condition metav1.Condition) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		apimeta.SetStatusCondition(&s.Conditions, condition)
+		return nil, nil
+	})
+}
+
 //HasDeletionTimestamp returns true if deletion timestamp is set
 func (r *pipelineRun) HasDeletionTimestamp() bool {
 	return !r.apiObj.ObjectMeta.DeletionTimestamp.IsZero()
@@ -398,7 +594,7 @@ func (r *pipelineRun) CommitStatus(ctx context.Context) ([]*api.StateItem, error
 	}(time.Now())
 
 	var changeError error
-	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+	err := retry.OnError(ctx, retry.DefaultBackoff, k8serrors.IsConflict, func() error {
 		var err error
 
 		if retryCount > 0 {
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
 	"gotest.tools/assert"
 	"gotest.tools/assert/cmp"
 	is "gotest.tools/assert/cmp"
@@ -15,7 +18,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	k8stesting "k8s.io/client-go/testing"
-	"k8s.io/client-go/util/retry"
 )
 
 const message string = "MyMessage"
@@ -165,6 +167,53 @@ func Test_pipelineRun_StoreErrorAsMessage(t *testing.T) {
 	assert.Equal(t, "ERROR: message1 [PipelineRun{name: foo, namespace: namespace1, state: running}]: error1", run.Status.Message)
 }
 
+func Test_pipelineRun_UpdateMessage_Truncates(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRunWithOpts(ctx, run, factory, PipelineRunOpts{
+		MessageTruncation: MessageTruncation{
+			MaxLength: 10,
+			Strategy:  utils.TruncationKeepTail,
+			Marker:    "[...]",
+		},
+	})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.UpdateMessage("line1\nline2\nline3\n")
+
+	// VERIFY
+	assert.Equal(t, "[...]line3", examinee.GetStatus().Message)
+	assert.Equal(t, "[...]line3", examinee.GetStatus().MessageShort)
+}
+
+func Test_pipelineRun_StoreErrorAsMessage_Truncates(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRunWithOpts(ctx, run, factory, PipelineRunOpts{
+		MessageTruncation: MessageTruncation{
+			MaxLength: 20,
+			Strategy:  utils.TruncationKeepHead,
+			Marker:    "[...]",
+		},
+	})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.StoreErrorAsMessage(fmt.Errorf("error1"), "message1")
+
+	// VERIFY
+	assert.Equal(t, "ERROR: message1[...]", examinee.GetStatus().Message)
+}
+
 func Test_pipelineRun_HasDeletionTimestamp_false(t *testing.T) {
 	t.Parallel()
 
@@ -216,6 +265,216 @@ func Test_pipelineRun_UpdateMessage_GoodCase(t *testing.T) {
 	assert.Equal(t, message, examinee.GetStatus().Message)
 }
 
+func Test_pipelineRun_OffloadStatus(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	run.Status.Message = "message1"
+	run.Status.MessageShort = "message1"
+	run.Status.History = []string{"message0"}
+	run.Status.Stages = []api.StageSummary{{Name: "stage1"}}
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	offloaded := &api.OffloadedStatus{
+		ConfigMapName: "configmap1",
+		Fields:        []string{api.OffloadedStatusFieldMessage, api.OffloadedStatusFieldHistory},
+	}
+
+	// EXERCISE
+	examinee.OffloadStatus(offloaded)
+
+	// VERIFY
+	status := examinee.GetStatus()
+	assert.Equal(t, "", status.Message)
+	assert.Equal(t, "message1", status.MessageShort)
+	assert.Assert(t, status.History == nil)
+	assert.Equal(t, 1, len(status.Stages))
+	assert.DeepEqual(t, offloaded, status.OffloadedStatus)
+}
+
+func Test_pipelineRun_UpdateResolvedRevision(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.UpdateResolvedRevision("0123456789abcdef0123456789abcdef01234567")
+
+	// VERIFY
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", examinee.GetStatus().ResolvedRevision)
+}
+
+func Test_pipelineRun_UpdateProgress(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	progress := &api.Progress{
+		Stage:      "integration tests",
+		StageIndex: 3,
+		StageCount: 7,
+		Summary:    "stage 3/7: integration tests",
+	}
+
+	// EXERCISE
+	examinee.UpdateProgress(progress)
+	progress.Stage = "modified"
+
+	// VERIFY
+	assert.Equal(t, "integration tests", examinee.GetStatus().Progress.Stage)
+	assert.Equal(t, 3, examinee.GetStatus().Progress.StageIndex)
+	assert.Equal(t, 7, examinee.GetStatus().Progress.StageCount)
+
+	// EXERCISE
+	examinee.UpdateProgress(nil)
+
+	// VERIFY
+	assert.Assert(t, examinee.GetStatus().Progress == nil)
+}
+
+func Test_pipelineRun_UpdateStages(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	stages := []api.StageSummary{
+		{Name: "build", Result: "SUCCESS", Duration: metav1.Duration{Duration: time.Minute}},
+		{Name: "test", Result: "FAILURE", Duration: metav1.Duration{Duration: time.Second}},
+	}
+
+	// EXERCISE
+	examinee.UpdateStages(stages)
+	stages[0].Name = "modified"
+
+	// VERIFY
+	assert.DeepEqual(t, []api.StageSummary{
+		{Name: "build", Result: "SUCCESS", Duration: metav1.Duration{Duration: time.Minute}},
+		{Name: "test", Result: "FAILURE", Duration: metav1.Duration{Duration: time.Second}},
+	}, examinee.GetStatus().Stages)
+}
+
+func Test_pipelineRun_UpdateTestResults(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	testResults := &api.TestResults{Tests: 10, Failures: 2, Errors: 1, Skipped: 3}
+
+	// EXERCISE
+	examinee.UpdateTestResults(testResults)
+	testResults.Tests = 99
+
+	// VERIFY
+	assert.DeepEqual(t, &api.TestResults{Tests: 10, Failures: 2, Errors: 1, Skipped: 3}, examinee.GetStatus().TestResults)
+}
+
+func Test_pipelineRun_UpdateArtifacts(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	artifacts := &api.ArtifactsStatus{Uploaded: 1, URLs: []string{"https://s3.example.com/b1/a.jar"}}
+
+	// EXERCISE
+	examinee.UpdateArtifacts(artifacts)
+	artifacts.URLs[0] = "changed"
+
+	// VERIFY
+	assert.DeepEqual(t, &api.ArtifactsStatus{Uploaded: 1, URLs: []string{"https://s3.example.com/b1/a.jar"}}, examinee.GetStatus().Artifacts)
+}
+
+func Test_pipelineRun_UpdateAbortHandlers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	statuses := []api.AbortHandlerStatus{
+		{Name: "handler1", Result: api.AbortHandlerResultSucceeded},
+		{Name: "handler2", Result: api.AbortHandlerResultFailed, Message: "error1"},
+	}
+
+	// EXERCISE
+	examinee.UpdateAbortHandlers(statuses)
+	statuses[0].Name = "changed"
+
+	// VERIFY
+	assert.DeepEqual(t, []api.AbortHandlerStatus{
+		{Name: "handler1", Result: api.AbortHandlerResultSucceeded},
+		{Name: "handler2", Result: api.AbortHandlerResultFailed, Message: "error1"},
+	}, examinee.GetStatus().AbortHandlers)
+}
+
+func Test_pipelineRun_AddWarning(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.AddWarning("Reason1", "message1")
+	examinee.AddWarning("Reason2", "message2")
+	examinee.AddWarning("Reason1", "message1")
+
+	// VERIFY
+	assert.DeepEqual(t, []api.Warning{
+		{Reason: "Reason1", Message: "message1"},
+		{Reason: "Reason2", Message: "message2"},
+	}, examinee.GetStatus().Warnings)
+}
+
+func Test_pipelineRun_AddWarning_DropsWarningsAboveMaximum(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	for i := 0; i <= maxWarnings; i++ {
+		examinee.AddWarning("Reason1", fmt.Sprintf("message%d", i))
+	}
+
+	// VERIFY
+	warnings := examinee.GetStatus().Warnings
+	assert.Equal(t, maxWarnings, len(warnings))
+	assert.Equal(t, "message0", warnings[0].Message)
+}
+
 func Test_pipelineRun_InitState(t *testing.T) {
 	t.Parallel()
 
@@ -243,6 +502,50 @@ func Test_pipelineRun_InitState(t *testing.T) {
 
 }
 
+func Test_pipelineRun_InitState_SetsCreatedBy(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	pipelineRun.ObjectMeta.Annotations = map[string]string{
+		api.AnnotationCreatedBy: "user1",
+	}
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	resultErr := examinee.InitState()
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "user1", examinee.GetStatus().CreatedBy)
+}
+
+func Test_pipelineRun_InitState_SetsTriggeredBy(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := newPipelineRunWithEmptySpec(ns1, run1)
+	pipelineRun.Spec.TriggeredBy = &api.TriggeredBy{
+		Type: "github-pull-request",
+		ID:   "org1/repo1#42",
+		URL:  "https://github.com/org1/repo1/pull/42",
+	}
+	factory := fake.NewClientFactory(pipelineRun)
+	examinee, err := NewPipelineRun(ctx, pipelineRun, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	resultErr := examinee.InitState()
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, pipelineRun.Spec.TriggeredBy, examinee.GetStatus().TriggeredBy)
+}
+
 func Test_pipelineRun_InitState_ReturnsErrorIfCalledMultipleTimes(t *testing.T) {
 	t.Parallel()
 
@@ -450,6 +753,8 @@ func Test_pipelineRun_GetPipelineRepoServerURL_CorrectURLs(t *testing.T) {
 		{url: "HTTPS://foo.com/Path", expectedURL: "https://foo.com"},
 		{url: "https://foo.com:1234/Path", expectedURL: "https://foo.com:1234"},
 		{url: "http://foo.com:1234/Path", expectedURL: "http://foo.com:1234"},
+		{url: "ssh://git@foo.com/Path", expectedURL: "foo.com"},
+		{url: "git@foo.com:Path", expectedURL: "foo.com"},
 	} {
 		t.Run(test.url, func(t *testing.T) {
 			// SETUP
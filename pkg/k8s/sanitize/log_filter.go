@@ -0,0 +1,34 @@
+package sanitize
+
+// LogFilter is a klog log filter redacting sensitive data from all
+// Kubernetes objects passed as log arguments. See Object for details.
+// Install it via `klog.SetLogFilter()`.
+type LogFilter struct{}
+
+// Filter implements `klog.LogFilter`.
+func (LogFilter) Filter(args []interface{}) []interface{} {
+	return objects(args)
+}
+
+// FilterF implements `klog.LogFilter`.
+func (LogFilter) FilterF(format string, args []interface{}) (string, []interface{}) {
+	return format, objects(args)
+}
+
+// FilterS implements `klog.LogFilter`.
+func (LogFilter) FilterS(msg string, keysAndValues []interface{}) (string, []interface{}) {
+	return msg, objects(keysAndValues)
+}
+
+// objects returns a copy of the given slice with all elements sanitized
+// via Object. The given slice is not modified.
+func objects(args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+	result := make([]interface{}, len(args))
+	for i, arg := range args {
+		result[i] = Object(arg)
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package sanitize
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_LogFilter(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			Data: map[string][]byte{"key1": []byte("secret1")},
+		}
+	}
+	examinee := LogFilter{}
+
+	for _, tc := range []struct {
+		name   string
+		filter func(args []interface{}) []interface{}
+	}{
+		{"Filter", examinee.Filter},
+		{"FilterF", func(args []interface{}) []interface{} {
+			format, result := examinee.FilterF("format1", args)
+			assert.Equal(t, "format1", format)
+			return result
+		}},
+		{"FilterS", func(args []interface{}) []interface{} {
+			msg, result := examinee.FilterS("msg1", args)
+			assert.Equal(t, "msg1", msg)
+			return result
+		}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := []interface{}{"secret", newSecret(), 42}
+
+			// EXERCISE
+			result := tc.filter(args)
+
+			// VERIFY
+			assert.Equal(t, 3, len(result))
+			assert.Equal(t, "secret", result[0])
+			assert.Equal(t, Redacted, string(result[1].(*corev1.Secret).Data["key1"]))
+			assert.Equal(t, 42, result[2])
+
+			// args are unchanged
+			assert.Equal(t, "secret1", string(args[1].(*corev1.Secret).Data["key1"]))
+		})
+	}
+}
+
+func Test_LogFilter_NoArgs(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := LogFilter{}.Filter(nil)
+
+	// VERIFY
+	assert.Assert(t, result == nil)
+}
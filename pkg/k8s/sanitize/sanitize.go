@@ -0,0 +1,140 @@
+package sanitize
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Redacted is the value replacing sensitive data in sanitized objects.
+const Redacted = "<redacted>"
+
+// sensitiveAnnotationKeyParts are substrings of annotation names (without
+// prefix) denoting sensitive annotations, compared case-insensitively.
+var sensitiveAnnotationKeyParts = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+}
+
+// Object returns a copy of the given object with sensitive data redacted,
+// so that it can be logged or dumped without leaking credentials.
+// Redacted are the values of `data` and `stringData` of secrets (typed or
+// unstructured), the value of annotation
+// `kubectl.kubernetes.io/last-applied-configuration`, which may contain the
+// complete secret data, and the values of annotations whose name contains
+// "password", "secret", "token" or "credential".
+// If obj is not a Kubernetes object or does not contain sensitive data,
+// it is returned unchanged. The given object is never modified.
+func Object(obj interface{}) interface{} {
+	if v := reflect.ValueOf(obj); v.Kind() == reflect.Ptr && v.IsNil() {
+		return obj
+	}
+
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		result := o.DeepCopy()
+		redactSecret(result)
+		return result
+
+	case *corev1.SecretList:
+		result := o.DeepCopy()
+		for i := range result.Items {
+			redactSecret(&result.Items[i])
+		}
+		return result
+
+	case *unstructured.Unstructured:
+		if !isUnstructuredSecret(o) && !hasSensitiveAnnotations(o) {
+			return obj
+		}
+		result := o.DeepCopy()
+		if isUnstructuredSecret(result) {
+			for _, field := range []string{"data", "stringData"} {
+				if data, ok := result.Object[field].(map[string]interface{}); ok {
+					for key := range data {
+						data[key] = Redacted
+					}
+				}
+			}
+		}
+		redactAnnotations(result)
+		return result
+
+	case runtime.Object:
+		metaObj, ok := o.(metav1.Object)
+		if !ok || !hasSensitiveAnnotations(metaObj) {
+			return obj
+		}
+		result, ok := o.DeepCopyObject().(metav1.Object)
+		if !ok {
+			return obj
+		}
+		redactAnnotations(result)
+		return result
+	}
+	return obj
+}
+
+// dumpConfig is the spew configuration used by Dump. Methods are
+// disabled, as the generated `String()` methods of API types print
+// byte slices as lists of numbers.
+var dumpConfig = spew.ConfigState{Indent: " ", DisableMethods: true}
+
+// Dump returns a dump of the given object like `spew.Sdump()` with
+// sensitive data redacted via Object.
+func Dump(obj interface{}) string {
+	return dumpConfig.Sdump(Object(obj))
+}
+
+func redactSecret(secret *corev1.Secret) {
+	for key := range secret.Data {
+		secret.Data[key] = []byte(Redacted)
+	}
+	for key := range secret.StringData {
+		secret.StringData[key] = Redacted
+	}
+	redactAnnotations(secret)
+}
+
+func redactAnnotations(obj metav1.Object) {
+	annotations := obj.GetAnnotations()
+	for key := range annotations {
+		if isSensitiveAnnotation(key) {
+			annotations[key] = Redacted
+		}
+	}
+}
+
+func hasSensitiveAnnotations(obj metav1.Object) bool {
+	for key := range obj.GetAnnotations() {
+		if isSensitiveAnnotation(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSensitiveAnnotation(key string) bool {
+	if key == corev1.LastAppliedConfigAnnotation {
+		return true
+	}
+	name := strings.ToLower(key[strings.LastIndex(key, "/")+1:])
+	for _, part := range sensitiveAnnotationKeyParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnstructuredSecret(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Secret"
+}
@@ -0,0 +1,207 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_Object_Secret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "secret1",
+			Annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`,
+				"annotation1":                      "value1",
+			},
+		},
+		Data: map[string][]byte{
+			"password": []byte("secret1"),
+		},
+		StringData: map[string]string{
+			"token": "secret2",
+		},
+	}
+
+	// EXERCISE
+	result := Object(secret)
+
+	// VERIFY
+	resultSecret, ok := result.(*corev1.Secret)
+	assert.Assert(t, ok)
+	assert.Equal(t, "secret1", resultSecret.GetName())
+	assert.DeepEqual(t, map[string][]byte{"password": []byte(Redacted)}, resultSecret.Data)
+	assert.DeepEqual(t, map[string]string{"token": Redacted}, resultSecret.StringData)
+	assert.DeepEqual(t, map[string]string{
+		corev1.LastAppliedConfigAnnotation: Redacted,
+		"annotation1":                      "value1",
+	}, resultSecret.GetAnnotations())
+
+	// original object is unchanged
+	assert.Equal(t, "secret1", string(secret.Data["password"]))
+	assert.Equal(t, "secret2", secret.StringData["token"])
+	assert.Equal(t, `{"data":{"password":"c2VjcmV0"}}`, secret.GetAnnotations()[corev1.LastAppliedConfigAnnotation])
+}
+
+func Test_Object_SecretList(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	list := &corev1.SecretList{
+		Items: []corev1.Secret{
+			{Data: map[string][]byte{"key1": []byte("secret1")}},
+			{Data: map[string][]byte{"key2": []byte("secret2")}},
+		},
+	}
+
+	// EXERCISE
+	result := Object(list)
+
+	// VERIFY
+	resultList := result.(*corev1.SecretList)
+	assert.Equal(t, Redacted, string(resultList.Items[0].Data["key1"]))
+	assert.Equal(t, Redacted, string(resultList.Items[1].Data["key2"]))
+	assert.Equal(t, "secret1", string(list.Items[0].Data["key1"]))
+}
+
+func Test_Object_UnstructuredSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name": "secret1",
+			},
+			"data": map[string]interface{}{
+				"key1": "c2VjcmV0MQ==",
+			},
+			"stringData": map[string]interface{}{
+				"key2": "secret2",
+			},
+		},
+	}
+
+	// EXERCISE
+	result := Object(obj)
+
+	// VERIFY
+	resultObj := result.(*unstructured.Unstructured)
+	assert.DeepEqual(t, map[string]interface{}{"key1": Redacted}, resultObj.Object["data"])
+	assert.DeepEqual(t, map[string]interface{}{"key2": Redacted}, resultObj.Object["stringData"])
+	assert.DeepEqual(t, map[string]interface{}{"key1": "c2VjcmV0MQ=="}, obj.Object["data"])
+}
+
+func Test_Object_UnstructuredNonSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"data": map[string]interface{}{
+				"key1": "value1",
+			},
+		},
+	}
+
+	// EXERCISE
+	result := Object(obj)
+
+	// VERIFY
+	assert.Assert(t, result == obj)
+}
+
+func Test_Object_OtherObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := &stewardv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "run1",
+			Annotations: map[string]string{
+				"example.com/git-token":       "token1",
+				"example.com/DB_PASSWORD":     "password1",
+				"example.com/someCredentials": "credential1",
+				"annotation1":                 "value1",
+			},
+		},
+	}
+
+	// EXERCISE
+	result := Object(run)
+
+	// VERIFY
+	resultRun := result.(*stewardv1alpha1.PipelineRun)
+	assert.DeepEqual(t, map[string]string{
+		"example.com/git-token":       Redacted,
+		"example.com/DB_PASSWORD":     Redacted,
+		"example.com/someCredentials": Redacted,
+		"annotation1":                 "value1",
+	}, resultRun.GetAnnotations())
+	assert.Equal(t, "token1", run.GetAnnotations()["example.com/git-token"])
+}
+
+func Test_Object_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	run := &stewardv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "run1",
+			Annotations: map[string]string{"annotation1": "value1"},
+		},
+	}
+	var nilSecret *corev1.Secret
+	var nilRun *stewardv1alpha1.PipelineRun
+
+	for _, tc := range []struct {
+		name string
+		obj  interface{}
+	}{
+		{"nil", nil},
+		{"nil secret", nilSecret},
+		{"nil pipeline run", nilRun},
+		{"string", "foo"},
+		{"pipeline run without sensitive annotations", run},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := Object(tc.obj)
+
+			// VERIFY
+			assert.Assert(t, result == tc.obj)
+		})
+	}
+}
+
+func Test_Dump(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("topsecret1"),
+		},
+	}
+
+	// EXERCISE
+	result := Dump(secret)
+
+	// VERIFY
+	assert.Assert(t, !strings.Contains(result, "topsecret1"))
+	assert.Assert(t, strings.Contains(result, Redacted))
+}
@@ -106,6 +106,21 @@ func (mr *MockSecretHelperMockRecorder) IsNotFound(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNotFound", reflect.TypeOf((*MockSecretHelper)(nil).IsNotFound), arg0)
 }
 
+// RefreshSecrets mocks base method
+func (m *MockSecretHelper) RefreshSecrets(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshSecrets", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshSecrets indicates an expected call of RefreshSecrets
+func (mr *MockSecretHelperMockRecorder) RefreshSecrets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshSecrets", reflect.TypeOf((*MockSecretHelper)(nil).RefreshSecrets), arg0)
+}
+
 // MockSecretProvider is a mock of SecretProvider interface
 type MockSecretProvider struct {
 	ctrl     *gomock.Controller
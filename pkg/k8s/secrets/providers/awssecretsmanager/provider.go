@@ -0,0 +1,185 @@
+package awssecretsmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets/providers"
+	"github.com/SAP/stewardci-core/pkg/utils/sigv4"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Keys of the entries of an AWS Secrets Manager configuration (see
+	// ConfigFromData).
+	configKeyRegion          = "region"
+	configKeyEndpoint        = "endpoint"
+	configKeyPrefix          = "prefix"
+	configKeyAccessKeyID     = "accessKeyId"
+	configKeySecretAccessKey = "secretAccessKey"
+
+	// service is the service name used for request signing.
+	service = "secretsmanager"
+
+	// targetGetSecretValue is the value of header `X-Amz-Target` of
+	// GetSecretValue requests.
+	targetGetSecretValue = "secretsmanager.GetSecretValue"
+
+	// errorTypeNotFound is the error type returned if a secret does not
+	// exist.
+	errorTypeNotFound = "ResourceNotFoundException"
+
+	// defaultTimeout is the timeout of requests to AWS Secrets Manager if
+	// no HTTP client is given.
+	defaultTimeout = 30 * time.Second
+)
+
+// Config is the configuration of an AWS Secrets Manager secret provider.
+type Config struct {
+	// Region is the AWS region, e.g. `eu-central-1`.
+	Region string
+
+	// Endpoint is the base URL of the AWS Secrets Manager API.
+	// Defaults to `https://secretsmanager.<region>.amazonaws.com`.
+	Endpoint string
+
+	// Prefix is prepended to secret names to get the secret ID, e.g.
+	// `steward/team1/`. Optional.
+	Prefix string
+
+	// AccessKeyID is the access key ID used to sign requests.
+	AccessKeyID string
+
+	// SecretAccessKey is the secret access key used to sign requests.
+	SecretAccessKey string
+}
+
+// ConfigFromData returns the AWS Secrets Manager configuration contained
+// in the given secret data. It has the entries `region`, `accessKeyId`,
+// `secretAccessKey` and optionally `endpoint` and `prefix`.
+func ConfigFromData(data map[string][]byte) (*Config, error) {
+	get := func(key string) string {
+		return strings.TrimSpace(string(data[key]))
+	}
+	config := &Config{
+		Region:          get(configKeyRegion),
+		Endpoint:        get(configKeyEndpoint),
+		Prefix:          get(configKeyPrefix),
+		AccessKeyID:     get(configKeyAccessKeyID),
+		SecretAccessKey: get(configKeySecretAccessKey),
+	}
+	if config.Region == "" {
+		return nil, errors.Errorf("entry %q must not be empty", configKeyRegion)
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", config.Region)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, errors.Errorf("entry %q has invalid value %q: must be an HTTP(S) URL", configKeyEndpoint, config.Endpoint)
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.Errorf("entries %q and %q must be set", configKeyAccessKeyID, configKeySecretAccessKey)
+	}
+	return config, nil
+}
+
+type provider struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewProvider returns a secret provider reading secrets from AWS Secrets
+// Manager.
+// The secret with name `<name>` is read from the secret with ID
+// `<prefix><name>`, whose secret string must be a JSON object with string
+// values. It is converted to a Kubernetes secret with
+// providers.SecretFromEntries.
+// If client is nil, a client with a default timeout is used.
+func NewProvider(config Config, client *http.Client) secrets.SecretProvider {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &provider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// GetSecret implements secrets.SecretProvider.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	secretID := p.config.Prefix + name
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode request")
+	}
+	var response struct {
+		SecretString *string `json:"SecretString"`
+	}
+	found, err := p.request(ctx, body, &response)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read secret %q from AWS Secrets Manager", secretID)
+	}
+	if !found {
+		return nil, nil
+	}
+	if response.SecretString == nil {
+		return nil, errors.Errorf("secret %q from AWS Secrets Manager has no secret string", secretID)
+	}
+	var entries map[string]string
+	if err := json.Unmarshal([]byte(*response.SecretString), &entries); err != nil {
+		return nil, errors.Wrapf(err, "secret %q from AWS Secrets Manager: secret string is not a JSON object with string values", secretID)
+	}
+	return providers.SecretFromEntries(name, entries), nil
+}
+
+// request sends a signed request to the AWS Secrets Manager API and
+// decodes the JSON response into result. It returns false if the secret
+// does not exist.
+func (p *provider) request(ctx context.Context, body []byte, result interface{}) (bool, error) {
+	requestURL := strings.TrimSuffix(p.config.Endpoint, "/") + "/"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create request")
+	}
+	request.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	request.Header.Set("X-Amz-Target", targetGetSecretValue)
+	credentials := sigv4.Credentials{
+		AccessKeyID:     p.config.AccessKeyID,
+		SecretAccessKey: p.config.SecretAccessKey,
+	}
+	sigv4.Sign(request, body, credentials, p.config.Region, service, p.now())
+	response, err := p.client.Do(request)
+	if err != nil {
+		return false, errors.Wrap(err, "request failed")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		var apiError struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(response.Body).Decode(&apiError)
+		if strings.HasSuffix(apiError.Type, errorTypeNotFound) {
+			return false, nil
+		}
+		if apiError.Type != "" {
+			return false, fmt.Errorf("unexpected response status %q: %s: %s", response.Status, apiError.Type, apiError.Message)
+		}
+		return false, fmt.Errorf("unexpected response status %q", response.Status)
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return false, errors.Wrap(err, "failed to decode response")
+	}
+	return true, nil
+}
@@ -0,0 +1,209 @@
+package awssecretsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeSecretsManager is a minimal AWS Secrets Manager API serving the
+// given secret strings.
+type fakeSecretsManager struct {
+	secrets  map[string]string
+	requests []*http.Request
+}
+
+func (m *fakeSecretsManager) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	m.requests = append(m.requests, r)
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key1/") {
+		rw.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(rw).Encode(map[string]string{"__type": "UnrecognizedClientException", "message": "invalid signature"})
+		return
+	}
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	secretString, ok := m.secrets[body["SecretId"]]
+	if !ok {
+		rw.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(rw).Encode(map[string]string{"__type": "ResourceNotFoundException", "message": "not found"})
+		return
+	}
+	json.NewEncoder(rw).Encode(map[string]string{"SecretString": secretString})
+}
+
+func Test_ConfigFromData(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		data           map[string]string
+		expectedConfig *Config
+		expectedError  string
+	}{
+		{
+			name: "default_endpoint",
+			data: map[string]string{"region": "eu-central-1", "accessKeyId": "key1", "secretAccessKey": "secret1"},
+			expectedConfig: &Config{
+				Region:          "eu-central-1",
+				Endpoint:        "https://secretsmanager.eu-central-1.amazonaws.com",
+				AccessKeyID:     "key1",
+				SecretAccessKey: "secret1",
+			},
+		},
+		{
+			name: "all",
+			data: map[string]string{
+				"region": "eu-central-1", "endpoint": "http://localhost:4566", "prefix": "steward/",
+				"accessKeyId": "key1", "secretAccessKey": "secret1",
+			},
+			expectedConfig: &Config{
+				Region:          "eu-central-1",
+				Endpoint:        "http://localhost:4566",
+				Prefix:          "steward/",
+				AccessKeyID:     "key1",
+				SecretAccessKey: "secret1",
+			},
+		},
+		{
+			name:          "no_region",
+			data:          map[string]string{"accessKeyId": "key1", "secretAccessKey": "secret1"},
+			expectedError: `entry "region" must not be empty`,
+		},
+		{
+			name:          "invalid_endpoint",
+			data:          map[string]string{"region": "eu-central-1", "endpoint": "localhost", "accessKeyId": "key1", "secretAccessKey": "secret1"},
+			expectedError: `entry "endpoint" has invalid value "localhost": must be an HTTP(S) URL`,
+		},
+		{
+			name:          "no_credentials",
+			data:          map[string]string{"region": "eu-central-1", "accessKeyId": "key1"},
+			expectedError: `entries "accessKeyId" and "secretAccessKey" must be set`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			data := map[string][]byte{}
+			for key, value := range tc.data {
+				data[key] = []byte(value)
+			}
+
+			// EXERCISE
+			config, err := ConfigFromData(data)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func Test_provider_GetSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secretsManager := &fakeSecretsManager{
+		secrets: map[string]string{
+			"steward/git": `{"username":"user1","password":"pass1","_type":"kubernetes.io/basic-auth","_annotation.tekton.dev/git-0":"https://github.com"}`,
+		},
+	}
+	server := httptest.NewServer(secretsManager)
+	defer server.Close()
+	examinee := NewProvider(Config{
+		Region:          "eu-central-1",
+		Endpoint:        server.URL,
+		Prefix:          "steward/",
+		AccessKeyID:     "key1",
+		SecretAccessKey: "secret1",
+	}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "git",
+			Annotations: map[string]string{"tekton.dev/git-0": "https://github.com"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("user1"),
+			"password": []byte("pass1"),
+		},
+	}, secret)
+	assert.Equal(t, 1, len(secretsManager.requests))
+	request := secretsManager.requests[0]
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "secretsmanager.GetSecretValue", request.Header.Get("X-Amz-Target"))
+	assert.Equal(t, "application/x-amz-json-1.1", request.Header.Get("Content-Type"))
+	assert.Assert(t, strings.Contains(request.Header.Get("Authorization"), "/eu-central-1/secretsmanager/aws4_request"))
+}
+
+func Test_provider_GetSecret_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeSecretsManager{})
+	defer server.Close()
+	examinee := NewProvider(Config{Region: "eu-central-1", Endpoint: server.URL, AccessKeyID: "key1", SecretAccessKey: "secret1"}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "unknown")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, secret == nil)
+}
+
+func Test_provider_GetSecret_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeSecretsManager{})
+	defer server.Close()
+	examinee := NewProvider(Config{Region: "eu-central-1", Endpoint: server.URL, AccessKeyID: "wrong", SecretAccessKey: "secret1"}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.Error(t, err, `failed to read secret "git" from AWS Secrets Manager: unexpected response status "403 Forbidden": UnrecognizedClientException: invalid signature`)
+	assert.Assert(t, secret == nil)
+}
+
+func Test_provider_GetSecret_NoJSONObject(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeSecretsManager{secrets: map[string]string{"token": "plain-token"}})
+	defer server.Close()
+	examinee := NewProvider(Config{Region: "eu-central-1", Endpoint: server.URL, AccessKeyID: "key1", SecretAccessKey: "secret1"}, nil)
+
+	// EXERCISE
+	_, err := examinee.GetSecret(ctx, "token")
+
+	// VERIFY
+	assert.ErrorContains(t, err, `secret "token" from AWS Secrets Manager: secret string is not a JSON object with string values`)
+}
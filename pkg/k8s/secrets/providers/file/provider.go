@@ -0,0 +1,83 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets/providers"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// configKeyDirectory is the key of the entry of a file provider
+	// configuration defining the directory (see ConfigFromData).
+	configKeyDirectory = "directory"
+
+	// fileExtension is the extension of secret files.
+	fileExtension = ".yaml"
+)
+
+// ConfigFromData returns the directory contained in entry `directory` of
+// the given configuration data.
+func ConfigFromData(data map[string][]byte) (string, error) {
+	directory := strings.TrimSpace(string(data[configKeyDirectory]))
+	if directory == "" {
+		return "", errors.Errorf("entry %q must not be empty", configKeyDirectory)
+	}
+	return directory, nil
+}
+
+type provider struct {
+	directory string
+}
+
+// NewProvider returns a secret provider reading Kubernetes secret
+// manifests in YAML or JSON format from files `<name>.yaml` in the
+// given directory, e.g. a local directory in tests or a mounted config
+// map. Entries of `stringData` are merged into `data`.
+// It is not meant to be used in production.
+func NewProvider(directory string) secrets.SecretProvider {
+	return &provider{directory: directory}
+}
+
+// GetSecret implements secrets.SecretProvider.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return nil, errors.Errorf("invalid secret name %q", name)
+	}
+	path := filepath.Join(p.directory, name+fileExtension)
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read secret file %q", path)
+	}
+	secret := &v1.Secret{}
+	if err := yaml.Unmarshal(content, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret file %q", path)
+	}
+	secret.TypeMeta = metav1.TypeMeta{}
+	secret.SetName(name)
+	providers.StripMetadata(secret)
+	if len(secret.StringData) > 0 {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		for key, value := range secret.StringData {
+			secret.Data[key] = []byte(value)
+		}
+		secret.StringData = nil
+	}
+	if secret.Type == "" {
+		secret.Type = v1.SecretTypeOpaque
+	}
+	return secret, nil
+}
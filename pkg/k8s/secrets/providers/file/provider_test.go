@@ -0,0 +1,112 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ConfigFromData(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	directory, err1 := ConfigFromData(map[string][]byte{"directory": []byte(" /etc/secrets ")})
+	_, err2 := ConfigFromData(map[string][]byte{})
+
+	// VERIFY
+	assert.NilError(t, err1)
+	assert.Equal(t, "/etc/secrets", directory)
+	assert.Error(t, err2, `entry "directory" must not be empty`)
+}
+
+func Test_provider_GetSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	directory := t.TempDir()
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(directory, "git.yaml"), []byte(`
+apiVersion: v1
+kind: Secret
+metadata:
+  name: other
+  namespace: ns1
+  annotations:
+    tekton.dev/git-0: https://github.com
+type: kubernetes.io/basic-auth
+data:
+  username: dXNlcjE=
+stringData:
+  password: pass1
+`), 0644))
+	examinee := NewProvider(directory)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "git",
+			Annotations: map[string]string{"tekton.dev/git-0": "https://github.com"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("user1"),
+			"password": []byte("pass1"),
+		},
+	}, secret)
+}
+
+func Test_provider_GetSecret_DefaultType(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	directory := t.TempDir()
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(directory, "token.yaml"), []byte(`{"stringData":{"token":"token1"}}`), 0644))
+	examinee := NewProvider(directory)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "token")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, v1.SecretTypeOpaque, secret.Type)
+	assert.Equal(t, "token1", string(secret.Data["token"]))
+}
+
+func Test_provider_GetSecret_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee := NewProvider(t.TempDir())
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "unknown")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, secret == nil)
+}
+
+func Test_provider_GetSecret_InvalidName(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee := NewProvider(t.TempDir())
+
+	// EXERCISE
+	_, err := examinee.GetSecret(ctx, "../secret1")
+
+	// VERIFY
+	assert.Error(t, err, `invalid secret name "../secret1"`)
+}
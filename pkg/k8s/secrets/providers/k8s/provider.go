@@ -5,6 +5,7 @@ import (
 
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets/providers"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -26,8 +27,14 @@ func NewProvider(secretsClient corev1.SecretInterface, namespace string) secrets
 }
 
 // GetSecret returns secret with the given name from the defined namespace if existing.
+// Requests failing due to transient problems of the API server are retried.
 func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
-	secret, err := p.secretsClient.Get(ctx, name, metav1.GetOptions{})
+	var secret *v1.Secret
+	err := retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		var err error
+		secret, err = p.secretsClient.Get(ctx, name, metav1.GetOptions{})
+		return err
+	})
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			return nil, nil
@@ -8,9 +8,11 @@ import (
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"gotest.tools/assert"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func Test_provider_GetSecret_Existing(t *testing.T) {
@@ -93,6 +95,29 @@ func Test_provider_GetSecret_NotExisting(t *testing.T) {
 	assert.Assert(t, resultSecret == nil)
 }
 
+func Test_provider_GetSecret_RetriesOnTransientError(t *testing.T) {
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(fake.SecretOpaque("foo", "ns1"))
+	attempts := 0
+	cf.KubernetesClientset().PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if attempts == 1 {
+			return true, nil, k8serrors.NewTooManyRequests("try again", 0)
+		}
+		return false, nil, nil
+	})
+	examinee := NewProvider(cf.CoreV1().Secrets("ns1"), "ns1")
+
+	// EXERCISE
+	resultSecret, resultErr := examinee.GetSecret(ctx, "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "foo", resultSecret.GetName())
+	assert.Equal(t, 2, attempts)
+}
+
 func initProvider(namespace string, secrets ...*v1.Secret) secrets.SecretProvider {
 	objects := make([]runtime.Object, len(secrets))
 	for i, e := range secrets {
@@ -1,10 +1,27 @@
 package providers
 
 import (
+	"strings"
+
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// TypeKey is the key of the entry of an external secret defining the
+	// type of the resulting Kubernetes secret (see SecretFromEntries).
+	TypeKey = "_type"
+
+	// AnnotationKeyPrefix is the key prefix of entries of an external
+	// secret defining annotations of the resulting Kubernetes secret,
+	// e.g. `_annotation.tekton.dev/git-0`.
+	AnnotationKeyPrefix = "_annotation."
+
+	// LabelKeyPrefix is the key prefix of entries of an external secret
+	// defining labels of the resulting Kubernetes secret.
+	LabelKeyPrefix = "_label."
+)
+
 // StripMetadata strips the metadata from a secret
 func StripMetadata(secret *v1.Secret) {
 	secret.ObjectMeta = metav1.ObjectMeta{
@@ -13,3 +30,31 @@ func StripMetadata(secret *v1.Secret) {
 		Annotations: secret.GetAnnotations(),
 	}
 }
+
+// SecretFromEntries converts the key-value entries of a secret stored in
+// an external secret store like HashiCorp Vault to a Kubernetes secret
+// with the given name.
+// All entries become entries of the Kubernetes secret, except the entry
+// with key TypeKey defining the type (`Opaque` if not set) and the
+// entries with a key starting with AnnotationKeyPrefix or LabelKeyPrefix
+// defining the annotations and the labels of the Kubernetes secret.
+func SecretFromEntries(name string, entries map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Type:       v1.SecretTypeOpaque,
+		Data:       map[string][]byte{},
+	}
+	for key, value := range entries {
+		switch {
+		case key == TypeKey:
+			secret.Type = v1.SecretType(value)
+		case strings.HasPrefix(key, AnnotationKeyPrefix):
+			metav1.SetMetaDataAnnotation(&secret.ObjectMeta, strings.TrimPrefix(key, AnnotationKeyPrefix), value)
+		case strings.HasPrefix(key, LabelKeyPrefix):
+			metav1.SetMetaDataLabel(&secret.ObjectMeta, strings.TrimPrefix(key, LabelKeyPrefix), value)
+		default:
+			secret.Data[key] = []byte(value)
+		}
+	}
+	return secret
+}
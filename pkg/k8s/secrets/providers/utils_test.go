@@ -53,3 +53,33 @@ func Test_StripMetadata(t *testing.T) {
 
 	assert.DeepEqual(t, *expectedSecret, *resultSecret)
 }
+
+func Test_SecretFromEntries(t *testing.T) {
+	// EXERCISE
+	result := SecretFromEntries("foo", map[string]string{
+		"username":                     "user1",
+		"_type":                        "kubernetes.io/basic-auth",
+		"_annotation.tekton.dev/git-0": "https://github.com",
+		"_label.example.com/team":      "team1",
+	})
+
+	// VERIFY
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Annotations: map[string]string{"tekton.dev/git-0": "https://github.com"},
+			Labels:      map[string]string{"example.com/team": "team1"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{"username": []byte("user1")},
+	}, result)
+}
+
+func Test_SecretFromEntries_DefaultType(t *testing.T) {
+	// EXERCISE
+	result := SecretFromEntries("foo", map[string]string{"token": "token1"})
+
+	// VERIFY
+	assert.Equal(t, v1.SecretTypeOpaque, result.Type)
+	assert.Equal(t, "token1", string(result.Data["token"]))
+}
@@ -0,0 +1,283 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets/providers"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// Keys of the entries of a Vault configuration secret (see
+	// ConfigFromSecret).
+	configKeyAddress   = "address"
+	configKeyNamespace = "namespace"
+	configKeyMount     = "mount"
+	configKeyPath      = "path"
+	configKeyKVVersion = "kvVersion"
+	configKeyToken     = "token"
+	configKeyRoleID    = "roleId"
+	configKeySecretID  = "secretId"
+	configKeyAuthPath  = "authPath"
+
+	// defaultMount is the default mount path of the KV secrets engine.
+	defaultMount = "secret"
+
+	// defaultAuthPath is the default mount path of the AppRole auth
+	// method.
+	defaultAuthPath = "approle"
+
+	// defaultTimeout is the timeout of requests to Vault if no HTTP
+	// client is given.
+	defaultTimeout = 30 * time.Second
+)
+
+// Config is the configuration of a Vault secret provider.
+type Config struct {
+	// Address is the base URL of the Vault server, e.g.
+	// `https://vault.example.com:8200`.
+	Address string
+
+	// Namespace is the Vault Enterprise namespace. Optional.
+	Namespace string
+
+	// Mount is the mount path of the KV secrets engine.
+	// Defaults to `secret`.
+	Mount string
+
+	// Path is the path prefix below the mount path the secrets are read
+	// from. The name of a secret is appended.
+	Path string
+
+	// KVVersion is the version of the KV secrets engine, 1 or 2.
+	// Defaults to 2.
+	KVVersion int
+
+	// Token is the Vault token used to read secrets.
+	// Either Token or RoleID and SecretID must be set.
+	Token string
+
+	// RoleID is the role ID used to log in with the AppRole auth method.
+	RoleID string
+
+	// SecretID is the secret ID used to log in with the AppRole auth
+	// method.
+	SecretID string
+
+	// AuthPath is the mount path of the AppRole auth method.
+	// Defaults to `approle`.
+	AuthPath string
+}
+
+// ConfigFromSecret returns the Vault configuration contained in the given
+// secret. It has the entries `address`, `path` and optionally
+// `namespace`, `mount` and `kvVersion` defining the location of the
+// secrets, and either entry `token` or the entries `roleId`, `secretId`
+// and optionally `authPath` defining the authentication.
+func ConfigFromSecret(secret *v1.Secret) (*Config, error) {
+	get := func(key string) string {
+		return strings.TrimSpace(string(secret.Data[key]))
+	}
+	config := &Config{
+		Address:   get(configKeyAddress),
+		Namespace: get(configKeyNamespace),
+		Mount:     get(configKeyMount),
+		Path:      get(configKeyPath),
+		Token:     get(configKeyToken),
+		RoleID:    get(configKeyRoleID),
+		SecretID:  get(configKeySecretID),
+		AuthPath:  get(configKeyAuthPath),
+	}
+	switch get(configKeyKVVersion) {
+	case "", "2":
+		config.KVVersion = 2
+	case "1":
+		config.KVVersion = 1
+	default:
+		return nil, errors.Errorf("entry %q has invalid value %q: must be 1 or 2", configKeyKVVersion, get(configKeyKVVersion))
+	}
+	if config.Mount == "" {
+		config.Mount = defaultMount
+	}
+	if config.AuthPath == "" {
+		config.AuthPath = defaultAuthPath
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	address, err := url.Parse(c.Address)
+	if err != nil || (address.Scheme != "http" && address.Scheme != "https") || address.Host == "" {
+		return errors.Errorf("entry %q has invalid value %q: must be an HTTP(S) URL", configKeyAddress, c.Address)
+	}
+	if c.Path == "" {
+		return errors.Errorf("entry %q must not be empty", configKeyPath)
+	}
+	if c.Token == "" && (c.RoleID == "" || c.SecretID == "") {
+		return errors.Errorf("either entry %q or entries %q and %q must be set", configKeyToken, configKeyRoleID, configKeySecretID)
+	}
+	return nil
+}
+
+type provider struct {
+	config Config
+	client *http.Client
+
+	mutex sync.Mutex
+	token string
+}
+
+// NewProvider returns a secret provider reading secrets from the KV
+// secrets engine of HashiCorp Vault.
+// The secret with name `<name>` is read from `<mount>/<path>/<name>`.
+// The Vault secret is converted to a Kubernetes secret with
+// providers.SecretFromEntries.
+// If client is nil, a client with a default timeout is used.
+func NewProvider(config Config, client *http.Client) secrets.SecretProvider {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &provider{
+		config: config,
+		client: client,
+		token:  config.Token,
+	}
+}
+
+// GetSecret implements secrets.SecretProvider.
+func (p *provider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	token, err := p.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secretPath := strings.Trim(p.config.Path, "/") + "/" + name
+	var apiPath string
+	if p.config.KVVersion == 1 {
+		apiPath = strings.Trim(p.config.Mount, "/") + "/" + secretPath
+	} else {
+		apiPath = strings.Trim(p.config.Mount, "/") + "/data/" + secretPath
+	}
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	found, err := p.request(ctx, http.MethodGet, apiPath, token, nil, &response)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read secret %q from Vault", secretPath)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	data := response.Data
+	if p.config.KVVersion != 1 {
+		var kv2 struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &kv2); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode secret %q from Vault", secretPath)
+		}
+		data = kv2.Data
+	}
+	var entries map[string]interface{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret %q from Vault", secretPath)
+	}
+	if entries == nil {
+		// deleted secret version
+		return nil, nil
+	}
+	return toSecret(name, entries)
+}
+
+// toSecret converts the entries of a Vault secret to a Kubernetes secret.
+func toSecret(name string, entries map[string]interface{}) (*v1.Secret, error) {
+	stringEntries := make(map[string]string, len(entries))
+	for key, value := range entries {
+		stringValue, ok := value.(string)
+		if !ok {
+			return nil, errors.Errorf("secret %q from Vault: entry %q is not a string", name, key)
+		}
+		stringEntries[key] = stringValue
+	}
+	return providers.SecretFromEntries(name, stringEntries), nil
+}
+
+// login returns the Vault token, logging in with the AppRole auth method
+// if no static token is configured.
+func (p *provider) login(ctx context.Context) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.token != "" {
+		return p.token, nil
+	}
+	body, err := json.Marshal(map[string]string{
+		"role_id":   p.config.RoleID,
+		"secret_id": p.config.SecretID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode Vault login request")
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	authPath := "auth/" + strings.Trim(p.config.AuthPath, "/") + "/login"
+	found, err := p.request(ctx, http.MethodPost, authPath, "", body, &response)
+	if err == nil && (!found || response.Auth.ClientToken == "") {
+		err = errors.New("no client token returned")
+	}
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to log in to Vault")
+	}
+	p.token = response.Auth.ClientToken
+	return p.token, nil
+}
+
+// request sends a request to the Vault API and decodes the JSON response
+// into result. It returns false if Vault responds with status 404.
+func (p *provider) request(ctx context.Context, method, apiPath, token string, body []byte, result interface{}) (bool, error) {
+	requestURL := strings.TrimSuffix(p.config.Address, "/") + "/v1/" + apiPath
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create request")
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("X-Vault-Token", token)
+	}
+	if p.config.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", p.config.Namespace)
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return false, errors.Wrap(err, "request failed")
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected response status %q", response.Status)
+	}
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return false, errors.Wrap(err, "failed to decode response")
+	}
+	return true, nil
+}
@@ -0,0 +1,279 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeVault is a minimal Vault server serving the given secrets for the
+// given token. It supports the AppRole login.
+type fakeVault struct {
+	token   string
+	secrets map[string]interface{}
+	logins  int
+}
+
+func (v *fakeVault) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/v1/auth/approle/login" {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["role_id"] != "role1" || body["secret_id"] != "secret1" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.logins++
+		json.NewEncoder(rw).Encode(map[string]interface{}{"auth": map[string]string{"client_token": v.token}})
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != v.token {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+	secret, ok := v.secrets[r.URL.Path]
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(rw).Encode(map[string]interface{}{"data": secret})
+}
+
+func Test_ConfigFromSecret(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		data           map[string]string
+		expectedConfig *Config
+		expectedError  string
+	}{
+		{
+			name: "token",
+			data: map[string]string{"address": "https://vault.example.com", "path": "teams/team1", "token": "token1"},
+			expectedConfig: &Config{
+				Address:   "https://vault.example.com",
+				Mount:     "secret",
+				Path:      "teams/team1",
+				KVVersion: 2,
+				Token:     "token1",
+				AuthPath:  "approle",
+			},
+		},
+		{
+			name: "approle",
+			data: map[string]string{
+				"address": "https://vault.example.com", "namespace": "ns1", "mount": "kv", "path": "team1", "kvVersion": "1",
+				"roleId": "role1", "secretId": "secret1", "authPath": "approle2",
+			},
+			expectedConfig: &Config{
+				Address:   "https://vault.example.com",
+				Namespace: "ns1",
+				Mount:     "kv",
+				Path:      "team1",
+				KVVersion: 1,
+				RoleID:    "role1",
+				SecretID:  "secret1",
+				AuthPath:  "approle2",
+			},
+		},
+		{
+			name:          "invalid_address",
+			data:          map[string]string{"address": "vault.example.com", "path": "team1", "token": "token1"},
+			expectedError: `entry "address" has invalid value "vault.example.com": must be an HTTP(S) URL`,
+		},
+		{
+			name:          "no_path",
+			data:          map[string]string{"address": "https://vault.example.com", "token": "token1"},
+			expectedError: `entry "path" must not be empty`,
+		},
+		{
+			name:          "no_auth",
+			data:          map[string]string{"address": "https://vault.example.com", "path": "team1", "roleId": "role1"},
+			expectedError: `either entry "token" or entries "roleId" and "secretId" must be set`,
+		},
+		{
+			name:          "invalid_kv_version",
+			data:          map[string]string{"address": "https://vault.example.com", "path": "team1", "token": "token1", "kvVersion": "3"},
+			expectedError: `entry "kvVersion" has invalid value "3": must be 1 or 2`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			secret := &v1.Secret{Data: map[string][]byte{}}
+			for key, value := range tc.data {
+				secret.Data[key] = []byte(value)
+			}
+
+			// EXERCISE
+			config, err := ConfigFromSecret(secret)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func Test_provider_GetSecret_KV2(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	vault := &fakeVault{
+		token: "token1",
+		secrets: map[string]interface{}{
+			"/v1/secret/data/team1/git": map[string]interface{}{
+				"data": map[string]string{
+					"username":                     "user1",
+					"password":                     "pass1",
+					"_type":                        "kubernetes.io/basic-auth",
+					"_annotation.tekton.dev/git-0": "https://github.com",
+					"_label.example.com/team":      "team1",
+				},
+			},
+		},
+	}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	examinee := NewProvider(Config{Address: server.URL, Mount: "secret", Path: "/team1/", KVVersion: 2, Token: "token1"}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "git",
+			Annotations: map[string]string{"tekton.dev/git-0": "https://github.com"},
+			Labels:      map[string]string{"example.com/team": "team1"},
+		},
+		Type: v1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			"username": []byte("user1"),
+			"password": []byte("pass1"),
+		},
+	}, secret)
+}
+
+func Test_provider_GetSecret_KV1AppRole(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	vault := &fakeVault{
+		token: "token2",
+		secrets: map[string]interface{}{
+			"/v1/kv/team1/token": map[string]string{"token": "secret-token"},
+		},
+	}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	examinee := NewProvider(Config{
+		Address:   server.URL,
+		Mount:     "kv",
+		Path:      "team1",
+		KVVersion: 1,
+		RoleID:    "role1",
+		SecretID:  "secret1",
+		AuthPath:  "approle",
+	}, server.Client())
+
+	// EXERCISE
+	secret1, err1 := examinee.GetSecret(ctx, "token")
+	secret2, err2 := examinee.GetSecret(ctx, "token")
+
+	// VERIFY
+	assert.NilError(t, err1)
+	assert.NilError(t, err2)
+	assert.Equal(t, v1.SecretTypeOpaque, secret1.Type)
+	assert.Equal(t, "secret-token", string(secret1.Data["token"]))
+	assert.DeepEqual(t, secret1, secret2)
+	assert.Equal(t, 1, vault.logins)
+}
+
+func Test_provider_GetSecret_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeVault{token: "token1"})
+	defer server.Close()
+	examinee := NewProvider(Config{Address: server.URL, Mount: "secret", Path: "team1", KVVersion: 2, Token: "token1"}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "unknown")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, secret == nil)
+}
+
+func Test_provider_GetSecret_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeVault{token: "token1"})
+	defer server.Close()
+	examinee := NewProvider(Config{Address: server.URL, Mount: "secret", Path: "team1", KVVersion: 2, Token: "wrong"}, nil)
+
+	// EXERCISE
+	secret, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.Error(t, err, `failed to read secret "team1/git" from Vault: unexpected response status "403 Forbidden"`)
+	assert.Assert(t, secret == nil)
+}
+
+func Test_provider_GetSecret_LoginFails(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	server := httptest.NewServer(&fakeVault{token: "token1"})
+	defer server.Close()
+	examinee := NewProvider(Config{Address: server.URL, Mount: "secret", Path: "team1", KVVersion: 2, RoleID: "role1", SecretID: "wrong", AuthPath: "approle"}, nil)
+
+	// EXERCISE
+	_, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.Error(t, err, `failed to log in to Vault: unexpected response status "400 Bad Request"`)
+}
+
+func Test_provider_GetSecret_NonStringEntry(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	vault := &fakeVault{
+		token: "token1",
+		secrets: map[string]interface{}{
+			"/v1/secret/data/team1/git": map[string]interface{}{
+				"data": map[string]interface{}{"port": 22},
+			},
+		},
+	}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	examinee := NewProvider(Config{Address: server.URL, Mount: "secret", Path: "team1", KVVersion: 2, Token: "token1"}, nil)
+
+	// EXERCISE
+	_, err := examinee.GetSecret(ctx, "git")
+
+	// VERIFY
+	assert.Error(t, err, `secret "git" from Vault: entry "port" is not a string`)
+}
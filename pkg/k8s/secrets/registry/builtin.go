@@ -0,0 +1,112 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	awssecretsmanagerprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/awssecretsmanager"
+	fileprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/file"
+	k8sprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/k8s"
+	vaultprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/vault"
+	"github.com/pkg/errors"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// newKubernetesProvider creates a provider reading secrets from the
+// tenant namespace.
+func newKubernetesProvider(ctx context.Context, opts Options) (secrets.SecretProvider, error) {
+	return k8sprovider.NewProvider(opts.Client.Secrets(opts.Namespace), opts.Namespace), nil
+}
+
+// newVaultProvider creates a provider reading secrets from HashiCorp
+// Vault. The configuration of the client owning the tenant namespace
+// takes precedence over the installation-wide configuration.
+func newVaultProvider(ctx context.Context, opts Options) (secrets.SecretProvider, error) {
+	config, err := loadVaultConfig(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("using Vault secret provider (%s) for namespace %q", config.Address, opts.Namespace)
+	return vaultprovider.NewProvider(*config, nil), nil
+}
+
+// loadVaultConfig returns the Vault configuration of the client owning
+// the tenant namespace, which is defined by the secret referenced by
+// annotation AnnotationVaultSecret of the client namespace. If the
+// annotation is not set, the installation-wide configuration is used
+// if present.
+// The client namespace is determined via label LabelOwnerClientNamespace
+// of the tenant namespace.
+func loadVaultConfig(ctx context.Context, opts Options) (*vaultprovider.Config, error) {
+	configError := func(err error) error {
+		return NewConfigError(stewardv1alpha1.SecretProviderVault, err)
+	}
+	fromInstallationConfig := func(cause error) (*vaultprovider.Config, error) {
+		if len(opts.Config) == 0 {
+			return nil, configError(cause)
+		}
+		config, err := vaultprovider.ConfigFromSecret(&corev1api.Secret{Data: opts.Config})
+		if err != nil {
+			return nil, configError(errors.WithMessage(err, "invalid configuration"))
+		}
+		return config, nil
+	}
+
+	namespaceIfce := opts.Client.Namespaces()
+	tenantNamespace, err := namespaceIfce.Get(ctx, opts.Namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get namespace %q", opts.Namespace)
+	}
+	clientNamespaceName := tenantNamespace.GetLabels()[stewardv1alpha1.LabelOwnerClientNamespace]
+	if clientNamespaceName == "" {
+		return fromInstallationConfig(errors.Errorf("namespace %q has no client namespace", opts.Namespace))
+	}
+	clientNamespace, err := namespaceIfce.Get(ctx, clientNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get client namespace %q", clientNamespaceName)
+	}
+	secretName := strings.TrimSpace(clientNamespace.GetAnnotations()[stewardv1alpha1.AnnotationVaultSecret])
+	if secretName == "" {
+		return fromInstallationConfig(errors.Errorf(
+			"client namespace %q has no annotation %q", clientNamespaceName, stewardv1alpha1.AnnotationVaultSecret,
+		))
+	}
+	secret, err := opts.Client.Secrets(clientNamespaceName).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get Vault secret %q of client namespace %q", secretName, clientNamespaceName)
+		if k8serrors.IsNotFound(errors.Cause(err)) {
+			return nil, configError(err)
+		}
+		return nil, err
+	}
+	config, err := vaultprovider.ConfigFromSecret(secret)
+	if err != nil {
+		return nil, configError(errors.WithMessagef(err, "invalid Vault secret \"%s/%s\"", clientNamespaceName, secretName))
+	}
+	return config, nil
+}
+
+// newAWSSecretsManagerProvider creates a provider reading secrets from
+// AWS Secrets Manager as configured installation-wide.
+func newAWSSecretsManagerProvider(ctx context.Context, opts Options) (secrets.SecretProvider, error) {
+	config, err := awssecretsmanagerprovider.ConfigFromData(opts.Config)
+	if err != nil {
+		return nil, NewConfigError(stewardv1alpha1.SecretProviderAWSSecretsManager, errors.WithMessage(err, "invalid configuration"))
+	}
+	return awssecretsmanagerprovider.NewProvider(*config, nil), nil
+}
+
+// newFileProvider creates a provider reading secrets from the directory
+// configured installation-wide.
+func newFileProvider(ctx context.Context, opts Options) (secrets.SecretProvider, error) {
+	directory, err := fileprovider.ConfigFromData(opts.Config)
+	if err != nil {
+		return nil, NewConfigError(stewardv1alpha1.SecretProviderFile, errors.WithMessage(err, "invalid configuration"))
+	}
+	return fileprovider.NewProvider(directory), nil
+}
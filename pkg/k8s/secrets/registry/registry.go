@@ -0,0 +1,154 @@
+// Package registry maintains the secret providers pipeline runs can read
+// their secrets from.
+//
+// A secret provider is an implementation of interface
+// secrets.SecretProvider:
+//
+//	type SecretProvider interface {
+//		// GetSecret returns a secret by its name
+//		// returns nil,nil if secret is not found
+//		GetSecret(ctx context.Context, name string) (*v1.Secret, error)
+//	}
+//
+// Providers are created per pipeline run by a Factory registered under a
+// unique name. The run controller uses the provider selected via
+// annotation `steward.sap.com/secret-provider` of the pipeline run or
+// its tenant namespace, or the default provider configured with flag
+// `-secret-provider`.
+//
+// Built-in providers are `kubernetes`, `vault`, `aws-secrets-manager` and
+// `file`. Further providers can be added without changes to Steward by
+// registering them from the `init()` function of a package linked into
+// the run controller:
+//
+//	func init() {
+//		registry.Register("my-provider", func(ctx context.Context, opts registry.Options) (secrets.SecretProvider, error) {
+//			return newMyProvider(opts.Config)
+//		})
+//	}
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/pkg/errors"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Options are the options passed to provider factories.
+type Options struct {
+	// Client is the client of the Kubernetes core API.
+	Client corev1client.CoreV1Interface
+
+	// Namespace is the tenant namespace of the pipeline run the secrets
+	// are provided for.
+	Namespace string
+
+	// Config is the installation-wide configuration of the provider
+	// (see SplitConfig). It is nil if there is none.
+	Config map[string][]byte
+}
+
+// Factory creates a secret provider for the pipeline run in the
+// namespace given by the options.
+// Errors due to invalid configuration should be classified with
+// result ErrorConfig (see NewConfigError).
+type Factory func(ctx context.Context, opts Options) (secrets.SecretProvider, error)
+
+var (
+	factoriesMutex sync.Mutex
+	factories      = map[string]Factory{
+		stewardv1alpha1.SecretProviderKubernetes:        newKubernetesProvider,
+		stewardv1alpha1.SecretProviderVault:             newVaultProvider,
+		stewardv1alpha1.SecretProviderAWSSecretsManager: newAWSSecretsManagerProvider,
+		stewardv1alpha1.SecretProviderFile:              newFileProvider,
+	}
+)
+
+// Register registers a secret provider. It is meant to be called from
+// `init()` functions of packages providing secret providers.
+// It panics if a provider with the same name has been registered already.
+func Register(name string, factory Factory) {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	if name == "" || factory == nil {
+		panic("secret provider registry: provider name and factory must not be empty")
+	}
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("secret provider registry: provider %q registered twice", name))
+	}
+	factories[name] = factory
+}
+
+// IsRegistered returns whether a secret provider with the given name has
+// been registered.
+func IsRegistered(name string) bool {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	_, ok := factories[name]
+	return ok
+}
+
+// Names returns the sorted names of all registered secret providers.
+func Names() []string {
+	factoriesMutex.Lock()
+	defer factoriesMutex.Unlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates a secret provider using the factory registered with the
+// given name.
+func New(ctx context.Context, name string, opts Options) (secrets.SecretProvider, error) {
+	factoriesMutex.Lock()
+	factory, ok := factories[name]
+	factoriesMutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown secret provider %q", name)
+	}
+	return factory(ctx, opts)
+}
+
+// SplitConfig splits the installation-wide configuration of all secret
+// providers, e.g. the data of a secret, into the configurations of the
+// single providers: entry `<name>.<key>` becomes entry `<key>` of the
+// configuration of provider `<name>`. Entries without provider name are
+// ignored.
+func SplitConfig(data map[string][]byte) map[string]map[string][]byte {
+	result := map[string]map[string][]byte{}
+	for key, value := range data {
+		parts := strings.SplitN(key, ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		if result[parts[0]] == nil {
+			result[parts[0]] = map[string][]byte{}
+		}
+		result[parts[0]][parts[1]] = value
+	}
+	return result
+}
+
+// NewConfigError returns the given error of the secret provider with the
+// given name classified as configuration error.
+func NewConfigError(name string, err error) error {
+	return serrors.Classify(
+		errors.WithMessagef(err, "secret provider %q", name),
+		stewardv1alpha1.ResultErrorConfig,
+	)
+}
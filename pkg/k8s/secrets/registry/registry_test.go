@@ -0,0 +1,277 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+type staticProvider struct {
+	secret *v1.Secret
+}
+
+func (p *staticProvider) GetSecret(ctx context.Context, name string) (*v1.Secret, error) {
+	return p.secret, nil
+}
+
+func Test_Register(t *testing.T) {
+	// not parallel: modifies the global registry
+
+	// SETUP
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret1"}}
+	var passedOpts Options
+	factory := func(ctx context.Context, opts Options) (secrets.SecretProvider, error) {
+		passedOpts = opts
+		return &staticProvider{secret: secret}, nil
+	}
+
+	// EXERCISE
+	Register("test-provider", factory)
+	defer func() {
+		factoriesMutex.Lock()
+		delete(factories, "test-provider")
+		factoriesMutex.Unlock()
+	}()
+	provider, err := New(context.Background(), "test-provider", Options{Namespace: "ns1", Config: map[string][]byte{"key": []byte("value")}})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, IsRegistered("test-provider"))
+	assert.Equal(t, "ns1", passedOpts.Namespace)
+	assert.Equal(t, "value", string(passedOpts.Config["key"]))
+	result, err := provider.GetSecret(context.Background(), "secret1")
+	assert.NilError(t, err)
+	assert.Equal(t, secret, result)
+	assert.Assert(t, func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		Register("test-provider", factory)
+		return
+	}())
+}
+
+func Test_Register_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		providerName string
+		factory      Factory
+	}{
+		{name: "empty_name", factory: newFileProvider},
+		{name: "nil_factory", providerName: "provider1"},
+		{name: "builtin", providerName: stewardv1alpha1.SecretProviderKubernetes, factory: newFileProvider},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			panicked := func() (panicked bool) {
+				defer func() { panicked = recover() != nil }()
+				Register(tc.providerName, tc.factory)
+				return
+			}()
+
+			// VERIFY
+			assert.Assert(t, panicked)
+		})
+	}
+}
+
+func Test_Names(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	names := Names()
+
+	// VERIFY
+	for _, name := range []string{"aws-secrets-manager", "file", "kubernetes", "vault"} {
+		assert.Assert(t, IsRegistered(name), name)
+	}
+	for i := 1; i < len(names); i++ {
+		assert.Assert(t, names[i-1] < names[i])
+	}
+}
+
+func Test_New_Unknown(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	_, err := New(context.Background(), "unknown", Options{})
+
+	// VERIFY
+	assert.Error(t, err, `unknown secret provider "unknown"`)
+}
+
+func Test_SplitConfig(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := SplitConfig(map[string][]byte{
+		"vault.address":              []byte("https://vault.example.com"),
+		"vault.path":                 []byte("team1"),
+		"aws-secrets-manager.region": []byte("eu-central-1"),
+		"noprovider":                 []byte("x"),
+		".key":                       []byte("x"),
+	})
+
+	// VERIFY
+	assert.DeepEqual(t, map[string]map[string][]byte{
+		"vault": {
+			"address": []byte("https://vault.example.com"),
+			"path":    []byte("team1"),
+		},
+		"aws-secrets-manager": {
+			"region": []byte("eu-central-1"),
+		},
+	}, result)
+}
+
+func Test_New_Kubernetes(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "ns1"}}
+	client := kubefake.NewSimpleClientset(secret)
+
+	// EXERCISE
+	provider, err := New(context.Background(), "kubernetes", Options{Client: client.CoreV1(), Namespace: "ns1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := provider.GetSecret(context.Background(), "secret1")
+	assert.NilError(t, err)
+	assert.Equal(t, "secret1", result.GetName())
+}
+
+func Test_New_Vault(t *testing.T) {
+	t.Parallel()
+
+	vault := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(vault.Close)
+
+	tenantNamespace := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "ns1",
+		Labels: map[string]string{stewardv1alpha1.LabelOwnerClientNamespace: "client1"},
+	}}
+	clientNamespace := func(annotations map[string]string) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "client1", Annotations: annotations}}
+	}
+	vaultSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault1", Namespace: "client1"},
+		Data: map[string][]byte{
+			"address": []byte(vault.URL),
+			"path":    []byte("team1"),
+			"token":   []byte("token1"),
+		},
+	}
+	installationConfig := map[string][]byte{
+		"address": []byte(vault.URL),
+		"path":    []byte("teams"),
+		"token":   []byte("token2"),
+	}
+
+	for _, tc := range []struct {
+		name             string
+		objects          []runtime.Object
+		config           map[string][]byte
+		expectedError    string
+		expectedErrClass stewardv1alpha1.Result
+	}{
+		{
+			name:    "client_config",
+			objects: []runtime.Object{tenantNamespace, clientNamespace(map[string]string{stewardv1alpha1.AnnotationVaultSecret: "vault1"}), vaultSecret},
+			config:  installationConfig,
+		},
+		{
+			name:    "installation_config",
+			objects: []runtime.Object{tenantNamespace, clientNamespace(nil)},
+			config:  installationConfig,
+		},
+		{
+			name:             "no_config",
+			objects:          []runtime.Object{tenantNamespace, clientNamespace(nil)},
+			expectedError:    `secret provider "vault": client namespace "client1" has no annotation "steward.sap.com/vault-secret"`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:             "invalid_installation_config",
+			objects:          []runtime.Object{tenantNamespace, clientNamespace(nil)},
+			config:           map[string][]byte{"address": []byte(vault.URL)},
+			expectedError:    `secret provider "vault": invalid configuration: entry "path" must not be empty`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:             "client_secret_not_found",
+			objects:          []runtime.Object{tenantNamespace, clientNamespace(map[string]string{stewardv1alpha1.AnnotationVaultSecret: "vault1"})},
+			config:           installationConfig,
+			expectedError:    `secret provider "vault": failed to get Vault secret "vault1" of client namespace "client1": secrets "vault1" not found`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			client := kubefake.NewSimpleClientset(tc.objects...)
+
+			// EXERCISE
+			provider, err := New(context.Background(), "vault", Options{Client: client.CoreV1(), Namespace: "ns1", Config: tc.config})
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Equal(t, tc.expectedErrClass, serrors.GetClass(err))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, provider != nil)
+		})
+	}
+}
+
+func Test_New_ConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		provider      string
+		expectedError string
+	}{
+		{
+			name:          "aws_secrets_manager",
+			provider:      "aws-secrets-manager",
+			expectedError: `secret provider "aws-secrets-manager": invalid configuration: entry "region" must not be empty`,
+		},
+		{
+			name:          "file",
+			provider:      "file",
+			expectedError: `secret provider "file": invalid configuration: entry "directory" must not be empty`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			_, err := New(context.Background(), tc.provider, Options{Namespace: "ns1"})
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+		})
+	}
+}
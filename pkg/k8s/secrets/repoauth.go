@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TokenKey is the key of the token in token-type repository auth
+	// secrets, e.g. a personal access token or a GitHub App installation
+	// token.
+	TokenKey = "token"
+
+	// DefaultTokenUsername is the username used for authentication with
+	// token-type repository auth secrets not containing key `username`.
+	// GitHub accepts it for all kinds of tokens.
+	DefaultTokenUsername = "x-access-token"
+)
+
+// IsTokenSecret returns true if the given secret is a token-type
+// repository auth secret, i.e. contains key `token` but no password.
+func IsTokenSecret(secret *v1.Secret) bool {
+	if secret.Type == v1.SecretTypeBasicAuth {
+		return false
+	}
+	if _, ok := secret.Data[v1.BasicAuthPasswordKey]; ok {
+		return false
+	}
+	_, ok := secret.Data[TokenKey]
+	return ok
+}
+
+// RepoCredentials returns the username and password for basic
+// authentication at a Git server contained in the given repository auth
+// secret.
+// For token-type secrets (see IsTokenSecret) the password is the token
+// and the username is the value of key `username` or
+// DefaultTokenUsername if there is no such key.
+// For other secrets the values of the basic-auth keys are returned.
+func RepoCredentials(secret *v1.Secret) (username, password string) {
+	username = string(secret.Data[v1.BasicAuthUsernameKey])
+	if !IsTokenSecret(secret) {
+		return username, string(secret.Data[v1.BasicAuthPasswordKey])
+	}
+	if username == "" {
+		username = DefaultTokenUsername
+	}
+	return username, string(secret.Data[TokenKey])
+}
+
+// TokenToBasicAuthTransformer returns a secret transformer function that
+// converts token-type repository auth secrets (see IsTokenSecret) into
+// secrets of type `kubernetes.io/basic-auth`, which Tekton provides as Git
+// credentials. Other secrets are kept unchanged.
+func TokenToBasicAuthTransformer() SecretTransformer {
+	return func(secret *v1.Secret) {
+		if !IsTokenSecret(secret) {
+			return
+		}
+		username, password := RepoCredentials(secret)
+		secret.Type = v1.SecretTypeBasicAuth
+		secret.Data = map[string][]byte{
+			v1.BasicAuthUsernameKey: []byte(username),
+			v1.BasicAuthPasswordKey: []byte(password),
+		}
+		secret.StringData = nil
+	}
+}
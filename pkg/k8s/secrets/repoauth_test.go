@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_RepoCredentials(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name             string
+		secretType       v1.SecretType
+		data             map[string]string
+		expectedUsername string
+		expectedPassword string
+	}{
+		{
+			name:             "basic_auth",
+			secretType:       v1.SecretTypeBasicAuth,
+			data:             map[string]string{"username": "user1", "password": "pwd1"},
+			expectedUsername: "user1",
+			expectedPassword: "pwd1",
+		}, {
+			name:             "basic_auth_with_token_key",
+			secretType:       v1.SecretTypeBasicAuth,
+			data:             map[string]string{"username": "user1", "token": "token1"},
+			expectedUsername: "user1",
+			expectedPassword: "",
+		}, {
+			name:             "token_only",
+			secretType:       v1.SecretTypeOpaque,
+			data:             map[string]string{"token": "token1"},
+			expectedUsername: DefaultTokenUsername,
+			expectedPassword: "token1",
+		}, {
+			name:             "token_with_username",
+			secretType:       v1.SecretTypeOpaque,
+			data:             map[string]string{"username": "oauth2", "token": "token1"},
+			expectedUsername: "oauth2",
+			expectedPassword: "token1",
+		}, {
+			name:             "opaque_with_password",
+			secretType:       v1.SecretTypeOpaque,
+			data:             map[string]string{"username": "user1", "password": "pwd1", "token": "token1"},
+			expectedUsername: "user1",
+			expectedPassword: "pwd1",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			secret := fake.SecretWithType("secret1", "ns1", tc.secretType)
+			secret.Data = toBytes(tc.data)
+
+			// EXERCISE
+			username, password := RepoCredentials(secret)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedUsername, username)
+			assert.Equal(t, tc.expectedPassword, password)
+		})
+	}
+}
+
+func Test_TokenToBasicAuthTransformer_TokenSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	orig := fake.SecretOpaque("secret1", "ns1")
+	orig.SetAnnotations(map[string]string{"key1": "value1"})
+	orig.Data = toBytes(map[string]string{"token": "token1", "other": "value2"})
+	transformed := orig.DeepCopy()
+
+	// EXERCISE
+	TokenToBasicAuthTransformer()(transformed)
+
+	// VERIFY
+	expected := orig.DeepCopy()
+	expected.Type = v1.SecretTypeBasicAuth
+	expected.Data = toBytes(map[string]string{"username": DefaultTokenUsername, "password": "token1"})
+	assert.DeepEqual(t, expected, transformed)
+}
+
+func Test_TokenToBasicAuthTransformer_BasicAuthSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	orig := fake.SecretWithType("secret1", "ns1", v1.SecretTypeBasicAuth)
+	orig.Data = toBytes(map[string]string{"username": "user1", "password": "pwd1"})
+	transformed := orig.DeepCopy()
+
+	// EXERCISE
+	TokenToBasicAuthTransformer()(transformed)
+
+	// VERIFY
+	assert.DeepEqual(t, orig, transformed)
+}
+
+func toBytes(data map[string]string) map[string][]byte {
+	result := map[string][]byte{}
+	for key, value := range data {
+		result[key] = []byte(value)
+	}
+	return result
+}
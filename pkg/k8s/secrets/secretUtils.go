@@ -2,8 +2,14 @@ package secrets
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +20,7 @@ import (
 type SecretHelper interface {
 	CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]string, error)
 	CreateSecret(ctx context.Context, secret *v1.Secret) (*v1.Secret, error)
+	RefreshSecrets(ctx context.Context) ([]string, error)
 	IsNotFound(err error) bool
 }
 
@@ -41,6 +48,7 @@ func NewSecretHelper(provider SecretProvider, namespace string, client corev1.Se
 // filter can be defined to copy only dedicated secrets
 // transformers can be defined to transform the secrets before they are stored
 // returns a list of the secret names (after transformation) which were stored
+// Each copy is annotated with the name and a hash of its source secret, see RefreshSecrets.
 // In case of an error the copying is stopped. The result list contains the secrets already copied
 // before the error occured. There is no rollback done by this function.
 func (h *secretHelper) CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]string, error) {
@@ -56,9 +64,12 @@ func (h *secretHelper) CopySecrets(ctx context.Context, secretNames []string, fi
 		if filter != nil && !filter(secret) {
 			continue
 		}
+		hash := sourceHash(secret)
 		for _, transformer := range transformers {
 			transformer(secret)
 		}
+		SetAnnotationTransformer(api.AnnotationSecretSource, secretName)(secret)
+		SetAnnotationTransformer(api.AnnotationSecretSourceHash, hash)(secret)
 		storedSecret, err := h.CreateSecret(ctx, secret)
 		if err != nil {
 			return storedSecretNames, err
@@ -76,6 +87,11 @@ func (e *notFoundError) Error() string {
 	return fmt.Sprintf("secret not found: '%s'", e.name)
 }
 
+// Is makes `errors.Is(err, serrors.ErrSecretMissing)` return true.
+func (e *notFoundError) Is(target error) bool {
+	return target == serrors.ErrSecretMissing
+}
+
 // NewNotFoundError returns a not found error
 func NewNotFoundError(name string) error {
 	return &notFoundError{name: name}
@@ -99,6 +115,9 @@ func (h *secretHelper) IsNotFound(err error) bool {
 }
 
 // CreateSecret creates the given secret in the storage the underlying client is connected to.
+// Requests failing due to transient problems of the API server are retried.
+// If a resource quota would be exceeded, the returned error is marked with
+// serrors.ErrQuotaExceeded.
 func (h *secretHelper) CreateSecret(ctx context.Context, secret *v1.Secret) (*v1.Secret, error) {
 	if h.testing != nil && h.testing.createSecretStub != nil {
 		return h.testing.createSecretStub(ctx, secret)
@@ -109,5 +128,98 @@ func (h *secretHelper) CreateSecret(ctx context.Context, secret *v1.Secret) (*v1
 	newSecret.SetNamespace(h.namespace)
 	newSecret.SetLabels(secret.GetLabels())
 	newSecret.SetAnnotations(secret.GetAnnotations())
-	return h.client.Create(ctx, newSecret, metav1.CreateOptions{})
+	var storedSecret *v1.Secret
+	err := retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		var err error
+		storedSecret, err = h.client.Create(ctx, newSecret, metav1.CreateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, serrors.MarkIfQuotaExceeded(err)
+	}
+	return storedSecret, nil
+}
+
+// RefreshSecrets updates the copies of secrets in the storage the
+// underlying client is connected to whose source secret has changed
+// since it has been copied, as indicated by the source hash annotated
+// by CopySecrets.
+// Only the data of copies is updated, while names, labels and
+// annotations are kept. Token-type sources of basic-auth copies are
+// converted like by TokenToBasicAuthTransformer. Copies of source
+// secrets that do not exist anymore are kept unchanged. Secrets
+// containing GitHub App installation tokens are refreshed separately
+// and are skipped.
+// It returns the names of the updated copies. In case of an error the
+// refreshing is stopped. The result list contains the secrets already
+// refreshed before the error occured.
+func (h *secretHelper) RefreshSecrets(ctx context.Context) ([]string, error) {
+	list, err := h.client.List(ctx, metav1.ListOptions{LabelSelector: "!" + api.LabelGitHubAppToken})
+	if err != nil {
+		return nil, err
+	}
+	var refreshedSecretNames []string
+	for i := range list.Items {
+		target := &list.Items[i]
+		sourceName := target.GetAnnotations()[api.AnnotationSecretSource]
+		if sourceName == "" {
+			continue
+		}
+		source, err := h.provider.GetSecret(ctx, sourceName)
+		if err != nil {
+			return refreshedSecretNames, err
+		}
+		if source == nil {
+			continue
+		}
+		hash := sourceHash(source)
+		if hash == target.GetAnnotations()[api.AnnotationSecretSourceHash] {
+			continue
+		}
+		if target.Type == v1.SecretTypeBasicAuth {
+			TokenToBasicAuthTransformer()(source)
+		}
+		if source.Type != target.Type {
+			return refreshedSecretNames, fmt.Errorf("cannot refresh secret %q: type of source secret %q changed from %q to %q", target.GetName(), sourceName, target.Type, source.Type)
+		}
+		target.Data = source.Data
+		target.StringData = source.StringData
+		metav1.SetMetaDataAnnotation(&target.ObjectMeta, api.AnnotationSecretSourceHash, hash)
+		err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+			_, err := h.client.Update(ctx, target, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			return refreshedSecretNames, err
+		}
+		refreshedSecretNames = append(refreshedSecretNames, target.GetName())
+	}
+	return refreshedSecretNames, nil
+}
+
+// sourceHash returns a hash of the type and data of the given secret.
+func sourceHash(secret *v1.Secret) string {
+	hash := sha256.New()
+	hash.Write([]byte(secret.Type))
+	for _, data := range []map[string][]byte{secret.Data, stringDataBytes(secret.StringData)} {
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(hash, "\x00%s\x00%d\x00", key, len(data[key]))
+			hash.Write(data[key])
+		}
+		hash.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func stringDataBytes(stringData map[string]string) map[string][]byte {
+	result := make(map[string][]byte, len(stringData))
+	for key, value := range stringData {
+		result[key] = []byte(value)
+	}
+	return result
 }
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
 	fakesecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
@@ -14,10 +16,13 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kubernetes "k8s.io/client-go/kubernetes/fake"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 const (
@@ -56,7 +61,7 @@ func Test_CopySecrets_NoFilter(t *testing.T) {
 	examinee, mockSecretHelper := initSecretHelperWithMock(t, mockCtrl, secret)
 
 	// EXPECT
-	expectedSecret := fake.SecretOpaque("foo", "")
+	expectedSecret := expectedCopy(fake.SecretOpaque("foo", namespace))
 	mockSecretHelper.EXPECT().CreateSecret(ctx, expectedSecret).Return(expectedSecret, nil)
 
 	// EXERCISE
@@ -88,9 +93,9 @@ func Test_CopySecrets_WithFilter(t *testing.T) {
 	}
 
 	// EXPECT
-	expectedSecret2 := fake.SecretOpaque("bar", "")
+	expectedSecret2 := expectedCopy(fake.SecretOpaque("bar", namespace))
 	mockSecretHelper.EXPECT().CreateSecret(ctx, expectedSecret2).Return(expectedSecret2, nil)
-	expectedSecret3 := fake.SecretOpaque("baz", "")
+	expectedSecret3 := expectedCopy(fake.SecretOpaque("baz", namespace))
 	mockSecretHelper.EXPECT().CreateSecret(ctx, expectedSecret3).Return(expectedSecret3, nil)
 
 	// EXERCISE
@@ -119,7 +124,7 @@ func Test_CopySecrets_NotExisting(t *testing.T) {
 	)
 
 	// EXPECT
-	expectedSecret := fake.SecretOpaque("foo", "")
+	expectedSecret := expectedCopy(fake.SecretOpaque("foo", namespace))
 	mockSecretHelper.EXPECT().CreateSecret(ctx, expectedSecret).Return(expectedSecret, nil)
 
 	// EXERCISE
@@ -131,9 +136,20 @@ func Test_CopySecrets_NotExisting(t *testing.T) {
 
 	// VERIFY
 	assert.Assert(t, examinee.IsNotFound(resultErr))
+	assert.Assert(t, errors.Is(resultErr, serrors.ErrSecretMissing))
 	assert.DeepEqual(t, []string{"foo"}, resultList)
 }
 
+// expectedCopy returns the expected copy of the given source secret
+// passed to CreateSecret by CopySecrets without transformers.
+func expectedCopy(source *v1.Secret) *v1.Secret {
+	result := source.DeepCopy()
+	result.SetNamespace("")
+	metav1.SetMetaDataAnnotation(&result.ObjectMeta, api.AnnotationSecretSource, source.GetName())
+	metav1.SetMetaDataAnnotation(&result.ObjectMeta, api.AnnotationSecretSourceHash, sourceHash(source))
+	return result
+}
+
 func initSecretHelperWithClient(secrets ...*v1.Secret) (SecretHelper, corev1.SecretInterface) {
 	provider := fakesecretprovider.NewProvider(namespace, secrets...)
 	cf := fake.NewClientFactory()
@@ -241,6 +257,56 @@ func Test_CreateSecret_Error(t *testing.T) {
 	assert.Assert(t, resultSecret == nil)
 }
 
+func Test_CreateSecret_QuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	fakeSecretProvider := fakesecretprovider.NewProvider(namespace)
+	cs := kubernetes.NewSimpleClientset()
+	quotaErr := k8serrors.NewForbidden(v1.Resource("secrets"), "foo", fmt.Errorf("exceeded quota: quota1, requested: count/secrets=1"))
+	cs.PrependReactor("create", "secrets", fake.NewErrorReactor(quotaErr))
+
+	origSecret := fake.SecretOpaque("foo", namespace)
+	examinee := NewSecretHelper(fakeSecretProvider, targetNamespace, cs.CoreV1().Secrets(targetNamespace))
+
+	// EXERCISE
+	resultSecret, resultErr := examinee.CreateSecret(ctx, origSecret.DeepCopy())
+
+	// VERIFY
+	assert.Assert(t, errors.Is(resultErr, serrors.ErrQuotaExceeded))
+	assert.Assert(t, k8serrors.IsForbidden(resultErr))
+	assert.Assert(t, resultSecret == nil)
+}
+
+func Test_CreateSecret_RetriesOnTransientError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	fakeSecretProvider := fakesecretprovider.NewProvider(namespace)
+	cs := kubernetes.NewSimpleClientset()
+	attempts := 0
+	cs.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		if attempts == 1 {
+			return true, nil, k8serrors.NewServiceUnavailable("try again")
+		}
+		return false, nil, nil
+	})
+
+	origSecret := fake.SecretOpaque("foo", namespace)
+	examinee := NewSecretHelper(fakeSecretProvider, targetNamespace, cs.CoreV1().Secrets(targetNamespace))
+
+	// EXERCISE
+	resultSecret, resultErr := examinee.CreateSecret(ctx, origSecret.DeepCopy())
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "foo", resultSecret.GetName())
+	assert.Equal(t, 2, attempts)
+}
+
 func Test_IsNotFound_True(t *testing.T) {
 	t.Parallel()
 
@@ -287,3 +353,105 @@ func Test_IsNotFound_False(t *testing.T) {
 		})
 	}
 }
+
+func Test_RefreshSecrets_ChangedSource(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	source := fake.SecretOpaque("foo", namespace)
+	source.Data = map[string][]byte{"key1": []byte("value1")}
+	unchanged := fake.SecretOpaque("bar", namespace)
+	examinee, targetClient := initSecretHelperWithClient(source, unchanged)
+	_, err := examinee.CopySecrets(ctx, []string{"foo", "bar"}, nil)
+	assert.NilError(t, err)
+	_, err = targetClient.Create(ctx, fake.SecretOpaque("other", targetNamespace), metav1.CreateOptions{})
+	assert.NilError(t, err)
+	source.Data["key1"] = []byte("value2")
+
+	// EXERCISE
+	resultList, resultErr := examinee.RefreshSecrets(ctx)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, []string{"foo"}, resultList)
+	storedSecret, err := targetClient.Get(ctx, "foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string][]byte{"key1": []byte("value2")}, storedSecret.Data)
+	assert.Equal(t, "foo", storedSecret.GetAnnotations()[api.AnnotationSecretSource])
+	assert.Equal(t, sourceHash(source), storedSecret.GetAnnotations()[api.AnnotationSecretSourceHash])
+
+	// a second refresh has nothing to do
+	resultList, resultErr = examinee.RefreshSecrets(ctx)
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 0, len(resultList))
+}
+
+func Test_RefreshSecrets_TokenToBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	source := fake.SecretOpaque("repo", namespace)
+	source.Data = map[string][]byte{TokenKey: []byte("token1")}
+	examinee, targetClient := initSecretHelperWithClient(source)
+	_, err := examinee.CopySecrets(ctx, []string{"repo"}, nil, TokenToBasicAuthTransformer())
+	assert.NilError(t, err)
+	source.Data[TokenKey] = []byte("token2")
+
+	// EXERCISE
+	resultList, resultErr := examinee.RefreshSecrets(ctx)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, []string{"repo"}, resultList)
+	storedSecret, err := targetClient.Get(ctx, "repo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, v1.SecretTypeBasicAuth, storedSecret.Type)
+	assert.DeepEqual(t, map[string][]byte{
+		v1.BasicAuthUsernameKey: []byte(DefaultTokenUsername),
+		v1.BasicAuthPasswordKey: []byte("token2"),
+	}, storedSecret.Data)
+}
+
+func Test_RefreshSecrets_TypeChanged(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	source := fake.SecretOpaque("foo", namespace)
+	examinee, targetClient := initSecretHelperWithClient(source)
+	_, err := examinee.CopySecrets(ctx, []string{"foo"}, nil)
+	assert.NilError(t, err)
+	source.Type = v1.SecretTypeDockerConfigJson
+
+	// EXERCISE
+	resultList, resultErr := examinee.RefreshSecrets(ctx)
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `type of source secret "foo" changed`)
+	assert.Equal(t, 0, len(resultList))
+	storedSecret, err := targetClient.Get(ctx, "foo", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, v1.SecretTypeOpaque, storedSecret.Type)
+}
+
+func Test_sourceHash(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	secret := fake.SecretOpaque("foo", namespace)
+	secret.Data = map[string][]byte{"a": []byte("bc")}
+	other := secret.DeepCopy()
+	other.Data = map[string][]byte{"ab": []byte("c")}
+
+	// EXERCISE + VERIFY
+	assert.Equal(t, sourceHash(secret), sourceHash(secret.DeepCopy()))
+	assert.Assert(t, sourceHash(secret) != sourceHash(other))
+	other = secret.DeepCopy()
+	other.Type = v1.SecretTypeBasicAuth
+	assert.Assert(t, sourceHash(secret) != sourceHash(other))
+	other = secret.DeepCopy()
+	other.StringData = map[string]string{"a": "bc"}
+	assert.Assert(t, sourceHash(secret) != sourceHash(other))
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"context"
+
+	namespaceinformers "github.com/SAP/stewardci-core/pkg/k8s/namespaceinformers"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Watcher watches secrets in namespaces on behalf of watchers identified
+// by keys, e.g. the keys of pipeline runs waiting for their run to start.
+// A namespace is watched as long as at least one key is registered for
+// it. For each change of a secret in a watched namespace the change
+// handler is called with the changed secret.
+type Watcher struct {
+	*namespaceinformers.Set
+
+	client   corev1.CoreV1Interface
+	onChange func(secret *v1.Secret)
+}
+
+// NewWatcher returns a new Watcher using the given client and calling
+// the given change handler for each changed secret.
+func NewWatcher(client corev1.CoreV1Interface, onChange func(secret *v1.Secret)) *Watcher {
+	w := &Watcher{
+		client:   client,
+		onChange: onChange,
+	}
+	w.Set = namespaceinformers.NewSet(func(namespace string, stopCh <-chan struct{}) {
+		go w.newInformer(namespace).Run(stopCh)
+	})
+	return w
+}
+
+// newInformer returns an informer for the secrets in the given namespace
+// calling the change handler on updates. Secrets listed when the
+// informer starts are not reported, as they have been current already,
+// but secrets added afterwards are.
+func (w *Watcher) newInformer(namespace string) cache.SharedIndexInformer {
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return w.client.Secrets(namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return w.client.Secrets(namespace).Watch(context.TODO(), options)
+		},
+	}
+	informer := cache.NewSharedIndexInformer(listWatch, &v1.Secret{}, 0, cache.Indexers{})
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if secret, ok := obj.(*v1.Secret); ok && informer.HasSynced() {
+				w.onChange(secret)
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret, ok1 := old.(*v1.Secret)
+			newSecret, ok2 := new.(*v1.Secret)
+			if ok1 && ok2 && oldSecret.GetResourceVersion() != newSecret.GetResourceVersion() {
+				w.onChange(newSecret)
+			}
+		},
+	})
+	return informer
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Watcher_Namespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+	examinee := NewWatcher(cf.CoreV1(), func(*v1.Secret) {})
+	defer examinee.UnwatchAll()
+
+	// EXERCISE
+	examinee.Watch("ns1", "ns1/run1")
+	examinee.Watch("ns1", "ns1/run2")
+	examinee.Watch("ns2", "ns2/run1")
+	examinee.Watch("ns2", "ns2/run1")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, examinee.Namespaces())
+
+	// EXERCISE + VERIFY
+	examinee.Unwatch("ns1/run1")
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, examinee.Namespaces())
+	examinee.Unwatch("ns1/run2")
+	assert.DeepEqual(t, []string{"ns2"}, examinee.Namespaces())
+	examinee.Unwatch("unknown")
+	assert.DeepEqual(t, []string{"ns2"}, examinee.Namespaces())
+
+	// a key registered for another namespace is moved
+	examinee.Watch("ns3", "ns2/run1")
+	assert.DeepEqual(t, []string{"ns3"}, examinee.Namespaces())
+
+	examinee.UnwatchAll()
+	assert.DeepEqual(t, []string{}, examinee.Namespaces())
+}
+
+func Test_Watcher_OnChange(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(
+		fake.SecretOpaque("secret1", "ns1"),
+		fake.SecretOpaque("secret1", "ns2"),
+	)
+	changed := make(chan *v1.Secret, 100)
+	examinee := NewWatcher(cf.CoreV1(), func(secret *v1.Secret) {
+		changed <- secret
+	})
+	defer examinee.UnwatchAll()
+
+	// EXERCISE
+	examinee.Watch("ns1", "ns1/run1")
+
+	// VERIFY
+	timeout := time.After(10 * time.Second)
+	for i := 1; ; i++ {
+		// secrets in namespaces not watched are not reported
+		for _, namespace := range []string{"ns2", "ns1"} {
+			secret := fake.SecretOpaque("secret1", namespace)
+			secret.SetResourceVersion(strconv.Itoa(i))
+			_, err := cf.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			assert.NilError(t, err)
+		}
+		select {
+		case secret := <-changed:
+			assert.Equal(t, "ns1", secret.GetNamespace())
+			assert.Equal(t, "secret1", secret.GetName())
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("change of secret in watched namespace not observed")
+		}
+	}
+}
@@ -5,12 +5,21 @@ import (
 	"time"
 
 	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	errorsk8s "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klog "k8s.io/klog/v2"
 )
 
+// serviceAccountTokenBackoff is the backoff for waiting until the token
+// secret of a service account is available.
+var serviceAccountTokenBackoff = retry.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   1.0,
+}
+
 type serviceAccountHelper struct {
 	factory ClientFactory
 	cache   *v1.ServiceAccount
@@ -38,9 +47,9 @@ func (h *serviceAccountHelper) Reload(ctx context.Context) error {
 
 // GetServiceAccountSecretNameRepeat retrieves the name of the service account
 // token secret.
-// If no token is available, it retries until there is one.
+// If no token is available, it retries until there is one or the context
+// is done.
 func (h *serviceAccountHelper) GetServiceAccountSecretNameRepeat(ctx context.Context) (string, error) {
-	retryInterval := 100 * time.Millisecond
 	retryCount := uint64(0)
 
 	defer func(start time.Time) {
@@ -59,21 +68,28 @@ func (h *serviceAccountHelper) GetServiceAccountSecretNameRepeat(ctx context.Con
 		}
 	}(time.Now())
 
-	for {
-		result, err := h.GetServiceAccountSecretName(ctx)
-		if err != nil {
-			return "", err
-		}
-		if result != "" {
-			return result, nil
+	var result string
+	err := retry.Do(ctx, serviceAccountTokenBackoff, func() error {
+		if retryCount > 0 {
+			if err := h.Reload(ctx); err != nil {
+				return err
+			}
 		}
-		retryCount++
-		time.Sleep(retryInterval)
-		err = h.Reload(ctx)
+		var err error
+		result, err = h.GetServiceAccountSecretName(ctx)
 		if err != nil {
-			return "", err
+			return err
 		}
+		if result == "" {
+			retryCount++
+			return retry.Retriable(errors.New("service account token secret not available yet"))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
+	return result, nil
 }
 
 // GetServiceAccountSecretName retrieves the name of the service account
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// WorkqueueRateLimiterOpts stores the parameters of a workqueue rate
+// limiter. Zero or negative values are replaced by the defaults of
+// client-go's workqueue.DefaultControllerRateLimiter().
+type WorkqueueRateLimiterOpts struct {
+	// BaseDelay is the delay of the first retry of a failed item.
+	// The delay doubles with each further retry of the same item.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay of retries of a failed item.
+	MaxDelay time.Duration
+
+	// QPS is the overall rate of items added to the workqueue with
+	// rate limiting.
+	QPS float64
+
+	// BucketSize is the burst size of the overall rate limit.
+	BucketSize int
+}
+
+const (
+	// DefaultWorkqueueBaseDelay is the default for
+	// WorkqueueRateLimiterOpts.BaseDelay.
+	DefaultWorkqueueBaseDelay = 5 * time.Millisecond
+
+	// DefaultWorkqueueMaxDelay is the default for
+	// WorkqueueRateLimiterOpts.MaxDelay.
+	DefaultWorkqueueMaxDelay = 1000 * time.Second
+
+	// DefaultWorkqueueQPS is the default for WorkqueueRateLimiterOpts.QPS.
+	DefaultWorkqueueQPS = 10
+
+	// DefaultWorkqueueBucketSize is the default for
+	// WorkqueueRateLimiterOpts.BucketSize.
+	DefaultWorkqueueBucketSize = 100
+)
+
+// NewWorkqueueRateLimiter returns a workqueue rate limiter combining a
+// per-item exponential backoff with an overall token bucket as
+// configured by the given options. The delay of an item is the maximum
+// of both.
+func NewWorkqueueRateLimiter(opts WorkqueueRateLimiterOpts) workqueue.RateLimiter {
+	if opts.BaseDelay <= 0 {
+		opts.BaseDelay = DefaultWorkqueueBaseDelay
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultWorkqueueMaxDelay
+	}
+	if opts.MaxDelay < opts.BaseDelay {
+		opts.MaxDelay = opts.BaseDelay
+	}
+	if opts.QPS <= 0 {
+		opts.QPS = DefaultWorkqueueQPS
+	}
+	if opts.BucketSize <= 0 {
+		opts.BucketSize = DefaultWorkqueueBucketSize
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(opts.BaseDelay, opts.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(opts.QPS), opts.BucketSize)},
+	)
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_NewWorkqueueRateLimiter_ExponentialBackoffPerItem(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewWorkqueueRateLimiter(WorkqueueRateLimiterOpts{
+		BaseDelay: 1 * time.Second,
+		MaxDelay:  3 * time.Second,
+	})
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, examinee.When("item1"), 1*time.Second)
+	assert.Equal(t, examinee.When("item1"), 2*time.Second)
+	assert.Equal(t, examinee.When("item1"), 3*time.Second)
+	assert.Equal(t, examinee.When("item2"), 1*time.Second)
+	assert.Equal(t, examinee.NumRequeues("item1"), 3)
+
+	examinee.Forget("item1")
+	assert.Equal(t, examinee.NumRequeues("item1"), 0)
+	assert.Equal(t, examinee.When("item1"), 1*time.Second)
+}
+
+func Test_NewWorkqueueRateLimiter_Defaults(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewWorkqueueRateLimiter(WorkqueueRateLimiterOpts{})
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, examinee.When("item1"), DefaultWorkqueueBaseDelay)
+	assert.Equal(t, examinee.When("item1"), 2*DefaultWorkqueueBaseDelay)
+}
+
+func Test_NewWorkqueueRateLimiter_BucketLimitsOverallRate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewWorkqueueRateLimiter(WorkqueueRateLimiterOpts{
+		BaseDelay:  1 * time.Millisecond,
+		QPS:        1,
+		BucketSize: 2,
+	})
+
+	// EXERCISE
+	examinee.When("item1")
+	examinee.When("item2")
+	delay := examinee.When("item3")
+
+	// VERIFY
+	assert.Assert(t, delay > 500*time.Millisecond, "delay: %v", delay)
+}
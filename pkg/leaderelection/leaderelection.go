@@ -0,0 +1,135 @@
+/*
+Package leaderelection implements the election of a leader among multiple
+replicas of a controller based on a Kubernetes Lease object in the
+system namespace. Only the leader processes work items, the other replicas
+are on standby and take over if the leader fails to renew the lease.
+*/
+package leaderelection
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	k8sleaderelection "k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+// DefaultLeaseDuration is the default duration non-leader candidates wait
+// after the last renewal of the lease before they try to acquire it.
+const DefaultLeaseDuration = 15 * time.Second
+
+// Config is the configuration of the leader election.
+type Config struct {
+	// LeaseName is the name of the Lease object in the system namespace.
+	LeaseName string
+
+	// Identity is the unique identity of this candidate.
+	// If empty, the host name is used.
+	Identity string
+
+	// LeaseDuration is the duration non-leader candidates wait after the
+	// last renewal of the lease before they try to acquire it.
+	// If zero or negative, DefaultLeaseDuration is used.
+	LeaseDuration time.Duration
+}
+
+// Run blocks until this candidate has been elected as leader and then
+// calls `run`. The stop channel passed to `run` is closed if either
+// `stopCh` is closed or the leadership is lost. Run returns after `run`
+// has returned.
+// The lease is released when `stopCh` gets closed, so that another
+// candidate can take over immediately.
+// Returns an error if the leadership has been lost, in which case the
+// process should terminate as work items might be processed by the new
+// leader already.
+func Run(client coordinationv1client.LeasesGetter, config Config, stopCh <-chan struct{}, run func(stopCh <-chan struct{})) error {
+	identity := config.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "failed to determine leader election identity")
+		}
+		identity = hostname
+	}
+	leaseDuration := config.LeaseDuration
+	if leaseDuration <= 0 {
+		leaseDuration = DefaultLeaseDuration
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace(),
+			Name:      config.LeaseName,
+		},
+		Client: client,
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// the leader context is handed over instead of calling `run` in the
+	// callback, as the elector does not wait for callbacks to return
+	started := make(chan context.Context, 1)
+	elector, err := k8sleaderelection.NewLeaderElector(k8sleaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   leaseDuration * 2 / 3,
+		RetryPeriod:     leaseDuration * 2 / 15,
+		ReleaseOnCancel: true,
+		Name:            config.LeaseName,
+		Callbacks: k8sleaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				klog.V(2).Infof("Started leading as %q", identity)
+				started <- ctx
+			},
+			OnStoppedLeading: func() {
+				klog.V(2).Infof("Stopped leading as %q", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					klog.V(2).Infof("Current leader is %q", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create leader elector")
+	}
+
+	klog.V(2).Infof("Waiting for leadership of lease %q as %q", config.LeaseName, identity)
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(ctx)
+	}()
+
+	select {
+	case leaderCtx := <-started:
+		run(leaderCtx.Done())
+		<-electorDone
+	case <-electorDone:
+	}
+
+	select {
+	case <-stopCh:
+		return nil
+	default:
+		return errors.Errorf("leadership of lease %q lost", config.LeaseName)
+	}
+}
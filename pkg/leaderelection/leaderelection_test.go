@@ -0,0 +1,71 @@
+package leaderelection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_Run_AcquiresLeaseAndCallsRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	clientset := kubefake.NewSimpleClientset()
+	config := Config{LeaseName: "lease1", Identity: "candidate1"}
+	stopCh := make(chan struct{})
+	runCalled := false
+
+	// EXERCISE
+	err := Run(clientset.CoordinationV1(), config, stopCh, func(runStopCh <-chan struct{}) {
+		runCalled = true
+		close(stopCh)
+		<-runStopCh
+	})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, runCalled)
+	lease, err := clientset.CoordinationV1().Leases(system.Namespace()).Get(context.Background(), "lease1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, lease.Spec.HolderIdentity != nil)
+}
+
+func Test_Run_LeaseHeldByOtherCandidate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	holder := "candidate2"
+	leaseDurationSeconds := int32(60)
+	clientset := kubefake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lease1",
+			Namespace: system.Namespace(),
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			AcquireTime:          &metav1.MicroTime{Time: time.Now()},
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	})
+	config := Config{LeaseName: "lease1", Identity: "candidate1", LeaseDuration: 60 * time.Second}
+	stopCh := make(chan struct{})
+	time.AfterFunc(100*time.Millisecond, func() { close(stopCh) })
+	runCalled := false
+
+	// EXERCISE
+	err := Run(clientset.CoordinationV1(), config, stopCh, func(runStopCh <-chan struct{}) {
+		runCalled = true
+	})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !runCalled)
+}
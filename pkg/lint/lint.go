@@ -0,0 +1,188 @@
+/*
+Package lint statically analyzes the spec of pipeline runs, pipeline run
+templates and pipeline schedules and reports findings which do not make
+the object invalid, but likely cause surprises, e.g. a missing timeout or
+a secret used by the pipeline but not made available to it.
+
+Each finding names the rule, the offending field and a hint how to
+resolve it.
+*/
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+// Rule identifies a lint rule.
+type Rule string
+
+const (
+	// RuleMissingTimeout reports specs without timeout, which are
+	// executed with the (typically long) timeout of the Steward
+	// installation.
+	RuleMissingTimeout Rule = "missing-timeout"
+
+	// RuleBroadNetworkProfile reports specs selecting a network profile
+	// configured as overly broad (see Options.BroadNetworkProfiles).
+	RuleBroadNetworkProfile Rule = "broad-network-profile"
+
+	// RuleDeprecatedField reports the usage of deprecated fields.
+	RuleDeprecatedField Rule = "deprecated-field"
+
+	// RuleUnmountedSecret reports Jenkins credential IDs passed as
+	// pipeline arguments which do not refer to a secret made available
+	// to the pipeline run.
+	RuleUnmountedSecret Rule = "unmounted-secret"
+)
+
+// credentialsIDArgSuffix is the (case-insensitive) suffix of the names of
+// pipeline arguments whose values are considered Jenkins credential IDs,
+// following the naming convention of Jenkins steps, e.g.
+// `deployCredentialsId`.
+const credentialsIDArgSuffix = "credentialsid"
+
+// Options configures the lint rules.
+type Options struct {
+	// BroadNetworkProfiles are the names of the network profiles of the
+	// Steward installation considered overly broad, e.g. a profile
+	// allowing access to the internet. If empty, rule
+	// `broad-network-profile` never reports findings.
+	BroadNetworkProfiles []string
+}
+
+// Finding is an issue detected by a lint rule.
+type Finding struct {
+	// Rule is the rule reporting the finding.
+	Rule Rule
+
+	// Field is the path of the offending field, e.g. `spec.timeout`.
+	Field string
+
+	// Message describes the issue.
+	Message string
+
+	// Hint describes how to resolve the issue.
+	Hint string
+}
+
+// String returns the finding in a human-readable single-line format.
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s %s [%s]", f.Field, f.Message, f.Hint, f.Rule)
+}
+
+// PipelineRun returns the findings for the given pipeline run.
+func PipelineRun(run *api.PipelineRun, opts Options) []Finding {
+	return Spec(&run.Spec, "spec", opts)
+}
+
+// PipelineRunTemplate returns the findings for the given pipeline run
+// template.
+func PipelineRunTemplate(template *api.PipelineRunTemplate, opts Options) []Finding {
+	return Spec(&template.Spec, "spec", opts)
+}
+
+// PipelineSchedule returns the findings for the template of the given
+// pipeline schedule.
+func PipelineSchedule(schedule *api.PipelineSchedule, opts Options) []Finding {
+	return Spec(&schedule.Spec.Template.Spec, "spec.template.spec", opts)
+}
+
+// Spec returns the findings for the given pipeline spec located at the
+// given field path, sorted by field path and rule.
+// Specs referring to a pipeline run template are checked without the
+// template, so rules whose findings may be resolved by a template are
+// skipped for them.
+func Spec(spec *api.PipelineSpec, path string, opts Options) []Finding {
+	var findings []Finding
+	for _, check := range []func(*api.PipelineSpec, string, Options) []Finding{
+		checkTimeout,
+		checkNetworkProfile,
+		checkDeprecatedFields,
+		checkUnmountedSecrets,
+	} {
+		findings = append(findings, check(spec, path, opts)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Field != findings[j].Field {
+			return findings[i].Field < findings[j].Field
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+func checkTimeout(spec *api.PipelineSpec, path string, _ Options) []Finding {
+	if spec.Timeout != nil || spec.TemplateRef != nil {
+		return nil
+	}
+	return []Finding{{
+		Rule:    RuleMissingTimeout,
+		Field:   path + ".timeout",
+		Message: "No timeout is set, so a hanging pipeline blocks resources until the timeout of the Steward installation is reached.",
+		Hint:    "Set the field to the maximum expected execution time, e.g. `30m`.",
+	}}
+}
+
+func checkNetworkProfile(spec *api.PipelineSpec, path string, opts Options) []Finding {
+	if spec.Profiles == nil || spec.Profiles.Network == "" {
+		return nil
+	}
+	profile := spec.Profiles.Network
+	for _, broad := range opts.BroadNetworkProfiles {
+		if profile == broad {
+			return []Finding{{
+				Rule:    RuleBroadNetworkProfile,
+				Field:   path + ".profiles.network",
+				Message: fmt.Sprintf("Network profile %q grants broad network access.", profile),
+				Hint:    "Select a more restrictive network profile if the pipeline does not need this access.",
+			}}
+		}
+	}
+	return nil
+}
+
+func checkDeprecatedFields(spec *api.PipelineSpec, path string, _ Options) []Finding {
+	if spec.Logging == nil || spec.Logging.Elasticsearch == nil {
+		return nil
+	}
+	return []Finding{{
+		Rule:    RuleDeprecatedField,
+		Field:   path + ".logging.elasticsearch",
+		Message: "The field is deprecated and will be removed in the next API version.",
+		Hint:    "Use field `logging.sink` with type `elasticsearch` instead.",
+	}}
+}
+
+func checkUnmountedSecrets(spec *api.PipelineSpec, path string, _ Options) []Finding {
+	if spec.TemplateRef != nil {
+		return nil
+	}
+	available := map[string]bool{}
+	for _, name := range spec.Secrets {
+		available[name] = true
+	}
+	for _, sealedSecret := range spec.SealedSecrets {
+		available[sealedSecret.Name] = true
+	}
+	var findings []Finding
+	for name, value := range spec.Args {
+		if !strings.HasSuffix(strings.ToLower(name), credentialsIDArgSuffix) {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || available[value] {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:    RuleUnmountedSecret,
+			Field:   fmt.Sprintf("%s.args[%s]", path, name),
+			Message: fmt.Sprintf("Credential %q is not listed in `secrets` or `sealedSecrets`, so it is not available as Jenkins credential.", value),
+			Hint:    "Add the secret to `secrets` or `sealedSecrets`, unless a listed secret is renamed to this name.",
+		})
+	}
+	return findings
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Spec(t *testing.T) {
+	t.Parallel()
+
+	timeout := &metav1.Duration{Duration: 10 * time.Minute}
+	opts := Options{BroadNetworkProfiles: []string{"internet"}}
+
+	for _, tc := range []struct {
+		name           string
+		spec           api.PipelineSpec
+		expectedFields []string
+	}{
+		{"clean", api.PipelineSpec{Timeout: timeout}, nil},
+		{"missing_timeout", api.PipelineSpec{}, []string{"spec.timeout"}},
+		{"missing_timeout_with_template", api.PipelineSpec{
+			TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+		}, nil},
+		{"broad_network_profile", api.PipelineSpec{
+			Timeout:  timeout,
+			Profiles: &api.Profiles{Network: "internet"},
+		}, []string{"spec.profiles.network"}},
+		{"other_network_profile", api.PipelineSpec{
+			Timeout:  timeout,
+			Profiles: &api.Profiles{Network: "blocked"},
+		}, nil},
+		{"deprecated_elasticsearch", api.PipelineSpec{
+			Timeout: timeout,
+			Logging: &api.Logging{Elasticsearch: &api.Elasticsearch{}},
+		}, []string{"spec.logging.elasticsearch"}},
+		{"log_sink", api.PipelineSpec{
+			Timeout: timeout,
+			Logging: &api.Logging{Sink: &api.LogSink{Type: api.LogSinkTypeElasticsearch}},
+		}, nil},
+		{"unmounted_secret", api.PipelineSpec{
+			Timeout: timeout,
+			Args: map[string]string{
+				"deployCredentialsId": "deploy",
+				"githubCredentialsID": "github",
+				"sealedCredentialsId": "sealed",
+				"emptyCredentialsId":  "",
+				"other":               "foo",
+			},
+			Secrets:       []string{"github"},
+			SealedSecrets: []api.SealedSecret{{Name: "sealed"}},
+		}, []string{"spec.args[deployCredentialsId]"}},
+		{"unmounted_secret_with_template", api.PipelineSpec{
+			Args:        map[string]string{"deployCredentialsId": "deploy"},
+			TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+		}, nil},
+		{"sorted_by_field", api.PipelineSpec{
+			Logging:  &api.Logging{Elasticsearch: &api.Elasticsearch{}},
+			Profiles: &api.Profiles{Network: "internet"},
+		}, []string{"spec.logging.elasticsearch", "spec.profiles.network", "spec.timeout"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := Spec(&tc.spec, "spec", opts)
+
+			// VERIFY
+			var fields []string
+			for _, finding := range result {
+				assert.Assert(t, finding.Message != "")
+				assert.Assert(t, finding.Hint != "")
+				fields = append(fields, finding.Field)
+			}
+			assert.DeepEqual(t, tc.expectedFields, fields)
+		})
+	}
+}
+
+func Test_Spec_NoBroadNetworkProfiles(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	spec := &api.PipelineSpec{
+		Timeout:  &metav1.Duration{Duration: time.Minute},
+		Profiles: &api.Profiles{Network: "internet"},
+	}
+
+	// EXERCISE
+	result := Spec(spec, "spec", Options{})
+
+	// VERIFY
+	assert.Equal(t, 0, len(result))
+}
+
+func Test_PipelineSchedule_UsesTemplatePath(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	schedule := &api.PipelineSchedule{}
+
+	// EXERCISE
+	result := PipelineSchedule(schedule, Options{})
+
+	// VERIFY
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, RuleMissingTimeout, result[0].Rule)
+	assert.Equal(t, "spec.template.spec.timeout", result[0].Field)
+}
+
+func Test_Finding_String(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	finding := Finding{
+		Rule:    RuleMissingTimeout,
+		Field:   "spec.timeout",
+		Message: "Message.",
+		Hint:    "Hint.",
+	}
+
+	// EXERCISE
+	result := finding.String()
+
+	// VERIFY
+	assert.Equal(t, "spec.timeout: Message. Hint. [missing-timeout]", result)
+}
@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	yaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Result are the findings for a single object.
+type Result struct {
+	// Kind is the kind of the object, e.g. `PipelineRun`.
+	Kind string
+
+	// Name is the name of the object, or its generate name if it has no
+	// name.
+	Name string
+
+	// Findings are the findings for the object.
+	Findings []Finding
+}
+
+// Manifests lints all pipeline runs, pipeline run templates and pipeline
+// schedules read from the given manifests in YAML or JSON format.
+// The manifests may contain multiple YAML documents and lists of objects
+// like the output of `kubectl get -o yaml`. Documents without kind are
+// considered pipeline runs. Objects of other kinds are skipped.
+func Manifests(r io.Reader, opts Options) ([]Result, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	var results []Result
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return results, nil
+			}
+			return nil, errors.Wrap(err, "failed to parse manifests")
+		}
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue
+		}
+		objectResults, err := lintObjects(raw.Raw, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, objectResults...)
+	}
+}
+
+// lintObjects lints a single object or the items of a list.
+func lintObjects(data []byte, opts Options) ([]Result, error) {
+	list := struct {
+		metav1.TypeMeta `json:",inline"`
+		Items           []runtime.RawExtension `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, errors.Wrap(err, "failed to parse object")
+	}
+	if strings.HasSuffix(list.Kind, "List") && list.Items != nil {
+		var results []Result
+		for _, item := range list.Items {
+			itemResults, err := lintObjects(item.Raw, opts)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, itemResults...)
+		}
+		return results, nil
+	}
+	if list.APIVersion != "" && list.APIVersion != api.SchemeGroupVersion.String() {
+		return nil, nil
+	}
+
+	var object metav1.Object
+	var findings func() []Finding
+	switch list.Kind {
+	case "", "PipelineRun":
+		run := &api.PipelineRun{}
+		object, findings = run, func() []Finding { return PipelineRun(run, opts) }
+	case "PipelineRunTemplate":
+		template := &api.PipelineRunTemplate{}
+		object, findings = template, func() []Finding { return PipelineRunTemplate(template, opts) }
+	case "PipelineSchedule":
+		schedule := &api.PipelineSchedule{}
+		object, findings = schedule, func() []Finding { return PipelineSchedule(schedule, opts) }
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, object); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", kindOrDefault(list.Kind))
+	}
+	name := object.GetName()
+	if name == "" {
+		name = object.GetGenerateName()
+	}
+	return []Result{{
+		Kind:     kindOrDefault(list.Kind),
+		Name:     name,
+		Findings: findings(),
+	}}, nil
+}
+
+// kindOrDefault returns the given kind or `PipelineRun` if it is empty.
+func kindOrDefault(kind string) string {
+	if kind == "" {
+		return "PipelineRun"
+	}
+	return kind
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Manifests(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	manifests := `
+apiVersion: steward.sap.com/v1alpha1
+kind: PipelineRun
+metadata:
+  generateName: run-
+spec:
+  jenkinsFile:
+    repoUrl: https://github.com/org1/repo1
+    revision: main
+    relativePath: Jenkinsfile
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret1
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: steward.sap.com/v1alpha1
+  kind: PipelineRunTemplate
+  metadata:
+    name: template1
+  spec:
+    timeout: 10m
+    logging:
+      elasticsearch:
+        runID: {"build": 1}
+- apiVersion: steward.sap.com/v1alpha1
+  kind: PipelineSchedule
+  metadata:
+    name: schedule1
+  spec:
+    schedule: "0 0 * * *"
+    template:
+      spec:
+        timeout: 10m
+---
+`
+
+	// EXERCISE
+	results, err := Manifests(strings.NewReader(manifests), Options{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, "PipelineRun", results[0].Kind)
+	assert.Equal(t, "run-", results[0].Name)
+	assert.Equal(t, 1, len(results[0].Findings))
+	assert.Equal(t, RuleMissingTimeout, results[0].Findings[0].Rule)
+	assert.Equal(t, "PipelineRunTemplate", results[1].Kind)
+	assert.Equal(t, "template1", results[1].Name)
+	assert.Equal(t, 1, len(results[1].Findings))
+	assert.Equal(t, RuleDeprecatedField, results[1].Findings[0].Rule)
+	assert.Equal(t, "PipelineSchedule", results[2].Kind)
+	assert.Equal(t, "schedule1", results[2].Name)
+	assert.Equal(t, 0, len(results[2].Findings))
+}
+
+func Test_Manifests_WithoutKind(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	manifests := `{"spec": {"timeout": "5m"}}`
+
+	// EXERCISE
+	results, err := Manifests(strings.NewReader(manifests), Options{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, "PipelineRun", results[0].Kind)
+	assert.Equal(t, 0, len(results[0].Findings))
+}
+
+func Test_Manifests_Invalid(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	manifests := `
+kind: PipelineRun
+spec:
+  timeout: 42
+`
+
+	// EXERCISE
+	_, err := Manifests(strings.NewReader(manifests), Options{})
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to parse PipelineRun")
+}
@@ -4,7 +4,6 @@ Package metrics provides metrics support shared among all packages in this Go
 module:
 
 -   the metrics registry
--   exporting metrics via HTTP
 -   common metrics
 
 It does NOT include code that is specific to other packages of this module.
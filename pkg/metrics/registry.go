@@ -13,3 +13,9 @@ var (
 func Registerer() prometheus.Registerer {
 	return registry
 }
+
+// Gatherer returns the gatherer for the metrics exported by the metrics
+// server
+func Gatherer() prometheus.Gatherer {
+	return registry
+}
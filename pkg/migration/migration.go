@@ -0,0 +1,105 @@
+// Package migration migrates existing Steward objects on upgrades, e.g.
+// to set new defaults or labels or to rewrite them in the current
+// storage version of their custom resource definition, so that upgrades
+// do not depend on manual scripts.
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamic "k8s.io/client-go/dynamic"
+)
+
+// MigrateFunc modifies the given object in place and returns whether it
+// has been changed.
+type MigrateFunc func(obj *unstructured.Unstructured) (bool, error)
+
+// Migration is a migration of all objects of a resource.
+// Migrations must be idempotent, as a migration interrupted before it
+// is recorded as completed is applied again.
+type Migration struct {
+	// ID identifies the migration. It is recorded in the state config
+	// map once the migration has completed, so that it is not applied
+	// again. It must consist of alphanumeric characters, '-', '_' or
+	// '.' only.
+	ID string
+
+	// Resource is the resource whose objects are migrated.
+	Resource schema.GroupVersionResource
+
+	// Migrate migrates a single object. Objects changed by it are
+	// updated. If nil, all objects are updated unchanged, which makes
+	// the API server rewrite them in the current storage version.
+	Migrate MigrateFunc
+
+	// Complete is called after all objects have been migrated.
+	// Optional.
+	Complete func(ctx context.Context, client dynamic.Interface) error
+}
+
+var crdResource = schema.GroupVersionResource{
+	Group:    "apiextensions.k8s.io",
+	Version:  "v1",
+	Resource: "customresourcedefinitions",
+}
+
+// StorageVersionMigration returns a migration rewriting all objects of
+// the given resource, which must refer to the storage version of its
+// custom resource definition. Afterwards all other versions are removed
+// from `status.storedVersions` of the custom resource definition, so
+// that they can be dropped from the custom resource definition.
+func StorageVersionMigration(resource schema.GroupVersionResource) Migration {
+	return Migration{
+		ID:       fmt.Sprintf("storage-version.%s.%s.%s", resource.Resource, resource.Group, resource.Version),
+		Resource: resource,
+		Complete: func(ctx context.Context, client dynamic.Interface) error {
+			patch := fmt.Sprintf(`{"status":{"storedVersions":[%q]}}`, resource.Version)
+			_, err := client.Resource(crdResource).Patch(
+				ctx, resource.GroupResource().String(), types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status",
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update stored versions of custom resource definition %q: %s", resource.GroupResource().String(), err.Error())
+			}
+			return nil
+		},
+	}
+}
+
+// SetLabelIfMissing returns a MigrateFunc setting the label with the
+// given key to the given value on objects not having this label yet.
+func SetLabelIfMissing(key, value string) MigrateFunc {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		labels := obj.GetLabels()
+		if _, ok := labels[key]; ok {
+			return false, nil
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = value
+		obj.SetLabels(labels)
+		return true, nil
+	}
+}
+
+// SetDefault returns a MigrateFunc setting the field with the given path
+// to the given value on objects not having this field yet. The value
+// must be a valid JSON value as used by unstructured objects, e.g.
+// `int64` instead of `int`.
+func SetDefault(value interface{}, fields ...string) MigrateFunc {
+	return func(obj *unstructured.Unstructured) (bool, error) {
+		_, found, err := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+		if err != nil || found {
+			return false, err
+		}
+		if err := unstructured.SetNestedField(obj.Object, value, fields...); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
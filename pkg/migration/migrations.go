@@ -0,0 +1,24 @@
+package migration
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+// Migrations returns the migrations of Steward objects in the order they
+// are applied.
+// New migrations are appended to the list. Released migrations must not
+// be changed, as they are not applied again to installations where they
+// have completed already.
+func Migrations() []Migration {
+	var result []Migration
+	for _, resource := range []string{
+		"pipelineruns",
+		"pipelineruntemplates",
+		"pipelineschedules",
+		"tenants",
+		"triggers",
+	} {
+		result = append(result, StorageVersionMigration(api.SchemeGroupVersion.WithResource(resource)))
+	}
+	return result
+}
@@ -0,0 +1,168 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamic "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	klog "k8s.io/klog/v2"
+)
+
+// DefaultStateConfigMap is the default name of the config map in the
+// system namespace recording the completed migrations.
+const DefaultStateConfigMap = "steward-migrations"
+
+// Migrator applies migrations not applied yet and records them as
+// completed in a config map.
+type Migrator struct {
+	factory       k8s.ClientFactory
+	namespace     string
+	configMapName string
+	migrations    []Migration
+}
+
+// NewMigrator returns a new Migrator applying the given migrations in
+// the given order and recording completed ones in the config map with
+// the given name in the given namespace.
+func NewMigrator(factory k8s.ClientFactory, namespace, configMapName string, migrations []Migration) *Migrator {
+	return &Migrator{
+		factory:       factory,
+		namespace:     namespace,
+		configMapName: configMapName,
+		migrations:    migrations,
+	}
+}
+
+// Run applies all migrations not completed yet and returns the IDs of
+// the migrations applied. It stops at the first failing migration, which
+// is applied again on the next run.
+func (m *Migrator) Run(ctx context.Context) ([]string, error) {
+	state, exists, err := m.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := []string{}
+	for _, migration := range m.migrations {
+		if _, done := state.Data[migration.ID]; done {
+			continue
+		}
+		klog.V(2).Infof("Applying migration %q", migration.ID)
+		count, err := m.migrate(ctx, migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %q failed: %s", migration.ID, err.Error())
+		}
+		if migration.Complete != nil {
+			if err := migration.Complete(ctx, m.factory.Dynamic()); err != nil {
+				return applied, fmt.Errorf("migration %q failed: %s", migration.ID, err.Error())
+			}
+		}
+		state.Data[migration.ID] = time.Now().UTC().Format(time.RFC3339)
+		if state, err = m.saveState(ctx, state, exists); err != nil {
+			return applied, err
+		}
+		exists = true
+		klog.V(2).Infof("Completed migration %q (updated objects: %d)", migration.ID, count)
+		applied = append(applied, migration.ID)
+	}
+	return applied, nil
+}
+
+// migrate applies the given migration to all objects of its resource and
+// returns the number of objects updated.
+func (m *Migrator) migrate(ctx context.Context, migration Migration) (int, error) {
+	client := m.factory.Dynamic().Resource(migration.Resource)
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.List(ctx, opts)
+	}
+	count := 0
+	err := k8s.EachListItem(ctx, list, metav1.ListOptions{}, func(obj runtime.Object) error {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected list item of type %T", obj)
+		}
+		updated, err := migrateObject(ctx, client.Namespace(item.GetNamespace()), migration.Migrate, item)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s %q: %s", migration.Resource.Resource, item.GetNamespace()+"/"+item.GetName(), err.Error())
+		}
+		if updated {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// migrateObject applies the given migrate function to the given object
+// and updates it if changed. On conflicts the current object is fetched
+// and migrated again. Objects deleted in the meantime are ignored.
+func migrateObject(ctx context.Context, client dynamic.ResourceInterface, migrate MigrateFunc, obj *unstructured.Unstructured) (bool, error) {
+	updated := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if migrate != nil {
+			changed, err := migrate(obj)
+			if err != nil || !changed {
+				return err
+			}
+		}
+		_, err := client.Update(ctx, obj, metav1.UpdateOptions{})
+		if k8serrors.IsConflict(err) {
+			current, getErr := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			obj = current
+		}
+		updated = err == nil
+		return err
+	})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	return updated, err
+}
+
+// loadState returns the state config map and whether it exists already.
+func (m *Migrator) loadState(ctx context.Context) (*corev1.ConfigMap, bool, error) {
+	exists := true
+	state, err := m.factory.CoreV1().ConfigMaps(m.namespace).Get(ctx, m.configMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		exists = false
+		state = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.configMapName,
+				Namespace: m.namespace,
+			},
+		}
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to get migration state config map %q: %s", m.configMapName, err.Error())
+	}
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+	return state, exists, nil
+}
+
+// saveState creates or updates the state config map. Concurrent
+// migrators fail with a conflict.
+func (m *Migrator) saveState(ctx context.Context, state *corev1.ConfigMap, exists bool) (*corev1.ConfigMap, error) {
+	client := m.factory.CoreV1().ConfigMaps(m.namespace)
+	var result *corev1.ConfigMap
+	var err error
+	if !exists {
+		result, err = client.Create(ctx, state, metav1.CreateOptions{})
+	} else {
+		result, err = client.Update(ctx, state, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save migration state config map %q: %s", m.configMapName, err.Error())
+	}
+	return result, nil
+}
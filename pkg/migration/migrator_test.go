@@ -0,0 +1,174 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var pipelineRunsResource = api.SchemeGroupVersion.WithResource("pipelineruns")
+
+func newFactory(t *testing.T, objects ...runtime.Object) *fake.ClientFactory {
+	t.Helper()
+	cf := fake.NewClientFactory()
+	cf.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			pipelineRunsResource: "PipelineRunList",
+			crdResource:          "CustomResourceDefinitionList",
+		},
+		objects...,
+	)
+	return cf
+}
+
+func unstructuredPipelineRun(t *testing.T, name, namespace string) *unstructured.Unstructured {
+	t.Helper()
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(fake.PipelineRun(name, namespace, api.PipelineSpec{}))
+	assert.NilError(t, err)
+	return &unstructured.Unstructured{Object: content}
+}
+
+func Test_Migrator_Run(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run1 := unstructuredPipelineRun(t, "run1", "ns1")
+	run2 := unstructuredPipelineRun(t, "run2", "ns2")
+	run2.SetLabels(map[string]string{"label1": "existing"})
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("pipelineruns.steward.sap.com")
+	assert.NilError(t, unstructured.SetNestedStringSlice(crd.Object, []string{"v1alpha0", "v1alpha1"}, "status", "storedVersions"))
+	cf := newFactory(t, run1, run2, crd)
+
+	calls := 0
+	setLabel := SetLabelIfMissing("label1", "new")
+	migrations := []Migration{
+		{
+			ID:       "label1",
+			Resource: pipelineRunsResource,
+			Migrate: func(obj *unstructured.Unstructured) (bool, error) {
+				calls++
+				return setLabel(obj)
+			},
+		},
+		StorageVersionMigration(pipelineRunsResource),
+	}
+	examinee := NewMigrator(cf, "system1", DefaultStateConfigMap, migrations)
+
+	// EXERCISE
+	applied, err := examinee.Run(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"label1", "storage-version.pipelineruns.steward.sap.com.v1alpha1"}, applied)
+	assert.Equal(t, 2, calls)
+	client := cf.Dynamic().Resource(pipelineRunsResource)
+	obj, err := client.Namespace("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "new", obj.GetLabels()["label1"])
+	obj, err = client.Namespace("ns2").Get(ctx, "run2", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "existing", obj.GetLabels()["label1"])
+	obj, err = cf.Dynamic().Resource(crdResource).Get(ctx, "pipelineruns.steward.sap.com", metav1.GetOptions{})
+	assert.NilError(t, err)
+	storedVersions, _, err := unstructured.NestedStringSlice(obj.Object, "status", "storedVersions")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"v1alpha1"}, storedVersions)
+	state, err := cf.CoreV1().ConfigMaps("system1").Get(ctx, DefaultStateConfigMap, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Contains(state.Data, "label1"))
+	assert.Assert(t, is.Contains(state.Data, "storage-version.pipelineruns.steward.sap.com.v1alpha1"))
+
+	// EXERCISE + VERIFY: completed migrations are not applied again
+	applied, err = examinee.Run(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{}, applied)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Migrator_Run_Failure(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := newFactory(t, unstructuredPipelineRun(t, "run1", "ns1"))
+	fail := true
+	migrations := []Migration{
+		{
+			ID:       "m1",
+			Resource: pipelineRunsResource,
+			Migrate: func(obj *unstructured.Unstructured) (bool, error) {
+				if fail {
+					return false, fmt.Errorf("error1")
+				}
+				return false, nil
+			},
+		},
+		{
+			ID:       "m2",
+			Resource: pipelineRunsResource,
+			Migrate:  SetLabelIfMissing("label1", "value1"),
+		},
+	}
+	examinee := NewMigrator(cf, "system1", DefaultStateConfigMap, migrations)
+
+	// EXERCISE
+	applied, err := examinee.Run(ctx)
+
+	// VERIFY
+	assert.Error(t, err, `migration "m1" failed: failed to migrate pipelineruns "ns1/run1": error1`)
+	assert.DeepEqual(t, []string{}, applied)
+	obj, err := cf.Dynamic().Resource(pipelineRunsResource).Namespace("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "", obj.GetLabels()["label1"])
+
+	// EXERCISE + VERIFY: failed migration is applied again
+	fail = false
+	applied, err = examinee.Run(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"m1", "m2"}, applied)
+}
+
+func Test_SetDefault(t *testing.T) {
+	t.Parallel()
+
+	examinee := SetDefault(int64(5), "spec", "maxConcurrentRuns")
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	changed, err := examinee(obj)
+	assert.NilError(t, err)
+	assert.Assert(t, changed)
+	value, _, _ := unstructured.NestedInt64(obj.Object, "spec", "maxConcurrentRuns")
+	assert.Equal(t, int64(5), value)
+
+	assert.NilError(t, unstructured.SetNestedField(obj.Object, int64(3), "spec", "maxConcurrentRuns"))
+	changed, err = examinee(obj)
+	assert.NilError(t, err)
+	assert.Assert(t, !changed)
+	value, _, _ = unstructured.NestedInt64(obj.Object, "spec", "maxConcurrentRuns")
+	assert.Equal(t, int64(3), value)
+}
+
+func Test_Migrations_UniqueIDs(t *testing.T) {
+	t.Parallel()
+
+	ids := map[string]bool{}
+	for _, migration := range Migrations() {
+		assert.Assert(t, !ids[migration.ID], migration.ID)
+		ids[migration.ID] = true
+	}
+}
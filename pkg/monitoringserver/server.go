@@ -0,0 +1,211 @@
+/*
+Package monitoringserver implements the HTTP server of the Steward
+controllers exposing metrics, health and debug endpoints. All controllers
+register the same command line flags, so that the exposure of these
+endpoints is configured the same way for all of them.
+
+The server provides the following endpoints:
+
+	/metrics        the Prometheus metrics
+	/healthz        the health of the process, never authenticated
+	/debug/pprof/   the Go runtime profiles, only if enabled
+
+If a bearer token file is configured, requests for metrics and debug
+endpoints must present this token.
+*/
+package monitoringserver
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// DefaultPort is the default TCP port of the monitoring server.
+	DefaultPort = 9090
+
+	// readHeaderTimeout is the maximum duration for reading the headers
+	// of a request.
+	readHeaderTimeout = 10 * time.Second
+
+	// restartDelay is the delay before the server gets restarted after
+	// it terminated unexpectedly.
+	restartDelay = 1 * time.Second
+)
+
+// Options are the options of the monitoring server.
+type Options struct {
+	// Port is the TCP port to listen on.
+	Port uint
+
+	// TLSCertFile is the path to the file containing the PEM-encoded TLS
+	// server certificate. If empty, plain HTTP is served.
+	TLSCertFile string
+
+	// TLSKeyFile is the path to the file containing the PEM-encoded TLS
+	// server private key. Required if TLSCertFile is set.
+	TLSKeyFile string
+
+	// BearerTokenFile is the path to a file containing the bearer token
+	// clients must present to access metrics and debug endpoints.
+	// The file is read for each request, so that the token can be rotated
+	// without restart. If empty, no authentication is required.
+	BearerTokenFile string
+
+	// EnableProfiling enables the Go runtime profiles at /debug/pprof/.
+	EnableProfiling bool
+}
+
+// AddFlags registers the command line flags for the options at the given
+// flag set.
+func (o *Options) AddFlags(fs *flag.FlagSet) {
+	fs.UintVar(
+		&o.Port,
+		"metrics-port",
+		DefaultPort,
+		"The TCP port of the HTTP server providing metrics, health and debug endpoints.",
+	)
+	fs.StringVar(
+		&o.TLSCertFile,
+		"metrics-tls-cert-file",
+		"",
+		"The path to the file containing the PEM-encoded TLS server certificate of the metrics server."+
+			" If not specified or empty, the metrics server serves plain HTTP.",
+	)
+	fs.StringVar(
+		&o.TLSKeyFile,
+		"metrics-tls-key-file",
+		"",
+		"The path to the file containing the PEM-encoded TLS server private key of the metrics server.",
+	)
+	fs.StringVar(
+		&o.BearerTokenFile,
+		"metrics-bearer-token-file",
+		"",
+		"The path to a file containing the bearer token required to access the metrics and debug endpoints."+
+			" If not specified or empty, no authentication is required. The health endpoint is never authenticated.",
+	)
+	fs.BoolVar(
+		&o.EnableProfiling,
+		"metrics-enable-profiling",
+		false,
+		"Whether the metrics server provides Go runtime profiles at /debug/pprof/.",
+	)
+}
+
+// Validate checks the options for consistency.
+func (o *Options) Validate() error {
+	if o.Port == 0 || o.Port > 65535 {
+		return errors.Errorf("invalid metrics server port: %d", o.Port)
+	}
+	if (o.TLSCertFile == "") != (o.TLSKeyFile == "") {
+		return errors.New("the TLS certificate file and the TLS key file of the metrics server must be set together")
+	}
+	return nil
+}
+
+// URL returns the base URL of the server for log messages.
+func (o *Options) URL() string {
+	scheme := "http"
+	if o.TLSCertFile != "" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://0.0.0.0:%d", scheme, o.Port)
+}
+
+// Server is the monitoring server.
+type Server struct {
+	opts   Options
+	server *http.Server
+}
+
+// NewServer returns a new monitoring server with the given options
+// exporting the metrics of the given gatherer.
+func NewServer(opts Options, gatherer prometheus.Gatherer) (*Server, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Server{opts: opts}
+	s.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", opts.Port),
+		Handler:           s.handler(gatherer),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	return s, nil
+}
+
+// Start starts serving in a separate goroutine. The server is restarted
+// if it terminates unexpectedly.
+func (s *Server) Start() {
+	klog.V(2).Infof("Provide metrics on %s/metrics", s.opts.URL())
+	go func() {
+		for {
+			var err error
+			if s.opts.TLSCertFile != "" {
+				err = s.server.ListenAndServeTLS(s.opts.TLSCertFile, s.opts.TLSKeyFile)
+			} else {
+				err = s.server.ListenAndServe()
+			}
+			if err == http.ErrServerClosed {
+				break
+			}
+			if err != nil {
+				klog.ErrorS(err, "metrics server terminated unexpectedly and will be restarted")
+			}
+			time.Sleep(restartDelay)
+		}
+	}()
+}
+
+func (s *Server) handler(gatherer prometheus.Gatherer) http.Handler {
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/metrics", s.authenticate(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})))
+	serveMux.HandleFunc("/healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	if s.opts.EnableProfiling {
+		serveMux.Handle("/debug/pprof/", s.authenticate(http.HandlerFunc(pprof.Index)))
+		serveMux.Handle("/debug/pprof/cmdline", s.authenticate(http.HandlerFunc(pprof.Cmdline)))
+		serveMux.Handle("/debug/pprof/profile", s.authenticate(http.HandlerFunc(pprof.Profile)))
+		serveMux.Handle("/debug/pprof/symbol", s.authenticate(http.HandlerFunc(pprof.Symbol)))
+		serveMux.Handle("/debug/pprof/trace", s.authenticate(http.HandlerFunc(pprof.Trace)))
+	}
+	return serveMux
+}
+
+// authenticate wraps the given handler so that it is only invoked for
+// requests presenting the configured bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.opts.BearerTokenFile == "" {
+		return next
+	}
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		expected, err := ioutil.ReadFile(s.opts.BearerTokenFile)
+		if err != nil {
+			klog.ErrorS(err, "failed to read metrics bearer token file")
+			http.Error(rw, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		expected = bytes.TrimSpace(expected)
+		header := r.Header.Get("Authorization")
+		if len(expected) == 0 || !strings.HasPrefix(header, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, "Bearer ")), expected) != 1 {
+			rw.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(rw, r)
+	})
+}
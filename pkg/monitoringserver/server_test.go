@@ -0,0 +1,158 @@
+package monitoringserver
+
+import (
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+)
+
+func newTestHandler(t *testing.T, opts Options) http.Handler {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_total", Help: "test"})
+	registry.MustRegister(counter)
+	counter.Inc()
+	if opts.Port == 0 {
+		opts.Port = DefaultPort
+	}
+	examinee, err := NewServer(opts, registry)
+	assert.NilError(t, err)
+	return examinee.server.Handler
+}
+
+func serve(handler http.Handler, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_Options_AddFlags(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	examinee := &Options{}
+	examinee.AddFlags(fs)
+
+	// EXERCISE
+	err := fs.Parse([]string{
+		"-metrics-port=9443",
+		"-metrics-tls-cert-file=/tls/tls.crt",
+		"-metrics-tls-key-file=/tls/tls.key",
+		"-metrics-bearer-token-file=/auth/token",
+		"-metrics-enable-profiling",
+	})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Options{
+		Port:            9443,
+		TLSCertFile:     "/tls/tls.crt",
+		TLSKeyFile:      "/tls/tls.key",
+		BearerTokenFile: "/auth/token",
+		EnableProfiling: true,
+	}, *examinee)
+	assert.NilError(t, examinee.Validate())
+	assert.Equal(t, "https://0.0.0.0:9443", examinee.URL())
+}
+
+func Test_Options_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		opts          Options
+		expectedError string
+	}{
+		{"defaults", Options{Port: DefaultPort}, ""},
+		{"port_zero", Options{}, "invalid metrics server port: 0"},
+		{"port_too_large", Options{Port: 65536}, "invalid metrics server port: 65536"},
+		{"cert_without_key", Options{Port: DefaultPort, TLSCertFile: "tls.crt"}, "the TLS certificate file and the TLS key file of the metrics server must be set together"},
+		{"key_without_cert", Options{Port: DefaultPort, TLSKeyFile: "tls.key"}, "the TLS certificate file and the TLS key file of the metrics server must be set together"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			err := tc.opts.Validate()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_Server_Unauthenticated(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestHandler(t, Options{})
+
+	// EXERCISE
+	metrics := serve(examinee, "/metrics", "")
+	health := serve(examinee, "/healthz", "")
+	pprof := serve(examinee, "/debug/pprof/", "")
+
+	// VERIFY
+	assert.Equal(t, http.StatusOK, metrics.Code)
+	assert.Assert(t, len(metrics.Body.String()) > 0)
+	assert.Equal(t, http.StatusOK, health.Code)
+	assert.Equal(t, http.StatusNotFound, pprof.Code)
+}
+
+func Test_Server_BearerToken(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NilError(t, ioutil.WriteFile(tokenFile, []byte("token1\n"), 0600))
+	examinee := newTestHandler(t, Options{BearerTokenFile: tokenFile, EnableProfiling: true})
+
+	for _, tc := range []struct {
+		path          string
+		authorization string
+		expectedCode  int
+	}{
+		{"/metrics", "", http.StatusUnauthorized},
+		{"/metrics", "Bearer token2", http.StatusUnauthorized},
+		{"/metrics", "Basic token1", http.StatusUnauthorized},
+		{"/metrics", "Bearer token1", http.StatusOK},
+		{"/debug/pprof/", "", http.StatusUnauthorized},
+		{"/debug/pprof/", "Bearer token1", http.StatusOK},
+		{"/healthz", "", http.StatusOK},
+	} {
+		// EXERCISE
+		rec := serve(examinee, tc.path, tc.authorization)
+
+		// VERIFY
+		assert.Equal(t, tc.expectedCode, rec.Code, "%s with %q", tc.path, tc.authorization)
+	}
+}
+
+func Test_Server_BearerTokenFileMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newTestHandler(t, Options{BearerTokenFile: filepath.Join(t.TempDir(), "missing")})
+
+	// EXERCISE
+	rec := serve(examinee, "/metrics", "Bearer token1")
+
+	// VERIFY
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,149 @@
+package runcli
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// scpLikeURLPattern matches Git remote URLs in scp-like syntax, e.g.
+// `git@github.com:org/repo.git`.
+var scpLikeURLPattern = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// LoadPipelineRun reads a pipeline run in YAML or JSON format.
+func LoadPipelineRun(r io.Reader) (*api.PipelineRun, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	run := &api.PipelineRun{}
+	if err := yaml.Unmarshal(data, run); err != nil {
+		return nil, errors.Wrap(err, "invalid pipeline run")
+	}
+	if run.Kind != "" && run.Kind != "PipelineRun" {
+		return nil, fmt.Errorf("invalid pipeline run: unexpected kind %q", run.Kind)
+	}
+	if run.APIVersion != "" && run.APIVersion != api.SchemeGroupVersion.String() {
+		return nil, fmt.Errorf("invalid pipeline run: unexpected apiVersion %q", run.APIVersion)
+	}
+	return run, nil
+}
+
+// GitCheckout describes the location of a file in a local Git checkout.
+type GitCheckout struct {
+	// RepoURL is the HTTP(S) URL of the remote repository.
+	RepoURL string
+
+	// Revision is the commit SHA checked out.
+	Revision string
+
+	// Path is the path of the file relative to the root of the checkout.
+	Path string
+}
+
+// PipelineRun returns a pipeline run executing the pipeline defined by
+// the file of the checkout.
+func (c GitCheckout) PipelineRun() *api.PipelineRun {
+	run := &api.PipelineRun{
+		Spec: api.PipelineSpec{
+			JenkinsFile: api.JenkinsFile{
+				URL:      c.RepoURL,
+				Revision: c.Revision,
+				Path:     c.Path,
+			},
+		},
+	}
+	run.SetGenerateName("run-")
+	return run
+}
+
+// LocateInGitCheckout returns the location of the given local file in the
+// Git checkout containing it, using the URL of the given remote and the
+// commit checked out. The commit must have been pushed to the remote, as
+// Steward clones the repository from there.
+func LocateInGitCheckout(file, remote string) (*GitCheckout, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(absFile)
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	revision, err := git(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	remoteURL, err := git(dir, "remote", "get-url", remote)
+	if err != nil {
+		return nil, err
+	}
+	repoURL, err := HTTPSRepoURL(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	absFile, err = filepath.EvalSymlinks(absFile)
+	if err != nil {
+		return nil, err
+	}
+	path, err := filepath.Rel(root, absFile)
+	if err != nil {
+		return nil, err
+	}
+	return &GitCheckout{
+		RepoURL:  repoURL,
+		Revision: revision,
+		Path:     filepath.ToSlash(path),
+	}, nil
+}
+
+// HTTPSRepoURL returns the HTTP(S) URL of a Git repository for the given
+// remote URL. HTTP(S) URLs are returned unchanged, SSH URLs and scp-like
+// URLs are converted to HTTPS URLs of the same host and path.
+func HTTPSRepoURL(remoteURL string) (string, error) {
+	switch {
+	case strings.HasPrefix(remoteURL, "https://"), strings.HasPrefix(remoteURL, "http://"):
+		return remoteURL, nil
+	case strings.HasPrefix(remoteURL, "ssh://"):
+		rest := strings.TrimPrefix(remoteURL, "ssh://")
+		if i := strings.Index(rest, "@"); i >= 0 && i < strings.Index(rest+"/", "/") {
+			rest = rest[i+1:]
+		}
+		host, path := rest, ""
+		if i := strings.Index(rest, "/"); i >= 0 {
+			host, path = rest[:i], rest[i:]
+		}
+		if i := strings.Index(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		return "https://" + host + path, nil
+	}
+	if match := scpLikeURLPattern.FindStringSubmatch(remoteURL); match != nil {
+		return "https://" + match[1] + "/" + strings.TrimPrefix(match[2], "/"), nil
+	}
+	return "", fmt.Errorf("unsupported Git remote URL %q", remoteURL)
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", errors.Wrapf(err, "git %s", strings.Join(args, " "))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
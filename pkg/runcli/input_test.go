@@ -0,0 +1,130 @@
+package runcli
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	assert "gotest.tools/assert"
+)
+
+func Test_LoadPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	input := `
+apiVersion: steward.sap.com/v1alpha1
+kind: PipelineRun
+metadata:
+  generateName: build-
+spec:
+  jenkinsFile:
+    repoUrl: https://github.com/org/repo
+    revision: main
+    relativePath: Jenkinsfile
+  args:
+    arg1: value1
+`
+
+	// EXERCISE
+	run, err := LoadPipelineRun(strings.NewReader(input))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "build-", run.GetGenerateName())
+	assert.DeepEqual(t, api.JenkinsFile{URL: "https://github.com/org/repo", Revision: "main", Path: "Jenkinsfile"}, run.Spec.JenkinsFile)
+	assert.DeepEqual(t, map[string]string{"arg1": "value1"}, run.Spec.Args)
+}
+
+func Test_LoadPipelineRun_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		input         string
+		expectedError string
+	}{
+		{
+			name:          "wrong_kind",
+			input:         "kind: Tenant",
+			expectedError: `invalid pipeline run: unexpected kind "Tenant"`,
+		},
+		{
+			name:          "wrong_api_version",
+			input:         "apiVersion: v1\nkind: PipelineRun",
+			expectedError: `invalid pipeline run: unexpected apiVersion "v1"`,
+		},
+		{
+			name:          "invalid_yaml",
+			input:         "spec: [",
+			expectedError: "invalid pipeline run",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			_, err := LoadPipelineRun(strings.NewReader(tc.input))
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_HTTPSRepoURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		remoteURL     string
+		expectedURL   string
+		expectedError string
+	}{
+		{remoteURL: "https://github.com/org/repo.git", expectedURL: "https://github.com/org/repo.git"},
+		{remoteURL: "http://git.example.com/repo", expectedURL: "http://git.example.com/repo"},
+		{remoteURL: "git@github.com:org/repo.git", expectedURL: "https://github.com/org/repo.git"},
+		{remoteURL: "github.com:org/repo.git", expectedURL: "https://github.com/org/repo.git"},
+		{remoteURL: "ssh://git@github.com/org/repo.git", expectedURL: "https://github.com/org/repo.git"},
+		{remoteURL: "ssh://git@git.example.com:2222/org/repo.git", expectedURL: "https://git.example.com/org/repo.git"},
+		{remoteURL: "/local/repo", expectedError: `unsupported Git remote URL "/local/repo"`},
+	} {
+		tc := tc
+		t.Run(tc.remoteURL, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := HTTPSRepoURL(tc.remoteURL)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedURL, result)
+			}
+		})
+	}
+}
+
+func Test_GitCheckout_PipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	checkout := GitCheckout{
+		RepoURL:  "https://github.com/org/repo",
+		Revision: "0123456789abcdef0123456789abcdef01234567",
+		Path:     "ci/Jenkinsfile",
+	}
+
+	// EXERCISE
+	run := checkout.PipelineRun()
+
+	// VERIFY
+	assert.Equal(t, "run-", run.GetGenerateName())
+	assert.DeepEqual(t, api.JenkinsFile{
+		URL:      "https://github.com/org/repo",
+		Revision: "0123456789abcdef0123456789abcdef01234567",
+		Path:     "ci/Jenkinsfile",
+	}, run.Spec.JenkinsFile)
+}
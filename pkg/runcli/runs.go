@@ -0,0 +1,224 @@
+package runcli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// jenkinsfileRunnerContainer is the name of the container of the run pod
+// executing the Jenkinsfile Runner.
+const jenkinsfileRunnerContainer = "step-jenkinsfile-runner"
+
+// Submit creates the given pipeline run in the given namespace and
+// returns the created object. If the pipeline run has no name, a name is
+// generated.
+func Submit(ctx context.Context, factory k8s.ClientFactory, namespace string, run *api.PipelineRun) (*api.PipelineRun, error) {
+	run = run.DeepCopy()
+	run.SetNamespace(namespace)
+	run.SetResourceVersion("")
+	if run.GetName() == "" && run.GetGenerateName() == "" {
+		run.SetGenerateName("run-")
+	}
+	run.Status = api.PipelineStatus{}
+	created, err := factory.StewardV1alpha1().PipelineRuns(namespace).Create(ctx, run, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pipeline run")
+	}
+	return created, nil
+}
+
+// Abort requests the abortion of the pipeline run with the given name.
+func Abort(ctx context.Context, factory k8s.ClientFactory, namespace, name string) error {
+	client := factory.StewardV1alpha1().PipelineRuns(namespace)
+	run, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if run.Spec.Intent == api.IntentAbort {
+		return nil
+	}
+	run.Spec.Intent = api.IntentAbort
+	if _, err := client.Update(ctx, run, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to abort pipeline run %q", name)
+	}
+	return nil
+}
+
+// List writes a table of the pipeline runs in the given namespace
+// matching the given label selector to out, sorted by creation time.
+// If namespace is empty, pipeline runs in all namespaces are listed.
+func List(ctx context.Context, factory k8s.ClientFactory, namespace, labelSelector string, now time.Time, out io.Writer) error {
+	list, err := factory.StewardV1alpha1().PipelineRuns(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return err
+	}
+	runs := list.Items
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].ObjectMeta.CreationTimestamp.Before(&runs[j].ObjectMeta.CreationTimestamp)
+	})
+
+	writer := tabwriter.NewWriter(out, 0, 8, 3, ' ', 0)
+	if namespace == "" {
+		fmt.Fprint(writer, "NAMESPACE\t")
+	}
+	fmt.Fprintln(writer, "NAME\tSTATE\tRESULT\tAGE")
+	for _, run := range runs {
+		if namespace == "" {
+			fmt.Fprintf(writer, "%s\t", run.GetNamespace())
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			run.GetName(),
+			orNone(string(run.Status.State)),
+			orNone(string(run.Status.Result)),
+			age(run.GetCreationTimestamp(), now),
+		)
+	}
+	return writer.Flush()
+}
+
+// Status writes the state of the pipeline run with the given name to out.
+// If watch is true, a line is written for each change of the state until
+// the pipeline run is finished or the context is cancelled.
+func Status(ctx context.Context, factory k8s.ClientFactory, namespace, name string, watch bool, out io.Writer) error {
+	client := factory.StewardV1alpha1().PipelineRuns(namespace)
+	run, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	last := statusLine(run)
+	fmt.Fprintln(out, last)
+	if !watch || run.Status.State == api.StateFinished {
+		return nil
+	}
+
+	watcher, err := client.Watch(ctx, metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: run.GetResourceVersion(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return errors.New("watch closed before the pipeline run finished")
+			}
+			run, isRun := event.Object.(*api.PipelineRun)
+			if !isRun {
+				continue
+			}
+			if line := statusLine(run); line != last {
+				fmt.Fprintln(out, line)
+				last = line
+			}
+			if run.Status.State == api.StateFinished {
+				return nil
+			}
+		}
+	}
+}
+
+// Logs writes the log of the Jenkinsfile Runner of the pipeline run with
+// the given name to out. If follow is true, the log is streamed until the
+// Jenkinsfile Runner terminates. Archived logs of cleaned up pipeline runs
+// are not retrieved, but their location is returned in the error.
+func Logs(ctx context.Context, factory k8s.ClientFactory, namespace, name string, follow bool, out io.Writer) error {
+	run, err := factory.StewardV1alpha1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	podName, err := runPodName(ctx, factory, run)
+	if err != nil {
+		return err
+	}
+	if podName == "" && run.Status.LogURL != "" {
+		return fmt.Errorf("pipeline run %q has no run pod, its log has been archived at %s", name, run.Status.LogURL)
+	}
+	if podName == "" {
+		return fmt.Errorf("pipeline run %q has no run pod (state: %s)", name, orNone(string(run.Status.State)))
+	}
+	stream, err := factory.CoreV1().Pods(run.Status.Namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: jenkinsfileRunnerContainer,
+		Follow:    follow,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	_, err = io.Copy(out, stream)
+	return err
+}
+
+// TenantNamespace returns the tenant namespace of the tenant with the
+// given name in the given client namespace.
+func TenantNamespace(ctx context.Context, factory k8s.ClientFactory, clientNamespace, tenantName string) (string, error) {
+	tenant, err := factory.StewardV1alpha1().Tenants(clientNamespace).Get(ctx, tenantName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if tenant.Status.TenantNamespaceName == "" {
+		return "", fmt.Errorf("tenant %q in namespace %q has no tenant namespace", tenantName, clientNamespace)
+	}
+	return tenant.Status.TenantNamespaceName, nil
+}
+
+// runPodName returns the name of the pod executing the Jenkinsfile Runner
+// of the given pipeline run or an empty string if there is none.
+func runPodName(ctx context.Context, factory k8s.ClientFactory, run *api.PipelineRun) (string, error) {
+	if run.Status.Namespace == "" {
+		return "", nil
+	}
+	pods, err := factory.CoreV1().Pods(run.Status.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == jenkinsfileRunnerContainer {
+				return pod.GetName(), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func statusLine(run *api.PipelineRun) string {
+	line := fmt.Sprintf("%s: state %s", run.GetName(), orNone(string(run.Status.State)))
+	if run.Status.Result != "" {
+		line += fmt.Sprintf(", result %s", run.Status.Result)
+	}
+	if run.Status.MessageShort != "" {
+		line += fmt.Sprintf(": %s", run.Status.MessageShort)
+	}
+	return line
+}
+
+func age(created metav1.Time, now time.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(created.Time))
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
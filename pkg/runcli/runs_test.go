@@ -0,0 +1,201 @@
+package runcli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_Submit(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory()
+	run := fake.PipelineRun("", "other", api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{URL: "https://git.example.com/repo1.git"},
+	})
+	run.Status.State = api.StateFinished
+
+	// EXERCISE
+	created, err := Submit(ctx, cf, "ns1", run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "ns1", created.GetNamespace())
+	assert.Equal(t, "run-", created.GetGenerateName())
+	assert.Equal(t, api.StateUndefined, created.Status.State)
+	assert.Equal(t, "https://git.example.com/repo1.git", created.Spec.JenkinsFile.URL)
+}
+
+func Test_Abort(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(fake.PipelineRun("run1", "ns1", api.PipelineSpec{}))
+
+	// EXERCISE
+	err := Abort(ctx, cf, "ns1", "run1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	run, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.IntentAbort, run.Spec.Intent)
+}
+
+func Test_Abort_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := fake.NewClientFactory()
+
+	// EXERCISE
+	err := Abort(context.Background(), cf, "ns1", "run1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, `"run1" not found`)
+}
+
+func Test_List(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2021, 12, 1, 12, 0, 0, 0, time.UTC)
+	run1 := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run1.SetCreationTimestamp(metav1.NewTime(now.Add(-2 * time.Hour)))
+	run1.Status.State = api.StateFinished
+	run1.Status.Result = api.ResultSuccess
+	run2 := fake.PipelineRun("run2", "ns1", api.PipelineSpec{})
+	run2.SetCreationTimestamp(metav1.NewTime(now.Add(-5 * time.Minute)))
+	run3 := fake.PipelineRun("run3", "ns2", api.PipelineSpec{})
+	cf := fake.NewClientFactory(run2, run1, run3)
+	out := &bytes.Buffer{}
+
+	// EXERCISE
+	err := List(context.Background(), cf, "ns1", "", now, out)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"NAME   STATE      RESULT    AGE",
+		"run1   finished   success   120m",
+		"run2   <none>     <none>    5m",
+		"",
+	}, "\n"), out.String())
+}
+
+func Test_Status_Watch(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateWaiting
+	cf := fake.NewClientFactory(run)
+	watcher := watch.NewFake()
+	cf.StewardClientset().PrependWatchReactor("pipelineruns", k8stesting.DefaultWatchReactor(watcher, nil))
+	out := &bytes.Buffer{}
+	done := make(chan error)
+
+	// EXERCISE
+	go func() { done <- Status(ctx, cf, "ns1", "run1", true, out) }()
+	for _, state := range []api.State{api.StateWaiting, api.StateRunning, api.StateFinished} {
+		update := run.DeepCopy()
+		update.Status.State = state
+		if state == api.StateFinished {
+			update.Status.Result = api.ResultSuccess
+		}
+		watcher.Modify(update)
+	}
+
+	// VERIFY
+	assert.NilError(t, <-done)
+	assert.Equal(t, strings.Join([]string{
+		"run1: state waiting",
+		"run1: state running",
+		"run1: state finished, result success",
+		"",
+	}, "\n"), out.String())
+}
+
+func Test_Logs(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "run-ns1"
+	pod := &corev1.Pod{
+		ObjectMeta: fake.ObjectMeta("pod1", "run-ns1"),
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: jenkinsfileRunnerContainer}}},
+	}
+	cf := fake.NewClientFactory(run, pod)
+	out := &bytes.Buffer{}
+
+	// EXERCISE
+	err := Logs(context.Background(), cf, "ns1", "run1", false, out)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "fake logs", out.String())
+}
+
+func Test_Logs_NoRunPod(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	cf := fake.NewClientFactory(run)
+
+	// EXERCISE
+	err := Logs(context.Background(), cf, "ns1", "run1", false, &bytes.Buffer{})
+
+	// VERIFY
+	assert.Error(t, err, `pipeline run "run1" has no run pod (state: new)`)
+}
+
+func Test_Logs_Archived(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateFinished
+	run.Status.LogURL = "https://logs.example.com/run1.log"
+	cf := fake.NewClientFactory(run)
+
+	// EXERCISE
+	err := Logs(context.Background(), cf, "ns1", "run1", false, &bytes.Buffer{})
+
+	// VERIFY
+	assert.Error(t, err, `pipeline run "run1" has no run pod, its log has been archived at https://logs.example.com/run1.log`)
+}
+
+func Test_TenantNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	cf := fake.NewClientFactory(tenant, fake.Tenant("tenant2", "client1"))
+
+	// EXERCISE
+	namespace, err := TenantNamespace(context.Background(), cf, "client1", "tenant1")
+	_, err2 := TenantNamespace(context.Background(), cf, "client1", "tenant2")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "tenantns1", namespace)
+	assert.Error(t, err2, `tenant "tenant2" in namespace "client1" has no tenant namespace`)
+}
@@ -0,0 +1,219 @@
+package runctl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/aborthandler"
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// defaultAbortHandlerWorkers is the number of abort handler workers
+	// if not configured otherwise.
+	defaultAbortHandlerWorkers = 4
+)
+
+// abortHandlerExecutor invokes abort handlers asynchronously by a fixed
+// number of workers, so that slow handlers neither block the workers of
+// the controller nor run in unbounded parallelism.
+type abortHandlerExecutor struct {
+	workers int
+	queue   workqueue.Interface
+
+	// onDone is called with the key of the pipeline run after the
+	// handlers have been invoked for it.
+	onDone func(key string)
+
+	mutex sync.Mutex
+	jobs  map[types.UID]*abortHandlerJob
+}
+
+// abortHandlerJob is the invocation of abort handlers for a pipeline run.
+type abortHandlerJob struct {
+	key      string
+	run      *api.PipelineRun
+	handlers []aborthandler.Registration
+	statuses []api.AbortHandlerStatus
+	done     bool
+}
+
+func newAbortHandlerExecutor(workers int, onDone func(key string)) *abortHandlerExecutor {
+	if workers <= 0 {
+		workers = defaultAbortHandlerWorkers
+	}
+	return &abortHandlerExecutor{
+		workers: workers,
+		queue:   workqueue.NewNamed(metrics.AbortHandlersWorkqueueName),
+		onDone:  onDone,
+		jobs:    map[types.UID]*abortHandlerJob{},
+	}
+}
+
+// start starts the workers, which run until stopCh is closed.
+func (e *abortHandlerExecutor) start(stopCh <-chan struct{}) {
+	klog.V(2).Infof("Starting %d abort handler workers", e.workers)
+	for i := 0; i < e.workers; i++ {
+		go wait.Until(e.runWorker, time.Second, stopCh)
+	}
+}
+
+// shutDown makes the workers stop after their current invocation.
+func (e *abortHandlerExecutor) shutDown() {
+	e.queue.ShutDown()
+}
+
+// submit schedules the invocation of the given handlers for the given
+// pipeline run, unless there is a job for the pipeline run already.
+// The pipeline run must not be modified afterwards.
+func (e *abortHandlerExecutor) submit(key string, run *api.PipelineRun, handlers []aborthandler.Registration) {
+	uid := run.GetUID()
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if _, exists := e.jobs[uid]; exists {
+		return
+	}
+	e.jobs[uid] = &abortHandlerJob{
+		key:      key,
+		run:      run,
+		handlers: handlers,
+	}
+	e.queue.Add(uid)
+}
+
+// take returns the outcomes of the finished job of the pipeline run with
+// the given UID and removes the job. If the job has not finished yet,
+// running is true. If there is no job, both results are zero.
+func (e *abortHandlerExecutor) take(uid types.UID) (statuses []api.AbortHandlerStatus, running bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	job, exists := e.jobs[uid]
+	if !exists {
+		return nil, false
+	}
+	if !job.done {
+		return nil, true
+	}
+	delete(e.jobs, uid)
+	return job.statuses, false
+}
+
+func (e *abortHandlerExecutor) runWorker() {
+	for e.processNextJob() {
+	}
+}
+
+func (e *abortHandlerExecutor) processNextJob() bool {
+	item, shutdown := e.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer e.queue.Done(item)
+
+	uid := item.(types.UID)
+	e.mutex.Lock()
+	job := e.jobs[uid]
+	e.mutex.Unlock()
+	if job == nil {
+		return true
+	}
+
+	statuses := aborthandler.Invoke(context.Background(), job.handlers, job.run)
+
+	e.mutex.Lock()
+	job.statuses = statuses
+	job.done = true
+	e.mutex.Unlock()
+	e.onDone(job.key)
+	return true
+}
+
+// invokeAbortHandlers ensures that the configured abort handlers have
+// been invoked for the given pipeline run.
+// Handlers which succeeded before according to the status of the pipeline
+// run are skipped. The others are invoked asynchronously by the abort
+// handler executor, which enqueues the pipeline run again when they have
+// finished. Their outcomes are then recorded and committed to the status
+// of the pipeline run. For each handler which did not succeed a warning
+// event is emitted.
+// It returns true if all handlers have been invoked and their outcomes
+// have been committed. Failing handlers do not prevent the cleanup of the
+// pipeline run.
+func (c *Controller) invokeAbortHandlers(ctx context.Context, pipelineRun k8s.PipelineRun) (bool, error) {
+	pending := c.pendingAbortHandlers(pipelineRun)
+	if len(pending) == 0 {
+		return true, nil
+	}
+
+	pipelineRunAPIObj := pipelineRun.GetAPIObject()
+	statuses, running := c.abortHandlerExecutor.take(pipelineRunAPIObj.GetUID())
+	if running {
+		return false, nil
+	}
+	if statuses == nil {
+		klog.V(4).Infof("invoking %d abort handler(s) for pipeline run %q", len(pending), pipelineRun.GetKey())
+		c.abortHandlerExecutor.submit(pipelineRun.GetKey(), pipelineRunAPIObj.DeepCopy(), pending)
+		return false, nil
+	}
+
+	for _, status := range statuses {
+		if status.Result == api.AbortHandlerResultSucceeded {
+			klog.V(4).Infof("abort handler %q succeeded for pipeline run %q", status.Name, pipelineRun.GetKey())
+			continue
+		}
+		klog.V(3).Infof("abort handler %q %s for pipeline run %q: %s", status.Name, status.Result, pipelineRun.GetKey(), status.Message)
+		c.recorder.Eventf(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonAbortHandlerFailed,
+			"abort handler %q %s: %s", status.Name, status.Result, status.Message)
+	}
+	pipelineRun.UpdateAbortHandlers(c.mergeAbortHandlerStatuses(pipelineRun.GetStatus().AbortHandlers, statuses))
+	if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// pendingAbortHandlers returns the configured abort handlers which did not
+// succeed for the given pipeline run yet.
+func (c *Controller) pendingAbortHandlers(pipelineRun k8s.PipelineRun) []aborthandler.Registration {
+	succeeded := map[string]bool{}
+	for _, status := range pipelineRun.GetStatus().AbortHandlers {
+		if status.Result == api.AbortHandlerResultSucceeded {
+			succeeded[status.Name] = true
+		}
+	}
+	var result []aborthandler.Registration
+	for _, handler := range c.abortHandlers {
+		if !succeeded[handler.Name] {
+			result = append(result, handler)
+		}
+	}
+	return result
+}
+
+// mergeAbortHandlerStatuses returns the statuses of the configured abort
+// handlers in configuration order. The latest statuses take precedence
+// over the previous ones.
+func (c *Controller) mergeAbortHandlerStatuses(previous, latest []api.AbortHandlerStatus) []api.AbortHandlerStatus {
+	byName := map[string]api.AbortHandlerStatus{}
+	for _, status := range previous {
+		byName[status.Name] = status
+	}
+	for _, status := range latest {
+		byName[status.Name] = status
+	}
+	result := make([]api.AbortHandlerStatus, 0, len(c.abortHandlers))
+	for _, handler := range c.abortHandlers {
+		if status, found := byName[handler.Name]; found {
+			result = append(result, status)
+		}
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/aborthandler"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_Controller_invokeAbortHandlers_NoHandlers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	done, err := controller.invokeAbortHandlers(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, done)
+	assert.Assert(t, is.Nil(pipelineRun.GetStatus().AbortHandlers))
+	assert.Assert(t, is.Len(controller.recorder.(*record.FakeRecorder).Events, 0))
+}
+
+func Test_Controller_invokeAbortHandlers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Result = api.ResultAborted
+	controller, cf := newController(run)
+	var seenResult api.Result
+	controller.abortHandlers = []aborthandler.Registration{
+		{
+			Name: "handler1",
+			Handler: aborthandler.HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				seenResult = run.Status.Result
+				return nil
+			}),
+		},
+		{
+			Name: "handler2",
+			Handler: aborthandler.HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				return errors.New("error1")
+			}),
+		},
+	}
+	var doneKeys []string
+	controller.abortHandlerExecutor = newAbortHandlerExecutor(1, func(key string) {
+		doneKeys = append(doneKeys, key)
+	})
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	done, err := controller.invokeAbortHandlers(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !done)
+	assert.Equal(t, api.Result(""), seenResult)
+	assert.Assert(t, is.Nil(pipelineRun.GetStatus().AbortHandlers))
+
+	// EXERCISE
+	assert.Assert(t, controller.abortHandlerExecutor.processNextJob())
+
+	// VERIFY
+	assert.Equal(t, api.ResultAborted, seenResult)
+	assert.DeepEqual(t, []string{"ns1/run1"}, doneKeys)
+
+	// EXERCISE
+	done, err = controller.invokeAbortHandlers(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, done)
+	run, err = cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	statuses := run.Status.AbortHandlers
+	assert.Assert(t, is.Len(statuses, 2))
+	assert.Equal(t, "handler1", statuses[0].Name)
+	assert.Equal(t, api.AbortHandlerResultSucceeded, statuses[0].Result)
+	assert.Equal(t, "handler2", statuses[1].Name)
+	assert.Equal(t, api.AbortHandlerResultFailed, statuses[1].Result)
+	assert.Equal(t, "error1", statuses[1].Message)
+
+	events := controller.recorder.(*record.FakeRecorder).Events
+	assert.Assert(t, is.Len(events, 1))
+	assert.Equal(t, `Warning AbortHandlerFailed abort handler "handler2" failed: error1`, <-events)
+}
+
+func Test_Controller_invokeAbortHandlers_SkipsSucceededHandlers(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.AbortHandlers = []api.AbortHandlerStatus{
+		{Name: "handler1", Result: api.AbortHandlerResultSucceeded},
+		{Name: "handler2", Result: api.AbortHandlerResultTimeout},
+	}
+	controller, cf := newController(run)
+	var invoked []string
+	handler := func(name string) aborthandler.Registration {
+		return aborthandler.Registration{
+			Name: name,
+			Handler: aborthandler.HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				invoked = append(invoked, name)
+				return nil
+			}),
+		}
+	}
+	controller.abortHandlers = []aborthandler.Registration{handler("handler1"), handler("handler2")}
+	controller.abortHandlerExecutor = newAbortHandlerExecutor(1, func(string) {})
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	done, err := controller.invokeAbortHandlers(ctx, pipelineRun)
+	assert.NilError(t, err)
+	assert.Assert(t, !done)
+	assert.Assert(t, controller.abortHandlerExecutor.processNextJob())
+	done, err = controller.invokeAbortHandlers(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, done)
+	assert.DeepEqual(t, []string{"handler2"}, invoked)
+	statuses := pipelineRun.GetStatus().AbortHandlers
+	assert.Assert(t, is.Len(statuses, 2))
+	assert.Equal(t, api.AbortHandlerResultSucceeded, statuses[0].Result)
+	assert.Equal(t, api.AbortHandlerResultSucceeded, statuses[1].Result)
+
+	// EXERCISE
+	done, err = controller.invokeAbortHandlers(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, done)
+	assert.DeepEqual(t, []string{"handler2"}, invoked)
+}
+
+func Test_abortHandlerExecutor_submit_Deduplicates(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newAbortHandlerExecutor(0, func(string) {})
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.SetUID("uid1")
+
+	// EXERCISE
+	examinee.submit("ns1/run1", run, nil)
+	examinee.submit("ns1/run1", run, nil)
+
+	// VERIFY
+	assert.Equal(t, defaultAbortHandlerWorkers, examinee.workers)
+	assert.Equal(t, 1, examinee.queue.Len())
+	_, running := examinee.take("uid1")
+	assert.Assert(t, running)
+}
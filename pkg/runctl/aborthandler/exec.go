@@ -0,0 +1,134 @@
+package aborthandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// maxExecOutputLength is the maximum number of bytes of the output
+	// of a failed exec hook included in the error message.
+	maxExecOutputLength = 512
+)
+
+// ExecHandler is a Handler running an executable (exec hook).
+//
+// The pipeline run is passed as JSON document via stdin. In addition,
+// the following environment variables are set:
+//
+//   - `STEWARD_PIPELINERUN_NAMESPACE`: the namespace of the pipeline run
+//   - `STEWARD_PIPELINERUN_NAME`: the name of the pipeline run
+//   - `STEWARD_PIPELINERUN_RESULT`: the result of the pipeline run, empty
+//     if the pipeline run has been deleted before it finished
+//
+// The executable must exit with code zero on success. Otherwise the
+// handler fails with the tail of the combined stdout and stderr output.
+type ExecHandler struct {
+	// Path is the path of the executable.
+	Path string
+}
+
+// let compiler verify interface compliance
+var _ Handler = (*ExecHandler)(nil)
+
+// Handle implements Handler.
+func (h *ExecHandler) Handle(ctx context.Context, run *api.PipelineRun) error {
+	runJSON, err := json.Marshal(run)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize pipeline run")
+	}
+
+	cmd := exec.CommandContext(ctx, h.Path)
+	cmd.Stdin = bytes.NewReader(runJSON)
+	cmd.Env = append(os.Environ(),
+		"STEWARD_PIPELINERUN_NAMESPACE="+run.GetNamespace(),
+		"STEWARD_PIPELINERUN_NAME="+run.GetName(),
+		"STEWARD_PIPELINERUN_RESULT="+string(run.Status.Result),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		output = bytes.TrimSpace(output)
+		if len(output) > maxExecOutputLength {
+			output = append([]byte("..."), output[len(output)-maxExecOutputLength:]...)
+		}
+		if len(output) > 0 {
+			return errors.Wrapf(err, "exec hook %q failed: %s", h.Path, output)
+		}
+		return errors.Wrapf(err, "exec hook %q failed", h.Path)
+	}
+	return nil
+}
+
+// ParseExecRegistration parses the specification of an exec hook and
+// returns a registration for it.
+// The format of the specification is `<name>[:<timeout>]=<path>`, e.g.
+// `delete-vms:5m=/opt/hooks/delete-vms`. The name must be a DNS-1123
+// label, the timeout a Go duration and the path an absolute path.
+func ParseExecRegistration(spec string) (Registration, error) {
+	wrapError := func(cause error) error {
+		return errors.WithMessagef(cause, "invalid exec hook specification %q", spec)
+	}
+
+	nameAndTimeout, path, found := cut(spec, "=")
+	if !found {
+		return Registration{}, wrapError(errors.New("expected format '<name>[:<timeout>]=<path>'"))
+	}
+	name, timeoutString, hasTimeout := cut(nameAndTimeout, ":")
+	if msgs := validation.IsDNS1123Label(name); len(msgs) > 0 {
+		return Registration{}, wrapError(errors.Errorf("invalid name: %s", strings.Join(msgs, "; ")))
+	}
+	var timeout time.Duration
+	if hasTimeout {
+		var err error
+		timeout, err = time.ParseDuration(timeoutString)
+		if err != nil || timeout <= 0 {
+			return Registration{}, wrapError(errors.Errorf("invalid timeout %q", timeoutString))
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return Registration{}, wrapError(errors.Errorf("path %q is not absolute", path))
+	}
+
+	return Registration{
+		Name:    name,
+		Handler: &ExecHandler{Path: path},
+		Timeout: timeout,
+	}, nil
+}
+
+// ParseExecRegistrations parses a list of exec hook specifications.
+// See ParseExecRegistration for the format. Names must be unique.
+func ParseExecRegistrations(specs []string) ([]Registration, error) {
+	result := make([]Registration, 0, len(specs))
+	names := map[string]bool{}
+	for _, spec := range specs {
+		registration, err := ParseExecRegistration(spec)
+		if err != nil {
+			return nil, err
+		}
+		if names[registration.Name] {
+			return nil, errors.Errorf("duplicate exec hook name %q", registration.Name)
+		}
+		names[registration.Name] = true
+		result = append(result, registration)
+	}
+	return result, nil
+}
+
+// cut slices s around the first instance of sep.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
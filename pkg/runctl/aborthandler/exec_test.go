@@ -0,0 +1,146 @@
+package aborthandler
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook")
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+content), 0700)
+	assert.NilError(t, err)
+	return path
+}
+
+func Test_ExecHandler_Handle_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	outputFile := filepath.Join(t.TempDir(), "output")
+	script := writeScript(t, `
+set -e
+echo "$STEWARD_PIPELINERUN_NAMESPACE/$STEWARD_PIPELINERUN_NAME/$STEWARD_PIPELINERUN_RESULT" >"`+outputFile+`"
+cat >>"`+outputFile+`"
+`)
+	examinee := &ExecHandler{Path: script}
+
+	// EXERCISE
+	err := examinee.Handle(context.Background(), newRun())
+
+	// VERIFY
+	assert.NilError(t, err)
+	output, err := ioutil.ReadFile(outputFile)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Contains(string(output), "ns1/run1/aborted\n"))
+	assert.Assert(t, is.Contains(string(output), `"name":"run1"`))
+}
+
+func Test_ExecHandler_Handle_Failure(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	script := writeScript(t, "echo 'something went wrong' >&2\nexit 3\n")
+	examinee := &ExecHandler{Path: script}
+
+	// EXERCISE
+	err := examinee.Handle(context.Background(), newRun())
+
+	// VERIFY
+	assert.Error(t, err, `exec hook "`+script+`" failed: something went wrong: exit status 3`)
+}
+
+func Test_ExecHandler_Handle_Timeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	script := writeScript(t, "exec sleep 10\n")
+	registrations := []Registration{
+		{Name: "hook1", Handler: &ExecHandler{Path: script}, Timeout: 100 * time.Millisecond},
+	}
+
+	// EXERCISE
+	result := Invoke(context.Background(), registrations, newRun())
+
+	// VERIFY
+	assert.Equal(t, api.AbortHandlerResultTimeout, result[0].Result)
+}
+
+func Test_ExecHandler_Handle_NotExecutable(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	path := filepath.Join(t.TempDir(), "missing")
+	examinee := &ExecHandler{Path: path}
+
+	// EXERCISE
+	err := examinee.Handle(context.Background(), newRun())
+
+	// VERIFY
+	assert.Assert(t, os.IsNotExist(errors.Cause(err)), "unexpected error: %v", err)
+}
+
+func Test_ParseExecRegistration(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		spec            string
+		expectedName    string
+		expectedTimeout time.Duration
+		expectedPath    string
+		expectedError   string
+	}{
+		{spec: "hook1=/opt/hook1", expectedName: "hook1", expectedPath: "/opt/hook1"},
+		{spec: "hook1:5m=/opt/hook1", expectedName: "hook1", expectedTimeout: 5 * time.Minute, expectedPath: "/opt/hook1"},
+		{spec: "/opt/hook1", expectedError: `invalid exec hook specification "/opt/hook1": expected format '<name>[:<timeout>]=<path>'`},
+		{spec: "Hook_1=/opt/hook1", expectedError: `invalid exec hook specification "Hook_1=/opt/hook1": invalid name: `},
+		{spec: "hook1:x=/opt/hook1", expectedError: `invalid exec hook specification "hook1:x=/opt/hook1": invalid timeout "x"`},
+		{spec: "hook1:0s=/opt/hook1", expectedError: `invalid exec hook specification "hook1:0s=/opt/hook1": invalid timeout "0s"`},
+		{spec: "hook1=hook1", expectedError: `invalid exec hook specification "hook1=hook1": path "hook1" is not absolute`},
+	} {
+		tc := tc
+		t.Run(tc.spec, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := ParseExecRegistration(tc.spec)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedName, result.Name)
+			assert.Equal(t, tc.expectedTimeout, result.Timeout)
+			assert.DeepEqual(t, &ExecHandler{Path: tc.expectedPath}, result.Handler)
+		})
+	}
+}
+
+func Test_ParseExecRegistrations(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, err := ParseExecRegistrations([]string{"hook1=/opt/hook1", "hook2:1m=/opt/hook2"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(result, 2))
+	assert.Equal(t, "hook2", result[1].Name)
+
+	_, err = ParseExecRegistrations([]string{"hook1=/opt/hook1", "invalid"})
+	assert.ErrorContains(t, err, `invalid exec hook specification "invalid"`)
+
+	_, err = ParseExecRegistrations([]string{"hook1=/opt/hook1", "hook1=/opt/hook2"})
+	assert.Error(t, err, `duplicate exec hook name "hook1"`)
+}
@@ -0,0 +1,146 @@
+package aborthandler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/utils/recovery"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultTimeout is the timeout of handlers registered without
+	// timeout.
+	DefaultTimeout = 1 * time.Minute
+)
+
+// Handler tears down external resources created by a pipeline, e.g.
+// cloud VMs or test tenants, which are not removed together with the
+// run namespace.
+//
+// Handlers are invoked whenever a pipeline run is cleaned up, i.e. when
+// it has been finished, aborted, timed out or deleted. Handlers can
+// inspect the pipeline run (e.g. `status.result`) to decide what to do.
+// The same pipeline run may be passed more than once, e.g. if the
+// cleanup is retried, therefore handlers must be idempotent.
+type Handler interface {
+	// Handle tears down the external resources created by the given
+	// pipeline run. It must return when ctx is done.
+	// The pipeline run must not be modified.
+	Handle(ctx context.Context, run *api.PipelineRun) error
+}
+
+// HandlerFunc is an adapter to use ordinary functions as Handler.
+type HandlerFunc func(ctx context.Context, run *api.PipelineRun) error
+
+// let compiler verify interface compliance
+var _ Handler = HandlerFunc(nil)
+
+// Handle calls f(ctx, run).
+func (f HandlerFunc) Handle(ctx context.Context, run *api.PipelineRun) error {
+	return f(ctx, run)
+}
+
+// Registration is a named handler with a timeout.
+type Registration struct {
+	// Name is the name of the handler reported in the status of
+	// pipeline runs.
+	Name string
+
+	// Handler is the handler to be invoked.
+	Handler Handler
+
+	// Timeout is the maximum duration of an invocation of the handler.
+	// If zero or negative, DefaultTimeout is used.
+	Timeout time.Duration
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      []Registration
+)
+
+// Register registers a compiled-in handler. It is meant to be called
+// from `init()` functions of packages providing handlers.
+// It panics if the registration is invalid or a handler with the same
+// name has been registered already.
+func Register(registration Registration) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if registration.Name == "" || registration.Handler == nil {
+		panic("aborthandler: handler name and handler must not be empty")
+	}
+	for _, existing := range registry {
+		if existing.Name == registration.Name {
+			panic(fmt.Sprintf("aborthandler: handler %q registered twice", registration.Name))
+		}
+	}
+	registry = append(registry, registration)
+}
+
+// Registered returns all compiled-in handlers in registration order.
+func Registered() []Registration {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	return append([]Registration(nil), registry...)
+}
+
+// Invoke invokes the given handlers for the given pipeline run one after
+// another and returns their outcomes in the same order.
+// Each handler is cancelled when its timeout expires. A failing handler
+// does not prevent subsequent handlers from being invoked. Panics of
+// handlers are recovered and reported as failure.
+func Invoke(ctx context.Context, registrations []Registration, run *api.PipelineRun) []api.AbortHandlerStatus {
+	result := make([]api.AbortHandlerStatus, 0, len(registrations))
+	for _, registration := range registrations {
+		result = append(result, invokeOne(ctx, registration, run))
+	}
+	return result
+}
+
+func invokeOne(ctx context.Context, registration Registration, run *api.PipelineRun) api.AbortHandlerStatus {
+	timeout := registration.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	// Buffered to not block the handler goroutine if we stop waiting
+	// for it because of a timeout.
+	done := make(chan error, 1)
+	go func() {
+		done <- recovery.Call(func() error {
+			return registration.Handler.Handle(ctx, run.DeepCopy())
+		})
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := api.AbortHandlerStatus{
+		Name:     registration.Name,
+		Result:   api.AbortHandlerResultSucceeded,
+		Duration: metav1.Duration{Duration: time.Since(start).Round(time.Millisecond)},
+	}
+	if err != nil {
+		status.Result = api.AbortHandlerResultFailed
+		if ctx.Err() == context.DeadlineExceeded {
+			status.Result = api.AbortHandlerResultTimeout
+			err = errors.Errorf("timed out after %v", timeout)
+		}
+		status.Message = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,134 @@
+package aborthandler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRun() *api.PipelineRun {
+	return &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: "ns1"},
+		Status:     api.PipelineStatus{Result: api.ResultAborted},
+	}
+}
+
+func Test_Invoke(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	var invoked []string
+	registrations := []Registration{
+		{
+			Name: "succeeding",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				invoked = append(invoked, run.GetName())
+				return nil
+			}),
+		},
+		{
+			Name: "failing",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				return errors.New("error1")
+			}),
+		},
+		{
+			Name: "panicking",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				panic("panic1")
+			}),
+		},
+		{
+			Name: "timingOut",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+			Timeout: 10 * time.Millisecond,
+		},
+		{
+			Name: "ignoringTimeout",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				time.Sleep(time.Second)
+				return nil
+			}),
+			Timeout: 10 * time.Millisecond,
+		},
+	}
+
+	// EXERCISE
+	result := Invoke(ctx, registrations, newRun())
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"run1"}, invoked)
+	assert.Assert(t, is.Len(result, 5))
+	for i, expected := range []struct {
+		result  api.AbortHandlerResult
+		message string
+	}{
+		{api.AbortHandlerResultSucceeded, ""},
+		{api.AbortHandlerResultFailed, "error1"},
+		{api.AbortHandlerResultFailed, "recovered from panic: panic1"},
+		{api.AbortHandlerResultTimeout, "timed out after 10ms"},
+		{api.AbortHandlerResultTimeout, "timed out after 10ms"},
+	} {
+		assert.Equal(t, registrations[i].Name, result[i].Name)
+		assert.Equal(t, expected.result, result[i].Result, "handler %q", result[i].Name)
+		assert.Equal(t, expected.message, result[i].Message, "handler %q", result[i].Name)
+	}
+}
+
+func Test_Invoke_PassesCopy(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newRun()
+	registrations := []Registration{
+		{
+			Name: "modifying",
+			Handler: HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error {
+				run.Status.Result = api.ResultSuccess
+				return nil
+			}),
+		},
+	}
+
+	// EXERCISE
+	Invoke(ctx, registrations, run)
+
+	// VERIFY
+	assert.Equal(t, api.ResultAborted, run.Status.Result)
+}
+
+func Test_Register(t *testing.T) {
+	// no t.Parallel() as the registry is global
+
+	// SETUP
+	defer func(saved []Registration) { registry = saved }(registry)
+	registry = nil
+	handler := HandlerFunc(func(ctx context.Context, run *api.PipelineRun) error { return nil })
+
+	// EXERCISE
+	Register(Registration{Name: "handler1", Handler: handler})
+	Register(Registration{Name: "handler2", Handler: handler})
+
+	// VERIFY
+	result := Registered()
+	assert.Assert(t, is.Len(result, 2))
+	assert.Equal(t, "handler1", result[0].Name)
+	assert.Equal(t, "handler2", result[1].Name)
+	assert.Assert(t, is.Panics(func() {
+		Register(Registration{Name: "handler1", Handler: handler})
+	}))
+	assert.Assert(t, is.Panics(func() {
+		Register(Registration{Name: "handler3"})
+	}))
+}
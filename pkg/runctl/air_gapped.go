@@ -0,0 +1,55 @@
+package runctl
+
+import (
+	"fmt"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/runctl/airgap"
+	"github.com/SAP/stewardci-core/pkg/runctl/imagepolicy"
+	"github.com/pkg/errors"
+)
+
+// checkAirGapped verifies that the pipeline run does not require internet
+// access if Steward runs in an air-gapped landscape, i.e. that the
+// Jenkinsfile Runner image is referenced by digest and all repositories,
+// log destinations and artifact destinations are internal hosts.
+func (c *runManager) checkAirGapped(runCtx *runContext) error {
+	config := runCtx.pipelineRunsConfig
+	if !config.AirGapped {
+		return nil
+	}
+
+	contentError := func(err error) error {
+		return serrors.Classify(
+			errors.WithMessage(err, "air-gapped installation"),
+			stewardv1alpha1.ResultErrorContent,
+		)
+	}
+
+	if image, _ := jenkinsfileRunnerImage(runCtx); image != "" && !imagepolicy.HasDigest(image) {
+		return contentError(fmt.Errorf("image %q is not referenced by digest", image))
+	}
+
+	policy := &airgap.Policy{InternalHosts: config.AirGappedInternalHosts}
+	spec := runCtx.pipelineRun.GetSpec()
+	if err := policy.CheckURL(spec.JenkinsFile.URL); err != nil {
+		return contentError(errors.WithMessage(err, "field spec.jenkinsFile.repoUrl"))
+	}
+	for i, source := range spec.Sources {
+		if err := policy.CheckURL(source.URL); err != nil {
+			return contentError(errors.WithMessagef(err, "field spec.sources[%d].repoUrl", i))
+		}
+	}
+	if spec.Logging != nil && spec.Logging.Elasticsearch != nil && spec.Logging.Elasticsearch.IndexURL != "" {
+		if err := policy.CheckURL(spec.Logging.Elasticsearch.IndexURL); err != nil {
+			return contentError(errors.WithMessage(err, "field spec.logging.elasticsearch.indexURL"))
+		}
+	}
+	if spec.Artifacts != nil {
+		if err := policy.CheckURL(spec.Artifacts.Destination.URL); err != nil {
+			return contentError(errors.WithMessage(err, "field spec.artifacts.destination.url"))
+		}
+	}
+	return nil
+}
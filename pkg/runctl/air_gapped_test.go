@@ -0,0 +1,182 @@
+package runctl
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/runctl/airgap"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+)
+
+const airGappedTestDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func Test__runManager_checkAirGapped(t *testing.T) {
+	t.Parallel()
+
+	internalRepo := "https://git.example.com/org/repo.git"
+	externalRepo := "https://github.com/org/repo.git"
+
+	for _, tc := range []struct {
+		name          string
+		airGapped     bool
+		configImage   string
+		spec          stewardv1alpha1.PipelineSpec
+		expectedError string
+	}{
+		{
+			name:        "disabled",
+			airGapped:   false,
+			configImage: "jfr:1",
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: externalRepo},
+			},
+		},
+		{
+			name:        "all_internal",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: internalRepo},
+				Sources:     []stewardv1alpha1.SourceRepository{{URL: "git@git.example.com:org/config.git"}},
+				Logging: &stewardv1alpha1.Logging{
+					Elasticsearch: &stewardv1alpha1.Elasticsearch{IndexURL: "http://es.logging.svc:9200/logs/_doc"},
+				},
+			},
+		},
+		{
+			name:        "image_without_digest",
+			airGapped:   true,
+			configImage: "jfr:1",
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: internalRepo},
+			},
+			expectedError: `air-gapped installation: image "mirror.example.com/jfr:1" is not referenced by digest`,
+		},
+		{
+			name:        "spec_image_without_digest",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{Image: "registry.example.com/jfr:2"},
+				JenkinsFile:       stewardv1alpha1.JenkinsFile{URL: internalRepo},
+			},
+			expectedError: `air-gapped installation: image "mirror.example.com/jfr:2" is not referenced by digest`,
+		},
+		{
+			name:        "external_pipeline_repo",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: externalRepo},
+			},
+			expectedError: "air-gapped installation: field spec.jenkinsFile.repoUrl",
+		},
+		{
+			name:        "external_source_repo",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: internalRepo},
+				Sources: []stewardv1alpha1.SourceRepository{
+					{URL: internalRepo},
+					{URL: "git@github.com:org/config.git"},
+				},
+			},
+			expectedError: "air-gapped installation: field spec.sources[1].repoUrl",
+		},
+		{
+			name:        "external_log_index",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: internalRepo},
+				Logging: &stewardv1alpha1.Logging{
+					Elasticsearch: &stewardv1alpha1.Elasticsearch{IndexURL: "https://es.example.org/logs/_doc"},
+				},
+			},
+			expectedError: "air-gapped installation: field spec.logging.elasticsearch.indexURL",
+		},
+		{
+			name:        "external_artifacts_destination",
+			airGapped:   true,
+			configImage: "jfr:1@" + airGappedTestDigest,
+			spec: stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{URL: internalRepo},
+				Artifacts: &stewardv1alpha1.ArtifactsSpec{
+					Paths:       []string{"target/*.jar"},
+					Destination: stewardv1alpha1.ArtifactsDestination{URL: "https://s3.amazonaws.com/bucket1", Secret: "s3"},
+				},
+			},
+			expectedError: "air-gapped installation: field spec.artifacts.destination.url",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := runManager{}
+			runCtx := contextWithSpec(t, "runNamespace1", tc.spec)
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerImage:  tc.configImage,
+				AirGapped:               tc.airGapped,
+				AirGappedMirrorRegistry: "mirror.example.com",
+				AirGappedInternalHosts:  []string{"git.example.com"},
+			}
+
+			// EXERCISE
+			err := examinee.checkAirGapped(runCtx)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+				assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+			}
+		})
+	}
+}
+
+func Test__runManager_checkAirGapped_ExternalURLError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := runManager{}
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		JenkinsFile: stewardv1alpha1.JenkinsFile{URL: "https://github.com/org/repo.git"},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		AirGapped:               true,
+		AirGappedMirrorRegistry: "mirror.example.com",
+	}
+
+	// EXERCISE
+	err := examinee.checkAirGapped(runCtx)
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, airgap.ErrExternalURL))
+}
+
+func Test__jenkinsfileRunnerImage_AirGapped(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerImage:           "docker.io/stewardci/jfr:1@" + airGappedTestDigest,
+		JenkinsfileRunnerImagePullPolicy: "Always",
+		AirGapped:                        true,
+		AirGappedMirrorRegistry:          "mirror.example.com/steward/",
+	}
+
+	// EXERCISE
+	image, imagePullPolicy := jenkinsfileRunnerImage(runCtx)
+
+	// VERIFY
+	assert.Equal(t, "mirror.example.com/steward/stewardci/jfr:1@"+airGappedTestDigest, image)
+	assert.Equal(t, "Always", imagePullPolicy)
+}
@@ -0,0 +1,113 @@
+package airgap
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrExternalURL is returned (wrapped) by Policy.CheckURL if a URL does
+// not point to a host reachable from within an air-gapped landscape.
+var ErrExternalURL = errors.New("external URL not allowed in air-gapped installation")
+
+// Policy defines which hosts are reachable in an air-gapped landscape.
+// Cluster-local hosts, i.e. host names without a dot and host names of
+// Kubernetes services (`*.svc` and `*.svc.cluster.local`), are always
+// considered internal.
+type Policy struct {
+	// InternalHosts is a list of host names reachable from within the
+	// air-gapped landscape. An entry starting with a dot, e.g.
+	// `.corp.example.com`, matches all subdomains of the domain.
+	InternalHosts []string
+}
+
+// IsInternalHost returns whether the given host name (optionally with
+// port) is reachable from within the air-gapped landscape.
+func (p *Policy) IsInternalHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	host = trimBrackets(host)
+	if host == "" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		if !strings.Contains(host, ".") ||
+			strings.HasSuffix(host, ".svc") ||
+			strings.HasSuffix(host, ".svc.cluster.local") {
+			return true
+		}
+	}
+	if p == nil {
+		return false
+	}
+	for _, internal := range p.InternalHosts {
+		internal = trimBrackets(strings.ToLower(internal))
+		if ip != nil {
+			// compare IP addresses semantically, as IPv6 addresses
+			// have multiple textual representations
+			if internalIP := net.ParseIP(internal); internalIP != nil && internalIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(internal, ".") {
+			if strings.HasSuffix(host, internal) {
+				return true
+			}
+		} else if host == internal {
+			return true
+		}
+	}
+	return false
+}
+
+// trimBrackets removes the square brackets enclosing IPv6 addresses in
+// URLs, e.g. `[fd00::1]`.
+func trimBrackets(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		return host[1 : len(host)-1]
+	}
+	return host
+}
+
+// CheckURL returns an error wrapping ErrExternalURL if the given URL does
+// not point to an internal host. SCP-like Git URLs
+// (`user@host:path`) are supported, too.
+func (p *Policy) CheckURL(rawURL string) error {
+	if p.IsInternalHost(urlHost(rawURL)) {
+		return nil
+	}
+	return errors.Wrapf(ErrExternalURL, "URL %q does not point to an internal host", rawURL)
+}
+
+// urlHost returns the host (including port if any) of the given URL or
+// an empty string if the URL cannot be parsed.
+func urlHost(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		// SCP-like syntax as supported by Git, e.g. `git@host:path` or
+		// `git@[fd00::1]:path`
+		i := strings.IndexRune(rawURL, ':')
+		if i > 0 && strings.Contains(rawURL[:i], "[") {
+			// IPv6 address containing colons itself
+			i = strings.Index(rawURL, "]:") + 1
+		}
+		if i > 0 {
+			host := rawURL[:i]
+			if j := strings.LastIndex(host, "@"); j >= 0 {
+				host = host[j+1:]
+			}
+			return host
+		}
+		return ""
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
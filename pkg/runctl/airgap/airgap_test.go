@@ -0,0 +1,96 @@
+package airgap
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+)
+
+func Test_Policy_IsInternalHost(t *testing.T) {
+	t.Parallel()
+
+	policy := &Policy{
+		InternalHosts: []string{"git.example.com", ".corp.example.com", "10.0.0.1", "fd00::1", "[fd00::2]"},
+	}
+
+	for _, tc := range []struct {
+		host     string
+		expected bool
+	}{
+		{"git.example.com", true},
+		{"GIT.example.com:443", true},
+		{"git.example.com.", true},
+		{"other.example.com", false},
+		{"corp.example.com", false},
+		{"github.corp.example.com", true},
+		{"a.b.corp.example.com:8443", true},
+		{"evilcorp.example.com", false},
+		{"elasticsearch", true},
+		{"elasticsearch:9200", true},
+		{"es.logging.svc", true},
+		{"es.logging.svc.cluster.local:9200", true},
+		{"10.0.0.1", true},
+		{"10.0.0.2", false},
+		{"fd00::1", true},
+		{"[fd00::1]", true},
+		{"[FD00:0:0::1]:443", true},
+		{"fd00::2", true},
+		{"[fd00::3]", false},
+		{"[2001:db8::1]:443", false},
+		{"github.com", false},
+		{"", false},
+	} {
+		assert.Equal(t, tc.expected, policy.IsInternalHost(tc.host), "host: %q", tc.host)
+	}
+}
+
+func Test_Policy_IsInternalHost_Nil(t *testing.T) {
+	t.Parallel()
+
+	var policy *Policy
+	assert.Assert(t, policy.IsInternalHost("elasticsearch"))
+	assert.Assert(t, !policy.IsInternalHost("github.com"))
+}
+
+func Test_Policy_CheckURL(t *testing.T) {
+	t.Parallel()
+
+	policy := &Policy{
+		InternalHosts: []string{"git.example.com", "fd00::1"},
+	}
+
+	for _, tc := range []struct {
+		url      string
+		expected bool
+	}{
+		{"https://git.example.com/org/repo.git", true},
+		{"https://user:pw@git.example.com:8443/org/repo", true},
+		{"git@git.example.com:org/repo.git", true},
+		{"git.example.com:org/repo.git", true},
+		{"http://elasticsearch.logging.svc:9200/jenkins-logs/_doc", true},
+		{"https://[fd00::1]:8443/org/repo.git", true},
+		{"git@[fd00::1]:org/repo.git", true},
+		{"https://[2001:db8::1]/org/repo.git", false},
+		{"git@[2001:db8::1]:org/repo.git", false},
+		{"https://github.com/org/repo.git", false},
+		{"git@github.com:org/repo.git", false},
+		{"not a url", false},
+		{"", false},
+	} {
+		tc := tc
+		t.Run(tc.url, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			err := policy.CheckURL(tc.url)
+
+			// VERIFY
+			if tc.expected {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, errors.Is(err, ErrExternalURL))
+			}
+		})
+	}
+}
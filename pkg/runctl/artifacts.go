@@ -0,0 +1,153 @@
+package runctl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// artifactsSecretKeyAccessKeyID is the key of the entry of an
+	// artifacts secret containing the access key ID for the object
+	// storage.
+	artifactsSecretKeyAccessKeyID = "accessKeyId"
+
+	// artifactsSecretKeySecretAccessKey is the key of the entry of an
+	// artifacts secret containing the secret access key for the object
+	// storage.
+	artifactsSecretKeySecretAccessKey = "secretAccessKey"
+
+	// artifactsDefaultRegion is the region used to sign requests to the
+	// object storage if the pipeline run does not define one.
+	artifactsDefaultRegion = "us-east-1"
+)
+
+// addTektonTaskRunParamsForArtifacts configures the upload of the
+// artifacts defined in the spec of the pipeline run by the Jenkinsfile
+// Runner step.
+// The credentials are copied from the secret referenced by the spec to
+// secret naming.ArtifactsSecret in the run namespace, where the
+// Jenkinsfile Runner ClusterTask picks them up. Artifacts are uploaded to
+// `<destination URL>/<namespace>/<name>-<uid>/<path>`.
+func (c *runManager) addTektonTaskRunParamsForArtifacts(
+	ctx context.Context,
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	artifacts := runCtx.pipelineRun.GetSpec().Artifacts
+	if artifacts == nil {
+		return nil
+	}
+	if err := validateArtifactsSpec(artifacts); err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+
+	destination := artifacts.Destination
+	secret, err := c.factory.CoreV1().Secrets(runCtx.pipelineRun.GetNamespace()).Get(ctx, destination.Secret, metav1.GetOptions{})
+	if err != nil {
+		notFound := k8serrors.IsNotFound(err)
+		err = errors.Wrapf(err,
+			"failed to get secret %q referenced by spec.artifacts.destination.secret", destination.Secret,
+		)
+		if notFound {
+			return serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+		}
+		return err
+	}
+	data := map[string][]byte{}
+	for _, key := range []string{artifactsSecretKeyAccessKeyID, artifactsSecretKeySecretAccessKey} {
+		if len(secret.Data[key]) == 0 {
+			return serrors.Classify(
+				errors.Errorf("secret %q referenced by spec.artifacts.destination.secret has no entry %q", destination.Secret, key),
+				stewardv1alpha1.ResultErrorContent,
+			)
+		}
+		data[key] = secret.Data[key]
+	}
+	if err := c.createArtifactsSecret(ctx, runCtx, data); err != nil {
+		return err
+	}
+
+	region := destination.Region
+	if region == "" {
+		region = artifactsDefaultRegion
+	}
+	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params,
+		tektonStringParam("ARTIFACTS_PATHS", strings.Join(artifacts.Paths, "\n")),
+		tektonStringParam("ARTIFACTS_URL", artifactsBaseURL(runCtx, destination.URL)),
+		tektonStringParam("ARTIFACTS_REGION", region),
+	)
+	return nil
+}
+
+// validateArtifactsSpec verifies the artifacts spec of a pipeline run.
+func validateArtifactsSpec(artifacts *stewardv1alpha1.ArtifactsSpec) error {
+	if len(artifacts.Paths) == 0 {
+		return errors.New("field \"spec.artifacts.paths\" must not be empty")
+	}
+	for i, pattern := range artifacts.Paths {
+		if strings.ContainsAny(pattern, "\n\r") {
+			return errors.Errorf("field \"spec.artifacts.paths[%d]\" must not contain line breaks", i)
+		}
+		if err := ensureValidTestResultsPath(pattern); err != nil {
+			return errors.Wrapf(err,
+				"field \"spec.artifacts.paths[%d]\" has invalid value %q",
+				i, pattern,
+			)
+		}
+	}
+	destinationURL, err := url.Parse(artifacts.Destination.URL)
+	if err != nil || (destinationURL.Scheme != "http" && destinationURL.Scheme != "https") || destinationURL.Host == "" {
+		return errors.Errorf(
+			"field \"spec.artifacts.destination.url\" has invalid value %q: must be an HTTP(S) URL",
+			artifacts.Destination.URL,
+		)
+	}
+	if artifacts.Destination.Secret == "" {
+		return errors.New("field \"spec.artifacts.destination.secret\" must not be empty")
+	}
+	return nil
+}
+
+// artifactsBaseURL returns the URL the artifacts of the given pipeline
+// run are uploaded to below the given destination URL.
+func artifactsBaseURL(runCtx *runContext, destinationURL string) string {
+	run := runCtx.pipelineRun.GetAPIObject()
+	return strings.TrimSuffix(destinationURL, "/") + "/" +
+		url.PathEscape(run.GetNamespace()) + "/" +
+		url.PathEscape(run.GetName()+"-"+string(run.GetUID()))
+}
+
+// createArtifactsSecret creates the secret with the credentials for the
+// object storage in the run namespace.
+func (c *runManager) createArtifactsSecret(ctx context.Context, runCtx *runContext, data map[string][]byte) error {
+	secret := &corev1api.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ArtifactsSecret,
+			Namespace: runCtx.runNamespace,
+		},
+		Type: corev1api.SecretTypeOpaque,
+		Data: data,
+	}
+	slabels.LabelAsSystemManaged(secret)
+	slabels.LabelWithInstanceID(secret, c.factory.InstanceID())
+
+	secretIfce := c.factory.CoreV1().Secrets(runCtx.runNamespace)
+	if _, err := secretIfce.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err,
+			"failed to create artifacts secret %q in namespace %q",
+			naming.ArtifactsSecret, runCtx.runNamespace,
+		)
+	}
+	return nil
+}
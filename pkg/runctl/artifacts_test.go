@@ -0,0 +1,178 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test__runManager_addTektonTaskRunParamsForArtifacts(t *testing.T) {
+	t.Parallel()
+
+	secret := func(data map[string]string) runtime.Object {
+		secret := k8sfake.SecretOpaque("storage1", "ns1")
+		secret.Data = map[string][]byte{}
+		for key, value := range data {
+			secret.Data[key] = []byte(value)
+		}
+		return secret
+	}
+	credentials := map[string]string{"accessKeyId": "id1", "secretAccessKey": "key1"}
+	artifactsSpec := func(modify func(*stewardv1alpha1.ArtifactsSpec)) *stewardv1alpha1.ArtifactsSpec {
+		spec := &stewardv1alpha1.ArtifactsSpec{
+			Paths: []string{"target/*.jar", "reports/**"},
+			Destination: stewardv1alpha1.ArtifactsDestination{
+				URL:    "https://s3.example.com/bucket1/",
+				Secret: "storage1",
+			},
+		}
+		if modify != nil {
+			modify(spec)
+		}
+		return spec
+	}
+
+	for _, tc := range []struct {
+		name                string
+		artifacts           *stewardv1alpha1.ArtifactsSpec
+		objects             []runtime.Object
+		expectedAddedParams []tektonv1beta1.Param
+		expectedSecret      map[string]string
+		expectedError       string
+		expectedResult      stewardv1alpha1.Result
+	}{
+		{
+			name:    "not_set",
+			objects: []runtime.Object{secret(credentials)},
+		},
+		{
+			name:      "default_region",
+			artifacts: artifactsSpec(nil),
+			objects:   []runtime.Object{secret(credentials)},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("ARTIFACTS_PATHS", "target/*.jar\nreports/**"),
+				tektonStringParam("ARTIFACTS_URL", "https://s3.example.com/bucket1/ns1/run1-uid1"),
+				tektonStringParam("ARTIFACTS_REGION", "us-east-1"),
+			},
+			expectedSecret: credentials,
+		},
+		{
+			name: "region",
+			artifacts: artifactsSpec(func(spec *stewardv1alpha1.ArtifactsSpec) {
+				spec.Destination.Region = "eu-central-1"
+			}),
+			objects: []runtime.Object{secret(credentials)},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("ARTIFACTS_PATHS", "target/*.jar\nreports/**"),
+				tektonStringParam("ARTIFACTS_URL", "https://s3.example.com/bucket1/ns1/run1-uid1"),
+				tektonStringParam("ARTIFACTS_REGION", "eu-central-1"),
+			},
+			expectedSecret: credentials,
+		},
+		{
+			name:           "secret_not_found",
+			artifacts:      artifactsSpec(nil),
+			expectedError:  `failed to get secret "storage1" referenced by spec.artifacts.destination.secret: secrets "storage1" not found`,
+			expectedResult: stewardv1alpha1.ResultErrorContent,
+		},
+		{
+			name:           "secret_without_secret_access_key",
+			artifacts:      artifactsSpec(nil),
+			objects:        []runtime.Object{secret(map[string]string{"accessKeyId": "id1"})},
+			expectedError:  `secret "storage1" referenced by spec.artifacts.destination.secret has no entry "secretAccessKey"`,
+			expectedResult: stewardv1alpha1.ResultErrorContent,
+		},
+		{
+			name: "no_paths",
+			artifacts: artifactsSpec(func(spec *stewardv1alpha1.ArtifactsSpec) {
+				spec.Paths = nil
+			}),
+			objects:        []runtime.Object{secret(credentials)},
+			expectedError:  `field "spec.artifacts.paths" must not be empty`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name: "path_with_line_break",
+			artifacts: artifactsSpec(func(spec *stewardv1alpha1.ArtifactsSpec) {
+				spec.Paths = []string{"a\nb"}
+			}),
+			objects:        []runtime.Object{secret(credentials)},
+			expectedError:  `field "spec.artifacts.paths[0]" must not contain line breaks`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name: "invalid_url",
+			artifacts: artifactsSpec(func(spec *stewardv1alpha1.ArtifactsSpec) {
+				spec.Destination.URL = "s3://bucket1"
+			}),
+			objects:        []runtime.Object{secret(credentials)},
+			expectedError:  `field "spec.artifacts.destination.url" has invalid value "s3://bucket1": must be an HTTP(S) URL`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name: "no_secret",
+			artifacts: artifactsSpec(func(spec *stewardv1alpha1.ArtifactsSpec) {
+				spec.Destination.Secret = ""
+			}),
+			expectedError:  `field "spec.artifacts.destination.secret" must not be empty`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := newFakeClientFactory(tc.objects...)
+			examinee := newRunManager(cf, nil)
+			run := k8sfake.PipelineRun("run1", "ns1", stewardv1alpha1.PipelineSpec{Artifacts: tc.artifacts})
+			run.SetUID("uid1")
+			pipelineRun, err := k8s.NewPipelineRun(ctx, run, nil)
+			assert.NilError(t, err)
+			runCtx := &runContext{runNamespace: "runNamespace1", pipelineRun: pipelineRun}
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					Params: []tektonv1beta1.Param{},
+				},
+			}
+
+			// EXERCISE
+			err = examinee.addTektonTaskRunParamsForArtifacts(ctx, runCtx, &tektonTaskRun)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Equal(t, tc.expectedResult, serrors.GetClass(err))
+				assert.Assert(t, len(tektonTaskRun.Spec.Params) == 0)
+				return
+			}
+			assert.NilError(t, err)
+			if tc.expectedAddedParams == nil {
+				tc.expectedAddedParams = []tektonv1beta1.Param{}
+			}
+			assert.DeepEqual(t, tc.expectedAddedParams, tektonTaskRun.Spec.Params)
+
+			runSecret, err := cf.CoreV1().Secrets("runNamespace1").Get(ctx, naming.ArtifactsSecret, metav1.GetOptions{})
+			if tc.expectedSecret == nil {
+				assert.Assert(t, k8serrors.IsNotFound(err))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, len(tc.expectedSecret), len(runSecret.Data))
+			for key, value := range tc.expectedSecret {
+				assert.Equal(t, value, string(runSecret.Data[key]), "key %q", key)
+			}
+		})
+	}
+}
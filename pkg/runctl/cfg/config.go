@@ -1,34 +1,67 @@
 package cfg
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/featureflag"
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/airgap"
+	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/system"
 )
 
 const (
-	mainConfigMapName            = "steward-pipelineruns"
-	mainConfigKeyTimeout         = "timeout"
-	mainConfigKeyLimitRange      = "limitRange"
-	mainConfigKeyResourceQuota   = "resourceQuota"
-	mainConfigKeyImage           = "jenkinsfileRunner.image"
-	mainConfigKeyImagePullPolicy = "jenkinsfileRunner.imagePullPolicy"
-	mainConfigKeyPSCRunAsUser    = "jenkinsfileRunner.podSecurityContext.runAsUser"
-	mainConfigKeyPSCRunAsGroup   = "jenkinsfileRunner.podSecurityContext.runAsGroup"
-	mainConfigKeyPSCFSGroup      = "jenkinsfileRunner.podSecurityContext.fsGroup"
+	mainConfigMapName                      = "steward-pipelineruns"
+	mainConfigKeyTimeout                   = "timeout"
+	mainConfigKeyLimitRange                = "limitRange"
+	mainConfigKeyResourceQuota             = "resourceQuota"
+	mainConfigKeyImage                     = "jenkinsfileRunner.image"
+	mainConfigKeyImagePullPolicy           = "jenkinsfileRunner.imagePullPolicy"
+	mainConfigKeyPSCRunAsUser              = "jenkinsfileRunner.podSecurityContext.runAsUser"
+	mainConfigKeyPSCRunAsGroup             = "jenkinsfileRunner.podSecurityContext.runAsGroup"
+	mainConfigKeyPSCFSGroup                = "jenkinsfileRunner.podSecurityContext.fsGroup"
+	mainConfigKeyPSCSeccompProfile         = "jenkinsfileRunner.podSecurityContext.seccompProfile"
+	mainConfigKeyRuntimeClassName          = "jenkinsfileRunner.runtimeClassName"
+	mainConfigKeyDeprecatedImageRegistries = "deprecatedImageRegistries"
+	mainConfigKeyArgsOffloadThreshold      = "pipelineArgs.offloadThresholdBytes"
+	mainConfigKeyResolveRevision           = "resolveRevision"
+	mainConfigKeyResolveRevisionHosts      = "resolveRevision.allowedHosts"
+	mainConfigKeyAllowedImageRegistries    = "allowedImageRegistries"
+	mainConfigKeyAllowedImageDigests       = "allowedImageDigests"
+	mainConfigKeyImageSignaturePublicKey   = "imageSignaturePublicKey"
+	mainConfigKeyUserNamespacesEnabled     = "userNamespaces.enabled"
+	mainConfigKeyExtendedResourceLimits    = "jenkinsfileRunner.extendedResourceLimits"
+	mainConfigKeyMinResources              = "jenkinsfileRunner.minResources"
+	mainConfigKeyMaxResources              = "jenkinsfileRunner.maxResources"
+	mainConfigKeyTopologySpread            = "jenkinsfileRunner.topologySpreadConstraints"
+	mainConfigKeyTerminationGracePeriod    = "jenkinsfileRunner.terminationGracePeriodSeconds"
+	mainConfigKeyLoggingIndexURL           = "logging.elasticsearch.indexURL"
+	mainConfigKeyLoggingClientCertSecret   = "logging.elasticsearch.clientCertSecret"
+	mainConfigKeyLoggingCABundleSecret     = "logging.elasticsearch.caBundleSecret"
+	mainConfigKeyAirGappedEnabled          = "airGapped.enabled"
+	mainConfigKeyAirGappedMirrorRegistry   = "airGapped.mirrorRegistry"
+	mainConfigKeyAirGappedInternalHosts    = "airGapped.internalHosts"
 
 	networkPoliciesConfigMapName    = "steward-pipelineruns-network-policies"
 	networkPoliciesConfigKeyDefault = "_default"
+
+	securityProfilesConfigMapName    = "steward-pipelineruns-security-profiles"
+	securityProfilesConfigKeyDefault = "_default"
+
+	schedulingProfilesConfigMapName = "steward-pipelineruns-scheduling-profiles"
 )
 
 // PipelineRunsConfigStruct is a struct holding the pipeline runs configuration.
@@ -71,6 +104,127 @@ type PipelineRunsConfigStruct struct {
 	// group id the Jenkinsfile Runner pod will use.
 	JenkinsfileRunnerPodSecurityContextFSGroup *int64
 
+	// JenkinsfileRunnerPodSecurityContextSeccompProfile is the seccomp
+	// profile of Jenkinsfile Runner pods whose pod security context does
+	// not define one, including pods with a security profile.
+	// If `nil`, the container runtime default applies.
+	JenkinsfileRunnerPodSecurityContextSeccompProfile *corev1.SeccompProfile
+
+	// JenkinsfileRunnerRuntimeClassName is the name of the runtime class
+	// (e.g. for gVisor or Kata Containers) of Jenkinsfile Runner pods
+	// whose security profile does not define one.
+	// If empty, the default container runtime of the node is used.
+	JenkinsfileRunnerRuntimeClassName string
+
+	// DeprecatedImageRegistries is a list of container image registry
+	// host names (optionally with port) that are deprecated. Pipeline runs
+	// using images from these registries get a warning in their status.
+	DeprecatedImageRegistries []string
+
+	// PipelineArgsOffloadThresholdBytes is the size in bytes (name plus
+	// value) above which a pipeline argument is not passed inline to the
+	// Jenkinsfile Runner but via a config map in the run namespace.
+	// If `nil` or not positive, no arguments are offloaded.
+	PipelineArgsOffloadThresholdBytes *int64
+
+	// ResolveRevision defines whether the revision of the pipeline
+	// repository is resolved to a commit SHA before a pipeline run is
+	// started. The commit SHA is recorded in the status of the pipeline
+	// run and passed to the Jenkinsfile Runner instead of the revision.
+	ResolveRevision bool
+
+	// ResolveRevisionAllowedHosts is a list of Git server host names the
+	// run controller may connect to for resolving revisions. An entry
+	// starting with a dot matches all subdomains. Revisions of
+	// repositories on other hosts are not resolved by the run controller
+	// but checked out as given by the Jenkinsfile Runner.
+	ResolveRevisionAllowedHosts []string
+
+	// AllowedImageRegistries is a list of container image registry host
+	// names (optionally with port and repository path prefix) images of
+	// pipeline runs may be pulled from.
+	// If both AllowedImageRegistries and AllowedImageDigests are empty,
+	// all images are allowed.
+	AllowedImageRegistries []string
+
+	// AllowedImageDigests is a list of container image digests. Images
+	// referenced by one of these digests are allowed regardless of the
+	// registry.
+	AllowedImageDigests []string
+
+	// ImageSignaturePublicKey is a PEM-encoded public key images of
+	// pipeline runs must have a valid cosign signature for.
+	// If empty, image signatures are not verified.
+	ImageSignaturePublicKey string
+
+	// UserNamespacesEnabled defines whether pipeline runs may select the
+	// execution profile `userNamespace`, which requires a Kubernetes
+	// cluster supporting user namespaces.
+	UserNamespacesEnabled bool
+
+	// JenkinsfileRunnerExtendedResourceLimits maps names of extended
+	// resources (e.g. `nvidia.com/gpu`) to the maximum amount a single
+	// pipeline run may request for the Jenkinsfile Runner container.
+	// Extended resources not contained may not be requested at all.
+	JenkinsfileRunnerExtendedResourceLimits corev1.ResourceList
+
+	// JenkinsfileRunnerMinResources maps names of compute resources
+	// (`cpu`, `memory` and `ephemeral-storage`) to the minimum amount
+	// pipeline runs may request or limit for the Jenkinsfile Runner
+	// container via `spec.resources`.
+	JenkinsfileRunnerMinResources corev1.ResourceList
+
+	// JenkinsfileRunnerMaxResources maps names of compute resources
+	// (`cpu`, `memory` and `ephemeral-storage`) to the maximum amount
+	// pipeline runs may request or limit for the Jenkinsfile Runner
+	// container via `spec.resources`.
+	// Compute resources not contained may not be set at all.
+	JenkinsfileRunnerMaxResources corev1.ResourceList
+
+	// JenkinsfileRunnerTopologySpreadConstraints are the topology spread
+	// constraints of Jenkinsfile Runner pods of pipeline runs not
+	// selecting a scheduling profile.
+	JenkinsfileRunnerTopologySpreadConstraints []corev1.TopologySpreadConstraint
+
+	// JenkinsfileRunnerTerminationGracePeriodSeconds is the time in
+	// seconds the Jenkinsfile Runner pod gets to terminate gracefully
+	// (e.g. when a pipeline run is aborted) before it gets killed.
+	// If `nil`, the Kubernetes default is used.
+	JenkinsfileRunnerTerminationGracePeriodSeconds *int64
+
+	// LoggingIndexURL is the URL of the Elasticsearch index pipeline logs
+	// are sent to by default.
+	// If empty, only clients configuring their own index receive logs.
+	LoggingIndexURL string
+
+	// LoggingClientCertSecret is the name of a secret of type
+	// `kubernetes.io/tls` in the system namespace containing the client
+	// certificate and key for mutual TLS authentication to Elasticsearch.
+	// If empty, no client certificate is used.
+	LoggingClientCertSecret string
+
+	// LoggingCABundleSecret is the name of a secret in the system
+	// namespace containing the bundle of CA certificates trusted when
+	// connecting to Elasticsearch.
+	// If empty, the default trusted certificates are used.
+	LoggingCABundleSecret string
+
+	// AirGapped defines whether Steward runs in an air-gapped landscape
+	// without internet access. If enabled, container images must be
+	// referenced by digest and are pulled from `AirGappedMirrorRegistry`,
+	// and pipeline runs must not refer to external hosts.
+	AirGapped bool
+
+	// AirGappedMirrorRegistry is the registry (optionally with a
+	// repository path prefix) all container images are pulled from in an
+	// air-gapped landscape.
+	AirGappedMirrorRegistry string
+
+	// AirGappedInternalHosts is a list of host names reachable from within
+	// the air-gapped landscape. An entry starting with a dot matches all
+	// subdomains. Cluster-local hosts are always reachable.
+	AirGappedInternalHosts []string
+
 	// DefaultNetworkProfile is the name of the network profile that should
 	// be used in case the user has not explicitly chosen one.
 	DefaultNetworkProfile string
@@ -78,6 +232,65 @@ type PipelineRunsConfigStruct struct {
 	// NetworkPolicies maps network profile names to network policies.
 	// Each value is a Kubernetes network policy manifest in YAML format.
 	NetworkPolicies map[string]string
+
+	// DefaultSecurityProfile is the name of the security profile that
+	// should be used in case the user has not explicitly chosen one.
+	// If empty, the pod security context is defined by the
+	// `JenkinsfileRunnerPodSecurityContext*` fields.
+	DefaultSecurityProfile string
+
+	// SecurityProfiles maps security profile names to security profiles.
+	SecurityProfiles map[string]*SecurityProfile
+
+	// SchedulingProfiles maps scheduling profile names to scheduling
+	// profiles.
+	SchedulingProfiles map[string]*SchedulingProfile
+}
+
+// SchedulingProfile defines how Jenkinsfile Runner pods are scheduled.
+type SchedulingProfile struct {
+	// TopologySpreadConstraints are the topology spread constraints of
+	// the Jenkinsfile Runner pod.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// SecurityProfile defines the security settings of Jenkinsfile Runner
+// pods.
+type SecurityProfile struct {
+	// PodSecurityContext is the security context of the Jenkinsfile Runner
+	// pod.
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ContainerSecurityContext is the security context of the step
+	// containers of the Jenkinsfile Runner pod, e.g. to drop capabilities.
+	// If set, it replaces the security context defined for the steps in
+	// the Jenkinsfile Runner ClusterTask.
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// RunAsUserRange is the range of user IDs to run the Jenkinsfile
+	// Runner pod with. If set, each pipeline run gets a user ID from this
+	// range derived from its run namespace, so that concurrent pipeline
+	// runs are likely to run with different user IDs.
+	RunAsUserRange *IDRange `json:"runAsUserRange,omitempty"`
+
+	// RunAsGroupRange is the range of group IDs to run the Jenkinsfile
+	// Runner pod with. It is applied like RunAsUserRange.
+	RunAsGroupRange *IDRange `json:"runAsGroupRange,omitempty"`
+
+	// RuntimeClassName is the name of the runtime class of the Jenkinsfile
+	// Runner pod, e.g. a sandboxed runtime like gVisor for untrusted
+	// pipelines. If empty, the runtime class configured for the Steward
+	// installation is used.
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+}
+
+// IDRange is a range of user or group IDs.
+type IDRange struct {
+	// Min is the smallest ID of the range.
+	Min int64 `json:"min"`
+
+	// Max is the largest ID of the range.
+	Max int64 `json:"max"`
 }
 
 // LoadPipelineRunsConfig loads the pipelineruns configuration and returns it.
@@ -99,6 +312,16 @@ func LoadPipelineRunsConfig(ctx context.Context, clientFactory k8s.ClientFactory
 			optional:      false,
 			processFunc:   processNetworkPoliciesConfig,
 		},
+		{
+			configMapName: securityProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processSecurityProfilesConfig,
+		},
+		{
+			configMapName: schedulingProfilesConfigMapName,
+			optional:      true,
+			processFunc:   processSchedulingProfilesConfig,
+		},
 	} {
 		err := processConfigMap(
 			ctx,
@@ -183,6 +406,17 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return nil, nil
 	}
 
+	parseBool := func(key string) (bool, error) {
+		if strVal, ok := configData[key]; ok && strVal != "" {
+			boolVal, err := strconv.ParseBool(strVal)
+			if err != nil {
+				return false, wrapParseError(err, key, strVal)
+			}
+			return boolVal, nil
+		}
+		return false, nil
+	}
+
 	parseDuration := func(key string) (*metav1.Duration, error) {
 		if strVal, ok := configData[key]; ok && strVal != "" {
 			d, err := time.ParseDuration(strVal)
@@ -198,6 +432,17 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 	dest.ResourceQuota = configData[mainConfigKeyResourceQuota]
 	dest.JenkinsfileRunnerImage = configData[mainConfigKeyImage]
 	dest.JenkinsfileRunnerImagePullPolicy = configData[mainConfigKeyImagePullPolicy]
+	dest.JenkinsfileRunnerRuntimeClassName = strings.TrimSpace(configData[mainConfigKeyRuntimeClassName])
+	dest.DeprecatedImageRegistries = parseList(configData[mainConfigKeyDeprecatedImageRegistries])
+	dest.ResolveRevisionAllowedHosts = parseList(configData[mainConfigKeyResolveRevisionHosts])
+	dest.AllowedImageRegistries = parseList(configData[mainConfigKeyAllowedImageRegistries])
+	dest.AllowedImageDigests = parseList(configData[mainConfigKeyAllowedImageDigests])
+	dest.ImageSignaturePublicKey = strings.TrimSpace(configData[mainConfigKeyImageSignaturePublicKey])
+	dest.LoggingIndexURL = strings.TrimSpace(configData[mainConfigKeyLoggingIndexURL])
+	dest.LoggingClientCertSecret = strings.TrimSpace(configData[mainConfigKeyLoggingClientCertSecret])
+	dest.LoggingCABundleSecret = strings.TrimSpace(configData[mainConfigKeyLoggingCABundleSecret])
+	dest.AirGappedMirrorRegistry = strings.TrimSpace(configData[mainConfigKeyAirGappedMirrorRegistry])
+	dest.AirGappedInternalHosts = parseList(configData[mainConfigKeyAirGappedInternalHosts])
 
 	var err error
 
@@ -220,9 +465,124 @@ func processMainConfig(configData map[string]string, dest *PipelineRunsConfigStr
 		return err
 	}
 
+	if strVal := configData[mainConfigKeyPSCSeccompProfile]; strings.TrimSpace(strVal) != "" {
+		if dest.JenkinsfileRunnerPodSecurityContextSeccompProfile, err =
+			parseSeccompProfile(strVal); err != nil {
+			return wrapParseError(err, mainConfigKeyPSCSeccompProfile, strVal)
+		}
+	}
+
+	if name := dest.JenkinsfileRunnerRuntimeClassName; name != "" {
+		if err = validateRuntimeClassName(name); err != nil {
+			return wrapParseError(err, mainConfigKeyRuntimeClassName, name)
+		}
+	}
+
+	if dest.PipelineArgsOffloadThresholdBytes, err =
+		parseInt64(mainConfigKeyArgsOffloadThreshold); err != nil {
+		return err
+	}
+
+	if dest.JenkinsfileRunnerTerminationGracePeriodSeconds, err =
+		parseInt64(mainConfigKeyTerminationGracePeriod); err != nil {
+		return err
+	}
+	if value := dest.JenkinsfileRunnerTerminationGracePeriodSeconds; value != nil && *value < 0 {
+		return fmt.Errorf("key %q: value must not be negative", mainConfigKeyTerminationGracePeriod)
+	}
+
+	if dest.ResolveRevision, err =
+		parseBool(mainConfigKeyResolveRevision); err != nil {
+		return err
+	}
+
+	if dest.UserNamespacesEnabled, err =
+		parseBool(mainConfigKeyUserNamespacesEnabled); err != nil {
+		return err
+	}
+
+	if dest.AirGapped, err =
+		parseBool(mainConfigKeyAirGappedEnabled); err != nil {
+		return err
+	}
+	if dest.AirGapped {
+		if err = validateAirGapped(dest); err != nil {
+			return err
+		}
+	}
+
+	if strVal := configData[mainConfigKeyExtendedResourceLimits]; strings.TrimSpace(strVal) != "" {
+		if dest.JenkinsfileRunnerExtendedResourceLimits, err =
+			parseExtendedResourceLimits(strVal); err != nil {
+			return wrapParseError(err, mainConfigKeyExtendedResourceLimits, strVal)
+		}
+	}
+
+	for _, r := range []struct {
+		key  string
+		dest *corev1.ResourceList
+	}{
+		{mainConfigKeyMinResources, &dest.JenkinsfileRunnerMinResources},
+		{mainConfigKeyMaxResources, &dest.JenkinsfileRunnerMaxResources},
+	} {
+		if strVal := configData[r.key]; strings.TrimSpace(strVal) != "" {
+			if *r.dest, err = parseComputeResources(strVal); err != nil {
+				return wrapParseError(err, r.key, strVal)
+			}
+		}
+	}
+	for name, minQuantity := range dest.JenkinsfileRunnerMinResources {
+		if maxQuantity, found := dest.JenkinsfileRunnerMaxResources[name]; found && minQuantity.Cmp(maxQuantity) > 0 {
+			return fmt.Errorf(
+				"key %q: minimum %s of resource %q exceeds the maximum of %s",
+				mainConfigKeyMinResources, minQuantity.String(), name, maxQuantity.String(),
+			)
+		}
+	}
+
+	if strVal := configData[mainConfigKeyTopologySpread]; strings.TrimSpace(strVal) != "" {
+		var constraints []corev1.TopologySpreadConstraint
+		if err = decodeYAMLStrict(strVal, &constraints); err != nil {
+			return wrapParseError(err, mainConfigKeyTopologySpread, strVal)
+		}
+		if len(constraints) > 0 {
+			dest.JenkinsfileRunnerTopologySpreadConstraints = constraints
+		}
+	}
+
 	return nil
 }
 
+// validateAirGapped verifies that the given configuration does not
+// require internet access.
+func validateAirGapped(config *PipelineRunsConfigStruct) error {
+	if config.AirGappedMirrorRegistry == "" {
+		return fmt.Errorf(
+			"key %q: must be set if %q is true",
+			mainConfigKeyAirGappedMirrorRegistry, mainConfigKeyAirGappedEnabled,
+		)
+	}
+	if config.LoggingIndexURL != "" {
+		policy := &airgap.Policy{InternalHosts: config.AirGappedInternalHosts}
+		if err := policy.CheckURL(config.LoggingIndexURL); err != nil {
+			return errors.Wrapf(err, "key %q", mainConfigKeyLoggingIndexURL)
+		}
+	}
+	return nil
+}
+
+// parseList splits a list of values separated by commas and/or whitespace.
+// Returns nil if the list is empty.
+func parseList(value string) []string {
+	items := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	if len(items) == 0 {
+		return nil
+	}
+	return items
+}
+
 func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
 
 	isValidKey := func(key string) bool {
@@ -272,3 +632,218 @@ func processNetworkPoliciesConfig(configData map[string]string, dest *PipelineRu
 
 	return nil
 }
+
+func processSecurityProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.DefaultSecurityProfile = ""
+	dest.SecurityProfiles = nil
+
+	securityProfiles := map[string]*SecurityProfile{}
+	for key, value := range configData {
+		if key == "" || key != strings.TrimSpace(key) || strings.HasPrefix(key, "_") || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile, err := parseSecurityProfile(value)
+		if err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse security profile", key)
+		}
+		securityProfiles[key] = profile
+	}
+
+	defaultSecurityProfile := configData[securityProfilesConfigKeyDefault]
+	if defaultSecurityProfile != "" {
+		if _, found := securityProfiles[defaultSecurityProfile]; !found {
+			return fmt.Errorf(
+				"key %q: value %q does not denote an existing security profile key",
+				securityProfilesConfigKeyDefault,
+				defaultSecurityProfile,
+			)
+		}
+	}
+
+	dest.DefaultSecurityProfile = defaultSecurityProfile
+	if len(securityProfiles) > 0 {
+		dest.SecurityProfiles = securityProfiles
+	}
+
+	return nil
+}
+
+// parseSecurityProfile parses a security profile in YAML format.
+// Unknown fields are rejected to reveal typos which would otherwise
+// silently weaken the security settings.
+func parseSecurityProfile(value string) (*SecurityProfile, error) {
+	result := &SecurityProfile{}
+	if err := decodeYAMLStrict(value, result); err != nil {
+		return nil, err
+	}
+
+	for field, idRange := range map[string]*IDRange{
+		"runAsUserRange":  result.RunAsUserRange,
+		"runAsGroupRange": result.RunAsGroupRange,
+	} {
+		if idRange != nil && (idRange.Min < 0 || idRange.Max < idRange.Min) {
+			return nil, fmt.Errorf("%s: invalid range [%d, %d]", field, idRange.Min, idRange.Max)
+		}
+	}
+
+	pod := result.PodSecurityContext
+	container := result.ContainerSecurityContext
+	if result.RunAsUserRange != nil &&
+		((pod != nil && pod.RunAsUser != nil) || (container != nil && container.RunAsUser != nil)) {
+		return nil, errors.New("runAsUserRange: must not be combined with runAsUser")
+	}
+	if result.RunAsGroupRange != nil &&
+		((pod != nil && pod.RunAsGroup != nil) || (container != nil && container.RunAsGroup != nil)) {
+		return nil, errors.New("runAsGroupRange: must not be combined with runAsGroup")
+	}
+	if pod != nil && pod.SeccompProfile != nil {
+		if err := validateSeccompProfile(pod.SeccompProfile); err != nil {
+			return nil, errors.Wrap(err, "podSecurityContext.seccompProfile")
+		}
+	}
+	if result.RuntimeClassName != "" {
+		if err := validateRuntimeClassName(result.RuntimeClassName); err != nil {
+			return nil, errors.Wrap(err, "runtimeClassName")
+		}
+	}
+
+	return result, nil
+}
+
+// parseSeccompProfile parses a seccomp profile in YAML format, e.g.
+// `type: RuntimeDefault`.
+func parseSeccompProfile(value string) (*corev1.SeccompProfile, error) {
+	result := &corev1.SeccompProfile{}
+	if err := decodeYAMLStrict(value, result); err != nil {
+		return nil, err
+	}
+	if err := validateSeccompProfile(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateSeccompProfile checks that the given seccomp profile has a
+// known type and defines a localhost profile if and only if its type is
+// `Localhost`.
+func validateSeccompProfile(profile *corev1.SeccompProfile) error {
+	switch profile.Type {
+	case corev1.SeccompProfileTypeRuntimeDefault, corev1.SeccompProfileTypeUnconfined:
+		if profile.LocalhostProfile != nil {
+			return fmt.Errorf("localhostProfile: must not be set for type %q", profile.Type)
+		}
+	case corev1.SeccompProfileTypeLocalhost:
+		if profile.LocalhostProfile == nil || *profile.LocalhostProfile == "" {
+			return fmt.Errorf("localhostProfile: must be set for type %q", profile.Type)
+		}
+	default:
+		return fmt.Errorf("type: unsupported value %q", profile.Type)
+	}
+	return nil
+}
+
+// validateRuntimeClassName checks that the given name is a valid name of
+// a runtime class.
+func validateRuntimeClassName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func processSchedulingProfilesConfig(configData map[string]string, dest *PipelineRunsConfigStruct) error {
+	dest.SchedulingProfiles = nil
+
+	schedulingProfiles := map[string]*SchedulingProfile{}
+	for key, value := range configData {
+		if key == "" || key != strings.TrimSpace(key) || strings.HasPrefix(key, "_") || strings.TrimSpace(value) == "" {
+			continue
+		}
+		profile := &SchedulingProfile{}
+		if err := decodeYAMLStrict(value, profile); err != nil {
+			return errors.Wrapf(err, "key %q: cannot parse scheduling profile", key)
+		}
+		schedulingProfiles[key] = profile
+	}
+
+	if len(schedulingProfiles) > 0 {
+		dest.SchedulingProfiles = schedulingProfiles
+	}
+
+	return nil
+}
+
+// decodeYAMLStrict decodes the given YAML document into dest.
+// Unknown fields are rejected.
+func decodeYAMLStrict(value string, dest interface{}) error {
+	jsonValue, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonValue))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dest)
+}
+
+// parseExtendedResourceLimits parses a map of extended resource names to
+// quantities in YAML format.
+func parseExtendedResourceLimits(value string) (corev1.ResourceList, error) {
+	jsonValue, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	result := corev1.ResourceList{}
+	if err := json.Unmarshal(jsonValue, &result); err != nil {
+		return nil, err
+	}
+	for name := range result {
+		if !IsExtendedResourceName(name) {
+			return nil, fmt.Errorf("%q is not an extended resource name", name)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// parseComputeResources parses a map of compute resource names to
+// quantities in YAML format.
+func parseComputeResources(value string) (corev1.ResourceList, error) {
+	jsonValue, err := yaml.YAMLToJSON([]byte(value))
+	if err != nil {
+		return nil, err
+	}
+	result := corev1.ResourceList{}
+	if err := json.Unmarshal(jsonValue, &result); err != nil {
+		return nil, err
+	}
+	for name := range result {
+		if !IsComputeResourceName(name) {
+			return nil, fmt.Errorf("%q is not a compute resource name", name)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// IsComputeResourceName returns whether the given resource name denotes
+// a compute resource pipeline runs may set via `spec.resources`.
+func IsComputeResourceName(name corev1.ResourceName) bool {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+		return true
+	}
+	return false
+}
+
+// IsExtendedResourceName returns whether the given resource name denotes
+// an extended resource, i.e. a fully-qualified resource name outside the
+// `kubernetes.io` domain.
+func IsExtendedResourceName(name corev1.ResourceName) bool {
+	return strings.Contains(string(name), "/") &&
+		!strings.Contains(string(name), corev1.ResourceDefaultNamespacePrefix) &&
+		!strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix)
+}
@@ -17,7 +17,9 @@ import (
 	"github.com/pkg/errors"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/system"
 	_ "knative.dev/pkg/system/testing"
 )
@@ -275,6 +277,36 @@ func Test_loadPipelineRunsConfig_InvalidValues(t *testing.T) {
 
 		{mainConfigKeyTimeout, "a"},
 		{mainConfigKeyTimeout, "1a"},
+
+		{mainConfigKeyArgsOffloadThreshold, "a"},
+		{mainConfigKeyArgsOffloadThreshold, "1a"},
+
+		{mainConfigKeyTerminationGracePeriod, "a"},
+		{mainConfigKeyTerminationGracePeriod, "5m"},
+		{mainConfigKeyTerminationGracePeriod, "-1"},
+
+		{mainConfigKeyResolveRevision, "a"},
+		{mainConfigKeyUserNamespacesEnabled, "a"},
+		{mainConfigKeyAirGappedEnabled, "a"},
+
+		{mainConfigKeyExtendedResourceLimits, "a"},
+		{mainConfigKeyExtendedResourceLimits, "example.com/foo: a"},
+		{mainConfigKeyExtendedResourceLimits, "cpu: 1"},
+
+		{mainConfigKeyMinResources, "a"},
+		{mainConfigKeyMinResources, "cpu: a"},
+		{mainConfigKeyMinResources, "nvidia.com/gpu: 1"},
+		{mainConfigKeyMaxResources, "memory: a"},
+		{mainConfigKeyMaxResources, "pods: 1"},
+
+		{mainConfigKeyTopologySpread, "a"},
+		{mainConfigKeyTopologySpread, "- maxSkw: 1"},
+
+		{mainConfigKeyPSCSeccompProfile, "a"},
+		{mainConfigKeyPSCSeccompProfile, "type: Foo"},
+		{mainConfigKeyPSCSeccompProfile, "type: Localhost"},
+		{mainConfigKeyPSCSeccompProfile, "type: RuntimeDefault\nlocalhostProfile: profile1.json"},
+		{mainConfigKeyRuntimeClassName, "Not_Valid"},
 	} {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			tc := tc // capture current value before going parallel
@@ -322,6 +354,28 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsGroup:   "2222",
 				mainConfigKeyPSCFSGroup:      "3333",
 
+				mainConfigKeyPSCSeccompProfile:         "type: RuntimeDefault",
+				mainConfigKeyRuntimeClassName:          " gvisor ",
+				mainConfigKeyDeprecatedImageRegistries: "registry1.example.com, registry2.example.com:5000\n registry3",
+				mainConfigKeyArgsOffloadThreshold:      "5555",
+				mainConfigKeyResolveRevision:           "true",
+				mainConfigKeyResolveRevisionHosts:      "github.com .example.com",
+				mainConfigKeyAllowedImageRegistries:    "registry1.example.com/team1 registry4.example.com",
+				mainConfigKeyAllowedImageDigests:       "sha256:1111,sha256:2222",
+				mainConfigKeyImageSignaturePublicKey:   "\n publicKey1\n",
+				mainConfigKeyUserNamespacesEnabled:     "true",
+				mainConfigKeyExtendedResourceLimits:    "nvidia.com/gpu: 2\nexample.com/fpga: '1'",
+				mainConfigKeyMinResources:              "cpu: 100m",
+				mainConfigKeyMaxResources:              "cpu: 4\nmemory: 8Gi",
+				mainConfigKeyTopologySpread:            "- maxSkew: 1\n  topologyKey: topology.kubernetes.io/zone\n  whenUnsatisfiable: ScheduleAnyway\n",
+				mainConfigKeyTerminationGracePeriod:    "300",
+				mainConfigKeyLoggingIndexURL:           "https://es.example.com/logs/_doc",
+				mainConfigKeyLoggingClientCertSecret:   " clientCert1 ",
+				mainConfigKeyLoggingCABundleSecret:     "caBundle1",
+				mainConfigKeyAirGappedEnabled:          "true",
+				mainConfigKeyAirGappedMirrorRegistry:   " mirror.example.com/team1 ",
+				mainConfigKeyAirGappedInternalHosts:    "es.example.com, .corp.example.com",
+
 				"someKeyThatShouldBeIgnored": "34957349",
 			},
 			&PipelineRunsConfigStruct{
@@ -334,6 +388,44 @@ func Test_processMainConfig(t *testing.T) {
 				JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1111),
 				JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 				JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(3333),
+				JenkinsfileRunnerPodSecurityContextSeccompProfile: &corev1.SeccompProfile{
+					Type: corev1.SeccompProfileTypeRuntimeDefault,
+				},
+				JenkinsfileRunnerRuntimeClassName: "gvisor",
+
+				DeprecatedImageRegistries:         []string{"registry1.example.com", "registry2.example.com:5000", "registry3"},
+				PipelineArgsOffloadThresholdBytes: int64Ptr(5555),
+				ResolveRevision:                   true,
+				ResolveRevisionAllowedHosts:       []string{"github.com", ".example.com"},
+				AllowedImageRegistries:            []string{"registry1.example.com/team1", "registry4.example.com"},
+				AllowedImageDigests:               []string{"sha256:1111", "sha256:2222"},
+				ImageSignaturePublicKey:           "publicKey1",
+				UserNamespacesEnabled:             true,
+				JenkinsfileRunnerExtendedResourceLimits: corev1.ResourceList{
+					"nvidia.com/gpu":   resource.MustParse("2"),
+					"example.com/fpga": resource.MustParse("1"),
+				},
+				JenkinsfileRunnerMinResources: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("100m"),
+				},
+				JenkinsfileRunnerMaxResources: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("4"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+				JenkinsfileRunnerTopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+					},
+				},
+				JenkinsfileRunnerTerminationGracePeriodSeconds: int64Ptr(300),
+				LoggingIndexURL:         "https://es.example.com/logs/_doc",
+				LoggingClientCertSecret: "clientCert1",
+				LoggingCABundleSecret:   "caBundle1",
+				AirGapped:               true,
+				AirGappedMirrorRegistry: "mirror.example.com/team1",
+				AirGappedInternalHosts:  []string{"es.example.com", ".corp.example.com"},
 			},
 		},
 		{
@@ -348,6 +440,28 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsUser:    "",
 				mainConfigKeyPSCRunAsGroup:   "",
 				mainConfigKeyPSCFSGroup:      "",
+
+				mainConfigKeyPSCSeccompProfile:         "",
+				mainConfigKeyRuntimeClassName:          "",
+				mainConfigKeyDeprecatedImageRegistries: "",
+				mainConfigKeyArgsOffloadThreshold:      "",
+				mainConfigKeyResolveRevision:           "",
+				mainConfigKeyResolveRevisionHosts:      "",
+				mainConfigKeyAllowedImageRegistries:    "",
+				mainConfigKeyAllowedImageDigests:       "",
+				mainConfigKeyImageSignaturePublicKey:   "",
+				mainConfigKeyUserNamespacesEnabled:     "",
+				mainConfigKeyExtendedResourceLimits:    "",
+				mainConfigKeyMinResources:              "",
+				mainConfigKeyMaxResources:              "",
+				mainConfigKeyTopologySpread:            "",
+				mainConfigKeyTerminationGracePeriod:    "",
+				mainConfigKeyLoggingIndexURL:           "",
+				mainConfigKeyLoggingClientCertSecret:   "",
+				mainConfigKeyLoggingCABundleSecret:     "",
+				mainConfigKeyAirGappedEnabled:          "",
+				mainConfigKeyAirGappedMirrorRegistry:   "",
+				mainConfigKeyAirGappedInternalHosts:    "",
 			},
 			&PipelineRunsConfigStruct{},
 		},
@@ -370,6 +484,97 @@ func Test_processMainConfig(t *testing.T) {
 	}
 }
 
+func Test_processMainConfig_AirGappedInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expectedError string
+	}{
+		{
+			"mirror_registry_missing",
+			map[string]string{
+				mainConfigKeyAirGappedEnabled: "true",
+			},
+			`key "airGapped.mirrorRegistry": must be set if "airGapped.enabled" is true`,
+		},
+		{
+			"external_log_index",
+			map[string]string{
+				mainConfigKeyAirGappedEnabled:        "true",
+				mainConfigKeyAirGappedMirrorRegistry: "mirror.example.com",
+				mainConfigKeyLoggingIndexURL:         "https://es.example.com/logs/_doc",
+			},
+			`key "logging.elasticsearch.indexURL": URL "https://es.example.com/logs/_doc" does not point to an internal host`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			resultErr := processMainConfig(tc.configData, &PipelineRunsConfigStruct{})
+
+			// VERIFY
+			assert.ErrorContains(t, resultErr, tc.expectedError)
+		})
+	}
+}
+
+func Test_processMainConfig_MinResourcesExceedMaxResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configData := map[string]string{
+		mainConfigKeyMinResources: "cpu: 2\nmemory: 1Gi",
+		mainConfigKeyMaxResources: "cpu: 1",
+	}
+
+	// EXERCISE
+	resultErr := processMainConfig(configData, &PipelineRunsConfigStruct{})
+
+	// VERIFY
+	assert.Error(t, resultErr, `key "jenkinsfileRunner.minResources": minimum 2 of resource "cpu" exceeds the maximum of 1`)
+}
+
+func Test_IsExtendedResourceName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     corev1.ResourceName
+		expected bool
+	}{
+		{"nvidia.com/gpu", true},
+		{"example.com/foo", true},
+		{"cpu", false},
+		{"memory", false},
+		{"kubernetes.io/foo", false},
+		{"sub.kubernetes.io/foo", false},
+		{"requests.example.com/foo", false},
+	} {
+		assert.Equal(t, tc.expected, IsExtendedResourceName(tc.name), "name: %q", tc.name)
+	}
+}
+
+func Test_IsComputeResourceName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     corev1.ResourceName
+		expected bool
+	}{
+		{"cpu", true},
+		{"memory", true},
+		{"ephemeral-storage", true},
+		{"pods", false},
+		{"hugepages-2Mi", false},
+		{"nvidia.com/gpu", false},
+	} {
+		assert.Equal(t, tc.expected, IsComputeResourceName(tc.name), "name: %q", tc.name)
+	}
+}
+
 func Test_processNetworkPoliciesConfig(t *testing.T) {
 	t.Parallel()
 
@@ -522,6 +727,284 @@ func Test_processNetworkPoliciesConfig(t *testing.T) {
 	}
 }
 
+func Test_processSecurityProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"profiles_without_default",
+			map[string]string{
+				"restricted": "podSecurityContext:\n  runAsNonRoot: true\n  runAsUser: 1000\n  seccompProfile:\n    type: RuntimeDefault\n",
+				"empty":      "",
+				"_other":     "podSecurityContext:\n  runAsUser: 0",
+			},
+			&PipelineRunsConfigStruct{
+				SecurityProfiles: map[string]*SecurityProfile{
+					"restricted": {
+						PodSecurityContext: &corev1.PodSecurityContext{
+							RunAsNonRoot: boolPtr(true),
+							RunAsUser:    int64Ptr(1000),
+							SeccompProfile: &corev1.SeccompProfile{
+								Type: corev1.SeccompProfileTypeRuntimeDefault,
+							},
+						},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"with_default",
+			map[string]string{
+				securityProfilesConfigKeyDefault: "profile1",
+				"profile1":                       "podSecurityContext:\n  fsGroup: 1000",
+			},
+			&PipelineRunsConfigStruct{
+				DefaultSecurityProfile: "profile1",
+				SecurityProfiles: map[string]*SecurityProfile{
+					"profile1": {
+						PodSecurityContext: &corev1.PodSecurityContext{FSGroup: int64Ptr(1000)},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"container_security_context_and_id_ranges",
+			map[string]string{
+				"profile1": "containerSecurityContext:\n" +
+					"  allowPrivilegeEscalation: false\n" +
+					"  capabilities:\n" +
+					"    drop: [ALL]\n" +
+					"runAsUserRange:\n" +
+					"  min: 100000\n" +
+					"  max: 165535\n" +
+					"runAsGroupRange:\n" +
+					"  min: 100000\n" +
+					"  max: 100000\n",
+			},
+			&PipelineRunsConfigStruct{
+				SecurityProfiles: map[string]*SecurityProfile{
+					"profile1": {
+						ContainerSecurityContext: &corev1.SecurityContext{
+							AllowPrivilegeEscalation: boolPtr(false),
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{"ALL"},
+							},
+						},
+						RunAsUserRange:  &IDRange{Min: 100000, Max: 165535},
+						RunAsGroupRange: &IDRange{Min: 100000, Max: 100000},
+					},
+				},
+			},
+			"",
+		},
+		{
+			"runtime_class_name",
+			map[string]string{
+				"sandboxed": "podSecurityContext:\n  seccompProfile:\n    type: Localhost\n    localhostProfile: profiles/strict.json\nruntimeClassName: gvisor\n",
+			},
+			&PipelineRunsConfigStruct{
+				SecurityProfiles: map[string]*SecurityProfile{
+					"sandboxed": {
+						PodSecurityContext: &corev1.PodSecurityContext{
+							SeccompProfile: &corev1.SeccompProfile{
+								Type:             corev1.SeccompProfileTypeLocalhost,
+								LocalhostProfile: stringPtr("profiles/strict.json"),
+							},
+						},
+						RuntimeClassName: "gvisor",
+					},
+				},
+			},
+			"",
+		},
+		{
+			"invalid_seccomp_profile",
+			map[string]string{
+				"profile1": "podSecurityContext:\n  seccompProfile:\n    type: Foo\n",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse security profile: podSecurityContext.seccompProfile: type: unsupported value "Foo"`,
+		},
+		{
+			"invalid_runtime_class_name",
+			map[string]string{
+				"profile1": "runtimeClassName: Not_Valid\n",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse security profile: runtimeClassName: ` + validation.IsDNS1123Subdomain("Not_Valid")[0],
+		},
+		{
+			"invalid_id_range",
+			map[string]string{
+				"profile1": "runAsUserRange:\n  min: 2000\n  max: 1000\n",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse security profile: runAsUserRange: invalid range [2000, 1000]`,
+		},
+		{
+			"id_range_with_fixed_id",
+			map[string]string{
+				"profile1": "podSecurityContext:\n  runAsUser: 1000\nrunAsUserRange:\n  min: 1000\n  max: 2000\n",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse security profile: runAsUserRange: must not be combined with runAsUser`,
+		},
+		{
+			"default_not_existing",
+			map[string]string{
+				securityProfilesConfigKeyDefault: "profile2",
+				"profile1":                       "podSecurityContext:\n  fsGroup: 1000",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "_default": value "profile2" does not denote an existing security profile key`,
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"profile1": "podSecurityContext:\n  runAsUsr: 1000",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse security profile: json: unknown field "runAsUsr"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processSecurityProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Equal(t, resultErr.Error(), tc.expectedError)
+			}
+			assert.DeepEqual(t, tc.expected, dest)
+		})
+	}
+}
+
+func Test_loadPipelineRunsConfig_SecurityProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(
+		newNetworkPolicyConfigMap(map[string]string{
+			networkPoliciesConfigKeyDefault: "key1",
+			"key1":                          "policy1",
+		}),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      securityProfilesConfigMapName,
+				Namespace: system.Namespace(),
+			},
+			Data: map[string]string{
+				securityProfilesConfigKeyDefault: "profile1",
+				"profile1":                       "podSecurityContext:\n  runAsGroup: 2000",
+			},
+		},
+	)
+
+	// EXERCISE
+	resultConfig, resultErr := LoadPipelineRunsConfig(ctx, cf)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "profile1", resultConfig.DefaultSecurityProfile)
+	assert.DeepEqual(t, map[string]*SecurityProfile{
+		"profile1": {
+			PodSecurityContext: &corev1.PodSecurityContext{RunAsGroup: int64Ptr(2000)},
+		},
+	}, resultConfig.SecurityProfiles)
+}
+
+func Test_processSchedulingProfilesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configData    map[string]string
+		expected      *PipelineRunsConfigStruct
+		expectedError string
+	}{
+		{
+			"empty",
+			map[string]string{},
+			&PipelineRunsConfigStruct{},
+			"",
+		},
+		{
+			"profiles",
+			map[string]string{
+				"spread": "topologySpreadConstraints:\n- maxSkew: 1\n  topologyKey: kubernetes.io/hostname\n  whenUnsatisfiable: DoNotSchedule\n",
+				"none":   "{}",
+				"empty":  "",
+				"_other": "topologySpreadConstraints: []",
+			},
+			&PipelineRunsConfigStruct{
+				SchedulingProfiles: map[string]*SchedulingProfile{
+					"spread": {
+						TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+							{
+								MaxSkew:           1,
+								TopologyKey:       "kubernetes.io/hostname",
+								WhenUnsatisfiable: corev1.DoNotSchedule,
+							},
+						},
+					},
+					"none": {},
+				},
+			},
+			"",
+		},
+		{
+			"unknown_field",
+			map[string]string{
+				"profile1": "topologySpread: []",
+			},
+			&PipelineRunsConfigStruct{},
+			`key "profile1": cannot parse scheduling profile: json: unknown field "topologySpread"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			dest := &PipelineRunsConfigStruct{}
+
+			// EXERCISE
+			resultErr := processSchedulingProfilesConfig(tc.configData, dest)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.Equal(t, resultErr.Error(), tc.expectedError)
+			}
+			assert.DeepEqual(t, tc.expected, dest)
+		})
+	}
+}
+
 func newMainConfigMap(data map[string]string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -547,3 +1030,7 @@ func metav1Duration(d time.Duration) *metav1.Duration {
 }
 
 func int64Ptr(val int64) *int64 { return &val }
+
+func boolPtr(val bool) *bool { return &val }
+
+func stringPtr(val string) *string { return &val }
@@ -0,0 +1,140 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+)
+
+// handleSuperseded aborts the given pipeline run if it has been
+// superseded by a newer pipeline run of the same concurrency group
+// (see `spec.concurrencyGroup`): a pipeline run waiting to be started is
+// superseded by any newer pipeline run of the group, a pipeline run in
+// progress only by a newer pipeline run with
+// `spec.concurrencyCancelInProgress`.
+// Superseded pipeline runs get result api.ResultAbortedSuperseded.
+func (c *Controller) handleSuperseded(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if pipelineRun.GetSpec().ConcurrencyGroup == "" || pipelineRun.GetStatus().Result != api.ResultUndefined {
+		return nil
+	}
+	groupRuns, err := c.listConcurrencyGroupRuns(pipelineRun.GetAPIObject())
+	if err != nil {
+		return err
+	}
+	superseding := findSupersedingRun(groupRuns, pipelineRun.GetAPIObject())
+	if superseding == nil {
+		return nil
+	}
+	message := fmt.Sprintf("superseded by pipeline run %q of concurrency group %q",
+		superseding.GetName(), pipelineRun.GetSpec().ConcurrencyGroup,
+	)
+	klog.V(3).Infof("aborting pipeline run %q: %s", pipelineRun.GetKey(), message)
+	c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, api.EventReasonSuperseded, message)
+	pipelineRun.UpdateMessage(message)
+	return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultAbortedSuperseded, metav1.Now())
+}
+
+// isStartAllowedByConcurrencyGroup returns whether the given pipeline
+// run, which must be in state `new` or `queued`, may be started with
+// respect to its concurrency group, i.e. no other pipeline run of the
+// group is in progress.
+// Older pipeline runs of the group superseded by the given pipeline run
+// are added to the work queue, so that they get aborted (see
+// handleSuperseded) without waiting for their next reconciliation.
+func (c *Controller) isStartAllowedByConcurrencyGroup(pipelineRun k8s.PipelineRun) (bool, error) {
+	if pipelineRun.GetSpec().ConcurrencyGroup == "" {
+		return true, nil
+	}
+	self := pipelineRun.GetAPIObject()
+	groupRuns, err := c.listConcurrencyGroupRuns(self)
+	if err != nil {
+		return false, err
+	}
+	allowed := true
+	for _, other := range groupRuns {
+		if isSupersededBy(other, self) {
+			c.addPipelineRun(other)
+		}
+		if isInProgress(other) {
+			allowed = false
+		}
+	}
+	return allowed, nil
+}
+
+// listConcurrencyGroupRuns returns the unfinished pipeline runs of the
+// concurrency group of the given pipeline run in its namespace, excluding
+// the given pipeline run itself.
+func (c *Controller) listConcurrencyGroupRuns(self *api.PipelineRun) ([]*api.PipelineRun, error) {
+	pipelineRuns, err := c.pipelineRunLister.PipelineRuns(self.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return nil, errors.WithMessagef(err,
+			"failed to list pipeline runs in namespace %q", self.GetNamespace(),
+		)
+	}
+	var result []*api.PipelineRun
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.GetName() == self.GetName() ||
+			stewardlabels.IsLabelledAsIgnore(pipelineRun) ||
+			pipelineRun.Spec.ConcurrencyGroup != self.Spec.ConcurrencyGroup ||
+			pipelineRun.Status.Result != api.ResultUndefined ||
+			!pipelineRun.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		switch pipelineRun.Status.State {
+		case api.StateCleaning, api.StateFinished:
+			continue
+		}
+		result = append(result, pipelineRun)
+	}
+	return result, nil
+}
+
+// findSupersedingRun returns the newest of the given pipeline runs of
+// the same concurrency group superseding the given pipeline run or nil
+// if there is none.
+func findSupersedingRun(groupRuns []*api.PipelineRun, self *api.PipelineRun) *api.PipelineRun {
+	var newest *api.PipelineRun
+	for _, other := range groupRuns {
+		if isSupersededBy(self, other) && (newest == nil || isCreatedBefore(newest, other)) {
+			newest = other
+		}
+	}
+	return newest
+}
+
+// isSupersededBy returns whether pipeline run a is superseded by
+// pipeline run b of the same concurrency group.
+func isSupersededBy(a, b *api.PipelineRun) bool {
+	if !isCreatedBefore(a, b) || a.Status.Result != api.ResultUndefined {
+		return false
+	}
+	return !isInProgress(a) || b.Spec.ConcurrencyCancelInProgress
+}
+
+// isInProgress returns whether the given pipeline run has been started
+// and not yet finished its execution.
+func isInProgress(pipelineRun *api.PipelineRun) bool {
+	switch pipelineRun.Status.State {
+	case api.StatePreparing, api.StateWaiting, api.StateRunning:
+		return true
+	}
+	return false
+}
+
+// isQueuedByConcurrencyGroup returns whether the given pipeline run has
+// been queued because another pipeline run of its concurrency group is
+// in progress.
+func isQueuedByConcurrencyGroup(pipelineRun *api.PipelineRun) bool {
+	condition := apimeta.FindStatusCondition(pipelineRun.Status.Conditions, api.PipelineRunConditionQueued)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == api.QueuedConcurrencyGroupReason
+}
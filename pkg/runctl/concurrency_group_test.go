@@ -0,0 +1,148 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_isSupersededBy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		state            api.State
+		created          int64
+		cancelInProgress bool
+		expected         bool
+	}{
+		{"waiting_older", api.StateQueued, 50, false, true},
+		{"new_older", api.StateNew, 50, false, true},
+		{"waiting_newer", api.StateQueued, 200, false, false},
+		{"running_older", api.StateRunning, 50, false, false},
+		{"running_older_cancelInProgress", api.StateRunning, 50, true, true},
+		{"preparing_older_cancelInProgress", api.StatePreparing, 50, true, true},
+		{"running_newer_cancelInProgress", api.StateRunning, 200, true, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			a := fake.PipelineRun("a", "ns1", api.PipelineSpec{ConcurrencyGroup: "group1"})
+			a.Status.State = tc.state
+			a.SetCreationTimestamp(metav1.Unix(tc.created, 0))
+			b := fake.PipelineRun("b", "ns1", api.PipelineSpec{
+				ConcurrencyGroup:            "group1",
+				ConcurrencyCancelInProgress: tc.cancelInProgress,
+			})
+			b.Status.State = api.StateNew
+			b.SetCreationTimestamp(metav1.Unix(100, 0))
+
+			// EXERCISE
+			result := isSupersededBy(a, b)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_isStartAllowedByConcurrencyGroup(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		group           string
+		otherGroup      string
+		otherState      api.State
+		otherCreated    int64
+		expectedAllowed bool
+		expectedQueued  int
+	}{
+		{"no_group", "", "", api.StateRunning, 50, true, 0},
+		{"other_group_running", "group1", "group2", api.StateRunning, 50, true, 0},
+		{"same_group_running", "group1", "group1", api.StateRunning, 50, false, 0},
+		{"same_group_cleaning", "group1", "group1", api.StateCleaning, 50, true, 0},
+		{"same_group_older_queued", "group1", "group1", api.StateQueued, 50, true, 1},
+		{"same_group_newer_queued", "group1", "group1", api.StateQueued, 200, true, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{ConcurrencyGroup: tc.group})
+			run.Status.State = api.StateNew
+			run.SetCreationTimestamp(metav1.Unix(100, 0))
+			controller, cf := newController(run)
+			addRunToInformerStore(t, cf, run)
+			other := fake.PipelineRun("other", "ns1", api.PipelineSpec{ConcurrencyGroup: tc.otherGroup})
+			other.Status.State = tc.otherState
+			other.SetCreationTimestamp(metav1.Unix(tc.otherCreated, 0))
+			addRunToInformerStore(t, cf, other)
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			allowed, err := controller.isStartAllowedByConcurrencyGroup(pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAllowed, allowed)
+			assert.Equal(t, tc.expectedQueued, controller.workqueue.Len())
+		})
+	}
+}
+
+func Test_Controller_handleSuperseded(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		state            api.State
+		cancelInProgress bool
+		expectedResult   api.Result
+		expectedState    api.State
+	}{
+		{"queued", api.StateQueued, false, api.ResultAbortedSuperseded, api.StateCleaning},
+		{"running", api.StateRunning, false, api.ResultUndefined, api.StateRunning},
+		{"running_cancelInProgress", api.StateRunning, true, api.ResultAbortedSuperseded, api.StateCleaning},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{ConcurrencyGroup: "group1"})
+			run.Status.State = tc.state
+			run.SetCreationTimestamp(metav1.Unix(50, 0))
+			controller, cf := newController(run)
+			newer := fake.PipelineRun("newer", "ns1", api.PipelineSpec{
+				ConcurrencyGroup:            "group1",
+				ConcurrencyCancelInProgress: tc.cancelInProgress,
+			})
+			newer.Status.State = api.StateNew
+			newer.SetCreationTimestamp(metav1.Unix(100, 0))
+			addRunToInformerStore(t, cf, newer)
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			err := controller.handleSuperseded(ctx, pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, err)
+			result, err := getAPIPipelineRun(cf, "run", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedResult, result.Status.Result)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			if tc.expectedResult == api.ResultAbortedSuperseded {
+				assert.Equal(t, `superseded by pipeline run "newer" of concurrency group "group1"`, result.Status.Message)
+			}
+		})
+	}
+}
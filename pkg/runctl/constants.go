@@ -5,4 +5,25 @@ import (
 )
 
 const runClusterRoleName k8s.RoleName = "steward-run"
+
+// runControllerRunNamespaceClusterRoleName is the name of the cluster role
+// with the permissions of the run controller within run namespaces. It is
+// bound in each run namespace in namespace-scoped mode only, where these
+// permissions are not granted cluster-wide.
+const runControllerRunNamespaceClusterRoleName k8s.RoleName = "steward-run-controller-run-namespace"
+
+// runControllerServiceAccountName is the name of the service account of
+// the run controller in the system namespace.
+const runControllerServiceAccountName = "steward-run-controller"
 const jfrResultKey string = "jfr-termination-log"
+
+// testResultsResultKey is the name of the Tekton task result holding the
+// totals of the JUnit XML reports extracted from the workspace as JSON
+// object of type api.TestResults.
+const testResultsResultKey string = "test-results"
+
+// artifactsResultKey is the name of the Tekton task result holding the
+// artifacts uploaded by the Jenkinsfile Runner step. The first line
+// contains the number of uploaded and failed files separated by a space,
+// each further line the URL of an uploaded file.
+const artifactsResultKey string = "artifacts"
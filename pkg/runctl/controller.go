@@ -7,25 +7,42 @@ package runctl
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
 	"github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/sanitize"
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/leaderelection"
 	"github.com/SAP/stewardci-core/pkg/maintenancemode"
+	"github.com/SAP/stewardci-core/pkg/runctl/aborthandler"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
 	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/SAP/stewardci-core/pkg/runctl/preflight"
+	"github.com/SAP/stewardci-core/pkg/runctl/prepull"
+	"github.com/SAP/stewardci-core/pkg/runctl/quiethours"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	"github.com/SAP/stewardci-core/pkg/runctl/runstats"
+	"github.com/SAP/stewardci-core/pkg/sealedsecrets"
 	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/SAP/stewardci-core/pkg/throttle"
 	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/SAP/stewardci-core/pkg/utils/recovery"
+	"github.com/benbjohnson/clock"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -51,14 +68,78 @@ type Controller struct {
 	pipelineRunFetcher   k8s.PipelineRunFetcher
 	pipelineRunSynced    cache.InformerSynced
 	tektonTaskRunsSynced cache.InformerSynced
+	taskRunInformers     *k8s.RunNamespaceTaskRunInformers
 	workqueue            workqueue.RateLimitingInterface
 	testing              *controllerTesting
 	recorder             record.EventRecorder
 	pipelineRunLister    v1alpha1.PipelineRunLister
-	pipelineRunStore     cache.Store
+	tenantSynced         cache.InformerSynced
+	tenantLister         v1alpha1.TenantLister
+	tenantsByNamespace   func(nsName string) ([]*api.Tenant, error)
+	namespaceInformer    cache.SharedIndexInformer
+	namespaceLister      corev1listers.NamespaceLister
+
+	tenantNamespaceRequester tenantNamespaceRequester
 
 	heartbeatInterval time.Duration
 	heartbeatLogLevel *klog.Level
+
+	sealedSecretsDecrypter sealedsecrets.Decrypter
+
+	provenanceBuilderID string
+
+	exportRunTimeline         bool
+	timelineQueue             workqueue.RateLimitingInterface
+	pipelineRunEventInformers []cache.SharedIndexInformer
+
+	throttle *throttle.Throttle
+
+	maxRetries int
+
+	leaderElection *leaderelection.Config
+
+	maxThreadiness   int
+	workerAutoscaler *workerAutoscaler
+
+	statusCoalescer *statusCoalescer
+
+	abortHandlers        []aborthandler.Registration
+	abortHandlerExecutor *abortHandlerExecutor
+
+	runnerHeartbeatMaxSilence time.Duration
+
+	defaultTTLAfterFinished time.Duration
+	ttlDeletionLimiter      *throttle.DeletionLimiter
+
+	defaultMaxConcurrentRuns int
+	maxParallelRuns          int
+
+	quietHours              *quiethours.Schedule
+	quietHoursExemptClients map[string]bool
+
+	preflightChecks []preflight.Check
+
+	pipelineRunOpts k8s.PipelineRunOpts
+
+	runStats runstats.Store
+
+	gitHubAppTokenMinter       *githubapp.Minter
+	gitHubAppTokenRefreshTimes sync.Map
+
+	runAPITokenExpiration   time.Duration
+	runAPITokenRefreshTimes sync.Map
+
+	secretWatcher       *secrets.Watcher
+	secretResyncPending sync.Map
+
+	logArchive logarchive.Sink
+
+	imagePrepull *prepull.Manager
+
+	defaultSecretProvider string
+	secretProviderConfigs map[string]map[string][]byte
+
+	statusOffloadThresholdBytes int
 }
 
 type controllerTesting struct {
@@ -80,49 +161,306 @@ type ControllerOpts struct {
 	// If nil, heartbeat logging is disabled and heartbeats are only
 	// exposed via metric.
 	HeartbeatLogLevel *klog.Level
+
+	// SealedSecretsDecrypter is used to decrypt sealed secrets of
+	// pipeline runs.
+	// If nil, pipeline runs with sealed secrets fail.
+	SealedSecretsDecrypter sealedsecrets.Decrypter
+
+	// ProvenanceBuilderID is the SLSA builder ID stated in provenance
+	// attestations of successful pipeline runs.
+	// If empty, no provenance attestations are created.
+	ProvenanceBuilderID string
+
+	// ExportRunTimeline defines whether the run event timeline of finished
+	// pipeline runs logging to Elasticsearch is sent to the same index as
+	// the pipeline log.
+	ExportRunTimeline bool
+
+	// Throttle is used to throttle the controller under Kubernetes API
+	// server pressure.
+	// If nil, the controller is never throttled.
+	Throttle *throttle.Throttle
+
+	// MaxThreadiness is the maximum number of workers.
+	// If greater than the threadiness passed to Run(), the number of
+	// workers is adjusted dynamically between threadiness and
+	// MaxThreadiness based on the workqueue depth and the average sync
+	// latency. Otherwise the number of workers is fixed.
+	MaxThreadiness int
+
+	// StatusUpdateInterval is the minimum interval between two status
+	// updates of the same pipeline run which do not change the state,
+	// e.g. updates of the container state of running pipeline runs.
+	// Such updates are deferred until the interval has passed since the
+	// last status update of the pipeline run. State changes are always
+	// committed immediately.
+	// If zero or negative, status updates are never deferred.
+	StatusUpdateInterval time.Duration
+
+	// AbortHandlers are invoked when pipeline runs are cleaned up to
+	// tear down external resources created by the pipelines.
+	// Compiled-in handlers (see aborthandler.Register) are not included
+	// implicitly.
+	AbortHandlers []aborthandler.Registration
+
+	// RunStats stores statistics of finished pipeline runs per pipeline,
+	// which are used to estimate the duration of new pipeline runs.
+	// If nil, no statistics are maintained.
+	RunStats runstats.Store
+
+	// AbortHandlerWorkers is the maximum number of pipeline runs for
+	// which abort handlers are invoked in parallel.
+	// If zero or negative, a default is used.
+	AbortHandlerWorkers int
+
+	// RunnerHeartbeatMaxSilence is the maximum time without heartbeat
+	// from the Jenkinsfile Runner step of a running pipeline run.
+	// Pipeline runs exceeding it are aborted with result `timeout`.
+	// Until the first heartbeat, the time since the pipeline run started
+	// running counts.
+	// If zero or negative, heartbeats are not checked.
+	RunnerHeartbeatMaxSilence time.Duration
+
+	// DefaultTTLAfterFinished is the time after which finished pipeline
+	// runs are deleted unless they define `spec.ttlSecondsAfterFinished`.
+	// If zero or negative, only pipeline runs defining
+	// `spec.ttlSecondsAfterFinished` are deleted.
+	DefaultTTLAfterFinished time.Duration
+
+	// TTLDeletionLimiter limits the rate at which finished pipeline runs
+	// are deleted after their time to live. Deletions exceeding the rate
+	// are deferred.
+	// If nil, the rate is not limited.
+	TTLDeletionLimiter *throttle.DeletionLimiter
+
+	// StatusMessageTruncation defines how messages stored in the status
+	// of pipeline runs are truncated.
+	// By default messages are not truncated.
+	StatusMessageTruncation k8s.MessageTruncation
+
+	// PreflightChecks are performed before new pipeline runs are started.
+	// Pipeline runs failing a check stay queued with a condition
+	// describing the failure until all checks pass.
+	// If empty, pipeline runs are started without checks.
+	PreflightChecks []preflight.Check
+
+	// DefaultMaxConcurrentRuns is the maximum number of pipeline runs
+	// per tenant namespace that are executed concurrently, unless the
+	// namespace or the tenant defines a limit.
+	// Further pipeline runs are queued and started in FIFO order.
+	// If zero or negative, the number is not limited by default.
+	DefaultMaxConcurrentRuns int
+
+	// MaxParallelRuns is the maximum number of pipeline runs in all
+	// watched namespaces that are executed concurrently.
+	// Further pipeline runs are queued and started as running pipeline
+	// runs finish, preferring namespaces with fewer running pipeline runs.
+	// If zero or negative, the number is not limited.
+	MaxParallelRuns int
+
+	// QuietHours is the schedule of the quiet hours of the Steward
+	// installation, during which pipeline runs are queued instead of
+	// being started. Pipeline runs already started are not affected.
+	// If nil, there are no quiet hours.
+	QuietHours *quiethours.Schedule
+
+	// QuietHoursExemptClients are the names of the client namespaces whose
+	// pipeline runs are started during quiet hours, too.
+	QuietHoursExemptClients []string
+
+	// WorkqueueRateLimiter determines the delays of pipeline runs requeued
+	// after failed reconciliations and limits the overall rate of
+	// requeued items.
+	// If nil, client-go's workqueue.DefaultControllerRateLimiter() is used.
+	WorkqueueRateLimiter workqueue.RateLimiter
+
+	// MaxRetries is the maximum number of retries of a failed
+	// reconciliation. If exceeded, the item is dropped from the
+	// workqueue until it gets queued again by the next change or resync.
+	// If zero or negative, failed reconciliations are retried forever.
+	MaxRetries int
+
+	// LeaderElection configures the election of a leader among multiple
+	// replicas of the controller. Only the leader processes pipeline runs.
+	// If nil, leader election is disabled.
+	LeaderElection *leaderelection.Config
+
+	// GitHubAppTokenMinter mints installation tokens for repository auth
+	// secrets of type `steward.sap.com/github-app`. The tokens in the run
+	// namespaces of running pipeline runs are refreshed before they
+	// expire.
+	// If nil, such secrets are copied to run namespaces unchanged.
+	GitHubAppTokenMinter *githubapp.Minter
+
+	// RunAPITokenExpiration is the expiration of the per-run tokens for
+	// the Steward API. Each pipeline run gets a service account in its
+	// namespace that may only update the progress annotations of this
+	// pipeline run. A token of this service account is provided to the
+	// Jenkinsfile Runner and refreshed before it expires. The service
+	// account is deleted when the pipeline run finishes, which revokes
+	// the token.
+	// It must not be less than MinRunAPITokenExpiration.
+	// If zero or negative, per-run tokens are disabled.
+	RunAPITokenExpiration time.Duration
+
+	// SecretResync enables the refresh of secrets copied to the run
+	// namespaces of waiting pipeline runs when their source secret in the
+	// tenant namespace changes. Secrets in tenant namespaces are watched
+	// while pipeline runs in there are preparing or waiting, so that
+	// long-queued runs do not use stale credentials.
+	SecretResync bool
+
+	// LogArchive stores the log of the Jenkinsfile Runner of finished
+	// pipeline runs before their run namespace is deleted. The location
+	// of the stored log is recorded in `status.logUrl`.
+	// If nil, logs are not archived.
+	LogArchive logarchive.Sink
+
+	// ImagePrepull pulls the default Jenkinsfile Runner image on the nodes
+	// running pipeline runs, so that pipeline runs do not wait for the
+	// image pull after an update of the image.
+	// If nil, images are not prepulled.
+	ImagePrepull *prepull.Manager
+
+	// DefaultSecretProvider is the name of the secret provider (see
+	// package registry of the secrets package) used for pipeline runs
+	// that do not select a provider via annotation
+	// `steward.sap.com/secret-provider`.
+	// If empty, secrets are read from the tenant namespace.
+	DefaultSecretProvider string
+
+	// SecretProviderConfigs are the installation-wide configurations of
+	// secret providers by provider name (see registry.SplitConfig).
+	SecretProviderConfigs map[string]map[string][]byte
+
+	// StatusOffloadThresholdBytes is the size in bytes of the bulky
+	// status fields (message, history and stages) of a finished pipeline
+	// run above which these fields are moved to a config map referenced
+	// via `status.offloadedStatus`. This keeps pipeline run objects small,
+	// e.g. for the memory consumption of informers.
+	// If zero or negative, status fields are not offloaded.
+	StatusOffloadThresholdBytes int
 }
 
 // NewController creates new Controller
 func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
-	pipelineRunInformer := factory.StewardInformerFactory().Steward().V1alpha1().PipelineRuns()
-	pipelineRunLister := pipelineRunInformer.Lister()
-	pipelineRunFetcher := k8s.NewListerBasedPipelineRunFetcher(pipelineRunInformer.Lister())
-	tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+	pipelineRunInformers := k8s.NewPipelineRunInformers(factory)
+	pipelineRunLister := pipelineRunInformers.Lister()
+	pipelineRunFetcher := k8s.NewListerBasedPipelineRunFetcher(pipelineRunLister)
+	tenantInformers := k8s.NewTenantInformers(factory)
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.V(3).Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: factory.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "runController"})
 
+	rateLimiter := opts.WorkqueueRateLimiter
+	if rateLimiter == nil {
+		rateLimiter = workqueue.DefaultControllerRateLimiter()
+	}
+
 	controller := &Controller{
 		factory:            factory,
 		pipelineRunFetcher: pipelineRunFetcher,
 		pipelineRunLister:  pipelineRunLister,
-		pipelineRunSynced:  pipelineRunInformer.Informer().HasSynced,
-
-		tektonTaskRunsSynced: tektonTaskRunInformer.Informer().HasSynced,
-		workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
-		recorder:             recorder,
-		pipelineRunStore:     pipelineRunInformer.Informer().GetStore(),
+		pipelineRunSynced:  pipelineRunInformers.HasSynced,
+		tenantSynced:       tenantInformers.HasSynced,
+		tenantLister:       tenantInformers.Lister(),
+		workqueue:          workqueue.NewNamedRateLimitingQueue(opts.Throttle.WorkqueueRateLimiter(rateLimiter), metrics.WorkqueueName),
+		recorder:           recorder,
 	}
+	controller.namespaceInformer = newNamespaceInformer(factory)
+	controller.namespaceLister = corev1listers.NewNamespaceLister(controller.namespaceInformer.GetIndexer())
 
 	controller.heartbeatInterval = opts.HeartbeatInterval
+	controller.sealedSecretsDecrypter = opts.SealedSecretsDecrypter
+	controller.provenanceBuilderID = opts.ProvenanceBuilderID
+	controller.exportRunTimeline = opts.ExportRunTimeline
+	if opts.ExportRunTimeline {
+		controller.timelineQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.TimelineExportWorkqueueName)
+		watchNamespaces := factory.WatchNamespaces()
+		if len(watchNamespaces) == 0 {
+			watchNamespaces = []string{metav1.NamespaceAll}
+		}
+		for _, namespace := range watchNamespaces {
+			controller.pipelineRunEventInformers = append(controller.pipelineRunEventInformers, newPipelineRunEventInformer(factory, namespace))
+		}
+	}
+	controller.throttle = opts.Throttle
+	controller.maxRetries = opts.MaxRetries
+	controller.maxThreadiness = opts.MaxThreadiness
+	controller.statusCoalescer = newStatusCoalescer(opts.StatusUpdateInterval, clock.New())
+	controller.abortHandlers = opts.AbortHandlers
+	if len(opts.AbortHandlers) > 0 {
+		controller.abortHandlerExecutor = newAbortHandlerExecutor(opts.AbortHandlerWorkers, func(key string) {
+			controller.workqueue.Add(key)
+		})
+	}
+	controller.runnerHeartbeatMaxSilence = opts.RunnerHeartbeatMaxSilence
+	controller.defaultTTLAfterFinished = opts.DefaultTTLAfterFinished
+	controller.ttlDeletionLimiter = opts.TTLDeletionLimiter
+	controller.preflightChecks = opts.PreflightChecks
+	controller.pipelineRunOpts = k8s.PipelineRunOpts{MessageTruncation: opts.StatusMessageTruncation}
+	controller.runStats = opts.RunStats
+	controller.gitHubAppTokenMinter = opts.GitHubAppTokenMinter
+	controller.runAPITokenExpiration = opts.RunAPITokenExpiration
+	if opts.SecretResync {
+		controller.secretWatcher = secrets.NewWatcher(factory.CoreV1(), controller.onTenantSecretChange)
+	}
+	controller.logArchive = opts.LogArchive
+	controller.imagePrepull = opts.ImagePrepull
+	controller.defaultSecretProvider = opts.DefaultSecretProvider
+	controller.secretProviderConfigs = opts.SecretProviderConfigs
+	controller.statusOffloadThresholdBytes = opts.StatusOffloadThresholdBytes
+	controller.defaultMaxConcurrentRuns = opts.DefaultMaxConcurrentRuns
+	controller.maxParallelRuns = opts.MaxParallelRuns
+	controller.quietHours = opts.QuietHours
+	if len(opts.QuietHoursExemptClients) > 0 {
+		controller.quietHoursExemptClients = map[string]bool{}
+		for _, client := range opts.QuietHoursExemptClients {
+			controller.quietHoursExemptClients[client] = true
+		}
+	}
+	controller.tenantNamespaceRequester = &annotatingTenantNamespaceRequester{factory: factory}
 	if opts.HeartbeatLogLevel != nil {
 		copyOfValue := *opts.HeartbeatLogLevel
 		controller.heartbeatLogLevel = &copyOfValue
 	}
+	if opts.LeaderElection != nil {
+		copyOfConfig := *opts.LeaderElection
+		controller.leaderElection = &copyOfConfig
+	}
 
-	pipelineRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	if err := tenantInformers.AddIndexers(cache.Indexers{k8s.TenantNamespaceIndex: k8s.TenantNamespaceIndexFunc}); err != nil {
+		klog.Errorf("failed to add tenant namespace index: %s", err.Error())
+	}
+	controller.tenantsByNamespace = func(nsName string) ([]*api.Tenant, error) {
+		return tenantInformers.ByIndex(k8s.TenantNamespaceIndex, nsName)
+	}
+
+	pipelineRunInformers.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.addPipelineRun,
 		UpdateFunc: func(old, new interface{}) {
 			controller.addPipelineRun(new)
 		},
 	})
-	tektonTaskRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+
+	taskRunEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.handleTektonTaskRun,
 		UpdateFunc: func(old, new interface{}) {
 			controller.handleTektonTaskRun(new)
 		},
-	})
+	}
+	if len(factory.WatchNamespaces()) == 0 {
+		tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+		controller.tektonTaskRunsSynced = tektonTaskRunInformer.Informer().HasSynced
+		tektonTaskRunInformer.Informer().AddEventHandler(taskRunEventHandler)
+	} else {
+		// In namespace-scoped mode the controller is not permitted to
+		// watch task runs in all namespaces. Instead task runs are
+		// watched per run namespace while the pipeline run is active.
+		controller.taskRunInformers = k8s.NewRunNamespaceTaskRunInformers(factory, taskRunEventHandler)
+	}
 
 	return controller
 }
@@ -130,10 +468,12 @@ func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
 // meterAllPipelineRunsPeriodic observes certain metrics of all existing pipeline runs (in the informer cache).
 func (c *Controller) meterAllPipelineRunsPeriodic() {
 	klog.V(4).Infof("metering all pipeline runs")
-	objs := c.pipelineRunStore.List()
-	for _, obj := range objs {
-		pipelineRun := obj.(*api.PipelineRun)
-
+	pipelineRuns, err := c.pipelineRunLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "failed to list pipeline runs for metering")
+		return
+	}
+	for _, pipelineRun := range pipelineRuns {
 		// do not meter delays caused by finalizers
 		if pipelineRun.DeletionTimestamp.IsZero() {
 			metrics.PipelineRunsPeriodic.Observe(pipelineRun)
@@ -145,12 +485,53 @@ func (c *Controller) meterAllPipelineRunsPeriodic() {
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	if c.taskRunInformers != nil {
+		defer c.taskRunInformers.UnwatchAll()
+	}
+	if c.secretWatcher != nil {
+		defer c.secretWatcher.UnwatchAll()
+	}
+	if c.abortHandlerExecutor != nil {
+		defer c.abortHandlerExecutor.shutDown()
+	}
+	if c.timelineQueue != nil {
+		defer c.timelineQueue.ShutDown()
+	}
 
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.pipelineRunSynced, c.tektonTaskRunsSynced); !ok {
+	go c.namespaceInformer.Run(stopCh)
+	cacheSyncs := []cache.InformerSynced{c.pipelineRunSynced, c.tenantSynced, c.namespaceInformer.HasSynced}
+	if c.tektonTaskRunsSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.tektonTaskRunsSynced)
+	}
+	for _, informer := range c.pipelineRunEventInformers {
+		go informer.Run(stopCh)
+		cacheSyncs = append(cacheSyncs, informer.HasSynced)
+	}
+	for _, check := range c.preflightChecks {
+		if cachedCheck, ok := check.(preflight.CachedCheck); ok {
+			informer := cachedCheck.Informer()
+			go informer.Run(stopCh)
+			cacheSyncs = append(cacheSyncs, informer.HasSynced)
+		}
+	}
+	if ok := cache.WaitForCacheSync(stopCh, cacheSyncs...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	if c.leaderElection != nil {
+		return leaderelection.Run(c.factory.CoordinationV1(), *c.leaderElection, stopCh, func(stopCh <-chan struct{}) {
+			c.runWorkers(threadiness, stopCh)
+		})
+	}
+	c.runWorkers(threadiness, stopCh)
+	return nil
+}
+
+// runWorkers starts the metering, the heartbeat stimulator, the run event
+// timeline export, the abort handler executor, the image prepull and the
+// workers and blocks until the stop channel is closed.
+func (c *Controller) runWorkers(threadiness int, stopCh <-chan struct{}) {
 	klog.V(2).Infof("Starting metering of pipeline runs with interval %v", meteringInterval)
 	go wait.Until(c.meterAllPipelineRunsPeriodic, meteringInterval, stopCh)
 
@@ -161,19 +542,47 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 		klog.V(2).Info("Controller heartbeat is disabled")
 	}
 
+	if c.abortHandlerExecutor != nil {
+		c.abortHandlerExecutor.start(stopCh)
+	}
+	if c.timelineQueue != nil {
+		klog.V(2).Infof("Starting run event timeline export")
+		go wait.Until(c.runTimelineExportWorker, time.Second, stopCh)
+	}
+
+	if c.imagePrepull != nil {
+		klog.V(2).Infof("Starting image prepull with interval %s", imagePrepullInterval)
+		go wait.Until(c.syncImagePrepull, imagePrepullInterval, stopCh)
+	}
+
 	klog.V(2).Infof("Start workers")
-	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+	workers := func() int { return threadiness }
+	maxWorkers := threadiness
+	if c.maxThreadiness > threadiness {
+		klog.V(2).Infof("Starting worker autoscaling (min: %d, max: %d)", threadiness, c.maxThreadiness)
+		autoscaler := newWorkerAutoscaler(threadiness, c.maxThreadiness)
+		c.workerAutoscaler = autoscaler
+		workers = autoscaler.Workers
+		maxWorkers = c.maxThreadiness
+		go wait.Until(func() { autoscaler.Adjust(c.workqueue.Len()) }, workerAutoscalingInterval, stopCh)
+	}
+	workerLimiter := c.throttle.NewDynamicWorkerLimiter(workers)
+	for i := 0; i < maxWorkers; i++ {
+		go wait.Until(func() { c.runWorker(workerLimiter, stopCh) }, time.Second, stopCh)
 	}
 	klog.V(2).Infof("Workers running")
 
 	<-stopCh
 	klog.V(2).Infof("Workers stopped")
-	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(workerLimiter *throttle.WorkerLimiter, stopCh <-chan struct{}) {
+	for workerLimiter.Acquire(stopCh) {
+		more := c.processNextWorkItem()
+		workerLimiter.Release()
+		if !more {
+			return
+		}
 	}
 }
 
@@ -206,13 +615,21 @@ func (c *Controller) processNextWorkItem() bool {
 			// Forget here else we'd go into a loop of attempting to
 			// process a work item that is invalid.
 			c.workqueue.Forget(obj)
-			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", sanitize.Object(obj)))
 			return nil
 		}
 
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Foo resource to be synced.
-		if err := c.syncHandler(key); err != nil {
+		start := time.Now()
+		err := c.syncHandlerRecovering(key)
+		c.workerAutoscaler.ObserveSync(time.Since(start))
+		if err != nil {
+			if c.maxRetries > 0 && c.workqueue.NumRequeues(key) >= c.maxRetries {
+				c.workqueue.Forget(key)
+				metrics.WorkqueueDroppedKeys.Inc()
+				return fmt.Errorf("error syncing '%s': %s, dropping after %d retries", key, err.Error(), c.maxRetries)
+			}
 			// Put the item back on the workqueue to handle any transient errors.
 			c.workqueue.AddRateLimited(key)
 			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
@@ -232,6 +649,29 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
+// Reconcile reconciles the pipeline run with the given key once, like a
+// worker of a running controller does. The informer caches must have been
+// synced before. It is meant for tools replaying reconciliations, e.g.
+// with fake clients, and must not be called while the controller runs.
+func (c *Controller) Reconcile(key string) error {
+	return c.syncHandlerRecovering(key)
+}
+
+// syncHandlerRecovering calls syncHandler and converts a panic into a sync
+// error, so that a single object cannot crash the whole controller.
+// The stack trace is logged and the object is requeued like for any other
+// sync error.
+func (c *Controller) syncHandlerRecovering(key string) error {
+	err := recovery.Call(func() error {
+		return c.syncHandler(key)
+	})
+	if panicErr, ok := err.(*recovery.PanicError); ok {
+		klog.Errorf("%s while syncing '%s'\n%s", panicErr.Error(), key, panicErr.Stack)
+		metrics.ControllerPanics.Inc()
+	}
+	return err
+}
+
 func (c *Controller) heartbeatStimulus() {
 	c.workqueue.Add(heartbeatStimulusKey)
 }
@@ -259,7 +699,7 @@ func (c *Controller) createRunManager(pipelineRun k8s.PipelineRun) run.Manager {
 	}
 	tenant := k8s.NewTenantNamespace(c.factory, pipelineRun.GetNamespace())
 	workFactory := tenant.TargetClientFactory()
-	return c.newRunManager(workFactory, tenant.GetSecretProvider())
+	return c.newRunManager(workFactory, c.secretProvider(pipelineRun))
 }
 
 func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
@@ -267,7 +707,10 @@ func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider
 		return c.testing.newRunManagerStub(workFactory, secretProvider)
 
 	}
-	return newRunManager(workFactory, secretProvider)
+	manager := newRunManager(workFactory, secretProvider)
+	manager.decrypter = c.sealedSecretsDecrypter
+	manager.runAPITokenExpiration = c.runAPITokenExpiration
+	return manager
 }
 
 func (c *Controller) loadPipelineRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
@@ -284,6 +727,28 @@ func (c *Controller) isMaintenanceMode(ctx context.Context) (bool, error) {
 	return maintenancemode.IsMaintenanceMode(ctx, c.factory)
 }
 
+// watchTaskRuns starts or stops watching task runs in the run namespace
+// of the given pipeline run with the given key in namespace-scoped mode.
+func (c *Controller) watchTaskRuns(pipelineRun *api.PipelineRun, key string) {
+	if c.taskRunInformers == nil || pipelineRun.Status.Namespace == "" {
+		return
+	}
+	if pipelineRun.Status.State == api.StateFinished {
+		c.taskRunInformers.Unwatch(key)
+	} else {
+		c.taskRunInformers.Watch(pipelineRun.Status.Namespace, key)
+	}
+}
+
+// unwatchTaskRuns stops watching the task runs of the pipeline run with
+// the given key, e.g. because the pipeline run has been deleted.
+func (c *Controller) unwatchTaskRuns(key string) {
+	if c.taskRunInformers == nil {
+		return
+	}
+	c.taskRunInformers.Unwatch(key)
+}
+
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Foo resource
 // with the current status of the resource.
@@ -303,19 +768,36 @@ func (c *Controller) syncHandler(key string) error {
 	}
 	// If pipelineRun is not found there is nothing to sync
 	if pipelineRunAPIObj == nil {
+		c.statusCoalescer.Forget(key)
+		c.ttlDeletionLimiter.Forget(key)
+		c.unwatchSecrets(key)
+		c.unwatchTaskRuns(key)
 		return nil
 	}
 	// don't process if labelled as to be ignored
 	if stewardlabels.IsLabelledAsIgnore(pipelineRunAPIObj) {
 		return nil
 	}
-	// fast exit - no finalizer cleanup needed
+	// move pipeline runs of tenants without tenant namespace
+	if _, pending := pipelineRunAPIObj.GetLabels()[api.LabelPendingTenant]; pending && pipelineRunAPIObj.Status.State == api.StateUndefined {
+		return c.handlePipelineRunOfPendingTenant(ctx, pipelineRunAPIObj)
+	}
+	// resolve the template of new pipeline runs
+	if pipelineRunAPIObj.Spec.TemplateRef != nil && pipelineRunAPIObj.Status.State == api.StateUndefined {
+		if resolved, err := c.resolvePipelineRunTemplate(ctx, pipelineRunAPIObj); err != nil || !resolved {
+			return err
+		}
+	}
+	c.watchTaskRuns(pipelineRunAPIObj, key)
+	c.watchSecrets(pipelineRunAPIObj, key)
+	// fast exit - no finalizer cleanup needed, only garbage collection
 	if pipelineRunAPIObj.Status.State == api.StateFinished && !utils.StringSliceContains(pipelineRunAPIObj.ObjectMeta.Finalizers, k8s.FinalizerName) {
-		return nil
+		c.statusCoalescer.Forget(key)
+		return c.collectFinishedPipelineRun(ctx, key, pipelineRunAPIObj, time.Now())
 	}
 
 	// Get real pipelineRun bypassing cache
-	pipelineRun, err := k8s.NewPipelineRun(ctx, pipelineRunAPIObj, c.factory)
+	pipelineRun, err := k8s.NewPipelineRunWithOpts(ctx, pipelineRunAPIObj, c.factory, c.pipelineRunOpts)
 	if err != nil {
 		return err
 	}
@@ -332,6 +814,10 @@ func (c *Controller) syncHandler(key string) error {
 
 	// Check if object has deletion timestamp ...
 	if pipelineRun.HasDeletionTimestamp() {
+		// wait for the abort handlers, they are invoked asynchronously
+		if done, err := c.invokeAbortHandlers(ctx, pipelineRun); err != nil || !done {
+			return err
+		}
 		runManager := c.createRunManager(pipelineRun)
 		err = runManager.Cleanup(ctx, pipelineRun)
 		if err != nil {
@@ -348,6 +834,11 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
+	// Check if pipeline run is superseded in its concurrency group
+	if err := c.handleSuperseded(ctx, pipelineRun); err != nil {
+		return err
+	}
+
 	// As soon as we have a result we can cleanup
 	if pipelineRun.GetStatus().Result != api.ResultUndefined && pipelineRun.GetStatus().State != api.StateCleaning {
 		err = c.changeState(pipelineRun, api.StateCleaning, metav1.Now())
@@ -365,7 +856,7 @@ func (c *Controller) syncHandler(key string) error {
 		}
 	}
 
-	if pipelineRun.GetStatus().State == api.StateNew {
+	if state := pipelineRun.GetStatus().State; state == api.StateNew || state == api.StateQueued {
 		maintenanceMode, err := c.isMaintenanceMode(ctx)
 		if err != nil {
 			return err
@@ -373,13 +864,52 @@ func (c *Controller) syncHandler(key string) error {
 		if maintenanceMode {
 			err := fmt.Errorf("pipeline execution is paused while the system is in maintenance mode")
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeNormal, api.EventReasonMaintenanceMode, err.Error())
+			if setSchedulingDelayed(pipelineRun, api.SchedulingMaintenanceModeReason, err.Error()) {
+				if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+					return err
+				}
+			}
 			// Return error that the pipeline stays in the queue and will be processed after switching back to normal mode.
 			return err
 		}
+		startAllowed, err := c.isStartAllowedByConcurrencyGroup(pipelineRun)
+		if err != nil {
+			return err
+		}
+		queuedReason := api.QueuedConcurrencyGroupReason
+		if startAllowed {
+			startAllowed, err = c.isStartAllowedByQuietHours(pipelineRun, time.Now())
+			if err != nil {
+				return err
+			}
+			queuedReason = api.QueuedQuietHoursReason
+		}
+		if startAllowed {
+			startAllowed, queuedReason, err = c.isStartAllowedByRunLimit(ctx, pipelineRun)
+			if err != nil {
+				return err
+			}
+		}
+		if !startAllowed {
+			if err := c.queuePipelineRun(ctx, pipelineRun, queuedReason); err != nil {
+				return err
+			}
+			// The pipeline run is processed again when another pipeline run has finished
+			// or the quiet hours are over.
+			klog.V(4).Infof("pipeline run %q stays queued (%s)", pipelineRun.GetKey(), queuedReason)
+			return nil
+		}
+		if err = c.checkPreflight(ctx, pipelineRun); err != nil {
+			// Return error that the pipeline stays in the queue and will be checked again later.
+			return err
+		}
+		c.dequeuePipelineRun(pipelineRun)
+		c.estimateDuration(ctx, pipelineRun)
 		if err = c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StatePreparing, metav1.Now()); err != nil {
 			return err
 		}
 		metrics.PipelineRunsStarted.Inc()
+		metrics.PipelineRunsStartedByCreatorType.Observe(pipelineRunAPIObj)
 	}
 
 	runManager := c.createRunManager(pipelineRun)
@@ -393,13 +923,13 @@ func (c *Controller) syncHandler(key string) error {
 		if err != nil {
 			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateFinished, api.ResultErrorInfra, "failed to load configuration for pipeline runs")
 		}
+		addWarnings(pipelineRun, pipelineRunsConfig)
 		namespace, auxNamespace, err := runManager.Start(ctx, pipelineRun, pipelineRunsConfig)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
 			resultClass := serrors.GetClass(err)
 			// In case we have a result we can cleanup. Otherwise we retry in the next iteration.
 			if resultClass != api.ResultUndefined {
-				pipelineRun.UpdateMessage(err.Error())
 				pipelineRun.StoreErrorAsMessage(err, "preparing failed")
 				return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, resultClass, metav1.Now())
 			}
@@ -417,6 +947,11 @@ func (c *Controller) syncHandler(key string) error {
 		if err != nil {
 			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateCleaning, api.ResultErrorInfra, "waiting failed")
 		}
+		if aborted, err := c.handleSpecTimeout(ctx, pipelineRun, time.Now()); err != nil || aborted {
+			return err
+		}
+		c.resyncSecretsIfChanged(ctx, pipelineRunAPIObj, pipelineRun, key)
+		c.refreshRunAPITokenIfDue(ctx, pipelineRunAPIObj, pipelineRun, key, time.Now())
 		started := run.GetStartTime()
 		if started != nil {
 			if err := c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateRunning, *started); err != nil {
@@ -429,25 +964,101 @@ func (c *Controller) syncHandler(key string) error {
 			return c.onGetRunError(ctx, pipelineRunAPIObj, pipelineRun, err, api.StateCleaning, api.ResultErrorInfra, "running failed")
 		}
 		containerInfo := run.GetContainerInfo()
+		containerChanged := containerInfo != nil && !equality.Semantic.DeepEqual(*containerInfo, pipelineRun.GetStatus().Container)
 		pipelineRun.UpdateContainer(containerInfo)
+		progressConfigMap, progressErr := c.getProgressConfigMap(ctx, pipelineRun)
+		progress := progressFromData(progressData(progressConfigMap, pipelineRunAPIObj.GetAnnotations()))
+		progressChanged := progress != nil && isProgressChanged(pipelineRun.GetStatus().Progress, progress)
+		if progressChanged {
+			progress.UpdatedAt = metav1.Now()
+			pipelineRun.UpdateProgress(progress)
+		}
 		if finished, result := run.IsFinished(); finished {
+			if stages := c.getStages(ctx, pipelineRun); stages != nil {
+				pipelineRun.UpdateStages(stages)
+			}
+			var testResults *api.TestResults
+			if pipelineRun.GetSpec().TestResults != nil {
+				testResults = run.GetTestResults()
+			}
+			if testResults != nil {
+				pipelineRun.UpdateTestResults(testResults)
+			}
+			if pipelineRun.GetSpec().Artifacts != nil {
+				if artifacts := run.GetArtifacts(); artifacts != nil {
+					pipelineRun.UpdateArtifacts(artifacts)
+				}
+			}
 			pipelineRun.UpdateMessage(run.GetMessage())
-			return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, result, *run.GetCompletionTime())
+			if err := c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, result, *run.GetCompletionTime()); err != nil {
+				return err
+			}
+			metrics.PipelineRunsTestCases.Observe(testResults)
+			return nil
+		}
+		if aborted, err := c.handleSpecTimeout(ctx, pipelineRun, time.Now()); err != nil || aborted {
+			return err
+		}
+		// the heartbeat cannot be checked if the progress config map could not be read
+		runningSince := pipelineRun.GetStatus().StateDetails.StartedAt.Time
+		if silence, exceeded := c.runnerSilence(progressConfigMap, runningSince, time.Now()); progressErr == nil && exceeded {
+			message := fmt.Sprintf(
+				"the Jenkinsfile Runner did not send a heartbeat for %v (maximum: %v)",
+				silence.Round(time.Second), c.runnerHeartbeatMaxSilence,
+			)
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonRunnerSilent, message)
+			pipelineRun.UpdateMessage(message)
+			return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultTimeout, metav1.Now())
+		}
+		c.refreshGitHubAppTokensIfDue(ctx, pipelineRunAPIObj, pipelineRun, key, time.Now())
+		c.refreshRunAPITokenIfDue(ctx, pipelineRunAPIObj, pipelineRun, key, time.Now())
+		// commit container and progress update unless deferred to batch rapid
+		// successive updates
+		if delay := c.statusCoalescer.Delay(key); delay > 0 {
+			if containerChanged || progressChanged {
+				klog.V(5).Infof("deferring status update of %q by %v", key, delay)
+				c.workqueue.AddAfter(key, delay)
+			}
+			return nil
 		}
-		// commit container update
 		err = c.commitStatusAndMeter(ctx, pipelineRun)
 		if err != nil {
 			return err
 		}
 
 	case api.StateCleaning:
+		c.gitHubAppTokenRefreshTimes.Delete(key)
+		c.runAPITokenRefreshTimes.Delete(key)
+		// wait for the abort handlers, they are invoked asynchronously
+		if done, err := c.invokeAbortHandlers(ctx, pipelineRun); err != nil || !done {
+			return err
+		}
+		if c.provenanceBuilderID != "" && pipelineRun.GetStatus().Result == api.ResultSuccess {
+			if err := c.attestProvenance(ctx, pipelineRun); err != nil {
+				c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonProvenanceFailed, err.Error())
+			}
+		}
+		// a failing log archive must not keep run namespaces forever
+		if err := c.archiveLog(ctx, pipelineRun); err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonLogArchiveFailed, err.Error())
+		}
 		err = runManager.Cleanup(ctx, pipelineRun)
 		if err != nil {
 			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
 		}
+		// a status that cannot be offloaded is kept as it is
+		if err := c.offloadStatus(ctx, pipelineRun); err != nil {
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonStatusOffloadFailed, err.Error())
+		}
 		if err := c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateFinished, metav1.Now()); err != nil {
 			return err
 		}
+		c.recordRunStats(ctx, pipelineRun)
+		if c.maxParallelRuns > 0 {
+			c.enqueueQueuedPipelineRuns(metav1.NamespaceAll)
+		} else {
+			c.enqueueQueuedPipelineRuns(pipelineRun.GetNamespace())
+		}
 		return pipelineRun.DeleteFinalizerIfExists(ctx)
 	default:
 		klog.V(2).Infof("Skip PipelineRun with state %s", pipelineRun.GetStatus().State)
@@ -477,6 +1088,7 @@ func (c *Controller) updateStateAndResult(ctx context.Context, pipelineRun k8s.P
 		return err
 	}
 	metrics.PipelineRunsResult.Observe(pipelineRun.GetStatus().Result)
+	c.recordResult(pipelineRun)
 	if state == api.StateFinished {
 		return pipelineRun.DeleteFinalizerIfExists(ctx)
 	}
@@ -493,13 +1105,50 @@ func (c *Controller) commitStatusAndMeter(ctx context.Context, pipelineRun k8s.P
 	end := time.Now()
 	elapsed := end.Sub(start)
 	klog.V(6).Infof("commit of %q took %v", pipelineRun.String(), elapsed)
+	c.statusCoalescer.Committed(pipelineRun.GetKey())
 	metrics.UpdatesLatency.Observe("UpdateState", elapsed)
 	for _, finishedState := range finishedStates {
 		metrics.PipelineRunsStateFinished.Observe(finishedState)
+		metrics.PipelineRunsPhaseDuration.Observe(finishedState)
 	}
+	c.recordStateTransitions(pipelineRun, finishedStates)
 	return nil
 }
 
+// recordStateTransitions emits an event for each state transition of
+// the given pipeline run that has just been committed. The given state
+// items are the states left, in chronological order.
+func (c *Controller) recordStateTransitions(pipelineRun k8s.PipelineRun, finishedStates []*api.StateItem) {
+	status := pipelineRun.GetStatus()
+	for i, finishedState := range finishedStates {
+		newState := status.State
+		if i+1 < len(finishedStates) {
+			newState = finishedStates[i+1].State
+		}
+		message := fmt.Sprintf("state changed from %q to %q", finishedState.State, newState)
+		if (newState == api.StateCleaning || newState == api.StateFinished) && status.Result != api.ResultUndefined {
+			message = fmt.Sprintf("%s with result %q", message, status.Result)
+		}
+		c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, api.EventReasonStateChanged, message)
+	}
+}
+
+// recordResult emits an event if the given pipeline run has been
+// aborted or timed out.
+func (c *Controller) recordResult(pipelineRun k8s.PipelineRun) {
+	status := pipelineRun.GetStatus()
+	switch status.Result {
+	case api.ResultAborted:
+		c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, api.EventReasonAborted, "pipeline run aborted")
+	case api.ResultTimeout:
+		message := "pipeline run timed out"
+		if status.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, status.Message)
+		}
+		c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeWarning, api.EventReasonTimeout, message)
+	}
+}
+
 // handleAborted checks if pipeline run should be aborted.
 // If the user requested abortion it updates message, result and state
 // to trigger a cleanup.
@@ -521,6 +1170,9 @@ func (c *Controller) addPipelineRun(obj interface{}) {
 	}
 	klog.V(4).Infof("Add to workqueue '%s'", key)
 	c.workqueue.Add(key)
+	if pipelineRun, ok := obj.(*api.PipelineRun); ok {
+		c.enqueueTimelineExport(key, pipelineRun)
+	}
 }
 
 // handleTektonTaskRun takes any resource implementing metav1.Object and attempts
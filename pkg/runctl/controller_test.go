@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
@@ -14,8 +15,11 @@ import (
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	metricstesting "github.com/SAP/stewardci-core/pkg/runctl/metrics/testing"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/benbjohnson/clock"
 	gomock "github.com/golang/mock/gomock"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	assert "gotest.tools/assert"
@@ -25,7 +29,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
 )
 
@@ -44,14 +50,15 @@ func Test_meterAllPipelineRunsPeriodic(t *testing.T) {
 		fake.ClusterRole(string(runClusterRoleName)),
 	)
 	c := NewController(cf, ControllerOpts{})
+	pipelineRunStore := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetStore()
 
 	run := fake.PipelineRun("r1", "ns1", api.PipelineSpec{})
-	c.pipelineRunStore.Add(run)
+	pipelineRunStore.Add(run)
 
 	deletedRun := fake.PipelineRun("r2", "ns1", api.PipelineSpec{})
 	now := metav1.Now()
 	deletedRun.SetDeletionTimestamp(&now)
-	c.pipelineRunStore.Add(deletedRun)
+	pipelineRunStore.Add(deletedRun)
 
 	// VERIFY
 	mockMetric.EXPECT().Observe(run).Times(1)
@@ -179,7 +186,7 @@ func newController(runs ...*api.PipelineRun) (*Controller, *fake.ClientFactory)
 	}
 	controller := NewController(cf, ControllerOpts{})
 	controller.pipelineRunFetcher = k8s.NewClientBasedPipelineRunFetcher(client)
-	controller.recorder = record.NewFakeRecorder(20)
+	controller.recorder = record.NewFakeRecorder(100)
 	return controller, cf
 }
 
@@ -795,6 +802,91 @@ func Test_Controller_syncHandler_initiatesRetrying_on500DuringPipelineRunFetch(t
 	assert.ErrorContains(t, err, message)
 }
 
+func Test_Controller_syncHandlerRecovering_ConvertsPanicToError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cf := newFakeClientFactory()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*api.PipelineRun, error) {
+			panic("panic1")
+		})
+
+	examinee := NewController(cf, ControllerOpts{})
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+
+	// EXERCISE
+	err := examinee.syncHandlerRecovering("foo/bar")
+
+	// VERIFY
+	assert.Error(t, err, "recovered from panic: panic1")
+}
+
+func Test_Controller_processNextWorkItem_requeues_onPanic(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cf := newFakeClientFactory()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(ctx, gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*api.PipelineRun, error) {
+			panic("panic1")
+		})
+
+	examinee := NewController(cf, ControllerOpts{})
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	examinee.workqueue.Add("foo/bar")
+
+	// EXERCISE
+	result := examinee.processNextWorkItem()
+
+	// VERIFY
+	assert.Assert(t, result == true)
+	assert.Equal(t, 1, examinee.workqueue.NumRequeues("foo/bar"))
+}
+
+func Test_Controller_processNextWorkItem_dropsAfterMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cf := newFakeClientFactory()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(ctx, gomock.Any()).
+		Return(nil, fmt.Errorf("error1"))
+
+	rateLimiter := workqueue.DefaultControllerRateLimiter()
+	examinee := NewController(cf, ControllerOpts{
+		WorkqueueRateLimiter: rateLimiter,
+		MaxRetries:           1,
+	})
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	rateLimiter.When("foo/bar") // first retry
+	examinee.workqueue.Add("foo/bar")
+
+	// EXERCISE
+	result := examinee.processNextWorkItem()
+
+	// VERIFY
+	assert.Assert(t, result == true)
+	assert.Equal(t, 0, examinee.workqueue.NumRequeues("foo/bar"))
+}
+
 func Test_Controller_syncHandler_OnTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -876,6 +968,98 @@ func Test_Controller_syncHandler_OnTimeout(t *testing.T) {
 	assert.Equal(t, "message from Succeeded condition", status.Message)
 }
 
+func Test_Controller_commitStatusAndMeter_RecordsStateTransitions(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	recorder := record.NewFakeRecorder(20)
+	controller.recorder = recorder
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+	assert.NilError(t, pipelineRun.UpdateState(api.StatePreparing, metav1.Now()))
+	assert.NilError(t, pipelineRun.UpdateState(api.StateWaiting, metav1.Now()))
+
+	// EXERCISE
+	err := controller.commitStatusAndMeter(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(recorder.Events))
+	assert.Equal(t, `Normal StateChanged state changed from "new" to "preparing"`, <-recorder.Events)
+	assert.Equal(t, `Normal StateChanged state changed from "preparing" to "waiting"`, <-recorder.Events)
+
+	// EXERCISE again without state change
+	err = controller.commitStatusAndMeter(ctx, pipelineRun)
+
+	// VERIFY no further event
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func Test_Controller_updateStateAndResult_RecordsEvents(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		result         api.Result
+		message        string
+		expectedEvents []string
+	}{
+		{
+			name:   "success",
+			result: api.ResultSuccess,
+			expectedEvents: []string{
+				`Normal StateChanged state changed from "running" to "cleaning" with result "success"`,
+			},
+		},
+		{
+			name:   "aborted",
+			result: api.ResultAborted,
+			expectedEvents: []string{
+				`Normal StateChanged state changed from "running" to "cleaning" with result "aborted"`,
+				"Normal Aborted pipeline run aborted",
+			},
+		},
+		{
+			name:    "timeout",
+			result:  api.ResultTimeout,
+			message: "message1",
+			expectedEvents: []string{
+				`Normal StateChanged state changed from "running" to "cleaning" with result "timeout"`,
+				"Warning Timeout pipeline run timed out: message1",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+			run.Status.State = api.StateRunning
+			run.Status.StateDetails = api.StateItem{State: api.StateRunning}
+			controller, cf := newController(run)
+			recorder := record.NewFakeRecorder(20)
+			controller.recorder = recorder
+			pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+			if tc.message != "" {
+				pipelineRun.UpdateMessage(tc.message)
+			}
+
+			// EXERCISE
+			err := controller.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, tc.result, metav1.Now())
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, len(tc.expectedEvents), len(recorder.Events))
+			for _, expected := range tc.expectedEvents {
+				assert.Equal(t, expected, <-recorder.Events)
+			}
+		})
+	}
+}
+
 func newTestRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
 	runManager := newRunManager(workFactory, secretProvider)
 	runManager.testing = &runManagerTesting{
@@ -966,7 +1150,7 @@ func updateRun(t *testing.T, run *api.PipelineRun, namespace string, cf *fake.Cl
 func getTektonTaskRun(t *testing.T, namespace string, cf *fake.ClientFactory) *tekton.TaskRun {
 	t.Helper()
 	ctx := context.Background()
-	taskRun, err := cf.TektonV1beta1().TaskRuns(namespace).Get(ctx, tektonTaskRunName, metav1.GetOptions{})
+	taskRun, err := cf.TektonV1beta1().TaskRuns(namespace).Get(ctx, naming.JenkinsfileRunnerTaskRun, metav1.GetOptions{})
 	if err != nil {
 		t.Fatalf("could not get Tekton task run: %s", err.Error())
 	}
@@ -993,8 +1177,157 @@ func newFakeClientFactory(objects ...runtime.Object) *fake.ClientFactory {
 	return cf
 }
 
+func Test_Controller_syncHandler_CoalescesContainerUpdates(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		elapsed           time.Duration
+		finished          bool
+		expectedState     api.State
+		expectedContainer corev1.ContainerState
+	}{
+		{
+			name:              "within interval",
+			elapsed:           3 * time.Second,
+			expectedState:     api.StateRunning,
+			expectedContainer: corev1.ContainerState{},
+		},
+		{
+			name:          "interval passed",
+			elapsed:       10 * time.Second,
+			expectedState: api.StateRunning,
+			expectedContainer: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		},
+		{
+			name:          "state change within interval",
+			elapsed:       3 * time.Second,
+			finished:      true,
+			expectedState: api.StateCleaning,
+			expectedContainer: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			run.Status = api.PipelineStatus{
+				State: api.StateRunning,
+			}
+			controller, cf := newController(run)
+			mockClock := clock.NewMock()
+			controller.statusCoalescer = newStatusCoalescer(10*time.Second, mockClock)
+			controller.statusCoalescer.Committed("ns1/foo")
+			mockClock.Add(tc.elapsed)
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runmock := runmocks.NewMockRun(mockCtrl)
+			runmock.EXPECT().GetContainerInfo().Return(
+				&corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{},
+				})
+			if tc.finished {
+				now := metav1.Now()
+				runmock.EXPECT().IsFinished().Return(true, api.ResultSuccess)
+				runmock.EXPECT().GetCompletionTime().Return(&now)
+				runmock.EXPECT().GetMessage()
+			} else {
+				runmock.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+			}
+			runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+			controller.testing = &controllerTesting{
+				createRunManagerStub:       runManager,
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+			}
+
+			// EXERCISE
+			err := controller.syncHandler("ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, err)
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+			assert.DeepEqual(t, tc.expectedContainer, result.Status.Container)
+		})
+	}
+}
+
 func newIsMaintenanceModeStub(maintenanceMode bool, err error) func(ctx context.Context) (bool, error) {
 	return func(ctx context.Context) (bool, error) {
 		return maintenanceMode, err
 	}
 }
+
+func Test_Controller_watchTaskRuns(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &Controller{
+		taskRunInformers: k8s.NewRunNamespaceTaskRunInformers(fake.NewClientFactory(), cache.ResourceEventHandlerFuncs{}),
+	}
+	defer examinee.taskRunInformers.UnwatchAll()
+	pipelineRun := &api.PipelineRun{
+		Status: api.PipelineStatus{
+			State:     api.StateRunning,
+			Namespace: "runNamespace1",
+		},
+	}
+
+	// EXERCISE
+	examinee.watchTaskRuns(pipelineRun, "ns1/run1")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"runNamespace1"}, examinee.taskRunInformers.Namespaces())
+
+	// EXERCISE
+	pipelineRun.Status.State = api.StateFinished
+	examinee.watchTaskRuns(pipelineRun, "ns1/run1")
+
+	// VERIFY
+	assert.DeepEqual(t, []string{}, examinee.taskRunInformers.Namespaces())
+}
+
+func Test_Controller_syncHandler_NotExisting_UnwatchesTaskRuns(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, cf := newController()
+	examinee.taskRunInformers = k8s.NewRunNamespaceTaskRunInformers(cf, cache.ResourceEventHandlerFuncs{})
+	defer examinee.taskRunInformers.UnwatchAll()
+	examinee.taskRunInformers.Watch("runNamespace1", "ns1/run1")
+	examinee.taskRunInformers.Watch("runNamespace2", "ns1/run2")
+
+	// EXERCISE
+	err := examinee.syncHandler("ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"runNamespace2"}, examinee.taskRunInformers.Namespaces())
+}
+
+func Test_NewController_StatusMessageTruncation(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	truncation := k8s.MessageTruncation{
+		MaxLength: 10,
+		Strategy:  utils.TruncationKeepTail,
+		Marker:    "[...]",
+	}
+
+	// EXERCISE
+	examinee := NewController(newFakeClientFactory(), ControllerOpts{StatusMessageTruncation: truncation})
+
+	// VERIFY
+	assert.DeepEqual(t, k8s.PipelineRunOpts{MessageTruncation: truncation}, examinee.pipelineRunOpts)
+}
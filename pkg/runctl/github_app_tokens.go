@@ -0,0 +1,129 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// gitHubAppTokenRefreshBefore is the remaining lifetime of GitHub App
+	// installation tokens in run namespaces below which they are
+	// refreshed. It must be less than githubapp.MinTokenLifetime, so
+	// that refreshing does not return the cached token again.
+	gitHubAppTokenRefreshBefore = 15 * time.Minute
+
+	// gitHubAppTokenRetryInterval is the interval in which refreshing
+	// GitHub App installation tokens is retried after failures.
+	gitHubAppTokenRetryInterval = time.Minute
+)
+
+// withGitHubAppTokens returns the given secret provider of a tenant
+// namespace, replacing GitHub App secrets by installation tokens if
+// GitHub App token minting is enabled.
+func (c *Controller) withGitHubAppTokens(secretProvider secrets.SecretProvider) secrets.SecretProvider {
+	if c.gitHubAppTokenMinter == nil {
+		return secretProvider
+	}
+	return githubapp.NewSecretProvider(secretProvider, c.gitHubAppTokenMinter)
+}
+
+// refreshGitHubAppTokensIfDue refreshes the GitHub App installation
+// tokens in the run namespace of the given running pipeline run if one
+// of them is about to expire. The pipeline run is requeued for the next
+// refresh, so that tokens are refreshed even if the pipeline run does not
+// change. Failures are reported as event and retried.
+func (c *Controller) refreshGitHubAppTokensIfDue(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, key string, now time.Time) {
+	if c.gitHubAppTokenMinter == nil {
+		return
+	}
+	if next, ok := c.gitHubAppTokenRefreshTimes.Load(key); ok && now.Before(next.(time.Time)) {
+		return
+	}
+	next, err := c.refreshGitHubAppTokens(ctx, pipelineRun, now)
+	if err != nil {
+		klog.Errorf("Failed to refresh GitHub App installation tokens of [%s]: %s", pipelineRun.String(), err.Error())
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonGitHubAppTokenRefreshFailed, err.Error())
+		next = now.Add(gitHubAppTokenRetryInterval)
+	}
+	c.gitHubAppTokenRefreshTimes.Store(key, next)
+	if !next.IsZero() {
+		c.workqueue.AddAfter(key, next.Sub(now))
+	}
+}
+
+// refreshGitHubAppTokens mints new installation tokens for the secrets
+// labelled with LabelGitHubAppToken in the run namespace of the given
+// pipeline run whose token expires within gitHubAppTokenRefreshBefore.
+// It returns the time at which the next refresh is due or the zero time
+// if there are no such secrets.
+func (c *Controller) refreshGitHubAppTokens(ctx context.Context, pipelineRun k8s.PipelineRun, now time.Time) (time.Time, error) {
+	runNamespace := pipelineRun.GetRunNamespace()
+	if runNamespace == "" {
+		return time.Time{}, nil
+	}
+	secretsClient := c.factory.CoreV1().Secrets(runNamespace)
+	list, err := secretsClient.List(ctx, metav1.ListOptions{LabelSelector: api.LabelGitHubAppToken})
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to list secrets")
+	}
+
+	var next time.Time
+	secretProvider := c.secretProvider(pipelineRun)
+	for i := range list.Items {
+		secret := &list.Items[i]
+		expiresAt, err := time.Parse(time.RFC3339, secret.GetAnnotations()[api.AnnotationGitHubAppTokenExpiresAt])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("secret %q: invalid value of annotation %q", secret.GetName(), api.AnnotationGitHubAppTokenExpiresAt)
+		}
+		if expiresAt.Sub(now) > gitHubAppTokenRefreshBefore {
+			next = earliest(next, expiresAt.Add(-gitHubAppTokenRefreshBefore))
+			continue
+		}
+
+		appSecretName := secret.GetAnnotations()[api.AnnotationGitHubAppSecret]
+		tokenSecret, err := secretProvider.GetSecret(ctx, appSecretName)
+		if err != nil {
+			return time.Time{}, errors.WithMessagef(err, "failed to refresh secret %q", secret.GetName())
+		}
+		if tokenSecret == nil {
+			return time.Time{}, fmt.Errorf("failed to refresh secret %q: GitHub App secret %q not found", secret.GetName(), appSecretName)
+		}
+		_, token := secrets.RepoCredentials(tokenSecret)
+		annotation := tokenSecret.GetAnnotations()[api.AnnotationGitHubAppTokenExpiresAt]
+		if secret.Type == corev1.SecretTypeBasicAuth {
+			secret.Data[corev1.BasicAuthPasswordKey] = []byte(token)
+		} else {
+			secret.Data[secrets.TokenKey] = []byte(token)
+		}
+		metav1.SetMetaDataAnnotation(&secret.ObjectMeta, api.AnnotationGitHubAppTokenExpiresAt, annotation)
+		if _, err := secretsClient.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return time.Time{}, errors.Wrapf(err, "failed to update secret %q", secret.GetName())
+		}
+		klog.V(3).Infof("Refreshed GitHub App installation token in secret %q [%s]", secret.GetName(), pipelineRun.String())
+
+		expiresAt, err = time.Parse(time.RFC3339, annotation)
+		if err != nil {
+			return time.Time{}, err
+		}
+		next = earliest(next, expiresAt.Add(-gitHubAppTokenRefreshBefore))
+	}
+	return next, nil
+}
+
+// earliest returns the earlier of the given times, ignoring zero times.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,134 @@
+package runctl
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newGitHubAppTokenTestSetup(t *testing.T, tokenExpiresIn time.Duration) (*Controller, *fake.ClientFactory, k8s.PipelineRun, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	newExpiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"newToken","expires_at":%q}`, newExpiry.Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "runns1"
+	controller, cf := newController(run)
+	controller.gitHubAppTokenMinter = githubapp.NewMinter(server.Client(), []string{server.URL})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	appSecret := fake.SecretWithType("app1", "ns1", githubapp.SecretType)
+	appSecret.Data = map[string][]byte{
+		githubapp.AppIDKey:          []byte("123"),
+		githubapp.InstallationIDKey: []byte("456"),
+		githubapp.PrivateKeyKey:     pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		githubapp.APIURLKey:         []byte(server.URL),
+	}
+	_, err = cf.CoreV1().Secrets("ns1").Create(ctx, appSecret, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	tokenSecret := fake.SecretWithType("app1-abcde", "runns1", corev1.SecretTypeBasicAuth)
+	tokenSecret.SetLabels(map[string]string{api.LabelGitHubAppToken: ""})
+	tokenSecret.SetAnnotations(map[string]string{
+		api.AnnotationGitHubAppSecret:         "app1",
+		api.AnnotationGitHubAppTokenExpiresAt: time.Now().Add(tokenExpiresIn).UTC().Format(time.RFC3339),
+	})
+	tokenSecret.Data = map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte("x-access-token"),
+		corev1.BasicAuthPasswordKey: []byte("oldToken"),
+	}
+	_, err = cf.CoreV1().Secrets("runns1").Create(ctx, tokenSecret, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+	assert.NilError(t, err)
+	return controller, cf, pipelineRun, newExpiry
+}
+
+func Test_Controller_refreshGitHubAppTokens_Expiring(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, newExpiry := newGitHubAppTokenTestSetup(t, 10*time.Minute)
+
+	// EXERCISE
+	next, err := examinee.refreshGitHubAppTokens(ctx, pipelineRun, time.Now())
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, newExpiry.Add(-gitHubAppTokenRefreshBefore).Equal(next))
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, "app1-abcde", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "newToken", string(secret.Data[corev1.BasicAuthPasswordKey]))
+	assert.Equal(t, "x-access-token", string(secret.Data[corev1.BasicAuthUsernameKey]))
+	assert.Equal(t, newExpiry.Format(time.RFC3339), secret.GetAnnotations()[api.AnnotationGitHubAppTokenExpiresAt])
+}
+
+func Test_Controller_refreshGitHubAppTokens_NotDue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, _ := newGitHubAppTokenTestSetup(t, 40*time.Minute)
+	now := time.Now()
+
+	// EXERCISE
+	next, err := examinee.refreshGitHubAppTokens(ctx, pipelineRun, now)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, next.After(now.Add(24*time.Minute)))
+	assert.Assert(t, next.Before(now.Add(26*time.Minute)))
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, "app1-abcde", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "oldToken", string(secret.Data[corev1.BasicAuthPasswordKey]))
+}
+
+func Test_Controller_refreshGitHubAppTokens_AppSecretMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, _ := newGitHubAppTokenTestSetup(t, 10*time.Minute)
+	err := cf.CoreV1().Secrets("ns1").Delete(ctx, "app1", metav1.DeleteOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.refreshGitHubAppTokens(ctx, pipelineRun, time.Now())
+
+	// VERIFY
+	assert.Error(t, err, `failed to refresh secret "app1-abcde": GitHub App secret "app1" not found`)
+}
+
+func Test_earliest(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Unix(1000, 0)
+	t2 := time.Unix(2000, 0)
+	assert.Equal(t, t1, earliest(t1, t2))
+	assert.Equal(t, t1, earliest(t2, t1))
+	assert.Equal(t, t1, earliest(time.Time{}, t1))
+	assert.Equal(t, t1, earliest(t1, time.Time{}))
+}
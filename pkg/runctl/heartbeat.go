@@ -0,0 +1,45 @@
+package runctl
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// lastWriteTime returns the time of the latest write to the given config
+// map as recorded by the API server in the managed fields, or its
+// creation time if there are no managed fields.
+// The time is taken from the API server instead of the entries of the
+// config map, so that skewed clocks of pipeline run pods do not matter.
+func lastWriteTime(configMap *corev1.ConfigMap) time.Time {
+	latest := configMap.GetCreationTimestamp().Time
+	for _, entry := range configMap.GetManagedFields() {
+		if entry.Time != nil && entry.Time.After(latest) {
+			latest = entry.Time.Time
+		}
+	}
+	return latest
+}
+
+// runnerSilence returns for how long the Jenkinsfile Runner step of a
+// running pipeline run has not sent a heartbeat and whether this exceeds
+// the configured maximum silence.
+// The step sends heartbeats by updating the progress config map, so the
+// time of the latest write to it counts as latest heartbeat. Until the
+// first heartbeat the silence is measured from the time the pipeline run
+// started running, i.e. runners which never send a heartbeat are
+// considered silent, too. progressConfigMap is nil if the config map does
+// not exist.
+func (c *Controller) runnerSilence(progressConfigMap *corev1.ConfigMap, runningSince, now time.Time) (time.Duration, bool) {
+	if c.runnerHeartbeatMaxSilence <= 0 {
+		return 0, false
+	}
+	latest := runningSince
+	if progressConfigMap != nil {
+		if heartbeat := lastWriteTime(progressConfigMap); heartbeat.After(latest) {
+			latest = heartbeat
+		}
+	}
+	silence := now.Sub(latest)
+	return silence, silence > c.runnerHeartbeatMaxSilence
+}
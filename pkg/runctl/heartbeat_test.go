@@ -0,0 +1,178 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newHeartbeatTestConfigMap(created time.Time, writes ...time.Time) *corev1.ConfigMap {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              naming.ProgressConfigMap,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	for _, write := range writes {
+		writeTime := metav1.NewTime(write)
+		configMap.ManagedFields = append(configMap.ManagedFields, metav1.ManagedFieldsEntry{
+			Manager: "manager1",
+			Time:    &writeTime,
+		})
+	}
+	return configMap
+}
+
+func Test_lastWriteTime(t *testing.T) {
+	t.Parallel()
+
+	t1 := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+
+	for _, tc := range []struct {
+		name      string
+		configMap *corev1.ConfigMap
+		expected  time.Time
+	}{
+		{"no managed fields", newHeartbeatTestConfigMap(t1), t1},
+		{"single write", newHeartbeatTestConfigMap(t1, t2), t2},
+		{"latest write wins", newHeartbeatTestConfigMap(t1, t3, t2), t3},
+		{"entry without time", &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(t1),
+				ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "manager1"}},
+			},
+		}, t1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := lastWriteTime(tc.configMap)
+
+			// VERIFY
+			assert.Assert(t, tc.expected.Equal(result), "expected %v, got %v", tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_runnerSilence(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	runningSince := now.Add(-20 * time.Minute)
+
+	for _, tc := range []struct {
+		name             string
+		maxSilence       time.Duration
+		configMap        *corev1.ConfigMap
+		expectedSilence  time.Duration
+		expectedExceeded bool
+	}{
+		{
+			name:       "disabled",
+			maxSilence: 0,
+			configMap:  newHeartbeatTestConfigMap(now.Add(-time.Hour)),
+		},
+		{
+			name:             "no config map",
+			maxSilence:       5 * time.Minute,
+			configMap:        nil,
+			expectedSilence:  20 * time.Minute,
+			expectedExceeded: true,
+		},
+		{
+			name:             "no write since running",
+			maxSilence:       5 * time.Minute,
+			configMap:        newHeartbeatTestConfigMap(now.Add(-time.Hour), now.Add(-30*time.Minute)),
+			expectedSilence:  20 * time.Minute,
+			expectedExceeded: true,
+		},
+		{
+			name:            "within limit",
+			maxSilence:      5 * time.Minute,
+			configMap:       newHeartbeatTestConfigMap(now.Add(-time.Hour), now.Add(-3*time.Minute)),
+			expectedSilence: 3 * time.Minute,
+		},
+		{
+			name:            "at limit",
+			maxSilence:      5 * time.Minute,
+			configMap:       newHeartbeatTestConfigMap(now.Add(-5 * time.Minute)),
+			expectedSilence: 5 * time.Minute,
+		},
+		{
+			name:             "exceeded",
+			maxSilence:       5 * time.Minute,
+			configMap:        newHeartbeatTestConfigMap(now.Add(-time.Hour), now.Add(-10*time.Minute)),
+			expectedSilence:  10 * time.Minute,
+			expectedExceeded: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &Controller{runnerHeartbeatMaxSilence: tc.maxSilence}
+
+			// EXERCISE
+			silence, exceeded := examinee.runnerSilence(tc.configMap, runningSince, now)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedSilence, silence)
+			assert.Equal(t, tc.expectedExceeded, exceeded)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_AbortsSilentRunner(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	run.Status = api.PipelineStatus{
+		State:     api.StateRunning,
+		Namespace: "run1",
+		StateDetails: api.StateItem{
+			State:     api.StateRunning,
+			StartedAt: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}
+	controller, cf := newController(run)
+	controller.runnerHeartbeatMaxSilence = 5 * time.Minute
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runmock := runmocks.NewMockRun(mockCtrl)
+	runmock.EXPECT().GetContainerInfo().Return(nil)
+	runmock.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	err := controller.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateCleaning, result.Status.State)
+	assert.Equal(t, api.ResultTimeout, result.Status.Result)
+	assert.Assert(t, is.Contains(result.Status.Message, "the Jenkinsfile Runner did not send a heartbeat for 1h0m0s"))
+}
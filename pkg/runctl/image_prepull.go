@@ -0,0 +1,79 @@
+package runctl
+
+import (
+	"context"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/imagepolicy"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// imagePrepullInterval is the interval in which the prepulled images
+	// are synchronized with the configured Jenkinsfile Runner image.
+	imagePrepullInterval = 5 * time.Minute
+
+	// imagePrepullTimeout is the maximum duration of a single
+	// synchronization of the prepulled images.
+	imagePrepullTimeout = 1 * time.Minute
+
+	// jenkinsfileRunnerImageParam is the name of the parameter of the
+	// Tekton ClusterTask defining the Jenkinsfile Runner image.
+	jenkinsfileRunnerImageParam = "JFR_IMAGE"
+)
+
+// syncImagePrepull lets the image prepull manager pull the current
+// Jenkinsfile Runner image on the nodes running pipeline runs.
+// Failures are logged only, as prepulling just speeds up the start of
+// pipeline runs.
+func (c *Controller) syncImagePrepull() {
+	ctx, cancel := context.WithTimeout(context.Background(), imagePrepullTimeout)
+	defer cancel()
+
+	image, err := c.defaultJenkinsfileRunnerImage(ctx)
+	if err != nil {
+		klog.Warningf("failed to determine the Jenkinsfile Runner image to prepull: %s", err.Error())
+		return
+	}
+	var images []string
+	if image != "" {
+		images = append(images, image)
+	}
+	if err := c.imagePrepull.Sync(ctx, images); err != nil {
+		klog.Warningf("failed to prepull the Jenkinsfile Runner image: %s", err.Error())
+	}
+}
+
+// defaultJenkinsfileRunnerImage returns the Jenkinsfile Runner image used
+// for pipeline runs which do not define their own image, i.e. the image
+// of the pipeline runs configuration or, if not configured, the default
+// of the Tekton ClusterTask. In an air-gapped installation the image of
+// the mirror registry is returned.
+func (c *Controller) defaultJenkinsfileRunnerImage(ctx context.Context) (string, error) {
+	pipelineRunsConfig, err := c.loadPipelineRunsConfig(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to load configuration for pipeline runs")
+	}
+	image := pipelineRunsConfig.JenkinsfileRunnerImage
+	if image == "" {
+		clusterTask, err := c.factory.TektonV1beta1().ClusterTasks().Get(ctx, tektonClusterTaskName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return "", nil
+			}
+			return "", errors.Wrapf(err, "failed to get Tekton ClusterTask %q", tektonClusterTaskName)
+		}
+		for _, param := range clusterTask.Spec.Params {
+			if param.Name == jenkinsfileRunnerImageParam && param.Default != nil {
+				image = param.Default.StringVal
+			}
+		}
+	}
+	if pipelineRunsConfig.AirGapped && image != "" {
+		image = imagepolicy.Mirror(image, pipelineRunsConfig.AirGappedMirrorRegistry)
+	}
+	return image, nil
+}
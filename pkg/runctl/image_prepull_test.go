@@ -0,0 +1,96 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/prepull"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_defaultJenkinsfileRunnerImage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		pipelineRunsConfig cfg.PipelineRunsConfigStruct
+		clusterTaskDefault string
+		expectedImage      string
+	}{
+		{
+			name:               "configured",
+			pipelineRunsConfig: cfg.PipelineRunsConfigStruct{JenkinsfileRunnerImage: "registry.example.com/jfr:2"},
+			clusterTaskDefault: "registry.example.com/jfr:1",
+			expectedImage:      "registry.example.com/jfr:2",
+		},
+		{
+			name:               "cluster_task_default",
+			clusterTaskDefault: "registry.example.com/jfr:1",
+			expectedImage:      "registry.example.com/jfr:1",
+		},
+		{
+			name: "air_gapped",
+			pipelineRunsConfig: cfg.PipelineRunsConfigStruct{
+				AirGapped:               true,
+				AirGappedMirrorRegistry: "mirror.example.com",
+			},
+			clusterTaskDefault: "registry.example.com/jfr:1",
+			expectedImage:      "mirror.example.com/jfr:1",
+		},
+		{
+			name:          "no_default",
+			expectedImage: "",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			examinee, cf := newController()
+			examinee.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &tc.pipelineRunsConfig, nil
+				},
+			}
+			clusterTask := newImagePolicyTestClusterTask()
+			if tc.clusterTaskDefault != "" {
+				clusterTask.Spec.Params[0].Default = tektonv1beta1.NewArrayOrString(tc.clusterTaskDefault)
+			}
+			_, err := cf.TektonV1beta1().ClusterTasks().Create(ctx, clusterTask, metav1.CreateOptions{})
+			assert.NilError(t, err)
+
+			// EXERCISE
+			image, err := examinee.defaultJenkinsfileRunnerImage(ctx)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedImage, image)
+		})
+	}
+}
+
+func Test_Controller_syncImagePrepull(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, cf := newController()
+	examinee.testing = &controllerTesting{
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{JenkinsfileRunnerImage: "registry.example.com/jfr:2"}, nil
+		},
+	}
+	examinee.imagePrepull = prepull.NewManager(cf.AppsV1(), prepull.Options{Namespace: "steward-system"})
+
+	// EXERCISE
+	examinee.syncImagePrepull()
+
+	// VERIFY
+	daemonSet, err := cf.AppsV1().DaemonSets("steward-system").Get(context.Background(), prepull.DefaultName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "registry.example.com/jfr:2", daemonSet.Spec.Template.Spec.InitContainers[0].Image)
+}
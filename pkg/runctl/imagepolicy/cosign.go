@@ -0,0 +1,188 @@
+package imagepolicy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// defaultCosignPath is the cosign executable used if CosignVerifier
+	// has no path set. It is looked up in the `PATH`.
+	defaultCosignPath = "cosign"
+
+	// maxCosignOutputLength is the maximum number of bytes of the output
+	// of a failed cosign invocation included in the error message.
+	maxCosignOutputLength = 512
+)
+
+// ErrSignatureInvalid is returned (wrapped) by SignatureVerifier.Verify
+// if an image has no valid signature.
+var ErrSignatureInvalid = errors.New("image signature verification failed")
+
+// SignatureVerifier verifies signatures of container images.
+type SignatureVerifier interface {
+	// Resolve returns the given image reference pinned to the digest it
+	// currently refers to, e.g. `registry.example.com/jfr@sha256:...`.
+	// References pinned to a digest already are returned unchanged.
+	Resolve(ctx context.Context, image string) (string, error)
+
+	// Verify returns an error wrapping ErrSignatureInvalid if the given
+	// image reference has no signature which can be verified with the
+	// given PEM-encoded public key.
+	// Other errors are returned if verification could not be performed.
+	Verify(ctx context.Context, image, publicKey string) error
+}
+
+// CosignVerifier is a SignatureVerifier running the `cosign` CLI
+// (https://github.com/sigstore/cosign) to verify image signatures
+// stored in the image registry.
+type CosignVerifier struct {
+	// Path is the path of the cosign executable.
+	// If empty, `cosign` is looked up in the `PATH`.
+	Path string
+}
+
+// let compiler verify interface compliance
+var _ SignatureVerifier = (*CosignVerifier)(nil)
+
+// Resolve implements SignatureVerifier.
+func (v *CosignVerifier) Resolve(ctx context.Context, image string) (string, error) {
+	if isDigestReference(image) {
+		return image, nil
+	}
+	path, err := v.lookPath()
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, path, "triangulate", "--type", "digest", image)
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", errors.Errorf("failed to resolve digest of image %q: %s", image, truncateCosignOutput(exitErr.Stderr, err))
+		}
+		return "", errors.Wrapf(err, "failed to run cosign for image %q", image)
+	}
+	resolved := string(bytes.TrimSpace(output))
+	if !isDigestReference(resolved) {
+		return "", errors.Errorf("failed to resolve digest of image %q: unexpected cosign output %q", image, resolved)
+	}
+	return resolved, nil
+}
+
+// Verify implements SignatureVerifier.
+func (v *CosignVerifier) Verify(ctx context.Context, image, publicKey string) error {
+	path, err := v.lookPath()
+	if err != nil {
+		return err
+	}
+
+	dir, err := ioutil.TempDir("", "cosign")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "cosign.pub")
+	if err := ioutil.WriteFile(keyFile, []byte(publicKey), 0600); err != nil {
+		return errors.Wrap(err, "failed to write public key file")
+	}
+
+	cmd := exec.CommandContext(ctx, path, "verify", "--key", keyFile, image)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return errors.Wrapf(err, "failed to run cosign for image %q", image)
+		}
+		return errors.Wrapf(ErrSignatureInvalid, "image %q: %s", image, truncateCosignOutput(output, err))
+	}
+	return nil
+}
+
+func (v *CosignVerifier) lookPath() (string, error) {
+	path := v.Path
+	if path == "" {
+		path = defaultCosignPath
+	}
+	if _, err := exec.LookPath(path); err != nil {
+		return "", errors.Wrapf(err, "cosign executable %q not found", path)
+	}
+	return path, nil
+}
+
+// truncateCosignOutput returns the end of the given output of a failed
+// cosign invocation or the error if there is no output.
+func truncateCosignOutput(output []byte, err error) string {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return err.Error()
+	}
+	if len(output) > maxCosignOutputLength {
+		output = append([]byte("..."), output[len(output)-maxCosignOutputLength:]...)
+	}
+	return string(output)
+}
+
+// isDigestReference returns whether the given image reference is pinned
+// to a digest.
+func isDigestReference(image string) bool {
+	return strings.Contains(image, "@sha256:")
+}
+
+// CachingVerifier is a SignatureVerifier remembering successful
+// verifications of images pinned to a digest for some time, so that
+// the signatures of images used by many pipeline runs are not fetched
+// from the registry for each of them.
+// Digests are not cached, as tags may be moved at any time.
+type CachingVerifier struct {
+	verifier SignatureVerifier
+	ttl      time.Duration
+	cache    *utilcache.Expiring
+}
+
+// let compiler verify interface compliance
+var _ SignatureVerifier = (*CachingVerifier)(nil)
+
+// NewCachingVerifier returns a new CachingVerifier delegating to the
+// given verifier and remembering successful verifications for the given
+// duration.
+func NewCachingVerifier(verifier SignatureVerifier, ttl time.Duration) *CachingVerifier {
+	return &CachingVerifier{
+		verifier: verifier,
+		ttl:      ttl,
+		cache:    utilcache.NewExpiring(),
+	}
+}
+
+// Resolve implements SignatureVerifier.
+func (v *CachingVerifier) Resolve(ctx context.Context, image string) (string, error) {
+	return v.verifier.Resolve(ctx, image)
+}
+
+// Verify implements SignatureVerifier.
+// Only images pinned to a digest are cached.
+func (v *CachingVerifier) Verify(ctx context.Context, image, publicKey string) error {
+	if !isDigestReference(image) {
+		return v.verifier.Verify(ctx, image, publicKey)
+	}
+	// the cache key contains the public key, so that a changed key
+	// takes effect immediately
+	cacheKey := fmt.Sprintf("%s %x", image, sha256.Sum256([]byte(publicKey)))
+	if _, ok := v.cache.Get(cacheKey); ok {
+		return nil
+	}
+	if err := v.verifier.Verify(ctx, image, publicKey); err != nil {
+		return err
+	}
+	v.cache.Set(cacheKey, struct{}{}, v.ttl)
+	return nil
+}
@@ -0,0 +1,162 @@
+package imagepolicy
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func writeFakeCosign(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cosign")
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+content), 0700)
+	assert.NilError(t, err)
+	return path
+}
+
+func Test_CosignVerifier_Verify_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	outputFile := filepath.Join(t.TempDir(), "output")
+	cosign := writeFakeCosign(t, `
+set -e
+echo "$1 $2 $4" >"`+outputFile+`"
+cat "$3" >>"`+outputFile+`"
+`)
+	examinee := &CosignVerifier{Path: cosign}
+
+	// EXERCISE
+	err := examinee.Verify(context.Background(), "registry.example.com/jfr:1", "publicKey1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	output, err := ioutil.ReadFile(outputFile)
+	assert.NilError(t, err)
+	assert.Equal(t, "verify --key registry.example.com/jfr:1\npublicKey1", string(output))
+}
+
+func Test_CosignVerifier_Verify_Invalid(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cosign := writeFakeCosign(t, "echo 'no matching signatures' >&2\nexit 1\n")
+	examinee := &CosignVerifier{Path: cosign}
+
+	// EXERCISE
+	err := examinee.Verify(context.Background(), "registry.example.com/jfr:1", "publicKey1")
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, ErrSignatureInvalid))
+	assert.Assert(t, is.Contains(err.Error(), `image "registry.example.com/jfr:1": no matching signatures`))
+}
+
+func Test_CosignVerifier_Verify_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &CosignVerifier{Path: filepath.Join(t.TempDir(), "cosign")}
+
+	// EXERCISE
+	err := examinee.Verify(context.Background(), "registry.example.com/jfr:1", "publicKey1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, "cosign executable")
+	assert.Assert(t, !errors.Is(err, ErrSignatureInvalid))
+}
+
+func Test_CosignVerifier_Resolve_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cosign := writeFakeCosign(t, `
+[ "$1 $2 $3 $4" = "triangulate --type digest registry.example.com/jfr:1" ] || exit 2
+echo "registry.example.com/jfr@sha256:abc"
+`)
+	examinee := &CosignVerifier{Path: cosign}
+
+	// EXERCISE
+	result, err := examinee.Resolve(context.Background(), "registry.example.com/jfr:1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "registry.example.com/jfr@sha256:abc", result)
+}
+
+func Test_CosignVerifier_Resolve_PinnedAlready(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &CosignVerifier{Path: filepath.Join(t.TempDir(), "cosign")}
+
+	// EXERCISE
+	result, err := examinee.Resolve(context.Background(), "registry.example.com/jfr@sha256:abc")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "registry.example.com/jfr@sha256:abc", result)
+}
+
+func Test_CosignVerifier_Resolve_Failure(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cosign := writeFakeCosign(t, "echo 'MANIFEST_UNKNOWN' >&2\nexit 1\n")
+	examinee := &CosignVerifier{Path: cosign}
+
+	// EXERCISE
+	_, err := examinee.Resolve(context.Background(), "registry.example.com/jfr:1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to resolve digest of image "registry.example.com/jfr:1": MANIFEST_UNKNOWN`)
+	assert.Assert(t, !errors.Is(err, ErrSignatureInvalid))
+}
+
+type countingVerifier struct {
+	err   error
+	calls int
+}
+
+func (v *countingVerifier) Resolve(_ context.Context, image string) (string, error) {
+	return image, nil
+}
+
+func (v *countingVerifier) Verify(context.Context, string, string) error {
+	v.calls++
+	return v.err
+}
+
+func Test_CachingVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	delegate := &countingVerifier{}
+	examinee := NewCachingVerifier(delegate, time.Hour)
+
+	// EXERCISE + VERIFY
+	assert.NilError(t, examinee.Verify(ctx, "registry.example.com/jfr@sha256:abc", "publicKey1"))
+	assert.NilError(t, examinee.Verify(ctx, "registry.example.com/jfr@sha256:abc", "publicKey1"))
+	assert.Equal(t, 1, delegate.calls)
+
+	// other public key
+	assert.NilError(t, examinee.Verify(ctx, "registry.example.com/jfr@sha256:abc", "publicKey2"))
+	assert.Equal(t, 2, delegate.calls)
+
+	// tags are not cached
+	assert.NilError(t, examinee.Verify(ctx, "registry.example.com/jfr:1", "publicKey1"))
+	assert.NilError(t, examinee.Verify(ctx, "registry.example.com/jfr:1", "publicKey1"))
+	assert.Equal(t, 4, delegate.calls)
+
+	// failures are not cached
+	delegate.err = ErrSignatureInvalid
+	assert.Assert(t, errors.Is(examinee.Verify(ctx, "registry.example.com/jfr@sha256:def", "publicKey1"), ErrSignatureInvalid))
+	assert.Assert(t, errors.Is(examinee.Verify(ctx, "registry.example.com/jfr@sha256:def", "publicKey1"), ErrSignatureInvalid))
+	assert.Equal(t, 6, delegate.calls)
+}
@@ -0,0 +1,152 @@
+package imagepolicy
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultRegistry is the registry used by container runtimes if an
+	// image reference does not contain a registry host.
+	defaultRegistry = "docker.io"
+
+	// legacyDefaultRegistry is an alias of defaultRegistry.
+	legacyDefaultRegistry = "index.docker.io"
+
+	// officialRepositoryPrefix is the repository path prefix of official
+	// images on Docker Hub, which may be omitted in image references.
+	officialRepositoryPrefix = "library/"
+)
+
+// ErrImageNotAllowed is returned (wrapped) by Policy.Check if an image
+// is not allowed by the policy.
+var ErrImageNotAllowed = errors.New("image not allowed")
+
+// Policy defines which container images may be run.
+// An image is allowed if it is pulled from one of the allowed registries
+// or if it is referenced by one of the allowed digests.
+// If neither registries nor digests are defined, all images are allowed.
+type Policy struct {
+	// AllowedRegistries is a list of registry hosts (optionally with
+	// port), optionally followed by a repository path prefix, e.g.
+	// `registry.example.com` or `registry.example.com/team1`.
+	AllowedRegistries []string
+
+	// AllowedDigests is a list of image digests, e.g. `sha256:abc...`.
+	// Images referenced by one of these digests are allowed regardless of
+	// the registry.
+	AllowedDigests []string
+}
+
+// IsEmpty returns whether the policy allows all images.
+func (p *Policy) IsEmpty() bool {
+	return p == nil || (len(p.AllowedRegistries) == 0 && len(p.AllowedDigests) == 0)
+}
+
+// Check returns an error wrapping ErrImageNotAllowed if the given image
+// reference is not allowed by the policy.
+func (p *Policy) Check(image string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+	name, digest := splitDigest(image)
+	if digest != "" {
+		for _, allowed := range p.AllowedDigests {
+			if digest == allowed {
+				return nil
+			}
+		}
+	}
+	repository := Repository(name)
+	for _, allowed := range p.AllowedRegistries {
+		allowed = normalizeAllowedRegistry(allowed)
+		if repository == allowed || strings.HasPrefix(repository, allowed+"/") {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrImageNotAllowed,
+		"image %q is neither pulled from an allowed registry nor referenced by an allowed digest",
+		image,
+	)
+}
+
+// Registry returns the registry host (including port if any) of the
+// given container image reference.
+func Registry(image string) string {
+	i := strings.IndexRune(image, '/')
+	if i < 0 {
+		return defaultRegistry
+	}
+	first := image[:i]
+	// like the Docker reference implementation, treat the first path
+	// component as registry host only if it looks like a host name
+	if strings.ContainsAny(first, ".:") || first == "localhost" {
+		return first
+	}
+	return defaultRegistry
+}
+
+// Repository returns the normalized repository of the given image
+// reference, i.e. registry host and repository path without tag and
+// digest. Like the Docker reference implementation, references to Docker
+// Hub are normalized: `alpine`, `docker.io/alpine` and
+// `index.docker.io/library/alpine:3` all yield `docker.io/library/alpine`.
+func Repository(image string) string {
+	name, _ := splitDigest(image)
+	registry := Registry(name)
+	path := name
+	if i := strings.IndexRune(name, '/'); i >= 0 && name[:i] == registry {
+		path = name[i+1:]
+	}
+	// a colon after the last slash separates the tag
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		path = path[:i]
+	}
+	if registry == legacyDefaultRegistry {
+		registry = defaultRegistry
+	}
+	if registry == defaultRegistry && !strings.Contains(path, "/") {
+		path = officialRepositoryPrefix + path
+	}
+	return registry + "/" + path
+}
+
+// normalizeAllowedRegistry normalizes an entry of
+// Policy.AllowedRegistries for comparison with results of Repository.
+func normalizeAllowedRegistry(allowed string) string {
+	allowed = strings.TrimSuffix(allowed, "/")
+	if allowed == legacyDefaultRegistry || strings.HasPrefix(allowed, legacyDefaultRegistry+"/") {
+		allowed = defaultRegistry + strings.TrimPrefix(allowed, legacyDefaultRegistry)
+	}
+	return allowed
+}
+
+// splitDigest splits an image reference into the part before `@` and the
+// digest. The digest is empty if the reference does not contain one.
+func splitDigest(image string) (name, digest string) {
+	if i := strings.IndexRune(image, '@'); i >= 0 {
+		return image[:i], image[i+1:]
+	}
+	return image, ""
+}
+
+// HasDigest returns whether the given image reference contains a digest.
+func HasDigest(image string) bool {
+	_, digest := splitDigest(image)
+	return digest != ""
+}
+
+// Mirror returns the given image reference with its registry host replaced
+// by the given mirror registry prefix, e.g. `mirror.example.com/team1`.
+// Repository path, tag and digest are retained.
+func Mirror(image, mirrorRegistry string) string {
+	name := image
+	if i := strings.IndexRune(image, '/'); i >= 0 {
+		first := image[:i]
+		if Registry(image) == first {
+			name = image[i+1:]
+		}
+	}
+	return strings.TrimSuffix(mirrorRegistry, "/") + "/" + name
+}
@@ -0,0 +1,136 @@
+package imagepolicy
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+)
+
+const digest1 = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func Test_Registry(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		image    string
+		expected string
+	}{
+		{"alpine", "docker.io"},
+		{"alpine:3", "docker.io"},
+		{"library/alpine", "docker.io"},
+		{"docker.io/library/alpine", "docker.io"},
+		{"registry.example.com/foo/bar:1", "registry.example.com"},
+		{"registry:5000/foo", "registry:5000"},
+		{"localhost/foo", "localhost"},
+	} {
+		assert.Equal(t, tc.expected, Registry(tc.image), "image: %q", tc.image)
+	}
+}
+
+func Test_Repository(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		image    string
+		expected string
+	}{
+		{"alpine", "docker.io/library/alpine"},
+		{"alpine:3", "docker.io/library/alpine"},
+		{"library/alpine", "docker.io/library/alpine"},
+		{"docker.io/alpine", "docker.io/library/alpine"},
+		{"docker.io/library/alpine:3", "docker.io/library/alpine"},
+		{"index.docker.io/library/alpine:3", "docker.io/library/alpine"},
+		{"stewardci/jfr:1", "docker.io/stewardci/jfr"},
+		{"registry.example.com/foo/bar:1", "registry.example.com/foo/bar"},
+		{"registry.example.com/foo", "registry.example.com/foo"},
+		{"registry:5000/foo", "registry:5000/foo"},
+		{"registry:5000/foo@" + digest1, "registry:5000/foo"},
+		{"registry:5000/foo:1@" + digest1, "registry:5000/foo"},
+	} {
+		assert.Equal(t, tc.expected, Repository(tc.image), "image: %q", tc.image)
+	}
+}
+
+func Test_Policy_Check(t *testing.T) {
+	t.Parallel()
+
+	policy := &Policy{
+		AllowedRegistries: []string{"registry.example.com", "docker.io/stewardci/", "registry:5000", "index.docker.io/library/busybox"},
+		AllowedDigests:    []string{digest1},
+	}
+
+	for _, tc := range []struct {
+		image    string
+		expected bool
+	}{
+		{"registry.example.com/foo/bar:1", true},
+		{"registry.example.com:443/foo/bar:1", false},
+		{"registry:5000/foo", true},
+		{"stewardci/stewardci-jenkinsfile-runner:1", true},
+		{"docker.io/stewardci/stewardci-jenkinsfile-runner:1", true},
+		{"stewardci-other/image:1", false},
+		{"alpine", false},
+		{"busybox:1", true},
+		{"docker.io/busybox", true},
+		{"index.docker.io/library/busybox@" + digest1, true},
+		{"library/busybox-evil", false},
+		{"index.docker.io/stewardci/jfr", true},
+		{"evil.example.com/registry.example.com/foo", false},
+		{"registry.example.com.evil.example.com/foo", false},
+		{"evil.example.com/foo@" + digest1, true},
+		{"evil.example.com/foo@sha256:other", false},
+	} {
+		tc := tc
+		t.Run(tc.image, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			err := policy.Check(tc.image)
+
+			// VERIFY
+			if tc.expected {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, errors.Is(err, ErrImageNotAllowed))
+				assert.ErrorContains(t, err, tc.image)
+			}
+		})
+	}
+}
+
+func Test_Policy_Check_Empty(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []*Policy{nil, {}} {
+		assert.Assert(t, policy.IsEmpty())
+		assert.NilError(t, policy.Check("alpine"))
+	}
+}
+
+func Test_HasDigest(t *testing.T) {
+	t.Parallel()
+
+	assert.Assert(t, HasDigest("registry.example.com/foo@"+digest1))
+	assert.Assert(t, HasDigest("foo:1@"+digest1))
+	assert.Assert(t, !HasDigest("registry.example.com/foo:1"))
+	assert.Assert(t, !HasDigest("foo"))
+}
+
+func Test_Mirror(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		image    string
+		mirror   string
+		expected string
+	}{
+		{"alpine", "mirror.example.com", "mirror.example.com/alpine"},
+		{"library/alpine:3", "mirror.example.com/", "mirror.example.com/library/alpine:3"},
+		{"docker.io/library/alpine:3", "mirror.example.com", "mirror.example.com/library/alpine:3"},
+		{"registry.example.com/foo/bar:1@" + digest1, "mirror:5000/team1", "mirror:5000/team1/foo/bar:1@" + digest1},
+		{"localhost/foo", "mirror.example.com", "mirror.example.com/foo"},
+	} {
+		assert.Equal(t, tc.expected, Mirror(tc.image, tc.mirror), "image: %q", tc.image)
+	}
+}
@@ -0,0 +1,77 @@
+package runctl
+
+import (
+	"context"
+	"time"
+
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// logArchiveTimeout is the maximum duration of archiving the log of a
+	// single pipeline run.
+	logArchiveTimeout = 2 * time.Minute
+
+	// logArchiveMaxBytes is the maximum size of the log of a single
+	// pipeline run that is archived. Exceeding parts are dropped.
+	logArchiveMaxBytes = 50 * 1024 * 1024
+)
+
+// archiveLog stores the log of the Jenkinsfile Runner of the given
+// finished pipeline run in the log archive and records its location in
+// the status of the pipeline run. It must be called before the run
+// namespace is deleted.
+// Pipeline runs without run pod, e.g. pipeline runs failed before they
+// have been started, and pipeline runs with an archived log are skipped.
+func (c *Controller) archiveLog(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if c.logArchive == nil || pipelineRun.GetStatus().LogURL != "" {
+		return nil
+	}
+	runNamespace := pipelineRun.GetRunNamespace()
+	if runNamespace == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, logArchiveTimeout)
+	defer cancel()
+
+	taskRun, err := c.factory.TektonV1beta1().TaskRuns(runNamespace).Get(ctx, naming.JenkinsfileRunnerTaskRun, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get task run of pipeline run %q", pipelineRun.String())
+	}
+	podName := taskRun.Status.PodName
+	if podName == "" {
+		return nil
+	}
+	limitBytes := int64(logArchiveMaxBytes)
+	log, err := c.factory.CoreV1().Pods(runNamespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  "step-" + tektonClusterTaskJenkinsfileRunnerStep,
+		Timestamps: true,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get log of pod %q in namespace %q", podName, runNamespace)
+	}
+
+	lines := logarchive.ParseLog(log, time.Now())
+	logURL, err := c.logArchive.Store(ctx, pipelineRun.GetAPIObject(), lines)
+	if err != nil {
+		return errors.WithMessage(err, "failed to archive log")
+	}
+	klog.V(4).Infof("archived log of pipeline run %q (%d lines) at %s", pipelineRun.String(), len(lines), logURL)
+	pipelineRun.UpdateLogURL(logURL)
+	return nil
+}
@@ -0,0 +1,111 @@
+package runctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/logarchive"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeLogArchive is a log archive recording the stored logs.
+type fakeLogArchive struct {
+	lines []logarchive.Line
+	err   error
+}
+
+func (a *fakeLogArchive) Store(_ context.Context, run *api.PipelineRun, lines []logarchive.Line) (string, error) {
+	if a.err != nil {
+		return "", a.err
+	}
+	a.lines = lines
+	return "https://logs.example.com/" + run.GetName(), nil
+}
+
+func newLogArchiveTestSetup(t *testing.T, podName string) (*Controller, k8s.PipelineRun, *fakeLogArchive) {
+	t.Helper()
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "runns1"
+	controller, cf := newController(run)
+	archive := &fakeLogArchive{}
+	controller.logArchive = archive
+
+	taskRun := &tekton.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: naming.JenkinsfileRunnerTaskRun, Namespace: "runns1"}}
+	taskRun.Status.PodName = podName
+	_, err := cf.TektonV1beta1().TaskRuns("runns1").Create(ctx, taskRun, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+	assert.NilError(t, err)
+	return controller, pipelineRun, archive
+}
+
+func Test_Controller_archiveLog(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, pipelineRun, archive := newLogArchiveTestSetup(t, "pod1")
+
+	// EXERCISE
+	err := examinee.archiveLog(context.Background(), pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "https://logs.example.com/run1", pipelineRun.GetStatus().LogURL)
+	assert.Equal(t, 1, len(archive.lines))
+	assert.Equal(t, "fake logs", archive.lines[0].Text)
+}
+
+func Test_Controller_archiveLog_NoPod(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, pipelineRun, archive := newLogArchiveTestSetup(t, "")
+
+	// EXERCISE
+	err := examinee.archiveLog(context.Background(), pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "", pipelineRun.GetStatus().LogURL)
+	assert.Assert(t, archive.lines == nil)
+}
+
+func Test_Controller_archiveLog_AlreadyArchived(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, pipelineRun, archive := newLogArchiveTestSetup(t, "pod1")
+	pipelineRun.UpdateLogURL("https://logs.example.com/old")
+
+	// EXERCISE
+	err := examinee.archiveLog(context.Background(), pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "https://logs.example.com/old", pipelineRun.GetStatus().LogURL)
+	assert.Assert(t, archive.lines == nil)
+}
+
+func Test_Controller_archiveLog_StoreFails(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, pipelineRun, archive := newLogArchiveTestSetup(t, "pod1")
+	archive.err = errors.New("boom")
+
+	// EXERCISE
+	err := examinee.archiveLog(context.Background(), pipelineRun)
+
+	// VERIFY
+	assert.Error(t, err, "failed to archive log: boom")
+	assert.Equal(t, "", pipelineRun.GetStatus().LogURL)
+}
@@ -0,0 +1,389 @@
+package runctl
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// logForwardingSecretKeyIndexURL is the key of the entry of a client
+	// log forwarding secret containing the URL of the Elasticsearch index
+	// to send logs to.
+	logForwardingSecretKeyIndexURL = "indexURL"
+
+	// logForwardingSecretKeyUsername is the key of the entry of a client
+	// log forwarding secret containing the user name to authenticate to
+	// Elasticsearch.
+	logForwardingSecretKeyUsername = "username"
+
+	// logForwardingSecretKeyPassword is the key of the entry of a client
+	// log forwarding secret containing the password to authenticate to
+	// Elasticsearch.
+	logForwardingSecretKeyPassword = "password"
+
+	// logForwardingSecretKeyCABundle is the key of the entry of a log
+	// forwarding secret containing the PEM-encoded CA certificates
+	// trusted when connecting to Elasticsearch.
+	logForwardingSecretKeyCABundle = "ca.crt"
+)
+
+// logForwardingConfig is the effective configuration of the connection
+// to Elasticsearch for a pipeline run. Empty values mean that the
+// defaults of the ClusterTask apply.
+type logForwardingConfig struct {
+	indexURL   string
+	username   []byte
+	password   []byte
+	clientCert []byte
+	clientKey  []byte
+	caBundle   []byte
+}
+
+// addTektonTaskRunParamsForLogForwarding sets the Elasticsearch endpoint,
+// credentials and TLS settings for the given pipeline run.
+// The installation-wide client certificate and CA bundle are taken from
+// the secrets configured in the pipeline runs configuration.
+// Each setting can be overridden per client by a secret in the client
+// namespace referenced by annotation AnnotationLogForwardingSecret of the
+// client namespace.
+// Credentials and certificates are copied to the run namespace.
+func (c *runManager) addTektonTaskRunParamsForLogForwarding(
+	ctx context.Context,
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	if !runCtx.pipelineRun.GetSpec().Logging.HasSink() {
+		return nil
+	}
+
+	config, err := loadLogForwardingConfig(ctx, c.factory, runCtx.pipelineRunsConfig, runCtx.pipelineRun)
+	if err != nil {
+		return err
+	}
+
+	var params []tekton.Param
+	if config.indexURL != "" {
+		params = append(params,
+			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", config.indexURL),
+		)
+	}
+	if len(config.username) > 0 {
+		err := c.createLogForwardingSecret(ctx, runCtx,
+			naming.LogForwardingAuthSecret,
+			corev1api.SecretTypeBasicAuth,
+			map[string][]byte{
+				corev1api.BasicAuthUsernameKey: config.username,
+				corev1api.BasicAuthPasswordKey: config.password,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		params = append(params,
+			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET", naming.LogForwardingAuthSecret),
+		)
+	}
+	if len(config.clientCert) > 0 {
+		err := c.createLogForwardingSecret(ctx, runCtx,
+			naming.LogForwardingClientCertSecret,
+			corev1api.SecretTypeTLS,
+			map[string][]byte{
+				corev1api.TLSCertKey:       config.clientCert,
+				corev1api.TLSPrivateKeyKey: config.clientKey,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		params = append(params,
+			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_CLIENTCERT_SECRET", naming.LogForwardingClientCertSecret),
+		)
+	}
+	if len(config.caBundle) > 0 {
+		err := c.createLogForwardingSecret(ctx, runCtx,
+			naming.LogForwardingCABundleSecret,
+			corev1api.SecretTypeOpaque,
+			map[string][]byte{
+				logForwardingSecretKeyCABundle: config.caBundle,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		params = append(params,
+			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET", naming.LogForwardingCABundleSecret),
+		)
+	}
+	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
+	return nil
+}
+
+// loadLogForwardingConfig returns the effective log forwarding
+// configuration for the given pipeline run, i.e. the configuration of the
+// Steward installation overridden by the log forwarding secret of the
+// client owning the pipeline run, if any, which in turn is overridden by
+// the endpoint and index of the log sink of the pipeline run.
+func loadLogForwardingConfig(
+	ctx context.Context,
+	factory k8s.ClientFactory,
+	pipelineRunsConfig *cfg.PipelineRunsConfigStruct,
+	pipelineRun k8s.PipelineRun,
+) (*logForwardingConfig, error) {
+	config, err := getInstallationLogForwardingConfig(ctx, factory, pipelineRunsConfig)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := getClientLogForwardingSecret(ctx, factory, pipelineRun)
+	if err != nil {
+		return nil, err
+	}
+	if clientSecret != nil {
+		if err := config.mergeClientSecret(clientSecret); err != nil {
+			return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+		}
+	}
+	sink, err := pipelineRun.GetSpec().Logging.EffectiveSink()
+	if err != nil {
+		return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	if sink == nil {
+		return config, nil
+	}
+	if sink.EndpointRef != nil {
+		name := sink.EndpointRef.SecretName
+		secret, err := factory.CoreV1().Secrets(pipelineRun.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			notFound := k8serrors.IsNotFound(err)
+			err = errors.Wrapf(err,
+				"failed to get secret %q referenced by spec.logging.sink.endpointRef", name,
+			)
+			if notFound {
+				return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+			}
+			return nil, err
+		}
+		if err := config.mergeClientSecret(secret); err != nil {
+			return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+		}
+	}
+	if sink.Index != "" {
+		if err := config.setIndex(sink.Index); err != nil {
+			return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+		}
+	}
+	return config, nil
+}
+
+// setIndex replaces the index of the index URL with the given index,
+// e.g. `https://es.example.com/logs2/_doc` for index `logs2` and index URL
+// `https://es.example.com/logs/_doc`.
+func (config *logForwardingConfig) setIndex(index string) error {
+	if config.indexURL == "" {
+		return errors.Errorf(
+			"cannot set index %q of spec.logging.sink: no log forwarding endpoint is configured", index,
+		)
+	}
+	indexURL, err := url.Parse(config.indexURL)
+	if err != nil {
+		return errors.Wrap(err, "invalid log forwarding index URL")
+	}
+	indexPath := strings.TrimSuffix(indexURL.Path, "/")
+	var suffix string
+	if strings.HasSuffix(indexPath, "/_doc") {
+		indexPath = strings.TrimSuffix(indexPath, "/_doc")
+		suffix = "/_doc"
+	}
+	if i := strings.LastIndex(indexPath, "/"); i >= 0 {
+		indexPath = indexPath[:i]
+	}
+	indexURL.Path = indexPath + "/" + index + suffix
+	indexURL.RawPath = ""
+	config.indexURL = indexURL.String()
+	return nil
+}
+
+// getInstallationLogForwardingConfig returns the log forwarding
+// configuration defined by the pipeline runs configuration.
+func getInstallationLogForwardingConfig(
+	ctx context.Context,
+	factory k8s.ClientFactory,
+	pipelineRunsConfig *cfg.PipelineRunsConfigStruct,
+) (*logForwardingConfig, error) {
+	config := &logForwardingConfig{}
+	if pipelineRunsConfig == nil {
+		return config, nil
+	}
+	config.indexURL = pipelineRunsConfig.LoggingIndexURL
+	secretIfce := factory.CoreV1().Secrets(system.Namespace())
+
+	if name := pipelineRunsConfig.LoggingClientCertSecret; name != "" {
+		secret, err := secretIfce.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to get log forwarding client certificate secret %q in namespace %q",
+				name, system.Namespace(),
+			)
+		}
+		config.clientCert = secret.Data[corev1api.TLSCertKey]
+		config.clientKey = secret.Data[corev1api.TLSPrivateKeyKey]
+		if err := config.validateClientCert(); err != nil {
+			return nil, errors.Wrapf(err,
+				"invalid log forwarding client certificate secret \"%s/%s\"",
+				system.Namespace(), name,
+			)
+		}
+	}
+	if name := pipelineRunsConfig.LoggingCABundleSecret; name != "" {
+		secret, err := secretIfce.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err,
+				"failed to get log forwarding CA bundle secret %q in namespace %q",
+				name, system.Namespace(),
+			)
+		}
+		config.caBundle = secret.Data[logForwardingSecretKeyCABundle]
+	}
+	return config, nil
+}
+
+// mergeClientSecret overrides the configuration with the entries of the
+// given client log forwarding secret.
+func (config *logForwardingConfig) mergeClientSecret(secret *corev1api.Secret) error {
+	wrap := func(err error) error {
+		return errors.Wrapf(err,
+			"invalid log forwarding secret \"%s/%s\"",
+			secret.GetNamespace(), secret.GetName(),
+		)
+	}
+
+	if indexURL := strings.TrimSpace(string(secret.Data[logForwardingSecretKeyIndexURL])); indexURL != "" {
+		if _, err := ensureValidElasticsearchIndexURL(indexURL); err != nil {
+			return wrap(errors.Wrapf(err, "entry %q has an invalid value", logForwardingSecretKeyIndexURL))
+		}
+		config.indexURL = indexURL
+	}
+	if username := secret.Data[logForwardingSecretKeyUsername]; len(username) > 0 {
+		config.username = username
+		config.password = secret.Data[logForwardingSecretKeyPassword]
+	}
+	if clientCert := secret.Data[corev1api.TLSCertKey]; len(clientCert) > 0 || len(secret.Data[corev1api.TLSPrivateKeyKey]) > 0 {
+		config.clientCert = clientCert
+		config.clientKey = secret.Data[corev1api.TLSPrivateKeyKey]
+		if err := config.validateClientCert(); err != nil {
+			return wrap(err)
+		}
+	}
+	if caBundle := secret.Data[logForwardingSecretKeyCABundle]; len(caBundle) > 0 {
+		config.caBundle = caBundle
+	}
+	return nil
+}
+
+func (config *logForwardingConfig) validateClientCert() error {
+	if len(config.clientCert) == 0 {
+		return errors.Errorf("entry %q must not be empty", corev1api.TLSCertKey)
+	}
+	if len(config.clientKey) == 0 {
+		return errors.Errorf("entry %q must not be empty", corev1api.TLSPrivateKeyKey)
+	}
+	return nil
+}
+
+// createLogForwardingSecret creates a secret with the given name, type and
+// data in the run namespace.
+func (c *runManager) createLogForwardingSecret(
+	ctx context.Context,
+	runCtx *runContext,
+	name string,
+	secretType corev1api.SecretType,
+	data map[string][]byte,
+) error {
+	secret := &corev1api.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: runCtx.runNamespace,
+		},
+		Type: secretType,
+		Data: data,
+	}
+	slabels.LabelAsSystemManaged(secret)
+	slabels.LabelWithInstanceID(secret, c.factory.InstanceID())
+
+	secretIfce := c.factory.CoreV1().Secrets(runCtx.runNamespace)
+	if _, err := secretIfce.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err,
+			"failed to create log forwarding secret %q in namespace %q",
+			name, runCtx.runNamespace,
+		)
+	}
+	return nil
+}
+
+// getClientLogForwardingSecret returns the log forwarding secret of the
+// client owning the given pipeline run or nil if the client does not
+// configure one.
+// The client namespace is determined via label LabelOwnerClientNamespace
+// of the namespace of the pipeline run.
+func getClientLogForwardingSecret(ctx context.Context, factory k8s.ClientFactory, pipelineRun k8s.PipelineRun) (*corev1api.Secret, error) {
+	namespaceIfce := factory.CoreV1().Namespaces()
+
+	tenantNamespace, err := namespaceIfce.Get(ctx, pipelineRun.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err,
+			"failed to get namespace %q", pipelineRun.GetNamespace(),
+		)
+	}
+	clientNamespaceName := tenantNamespace.GetLabels()[stewardv1alpha1.LabelOwnerClientNamespace]
+	if clientNamespaceName == "" {
+		return nil, nil
+	}
+
+	clientNamespace, err := namespaceIfce.Get(ctx, clientNamespaceName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err,
+			"failed to get client namespace %q", clientNamespaceName,
+		)
+	}
+	secretName := strings.TrimSpace(clientNamespace.GetAnnotations()[stewardv1alpha1.AnnotationLogForwardingSecret])
+	if secretName == "" {
+		return nil, nil
+	}
+
+	secret, err := factory.CoreV1().Secrets(clientNamespaceName).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		err = errors.Wrapf(err,
+			"failed to get log forwarding secret %q of client namespace %q",
+			secretName, clientNamespaceName,
+		)
+		if k8serrors.IsNotFound(errors.Cause(err)) {
+			return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+		}
+		return nil, err
+	}
+	klog.V(4).Infof(
+		"using log forwarding secret %q of client namespace %q for pipeline run %q",
+		secretName, clientNamespaceName, pipelineRun.GetKey(),
+	)
+	return secret, nil
+}
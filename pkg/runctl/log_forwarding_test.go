@@ -0,0 +1,348 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	tektonv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test__runManager_addTektonTaskRunParamsForLogForwarding(t *testing.T) {
+	t.Parallel()
+
+	tenantNamespace := func(clientNamespace string) *corev1.Namespace {
+		namespace := k8sfake.Namespace("ns1")
+		if clientNamespace != "" {
+			namespace.SetLabels(map[string]string{
+				stewardv1alpha1.LabelOwnerClientNamespace: clientNamespace,
+			})
+		}
+		return namespace
+	}
+	clientNamespace := func(secretName string) *corev1.Namespace {
+		annotations := map[string]string{}
+		if secretName != "" {
+			annotations[stewardv1alpha1.AnnotationLogForwardingSecret] = secretName
+		}
+		return k8sfake.NamespaceWithAnnotations("client1", annotations)
+	}
+	secret := func(name, namespace string, data map[string]string) *corev1.Secret {
+		secret := k8sfake.SecretOpaque(name, namespace)
+		secret.Data = map[string][]byte{}
+		for key, value := range data {
+			secret.Data[key] = []byte(value)
+		}
+		return secret
+	}
+	clientSecret := func(data map[string]string) *corev1.Secret {
+		return secret("logs1", "client1", data)
+	}
+	loggingSpec := &stewardv1alpha1.Logging{
+		Elasticsearch: &stewardv1alpha1.Elasticsearch{},
+	}
+	tlsConfig := &cfg.PipelineRunsConfigStruct{
+		LoggingClientCertSecret: "installationCert1",
+		LoggingCABundleSecret:   "installationCA1",
+	}
+	installationSecrets := []runtime.Object{
+		secret("installationCert1", system.Namespace(), map[string]string{"tls.crt": "cert0", "tls.key": "key0"}),
+		secret("installationCA1", system.Namespace(), map[string]string{"ca.crt": "ca0"}),
+	}
+
+	for _, tc := range []struct {
+		name                string
+		logging             *stewardv1alpha1.Logging
+		config              *cfg.PipelineRunsConfigStruct
+		objects             []runtime.Object
+		expectedAddedParams []tektonv1beta1.Param
+		expectedSecrets     map[string]map[string]string
+		expectedError       string
+		expectedResult      stewardv1alpha1.Result
+	}{
+		{
+			name:    "logging_disabled",
+			config:  tlsConfig,
+			objects: append([]runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"indexURL": "https://es.example.com/logs"})}, installationSecrets...),
+		},
+		{
+			name:    "tenant_namespace_not_found",
+			logging: loggingSpec,
+		},
+		{
+			name:    "no_client_namespace_label",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("")},
+		},
+		{
+			name:    "client_namespace_not_found",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("client1")},
+		},
+		{
+			name:    "no_annotation",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("client1"), clientNamespace("")},
+		},
+		{
+			name:           "secret_not_found",
+			logging:        loggingSpec,
+			objects:        []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1")},
+			expectedError:  `failed to get log forwarding secret "logs1" of client namespace "client1": secrets "logs1" not found`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "index_url_only",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"indexURL": " https://es.example.com/logs "})},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", "https://es.example.com/logs"),
+			},
+		},
+		{
+			name:           "invalid_index_url",
+			logging:        loggingSpec,
+			objects:        []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"indexURL": "ftp://es.example.com/logs"})},
+			expectedError:  `invalid log forwarding secret "client1/logs1": entry "indexURL" has an invalid value: scheme not supported: "ftp"`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "credentials_only",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"username": "user1", "password": "pass1"})},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET", naming.LogForwardingAuthSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingAuthSecret: {"username": "user1", "password": "pass1"},
+			},
+		},
+		{
+			name:    "index_url_and_credentials",
+			logging: loggingSpec,
+			objects: []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"indexURL": "https://es.example.com/logs", "username": "user1", "password": "pass1"})},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", "https://es.example.com/logs"),
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET", naming.LogForwardingAuthSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingAuthSecret: {"username": "user1", "password": "pass1"},
+			},
+		},
+		{
+			name:    "installation_tls",
+			logging: loggingSpec,
+			config:  tlsConfig,
+			objects: append([]runtime.Object{tenantNamespace("")}, installationSecrets...),
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_CLIENTCERT_SECRET", naming.LogForwardingClientCertSecret),
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET", naming.LogForwardingCABundleSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingClientCertSecret: {"tls.crt": "cert0", "tls.key": "key0"},
+				naming.LogForwardingCABundleSecret:   {"ca.crt": "ca0"},
+			},
+		},
+		{
+			name:          "installation_client_cert_secret_not_found",
+			logging:       loggingSpec,
+			config:        tlsConfig,
+			objects:       []runtime.Object{installationSecrets[1]},
+			expectedError: fmt.Sprintf(`failed to get log forwarding client certificate secret "installationCert1" in namespace %q: secrets "installationCert1" not found`, system.Namespace()),
+		},
+		{
+			name:          "installation_client_cert_without_key",
+			logging:       loggingSpec,
+			config:        &cfg.PipelineRunsConfigStruct{LoggingClientCertSecret: "installationCert1"},
+			objects:       []runtime.Object{secret("installationCert1", system.Namespace(), map[string]string{"tls.crt": "cert0"})},
+			expectedError: fmt.Sprintf(`invalid log forwarding client certificate secret "%s/installationCert1": entry "tls.key" must not be empty`, system.Namespace()),
+		},
+		{
+			name:    "client_overrides_installation_tls",
+			logging: loggingSpec,
+			config:  tlsConfig,
+			objects: append([]runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"tls.crt": "cert1", "tls.key": "key1", "ca.crt": "ca1"})}, installationSecrets...),
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_CLIENTCERT_SECRET", naming.LogForwardingClientCertSecret),
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET", naming.LogForwardingCABundleSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingClientCertSecret: {"tls.crt": "cert1", "tls.key": "key1"},
+				naming.LogForwardingCABundleSecret:   {"ca.crt": "ca1"},
+			},
+		},
+		{
+			name:    "client_ca_bundle_only",
+			logging: loggingSpec,
+			config:  tlsConfig,
+			objects: append([]runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"ca.crt": "ca1"})}, installationSecrets...),
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_CLIENTCERT_SECRET", naming.LogForwardingClientCertSecret),
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_TRUSTEDCERTS_SECRET", naming.LogForwardingCABundleSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingClientCertSecret: {"tls.crt": "cert0", "tls.key": "key0"},
+				naming.LogForwardingCABundleSecret:   {"ca.crt": "ca1"},
+			},
+		},
+		{
+			name: "sink_endpoint_ref_overrides_client",
+			logging: &stewardv1alpha1.Logging{
+				Sink: &stewardv1alpha1.LogSink{
+					Type:        stewardv1alpha1.LogSinkTypeElasticsearch,
+					EndpointRef: &stewardv1alpha1.LogSinkEndpointRef{SecretName: "sink1"},
+				},
+			},
+			objects: []runtime.Object{
+				tenantNamespace("client1"), clientNamespace("logs1"),
+				clientSecret(map[string]string{"indexURL": "https://es.example.com/logs", "username": "user1", "password": "pass1"}),
+				secret("sink1", "ns1", map[string]string{"indexURL": "https://es2.example.com/logs2/_doc"}),
+			},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", "https://es2.example.com/logs2/_doc"),
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_AUTH_SECRET", naming.LogForwardingAuthSecret),
+			},
+			expectedSecrets: map[string]map[string]string{
+				naming.LogForwardingAuthSecret: {"username": "user1", "password": "pass1"},
+			},
+		},
+		{
+			name: "sink_endpoint_ref_not_found",
+			logging: &stewardv1alpha1.Logging{
+				Sink: &stewardv1alpha1.LogSink{
+					Type:        stewardv1alpha1.LogSinkTypeElasticsearch,
+					EndpointRef: &stewardv1alpha1.LogSinkEndpointRef{SecretName: "sink1"},
+				},
+			},
+			objects:        []runtime.Object{tenantNamespace("")},
+			expectedError:  `failed to get secret "sink1" referenced by spec.logging.sink.endpointRef: secrets "sink1" not found`,
+			expectedResult: stewardv1alpha1.ResultErrorContent,
+		},
+		{
+			name: "sink_index",
+			logging: &stewardv1alpha1.Logging{
+				Sink: &stewardv1alpha1.LogSink{
+					Type:  stewardv1alpha1.LogSinkTypeElasticsearch,
+					Index: "logs2",
+				},
+			},
+			config:  &cfg.PipelineRunsConfigStruct{LoggingIndexURL: "https://es.example.com/logs/_doc"},
+			objects: []runtime.Object{tenantNamespace("")},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", "https://es.example.com/logs2/_doc"),
+			},
+		},
+		{
+			name: "sink_index_without_endpoint",
+			logging: &stewardv1alpha1.Logging{
+				Sink: &stewardv1alpha1.LogSink{
+					Type:  stewardv1alpha1.LogSinkTypeElasticsearch,
+					Index: "logs2",
+				},
+			},
+			objects:        []runtime.Object{tenantNamespace("")},
+			expectedError:  `cannot set index "logs2" of spec.logging.sink: no log forwarding endpoint is configured`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:           "client_cert_without_key",
+			logging:        loggingSpec,
+			objects:        []runtime.Object{tenantNamespace("client1"), clientNamespace("logs1"), clientSecret(map[string]string{"tls.crt": "cert1"})},
+			expectedError:  `invalid log forwarding secret "client1/logs1": entry "tls.key" must not be empty`,
+			expectedResult: stewardv1alpha1.ResultErrorConfig,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := newFakeClientFactory(tc.objects...)
+			examinee := newRunManager(cf, nil)
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{Logging: tc.logging})
+			runCtx.pipelineRunsConfig = tc.config
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					Params: []tektonv1beta1.Param{},
+				},
+			}
+
+			// EXERCISE
+			err := examinee.addTektonTaskRunParamsForLogForwarding(ctx, runCtx, &tektonTaskRun)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Equal(t, tc.expectedResult, serrors.GetClass(err))
+				assert.Assert(t, len(tektonTaskRun.Spec.Params) == 0)
+				return
+			}
+			assert.NilError(t, err)
+			if tc.expectedAddedParams == nil {
+				tc.expectedAddedParams = []tektonv1beta1.Param{}
+			}
+			assert.DeepEqual(t, tc.expectedAddedParams, tektonTaskRun.Spec.Params)
+
+			for _, name := range []string{
+				naming.LogForwardingAuthSecret,
+				naming.LogForwardingClientCertSecret,
+				naming.LogForwardingCABundleSecret,
+			} {
+				runSecret, err := cf.CoreV1().Secrets("runNamespace1").Get(ctx, name, metav1.GetOptions{})
+				expectedData, expected := tc.expectedSecrets[name]
+				if !expected {
+					assert.Assert(t, k8serrors.IsNotFound(err), "secret %q", name)
+					continue
+				}
+				assert.NilError(t, err)
+				assert.Equal(t, len(expectedData), len(runSecret.Data), "secret %q", name)
+				for key, value := range expectedData {
+					assert.Equal(t, value, string(runSecret.Data[key]), "secret %q, key %q", name, key)
+				}
+			}
+		})
+	}
+}
+
+func Test_logForwardingConfig_setIndex(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		indexURL string
+		expected string
+	}{
+		{"https://es.example.com/logs/_doc", "https://es.example.com/logs2/_doc"},
+		{"https://es.example.com/logs/_doc/", "https://es.example.com/logs2/_doc"},
+		{"https://es.example.com/logs", "https://es.example.com/logs2"},
+		{"https://es.example.com/prefix/logs/_doc", "https://es.example.com/prefix/logs2/_doc"},
+		{"https://es.example.com", "https://es.example.com/logs2"},
+	} {
+		tc := tc
+		t.Run(tc.indexURL, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &logForwardingConfig{indexURL: tc.indexURL}
+
+			// EXERCISE
+			err := examinee.setIndex("logs2")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expected, examinee.indexURL)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package logarchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// elasticsearchBulkLines is the maximum number of log lines sent to
+	// Elasticsearch with a single bulk request.
+	elasticsearchBulkLines = 5000
+
+	// elasticsearchDocumentType is the value of field `type` of all
+	// documents of archived logs.
+	elasticsearchDocumentType = "stewardPipelineLog"
+)
+
+// elasticsearchDocument is the document stored in Elasticsearch for each
+// log line.
+type elasticsearchDocument struct {
+	Timestamp   time.Time           `json:"@timestamp"`
+	Type        string              `json:"type"`
+	PipelineRun elasticsearchRunRef `json:"pipelineRun"`
+	Line        int                 `json:"line"`
+	Message     string              `json:"message"`
+}
+
+// elasticsearchRunRef identifies the pipeline run in Elasticsearch
+// documents.
+type elasticsearchRunRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// elasticsearchSink is a Sink sending each log line as a document to an
+// Elasticsearch index.
+type elasticsearchSink struct {
+	opts     Options
+	indexURL string
+}
+
+// NewElasticsearchSink returns a Sink sending logs to the Elasticsearch
+// index with the URL given by the options, e.g.
+// `https://es.example.com:9200/pipeline-logs`.
+// The location of a stored log is the URL of a search for the documents
+// of the pipeline run.
+func NewElasticsearchSink(opts Options) (Sink, error) {
+	indexURL, err := url.Parse(opts.URL)
+	if err != nil || (indexURL.Scheme != "http" && indexURL.Scheme != "https") || strings.Trim(indexURL.Path, "/") == "" {
+		return nil, fmt.Errorf("invalid Elasticsearch index URL %q", opts.URL)
+	}
+	return &elasticsearchSink{
+		opts:     opts,
+		indexURL: strings.TrimSuffix(opts.URL, "/"),
+	}, nil
+}
+
+// Store implements interface Sink
+func (s *elasticsearchSink) Store(ctx context.Context, run *api.PipelineRun, lines []Line) (string, error) {
+	ref := elasticsearchRunRef{
+		Namespace: run.GetNamespace(),
+		Name:      run.GetName(),
+		UID:       string(run.GetUID()),
+	}
+	lineNumber := 0
+	for _, batch := range batches(lines, elasticsearchBulkLines) {
+		body := &bytes.Buffer{}
+		encoder := json.NewEncoder(body)
+		for _, line := range batch {
+			lineNumber++
+			if err := encoder.Encode(map[string]interface{}{"index": map[string]interface{}{}}); err != nil {
+				return "", err
+			}
+			document := elasticsearchDocument{
+				Timestamp:   line.Time.UTC(),
+				Type:        elasticsearchDocumentType,
+				PipelineRun: ref,
+				Line:        lineNumber,
+				Message:     line.Text,
+			}
+			if err := encoder.Encode(document); err != nil {
+				return "", err
+			}
+		}
+		if err := s.bulk(ctx, body); err != nil {
+			return "", err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("pipelineRun.uid:%q", ref.UID))
+	query.Set("sort", "line:asc")
+	return s.indexURL + "/_search?" + query.Encode(), nil
+}
+
+// bulk sends the given request body to the bulk API of the index.
+func (s *elasticsearchSink) bulk(ctx context.Context, body *bytes.Buffer) error {
+	request, err := newRequest(ctx, s.opts, http.MethodPost, s.indexURL+"/_bulk", body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	responseBody, err := send(s.opts.httpClient(), request)
+	if err != nil {
+		return errors.WithMessage(err, "failed to send log to Elasticsearch")
+	}
+	response := struct {
+		Errors bool `json:"errors"`
+	}{}
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return errors.Wrap(err, "failed to decode response of Elasticsearch")
+	}
+	if response.Errors {
+		return errors.New("failed to send log to Elasticsearch: some documents were rejected")
+	}
+	return nil
+}
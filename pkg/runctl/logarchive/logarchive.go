@@ -0,0 +1,182 @@
+package logarchive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+// maxErrorBodyBytes is the maximum number of bytes of the body of an
+// error response included in error messages.
+const maxErrorBodyBytes = 1024
+
+// Line is a line of a pipeline log.
+type Line struct {
+	// Time is the time the line has been written.
+	Time time.Time
+
+	// Text is the content of the line without line break.
+	Text string
+}
+
+// Sink stores logs of finished pipeline runs outside of the cluster.
+// Implementations must be safe for concurrent use.
+type Sink interface {
+	// Store stores the given log lines of the given pipeline run and
+	// returns the location of the stored log, e.g. the URL of an object
+	// or of a query returning the log.
+	Store(ctx context.Context, run *api.PipelineRun, lines []Line) (string, error)
+}
+
+// Options are the options of sink backends.
+type Options struct {
+	// URL is the endpoint of the sink. Its meaning depends on the
+	// backend.
+	URL string
+
+	// Username is the user name to authenticate to the sink. For backend
+	// `s3` it is the access key ID.
+	// If empty, requests are not authenticated.
+	Username string
+
+	// Password is the password to authenticate to the sink. For backend
+	// `s3` it is the secret access key.
+	Password string
+
+	// Region is the region of the bucket of backend `s3`.
+	// If empty, `us-east-1` is used.
+	Region string
+
+	// HTTPClient is the client used to send requests to the sink.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+func (opts Options) httpClient() *http.Client {
+	if opts.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return opts.HTTPClient
+}
+
+// BackendFactory creates a Sink with the given options.
+type BackendFactory func(opts Options) (Sink, error)
+
+var (
+	backendsMutex sync.Mutex
+	backends      = map[string]BackendFactory{
+		"elasticsearch": NewElasticsearchSink,
+		"loki":          NewLokiSink,
+		"s3":            NewS3Sink,
+	}
+)
+
+// RegisterBackend registers a sink backend. It is meant to be called
+// from `init()` functions of packages providing backends.
+// It panics if a backend with the same name has been registered already.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+
+	if name == "" || factory == nil {
+		panic("logarchive: backend name and factory must not be empty")
+	}
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("logarchive: backend %q registered twice", name))
+	}
+	backends[name] = factory
+}
+
+// NewSink creates a Sink using the backend with the given name.
+// Built-in backends are `elasticsearch`, `loki` and `s3`.
+func NewSink(backend string, opts Options) (Sink, error) {
+	backendsMutex.Lock()
+	factory, ok := backends[backend]
+	backendsMutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown log archive backend %q", backend)
+	}
+	return factory(opts)
+}
+
+// ParseLog splits a container log retrieved with timestamps (see
+// `corev1.PodLogOptions.Timestamps`) into lines. Lines without valid
+// timestamp get the time of the preceding line or defaultTime if there
+// is none.
+func ParseLog(log []byte, defaultTime time.Time) []Line {
+	var lines []Line
+	lastTime := defaultTime
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	scanner.Buffer(nil, len(log)+1)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, ' '); i > 0 {
+			if t, err := time.Parse(time.RFC3339Nano, text[:i]); err == nil {
+				lastTime = t
+				text = text[i+1:]
+			}
+		}
+		lines = append(lines, Line{Time: lastTime, Text: text})
+	}
+	return lines
+}
+
+// send sends the given request and fails if the response status is not
+// 2xx. It returns the response body.
+func send(client *http.Client, request *http.Request) ([]byte, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		if len(body) > maxErrorBodyBytes {
+			body = body[:maxErrorBodyBytes]
+		}
+		return nil, errors.Errorf("%s %s: unexpected response status %q: %s",
+			request.Method, request.URL.Redacted(), response.Status, strings.TrimSpace(string(body)),
+		)
+	}
+	return body, nil
+}
+
+// newRequest returns a request with basic authentication if a user name
+// is configured.
+func newRequest(ctx context.Context, opts Options, method, url string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Username != "" {
+		request.SetBasicAuth(opts.Username, opts.Password)
+	}
+	return request, nil
+}
+
+// batches splits lines into batches of at most size lines.
+func batches(lines []Line, size int) [][]Line {
+	var result [][]Line
+	for len(lines) > size {
+		result = append(result, lines[:size])
+		lines = lines[size:]
+	}
+	if len(lines) > 0 {
+		result = append(result, lines)
+	}
+	return result
+}
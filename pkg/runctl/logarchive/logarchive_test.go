@@ -0,0 +1,258 @@
+package logarchive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	testTime1 = time.Date(2022, 12, 1, 8, 0, 0, 0, time.UTC)
+	testTime2 = testTime1.Add(1500 * time.Millisecond)
+)
+
+func newTestRun() *api.PipelineRun {
+	return &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: "ns1", UID: "uid1"},
+	}
+}
+
+// recordingServer is an HTTP server recording the requests it receives.
+type recordingServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func newRecordingServer(t *testing.T, status int, response string) *recordingServer {
+	t.Helper()
+	server := &recordingServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		server.mutex.Lock()
+		server.requests = append(server.requests, r)
+		server.bodies = append(server.bodies, string(body))
+		server.mutex.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_ParseLog(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	defaultTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := "no timestamp\n" +
+		"2022-12-01T08:00:00Z first\n" +
+		"continued\n" +
+		"2022-12-01T08:00:01.5Z second line\n"
+
+	// EXERCISE
+	lines := ParseLog([]byte(log), defaultTime)
+
+	// VERIFY
+	assert.DeepEqual(t, []Line{
+		{Time: defaultTime, Text: "no timestamp"},
+		{Time: testTime1, Text: "first"},
+		{Time: testTime1, Text: "continued"},
+		{Time: testTime2, Text: "second line"},
+	}, lines)
+}
+
+func Test_NewSink_UnknownBackend(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	sink, err := NewSink("foo", Options{})
+
+	// VERIFY
+	assert.Error(t, err, `unknown log archive backend "foo"`)
+	assert.Assert(t, sink == nil)
+}
+
+func Test_NewSink_InvalidURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		backend       string
+		url           string
+		expectedError string
+	}{
+		{"elasticsearch", "https://es.example.com", `invalid Elasticsearch index URL "https://es.example.com"`},
+		{"loki", "loki.example.com", `invalid Loki URL "loki.example.com"`},
+		{"s3", "https://s3.example.com/", `invalid S3 bucket URL "https://s3.example.com/"`},
+	} {
+		tc := tc
+		t.Run(tc.backend, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			_, err := NewSink(tc.backend, Options{URL: tc.url})
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_ElasticsearchSink_Store(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusOK, `{"errors":false}`)
+	examinee, err := NewSink("elasticsearch", Options{URL: server.URL + "/logs/", Username: "user1", Password: "pw1"})
+	assert.NilError(t, err)
+	lines := []Line{{Time: testTime1, Text: "line 1"}, {Time: testTime2, Text: "line 2"}}
+
+	// EXERCISE
+	location, err := examinee.Store(context.Background(), newTestRun(), lines)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, server.URL+`/logs/_search?q=pipelineRun.uid%3A%22uid1%22&sort=line%3Aasc`, location)
+	assert.Equal(t, 1, len(server.requests))
+	request := server.requests[0]
+	assert.Equal(t, "/logs/_bulk", request.URL.Path)
+	username, password, _ := request.BasicAuth()
+	assert.Equal(t, "user1", username)
+	assert.Equal(t, "pw1", password)
+
+	var documents []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(server.bodies[0]))
+	for scanner.Scan() {
+		document := map[string]interface{}{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &document))
+		documents = append(documents, document)
+	}
+	assert.Equal(t, 4, len(documents))
+	assert.DeepEqual(t, map[string]interface{}{"index": map[string]interface{}{}}, documents[0])
+	assert.DeepEqual(t, map[string]interface{}{
+		"@timestamp":  "2022-12-01T08:00:01.5Z",
+		"type":        "stewardPipelineLog",
+		"pipelineRun": map[string]interface{}{"namespace": "ns1", "name": "run1", "uid": "uid1"},
+		"line":        float64(2),
+		"message":     "line 2",
+	}, documents[3])
+}
+
+func Test_ElasticsearchSink_Store_RejectedDocuments(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusOK, `{"errors":true}`)
+	examinee, err := NewSink("elasticsearch", Options{URL: server.URL + "/logs"})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Store(context.Background(), newTestRun(), []Line{{Time: testTime1, Text: "line 1"}})
+
+	// VERIFY
+	assert.Error(t, err, "failed to send log to Elasticsearch: some documents were rejected")
+}
+
+func Test_LokiSink_Store(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusNoContent, "")
+	examinee, err := NewSink("loki", Options{URL: server.URL})
+	assert.NilError(t, err)
+	lines := []Line{{Time: testTime1, Text: "line 1"}, {Time: testTime2, Text: "line 2"}}
+
+	// EXERCISE
+	location, err := examinee.Store(context.Background(), newTestRun(), lines)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(server.requests))
+	assert.Equal(t, "/loki/api/v1/push", server.requests[0].URL.Path)
+	pushRequest := lokiPushRequest{}
+	assert.NilError(t, json.Unmarshal([]byte(server.bodies[0]), &pushRequest))
+	assert.DeepEqual(t, []lokiStream{{
+		Stream: map[string]string{"job": "steward", "namespace": "ns1", "pipelinerun": "run1", "uid": "uid1"},
+		Values: [][2]string{
+			{"1669881600000000000", "line 1"},
+			{"1669881601500000000", "line 2"},
+		},
+	}}, pushRequest.Streams)
+	assert.Assert(t, is.Contains(location, server.URL+"/loki/api/v1/query_range?"))
+	assert.Assert(t, is.Contains(location, "start=1669881600000000000"))
+	assert.Assert(t, is.Contains(location, "end=1669881601500000001"))
+}
+
+func Test_LokiSink_Store_ErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusBadRequest, "entry too far behind\n")
+	examinee, err := NewSink("loki", Options{URL: server.URL})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Store(context.Background(), newTestRun(), []Line{{Time: testTime1, Text: "line 1"}})
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to send log to Loki: POST `)
+	assert.ErrorContains(t, err, `unexpected response status "400 Bad Request": entry too far behind`)
+}
+
+func Test_S3Sink_Store(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusOK, "")
+	sink, err := NewS3Sink(Options{URL: server.URL + "/bucket1/logs", Username: "key1", Password: "secret1", Region: "eu-central-1"})
+	assert.NilError(t, err)
+	examinee := sink.(*s3Sink)
+	examinee.now = func() time.Time { return testTime1 }
+	lines := []Line{{Time: testTime1, Text: "line 1"}, {Time: testTime2, Text: "line 2"}}
+
+	// EXERCISE
+	location, err := examinee.Store(context.Background(), newTestRun(), lines)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, server.URL+"/bucket1/logs/ns1/run1-uid1.log", location)
+	assert.Equal(t, 1, len(server.requests))
+	request := server.requests[0]
+	assert.Equal(t, http.MethodPut, request.Method)
+	assert.Equal(t, "/bucket1/logs/ns1/run1-uid1.log", request.URL.Path)
+	assert.Equal(t, "line 1\nline 2\n", server.bodies[0])
+	assert.Equal(t, "20221201T080000Z", request.Header.Get("X-Amz-Date"))
+	assert.Assert(t, is.Regexp(
+		`^AWS4-HMAC-SHA256 Credential=key1/20221201/eu-central-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`,
+		request.Header.Get("Authorization"),
+	))
+}
+
+func Test_S3Sink_Store_Anonymous(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newRecordingServer(t, http.StatusOK, "")
+	examinee, err := NewSink("s3", Options{URL: server.URL + "/bucket1"})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Store(context.Background(), newTestRun(), nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "", server.requests[0].Header.Get("Authorization"))
+}
@@ -0,0 +1,107 @@
+package logarchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// lokiPushLines is the maximum number of log lines sent to Loki with
+	// a single push request.
+	lokiPushLines = 5000
+
+	// lokiJob is the value of label `job` of all archived logs.
+	lokiJob = "steward"
+)
+
+// lokiPushRequest is the body of a request to the push API of Loki.
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// lokiStream is a stream of log entries with the same labels.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiSink is a Sink pushing logs to Loki.
+type lokiSink struct {
+	opts    Options
+	baseURL string
+}
+
+// NewLokiSink returns a Sink pushing logs to the Loki server with the
+// base URL given by the options, e.g. `https://loki.example.com`.
+// The log of a pipeline run is a separate stream with labels `job`
+// (always `steward`), `namespace`, `pipelinerun` and `uid`.
+// The location of a stored log is the URL of a range query for the
+// stream.
+func NewLokiSink(opts Options) (Sink, error) {
+	baseURL, err := url.Parse(opts.URL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid Loki URL %q", opts.URL)
+	}
+	baseURL.Path = ""
+	baseURL.RawPath = ""
+	return &lokiSink{
+		opts:    opts,
+		baseURL: baseURL.String(),
+	}, nil
+}
+
+// Store implements interface Sink
+func (s *lokiSink) Store(ctx context.Context, run *api.PipelineRun, lines []Line) (string, error) {
+	labels := map[string]string{
+		"job":         lokiJob,
+		"namespace":   run.GetNamespace(),
+		"pipelinerun": run.GetName(),
+		"uid":         string(run.GetUID()),
+	}
+	for _, batch := range batches(lines, lokiPushLines) {
+		stream := lokiStream{Stream: labels}
+		for _, line := range batch {
+			stream.Values = append(stream.Values, [2]string{
+				strconv.FormatInt(line.Time.UnixNano(), 10),
+				line.Text,
+			})
+		}
+		if err := s.push(ctx, lokiPushRequest{Streams: []lokiStream{stream}}); err != nil {
+			return "", err
+		}
+	}
+
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf(`{job=%q,uid=%q}`, lokiJob, labels["uid"]))
+	query.Set("direction", "forward")
+	if len(lines) > 0 {
+		query.Set("start", strconv.FormatInt(lines[0].Time.UnixNano(), 10))
+		query.Set("end", strconv.FormatInt(lines[len(lines)-1].Time.UnixNano()+1, 10))
+	}
+	return s.baseURL + "/loki/api/v1/query_range?" + query.Encode(), nil
+}
+
+// push sends the given request to the push API of Loki.
+func (s *lokiSink) push(ctx context.Context, pushRequest lokiPushRequest) error {
+	body, err := json.Marshal(pushRequest)
+	if err != nil {
+		return err
+	}
+	request, err := newRequest(ctx, s.opts, http.MethodPost, s.baseURL+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if _, err := send(s.opts.httpClient(), request); err != nil {
+		return errors.WithMessage(err, "failed to send log to Loki")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package logarchive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/utils/sigv4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// s3DefaultRegion is the region used for request signing if none is
+	// configured.
+	s3DefaultRegion = "us-east-1"
+)
+
+// s3Sink is a Sink storing each log as an object in an S3 bucket.
+type s3Sink struct {
+	opts      Options
+	bucketURL *url.URL
+	now       func() time.Time
+}
+
+// NewS3Sink returns a Sink storing logs in the S3 bucket with the
+// path-style URL given by the options, optionally followed by a key
+// prefix, e.g. `https://s3.eu-central-1.amazonaws.com/bucket/logs`.
+// Any S3-compatible object store can be used.
+// The log of a pipeline run is stored as plain text object
+// `<prefix>/<namespace>/<name>-<uid>.log`, which is also the location of
+// the stored log.
+// Requests are signed with AWS Signature Version 4 using the user name
+// and password of the options as access key ID and secret access key.
+func NewS3Sink(opts Options) (Sink, error) {
+	bucketURL, err := url.Parse(opts.URL)
+	if err != nil || (bucketURL.Scheme != "http" && bucketURL.Scheme != "https") || strings.Trim(bucketURL.Path, "/") == "" {
+		return nil, fmt.Errorf("invalid S3 bucket URL %q", opts.URL)
+	}
+	if bucketURL.RawQuery != "" || bucketURL.Fragment != "" {
+		return nil, fmt.Errorf("invalid S3 bucket URL %q: must not have a query or fragment", opts.URL)
+	}
+	if opts.Region == "" {
+		opts.Region = s3DefaultRegion
+	}
+	return &s3Sink{
+		opts:      opts,
+		bucketURL: bucketURL,
+		now:       time.Now,
+	}, nil
+}
+
+// Store implements interface Sink
+func (s *s3Sink) Store(ctx context.Context, run *api.PipelineRun, lines []Line) (string, error) {
+	body := &bytes.Buffer{}
+	for _, line := range lines {
+		body.WriteString(line.Text)
+		body.WriteByte('\n')
+	}
+
+	objectURL := *s.bucketURL
+	objectURL.Path = path.Join(objectURL.Path, run.GetNamespace(), fmt.Sprintf("%s-%s.log", run.GetName(), run.GetUID()))
+	objectURL.RawPath = ""
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	s.sign(request, body.Bytes())
+	if _, err := send(s.opts.httpClient(), request); err != nil {
+		return "", errors.WithMessage(err, "failed to store log in S3")
+	}
+	return objectURL.String(), nil
+}
+
+// sign adds the headers of AWS Signature Version 4 to the given request
+// with the given payload. Requests are not signed if no access key ID
+// is configured.
+func (s *s3Sink) sign(request *http.Request, payload []byte) {
+	credentials := sigv4.Credentials{
+		AccessKeyID:     s.opts.Username,
+		SecretAccessKey: s.opts.Password,
+	}
+	sigv4.Sign(request, payload, credentials, s.opts.Region, "s3", s.now())
+}
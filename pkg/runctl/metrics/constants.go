@@ -9,4 +9,12 @@ const (
 	// WorkqueueName is the name of the run controller workqueue.
 	// It is required by the metrics adapter for workqueues.
 	WorkqueueName = "runctl"
+
+	// AbortHandlersWorkqueueName is the name of the workqueue of abort
+	// handler invocations.
+	AbortHandlersWorkqueueName = "runctl_abort_handlers"
+
+	// TimelineExportWorkqueueName is the name of the workqueue of run
+	// timeline exports.
+	TimelineExportWorkqueueName = "runctl_timeline_export"
 )
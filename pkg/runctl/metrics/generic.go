@@ -26,3 +26,8 @@ type StateItemsMetric interface {
 type ResultsMetric interface {
 	Observe(result stewardapi.Result)
 }
+
+// TestResultsMetric observes the test results of a finished pipeline run.
+type TestResultsMetric interface {
+	Observe(testResults *stewardapi.TestResults)
+}
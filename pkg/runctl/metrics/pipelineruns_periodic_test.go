@@ -184,6 +184,27 @@ func Test_pipelineRunsPeriodic_NonNewRun(t *testing.T) {
 	}
 }
 
+func Test_pipelineRunsPeriodic_QueuedRun(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	mockClock := clock.NewMock()
+	mockClock.Set(fakeNow)
+	run := &stewardapi.PipelineRun{}
+	run.Status.State = stewardapi.StateQueued
+	run.CreationTimestamp = metav1.NewTime(mockClock.Now().Add(-2 * time.Second))
+
+	// EXERCISE and VERIFY
+	doTestPipelineRunsPeriodic(
+		t,
+		mockClock,
+		run,
+		2*time.Second,
+		true,
+		stewardapi.StateQueued,
+	)
+}
+
 func doTestPipelineRunsPeriodic(
 	t *testing.T,
 	mockClock *clock.Mock,
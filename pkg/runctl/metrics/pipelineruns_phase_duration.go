@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsPhaseDuration is a metric that observes the duration
+	// of the processing phases of pipeline runs (preparing, waiting,
+	// running, cleaning) when a phase has just been finished.
+	// Other states are ignored.
+	PipelineRunsPhaseDuration StateItemsMetric = &pipelineRunsPhaseDuration{}
+)
+
+func init() {
+	PipelineRunsPhaseDuration.(*pipelineRunsPhaseDuration).init()
+}
+
+// phaseHistogramDefs defines one histogram per processing phase.
+// The buckets are tailored to the typical duration of the phase, so
+// that slow startup or cleanup can be detected at a finer granularity
+// than with the state duration metric that covers all states.
+var phaseHistogramDefs = []struct {
+	state   stewardapi.State
+	name    string
+	help    string
+	buckets []float64
+}{
+	{
+		state: stewardapi.StatePreparing,
+		name:  "preparing_duration_seconds",
+		help: "A histogram counting the pipeline runs that finished state `preparing` grouped by the state duration." +
+			"\n\nState `preparing` covers the setup of the run namespace and its contents.",
+		// 0.25s .. 512s
+		buckets: prometheus.ExponentialBuckets(0.25, 2, 12),
+	},
+	{
+		state: stewardapi.StateWaiting,
+		name:  "waiting_duration_seconds",
+		help: "A histogram counting the pipeline runs that finished state `waiting` grouped by the state duration." +
+			"\n\nState `waiting` covers the scheduling of the pipeline run pod including image pulls.",
+		// 0.25s .. 512s
+		buckets: prometheus.ExponentialBuckets(0.25, 2, 12),
+	},
+	{
+		state: stewardapi.StateRunning,
+		name:  "running_duration_seconds",
+		help: "A histogram counting the pipeline runs that finished state `running` grouped by the state duration." +
+			"\n\nState `running` covers the execution of the pipeline.",
+		// 15s .. ~8.5h
+		buckets: prometheus.ExponentialBuckets(15, 2, 12),
+	},
+	{
+		state: stewardapi.StateCleaning,
+		name:  "cleaning_duration_seconds",
+		help: "A histogram counting the pipeline runs that finished state `cleaning` grouped by the state duration." +
+			"\n\nState `cleaning` covers the deletion of the run namespace.",
+		// 0.25s .. 512s
+		buckets: prometheus.ExponentialBuckets(0.25, 2, 12),
+	},
+}
+
+type pipelineRunsPhaseDuration struct {
+	initOnlyOnce sync.Once
+	metrics      map[stewardapi.State]prometheus.Histogram
+}
+
+func (m *pipelineRunsPhaseDuration) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metrics = map[stewardapi.State]prometheus.Histogram{}
+		for _, def := range phaseHistogramDefs {
+			metric := prometheus.NewHistogram(
+				prometheus.HistogramOpts{
+					Subsystem: subsystem,
+					Name:      def.name,
+					Help:      def.help,
+					Buckets:   def.buckets,
+				},
+			)
+			metrics.Registerer().MustRegister(metric)
+			m.metrics[def.state] = metric
+		}
+	})
+}
+
+func (m *pipelineRunsPhaseDuration) Observe(state *stewardapi.StateItem) {
+	metric, ok := m.metrics[state.State]
+	if !ok {
+		return
+	}
+	if state.StartedAt.IsZero() || state.FinishedAt.IsZero() {
+		// cannot observe state if timestamps are not set
+		return
+	}
+	duration := state.FinishedAt.Sub(state.StartedAt.Time)
+	if duration < 0 {
+		return
+	}
+	metric.Observe(duration.Seconds())
+}
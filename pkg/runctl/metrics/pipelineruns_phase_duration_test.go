@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+	k8sapiv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_PipelineRunsPhaseDuration_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Equal(t, len(PipelineRunsPhaseDuration.(*pipelineRunsPhaseDuration).metrics), 4)
+}
+
+func Test_pipelineRunsPhaseDuration_Valid(t *testing.T) {
+	// no parallel: patching global state
+
+	for _, tc := range []struct {
+		state        stewardapi.State
+		expectedName string
+	}{
+		{stewardapi.StatePreparing, "steward_pipelineruns_preparing_duration_seconds"},
+		{stewardapi.StateWaiting, "steward_pipelineruns_waiting_duration_seconds"},
+		{stewardapi.StateRunning, "steward_pipelineruns_running_duration_seconds"},
+		{stewardapi.StateCleaning, "steward_pipelineruns_cleaning_duration_seconds"},
+	} {
+		t.Run(string(tc.state), func(t *testing.T) {
+			// no parallel: patching global state
+
+			// SETUP
+			reg := prometheus.NewPedanticRegistry()
+			t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+			examinee := &pipelineRunsPhaseDuration{}
+			examinee.init()
+
+			startTime := time.Unix(1000, 0)
+			duration := 12_345_678 * time.Microsecond
+			stateItem := &stewardapi.StateItem{
+				State:      tc.state,
+				StartedAt:  k8sapiv1.Time{Time: startTime},
+				FinishedAt: k8sapiv1.Time{Time: startTime.Add(duration)},
+			}
+
+			// EXERCISE
+			examinee.Observe(stateItem)
+
+			// VERIFY
+			metricFamilies, err := reg.Gather()
+			assert.NilError(t, err)
+			assert.Equal(t, len(metricFamilies), 4)
+
+			for _, metricFamily := range metricFamilies {
+				assert.Equal(t, len(metricFamily.GetMetric()), 1)
+				histogram := metricFamily.GetMetric()[0].GetHistogram()
+				if metricFamily.GetName() != tc.expectedName {
+					assert.Equal(t, histogram.GetSampleCount(), uint64(0), metricFamily.GetName())
+					continue
+				}
+				assert.Equal(t, histogram.GetSampleCount(), uint64(1))
+				assert.Equal(t, histogram.GetSampleSum(), duration.Seconds())
+				for _, bucket := range histogram.GetBucket() {
+					if duration.Seconds() <= bucket.GetUpperBound() {
+						assert.Equal(t, bucket.GetCumulativeCount(), uint64(1))
+					} else {
+						assert.Equal(t, bucket.GetCumulativeCount(), uint64(0))
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_pipelineRunsPhaseDuration_Invalid(t *testing.T) {
+	// no parallel: patching global state
+
+	for i, tc := range []struct {
+		state     stewardapi.State
+		startTime time.Time
+		endTime   time.Time
+	}{
+		{
+			// not a phase
+			state:     stewardapi.StateQueued,
+			startTime: time.Unix(1000, 0),
+			endTime:   time.Unix(1001, 0),
+		},
+		{
+			// not a phase
+			state:     stewardapi.State(dummyStateName1),
+			startTime: time.Unix(1000, 0),
+			endTime:   time.Unix(1001, 0),
+		},
+		{
+			state: stewardapi.StateRunning,
+			// no start time
+			// no end time
+		},
+		{
+			state:     stewardapi.StateRunning,
+			startTime: time.Unix(1000, 0),
+			// no end time
+		},
+		{
+			state: stewardapi.StateRunning,
+			// no start time
+			endTime: time.Unix(1000, 0),
+		},
+		{
+			state:     stewardapi.StateRunning,
+			startTime: time.Unix(1000, 0),
+			// end time before start time
+			endTime: time.Unix(999, 0),
+		},
+	} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			// no parallel: patching global state
+
+			// SETUP
+			reg := prometheus.NewPedanticRegistry()
+			t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+			examinee := &pipelineRunsPhaseDuration{}
+			examinee.init()
+
+			stateItem := &stewardapi.StateItem{
+				State:      tc.state,
+				StartedAt:  k8sapiv1.Time{Time: tc.startTime},
+				FinishedAt: k8sapiv1.Time{Time: tc.endTime},
+			}
+
+			// EXERCISE
+			examinee.Observe(stateItem)
+
+			// VERIFY
+			metricFamilies, err := reg.Gather()
+			assert.NilError(t, err)
+			for _, metricFamily := range metricFamilies {
+				for _, metric := range metricFamily.GetMetric() {
+					assert.Equal(t, metric.GetHistogram().GetSampleCount(), uint64(0), metricFamily.GetName())
+				}
+			}
+		})
+	}
+}
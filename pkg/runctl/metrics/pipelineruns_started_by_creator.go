@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	creatorTypeServiceAccount = "serviceaccount"
+	creatorTypeSystem         = "system"
+	creatorTypeUnknown        = "unknown"
+	creatorTypeUser           = "user"
+)
+
+var (
+	// PipelineRunsStartedByCreatorType counts the pipeline runs that have
+	// been started partitioned by the type of the creator.
+	PipelineRunsStartedByCreatorType PipelineRunsMetric = &pipelineRunsStartedByCreatorType{}
+)
+
+func init() {
+	PipelineRunsStartedByCreatorType.(*pipelineRunsStartedByCreatorType).init()
+}
+
+type pipelineRunsStartedByCreatorType struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *pipelineRunsStartedByCreatorType) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "started_by_creator_type_total",
+				Help:      "The total number of started pipeline runs partitioned by the type of the creator.",
+			},
+			[]string{
+				"creator_type",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *pipelineRunsStartedByCreatorType) Observe(pipelineRun *stewardapi.PipelineRun) {
+	m.metric.WithLabelValues(creatorType(pipelineRun)).Inc()
+}
+
+// creatorType classifies the creator of the given pipeline run.
+// The user name itself is not used as label value because it would
+// make the metric cardinality unbounded.
+func creatorType(pipelineRun *stewardapi.PipelineRun) string {
+	createdBy := pipelineRun.GetAnnotations()[stewardapi.AnnotationCreatedBy]
+	switch {
+	case createdBy == "":
+		return creatorTypeUnknown
+	case strings.HasPrefix(createdBy, "system:serviceaccount:"):
+		return creatorTypeServiceAccount
+	case strings.HasPrefix(createdBy, "system:"):
+		return creatorTypeSystem
+	default:
+		return creatorTypeUser
+	}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_PipelineRunsStartedByCreatorType_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, PipelineRunsStartedByCreatorType.(*pipelineRunsStartedByCreatorType).metric != nil)
+}
+
+func Test_creatorType(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		annotations map[string]string
+		expected    string
+	}{
+		{nil, creatorTypeUnknown},
+		{map[string]string{stewardapi.AnnotationCreatedBy: ""}, creatorTypeUnknown},
+		{map[string]string{stewardapi.AnnotationCreatedBy: "system:serviceaccount:ns1:sa1"}, creatorTypeServiceAccount},
+		{map[string]string{stewardapi.AnnotationCreatedBy: "system:admin"}, creatorTypeSystem},
+		{map[string]string{stewardapi.AnnotationCreatedBy: "jane.doe@example.com"}, creatorTypeUser},
+	} {
+		pipelineRun := &stewardapi.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+		}
+
+		// EXERCISE
+		result := creatorType(pipelineRun)
+
+		// VERIFY
+		assert.Equal(t, tc.expected, result, "annotations: %v", tc.annotations)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsTestCases counts the number of test cases of completed
+	// pipeline runs by test result.
+	PipelineRunsTestCases TestResultsMetric = &pipelineRunsTestCases{}
+)
+
+func init() {
+	PipelineRunsTestCases.(*pipelineRunsTestCases).init()
+}
+
+type pipelineRunsTestCases struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *pipelineRunsTestCases) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "testcases_total",
+				Help:      "The number of test cases of completed pipeline runs with test result extraction partitioned by test result.",
+			},
+			[]string{
+				"result",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *pipelineRunsTestCases) Observe(testResults *stewardapi.TestResults) {
+	if testResults == nil {
+		return
+	}
+	passed := testResults.Tests - testResults.Failures - testResults.Errors - testResults.Skipped
+	if passed < 0 {
+		passed = 0
+	}
+	m.metric.WithLabelValues("passed").Add(float64(passed))
+	m.metric.WithLabelValues("failed").Add(float64(testResults.Failures))
+	m.metric.WithLabelValues("error").Add(float64(testResults.Errors))
+	m.metric.WithLabelValues("skipped").Add(float64(testResults.Skipped))
+}
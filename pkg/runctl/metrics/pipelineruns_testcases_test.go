@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+)
+
+func Test_PipelineRunsTestCases_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, PipelineRunsTestCases.(*pipelineRunsTestCases).metric != nil)
+}
+
+func Test_pipelineRunsTestCases_Observe(t *testing.T) {
+	// no parallel: patches global state
+
+	// SETUP
+	t.Cleanup(metrics.Testing{}.PatchRegistry(prometheus.NewPedanticRegistry()))
+	examinee := &pipelineRunsTestCases{}
+	examinee.init()
+
+	// EXERCISE
+	examinee.Observe(&stewardapi.TestResults{Tests: 10, Failures: 2, Errors: 1, Skipped: 3})
+	examinee.Observe(&stewardapi.TestResults{Tests: 1})
+	examinee.Observe(nil)
+
+	// VERIFY
+	assert.Equal(t, 5.0, testutil.ToFloat64(examinee.metric.WithLabelValues("passed")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(examinee.metric.WithLabelValues("failed")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(examinee.metric.WithLabelValues("error")))
+	assert.Equal(t, 3.0, testutil.ToFloat64(examinee.metric.WithLabelValues("skipped")))
+}
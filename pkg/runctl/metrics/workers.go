@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// Workers reflects the current number of workers of the run
+	// controller.
+	Workers SettableGaugeMetric = &workers{}
+)
+
+func init() {
+	Workers.(*workers).init()
+}
+
+type workers struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Gauge
+}
+
+func (m *workers) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "workers",
+			Help:      "The current number of workers of the run controller if worker autoscaling is enabled.",
+		})
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *workers) Set(value float64) {
+	m.metric.Set(value)
+}
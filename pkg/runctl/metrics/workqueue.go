@@ -8,8 +8,13 @@ func init() {
 	metricswq.RegisterNameProvider(
 		metricswq.NameProviderFunc(
 			func(queueName string) (string, bool) {
-				if queueName == WorkqueueName {
+				switch queueName {
+				case WorkqueueName:
 					return subsystemForWorkqueue, true
+				case AbortHandlersWorkqueueName:
+					return subsystem + "_abort_handlers_workqueue", true
+				case TimelineExportWorkqueueName:
+					return subsystem + "_timeline_export_workqueue", true
 				}
 				return "", false
 			},
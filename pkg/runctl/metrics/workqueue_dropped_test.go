@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_WorkqueueDroppedKeys_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, *(WorkqueueDroppedKeys.(*workqueueDroppedKeys)) != workqueueDroppedKeys{})
+}
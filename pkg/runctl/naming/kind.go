@@ -0,0 +1,159 @@
+package naming
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// hashLength is the number of hex digits of the hash appended to
+	// truncated names.
+	hashLength = 10
+
+	// generatedSuffixLength is the number of random characters Kubernetes
+	// appends to `metadata.generateName`.
+	generatedSuffixLength = 5
+
+	// maxGenerateNameLength is the maximum length of
+	// `metadata.generateName` that Kubernetes does not truncate.
+	maxGenerateNameLength = validation.DNS1123LabelMaxLength - generatedSuffixLength
+)
+
+// Kind is a kind of resource objects and defines the constraints for the
+// names of objects of this kind.
+type Kind struct {
+	name      string
+	maxLength int
+	isLabel   bool
+}
+
+var (
+	// Namespace is the kind of namespaces, whose names must be DNS-1123
+	// labels.
+	Namespace = Kind{name: "namespace", maxLength: validation.DNS1123LabelMaxLength, isLabel: true}
+
+	// Secret is the kind of secrets, whose names must be DNS-1123
+	// subdomains.
+	Secret = Kind{name: "secret", maxLength: validation.DNS1123SubdomainMaxLength}
+
+	// ConfigMap is the kind of config maps, whose names must be DNS-1123
+	// subdomains.
+	ConfigMap = Kind{name: "config map", maxLength: validation.DNS1123SubdomainMaxLength}
+
+	// RoleBinding is the kind of role bindings, whose names must be
+	// DNS-1123 subdomains.
+	RoleBinding = Kind{name: "role binding", maxLength: validation.DNS1123SubdomainMaxLength}
+
+	// ServiceAccount is the kind of service accounts, whose names must be
+	// DNS-1123 subdomains.
+	ServiceAccount = Kind{name: "service account", maxLength: validation.DNS1123SubdomainMaxLength}
+
+	// Role is the kind of roles. Their names must be valid path segments,
+	// but are restricted to DNS-1123 subdomains here for simplicity.
+	Role = Kind{name: "role", maxLength: validation.DNS1123SubdomainMaxLength}
+
+	// TaskRun is the kind of Tekton task runs. Their names must be
+	// DNS-1123 labels, because Tekton labels the pods of a task run with
+	// its name.
+	TaskRun = Kind{name: "task run", maxLength: validation.DNS1123LabelMaxLength, isLabel: true}
+)
+
+// MaxLength returns the maximum length of names of this kind.
+func (k Kind) MaxLength() int {
+	return k.maxLength
+}
+
+// Name returns a valid name for an object of this kind composed of the
+// given parts separated by '-'.
+// Upper case letters are converted to lower case and other invalid
+// characters are replaced by '-'. If the result exceeds the maximum
+// length, it gets truncated and a hash of the untruncated name is
+// appended, so that different long names do not collide.
+func (k Kind) Name(parts ...string) string {
+	return k.shorten(k.sanitize(join(parts)), k.maxLength)
+}
+
+// GenerateName returns a valid value for `metadata.generateName` of an
+// object of this kind composed of the given parts separated by '-'.
+// The result ends with '-' and is short enough that Kubernetes does not
+// truncate it when appending the random suffix.
+// See Name for the conversion of invalid and long names.
+func (k Kind) GenerateName(parts ...string) string {
+	maxLength := k.maxLength - generatedSuffixLength
+	if maxLength > maxGenerateNameLength {
+		maxLength = maxGenerateNameLength
+	}
+	return k.shorten(k.sanitize(join(parts)), maxLength-1) + "-"
+}
+
+// Validate returns an error if the given name is not a valid name for
+// objects of this kind.
+func (k Kind) Validate(name string) error {
+	var msgs []string
+	if k.isLabel {
+		msgs = validation.IsDNS1123Label(name)
+	} else {
+		msgs = validation.IsDNS1123Subdomain(name)
+	}
+	if len(msgs) > 0 {
+		return errors.Errorf("invalid %s name %q: %s", k.name, name, strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+func join(parts []string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "-")
+}
+
+// sanitize converts the given name into a valid name of unlimited length.
+func (k Kind) sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		case r == '.' && !k.isLabel:
+			return r
+		default:
+			return '-'
+		}
+	}, name)
+	name = trimNonAlphaNumeric(name)
+	if name == "" {
+		return "x"
+	}
+	return name
+}
+
+// shorten truncates the given name to the given maximum length if
+// necessary and appends a hash of the original name in this case.
+func (k Kind) shorten(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+	prefix := trimNonAlphaNumeric(name[:maxLength-hashLength-1])
+	if prefix == "" {
+		return hash
+	}
+	return prefix + "-" + hash
+}
+
+// trimNonAlphaNumeric removes leading and trailing characters that are
+// not alphanumeric, which are not allowed at the start and the end of
+// names.
+func trimNonAlphaNumeric(s string) string {
+	return strings.Trim(s, "-.")
+}
@@ -0,0 +1,106 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func Test_Kind_Name(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 300)
+
+	for _, tc := range []struct {
+		name     string
+		kind     Kind
+		parts    []string
+		expected string
+	}{
+		{"short", ConfigMap, []string{"steward", "foo"}, "steward-foo"},
+		{"empty parts skipped", ConfigMap, []string{"steward", "", "foo"}, "steward-foo"},
+		{"upper case", Secret, []string{"Steward", "FOO"}, "steward-foo"},
+		{"invalid chars", Secret, []string{"foo_bar", "baz~"}, "foo-bar-baz"},
+		{"dot in subdomain", Secret, []string{"foo.bar"}, "foo.bar"},
+		{"dot in label", TaskRun, []string{"foo.bar"}, "foo-bar"},
+		{"nothing valid", Secret, []string{"__"}, "x"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := tc.kind.Name(tc.parts...)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+			assert.NilError(t, tc.kind.Validate(result))
+		})
+	}
+
+	t.Run("long", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kind := range []Kind{Namespace, Secret, ConfigMap, RoleBinding, TaskRun} {
+			// EXERCISE
+			result := kind.Name("steward", long)
+
+			// VERIFY
+			assert.Equal(t, kind.MaxLength(), len(result))
+			assert.NilError(t, kind.Validate(result))
+		}
+	})
+}
+
+func Test_Kind_Name_LongNamesDoNotCollide(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	prefix := strings.Repeat("a", 300)
+
+	// EXERCISE
+	name1 := ConfigMap.Name(prefix, "1")
+	name2 := ConfigMap.Name(prefix, "2")
+
+	// VERIFY
+	assert.Assert(t, name1 != name2)
+	assert.Equal(t, name1, ConfigMap.Name(prefix, "1"))
+}
+
+func Test_Kind_GenerateName(t *testing.T) {
+	t.Parallel()
+
+	t.Run("short", func(t *testing.T) {
+		t.Parallel()
+
+		// EXERCISE
+		result := Namespace.GenerateName("steward-run", "abcde", "main")
+
+		// VERIFY
+		assert.Equal(t, "steward-run-abcde-main-", result)
+	})
+
+	t.Run("long", func(t *testing.T) {
+		t.Parallel()
+
+		for _, kind := range []Kind{Namespace, Secret, TaskRun} {
+			// EXERCISE
+			result := kind.GenerateName("steward-run", strings.Repeat("x", 100))
+
+			// VERIFY
+			assert.Equal(t, maxGenerateNameLength, len(result))
+			assert.Assert(t, strings.HasSuffix(result, "-"))
+			assert.NilError(t, kind.Validate(result+"12345"))
+		}
+	})
+}
+
+func Test_Kind_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NilError(t, Secret.Validate("foo.bar"))
+	assert.Assert(t, is.ErrorContains(TaskRun.Validate("foo.bar"), `invalid task run name "foo.bar"`))
+	assert.Assert(t, is.ErrorContains(ConfigMap.Validate(strings.Repeat("a", 254)), "invalid config map name"))
+}
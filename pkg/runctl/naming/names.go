@@ -0,0 +1,104 @@
+/*
+Package naming defines the names of the resource objects the run
+controller creates for pipeline runs, i.e. run namespaces and their
+contents.
+
+All names are derived via the resource Kind, which makes them valid
+for the respective kind of resource object. Names that would be too long,
+e.g. because they contain the name of a pipeline run, are truncated and
+made unique by a hash.
+*/
+package naming
+
+const (
+	// RunNamespacePrefix is the prefix of the names of run namespaces.
+	RunNamespacePrefix = "steward-run"
+
+	// JenkinsfileRunnerTaskRun is the name of the Tekton task run in each
+	// run namespace executing the Jenkinsfile Runner.
+	JenkinsfileRunnerTaskRun = "steward-jenkinsfile-runner"
+
+	// PipelineParamsConfigMap is the name of the config map in each run
+	// namespace holding the pipeline arguments that are too large to be
+	// passed inline via the Tekton task run.
+	PipelineParamsConfigMap = "steward-pipeline-params"
+
+	// ProgressConfigMap is the name of the config map in each run
+	// namespace through which the Jenkinsfile Runner reports the
+	// stage-level progress of the pipeline.
+	ProgressConfigMap = "steward-progress"
+
+	// LogForwardingAuthSecret is the name of the basic-auth secret in the
+	// run namespace holding the Elasticsearch credentials of the client.
+	LogForwardingAuthSecret = "steward-log-forwarding-auth"
+
+	// LogForwardingClientCertSecret is the name of the TLS secret in the
+	// run namespace holding the client certificate and key for mutual TLS
+	// authentication to Elasticsearch.
+	LogForwardingClientCertSecret = "steward-log-forwarding-client-cert"
+
+	// LogForwardingCABundleSecret is the name of the secret in the run
+	// namespace holding the CA certificates trusted when connecting to
+	// Elasticsearch.
+	LogForwardingCABundleSecret = "steward-log-forwarding-ca"
+
+	// ArtifactsSecret is the name of the secret in the run namespace
+	// holding the credentials for the upload of artifacts to the object
+	// storage. It is referenced by the Jenkinsfile Runner ClusterTask.
+	ArtifactsSecret = "steward-artifacts"
+
+	// RunAPITokenSecret is the name of the secret in the run namespace
+	// holding the per-run token for the Steward API, the namespace and
+	// the name of the pipeline run. It is mounted into the Jenkinsfile
+	// Runner pod by the Jenkinsfile Runner ClusterTask.
+	RunAPITokenSecret = "steward-run-api-token"
+
+	// RunAPIServiceAccountPrefix is the prefix of the names of the
+	// per-run service accounts in the namespaces of pipeline runs, whose
+	// tokens allow pipeline runs to update their progress annotations.
+	// The admission webhook restricts the updates of service accounts
+	// with this prefix.
+	RunAPIServiceAccountPrefix = "steward-run-api"
+
+	// RunControllerRoleBinding is the name of the role binding in each
+	// run namespace granting the run controller access to the namespace
+	// in namespace-scoped mode.
+	RunControllerRoleBinding = "steward-run-controller-run-namespace"
+
+	// provenanceConfigMapPrefix is the prefix of the names of config maps
+	// holding provenance attestations.
+	provenanceConfigMapPrefix = "steward-provenance"
+
+	// statusConfigMapPrefix is the prefix of the names of config maps
+	// holding fields offloaded from the status of pipeline runs.
+	statusConfigMapPrefix = "steward-status"
+)
+
+// RunNamespaceGenerateName returns the value of `metadata.generateName`
+// of a run namespace. All run namespaces of a pipeline run share the
+// same random part and differ in their purpose, e.g. `main`.
+func RunNamespaceGenerateName(randomPart, purpose string) string {
+	return Namespace.GenerateName(RunNamespacePrefix, randomPart, purpose)
+}
+
+// ProvenanceConfigMap returns the name of the config map in the
+// namespace of a pipeline run holding the provenance attestation of the
+// pipeline run with the given name.
+func ProvenanceConfigMap(pipelineRunName string) string {
+	return ConfigMap.Name(provenanceConfigMapPrefix, pipelineRunName)
+}
+
+// StatusConfigMap returns the name of the config map in the namespace of
+// a pipeline run holding the fields offloaded from the status of the
+// pipeline run with the given name.
+func StatusConfigMap(pipelineRunName string) string {
+	return ConfigMap.Name(statusConfigMapPrefix, pipelineRunName)
+}
+
+// RunAPIServiceAccount returns the name of the per-run service account in
+// the namespace of a pipeline run with the given name. The role and role
+// binding granting the service account access to the pipeline run have
+// the same name.
+func RunAPIServiceAccount(pipelineRunName string) string {
+	return ServiceAccount.Name(RunAPIServiceAccountPrefix, pipelineRunName)
+}
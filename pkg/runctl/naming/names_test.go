@@ -0,0 +1,59 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Constants_AreValid(t *testing.T) {
+	t.Parallel()
+
+	assert.NilError(t, TaskRun.Validate(JenkinsfileRunnerTaskRun))
+	assert.NilError(t, ConfigMap.Validate(PipelineParamsConfigMap))
+	assert.NilError(t, ConfigMap.Validate(ProgressConfigMap))
+	assert.NilError(t, Secret.Validate(LogForwardingAuthSecret))
+	assert.NilError(t, Secret.Validate(LogForwardingClientCertSecret))
+	assert.NilError(t, Secret.Validate(LogForwardingCABundleSecret))
+	assert.NilError(t, Secret.Validate(RunAPITokenSecret))
+	assert.NilError(t, RoleBinding.Validate(RunControllerRoleBinding))
+}
+
+func Test_RunNamespaceGenerateName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "steward-run-abcde-main-", RunNamespaceGenerateName("abcde", "main"))
+}
+
+func Test_ProvenanceConfigMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "steward-provenance-run1", ProvenanceConfigMap("run1"))
+
+	long := ProvenanceConfigMap(strings.Repeat("r", 300))
+	assert.Equal(t, ConfigMap.MaxLength(), len(long))
+	assert.NilError(t, ConfigMap.Validate(long))
+}
+
+func Test_StatusConfigMap(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "steward-status-run1", StatusConfigMap("run1"))
+
+	long := StatusConfigMap(strings.Repeat("r", 300))
+	assert.Equal(t, ConfigMap.MaxLength(), len(long))
+	assert.NilError(t, ConfigMap.Validate(long))
+}
+
+func Test_RunAPIServiceAccount(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "steward-run-api-run1", RunAPIServiceAccount("run1"))
+
+	long := RunAPIServiceAccount(strings.Repeat("r", 300))
+	assert.Equal(t, ServiceAccount.MaxLength(), len(long))
+	assert.NilError(t, ServiceAccount.Validate(long))
+	assert.NilError(t, Role.Validate(long))
+	assert.Assert(t, strings.HasPrefix(long, RunAPIServiceAccountPrefix+"-"))
+}
@@ -0,0 +1,160 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+)
+
+// tenantNamespaceRequester requests the creation of tenant namespaces
+// from the tenant controller.
+type tenantNamespaceRequester interface {
+	// RequestTenantNamespace requests the creation of the tenant namespace
+	// of the given tenant. Requesting it multiple times has the same
+	// effect as requesting it once.
+	RequestTenantNamespace(ctx context.Context, tenant *api.Tenant) error
+}
+
+// annotatingTenantNamespaceRequester is a tenantNamespaceRequester setting
+// annotation api.AnnotationTenantNamespaceRequested at tenants, which
+// makes the tenant controller create the tenant namespace.
+type annotatingTenantNamespaceRequester struct {
+	factory k8s.ClientFactory
+}
+
+// RequestTenantNamespace implements interface tenantNamespaceRequester.
+func (r *annotatingTenantNamespaceRequester) RequestTenantNamespace(ctx context.Context, tenant *api.Tenant) error {
+	if _, requested := tenant.GetAnnotations()[api.AnnotationTenantNamespaceRequested]; requested {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				api.AnnotationTenantNamespaceRequested: "",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		_, err := r.factory.StewardV1alpha1().Tenants(tenant.GetNamespace()).Patch(
+			ctx, tenant.GetName(), types.MergePatchType, patch, metav1.PatchOptions{},
+		)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessagef(err,
+			"failed to request tenant namespace of tenant %q in namespace %q", tenant.GetName(), tenant.GetNamespace(),
+		)
+	}
+	klog.V(3).Infof("Requested tenant namespace of tenant %s/%s", tenant.GetNamespace(), tenant.GetName())
+	return nil
+}
+
+// handlePipelineRunOfPendingTenant processes a pipeline run labelled with
+// api.LabelPendingTenant, i.e. a pipeline run created in a client
+// namespace for a tenant without tenant namespace.
+// If the tenant has no tenant namespace, its creation is requested and
+// an error is returned, so that the pipeline run stays in the work queue.
+// Once the tenant namespace exists, the pipeline run is moved into it,
+// i.e. a copy without the label is created in the tenant namespace and
+// the pipeline run in the client namespace gets deleted. The copy is
+// annotated with the UID of the original, so that only the original of
+// an existing copy gets deleted. If a different pipeline run with the
+// same name exists in the tenant namespace, the pipeline run is finished
+// with result `error_content`.
+// If the tenant does not exist, the pipeline run is finished with result
+// `error_content`.
+func (c *Controller) handlePipelineRunOfPendingTenant(ctx context.Context, pipelineRunAPIObj *api.PipelineRun) error {
+	namespace := pipelineRunAPIObj.GetNamespace()
+	tenantName := pipelineRunAPIObj.GetLabels()[api.LabelPendingTenant]
+
+	tenant, err := c.tenantLister.Tenants(namespace).Get(tenantName)
+	if k8serrors.IsNotFound(err) {
+		pipelineRun, err := k8s.NewPipelineRunWithOpts(ctx, pipelineRunAPIObj, c.factory, c.pipelineRunOpts)
+		if err != nil || pipelineRun == nil {
+			return err
+		}
+		err = fmt.Errorf("label %q: tenant %q does not exist in namespace %q", api.LabelPendingTenant, tenantName, namespace)
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+		pipelineRun.StoreErrorAsMessage(err, "preparing failed")
+		return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultErrorContent, metav1.Now())
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get tenant %q in namespace %q", tenantName, namespace)
+	}
+
+	tenantNamespace := tenant.Status.TenantNamespaceName
+	if tenantNamespace == "" {
+		if err := c.tenantNamespaceRequester.RequestTenantNamespace(ctx, tenant); err != nil {
+			return err
+		}
+		// Return error that the pipeline stays in the queue and will be processed after the tenant namespace has been created.
+		return fmt.Errorf("pipeline run is waiting for the creation of the tenant namespace of tenant %q", tenantName)
+	}
+
+	sourceUID := string(pipelineRunAPIObj.GetUID())
+	movedPipelineRun := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pipelineRunAPIObj.GetName(),
+			Namespace:   tenantNamespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *pipelineRunAPIObj.Spec.DeepCopy(),
+	}
+	for key, value := range pipelineRunAPIObj.GetLabels() {
+		if key != api.LabelPendingTenant {
+			movedPipelineRun.Labels[key] = value
+		}
+	}
+	for key, value := range pipelineRunAPIObj.GetAnnotations() {
+		movedPipelineRun.Annotations[key] = value
+	}
+	movedPipelineRun.Annotations[api.AnnotationMovedFromUID] = sourceUID
+	_, err = c.factory.StewardV1alpha1().PipelineRuns(tenantNamespace).Create(ctx, movedPipelineRun, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		// The pipeline run has been moved before if the existing one is
+		// our copy. Otherwise a different pipeline run with the same name
+		// exists in the tenant namespace and we must not delete ours.
+		existing, err := c.factory.StewardV1alpha1().PipelineRuns(tenantNamespace).Get(ctx, pipelineRunAPIObj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return errors.WithMessagef(err,
+				"failed to get pipeline run %q in tenant namespace %q", pipelineRunAPIObj.GetName(), tenantNamespace,
+			)
+		}
+		if existing.GetAnnotations()[api.AnnotationMovedFromUID] != sourceUID {
+			pipelineRun, err := k8s.NewPipelineRunWithOpts(ctx, pipelineRunAPIObj, c.factory, c.pipelineRunOpts)
+			if err != nil || pipelineRun == nil {
+				return err
+			}
+			err = fmt.Errorf("cannot move pipeline run into tenant namespace %q: a pipeline run with the same name exists there", tenantNamespace)
+			c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+			pipelineRun.StoreErrorAsMessage(err, "preparing failed")
+			return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultErrorContent, metav1.Now())
+		}
+	} else if err != nil {
+		return errors.WithMessagef(err,
+			"failed to move pipeline run %q into tenant namespace %q", pipelineRunAPIObj.GetName(), tenantNamespace,
+		)
+	}
+	err = c.factory.StewardV1alpha1().PipelineRuns(namespace).Delete(ctx, pipelineRunAPIObj.GetName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.WithMessagef(err,
+			"failed to delete pipeline run %q in namespace %q after moving it", pipelineRunAPIObj.GetName(), namespace,
+		)
+	}
+	klog.V(3).Infof("Moved pipeline run %s/%s into tenant namespace %q", namespace, pipelineRunAPIObj.GetName(), tenantNamespace)
+	return nil
+}
@@ -0,0 +1,152 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPendingTenantTestRun() *api.PipelineRun {
+	run := fake.PipelineRun("run1", "client1", api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{URL: "https://git.example.com/org/repo.git"},
+	})
+	run.SetUID("uid1")
+	run.SetLabels(map[string]string{
+		api.LabelPendingTenant: "tenant1",
+		"other":                "value1",
+	})
+	return run
+}
+
+func addTenant(t *testing.T, cf *fake.ClientFactory, tenant *api.Tenant) {
+	t.Helper()
+	tenant, err := cf.StewardV1alpha1().Tenants(tenant.GetNamespace()).Create(context.Background(), tenant, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	err = cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore().Add(tenant)
+	assert.NilError(t, err)
+}
+
+func Test_Controller_handlePipelineRunOfPendingTenant_RequestsTenantNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPendingTenantTestRun()
+	controller, cf := newController(run)
+	addTenant(t, cf, fake.Tenant("tenant1", "client1"))
+
+	// EXERCISE
+	err := controller.handlePipelineRunOfPendingTenant(ctx, run)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `pipeline run is waiting for the creation of the tenant namespace of tenant "tenant1"`)
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	_, requested := tenant.GetAnnotations()[api.AnnotationTenantNamespaceRequested]
+	assert.Assert(t, requested)
+	_, err = cf.StewardV1alpha1().PipelineRuns("client1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_Controller_handlePipelineRunOfPendingTenant_MovesPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPendingTenantTestRun()
+	controller, cf := newController(run)
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	addTenant(t, cf, tenant)
+
+	// EXERCISE
+	err := controller.handlePipelineRunOfPendingTenant(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	_, err = cf.StewardV1alpha1().PipelineRuns("client1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	moved, err := cf.StewardV1alpha1().PipelineRuns("tenantns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"other": "value1"}, moved.GetLabels())
+	assert.Equal(t, "uid1", moved.GetAnnotations()[api.AnnotationMovedFromUID])
+	assert.DeepEqual(t, run.Spec, moved.Spec)
+	assert.Equal(t, api.StateUndefined, moved.Status.State)
+}
+
+func Test_Controller_handlePipelineRunOfPendingTenant_AlreadyMoved(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPendingTenantTestRun()
+	movedRun := fake.PipelineRun("run1", "tenantns1", run.Spec)
+	movedRun.SetAnnotations(map[string]string{api.AnnotationMovedFromUID: "uid1"})
+	controller, cf := newController(run, movedRun)
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	addTenant(t, cf, tenant)
+
+	// EXERCISE
+	err := controller.handlePipelineRunOfPendingTenant(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	_, err = cf.StewardV1alpha1().PipelineRuns("client1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	_, err = cf.StewardV1alpha1().PipelineRuns("tenantns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_Controller_handlePipelineRunOfPendingTenant_NameClash(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPendingTenantTestRun()
+	otherRun := fake.PipelineRun("run1", "tenantns1", api.PipelineSpec{})
+	controller, cf := newController(run, otherRun)
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	addTenant(t, cf, tenant)
+
+	// EXERCISE
+	err := controller.handlePipelineRunOfPendingTenant(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	pipelineRun, err := cf.StewardV1alpha1().PipelineRuns("client1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, pipelineRun.Status.State)
+	assert.Equal(t, api.ResultErrorContent, pipelineRun.Status.Result)
+	assert.Assert(t, is.Contains(pipelineRun.Status.Message, `a pipeline run with the same name exists there`))
+	existing, err := cf.StewardV1alpha1().PipelineRuns("tenantns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, otherRun.Spec, existing.Spec)
+}
+
+func Test_Controller_handlePipelineRunOfPendingTenant_TenantNotExisting(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPendingTenantTestRun()
+	controller, cf := newController(run)
+
+	// EXERCISE
+	err := controller.handlePipelineRunOfPendingTenant(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	pipelineRun, err := cf.StewardV1alpha1().PipelineRuns("client1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, pipelineRun.Status.State)
+	assert.Equal(t, api.ResultErrorContent, pipelineRun.Status.Result)
+	assert.Assert(t, is.Contains(pipelineRun.Status.Message, `tenant "tenant1" does not exist in namespace "client1"`))
+}
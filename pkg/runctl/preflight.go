@@ -0,0 +1,67 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/preflight"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// checkPreflight performs the configured pre-flight checks for the given
+// pipeline run, which must be in state `new` or `queued`.
+// If all checks pass, condition `PreflightChecksPassed` is set to true
+// and nil is returned. The condition is committed together with the
+// subsequent state change.
+// Otherwise the condition is set to false with the reason and message of
+// the failure and committed, and an error is returned to keep the
+// pipeline run queued. Condition `Scheduling` is set accordingly. The
+// conditions are only committed and an event is only emitted if the
+// failure differs from the recorded one.
+func (c *Controller) checkPreflight(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if len(c.preflightChecks) == 0 {
+		return nil
+	}
+
+	checkErr := preflight.Run(ctx, c.preflightChecks, pipelineRun.GetAPIObject())
+	if checkErr == nil {
+		pipelineRun.UpdateCondition(metav1.Condition{
+			Type:    api.PipelineRunConditionPreflightChecksPassed,
+			Status:  metav1.ConditionTrue,
+			Reason:  api.PreflightChecksPassedReason,
+			Message: "all pre-flight checks passed",
+		})
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:    api.PipelineRunConditionPreflightChecksPassed,
+		Status:  metav1.ConditionFalse,
+		Reason:  api.PreflightChecksErrorReason,
+		Message: checkErr.Error(),
+	}
+	var failure *preflight.Failure
+	if errors.As(checkErr, &failure) {
+		condition.Reason = failure.Reason
+	}
+
+	current := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, condition.Type)
+	changed := current == nil || current.Status != condition.Status || current.Reason != condition.Reason || current.Message != condition.Message
+	if changed {
+		klog.V(3).Infof("pre-flight check failed for pipeline run %q: %s", pipelineRun.GetKey(), checkErr.Error())
+		c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, api.EventReasonPreflightCheckFailed, checkErr.Error())
+		pipelineRun.UpdateCondition(condition)
+	}
+	if schedulingChanged := setSchedulingDelayed(pipelineRun, condition.Reason, condition.Message); changed || schedulingChanged {
+		if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("pipeline run is queued because a pre-flight check failed: %s", checkErr.Error())
+}
@@ -0,0 +1,345 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/revision"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// CheckCapacity is the name of the check for pending pods in the
+	// cluster.
+	CheckCapacity = "capacity"
+
+	// CheckQuota is the name of the check for resource quota headroom in
+	// the namespace of the pipeline run.
+	CheckQuota = "quota"
+
+	// CheckImagePullSecrets is the name of the check for the existence of
+	// the image pull secrets of the pipeline run.
+	CheckImagePullSecrets = "image-pull-secrets"
+
+	// CheckRepository is the name of the check for the reachability of
+	// the pipeline repository.
+	CheckRepository = "repository"
+
+	// DefaultMaxPendingPods is the maximum number of pending pods in the
+	// cluster accepted by the capacity check if not configured otherwise.
+	DefaultMaxPendingPods = 50
+
+	// DefaultDialTimeout is the timeout for connecting to the host of the
+	// pipeline repository if not configured otherwise.
+	DefaultDialTimeout = 5 * time.Second
+)
+
+// Failure is returned by checks if a pipeline run should not be started
+// yet.
+type Failure struct {
+	// Reason is a machine-readable reason in CamelCase.
+	Reason string
+
+	// Message is a human-readable description of the failure.
+	Message string
+}
+
+// Error implements interface error.
+func (f *Failure) Error() string {
+	return f.Message
+}
+
+// Check is a pre-flight check performed before a pipeline run is started.
+type Check interface {
+	// Name returns the name of the check used in the configuration.
+	Name() string
+
+	// Check checks whether the given pipeline run can be started.
+	// It returns a *Failure if the pipeline run should not be started
+	// yet and other errors if the check could not be performed.
+	Check(ctx context.Context, run *api.PipelineRun) error
+}
+
+// Options configures the checks.
+type Options struct {
+	// MaxPendingPods is the maximum number of pending pods in the cluster
+	// accepted by the capacity check. If zero or negative,
+	// DefaultMaxPendingPods is used.
+	MaxPendingPods int
+
+	// DialTimeout is the timeout for connecting to the host of the
+	// pipeline repository. If zero or negative, DefaultDialTimeout is
+	// used.
+	DialTimeout time.Duration
+
+	// RepositoryAllowedHosts returns the host names the repository check
+	// may connect to, e.g. the hosts allowed for resolving revisions. An
+	// entry starting with a dot matches all subdomains. Repositories on
+	// other hosts are not checked. If nil, no repository is checked.
+	RepositoryAllowedHosts func(ctx context.Context) ([]string, error)
+}
+
+// CachedCheck is a Check reading from an informer cache. The informer
+// must be running and synced before the check is performed.
+type CachedCheck interface {
+	Check
+
+	// Informer returns the informer of the check.
+	Informer() cache.SharedIndexInformer
+}
+
+// NewChecks creates the checks with the given names in the given order.
+func NewChecks(names []string, client corev1client.CoreV1Interface, opts Options) ([]Check, error) {
+	if opts.MaxPendingPods <= 0 {
+		opts.MaxPendingPods = DefaultMaxPendingPods
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = DefaultDialTimeout
+	}
+	var checks []Check
+	for _, name := range names {
+		switch name {
+		case CheckCapacity:
+			checks = append(checks, newCapacityCheck(client, opts.MaxPendingPods))
+		case CheckQuota:
+			checks = append(checks, &quotaCheck{client: client})
+		case CheckImagePullSecrets:
+			checks = append(checks, &imagePullSecretsCheck{client: client})
+		case CheckRepository:
+			dialer := &net.Dialer{Timeout: opts.DialTimeout}
+			checks = append(checks, &repositoryCheck{dial: dialer.DialContext, allowedHosts: opts.RepositoryAllowedHosts})
+		default:
+			return nil, fmt.Errorf("unknown pre-flight check %q", name)
+		}
+	}
+	return checks, nil
+}
+
+// Run performs the given checks in order and returns the error of the
+// first check which did not pass.
+func Run(ctx context.Context, checks []Check, run *api.PipelineRun) error {
+	for _, check := range checks {
+		if err := check.Check(ctx, run); err != nil {
+			var failure *Failure
+			if errors.As(err, &failure) {
+				return err
+			}
+			return errors.WithMessagef(err, "pre-flight check %q failed", check.Name())
+		}
+	}
+	return nil
+}
+
+// capacityCheck fails if the number of pending pods in the cluster
+// exceeds a maximum, i.e. if the cluster is likely out of capacity.
+// The pending pods are read from an informer cache.
+type capacityCheck struct {
+	informer       cache.SharedIndexInformer
+	lister         corev1listers.PodLister
+	maxPendingPods int
+}
+
+// let compiler verify interface compliance
+var _ CachedCheck = (*capacityCheck)(nil)
+
+// newCapacityCheck returns a capacityCheck with an informer for the
+// pending pods in all namespaces.
+func newCapacityCheck(client corev1client.CoreV1Interface, maxPendingPods int) *capacityCheck {
+	selector := fields.OneTermEqualSelector("status.phase", string(corev1.PodPending)).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return client.Pods(metav1.NamespaceAll).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return client.Pods(metav1.NamespaceAll).Watch(context.TODO(), options)
+		},
+	}
+	informer := cache.NewSharedIndexInformer(listWatch, &corev1.Pod{}, 0, cache.Indexers{})
+	return &capacityCheck{
+		informer:       informer,
+		lister:         corev1listers.NewPodLister(informer.GetIndexer()),
+		maxPendingPods: maxPendingPods,
+	}
+}
+
+func (c *capacityCheck) Name() string {
+	return CheckCapacity
+}
+
+func (c *capacityCheck) Informer() cache.SharedIndexInformer {
+	return c.informer
+}
+
+func (c *capacityCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	pods, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return errors.WithMessage(err, "failed to list pending pods")
+	}
+	if pending := len(pods); pending > c.maxPendingPods {
+		return &Failure{
+			Reason:  "InsufficientCapacity",
+			Message: fmt.Sprintf("%d pods are pending in the cluster (maximum: %d)", pending, c.maxPendingPods),
+		}
+	}
+	return nil
+}
+
+// quotaCheck fails if a resource quota in the namespace of the pipeline
+// run is exhausted.
+type quotaCheck struct {
+	client corev1client.CoreV1Interface
+}
+
+func (c *quotaCheck) Name() string {
+	return CheckQuota
+}
+
+func (c *quotaCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	quotas, err := c.client.ResourceQuotas(run.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list resource quotas in namespace %q", run.GetNamespace())
+	}
+	for _, quota := range quotas.Items {
+		resourceNames := make([]string, 0, len(quota.Status.Hard))
+		for resourceName := range quota.Status.Hard {
+			resourceNames = append(resourceNames, string(resourceName))
+		}
+		sort.Strings(resourceNames)
+		for _, resourceName := range resourceNames {
+			hard := quota.Status.Hard[corev1.ResourceName(resourceName)]
+			used, found := quota.Status.Used[corev1.ResourceName(resourceName)]
+			// resources with a hard limit of zero are not used at all
+			if found && !hard.IsZero() && used.Cmp(hard) >= 0 {
+				return &Failure{
+					Reason: "QuotaExhausted",
+					Message: fmt.Sprintf("resource quota %q in namespace %q is exhausted for %s: used %s of %s",
+						quota.GetName(), run.GetNamespace(), resourceName, used.String(), hard.String()),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// imagePullSecretsCheck fails if an image pull secret of the pipeline run
+// does not exist.
+type imagePullSecretsCheck struct {
+	client corev1client.CoreV1Interface
+}
+
+func (c *imagePullSecretsCheck) Name() string {
+	return CheckImagePullSecrets
+}
+
+func (c *imagePullSecretsCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	for _, name := range run.Spec.ImagePullSecrets {
+		_, err := c.client.Secrets(run.GetNamespace()).Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return &Failure{
+				Reason:  "ImagePullSecretMissing",
+				Message: fmt.Sprintf("image pull secret %q does not exist in namespace %q", name, run.GetNamespace()),
+			}
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get image pull secret %q", name)
+		}
+	}
+	return nil
+}
+
+// repositoryCheck fails if the host of the pipeline repository cannot be
+// connected. Only hosts returned by allowedHosts are connected, so that
+// pipeline runs cannot probe arbitrary, e.g. cluster-internal, endpoints.
+// The failure message does not contain the connection error for the same
+// reason.
+type repositoryCheck struct {
+	dial         func(ctx context.Context, network, address string) (net.Conn, error)
+	allowedHosts func(ctx context.Context) ([]string, error)
+}
+
+func (c *repositoryCheck) Name() string {
+	return CheckRepository
+}
+
+func (c *repositoryCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	address := repositoryAddress(run.Spec.JenkinsFile.URL)
+	if address == "" || c.allowedHosts == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil
+	}
+	allowedHosts, err := c.allowedHosts(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the allowed pipeline repository hosts")
+	}
+	if !revision.IsHostAllowed(host, allowedHosts) {
+		return nil
+	}
+	conn, err := c.dial(ctx, "tcp", address)
+	if err != nil {
+		klog.V(4).Infof("pipeline repository host %s of pipeline run %s/%s is not reachable: %s", address, run.GetNamespace(), run.GetName(), err.Error())
+		return &Failure{
+			Reason:  "RepositoryUnreachable",
+			Message: "pipeline repository host is not reachable",
+		}
+	}
+	conn.Close()
+	return nil
+}
+
+// repositoryAddress returns the network address (host and port) of the
+// given repository URL or an empty string if the URL has no host.
+// SCP-like URLs like `git@host:path` are supported, too.
+func repositoryAddress(repoURL string) string {
+	if !strings.Contains(repoURL, "://") {
+		if i := strings.Index(repoURL, ":"); i > 0 {
+			host := repoURL[:i]
+			if j := strings.LastIndex(host, "@"); j >= 0 {
+				host = host[j+1:]
+			}
+			if host != "" && !strings.Contains(host, "/") {
+				return net.JoinHostPort(host, "22")
+			}
+		}
+		return ""
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	port := parsed.Port()
+	if port == "" {
+		switch parsed.Scheme {
+		case "http":
+			port = "80"
+		case "ssh", "git+ssh":
+			port = "22"
+		case "git":
+			port = "9418"
+		default:
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(parsed.Hostname(), port)
+}
@@ -0,0 +1,339 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestRun(spec api.PipelineSpec) *api.PipelineRun {
+	return &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: "client1"},
+		Spec:       spec,
+	}
+}
+
+func newPendingPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns1"},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func Test_NewChecks(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := kubernetes.NewSimpleClientset().CoreV1()
+
+	// EXERCISE
+	checks, err := NewChecks([]string{CheckRepository, CheckCapacity, CheckQuota, CheckImagePullSecrets}, client, Options{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name())
+	}
+	assert.DeepEqual(t, []string{CheckRepository, CheckCapacity, CheckQuota, CheckImagePullSecrets}, names)
+	assert.Equal(t, DefaultMaxPendingPods, checks[1].(*capacityCheck).maxPendingPods)
+}
+
+func Test_NewChecks_Unknown(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	checks, err := NewChecks([]string{CheckQuota, "foo"}, kubernetes.NewSimpleClientset().CoreV1(), Options{})
+
+	// VERIFY
+	assert.Error(t, err, `unknown pre-flight check "foo"`)
+	assert.Assert(t, checks == nil)
+}
+
+func Test_Run(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		errors        []error
+		expectedError string
+		isFailure     bool
+	}{
+		{"all passed", []error{nil, nil}, "", false},
+		{"failure", []error{nil, &Failure{Reason: "R1", Message: "m1"}, fmt.Errorf("not reached")}, "m1", true},
+		{"error", []error{fmt.Errorf("e1"), nil}, `pre-flight check "check0" failed: e1`, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			var checks []Check
+			for i, err := range tc.errors {
+				checks = append(checks, &stubCheck{name: fmt.Sprintf("check%d", i), err: err})
+			}
+
+			// EXERCISE
+			err := Run(context.Background(), checks, newTestRun(api.PipelineSpec{}))
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+				return
+			}
+			assert.Error(t, err, tc.expectedError)
+			var failure *Failure
+			assert.Equal(t, tc.isFailure, errors.As(err, &failure))
+		})
+	}
+}
+
+func Test_capacityCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		pendingPods   int
+		expectedError string
+	}{
+		{"below maximum", 1, ""},
+		{"at maximum", 2, ""},
+		{"above maximum", 3, "3 pods are pending in the cluster (maximum: 2)"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := newCapacityCheck(kubernetes.NewSimpleClientset().CoreV1(), 2)
+			for i := 0; i < tc.pendingPods; i++ {
+				err := examinee.Informer().GetStore().Add(newPendingPod(fmt.Sprintf("pod%d", i)))
+				assert.NilError(t, err)
+			}
+
+			// EXERCISE
+			err := examinee.Check(context.Background(), newTestRun(api.PipelineSpec{}))
+
+			// VERIFY
+			assertFailure(t, err, "InsufficientCapacity", tc.expectedError)
+		})
+	}
+}
+
+func Test_quotaCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		hard          corev1.ResourceList
+		used          corev1.ResourceList
+		expectedError string
+	}{
+		{
+			name: "headroom",
+			hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+			used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("9")},
+		},
+		{
+			name:          "exhausted",
+			hard:          corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10"), corev1.ResourceLimitsCPU: resource.MustParse("2")},
+			used:          corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10"), corev1.ResourceLimitsCPU: resource.MustParse("2")},
+			expectedError: `resource quota "quota1" in namespace "client1" is exhausted for limits.cpu: used 2 of 2`,
+		},
+		{
+			name: "zero hard limit ignored",
+			hard: corev1.ResourceList{corev1.ResourceServices: resource.MustParse("0")},
+			used: corev1.ResourceList{corev1.ResourceServices: resource.MustParse("0")},
+		},
+		{
+			name: "not used",
+			hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			quota := &corev1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota1", Namespace: "client1"},
+				Status:     corev1.ResourceQuotaStatus{Hard: tc.hard, Used: tc.used},
+			}
+			examinee := &quotaCheck{client: kubernetes.NewSimpleClientset(quota).CoreV1()}
+
+			// EXERCISE
+			err := examinee.Check(context.Background(), newTestRun(api.PipelineSpec{}))
+
+			// VERIFY
+			assertFailure(t, err, "QuotaExhausted", tc.expectedError)
+		})
+	}
+}
+
+func Test_imagePullSecretsCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		secrets       []string
+		expectedError string
+	}{
+		{"none", nil, ""},
+		{"existing", []string{"secret1"}, ""},
+		{"missing", []string{"secret1", "secret2"}, `image pull secret "secret2" does not exist in namespace "client1"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "client1"}}
+			examinee := &imagePullSecretsCheck{client: kubernetes.NewSimpleClientset(secret).CoreV1()}
+
+			// EXERCISE
+			err := examinee.Check(context.Background(), newTestRun(api.PipelineSpec{ImagePullSecrets: tc.secrets}))
+
+			// VERIFY
+			assertFailure(t, err, "ImagePullSecretMissing", tc.expectedError)
+		})
+	}
+}
+
+func Test_repositoryCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		url             string
+		allowedHosts    []string
+		dialErr         error
+		expectedAddress string
+		expectedError   string
+	}{
+		{"reachable", "https://github.com/org/repo", []string{"github.com"}, nil, "github.com:443", ""},
+		{"unreachable", "https://github.com/org/repo", []string{"github.com"}, fmt.Errorf("timeout"), "github.com:443", "pipeline repository host is not reachable"},
+		{"subdomain allowed", "https://git.example.com/org/repo", []string{".example.com"}, nil, "git.example.com:443", ""},
+		{"host not allowed", "https://kubernetes.default.svc:443/api", []string{"github.com"}, nil, "", ""},
+		{"no allowed hosts", "https://github.com/org/repo", nil, nil, "", ""},
+		{"no host", "repo", []string{"github.com"}, nil, "", ""},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			var dialedAddress string
+			examinee := &repositoryCheck{
+				dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+					dialedAddress = address
+					if tc.dialErr != nil {
+						return nil, tc.dialErr
+					}
+					client, server := net.Pipe()
+					server.Close()
+					return client, nil
+				},
+				allowedHosts: func(ctx context.Context) ([]string, error) {
+					return tc.allowedHosts, nil
+				},
+			}
+			run := newTestRun(api.PipelineSpec{JenkinsFile: api.JenkinsFile{URL: tc.url}})
+
+			// EXERCISE
+			err := examinee.Check(context.Background(), run)
+
+			// VERIFY
+			assertFailure(t, err, "RepositoryUnreachable", tc.expectedError)
+			assert.Equal(t, tc.expectedAddress, dialedAddress)
+		})
+	}
+}
+
+func Test_repositoryCheck_AllowedHostsError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &repositoryCheck{
+		dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			t.Fatalf("unexpected dial of %s", address)
+			return nil, nil
+		},
+		allowedHosts: func(ctx context.Context) ([]string, error) {
+			return nil, errors.New("foo")
+		},
+	}
+	run := newTestRun(api.PipelineSpec{JenkinsFile: api.JenkinsFile{URL: "https://github.com/org/repo"}})
+
+	// EXERCISE
+	err := examinee.Check(context.Background(), run)
+
+	// VERIFY
+	assert.Error(t, err, "failed to get the allowed pipeline repository hosts: foo")
+}
+
+func Test_repositoryAddress(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/org/repo.git", "github.com:443"},
+		{"https://github.com:8443/org/repo.git", "github.com:8443"},
+		{"http://git.example.com/repo", "git.example.com:80"},
+		{"ssh://git@git.example.com/repo", "git.example.com:22"},
+		{"git+ssh://git.example.com/repo", "git.example.com:22"},
+		{"git://git.example.com/repo", "git.example.com:9418"},
+		{"git@github.com:org/repo.git", "github.com:22"},
+		{"https://[::1]/repo", "[::1]:443"},
+		{"file:///repo", ""},
+		{"repo", ""},
+		{"/path/to/repo", ""},
+		{"", ""},
+	} {
+		tc := tc
+		t.Run(tc.url, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := repositoryAddress(tc.url)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func assertFailure(t *testing.T, err error, expectedReason, expectedMessage string) {
+	t.Helper()
+	if expectedMessage == "" {
+		assert.NilError(t, err)
+		return
+	}
+	failure, ok := err.(*Failure)
+	assert.Assert(t, ok, "not a failure: %v", err)
+	assert.Equal(t, expectedReason, failure.Reason)
+	assert.Equal(t, expectedMessage, failure.Message)
+}
+
+type stubCheck struct {
+	name string
+	err  error
+}
+
+func (c *stubCheck) Name() string {
+	return c.name
+}
+
+func (c *stubCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	return c.err
+}
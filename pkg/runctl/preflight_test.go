@@ -0,0 +1,105 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/preflight"
+	assert "gotest.tools/assert"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+type stubPreflightCheck struct {
+	err error
+}
+
+func (c *stubPreflightCheck) Name() string {
+	return "stub"
+}
+
+func (c *stubPreflightCheck) Check(ctx context.Context, run *api.PipelineRun) error {
+	return c.err
+}
+
+func Test_Controller_checkPreflight_Passed(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	controller.preflightChecks = []preflight.Check{&stubPreflightCheck{}}
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	err := controller.checkPreflight(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	condition := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionPreflightChecksPassed)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, api.PreflightChecksPassedReason, condition.Reason)
+}
+
+func Test_Controller_checkPreflight_Failed(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	recorder := record.NewFakeRecorder(20)
+	controller.recorder = recorder
+	controller.preflightChecks = []preflight.Check{
+		&stubPreflightCheck{err: &preflight.Failure{Reason: "QuotaExhausted", Message: "quota exhausted"}},
+	}
+
+	// EXERCISE
+	err := controller.checkPreflight(ctx, getPipelineRun(t, "run1", "ns1", cf))
+
+	// VERIFY
+	assert.Error(t, err, "pipeline run is queued because a pre-flight check failed: quota exhausted")
+	apiRun, err := getAPIPipelineRun(cf, "run1", "ns1")
+	assert.NilError(t, err)
+	condition := apimeta.FindStatusCondition(apiRun.Status.Conditions, api.PipelineRunConditionPreflightChecksPassed)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "QuotaExhausted", condition.Reason)
+	assert.Equal(t, "quota exhausted", condition.Message)
+	condition = apimeta.FindStatusCondition(apiRun.Status.Conditions, api.PipelineRunConditionScheduling)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "QuotaExhausted", condition.Reason)
+	assert.Equal(t, "quota exhausted", condition.Message)
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Equal(t, "Normal PreflightCheckFailed quota exhausted", <-recorder.Events)
+
+	// EXERCISE again with the same failure
+	err = controller.checkPreflight(ctx, getPipelineRun(t, "run1", "ns1", cf))
+
+	// VERIFY no further event
+	assert.ErrorContains(t, err, "quota exhausted")
+	assert.Equal(t, 0, len(recorder.Events))
+}
+
+func Test_Controller_checkPreflight_NoChecks(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	err := controller.checkPreflight(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, pipelineRun.GetStatus().Conditions == nil)
+}
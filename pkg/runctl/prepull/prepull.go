@@ -0,0 +1,203 @@
+package prepull
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	steward "github.com/SAP/stewardci-core/pkg/apis/steward"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// DefaultName is the default name of the prepull DaemonSet.
+	DefaultName = "steward-image-prepull"
+
+	// DefaultPauseImage is the default image of the container keeping
+	// the pods of the prepull DaemonSet alive.
+	DefaultPauseImage = "k8s.gcr.io/pause:3.6"
+
+	// imagesAnnotation is the annotation of the prepull DaemonSet listing
+	// the prepulled images. It is used to detect changes.
+	imagesAnnotation = steward.GroupName + "/prepull-images"
+
+	// appLabel is the label selecting the pods of the prepull DaemonSet.
+	appLabel = "app.kubernetes.io/name"
+)
+
+// Options are the options of a Manager.
+type Options struct {
+	// Namespace is the namespace of the prepull DaemonSet.
+	Namespace string
+
+	// Name is the name of the prepull DaemonSet.
+	// If empty, DefaultName is used.
+	Name string
+
+	// NodeSelector selects the nodes to prepull images on, e.g. the node
+	// pool of run pods.
+	// If empty, images are prepulled on all nodes.
+	NodeSelector map[string]string
+
+	// Tolerations are the tolerations of the pods of the prepull
+	// DaemonSet, e.g. for the taints of a dedicated node pool.
+	Tolerations []corev1.Toleration
+
+	// PauseImage is the image of the container keeping the pods of the
+	// prepull DaemonSet alive after the images have been pulled.
+	// If empty, DefaultPauseImage is used.
+	PauseImage string
+}
+
+// Manager manages a DaemonSet pulling container images on the nodes of a
+// cluster, so that pods using these images start without waiting for
+// the image pull.
+// Each image is pulled by an init container running `sh -c "exit 0"`,
+// i.e. the images must contain a shell.
+type Manager struct {
+	client appsv1client.DaemonSetsGetter
+	opts   Options
+}
+
+// NewManager returns a Manager maintaining the prepull DaemonSet with the
+// given client and options.
+func NewManager(client appsv1client.DaemonSetsGetter, opts Options) *Manager {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+	if opts.PauseImage == "" {
+		opts.PauseImage = DefaultPauseImage
+	}
+	return &Manager{client: client, opts: opts}
+}
+
+// Sync ensures that the prepull DaemonSet pulls exactly the given images.
+// The DaemonSet is created if it does not exist, updated if the images
+// have changed and deleted if there are no images.
+func (m *Manager) Sync(ctx context.Context, images []string) error {
+	images = normalize(images)
+	client := m.client.DaemonSets(m.opts.Namespace)
+
+	existing, err := client.Get(ctx, m.opts.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to get prepull daemon set %q", m.opts.Name)
+	}
+	if k8serrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	if len(images) == 0 {
+		if existing == nil {
+			return nil
+		}
+		klog.V(3).Infof("deleting prepull daemon set %q", m.opts.Name)
+		err := client.Delete(ctx, m.opts.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete prepull daemon set %q", m.opts.Name)
+		}
+		return nil
+	}
+
+	desired := m.daemonSet(images)
+	if existing == nil {
+		klog.V(3).Infof("creating prepull daemon set %q for images %v", m.opts.Name, images)
+		if _, err := client.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return errors.Wrapf(err, "failed to create prepull daemon set %q", m.opts.Name)
+		}
+		return nil
+	}
+	if existing.GetAnnotations()[imagesAnnotation] == desired.GetAnnotations()[imagesAnnotation] {
+		return nil
+	}
+	klog.V(3).Infof("updating prepull daemon set %q for images %v", m.opts.Name, images)
+	existing = existing.DeepCopy()
+	existing.SetLabels(desired.GetLabels())
+	existing.SetAnnotations(desired.GetAnnotations())
+	existing.Spec = desired.Spec
+	if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update prepull daemon set %q", m.opts.Name)
+	}
+	return nil
+}
+
+// daemonSet returns the prepull DaemonSet for the given images.
+func (m *Manager) daemonSet(images []string) *appsv1.DaemonSet {
+	podLabels := map[string]string{appLabel: m.opts.Name}
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("32Mi"),
+		},
+	}
+	var initContainers []corev1.Container
+	for i, image := range images {
+		initContainers = append(initContainers, corev1.Container{
+			Name:            fmt.Sprintf("prepull-%d", i),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"sh", "-c", "exit 0"},
+			Resources:       resources,
+		})
+	}
+	automountServiceAccountToken := false
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        m.opts.Name,
+			Namespace:   m.opts.Namespace,
+			Labels:      map[string]string{appLabel: m.opts.Name},
+			Annotations: map[string]string{imagesAnnotation: strings.Join(images, ",")},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			UpdateStrategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: &automountServiceAccountToken,
+					NodeSelector:                 m.opts.NodeSelector,
+					Tolerations:                  m.opts.Tolerations,
+					InitContainers:               initContainers,
+					Containers: []corev1.Container{{
+						Name:      "pause",
+						Image:     m.opts.PauseImage,
+						Resources: resources,
+					}},
+				},
+			},
+		},
+	}
+	slabels.LabelAsSystemManaged(daemonSet)
+	return daemonSet
+}
+
+// normalize returns the given images sorted and without empty entries
+// and duplicates.
+func normalize(images []string) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, image := range images {
+		image = strings.TrimSpace(image)
+		if image != "" && !seen[image] {
+			seen[image] = true
+			result = append(result, image)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
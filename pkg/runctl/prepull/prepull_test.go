@@ -0,0 +1,121 @@
+package prepull
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Manager_Sync_Create(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	examinee := NewManager(clientset.AppsV1(), Options{
+		Namespace:    "steward-system",
+		NodeSelector: map[string]string{"pool": "runs"},
+	})
+
+	// EXERCISE
+	err := examinee.Sync(ctx, []string{"image2:1", "image1:1", "", "image2:1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	daemonSet, err := clientset.AppsV1().DaemonSets("steward-system").Get(ctx, DefaultName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "image1:1,image2:1", daemonSet.GetAnnotations()[imagesAnnotation])
+	podSpec := daemonSet.Spec.Template.Spec
+	assert.DeepEqual(t, map[string]string{"pool": "runs"}, podSpec.NodeSelector)
+	assert.Equal(t, 2, len(podSpec.InitContainers))
+	assert.Equal(t, "image1:1", podSpec.InitContainers[0].Image)
+	assert.DeepEqual(t, []string{"sh", "-c", "exit 0"}, podSpec.InitContainers[0].Command)
+	assert.Equal(t, "image2:1", podSpec.InitContainers[1].Image)
+	assert.Equal(t, DefaultPauseImage, podSpec.Containers[0].Image)
+	assert.DeepEqual(t, daemonSet.Spec.Selector.MatchLabels, daemonSet.Spec.Template.GetLabels())
+}
+
+func Test_Manager_Sync_Update(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	examinee := NewManager(clientset.AppsV1(), Options{Namespace: "steward-system", Name: "prepull1"})
+	assert.NilError(t, examinee.Sync(ctx, []string{"image1:1"}))
+
+	// EXERCISE
+	err := examinee.Sync(ctx, []string{"image1:2"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	daemonSet, err := clientset.AppsV1().DaemonSets("steward-system").Get(ctx, "prepull1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "image1:2", daemonSet.GetAnnotations()[imagesAnnotation])
+	assert.Equal(t, "image1:2", daemonSet.Spec.Template.Spec.InitContainers[0].Image)
+}
+
+func Test_Manager_Sync_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	examinee := NewManager(clientset.AppsV1(), Options{Namespace: "steward-system"})
+	assert.NilError(t, examinee.Sync(ctx, []string{"image1:1"}))
+	clientset.ClearActions()
+
+	// EXERCISE
+	err := examinee.Sync(ctx, []string{"image1:1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	actions := clientset.Actions()
+	assert.Equal(t, 1, len(actions))
+	assert.Equal(t, "get", actions[0].GetVerb())
+}
+
+func Test_Manager_Sync_NoImages(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	examinee := NewManager(clientset.AppsV1(), Options{Namespace: "steward-system"})
+	assert.NilError(t, examinee.Sync(ctx, []string{"image1:1"}))
+
+	// EXERCISE
+	err1 := examinee.Sync(ctx, nil)
+	err2 := examinee.Sync(ctx, nil)
+
+	// VERIFY
+	assert.NilError(t, err1)
+	assert.NilError(t, err2)
+	_, err := clientset.AppsV1().DaemonSets("steward-system").Get(ctx, DefaultName, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func Test_Manager_Sync_Tolerations(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	tolerations := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "runs", Effect: corev1.TaintEffectNoSchedule}}
+	examinee := NewManager(clientset.AppsV1(), Options{Namespace: "steward-system", Tolerations: tolerations, PauseImage: "pause:1"})
+
+	// EXERCISE
+	err := examinee.Sync(ctx, []string{"image1:1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	daemonSet, err := clientset.AppsV1().DaemonSets("steward-system").Get(ctx, DefaultName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tolerations, daemonSet.Spec.Template.Spec.Tolerations)
+	assert.Equal(t, "pause:1", daemonSet.Spec.Template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,197 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// progressConfigMapKeyStage is the key of the config map entry
+	// containing the name of the currently executed stage.
+	progressConfigMapKeyStage = "stage"
+
+	// progressConfigMapKeyStageIndex is the key of the config map entry
+	// containing the 1-based index of the currently executed stage.
+	progressConfigMapKeyStageIndex = "stageIndex"
+
+	// progressConfigMapKeyStageCount is the key of the config map entry
+	// containing the total number of stages.
+	progressConfigMapKeyStageCount = "stageCount"
+
+	// progressConfigMapKeyStages is the key of the config map entry
+	// containing a JSON array with a summary of the executed stages.
+	progressConfigMapKeyStages = "stages.json"
+
+	// maxStages is the maximum number of stage summaries stored in the
+	// status of a pipeline run. Further stages are dropped.
+	maxStages = 100
+)
+
+// reportedStage is the summary of an executed stage as reported by
+// the Jenkinsfile Runner.
+type reportedStage struct {
+	Name           string `json:"name"`
+	Result         string `json:"result"`
+	DurationMillis int64  `json:"durationMillis"`
+}
+
+// getProgress returns the progress reported by the Jenkinsfile Runner
+// of the given pipeline run or nil if no progress has been reported.
+// Progress reporting is best effort, so errors are logged only.
+func (c *Controller) getProgress(ctx context.Context, pipelineRun k8s.PipelineRun) *api.Progress {
+	return progressFromData(c.getProgressData(ctx, pipelineRun))
+}
+
+// getStages returns the summary of the executed stages reported by the
+// Jenkinsfile Runner of the given pipeline run or nil if no stages have
+// been reported.
+// Progress reporting is best effort, so errors are logged only.
+func (c *Controller) getStages(ctx context.Context, pipelineRun k8s.PipelineRun) []api.StageSummary {
+	stages, err := stagesFromData(c.getProgressData(ctx, pipelineRun))
+	if err != nil {
+		klog.V(3).Infof("failed to get stages of pipeline run %q: %s", pipelineRun.String(), err.Error())
+		return nil
+	}
+	return stages
+}
+
+// getProgressData returns the progress reported via the progress config
+// map in the run namespace and the progress annotations of the given
+// pipeline run (see progressData).
+func (c *Controller) getProgressData(ctx context.Context, pipelineRun k8s.PipelineRun) map[string]string {
+	configMap, _ := c.getProgressConfigMap(ctx, pipelineRun)
+	return progressData(configMap, pipelineRun.GetAPIObject().GetAnnotations())
+}
+
+// progressData returns the entries of the given progress config map
+// overridden by the given progress annotations of a pipeline run (see
+// api.AnnotationProgressPrefix). The config map may be nil.
+func progressData(configMap *corev1.ConfigMap, annotations map[string]string) map[string]string {
+	data := map[string]string{}
+	if configMap != nil {
+		for key, value := range configMap.Data {
+			data[key] = value
+		}
+	}
+	for key, value := range annotations {
+		if strings.HasPrefix(key, api.AnnotationProgressPrefix) {
+			data[strings.TrimPrefix(key, api.AnnotationProgressPrefix)] = value
+		}
+	}
+	return data
+}
+
+// getProgressConfigMap returns the progress config map in the run
+// namespace of the given pipeline run or nil if it does not exist.
+// Other errors are logged and returned.
+func (c *Controller) getProgressConfigMap(ctx context.Context, pipelineRun k8s.PipelineRun) (*corev1.ConfigMap, error) {
+	namespace := pipelineRun.GetRunNamespace()
+	if namespace == "" {
+		return nil, nil
+	}
+	configMap, err := c.factory.CoreV1().ConfigMaps(namespace).Get(ctx, naming.ProgressConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		klog.V(3).Infof("failed to get progress of pipeline run %q: %s", pipelineRun.String(), err.Error())
+		return nil, err
+	}
+	return configMap, nil
+}
+
+// progressFromData creates a progress from the entries of the progress
+// config map. Returns nil if no stage is reported. Invalid stage index
+// or count values are treated as unknown.
+func progressFromData(data map[string]string) *api.Progress {
+	stage := strings.TrimSpace(data[progressConfigMapKeyStage])
+	if stage == "" {
+		return nil
+	}
+	progress := &api.Progress{
+		Stage:      stage,
+		StageIndex: parseNonNegativeInt(data[progressConfigMapKeyStageIndex]),
+		StageCount: parseNonNegativeInt(data[progressConfigMapKeyStageCount]),
+	}
+	if progress.StageCount > 0 && progress.StageIndex > progress.StageCount {
+		progress.StageCount = 0
+	}
+	progress.Summary = progressSummary(progress)
+	return progress
+}
+
+// stagesFromData creates the stage summaries from the entries of the
+// progress config map. Returns nil if no stages are reported. Stages
+// without name are skipped and at most maxStages stages are returned.
+func stagesFromData(data map[string]string) ([]api.StageSummary, error) {
+	stagesJSON := strings.TrimSpace(data[progressConfigMapKeyStages])
+	if stagesJSON == "" {
+		return nil, nil
+	}
+	var reported []reportedStage
+	if err := json.Unmarshal([]byte(stagesJSON), &reported); err != nil {
+		return nil, errors.Wrapf(err, "invalid value of entry %q in config map %q", progressConfigMapKeyStages, naming.ProgressConfigMap)
+	}
+	var stages []api.StageSummary
+	for _, stage := range reported {
+		name := strings.TrimSpace(stage.Name)
+		if name == "" {
+			continue
+		}
+		if len(stages) >= maxStages {
+			break
+		}
+		summary := api.StageSummary{
+			Name:   name,
+			Result: strings.TrimSpace(stage.Result),
+		}
+		if stage.DurationMillis > 0 {
+			summary.Duration = metav1.Duration{Duration: time.Duration(stage.DurationMillis) * time.Millisecond}
+		}
+		stages = append(stages, summary)
+	}
+	return stages, nil
+}
+
+func parseNonNegativeInt(value string) int {
+	result, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || result < 0 {
+		return 0
+	}
+	return result
+}
+
+func progressSummary(progress *api.Progress) string {
+	switch {
+	case progress.StageIndex > 0 && progress.StageCount > 0:
+		return fmt.Sprintf("stage %d/%d: %s", progress.StageIndex, progress.StageCount, progress.Stage)
+	case progress.StageIndex > 0:
+		return fmt.Sprintf("stage %d: %s", progress.StageIndex, progress.Stage)
+	default:
+		return fmt.Sprintf("stage: %s", progress.Stage)
+	}
+}
+
+// isProgressChanged returns whether the reported progress differs from
+// the progress in the status of a pipeline run, ignoring the update time.
+func isProgressChanged(current, reported *api.Progress) bool {
+	if current == nil || reported == nil {
+		return current != reported
+	}
+	return current.Stage != reported.Stage ||
+		current.StageIndex != reported.StageIndex ||
+		current.StageCount != reported.StageCount
+}
@@ -0,0 +1,439 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_progressFromData(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		data     map[string]string
+		expected *api.Progress
+	}{
+		{
+			name:     "nil",
+			data:     nil,
+			expected: nil,
+		},
+		{
+			name: "no stage",
+			data: map[string]string{
+				progressConfigMapKeyStageIndex: "1",
+				progressConfigMapKeyStageCount: "2",
+			},
+			expected: nil,
+		},
+		{
+			name: "complete",
+			data: map[string]string{
+				progressConfigMapKeyStage:      " integration tests ",
+				progressConfigMapKeyStageIndex: "3",
+				progressConfigMapKeyStageCount: "7",
+			},
+			expected: &api.Progress{
+				Stage:      "integration tests",
+				StageIndex: 3,
+				StageCount: 7,
+				Summary:    "stage 3/7: integration tests",
+			},
+		},
+		{
+			name: "index only",
+			data: map[string]string{
+				progressConfigMapKeyStage:      "build",
+				progressConfigMapKeyStageIndex: "2",
+			},
+			expected: &api.Progress{
+				Stage:      "build",
+				StageIndex: 2,
+				Summary:    "stage 2: build",
+			},
+		},
+		{
+			name: "stage only",
+			data: map[string]string{
+				progressConfigMapKeyStage: "build",
+			},
+			expected: &api.Progress{
+				Stage:   "build",
+				Summary: "stage: build",
+			},
+		},
+		{
+			name: "invalid numbers",
+			data: map[string]string{
+				progressConfigMapKeyStage:      "build",
+				progressConfigMapKeyStageIndex: "two",
+				progressConfigMapKeyStageCount: "-1",
+			},
+			expected: &api.Progress{
+				Stage:   "build",
+				Summary: "stage: build",
+			},
+		},
+		{
+			name: "index greater than count",
+			data: map[string]string{
+				progressConfigMapKeyStage:      "deploy",
+				progressConfigMapKeyStageIndex: "8",
+				progressConfigMapKeyStageCount: "7",
+			},
+			expected: &api.Progress{
+				Stage:      "deploy",
+				StageIndex: 8,
+				Summary:    "stage 8: deploy",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := progressFromData(tc.data)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_isProgressChanged(t *testing.T) {
+	t.Parallel()
+
+	progress := func(stage string, index, count int) *api.Progress {
+		return &api.Progress{Stage: stage, StageIndex: index, StageCount: count}
+	}
+	for i, tc := range []struct {
+		current, reported *api.Progress
+		expected          bool
+	}{
+		{nil, nil, false},
+		{nil, progress("a", 1, 2), true},
+		{progress("a", 1, 2), nil, true},
+		{progress("a", 1, 2), progress("a", 1, 2), false},
+		{progress("a", 1, 2), progress("b", 1, 2), true},
+		{progress("a", 1, 2), progress("a", 2, 2), true},
+		{progress("a", 1, 2), progress("a", 1, 3), true},
+	} {
+		tc := tc
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			if tc.current != nil {
+				tc.current.UpdatedAt = metav1.Now()
+			}
+
+			// EXERCISE
+			result := isProgressChanged(tc.current, tc.reported)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_getProgress(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		runNamespace string
+		configMap    *corev1.ConfigMap
+		annotations  map[string]string
+		expected     *api.Progress
+	}{
+		{
+			name:         "no run namespace",
+			runNamespace: "",
+			expected:     nil,
+		},
+		{
+			name:         "no config map",
+			runNamespace: "run1",
+			expected:     nil,
+		},
+		{
+			name:         "config map in run namespace",
+			runNamespace: "run1",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      naming.ProgressConfigMap,
+					Namespace: "run1",
+				},
+				Data: map[string]string{
+					progressConfigMapKeyStage:      "build",
+					progressConfigMapKeyStageIndex: "1",
+					progressConfigMapKeyStageCount: "3",
+				},
+			},
+			expected: &api.Progress{
+				Stage:      "build",
+				StageIndex: 1,
+				StageCount: 3,
+				Summary:    "stage 1/3: build",
+			},
+		},
+		{
+			name:         "annotations without run namespace",
+			runNamespace: "",
+			annotations: map[string]string{
+				api.AnnotationProgressPrefix + progressConfigMapKeyStage: "build",
+				"other": "value1",
+			},
+			expected: &api.Progress{
+				Stage:   "build",
+				Summary: "stage: build",
+			},
+		},
+		{
+			name:         "annotations override config map",
+			runNamespace: "run1",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      naming.ProgressConfigMap,
+					Namespace: "run1",
+				},
+				Data: map[string]string{
+					progressConfigMapKeyStage:      "build",
+					progressConfigMapKeyStageIndex: "1",
+					progressConfigMapKeyStageCount: "3",
+				},
+			},
+			annotations: map[string]string{
+				api.AnnotationProgressPrefix + progressConfigMapKeyStage:      "test",
+				api.AnnotationProgressPrefix + progressConfigMapKeyStageIndex: "2",
+			},
+			expected: &api.Progress{
+				Stage:      "test",
+				StageIndex: 2,
+				StageCount: 3,
+				Summary:    "stage 2/3: test",
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			run.Status.Namespace = tc.runNamespace
+			run.Annotations = tc.annotations
+			controller, cf := newController(run)
+			if tc.configMap != nil {
+				_, err := cf.CoreV1().ConfigMaps(tc.configMap.Namespace).Create(ctx, tc.configMap, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result := controller.getProgress(ctx, pipelineRun)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_UpdatesProgress(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	run.Status = api.PipelineStatus{
+		State:     api.StateRunning,
+		Namespace: "run1",
+	}
+	controller, cf := newController(run)
+	_, err := cf.CoreV1().ConfigMaps("run1").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ProgressConfigMap,
+			Namespace: "run1",
+		},
+		Data: map[string]string{
+			progressConfigMapKeyStage:      "integration tests",
+			progressConfigMapKeyStageIndex: "3",
+			progressConfigMapKeyStageCount: "7",
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runmock := runmocks.NewMockRun(mockCtrl)
+	runmock.EXPECT().GetContainerInfo().Return(nil)
+	runmock.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	err = controller.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateRunning, result.Status.State)
+	assert.Assert(t, result.Status.Progress != nil)
+	assert.Equal(t, "stage 3/7: integration tests", result.Status.Progress.Summary)
+	assert.Assert(t, !result.Status.Progress.UpdatedAt.IsZero())
+}
+
+func Test_stagesFromData(t *testing.T) {
+	t.Parallel()
+
+	manyStages := make([]string, maxStages+1)
+	for i := range manyStages {
+		manyStages[i] = fmt.Sprintf(`{"name":"stage%d"}`, i)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		data          map[string]string
+		expected      []api.StageSummary
+		expectedCount int
+		expectedError string
+	}{
+		{
+			name:     "nil",
+			data:     nil,
+			expected: nil,
+		},
+		{
+			name: "empty",
+			data: map[string]string{
+				progressConfigMapKeyStages: " ",
+			},
+			expected: nil,
+		},
+		{
+			name: "invalid",
+			data: map[string]string{
+				progressConfigMapKeyStages: "{",
+			},
+			expectedError: `invalid value of entry "stages.json" in config map "steward-progress": .*`,
+		},
+		{
+			name: "valid",
+			data: map[string]string{
+				progressConfigMapKeyStages: `[
+					{"name":"build","result":"SUCCESS","durationMillis":65000},
+					{"name":" ","result":"SUCCESS"},
+					{"name":"test","result":"FAILURE","durationMillis":-1},
+					{"name":"deploy"}
+				]`,
+			},
+			expected: []api.StageSummary{
+				{Name: "build", Result: "SUCCESS", Duration: metav1.Duration{Duration: 65 * time.Second}},
+				{Name: "test", Result: "FAILURE"},
+				{Name: "deploy"},
+			},
+			expectedCount: 3,
+		},
+		{
+			name: "too many stages",
+			data: map[string]string{
+				progressConfigMapKeyStages: "[" + strings.Join(manyStages, ",") + "]",
+			},
+			expectedCount: maxStages,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := stagesFromData(tc.data)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Assert(t, is.Regexp(tc.expectedError, err.Error()))
+				assert.Assert(t, result == nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedCount, len(result))
+			if tc.expected != nil {
+				assert.DeepEqual(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_RecordsStagesOnCompletion(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	run.Status = api.PipelineStatus{
+		State:     api.StateRunning,
+		Namespace: "run1",
+	}
+	controller, cf := newController(run)
+	_, err := cf.CoreV1().ConfigMaps("run1").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ProgressConfigMap,
+			Namespace: "run1",
+		},
+		Data: map[string]string{
+			progressConfigMapKeyStages: `[{"name":"build","result":"SUCCESS","durationMillis":2000}]`,
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runmock := runmocks.NewMockRun(mockCtrl)
+	now := metav1.Now()
+	runmock.EXPECT().GetContainerInfo().Return(nil)
+	runmock.EXPECT().IsFinished().Return(true, api.ResultSuccess)
+	runmock.EXPECT().GetCompletionTime().Return(&now)
+	runmock.EXPECT().GetMessage()
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	err = controller.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateCleaning, result.Status.State)
+	assert.DeepEqual(t, []api.StageSummary{
+		{Name: "build", Result: "SUCCESS", Duration: metav1.Duration{Duration: 2 * time.Second}},
+	}, result.Status.Stages)
+}
@@ -0,0 +1,166 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	proptest "github.com/SAP/stewardci-core/pkg/utils/proptest"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// stateRanks defines the order of the states of the pipeline run
+// lifecycle. States never go back in this order.
+var stateRanks = map[api.State]int{
+	api.StateUndefined: 0,
+	api.StateNew:       1,
+	api.StateQueued:    2,
+	api.StatePreparing: 3,
+	api.StateWaiting:   4,
+	api.StateRunning:   5,
+	api.StateCleaning:  6,
+	api.StateFinished:  7,
+}
+
+// randomRunManager is a run manager returning random outcomes, simulating
+// an arbitrary order of events of the run infrastructure.
+type randomRunManager struct {
+	r *rand.Rand
+}
+
+func (m *randomRunManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, string, error) {
+	switch m.r.Intn(4) {
+	case 0:
+		return "", "", fmt.Errorf("error1")
+	case 1:
+		return "", "", serrors.Classify(fmt.Errorf("error1"), api.ResultErrorContent)
+	default:
+		return "run-ns-1", "", nil
+	}
+}
+
+func (m *randomRunManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (run.Run, error) {
+	switch m.r.Intn(6) {
+	case 0:
+		return nil, fmt.Errorf("error1")
+	case 1:
+		return nil, serrors.Recoverable(fmt.Errorf("error1"))
+	case 2:
+		return &randomRun{}, nil
+	case 3:
+		return &randomRun{started: true}, nil
+	default:
+		result := api.Result(proptest.OneOf(m.r,
+			string(api.ResultSuccess),
+			string(api.ResultErrorContent),
+			string(api.ResultErrorInfra),
+			string(api.ResultTimeout),
+		))
+		return &randomRun{started: true, result: result}, nil
+	}
+}
+
+func (m *randomRunManager) Cleanup(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if m.r.Intn(3) == 0 {
+		return fmt.Errorf("error1")
+	}
+	return nil
+}
+
+// randomRun is a run which is finished if it has a result.
+type randomRun struct {
+	started bool
+	result  api.Result
+}
+
+func (r *randomRun) GetStartTime() *metav1.Time {
+	if !r.started {
+		return nil
+	}
+	now := metav1.Now()
+	return &now
+}
+
+func (r *randomRun) IsFinished() (bool, api.Result) {
+	return r.result != api.ResultUndefined, r.result
+}
+
+func (r *randomRun) GetCompletionTime() *metav1.Time {
+	if r.result == api.ResultUndefined {
+		return nil
+	}
+	now := metav1.Now()
+	return &now
+}
+
+func (r *randomRun) GetContainerInfo() *corev1.ContainerState { return nil }
+func (r *randomRun) GetMessage() string                       { return "message1" }
+func (r *randomRun) GetTestResults() *api.TestResults         { return nil }
+func (r *randomRun) GetArtifacts() *api.ArtifactsStatus       { return nil }
+
+func Test_Controller_syncHandler_Property_LifecycleIsMonotonic(t *testing.T) {
+	t.Parallel()
+
+	const maxSyncs = 30
+
+	proptest.Check(t, func(t *testing.T, r *rand.Rand) {
+		// SETUP
+		ctx := context.Background()
+		pipelineRun := fake.RandomPipelineRun(r, "foo", "ns1")
+		controller, cf := newController(pipelineRun)
+		// events are dropped to not block on a full channel
+		controller.recorder = &record.FakeRecorder{}
+		controller.testing = &controllerTesting{
+			createRunManagerStub:       &randomRunManager{r: r},
+			loadPipelineRunsConfigStub: newEmptyRunsConfig,
+			isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+		}
+		client := cf.StewardV1alpha1().PipelineRuns("ns1")
+
+		previous := api.PipelineStatus{}
+		for i := 0; i < maxSyncs && previous.State != api.StateFinished; i++ {
+			// clients may abort at any time
+			if r.Intn(10) == 0 {
+				current, err := client.Get(ctx, "foo", metav1.GetOptions{})
+				assert.NilError(t, err)
+				current.Spec.Intent = api.IntentAbort
+				_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+				assert.NilError(t, err)
+			}
+
+			// EXERCISE
+			// errors are expected for failing outcomes and lead to retries
+			_ = controller.syncHandler("ns1/foo")
+
+			// VERIFY
+			current, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			status := current.Status
+			rank, valid := stateRanks[status.State]
+			assert.Assert(t, valid, "invalid state %q", status.State)
+			assert.Assert(t, rank >= stateRanks[previous.State],
+				"state changed from %q to %q", previous.State, status.State)
+			if previous.Result != api.ResultUndefined {
+				assert.Equal(t, previous.Result, status.Result, "result changed")
+			}
+			if status.State == api.StateFinished {
+				assert.Assert(t, status.Result != api.ResultUndefined, "finished without result")
+				assert.Equal(t, 0, len(current.Finalizers))
+			}
+			if status.State == api.StateCleaning {
+				assert.Assert(t, status.Result != api.ResultUndefined, "cleaning without result")
+			}
+			previous = status
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package runctl
+
+import (
+	"context"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/provenance"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+)
+
+// attestProvenance creates a signed SLSA provenance attestation for the
+// given successfully finished pipeline run and stores it in a config map
+// in the namespace of the pipeline run.
+// The signing key is loaded from (or created in) the system namespace.
+// If the config map exists already, e.g. because the state transition
+// has been retried, it is left untouched.
+func (c *Controller) attestProvenance(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	pipelineRunAPIObj := pipelineRun.GetAPIObject()
+
+	key, err := provenance.LoadOrCreateSigningKey(ctx, c.factory.CoreV1().Secrets(system.Namespace()))
+	if err != nil {
+		return errors.WithMessage(err, "failed to load provenance signing key")
+	}
+	signer, err := provenance.NewSigner(key)
+	if err != nil {
+		return err
+	}
+	statement, err := provenance.NewStatement(pipelineRunAPIObj, c.provenanceBuilderID)
+	if err != nil {
+		return err
+	}
+	envelope, err := signer.Sign(statement)
+	if err != nil {
+		return err
+	}
+	configMap, err := provenance.NewConfigMap(pipelineRunAPIObj, statement, envelope)
+	if err != nil {
+		return err
+	}
+	slabels.LabelWithInstanceID(configMap, c.factory.InstanceID())
+
+	_, err = c.factory.CoreV1().ConfigMaps(configMap.GetNamespace()).Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create provenance config map %q", configMap.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"encoding/json"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConfigMapKeyStatement is the key of the config map data entry
+	// containing the unsigned provenance statement.
+	ConfigMapKeyStatement = "provenance.json"
+
+	// ConfigMapKeyEnvelope is the key of the config map data entry
+	// containing the DSSE envelope with the signed provenance statement.
+	ConfigMapKeyEnvelope = "provenance.dsse.json"
+)
+
+// ConfigMapName returns the name of the config map holding the provenance
+// attestation of the pipeline run with the given name.
+// Long names are shortened (see naming.ProvenanceConfigMap).
+func ConfigMapName(pipelineRunName string) string {
+	return naming.ProvenanceConfigMap(pipelineRunName)
+}
+
+// NewConfigMap returns a config map holding the given provenance statement
+// and its signed envelope.
+// The config map is placed in the namespace of the pipeline run and owned
+// by it, i.e. it gets deleted together with the pipeline run.
+func NewConfigMap(pipelineRun *api.PipelineRun, statement *Statement, envelope *Envelope) (*corev1.ConfigMap, error) {
+	statementJSON, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize provenance statement")
+	}
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize provenance envelope")
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(pipelineRun.GetName()),
+			Namespace: pipelineRun.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipelineRun, api.SchemeGroupVersion.WithKind("PipelineRun")),
+			},
+		},
+		Data: map[string]string{
+			ConfigMapKeyStatement: string(statementJSON),
+			ConfigMapKeyEnvelope:  string(envelopeJSON),
+		},
+	}
+	stewardlabels.LabelAsSystemManaged(configMap)
+	if err := stewardlabels.LabelAsOwnedByPipelineRun(configMap, pipelineRun); err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
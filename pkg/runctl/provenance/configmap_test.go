@@ -0,0 +1,39 @@
+package provenance
+
+import (
+	"encoding/json"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+)
+
+func Test_NewConfigMap(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	pipelineRun.UID = "uid1"
+	statement := &Statement{Type: StatementType}
+	envelope := &Envelope{PayloadType: PayloadType, Payload: "cGF5bG9hZA=="}
+
+	// EXERCISE
+	configMap, err := NewConfigMap(pipelineRun, statement, envelope)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "steward-provenance-run1", configMap.GetName())
+	assert.Equal(t, "ns1", configMap.GetNamespace())
+	assert.Equal(t, 1, len(configMap.OwnerReferences))
+	assert.Equal(t, "PipelineRun", configMap.OwnerReferences[0].Kind)
+	assert.Equal(t, "uid1", string(configMap.OwnerReferences[0].UID))
+	assert.Equal(t, "run1", configMap.GetLabels()[api.LabelOwnerPipelineRunName])
+
+	var decodedEnvelope Envelope
+	assert.NilError(t, json.Unmarshal([]byte(configMap.Data[ConfigMapKeyEnvelope]), &decodedEnvelope))
+	assert.DeepEqual(t, *envelope, decodedEnvelope)
+	var decodedStatement Statement
+	assert.NilError(t, json.Unmarshal([]byte(configMap.Data[ConfigMapKeyStatement]), &decodedStatement))
+	assert.Equal(t, StatementType, decodedStatement.Type)
+}
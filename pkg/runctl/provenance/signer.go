@@ -0,0 +1,169 @@
+package provenance
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// PayloadType is the DSSE payload type of in-toto statements.
+	PayloadType = "application/vnd.in-toto+json"
+
+	// SigningKeySecretName is the name of the secret in the system
+	// namespace holding the key pair used to sign provenance statements.
+	SigningKeySecretName = "steward-provenance-signing-key"
+
+	// SigningKeySecretPrivateKey is the key of the secret data entry
+	// containing the PEM-encoded PKCS #8 private key.
+	SigningKeySecretPrivateKey = "private.pem"
+
+	// SigningKeySecretPublicKey is the key of the secret data entry
+	// containing the PEM-encoded PKIX public key, which can be used to
+	// verify signatures.
+	SigningKeySecretPublicKey = "public.pem"
+)
+
+// Envelope is a DSSE envelope containing a signed payload.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Signer signs provenance statements.
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewSigner creates a Signer using the given private key.
+// The key ID of the signatures is the hex-encoded SHA-256 digest of the
+// PKIX-encoded public key.
+func NewSigner(key ed25519.PrivateKey) (*Signer, error) {
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+	digest := sha256.Sum256(publicKeyDER)
+	return &Signer{
+		key:   key,
+		keyID: hex.EncodeToString(digest[:]),
+	}, nil
+}
+
+// Sign serializes the given statement and returns it wrapped into a
+// signed DSSE envelope.
+func (s *Signer) Sign(statement *Statement) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize provenance statement")
+	}
+	sig := ed25519.Sign(s.key, preAuthEncoding(PayloadType, payload))
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []Signature{
+			{
+				KeyID: s.keyID,
+				Sig:   base64.StdEncoding.EncodeToString(sig),
+			},
+		},
+	}, nil
+}
+
+// preAuthEncoding returns the DSSE pre-authentication encoding of the
+// given payload, which is the message actually signed.
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// LoadOrCreateSigningKey returns the signing key stored in secret
+// SigningKeySecretName. If the secret does not exist, a new key pair
+// is generated and stored.
+func LoadOrCreateSigningKey(ctx context.Context, secrets corev1client.SecretInterface) (ed25519.PrivateKey, error) {
+	secret, err := secrets.Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	if err == nil {
+		return parsePrivateKey(secret)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get secret %q", SigningKeySecretName)
+	}
+
+	secret, err = newSigningKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	created, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		// created concurrently
+		created, err = secrets.Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create secret %q", SigningKeySecretName)
+	}
+	return parsePrivateKey(created)
+}
+
+func newSigningKeySecret() (*corev1.Secret, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate signing key")
+	}
+	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode private key")
+	}
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode public key")
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: SigningKeySecretName,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			SigningKeySecretPrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyDER}),
+			SigningKeySecretPublicKey:  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}),
+		},
+	}, nil
+}
+
+func parsePrivateKey(secret *corev1.Secret) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(secret.Data[SigningKeySecretPrivateKey])
+	if block == nil {
+		return nil, fmt.Errorf("secret %q: key %q does not contain a PEM-encoded private key",
+			secret.GetName(), SigningKeySecretPrivateKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "secret %q: key %q: failed to parse private key",
+			secret.GetName(), SigningKeySecretPrivateKey)
+	}
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("secret %q: key %q: private key is not an Ed25519 key",
+			secret.GetName(), SigningKeySecretPrivateKey)
+	}
+	return ed25519Key, nil
+}
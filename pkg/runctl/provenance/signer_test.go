@@ -0,0 +1,108 @@
+package provenance
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Signer_Sign(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secretsClient := kubernetes.NewSimpleClientset().CoreV1().Secrets("ns1")
+	key, err := LoadOrCreateSigningKey(ctx, secretsClient)
+	assert.NilError(t, err)
+	examinee, err := NewSigner(key)
+	assert.NilError(t, err)
+	statement := &Statement{
+		Type:          StatementType,
+		PredicateType: PredicateTypeSLSAProvenance,
+		Predicate:     Provenance{Builder: Builder{ID: "builder1"}},
+	}
+
+	// EXERCISE
+	envelope, err := examinee.Sign(statement)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, PayloadType, envelope.PayloadType)
+	assert.Equal(t, 1, len(envelope.Signatures))
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	assert.NilError(t, err)
+	var decoded Statement
+	assert.NilError(t, json.Unmarshal(payload, &decoded))
+	assert.Equal(t, "builder1", decoded.Predicate.Builder.ID)
+
+	// verify with the public key as published in the secret
+	secret, err := secretsClient.Get(ctx, SigningKeySecretName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	block, _ := pem.Decode(secret.Data[SigningKeySecretPublicKey])
+	assert.Assert(t, block != nil)
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	assert.NilError(t, err)
+	sig, err := base64.StdEncoding.DecodeString(envelope.Signatures[0].Sig)
+	assert.NilError(t, err)
+	assert.Assert(t, ed25519.Verify(publicKey.(ed25519.PublicKey), preAuthEncoding(PayloadType, payload), sig))
+}
+
+func Test_preAuthEncoding(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := preAuthEncoding("type1", []byte("payload1"))
+
+	// VERIFY
+	assert.Equal(t, "DSSEv1 5 type1 8 payload1", string(result))
+}
+
+func Test_LoadOrCreateSigningKey_LoadsExistingKey(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secretsClient := kubernetes.NewSimpleClientset().CoreV1().Secrets("ns1")
+	firstKey, err := LoadOrCreateSigningKey(ctx, secretsClient)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	secondKey, err := LoadOrCreateSigningKey(ctx, secretsClient)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, firstKey.Equal(secondKey))
+}
+
+func Test_LoadOrCreateSigningKey_InvalidKeySecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SigningKeySecretName,
+			Namespace: "ns1",
+		},
+		Data: map[string][]byte{
+			SigningKeySecretPrivateKey: []byte("garbage"),
+		},
+	}
+	secretsClient := kubernetes.NewSimpleClientset(secret).CoreV1().Secrets("ns1")
+
+	// EXERCISE
+	_, err := LoadOrCreateSigningKey(ctx, secretsClient)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "does not contain a PEM-encoded private key")
+}
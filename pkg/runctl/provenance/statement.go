@@ -0,0 +1,161 @@
+package provenance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/runctl/revision"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatementType is the type of in-toto statements.
+	StatementType = "https://in-toto.io/Statement/v0.1"
+
+	// PredicateTypeSLSAProvenance is the predicate type of SLSA
+	// provenance documents.
+	PredicateTypeSLSAProvenance = "https://slsa.dev/provenance/v0.2"
+
+	// BuildType is the SLSA build type of Steward pipeline runs.
+	BuildType = "https://github.com/SAP/stewardci-core/PipelineRun@v1alpha1"
+)
+
+// Statement is an in-toto statement with a SLSA provenance predicate.
+type Statement struct {
+	Type          string     `json:"_type"`
+	Subject       []Subject  `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject is a software artifact the provenance applies to.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is a SLSA provenance predicate.
+type Provenance struct {
+	Builder    Builder    `json:"builder"`
+	BuildType  string     `json:"buildType"`
+	Invocation Invocation `json:"invocation"`
+	Metadata   Metadata   `json:"metadata"`
+	Materials  []Material `json:"materials,omitempty"`
+}
+
+// Builder identifies the entity that executed the build.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation describes how the build was started.
+type Invocation struct {
+	ConfigSource ConfigSource      `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+}
+
+// ConfigSource identifies the pipeline definition.
+type ConfigSource struct {
+	URI        string            `json:"uri"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint"`
+}
+
+// Metadata contains additional details about the build.
+type Metadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+}
+
+// Material is an input of the build, e.g. a source repository.
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// NewStatement creates the provenance statement for the given finished
+// pipeline run.
+// The subject of the statement is the pipeline run itself, identified by
+// the SHA-256 digest of its spec, because Steward does not know the
+// artifacts produced by the pipeline.
+func NewStatement(pipelineRun *api.PipelineRun, builderID string) (*Statement, error) {
+	specJSON, err := json.Marshal(pipelineRun.Spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize pipeline run spec")
+	}
+	specDigest := sha256.Sum256(specJSON)
+
+	spec := &pipelineRun.Spec
+	status := &pipelineRun.Status
+
+	statement := &Statement{
+		Type: StatementType,
+		Subject: []Subject{
+			{
+				Name:   fmt.Sprintf("pipelinerun:%s/%s", pipelineRun.GetNamespace(), pipelineRun.GetName()),
+				Digest: map[string]string{"sha256": hex.EncodeToString(specDigest[:])},
+			},
+		},
+		PredicateType: PredicateTypeSLSAProvenance,
+		Predicate: Provenance{
+			Builder:   Builder{ID: builderID},
+			BuildType: BuildType,
+			Invocation: Invocation{
+				ConfigSource: ConfigSource{
+					URI:        gitURI(spec.JenkinsFile.URL, spec.JenkinsFile.Revision),
+					Digest:     gitDigest(status.ResolvedRevision, spec.JenkinsFile.Revision),
+					EntryPoint: spec.JenkinsFile.Path,
+				},
+				Parameters: spec.Args,
+			},
+			Metadata: Metadata{
+				BuildInvocationID: string(pipelineRun.GetUID()),
+			},
+		},
+	}
+
+	if status.StartedAt != nil {
+		t := status.StartedAt.UTC()
+		statement.Predicate.Metadata.BuildStartedOn = &t
+	}
+	if status.FinishedAt != nil {
+		t := status.FinishedAt.UTC()
+		statement.Predicate.Metadata.BuildFinishedOn = &t
+	}
+
+	statement.Predicate.Materials = append(statement.Predicate.Materials, Material{
+		URI:    statement.Predicate.Invocation.ConfigSource.URI,
+		Digest: statement.Predicate.Invocation.ConfigSource.Digest,
+	})
+	for _, source := range spec.Sources {
+		statement.Predicate.Materials = append(statement.Predicate.Materials, Material{
+			URI:    gitURI(source.URL, source.Revision),
+			Digest: gitDigest("", source.Revision),
+		})
+	}
+	return statement, nil
+}
+
+func gitURI(repoURL, rev string) string {
+	return fmt.Sprintf("git+%s@%s", repoURL, rev)
+}
+
+// gitDigest returns the digest of a Git revision if it is known, i.e. if
+// the revision has been resolved or is a commit SHA already.
+func gitDigest(resolvedRevision, rev string) map[string]string {
+	if resolvedRevision == "" && revision.IsCommitSHA(rev) {
+		resolvedRevision = rev
+	}
+	if resolvedRevision == "" {
+		return nil
+	}
+	if len(resolvedRevision) == 64 {
+		return map[string]string{"sha256": resolvedRevision}
+	}
+	return map[string]string{"sha1": resolvedRevision}
+}
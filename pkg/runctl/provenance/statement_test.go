@@ -0,0 +1,115 @@
+package provenance
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	sha1Revision  = "0123456789abcdef0123456789abcdef01234567"
+	sha1Revision2 = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func Test_NewStatement(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	start := metav1.NewTime(time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600)))
+	finish := metav1.NewTime(start.Add(time.Minute))
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://github.com/org/pipeline",
+			Revision: "master",
+			Path:     "Jenkinsfile",
+		},
+		Args: map[string]string{"arg1": "value1"},
+		Sources: []api.SourceRepository{
+			{URL: "https://github.com/org/src1", Revision: sha1Revision2, TargetPath: "src1"},
+			{URL: "https://github.com/org/src2", Revision: "dev", TargetPath: "src2"},
+		},
+	})
+	pipelineRun.UID = "uid1"
+	pipelineRun.Status.ResolvedRevision = sha1Revision
+	pipelineRun.Status.StartedAt = &start
+	pipelineRun.Status.FinishedAt = &finish
+
+	// EXERCISE
+	statement, err := NewStatement(pipelineRun, "https://builder.example.com")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, PredicateTypeSLSAProvenance, statement.PredicateType)
+	assert.Equal(t, 1, len(statement.Subject))
+	assert.Equal(t, "pipelinerun:ns1/run1", statement.Subject[0].Name)
+	assert.Equal(t, 64, len(statement.Subject[0].Digest["sha256"]))
+
+	predicate := statement.Predicate
+	assert.Equal(t, "https://builder.example.com", predicate.Builder.ID)
+	assert.Equal(t, BuildType, predicate.BuildType)
+	assert.DeepEqual(t, ConfigSource{
+		URI:        "git+https://github.com/org/pipeline@master",
+		Digest:     map[string]string{"sha1": sha1Revision},
+		EntryPoint: "Jenkinsfile",
+	}, predicate.Invocation.ConfigSource)
+	assert.DeepEqual(t, map[string]string{"arg1": "value1"}, predicate.Invocation.Parameters)
+	assert.Equal(t, "uid1", predicate.Metadata.BuildInvocationID)
+	assert.Equal(t, time.UTC, predicate.Metadata.BuildStartedOn.Location())
+	assert.Assert(t, predicate.Metadata.BuildStartedOn.Equal(start.Time))
+	assert.Assert(t, predicate.Metadata.BuildFinishedOn.Equal(finish.Time))
+	assert.DeepEqual(t, []Material{
+		{URI: "git+https://github.com/org/pipeline@master", Digest: map[string]string{"sha1": sha1Revision}},
+		{URI: "git+https://github.com/org/src1@" + sha1Revision2, Digest: map[string]string{"sha1": sha1Revision2}},
+		{URI: "git+https://github.com/org/src2@dev"},
+	}, predicate.Materials)
+}
+
+func Test_NewStatement_NotStarted(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+
+	// EXERCISE
+	statement, err := NewStatement(pipelineRun, "builder1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, is.Nil(statement.Predicate.Metadata.BuildStartedOn))
+	assert.Assert(t, is.Nil(statement.Predicate.Metadata.BuildFinishedOn))
+	assert.Assert(t, is.Nil(statement.Predicate.Invocation.ConfigSource.Digest))
+}
+
+func Test_gitDigest(t *testing.T) {
+	t.Parallel()
+
+	sha256Revision := sha1Revision + "0123456789abcdef01234567"
+	for _, tc := range []struct {
+		name             string
+		resolvedRevision string
+		revision         string
+		expected         map[string]string
+	}{
+		{"resolved", sha1Revision, "master", map[string]string{"sha1": sha1Revision}},
+		{"resolved_sha256", sha256Revision, "master", map[string]string{"sha256": sha256Revision}},
+		{"revision_is_sha", "", sha1Revision, map[string]string{"sha1": sha1Revision}},
+		{"unresolved", "", "master", nil},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := gitDigest(tc.resolvedRevision, tc.revision)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
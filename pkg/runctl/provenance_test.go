@@ -0,0 +1,56 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/provenance"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_Controller_attestProvenance(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Result = api.ResultSuccess
+	controller, cf := newController(run)
+	controller.provenanceBuilderID = "builder1"
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	err := controller.attestProvenance(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get(ctx, provenance.ConfigMapName("run1"), metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, configMap.Data[provenance.ConfigMapKeyEnvelope] != "")
+	_, err = cf.CoreV1().Secrets(system.Namespace()).Get(ctx, provenance.SigningKeySecretName, metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_Controller_attestProvenance_ConfigMapExists(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	controller.provenanceBuilderID = "builder1"
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+	err := controller.attestProvenance(ctx, pipelineRun)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	err = controller.attestProvenance(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
@@ -0,0 +1,42 @@
+package runctl
+
+import (
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isStartAllowedByQuietHours returns whether the given pipeline run,
+// which must be in state `new` or `queued`, may be started with respect
+// to the quiet hours of the Steward installation (see
+// ControllerOpts.QuietHours).
+// Pipeline runs of exempt clients may always be started. Pipeline runs
+// which may not be started are added to the work queue again at the end
+// of the quiet-hours window.
+func (c *Controller) isStartAllowedByQuietHours(pipelineRun k8s.PipelineRun, now time.Time) (bool, error) {
+	active, end := c.quietHours.Active(now)
+	if !active {
+		return true, nil
+	}
+	if len(c.quietHoursExemptClients) > 0 {
+		tenant, err := c.findTenantByTenantNamespace(pipelineRun.GetNamespace())
+		if err != nil {
+			return false, err
+		}
+		if tenant != nil && c.quietHoursExemptClients[tenant.GetNamespace()] {
+			return true, nil
+		}
+	}
+	c.workqueue.AddAfter(pipelineRun.GetKey(), end.Sub(now))
+	return false, nil
+}
+
+// isQueuedByQuietHours returns whether the given pipeline run has been
+// queued because of the quiet hours of the Steward installation.
+func isQueuedByQuietHours(pipelineRun *api.PipelineRun) bool {
+	condition := apimeta.FindStatusCondition(pipelineRun.Status.Conditions, api.PipelineRunConditionQueued)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == api.QueuedQuietHoursReason
+}
@@ -0,0 +1,94 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/quiethours"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_isStartAllowedByQuietHours(t *testing.T) {
+	t.Parallel()
+
+	// 2022-03-07 is a Monday
+	now := time.Date(2022, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name            string
+		quietHours      string
+		exemptClients   []string
+		expectedAllowed bool
+	}{
+		{"no_quiet_hours", "", nil, true},
+		{"outside_quiet_hours", "0 20 * * * 8h", nil, true},
+		{"inside_quiet_hours", "0 8 * * 1-5 10h", nil, false},
+		{"inside_quiet_hours_other_client_exempt", "0 8 * * 1-5 10h", []string{"client2"}, false},
+		{"inside_quiet_hours_client_exempt", "0 8 * * 1-5 10h", []string{"client2", "client1"}, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "tenantns1", api.PipelineSpec{})
+			run.Status.State = api.StateNew
+			controller, cf := newController(run)
+			schedule, err := quiethours.Parse(tc.quietHours, time.UTC)
+			assert.NilError(t, err)
+			controller.quietHours = schedule
+			controller.quietHoursExemptClients = map[string]bool{}
+			for _, client := range tc.exemptClients {
+				controller.quietHoursExemptClients[client] = true
+			}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Status.TenantNamespaceName = "tenantns1"
+			err = cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore().Add(tenant)
+			assert.NilError(t, err)
+			pipelineRun := getPipelineRun(t, "run", "tenantns1", cf)
+
+			// EXERCISE
+			allowed, err := controller.isStartAllowedByQuietHours(pipelineRun, now)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAllowed, allowed)
+		})
+	}
+}
+
+func Test_isQueuedByQuietHours(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		condition *metav1.Condition
+		expected  bool
+	}{
+		{"no_condition", nil, false},
+		{"queued_by_quiet_hours", &metav1.Condition{Status: metav1.ConditionTrue, Reason: api.QueuedQuietHoursReason}, true},
+		{"queued_by_other_reason", &metav1.Condition{Status: metav1.ConditionTrue, Reason: api.QueuedGlobalLimitReason}, false},
+		{"started", &metav1.Condition{Status: metav1.ConditionFalse, Reason: api.QueuedStartedReason}, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+			if tc.condition != nil {
+				tc.condition.Type = api.PipelineRunConditionQueued
+				run.Status.Conditions = []metav1.Condition{*tc.condition}
+			}
+
+			// EXERCISE
+			result := isQueuedByQuietHours(run)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
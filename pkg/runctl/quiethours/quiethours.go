@@ -0,0 +1,98 @@
+package quiethours
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/cron"
+	"github.com/pkg/errors"
+)
+
+// MaxWindowDuration is the maximum duration of a single quiet-hours
+// window.
+const MaxWindowDuration = 7 * 24 * time.Hour
+
+// Schedule is a set of quiet-hours windows during which pipeline runs
+// should not be started.
+type Schedule struct {
+	windows     []window
+	location    *time.Location
+	maxDuration time.Duration
+}
+
+// window is a quiet-hours window starting at each time matching a cron
+// expression and lasting for a fixed duration.
+type window struct {
+	schedule *cron.Schedule
+	duration time.Duration
+}
+
+// Parse parses a quiet-hours schedule.
+// The schedule consists of windows separated by `;`. Each window is
+// given as five cron fields (minute, hour, day of month, month, day of
+// week) followed by the duration of the window, e.g. `0 8 * * 1-5 10h`
+// for 8am to 6pm on weekdays. See cron.Parse for the syntax of the cron
+// fields.
+// The cron fields are evaluated in the given location.
+// Returns nil if the schedule is empty.
+func Parse(spec string, location *time.Location) (*Schedule, error) {
+	schedule := &Schedule{location: location}
+	for _, windowSpec := range strings.Split(spec, ";") {
+		windowSpec = strings.TrimSpace(windowSpec)
+		if windowSpec == "" {
+			continue
+		}
+		w, err := parseWindow(windowSpec)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid quiet-hours window %q", windowSpec)
+		}
+		schedule.windows = append(schedule.windows, w)
+		if w.duration > schedule.maxDuration {
+			schedule.maxDuration = w.duration
+		}
+	}
+	if len(schedule.windows) == 0 {
+		return nil, nil
+	}
+	return schedule, nil
+}
+
+func parseWindow(spec string) (window, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != 6 {
+		return window{}, errors.Errorf("expected 5 cron fields and a duration, got %d fields", len(parts))
+	}
+	var w window
+	var err error
+	if w.schedule, err = cron.Parse(strings.Join(parts[:5], " ")); err != nil {
+		return window{}, err
+	}
+	if w.duration, err = time.ParseDuration(parts[5]); err != nil {
+		return window{}, errors.WithMessage(err, "duration")
+	}
+	if w.duration <= 0 || w.duration > MaxWindowDuration {
+		return window{}, errors.Errorf("duration must be positive and at most %v", MaxWindowDuration)
+	}
+	return w, nil
+}
+
+// Active returns whether the given time is within a quiet-hours window
+// and, if so, the end of the window. If windows overlap, the returned end
+// is the end of the window started last, i.e. the time may still be
+// within another window at that time.
+func (s *Schedule) Active(now time.Time) (bool, time.Time) {
+	if s == nil {
+		return false, time.Time{}
+	}
+	start := now.Truncate(time.Minute)
+	for elapsed := now.Sub(start); elapsed < s.maxDuration; elapsed += time.Minute {
+		local := start.In(s.location)
+		for _, w := range s.windows {
+			if elapsed < w.duration && w.schedule.Matches(local) {
+				return true, start.Add(w.duration)
+			}
+		}
+		start = start.Add(-time.Minute)
+	}
+	return false, time.Time{}
+}
@@ -0,0 +1,109 @@
+package quiethours
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_Parse_Empty(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	schedule, err := Parse(" ; ", time.UTC)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, schedule == nil)
+	active, _ := schedule.Active(time.Now())
+	assert.Assert(t, !active)
+}
+
+func Test_Parse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		spec          string
+		expectedError string
+	}{
+		{"0 8 * * 1-5", "expected 5 cron fields and a duration, got 5 fields"},
+		{"60 8 * * * 1h", `minute: "60" out of range 0-59`},
+		{"0 8-6 * * * 1h", `hour: "8-6" out of range 0-23`},
+		{"0 8 0 * * 1h", `day of month: "0" out of range 1-31`},
+		{"0 8 * x * 1h", `month: invalid value in "x"`},
+		{"0 8 * * */0 1h", `day of week: invalid step in "*/0"`},
+		{"0 8 * * * 1x", "duration: "},
+		{"0 8 * * * 0s", "duration must be positive and at most 168h0m0s"},
+		{"0 8 * * * 169h", "duration must be positive and at most 168h0m0s"},
+	} {
+		tc := tc
+		t.Run(tc.spec, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			schedule, err := Parse("0 0 * * * 1h;"+tc.spec, time.UTC)
+
+			// VERIFY
+			assert.Assert(t, schedule == nil)
+			assert.ErrorContains(t, err, `invalid quiet-hours window "`+tc.spec+`": `+tc.expectedError)
+		})
+	}
+}
+
+func Test_Schedule_Active(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		spec        string
+		location    *time.Location
+		now         time.Time
+		expectedEnd time.Time
+	}{
+		// 2022-03-07 is a Monday
+		{"weekday_inside", "0 8 * * 1-5 10h", time.UTC,
+			time.Date(2022, 3, 7, 12, 30, 15, 0, time.UTC), time.Date(2022, 3, 7, 18, 0, 0, 0, time.UTC)},
+		{"weekday_start", "0 8 * * 1-5 10h", time.UTC,
+			time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 18, 0, 0, 0, time.UTC)},
+		{"weekday_end", "0 8 * * 1-5 10h", time.UTC,
+			time.Date(2022, 3, 7, 18, 0, 0, 0, time.UTC), time.Time{}},
+		{"weekday_before", "0 8 * * 1-5 10h", time.UTC,
+			time.Date(2022, 3, 7, 7, 59, 59, 0, time.UTC), time.Time{}},
+		{"weekend", "0 8 * * 1-5 10h", time.UTC,
+			time.Date(2022, 3, 6, 12, 0, 0, 0, time.UTC), time.Time{}},
+		{"overnight", "0 22 * * * 8h", time.UTC,
+			time.Date(2022, 3, 8, 3, 0, 0, 0, time.UTC), time.Date(2022, 3, 8, 6, 0, 0, 0, time.UTC)},
+		{"sunday_as_7", "0 0 * * 7 24h", time.UTC,
+			time.Date(2022, 3, 6, 12, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"day_of_month_or_week", "0 0 1 * 1 1h", time.UTC,
+			time.Date(2022, 3, 1, 0, 30, 0, 0, time.UTC), time.Date(2022, 3, 1, 1, 0, 0, 0, time.UTC)},
+		{"step", "*/30 * * * * 10m", time.UTC,
+			time.Date(2022, 3, 7, 12, 35, 0, 0, time.UTC), time.Date(2022, 3, 7, 12, 40, 0, 0, time.UTC)},
+		{"step_outside", "*/30 * * * * 10m", time.UTC,
+			time.Date(2022, 3, 7, 12, 45, 0, 0, time.UTC), time.Time{}},
+		{"second_window", "0 8 * * 1-5 1h; 0 12 * * 1-5 2h", time.UTC,
+			time.Date(2022, 3, 7, 13, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 14, 0, 0, 0, time.UTC)},
+		{"location", "0 8 * * 1-5 10h", berlin,
+			time.Date(2022, 3, 7, 7, 30, 0, 0, time.UTC), time.Date(2022, 3, 7, 17, 0, 0, 0, time.UTC)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule, err := Parse(tc.spec, tc.location)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			active, end := schedule.Active(tc.now)
+
+			// VERIFY
+			assert.Equal(t, !tc.expectedEnd.IsZero(), active)
+			assert.Assert(t, tc.expectedEnd.Equal(end), "end: %v", end)
+		})
+	}
+}
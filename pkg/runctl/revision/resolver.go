@@ -0,0 +1,235 @@
+package revision
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultTimeout is the timeout for requests to Git servers if the
+	// resolver has been created without an HTTP client. It is short, as
+	// resolution blocks the start of the pipeline run.
+	defaultTimeout = 5 * time.Second
+
+	// maxRefAdvertisementBytes limits the size of ref advertisements read
+	// from Git servers.
+	maxRefAdvertisementBytes = 8 << 20
+
+	// peeledSuffix is the suffix of ref names in a ref advertisement
+	// which denote the commit an annotated tag points to.
+	peeledSuffix = "^{}"
+)
+
+var commitSHAPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// ErrNotFound is returned (possibly wrapped) by Resolve if the repository
+// does not contain the requested revision.
+var ErrNotFound = errors.New("revision not found")
+
+// ErrURLNotAllowed is returned (possibly wrapped) by CheckURL if
+// revisions of the repository must not be resolved.
+var ErrURLNotAllowed = errors.New("repository URL not allowed for revision resolution")
+
+// Credentials are the credentials for basic authentication at the Git server.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Resolver resolves revisions of Git repositories, e.g. branch or tag
+// names, to commit SHAs.
+// It uses the ref advertisement of the Git smart HTTP protocol and
+// therefore only supports repositories with `http` and `https` URLs.
+type Resolver struct {
+	client *http.Client
+}
+
+// NewResolver creates a new Resolver.
+// If `client` is nil, an HTTP client with a short default timeout is used
+// which does not follow redirects, as redirects could lead to hosts not
+// checked by CheckURL.
+func NewResolver(client *http.Client) *Resolver {
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &Resolver{client: client}
+}
+
+// CheckURL returns an error wrapping ErrURLNotAllowed if the given
+// repository URL is not an `http` or `https` URL or its host is not
+// contained in `allowedHosts`. An entry of `allowedHosts` starting with a
+// dot, e.g. `.example.com`, matches all subdomains of the domain.
+// Callers must check URLs before calling Resolve to prevent requests to
+// arbitrary, e.g. cluster-internal, endpoints.
+func CheckURL(repoURL string, allowedHosts []string) error {
+	u, err := url.Parse(repoURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.Wrapf(ErrURLNotAllowed, "not an HTTP(S) URL: %q", repoURL)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if IsHostAllowed(host, allowedHosts) {
+		return nil
+	}
+	return errors.Wrapf(ErrURLNotAllowed, "host %q not allowed", host)
+}
+
+// IsHostAllowed returns whether the given host name is contained in
+// `allowedHosts`. An entry of `allowedHosts` starting with a dot, e.g.
+// `.example.com`, matches all subdomains of the domain.
+func IsHostAllowed(host string, allowedHosts []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCommitSHA returns whether the given revision is a full commit SHA,
+// which does not need to be resolved.
+func IsCommitSHA(revision string) bool {
+	return commitSHAPattern.MatchString(revision)
+}
+
+// Resolve returns the commit SHA the given revision of the repository at
+// `repoURL` currently points to.
+// The revision can be a full ref name (e.g. `refs/heads/master`), a branch
+// name, a tag name or a full commit SHA. Branches take precedence over
+// tags with the same name. Annotated tags are resolved to the commit they
+// point to.
+// `credentials` may be nil if the repository does not require
+// authentication.
+func (r *Resolver) Resolve(ctx context.Context, repoURL, revision string, credentials *Credentials) (string, error) {
+	if IsCommitSHA(revision) {
+		return revision, nil
+	}
+
+	refs, err := r.listRefs(ctx, repoURL, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidateRefNames(revision) {
+		if sha, ok := refs[candidate+peeledSuffix]; ok {
+			return sha, nil
+		}
+		if sha, ok := refs[candidate]; ok {
+			return sha, nil
+		}
+	}
+	return "", errors.Wrapf(ErrNotFound, "revision %q not found in repository %q", revision, repoURL)
+}
+
+func candidateRefNames(revision string) []string {
+	if strings.HasPrefix(revision, "refs/") || revision == "HEAD" {
+		return []string{revision}
+	}
+	return []string{
+		"refs/heads/" + revision,
+		"refs/tags/" + revision,
+	}
+}
+
+// listRefs returns all refs advertised by the Git server as map from
+// ref name to object SHA.
+func (r *Resolver) listRefs(ctx context.Context, repoURL string, credentials *Credentials) (map[string]string, error) {
+	refsURL := strings.TrimSuffix(repoURL, "/") + "/info/refs?service=git-upload-pack"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, refsURL, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid repository URL %q", repoURL)
+	}
+	if credentials != nil {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list refs of repository %q", repoURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"failed to list refs of repository %q: unexpected HTTP status %q",
+			repoURL, resp.Status,
+		)
+	}
+	refs, err := parseRefAdvertisement(io.LimitReader(resp.Body, maxRefAdvertisementBytes))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list refs of repository %q", repoURL)
+	}
+	return refs, nil
+}
+
+// parseRefAdvertisement parses the response of a smart HTTP ref
+// discovery request, which consists of pkt-lines.
+func parseRefAdvertisement(body io.Reader) (map[string]string, error) {
+	reader := bufio.NewReader(body)
+	refs := map[string]string{}
+	for {
+		line, flush, err := readPktLine(reader)
+		if err == io.EOF {
+			return refs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if flush || bytes.HasPrefix(line, []byte("#")) {
+			continue
+		}
+
+		// the first ref is followed by the capability list
+		if i := bytes.IndexByte(line, 0); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(string(line))
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid ref advertisement line %q", string(line))
+		}
+		refs[fields[1]] = fields[0]
+	}
+}
+
+// readPktLine reads a single pkt-line. `flush` is true for flush packets.
+func readPktLine(reader io.Reader) (line []byte, flush bool, err error) {
+	lengthHex := make([]byte, 4)
+	if _, err = io.ReadFull(reader, lengthHex); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errors.New("truncated pkt-line")
+		}
+		return nil, false, err
+	}
+	length, err := strconv.ParseUint(string(lengthHex), 16, 16)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "invalid pkt-line length %q", string(lengthHex))
+	}
+	if length == 0 {
+		return nil, true, nil
+	}
+	if length < 4 {
+		return nil, false, fmt.Errorf("invalid pkt-line length %q", string(lengthHex))
+	}
+	line = make([]byte, length-4)
+	if _, err = io.ReadFull(reader, line); err != nil {
+		return nil, false, errors.New("truncated pkt-line")
+	}
+	return bytes.TrimSuffix(line, []byte("\n")), false, nil
+}
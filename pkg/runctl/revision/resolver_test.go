@@ -0,0 +1,217 @@
+package revision
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+)
+
+const (
+	shaMaster  = "1111111111111111111111111111111111111111"
+	shaFeature = "2222222222222222222222222222222222222222"
+	shaTagObj  = "3333333333333333333333333333333333333333"
+	shaTagged  = "4444444444444444444444444444444444444444"
+	shaLight   = "5555555555555555555555555555555555555555"
+)
+
+func pktLine(s string) string {
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+func refAdvertisement() string {
+	return pktLine("# service=git-upload-pack\n") +
+		"0000" +
+		pktLine(shaMaster+" HEAD\x00multi_ack symref=HEAD:refs/heads/master\n") +
+		pktLine(shaMaster+" refs/heads/master\n") +
+		pktLine(shaFeature+" refs/heads/v1\n") +
+		pktLine(shaTagObj+" refs/tags/v1\n") +
+		pktLine(shaTagged+" refs/tags/v1^{}\n") +
+		pktLine(shaLight+" refs/tags/light\n") +
+		"0000"
+}
+
+func newGitServer(username, password string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/org/repo.git/info/refs" || r.URL.Query().Get("service") != "git-upload-pack" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if username != "" {
+			u, p, ok := r.BasicAuth()
+			if !ok || u != username || p != password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+		fmt.Fprint(w, refAdvertisement())
+	}))
+}
+
+func Test_Resolver_Resolve(t *testing.T) {
+	t.Parallel()
+
+	server := newGitServer("", "")
+	t.Cleanup(server.Close)
+
+	for _, tc := range []struct {
+		revision    string
+		expectedSHA string
+	}{
+		{"master", shaMaster},
+		{"refs/heads/master", shaMaster},
+		{"HEAD", shaMaster},
+		{"v1", shaFeature}, // branch takes precedence over tag
+		{"refs/tags/v1", shaTagged},
+		{"light", shaLight},
+		{shaTagObj, shaTagObj},
+	} {
+		tc := tc
+		t.Run(tc.revision, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := NewResolver(server.Client())
+
+			// EXERCISE
+			sha, err := examinee.Resolve(context.Background(), server.URL+"/org/repo.git", tc.revision, nil)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedSHA, sha)
+		})
+	}
+}
+
+func Test_Resolver_Resolve_NotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newGitServer("", "")
+	defer server.Close()
+	examinee := NewResolver(server.Client())
+
+	// EXERCISE
+	_, err := examinee.Resolve(context.Background(), server.URL+"/org/repo.git/", "unknown", nil)
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+}
+
+func Test_Resolver_Resolve_WithCredentials(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newGitServer("user1", "pass1")
+	defer server.Close()
+	examinee := NewResolver(server.Client())
+
+	// EXERCISE
+	sha, err := examinee.Resolve(context.Background(), server.URL+"/org/repo.git", "master",
+		&Credentials{Username: "user1", Password: "pass1"})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, shaMaster, sha)
+}
+
+func Test_Resolver_Resolve_HTTPError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newGitServer("user1", "pass1")
+	defer server.Close()
+	examinee := NewResolver(server.Client())
+
+	// EXERCISE
+	_, err := examinee.Resolve(context.Background(), server.URL+"/org/repo.git", "master", nil)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "unexpected HTTP status \"401 Unauthorized\"")
+	assert.Assert(t, !errors.Is(err, ErrNotFound))
+}
+
+func Test_Resolver_Resolve_DoesNotFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	target := newGitServer("", "")
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL+"/org/repo.git/info/refs?service=git-upload-pack", http.StatusFound))
+	defer server.Close()
+	examinee := NewResolver(nil)
+
+	// EXERCISE
+	_, err := examinee.Resolve(context.Background(), server.URL+"/org/repo.git", "master", nil)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "unexpected HTTP status \"302 Found\"")
+}
+
+func Test_CheckURL(t *testing.T) {
+	t.Parallel()
+
+	allowedHosts := []string{"github.com", ".example.com"}
+	for _, tc := range []struct {
+		url      string
+		expected bool
+	}{
+		{"https://github.com/org/repo", true},
+		{"http://GitHub.com:8080/org/repo", true},
+		{"https://git.example.com/org/repo", true},
+		{"https://a.b.example.com/org/repo", true},
+		{"https://example.com/org/repo", false},
+		{"https://evilexample.com/org/repo", false},
+		{"https://github.com.evil.org/org/repo", false},
+		{"https://10.0.0.1/org/repo", false},
+		{"https://kubernetes.default.svc/api", false},
+		{"git@github.com:org/repo.git", false},
+		{"ssh://git@github.com/org/repo.git", false},
+		{"file:///etc/passwd", false},
+		{"", false},
+	} {
+		// EXERCISE
+		err := CheckURL(tc.url, allowedHosts)
+
+		// VERIFY
+		if tc.expected {
+			assert.NilError(t, err, "url: %q", tc.url)
+		} else {
+			assert.Assert(t, errors.Is(err, ErrURLNotAllowed), "url: %q", tc.url)
+		}
+	}
+}
+
+func Test_parseRefAdvertisement_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, body := range []string{
+		"00",
+		"zzzz",
+		"0003",
+		"0010abc",
+		pktLine("only-one-field\n"),
+	} {
+		// EXERCISE
+		_, err := parseRefAdvertisement(strings.NewReader(body))
+
+		// VERIFY
+		assert.Assert(t, err != nil, "body: %q", body)
+	}
+}
+
+func Test_IsCommitSHA(t *testing.T) {
+	t.Parallel()
+
+	assert.Assert(t, IsCommitSHA(shaMaster))
+	assert.Assert(t, IsCommitSHA(strings.Repeat("a", 64)))
+	assert.Assert(t, !IsCommitSHA("master"))
+	assert.Assert(t, !IsCommitSHA(strings.ToUpper(strings.Repeat("a", 40))))
+	assert.Assert(t, !IsCommitSHA(shaMaster[:7]))
+}
@@ -1,6 +1,10 @@
 package runctl
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -8,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
 	knativeapis "knative.dev/pkg/apis"
 )
 
@@ -108,6 +113,49 @@ func (r *tektonRun) GetMessage() string {
 	return "internal error"
 }
 
+// GetTestResults returns the totals of the JUnit XML reports extracted
+// from the workspace by the Jenkinsfile Runner step or nil if there are
+// none.
+func (r *tektonRun) GetTestResults() *steward.TestResults {
+	for _, result := range r.tektonTaskRun.Status.TaskRunResults {
+		if result.Name != testResultsResultKey {
+			continue
+		}
+		testResults := &steward.TestResults{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(result.Value)), testResults); err != nil {
+			klog.V(3).Infof("ignoring invalid test results of task run %s/%s: %s",
+				r.tektonTaskRun.GetNamespace(), r.tektonTaskRun.GetName(), err.Error())
+			return nil
+		}
+		return testResults
+	}
+	return nil
+}
+
+// GetArtifacts returns the artifacts uploaded by the Jenkinsfile Runner
+// step or nil if there are none.
+func (r *tektonRun) GetArtifacts() *steward.ArtifactsStatus {
+	for _, result := range r.tektonTaskRun.Status.TaskRunResults {
+		if result.Name != artifactsResultKey {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(result.Value), "\n")
+		artifacts := &steward.ArtifactsStatus{}
+		if _, err := fmt.Sscanf(lines[0], "%d %d", &artifacts.Uploaded, &artifacts.Failed); err != nil {
+			klog.V(3).Infof("ignoring invalid artifacts of task run %s/%s: %s",
+				r.tektonTaskRun.GetNamespace(), r.tektonTaskRun.GetName(), err.Error())
+			return nil
+		}
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				artifacts.URLs = append(artifacts.URLs, line)
+			}
+		}
+		return artifacts
+	}
+	return nil
+}
+
 func (r *tektonRun) getJenkinsfileRunnerStepState() *tekton.StepState {
 	steps := r.tektonTaskRun.Status.Steps
 	if steps != nil {
@@ -24,9 +24,13 @@ type Run interface {
 	GetCompletionTime() *metav1.Time
 	GetContainerInfo() *corev1.ContainerState
 	GetMessage() string
+	GetTestResults() *steward.TestResults
+	GetArtifacts() *steward.ArtifactsStatus
 }
 
 // SecretManager manages secrets of a pipelinerun
 type SecretManager interface {
 	CopyAll(ctx context.Context, pipelineRun k8s.PipelineRun) (string, []string, error)
+	CopyImagePullSecrets(ctx context.Context, pipelineRun k8s.PipelineRun, secretNames []string) ([]string, error)
+	CopySourceSecrets(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error)
 }
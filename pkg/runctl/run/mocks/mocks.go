@@ -62,6 +62,20 @@ func (m *MockRun) EXPECT() *MockRunMockRecorder {
 	return m.recorder
 }
 
+// GetArtifacts mocks base method
+func (m *MockRun) GetArtifacts() *v1alpha1.ArtifactsStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArtifacts")
+	ret0, _ := ret[0].(*v1alpha1.ArtifactsStatus)
+	return ret0
+}
+
+// GetArtifacts indicates an expected call of GetArtifacts
+func (mr *MockRunMockRecorder) GetArtifacts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArtifacts", reflect.TypeOf((*MockRun)(nil).GetArtifacts))
+}
+
 // GetCompletionTime mocks base method
 func (m *MockRun) GetCompletionTime() *v10.Time {
 	m.ctrl.T.Helper()
@@ -118,6 +132,20 @@ func (mr *MockRunMockRecorder) GetStartTime() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStartTime", reflect.TypeOf((*MockRun)(nil).GetStartTime))
 }
 
+// GetTestResults mocks base method
+func (m *MockRun) GetTestResults() *v1alpha1.TestResults {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTestResults")
+	ret0, _ := ret[0].(*v1alpha1.TestResults)
+	return ret0
+}
+
+// GetTestResults indicates an expected call of GetTestResults
+func (mr *MockRunMockRecorder) GetTestResults() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTestResults", reflect.TypeOf((*MockRun)(nil).GetTestResults))
+}
+
 // IsFinished mocks base method
 func (m *MockRun) IsFinished() (bool, v1alpha1.Result) {
 	m.ctrl.T.Helper()
@@ -239,3 +267,33 @@ func (mr *MockSecretManagerMockRecorder) CopyAll(arg0, arg1 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyAll", reflect.TypeOf((*MockSecretManager)(nil).CopyAll), arg0, arg1)
 }
+
+// CopyImagePullSecrets mocks base method
+func (m *MockSecretManager) CopyImagePullSecrets(arg0 context.Context, arg1 k8s.PipelineRun, arg2 []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyImagePullSecrets", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyImagePullSecrets indicates an expected call of CopyImagePullSecrets
+func (mr *MockSecretManagerMockRecorder) CopyImagePullSecrets(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyImagePullSecrets", reflect.TypeOf((*MockSecretManager)(nil).CopyImagePullSecrets), arg0, arg1, arg2)
+}
+
+// CopySourceSecrets mocks base method
+func (m *MockSecretManager) CopySourceSecrets(arg0 context.Context, arg1 k8s.PipelineRun) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopySourceSecrets", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopySourceSecrets indicates an expected call of CopySourceSecrets
+func (mr *MockSecretManagerMockRecorder) CopySourceSecrets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySourceSecrets", reflect.TypeOf((*MockSecretManager)(nil).CopySourceSecrets), arg0, arg1)
+}
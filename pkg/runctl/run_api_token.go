@@ -0,0 +1,269 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// MinRunAPITokenExpiration is the minimum expiration of per-run
+	// tokens for the Steward API accepted by the Kubernetes TokenRequest
+	// API.
+	MinRunAPITokenExpiration = 10 * time.Minute
+
+	// runAPITokenRetryInterval is the interval in which refreshing the
+	// per-run token for the Steward API is retried after failures.
+	runAPITokenRetryInterval = time.Minute
+
+	// runAPITokenSecretKeyToken is the key of the entry of the run API
+	// token secret containing the token.
+	runAPITokenSecretKeyToken = "token"
+
+	// runAPITokenSecretKeyNamespace is the key of the entry of the run API
+	// token secret containing the namespace of the pipeline run.
+	runAPITokenSecretKeyNamespace = "namespace"
+
+	// runAPITokenSecretKeyName is the key of the entry of the run API
+	// token secret containing the name of the pipeline run.
+	runAPITokenSecretKeyName = "name"
+)
+
+// setupRunAPIToken creates a service account in the namespace of the
+// pipeline run which may only get and patch this pipeline run, mints a
+// token for it and stores the token in secret naming.RunAPITokenSecret in
+// the run namespace, where the Jenkinsfile Runner ClusterTask picks it
+// up. The admission webhook restricts the changes of the service account
+// to the progress annotations of the pipeline run.
+// Does nothing if per-run tokens are disabled.
+func (c *runManager) setupRunAPIToken(ctx context.Context, runCtx *runContext) error {
+	if c.runAPITokenExpiration <= 0 {
+		return nil
+	}
+	pipelineRun := runCtx.pipelineRun
+	namespace := pipelineRun.GetNamespace()
+	name := naming.RunAPIServiceAccount(pipelineRun.GetName())
+	ownerReferences := []metav1.OwnerReference{
+		*metav1.NewControllerRef(pipelineRun.GetAPIObject(), api.SchemeGroupVersion.WithKind("PipelineRun")),
+	}
+
+	automountToken := false
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: ownerReferences,
+		},
+		AutomountServiceAccountToken: &automountToken,
+	}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: ownerReferences,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{api.SchemeGroupVersion.Group},
+				Resources:     []string{"pipelineruns"},
+				ResourceNames: []string{pipelineRun.GetName()},
+				Verbs:         []string{"get", "patch"},
+			},
+		},
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: ownerReferences,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: namespace,
+			},
+		},
+	}
+	for _, obj := range []metav1.Object{serviceAccount, role, roleBinding} {
+		slabels.LabelAsSystemManaged(obj)
+		slabels.LabelWithInstanceID(obj, c.factory.InstanceID())
+	}
+
+	// the objects exist already if a previous start attempt failed
+	ignoreExisting := func(err error) error {
+		if k8serrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	_, err := c.factory.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
+	if err = ignoreExisting(err); err != nil {
+		return errors.Wrapf(err, "failed to create run API service account %q in namespace %q", name, namespace)
+	}
+	_, err = c.factory.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{})
+	if err = ignoreExisting(err); err != nil {
+		return errors.Wrapf(err, "failed to create run API role %q in namespace %q", name, namespace)
+	}
+	_, err = c.factory.RbacV1().RoleBindings(namespace).Create(ctx, roleBinding, metav1.CreateOptions{})
+	if err = ignoreExisting(err); err != nil {
+		return errors.Wrapf(err, "failed to create run API role binding %q in namespace %q", name, namespace)
+	}
+
+	token, expiresAt, err := mintRunAPIToken(ctx, c.factory, pipelineRun, c.runAPITokenExpiration)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.RunAPITokenSecret,
+			Namespace: runCtx.runNamespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			runAPITokenSecretKeyToken:     []byte(token),
+			runAPITokenSecretKeyNamespace: []byte(namespace),
+			runAPITokenSecretKeyName:      []byte(pipelineRun.GetName()),
+		},
+	}
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, api.AnnotationRunAPITokenExpiresAt, expiresAt.UTC().Format(time.RFC3339))
+	slabels.LabelAsSystemManaged(secret)
+	slabels.LabelWithInstanceID(secret, c.factory.InstanceID())
+	if _, err := c.factory.CoreV1().Secrets(runCtx.runNamespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return errors.Wrapf(err,
+			"failed to create run API token secret %q in namespace %q",
+			naming.RunAPITokenSecret, runCtx.runNamespace,
+		)
+	}
+	return nil
+}
+
+// revokeRunAPIToken deletes the service account of the per-run token for
+// the Steward API, which invalidates all its tokens, and the role and
+// role binding granting it access to the pipeline run.
+// Does nothing if per-run tokens are disabled.
+func (c *runManager) revokeRunAPIToken(ctx context.Context, runCtx *runContext) error {
+	if c.runAPITokenExpiration <= 0 {
+		return nil
+	}
+	namespace := runCtx.pipelineRun.GetNamespace()
+	name := naming.RunAPIServiceAccount(runCtx.pipelineRun.GetName())
+	ignoreNotFound := func(err error) error {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := ignoreNotFound(c.factory.CoreV1().ServiceAccounts(namespace).Delete(ctx, name, metav1.DeleteOptions{})); err != nil {
+		return errors.Wrapf(err, "failed to delete run API service account %q in namespace %q", name, namespace)
+	}
+	if err := ignoreNotFound(c.factory.RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})); err != nil {
+		return errors.Wrapf(err, "failed to delete run API role binding %q in namespace %q", name, namespace)
+	}
+	if err := ignoreNotFound(c.factory.RbacV1().Roles(namespace).Delete(ctx, name, metav1.DeleteOptions{})); err != nil {
+		return errors.Wrapf(err, "failed to delete run API role %q in namespace %q", name, namespace)
+	}
+	return nil
+}
+
+// mintRunAPIToken requests a token for the per-run service account of the
+// given pipeline run with the given expiration and returns the token and
+// its expiration time.
+func mintRunAPIToken(ctx context.Context, factory k8s.ClientFactory, pipelineRun k8s.PipelineRun, expiration time.Duration) (string, time.Time, error) {
+	name := naming.RunAPIServiceAccount(pipelineRun.GetName())
+	expirationSeconds := int64(expiration / time.Second)
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	result, err := factory.CoreV1().ServiceAccounts(pipelineRun.GetNamespace()).CreateToken(ctx, name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "failed to mint token for run API service account %q", name)
+	}
+	return result.Status.Token, result.Status.ExpirationTimestamp.Time, nil
+}
+
+// refreshRunAPITokenIfDue refreshes the per-run token for the Steward API
+// in the run namespace of the given pipeline run if less than a third of
+// its lifetime remains. The pipeline run is requeued for the next
+// refresh, so that the token is refreshed even if the pipeline run does
+// not change. Failures are reported as event and retried.
+func (c *Controller) refreshRunAPITokenIfDue(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, key string, now time.Time) {
+	if c.runAPITokenExpiration <= 0 {
+		return
+	}
+	if next, ok := c.runAPITokenRefreshTimes.Load(key); ok && now.Before(next.(time.Time)) {
+		return
+	}
+	next, err := c.refreshRunAPIToken(ctx, pipelineRun, now)
+	if err != nil {
+		klog.Errorf("Failed to refresh the run API token of [%s]: %s", pipelineRun.String(), err.Error())
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonRunAPITokenRefreshFailed, err.Error())
+		next = now.Add(runAPITokenRetryInterval)
+	}
+	c.runAPITokenRefreshTimes.Store(key, next)
+	if !next.IsZero() {
+		c.workqueue.AddAfter(key, next.Sub(now))
+	}
+}
+
+// refreshRunAPIToken mints a new token for the run API token secret in the
+// run namespace of the given pipeline run if the current one is due. It
+// returns the time at which the next refresh is due or the zero time if
+// there is no such secret, e.g. because the pipeline run has been started
+// before per-run tokens have been enabled.
+func (c *Controller) refreshRunAPIToken(ctx context.Context, pipelineRun k8s.PipelineRun, now time.Time) (time.Time, error) {
+	runNamespace := pipelineRun.GetRunNamespace()
+	if runNamespace == "" {
+		return time.Time{}, nil
+	}
+	refreshBefore := c.runAPITokenExpiration / 3
+	secretsClient := c.factory.CoreV1().Secrets(runNamespace)
+	secret, err := secretsClient.Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, errors.Wrapf(err, "failed to get secret %q", naming.RunAPITokenSecret)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, secret.GetAnnotations()[api.AnnotationRunAPITokenExpiresAt])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("secret %q: invalid value of annotation %q", secret.GetName(), api.AnnotationRunAPITokenExpiresAt)
+	}
+	if expiresAt.Sub(now) > refreshBefore {
+		return expiresAt.Add(-refreshBefore), nil
+	}
+
+	token, expiresAt, err := mintRunAPIToken(ctx, c.factory, pipelineRun, c.runAPITokenExpiration)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[runAPITokenSecretKeyToken] = []byte(token)
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, api.AnnotationRunAPITokenExpiresAt, expiresAt.UTC().Format(time.RFC3339))
+	if _, err := secretsClient.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to update secret %q", secret.GetName())
+	}
+	klog.V(3).Infof("Refreshed run API token in secret %q [%s]", secret.GetName(), pipelineRun.String())
+	return expiresAt.Add(-refreshBefore), nil
+}
@@ -0,0 +1,263 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	assert "gotest.tools/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// addTokenRequestReactor lets token requests for service accounts return
+// the given token expiring at the given time.
+func addTokenRequestReactor(cf *fake.ClientFactory, token string, expiresAt time.Time) {
+	cf.KubernetesClientset().PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{
+			Status: authenticationv1.TokenRequestStatus{
+				Token:               token,
+				ExpirationTimestamp: metav1.NewTime(expiresAt),
+			},
+		}, nil
+	})
+}
+
+func newRunAPITokenTestSetup(t *testing.T) (*fake.ClientFactory, k8s.PipelineRun, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "runns1"
+	cf := fake.NewClientFactory(run)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	addTokenRequestReactor(cf, "token1", expiresAt)
+	pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+	assert.NilError(t, err)
+	return cf, pipelineRun, expiresAt
+}
+
+func Test_runManager_setupRunAPIToken(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf, pipelineRun, expiresAt := newRunAPITokenTestSetup(t)
+	examinee := &runManager{
+		factory:               cf,
+		runAPITokenExpiration: time.Hour,
+	}
+	runCtx := &runContext{
+		pipelineRun:  pipelineRun,
+		runNamespace: "runns1",
+	}
+
+	// EXERCISE
+	err := examinee.setupRunAPIToken(ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	name := "steward-run-api-run1"
+	serviceAccount, err := cf.CoreV1().ServiceAccounts("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "run1", serviceAccount.OwnerReferences[0].Name)
+	assert.Equal(t, false, *serviceAccount.AutomountServiceAccountToken)
+	role, err := cf.RbacV1().Roles("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []rbacv1.PolicyRule{
+		{
+			APIGroups:     []string{"steward.sap.com"},
+			Resources:     []string{"pipelineruns"},
+			ResourceNames: []string{"run1"},
+			Verbs:         []string{"get", "patch"},
+		},
+	}, role.Rules)
+	roleBinding, err := cf.RbacV1().RoleBindings("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, name, roleBinding.RoleRef.Name)
+	assert.DeepEqual(t, []rbacv1.Subject{
+		{Kind: "ServiceAccount", Name: name, Namespace: "ns1"},
+	}, roleBinding.Subjects)
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string][]byte{
+		"token":     []byte("token1"),
+		"namespace": []byte("ns1"),
+		"name":      []byte("run1"),
+	}, secret.Data)
+	assert.Equal(t, expiresAt.Format(time.RFC3339), secret.GetAnnotations()[api.AnnotationRunAPITokenExpiresAt])
+
+	// EXERCISE + VERIFY: a retried start succeeds
+	runCtx.runNamespace = "runns2"
+	assert.NilError(t, examinee.setupRunAPIToken(ctx, runCtx))
+	_, err = cf.CoreV1().Secrets("runns2").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+}
+
+func Test_runManager_setupRunAPIToken_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf, pipelineRun, _ := newRunAPITokenTestSetup(t)
+	examinee := &runManager{factory: cf}
+	runCtx := &runContext{
+		pipelineRun:  pipelineRun,
+		runNamespace: "runns1",
+	}
+
+	// EXERCISE
+	err := examinee.setupRunAPIToken(ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	_, err = cf.CoreV1().ServiceAccounts("ns1").Get(ctx, "steward-run-api-run1", metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	_, err = cf.CoreV1().Secrets("runns1").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func Test_runManager_revokeRunAPIToken(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf, pipelineRun, _ := newRunAPITokenTestSetup(t)
+	examinee := &runManager{
+		factory:               cf,
+		runAPITokenExpiration: time.Hour,
+	}
+	runCtx := &runContext{
+		pipelineRun:  pipelineRun,
+		runNamespace: "runns1",
+	}
+	assert.NilError(t, examinee.setupRunAPIToken(ctx, runCtx))
+
+	// EXERCISE
+	err := examinee.revokeRunAPIToken(ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	name := "steward-run-api-run1"
+	_, err = cf.CoreV1().ServiceAccounts("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	_, err = cf.RbacV1().Roles("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+	_, err = cf.RbacV1().RoleBindings("ns1").Get(ctx, name, metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+
+	// EXERCISE + VERIFY: revoking again succeeds
+	assert.NilError(t, examinee.revokeRunAPIToken(ctx, runCtx))
+}
+
+func newRunAPITokenRefreshTestSetup(t *testing.T, tokenExpiresIn time.Duration) (*Controller, *fake.ClientFactory, k8s.PipelineRun, time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Namespace = "runns1"
+	controller, cf := newController(run)
+	controller.runAPITokenExpiration = time.Hour
+	newExpiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	addTokenRequestReactor(cf, "newToken", newExpiry)
+
+	secret := fake.SecretOpaque(naming.RunAPITokenSecret, "runns1")
+	secret.SetAnnotations(map[string]string{
+		api.AnnotationRunAPITokenExpiresAt: time.Now().Add(tokenExpiresIn).UTC().Format(time.RFC3339),
+	})
+	secret.Data = map[string][]byte{
+		runAPITokenSecretKeyToken:     []byte("oldToken"),
+		runAPITokenSecretKeyNamespace: []byte("ns1"),
+		runAPITokenSecretKeyName:      []byte("run1"),
+	}
+	_, err := cf.CoreV1().Secrets("runns1").Create(ctx, secret, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+	assert.NilError(t, err)
+	return controller, cf, pipelineRun, newExpiry
+}
+
+func Test_Controller_refreshRunAPIToken_Expiring(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, newExpiry := newRunAPITokenRefreshTestSetup(t, 10*time.Minute)
+
+	// EXERCISE
+	next, err := examinee.refreshRunAPIToken(ctx, pipelineRun, time.Now())
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, newExpiry.Add(-20*time.Minute).Equal(next))
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "newToken", string(secret.Data[runAPITokenSecretKeyToken]))
+	assert.Equal(t, "run1", string(secret.Data[runAPITokenSecretKeyName]))
+	assert.Equal(t, newExpiry.Format(time.RFC3339), secret.GetAnnotations()[api.AnnotationRunAPITokenExpiresAt])
+}
+
+func Test_Controller_refreshRunAPIToken_NotDue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, _ := newRunAPITokenRefreshTestSetup(t, 40*time.Minute)
+	now := time.Now()
+
+	// EXERCISE
+	next, err := examinee.refreshRunAPIToken(ctx, pipelineRun, now)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, next.After(now.Add(19*time.Minute)))
+	assert.Assert(t, next.Before(now.Add(21*time.Minute)))
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "oldToken", string(secret.Data[runAPITokenSecretKeyToken]))
+}
+
+func Test_Controller_refreshRunAPIToken_NoSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, _ := newRunAPITokenRefreshTestSetup(t, 10*time.Minute)
+	assert.NilError(t, cf.CoreV1().Secrets("runns1").Delete(ctx, naming.RunAPITokenSecret, metav1.DeleteOptions{}))
+
+	// EXERCISE
+	next, err := examinee.refreshRunAPIToken(ctx, pipelineRun, time.Now())
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, next.IsZero())
+}
+
+func Test_Controller_refreshRunAPITokenIfDue_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee, cf, pipelineRun, _ := newRunAPITokenRefreshTestSetup(t, 10*time.Minute)
+	examinee.runAPITokenExpiration = 0
+
+	// EXERCISE
+	examinee.refreshRunAPITokenIfDue(ctx, pipelineRun.GetAPIObject(), pipelineRun, "ns1/run1", time.Now())
+
+	// VERIFY
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, naming.RunAPITokenSecret, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "oldToken", string(secret.Data[runAPITokenSecretKeyToken]))
+	_, ok := examinee.runAPITokenRefreshTimes.Load("ns1/run1")
+	assert.Assert(t, !ok)
+}
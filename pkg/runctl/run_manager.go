@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward"
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
@@ -14,26 +19,31 @@ import (
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/imagepolicy"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/SAP/stewardci-core/pkg/runctl/revision"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	"github.com/SAP/stewardci-core/pkg/runctl/secretmgr"
+	"github.com/SAP/stewardci-core/pkg/sealedsecrets"
 	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
 	"github.com/SAP/stewardci-core/pkg/utils"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
 	"github.com/pkg/errors"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1api "k8s.io/api/core/v1"
 	networkingv1api "k8s.io/api/networking/v1"
+	rbacv1api "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	yamlserial "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
-	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
 )
 
 const (
-	runNamespacePrefix       = "steward-run"
 	runNamespaceRandomLength = 5
 	serviceAccountName       = "default"
 
@@ -50,14 +60,33 @@ const (
 	// in the Tekton TaskRun that executes the Jenkinsfile Runner
 	tektonClusterTaskJenkinsfileRunnerStep = "jenkinsfile-runner"
 
-	// tektonTaskRun is the name of the Tekton TaskRun in each
-	// run namespace.
-	tektonTaskRunName = "steward-jenkinsfile-runner"
+	// pipelineParamsConfigMapKey is the key of the config map entry
+	// containing the offloaded pipeline arguments as JSON object.
+	pipelineParamsConfigMapKey = "params.json"
+
+	// pipelineParamsVolumeName is the name of the volume in the
+	// Jenkinsfile Runner pod providing the offloaded pipeline arguments.
+	pipelineParamsVolumeName = "pipeline-params"
+
+	// runAPITokenVolumeName is the name of the volume in the Jenkinsfile
+	// Runner pod providing the per-run token for the Steward API.
+	runAPITokenVolumeName = "run-api-token"
+
+	// verifiedImageCacheTTL is the time successful signature
+	// verifications of images pinned to a digest are remembered.
+	verifiedImageCacheTTL = time.Hour
 )
 
 type runManager struct {
-	factory        k8s.ClientFactory
-	secretProvider secrets.SecretProvider
+	factory          k8s.ClientFactory
+	secretProvider   secrets.SecretProvider
+	decrypter        sealedsecrets.Decrypter
+	revisionResolver revisionResolver
+	imageVerifier    imagepolicy.SignatureVerifier
+
+	// runAPITokenExpiration is the expiration of per-run tokens for the
+	// Steward API. If zero or negative, per-run tokens are disabled.
+	runAPITokenExpiration time.Duration
 
 	testing *runManagerTesting
 }
@@ -65,10 +94,12 @@ type runManager struct {
 type runManagerTesting struct {
 	cleanupStub                               func(context.Context, *runContext) error
 	copySecretsToRunNamespaceStub             func(context.Context, *runContext) (string, []string, error)
+	copySourceSecretsToRunNamespaceStub       func(context.Context, *runContext) ([]string, error)
 	createTektonTaskRunStub                   func(context.Context, *runContext) error
 	getSecretManagerStub                      func(*runContext) runifc.SecretManager
 	getServiceAccountSecretNameStub           func(context.Context, *runContext) (string, error)
 	prepareRunNamespaceStub                   func(context.Context, *runContext) error
+	resolveRevisionStub                       func(context.Context, *runContext) error
 	setupLimitRangeFromConfigStub             func(context.Context, *runContext) error
 	setupNetworkPolicyFromConfigStub          func(context.Context, *runContext) error
 	setupNetworkPolicyThatIsolatesAllPodsStub func(context.Context, *runContext) error
@@ -85,13 +116,30 @@ type runContext struct {
 	runNamespace       string
 	auxNamespace       string
 	serviceAccount     *k8s.ServiceAccountWrap
+
+	// resolvedRevision is the commit SHA the revision of the pipeline
+	// repository has been resolved to or empty if the revision has not
+	// been resolved.
+	resolvedRevision string
+
+	// sourceSecretNames are the names of the auth secrets of the
+	// source repositories in the run namespace, in the order of
+	// `spec.sources`. They get attached to the service account.
+	sourceSecretNames []string
+}
+
+// revisionResolver resolves revisions of Git repositories to commit SHAs.
+type revisionResolver interface {
+	Resolve(ctx context.Context, repoURL, revision string, credentials *revision.Credentials) (string, error)
 }
 
 // newRunManager creates a new runManager.
 func newRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvider) *runManager {
 	return &runManager{
-		factory:        factory,
-		secretProvider: secretProvider,
+		factory:          factory,
+		secretProvider:   secretProvider,
+		revisionResolver: revision.NewResolver(nil),
+		imageVerifier:    imagepolicy.NewCachingVerifier(&imagepolicy.CosignVerifier{}, verifiedImageCacheTTL),
 	}
 }
 
@@ -117,6 +165,16 @@ func (c *runManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pip
 		}
 	}()
 
+	err = c.checkAirGapped(runCtx)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = c.resolveRevision(ctx, runCtx)
+	if err != nil {
+		return "", "", err
+	}
+
 	err = c.prepareRunNamespace(ctx, runCtx)
 	if err != nil {
 		return "", "", err
@@ -125,6 +183,70 @@ func (c *runManager) Start(ctx context.Context, pipelineRun k8s.PipelineRun, pip
 	return runCtx.runNamespace, runCtx.auxNamespace, c.createTektonTaskRun(ctx, runCtx)
 }
 
+// resolveRevision resolves the revision of the pipeline repository to a
+// commit SHA and records it in the status of the pipeline run, if enabled
+// in the pipeline runs configuration and the repository is hosted on one
+// of the allowed hosts.
+func (c *runManager) resolveRevision(ctx context.Context, runCtx *runContext) error {
+	if c.testing != nil && c.testing.resolveRevisionStub != nil {
+		return c.testing.resolveRevisionStub(ctx, runCtx)
+	}
+	if !runCtx.pipelineRunsConfig.ResolveRevision {
+		return nil
+	}
+
+	pipeline := runCtx.pipelineRun.GetSpec().JenkinsFile
+	if err := revision.CheckURL(pipeline.URL, runCtx.pipelineRunsConfig.ResolveRevisionAllowedHosts); err != nil {
+		// the Jenkinsfile Runner checks out the revision as given
+		klog.V(4).Infof("Not resolving revision of pipeline repository: %s [%s]", err, runCtx.pipelineRun.String())
+		return nil
+	}
+	credentials, err := c.getRepoCredentials(ctx, pipeline.RepoAuthSecret)
+	if err != nil {
+		return err
+	}
+	sha, err := c.revisionResolver.Resolve(ctx, pipeline.URL, pipeline.Revision, credentials)
+	if err != nil {
+		err = errors.WithMessage(err, "failed to resolve the revision of the pipeline repository")
+		if errors.Is(err, revision.ErrNotFound) {
+			return serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+		}
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorInfra)
+	}
+	klog.V(4).Infof("Resolved revision %q of pipeline repository to %q [%s]", pipeline.Revision, sha, runCtx.pipelineRun.String())
+	runCtx.resolvedRevision = sha
+	runCtx.pipelineRun.UpdateResolvedRevision(sha)
+	return nil
+}
+
+// getRepoCredentials returns the credentials contained in the repository
+// auth secret with the given name or nil if the name is empty.
+// Besides basic-auth secrets, token-type secrets are supported (see
+// secrets.RepoCredentials).
+func (c *runManager) getRepoCredentials(ctx context.Context, secretName string) (*revision.Credentials, error) {
+	if secretName == "" {
+		return nil, nil
+	}
+	secret, err := c.secretProvider.GetSecret(ctx, secretName)
+	if err != nil {
+		return nil, serrors.Classify(
+			errors.Wrapf(err, "failed to get secret %q", secretName),
+			stewardv1alpha1.ResultErrorInfra,
+		)
+	}
+	if secret == nil {
+		return nil, serrors.Classify(
+			fmt.Errorf("secret %q not found", secretName),
+			stewardv1alpha1.ResultErrorContent,
+		)
+	}
+	username, password := secrets.RepoCredentials(secret)
+	return &revision.Credentials{
+		Username: username,
+		Password: password,
+	}, nil
+}
+
 // prepareRunNamespace creates a new namespace for the pipeline run
 // and populates it with needed resources.
 func (c *runManager) prepareRunNamespace(ctx context.Context, runCtx *runContext) error {
@@ -157,11 +279,20 @@ func (c *runManager) prepareRunNamespace(ctx context.Context, runCtx *runContext
 		return err
 	}
 
+	runCtx.sourceSecretNames, err = c.copySourceSecretsToRunNamespace(ctx, runCtx)
+	if err != nil {
+		return err
+	}
+
 	err = c.setupServiceAccount(ctx, runCtx, pipelineCloneSecretName, imagePullSecretNames)
 	if err != nil {
 		return err
 	}
 
+	if err = c.setupRunAPIToken(ctx, runCtx); err != nil {
+		return err
+	}
+
 	if err = c.setupStaticNetworkPolicies(ctx, runCtx); err != nil {
 		return err
 	}
@@ -184,13 +315,13 @@ func (c *runManager) setupServiceAccount(ctx context.Context, runCtx *runContext
 
 	accountManager := k8s.NewServiceAccountManager(c.factory, runCtx.runNamespace)
 	serviceAccount, err := accountManager.CreateServiceAccount(ctx, serviceAccountName, pipelineCloneSecretName, imagePullSecrets)
-	if err != nil {
-		if !k8serrors.IsAlreadyExists(err) {
-			return errors.Wrapf(err, "failed to create service account %q", serviceAccountName)
-		}
-
-		// service account exists already, so we need to attach secrets to it
-		for { // retry loop
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create service account %q", serviceAccountName)
+	}
+	if err != nil || hasNonEmpty(runCtx.sourceSecretNames) {
+		// service account exists already or the auth secrets of source
+		// repositories are required, so we need to attach secrets to it
+		err = retry.OnConflict(ctx, func() error {
 			serviceAccount, err = accountManager.GetServiceAccount(ctx, serviceAccountName)
 			if err != nil {
 				return errors.Wrapf(err, "failed to get service account %q", serviceAccountName)
@@ -198,12 +329,10 @@ func (c *runManager) setupServiceAccount(ctx context.Context, runCtx *runContext
 			if pipelineCloneSecretName != "" {
 				serviceAccount.AttachSecrets(pipelineCloneSecretName)
 			}
+			serviceAccount.AttachSecrets(runCtx.sourceSecretNames...)
 			serviceAccount.AttachImagePullSecrets(imagePullSecrets...)
 			serviceAccount.SetDoAutomountServiceAccountToken(automountServiceAccountToken)
 			err = serviceAccount.Update(ctx)
-			if err == nil {
-				break // ...the retry loop
-			}
 			if k8serrors.IsConflict(err) {
 				// resource version conflict -> retry update with latest version
 				klog.V(4).Infof(
@@ -211,9 +340,18 @@ func (c *runManager) setupServiceAccount(ctx context.Context, runCtx *runContext
 						" after resource version conflict",
 					serviceAccountName, runCtx.runNamespace,
 				)
-			} else {
-				return errors.Wrapf(err, "failed to update service account %q", serviceAccountName)
+				return err
 			}
+			return errors.Wrapf(err, "failed to update service account %q", serviceAccountName)
+		})
+		if k8serrors.IsConflict(err) {
+			return errors.Wrapf(err,
+				"failed to update service account %q: retries on resource version conflicts exhausted",
+				serviceAccountName,
+			)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
@@ -233,7 +371,56 @@ func (c *runManager) copySecretsToRunNamespace(ctx context.Context, runCtx *runC
 	if c.testing != nil && c.testing.copySecretsToRunNamespaceStub != nil {
 		return c.testing.copySecretsToRunNamespaceStub(ctx, runCtx)
 	}
-	return c.getSecretManager(runCtx).CopyAll(ctx, runCtx.pipelineRun)
+	secretManager := c.getSecretManager(runCtx)
+	pipelineCloneSecretName, imagePullSecretNames, err := secretManager.CopyAll(ctx, runCtx.pipelineRun)
+	if err != nil {
+		return "", nil, err
+	}
+	defaultImagePullSecrets, err := c.getDefaultImagePullSecrets(ctx, runCtx)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(defaultImagePullSecrets) > 0 {
+		names, err := secretManager.CopyImagePullSecrets(ctx, runCtx.pipelineRun, defaultImagePullSecrets)
+		if err != nil {
+			return "", nil, errors.Wrap(err, "failed to copy default image pull secrets of the tenant namespace")
+		}
+		imagePullSecretNames = append(imagePullSecretNames, names...)
+	}
+	return pipelineCloneSecretName, imagePullSecretNames, nil
+}
+
+// getDefaultImagePullSecrets returns the names of the default image pull
+// secrets defined via annotation AnnotationDefaultImagePullSecrets of the
+// namespace of the pipeline run, except those listed in
+// `spec.imagePullSecrets` already.
+func (c *runManager) getDefaultImagePullSecrets(ctx context.Context, runCtx *runContext) ([]string, error) {
+	namespaceName := runCtx.pipelineRun.GetNamespace()
+	namespace, err := c.factory.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, serrors.Classify(
+			errors.Wrapf(err, "failed to get namespace %q", namespaceName),
+			stewardv1alpha1.ResultErrorInfra,
+		)
+	}
+	var result []string
+	specSecrets := runCtx.pipelineRun.GetSpec().ImagePullSecrets
+	for _, name := range utils.SplitList(namespace.GetAnnotations()[stewardv1alpha1.AnnotationDefaultImagePullSecrets]) {
+		if !utils.StringSliceContains(specSecrets, name) && !utils.StringSliceContains(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+func (c *runManager) copySourceSecretsToRunNamespace(ctx context.Context, runCtx *runContext) ([]string, error) {
+	if c.testing != nil && c.testing.copySourceSecretsToRunNamespaceStub != nil {
+		return c.testing.copySourceSecretsToRunNamespaceStub(ctx, runCtx)
+	}
+	return c.getSecretManager(runCtx).CopySourceSecrets(ctx, runCtx.pipelineRun)
 }
 
 func (c *runManager) getSecretManager(runCtx *runContext) runifc.SecretManager {
@@ -242,7 +429,7 @@ func (c *runManager) getSecretManager(runCtx *runContext) runifc.SecretManager {
 	}
 	targetClient := c.factory.CoreV1().Secrets(runCtx.runNamespace)
 	secretHelper := secrets.NewSecretHelper(c.secretProvider, runCtx.runNamespace, targetClient)
-	return secretmgr.NewSecretManager(secretHelper)
+	return secretmgr.NewSecretManager(secretHelper, c.decrypter)
 }
 
 func (c *runManager) setupStaticNetworkPolicies(ctx context.Context, runCtx *runContext) error {
@@ -285,6 +472,7 @@ func (c *runManager) setupNetworkPolicyThatIsolatesAllPods(ctx context.Context,
 	}
 
 	slabels.LabelAsSystemManaged(policy)
+	slabels.LabelWithInstanceID(policy, c.factory.InstanceID())
 
 	policyIfce := c.factory.NetworkingV1().NetworkPolicies(runCtx.runNamespace)
 	if _, err := policyIfce.Create(ctx, policy, metav1.CreateOptions{}); err != nil {
@@ -420,6 +608,7 @@ func (c *runManager) createResource(ctx context.Context, configStr string, resou
 		obj.SetNamespace(runCtx.runNamespace)
 
 		slabels.LabelAsSystemManaged(obj)
+		slabels.LabelWithInstanceID(obj, c.factory.InstanceID())
 	}
 
 	// create resource object
@@ -453,6 +642,97 @@ func (c *runManager) volumesWithServiceAccountSecret(secretName string) []corev1
 	}
 }
 
+// volumeWithPipelineParams returns the volume providing the offloaded
+// pipeline arguments. The volume is optional, i.e. it is empty if no
+// arguments have been offloaded.
+func (c *runManager) volumeWithPipelineParams() corev1api.Volume {
+	var mode int32 = 0644
+	optional := true
+	return corev1api.Volume{
+		Name: pipelineParamsVolumeName,
+		VolumeSource: corev1api.VolumeSource{
+			ConfigMap: &corev1api.ConfigMapVolumeSource{
+				LocalObjectReference: corev1api.LocalObjectReference{
+					Name: naming.PipelineParamsConfigMap,
+				},
+				DefaultMode: &mode,
+				Optional:    &optional,
+			},
+		},
+	}
+}
+
+// volumeWithRunAPIToken returns the volume providing the per-run token
+// for the Steward API. The volume is optional, i.e. it is empty if
+// per-run tokens are disabled.
+func (c *runManager) volumeWithRunAPIToken() corev1api.Volume {
+	var mode int32 = 0644
+	optional := true
+	return corev1api.Volume{
+		Name: runAPITokenVolumeName,
+		VolumeSource: corev1api.VolumeSource{
+			Secret: &corev1api.SecretVolumeSource{
+				SecretName:  naming.RunAPITokenSecret,
+				DefaultMode: &mode,
+				Optional:    &optional,
+			},
+		},
+	}
+}
+
+// offloadLargePipelineArgs stores all pipeline arguments exceeding the
+// configured size threshold in a config map in the run namespace.
+// It returns the remaining arguments to be passed inline.
+func (c *runManager) offloadLargePipelineArgs(ctx context.Context, runCtx *runContext) (map[string]string, error) {
+	args := runCtx.pipelineRun.GetSpec().Args
+	threshold := runCtx.pipelineRunsConfig.PipelineArgsOffloadThresholdBytes
+	if threshold == nil || *threshold <= 0 {
+		return args, nil
+	}
+
+	inlineArgs := map[string]string{}
+	offloadedArgs := map[string]string{}
+	for key, value := range args {
+		if int64(len(key)+len(value)) > *threshold {
+			offloadedArgs[key] = value
+		} else {
+			inlineArgs[key] = value
+		}
+	}
+	if len(offloadedArgs) == 0 {
+		return args, nil
+	}
+
+	offloadedArgsJSON, err := toJSONString(&offloadedArgs)
+	if err != nil {
+		return nil, serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	configMap := &corev1api.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.PipelineParamsConfigMap,
+			Namespace: runCtx.runNamespace,
+		},
+		Data: map[string]string{
+			pipelineParamsConfigMapKey: offloadedArgsJSON,
+		},
+	}
+	slabels.LabelAsSystemManaged(configMap)
+	slabels.LabelWithInstanceID(configMap, c.factory.InstanceID())
+
+	configMapIfce := c.factory.CoreV1().ConfigMaps(runCtx.runNamespace)
+	if _, err := configMapIfce.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return nil, errors.Wrapf(err,
+			"failed to create config map %q for offloaded pipeline arguments in namespace %q",
+			naming.PipelineParamsConfigMap, runCtx.runNamespace,
+		)
+	}
+	klog.V(4).Infof(
+		"offloaded %d pipeline arguments of pipeline run %q to config map %q in namespace %q",
+		len(offloadedArgs), runCtx.pipelineRun.GetKey(), naming.PipelineParamsConfigMap, runCtx.runNamespace,
+	)
+	return inlineArgs, nil
+}
+
 func (c *runManager) getServiceAccountSecretName(ctx context.Context, runCtx *runContext) (string, error) {
 	if c.testing != nil && c.testing.getServiceAccountSecretNameStub != nil {
 		return c.testing.getServiceAccountSecretNameStub(ctx, runCtx)
@@ -469,12 +749,13 @@ func (c *runManager) createTektonTaskRun(ctx context.Context, runCtx *runContext
 
 	var err error
 
-	copyInt64Ptr := func(ptr *int64) *int64 {
-		if ptr != nil {
-			v := *ptr
-			return &v
-		}
-		return nil
+	securityContext, err := podSecurityContext(runCtx)
+	if err != nil {
+		return err
+	}
+	runtimeClass, err := runtimeClassName(runCtx)
+	if err != nil {
+		return err
 	}
 
 	namespace := runCtx.runNamespace
@@ -485,7 +766,7 @@ func (c *runManager) createTektonTaskRun(ctx context.Context, runCtx *runContext
 
 	tektonTaskRun := tekton.TaskRun{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tektonTaskRunName,
+			Name:      naming.JenkinsfileRunnerTaskRun,
 			Namespace: namespace,
 			Annotations: map[string]string{
 				annotationPipelineRunKey: runCtx.pipelineRun.GetKey(),
@@ -500,46 +781,654 @@ func (c *runManager) createTektonTaskRun(ctx context.Context, runCtx *runContext
 			Params: []tekton.Param{
 				tektonStringParam("RUN_NAMESPACE", namespace),
 			},
-			Timeout: runCtx.pipelineRunsConfig.Timeout,
+			Timeout: effectiveTimeout(runCtx.pipelineRun.GetSpec().Timeout, runCtx.pipelineRunsConfig.Timeout),
 
 			// Always set a non-empty pod template even if we don't have
 			// values to set. Otherwise the Tekton default pod template
 			// would be used only in such cases but not if we have values
 			// to set.
 			PodTemplate: &tekton.PodTemplate{
-				SecurityContext: &corev1api.PodSecurityContext{
-					RunAsUser:  copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
-					RunAsGroup: copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
-					FSGroup:    copyInt64Ptr(runCtx.pipelineRunsConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
-				},
-				Volumes: c.volumesWithServiceAccountSecret(serviceAccountSecretName),
+				SecurityContext:  securityContext,
+				RuntimeClassName: runtimeClass,
+				Volumes: append(
+					c.volumesWithServiceAccountSecret(serviceAccountSecretName),
+					c.volumeWithPipelineParams(),
+					c.volumeWithRunAPIToken(),
+				),
 			},
 		},
 	}
+	inlineArgs, err := c.offloadLargePipelineArgs(ctx, runCtx)
+	if err != nil {
+		return err
+	}
 	c.addTektonTaskRunParamsForJenkinsfileRunnerImage(runCtx, &tektonTaskRun)
-	err = c.addTektonTaskRunParamsForPipeline(runCtx, &tektonTaskRun)
+	err = c.addTektonTaskRunParamsForPipeline(runCtx, &tektonTaskRun, inlineArgs)
 	if err != nil {
 		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
 	}
+	err = c.addTektonTaskRunParamsForSources(runCtx, &tektonTaskRun)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+	}
 	err = c.addTektonTaskRunParamsForLoggingElasticsearch(runCtx, &tektonTaskRun)
 	if err != nil {
 		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
 	}
+	err = c.addTektonTaskRunParamsForLogForwarding(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+
+	err = c.addTektonTaskRunParamsForTestResults(runCtx, &tektonTaskRun)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	err = c.addTektonTaskRunParamsForArtifacts(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
 
 	c.addTektonTaskRunParamsForRunDetails(runCtx, &tektonTaskRun)
+	err = applyExecutionProfile(runCtx, &tektonTaskRun)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	err = applySchedulingProfile(runCtx, &tektonTaskRun)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorConfig)
+	}
+	applyTerminationGracePeriod(runCtx, &tektonTaskRun)
+	err = c.addTektonTaskRunExtendedResources(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	err = c.addTektonTaskRunComputeResources(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	err = c.addTektonTaskRunContainerSecurityContext(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	err = c.checkImagePolicy(ctx, runCtx, &tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	slabels.LabelWithInstanceID(&tektonTaskRun, c.factory.InstanceID())
 	tektonClient := c.factory.TektonV1beta1()
 	_, err = tektonClient.TaskRuns(tektonTaskRun.GetNamespace()).Create(ctx, &tektonTaskRun, metav1.CreateOptions{})
-	return err
+	return serrors.MarkIfQuotaExceeded(err)
+}
+
+// checkImagePolicy verifies that the images of all containers of the
+// Tekton task run, i.e. all steps and sidecars of the task with task run
+// parameters substituted, are allowed by the image policy defined in the
+// pipeline runs configuration. If an image signature public key is
+// configured, the images get resolved to their current digest and the
+// signatures of the digests get verified. The task spec then gets
+// embedded into the task run with the images pinned to the verified
+// digests, so that a tag moved after the verification cannot take
+// effect.
+// Containers injected by Tekton itself are not checked.
+func (c *runManager) checkImagePolicy(ctx context.Context, runCtx *runContext, tektonTaskRun *tekton.TaskRun) error {
+	config := runCtx.pipelineRunsConfig
+	policy := &imagepolicy.Policy{
+		AllowedRegistries: config.AllowedImageRegistries,
+		AllowedDigests:    config.AllowedImageDigests,
+	}
+	publicKey := config.ImageSignaturePublicKey
+	if policy.IsEmpty() && publicKey == "" {
+		return nil
+	}
+
+	var taskSpec *tekton.TaskSpec
+	if publicKey != "" {
+		var err error
+		taskSpec, err = c.embedTaskSpec(ctx, tektonTaskRun)
+		if err != nil {
+			return err
+		}
+	} else {
+		taskSpec = tektonTaskRun.Spec.TaskSpec
+		if taskSpec == nil {
+			clusterTask, err := c.factory.TektonV1beta1().ClusterTasks().Get(ctx, tektonClusterTaskName, metav1.GetOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "failed to get Tekton ClusterTask %q", tektonClusterTaskName)
+			}
+			taskSpec = &clusterTask.Spec
+		}
+	}
+
+	params := map[string]string{}
+	for _, paramSpec := range taskSpec.Params {
+		if paramSpec.Default != nil {
+			params[paramSpec.Name] = paramSpec.Default.StringVal
+		}
+	}
+	for _, param := range tektonTaskRun.Spec.Params {
+		params[param.Name] = param.Value.StringVal
+	}
+
+	// check checks the given image and returns the image to be used,
+	// which is pinned to the verified digest if signatures are verified
+	check := func(kind, name, image string) (string, error) {
+		image = substituteTaskParams(image, params)
+		if err := policy.Check(image); err != nil {
+			return "", serrors.Classify(
+				errors.WithMessagef(err, "image policy violation in %s %q", kind, name),
+				stewardv1alpha1.ResultErrorContent,
+			)
+		}
+		if publicKey == "" {
+			return image, nil
+		}
+		resolved, err := c.imageVerifier.Resolve(ctx, image)
+		if err != nil {
+			return "", errors.WithMessagef(err, "failed to resolve image of %s %q", kind, name)
+		}
+		if err := c.imageVerifier.Verify(ctx, resolved, publicKey); err != nil {
+			if errors.Is(err, imagepolicy.ErrSignatureInvalid) {
+				return "", serrors.Classify(
+					errors.WithMessagef(err, "image policy violation in %s %q", kind, name),
+					stewardv1alpha1.ResultErrorContent,
+				)
+			}
+			return "", errors.WithMessagef(err, "failed to verify image signature of %s %q", kind, name)
+		}
+		return resolved, nil
+	}
+	for i := range taskSpec.Steps {
+		step := &taskSpec.Steps[i]
+		image, err := check("step", step.Name, step.Image)
+		if err != nil {
+			return err
+		}
+		if publicKey != "" {
+			step.Image = image
+		}
+	}
+	for i := range taskSpec.Sidecars {
+		sidecar := &taskSpec.Sidecars[i]
+		image, err := check("sidecar", sidecar.Name, sidecar.Image)
+		if err != nil {
+			return err
+		}
+		if publicKey != "" {
+			sidecar.Image = image
+		}
+	}
+	return nil
+}
+
+// taskParamRefPattern matches references to Tekton task parameters like
+// `$(params.NAME)`.
+var taskParamRefPattern = regexp.MustCompile(`\$\(params\.([-_a-zA-Z0-9]+)\)`)
+
+// substituteTaskParams replaces references to Tekton task parameters in
+// the given string by the given parameter values like Tekton does.
+// References to unknown parameters are retained.
+func substituteTaskParams(value string, params map[string]string) string {
+	return taskParamRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		name := taskParamRefPattern.FindStringSubmatch(ref)[1]
+		if paramValue, ok := params[name]; ok {
+			return paramValue
+		}
+		return ref
+	})
+}
+
+// podSecurityContext returns the pod security context of the Jenkinsfile
+// Runner pod. It is taken from the security context profile selected in
+// the pipeline run spec or the default profile, with user and group IDs
+// picked from the ID ranges of the profile if defined. If there is no
+// such profile, the security context is assembled from the individual
+// `jenkinsfileRunner.podSecurityContext.*` configuration values.
+// In both cases the configured seccomp profile applies if the security
+// context does not define one.
+func podSecurityContext(runCtx *runContext) (*corev1api.PodSecurityContext, error) {
+	config := runCtx.pipelineRunsConfig
+	profile, err := securityProfile(runCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile != nil {
+		result := profile.PodSecurityContext.DeepCopy()
+		if result == nil {
+			result = &corev1api.PodSecurityContext{}
+		}
+		if profile.RunAsUserRange != nil {
+			result.RunAsUser = idFromRange(profile.RunAsUserRange, runCtx.runNamespace)
+		}
+		if profile.RunAsGroupRange != nil {
+			result.RunAsGroup = idFromRange(profile.RunAsGroupRange, runCtx.runNamespace)
+		}
+		if result.SeccompProfile == nil {
+			result.SeccompProfile = config.JenkinsfileRunnerPodSecurityContextSeccompProfile.DeepCopy()
+		}
+		return result, nil
+	}
+
+	copyInt64Ptr := func(ptr *int64) *int64 {
+		if ptr != nil {
+			v := *ptr
+			return &v
+		}
+		return nil
+	}
+
+	return &corev1api.PodSecurityContext{
+		RunAsUser:      copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextRunAsUser),
+		RunAsGroup:     copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextRunAsGroup),
+		FSGroup:        copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextFSGroup),
+		SeccompProfile: config.JenkinsfileRunnerPodSecurityContextSeccompProfile.DeepCopy(),
+	}, nil
+}
+
+// runtimeClassName returns the name of the runtime class of the
+// Jenkinsfile Runner pod. It is taken from the security context profile
+// selected in the pipeline run spec or the default profile. If the
+// profile does not define one, the configured runtime class is used.
+// It returns nil if no runtime class is defined.
+func runtimeClassName(runCtx *runContext) (*string, error) {
+	profile, err := securityProfile(runCtx)
+	if err != nil {
+		return nil, err
+	}
+	name := runCtx.pipelineRunsConfig.JenkinsfileRunnerRuntimeClassName
+	if profile != nil && profile.RuntimeClassName != "" {
+		name = profile.RuntimeClassName
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return &name, nil
+}
+
+// securityProfile returns the security context profile selected in the
+// pipeline run spec or the default profile. It returns nil if neither
+// is defined.
+func securityProfile(runCtx *runContext) (*cfg.SecurityProfile, error) {
+	config := runCtx.pipelineRunsConfig
+	profile := config.DefaultSecurityProfile
+
+	spec := runCtx.pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.SecurityContext != "" {
+		profile = spec.Profiles.SecurityContext
+
+		if _, exists := config.SecurityProfiles[profile]; !exists {
+			return nil, serrors.Classify(fmt.Errorf("security context profile %q does not exist", profile), stewardv1alpha1.ResultErrorConfig)
+		}
+	}
+
+	if profile == "" {
+		return nil, nil
+	}
+	return config.SecurityProfiles[profile], nil
+}
+
+// idFromRange returns an ID from the given range. The ID is derived from
+// the given key, so that the same key always yields the same ID.
+func idFromRange(idRange *cfg.IDRange, key string) *int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	size := uint64(idRange.Max-idRange.Min) + 1
+	id := idRange.Min + int64(hash.Sum64()%size)
+	return &id
+}
+
+// addTektonTaskRunContainerSecurityContext sets the container security
+// context of the selected security context profile at all steps of the
+// Jenkinsfile Runner task.
+// Like for extended resources, the spec of the Jenkinsfile Runner
+// ClusterTask gets embedded into the task run for this.
+func (c *runManager) addTektonTaskRunContainerSecurityContext(
+	ctx context.Context,
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	profile, err := securityProfile(runCtx)
+	if err != nil {
+		return err
+	}
+	if profile == nil || profile.ContainerSecurityContext == nil {
+		return nil
+	}
+
+	taskSpec, err := c.embedTaskSpec(ctx, tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	for i := range taskSpec.Steps {
+		taskSpec.Steps[i].SecurityContext = profile.ContainerSecurityContext.DeepCopy()
+	}
+	return nil
+}
+
+// applyExecutionProfile adjusts the given task run according to the
+// execution profile selected in the pipeline run spec.
+// Pods cannot be configured to run in a user namespace via the Tekton
+// pod template, so the task run gets labelled accordingly instead. The
+// Steward admission webhook sets `hostUsers: false` at pods with this label.
+func applyExecutionProfile(runCtx *runContext, tektonTaskRun *tekton.TaskRun) error {
+	spec := runCtx.pipelineRun.GetSpec()
+	if spec.Profiles == nil {
+		return nil
+	}
+
+	switch profile := spec.Profiles.Execution; profile {
+	case stewardv1alpha1.ExecutionProfileDefault:
+		return nil
+	case stewardv1alpha1.ExecutionProfileUserNamespace:
+		if !runCtx.pipelineRunsConfig.UserNamespacesEnabled {
+			return fmt.Errorf("execution profile %q is not enabled in this Steward installation", profile)
+		}
+		labels := tektonTaskRun.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[stewardv1alpha1.LabelHostUsers] = "false"
+		tektonTaskRun.SetLabels(labels)
+		return nil
+	default:
+		return fmt.Errorf("execution profile %q does not exist", profile)
+	}
+}
+
+// applySchedulingProfile adds the topology spread constraints of the
+// scheduling profile selected in the pipeline run spec to the given task
+// run. If no profile is selected, the constraints from the pipeline runs
+// configuration are used.
+// Topology spread constraints cannot be set via the Tekton pod template,
+// so the task run gets labelled and annotated accordingly instead. The
+// Steward admission webhook sets the constraints at pods with this label.
+func applySchedulingProfile(runCtx *runContext, tektonTaskRun *tekton.TaskRun) error {
+	config := runCtx.pipelineRunsConfig
+	constraints := config.JenkinsfileRunnerTopologySpreadConstraints
+
+	spec := runCtx.pipelineRun.GetSpec()
+	if spec.Profiles != nil && spec.Profiles.Scheduling != "" {
+		profile, exists := config.SchedulingProfiles[spec.Profiles.Scheduling]
+		if !exists {
+			return fmt.Errorf("scheduling profile %q does not exist", spec.Profiles.Scheduling)
+		}
+		constraints = profile.TopologySpreadConstraints
+	}
+
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	constraintsJSON, err := json.Marshal(constraints)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal topology spread constraints")
+	}
+
+	labels := tektonTaskRun.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[stewardv1alpha1.LabelTopologySpread] = ""
+	tektonTaskRun.SetLabels(labels)
+
+	annotations := tektonTaskRun.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[stewardv1alpha1.AnnotationTopologySpreadConstraints] = string(constraintsJSON)
+	tektonTaskRun.SetAnnotations(annotations)
+	return nil
+}
+
+// applyTerminationGracePeriod labels the given task run with the
+// termination grace period of the Jenkinsfile Runner pod from the
+// pipeline runs configuration, if any.
+// The termination grace period cannot be set via the Tekton pod template,
+// so the Steward admission webhook sets it at pods with this label.
+func applyTerminationGracePeriod(runCtx *runContext, tektonTaskRun *tekton.TaskRun) {
+	seconds := runCtx.pipelineRunsConfig.JenkinsfileRunnerTerminationGracePeriodSeconds
+	if seconds == nil {
+		return
+	}
+	labels := tektonTaskRun.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[stewardv1alpha1.LabelTerminationGracePeriod] = strconv.FormatInt(*seconds, 10)
+	tektonTaskRun.SetLabels(labels)
+}
+
+// addTektonTaskRunExtendedResources adds the extended resources requested
+// in the pipeline run spec to the Jenkinsfile Runner step.
+// Tekton does not allow to override step resources in a task run, so the
+// spec of the Jenkinsfile Runner ClusterTask gets embedded into the task
+// run instead of referencing it. Task runs without extended resources
+// still reference the ClusterTask.
+func (c *runManager) addTektonTaskRunExtendedResources(
+	ctx context.Context,
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	limits, err := extendedResourceLimits(runCtx)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	taskSpec, err := c.embedTaskSpec(ctx, tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	step, err := jenkinsfileRunnerStep(taskSpec)
+	if err != nil {
+		return err
+	}
+	if step.Resources.Limits == nil {
+		step.Resources.Limits = corev1api.ResourceList{}
+	}
+	for name, quantity := range limits {
+		step.Resources.Limits[name] = quantity
+	}
+	return nil
+}
+
+// addTektonTaskRunComputeResources sets the compute resource requests
+// and limits from `spec.resources` of the pipeline run at the Jenkinsfile
+// Runner step, replacing those of the ClusterTask. Like for extended
+// resources, the spec of the ClusterTask gets embedded into the task run
+// for this. Requests and limits of the ClusterTask not overridden are
+// adjusted, so that no request exceeds its limit.
+func (c *runManager) addTektonTaskRunComputeResources(
+	ctx context.Context,
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	resources, err := computeResources(runCtx)
+	if err != nil {
+		return serrors.Classify(err, stewardv1alpha1.ResultErrorContent)
+	}
+	if resources == nil {
+		return nil
+	}
+
+	taskSpec, err := c.embedTaskSpec(ctx, tektonTaskRun)
+	if err != nil {
+		return err
+	}
+	step, err := jenkinsfileRunnerStep(taskSpec)
+	if err != nil {
+		return err
+	}
+	if step.Resources.Requests == nil {
+		step.Resources.Requests = corev1api.ResourceList{}
+	}
+	if step.Resources.Limits == nil {
+		step.Resources.Limits = corev1api.ResourceList{}
+	}
+	for name, quantity := range resources.Requests {
+		step.Resources.Requests[name] = quantity
+	}
+	for name, quantity := range resources.Limits {
+		step.Resources.Limits[name] = quantity
+	}
+	for name, limit := range step.Resources.Limits {
+		request, found := step.Resources.Requests[name]
+		if !found || request.Cmp(limit) <= 0 {
+			continue
+		}
+		if _, overridden := resources.Requests[name]; overridden {
+			step.Resources.Limits[name] = request
+		} else {
+			step.Resources.Requests[name] = limit
+		}
+	}
+	return nil
+}
+
+// jenkinsfileRunnerStep returns the step running the Jenkinsfile Runner
+// in the given task spec.
+func jenkinsfileRunnerStep(taskSpec *tekton.TaskSpec) (*tekton.Step, error) {
+	for i := range taskSpec.Steps {
+		if taskSpec.Steps[i].Name == tektonClusterTaskJenkinsfileRunnerStep {
+			return &taskSpec.Steps[i], nil
+		}
+	}
+	return nil, serrors.Classify(
+		fmt.Errorf("Tekton ClusterTask %q has no step %q", tektonClusterTaskName, tektonClusterTaskJenkinsfileRunnerStep),
+		stewardv1alpha1.ResultErrorConfig,
+	)
+}
+
+// embedTaskSpec replaces the reference to the Jenkinsfile Runner
+// ClusterTask in the given task run by a copy of the ClusterTask spec and
+// returns the embedded spec. If the task run embeds a task spec already,
+// it is returned as is.
+func (c *runManager) embedTaskSpec(ctx context.Context, tektonTaskRun *tekton.TaskRun) (*tekton.TaskSpec, error) {
+	if tektonTaskRun.Spec.TaskSpec != nil {
+		return tektonTaskRun.Spec.TaskSpec, nil
+	}
+
+	clusterTask, err := c.factory.TektonV1beta1().ClusterTasks().Get(ctx, tektonClusterTaskName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Tekton ClusterTask %q", tektonClusterTaskName)
+	}
+
+	tektonTaskRun.Spec.TaskRef = nil
+	tektonTaskRun.Spec.TaskSpec = clusterTask.Spec.DeepCopy()
+	return tektonTaskRun.Spec.TaskSpec, nil
+}
+
+// extendedResourceLimits returns the extended resource limits requested
+// for the Jenkinsfile Runner container in the pipeline run spec.
+// An error is returned if a requested resource is not an extended
+// resource or exceeds the maximum configured for the Steward installation.
+func extendedResourceLimits(runCtx *runContext) (corev1api.ResourceList, error) {
+	spec := runCtx.pipelineRun.GetSpec()
+	if spec.JenkinsfileRunner == nil || spec.JenkinsfileRunner.Resources == nil {
+		return nil, nil
+	}
+
+	limits := spec.JenkinsfileRunner.Resources.Limits
+	maxLimits := runCtx.pipelineRunsConfig.JenkinsfileRunnerExtendedResourceLimits
+	for name, quantity := range limits {
+		if !cfg.IsExtendedResourceName(name) {
+			return nil, fmt.Errorf("resource %q is not an extended resource", name)
+		}
+		maxQuantity, found := maxLimits[name]
+		if !found {
+			return nil, fmt.Errorf("extended resource %q is not available for pipeline runs", name)
+		}
+		if quantity.Cmp(maxQuantity) > 0 {
+			return nil, fmt.Errorf(
+				"extended resource %q: requested quantity %s exceeds the maximum of %s",
+				name, quantity.String(), maxQuantity.String(),
+			)
+		}
+	}
+	return limits.DeepCopy(), nil
+}
+
+// computeResources returns the compute resource requests and limits of
+// the Jenkinsfile Runner container from `spec.resources` of the pipeline
+// run or nil if none are set.
+// An error is returned if a resource is not a supported compute resource,
+// is outside of the bounds configured for the Steward installation or if
+// a request exceeds its limit.
+func computeResources(runCtx *runContext) (*corev1api.ResourceRequirements, error) {
+	spec := runCtx.pipelineRun.GetSpec()
+	if spec.Resources == nil || (len(spec.Resources.Requests) == 0 && len(spec.Resources.Limits) == 0) {
+		return nil, nil
+	}
+
+	config := runCtx.pipelineRunsConfig
+	for _, field := range []struct {
+		path   string
+		values corev1api.ResourceList
+	}{
+		{"spec.resources.requests", spec.Resources.Requests},
+		{"spec.resources.limits", spec.Resources.Limits},
+	} {
+		for name, quantity := range field.values {
+			if !cfg.IsComputeResourceName(name) {
+				return nil, fmt.Errorf(
+					"%s: resource %q is not supported, only %q, %q and %q can be set",
+					field.path, name, corev1api.ResourceCPU, corev1api.ResourceMemory, corev1api.ResourceEphemeralStorage,
+				)
+			}
+			maxQuantity, found := config.JenkinsfileRunnerMaxResources[name]
+			if !found {
+				return nil, fmt.Errorf("%s: resource %q cannot be set for pipeline runs", field.path, name)
+			}
+			if quantity.Cmp(maxQuantity) > 0 {
+				return nil, fmt.Errorf(
+					"%s: resource %q: quantity %s exceeds the maximum of %s",
+					field.path, name, quantity.String(), maxQuantity.String(),
+				)
+			}
+			if minQuantity, found := config.JenkinsfileRunnerMinResources[name]; found && quantity.Cmp(minQuantity) < 0 {
+				return nil, fmt.Errorf(
+					"%s: resource %q: quantity %s is below the minimum of %s",
+					field.path, name, quantity.String(), minQuantity.String(),
+				)
+			}
+		}
+	}
+	for name, request := range spec.Resources.Requests {
+		if limit, found := spec.Resources.Limits[name]; found && request.Cmp(limit) > 0 {
+			return nil, fmt.Errorf(
+				"spec.resources: resource %q: request %s exceeds the limit of %s",
+				name, request.String(), limit.String(),
+			)
+		}
+	}
+	return spec.Resources.DeepCopy(), nil
 }
 
 func (c *runManager) addTektonTaskRunParamsForJenkinsfileRunnerImage(
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
 ) {
+	image, imagePullPolicy := jenkinsfileRunnerImage(runCtx)
+	params := []tekton.Param{
+		tektonStringParam("JFR_IMAGE", image),
+		tektonStringParam("JFR_IMAGE_PULL_POLICY", imagePullPolicy),
+	}
+	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
+}
+
+// jenkinsfileRunnerImage returns the Jenkinsfile Runner image and its pull
+// policy to be used for the pipeline run. An image defined in the pipeline
+// run spec takes precedence over the one from the pipeline runs
+// configuration. In an air-gapped installation the image is pulled from
+// the configured mirror registry.
+func jenkinsfileRunnerImage(runCtx *runContext) (image, imagePullPolicy string) {
 	spec := runCtx.pipelineRun.GetSpec()
 	jfrSpec := spec.JenkinsfileRunner
-	image := runCtx.pipelineRunsConfig.JenkinsfileRunnerImage
-	imagePullPolicy := runCtx.pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy
+	image = runCtx.pipelineRunsConfig.JenkinsfileRunnerImage
+	imagePullPolicy = runCtx.pipelineRunsConfig.JenkinsfileRunnerImagePullPolicy
 
 	if jfrSpec != nil {
 		if jfrSpec.Image != "" {
@@ -551,11 +1440,10 @@ func (c *runManager) addTektonTaskRunParamsForJenkinsfileRunnerImage(
 			}
 		}
 	}
-	params := []tekton.Param{
-		tektonStringParam("JFR_IMAGE", image),
-		tektonStringParam("JFR_IMAGE_PULL_POLICY", imagePullPolicy),
+	if runCtx.pipelineRunsConfig.AirGapped && image != "" {
+		image = imagepolicy.Mirror(image, runCtx.pipelineRunsConfig.AirGappedMirrorRegistry)
 	}
-	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params, params...)
+	return image, imagePullPolicy
 }
 
 func (c *runManager) addTektonTaskRunParamsForRunDetails(
@@ -582,12 +1470,12 @@ func (c *runManager) addTektonTaskRunParamsForRunDetails(
 func (c *runManager) addTektonTaskRunParamsForPipeline(
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
+	pipelineArgs map[string]string,
 ) error {
 	var err error
 
 	spec := runCtx.pipelineRun.GetSpec()
 	pipeline := spec.JenkinsFile
-	pipelineArgs := spec.Args
 	pipelineArgsJSON := "{}"
 	if pipelineArgs != nil {
 		if pipelineArgsJSON, err = toJSONString(&pipelineArgs); err != nil {
@@ -595,9 +1483,14 @@ func (c *runManager) addTektonTaskRunParamsForPipeline(
 		}
 	}
 
+	pipelineRevision := pipeline.Revision
+	if runCtx.resolvedRevision != "" {
+		pipelineRevision = runCtx.resolvedRevision
+	}
+
 	params := []tekton.Param{
 		tektonStringParam("PIPELINE_GIT_URL", pipeline.URL),
-		tektonStringParam("PIPELINE_GIT_REVISION", pipeline.Revision),
+		tektonStringParam("PIPELINE_GIT_REVISION", pipelineRevision),
 		tektonStringParam("PIPELINE_FILE", pipeline.Path),
 		tektonStringParam("PIPELINE_PARAMS_JSON", pipelineArgsJSON),
 	}
@@ -606,6 +1499,68 @@ func (c *runManager) addTektonTaskRunParamsForPipeline(
 	return nil
 }
 
+// addTektonTaskRunParamsForSources passes the additional source
+// repositories to the `clone-sources` step of the ClusterTask, one
+// repository per line in the format `<targetPath> <revision> <repoUrl>`.
+// Credentials are not passed, as the auth secrets are attached to the
+// service account of the run and provided to the step by Tekton.
+func (c *runManager) addTektonTaskRunParamsForSources(
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	spec := runCtx.pipelineRun.GetSpec()
+	if len(spec.Sources) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for i, source := range spec.Sources {
+		if err := ensureValidSourceTargetPath(source.TargetPath); err != nil {
+			return errors.Wrapf(err,
+				"field \"spec.sources[%d].targetPath\" has invalid value %q",
+				i, source.TargetPath,
+			)
+		}
+		for _, field := range []struct{ name, value string }{
+			{"repoUrl", source.URL},
+			{"revision", source.Revision},
+		} {
+			if strings.ContainsAny(field.value, " \t\r\n") {
+				return errors.Errorf(
+					"field \"spec.sources[%d].%s\" has invalid value %q: must not contain whitespace",
+					i, field.name, field.value,
+				)
+			}
+		}
+		lines = append(lines, strings.Join([]string{source.TargetPath, source.Revision, source.URL}, " "))
+	}
+
+	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params,
+		tektonStringParam("PIPELINE_SOURCES", strings.Join(lines, "\n")),
+	)
+	return nil
+}
+
+func (c *runManager) addTektonTaskRunParamsForTestResults(
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) error {
+	testResults := runCtx.pipelineRun.GetSpec().TestResults
+	if testResults == nil {
+		return nil
+	}
+	if err := ensureValidTestResultsPath(testResults.Path); err != nil {
+		return errors.Wrapf(err,
+			"field \"spec.testResults.path\" has invalid value %q",
+			testResults.Path,
+		)
+	}
+	tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params,
+		tektonStringParam("TEST_RESULTS_PATH", testResults.Path),
+	)
+	return nil
+}
+
 func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
@@ -613,7 +1568,11 @@ func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
 	spec := runCtx.pipelineRun.GetSpec()
 	var params []tekton.Param
 
-	if spec.Logging == nil || spec.Logging.Elasticsearch == nil {
+	sink, err := spec.Logging.EffectiveSink()
+	if err != nil {
+		return err
+	}
+	if sink == nil {
 		params = []tekton.Param{
 			// overide the index URL hardcoded in the template by
 			// the empty string to effective disable logging to
@@ -621,17 +1580,24 @@ func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
 			tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_INDEX_URL", ""),
 		}
 	} else {
-		runIDJSON, err := toJSONString(&spec.Logging.Elasticsearch.RunID)
+		// converted deprecated configurations are not validated
+		// to retain compatibility
+		if spec.Logging.Sink != nil {
+			if err := sink.Validate(); err != nil {
+				return err
+			}
+		}
+		runIDJSON, err := toJSONString(spec.Logging.RunIDJSON())
 		if err != nil {
 			return errors.WithMessage(err,
-				"could not serialize spec.logging.elasticsearch.runid to JSON",
+				"could not serialize run ID of log sink to JSON",
 			)
 		}
 
 		params = append(params, tektonStringParam("PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON", runIDJSON))
 		// use default values from build template for all other params
 
-		if spec.Logging.Elasticsearch.IndexURL != "" {
+		if spec.Logging.Elasticsearch != nil && spec.Logging.Elasticsearch.IndexURL != "" {
 
 			_, err := ensureValidElasticsearchIndexURL(spec.Logging.Elasticsearch.IndexURL)
 			if err != nil {
@@ -651,15 +1617,9 @@ func (c *runManager) addTektonTaskRunParamsForLoggingElasticsearch(
 // GetRun based on a pipelineRun
 func (c *runManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (runifc.Run, error) {
 	namespace := pipelineRun.GetRunNamespace()
-	run, err := c.factory.TektonV1beta1().TaskRuns(namespace).Get(ctx, tektonTaskRunName, metav1.GetOptions{})
+	run, err := c.factory.TektonV1beta1().TaskRuns(namespace).Get(ctx, naming.JenkinsfileRunnerTaskRun, metav1.GetOptions{})
 	if err != nil {
-		return nil, serrors.RecoverableIf(err,
-			k8serrors.IsServerTimeout(err) ||
-				k8serrors.IsServiceUnavailable(err) ||
-				k8serrors.IsTimeout(err) ||
-				k8serrors.IsTooManyRequests(err) ||
-				k8serrors.IsInternalError(err) ||
-				k8serrors.IsUnexpectedServerError(err))
+		return nil, serrors.RecoverableIf(err, retry.IsTransientServerError(err))
 	}
 	return NewRun(run), nil
 }
@@ -671,7 +1631,11 @@ func (c *runManager) Cleanup(ctx context.Context, pipelineRun k8s.PipelineRun) e
 		runNamespace: pipelineRun.GetRunNamespace(),
 		auxNamespace: pipelineRun.GetAuxNamespace(),
 	}
-	return c.cleanupNamespaces(ctx, runCtx)
+	err := c.cleanupNamespaces(ctx, runCtx)
+	if revokeErr := c.revokeRunAPIToken(ctx, runCtx); err == nil {
+		err = revokeErr
+	}
+	return err
 }
 
 func (c *runManager) cleanupNamespaces(ctx context.Context, runCtx *runContext) error {
@@ -718,29 +1682,24 @@ func (c *runManager) createNamespace(ctx context.Context, runCtx *runContext, pu
 
 	wanted := &corev1api.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: fmt.Sprintf("%s-%s-%s-", runNamespacePrefix, randName, purpose),
+			GenerateName: naming.RunNamespaceGenerateName(randName, purpose),
 		},
 	}
 
 	slabels.LabelAsSystemManaged(wanted)
+	slabels.LabelWithInstanceID(wanted, c.factory.InstanceID())
 	err = slabels.LabelAsOwnedByPipelineRun(wanted, runCtx.pipelineRun.GetAPIObject())
 	if err != nil {
 		return "", errors.Wrap(err, "failed to label namespace as owned by pipeline run")
 	}
-
-	isRetriable := func(err error) bool {
-		return k8serrors.IsConflict(err) ||
-			k8serrors.IsInternalError(err) ||
-			k8serrors.IsServerTimeout(err) ||
-			k8serrors.IsServiceUnavailable(err) ||
-			k8serrors.IsTimeout(err) ||
-			k8serrors.IsTooManyRequests(err) ||
-			k8serrors.IsUnexpectedServerError(err)
+	err = c.factory.NamespaceMetadata().Apply(wanted)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to set configured namespace metadata")
 	}
 
 	var created *corev1api.Namespace
 
-	err = retry.OnError(retry.DefaultBackoff, isRetriable,
+	err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientAPIError,
 		func() error {
 			var err error
 			created, err = c.factory.CoreV1().Namespaces().Create(ctx, wanted, metav1.CreateOptions{})
@@ -748,12 +1707,52 @@ func (c *runManager) createNamespace(ctx context.Context, runCtx *runContext, pu
 		},
 	)
 	if err != nil {
-		return "", err
+		return "", serrors.MarkIfQuotaExceeded(err)
+	}
+
+	if len(c.factory.WatchNamespaces()) > 0 {
+		// return the name to let the caller clean up the namespace
+		return created.GetName(), c.grantRunNamespaceAccess(ctx, created.GetName())
 	}
 
 	return created.GetName(), err
 }
 
+// grantRunNamespaceAccess binds the cluster role with the permissions the
+// run controller requires within run namespaces to the service account of
+// the run controller. This is necessary in namespace-scoped mode, where
+// these permissions are not granted cluster-wide.
+func (c *runManager) grantRunNamespaceAccess(ctx context.Context, namespace string) error {
+	roleBinding := &rbacv1api.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.RunControllerRoleBinding,
+			Namespace: namespace,
+		},
+		RoleRef: rbacv1api.RoleRef{
+			APIGroup: rbacv1api.GroupName,
+			Kind:     "ClusterRole",
+			Name:     string(runControllerRunNamespaceClusterRoleName),
+		},
+		Subjects: []rbacv1api.Subject{
+			{
+				Kind:      rbacv1api.ServiceAccountKind,
+				Name:      runControllerServiceAccountName,
+				Namespace: system.Namespace(),
+			},
+		},
+	}
+	slabels.LabelAsSystemManaged(roleBinding)
+	slabels.LabelWithInstanceID(roleBinding, c.factory.InstanceID())
+
+	err := retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientAPIError,
+		func() error {
+			_, err := c.factory.RbacV1().RoleBindings(namespace).Create(ctx, roleBinding, metav1.CreateOptions{})
+			return err
+		},
+	)
+	return errors.Wrapf(err, "failed to grant run controller access to namespace %q", namespace)
+}
+
 func (c *runManager) deleteNamespace(ctx context.Context, name string, options metav1.DeleteOptions) error {
 	isIgnorable := func(err error) bool {
 		return k8serrors.IsNotFound(err) ||
@@ -761,17 +1760,7 @@ func (c *runManager) deleteNamespace(ctx context.Context, name string, options m
 			k8serrors.IsResourceExpired(err)
 	}
 
-	isRetriable := func(err error) bool {
-		return k8serrors.IsConflict(err) ||
-			k8serrors.IsInternalError(err) ||
-			k8serrors.IsServerTimeout(err) ||
-			k8serrors.IsServiceUnavailable(err) ||
-			k8serrors.IsTimeout(err) ||
-			k8serrors.IsTooManyRequests(err) ||
-			k8serrors.IsUnexpectedServerError(err)
-	}
-
-	return retry.OnError(retry.DefaultBackoff, isRetriable,
+	return retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientAPIError,
 		func() error {
 			err := c.factory.CoreV1().Namespaces().Delete(ctx, name, options)
 			if isIgnorable(err) {
@@ -782,6 +1771,19 @@ func (c *runManager) deleteNamespace(ctx context.Context, name string, options m
 	)
 }
 
+// effectiveTimeout returns the timeout of a pipeline run given the
+// timeout from its spec and the timeout configured for the Steward
+// installation. The spec timeout can only shorten the configured one.
+func effectiveTimeout(specTimeout, configTimeout *metav1.Duration) *metav1.Duration {
+	if specTimeout == nil || specTimeout.Duration <= 0 {
+		return configTimeout
+	}
+	if configTimeout != nil && configTimeout.Duration < specTimeout.Duration {
+		return configTimeout
+	}
+	return specTimeout
+}
+
 func toJSONString(value interface{}) (string, error) {
 	bytes, err := json.Marshal(value)
 	if err != nil {
@@ -811,3 +1813,50 @@ func ensureValidElasticsearchIndexURL(indexURL string) (string, error) {
 
 	return validURL.String(), nil
 }
+
+// hasNonEmpty returns whether the given slice contains a non-empty string.
+func hasNonEmpty(values []string) bool {
+	for _, v := range values {
+		if v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureValidSourceTargetPath checks that the given target path of a
+// source repository is a relative path without whitespace that does not
+// point outside the pipeline workspace.
+func ensureValidSourceTargetPath(targetPath string) error {
+	if targetPath == "" {
+		return errors.New("must not be empty")
+	}
+	if path.IsAbs(targetPath) {
+		return errors.New("must be a relative path")
+	}
+	if strings.ContainsAny(targetPath, " \t\r\n") {
+		return errors.New("must not contain whitespace")
+	}
+	cleaned := path.Clean(targetPath)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return errors.New("must point to a subdirectory of the workspace")
+	}
+	return nil
+}
+
+// ensureValidTestResultsPath checks that the given file pattern of test
+// result files is a relative path that does not point outside the
+// pipeline workspace.
+func ensureValidTestResultsPath(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return errors.New("must not be empty")
+	}
+	if path.IsAbs(pattern) {
+		return errors.New("must be a relative path")
+	}
+	cleaned := path.Clean(pattern)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return errors.New("must point into the workspace")
+	}
+	return nil
+}
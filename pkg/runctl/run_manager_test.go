@@ -2,10 +2,12 @@ package runctl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,9 +19,14 @@ import (
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
 	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	k8smocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	namespacemetadata "github.com/SAP/stewardci-core/pkg/k8s/namespacemetadata"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	secretmocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
 	secretproviderfakes "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
 	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/imagepolicy"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"github.com/SAP/stewardci-core/pkg/runctl/revision"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
 	tektonfakeclient "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/fake"
@@ -32,17 +39,21 @@ import (
 	is "gotest.tools/assert/cmp"
 	corev1 "k8s.io/api/core/v1"
 	equality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/system"
 )
 
 func newRunManagerTestingWithAllNoopStubs() *runManagerTesting {
 	return &runManagerTesting{
 		cleanupStub:                               func(context.Context, *runContext) error { return nil },
 		copySecretsToRunNamespaceStub:             func(context.Context, *runContext) (string, []string, error) { return "", []string{}, nil },
+		copySourceSecretsToRunNamespaceStub:       func(context.Context, *runContext) ([]string, error) { return nil, nil },
 		getServiceAccountSecretNameStub:           func(context.Context, *runContext) (string, error) { return "", nil },
 		setupLimitRangeFromConfigStub:             func(context.Context, *runContext) error { return nil },
 		setupNetworkPolicyFromConfigStub:          func(context.Context, *runContext) error { return nil },
@@ -125,6 +136,142 @@ func Test__runManager_prepareRunNamespace__CreatesNamespaces(t *testing.T) {
 	}
 }
 
+func Test__runManager_createNamespace__SetsInstanceIDLabel(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+	cf.SetInstanceID("instance1")
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", k8sfake.GenerateNameReactor(7))
+
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	// EXERCISE
+	name, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	namespace, err := cf.CoreV1().Namespaces().Get(h.ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "instance1", namespace.GetLabels()[stewardv1alpha1.LabelInstanceID])
+}
+
+func Test__runManager_createNamespace__SetsNamespaceMetadata(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+	cf.SetNamespaceMetadata(&namespacemetadata.Metadata{
+		Labels:         map[string]string{"example.com/cost-center": "cc1"},
+		Annotations:    map[string]string{"example.com/classification": "internal"},
+		RequiredLabels: []string{"example.com/cost-center"},
+	})
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", k8sfake.GenerateNameReactor(7))
+
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	// EXERCISE
+	name, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	namespace, err := cf.CoreV1().Namespaces().Get(h.ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "cc1", namespace.GetLabels()["example.com/cost-center"])
+	assert.Equal(t, "", namespace.GetLabels()[stewardv1alpha1.LabelSystemManaged])
+	assert.Equal(t, "internal", namespace.GetAnnotations()["example.com/classification"])
+}
+
+func Test__runManager_createNamespace__MarksQuotaExceededError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+	quotaErr := k8serrors.NewForbidden(corev1.Resource("namespaces"), "", fmt.Errorf("exceeded quota: quota1"))
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", k8sfake.NewErrorReactor(quotaErr))
+
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	// EXERCISE
+	name, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "foo")
+
+	// VERIFY
+	assert.Assert(t, errors.Is(resultErr, serrors.ErrQuotaExceeded))
+	assert.Assert(t, k8serrors.IsForbidden(resultErr))
+	assert.Equal(t, "", name)
+}
+
+func Test__runManager_createNamespace__NamespaceScopedMode__GrantsRunControllerAccess(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+	)
+	cf.SetWatchNamespaces(h.namespace1)
+	cf.KubernetesClientset().PrependReactor("create", "namespaces", k8sfake.GenerateNameReactor(7))
+
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{},
+	}
+
+	// EXERCISE
+	name, resultErr := examinee.createNamespace(h.ctx, runCtx, "main", "foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	roleBinding, err := cf.RbacV1().RoleBindings(name).Get(h.ctx, naming.RunControllerRoleBinding, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, string(runControllerRunNamespaceClusterRoleName), roleBinding.RoleRef.Name)
+	assert.Equal(t, 1, len(roleBinding.Subjects))
+	assert.Equal(t, runControllerServiceAccountName, roleBinding.Subjects[0].Name)
+	assert.Equal(t, system.Namespace(), roleBinding.Subjects[0].Namespace)
+}
+
 func Test__runManager_prepareRunNamespace__Calls__copySecretsToRunNamespace__AndPropagatesError(t *testing.T) {
 	t.Parallel()
 
@@ -1203,6 +1350,7 @@ func Test__runManager_createTektonTaskRun__PodTemplate_IsNotEmptyIfNoValuesToSet
 func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T) {
 	t.Parallel()
 
+	boolPtr := func(val bool) *bool { return &val }
 	int32Ptr := func(val int32) *int32 { return &val }
 	int64Ptr := func(val int64) *int64 { return &val }
 
@@ -1224,6 +1372,10 @@ func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T
 			JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1111),
 			JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 			JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(3333),
+			JenkinsfileRunnerPodSecurityContextSeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+			JenkinsfileRunnerRuntimeClassName: "gvisor",
 		},
 	}
 	cf := k8sfake.NewClientFactory()
@@ -1249,7 +1401,11 @@ func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T
 			FSGroup:    int64Ptr(1111),
 			RunAsGroup: int64Ptr(2222),
 			RunAsUser:  int64Ptr(3333),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
 		},
+		RuntimeClassName: stringPtr("gvisor"),
 		Volumes: []corev1.Volume{
 			{
 				Name: "service-account-token",
@@ -1260,6 +1416,28 @@ func Test__runManager_createTektonTaskRun__PodTemplate_AllValuesSet(t *testing.T
 					},
 				},
 			},
+			{
+				Name: "pipeline-params",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "steward-pipeline-params",
+						},
+						DefaultMode: int32Ptr(0644),
+						Optional:    boolPtr(true),
+					},
+				},
+			},
+			{
+				Name: "run-api-token",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName:  "steward-run-api-token",
+						DefaultMode: int32Ptr(0644),
+						Optional:    boolPtr(true),
+					},
+				},
+			},
 		},
 	}
 	podTemplate := taskRun.Spec.PodTemplate
@@ -1274,6 +1452,115 @@ var metav1Duration = func(d time.Duration) *metav1.Duration {
 	return &metav1.Duration{Duration: d}
 }
 
+func Test_effectiveTimeout(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		specTimeout   *metav1.Duration
+		configTimeout *metav1.Duration
+		expected      *metav1.Duration
+	}{
+		{"no timeouts", nil, nil, nil},
+		{"spec timeout only", metav1Duration(time.Minute), nil, metav1Duration(time.Minute)},
+		{"config timeout only", nil, metav1Duration(time.Hour), metav1Duration(time.Hour)},
+		{"spec timeout shorter", metav1Duration(time.Minute), metav1Duration(time.Hour), metav1Duration(time.Minute)},
+		{"spec timeout longer", metav1Duration(2 * time.Hour), metav1Duration(time.Hour), metav1Duration(time.Hour)},
+		{"spec timeout zero", metav1Duration(0), metav1Duration(time.Hour), metav1Duration(time.Hour)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := effectiveTimeout(tc.specTimeout, tc.configTimeout)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test__runManager_offloadLargePipelineArgs(t *testing.T) {
+	t.Parallel()
+
+	int64Ptr := func(val int64) *int64 { return &val }
+
+	args := map[string]string{
+		"small": "1234",
+		"large": "1234567890",
+	}
+
+	for _, tc := range []struct {
+		name                   string
+		threshold              *int64
+		expectedInlineArgs     map[string]string
+		expectedOffloadedJSON  string
+		expectConfigMapCreated bool
+	}{
+		{
+			name:               "threshold not set",
+			threshold:          nil,
+			expectedInlineArgs: args,
+		},
+		{
+			name:               "threshold zero",
+			threshold:          int64Ptr(0),
+			expectedInlineArgs: args,
+		},
+		{
+			name:               "no arg exceeds threshold",
+			threshold:          int64Ptr(15),
+			expectedInlineArgs: args,
+		},
+		{
+			name:                   "arg exceeds threshold",
+			threshold:              int64Ptr(14),
+			expectedInlineArgs:     map[string]string{"small": "1234"},
+			expectedOffloadedJSON:  `{"large":"1234567890"}`,
+			expectConfigMapCreated: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			cf := newFakeClientFactory(
+				k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{
+					Args: args,
+				}),
+			)
+			pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+			assert.NilError(t, err)
+			runCtx := &runContext{
+				pipelineRun:  pipelineRunHelper,
+				runNamespace: h.namespace1,
+				pipelineRunsConfig: &cfg.PipelineRunsConfigStruct{
+					PipelineArgsOffloadThresholdBytes: tc.threshold,
+				},
+			}
+			examinee := newRunManager(cf, nil)
+
+			// EXERCISE
+			inlineArgs, resultErr := examinee.offloadLargePipelineArgs(h.ctx, runCtx)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.DeepEqual(t, tc.expectedInlineArgs, inlineArgs)
+
+			configMap, err := cf.CoreV1().ConfigMaps(h.namespace1).Get(h.ctx, naming.PipelineParamsConfigMap, metav1.GetOptions{})
+			if tc.expectConfigMapCreated {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedOffloadedJSON, configMap.Data[pipelineParamsConfigMapKey])
+			} else {
+				assert.Assert(t, k8serrors.IsNotFound(err))
+			}
+		})
+	}
+}
+
 func Test__runManager_Start__CreatesTektonTaskRun(t *testing.T) {
 	t.Parallel()
 
@@ -1294,7 +1581,7 @@ func Test__runManager_Start__CreatesTektonTaskRun(t *testing.T) {
 
 	// VERIFY
 	result, err := mockFactory.TektonV1beta1().TaskRuns(runNamespace).Get(
-		h.ctx, tektonTaskRunName, metav1.GetOptions{})
+		h.ctx, naming.JenkinsfileRunnerTaskRun, metav1.GetOptions{})
 	assert.NilError(t, err)
 	assert.Assert(t, result != nil)
 }
@@ -1489,568 +1776,2339 @@ func Test__runManager_addTektonTaskRunParamsForJenkinsfileRunnerImage(t *testing
 	}
 }
 
-func Test__runManager_Start__DoesNotSetPipelineRunStatus(t *testing.T) {
+func Test__runManager_addTektonTaskRunParamsForSources(t *testing.T) {
 	t.Parallel()
 
-	// SETUP
-	h := newTestHelper1(t)
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
-	h.preparePredefinedClusterRole(mockFactory, mockPipelineRun)
-	config := &cfg.PipelineRunsConfigStruct{}
+	examinee := runManager{}
+	for _, tc := range []struct {
+		name                string
+		sources             []stewardv1alpha1.SourceRepository
+		expectedAddedParams []tektonv1beta1.Param
+	}{
+		{
+			name:                "no_sources",
+			expectedAddedParams: []tektonv1beta1.Param{},
+		},
+		{
+			name: "single",
+			sources: []stewardv1alpha1.SourceRepository{
+				{URL: "https://github.com/org/config", Revision: "main", TargetPath: "config"},
+			},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_SOURCES", "config main https://github.com/org/config"),
+			},
+		},
+		{
+			name: "multiple",
+			sources: []stewardv1alpha1.SourceRepository{
+				{URL: "https://github.com/org/config", Revision: "main", TargetPath: "config", RepoAuthSecret: "secret1"},
+				{URL: "https://github.com/org/code", Revision: "v1", TargetPath: "src/code"},
+			},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("PIPELINE_SOURCES",
+					"config main https://github.com/org/config\n"+
+						"src/code v1 https://github.com/org/code"),
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	examinee := newRunManager(mockFactory, mockSecretProvider)
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{Sources: tc.sources})
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					Params: []tektonv1beta1.Param{},
+				},
+			}
 
-	// EXERCISE
-	_, _, resultError := examinee.Start(h.ctx, mockPipelineRun, config)
-	assert.NilError(t, resultError)
+			// EXERCISE
+			err := examinee.addTektonTaskRunParamsForSources(runCtx, &tektonTaskRun)
 
-	// VERIFY
-	// UpdateState should never be called
-	mockPipelineRun.EXPECT().UpdateState(gomock.Any(), gomock.Any()).Times(0)
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedAddedParams, tektonTaskRun.Spec.Params)
+		})
+	}
 }
 
-func Test__runManager_copySecretsToRunNamespace__DoesCopySecret(t *testing.T) {
+func Test__runManager_addTektonTaskRunParamsForSources__WhitespaceInURLOrRevision(t *testing.T) {
 	t.Parallel()
 
-	// SETUP
-	ctx := context.Background()
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+	for _, tc := range []struct {
+		name          string
+		source        stewardv1alpha1.SourceRepository
+		expectedError string
+	}{
+		{
+			name:          "url",
+			source:        stewardv1alpha1.SourceRepository{URL: "https://github.com/org/config x", Revision: "main", TargetPath: "config"},
+			expectedError: `field "spec.sources[0].repoUrl" has invalid value "https://github.com/org/config x": must not contain whitespace`,
+		},
+		{
+			name:          "revision",
+			source:        stewardv1alpha1.SourceRepository{URL: "https://github.com/org/config", Revision: "main\nfoo", TargetPath: "config"},
+			expectedError: `field "spec.sources[0].revision" has invalid value "main\nfoo": must not contain whitespace`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	examinee := &runManager{}
+			// SETUP
+			examinee := runManager{}
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Sources: []stewardv1alpha1.SourceRepository{tc.source},
+			})
+			tektonTaskRun := tektonv1beta1.TaskRun{}
 
-	mockSecretManager := runmocks.NewMockSecretManager(mockCtrl)
-	// inject secret manager
+			// EXERCISE
+			err := examinee.addTektonTaskRunParamsForSources(runCtx, &tektonTaskRun)
 
-	examinee.testing = newRunManagerTestingWithRequiredStubs()
-	examinee.testing.getSecretManagerStub = func(*runContext) runifc.SecretManager {
-		return mockSecretManager
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Assert(t, len(tektonTaskRun.Spec.Params) == 0)
+		})
 	}
+}
 
-	run := k8smocks.NewMockPipelineRun(mockCtrl)
-	runCtx := &runContext{
-		pipelineRun: run,
-	}
+func Test__runManager_addTektonTaskRunParamsForSources__InvalidTargetPath(t *testing.T) {
+	t.Parallel()
 
-	// EXPECT
-	mockSecretManager.EXPECT().CopyAll(gomock.Not(gomock.Nil()), run).
-		Return("cloneSecret1", []string{"foo", "bar"}, nil).
-		Times(1)
+	for _, targetPath := range []string{"", ".", "/abs", "..", "../foo", "foo/../../bar", "foo bar"} {
+		targetPath := targetPath
+		t.Run(targetPath, func(t *testing.T) {
+			t.Parallel()
 
-	// EXERCISE
-	cloneSecret, imagePullSecrets, resultError := examinee.copySecretsToRunNamespace(ctx, runCtx)
+			// SETUP
+			examinee := runManager{}
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Sources: []stewardv1alpha1.SourceRepository{
+					{URL: "https://github.com/org/config", Revision: "main", TargetPath: targetPath},
+				},
+			})
+			tektonTaskRun := tektonv1beta1.TaskRun{}
 
-	// VERFIY
-	assert.NilError(t, resultError)
-	assert.Equal(t, "cloneSecret1", cloneSecret)
-	assert.DeepEqual(t, []string{"foo", "bar"}, imagePullSecrets)
-}
-
-func Test__runManager_Cleanup__RemovesNamespaces(t *testing.T) {
-	for _, ffEnabled := range []bool{true, false} {
-		t.Run(fmt.Sprintf("featureflag_CreateAuxNamespaceIfUnused_%t", ffEnabled), func(t *testing.T) {
-			defer featureflagtesting.WithFeatureFlag(featureflag.CreateAuxNamespaceIfUnused, ffEnabled)()
-
-			// SETUP
-			h := newTestHelper1(t)
-
-			cf := newFakeClientFactory(
-				k8sfake.Namespace(h.namespace1),
-				k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
-			)
+			// EXERCISE
+			err := examinee.addTektonTaskRunParamsForSources(runCtx, &tektonTaskRun)
 
-			config := &cfg.PipelineRunsConfigStruct{}
-			secretProvider := secretproviderfakes.NewProvider(h.namespace1)
+			// VERIFY
+			assert.ErrorContains(t, err, `field "spec.sources[0].targetPath" has invalid value`)
+			assert.Assert(t, len(tektonTaskRun.Spec.Params) == 0)
+		})
+	}
+}
 
-			examinee := newRunManager(cf, secretProvider)
-			examinee.testing = newRunManagerTestingWithAllNoopStubs()
-			examinee.testing.cleanupStub = nil
+func Test__runManager_addTektonTaskRunParamsForTestResults(t *testing.T) {
+	t.Parallel()
 
-			pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
-			assert.NilError(t, err)
+	examinee := runManager{}
+	for _, tc := range []struct {
+		name                string
+		testResults         *stewardv1alpha1.TestResultsSpec
+		expectedAddedParams []tektonv1beta1.Param
+		expectedError       string
+	}{
+		{
+			name:                "not_configured",
+			expectedAddedParams: []tektonv1beta1.Param{},
+		},
+		{
+			name:        "valid_path",
+			testResults: &stewardv1alpha1.TestResultsSpec{Path: "**/target/surefire-reports/*.xml"},
+			expectedAddedParams: []tektonv1beta1.Param{
+				tektonStringParam("TEST_RESULTS_PATH", "**/target/surefire-reports/*.xml"),
+			},
+		},
+		{
+			name:          "empty_path",
+			testResults:   &stewardv1alpha1.TestResultsSpec{Path: " "},
+			expectedError: `field "spec.testResults.path" has invalid value " ": must not be empty`,
+		},
+		{
+			name:          "absolute_path",
+			testResults:   &stewardv1alpha1.TestResultsSpec{Path: "/reports/*.xml"},
+			expectedError: `field "spec.testResults.path" has invalid value "/reports/*.xml": must be a relative path`,
+		},
+		{
+			name:          "path_outside_workspace",
+			testResults:   &stewardv1alpha1.TestResultsSpec{Path: "foo/../../*.xml"},
+			expectedError: `field "spec.testResults.path" has invalid value "foo/../../*.xml": must point into the workspace`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-			runCtx := &runContext{
-				pipelineRun:        pipelineRunHelper,
-				pipelineRunsConfig: config,
-			}
-			err = examinee.prepareRunNamespace(h.ctx, runCtx)
-			assert.NilError(t, err)
-			runCtx.pipelineRun.UpdateRunNamespace(runCtx.runNamespace)
-			runCtx.pipelineRun.UpdateAuxNamespace(runCtx.auxNamespace)
-			_, err = runCtx.pipelineRun.CommitStatus(h.ctx)
-			assert.NilError(t, err)
-			{
-				pipelineRun1 := h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1)
-				expectedNamespaces := []string{h.namespace1}
-				assert.Assert(t, pipelineRun1.Status.Namespace != "")
-				expectedNamespaces = append(expectedNamespaces, pipelineRun1.Status.Namespace)
-				if ffEnabled {
-					assert.Assert(t, pipelineRun1.Status.AuxiliaryNamespace != "")
-					expectedNamespaces = append(expectedNamespaces, pipelineRun1.Status.AuxiliaryNamespace)
-				}
-				h.assertThatExactlyTheseNamespacesExist(cf, expectedNamespaces...)
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{TestResults: tc.testResults})
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					Params: []tektonv1beta1.Param{},
+				},
 			}
 
 			// EXERCISE
-			resultErr := examinee.Cleanup(h.ctx, pipelineRunHelper)
+			err := examinee.addTektonTaskRunParamsForTestResults(runCtx, &tektonTaskRun)
 
 			// VERIFY
-			assert.NilError(t, resultErr)
-
-			// namespaces
-			{
-				pipelineRun1 := h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1)
-				assert.Assert(t, pipelineRun1.Status.Namespace != "")
-				if ffEnabled {
-					assert.Assert(t, pipelineRun1.Status.AuxiliaryNamespace != "")
-				}
-				h.assertThatExactlyTheseNamespacesExist(cf, h.namespace1)
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Assert(t, len(tektonTaskRun.Spec.Params) == 0)
+			} else {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, tc.expectedAddedParams, tektonTaskRun.Spec.Params)
 			}
 		})
 	}
 }
 
-func Test__runManager__Log_Elasticsearch(t *testing.T) {
+func Test__runManager_prepareRunNamespace__Calls_copySourceSecretsToRunNamespace(t *testing.T) {
 	t.Parallel()
 
-	const (
-		TaskRunParamNameIndexURL  = "PIPELINE_LOG_ELASTICSEARCH_INDEX_URL"
-		TaskRunParamNameRunIDJSON = "PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON"
+	// SETUP
+	h := newTestHelper1(t)
+
+	cf := newFakeClientFactory(
+		k8sfake.Namespace(h.namespace1),
+		k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
 	)
 
-	findTaskRunParam := func(taskRun *tektonv1beta1.TaskRun, paramName string) (param *tektonv1beta1.Param) {
-		assert.Assert(t, taskRun.Spec.Params != nil)
-		for _, p := range taskRun.Spec.Params {
-			if p.Name == paramName {
-				if param != nil {
-					t.Fatalf("input param specified twice: %s", paramName)
-				}
-				param = &p
-			}
-		}
-		return
-	}
+	config := &cfg.PipelineRunsConfigStruct{}
+	secretProvider := secretproviderfakes.NewProvider(h.namespace1)
+	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+	assert.NilError(t, err)
 
-	setupExaminee := func(
-		t *testing.T, pipelineRunJSON string,
-	) (
-		examinee *runManager, runCtx *runContext, cf *k8sfake.ClientFactory,
-	) {
-		pipelineRun := StewardObjectFromJSON(t, pipelineRunJSON).(*stewardv1alpha1.PipelineRun)
-		t.Log("decoded:\n", spew.Sdump(pipelineRun))
+	examinee := newRunManager(cf, secretProvider)
+	examinee.testing = newRunManagerTestingWithAllNoopStubs()
+	examinee.testing.copySourceSecretsToRunNamespaceStub = func(_ context.Context, runCtx *runContext) ([]string, error) {
+		assert.Assert(t, runCtx.runNamespace != "")
+		return []string{"secret1"}, nil
+	}
 
-		cf = k8sfake.NewClientFactory(
-			k8sfake.Namespace("namespace1"),
-			pipelineRun,
-		)
-		ctx := context.Background()
-		k8sPipelineRun, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
-		assert.NilError(t, err)
-		config := &cfg.PipelineRunsConfigStruct{}
-		examinee = newRunManager(
-			cf,
-			k8s.NewTenantNamespace(cf, pipelineRun.GetNamespace()).GetSecretProvider(),
-		)
-		examinee.testing = newRunManagerTestingWithRequiredStubs()
-		runCtx = &runContext{
-			pipelineRun:        k8sPipelineRun,
-			pipelineRunsConfig: config,
-		}
-		return
+	runCtx := &runContext{
+		pipelineRun:        pipelineRunHelper,
+		pipelineRunsConfig: config,
 	}
 
-	expectSingleTaskRun := func(t *testing.T, cf *k8sfake.ClientFactory, k8sPipelineRun k8s.PipelineRun) *tektonv1beta1.TaskRun {
-		ctx := context.Background()
-		taskRunList, err := cf.TektonV1beta1().TaskRuns(k8sPipelineRun.GetRunNamespace()).List(ctx, metav1.ListOptions{})
-		assert.NilError(t, err)
-		assert.Equal(t, 1, len(taskRunList.Items), "%s", spew.Sdump(taskRunList))
-		return &taskRunList.Items[0]
+	// EXERCISE
+	resultErr := examinee.prepareRunNamespace(h.ctx, runCtx)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, []string{"secret1"}, runCtx.sourceSecretNames)
+}
+
+func Test__runManager_setupServiceAccount_AttachesSourceSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	const runNamespace = "runNamespace1"
+	cf := newFakeClientFactory(
+		k8sfake.Namespace(runNamespace),
+		k8sfake.ClusterRole(string(runClusterRoleName)),
+	)
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	runCtx := &runContext{
+		runNamespace:      runNamespace,
+		sourceSecretNames: []string{"sourceSecret1", "", "sourceSecret2"},
 	}
 
-	/**
-	 * Test: Various JSON values for spec.logging.elasticsearch.runID
-	 * are correctly passed as Tekton TaskRun input parameter.
-	 */
-	test := "Passthrough"
-	for _, tc := range []struct {
-		name               string
-		runIDJSON          string
-		expectedParamValue string
-	}{
-		{"none", ``, `null`},
-		{"dummy", `"___dummy___": 1`, `null`},
-		{"null", `"runID": null`, `null`},
-		{"true", `"runID": true`, `true`},
-		{"false", `"runID": false`, `false`},
-		{"int", `"runID": 123`, `123`},
-		{"intneg", `"runID": -123`, `-123`},
-		{"float", `"runID": 123.45`, `123.45`},
-		{"floatneg", `"runID": -123.45`, `-123.45`},
-		{"string", `"runID": "some string"`, `"some string"`},
-		{"map", `"runID": { "key2": "value2", "key1": "value1" }`, `{"key1":"value1","key2":"value2"}`},
-		{"mapdeep", `
-			"runID": {
-				"key1": {
-					"key2": {
-						"key3_1": "value3",
-						"key3_2": null,
-						"key3_3": [1, "2", true]
-					}
-				}
-			}`,
-			`{"key1":{"key2":{"key3_1":"value3","key3_2":null,"key3_3":[1,"2",true]}}}`},
-	} {
-		t.Run(test+"_"+tc.name, func(t *testing.T) {
-			// setup
-			ctx := context.Background()
-			pipelineRunJSON := fmt.Sprintf(fixIndent(`
-				{
-					"apiVersion": "steward.sap.com/v1alpha1",
-					"kind": "PipelineRun",
-					"metadata": {
-						"name": "dummy1",
-						"namespace": "namespace1"
-					},
-					"spec": {
-						"jenkinsFile": {
-							"repoUrl": "dummyRepoUrl",
-							"revision": "dummyRevision",
-							"relativePath": "dummyRelativePath"
-						},
-						"logging": {
-							"elasticsearch": {
-								%s
-							}
-						}
-					}
-				}`),
-				tc.runIDJSON,
-			)
-			t.Log("input:", pipelineRunJSON)
-			examinee, runCtx, cf := setupExaminee(t, pipelineRunJSON)
+	// EXERCISE
+	resultErr := examinee.setupServiceAccount(h.ctx, runCtx, "pipelineCloneSecret1", []string{"imagePullSecret1"})
 
-			// exercise
-			resultError := examinee.createTektonTaskRun(ctx, runCtx)
-			assert.NilError(t, resultError)
-			// verify
-			taskRun := expectSingleTaskRun(t, cf, runCtx.pipelineRun)
-			param := findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
-			assert.Assert(t, param != nil)
-			assert.Equal(t, tektonv1beta1.ParamTypeString, param.Value.Type)
-			assert.Equal(t, tc.expectedParamValue, param.Value.StringVal)
+	// VERIFY
+	assert.NilError(t, resultErr)
+	serviceAccount, err := cf.CoreV1().ServiceAccounts(runNamespace).Get(h.ctx, serviceAccountName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	secretNames := []string{}
+	for _, ref := range serviceAccount.Secrets {
+		secretNames = append(secretNames, ref.Name)
+	}
+	assert.DeepEqual(t, []string{"pipelineCloneSecret1", "sourceSecret1", "sourceSecret2"}, secretNames)
+	assert.DeepEqual(t, []corev1.LocalObjectReference{{Name: "imagePullSecret1"}}, serviceAccount.ImagePullSecrets)
+}
 
-			param = findTaskRunParam(taskRun, TaskRunParamNameIndexURL)
-			assert.Assert(t, is.Nil(param))
-		})
+func Test__runManager_setupServiceAccount_WrapsConflictAfterRetries(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	const runNamespace = "runNamespace1"
+	cf := newFakeClientFactory(
+		k8sfake.Namespace(runNamespace),
+	)
+	conflict := k8serrors.NewConflict(corev1.Resource("serviceaccounts"), serviceAccountName, fmt.Errorf("conflict1"))
+	cf.KubernetesClientset().PrependReactor("update", "serviceaccounts", k8sfake.NewErrorReactor(conflict))
+	examinee := newRunManager(cf, secretproviderfakes.NewProvider(h.namespace1))
+	runCtx := &runContext{
+		runNamespace:      runNamespace,
+		sourceSecretNames: []string{"sourceSecret1"},
 	}
 
-	/**
-	 * Test: If there is no spec.logging.elasticsearch, the index URL
-	 * template parameter should be defined as empty string, effectively
-	 * disabling logging to Elasticsearch.
-	 */
-	test = "SuppressIndexURL"
-	for _, tc := range []struct {
-		name            string
-		loggingFragment string
-	}{
+	// EXERCISE
+	resultErr := examinee.setupServiceAccount(h.ctx, runCtx, "", nil)
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `failed to update service account "default": retries on resource version conflicts exhausted`)
+	assert.Assert(t, k8serrors.IsConflict(resultErr))
+}
+
+func Test__runManager_Start__DoesNotSetPipelineRunStatus(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	h := newTestHelper1(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
+	h.preparePredefinedClusterRole(mockFactory, mockPipelineRun)
+	config := &cfg.PipelineRunsConfigStruct{}
+
+	examinee := newRunManager(mockFactory, mockSecretProvider)
+	examinee.testing = newRunManagerTestingWithRequiredStubs()
+
+	// EXERCISE
+	_, _, resultError := examinee.Start(h.ctx, mockPipelineRun, config)
+	assert.NilError(t, resultError)
+
+	// VERIFY
+	// UpdateState should never be called
+	mockPipelineRun.EXPECT().UpdateState(gomock.Any(), gomock.Any()).Times(0)
+}
+
+type fakeRevisionResolver struct {
+	sha   string
+	err   error
+	calls int
+
+	repoURL, revision string
+	credentials       *revision.Credentials
+}
+
+func (r *fakeRevisionResolver) Resolve(_ context.Context, repoURL, revision string, credentials *revision.Credentials) (string, error) {
+	r.calls++
+	r.repoURL, r.revision, r.credentials = repoURL, revision, credentials
+	return r.sha, r.err
+}
+
+func newRunContextForRevisionResolution(t *testing.T, resolveRevision bool) *runContext {
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		JenkinsFile: stewardv1alpha1.JenkinsFile{
+			URL:            "https://github.com/org/repo",
+			Revision:       "master",
+			RepoAuthSecret: "secret1",
+		},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		ResolveRevision:             resolveRevision,
+		ResolveRevisionAllowedHosts: []string{"github.com"},
+	}
+	return runCtx
+}
+
+func Test__runManager_resolveRevision__Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	resolver := &fakeRevisionResolver{}
+	examinee := newRunManager(nil, secretproviderfakes.NewProvider("ns1"))
+	examinee.revisionResolver = resolver
+	runCtx := newRunContextForRevisionResolution(t, false)
+
+	// EXERCISE
+	err := examinee.resolveRevision(context.Background(), runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 0, resolver.calls)
+	assert.Equal(t, "", runCtx.resolvedRevision)
+	assert.Equal(t, "", runCtx.pipelineRun.GetStatus().ResolvedRevision)
+}
+
+func Test__runManager_resolveRevision__SkipsURLsNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	for _, repoURL := range []string{
+		"git@github.com:org/repo.git",
+		"https://kubernetes.default.svc/org/repo",
+		"http://169.254.169.254/latest/meta-data",
+	} {
+		repoURL := repoURL
+		t.Run(repoURL, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			resolver := &fakeRevisionResolver{}
+			examinee := newRunManager(nil, secretproviderfakes.NewProvider("ns1"))
+			examinee.revisionResolver = resolver
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				JenkinsFile: stewardv1alpha1.JenkinsFile{
+					URL:            repoURL,
+					Revision:       "master",
+					RepoAuthSecret: "secret1",
+				},
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				ResolveRevision:             true,
+				ResolveRevisionAllowedHosts: []string{"github.com"},
+			}
+
+			// EXERCISE
+			err := examinee.resolveRevision(context.Background(), runCtx)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, 0, resolver.calls)
+			assert.Equal(t, "", runCtx.resolvedRevision)
+		})
+	}
+}
+
+func Test__runManager_resolveRevision__Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	resolver := &fakeRevisionResolver{sha: sha}
+	secret := k8sfake.SecretWithType("secret1", "ns1", corev1.SecretTypeBasicAuth)
+	secret.Data = map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte("user1"),
+		corev1.BasicAuthPasswordKey: []byte("pass1"),
+	}
+	examinee := newRunManager(nil, secretproviderfakes.NewProvider("ns1", secret))
+	examinee.revisionResolver = resolver
+	runCtx := newRunContextForRevisionResolution(t, true)
+
+	// EXERCISE
+	err := examinee.resolveRevision(context.Background(), runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 1, resolver.calls)
+	assert.Equal(t, "https://github.com/org/repo", resolver.repoURL)
+	assert.Equal(t, "master", resolver.revision)
+	assert.DeepEqual(t, &revision.Credentials{Username: "user1", Password: "pass1"}, resolver.credentials)
+	assert.Equal(t, sha, runCtx.resolvedRevision)
+	assert.Equal(t, sha, runCtx.pipelineRun.GetStatus().ResolvedRevision)
+}
+
+func Test__runManager_resolveRevision__TokenSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	resolver := &fakeRevisionResolver{sha: sha}
+	secret := k8sfake.SecretOpaque("secret1", "ns1")
+	secret.Data = map[string][]byte{
+		secrets.TokenKey: []byte("token1"),
+	}
+	examinee := newRunManager(nil, secretproviderfakes.NewProvider("ns1", secret))
+	examinee.revisionResolver = resolver
+	runCtx := newRunContextForRevisionResolution(t, true)
+
+	// EXERCISE
+	err := examinee.resolveRevision(context.Background(), runCtx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &revision.Credentials{Username: secrets.DefaultTokenUsername, Password: "token1"}, resolver.credentials)
+}
+
+func Test__runManager_resolveRevision__Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		secrets       []*corev1.Secret
+		resolverErr   error
+		expectedClass stewardv1alpha1.Result
+	}{
+		{
+			name:          "secret_not_found",
+			expectedClass: stewardv1alpha1.ResultErrorContent,
+		},
+		{
+			name:          "revision_not_found",
+			secrets:       []*corev1.Secret{k8sfake.SecretOpaque("secret1", "ns1")},
+			resolverErr:   errors.Wrap(revision.ErrNotFound, "foo"),
+			expectedClass: stewardv1alpha1.ResultErrorContent,
+		},
+		{
+			name:          "other_error",
+			secrets:       []*corev1.Secret{k8sfake.SecretOpaque("secret1", "ns1")},
+			resolverErr:   errors.New("connection refused"),
+			expectedClass: stewardv1alpha1.ResultErrorInfra,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := newRunManager(nil, secretproviderfakes.NewProvider("ns1", tc.secrets...))
+			examinee.revisionResolver = &fakeRevisionResolver{err: tc.resolverErr}
+			runCtx := newRunContextForRevisionResolution(t, true)
+
+			// EXERCISE
+			err := examinee.resolveRevision(context.Background(), runCtx)
+
+			// VERIFY
+			assert.Assert(t, err != nil)
+			assert.Equal(t, tc.expectedClass, serrors.GetClass(err))
+			assert.Equal(t, "", runCtx.pipelineRun.GetStatus().ResolvedRevision)
+		})
+	}
+}
+
+func Test__runManager_addTektonTaskRunParamsForPipeline__UsesResolvedRevision(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := runManager{}
+	runCtx := newRunContextForRevisionResolution(t, true)
+	runCtx.resolvedRevision = "0123456789abcdef0123456789abcdef01234567"
+	tektonTaskRun := tektonv1beta1.TaskRun{}
+
+	// EXERCISE
+	err := examinee.addTektonTaskRunParamsForPipeline(runCtx, &tektonTaskRun, nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, assertcmp.Contains(tektonTaskRun.Spec.Params,
+		tektonStringParam("PIPELINE_GIT_REVISION", "0123456789abcdef0123456789abcdef01234567")))
+}
+
+func Test__runManager_copySecretsToRunNamespace__DoesCopySecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	examinee := &runManager{factory: k8sfake.NewClientFactory()}
+
+	mockSecretManager := runmocks.NewMockSecretManager(mockCtrl)
+	// inject secret manager
+
+	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee.testing.getSecretManagerStub = func(*runContext) runifc.SecretManager {
+		return mockSecretManager
+	}
+
+	run := k8smocks.NewMockPipelineRun(mockCtrl)
+	run.EXPECT().GetNamespace().Return("ns1").AnyTimes()
+	runCtx := &runContext{
+		pipelineRun: run,
+	}
+
+	// EXPECT
+	mockSecretManager.EXPECT().CopyAll(gomock.Not(gomock.Nil()), run).
+		Return("cloneSecret1", []string{"foo", "bar"}, nil).
+		Times(1)
+
+	// EXERCISE
+	cloneSecret, imagePullSecrets, resultError := examinee.copySecretsToRunNamespace(ctx, runCtx)
+
+	// VERFIY
+	assert.NilError(t, resultError)
+	assert.Equal(t, "cloneSecret1", cloneSecret)
+	assert.DeepEqual(t, []string{"foo", "bar"}, imagePullSecrets)
+}
+
+func Test__runManager_copySecretsToRunNamespace__CopiesDefaultImagePullSecretsOfTenantNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	namespace := k8sfake.NamespaceWithAnnotations("ns1", map[string]string{
+		stewardv1alpha1.AnnotationDefaultImagePullSecrets: "default1, foo,default2,default1",
+	})
+	examinee := &runManager{factory: k8sfake.NewClientFactory(namespace)}
+
+	mockSecretManager := runmocks.NewMockSecretManager(mockCtrl)
+	examinee.testing = newRunManagerTestingWithRequiredStubs()
+	examinee.testing.getSecretManagerStub = func(*runContext) runifc.SecretManager {
+		return mockSecretManager
+	}
+
+	run := k8smocks.NewMockPipelineRun(mockCtrl)
+	run.EXPECT().GetNamespace().Return("ns1").AnyTimes()
+	run.EXPECT().GetSpec().Return(&stewardv1alpha1.PipelineSpec{ImagePullSecrets: []string{"foo"}}).AnyTimes()
+	runCtx := &runContext{
+		pipelineRun: run,
+	}
+
+	// EXPECT
+	mockSecretManager.EXPECT().CopyAll(gomock.Not(gomock.Nil()), run).
+		Return("cloneSecret1", []string{"foo-abcde"}, nil).
+		Times(1)
+	mockSecretManager.EXPECT().CopyImagePullSecrets(gomock.Not(gomock.Nil()), run, []string{"default1", "default2"}).
+		Return([]string{"default1-fghij", "default2-klmno"}, nil).
+		Times(1)
+
+	// EXERCISE
+	cloneSecret, imagePullSecrets, resultError := examinee.copySecretsToRunNamespace(ctx, runCtx)
+
+	// VERFIY
+	assert.NilError(t, resultError)
+	assert.Equal(t, "cloneSecret1", cloneSecret)
+	assert.DeepEqual(t, []string{"foo-abcde", "default1-fghij", "default2-klmno"}, imagePullSecrets)
+}
+
+func Test__runManager_Cleanup__RemovesNamespaces(t *testing.T) {
+	for _, ffEnabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("featureflag_CreateAuxNamespaceIfUnused_%t", ffEnabled), func(t *testing.T) {
+			defer featureflagtesting.WithFeatureFlag(featureflag.CreateAuxNamespaceIfUnused, ffEnabled)()
+
+			// SETUP
+			h := newTestHelper1(t)
+
+			cf := newFakeClientFactory(
+				k8sfake.Namespace(h.namespace1),
+				k8sfake.PipelineRun(h.pipelineRun1, h.namespace1, stewardv1alpha1.PipelineSpec{}),
+			)
+
+			config := &cfg.PipelineRunsConfigStruct{}
+			secretProvider := secretproviderfakes.NewProvider(h.namespace1)
+
+			examinee := newRunManager(cf, secretProvider)
+			examinee.testing = newRunManagerTestingWithAllNoopStubs()
+			examinee.testing.cleanupStub = nil
+
+			pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
+			assert.NilError(t, err)
+
+			runCtx := &runContext{
+				pipelineRun:        pipelineRunHelper,
+				pipelineRunsConfig: config,
+			}
+			err = examinee.prepareRunNamespace(h.ctx, runCtx)
+			assert.NilError(t, err)
+			runCtx.pipelineRun.UpdateRunNamespace(runCtx.runNamespace)
+			runCtx.pipelineRun.UpdateAuxNamespace(runCtx.auxNamespace)
+			_, err = runCtx.pipelineRun.CommitStatus(h.ctx)
+			assert.NilError(t, err)
+			{
+				pipelineRun1 := h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1)
+				expectedNamespaces := []string{h.namespace1}
+				assert.Assert(t, pipelineRun1.Status.Namespace != "")
+				expectedNamespaces = append(expectedNamespaces, pipelineRun1.Status.Namespace)
+				if ffEnabled {
+					assert.Assert(t, pipelineRun1.Status.AuxiliaryNamespace != "")
+					expectedNamespaces = append(expectedNamespaces, pipelineRun1.Status.AuxiliaryNamespace)
+				}
+				h.assertThatExactlyTheseNamespacesExist(cf, expectedNamespaces...)
+			}
+
+			// EXERCISE
+			resultErr := examinee.Cleanup(h.ctx, pipelineRunHelper)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+
+			// namespaces
+			{
+				pipelineRun1 := h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1)
+				assert.Assert(t, pipelineRun1.Status.Namespace != "")
+				if ffEnabled {
+					assert.Assert(t, pipelineRun1.Status.AuxiliaryNamespace != "")
+				}
+				h.assertThatExactlyTheseNamespacesExist(cf, h.namespace1)
+			}
+		})
+	}
+}
+
+func Test__runManager__Log_Elasticsearch(t *testing.T) {
+	t.Parallel()
+
+	const (
+		TaskRunParamNameIndexURL  = "PIPELINE_LOG_ELASTICSEARCH_INDEX_URL"
+		TaskRunParamNameRunIDJSON = "PIPELINE_LOG_ELASTICSEARCH_RUN_ID_JSON"
+	)
+
+	findTaskRunParam := func(taskRun *tektonv1beta1.TaskRun, paramName string) (param *tektonv1beta1.Param) {
+		assert.Assert(t, taskRun.Spec.Params != nil)
+		for _, p := range taskRun.Spec.Params {
+			if p.Name == paramName {
+				if param != nil {
+					t.Fatalf("input param specified twice: %s", paramName)
+				}
+				param = &p
+			}
+		}
+		return
+	}
+
+	setupExaminee := func(
+		t *testing.T, pipelineRunJSON string,
+	) (
+		examinee *runManager, runCtx *runContext, cf *k8sfake.ClientFactory,
+	) {
+		pipelineRun := StewardObjectFromJSON(t, pipelineRunJSON).(*stewardv1alpha1.PipelineRun)
+		t.Log("decoded:\n", spew.Sdump(pipelineRun))
+
+		cf = k8sfake.NewClientFactory(
+			k8sfake.Namespace("namespace1"),
+			pipelineRun,
+		)
+		ctx := context.Background()
+		k8sPipelineRun, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
+		assert.NilError(t, err)
+		config := &cfg.PipelineRunsConfigStruct{}
+		examinee = newRunManager(
+			cf,
+			k8s.NewTenantNamespace(cf, pipelineRun.GetNamespace()).GetSecretProvider(),
+		)
+		examinee.testing = newRunManagerTestingWithRequiredStubs()
+		runCtx = &runContext{
+			pipelineRun:        k8sPipelineRun,
+			pipelineRunsConfig: config,
+		}
+		return
+	}
+
+	expectSingleTaskRun := func(t *testing.T, cf *k8sfake.ClientFactory, k8sPipelineRun k8s.PipelineRun) *tektonv1beta1.TaskRun {
+		ctx := context.Background()
+		taskRunList, err := cf.TektonV1beta1().TaskRuns(k8sPipelineRun.GetRunNamespace()).List(ctx, metav1.ListOptions{})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(taskRunList.Items), "%s", spew.Sdump(taskRunList))
+		return &taskRunList.Items[0]
+	}
+
+	/**
+	 * Test: Various JSON values for spec.logging.elasticsearch.runID
+	 * are correctly passed as Tekton TaskRun input parameter.
+	 */
+	test := "Passthrough"
+	for _, tc := range []struct {
+		name               string
+		runIDJSON          string
+		expectedParamValue string
+	}{
+		{"none", ``, `null`},
+		{"dummy", `"___dummy___": 1`, `null`},
+		{"null", `"runID": null`, `null`},
+		{"true", `"runID": true`, `true`},
+		{"false", `"runID": false`, `false`},
+		{"int", `"runID": 123`, `123`},
+		{"intneg", `"runID": -123`, `-123`},
+		{"float", `"runID": 123.45`, `123.45`},
+		{"floatneg", `"runID": -123.45`, `-123.45`},
+		{"string", `"runID": "some string"`, `"some string"`},
+		{"map", `"runID": { "key2": "value2", "key1": "value1" }`, `{"key1":"value1","key2":"value2"}`},
+		{"mapWithID", `"runID": { "id": "value2", "key1": "value1" }`, `{"id":"value2","key1":"value1"}`},
+		{"mapWithEmptyKey", `"runID": { "": "value1" }`, `{"":"value1"}`},
+		{"mapdeep", `
+			"runID": {
+				"key1": {
+					"key2": {
+						"key3_1": "value3",
+						"key3_2": null,
+						"key3_3": [1, "2", true]
+					}
+				}
+			}`,
+			`{"key1":{"key2":{"key3_1":"value3","key3_2":null,"key3_3":[1,"2",true]}}}`},
+	} {
+		t.Run(test+"_"+tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			pipelineRunJSON := fmt.Sprintf(fixIndent(`
+				{
+					"apiVersion": "steward.sap.com/v1alpha1",
+					"kind": "PipelineRun",
+					"metadata": {
+						"name": "dummy1",
+						"namespace": "namespace1"
+					},
+					"spec": {
+						"jenkinsFile": {
+							"repoUrl": "dummyRepoUrl",
+							"revision": "dummyRevision",
+							"relativePath": "dummyRelativePath"
+						},
+						"logging": {
+							"elasticsearch": {
+								%s
+							}
+						}
+					}
+				}`),
+				tc.runIDJSON,
+			)
+			t.Log("input:", pipelineRunJSON)
+			examinee, runCtx, cf := setupExaminee(t, pipelineRunJSON)
+
+			// exercise
+			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+			assert.NilError(t, resultError)
+			// verify
+			taskRun := expectSingleTaskRun(t, cf, runCtx.pipelineRun)
+			param := findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
+			assert.Assert(t, param != nil)
+			assert.Equal(t, tektonv1beta1.ParamTypeString, param.Value.Type)
+			assert.Equal(t, tc.expectedParamValue, param.Value.StringVal)
+
+			param = findTaskRunParam(taskRun, TaskRunParamNameIndexURL)
+			assert.Assert(t, is.Nil(param))
+		})
+	}
+
+	/**
+	 * Test: The run ID of spec.logging.sink is passed as Tekton TaskRun
+	 * input parameter, invalid sinks result in an error.
+	 */
+	test = "Sink"
+	for _, tc := range []struct {
+		name               string
+		loggingJSON        string
+		expectedParamValue string
+		expectedError      string
+	}{
+		{"empty", `"sink": {"type": "elasticsearch"}`, `null`, ""},
+		{"runID", `"sink": {"type": "elasticsearch", "runID": "run1"}`, `"run1"`, ""},
+		{"fields", `"sink": {"type": "elasticsearch", "fields": {"k1": "v1"}}`, `{"k1":"v1"}`, ""},
+		{"runIDAndFields", `"sink": {"type": "elasticsearch", "runID": "run1", "fields": {"k1": "v1"}}`, `{"id":"run1","k1":"v1"}`, ""},
+		{"unknownType", `"sink": {"type": "fluentd"}`, "", `field spec.logging.sink.type has unsupported value "fluentd"`},
+		{"invalidIndex", `"sink": {"type": "elasticsearch", "index": "Logs"}`, "", `field spec.logging.sink.index has invalid value "Logs": must be lowercase`},
+		{"combined", `"sink": {"type": "elasticsearch"}, "elasticsearch": {}`, "", "fields spec.logging.sink and spec.logging.elasticsearch must not be combined"},
+	} {
+		t.Run(test+"_"+tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			pipelineRunJSON := fmt.Sprintf(fixIndent(`
+				{
+					"apiVersion": "steward.sap.com/v1alpha1",
+					"kind": "PipelineRun",
+					"metadata": {
+						"name": "dummy1",
+						"namespace": "namespace1"
+					},
+					"spec": {
+						"jenkinsFile": {
+							"repoUrl": "dummyRepoUrl",
+							"revision": "dummyRevision",
+							"relativePath": "dummyRelativePath"
+						},
+						"logging": {
+							%s
+						}
+					}
+				}`),
+				tc.loggingJSON,
+			)
+			t.Log("input:", pipelineRunJSON)
+			examinee, runCtx, cf := setupExaminee(t, pipelineRunJSON)
+
+			// exercise
+			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+
+			// verify
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultError, tc.expectedError)
+				return
+			}
+			assert.NilError(t, resultError)
+			taskRun := expectSingleTaskRun(t, cf, runCtx.pipelineRun)
+			param := findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
+			assert.Assert(t, param != nil)
+			assert.Equal(t, tc.expectedParamValue, param.Value.StringVal)
+		})
+	}
+
+	/**
+	 * Test: If there is no spec.logging.elasticsearch, the index URL
+	 * template parameter should be defined as empty string, effectively
+	 * disabling logging to Elasticsearch.
+	 */
+	test = "SuppressIndexURL"
+	for _, tc := range []struct {
+		name            string
+		loggingFragment string
+	}{
 		{"NoLogging", ``},
 		{"NoElasticsearch", `,"logging":{"___dummy___":1}`},
 	} {
-		t.Run(test+"_"+tc.name, func(t *testing.T) {
-			// setup
+		t.Run(test+"_"+tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			pipelineRunJSON := fmt.Sprintf(fixIndent(`
+				{
+					"apiVersion": "steward.sap.com/v1alpha1",
+					"kind": "PipelineRun",
+					"metadata": {
+						"name": "dummy1",
+						"namespace": "namespace1"
+					},
+					"spec": {
+						"jenkinsFile": {
+							"repoUrl": "dummyRepoUrl",
+							"revision": "dummyRevision",
+							"relativePath": "dummyRelativePath"
+						}
+						%s
+					}
+				}`),
+				tc.loggingFragment,
+			)
+			t.Log("input:", pipelineRunJSON)
+			examinee, runCtx, cf := setupExaminee(t, pipelineRunJSON)
+
+			// exercise
+			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+			assert.NilError(t, resultError)
+
+			// verify
+			taskRun := expectSingleTaskRun(t, cf, runCtx.pipelineRun)
+
+			param := findTaskRunParam(taskRun, TaskRunParamNameIndexURL)
+			assert.Assert(t, param != nil)
+			assert.Equal(t, tektonv1beta1.ParamTypeString, param.Value.Type)
+			assert.Equal(t, "", param.Value.StringVal)
+
+			param = findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
+			assert.Assert(t, is.Nil(param))
+		})
+	}
+
+	/**
+	 * Test: If `spec.logging.elasticsearch.indexURL` has an unsupported
+	 * scheme, or format of the URL is incorrect an error is returned.
+	 */
+	test = "ErrorOnWrongFormattedIndexURL"
+	for _, tc := range []struct {
+		name          string
+		URL           string
+		expectedError string
+	}{
+		{"indexURLWithNoScheme", `"indexURL": "testURL"`, "scheme not supported"},
+		{"indexURLWithIncorrectScheme", `"indexURL": "ftp://testURL"`, "scheme not supported"},
+		{"indexURLWithNonsenseScheme", `"indexURL": "nonsense://testURL"`, "scheme not supported"},
+		{"indexURLWithWrongFormat2", `"indexURL": "http//testURL"`, "scheme not supported"},
+		{"indexURLWithWrongFormat3", `"indexURL": ":///bar"`, "missing protocol scheme"},
+		{"indexURLWithWrongFormat4", `"indexURL": "http://\bar"`, "invalid control character in URL"},
+		{"emptyIndexURL", `"indexURL": "http://testURL:wrongPort"`, "invalid port"},
+	} {
+		t.Run(test+"_"+tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			pipelineRunJSON := fmt.Sprintf(fixIndent(`
+				{
+					"apiVersion": "steward.sap.com/v1alpha1",
+					"kind": "PipelineRun",
+					"metadata": {
+						"name": "dummy1",
+						"namespace": "namespace1"
+					},
+					"spec": {
+						"jenkinsFile": {
+							"repoUrl": "dummyRepoUrl",
+							"revision": "dummyRevision",
+							"relativePath": "dummyRelativePath"
+						},
+						"logging": {
+							"elasticsearch": {
+								%s
+							}
+						}
+					}
+				}`),
+				tc.URL,
+			)
+			t.Log("input:", pipelineRunJSON)
+			examinee, runCtx, _ := setupExaminee(t, pipelineRunJSON)
+			// exercise
+			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+			assert.ErrorContains(t, resultError, tc.expectedError)
+		})
+	}
+
+	/**
+	 * Test: `createTektonTaskRun` handles valid index URLs correctly.
+	 */
+	test = "CorrectFormatForIndexURL"
+	for _, tc := range []struct {
+		name string
+		URL  string
+	}{
+		{"validhttpURL", `"indexURL": "http://host.domain"`},
+		{"validhttpsURL", `"indexURL": "https://host.domain"`},
+		{"validHTTPURL", `"indexURL": "HTTP://host.domain"`},
+		{"validHTTPSURL", `"indexURL": "HTTPS://host.domain"`},
+	} {
+		t.Run(test+"_"+tc.name, func(t *testing.T) {
+			// setup
+			ctx := context.Background()
+			pipelineRunJSON := fmt.Sprintf(fixIndent(`
+				{
+					"apiVersion": "steward.sap.com/v1alpha1",
+					"kind": "PipelineRun",
+					"metadata": {
+						"name": "dummy1",
+						"namespace": "namespace1"
+					},
+					"spec": {
+						"jenkinsFile": {
+							"repoUrl": "dummyRepoUrl",
+							"revision": "dummyRevision",
+							"relativePath": "dummyRelativePath"
+						},
+						"logging": {
+							"elasticsearch": {
+								%s
+							}
+						}
+					}
+				}`),
+				tc.URL,
+			)
+			t.Log("input:", pipelineRunJSON)
+			examinee, runCtx, _ := setupExaminee(t, pipelineRunJSON)
+
+			// exercise
+			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+
+			// verify
+			assert.NilError(t, resultError)
+		})
+	}
+}
+
+type testHelper1 struct {
+	t            *testing.T
+	ctx          context.Context
+	namespace1   string
+	pipelineRun1 string
+}
+
+func newTestHelper1(t *testing.T) *testHelper1 {
+	h := &testHelper1{
+		t:            t,
+		ctx:          context.Background(),
+		namespace1:   "namespace1",
+		pipelineRun1: "pipelinerun1",
+	}
+	return h
+}
+
+func (h *testHelper1) getPipelineRunFromStorage(cf *k8sfake.ClientFactory, namespace, name string) *stewardv1alpha1.PipelineRun {
+	t := h.t
+	t.Helper()
+
+	pipelineRun, err := cf.StewardV1alpha1().PipelineRuns(namespace).Get(h.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("could not get pipeline run %q from namespace %q: %s", name, namespace, err.Error())
+	}
+	if pipelineRun == nil {
+		t.Fatalf("could not get pipeline run %q from namespace %q: get was successfull but returned nil", name, namespace)
+	}
+	return pipelineRun
+}
+
+func (h *testHelper1) verifyNamespace(cf *k8sfake.ClientFactory, nsName, expectedPurpose string) {
+	t := h.t
+	t.Helper()
+
+	namePattern := regexp.MustCompile(
+		`^(steward-run-([[:alnum:]]{` + strconv.Itoa(runNamespaceRandomLength) + `})-` +
+			regexp.QuoteMeta(expectedPurpose) +
+			`-)[[:alnum:]]*$`)
+	assert.Assert(t, assertcmp.Regexp(namePattern, nsName))
+
+	namespace, err := cf.CoreV1().Namespaces().Get(h.ctx, nsName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, namespace.ObjectMeta.GenerateName, namePattern.FindStringSubmatch(nsName)[1])
+
+	// labels
+	{
+		_, exists := namespace.GetLabels()[stewardv1alpha1.LabelSystemManaged]
+		assert.Assert(t, exists)
+	}
+}
+
+func (h *testHelper1) assertThatExactlyTheseNamespacesExist(cf *k8sfake.ClientFactory, expected ...string) {
+	t := h.t
+	t.Helper()
+
+	list, err := cf.CoreV1().Namespaces().List(h.ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	actual := []string{}
+	for _, item := range list.Items {
+		if item.GetName() != "" {
+			actual = append(actual, item.GetName())
+		}
+	}
+	sort.Strings(actual)
+
+	if expected == nil {
+		expected = []string{}
+	}
+	{
+		temp := []string{}
+		for _, item := range expected {
+			if item != "" {
+				temp = append(temp, item)
+			}
+		}
+		expected = temp
+	}
+	sort.Strings(expected)
+
+	assert.DeepEqual(t, expected, actual)
+}
+
+func (h *testHelper1) preparePredefinedClusterRole(cf *k8smocks.MockClientFactory, pipelineRun *k8smocks.MockPipelineRun) {
+	t := h.t
+	t.Helper()
+
+	_, err := cf.RbacV1().ClusterRoles().Create(h.ctx, k8sfake.ClusterRole(string(runClusterRoleName)), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("could not create cluster role: %s", err.Error())
+	}
+}
+
+func (h *testHelper1) prepareMocks(ctrl *gomock.Controller) (*k8smocks.MockClientFactory, *k8smocks.MockPipelineRun, *secretmocks.MockSecretProvider) {
+	return h.prepareMocksWithSpec(ctrl, &stewardv1alpha1.PipelineSpec{})
+}
+
+func (*testHelper1) prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewardv1alpha1.PipelineSpec) (*k8smocks.MockClientFactory, *k8smocks.MockPipelineRun, *secretmocks.MockSecretProvider) {
+	mockFactory := k8smocks.NewMockClientFactory(ctrl)
+
+	kubeClientSet := kubefake.NewSimpleClientset()
+	kubeClientSet.PrependReactor("create", "*", k8sfake.GenerateNameReactor(0))
+
+	mockFactory.EXPECT().CoreV1().Return(kubeClientSet.CoreV1()).AnyTimes()
+	mockFactory.EXPECT().RbacV1().Return(kubeClientSet.RbacV1()).AnyTimes()
+	mockFactory.EXPECT().NetworkingV1().Return(kubeClientSet.NetworkingV1()).AnyTimes()
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	mockFactory.EXPECT().Dynamic().Return(dynamicClient).AnyTimes()
+	mockFactory.EXPECT().InstanceID().Return("").AnyTimes()
+	mockFactory.EXPECT().NamespaceMetadata().Return(nil).AnyTimes()
+	mockFactory.EXPECT().WatchNamespaces().Return(nil).AnyTimes()
+
+	stewardClientset := stewardfakeclient.NewSimpleClientset()
+	mockFactory.EXPECT().StewardV1alpha1().Return(stewardClientset.StewardV1alpha1()).AnyTimes()
+
+	tektonClientset := tektonfakeclient.NewSimpleClientset()
+	mockFactory.EXPECT().TektonV1beta1().Return(tektonClientset.TektonV1beta1()).AnyTimes()
+
+	runNamespace := ""
+	auxNamespace := ""
+	mockPipelineRun := k8smocks.NewMockPipelineRun(ctrl)
+	mockPipelineRun.EXPECT().GetAPIObject().Return(&stewardv1alpha1.PipelineRun{Spec: *spec}).AnyTimes()
+	mockPipelineRun.EXPECT().GetSpec().Return(spec).AnyTimes()
+	mockPipelineRun.EXPECT().GetStatus().Return(&stewardv1alpha1.PipelineStatus{}).AnyTimes()
+	mockPipelineRun.EXPECT().GetKey().Return("key").AnyTimes()
+	mockPipelineRun.EXPECT().GetNamespace().Return("tenantNamespace1").AnyTimes()
+	mockPipelineRun.EXPECT().GetPipelineRepoServerURL().Return("server", nil).AnyTimes()
+	mockPipelineRun.EXPECT().GetRunNamespace().DoAndReturn(func() string {
+		return runNamespace
+	}).AnyTimes()
+	mockPipelineRun.EXPECT().GetAuxNamespace().DoAndReturn(func() string {
+		return auxNamespace
+	}).AnyTimes()
+
+	mockPipelineRun.EXPECT().UpdateRunNamespace(gomock.Any()).Do(func(arg string) {
+		runNamespace = arg
+	}).MaxTimes(1)
+	mockPipelineRun.EXPECT().UpdateAuxNamespace(gomock.Any()).Do(func(arg string) {
+		auxNamespace = arg
+	}).MaxTimes(1)
+	mockPipelineRun.EXPECT().CommitStatus(gomock.Any()).MaxTimes(1)
+
+	mockSecretProvider := secretmocks.NewMockSecretProvider(ctrl)
+
+	return mockFactory, mockPipelineRun, mockSecretProvider
+}
+
+func newEmptyRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+	return &cfg.PipelineRunsConfigStruct{}, nil
+}
+
+func newImagePolicyTestClusterTask() *tektonv1beta1.ClusterTask {
+	return &tektonv1beta1.ClusterTask{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "tekton.dev/v1beta1", Kind: "ClusterTask"},
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tektonv1beta1.TaskSpec{
+			Params: []tektonv1beta1.ParamSpec{
+				{Name: "JFR_IMAGE", Type: tektonv1beta1.ParamTypeString},
+				{
+					Name:    "SIDECAR_IMAGE",
+					Type:    tektonv1beta1.ParamTypeString,
+					Default: tektonv1beta1.NewArrayOrString("registry.example.com/sidecar:1"),
+				},
+			},
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "clone-sources", Image: "$(params.JFR_IMAGE)"}},
+				{Container: corev1.Container{Name: tektonClusterTaskJenkinsfileRunnerStep, Image: "$(params.JFR_IMAGE)"}},
+			},
+			Sidecars: []tektonv1beta1.Sidecar{
+				{Container: corev1.Container{Name: "sidecar1", Image: "$(params.SIDECAR_IMAGE)"}},
+			},
+		},
+	}
+}
+
+func Test__runManager_checkImagePolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		allowedRegistries []string
+		jfrImage          string
+		expectedErr       string
+	}{
+		{"no_policy", nil, "other.example.com/jfr", ""},
+		{"all_allowed", []string{"registry.example.com"}, "registry.example.com/jfr", ""},
+		{"step_not_allowed", []string{"registry.example.com"}, "other.example.com/jfr", `image policy violation in step "clone-sources"`},
+		{"sidecar_not_allowed", []string{"registry.example.com/jfr", "docker.io/library"}, "jfr:1", `image policy violation in sidecar "sidecar1"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
 			ctx := context.Background()
-			pipelineRunJSON := fmt.Sprintf(fixIndent(`
-				{
-					"apiVersion": "steward.sap.com/v1alpha1",
-					"kind": "PipelineRun",
-					"metadata": {
-						"name": "dummy1",
-						"namespace": "namespace1"
-					},
-					"spec": {
-						"jenkinsFile": {
-							"repoUrl": "dummyRepoUrl",
-							"revision": "dummyRevision",
-							"relativePath": "dummyRelativePath"
-						}
-						%s
-					}
-				}`),
-				tc.loggingFragment,
-			)
-			t.Log("input:", pipelineRunJSON)
-			examinee, runCtx, cf := setupExaminee(t, pipelineRunJSON)
+			cf := newFakeClientFactory(newImagePolicyTestClusterTask())
+			examinee := newRunManager(cf, nil)
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				AllowedImageRegistries: tc.allowedRegistries,
+			}
+			tektonTaskRun := &tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					TaskRef: &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName},
+					Params:  []tektonv1beta1.Param{tektonStringParam("JFR_IMAGE", tc.jfrImage)},
+				},
+			}
+
+			// EXERCISE
+			err := examinee.checkImagePolicy(ctx, runCtx, tektonTaskRun)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Assert(t, errors.Is(err, imagepolicy.ErrImageNotAllowed))
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
+
+func Test__runManager_checkImagePolicy_EmbeddedTaskSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := newFakeClientFactory( /* no ClusterTask */ )
+	examinee := newRunManager(cf, nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		AllowedImageRegistries: []string{"registry.example.com"},
+	}
+	tektonTaskRun := &tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{
+			TaskSpec: &newImagePolicyTestClusterTask().Spec,
+			Params:   []tektonv1beta1.Param{tektonStringParam("JFR_IMAGE", "registry.example.com/jfr")},
+		},
+	}
+
+	// EXERCISE
+	err := examinee.checkImagePolicy(context.Background(), runCtx, tektonTaskRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+type fakeImageVerifier struct {
+	invalidImages map[string]bool
+	err           error
+
+	verifiedImages []string
+	publicKey      string
+}
+
+// Resolve pins the given image to a digest derived from the tag.
+func (v *fakeImageVerifier) Resolve(_ context.Context, image string) (string, error) {
+	if strings.Contains(image, "@") {
+		return image, nil
+	}
+	tag := "latest"
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, tag = image[:i], image[i+1:]
+	}
+	return image + "@sha256:" + tag, nil
+}
+
+func (v *fakeImageVerifier) Verify(_ context.Context, image, publicKey string) error {
+	v.verifiedImages = append(v.verifiedImages, image)
+	v.publicKey = publicKey
+	if v.err != nil {
+		return v.err
+	}
+	if v.invalidImages[image] {
+		return errors.Wrapf(imagepolicy.ErrSignatureInvalid, "image %q", image)
+	}
+	return nil
+}
+
+func Test__runManager_checkImagePolicy_Signatures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		publicKey         string
+		invalidImages     map[string]bool
+		verifierErr       error
+		expectedVerified  []string
+		expectedImages    []string
+		expectedErr       string
+		expectedErrClass  stewardv1alpha1.Result
+		expectedSigErrors bool
+	}{
+		{
+			name: "no_public_key",
+		},
+		{
+			name:             "all_valid",
+			publicKey:        "publicKey1",
+			expectedVerified: []string{"registry.example.com/jfr@sha256:latest", "registry.example.com/jfr@sha256:latest", "registry.example.com/sidecar@sha256:1"},
+			expectedImages:   []string{"registry.example.com/jfr@sha256:latest", "registry.example.com/jfr@sha256:latest", "registry.example.com/sidecar@sha256:1"},
+		},
+		{
+			name:              "sidecar_invalid",
+			publicKey:         "publicKey1",
+			invalidImages:     map[string]bool{"registry.example.com/sidecar@sha256:1": true},
+			expectedVerified:  []string{"registry.example.com/jfr@sha256:latest", "registry.example.com/jfr@sha256:latest", "registry.example.com/sidecar@sha256:1"},
+			expectedErr:       `image policy violation in sidecar "sidecar1"`,
+			expectedErrClass:  stewardv1alpha1.ResultErrorContent,
+			expectedSigErrors: true,
+		},
+		{
+			name:             "verifier_error",
+			publicKey:        "publicKey1",
+			verifierErr:      errors.New("cosign executable not found"),
+			expectedVerified: []string{"registry.example.com/jfr@sha256:latest"},
+			expectedErr:      `failed to verify image signature of step "clone-sources": cosign executable not found`,
+			expectedErrClass: stewardv1alpha1.ResultUndefined,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := newFakeClientFactory(newImagePolicyTestClusterTask())
+			verifier := &fakeImageVerifier{invalidImages: tc.invalidImages, err: tc.verifierErr}
+			examinee := newRunManager(cf, nil)
+			examinee.imageVerifier = verifier
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				ImageSignaturePublicKey: tc.publicKey,
+			}
+			tektonTaskRun := &tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					TaskRef: &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName},
+					Params:  []tektonv1beta1.Param{tektonStringParam("JFR_IMAGE", "registry.example.com/jfr")},
+				},
+			}
+
+			// EXERCISE
+			err := examinee.checkImagePolicy(ctx, runCtx, tektonTaskRun)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedVerified, verifier.verifiedImages)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				assert.Equal(t, tc.expectedSigErrors, errors.Is(err, imagepolicy.ErrSignatureInvalid))
+				assert.Equal(t, tc.expectedErrClass, serrors.GetClass(err))
+			} else {
+				assert.NilError(t, err)
+			}
+			if len(tc.expectedVerified) > 0 {
+				assert.Equal(t, "publicKey1", verifier.publicKey)
+			}
+			if tc.expectedImages != nil {
+				// the task spec is embedded with images pinned to the verified digests
+				assert.Assert(t, tektonTaskRun.Spec.TaskRef == nil)
+				taskSpec := tektonTaskRun.Spec.TaskSpec
+				assert.Assert(t, taskSpec != nil)
+				images := []string{}
+				for _, step := range taskSpec.Steps {
+					images = append(images, step.Image)
+				}
+				for _, sidecar := range taskSpec.Sidecars {
+					images = append(images, sidecar.Image)
+				}
+				assert.DeepEqual(t, tc.expectedImages, images)
+			}
+		})
+	}
+}
+
+func Test__substituteTaskParams(t *testing.T) {
+	t.Parallel()
+
+	params := map[string]string{"A": "a1", "B-2": "b2"}
+	assert.Equal(t, "a1", substituteTaskParams("$(params.A)", params))
+	assert.Equal(t, "x/a1:b2", substituteTaskParams("x/$(params.A):$(params.B-2)", params))
+	assert.Equal(t, "$(params.C)", substituteTaskParams("$(params.C)", params))
+	assert.Equal(t, "plain", substituteTaskParams("plain", params))
+}
+
+func Test__podSecurityContext(t *testing.T) {
+	t.Parallel()
+
+	securityProfiles := map[string]*cfg.SecurityProfile{
+		"profile1": {
+			PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: int64Ptr(1111)},
+		},
+		"profile2": {
+			PodSecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   boolPtr(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+		},
+		"container_only": {
+			ContainerSecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: boolPtr(false)},
+		},
+		"ranges": {
+			PodSecurityContext: &corev1.PodSecurityContext{FSGroup: int64Ptr(2222)},
+			RunAsUserRange:     &cfg.IDRange{Min: 100000, Max: 100000},
+			RunAsGroupRange:    &cfg.IDRange{Min: 200000, Max: 200000},
+		},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		defaultProfile  string
+		selectedProfile string
+		expected        *corev1.PodSecurityContext
+		expectedErr     string
+	}{
+		{
+			name:     "no_profile",
+			expected: &corev1.PodSecurityContext{FSGroup: int64Ptr(3333)},
+		},
+		{
+			name:           "default_profile",
+			defaultProfile: "profile1",
+			expected:       securityProfiles["profile1"].PodSecurityContext,
+		},
+		{
+			name:            "selected_profile",
+			defaultProfile:  "profile1",
+			selectedProfile: "profile2",
+			expected:        securityProfiles["profile2"].PodSecurityContext,
+		},
+		{
+			name:            "selected_profile_without_pod_security_context",
+			selectedProfile: "container_only",
+			expected:        &corev1.PodSecurityContext{},
+		},
+		{
+			name:            "selected_profile_with_id_ranges",
+			selectedProfile: "ranges",
+			expected: &corev1.PodSecurityContext{
+				RunAsUser:  int64Ptr(100000),
+				RunAsGroup: int64Ptr(200000),
+				FSGroup:    int64Ptr(2222),
+			},
+		},
+		{
+			name:            "selected_profile_not_existing",
+			selectedProfile: "unknown1",
+			expectedErr:     `security context profile "unknown1" does not exist`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Profiles: &stewardv1alpha1.Profiles{SecurityContext: tc.selectedProfile},
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerPodSecurityContextFSGroup: int64Ptr(3333),
+				DefaultSecurityProfile:                     tc.defaultProfile,
+				SecurityProfiles:                           securityProfiles,
+			}
+
+			// EXERCISE
+			result, err := podSecurityContext(runCtx)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+			} else {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, tc.expected, result)
+				if tc.defaultProfile != "" {
+					assert.Assert(t, result != securityProfiles[tc.defaultProfile].PodSecurityContext, "profile must be copied")
+				}
+			}
+		})
+	}
+}
+
+func Test__podSecurityContext_DefaultSeccompProfile(t *testing.T) {
+	t.Parallel()
+
+	defaultSeccompProfile := &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	localhostSeccompProfile := &corev1.SeccompProfile{
+		Type:             corev1.SeccompProfileTypeLocalhost,
+		LocalhostProfile: stringPtr("profiles/jfr.json"),
+	}
+	securityProfiles := map[string]*cfg.SecurityProfile{
+		"without_seccomp": {
+			PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: int64Ptr(1111)},
+		},
+		"with_seccomp": {
+			PodSecurityContext: &corev1.PodSecurityContext{SeccompProfile: localhostSeccompProfile},
+		},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		selectedProfile string
+		expected        *corev1.PodSecurityContext
+	}{
+		{
+			name: "no_profile",
+			expected: &corev1.PodSecurityContext{
+				FSGroup:        int64Ptr(3333),
+				SeccompProfile: defaultSeccompProfile,
+			},
+		},
+		{
+			name:            "profile_without_seccomp_profile",
+			selectedProfile: "without_seccomp",
+			expected: &corev1.PodSecurityContext{
+				RunAsUser:      int64Ptr(1111),
+				SeccompProfile: defaultSeccompProfile,
+			},
+		},
+		{
+			name:            "profile_with_seccomp_profile",
+			selectedProfile: "with_seccomp",
+			expected:        &corev1.PodSecurityContext{SeccompProfile: localhostSeccompProfile},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Profiles: &stewardv1alpha1.Profiles{SecurityContext: tc.selectedProfile},
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerPodSecurityContextFSGroup:        int64Ptr(3333),
+				JenkinsfileRunnerPodSecurityContextSeccompProfile: defaultSeccompProfile,
+				SecurityProfiles: securityProfiles,
+			}
+
+			// EXERCISE
+			result, err := podSecurityContext(runCtx)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result)
+			assert.Assert(t, result.SeccompProfile != defaultSeccompProfile, "seccomp profile must be copied")
+		})
+	}
+}
+
+func Test__runtimeClassName(t *testing.T) {
+	t.Parallel()
+
+	securityProfiles := map[string]*cfg.SecurityProfile{
+		"sandboxed": {RuntimeClassName: "gvisor"},
+		"plain":     {},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		configured      string
+		defaultProfile  string
+		selectedProfile string
+		expected        *string
+		expectedErr     string
+	}{
+		{name: "none"},
+		{name: "configured", configured: "kata", expected: stringPtr("kata")},
+		{name: "default_profile", defaultProfile: "sandboxed", expected: stringPtr("gvisor")},
+		{name: "selected_profile_overrides_configured", configured: "kata", selectedProfile: "sandboxed", expected: stringPtr("gvisor")},
+		{name: "selected_profile_without_runtime_class", configured: "kata", selectedProfile: "plain", expected: stringPtr("kata")},
+		{name: "selected_profile_not_existing", selectedProfile: "unknown1", expectedErr: `security context profile "unknown1" does not exist`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Profiles: &stewardv1alpha1.Profiles{SecurityContext: tc.selectedProfile},
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerRuntimeClassName: tc.configured,
+				DefaultSecurityProfile:            tc.defaultProfile,
+				SecurityProfiles:                  securityProfiles,
+			}
+
+			// EXERCISE
+			result, err := runtimeClassName(runCtx)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+			} else {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test__idFromRange(t *testing.T) {
+	t.Parallel()
+
+	idRange := &cfg.IDRange{Min: 100000, Max: 165535}
+	seen := map[int64]bool{}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("steward-run-%d", i)
+
+		// EXERCISE
+		id := idFromRange(idRange, key)
+
+		// VERIFY
+		assert.Assert(t, *id >= idRange.Min && *id <= idRange.Max, "id: %d", *id)
+		assert.Equal(t, *id, *idFromRange(idRange, key), "must be deterministic")
+		seen[*id] = true
+	}
+	assert.Assert(t, len(seen) > 1, "keys must be spread over the range")
+}
+
+func Test__runManager_addTektonTaskRunContainerSecurityContext(t *testing.T) {
+	t.Parallel()
 
-			// exercise
-			resultError := examinee.createTektonTaskRun(ctx, runCtx)
-			assert.NilError(t, resultError)
+	// SETUP
+	ctx := context.Background()
+	cf := newFakeClientFactory(&tektonv1beta1.ClusterTask{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "tekton.dev/v1beta1", Kind: "ClusterTask"},
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "clone-sources"}},
+				{Container: corev1.Container{
+					Name:            tektonClusterTaskJenkinsfileRunnerStep,
+					SecurityContext: &corev1.SecurityContext{Privileged: boolPtr(true)},
+				}},
+			},
+		},
+	})
+	containerSecurityContext := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: boolPtr(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+	examinee := newRunManager(cf, nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		Profiles: &stewardv1alpha1.Profiles{SecurityContext: "restricted"},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		SecurityProfiles: map[string]*cfg.SecurityProfile{
+			"restricted": {ContainerSecurityContext: containerSecurityContext},
+		},
+	}
+	tektonTaskRun := tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{
+			TaskRef: &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName},
+		},
+	}
 
-			// verify
-			taskRun := expectSingleTaskRun(t, cf, runCtx.pipelineRun)
+	// EXERCISE
+	err := examinee.addTektonTaskRunContainerSecurityContext(ctx, runCtx, &tektonTaskRun)
 
-			param := findTaskRunParam(taskRun, TaskRunParamNameIndexURL)
-			assert.Assert(t, param != nil)
-			assert.Equal(t, tektonv1beta1.ParamTypeString, param.Value.Type)
-			assert.Equal(t, "", param.Value.StringVal)
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskRef))
+	assert.Assert(t, tektonTaskRun.Spec.TaskSpec != nil)
+	for _, step := range tektonTaskRun.Spec.TaskSpec.Steps {
+		assert.DeepEqual(t, containerSecurityContext, step.SecurityContext)
+		assert.Assert(t, step.SecurityContext != containerSecurityContext, "security context must be copied")
+	}
+}
 
-			param = findTaskRunParam(taskRun, TaskRunParamNameRunIDJSON)
-			assert.Assert(t, is.Nil(param))
+func Test__runManager_addTektonTaskRunContainerSecurityContext_NoContainerSecurityContext(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee := newRunManager(newFakeClientFactory( /* no ClusterTask */ ), nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		DefaultSecurityProfile: "profile1",
+		SecurityProfiles: map[string]*cfg.SecurityProfile{
+			"profile1": {PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: int64Ptr(1111)}},
+		},
+	}
+	taskRef := &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName}
+	tektonTaskRun := tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{TaskRef: taskRef},
+	}
+
+	// EXERCISE
+	err := examinee.addTektonTaskRunContainerSecurityContext(ctx, runCtx, &tektonTaskRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, taskRef, tektonTaskRun.Spec.TaskRef)
+	assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskSpec))
+}
+
+func Test__applyExecutionProfile(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                  string
+		profiles              *stewardv1alpha1.Profiles
+		userNamespacesEnabled bool
+		expectedLabels        map[string]string
+		expectedErr           string
+	}{
+		{
+			name: "no_profiles",
+		},
+		{
+			name:     "default_profile",
+			profiles: &stewardv1alpha1.Profiles{},
+		},
+		{
+			name:                  "user_namespace",
+			profiles:              &stewardv1alpha1.Profiles{Execution: stewardv1alpha1.ExecutionProfileUserNamespace},
+			userNamespacesEnabled: true,
+			expectedLabels:        map[string]string{stewardv1alpha1.LabelHostUsers: "false"},
+		},
+		{
+			name:        "user_namespace_not_enabled",
+			profiles:    &stewardv1alpha1.Profiles{Execution: stewardv1alpha1.ExecutionProfileUserNamespace},
+			expectedErr: `execution profile "userNamespace" is not enabled in this Steward installation`,
+		},
+		{
+			name:                  "unknown_profile",
+			profiles:              &stewardv1alpha1.Profiles{Execution: "unknown1"},
+			userNamespacesEnabled: true,
+			expectedErr:           `execution profile "unknown1" does not exist`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Profiles: tc.profiles,
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				UserNamespacesEnabled: tc.userNamespacesEnabled,
+			}
+			tektonTaskRun := tektonv1beta1.TaskRun{}
+
+			// EXERCISE
+			err := applyExecutionProfile(runCtx, &tektonTaskRun)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expectedLabels, tektonTaskRun.GetLabels())
 		})
 	}
+}
+
+func Test__extendedResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	maxLimits := corev1.ResourceList{
+		"nvidia.com/gpu": k8sresource.MustParse("2"),
+	}
 
-	/**
-	 * Test: If `spec.logging.elasticsearch.indexURL` has an unsupported
-	 * scheme, or format of the URL is incorrect an error is returned.
-	 */
-	test = "ErrorOnWrongFormattedIndexURL"
 	for _, tc := range []struct {
-		name          string
-		URL           string
-		expectedError string
+		name        string
+		resources   *stewardv1alpha1.JenkinsfileRunnerResources
+		expected    corev1.ResourceList
+		expectedErr string
 	}{
-		{"indexURLWithNoScheme", `"indexURL": "testURL"`, "scheme not supported"},
-		{"indexURLWithIncorrectScheme", `"indexURL": "ftp://testURL"`, "scheme not supported"},
-		{"indexURLWithNonsenseScheme", `"indexURL": "nonsense://testURL"`, "scheme not supported"},
-		{"indexURLWithWrongFormat2", `"indexURL": "http//testURL"`, "scheme not supported"},
-		{"indexURLWithWrongFormat3", `"indexURL": ":///bar"`, "missing protocol scheme"},
-		{"indexURLWithWrongFormat4", `"indexURL": "http://\bar"`, "invalid control character in URL"},
-		{"emptyIndexURL", `"indexURL": "http://testURL:wrongPort"`, "invalid port"},
+		{
+			name: "no_resources",
+		},
+		{
+			name:      "no_limits",
+			resources: &stewardv1alpha1.JenkinsfileRunnerResources{},
+		},
+		{
+			name: "within_limits",
+			resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("2")},
+			},
+			expected: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("2")},
+		},
+		{
+			name: "exceeds_limits",
+			resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("3")},
+			},
+			expectedErr: `extended resource "nvidia.com/gpu": requested quantity 3 exceeds the maximum of 2`,
+		},
+		{
+			name: "not_available",
+			resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{"example.com/fpga": k8sresource.MustParse("1")},
+			},
+			expectedErr: `extended resource "example.com/fpga" is not available for pipeline runs`,
+		},
+		{
+			name: "not_extended",
+			resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+			},
+			expectedErr: `resource "cpu" is not an extended resource`,
+		},
 	} {
-		t.Run(test+"_"+tc.name, func(t *testing.T) {
-			// setup
-			ctx := context.Background()
-			pipelineRunJSON := fmt.Sprintf(fixIndent(`
-				{
-					"apiVersion": "steward.sap.com/v1alpha1",
-					"kind": "PipelineRun",
-					"metadata": {
-						"name": "dummy1",
-						"namespace": "namespace1"
-					},
-					"spec": {
-						"jenkinsFile": {
-							"repoUrl": "dummyRepoUrl",
-							"revision": "dummyRevision",
-							"relativePath": "dummyRelativePath"
-						},
-						"logging": {
-							"elasticsearch": {
-								%s
-							}
-						}
-					}
-				}`),
-				tc.URL,
-			)
-			t.Log("input:", pipelineRunJSON)
-			examinee, runCtx, _ := setupExaminee(t, pipelineRunJSON)
-			// exercise
-			resultError := examinee.createTektonTaskRun(ctx, runCtx)
-			assert.ErrorContains(t, resultError, tc.expectedError)
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			spec := stewardv1alpha1.PipelineSpec{}
+			if tc.resources != nil {
+				spec.JenkinsfileRunner = &stewardv1alpha1.JenkinsfileRunnerSpec{Resources: tc.resources}
+			}
+			runCtx := contextWithSpec(t, "runNamespace1", spec)
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerExtendedResourceLimits: maxLimits,
+			}
+
+			// EXERCISE
+			result, err := extendedResourceLimits(runCtx)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expected, result)
 		})
 	}
+}
+
+func Test__runManager_addTektonTaskRunExtendedResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory()
+	_, err := cf.TektonV1beta1().ClusterTasks().Create(ctx, &tektonv1beta1.ClusterTask{
+		ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+		Spec: tektonv1beta1.TaskSpec{
+			Steps: []tektonv1beta1.Step{
+				{Container: corev1.Container{Name: "other"}},
+				{Container: corev1.Container{
+					Name: tektonClusterTaskJenkinsfileRunnerStep,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("3")},
+					},
+				}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	examinee := newRunManager(cf, nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+			Resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("1")},
+			},
+		},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerExtendedResourceLimits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("1")},
+	}
+	tektonTaskRun := tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{
+			TaskRef: &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName},
+		},
+	}
+
+	// EXERCISE
+	err = examinee.addTektonTaskRunExtendedResources(ctx, runCtx, &tektonTaskRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskRef))
+	assert.Assert(t, tektonTaskRun.Spec.TaskSpec != nil)
+	assert.DeepEqual(t, corev1.ResourceList(nil), tektonTaskRun.Spec.TaskSpec.Steps[0].Resources.Limits)
+	assert.DeepEqual(t, corev1.ResourceList{
+		corev1.ResourceCPU: k8sresource.MustParse("3"),
+		"nvidia.com/gpu":   k8sresource.MustParse("1"),
+	}, tektonTaskRun.Spec.TaskSpec.Steps[1].Resources.Limits)
+}
+
+func Test__runManager_addTektonTaskRunExtendedResources_NoResources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee := newRunManager(k8sfake.NewClientFactory(), nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{}
+	taskRef := &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName}
+	tektonTaskRun := tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{TaskRef: taskRef},
+	}
+
+	// EXERCISE
+	err := examinee.addTektonTaskRunExtendedResources(ctx, runCtx, &tektonTaskRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, taskRef, tektonTaskRun.Spec.TaskRef)
+	assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskSpec))
+}
+
+func Test__runManager_addTektonTaskRunExtendedResources_ClusterTaskMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	examinee := newRunManager(k8sfake.NewClientFactory(), nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		JenkinsfileRunner: &stewardv1alpha1.JenkinsfileRunnerSpec{
+			Resources: &stewardv1alpha1.JenkinsfileRunnerResources{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("1")},
+			},
+		},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerExtendedResourceLimits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("1")},
+	}
+	tektonTaskRun := tektonv1beta1.TaskRun{}
+
+	// EXERCISE
+	err := examinee.addTektonTaskRunExtendedResources(ctx, runCtx, &tektonTaskRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to get Tekton ClusterTask "steward-jenkinsfile-runner"`)
+	assert.Assert(t, k8serrors.IsNotFound(errors.Cause(err)))
+}
+
+func Test__computeResources(t *testing.T) {
+	t.Parallel()
+
+	config := &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerMinResources: corev1.ResourceList{
+			corev1.ResourceCPU: k8sresource.MustParse("100m"),
+		},
+		JenkinsfileRunnerMaxResources: corev1.ResourceList{
+			corev1.ResourceCPU:    k8sresource.MustParse("4"),
+			corev1.ResourceMemory: k8sresource.MustParse("8Gi"),
+		},
+	}
 
-	/**
-	 * Test: `createTektonTaskRun` handles valid index URLs correctly.
-	 */
-	test = "CorrectFormatForIndexURL"
 	for _, tc := range []struct {
-		name string
-		URL  string
+		name        string
+		resources   *corev1.ResourceRequirements
+		expected    *corev1.ResourceRequirements
+		expectedErr string
+	}{
+		{
+			name: "no_resources",
+		},
+		{
+			name:      "empty_resources",
+			resources: &corev1.ResourceRequirements{},
+		},
+		{
+			name: "within_bounds",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("100m")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("4"),
+					corev1.ResourceMemory: k8sresource.MustParse("1Gi"),
+				},
+			},
+			expected: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("100m")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("4"),
+					corev1.ResourceMemory: k8sresource.MustParse("1Gi"),
+				},
+			},
+		},
+		{
+			name: "exceeds_maximum",
+			resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("16Gi")},
+			},
+			expectedErr: `spec.resources.limits: resource "memory": quantity 16Gi exceeds the maximum of 8Gi`,
+		},
+		{
+			name: "below_minimum",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("50m")},
+			},
+			expectedErr: `spec.resources.requests: resource "cpu": quantity 50m is below the minimum of 100m`,
+		},
+		{
+			name: "no_maximum",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: k8sresource.MustParse("1Gi")},
+			},
+			expectedErr: `spec.resources.requests: resource "ephemeral-storage" cannot be set for pipeline runs`,
+		},
+		{
+			name: "not_supported",
+			resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": k8sresource.MustParse("1")},
+			},
+			expectedErr: `spec.resources.limits: resource "nvidia.com/gpu" is not supported, only "cpu", "memory" and "ephemeral-storage" can be set`,
+		},
+		{
+			name: "request_exceeds_limit",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+			},
+			expectedErr: `spec.resources: resource "cpu": request 2 exceeds the limit of 1`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Resources: tc.resources,
+			})
+			runCtx.pipelineRunsConfig = config
+
+			// EXERCISE
+			result, err := computeResources(runCtx)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test__runManager_addTektonTaskRunComputeResources(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		resources *corev1.ResourceRequirements
+		expected  corev1.ResourceRequirements
 	}{
-		{"validhttpURL", `"indexURL": "http://host.domain"`},
-		{"validhttpsURL", `"indexURL": "https://host.domain"`},
-		{"validHTTPURL", `"indexURL": "HTTP://host.domain"`},
-		{"validHTTPSURL", `"indexURL": "HTTPS://host.domain"`},
+		{
+			name: "override",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: k8sresource.MustParse("4Gi")},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("1")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("3"),
+					corev1.ResourceMemory: k8sresource.MustParse("4Gi"),
+				},
+			},
+		},
+		{
+			name: "request_above_default_limit",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("4")},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("4")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("4"),
+					corev1.ResourceMemory: k8sresource.MustParse("2Gi"),
+				},
+			},
+		},
+		{
+			name: "limit_below_default_request",
+			resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("200m")},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("200m")},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("200m"),
+					corev1.ResourceMemory: k8sresource.MustParse("2Gi"),
+				},
+			},
+		},
 	} {
-		t.Run(test+"_"+tc.name, func(t *testing.T) {
-			// setup
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
 			ctx := context.Background()
-			pipelineRunJSON := fmt.Sprintf(fixIndent(`
-				{
-					"apiVersion": "steward.sap.com/v1alpha1",
-					"kind": "PipelineRun",
-					"metadata": {
-						"name": "dummy1",
-						"namespace": "namespace1"
+			cf := k8sfake.NewClientFactory()
+			_, err := cf.TektonV1beta1().ClusterTasks().Create(ctx, &tektonv1beta1.ClusterTask{
+				ObjectMeta: metav1.ObjectMeta{Name: tektonClusterTaskName},
+				Spec: tektonv1beta1.TaskSpec{
+					Steps: []tektonv1beta1.Step{
+						{Container: corev1.Container{Name: "other"}},
+						{Container: corev1.Container{
+							Name: tektonClusterTaskJenkinsfileRunnerStep,
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("500m")},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    k8sresource.MustParse("3"),
+									corev1.ResourceMemory: k8sresource.MustParse("2Gi"),
+								},
+							},
+						}},
 					},
-					"spec": {
-						"jenkinsFile": {
-							"repoUrl": "dummyRepoUrl",
-							"revision": "dummyRevision",
-							"relativePath": "dummyRelativePath"
-						},
-						"logging": {
-							"elasticsearch": {
-								%s
-							}
-						}
-					}
-				}`),
-				tc.URL,
-			)
-			t.Log("input:", pipelineRunJSON)
-			examinee, runCtx, _ := setupExaminee(t, pipelineRunJSON)
+				},
+			}, metav1.CreateOptions{})
+			assert.NilError(t, err)
 
-			// exercise
-			resultError := examinee.createTektonTaskRun(ctx, runCtx)
+			examinee := newRunManager(cf, nil)
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Resources: tc.resources,
+			})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerMaxResources: corev1.ResourceList{
+					corev1.ResourceCPU:    k8sresource.MustParse("4"),
+					corev1.ResourceMemory: k8sresource.MustParse("8Gi"),
+				},
+			}
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					TaskRef: &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName},
+				},
+			}
 
-			// verify
-			assert.NilError(t, resultError)
+			// EXERCISE
+			err = examinee.addTektonTaskRunComputeResources(ctx, runCtx, &tektonTaskRun)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskRef))
+			assert.Assert(t, tektonTaskRun.Spec.TaskSpec != nil)
+			assert.DeepEqual(t, corev1.ResourceRequirements{}, tektonTaskRun.Spec.TaskSpec.Steps[0].Resources)
+			assert.DeepEqual(t, tc.expected, tektonTaskRun.Spec.TaskSpec.Steps[1].Resources)
 		})
 	}
 }
 
-type testHelper1 struct {
-	t            *testing.T
-	ctx          context.Context
-	namespace1   string
-	pipelineRun1 string
-}
+func Test__runManager_addTektonTaskRunComputeResources_NoResources(t *testing.T) {
+	t.Parallel()
 
-func newTestHelper1(t *testing.T) *testHelper1 {
-	h := &testHelper1{
-		t:            t,
-		ctx:          context.Background(),
-		namespace1:   "namespace1",
-		pipelineRun1: "pipelinerun1",
+	// SETUP
+	ctx := context.Background()
+	examinee := newRunManager(k8sfake.NewClientFactory(), nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{}
+	taskRef := &tektonv1beta1.TaskRef{Kind: tektonv1beta1.ClusterTaskKind, Name: tektonClusterTaskName}
+	tektonTaskRun := tektonv1beta1.TaskRun{
+		Spec: tektonv1beta1.TaskRunSpec{TaskRef: taskRef},
 	}
-	return h
-}
 
-func (h *testHelper1) getPipelineRunFromStorage(cf *k8sfake.ClientFactory, namespace, name string) *stewardv1alpha1.PipelineRun {
-	t := h.t
-	t.Helper()
+	// EXERCISE
+	err := examinee.addTektonTaskRunComputeResources(ctx, runCtx, &tektonTaskRun)
 
-	pipelineRun, err := cf.StewardV1alpha1().PipelineRuns(namespace).Get(h.ctx, name, metav1.GetOptions{})
-	if err != nil {
-		t.Fatalf("could not get pipeline run %q from namespace %q: %s", name, namespace, err.Error())
-	}
-	if pipelineRun == nil {
-		t.Fatalf("could not get pipeline run %q from namespace %q: get was successfull but returned nil", name, namespace)
-	}
-	return pipelineRun
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, taskRef, tektonTaskRun.Spec.TaskRef)
+	assert.Assert(t, is.Nil(tektonTaskRun.Spec.TaskSpec))
 }
 
-func (h *testHelper1) verifyNamespace(cf *k8sfake.ClientFactory, nsName, expectedPurpose string) {
-	t := h.t
-	t.Helper()
+func Test__runManager_addTektonTaskRunComputeResources_OutOfBounds(t *testing.T) {
+	t.Parallel()
 
-	namePattern := regexp.MustCompile(
-		`^(steward-run-([[:alnum:]]{` + strconv.Itoa(runNamespaceRandomLength) + `})-` +
-			regexp.QuoteMeta(expectedPurpose) +
-			`-)[[:alnum:]]*$`)
-	assert.Assert(t, assertcmp.Regexp(namePattern, nsName))
+	// SETUP
+	ctx := context.Background()
+	examinee := newRunManager(k8sfake.NewClientFactory(), nil)
+	runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("8")},
+		},
+	})
+	runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerMaxResources: corev1.ResourceList{corev1.ResourceCPU: k8sresource.MustParse("4")},
+	}
+	tektonTaskRun := tektonv1beta1.TaskRun{}
 
-	namespace, err := cf.CoreV1().Namespaces().Get(h.ctx, nsName, metav1.GetOptions{})
-	assert.NilError(t, err)
-	assert.Equal(t, namespace.ObjectMeta.GenerateName, namePattern.FindStringSubmatch(nsName)[1])
+	// EXERCISE
+	err := examinee.addTektonTaskRunComputeResources(ctx, runCtx, &tektonTaskRun)
 
-	// labels
-	{
-		_, exists := namespace.GetLabels()[stewardv1alpha1.LabelSystemManaged]
-		assert.Assert(t, exists)
-	}
+	// VERIFY
+	assert.Error(t, err, `spec.resources.limits: resource "cpu": quantity 8 exceeds the maximum of 4`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
 }
 
-func (h *testHelper1) assertThatExactlyTheseNamespacesExist(cf *k8sfake.ClientFactory, expected ...string) {
-	t := h.t
-	t.Helper()
+func Test__applySchedulingProfile(t *testing.T) {
+	t.Parallel()
 
-	list, err := cf.CoreV1().Namespaces().List(h.ctx, metav1.ListOptions{})
-	if err != nil {
-		t.Fatal(err.Error())
-	}
-	actual := []string{}
-	for _, item := range list.Items {
-		if item.GetName() != "" {
-			actual = append(actual, item.GetName())
-		}
+	zoneConstraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
 	}
-	sort.Strings(actual)
-
-	if expected == nil {
-		expected = []string{}
+	hostConstraints := []corev1.TopologySpreadConstraint{
+		{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.DoNotSchedule},
 	}
-	{
-		temp := []string{}
-		for _, item := range expected {
-			if item != "" {
-				temp = append(temp, item)
-			}
-		}
-		expected = temp
+	config := &cfg.PipelineRunsConfigStruct{
+		JenkinsfileRunnerTopologySpreadConstraints: zoneConstraints,
+		SchedulingProfiles: map[string]*cfg.SchedulingProfile{
+			"host": {TopologySpreadConstraints: hostConstraints},
+			"none": {},
+		},
 	}
-	sort.Strings(expected)
 
-	assert.DeepEqual(t, expected, actual)
-}
+	for _, tc := range []struct {
+		name                string
+		profiles            *stewardv1alpha1.Profiles
+		config              *cfg.PipelineRunsConfigStruct
+		expectedConstraints []corev1.TopologySpreadConstraint
+		expectedErr         string
+	}{
+		{
+			name:   "no_constraints",
+			config: &cfg.PipelineRunsConfigStruct{},
+		},
+		{
+			name:                "config_default",
+			config:              config,
+			expectedConstraints: zoneConstraints,
+		},
+		{
+			name:                "default_profile",
+			profiles:            &stewardv1alpha1.Profiles{},
+			config:              config,
+			expectedConstraints: zoneConstraints,
+		},
+		{
+			name:                "profile",
+			profiles:            &stewardv1alpha1.Profiles{Scheduling: "host"},
+			config:              config,
+			expectedConstraints: hostConstraints,
+		},
+		{
+			name:     "profile_without_constraints",
+			profiles: &stewardv1alpha1.Profiles{Scheduling: "none"},
+			config:   config,
+		},
+		{
+			name:        "unknown_profile",
+			profiles:    &stewardv1alpha1.Profiles{Scheduling: "unknown1"},
+			config:      config,
+			expectedErr: `scheduling profile "unknown1" does not exist`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-func (h *testHelper1) preparePredefinedClusterRole(cf *k8smocks.MockClientFactory, pipelineRun *k8smocks.MockPipelineRun) {
-	t := h.t
-	t.Helper()
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{
+				Profiles: tc.profiles,
+			})
+			runCtx.pipelineRunsConfig = tc.config
+			tektonTaskRun := tektonv1beta1.TaskRun{}
 
-	_, err := cf.RbacV1().ClusterRoles().Create(h.ctx, k8sfake.ClusterRole(string(runClusterRoleName)), metav1.CreateOptions{})
-	if err != nil {
-		t.Fatalf("could not create cluster role: %s", err.Error())
-	}
-}
+			// EXERCISE
+			err := applySchedulingProfile(runCtx, &tektonTaskRun)
 
-func (h *testHelper1) prepareMocks(ctrl *gomock.Controller) (*k8smocks.MockClientFactory, *k8smocks.MockPipelineRun, *secretmocks.MockSecretProvider) {
-	return h.prepareMocksWithSpec(ctrl, &stewardv1alpha1.PipelineSpec{})
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			if tc.expectedConstraints == nil {
+				assert.Assert(t, is.Nil(tektonTaskRun.GetLabels()))
+				assert.Assert(t, is.Nil(tektonTaskRun.GetAnnotations()))
+			} else {
+				_, labelled := tektonTaskRun.GetLabels()[stewardv1alpha1.LabelTopologySpread]
+				assert.Assert(t, labelled)
+				var constraints []corev1.TopologySpreadConstraint
+				err = json.Unmarshal([]byte(tektonTaskRun.GetAnnotations()[stewardv1alpha1.AnnotationTopologySpreadConstraints]), &constraints)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, tc.expectedConstraints, constraints)
+			}
+		})
+	}
 }
 
-func (*testHelper1) prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewardv1alpha1.PipelineSpec) (*k8smocks.MockClientFactory, *k8smocks.MockPipelineRun, *secretmocks.MockSecretProvider) {
-	mockFactory := k8smocks.NewMockClientFactory(ctrl)
-
-	kubeClientSet := kubefake.NewSimpleClientset()
-	kubeClientSet.PrependReactor("create", "*", k8sfake.GenerateNameReactor(0))
-
-	mockFactory.EXPECT().CoreV1().Return(kubeClientSet.CoreV1()).AnyTimes()
-	mockFactory.EXPECT().RbacV1().Return(kubeClientSet.RbacV1()).AnyTimes()
-	mockFactory.EXPECT().NetworkingV1().Return(kubeClientSet.NetworkingV1()).AnyTimes()
-
-	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
-	mockFactory.EXPECT().Dynamic().Return(dynamicClient).AnyTimes()
-
-	stewardClientset := stewardfakeclient.NewSimpleClientset()
-	mockFactory.EXPECT().StewardV1alpha1().Return(stewardClientset.StewardV1alpha1()).AnyTimes()
-
-	tektonClientset := tektonfakeclient.NewSimpleClientset()
-	mockFactory.EXPECT().TektonV1beta1().Return(tektonClientset.TektonV1beta1()).AnyTimes()
+func Test__applyTerminationGracePeriod(t *testing.T) {
+	t.Parallel()
 
-	runNamespace := ""
-	auxNamespace := ""
-	mockPipelineRun := k8smocks.NewMockPipelineRun(ctrl)
-	mockPipelineRun.EXPECT().GetAPIObject().Return(&stewardv1alpha1.PipelineRun{Spec: *spec}).AnyTimes()
-	mockPipelineRun.EXPECT().GetSpec().Return(spec).AnyTimes()
-	mockPipelineRun.EXPECT().GetStatus().Return(&stewardv1alpha1.PipelineStatus{}).AnyTimes()
-	mockPipelineRun.EXPECT().GetKey().Return("key").AnyTimes()
-	mockPipelineRun.EXPECT().GetPipelineRepoServerURL().Return("server", nil).AnyTimes()
-	mockPipelineRun.EXPECT().GetRunNamespace().DoAndReturn(func() string {
-		return runNamespace
-	}).AnyTimes()
-	mockPipelineRun.EXPECT().GetAuxNamespace().DoAndReturn(func() string {
-		return auxNamespace
-	}).AnyTimes()
+	int64Ptr := func(val int64) *int64 { return &val }
 
-	mockPipelineRun.EXPECT().UpdateRunNamespace(gomock.Any()).Do(func(arg string) {
-		runNamespace = arg
-	}).MaxTimes(1)
-	mockPipelineRun.EXPECT().UpdateAuxNamespace(gomock.Any()).Do(func(arg string) {
-		auxNamespace = arg
-	}).MaxTimes(1)
-	mockPipelineRun.EXPECT().CommitStatus(gomock.Any()).MaxTimes(1)
+	for _, tc := range []struct {
+		name           string
+		seconds        *int64
+		expectedLabels map[string]string
+	}{
+		{
+			name: "not_configured",
+		},
+		{
+			name:           "configured",
+			seconds:        int64Ptr(300),
+			expectedLabels: map[string]string{stewardv1alpha1.LabelTerminationGracePeriod: "300"},
+		},
+		{
+			name:           "zero",
+			seconds:        int64Ptr(0),
+			expectedLabels: map[string]string{stewardv1alpha1.LabelTerminationGracePeriod: "0"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	mockSecretProvider := secretmocks.NewMockSecretProvider(ctrl)
+			// SETUP
+			runCtx := contextWithSpec(t, "runNamespace1", stewardv1alpha1.PipelineSpec{})
+			runCtx.pipelineRunsConfig = &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerTerminationGracePeriodSeconds: tc.seconds,
+			}
+			tektonTaskRun := tektonv1beta1.TaskRun{}
 
-	return mockFactory, mockPipelineRun, mockSecretProvider
-}
+			// EXERCISE
+			applyTerminationGracePeriod(runCtx, &tektonTaskRun)
 
-func newEmptyRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
-	return &cfg.PipelineRunsConfigStruct{}, nil
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedLabels, tektonTaskRun.GetLabels())
+		})
+	}
 }
@@ -0,0 +1,187 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// isStartAllowedByGlobalRunLimit returns whether the given pipeline run,
+// which must be in state `new` or `queued`, may be started with respect
+// to the maximum number of parallel pipeline runs in all namespaces
+// watched by the controller (see ControllerOpts.MaxParallelRuns).
+//
+// Free slots are shared fairly between namespaces, so that a tenant
+// creating many pipeline runs cannot starve other tenants. See
+// countActiveAndAheadFairShare for the order in which waiting pipeline
+// runs are started.
+//
+// Like the limit per namespace, the limit is enforced based on the
+// informer caches, i.e. it may be exceeded for a short time if pipeline
+// runs are started concurrently.
+func (c *Controller) isStartAllowedByGlobalRunLimit(pipelineRun *api.PipelineRun) (bool, error) {
+	if c.maxParallelRuns <= 0 {
+		return true, nil
+	}
+	pipelineRuns, err := c.pipelineRunLister.List(labels.Everything())
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to list pipeline runs")
+	}
+	active, ahead := countActiveAndAheadFairShare(pipelineRuns, pipelineRun)
+	return active+ahead < c.maxParallelRuns, nil
+}
+
+// countActiveAndAheadFairShare counts the pipeline runs in all given
+// namespaces that are started but not yet finished (active) and the
+// pipeline runs waiting to be started that are ranked before the given
+// pipeline run (ahead). The given pipeline run itself is excluded.
+//
+// Waiting pipeline runs are ranked by the number of active pipeline
+// runs their namespace would have if all waiting pipeline runs of the
+// namespace created before them were started. Pipeline runs with the
+// same rank are ordered by creation time. Thus free slots go to the
+// namespaces with the fewest active pipeline runs first.
+// Waiting pipeline runs held back by the limit of their namespace, by
+// their concurrency group or by quiet hours are not ranked, as they
+// cannot be started anyway.
+func countActiveAndAheadFairShare(pipelineRuns []*api.PipelineRun, self *api.PipelineRun) (active, ahead int) {
+	activeByNamespace := map[string]int{}
+	waitingByNamespace := map[string][]*api.PipelineRun{self.GetNamespace(): {self}}
+	for _, pipelineRun := range pipelineRuns {
+		if isSamePipelineRun(pipelineRun, self) || stewardlabels.IsLabelledAsIgnore(pipelineRun) {
+			continue
+		}
+		switch pipelineRun.Status.State {
+		case api.StatePreparing, api.StateWaiting, api.StateRunning:
+			active++
+			activeByNamespace[pipelineRun.GetNamespace()]++
+		case api.StateUndefined, api.StateNew, api.StateQueued:
+			if pipelineRun.GetDeletionTimestamp().IsZero() && !isQueuedByTenantLimit(pipelineRun) &&
+				!isQueuedByConcurrencyGroup(pipelineRun) && !isQueuedByQuietHours(pipelineRun) {
+				namespace := pipelineRun.GetNamespace()
+				waitingByNamespace[namespace] = append(waitingByNamespace[namespace], pipelineRun)
+			}
+		}
+	}
+
+	type rankedRun struct {
+		rank        int
+		pipelineRun *api.PipelineRun
+	}
+	var ranked []rankedRun
+	var selfRank int
+	for namespace, waiting := range waitingByNamespace {
+		sort.Slice(waiting, func(i, j int) bool {
+			return isCreatedBefore(waiting[i], waiting[j])
+		})
+		for i, pipelineRun := range waiting {
+			rank := activeByNamespace[namespace] + i
+			if isSamePipelineRun(pipelineRun, self) {
+				selfRank = rank
+				continue
+			}
+			ranked = append(ranked, rankedRun{rank: rank, pipelineRun: pipelineRun})
+		}
+	}
+	for _, other := range ranked {
+		if other.rank < selfRank || other.rank == selfRank && isCreatedBeforeInAnyNamespace(other.pipelineRun, self) {
+			ahead++
+		}
+	}
+	return
+}
+
+// isQueuedByTenantLimit returns whether the given pipeline run has been
+// queued because the maximum number of concurrent pipeline runs in its
+// namespace is reached.
+func isQueuedByTenantLimit(pipelineRun *api.PipelineRun) bool {
+	condition := apimeta.FindStatusCondition(pipelineRun.Status.Conditions, api.PipelineRunConditionQueued)
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == api.QueuedTenantLimitReason
+}
+
+// isSamePipelineRun returns whether a and b refer to the same pipeline
+// run.
+func isSamePipelineRun(a, b *api.PipelineRun) bool {
+	return a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
+
+// isCreatedBeforeInAnyNamespace is like isCreatedBefore, but orders
+// pipeline runs of different namespaces created at the same time by
+// namespace.
+func isCreatedBeforeInAnyNamespace(a, b *api.PipelineRun) bool {
+	createdA, createdB := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if createdA.Equal(&createdB) && a.GetNamespace() != b.GetNamespace() {
+		return a.GetNamespace() < b.GetNamespace()
+	}
+	return isCreatedBefore(a, b)
+}
+
+// queuePipelineRun keeps the given pipeline run, which must be in state
+// `new` or `queued`, waiting to be started for the given reason (see
+// PipelineRunConditionQueued). A pipeline run in state `new` is moved to
+// state `queued`.
+// Conditions `Queued` and `Scheduling` are set accordingly. The status is
+// only committed and an event is only emitted if the state or the reason
+// changes.
+func (c *Controller) queuePipelineRun(ctx context.Context, pipelineRun k8s.PipelineRun, reason string) error {
+	eventReason, message := api.EventReasonMaxConcurrentRunsReached,
+		"pipeline run is queued because the maximum number of concurrent pipeline runs in the namespace is reached"
+	switch reason {
+	case api.QueuedGlobalLimitReason:
+		eventReason, message = api.EventReasonMaxParallelRunsReached,
+			"pipeline run is queued because the maximum number of parallel pipeline runs of the Steward installation is reached"
+	case api.QueuedConcurrencyGroupReason:
+		eventReason, message = api.EventReasonConcurrencyGroupBusy,
+			fmt.Sprintf("pipeline run is queued because another pipeline run of concurrency group %q is in progress", pipelineRun.GetSpec().ConcurrencyGroup)
+	case api.QueuedQuietHoursReason:
+		eventReason, message = api.EventReasonQuietHours,
+			"pipeline run is queued because of the quiet hours of the Steward installation"
+	}
+
+	current := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionQueued)
+	changed := current == nil || current.Status != metav1.ConditionTrue || current.Reason != reason
+	schedulingChanged := setSchedulingDelayed(pipelineRun, reason, message)
+	if !changed && !schedulingChanged && pipelineRun.GetStatus().State == api.StateQueued {
+		return nil
+	}
+
+	if changed || pipelineRun.GetStatus().State == api.StateNew {
+		c.recorder.Event(pipelineRun.GetAPIObject(), corev1.EventTypeNormal, eventReason, message)
+		pipelineRun.UpdateCondition(metav1.Condition{
+			Type:    api.PipelineRunConditionQueued,
+			Status:  metav1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+	if pipelineRun.GetStatus().State == api.StateNew {
+		return c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateQueued, metav1.Now())
+	}
+	return c.commitStatusAndMeter(ctx, pipelineRun)
+}
+
+// dequeuePipelineRun sets conditions `Queued` and `Scheduling` of the
+// given pipeline run to false if the pipeline run has been queued or
+// delayed before. The conditions are committed together with the
+// subsequent state change.
+func (c *Controller) dequeuePipelineRun(pipelineRun k8s.PipelineRun) {
+	setSchedulingStarted(pipelineRun)
+	if apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionQueued) == nil {
+		return
+	}
+	pipelineRun.UpdateCondition(metav1.Condition{
+		Type:    api.PipelineRunConditionQueued,
+		Status:  metav1.ConditionFalse,
+		Reason:  api.QueuedStartedReason,
+		Message: "pipeline run has been started",
+	})
+}
@@ -0,0 +1,312 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_countActiveAndAheadFairShare(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	newRun := func(name, namespace string, state api.State, created int64) *api.PipelineRun {
+		run := fake.PipelineRun(name, namespace, api.PipelineSpec{})
+		run.Status.State = state
+		run.SetCreationTimestamp(metav1.Unix(created, 0))
+		return run
+	}
+	tenantLimited := newRun("tenantlimited", "ns3", api.StateQueued, 10)
+	tenantLimited.Status.Conditions = []metav1.Condition{{
+		Type:   api.PipelineRunConditionQueued,
+		Status: metav1.ConditionTrue,
+		Reason: api.QueuedTenantLimitReason,
+	}}
+	deleted := newRun("deleted", "ns3", api.StateNew, 10)
+	now := metav1.Now()
+	deleted.SetDeletionTimestamp(&now)
+	ignored := newRun("ignored", "ns3", api.StateRunning, 10)
+	ignored.SetLabels(map[string]string{api.LabelIgnore: ""})
+	runs := []*api.PipelineRun{
+		// ns1 (busy tenant): 2 active, 3 waiting
+		newRun("running1", "ns1", api.StateRunning, 1),
+		newRun("running2", "ns1", api.StatePreparing, 2),
+		newRun("waiting1", "ns1", api.StateQueued, 10),
+		newRun("waiting2", "ns1", api.StateQueued, 20),
+		newRun("self", "ns1", api.StateQueued, 30),
+		// ns2: 0 active, 2 waiting
+		newRun("waiting1", "ns2", api.StateNew, 100),
+		newRun("waiting2", "ns2", api.StateNew, 200),
+		// ns3: 1 active, not ranked
+		newRun("running1", "ns3", api.StateWaiting, 1),
+		newRun("finished", "ns3", api.StateFinished, 1),
+		newRun("cleaning", "ns3", api.StateCleaning, 1),
+		tenantLimited,
+		deleted,
+		ignored,
+	}
+
+	for _, tc := range []struct {
+		name          string
+		self          *api.PipelineRun
+		expectedAhead int
+	}{
+		// rank 4, ahead: ns1/waiting1 (2), ns1/waiting2 (3), ns2/* (0, 1)
+		{"busy_tenant", runs[4], 4},
+		// rank 0, nothing ahead
+		{"idle_tenant_first", runs[5], 0},
+		// rank 1, ahead: ns2/waiting1 (0)
+		{"idle_tenant_second", runs[6], 1},
+		// rank 2, ahead: ns2/* (0, 1)
+		{"busy_tenant_oldest", runs[2], 2},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			active, ahead := countActiveAndAheadFairShare(runs, tc.self)
+
+			// VERIFY
+			assert.Equal(t, 3, active)
+			assert.Equal(t, tc.expectedAhead, ahead)
+		})
+	}
+}
+
+func Test_countActiveAndAheadFairShare_SameRankOrderedByCreation(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	self := fake.PipelineRun("run", "ns2", api.PipelineSpec{})
+	self.SetCreationTimestamp(metav1.Unix(100, 0))
+	sameTime := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	sameTime.SetCreationTimestamp(metav1.Unix(100, 0))
+	younger := fake.PipelineRun("run", "ns3", api.PipelineSpec{})
+	younger.SetCreationTimestamp(metav1.Unix(200, 0))
+
+	// EXERCISE
+	active, ahead := countActiveAndAheadFairShare([]*api.PipelineRun{self, sameTime, younger}, self)
+
+	// VERIFY
+	assert.Equal(t, 0, active)
+	assert.Equal(t, 1, ahead)
+}
+
+func Test_Controller_isStartAllowedByGlobalRunLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		maxParallelRuns int
+		expectedAllowed bool
+	}{
+		{"no_limit", 0, true},
+		{"limit_reached", 1, false},
+		{"idle_namespace_preferred", 2, true},
+		{"above_limit", 3, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "ns2", api.PipelineSpec{})
+			run.Status.State = api.StateNew
+			run.SetCreationTimestamp(metav1.Unix(100, 0))
+			controller, cf := newController(run)
+			controller.maxParallelRuns = tc.maxParallelRuns
+			addRunToInformerStore(t, cf, run)
+			running := fake.PipelineRun("running", "ns1", api.PipelineSpec{})
+			running.Status.State = api.StateRunning
+			addRunToInformerStore(t, cf, running)
+			olderInBusyNamespace := fake.PipelineRun("older", "ns1", api.PipelineSpec{})
+			olderInBusyNamespace.Status.State = api.StateQueued
+			olderInBusyNamespace.SetCreationTimestamp(metav1.Unix(50, 0))
+			addRunToInformerStore(t, cf, olderInBusyNamespace)
+
+			// EXERCISE
+			allowed, err := controller.isStartAllowedByGlobalRunLimit(run)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAllowed, allowed)
+		})
+	}
+}
+
+func Test_Controller_isStartAllowedByRunLimit_GlobalLimit(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "tenantns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	controller.maxParallelRuns = 1
+	addRunToInformerStore(t, cf, run)
+	running := fake.PipelineRun("running", "tenantns2", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	err = cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore().Add(tenant)
+	assert.NilError(t, err)
+	pipelineRun := getPipelineRun(t, "run", "tenantns1", cf)
+
+	// EXERCISE
+	allowed, reason, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+	assert.Equal(t, api.QueuedGlobalLimitReason, reason)
+	tenant, err = cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, int32(1), tenant.Status.QueuedRuns)
+}
+
+func Test_Controller_isStartAllowedByRunLimit_TenantLimitBeforeGlobalLimit(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	controller.defaultMaxConcurrentRuns = 1
+	controller.maxParallelRuns = 1
+	addRunToInformerStore(t, cf, run)
+	running := fake.PipelineRun("running", "ns1", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	allowed, reason, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+	assert.Equal(t, api.QueuedTenantLimitReason, reason)
+}
+
+func Test_Controller_syncHandler_QueuesRunIfGlobalRunLimitReached(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	controller.maxParallelRuns = 1
+	controller.testing = &controllerTesting{
+		isMaintenanceModeStub: newIsMaintenanceModeStub(false, nil),
+	}
+	addRunToInformerStore(t, cf, run)
+	running := fake.PipelineRun("running", "ns2", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+
+	// EXERCISE
+	err := controller.syncHandler("ns1/run")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "run", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateQueued, result.Status.State)
+	condition := apimeta.FindStatusCondition(result.Status.Conditions, api.PipelineRunConditionQueued)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, api.QueuedGlobalLimitReason, condition.Reason)
+}
+
+func Test_Controller_queuePipelineRun_ReasonChanged(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateQueued
+	run.Status.Conditions = []metav1.Condition{{
+		Type:   api.PipelineRunConditionQueued,
+		Status: metav1.ConditionTrue,
+		Reason: api.QueuedTenantLimitReason,
+	}}
+	controller, cf := newController(run)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	err := controller.queuePipelineRun(ctx, pipelineRun, api.QueuedGlobalLimitReason)
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "run", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateQueued, result.Status.State)
+	condition := apimeta.FindStatusCondition(result.Status.Conditions, api.PipelineRunConditionQueued)
+	assert.Equal(t, api.QueuedGlobalLimitReason, condition.Reason)
+	condition = apimeta.FindStatusCondition(result.Status.Conditions, api.PipelineRunConditionScheduling)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, api.QueuedGlobalLimitReason, condition.Reason)
+	assert.Equal(t, "pipeline run is queued because the maximum number of parallel pipeline runs of the Steward installation is reached", condition.Message)
+}
+
+func Test_Controller_dequeuePipelineRun(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		queued            bool
+		expectedCondition bool
+	}{
+		{"queued", true, true},
+		{"not_queued", false, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+			if tc.queued {
+				run.Status.Conditions = []metav1.Condition{{
+					Type:   api.PipelineRunConditionQueued,
+					Status: metav1.ConditionTrue,
+					Reason: api.QueuedGlobalLimitReason,
+				}, {
+					Type:   api.PipelineRunConditionScheduling,
+					Status: metav1.ConditionTrue,
+					Reason: api.QueuedGlobalLimitReason,
+				}}
+			}
+			controller, cf := newController(run)
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			controller.dequeuePipelineRun(pipelineRun)
+
+			// VERIFY
+			condition := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionQueued)
+			assert.Equal(t, tc.expectedCondition, condition != nil)
+			if tc.expectedCondition {
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, api.QueuedStartedReason, condition.Reason)
+			}
+			condition = apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionScheduling)
+			assert.Equal(t, tc.expectedCondition, condition != nil)
+			if tc.expectedCondition {
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				assert.Equal(t, api.SchedulingStartedReason, condition.Reason)
+			}
+		})
+	}
+}
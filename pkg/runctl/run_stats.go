@@ -0,0 +1,52 @@
+package runctl
+
+import (
+	"context"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/runstats"
+	klog "k8s.io/klog/v2"
+)
+
+// estimateDuration sets the estimated duration of the given pipeline run
+// from the run statistics of its pipeline, if any.
+// Failures to read the statistics are logged only, as the estimation is
+// not essential for the pipeline run.
+func (c *Controller) estimateDuration(ctx context.Context, pipelineRun k8s.PipelineRun) {
+	if c.runStats == nil {
+		return
+	}
+	stats, err := c.runStats.Get(ctx, runstats.Identity(pipelineRun.GetAPIObject()))
+	if err != nil {
+		klog.Warningf("pipeline run %q: failed to get run statistics: %s", pipelineRun.GetKey(), err.Error())
+		return
+	}
+	if stats != nil {
+		pipelineRun.UpdateEstimatedDuration(stats.AverageDuration)
+	}
+}
+
+// recordRunStats adds the given finished pipeline run to the run
+// statistics of its pipeline.
+// Only pipeline runs which have been executed to the end are recorded,
+// i.e. aborted pipeline runs and pipeline runs which failed for other
+// reasons than the pipeline itself are not.
+// Failures to write the statistics are logged only.
+func (c *Controller) recordRunStats(ctx context.Context, pipelineRun k8s.PipelineRun) {
+	if c.runStats == nil {
+		return
+	}
+	status := pipelineRun.GetStatus()
+	if status.Result != api.ResultSuccess && status.Result != api.ResultErrorContent {
+		return
+	}
+	if status.StartedAt == nil || status.FinishedAt == nil {
+		return
+	}
+	duration := status.FinishedAt.Sub(status.StartedAt.Time)
+	success := status.Result == api.ResultSuccess
+	if err := c.runStats.Record(ctx, runstats.Identity(pipelineRun.GetAPIObject()), duration, success); err != nil {
+		klog.Warningf("pipeline run %q: failed to record run statistics: %s", pipelineRun.GetKey(), err.Error())
+	}
+}
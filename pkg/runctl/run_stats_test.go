@@ -0,0 +1,99 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/runstats"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_recordRunStats(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		result          api.Result
+		expectedCount   int64
+		expectedSuccess float64
+	}{
+		{"success", api.ResultSuccess, 1, 1},
+		{"error_content", api.ResultErrorContent, 1, 0},
+		{"error_infra", api.ResultErrorInfra, 0, 0},
+		{"aborted", api.ResultAborted, 0, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			startedAt := time.Date(2022, 3, 7, 12, 0, 0, 0, time.UTC)
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+			run.Status.State = api.StateFinished
+			run.Status.Result = tc.result
+			run.Status.StartedAt = &metav1.Time{Time: startedAt}
+			run.Status.FinishedAt = &metav1.Time{Time: startedAt.Add(10 * time.Minute)}
+			controller, cf := newController(run)
+			store := runstats.NewMemoryStore(0)
+			controller.runStats = store
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			controller.recordRunStats(ctx, pipelineRun)
+
+			// VERIFY
+			stats, err := store.Get(ctx, runstats.Identity(run))
+			assert.NilError(t, err)
+			if tc.expectedCount == 0 {
+				assert.Assert(t, stats == nil)
+				return
+			}
+			assert.Equal(t, tc.expectedCount, stats.Count)
+			assert.Equal(t, 10*time.Minute, stats.AverageDuration)
+			assert.Equal(t, tc.expectedSuccess, stats.SuccessRate)
+		})
+	}
+}
+
+func Test_Controller_estimateDuration(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	store := runstats.NewMemoryStore(0)
+	assert.NilError(t, store.Record(ctx, runstats.Identity(run), 7*time.Minute, true))
+	controller.runStats = store
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	controller.estimateDuration(ctx, pipelineRun)
+
+	// VERIFY
+	estimated := pipelineRun.GetStatus().EstimatedDuration
+	assert.Assert(t, estimated != nil)
+	assert.Equal(t, 7*time.Minute, estimated.Duration)
+}
+
+func Test_Controller_estimateDuration_NoStats(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	controller, cf := newController(run)
+	controller.runStats = runstats.NewMemoryStore(0)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	controller.estimateDuration(ctx, pipelineRun)
+
+	// VERIFY
+	assert.Assert(t, pipelineRun.GetStatus().EstimatedDuration == nil)
+}
@@ -241,3 +241,74 @@ func Test__GetMessage(t *testing.T) {
 		})
 	}
 }
+
+func Test__GetTestResults(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		results  string
+		expected *api.TestResults
+	}{
+		{name: "set",
+			results:  `[{"name": "test-results", "value": "{\"tests\":10,\"failures\":2,\"errors\":1,\"skipped\":3}\n"}]`,
+			expected: &api.TestResults{Tests: 10, Failures: 2, Errors: 1, Skipped: 3},
+		},
+		{name: "not_set",
+			results:  `[{"name": "foo", "value": "bar"}]`,
+			expected: nil,
+		},
+		{name: "invalid",
+			results:  `[{"name": "test-results", "value": "{no json"}]`,
+			expected: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+			t.Parallel()
+			build := fakeTektonTaskRun(fmt.Sprintf(`{"status": {"taskResults": %s}}`, test.results))
+			run := NewRun(build)
+			result := run.GetTestResults()
+			assert.DeepEqual(t, test.expected, result)
+		})
+	}
+}
+
+func Test__GetArtifacts(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		results  string
+		expected *api.ArtifactsStatus
+	}{
+		{name: "set",
+			results: `[{"name": "artifacts", "value": "2 1\nhttps://s3.example.com/b1/ns1/run1/a.jar\nhttps://s3.example.com/b1/ns1/run1/b.jar\n"}]`,
+			expected: &api.ArtifactsStatus{
+				Uploaded: 2,
+				Failed:   1,
+				URLs: []string{
+					"https://s3.example.com/b1/ns1/run1/a.jar",
+					"https://s3.example.com/b1/ns1/run1/b.jar",
+				},
+			},
+		},
+		{name: "no_files",
+			results:  `[{"name": "artifacts", "value": "0 0\n"}]`,
+			expected: &api.ArtifactsStatus{},
+		},
+		{name: "not_set",
+			results:  `[{"name": "foo", "value": "bar"}]`,
+			expected: nil,
+		},
+		{name: "invalid",
+			results:  `[{"name": "artifacts", "value": "foo"}]`,
+			expected: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test := test
+			t.Parallel()
+			build := fakeTektonTaskRun(fmt.Sprintf(`{"status": {"taskResults": %s}}`, test.results))
+			run := NewRun(build)
+			result := run.GetArtifacts()
+			assert.DeepEqual(t, test.expected, result)
+		})
+	}
+}
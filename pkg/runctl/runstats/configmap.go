@@ -0,0 +1,129 @@
+package runstats
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// configMapStore is a Store persisting statistics in a ConfigMap with
+// one data entry per pipeline identity. Statistics are cached in memory,
+// which is valid as long as only one controller instance writes to the
+// ConfigMap.
+type configMapStore struct {
+	opts Options
+	now  func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]Stats // nil until loaded
+}
+
+// NewConfigMapStore returns a Store persisting statistics in the
+// ConfigMap given by the options. The ConfigMap is created if it does
+// not exist. As the size of ConfigMaps is limited, the number of
+// pipeline identities should not exceed a few thousand.
+func NewConfigMapStore(opts Options) Store {
+	opts.MaxEntries = maxEntriesOrDefault(opts.MaxEntries)
+	return &configMapStore{opts: opts, now: time.Now}
+}
+
+// Get implements interface Store
+func (s *configMapStore) Get(ctx context.Context, identity string) (*Stats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+	if stats, ok := s.entries[identity]; ok {
+		return &stats, nil
+	}
+	return nil, nil
+}
+
+// Record implements interface Store
+func (s *configMapStore) Record(ctx context.Context, identity string, duration time.Duration, success bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	s.entries[identity] = s.entries[identity].Add(duration, success, s.now())
+	evict(s.entries, s.opts.MaxEntries)
+	return s.save(ctx)
+}
+
+// load reads the ConfigMap into the cache unless it has been loaded
+// already.
+func (s *configMapStore) load(ctx context.Context) error {
+	if s.entries != nil {
+		return nil
+	}
+	configMap, err := s.client().Get(ctx, s.opts.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		s.entries = map[string]Stats{}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to load run statistics from config map %q", s.opts.Name)
+	}
+	entries := map[string]Stats{}
+	for identity, value := range configMap.Data {
+		var stats Stats
+		if err := json.Unmarshal([]byte(value), &stats); err != nil {
+			klog.Warningf("dropping invalid run statistics %q in config map %q: %s", identity, s.opts.Name, err.Error())
+			continue
+		}
+		entries[identity] = stats
+	}
+	s.entries = entries
+	return nil
+}
+
+// save writes the cache to the ConfigMap, creating it if necessary.
+func (s *configMapStore) save(ctx context.Context) error {
+	data := make(map[string]string, len(s.entries))
+	for identity, stats := range s.entries {
+		value, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		data[identity] = string(value)
+	}
+	isConflict := func(err error) bool {
+		return k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err)
+	}
+	err := retry.OnError(ctx, retry.DefaultRetry, isConflict, func() error {
+		configMap, err := s.client().Get(ctx, s.opts.Name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: s.opts.Name, Namespace: s.opts.Namespace},
+				Data:       data,
+			}
+			_, err = s.client().Create(ctx, configMap, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		configMap = configMap.DeepCopy()
+		configMap.Data = data
+		_, err = s.client().Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	return errors.Wrapf(err, "failed to save run statistics to config map %q", s.opts.Name)
+}
+
+func (s *configMapStore) client() corev1client.ConfigMapInterface {
+	return s.opts.ConfigMaps.ConfigMaps(s.opts.Namespace)
+}
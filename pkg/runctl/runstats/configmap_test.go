@@ -0,0 +1,62 @@
+package runstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ConfigMapStore_RecordAndGet(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset()
+	opts := Options{ConfigMaps: clientset.CoreV1(), Namespace: "steward-system", Name: "run-stats"}
+	examinee := NewConfigMapStore(opts)
+
+	// EXERCISE
+	assert.NilError(t, examinee.Record(ctx, "id1", 10*time.Minute, true))
+	assert.NilError(t, examinee.Record(ctx, "id1", 20*time.Minute, false))
+
+	// VERIFY
+	configMap, err := clientset.CoreV1().ConfigMaps("steward-system").Get(ctx, "run-stats", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(configMap.Data))
+
+	// a new store loads the persisted statistics
+	stats, err := NewConfigMapStore(opts).Get(ctx, "id1")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(2), stats.Count)
+	assert.Equal(t, 12*time.Minute, stats.AverageDuration)
+}
+
+func Test_ConfigMapStore_Get_IgnoresInvalidEntries(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	clientset := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-stats", Namespace: "steward-system"},
+		Data: map[string]string{
+			"id1": `{"count":3,"averageDuration":60000000000}`,
+			"id2": `not json`,
+		},
+	})
+	examinee := NewConfigMapStore(Options{ConfigMaps: clientset.CoreV1(), Namespace: "steward-system", Name: "run-stats"})
+
+	// EXERCISE
+	stats1, err1 := examinee.Get(ctx, "id1")
+	stats2, err2 := examinee.Get(ctx, "id2")
+
+	// VERIFY
+	assert.NilError(t, err1)
+	assert.Equal(t, time.Minute, stats1.AverageDuration)
+	assert.NilError(t, err2)
+	assert.Assert(t, stats2 == nil)
+}
@@ -0,0 +1,48 @@
+package runstats
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore is a Store keeping statistics in memory only.
+type memoryStore struct {
+	mutex      sync.Mutex
+	entries    map[string]Stats
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewMemoryStore returns a Store keeping statistics in memory, i.e. they
+// are lost when the process terminates. It keeps statistics of at most
+// maxEntries pipeline identities or DefaultMaxEntries if maxEntries is
+// zero or negative.
+func NewMemoryStore(maxEntries int) Store {
+	return &memoryStore{
+		entries:    map[string]Stats{},
+		maxEntries: maxEntriesOrDefault(maxEntries),
+		now:        time.Now,
+	}
+}
+
+// Get implements interface Store
+func (s *memoryStore) Get(_ context.Context, identity string) (*Stats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if stats, ok := s.entries[identity]; ok {
+		return &stats, nil
+	}
+	return nil, nil
+}
+
+// Record implements interface Store
+func (s *memoryStore) Record(_ context.Context, identity string, duration time.Duration, success bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries[identity] = s.entries[identity].Add(duration, success, s.now())
+	evict(s.entries, s.maxEntries)
+	return nil
+}
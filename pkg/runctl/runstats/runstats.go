@@ -0,0 +1,170 @@
+package runstats
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// smoothingFactor is the weight of a new observation in the
+	// exponentially weighted moving averages of Stats.
+	smoothingFactor = 0.2
+
+	// DefaultMaxEntries is the default maximum number of pipeline
+	// identities a store keeps statistics for.
+	DefaultMaxEntries = 1000
+)
+
+// Stats are rolling statistics of the pipeline runs of one pipeline
+// identity.
+type Stats struct {
+	// Count is the number of recorded pipeline runs.
+	Count int64 `json:"count"`
+
+	// AverageDuration is the exponentially weighted moving average of
+	// the duration of the recorded pipeline runs.
+	AverageDuration time.Duration `json:"averageDuration"`
+
+	// SuccessRate is the exponentially weighted moving average of the
+	// success of the recorded pipeline runs, between 0 and 1.
+	SuccessRate float64 `json:"successRate"`
+
+	// LastUpdate is the time the latest pipeline run has been recorded.
+	LastUpdate time.Time `json:"lastUpdate"`
+}
+
+// Add returns the statistics updated with a pipeline run of the given
+// duration and success recorded at the given time.
+func (s Stats) Add(duration time.Duration, success bool, now time.Time) Stats {
+	successValue := 0.0
+	if success {
+		successValue = 1
+	}
+	if s.Count == 0 {
+		s.AverageDuration = duration
+		s.SuccessRate = successValue
+	} else {
+		s.AverageDuration += time.Duration(smoothingFactor * float64(duration-s.AverageDuration))
+		s.SuccessRate += smoothingFactor * (successValue - s.SuccessRate)
+	}
+	s.Count++
+	s.LastUpdate = now
+	return s
+}
+
+// Store persists Stats per pipeline identity (see Identity).
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the statistics of the given pipeline identity or nil
+	// if there are none.
+	Get(ctx context.Context, identity string) (*Stats, error)
+
+	// Record adds a pipeline run of the given duration and success to
+	// the statistics of the given pipeline identity.
+	Record(ctx context.Context, identity string, duration time.Duration, success bool) error
+}
+
+// Identity returns the pipeline identity of the given pipeline run.
+// Pipeline runs of the same pipeline definition in the same namespace
+// have the same identity. The identity is a valid ConfigMap key.
+func Identity(run *api.PipelineRun) string {
+	jenkinsFile := run.Spec.JenkinsFile
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s", run.GetNamespace(), jenkinsFile.URL, jenkinsFile.Path)))
+	return hex.EncodeToString(hash[:16])
+}
+
+// Options are the options of store backends.
+type Options struct {
+	// ConfigMaps is the client used by backends persisting to ConfigMaps.
+	ConfigMaps corev1client.ConfigMapsGetter
+
+	// Namespace is the namespace of the ConfigMap of backend `configmap`.
+	Namespace string
+
+	// Name is the name of the ConfigMap of backend `configmap`.
+	Name string
+
+	// MaxEntries is the maximum number of pipeline identities to keep
+	// statistics for. Statistics updated least recently are dropped
+	// first. If zero or negative, DefaultMaxEntries is used.
+	MaxEntries int
+}
+
+// BackendFactory creates a Store with the given options.
+type BackendFactory func(opts Options) (Store, error)
+
+var (
+	backendsMutex sync.Mutex
+	backends      = map[string]BackendFactory{
+		"memory": func(opts Options) (Store, error) {
+			return NewMemoryStore(opts.MaxEntries), nil
+		},
+		"configmap": func(opts Options) (Store, error) {
+			return NewConfigMapStore(opts), nil
+		},
+	}
+)
+
+// RegisterBackend registers a store backend, e.g. one persisting to an
+// external database for large installations. It is meant to be called
+// from `init()` functions of packages providing backends.
+// It panics if a backend with the same name has been registered already.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMutex.Lock()
+	defer backendsMutex.Unlock()
+
+	if name == "" || factory == nil {
+		panic("runstats: backend name and factory must not be empty")
+	}
+	if _, ok := backends[name]; ok {
+		panic(fmt.Sprintf("runstats: backend %q registered twice", name))
+	}
+	backends[name] = factory
+}
+
+// NewStore creates a Store using the backend with the given name.
+// Built-in backends are `memory`, which does not persist statistics
+// across restarts, and `configmap`.
+func NewStore(backend string, opts Options) (Store, error) {
+	backendsMutex.Lock()
+	factory, ok := backends[backend]
+	backendsMutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown run statistics backend %q", backend)
+	}
+	return factory(opts)
+}
+
+// evict removes the least recently updated entries from the given map
+// until it has at most maxEntries entries.
+func evict(entries map[string]Stats, maxEntries int) {
+	if len(entries) <= maxEntries {
+		return
+	}
+	identities := make([]string, 0, len(entries))
+	for identity := range entries {
+		identities = append(identities, identity)
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		return entries[identities[i]].LastUpdate.Before(entries[identities[j]].LastUpdate)
+	})
+	for _, identity := range identities[:len(entries)-maxEntries] {
+		delete(entries, identity)
+	}
+}
+
+func maxEntriesOrDefault(maxEntries int) int {
+	if maxEntries <= 0 {
+		return DefaultMaxEntries
+	}
+	return maxEntries
+}
@@ -0,0 +1,107 @@
+package runstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Stats_Add(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)
+	var stats Stats
+
+	// EXERCISE
+	stats = stats.Add(10*time.Minute, true, now)
+	stats = stats.Add(20*time.Minute, false, now.Add(time.Hour))
+
+	// VERIFY
+	assert.Equal(t, int64(2), stats.Count)
+	assert.Equal(t, 12*time.Minute, stats.AverageDuration)
+	assert.Equal(t, 0.8, stats.SuccessRate)
+	assert.Assert(t, now.Add(time.Hour).Equal(stats.LastUpdate))
+}
+
+func Test_Identity(t *testing.T) {
+	t.Parallel()
+
+	newRun := func(namespace, url, path, revision string) *api.PipelineRun {
+		return &api.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "run1", Namespace: namespace},
+			Spec: api.PipelineSpec{
+				JenkinsFile: api.JenkinsFile{URL: url, Path: path, Revision: revision},
+			},
+		}
+	}
+	base := Identity(newRun("ns1", "https://github.com/foo/bar", "Jenkinsfile", "main"))
+
+	for _, tc := range []struct {
+		name          string
+		run           *api.PipelineRun
+		expectedEqual bool
+	}{
+		{"other_revision", newRun("ns1", "https://github.com/foo/bar", "Jenkinsfile", "dev"), true},
+		{"other_namespace", newRun("ns2", "https://github.com/foo/bar", "Jenkinsfile", "main"), false},
+		{"other_url", newRun("ns1", "https://github.com/foo/baz", "Jenkinsfile", "main"), false},
+		{"other_path", newRun("ns1", "https://github.com/foo/bar", "ci/Jenkinsfile", "main"), false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := Identity(tc.run)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedEqual, result == base)
+			assert.Equal(t, 32, len(result))
+		})
+	}
+}
+
+func Test_NewStore_UnknownBackend(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	store, err := NewStore("foo", Options{})
+
+	// VERIFY
+	assert.Error(t, err, `unknown run statistics backend "foo"`)
+	assert.Assert(t, store == nil)
+}
+
+func Test_MemoryStore(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	now := time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)
+	examinee := NewMemoryStore(2).(*memoryStore)
+	examinee.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	// EXERCISE
+	assert.NilError(t, examinee.Record(ctx, "id1", time.Minute, true))
+	assert.NilError(t, examinee.Record(ctx, "id2", time.Minute, true))
+	assert.NilError(t, examinee.Record(ctx, "id1", time.Minute, true))
+	assert.NilError(t, examinee.Record(ctx, "id3", time.Minute, true))
+
+	// VERIFY
+	stats, err := examinee.Get(ctx, "id1")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(2), stats.Count)
+	stats, err = examinee.Get(ctx, "id2")
+	assert.NilError(t, err)
+	assert.Assert(t, stats == nil, "least recently updated entry should have been evicted")
+	stats, err = examinee.Get(ctx, "id3")
+	assert.NilError(t, err)
+	assert.Equal(t, int64(1), stats.Count)
+}
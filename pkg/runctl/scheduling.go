@@ -0,0 +1,41 @@
+package runctl
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// setSchedulingDelayed sets condition `Scheduling` of the given pipeline
+// run to true with the given reason and message, telling users why the
+// pipeline run has not been started yet.
+// Returns whether the condition has changed and must be committed.
+func setSchedulingDelayed(pipelineRun k8s.PipelineRun, reason, message string) bool {
+	current := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionScheduling)
+	if current != nil && current.Status == metav1.ConditionTrue && current.Reason == reason && current.Message == message {
+		return false
+	}
+	pipelineRun.UpdateCondition(metav1.Condition{
+		Type:    api.PipelineRunConditionScheduling,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+	return true
+}
+
+// setSchedulingStarted sets condition `Scheduling` of the given pipeline
+// run to false if the pipeline run has been delayed before. The condition
+// is committed together with the subsequent state change.
+func setSchedulingStarted(pipelineRun k8s.PipelineRun) {
+	if apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionScheduling) == nil {
+		return
+	}
+	pipelineRun.UpdateCondition(metav1.Condition{
+		Type:    api.PipelineRunConditionScheduling,
+		Status:  metav1.ConditionFalse,
+		Reason:  api.SchedulingStartedReason,
+		Message: "pipeline run has been started",
+	})
+}
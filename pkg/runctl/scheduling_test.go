@@ -0,0 +1,78 @@
+package runctl
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_setSchedulingDelayed(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		current         *metav1.Condition
+		expectedChanged bool
+	}{
+		{"no_condition", nil, true},
+		{"same", &metav1.Condition{Status: metav1.ConditionTrue, Reason: "Reason1", Message: "message1"}, false},
+		{"other_reason", &metav1.Condition{Status: metav1.ConditionTrue, Reason: "Reason2", Message: "message1"}, true},
+		{"other_message", &metav1.Condition{Status: metav1.ConditionTrue, Reason: "Reason1", Message: "message2"}, true},
+		{"started", &metav1.Condition{Status: metav1.ConditionFalse, Reason: api.SchedulingStartedReason}, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+			if tc.current != nil {
+				tc.current.Type = api.PipelineRunConditionScheduling
+				run.Status.Conditions = []metav1.Condition{*tc.current}
+			}
+			_, cf := newController(run)
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			changed := setSchedulingDelayed(pipelineRun, "Reason1", "message1")
+
+			// VERIFY
+			assert.Equal(t, tc.expectedChanged, changed)
+			condition := apimeta.FindStatusCondition(pipelineRun.GetStatus().Conditions, api.PipelineRunConditionScheduling)
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			assert.Equal(t, "Reason1", condition.Reason)
+			assert.Equal(t, "message1", condition.Message)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_SetsSchedulingConditionInMaintenanceMode(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	controller.testing = &controllerTesting{
+		isMaintenanceModeStub: newIsMaintenanceModeStub(true, nil),
+	}
+
+	// EXERCISE
+	err := controller.syncHandler("ns1/run")
+
+	// VERIFY
+	assert.Error(t, err, "pipeline execution is paused while the system is in maintenance mode")
+	result, err := getAPIPipelineRun(cf, "run", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateNew, result.Status.State)
+	condition := apimeta.FindStatusCondition(result.Status.Conditions, api.PipelineRunConditionScheduling)
+	assert.Assert(t, condition != nil)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, api.SchedulingMaintenanceModeReason, condition.Reason)
+	assert.Equal(t, "pipeline execution is paused while the system is in maintenance mode", condition.Message)
+}
@@ -0,0 +1,97 @@
+package runctl
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	secretproviders "github.com/SAP/stewardci-core/pkg/k8s/secrets/registry"
+	"github.com/pkg/errors"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// secretProvider returns the provider of the secrets referenced by the
+// given pipeline run.
+// The provider is selected via annotation AnnotationSecretProvider of the
+// pipeline run or its namespace when the first secret is requested. If
+// not set, the default provider of the controller is used.
+func (c *Controller) secretProvider(pipelineRun k8s.PipelineRun) secrets.SecretProvider {
+	defaultProviderName := c.defaultSecretProvider
+	if defaultProviderName == "" {
+		defaultProviderName = stewardv1alpha1.SecretProviderKubernetes
+	}
+	return c.withGitHubAppTokens(&selectingSecretProvider{
+		factory:             c.factory,
+		pipelineRun:         pipelineRun,
+		defaultProviderName: defaultProviderName,
+		providerConfigs:     c.secretProviderConfigs,
+	})
+}
+
+// selectingSecretProvider is a secret provider delegating to the secret
+// provider selected for a pipeline run.
+type selectingSecretProvider struct {
+	factory             k8s.ClientFactory
+	pipelineRun         k8s.PipelineRun
+	defaultProviderName string
+	providerConfigs     map[string]map[string][]byte
+
+	mutex    sync.Mutex
+	selected secrets.SecretProvider
+}
+
+// GetSecret implements secrets.SecretProvider.
+func (p *selectingSecretProvider) GetSecret(ctx context.Context, name string) (*corev1api.Secret, error) {
+	provider, err := p.selectProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetSecret(ctx, name)
+}
+
+func (p *selectingSecretProvider) selectProvider(ctx context.Context) (secrets.SecretProvider, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.selected != nil {
+		return p.selected, nil
+	}
+
+	namespaceName := p.pipelineRun.GetNamespace()
+	providerName := strings.TrimSpace(p.pipelineRun.GetAPIObject().GetAnnotations()[stewardv1alpha1.AnnotationSecretProvider])
+	if providerName == "" {
+		namespace, err := p.factory.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get namespace %q", namespaceName)
+		}
+		if err == nil {
+			providerName = strings.TrimSpace(namespace.GetAnnotations()[stewardv1alpha1.AnnotationSecretProvider])
+		}
+	}
+	if providerName == "" {
+		providerName = p.defaultProviderName
+	} else if !secretproviders.IsRegistered(providerName) {
+		return nil, serrors.Classify(
+			errors.Errorf("invalid value %q of annotation %q", providerName, stewardv1alpha1.AnnotationSecretProvider),
+			stewardv1alpha1.ResultErrorConfig,
+		)
+	}
+
+	provider, err := secretproviders.New(ctx, providerName, secretproviders.Options{
+		Client:    p.factory.CoreV1(),
+		Namespace: namespaceName,
+		Config:    p.providerConfigs[providerName],
+	})
+	if err != nil {
+		return nil, err
+	}
+	klog.V(4).Infof("using secret provider %q for pipeline run %q", providerName, p.pipelineRun.GetKey())
+	p.selected = provider
+	return p.selected, nil
+}
@@ -0,0 +1,152 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_Controller_secretProvider(t *testing.T) {
+	t.Parallel()
+
+	vault := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token1" || r.URL.Path != "/v1/secret/data/team1/secret1" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]string{"source": "vault"}},
+		})
+	}))
+	t.Cleanup(vault.Close)
+
+	tenantNamespace := func(providerAnnotation string) *corev1.Namespace {
+		namespace := k8sfake.Namespace("ns1")
+		namespace.SetLabels(map[string]string{stewardv1alpha1.LabelOwnerClientNamespace: "client1"})
+		if providerAnnotation != "" {
+			namespace.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationSecretProvider: providerAnnotation})
+		}
+		return namespace
+	}
+	clientNamespace := k8sfake.NamespaceWithAnnotations("client1", map[string]string{
+		stewardv1alpha1.AnnotationVaultSecret: "vault1",
+	})
+	vaultSecret := k8sfake.SecretOpaque("vault1", "client1")
+	vaultSecret.Data = map[string][]byte{
+		"address": []byte(vault.URL),
+		"path":    []byte("team1"),
+		"token":   []byte("token1"),
+	}
+	tenantSecret := k8sfake.SecretOpaque("secret1", "ns1")
+	tenantSecret.Data = map[string][]byte{"source": []byte("kubernetes")}
+
+	for _, tc := range []struct {
+		name             string
+		runAnnotation    string
+		defaultProvider  string
+		providerConfigs  map[string]map[string][]byte
+		objects          []runtime.Object
+		expectedSource   string
+		expectedError    string
+		expectedErrClass stewardv1alpha1.Result
+	}{
+		{
+			name:           "default",
+			objects:        []runtime.Object{tenantNamespace(""), tenantSecret},
+			expectedSource: "kubernetes",
+		},
+		{
+			name:           "vault_via_namespace",
+			objects:        []runtime.Object{tenantNamespace(stewardv1alpha1.SecretProviderVault), clientNamespace, vaultSecret, tenantSecret},
+			expectedSource: "vault",
+		},
+		{
+			name:           "vault_via_pipeline_run",
+			runAnnotation:  stewardv1alpha1.SecretProviderVault,
+			objects:        []runtime.Object{tenantNamespace(""), clientNamespace, vaultSecret, tenantSecret},
+			expectedSource: "vault",
+		},
+		{
+			name:           "pipeline_run_overrides_namespace",
+			runAnnotation:  stewardv1alpha1.SecretProviderKubernetes,
+			objects:        []runtime.Object{tenantNamespace(stewardv1alpha1.SecretProviderVault), clientNamespace, vaultSecret, tenantSecret},
+			expectedSource: "kubernetes",
+		},
+		{
+			name:            "default_provider_of_controller",
+			defaultProvider: stewardv1alpha1.SecretProviderVault,
+			providerConfigs: map[string]map[string][]byte{stewardv1alpha1.SecretProviderVault: vaultSecret.Data},
+			objects:         []runtime.Object{tenantNamespace(""), k8sfake.Namespace("client1"), tenantSecret},
+			expectedSource:  "vault",
+		},
+		{
+			name:            "namespace_overrides_default_provider",
+			defaultProvider: stewardv1alpha1.SecretProviderVault,
+			objects:         []runtime.Object{tenantNamespace(stewardv1alpha1.SecretProviderKubernetes), tenantSecret},
+			expectedSource:  "kubernetes",
+		},
+		{
+			name:             "vault_secret_not_found",
+			runAnnotation:    stewardv1alpha1.SecretProviderVault,
+			objects:          []runtime.Object{tenantNamespace(""), clientNamespace},
+			expectedError:    `secret provider "vault": failed to get Vault secret "vault1" of client namespace "client1": secrets "vault1" not found`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:             "no_vault_annotation",
+			runAnnotation:    stewardv1alpha1.SecretProviderVault,
+			objects:          []runtime.Object{tenantNamespace(""), k8sfake.Namespace("client1")},
+			expectedError:    `secret provider "vault": client namespace "client1" has no annotation "steward.sap.com/vault-secret"`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:             "unknown_provider",
+			runAnnotation:    "unknown",
+			objects:          []runtime.Object{tenantNamespace("")},
+			expectedError:    `invalid value "unknown" of annotation "steward.sap.com/secret-provider"`,
+			expectedErrClass: stewardv1alpha1.ResultErrorConfig,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := k8sfake.PipelineRun("run1", "ns1", stewardv1alpha1.PipelineSpec{})
+			if tc.runAnnotation != "" {
+				run.SetAnnotations(map[string]string{stewardv1alpha1.AnnotationSecretProvider: tc.runAnnotation})
+			}
+			examinee := &Controller{
+				factory:               newFakeClientFactory(tc.objects...),
+				defaultSecretProvider: tc.defaultProvider,
+				secretProviderConfigs: tc.providerConfigs,
+			}
+			pipelineRun, err := k8s.NewPipelineRun(ctx, run, nil)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			secret, err := examinee.secretProvider(pipelineRun).GetSecret(ctx, "secret1")
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Equal(t, tc.expectedErrClass, serrors.GetClass(err))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, secret != nil)
+			assert.Equal(t, tc.expectedSource, string(secret.Data["source"]))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package runctl
+
+import (
+	"context"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// isSecretResyncState returns whether secrets of pipeline runs in the
+// given state are watched for changes, i.e. the secrets have been or
+// are about to be copied to the run namespace, but the run has not
+// started yet.
+func isSecretResyncState(state api.State) bool {
+	return state == api.StatePreparing || state == api.StateWaiting
+}
+
+// watchSecrets starts or stops watching the secrets in the namespace of
+// the given pipeline run if secret resync is enabled.
+func (c *Controller) watchSecrets(pipelineRun *api.PipelineRun, key string) {
+	if c.secretWatcher == nil {
+		return
+	}
+	if isSecretResyncState(pipelineRun.Status.State) {
+		c.secretWatcher.Watch(pipelineRun.GetNamespace(), key)
+	} else {
+		c.unwatchSecrets(key)
+	}
+}
+
+// unwatchSecrets stops watching the secrets on behalf of the pipeline run
+// with the given key.
+func (c *Controller) unwatchSecrets(key string) {
+	if c.secretWatcher == nil {
+		return
+	}
+	c.secretWatcher.Unwatch(key)
+	c.secretResyncPending.Delete(key)
+}
+
+// onTenantSecretChange enqueues the preparing and waiting pipeline runs
+// referencing the given changed secret for the resync of their secrets.
+func (c *Controller) onTenantSecretChange(secret *corev1.Secret) {
+	pipelineRuns, err := c.pipelineRunLister.PipelineRuns(secret.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("Failed to list pipeline runs referencing changed secret %q in namespace %q: %s", secret.GetName(), secret.GetNamespace(), err.Error())
+		return
+	}
+	for _, pipelineRun := range pipelineRuns {
+		if !isSecretResyncState(pipelineRun.Status.State) || !referencesSecret(pipelineRun, secret.GetName()) {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(pipelineRun)
+		if err != nil {
+			continue
+		}
+		klog.V(4).Infof("Secret %q changed, resyncing secrets of pipeline run %q", secret.GetName(), key)
+		c.secretResyncPending.Store(key, struct{}{})
+		c.workqueue.Add(key)
+	}
+}
+
+// referencesSecret returns whether the spec of the given pipeline run
+// references a secret with the given name.
+func referencesSecret(pipelineRun *api.PipelineRun, name string) bool {
+	spec := pipelineRun.Spec
+	if spec.JenkinsFile.RepoAuthSecret == name {
+		return true
+	}
+	for _, source := range spec.Sources {
+		if source.RepoAuthSecret == name {
+			return true
+		}
+	}
+	for _, secretNames := range [][]string{spec.Secrets, spec.ImagePullSecrets} {
+		for _, secretName := range secretNames {
+			if secretName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resyncSecretsIfChanged refreshes the copies of secrets in the run
+// namespace of the given waiting pipeline run if a referenced secret has
+// changed since the last resync. Failures are reported as event. They
+// are not retried unless the secret changes again, as the run may start
+// any time.
+func (c *Controller) resyncSecretsIfChanged(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, pipelineRun k8s.PipelineRun, key string) {
+	if c.secretWatcher == nil {
+		return
+	}
+	if _, pending := c.secretResyncPending.LoadAndDelete(key); !pending {
+		return
+	}
+	runNamespace := pipelineRun.GetRunNamespace()
+	if runNamespace == "" {
+		return
+	}
+	secretHelper := secrets.NewSecretHelper(c.secretProvider(pipelineRun), runNamespace, c.factory.CoreV1().Secrets(runNamespace))
+	refreshed, err := secretHelper.RefreshSecrets(ctx)
+	if err != nil {
+		klog.Errorf("Failed to resync secrets of [%s]: %s", pipelineRun.String(), err.Error())
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonSecretResyncFailed, err.Error())
+	}
+	for _, name := range refreshed {
+		klog.V(3).Infof("Refreshed secret %q from changed source secret [%s]", name, pipelineRun.String())
+	}
+}
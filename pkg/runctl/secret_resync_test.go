@@ -0,0 +1,108 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_referencesSecret(t *testing.T) {
+	t.Parallel()
+
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		JenkinsFile:      api.JenkinsFile{RepoAuthSecret: "clone1"},
+		Sources:          []api.SourceRepository{{RepoAuthSecret: "source1"}},
+		Secrets:          []string{"secret1"},
+		ImagePullSecrets: []string{"pull1"},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		expected bool
+	}{
+		{"clone1", true},
+		{"source1", true},
+		{"secret1", true},
+		{"pull1", true},
+		{"other", false},
+		{"", false},
+	} {
+		assert.Equal(t, tc.expected, referencesSecret(run, tc.name), tc.name)
+	}
+}
+
+func Test_Controller_onTenantSecretChange(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	waiting := fake.PipelineRun("run1", "ns1", api.PipelineSpec{Secrets: []string{"secret1"}})
+	waiting.Status.State = api.StateWaiting
+	running := fake.PipelineRun("run2", "ns1", api.PipelineSpec{Secrets: []string{"secret1"}})
+	running.Status.State = api.StateRunning
+	unrelated := fake.PipelineRun("run3", "ns1", api.PipelineSpec{Secrets: []string{"secret2"}})
+	unrelated.Status.State = api.StateWaiting
+	examinee, cf := newController()
+	for _, run := range []*api.PipelineRun{waiting, running, unrelated} {
+		err := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetStore().Add(run)
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE
+	examinee.onTenantSecretChange(fake.SecretOpaque("secret1", "ns1"))
+
+	// VERIFY
+	assert.Equal(t, 1, examinee.workqueue.Len())
+	_, pending := examinee.secretResyncPending.Load("ns1/run1")
+	assert.Assert(t, pending)
+}
+
+func Test_Controller_resyncSecretsIfChanged(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{Secrets: []string{"secret1"}})
+	run.Status.State = api.StateWaiting
+	run.Status.Namespace = "runns1"
+	examinee, cf := newController(run)
+	examinee.secretWatcher = secrets.NewWatcher(cf.CoreV1(), examinee.onTenantSecretChange)
+
+	source := fake.SecretOpaque("secret1", "ns1")
+	source.Data = map[string][]byte{"key1": []byte("new")}
+	_, err := cf.CoreV1().Secrets("ns1").Create(ctx, source, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	target := fake.SecretOpaque("secret1", "runns1")
+	target.SetAnnotations(map[string]string{
+		api.AnnotationSecretSource:     "secret1",
+		api.AnnotationSecretSourceHash: "outdated",
+	})
+	target.Data = map[string][]byte{"key1": []byte("old")}
+	_, err = cf.CoreV1().Secrets("runns1").Create(ctx, target, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	pipelineRun, err := k8s.NewPipelineRun(ctx, run, cf)
+	assert.NilError(t, err)
+
+	// EXERCISE + VERIFY
+
+	// no change of a referenced secret observed
+	examinee.resyncSecretsIfChanged(ctx, run, pipelineRun, "ns1/run1")
+	secret, err := cf.CoreV1().Secrets("runns1").Get(ctx, "secret1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "old", string(secret.Data["key1"]))
+
+	// change observed
+	examinee.secretResyncPending.Store("ns1/run1", struct{}{})
+	examinee.resyncSecretsIfChanged(ctx, run, pipelineRun, "ns1/run1")
+	secret, err = cf.CoreV1().Secrets("runns1").Get(ctx, "secret1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "new", string(secret.Data["key1"]))
+	_, pending := examinee.secretResyncPending.Load("ns1/run1")
+	assert.Assert(t, !pending)
+}
@@ -2,12 +2,15 @@ package secretmgr
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/sealedsecrets"
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	klog "k8s.io/klog/v2"
 )
@@ -15,12 +18,16 @@ import (
 // SecretManager manages the serets in a run-namespace for the controller.
 type SecretManager struct {
 	secretHelper secrets.SecretHelper
+	decrypter    sealedsecrets.Decrypter
 }
 
 // NewSecretManager creates secrets in the run namesapce
-func NewSecretManager(secretHelper secrets.SecretHelper) SecretManager {
+// The decrypter is used to decrypt sealed secrets. If nil, pipeline runs
+// with sealed secrets fail with a content error.
+func NewSecretManager(secretHelper secrets.SecretHelper, decrypter sealedsecrets.Decrypter) SecretManager {
 	return SecretManager{
 		secretHelper: secretHelper,
+		decrypter:    decrypter,
 	}
 }
 
@@ -41,11 +48,24 @@ func (s SecretManager) CopyAll(ctx context.Context, pipelineRun k8s.PipelineRun)
 		return "", nil, errors.Wrap(err, "failed to copy pipeline secrets")
 	}
 
+	_, err = s.createSealedSecretsInRunNamespace(ctx, pipelineRun)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create sealed secrets")
+	}
+
 	return pipelineCloneSecretName, imagePullSecretNames, nil
 }
 
 func (s SecretManager) copyImagePullSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
-	secretNames := pipelineRun.GetSpec().ImagePullSecrets
+	return s.CopyImagePullSecrets(ctx, pipelineRun, pipelineRun.GetSpec().ImagePullSecrets)
+}
+
+// CopyImagePullSecrets copies the image pull secrets with the given names
+// to the run namespace of the given pipeline run, e.g. default image pull
+// secrets of the tenant namespace. Only secrets of type
+// `kubernetes.io/dockerconfigjson` or `kubernetes.io/dockercfg` are
+// copied. It returns the names of the copied secrets.
+func (s SecretManager) CopyImagePullSecrets(ctx context.Context, pipelineRun k8s.PipelineRun, secretNames []string) ([]string, error) {
 	transformers := []secrets.SecretTransformer{
 		secrets.StripAnnotationsTransformer("tekton.dev/"),
 		secrets.StripAnnotationsTransformer("jenkins.io/"),
@@ -68,6 +88,7 @@ func (s SecretManager) copyPipelineCloneSecretToRunNamespace(ctx context.Context
 		secrets.StripAnnotationsTransformer("jenkins.io/"),
 		secrets.StripLabelsTransformer("jenkins.io/"),
 		secrets.UniqueNameTransformer(),
+		secrets.TokenToBasicAuthTransformer(),
 		secrets.SetAnnotationTransformer("tekton.dev/git-0", repoServerURL),
 	}
 	names, err := s.copySecrets(ctx, pipelineRun, []string{secretName}, nil, transformers...)
@@ -77,6 +98,68 @@ func (s SecretManager) copyPipelineCloneSecretToRunNamespace(ctx context.Context
 	return names[0], nil
 }
 
+// CopySourceSecrets copies the auth secrets of the source repositories
+// of a pipeline run to the respective run namespace.
+// Like the pipeline clone secret, each copy is annotated as Tekton Git
+// credential for the server of the respective repository, so that it can
+// be used for cloning once attached to the service account of the run.
+// It returns the names of the copied secrets in the order of the source
+// repositories. The entry for a source repository without auth secret is
+// the empty string.
+func (s SecretManager) CopySourceSecrets(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
+	sources := pipelineRun.GetSpec().Sources
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	copied := map[string]string{}
+	result := make([]string, len(sources))
+	for i, source := range sources {
+		secretName := source.RepoAuthSecret
+		if secretName == "" {
+			continue
+		}
+		repoServerURL, err := sourceRepoServerURL(source.URL)
+		if err != nil {
+			return nil, serrors.Classify(
+				errors.Wrapf(err, "field \"spec.sources[%d].repoUrl\" has invalid value %q", i, source.URL),
+				v1alpha1.ResultErrorContent,
+			)
+		}
+		key := secretName + " " + repoServerURL
+		if _, ok := copied[key]; !ok {
+			transformers := []secrets.SecretTransformer{
+				secrets.StripAnnotationsTransformer("tekton.dev/"),
+				secrets.StripAnnotationsTransformer("jenkins.io/"),
+				secrets.StripLabelsTransformer("jenkins.io/"),
+				secrets.UniqueNameTransformer(),
+				secrets.TokenToBasicAuthTransformer(),
+				secrets.SetAnnotationTransformer("tekton.dev/git-0", repoServerURL),
+			}
+			names, err := s.copySecrets(ctx, pipelineRun, []string{secretName}, nil, transformers...)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to copy auth secret of source repository %q", source.URL)
+			}
+			copied[key] = names[0]
+		}
+		result[i] = copied[key]
+	}
+	return result, nil
+}
+
+// sourceRepoServerURL returns the scheme and host of the given repository
+// URL, e.g. `https://github.com`. Only HTTP(S) URLs are supported, as
+// auth secrets are of type basic-auth or converted to it.
+func sourceRepoServerURL(rawURL string) (string, error) {
+	repoURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if repoURL.Scheme != "http" && repoURL.Scheme != "https" {
+		return "", errors.Errorf("scheme not supported with auth secret: %q", repoURL.Scheme)
+	}
+	return repoURL.Scheme + "://" + repoURL.Host, nil
+}
+
 func (s SecretManager) copyPipelineSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
 	secretNames := pipelineRun.GetSpec().Secrets
 	transformers := []secrets.SecretTransformer{
@@ -86,6 +169,58 @@ func (s SecretManager) copyPipelineSecretsToRunNamespace(ctx context.Context, pi
 	return s.copySecrets(ctx, pipelineRun, secretNames, nil, transformers...)
 }
 
+func (s SecretManager) createSealedSecretsInRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
+	sealedSecrets := pipelineRun.GetSpec().SealedSecrets
+	if len(sealedSecrets) == 0 {
+		return nil, nil
+	}
+	if s.decrypter == nil {
+		return nil, serrors.Classify(errors.New("sealed secrets are not supported by this Steward installation"), v1alpha1.ResultErrorConfig)
+	}
+
+	var storedSecretNames []string
+	for _, sealedSecret := range sealedSecrets {
+		secret, err := s.unsealSecret(pipelineRun.GetNamespace(), sealedSecret)
+		if err != nil {
+			return storedSecretNames, serrors.Classify(err, v1alpha1.ResultErrorContent)
+		}
+		storedSecret, err := s.secretHelper.CreateSecret(ctx, secret)
+		if err != nil {
+			klog.Errorf("Cannot create sealed secret %q for [%s]. Error: %s", sealedSecret.Name, pipelineRun.String(), err)
+			if k8serrors.IsInvalid(err) || k8serrors.IsAlreadyExists(err) {
+				err = serrors.Classify(err, v1alpha1.ResultErrorContent)
+			} else {
+				err = serrors.Classify(err, v1alpha1.ResultErrorInfra)
+			}
+			return storedSecretNames, err
+		}
+		storedSecretNames = append(storedSecretNames, storedSecret.GetName())
+	}
+	return storedSecretNames, nil
+}
+
+// unsealSecret decrypts all values of the given sealed secret.
+// Sealed values are bound to the namespace of the pipeline run.
+func (s SecretManager) unsealSecret(namespace string, sealedSecret v1alpha1.SealedSecret) (*v1.Secret, error) {
+	secretType := sealedSecret.Type
+	if secretType == "" {
+		secretType = v1.SecretTypeOpaque
+	}
+	secret := &v1.Secret{
+		Type: secretType,
+		Data: make(map[string][]byte, len(sealedSecret.SealedValue)),
+	}
+	secret.SetName(sealedSecret.Name)
+	for key, sealedValue := range sealedSecret.SealedValue {
+		value, err := s.decrypter.Decrypt(namespace, sealedValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt key %q of sealed secret %q", key, sealedSecret.Name)
+		}
+		secret.Data[key] = value
+	}
+	return secret, nil
+}
+
 func (s SecretManager) copySecrets(ctx context.Context, pipelineRun k8s.PipelineRun, secretNames []string, filter secrets.SecretFilter, transformers ...secrets.SecretTransformer) ([]string, error) {
 	storedSecretNames, err := s.secretHelper.CopySecrets(ctx, secretNames, filter, transformers...)
 	if err != nil {
@@ -9,8 +9,10 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
+	"github.com/SAP/stewardci-core/pkg/sealedsecrets"
 	gomock "github.com/golang/mock/gomock"
 	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
 )
 
 type testHelper struct {
@@ -20,6 +22,7 @@ type testHelper struct {
 	imagePullSecretFilterMatcher     gomock.Matcher
 	imagePullSecretTransormerMatcher gomock.Matcher
 	cloneSecretTransormerMatcher     gomock.Matcher
+	decrypter                        sealedsecrets.Decrypter
 	spec                             *stewardv1alpha1.PipelineSpec
 }
 
@@ -30,7 +33,8 @@ func newTestHelper(t *testing.T) *testHelper {
 		pipelineSecretTransormerMatcher:  gomock.Len(2),
 		imagePullSecretFilterMatcher:     gomock.Any(),
 		imagePullSecretTransormerMatcher: gomock.Len(4),
-		cloneSecretTransormerMatcher:     gomock.Len(4),
+		cloneSecretTransormerMatcher:     gomock.Len(5),
+		decrypter:                        &fakeDecrypter{},
 
 		spec: &stewardv1alpha1.PipelineSpec{
 			JenkinsFile: stewardv1alpha1.JenkinsFile{
@@ -53,10 +57,11 @@ func mockPipelineRunWithSpec(th *testHelper) (*gomock.Controller, SecretManager,
 
 	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
 	mockSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
-	examinee := NewSecretManager(mockSecretHelper)
+	examinee := NewSecretManager(mockSecretHelper, th.decrypter)
 
 	// EXPECT
 	mockPipelineRun.EXPECT().GetSpec().Return(th.spec).AnyTimes()
+	mockPipelineRun.EXPECT().GetNamespace().Return("ns1").AnyTimes()
 	mockPipelineRun.EXPECT().String().AnyTimes() //logging
 	return mockCtrl, examinee, mockPipelineRun, mockSecretHelper
 }
@@ -122,6 +127,100 @@ func Test_copyPipelineCloneSecretToRunNamespace_FailsWithContentErrorOnGetPipeli
 	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
 }
 
+func Test_CopySourceSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.Sources = []stewardv1alpha1.SourceRepository{
+		{URL: "https://github.com/org/repo1", RepoAuthSecret: "scm_secret2"},
+		{URL: "https://github.com/org/repo2"},
+		{URL: "https://github.com/org/repo3", RepoAuthSecret: "scm_secret2"},
+		{URL: "https://git.example.com/org/repo4", RepoAuthSecret: "scm_secret2"},
+	}
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXPECT
+	// one copy per secret and repository server
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"scm_secret2"}, nil, gomock.Len(6)).
+		Return([]string{"scm-secret2-abcde"}, nil).
+		Times(1)
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"scm_secret2"}, nil, gomock.Len(6)).
+		Return([]string{"scm-secret2-fghij"}, nil).
+		Times(1)
+
+	// EXERCISE
+	names, err := examinee.CopySourceSecrets(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"scm-secret2-abcde", "", "scm-secret2-abcde", "scm-secret2-fghij"}, names)
+}
+
+func Test_CopySourceSecrets_FailsOnNonHTTPURLWithAuthSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.Sources = []stewardv1alpha1.SourceRepository{
+		{URL: "git@github.com:org/repo1.git", RepoAuthSecret: "scm_secret2"},
+	}
+	mockCtrl, examinee, mockPipelineRun, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	_, err := examinee.CopySourceSecrets(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `field "spec.sources[0].repoUrl" has invalid value "git@github.com:org/repo1.git"`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+}
+
+func Test_CopySourceSecrets_NoSources(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	names, err := examinee.CopySourceSecrets(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, names == nil)
+}
+
+func Test_CopySourceSecrets_PropagatesErrorClass(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.Sources = []stewardv1alpha1.SourceRepository{
+		{URL: "https://github.com/org/repo1", RepoAuthSecret: "foo"},
+	}
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+	expectedError := fmt.Errorf("err1")
+
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"foo"}, nil, gomock.Len(6)).Return(nil, expectedError)
+	mockSecretHelper.EXPECT().
+		IsNotFound(expectedError).Return(true)
+
+	// EXERCISE
+	_, err := examinee.CopySourceSecrets(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to copy auth secret of source repository "https://github.com/org/repo1"`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+}
+
 func Test_copyPipelineSecretsToRunNamespace(t *testing.T) {
 	t.Parallel()
 
@@ -187,3 +286,155 @@ func Test_copySecrets_FailsWithInfraErrorOnOtherError(t *testing.T) {
 	assert.Equal(t, "err1", err.Error())
 	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
 }
+
+func Test_createSealedSecretsInRunNamespace_NoSealedSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	names, err := examinee.createSealedSecretsInRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(names))
+}
+
+func Test_createSealedSecretsInRunNamespace_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.SealedSecrets = []stewardv1alpha1.SealedSecret{
+		{
+			Name: "sealed1",
+			SealedValue: map[string]string{
+				"username": "sealedUser",
+				"password": "sealedPassword",
+			},
+		},
+		{
+			Name: "sealed2",
+			Type: v1.SecretTypeBasicAuth,
+			SealedValue: map[string]string{
+				"token": "sealedToken",
+			},
+		},
+	}
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	var createdSecrets []*v1.Secret
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CreateSecret(th.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, secret *v1.Secret) (*v1.Secret, error) {
+			createdSecrets = append(createdSecrets, secret)
+			return secret, nil
+		}).
+		Times(2)
+
+	// EXERCISE
+	names, err := examinee.createSealedSecretsInRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"sealed1", "sealed2"}, names)
+	assert.Equal(t, v1.SecretTypeOpaque, createdSecrets[0].Type)
+	assert.DeepEqual(t, map[string][]byte{
+		"username": []byte("ns1:sealedUser"),
+		"password": []byte("ns1:sealedPassword"),
+	}, createdSecrets[0].Data)
+	assert.Equal(t, v1.SecretTypeBasicAuth, createdSecrets[1].Type)
+	assert.DeepEqual(t, map[string][]byte{
+		"token": []byte("ns1:sealedToken"),
+	}, createdSecrets[1].Data)
+}
+
+func Test_createSealedSecretsInRunNamespace_FailsWithContentErrorOnDecryptionError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.decrypter = &fakeDecrypter{err: fmt.Errorf("err1")}
+	th.spec.SealedSecrets = []stewardv1alpha1.SealedSecret{
+		{
+			Name:        "sealed1",
+			SealedValue: map[string]string{"key1": "value1"},
+		},
+	}
+	mockCtrl, examinee, mockPipelineRun, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	_, err := examinee.createSealedSecretsInRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to decrypt key "key1" of sealed secret "sealed1": err1`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorContent, serrors.GetClass(err))
+}
+
+func Test_createSealedSecretsInRunNamespace_FailsWithConfigErrorIfNoDecrypter(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.decrypter = nil
+	th.spec.SealedSecrets = []stewardv1alpha1.SealedSecret{
+		{
+			Name:        "sealed1",
+			SealedValue: map[string]string{"key1": "value1"},
+		},
+	}
+	mockCtrl, examinee, mockPipelineRun, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	_, err := examinee.createSealedSecretsInRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "sealed secrets are not supported")
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+}
+
+func Test_createSealedSecretsInRunNamespace_FailsWithInfraErrorOnCreateError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.SealedSecrets = []stewardv1alpha1.SealedSecret{
+		{
+			Name:        "sealed1",
+			SealedValue: map[string]string{"key1": "value1"},
+		},
+	}
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CreateSecret(th.ctx, gomock.Any()).
+		Return(nil, fmt.Errorf("err1"))
+
+	// EXERCISE
+	_, err := examinee.createSealedSecretsInRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "err1")
+	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
+}
+
+// fakeDecrypter "decrypts" values by prefixing them with the label.
+type fakeDecrypter struct {
+	err error
+}
+
+func (d *fakeDecrypter) Decrypt(label string, sealedValue string) ([]byte, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return []byte(label + ":" + sealedValue), nil
+}
@@ -0,0 +1,77 @@
+package runctl
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// statusCoalescer batches rapid successive status updates of the same
+// pipeline run by limiting non-essential status commits to one per
+// interval and pipeline run.
+// Status changes deferred by the coalescer are not lost, as they are
+// derived again from the current state of the run when the pipeline run
+// gets reconciled the next time.
+// A nil *statusCoalescer never defers status updates.
+type statusCoalescer struct {
+	clock    clock.Clock
+	interval time.Duration
+
+	mutex       sync.Mutex
+	lastCommits map[string]time.Time
+}
+
+// newStatusCoalescer creates a new statusCoalescer instance.
+// Returns nil if the interval is zero or negative.
+func newStatusCoalescer(interval time.Duration, clock clock.Clock) *statusCoalescer {
+	if interval <= 0 {
+		return nil
+	}
+	return &statusCoalescer{
+		clock:       clock,
+		interval:    interval,
+		lastCommits: map[string]time.Time{},
+	}
+}
+
+// Delay returns the time to wait before the status of the pipeline run
+// with the given key may be committed again. Returns zero if the status
+// may be committed immediately.
+func (s *statusCoalescer) Delay(key string) time.Duration {
+	if s == nil {
+		return 0
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lastCommit, found := s.lastCommits[key]
+	if !found {
+		return 0
+	}
+	delay := s.interval - s.clock.Since(lastCommit)
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// Committed records that the status of the pipeline run with the given
+// key has just been committed.
+func (s *statusCoalescer) Committed(key string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastCommits[key] = s.clock.Now()
+}
+
+// Forget removes all records of the pipeline run with the given key.
+func (s *statusCoalescer) Forget(key string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.lastCommits, key)
+}
@@ -0,0 +1,78 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"gotest.tools/assert"
+)
+
+func Test_newStatusCoalescer_Disabled(t *testing.T) {
+	t.Parallel()
+
+	for _, interval := range []time.Duration{0, -1 * time.Second} {
+		// EXERCISE
+		examinee := newStatusCoalescer(interval, clock.NewMock())
+
+		// VERIFY
+		assert.Assert(t, examinee == nil)
+		examinee.Committed("ns1/run1")
+		assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run1"))
+		examinee.Forget("ns1/run1")
+	}
+}
+
+func Test_statusCoalescer_Delay(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		committed     bool
+		elapsed       time.Duration
+		expectedDelay time.Duration
+	}{
+		{"never committed", false, 0, 0},
+		{"just committed", true, 0, 10 * time.Second},
+		{"committed recently", true, 3 * time.Second, 7 * time.Second},
+		{"interval passed exactly", true, 10 * time.Second, 0},
+		{"interval passed", true, 1 * time.Minute, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			mockClock := clock.NewMock()
+			examinee := newStatusCoalescer(10*time.Second, mockClock)
+			if tc.committed {
+				examinee.Committed("ns1/run1")
+			}
+			mockClock.Add(tc.elapsed)
+
+			// EXERCISE
+			delay := examinee.Delay("ns1/run1")
+
+			// VERIFY
+			assert.Equal(t, tc.expectedDelay, delay)
+			assert.Equal(t, time.Duration(0), examinee.Delay("ns1/other"))
+		})
+	}
+}
+
+func Test_statusCoalescer_Forget(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := newStatusCoalescer(10*time.Second, clock.NewMock())
+	examinee.Committed("ns1/run1")
+	examinee.Committed("ns1/run2")
+
+	// EXERCISE
+	examinee.Forget("ns1/run1")
+
+	// VERIFY
+	assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run1"))
+	assert.Equal(t, 10*time.Second, examinee.Delay("ns1/run2"))
+	assert.Equal(t, 1, len(examinee.lastCommits))
+}
@@ -0,0 +1,122 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// offloadStatus moves the bulky status fields of the given finished
+// pipeline run to a config map in the namespace of the pipeline run if
+// their size exceeds the configured threshold, and replaces them by a
+// reference to the config map (see api.OffloadedStatus).
+// The config map is owned by the pipeline run. If it exists already,
+// e.g. because committing the status failed before, it is overwritten.
+// Nothing is done if offloading is disabled or has been done already.
+func (c *Controller) offloadStatus(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if c.statusOffloadThresholdBytes <= 0 || pipelineRun.GetStatus().OffloadedStatus != nil {
+		return nil
+	}
+
+	data, err := offloadableStatusFields(pipelineRun.GetStatus())
+	if err != nil {
+		return err
+	}
+	size := 0
+	fields := make([]string, 0, len(data))
+	for field, value := range data {
+		size += len(value)
+		fields = append(fields, field)
+	}
+	if size <= c.statusOffloadThresholdBytes {
+		return nil
+	}
+	sort.Strings(fields)
+
+	pipelineRunAPIObj := pipelineRun.GetAPIObject()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.StatusConfigMap(pipelineRun.GetName()),
+			Namespace: pipelineRun.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipelineRunAPIObj, api.SchemeGroupVersion.WithKind("PipelineRun")),
+			},
+		},
+		Data: data,
+	}
+	slabels.LabelAsSystemManaged(configMap)
+	slabels.LabelWithInstanceID(configMap, c.factory.InstanceID())
+	if err := slabels.LabelAsOwnedByPipelineRun(configMap, pipelineRunAPIObj); err != nil {
+		return err
+	}
+
+	if err := c.createOrReplaceConfigMap(ctx, configMap); err != nil {
+		return errors.WithMessage(err, "failed to offload status")
+	}
+	klog.V(4).Infof(
+		"offloaded status fields %v of pipeline run %q (%d bytes) to config map %q",
+		fields, pipelineRun.GetKey(), size, configMap.GetName(),
+	)
+	pipelineRun.OffloadStatus(&api.OffloadedStatus{
+		ConfigMapName: configMap.GetName(),
+		Fields:        fields,
+	})
+	return nil
+}
+
+// offloadableStatusFields returns the non-empty status fields that can be
+// offloaded as config map entries.
+func offloadableStatusFields(status *api.PipelineStatus) (map[string]string, error) {
+	data := map[string]string{}
+	if status.Message != "" {
+		data[api.OffloadedStatusFieldMessage] = status.Message
+	}
+	if len(status.History) > 0 {
+		history, err := json.Marshal(status.History)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to serialize status field 'history'")
+		}
+		data[api.OffloadedStatusFieldHistory] = string(history)
+	}
+	if len(status.Stages) > 0 {
+		stages, err := json.Marshal(status.Stages)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to serialize status field 'stages'")
+		}
+		data[api.OffloadedStatusFieldStages] = string(stages)
+	}
+	return data, nil
+}
+
+// createOrReplaceConfigMap creates the given config map or replaces the
+// data of an existing config map with the same name.
+func (c *Controller) createOrReplaceConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	client := c.factory.CoreV1().ConfigMaps(configMap.GetNamespace())
+	_, err := client.Create(ctx, configMap, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create config map %q in namespace %q", configMap.GetName(), configMap.GetNamespace())
+	}
+	existing, err := client.Get(ctx, configMap.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get config map %q in namespace %q", configMap.GetName(), configMap.GetNamespace())
+	}
+	existing = existing.DeepCopy()
+	existing.Data = configMap.Data
+	if _, err := client.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to update config map %q in namespace %q", configMap.GetName(), configMap.GetNamespace())
+	}
+	return nil
+}
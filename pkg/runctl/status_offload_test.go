@@ -0,0 +1,124 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/naming"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newRunWithLargeStatus() *api.PipelineRun {
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	run.Status.Message = strings.Repeat("m", 100)
+	run.Status.MessageShort = "message1"
+	run.Status.History = []string{"history1"}
+	run.Status.Stages = []api.StageSummary{{Name: "stage1"}}
+	return run
+}
+
+func Test_Controller_offloadStatus_Disabled(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		threshold int
+	}{
+		{name: "disabled", threshold: 0},
+		{name: "below_threshold", threshold: 1000},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := newRunWithLargeStatus()
+			controller, cf := newController(run)
+			controller.statusOffloadThresholdBytes = tc.threshold
+			pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+			// EXERCISE
+			err := controller.offloadStatus(ctx, pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, err)
+			status := pipelineRun.GetStatus()
+			assert.Assert(t, status.OffloadedStatus == nil)
+			assert.Equal(t, run.Status.Message, status.Message)
+			_, err = cf.CoreV1().ConfigMaps("ns1").Get(ctx, naming.StatusConfigMap("run1"), metav1.GetOptions{})
+			assert.Assert(t, k8serrors.IsNotFound(err))
+		})
+	}
+}
+
+func Test_Controller_offloadStatus(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newRunWithLargeStatus()
+	controller, cf := newController(run)
+	controller.statusOffloadThresholdBytes = 50
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	err := controller.offloadStatus(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	configMapName := naming.StatusConfigMap("run1")
+	status := pipelineRun.GetStatus()
+	assert.DeepEqual(t, &api.OffloadedStatus{
+		ConfigMapName: configMapName,
+		Fields:        []string{"history", "message", "stages"},
+	}, status.OffloadedStatus)
+	assert.Equal(t, "", status.Message)
+	assert.Equal(t, "message1", status.MessageShort)
+	assert.Assert(t, status.History == nil)
+	assert.Assert(t, status.Stages == nil)
+
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get(ctx, configMapName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, run.Status.Message, configMap.Data["message"])
+	assert.Equal(t, `["history1"]`, configMap.Data["history"])
+	var stages []api.StageSummary
+	assert.NilError(t, json.Unmarshal([]byte(configMap.Data["stages"]), &stages))
+	assert.DeepEqual(t, run.Status.Stages, stages)
+	assert.Equal(t, 1, len(configMap.GetOwnerReferences()))
+	assert.Equal(t, "run1", configMap.GetOwnerReferences()[0].Name)
+}
+
+func Test_Controller_offloadStatus_ConfigMapExists(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newRunWithLargeStatus()
+	controller, cf := newController(run)
+	controller.statusOffloadThresholdBytes = 50
+	configMapName := naming.StatusConfigMap("run1")
+	_, err := cf.CoreV1().ConfigMaps("ns1").Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: "ns1"},
+		Data:       map[string]string{"message": "outdated"},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+	// EXERCISE
+	err = controller.offloadStatus(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	configMap, err := cf.CoreV1().ConfigMaps("ns1").Get(ctx, configMapName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, run.Status.Message, configMap.Data["message"])
+	assert.Equal(t, configMapName, pipelineRun.GetStatus().OffloadedStatus.ConfigMapName)
+}
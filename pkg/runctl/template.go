@@ -0,0 +1,164 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	equality "k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// resolvePipelineRunTemplate merges the PipelineRunTemplate referenced by
+// `spec.templateRef` of the given new pipeline run into its spec and
+// updates the pipeline run, so that the resolved spec is stored and later
+// changes of the template do not affect the pipeline run.
+// Resolving the template of a pipeline run whose spec has been resolved
+// already does not change the spec.
+// If the template does not exist or the resolved spec is invalid, the
+// pipeline run is finished with result `error_config` and false is
+// returned. Otherwise true is returned and the pipeline run can be
+// processed further.
+func (c *Controller) resolvePipelineRunTemplate(ctx context.Context, pipelineRunAPIObj *api.PipelineRun) (bool, error) {
+	namespace := pipelineRunAPIObj.GetNamespace()
+	templateName := pipelineRunAPIObj.Spec.TemplateRef.Name
+
+	template, err := c.factory.StewardV1alpha1().PipelineRunTemplates(namespace).Get(ctx, templateName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		err = fmt.Errorf("spec.templateRef: pipeline run template %q does not exist in namespace %q", templateName, namespace)
+		return false, c.finishWithTemplateError(ctx, pipelineRunAPIObj, err)
+	}
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to get pipeline run template %q in namespace %q", templateName, namespace)
+	}
+
+	spec, err := mergePipelineSpec(&template.Spec, &pipelineRunAPIObj.Spec)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to merge pipeline run template %q", templateName)
+	}
+	if err := validateResolvedPipelineSpec(spec); err != nil {
+		err = errors.WithMessagef(err, "spec resolved with pipeline run template %q", templateName)
+		return false, c.finishWithTemplateError(ctx, pipelineRunAPIObj, err)
+	}
+	if equality.Semantic.DeepEqual(spec, &pipelineRunAPIObj.Spec) {
+		return true, nil
+	}
+
+	resolved := pipelineRunAPIObj.DeepCopy()
+	resolved.Spec = *spec
+	_, err = c.factory.StewardV1alpha1().PipelineRuns(namespace).Update(ctx, resolved, metav1.UpdateOptions{})
+	if k8serrors.IsInvalid(err) {
+		err = errors.WithMessagef(err, "spec resolved with pipeline run template %q is invalid", templateName)
+		return false, c.finishWithTemplateError(ctx, pipelineRunAPIObj, err)
+	}
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to store spec resolved with pipeline run template %q", templateName)
+	}
+	klog.V(3).Infof("Resolved pipeline run template %q of pipeline run %s/%s", templateName, namespace, pipelineRunAPIObj.GetName())
+	return true, nil
+}
+
+// finishWithTemplateError finishes the given pipeline run with result
+// `error_config` because its template could not be resolved.
+func (c *Controller) finishWithTemplateError(ctx context.Context, pipelineRunAPIObj *api.PipelineRun, err error) error {
+	pipelineRun, getErr := k8s.NewPipelineRunWithOpts(ctx, pipelineRunAPIObj, c.factory, c.pipelineRunOpts)
+	if getErr != nil || pipelineRun == nil {
+		return getErr
+	}
+	c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
+	pipelineRun.StoreErrorAsMessage(err, "preparing failed")
+	return c.updateStateAndResult(ctx, pipelineRun, api.StateFinished, api.ResultErrorConfig, metav1.Now())
+}
+
+// mergePipelineSpec returns the spec of a pipeline run resulting from the
+// given template spec and the given spec of the pipeline run.
+// Fields of the pipeline run spec take precedence over the template,
+// objects like `args` are merged recursively. Fields set to an empty
+// value count as omitted. Lists are replaced, except `secrets` and
+// `imagePullSecrets`, which are added to those of the template.
+// Fields `templateRef` and `triggeredBy` of the template are ignored.
+func mergePipelineSpec(template, spec *api.PipelineSpec) (*api.PipelineSpec, error) {
+	merged, err := toJSONObject(template)
+	if err != nil {
+		return nil, err
+	}
+	override, err := toJSONObject(spec)
+	if err != nil {
+		return nil, err
+	}
+	mergeJSONObjects(merged, override)
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.PipelineSpec{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	result.Secrets = appendMissing(template.Secrets, spec.Secrets)
+	result.ImagePullSecrets = appendMissing(template.ImagePullSecrets, spec.ImagePullSecrets)
+	result.TemplateRef = spec.TemplateRef.DeepCopy()
+	result.TriggeredBy = spec.TriggeredBy.DeepCopy()
+	return result, nil
+}
+
+// validateResolvedPipelineSpec checks the fields of a resolved spec which
+// are optional for pipeline runs referring to a template only.
+func validateResolvedPipelineSpec(spec *api.PipelineSpec) error {
+	if spec.JenkinsFile.URL == "" || spec.JenkinsFile.Revision == "" || spec.JenkinsFile.Path == "" {
+		return fmt.Errorf("spec.jenkinsFile: fields repoUrl, revision and relativePath must not be empty")
+	}
+	return nil
+}
+
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// mergeJSONObjects sets the non-empty values of `override` in `base`,
+// merging nested objects recursively.
+func mergeJSONObjects(base, override map[string]interface{}) {
+	for key, value := range override {
+		if value == nil || value == "" {
+			continue
+		}
+		if overrideObject, ok := value.(map[string]interface{}); ok {
+			if baseObject, ok := base[key].(map[string]interface{}); ok {
+				mergeJSONObjects(baseObject, overrideObject)
+				continue
+			}
+		}
+		base[key] = value
+	}
+}
+
+// appendMissing returns the entries of `base` followed by the entries
+// of `additional` not contained in `base`.
+func appendMissing(base, additional []string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, list := range [][]string{base, additional} {
+		for _, entry := range list {
+			if !seen[entry] {
+				seen[entry] = true
+				result = append(result, entry)
+			}
+		}
+	}
+	return result
+}
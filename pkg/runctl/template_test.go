@@ -0,0 +1,151 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTemplateTestSpec() api.PipelineSpec {
+	return api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://git.example.com/org/repo.git",
+			Revision: "main",
+			Path:     "Jenkinsfile",
+		},
+		Args:             map[string]string{"arg1": "template1", "arg2": "template2"},
+		Secrets:          []string{"secret1", "secret2"},
+		ImagePullSecrets: []string{"pull1"},
+	}
+}
+
+func Test_mergePipelineSpec(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	template := newTemplateTestSpec()
+	spec := api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{Revision: "feature1"},
+		Args:        map[string]string{"arg2": "run2", "arg3": "run3"},
+		Secrets:     []string{"secret2", "secret3"},
+		TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+	}
+
+	// EXERCISE
+	result, err := mergePipelineSpec(&template, &spec)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{
+			URL:      "https://git.example.com/org/repo.git",
+			Revision: "feature1",
+			Path:     "Jenkinsfile",
+		},
+		Args:             map[string]string{"arg1": "template1", "arg2": "run2", "arg3": "run3"},
+		Secrets:          []string{"secret1", "secret2", "secret3"},
+		ImagePullSecrets: []string{"pull1"},
+		TemplateRef:      &api.PipelineRunTemplateRef{Name: "template1"},
+	}, result)
+
+	// merging again does not change the result
+	again, err := mergePipelineSpec(&template, result)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, again)
+}
+
+func Test_mergePipelineSpec_IgnoresTriggeredByOfTemplate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	template := newTemplateTestSpec()
+	template.TriggeredBy = &api.TriggeredBy{Type: "type1", ID: "id1"}
+	spec := api.PipelineSpec{TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"}}
+
+	// EXERCISE
+	result, err := mergePipelineSpec(&template, &spec)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, result.TriggeredBy == nil)
+}
+
+func Test_Controller_resolvePipelineRunTemplate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Args:        map[string]string{"arg1": "run1"},
+		TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+	})
+	controller, cf := newController(run)
+	template := fake.PipelineRunTemplate("template1", "ns1", newTemplateTestSpec())
+	_, err := cf.StewardV1alpha1().PipelineRunTemplates("ns1").Create(ctx, template, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	resolved, err := controller.resolvePipelineRunTemplate(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, resolved)
+	updated, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "https://git.example.com/org/repo.git", updated.Spec.JenkinsFile.URL)
+	assert.DeepEqual(t, map[string]string{"arg1": "run1", "arg2": "template2"}, updated.Spec.Args)
+	assert.Equal(t, "template1", updated.Spec.TemplateRef.Name)
+}
+
+func Test_Controller_resolvePipelineRunTemplate_TemplateNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+	})
+	controller, cf := newController(run)
+
+	// EXERCISE
+	resolved, err := controller.resolvePipelineRunTemplate(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !resolved)
+	updated, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, updated.Status.State)
+	assert.Equal(t, api.ResultErrorConfig, updated.Status.Result)
+	assert.Assert(t, updated.Status.Message != "")
+}
+
+func Test_Controller_resolvePipelineRunTemplate_Incomplete(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		TemplateRef: &api.PipelineRunTemplateRef{Name: "template1"},
+	})
+	controller, cf := newController(run)
+	spec := newTemplateTestSpec()
+	spec.JenkinsFile.Revision = ""
+	template := fake.PipelineRunTemplate("template1", "ns1", spec)
+	_, err := cf.StewardV1alpha1().PipelineRunTemplates("ns1").Create(ctx, template, metav1.CreateOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	resolved, err := controller.resolvePipelineRunTemplate(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !resolved)
+	updated, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultErrorConfig, updated.Status.Result)
+}
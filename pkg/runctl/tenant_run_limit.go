@@ -0,0 +1,230 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// isStartAllowedByRunLimit returns whether the given pipeline run, which
+// must be in state `new` or `queued`, may be started with respect to the
+// maximum number of concurrent pipeline runs in its namespace (see
+// maxConcurrentRuns) and the maximum number of parallel pipeline runs of
+// the controller (see isStartAllowedByGlobalRunLimit). Pipeline runs
+// waiting to be started in a namespace are started in FIFO order, i.e. a
+// pipeline run may only be started if the limit is not reached by the
+// active pipeline runs and the waiting pipeline runs that have been
+// created before it.
+// If the start is not allowed, the reason of condition `Queued` is
+// returned, too.
+// As a side effect, `status.queuedRuns` of the tenant owning the
+// namespace gets updated.
+//
+// The limit is enforced based on the informer caches, i.e. it may be
+// exceeded for a short time if pipeline runs are started concurrently.
+// In namespace-scoped mode the tenant is only found if the client
+// namespace it belongs to is watched, too. Otherwise only the namespace
+// annotation and the default limit are enforced.
+func (c *Controller) isStartAllowedByRunLimit(ctx context.Context, pipelineRun k8s.PipelineRun) (bool, string, error) {
+	tenant, err := c.findTenantByTenantNamespace(pipelineRun.GetNamespace())
+	if err != nil {
+		return false, "", err
+	}
+	pipelineRuns, err := c.pipelineRunLister.PipelineRuns(pipelineRun.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return false, "", errors.WithMessagef(err,
+			"failed to list pipeline runs in namespace %q", pipelineRun.GetNamespace(),
+		)
+	}
+	active, queued, ahead := countActiveAndQueuedRuns(pipelineRuns, pipelineRun.GetAPIObject())
+
+	// Without other active or queued pipeline runs in the namespace
+	// the limit can neither be reached nor cause a backlog.
+	limit := 0
+	if active+queued > 0 {
+		limit, err = c.maxConcurrentRuns(pipelineRun.GetNamespace(), tenant)
+		if err != nil {
+			return false, "", err
+		}
+	}
+	if limit <= 0 && c.maxParallelRuns <= 0 {
+		return true, "", c.updateTenantQueuedRuns(ctx, tenant, 0)
+	}
+
+	reason := ""
+	if limit > 0 && active+ahead >= limit {
+		reason = api.QueuedTenantLimitReason
+	} else {
+		allowed, err := c.isStartAllowedByGlobalRunLimit(pipelineRun.GetAPIObject())
+		if err != nil {
+			return false, "", err
+		}
+		if !allowed {
+			reason = api.QueuedGlobalLimitReason
+		}
+	}
+	if reason != "" {
+		queued++
+	}
+	return reason == "", reason, c.updateTenantQueuedRuns(ctx, tenant, queued)
+}
+
+// maxConcurrentRuns returns the maximum number of concurrent pipeline
+// runs in the given namespace, where zero means no limit. It is defined
+// by the first of:
+//
+//   - annotation `steward.sap.com/max-concurrent-runs` of the namespace,
+//   - `spec.maxConcurrentRuns` of the given tenant owning the namespace,
+//     if greater than zero,
+//   - the default of the controller.
+//
+// Invalid annotation values are ignored. The namespace is read from the
+// informer cache.
+func (c *Controller) maxConcurrentRuns(namespace string, tenant *api.Tenant) (int, error) {
+	ns, err := c.namespaceLister.Get(namespace)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return 0, errors.WithMessagef(err, "failed to get namespace %q", namespace)
+	}
+	if err == nil {
+		if value, found := ns.GetAnnotations()[api.AnnotationMaxConcurrentRuns]; found {
+			limit, err := strconv.Atoi(value)
+			if err == nil && limit >= 0 {
+				return limit, nil
+			}
+			klog.V(3).Infof("Ignoring invalid value %q of annotation %q of namespace %q", value, api.AnnotationMaxConcurrentRuns, namespace)
+		}
+	}
+	if tenant != nil && tenant.Spec.MaxConcurrentRuns > 0 {
+		return int(tenant.Spec.MaxConcurrentRuns), nil
+	}
+	return c.defaultMaxConcurrentRuns, nil
+}
+
+// newNamespaceInformer returns an informer for all namespaces, which
+// provides the annotations of tenant namespaces, e.g.
+// `steward.sap.com/max-concurrent-runs`, without querying the Kubernetes
+// API server for each pipeline run.
+func newNamespaceInformer(factory k8s.ClientFactory) cache.SharedIndexInformer {
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return factory.CoreV1().Namespaces().List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return factory.CoreV1().Namespaces().Watch(context.TODO(), options)
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &corev1.Namespace{}, 0, cache.Indexers{})
+}
+
+// countActiveAndQueuedRuns counts the pipeline runs that are started but
+// not yet finished (active), the pipeline runs that are not yet started
+// (queued) and the queued pipeline runs that have been created before
+// the given pipeline run (ahead). Pipeline runs held back by their
+// concurrency group are not counted as ahead, as they cannot be started
+// anyway. The given pipeline run itself is excluded.
+func countActiveAndQueuedRuns(pipelineRuns []*api.PipelineRun, self *api.PipelineRun) (active, queued, ahead int) {
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.GetName() == self.GetName() || stewardlabels.IsLabelledAsIgnore(pipelineRun) {
+			continue
+		}
+		switch pipelineRun.Status.State {
+		case api.StatePreparing, api.StateWaiting, api.StateRunning:
+			active++
+		case api.StateUndefined, api.StateNew, api.StateQueued:
+			if pipelineRun.GetDeletionTimestamp().IsZero() {
+				queued++
+				if isCreatedBefore(pipelineRun, self) && !isQueuedByConcurrencyGroup(pipelineRun) {
+					ahead++
+				}
+			}
+		}
+	}
+	return
+}
+
+// isCreatedBefore returns whether pipeline run a has been created before
+// pipeline run b. Pipeline runs created at the same time are ordered by
+// name.
+func isCreatedBefore(a, b *api.PipelineRun) bool {
+	createdA, createdB := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !createdA.Equal(&createdB) {
+		return createdA.Before(&createdB)
+	}
+	return a.GetName() < b.GetName()
+}
+
+// findTenantByTenantNamespace returns the tenant whose tenant namespace
+// is the given namespace or nil if there is no such tenant.
+func (c *Controller) findTenantByTenantNamespace(namespace string) (*api.Tenant, error) {
+	tenants, err := c.tenantsByNamespace(namespace)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get tenants from index")
+	}
+	if len(tenants) == 0 {
+		return nil, nil
+	}
+	return tenants[0], nil
+}
+
+// updateTenantQueuedRuns sets `status.queuedRuns` of the given tenant,
+// if any.
+// The tenant is patched, so that the request does not fail if the given
+// tenant object from the informer cache is outdated.
+func (c *Controller) updateTenantQueuedRuns(ctx context.Context, tenant *api.Tenant, queued int) error {
+	if tenant == nil || int(tenant.Status.QueuedRuns) == queued {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"queuedRuns": queued,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		_, err := c.factory.StewardV1alpha1().Tenants(tenant.GetNamespace()).Patch(
+			ctx, tenant.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}, "status",
+		)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessagef(err,
+			"failed to update status of tenant %q in namespace %q", tenant.GetName(), tenant.GetNamespace(),
+		)
+	}
+	klog.V(4).Infof("Updated queued runs of tenant %s/%s to %d", tenant.GetNamespace(), tenant.GetName(), queued)
+	return nil
+}
+
+// enqueueQueuedPipelineRuns adds all pipeline runs in the given namespace
+// (all namespaces if metav1.NamespaceAll) that are not yet started to the
+// work queue, e.g. because a pipeline run has finished and a queued one
+// may be started now.
+func (c *Controller) enqueueQueuedPipelineRuns(namespace string) {
+	pipelineRuns, err := c.pipelineRunLister.PipelineRuns(namespace).List(labels.Everything())
+	if err != nil {
+		klog.V(3).Infof("Failed to list pipeline runs in namespace %q: %s", namespace, err.Error())
+		return
+	}
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.Status.State == api.StateNew || pipelineRun.Status.State == api.StateQueued {
+			c.addPipelineRun(pipelineRun)
+		}
+	}
+}
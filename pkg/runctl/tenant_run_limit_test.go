@@ -0,0 +1,327 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_countActiveAndQueuedRuns(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	newRun := func(name string, state api.State) *api.PipelineRun {
+		run := fake.PipelineRun(name, "ns1", api.PipelineSpec{})
+		run.Status.State = state
+		return run
+	}
+	deleted := newRun("deleted", api.StateNew)
+	now := metav1.Now()
+	deleted.SetDeletionTimestamp(&now)
+	ignored := newRun("ignored", api.StateRunning)
+	ignored.SetLabels(map[string]string{api.LabelIgnore: ""})
+	self := newRun("self", api.StateQueued)
+	self.SetCreationTimestamp(metav1.Unix(100, 0))
+	older := newRun("older", api.StateQueued)
+	older.SetCreationTimestamp(metav1.Unix(50, 0))
+	sameTimeLowerName := newRun("same", api.StateNew)
+	sameTimeLowerName.SetCreationTimestamp(metav1.Unix(100, 0))
+	younger := newRun("younger", api.StateNew)
+	younger.SetCreationTimestamp(metav1.Unix(200, 0))
+	runs := []*api.PipelineRun{
+		self,
+		older,
+		sameTimeLowerName,
+		younger,
+		newRun("preparing", api.StatePreparing),
+		newRun("waiting", api.StateWaiting),
+		newRun("running", api.StateRunning),
+		newRun("cleaning", api.StateCleaning),
+		newRun("finished", api.StateFinished),
+		deleted,
+		ignored,
+	}
+
+	// EXERCISE
+	active, queued, ahead := countActiveAndQueuedRuns(runs, self)
+
+	// VERIFY
+	assert.Equal(t, 3, active)
+	assert.Equal(t, 3, queued)
+	assert.Equal(t, 2, ahead)
+}
+
+func Test_Controller_isStartAllowedByRunLimit(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		maxConcurrentRuns  int32
+		otherRunStates     []api.State
+		olderQueuedRuns    int
+		expectedAllowed    bool
+		expectedQueuedRuns int32
+	}{
+		{"no_limit", 0, []api.State{api.StateRunning, api.StateNew}, 0, true, 0},
+		{"below_limit", 2, []api.State{api.StateRunning, api.StateNew}, 0, true, 1},
+		{"limit_reached", 1, []api.State{api.StateRunning, api.StateNew}, 0, false, 2},
+		{"finished_runs_not_counted", 1, []api.State{api.StateFinished}, 0, true, 0},
+		{"older_queued_runs_first", 2, []api.State{api.StateRunning}, 1, false, 2},
+		{"older_queued_runs_below_limit", 3, []api.State{api.StateRunning}, 1, true, 1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := fake.PipelineRun("run", "tenantns1", api.PipelineSpec{})
+			run.Status.State = api.StateNew
+			run.SetCreationTimestamp(metav1.Unix(100, 0))
+			controller, cf := newController(run)
+			addRunToInformerStore(t, cf, run)
+			for i, state := range tc.otherRunStates {
+				other := fake.PipelineRun(string(rune('a'+i)), "tenantns1", api.PipelineSpec{})
+				other.Status.State = state
+				other.SetCreationTimestamp(metav1.Unix(200, 0))
+				addRunToInformerStore(t, cf, other)
+			}
+			for i := 0; i < tc.olderQueuedRuns; i++ {
+				older := fake.PipelineRun(fmt.Sprintf("older%d", i), "tenantns1", api.PipelineSpec{})
+				older.Status.State = api.StateQueued
+				older.SetCreationTimestamp(metav1.Unix(50, 0))
+				addRunToInformerStore(t, cf, older)
+			}
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec.MaxConcurrentRuns = tc.maxConcurrentRuns
+			tenant.Status.TenantNamespaceName = "tenantns1"
+			tenant.Status.QueuedRuns = 5
+			tenant, err := cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+			assert.NilError(t, err)
+			err = cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore().Add(tenant)
+			assert.NilError(t, err)
+			pipelineRun := getPipelineRun(t, "run", "tenantns1", cf)
+
+			// EXERCISE
+			allowed, _, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAllowed, allowed)
+			tenant, err = cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedQueuedRuns, tenant.Status.QueuedRuns)
+		})
+	}
+}
+
+func Test_Controller_isStartAllowedByRunLimit_NoTenant(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	allowed, _, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, allowed)
+}
+
+func Test_Controller_isStartAllowedByRunLimit_DefaultLimitWithoutTenant(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	controller.defaultMaxConcurrentRuns = 1
+	running := fake.PipelineRun("running", "ns1", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	allowed, _, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+}
+
+func Test_Controller_isStartAllowedByRunLimit_NamespaceAnnotation(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "ns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	controller, cf := newController(run)
+	namespace := fake.Namespace("ns1")
+	namespace.SetAnnotations(map[string]string{api.AnnotationMaxConcurrentRuns: "1"})
+	err := controller.namespaceInformer.GetStore().Add(namespace)
+	assert.NilError(t, err)
+	running := fake.PipelineRun("running", "ns1", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+	pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+	// EXERCISE
+	allowed, reason, err := controller.isStartAllowedByRunLimit(ctx, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, !allowed)
+	assert.Equal(t, api.QueuedTenantLimitReason, reason)
+}
+
+func Test_Controller_maxConcurrentRuns(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		annotation    *string
+		tenantLimit   int32
+		defaultLimit  int
+		expectedLimit int
+	}{
+		{"default", nil, 0, 3, 3},
+		{"no_limit", nil, 0, 0, 0},
+		{"tenant", nil, 2, 3, 2},
+		{"annotation", stringPtr("1"), 2, 3, 1},
+		{"annotation_no_limit", stringPtr("0"), 2, 3, 0},
+		{"annotation_invalid", stringPtr("-1"), 2, 3, 2},
+		{"annotation_not_a_number", stringPtr("many"), 0, 3, 3},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			namespace := fake.Namespace("tenantns1")
+			if tc.annotation != nil {
+				namespace.SetAnnotations(map[string]string{api.AnnotationMaxConcurrentRuns: *tc.annotation})
+			}
+			controller, _ := newController()
+			err := controller.namespaceInformer.GetStore().Add(namespace)
+			assert.NilError(t, err)
+			controller.defaultMaxConcurrentRuns = tc.defaultLimit
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Spec.MaxConcurrentRuns = tc.tenantLimit
+
+			// EXERCISE
+			limit, err := controller.maxConcurrentRuns("tenantns1", tenant)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedLimit, limit)
+		})
+	}
+}
+
+func Test_Controller_maxConcurrentRuns_NamespaceNotExisting(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	controller, _ := newController()
+	controller.defaultMaxConcurrentRuns = 3
+
+	// EXERCISE
+	limit, err := controller.maxConcurrentRuns("notexisting", nil)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 3, limit)
+}
+
+func Test_Controller_updateTenantQueuedRuns_OutdatedTenant(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	controller, cf := newController()
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	outdated := tenant.DeepCopy()
+	outdated.SetResourceVersion("outdated")
+	tenant.Spec.MaxConcurrentRuns = 3
+	_, err = cf.StewardV1alpha1().Tenants("client1").Update(ctx, tenant, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	err = controller.updateTenantQueuedRuns(ctx, outdated, 2)
+
+	// VERIFY
+	assert.NilError(t, err)
+	tenant, err = cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, int32(2), tenant.Status.QueuedRuns)
+	assert.Equal(t, int32(3), tenant.Spec.MaxConcurrentRuns)
+}
+
+func Test_Controller_syncHandler_QueuesRunIfTenantRunLimitReached(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := fake.PipelineRun("run", "tenantns1", api.PipelineSpec{})
+	run.Status.State = api.StateNew
+	run.Status.StateDetails = api.StateItem{State: api.StateNew}
+	controller, cf := newController(run)
+	controller.testing = &controllerTesting{
+		isMaintenanceModeStub: newIsMaintenanceModeStub(false, nil),
+	}
+	addRunToInformerStore(t, cf, run)
+	running := fake.PipelineRun("running", "tenantns1", api.PipelineSpec{})
+	running.Status.State = api.StateRunning
+	addRunToInformerStore(t, cf, running)
+	tenant := fake.Tenant("tenant1", "client1")
+	tenant.Spec.MaxConcurrentRuns = 1
+	tenant.Status.TenantNamespaceName = "tenantns1"
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Create(ctx, tenant, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	err = cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore().Add(tenant)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	err = controller.syncHandler("tenantns1/run")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "run", "tenantns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateQueued, result.Status.State)
+	assert.Equal(t, api.StateNew, result.Status.StateHistory[0].State)
+
+	// EXERCISE again while still queued
+	err = controller.syncHandler("tenantns1/run")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err = getAPIPipelineRun(cf, "run", "tenantns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateQueued, result.Status.State)
+	assert.Equal(t, 1, len(result.Status.StateHistory))
+}
+
+func addRunToInformerStore(t *testing.T, cf *fake.ClientFactory, run *api.PipelineRun) {
+	t.Helper()
+	err := cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetStore().Add(run)
+	assert.NilError(t, err)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,438 @@
+package runctl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// timelineDocumentType is the value of field `type` of all documents
+	// of the run event timeline, which distinguishes them from the
+	// pipeline log entries in the same index.
+	timelineDocumentType = "stewardRunEvent"
+
+	// timelineExportTimeout is the maximum duration of the export of the
+	// timeline of a single pipeline run.
+	timelineExportTimeout = 30 * time.Second
+
+	// maxTimelineExportRetries is the maximum number of retries of a
+	// failed timeline export of a single pipeline run.
+	maxTimelineExportRetries = 5
+
+	// pipelineRunEventIndex is the name of the index of the event
+	// informers by UID of the involved pipeline run.
+	pipelineRunEventIndex = "involvedObjectUID"
+)
+
+// kinds of timeline events
+const (
+	timelineEventState    = "state"
+	timelineEventK8sEvent = "event"
+	timelineEventResult   = "result"
+)
+
+// timelineEvent is a document of the run event timeline.
+type timelineEvent struct {
+	Timestamp   time.Time           `json:"@timestamp"`
+	Type        string              `json:"type"`
+	RunID       *api.CustomJSON     `json:"runId"`
+	PipelineRun timelinePipelineRun `json:"pipelineRun"`
+	Event       string              `json:"event"`
+
+	// set for state events
+	State           api.State `json:"state,omitempty"`
+	FinishedAt      string    `json:"finishedAt,omitempty"`
+	DurationSeconds float64   `json:"durationSeconds,omitempty"`
+
+	// set for Kubernetes events
+	Reason    string `json:"reason,omitempty"`
+	EventType string `json:"eventType,omitempty"`
+	Count     int32  `json:"count,omitempty"`
+
+	// set for result events
+	Result api.Result `json:"result,omitempty"`
+	Intent api.Intent `json:"intent,omitempty"`
+
+	// set for Kubernetes events and result events
+	Message string `json:"message,omitempty"`
+}
+
+// timelinePipelineRun identifies the pipeline run in timeline documents.
+type timelinePipelineRun struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+// newPipelineRunEventInformer returns an informer for the Kubernetes
+// events involving pipeline runs in the given namespace, indexed by UID
+// of the pipeline run. Only events of pipeline runs are cached.
+func newPipelineRunEventInformer(factory k8s.ClientFactory, namespace string) cache.SharedIndexInformer {
+	selector := fields.OneTermEqualSelector("involvedObject.kind", "PipelineRun").String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return factory.CoreV1().Events(namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return factory.CoreV1().Events(namespace).Watch(context.TODO(), options)
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &corev1.Event{}, 0, cache.Indexers{
+		pipelineRunEventIndex: func(obj interface{}) ([]string, error) {
+			event, ok := obj.(*corev1.Event)
+			if !ok {
+				return nil, nil
+			}
+			return []string{string(event.InvolvedObject.UID)}, nil
+		},
+	})
+}
+
+// needsTimelineExport returns whether the run event timeline of the given
+// pipeline run is still to be exported.
+func (c *Controller) needsTimelineExport(pipelineRun *api.PipelineRun) bool {
+	if !c.exportRunTimeline || pipelineRun.Status.State != api.StateFinished || pipelineRun.Status.TimelineExported {
+		return false
+	}
+	return pipelineRun.Spec.Logging.HasSink()
+}
+
+// enqueueTimelineExport adds the given pipeline run to the timeline export
+// queue if its timeline is still to be exported.
+func (c *Controller) enqueueTimelineExport(key string, pipelineRun *api.PipelineRun) {
+	if c.timelineQueue != nil && c.needsTimelineExport(pipelineRun) {
+		c.timelineQueue.Add(key)
+	}
+}
+
+// runTimelineExportWorker exports the timelines of the pipeline runs in
+// the timeline export queue until the queue is shut down.
+// Timelines are exported off the reconcile path, so that a slow or
+// unavailable log sink does not delay the processing of pipeline runs.
+func (c *Controller) runTimelineExportWorker() {
+	for c.processNextTimelineExport() {
+	}
+}
+
+func (c *Controller) processNextTimelineExport() bool {
+	obj, shutdown := c.timelineQueue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.timelineQueue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		c.timelineQueue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in timeline export queue but got %#v", obj))
+		return true
+	}
+	pipelineRunAPIObj, err := c.syncTimelineExport(context.Background(), key)
+	if err == nil {
+		c.timelineQueue.Forget(obj)
+		return true
+	}
+	if c.timelineQueue.NumRequeues(obj) < maxTimelineExportRetries {
+		klog.V(3).Infof("failed to export run event timeline of pipeline run %q, retrying: %s", key, err.Error())
+		c.timelineQueue.AddRateLimited(obj)
+		return true
+	}
+	c.timelineQueue.Forget(obj)
+	klog.V(3).Infof("giving up exporting run event timeline of pipeline run %q: %s", key, err.Error())
+	if pipelineRunAPIObj != nil {
+		c.recorder.Event(pipelineRunAPIObj, corev1.EventTypeWarning, api.EventReasonTimelineExportFailed, err.Error())
+	}
+	return true
+}
+
+// syncTimelineExport exports the run event timeline of the pipeline run
+// with the given key, if still required, and records the export in the
+// status of the pipeline run.
+// Returns the pipeline run from the cache, if it exists.
+func (c *Controller) syncTimelineExport(ctx context.Context, key string) (*api.PipelineRun, error) {
+	pipelineRunAPIObj, err := c.pipelineRunFetcher.ByKey(ctx, key)
+	if err != nil || pipelineRunAPIObj == nil || !c.needsTimelineExport(pipelineRunAPIObj) {
+		return pipelineRunAPIObj, err
+	}
+	pipelineRun, err := k8s.NewPipelineRunWithOpts(ctx, pipelineRunAPIObj, c.factory, c.pipelineRunOpts)
+	if err != nil || pipelineRun == nil {
+		return pipelineRunAPIObj, err
+	}
+	if err := c.exportTimeline(ctx, pipelineRun); err != nil {
+		return pipelineRunAPIObj, err
+	}
+	pipelineRun.UpdateTimelineExported(true)
+	if _, err := pipelineRun.CommitStatus(ctx); err != nil {
+		return pipelineRunAPIObj, errors.WithMessage(err, "failed to record timeline export")
+	}
+	return pipelineRunAPIObj, nil
+}
+
+// exportTimeline sends the run event timeline of the given finished
+// pipeline run as individual documents to the Elasticsearch index the
+// pipeline log has been sent to, using a single bulk request.
+// It does nothing if timeline export is disabled, the pipeline run does
+// not log to Elasticsearch or no index is configured.
+func (c *Controller) exportTimeline(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	if !c.exportRunTimeline {
+		return nil
+	}
+	if sink, err := pipelineRun.GetSpec().Logging.EffectiveSink(); err != nil || sink == nil {
+		// invalid logging configurations fail the pipeline run before
+		// any log is sent
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timelineExportTimeout)
+	defer cancel()
+
+	pipelineRunsConfig, err := c.loadPipelineRunsConfig(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load configuration for pipeline runs")
+	}
+	config, err := loadLogForwardingConfig(ctx, c.factory, pipelineRunsConfig, pipelineRun)
+	if err != nil {
+		return err
+	}
+	if config.indexURL == "" {
+		return nil
+	}
+	events, err := c.getPipelineRunEvents(pipelineRun)
+	if err != nil {
+		// the timeline is still useful without events
+		klog.V(3).Infof("failed to get events of pipeline run %q: %s", pipelineRun.String(), err.Error())
+	}
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return err
+	}
+
+	documents := []interface{}{}
+	for _, event := range buildTimeline(pipelineRun, events) {
+		documents = append(documents, event)
+	}
+	if err := config.postDocuments(ctx, httpClient, documents); err != nil {
+		return errors.WithMessage(err, "failed to export run event timeline")
+	}
+	return nil
+}
+
+// getPipelineRunEvents returns the Kubernetes events involving the given
+// pipeline run from the event informer cache.
+func (c *Controller) getPipelineRunEvents(pipelineRun k8s.PipelineRun) ([]corev1.Event, error) {
+	uid := string(pipelineRun.GetAPIObject().GetUID())
+	var events []corev1.Event
+	for _, informer := range c.pipelineRunEventInformers {
+		objs, err := informer.GetIndexer().ByIndex(pipelineRunEventIndex, uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			if event, ok := obj.(*corev1.Event); ok {
+				events = append(events, *event)
+			}
+		}
+	}
+	return events, nil
+}
+
+// buildTimeline returns the run event timeline of the given pipeline
+// run ordered by time. It consists of the state transitions, the given
+// Kubernetes events and the result of the pipeline run.
+func buildTimeline(pipelineRun k8s.PipelineRun, events []corev1.Event) []timelineEvent {
+	apiObj := pipelineRun.GetAPIObject()
+	status := pipelineRun.GetStatus()
+	runID := pipelineRun.GetSpec().Logging.RunIDJSON()
+	newEvent := func(kind string, timestamp time.Time) timelineEvent {
+		event := timelineEvent{
+			Timestamp: timestamp.UTC(),
+			Type:      timelineDocumentType,
+			PipelineRun: timelinePipelineRun{
+				Namespace: apiObj.GetNamespace(),
+				Name:      apiObj.GetName(),
+				UID:       string(apiObj.GetUID()),
+			},
+			Event: kind,
+			RunID: runID,
+		}
+		return event
+	}
+
+	var timeline []timelineEvent
+	for _, item := range status.StateHistory {
+		event := newEvent(timelineEventState, item.StartedAt.Time)
+		event.State = item.State
+		if !item.FinishedAt.IsZero() {
+			event.FinishedAt = item.FinishedAt.UTC().Format(time.RFC3339)
+			event.DurationSeconds = item.FinishedAt.Sub(item.StartedAt.Time).Seconds()
+		}
+		timeline = append(timeline, event)
+	}
+	for _, k8sEvent := range events {
+		timestamp := k8sEvent.LastTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = k8sEvent.EventTime.Time
+		}
+		if timestamp.IsZero() {
+			timestamp = k8sEvent.FirstTimestamp.Time
+		}
+		event := newEvent(timelineEventK8sEvent, timestamp)
+		event.Reason = k8sEvent.Reason
+		event.EventType = k8sEvent.Type
+		event.Count = k8sEvent.Count
+		event.Message = k8sEvent.Message
+		timeline = append(timeline, event)
+	}
+	if status.Result != api.ResultUndefined {
+		timestamp := time.Now()
+		if status.FinishedAt != nil {
+			timestamp = status.FinishedAt.Time
+		}
+		event := newEvent(timelineEventResult, timestamp)
+		event.Result = status.Result
+		event.Intent = pipelineRun.GetSpec().Intent
+		event.Message = status.Message
+		if event.Message == "" {
+			// the message may have been offloaded
+			event.Message = status.MessageShort
+		}
+		timeline = append(timeline, event)
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	return timeline
+}
+
+// httpClient returns an HTTP client for the connection to Elasticsearch
+// using the configured client certificate and trusted CA certificates.
+func (config *logForwardingConfig) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+	if len(config.clientCert) > 0 {
+		certificate, err := tls.X509KeyPair(config.clientCert, config.clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid log forwarding client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if len(config.caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(config.caBundle) {
+			return nil, errors.New("invalid log forwarding CA bundle: no PEM-encoded certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
+
+// bulkURL returns the URL of the Elasticsearch bulk API for the
+// configured index URL, e.g. `https://es.example.com/logs/_bulk` for
+// `https://es.example.com/logs/_doc`.
+func (config *logForwardingConfig) bulkURL() (string, error) {
+	indexURL, err := url.Parse(config.indexURL)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid log forwarding index URL")
+	}
+	indexPath := strings.TrimSuffix(indexURL.Path, "/")
+	indexPath = strings.TrimSuffix(indexPath, "/_doc")
+	indexURL.Path = indexPath + "/_bulk"
+	indexURL.RawPath = ""
+	return indexURL.String(), nil
+}
+
+// bulkResponse is the part of the response of the Elasticsearch bulk API
+// needed to detect failed items.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int             `json:"status"`
+		Error  json.RawMessage `json:"error,omitempty"`
+	} `json:"items"`
+}
+
+// postDocuments sends the given documents to the configured Elasticsearch
+// index using a single request to the bulk API.
+func (config *logForwardingConfig) postDocuments(ctx context.Context, httpClient *http.Client, documents []interface{}) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	bulkURL, err := config.bulkURL()
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	for _, document := range documents {
+		line, err := json.Marshal(document)
+		if err != nil {
+			return err
+		}
+		body.WriteString("{\"index\":{}}\n")
+		body.Write(line)
+		body.WriteString("\n")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, bulkURL, &body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	if len(config.username) > 0 {
+		request.SetBasicAuth(string(config.username), string(config.password))
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		responseBody, _ := ioutil.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("unexpected response status %q: %s", response.Status, string(responseBody))
+	}
+	result := bulkResponse{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return errors.Wrap(err, "invalid bulk response")
+	}
+	if !result.Errors {
+		return nil
+	}
+	failed := 0
+	var firstError json.RawMessage
+	for _, item := range result.Items {
+		for _, outcome := range item {
+			if outcome.Status < 200 || outcome.Status > 299 {
+				failed++
+				if firstError == nil {
+					firstError = outcome.Error
+				}
+			}
+		}
+	}
+	return fmt.Errorf("%d of %d documents rejected: %s", failed, len(documents), string(firstError))
+}
@@ -0,0 +1,285 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newTimelineTestPipelineRun() *api.PipelineRun {
+	t0 := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Intent: api.IntentAbort,
+		Logging: &api.Logging{
+			Elasticsearch: &api.Elasticsearch{
+				RunID: &api.CustomJSON{Value: map[string]interface{}{"jobId": "job1"}},
+			},
+		},
+	})
+	run.UID = "uid1"
+	run.Status.StateHistory = []api.StateItem{
+		{State: api.StateNew, StartedAt: metav1.NewTime(t0), FinishedAt: metav1.NewTime(t0.Add(1 * time.Second))},
+		{State: api.StatePreparing, StartedAt: metav1.NewTime(t0.Add(1 * time.Second)), FinishedAt: metav1.NewTime(t0.Add(5 * time.Second))},
+		{State: api.StateCleaning, StartedAt: metav1.NewTime(t0.Add(5 * time.Second)), FinishedAt: metav1.NewTime(t0.Add(7 * time.Second))},
+		{State: api.StateFinished, StartedAt: metav1.NewTime(t0.Add(7 * time.Second))},
+	}
+	finishedAt := metav1.NewTime(t0.Add(5 * time.Second))
+	run.Status.FinishedAt = &finishedAt
+	run.Status.Result = api.ResultAborted
+	run.Status.Message = "Aborted"
+	return run
+}
+
+func newTimelineTestEvent(name string, uid string, reason string, timestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     fake.ObjectMeta(name, "ns1"),
+		InvolvedObject: corev1.ObjectReference{Kind: "PipelineRun", Namespace: "ns1", Name: "run1", UID: types.UID(uid)},
+		Reason:         reason,
+		Type:           corev1.EventTypeWarning,
+		Message:        "message of " + name,
+		Count:          2,
+		LastTimestamp:  metav1.NewTime(timestamp),
+	}
+}
+
+func Test_buildTimeline(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	apiObj := newTimelineTestPipelineRun()
+	pipelineRun, err := k8s.NewPipelineRun(ctx, apiObj, nil)
+	assert.NilError(t, err)
+	t0 := apiObj.Status.StateHistory[0].StartedAt.Time
+	events := []corev1.Event{
+		*newTimelineTestEvent("event1", "uid1", api.EventReasonPreparingFailed, t0.Add(2*time.Second)),
+	}
+
+	// EXERCISE
+	timeline := buildTimeline(pipelineRun, events)
+
+	// VERIFY
+	assert.Equal(t, 6, len(timeline))
+	var kinds []string
+	for _, event := range timeline {
+		kinds = append(kinds, event.Event+":"+string(event.State)+string(event.Result)+event.Reason)
+		assert.Equal(t, timelineDocumentType, event.Type)
+		assert.DeepEqual(t, apiObj.Spec.Logging.Elasticsearch.RunID, event.RunID)
+		assert.DeepEqual(t, timelinePipelineRun{Namespace: "ns1", Name: "run1", UID: "uid1"}, event.PipelineRun)
+	}
+	assert.DeepEqual(t, []string{
+		"state:new",
+		"state:preparing",
+		"event:PreparingFailed",
+		"state:cleaning",
+		"result:aborted",
+		"state:finished",
+	}, kinds)
+
+	assert.Equal(t, 4.0, timeline[1].DurationSeconds)
+	assert.Equal(t, "2022-03-01T10:00:05Z", timeline[1].FinishedAt)
+	assert.Equal(t, int32(2), timeline[2].Count)
+	assert.Equal(t, corev1.EventTypeWarning, timeline[2].EventType)
+	assert.Equal(t, "message of event1", timeline[2].Message)
+	assert.Equal(t, api.IntentAbort, timeline[4].Intent)
+	assert.Equal(t, "Aborted", timeline[4].Message)
+	assert.Equal(t, 0.0, timeline[5].DurationSeconds)
+	assert.Equal(t, "", timeline[5].FinishedAt)
+}
+
+// newTimelineTestServer returns a test server emulating the Elasticsearch
+// bulk API. It records the received documents and responds with the
+// given status. If rejectAll is true, all items are rejected.
+func newTimelineTestServer(t *testing.T, responseStatus int, rejectAll bool) (*httptest.Server, func() []map[string]interface{}) {
+	var mutex sync.Mutex
+	var documents []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/logs/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+		var items []string
+		mutex.Lock()
+		for i := 1; i < len(lines); i += 2 {
+			assert.Equal(t, `{"index":{}}`, lines[i-1])
+			document := map[string]interface{}{}
+			json.Unmarshal([]byte(lines[i]), &document)
+			documents = append(documents, document)
+			if rejectAll {
+				items = append(items, `{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}`)
+			} else {
+				items = append(items, `{"index":{"status":201}}`)
+			}
+		}
+		mutex.Unlock()
+		w.WriteHeader(responseStatus)
+		if responseStatus >= 300 {
+			w.Write([]byte("denied"))
+			return
+		}
+		fmt.Fprintf(w, `{"errors":%t,"items":[%s]}`, rejectAll, strings.Join(items, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []map[string]interface{} {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return documents
+	}
+}
+
+func Test_Controller_exportTimeline(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		enabled           bool
+		logging           bool
+		indexURLSet       bool
+		responseStatus    int
+		rejectAll         bool
+		expectedDocuments int
+		expectedError     string
+	}{
+		{"disabled", false, true, true, http.StatusOK, false, 0, ""},
+		{"no_logging", true, false, true, http.StatusOK, false, 0, ""},
+		{"no_index_url", true, true, false, http.StatusOK, false, 0, ""},
+		{"exported", true, true, true, http.StatusOK, false, 6, ""},
+		{"sink_error", true, true, true, http.StatusForbidden, false, 6, `failed to export run event timeline: unexpected response status "403 Forbidden": denied`},
+		{"rejected", true, true, true, http.StatusOK, true, 6, `failed to export run event timeline: 6 of 6 documents rejected: {"type":"mapper_parsing_exception"}`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			server, documents := newTimelineTestServer(t, tc.responseStatus, tc.rejectAll)
+
+			run := newTimelineTestPipelineRun()
+			if !tc.logging {
+				run.Spec.Logging = nil
+			}
+			controller, cf := newController(run)
+			controller.exportRunTimeline = tc.enabled
+			pipelineRunsConfig := &cfg.PipelineRunsConfigStruct{}
+			if tc.indexURLSet {
+				pipelineRunsConfig.LoggingIndexURL = server.URL + "/logs/_doc"
+			}
+			controller.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return pipelineRunsConfig, nil
+				},
+			}
+			eventInformer := newPipelineRunEventInformer(cf, "")
+			controller.pipelineRunEventInformers = []cache.SharedIndexInformer{eventInformer}
+			t0 := run.Status.StateHistory[0].StartedAt.Time
+			for _, event := range []*corev1.Event{
+				newTimelineTestEvent("event1", "uid1", api.EventReasonPreparingFailed, t0.Add(2*time.Second)),
+				newTimelineTestEvent("event2", "otherUID", api.EventReasonPreparingFailed, t0.Add(3*time.Second)),
+			} {
+				assert.NilError(t, eventInformer.GetIndexer().Add(event))
+			}
+			pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+
+			// EXERCISE
+			err := controller.exportTimeline(ctx, pipelineRun)
+
+			// VERIFY
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, tc.expectedDocuments, len(documents()))
+			for _, document := range documents() {
+				assert.Equal(t, timelineDocumentType, document["type"])
+				assert.DeepEqual(t, map[string]interface{}{"jobId": "job1"}, document["runId"])
+				assert.Assert(t, document["reason"] == nil || document["message"] == "message of event1")
+			}
+		})
+	}
+}
+
+func Test_Controller_syncTimelineExport(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		state             api.State
+		exported          bool
+		expectedDocuments int
+	}{
+		{"finished", api.StateFinished, false, 5},
+		{"already_exported", api.StateFinished, true, 0},
+		{"not_finished", api.StateCleaning, false, 0},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			server, documents := newTimelineTestServer(t, http.StatusOK, false)
+			run := newTimelineTestPipelineRun()
+			run.Status.State = tc.state
+			run.Status.TimelineExported = tc.exported
+			controller, cf := newController(run)
+			controller.exportRunTimeline = true
+			controller.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{LoggingIndexURL: server.URL + "/logs/_doc"}, nil
+				},
+			}
+
+			// EXERCISE
+			pipelineRunAPIObj, err := controller.syncTimelineExport(ctx, "ns1/run1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, pipelineRunAPIObj != nil)
+			assert.Equal(t, tc.expectedDocuments, len(documents()))
+			pipelineRun := getPipelineRun(t, "run1", "ns1", cf)
+			assert.Equal(t, tc.exported || tc.expectedDocuments > 0, pipelineRun.GetStatus().TimelineExported)
+		})
+	}
+}
+
+func Test_logForwardingConfig_bulkURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		indexURL string
+		expected string
+	}{
+		{"https://es.example.com/logs/_doc", "https://es.example.com/logs/_bulk"},
+		{"https://es.example.com/logs/_doc/", "https://es.example.com/logs/_bulk"},
+		{"http://es:9200/logs", "http://es:9200/logs/_bulk"},
+		{"http://es:9200/logs/_doc?pipeline=p1", "http://es:9200/logs/_bulk?pipeline=p1"},
+	} {
+		config := &logForwardingConfig{indexURL: tc.indexURL}
+
+		// EXERCISE
+		result, err := config.bulkURL()
+
+		// VERIFY
+		assert.NilError(t, err)
+		assert.Equal(t, tc.expected, result, "index URL: %s", tc.indexURL)
+	}
+}
@@ -0,0 +1,53 @@
+package runctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// specTimeoutRemaining returns how much time is left until the given
+// pipeline run exceeds the maximum execution time set in `spec.timeout`
+// and whether a timeout is set at all.
+// The execution time is measured from the time the pipeline run has been
+// started, i.e. entered state `preparing`. The remaining time is zero or
+// negative if the timeout has been exceeded.
+func specTimeoutRemaining(pipelineRun k8s.PipelineRun, now time.Time) (time.Duration, bool) {
+	timeout := pipelineRun.GetSpec().Timeout
+	startedAt := pipelineRun.GetStatus().StartedAt
+	if timeout == nil || timeout.Duration <= 0 || startedAt == nil {
+		return 0, false
+	}
+	return startedAt.Add(timeout.Duration).Sub(now), true
+}
+
+// handleSpecTimeout aborts the given pipeline run with result
+// api.ResultTimeout if it has exceeded the maximum execution time set in
+// `spec.timeout`. Returns whether the pipeline run has been aborted.
+// Otherwise the pipeline run is added to the work queue again when the
+// timeout is reached, so that it gets aborted in time even if nothing
+// else triggers its reconciliation.
+// The timeout is enforced by the Tekton task run, too, but the task run
+// does not account for the time spent before it has been started.
+func (c *Controller) handleSpecTimeout(ctx context.Context, pipelineRun k8s.PipelineRun, now time.Time) (bool, error) {
+	remaining, ok := specTimeoutRemaining(pipelineRun, now)
+	if !ok {
+		return false, nil
+	}
+	if remaining > 0 {
+		c.workqueue.AddAfter(pipelineRun.GetKey(), remaining)
+		return false, nil
+	}
+	message := fmt.Sprintf(
+		"the pipeline run exceeded its maximum execution time of %v (spec.timeout)",
+		pipelineRun.GetSpec().Timeout.Duration,
+	)
+	klog.V(3).Infof("aborting pipeline run %q: %s", pipelineRun.GetKey(), message)
+	pipelineRun.UpdateMessage(message)
+	return true, c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultTimeout, metav1.NewTime(now))
+}
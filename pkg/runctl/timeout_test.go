@@ -0,0 +1,112 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Controller_handleSpecTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+
+	for _, tc := range []struct {
+		name            string
+		timeout         *metav1.Duration
+		startedAt       *metav1.Time
+		expectedAborted bool
+	}{
+		{"no_timeout", nil, metav1Time(now.Add(-time.Hour)), false},
+		{"zero_timeout", metav1Duration(0), metav1Time(now.Add(-time.Hour)), false},
+		{"not_started", metav1Duration(time.Minute), nil, false},
+		{"within_timeout", metav1Duration(time.Hour), metav1Time(now.Add(-time.Minute)), false},
+		{"timeout_exceeded", metav1Duration(time.Minute), metav1Time(now.Add(-time.Hour)), true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := fake.PipelineRun("run", "ns1", api.PipelineSpec{Timeout: tc.timeout})
+			run.Status.State = api.StateRunning
+			run.Status.StateDetails = api.StateItem{State: api.StateRunning}
+			run.Status.StartedAt = tc.startedAt
+			controller, cf := newController(run)
+			pipelineRun := getPipelineRun(t, "run", "ns1", cf)
+
+			// EXERCISE
+			aborted, err := controller.handleSpecTimeout(ctx, pipelineRun, now)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAborted, aborted)
+			result, err := getAPIPipelineRun(cf, "run", "ns1")
+			assert.NilError(t, err)
+			if tc.expectedAborted {
+				assert.Equal(t, api.ResultTimeout, result.Status.Result)
+				assert.Equal(t, api.StateCleaning, result.Status.State)
+				assert.Equal(t, "the pipeline run exceeded its maximum execution time of 1m0s (spec.timeout)", result.Status.Message)
+			} else {
+				assert.Equal(t, api.ResultUndefined, result.Status.Result)
+				assert.Equal(t, api.StateRunning, result.Status.State)
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_AbortsOnSpecTimeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		Timeout: metav1Duration(10 * time.Minute),
+	})
+	run.Status = api.PipelineStatus{
+		State:     api.StateRunning,
+		Namespace: "run1",
+		StartedAt: metav1Time(time.Now().Add(-time.Hour)),
+		StateDetails: api.StateItem{
+			State:     api.StateRunning,
+			StartedAt: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+	}
+	controller, cf := newController(run)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runmock := runmocks.NewMockRun(mockCtrl)
+	runmock.EXPECT().GetContainerInfo().Return(nil)
+	runmock.EXPECT().IsFinished().Return(false, api.ResultUndefined)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runmock, nil)
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       runManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+	}
+
+	// EXERCISE
+	err := controller.syncHandler("ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, err)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateCleaning, result.Status.State)
+	assert.Equal(t, api.ResultTimeout, result.Status.Result)
+	assert.Equal(t, "the pipeline run exceeded its maximum execution time of 10m0s (spec.timeout)", result.Status.Message)
+}
+
+func metav1Time(t time.Time) *metav1.Time {
+	result := metav1.NewTime(t)
+	return &result
+}
@@ -0,0 +1,113 @@
+package runctl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/utils/retry"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+)
+
+// pipelineRunTTL returns the time to live of the given pipeline run after
+// it has finished and whether it expires at all.
+// Field `spec.ttlSecondsAfterFinished` takes precedence over the default
+// of the controller.
+func (c *Controller) pipelineRunTTL(pipelineRun *api.PipelineRun) (time.Duration, bool) {
+	if ttl := pipelineRun.Spec.TTLSecondsAfterFinished; ttl != nil {
+		return time.Duration(*ttl) * time.Second, true
+	}
+	if c.defaultTTLAfterFinished > 0 {
+		return c.defaultTTLAfterFinished, true
+	}
+	return 0, false
+}
+
+// finishedAt returns the time the given finished pipeline run entered
+// state `finished`.
+func finishedAt(pipelineRun *api.PipelineRun) time.Time {
+	status := &pipelineRun.Status
+	if status.StateDetails.State == api.StateFinished && !status.StateDetails.StartedAt.IsZero() {
+		return status.StateDetails.StartedAt.Time
+	}
+	if status.FinishedAt != nil {
+		return status.FinishedAt.Time
+	}
+	return pipelineRun.GetCreationTimestamp().Time
+}
+
+// collectFinishedPipelineRun deletes the given finished pipeline run if
+// its time to live has expired. The finish time is recorded in the status
+// of the tenant owning the namespace of the pipeline run before, so that
+// the activity of the tenant is not lost with the pipeline run. Otherwise the pipeline run is enqueued
+// again for the time it expires.
+// Deletions exceeding the rate of the TTL deletion limiter are deferred
+// by enqueuing the pipeline run again for the time slot reserved for it.
+func (c *Controller) collectFinishedPipelineRun(ctx context.Context, key string, pipelineRun *api.PipelineRun, now time.Time) error {
+	ttl, expires := c.pipelineRunTTL(pipelineRun)
+	if !expires {
+		return nil
+	}
+	if remaining := finishedAt(pipelineRun).Add(ttl).Sub(now); remaining > 0 {
+		c.workqueue.AddAfter(key, remaining)
+		return nil
+	}
+	if delay := c.ttlDeletionLimiter.Delay(key); delay > 0 {
+		klog.V(5).Infof("Deferring deletion of finished pipeline run %q by %v due to the deletion rate limit", key, delay)
+		c.workqueue.AddAfter(key, delay)
+		return nil
+	}
+	if err := c.recordTenantRunActivity(ctx, pipelineRun); err != nil {
+		return err
+	}
+	uid := pipelineRun.GetUID()
+	err := c.factory.StewardV1alpha1().PipelineRuns(pipelineRun.GetNamespace()).Delete(ctx, pipelineRun.GetName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.WithMessagef(err, "failed to delete finished pipeline run %q after its time to live", key)
+	}
+	klog.V(3).Infof("Deleted finished pipeline run %q after its time to live of %v", key, ttl)
+	return nil
+}
+
+// recordTenantRunActivity sets `status.lastRunActivity` of the tenant
+// owning the namespace of the given finished pipeline run, if any, to the
+// finish time of the pipeline run unless it is later already.
+// The tenant is patched, so that the request does not fail if the tenant
+// object from the informer cache is outdated.
+func (c *Controller) recordTenantRunActivity(ctx context.Context, pipelineRun *api.PipelineRun) error {
+	tenant, err := c.findTenantByTenantNamespace(pipelineRun.GetNamespace())
+	if err != nil || tenant == nil {
+		return err
+	}
+	activity := metav1.NewTime(finishedAt(pipelineRun))
+	if last := tenant.Status.LastRunActivity; last != nil && !last.Before(&activity) {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"lastRunActivity": activity,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	err = retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		_, err := c.factory.StewardV1alpha1().Tenants(tenant.GetNamespace()).Patch(
+			ctx, tenant.GetName(), types.MergePatchType, patch, metav1.PatchOptions{}, "status",
+		)
+		return err
+	})
+	if err != nil {
+		return errors.WithMessagef(err,
+			"failed to update status of tenant %q in namespace %q", tenant.GetName(), tenant.GetNamespace(),
+		)
+	}
+	return nil
+}
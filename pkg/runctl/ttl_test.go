@@ -0,0 +1,204 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/throttle"
+	"gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newFinishedTestRun(finished time.Time, ttlSeconds *int32) *api.PipelineRun {
+	run := fake.PipelineRun("run1", "ns1", api.PipelineSpec{TTLSecondsAfterFinished: ttlSeconds})
+	run.Status.State = api.StateFinished
+	run.Status.StateDetails = api.StateItem{State: api.StateFinished, StartedAt: metav1.NewTime(finished)}
+	return run
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func Test_Controller_pipelineRunTTL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		defaultTTL      time.Duration
+		ttlSeconds      *int32
+		expectedTTL     time.Duration
+		expectedExpires bool
+	}{
+		{"no TTL", 0, nil, 0, false},
+		{"default only", time.Hour, nil, time.Hour, true},
+		{"spec only", 0, int32Ptr(60), time.Minute, true},
+		{"spec overrides default", time.Hour, int32Ptr(7200), 2 * time.Hour, true},
+		{"spec zero", time.Hour, int32Ptr(0), 0, true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &Controller{defaultTTLAfterFinished: tc.defaultTTL}
+			run := newFinishedTestRun(time.Now(), tc.ttlSeconds)
+
+			// EXERCISE
+			ttl, expires := examinee.pipelineRunTTL(run)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedTTL, ttl)
+			assert.Equal(t, tc.expectedExpires, expires)
+		})
+	}
+}
+
+func Test_finishedAt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	t1 := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	run := newFinishedTestRun(t2, nil)
+	finishedAtResult := metav1.NewTime(t1)
+	run.Status.FinishedAt = &finishedAtResult
+
+	// EXERCISE
+	result := finishedAt(run)
+
+	// VERIFY
+	assert.Assert(t, t2.Equal(result))
+
+	// SETUP
+	run.Status.StateDetails = api.StateItem{}
+
+	// EXERCISE
+	result = finishedAt(run)
+
+	// VERIFY
+	assert.Assert(t, t1.Equal(result))
+}
+
+func Test_Controller_collectFinishedPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name            string
+		defaultTTL      time.Duration
+		ttlSeconds      *int32
+		expectedDeleted bool
+	}{
+		{"no TTL", 0, nil, false},
+		{"default TTL not expired", 2 * time.Hour, nil, false},
+		{"default TTL expired", 30 * time.Minute, nil, true},
+		{"spec TTL not expired", 0, int32Ptr(7200), false},
+		{"spec TTL expired", 2 * time.Hour, int32Ptr(60), true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := newFinishedTestRun(now.Add(-time.Hour), tc.ttlSeconds)
+			controller, cf := newController(run)
+			controller.defaultTTLAfterFinished = tc.defaultTTL
+
+			// EXERCISE
+			err := controller.collectFinishedPipelineRun(ctx, "ns1/run1", run, now)
+
+			// VERIFY
+			assert.NilError(t, err)
+			_, err = cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+			if tc.expectedDeleted {
+				assert.Assert(t, k8serrors.IsNotFound(err))
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Controller_collectFinishedPipelineRun_AlreadyDeleted(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newFinishedTestRun(time.Now().Add(-time.Hour), int32Ptr(0))
+	controller, _ := newController()
+
+	// EXERCISE
+	err := controller.collectFinishedPipelineRun(ctx, "ns1/run1", run, time.Now())
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_Controller_collectFinishedPipelineRun_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newFinishedTestRun(time.Now().Add(-time.Hour), int32Ptr(0))
+	controller, cf := newController(run)
+	controller.ttlDeletionLimiter = throttle.NewDeletionLimiter(0.001, 1)
+	assert.Equal(t, time.Duration(0), controller.ttlDeletionLimiter.Delay("ns1/other"))
+
+	// EXERCISE
+	err := controller.collectFinishedPipelineRun(ctx, "ns1/run1", run, time.Now())
+
+	// VERIFY
+	assert.NilError(t, err)
+	_, err = cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, controller.ttlDeletionLimiter.Delay("ns1/run1") > 0)
+}
+
+func Test_Controller_collectFinishedPipelineRun_RecordsTenantRunActivity(t *testing.T) {
+	t.Parallel()
+
+	finished := time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		recorded *metav1.Time
+		expected time.Time
+	}{
+		{"none", nil, finished},
+		{"older", &metav1.Time{Time: finished.Add(-time.Hour)}, finished},
+		{"newer", &metav1.Time{Time: finished.Add(time.Hour)}, finished.Add(time.Hour)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := newFinishedTestRun(finished, int32Ptr(0))
+			tenant := fake.Tenant("tenant1", "client1")
+			tenant.Status.TenantNamespaceName = "ns1"
+			tenant.Status.LastRunActivity = tc.recorded
+			controller, cf := newController(run)
+			addTenant(t, cf, tenant)
+
+			// EXERCISE
+			err := controller.collectFinishedPipelineRun(ctx, "ns1/run1", run, finished.Add(time.Minute))
+
+			// VERIFY
+			assert.NilError(t, err)
+			_, err = cf.StewardV1alpha1().PipelineRuns("ns1").Get(ctx, "run1", metav1.GetOptions{})
+			assert.Assert(t, k8serrors.IsNotFound(err))
+			tenant, err = cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Assert(t, tenant.Status.LastRunActivity != nil)
+			assert.Assert(t, tenant.Status.LastRunActivity.Time.Equal(tc.expected))
+		})
+	}
+}
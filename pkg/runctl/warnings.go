@@ -0,0 +1,50 @@
+package runctl
+
+import (
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/imagepolicy"
+)
+
+// collectWarnings checks the given pipeline run for non-fatal issues
+// the user should be made aware of.
+func collectWarnings(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) []api.Warning {
+	var warnings []api.Warning
+	spec := pipelineRun.GetSpec()
+
+	if jfr := spec.JenkinsfileRunner; jfr != nil {
+		if jfr.ImagePullPolicy != "" && jfr.Image == "" {
+			warnings = append(warnings, api.Warning{
+				Reason:  api.WarningReasonIneffectiveField,
+				Message: "Field spec.jenkinsfileRunner.imagePullPolicy has no effect if field spec.jenkinsfileRunner.image is not set.",
+			})
+		}
+		if jfr.Image != "" && pipelineRunsConfig != nil {
+			registry := imagepolicy.Registry(jfr.Image)
+			for _, deprecated := range pipelineRunsConfig.DeprecatedImageRegistries {
+				if registry == deprecated {
+					warnings = append(warnings, api.Warning{
+						Reason: api.WarningReasonDeprecatedImageRegistry,
+						Message: fmt.Sprintf(
+							"Image %q of field spec.jenkinsfileRunner.image is pulled from deprecated registry %q. Use an image from a different registry.",
+							jfr.Image, registry,
+						),
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return warnings
+}
+
+// addWarnings adds all warnings for the given pipeline run to its status.
+func addWarnings(pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) {
+	for _, warning := range collectWarnings(pipelineRun, pipelineRunsConfig) {
+		pipelineRun.AddWarning(warning.Reason, warning.Message)
+	}
+}
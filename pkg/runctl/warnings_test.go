@@ -0,0 +1,53 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"gotest.tools/assert"
+)
+
+func Test_collectWarnings(t *testing.T) {
+	t.Parallel()
+
+	config := &cfg.PipelineRunsConfigStruct{
+		DeprecatedImageRegistries: []string{"old.example.com"},
+	}
+
+	for _, tc := range []struct {
+		name            string
+		jfrSpec         *api.JenkinsfileRunnerSpec
+		expectedReasons []string
+	}{
+		{"no_jfr_spec", nil, nil},
+		{"image_from_other_registry", &api.JenkinsfileRunnerSpec{Image: "new.example.com/jfr"}, nil},
+		{"image_from_deprecated_registry", &api.JenkinsfileRunnerSpec{Image: "old.example.com/jfr"}, []string{api.WarningReasonDeprecatedImageRegistry}},
+		{"image_pull_policy_with_image", &api.JenkinsfileRunnerSpec{Image: "jfr", ImagePullPolicy: "Always"}, nil},
+		{"image_pull_policy_without_image", &api.JenkinsfileRunnerSpec{ImagePullPolicy: "Always"}, []string{api.WarningReasonIneffectiveField}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			pipelineRunAPIObj := &api.PipelineRun{
+				Spec: api.PipelineSpec{JenkinsfileRunner: tc.jfrSpec},
+			}
+			pipelineRun, err := k8s.NewPipelineRun(context.Background(), pipelineRunAPIObj, nil)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result := collectWarnings(pipelineRun, config)
+
+			// VERIFY
+			var reasons []string
+			for _, w := range result {
+				reasons = append(reasons, w.Reason)
+			}
+			assert.DeepEqual(t, tc.expectedReasons, reasons)
+		})
+	}
+}
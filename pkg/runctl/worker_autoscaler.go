@@ -0,0 +1,99 @@
+package runctl
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// workerAutoscalingInterval is the interval in which the number of
+	// workers gets adjusted.
+	workerAutoscalingInterval = 10 * time.Second
+)
+
+// workerAutoscaler determines the number of workers needed to process
+// the items in the workqueue based on the queue depth and the average
+// sync latency.
+// A nil *workerAutoscaler ignores observations.
+type workerAutoscaler struct {
+	min, max int
+	interval time.Duration
+
+	mutex           sync.Mutex
+	current         int
+	syncCount       int
+	syncDurationSum time.Duration
+}
+
+func newWorkerAutoscaler(min, max int) *workerAutoscaler {
+	if max < min {
+		max = min
+	}
+	a := &workerAutoscaler{
+		min:      min,
+		max:      max,
+		interval: workerAutoscalingInterval,
+		current:  min,
+	}
+	metrics.Workers.Set(float64(min))
+	return a
+}
+
+// Workers returns the current number of workers.
+func (a *workerAutoscaler) Workers() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.current
+}
+
+// ObserveSync records the duration of a single sync.
+func (a *workerAutoscaler) ObserveSync(duration time.Duration) {
+	if a == nil {
+		return
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.syncCount++
+	a.syncDurationSum += duration
+}
+
+// Adjust recalculates the number of workers given the current depth of
+// the workqueue.
+// The number of workers is the number needed to process all queued items
+// within one autoscaling interval at the average sync latency observed
+// since the last adjustment, bounded by the configured minimum and
+// maximum. To avoid flapping, the number of workers is reduced by at most
+// one per adjustment.
+func (a *workerAutoscaler) Adjust(queueDepth int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	desired := a.current
+	if a.syncCount > 0 {
+		avgLatency := a.syncDurationSum / time.Duration(a.syncCount)
+		desired = int(math.Ceil(float64(queueDepth) * avgLatency.Seconds() / a.interval.Seconds()))
+	} else if queueDepth == 0 {
+		desired = a.min
+	}
+	if desired < a.current-1 {
+		desired = a.current - 1
+	}
+	if desired < a.min {
+		desired = a.min
+	}
+	if desired > a.max {
+		desired = a.max
+	}
+
+	if desired != a.current {
+		klog.V(3).Infof("Changing number of workers from %d to %d (queue depth: %d, syncs: %d)", a.current, desired, queueDepth, a.syncCount)
+		a.current = desired
+		metrics.Workers.Set(float64(desired))
+	}
+	a.syncCount = 0
+	a.syncDurationSum = 0
+}
@@ -0,0 +1,71 @@
+package runctl
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_workerAutoscaler_Adjust(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		current         int
+		syncDurations   []time.Duration
+		queueDepth      int
+		expectedWorkers int
+	}{
+		{"idle", 2, nil, 0, 2},
+		{"idle scale down", 5, nil, 0, 4},
+		{"no syncs but queued items", 3, nil, 10, 3},
+		{"fast syncs", 2, []time.Duration{10 * time.Millisecond}, 100, 2},
+		{"slow syncs", 2, []time.Duration{1 * time.Second, 3 * time.Second}, 30, 6},
+		{"capped at max", 2, []time.Duration{10 * time.Second}, 100, 8},
+		{"scale down by one", 6, []time.Duration{10 * time.Millisecond}, 1, 5},
+		{"not below min", 3, []time.Duration{10 * time.Millisecond}, 0, 2},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := newWorkerAutoscaler(2, 8)
+			examinee.current = tc.current
+			for _, d := range tc.syncDurations {
+				examinee.ObserveSync(d)
+			}
+
+			// EXERCISE
+			examinee.Adjust(tc.queueDepth)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedWorkers, examinee.Workers())
+			assert.Equal(t, 0, examinee.syncCount)
+			assert.Equal(t, time.Duration(0), examinee.syncDurationSum)
+		})
+	}
+}
+
+func Test_newWorkerAutoscaler_MaxBelowMin(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	examinee := newWorkerAutoscaler(4, 2)
+
+	// VERIFY
+	assert.Equal(t, 4, examinee.min)
+	assert.Equal(t, 4, examinee.max)
+	assert.Equal(t, 4, examinee.Workers())
+}
+
+func Test_workerAutoscaler_ObserveSync_Nil(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var examinee *workerAutoscaler
+
+	// EXERCISE
+	examinee.ObserveSync(1 * time.Second)
+}
@@ -91,3 +91,7 @@ func TimeEqual(expectedAsRFC3339 string, actual metav1.Time) cmp.Comparison {
 		return cmp.ResultSuccess
 	}
 }
+
+func boolPtr(val bool) *bool { return &val }
+
+func int64Ptr(val int64) *int64 { return &val }
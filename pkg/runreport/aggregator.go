@@ -0,0 +1,198 @@
+// Package runreport periodically summarizes the recently finished pipeline
+// runs of the tenants of each client namespace in a RunReport object, so
+// that clients without access to the metrics of the Steward installation
+// get insight into their usage.
+package runreport
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardv1alpha1listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	leaderelection "github.com/SAP/stewardci-core/pkg/leaderelection"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	wait "k8s.io/apimachinery/pkg/util/wait"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// ReportName is the name of the RunReport object in each client
+	// namespace.
+	ReportName = "pipeline-runs"
+
+	// DefaultWindow is the default time window of run reports.
+	DefaultWindow = 24 * time.Hour
+
+	// DefaultTopFailingPipelines is the default maximum number of
+	// failing pipelines listed in a run report.
+	DefaultTopFailingPipelines = 5
+)
+
+// Aggregator writes a RunReport object to each client namespace
+// containing tenants, summarizing the pipeline runs in the tenant
+// namespaces of these tenants.
+type Aggregator struct {
+	factory             k8s.ClientFactory
+	tenantSynced        cache.InformerSynced
+	tenantLister        stewardv1alpha1listers.TenantLister
+	pipelineRunSynced   cache.InformerSynced
+	pipelineRunLister   stewardv1alpha1listers.PipelineRunLister
+	interval            time.Duration
+	window              time.Duration
+	topFailingPipelines int
+	leaderElection      *leaderelection.Config
+	testing             *aggregatorTesting
+}
+
+type aggregatorTesting struct {
+	nowStub func() time.Time
+}
+
+// AggregatorOpts stores options for the construction of an Aggregator
+// instance.
+type AggregatorOpts struct {
+	// Interval is the time between two updates of the run reports.
+	Interval time.Duration
+
+	// Window is the time window summarized by the run reports, ending
+	// at the time of the update.
+	// If zero or negative, DefaultWindow is used.
+	Window time.Duration
+
+	// TopFailingPipelines is the maximum number of failing pipelines
+	// listed in a run report.
+	// If zero or negative, DefaultTopFailingPipelines is used.
+	TopFailingPipelines int
+
+	// LeaderElection configures the election of a leader among multiple
+	// replicas of the controller. Only the leader writes run reports.
+	// If nil, leader election is disabled.
+	LeaderElection *leaderelection.Config
+}
+
+// NewAggregator creates a new Aggregator.
+func NewAggregator(factory k8s.ClientFactory, opts AggregatorOpts) *Aggregator {
+	tenantInformers := k8s.NewTenantInformers(factory)
+	pipelineRunInformers := k8s.NewPipelineRunInformers(factory)
+
+	aggregator := &Aggregator{
+		factory:             factory,
+		tenantSynced:        tenantInformers.HasSynced,
+		tenantLister:        tenantInformers.Lister(),
+		pipelineRunSynced:   pipelineRunInformers.HasSynced,
+		pipelineRunLister:   pipelineRunInformers.Lister(),
+		interval:            opts.Interval,
+		window:              opts.Window,
+		topFailingPipelines: opts.TopFailingPipelines,
+	}
+	if aggregator.window <= 0 {
+		aggregator.window = DefaultWindow
+	}
+	if aggregator.topFailingPipelines <= 0 {
+		aggregator.topFailingPipelines = DefaultTopFailingPipelines
+	}
+	if opts.LeaderElection != nil {
+		copyOfConfig := *opts.LeaderElection
+		aggregator.leaderElection = &copyOfConfig
+	}
+	return aggregator
+}
+
+// Run runs the aggregator until the stop channel is closed.
+func (a *Aggregator) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+
+	klog.V(2).Infof("Sync cache")
+	if ok := cache.WaitForCacheSync(stopCh, a.tenantSynced, a.pipelineRunSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	run := func(stopCh <-chan struct{}) {
+		wait.Until(func() {
+			if err := a.aggregate(context.Background()); err != nil {
+				utilruntime.HandleError(err)
+			}
+		}, a.interval, stopCh)
+	}
+	if a.leaderElection != nil {
+		return leaderelection.Run(a.factory.CoordinationV1(), *a.leaderElection, stopCh, run)
+	}
+	run(stopCh)
+	return nil
+}
+
+// aggregate writes the run reports of all client namespaces containing
+// tenants. Failing namespaces do not prevent the update of the others.
+func (a *Aggregator) aggregate(ctx context.Context) error {
+	tenants, err := a.tenantLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list tenants: %s", err.Error())
+	}
+	runsByNamespace := map[string][]*api.PipelineRun{}
+	for _, tenant := range tenants {
+		clientNamespace := tenant.GetNamespace()
+		runs := runsByNamespace[clientNamespace]
+		if tenantNamespace := tenant.Status.TenantNamespaceName; tenantNamespace != "" {
+			tenantRuns, err := a.pipelineRunLister.PipelineRuns(tenantNamespace).List(labels.Everything())
+			if err != nil {
+				return fmt.Errorf("failed to list pipeline runs in tenant namespace %q: %s", tenantNamespace, err.Error())
+			}
+			runs = append(runs, tenantRuns...)
+		}
+		runsByNamespace[clientNamespace] = runs
+	}
+
+	end := a.now()
+	start := end.Add(-a.window)
+	failed := 0
+	for namespace, runs := range runsByNamespace {
+		summary := Summarize(runs, start, end, a.topFailingPipelines)
+		if err := a.writeReport(ctx, namespace, summary); err != nil {
+			utilruntime.HandleError(err)
+			failed++
+		}
+	}
+	klog.V(3).Infof("Updated run reports of %d namespaces", len(runsByNamespace)-failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to update run reports of %d namespaces", failed)
+	}
+	return nil
+}
+
+// writeReport creates or updates the run report in the given namespace.
+func (a *Aggregator) writeReport(ctx context.Context, namespace string, summary api.RunReportSummary) error {
+	client := a.factory.StewardV1alpha1().RunReports(namespace)
+	report, err := client.Get(ctx, ReportName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		report = &api.RunReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ReportName,
+				Namespace: namespace,
+			},
+			Summary: summary,
+		}
+		_, err = client.Create(ctx, report, metav1.CreateOptions{})
+	} else if err == nil {
+		report = report.DeepCopy()
+		report.Summary = summary
+		_, err = client.Update(ctx, report, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write run report '%s/%s': %s", namespace, ReportName, err.Error())
+	}
+	return nil
+}
+
+func (a *Aggregator) now() time.Time {
+	if a.testing != nil && a.testing.nowStub != nil {
+		return a.testing.nowStub()
+	}
+	return time.Now()
+}
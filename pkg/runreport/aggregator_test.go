@@ -0,0 +1,79 @@
+package runreport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTenant(name, clientNamespace, tenantNamespace string) *api.Tenant {
+	tenant := k8sfake.Tenant(name, clientNamespace)
+	tenant.Status.TenantNamespaceName = tenantNamespace
+	return tenant
+}
+
+func newTestAggregator(t *testing.T, reports []*api.RunReport, tenants []*api.Tenant, runs []*api.PipelineRun) (*Aggregator, *k8sfake.ClientFactory) {
+	t.Helper()
+	var objects []runtime.Object
+	for _, report := range reports {
+		objects = append(objects, report)
+	}
+	cf := k8sfake.NewClientFactory(objects...)
+	informers := cf.StewardInformerFactory().Steward().V1alpha1()
+	for _, tenant := range tenants {
+		assert.NilError(t, informers.Tenants().Informer().GetStore().Add(tenant))
+	}
+	for _, run := range runs {
+		assert.NilError(t, informers.PipelineRuns().Informer().GetStore().Add(run))
+	}
+	aggregator := NewAggregator(cf, AggregatorOpts{Interval: time.Minute})
+	aggregator.testing = &aggregatorTesting{nowStub: func() time.Time { return windowEnd }}
+	return aggregator, cf
+}
+
+func getReport(t *testing.T, cf *k8sfake.ClientFactory, namespace string) *api.RunReport {
+	t.Helper()
+	report, err := cf.StewardV1alpha1().RunReports(namespace).Get(context.TODO(), ReportName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	return report
+}
+
+func Test_Aggregator_aggregate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	run1 := newFinishedRun("run1", "repo1", api.ResultSuccess, time.Hour, time.Minute)
+	run1.SetNamespace("tenantns1")
+	run2 := newFinishedRun("run2", "repo1", api.ResultErrorContent, time.Hour, time.Minute)
+	run2.SetNamespace("tenantns2")
+	// not in a tenant namespace
+	run3 := newFinishedRun("run3", "repo1", api.ResultErrorContent, time.Hour, time.Minute)
+	run3.SetNamespace("otherns")
+	tenants := []*api.Tenant{
+		newTenant("tenant1", "client1", "tenantns1"),
+		newTenant("tenant2", "client1", "tenantns2"),
+		newTenant("tenant3", "client2", ""),
+	}
+	existing := k8sfake.RunReport(ReportName, "client2", api.RunReportSummary{Total: 42})
+	examinee, cf := newTestAggregator(t, []*api.RunReport{existing}, tenants, []*api.PipelineRun{run1, run2, run3})
+
+	// EXERCISE
+	err := examinee.aggregate(context.Background())
+
+	// VERIFY
+	assert.NilError(t, err)
+	report := getReport(t, cf, "client1")
+	assert.Equal(t, 2, report.Summary.Total)
+	assert.Equal(t, 1, report.Summary.Failed)
+	assert.Equal(t, windowEnd.Add(-DefaultWindow), report.Summary.WindowStart.Time)
+	report = getReport(t, cf, "client2")
+	assert.Equal(t, 0, report.Summary.Total)
+	_, err = cf.StewardV1alpha1().RunReports("otherns").Get(context.TODO(), ReportName, metav1.GetOptions{})
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,106 @@
+package runreport
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// failureResults are the results counted as failures.
+var failureResults = map[api.Result]bool{
+	api.ResultErrorInfra:   true,
+	api.ResultErrorContent: true,
+	api.ResultErrorConfig:  true,
+	api.ResultTimeout:      true,
+}
+
+type pipelineKey struct {
+	repoURL      string
+	relativePath string
+}
+
+// Summarize returns the summary of the given pipeline runs finished
+// within the time window from start (inclusive) to end (exclusive),
+// listing at most topFailingPipelines failing pipelines.
+func Summarize(runs []*api.PipelineRun, start, end time.Time, topFailingPipelines int) api.RunReportSummary {
+	summary := api.RunReportSummary{
+		WindowStart: metav1.NewTime(start),
+		WindowEnd:   metav1.NewTime(end),
+	}
+	var durations []time.Duration
+	failing := map[pipelineKey]*api.FailingPipeline{}
+	lastFailure := map[pipelineKey]time.Time{}
+
+	for _, run := range runs {
+		finishedAt := run.Status.FinishedAt
+		if finishedAt == nil || finishedAt.Time.Before(start) || !finishedAt.Time.Before(end) {
+			continue
+		}
+		summary.Total++
+		if summary.Results == nil {
+			summary.Results = map[api.Result]int{}
+		}
+		summary.Results[run.Status.Result]++
+		if startedAt := run.Status.StartedAt; startedAt != nil {
+			durations = append(durations, finishedAt.Sub(startedAt.Time))
+		}
+		if !failureResults[run.Status.Result] {
+			continue
+		}
+		summary.Failed++
+		key := pipelineKey{
+			repoURL:      run.Spec.JenkinsFile.URL,
+			relativePath: run.Spec.JenkinsFile.Path,
+		}
+		pipeline := failing[key]
+		if pipeline == nil {
+			pipeline = &api.FailingPipeline{
+				RepoURL:      key.repoURL,
+				RelativePath: key.relativePath,
+			}
+			failing[key] = pipeline
+		}
+		pipeline.Failures++
+		if last, ok := lastFailure[key]; !ok || finishedAt.Time.After(last) {
+			lastFailure[key] = finishedAt.Time
+			pipeline.LastFailedRun = run.GetName()
+		}
+	}
+
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		summary.DurationP50 = &metav1.Duration{Duration: percentile(durations, 0.5)}
+		summary.DurationP95 = &metav1.Duration{Duration: percentile(durations, 0.95)}
+	}
+
+	for _, pipeline := range failing {
+		summary.TopFailingPipelines = append(summary.TopFailingPipelines, *pipeline)
+	}
+	sort.Slice(summary.TopFailingPipelines, func(i, j int) bool {
+		a, b := summary.TopFailingPipelines[i], summary.TopFailingPipelines[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.RepoURL != b.RepoURL {
+			return a.RepoURL < b.RepoURL
+		}
+		return a.RelativePath < b.RelativePath
+	})
+	if len(summary.TopFailingPipelines) > topFailingPipelines {
+		summary.TopFailingPipelines = summary.TopFailingPipelines[:topFailingPipelines]
+	}
+	return summary
+}
+
+// percentile returns the p-th percentile of the given sorted durations
+// using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
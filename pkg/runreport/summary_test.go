@@ -0,0 +1,98 @@
+package runreport
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var windowEnd = time.Date(2022, 3, 7, 12, 0, 0, 0, time.UTC)
+
+func newFinishedRun(name, repoURL string, result api.Result, finishedBefore, duration time.Duration) *api.PipelineRun {
+	run := k8sfake.PipelineRun(name, "ns1", api.PipelineSpec{
+		JenkinsFile: api.JenkinsFile{URL: repoURL, Path: "Jenkinsfile"},
+	})
+	finishedAt := metav1.NewTime(windowEnd.Add(-finishedBefore))
+	startedAt := metav1.NewTime(finishedAt.Add(-duration))
+	run.Status.StartedAt = &startedAt
+	run.Status.FinishedAt = &finishedAt
+	run.Status.Result = result
+	return run
+}
+
+func Test_Summarize(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	start := windowEnd.Add(-24 * time.Hour)
+	runs := []*api.PipelineRun{
+		newFinishedRun("run1", "repo1", api.ResultSuccess, 1*time.Hour, 1*time.Minute),
+		newFinishedRun("run2", "repo1", api.ResultErrorContent, 2*time.Hour, 2*time.Minute),
+		newFinishedRun("run3", "repo1", api.ResultErrorContent, 3*time.Hour, 3*time.Minute),
+		newFinishedRun("run4", "repo2", api.ResultTimeout, 4*time.Hour, 4*time.Minute),
+		newFinishedRun("run5", "repo3", api.ResultAborted, 5*time.Hour, 5*time.Minute),
+		newFinishedRun("run6", "repo4", api.ResultErrorInfra, 6*time.Hour, 6*time.Minute),
+		// outside of the window
+		newFinishedRun("run7", "repo1", api.ResultErrorContent, 25*time.Hour, 1*time.Minute),
+		// not finished
+		k8sfake.PipelineRun("run8", "ns1", api.PipelineSpec{}),
+	}
+
+	// EXERCISE
+	summary := Summarize(runs, start, windowEnd, 2)
+
+	// VERIFY
+	assert.DeepEqual(t, api.RunReportSummary{
+		WindowStart: metav1.NewTime(start),
+		WindowEnd:   metav1.NewTime(windowEnd),
+		Total:       6,
+		Failed:      4,
+		Results: map[api.Result]int{
+			api.ResultSuccess:      1,
+			api.ResultErrorContent: 2,
+			api.ResultTimeout:      1,
+			api.ResultAborted:      1,
+			api.ResultErrorInfra:   1,
+		},
+		DurationP50: &metav1.Duration{Duration: 3 * time.Minute},
+		DurationP95: &metav1.Duration{Duration: 6 * time.Minute},
+		TopFailingPipelines: []api.FailingPipeline{
+			{RepoURL: "repo1", RelativePath: "Jenkinsfile", Failures: 2, LastFailedRun: "run2"},
+			{RepoURL: "repo2", RelativePath: "Jenkinsfile", Failures: 1, LastFailedRun: "run4"},
+		},
+	}, summary)
+}
+
+func Test_Summarize_NoRuns(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	summary := Summarize(nil, windowEnd.Add(-time.Hour), windowEnd, 5)
+
+	// VERIFY
+	assert.Equal(t, 0, summary.Total)
+	assert.Assert(t, summary.DurationP50 == nil)
+	assert.Assert(t, summary.DurationP95 == nil)
+	assert.Assert(t, summary.TopFailingPipelines == nil)
+}
+
+func Test_percentile(t *testing.T) {
+	t.Parallel()
+
+	durations := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for _, tc := range []struct {
+		p        float64
+		expected time.Duration
+	}{
+		{0, 1},
+		{0.5, 5},
+		{0.95, 10},
+		{1, 10},
+	} {
+		assert.Equal(t, tc.expected, percentile(durations, tc.p), tc.p)
+	}
+}
@@ -0,0 +1,223 @@
+/*
+based on sample-controller from https://github.com/kubernetes/sample-controller/blob/7047ee6ceceef2118a2017bbfff4a86c1f56f1ca/controller.go
+*/
+
+package schedulectl
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardv1alpha1listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	sanitize "github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	leaderelection "github.com/SAP/stewardci-core/pkg/leaderelection"
+	recovery "github.com/SAP/stewardci-core/pkg/utils/recovery"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	wait "k8s.io/apimachinery/pkg/util/wait"
+	cache "k8s.io/client-go/tools/cache"
+	workqueue "k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+)
+
+// workqueueName is the name of the work queue of the controller.
+const workqueueName = "pipelineschedules"
+
+// Controller creates pipeline runs according to PipelineSchedules.
+type Controller struct {
+	factory           k8s.ClientFactory
+	scheduleSynced    cache.InformerSynced
+	scheduleLister    stewardv1alpha1listers.PipelineScheduleLister
+	pipelineRunSynced cache.InformerSynced
+	pipelineRunLister stewardv1alpha1listers.PipelineRunLister
+	workqueue         workqueue.RateLimitingInterface
+	testing           *controllerTesting
+
+	maxRetries int
+
+	leaderElection *leaderelection.Config
+}
+
+type controllerTesting struct {
+	nowStub func() time.Time
+}
+
+// ControllerOpts stores options for the construction of a Controller
+// instance.
+type ControllerOpts struct {
+	// WorkqueueRateLimiter determines the delays of schedules requeued
+	// after failed reconciliations.
+	// If nil, client-go's workqueue.DefaultControllerRateLimiter() is used.
+	WorkqueueRateLimiter workqueue.RateLimiter
+
+	// MaxRetries is the maximum number of retries of a failed
+	// reconciliation. If exceeded, the item is dropped from the
+	// workqueue until it gets queued again by the next change or resync.
+	// If zero or negative, failed reconciliations are retried forever.
+	MaxRetries int
+
+	// LeaderElection configures the election of a leader among multiple
+	// replicas of the controller. Only the leader processes schedules.
+	// If nil, leader election is disabled.
+	LeaderElection *leaderelection.Config
+}
+
+// NewController creates a new Controller.
+func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
+	scheduleInformers := k8s.NewPipelineScheduleInformers(factory)
+	pipelineRunInformers := k8s.NewPipelineRunInformers(factory)
+
+	rateLimiter := opts.WorkqueueRateLimiter
+	if rateLimiter == nil {
+		rateLimiter = workqueue.DefaultControllerRateLimiter()
+	}
+
+	controller := &Controller{
+		factory:           factory,
+		scheduleSynced:    scheduleInformers.HasSynced,
+		scheduleLister:    scheduleInformers.Lister(),
+		pipelineRunSynced: pipelineRunInformers.HasSynced,
+		pipelineRunLister: pipelineRunInformers.Lister(),
+		workqueue:         workqueue.NewNamedRateLimitingQueue(rateLimiter, workqueueName),
+		maxRetries:        opts.MaxRetries,
+	}
+	if opts.LeaderElection != nil {
+		copyOfConfig := *opts.LeaderElection
+		controller.leaderElection = &copyOfConfig
+	}
+
+	scheduleInformers.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.onScheduleChange,
+		UpdateFunc: func(_, new interface{}) { controller.onScheduleChange(new) },
+	})
+	// the active pipeline runs of a schedule are part of its status
+	pipelineRunInformers.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, new interface{}) { controller.onPipelineRunChange(new) },
+		DeleteFunc: controller.onPipelineRunChange,
+	})
+	return controller
+}
+
+// Run runs the controller.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.V(2).Infof("Sync cache")
+	if ok := cache.WaitForCacheSync(stopCh, c.scheduleSynced, c.pipelineRunSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	if c.leaderElection != nil {
+		return leaderelection.Run(c.factory.CoordinationV1(), *c.leaderElection, stopCh, func(stopCh <-chan struct{}) {
+			c.runWorkers(threadiness, stopCh)
+		})
+	}
+	c.runWorkers(threadiness, stopCh)
+	return nil
+}
+
+// runWorkers starts the workers and blocks until the stop channel is
+// closed.
+func (c *Controller) runWorkers(threadiness int, stopCh <-chan struct{}) {
+	klog.V(2).Infof("Start workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	klog.V(2).Infof("Workers running [%v]", threadiness)
+
+	<-stopCh
+	klog.V(2).Infof("Workers stopped")
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		key, ok := obj.(string)
+		if !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", sanitize.Object(obj)))
+			return nil
+		}
+		if err := c.syncHandlerRecovering(key); err != nil {
+			if c.maxRetries > 0 && c.workqueue.NumRequeues(obj) >= c.maxRetries {
+				c.workqueue.Forget(obj)
+				return fmt.Errorf("error syncing '%s': %s, dropping after %d retries", key, err.Error(), c.maxRetries)
+			}
+			c.workqueue.AddRateLimited(obj)
+			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+		}
+		c.workqueue.Forget(obj)
+		klog.V(5).Infof("Finished syncing '%s'", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+	return true
+}
+
+// syncHandlerRecovering calls syncHandler and converts a panic into a sync
+// error, so that a single object cannot crash the whole controller.
+func (c *Controller) syncHandlerRecovering(key string) error {
+	err := recovery.Call(func() error {
+		return c.syncHandler(key)
+	})
+	if panicErr, ok := err.(*recovery.PanicError); ok {
+		klog.Errorf("%s while syncing '%s'\n%s", panicErr.Error(), key, panicErr.Stack)
+	}
+	return err
+}
+
+func (c *Controller) onScheduleChange(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// onPipelineRunChange queues the schedule that created the changed
+// pipeline run, if any.
+func (c *Controller) onPipelineRunChange(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	run, ok := obj.(*api.PipelineRun)
+	if !ok {
+		return
+	}
+	if scheduleName := run.GetLabels()[api.LabelPipelineSchedule]; scheduleName != "" {
+		c.workqueue.Add(run.GetNamespace() + "/" + scheduleName)
+	}
+}
+
+func (c *Controller) now() time.Time {
+	if c.testing != nil && c.testing.nowStub != nil {
+		return c.testing.nowStub()
+	}
+	return time.Now()
+}
+
+func (c *Controller) formatLog(schedule *api.PipelineSchedule, v ...interface{}) string {
+	return fmt.Sprintf(
+		"pipeline schedule '%s/%s': %s",
+		schedule.GetNamespace(), schedule.GetName(),
+		fmt.Sprint(v...),
+	)
+}
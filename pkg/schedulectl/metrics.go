@@ -0,0 +1,20 @@
+package schedulectl
+
+import (
+	metrics "github.com/SAP/stewardci-core/pkg/metrics"
+	metricswq "github.com/SAP/stewardci-core/pkg/metrics/workqueue"
+)
+
+func init() {
+	// register name provider for workqueue metrics
+	metricswq.RegisterNameProvider(
+		metricswq.NameProviderFunc(
+			func(queueName string) (string, bool) {
+				if queueName == workqueueName {
+					return metrics.Subsystem + "_pipelineschedules_workqueue", true
+				}
+				return "", false
+			},
+		),
+	)
+}
@@ -0,0 +1,223 @@
+package schedulectl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/cron"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	errors "github.com/pkg/errors"
+	equality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// maxMissedScheduleTimes is the number of missed scheduled times after
+// which the search for the most recent one skips to the last hour.
+const maxMissedScheduleTimes = 100
+
+// syncHandler creates the pipeline run due for the schedule with the
+// given key, if any, and updates the status of the schedule.
+func (c *Controller) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	origSchedule, err := c.scheduleLister.PipelineSchedules(namespace).Get(name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if slabels.IsLabelledAsIgnore(origSchedule) {
+		return nil
+	}
+	schedule := origSchedule.DeepCopy()
+	ctx := context.Background()
+
+	err = c.reconcile(ctx, schedule)
+	if equality.Semantic.DeepEqual(origSchedule.Status, schedule.Status) {
+		return err
+	}
+	if _, updateErr := c.factory.StewardV1alpha1().PipelineSchedules(namespace).UpdateStatus(ctx, schedule, metav1.UpdateOptions{}); updateErr != nil {
+		if err == nil {
+			err = errors.Wrap(updateErr, "failed to update status")
+		}
+	}
+	return err
+}
+
+// reconcile creates the pipeline run due for the given schedule, if any,
+// and updates the status of the given schedule object accordingly.
+// The schedule is requeued for the next scheduled time.
+func (c *Controller) reconcile(ctx context.Context, schedule *api.PipelineSchedule) error {
+	now := c.now()
+
+	cronSchedule, err := cron.Parse(schedule.Spec.Schedule)
+	if err != nil {
+		schedule.Status.Message = fmt.Sprintf("invalid schedule: %s", err.Error())
+		return nil
+	}
+	location, err := time.LoadLocation(schedule.Spec.TimeZone)
+	if err != nil {
+		schedule.Status.Message = fmt.Sprintf("invalid time zone: %s", err.Error())
+		return nil
+	}
+
+	active, err := c.activePipelineRuns(schedule)
+	if err != nil {
+		return err
+	}
+	schedule.Status.Active = names(active)
+
+	if schedule.Spec.Suspend {
+		schedule.Status.Message = "suspended"
+		return nil
+	}
+
+	next := cronSchedule.Next(now.In(location))
+	if !next.IsZero() {
+		c.workqueue.AddAfter(schedule.GetNamespace()+"/"+schedule.GetName(), next.Sub(now))
+	}
+
+	earliest := schedule.GetCreationTimestamp().Time
+	if schedule.Status.LastScheduleTime != nil {
+		earliest = schedule.Status.LastScheduleTime.Time
+	}
+	if deadline := schedule.Spec.StartingDeadlineSeconds; deadline != nil {
+		if start := now.Add(-time.Duration(*deadline) * time.Second); start.After(earliest) {
+			earliest = start
+		}
+	}
+	scheduledTime := mostRecentScheduleTime(cronSchedule, earliest.In(location), now)
+	if scheduledTime.IsZero() {
+		return nil
+	}
+
+	switch schedule.Spec.ConcurrencyPolicy {
+	case api.ConcurrencyPolicyForbid:
+		if len(active) > 0 {
+			schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+			schedule.Status.Message = fmt.Sprintf("skipped scheduled time %s because pipeline runs are still active", scheduledTime.Format(time.RFC3339))
+			klog.V(3).Infof(c.formatLog(schedule, schedule.Status.Message))
+			return nil
+		}
+	case api.ConcurrencyPolicyReplace:
+		for _, run := range active {
+			if err := c.abortPipelineRun(ctx, run); err != nil {
+				return err
+			}
+		}
+	}
+
+	run, err := c.createPipelineRun(ctx, schedule, scheduledTime)
+	if err != nil {
+		return err
+	}
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	schedule.Status.LastPipelineRunName = run.GetName()
+	if schedule.Spec.ConcurrencyPolicy == api.ConcurrencyPolicyReplace {
+		schedule.Status.Active = nil
+	}
+	schedule.Status.Active = append(schedule.Status.Active, run.GetName())
+	schedule.Status.Message = fmt.Sprintf("created pipeline run %q for scheduled time %s", run.GetName(), scheduledTime.Format(time.RFC3339))
+	klog.V(3).Infof(c.formatLog(schedule, schedule.Status.Message))
+	return nil
+}
+
+// mostRecentScheduleTime returns the most recent time matching the
+// given cron schedule after `earliest` and not after `now`, or the zero
+// time if there is none. The cron schedule is evaluated in the location
+// of `earliest`.
+func mostRecentScheduleTime(cronSchedule *cron.Schedule, earliest, now time.Time) time.Time {
+	var result time.Time
+	missed := 0
+	for t := cronSchedule.Next(earliest); !t.IsZero() && !t.After(now); t = cronSchedule.Next(t) {
+		result = t
+		missed++
+		if missed == maxMissedScheduleTimes {
+			// avoid iterating over all times missed by frequent schedules
+			if skip := now.Add(-time.Hour).In(earliest.Location()); skip.After(t) {
+				t = skip
+			}
+		}
+	}
+	return result
+}
+
+// activePipelineRuns returns the unfinished pipeline runs created by the
+// given schedule.
+func (c *Controller) activePipelineRuns(schedule *api.PipelineSchedule) ([]*api.PipelineRun, error) {
+	selector := labels.SelectorFromSet(labels.Set{api.LabelPipelineSchedule: schedule.GetName()})
+	runs, err := c.pipelineRunLister.PipelineRuns(schedule.GetNamespace()).List(selector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pipeline runs")
+	}
+	var result []*api.PipelineRun
+	for _, run := range runs {
+		if metav1.IsControlledBy(run, schedule) && run.Status.State != api.StateFinished {
+			result = append(result, run)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].GetName() < result[j].GetName() })
+	return result, nil
+}
+
+// abortPipelineRun requests the abortion of the given pipeline run.
+func (c *Controller) abortPipelineRun(ctx context.Context, run *api.PipelineRun) error {
+	if run.Spec.Intent == api.IntentAbort {
+		return nil
+	}
+	run = run.DeepCopy()
+	run.Spec.Intent = api.IntentAbort
+	if _, err := c.factory.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Update(ctx, run, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "failed to abort pipeline run %q", run.GetName())
+	}
+	return nil
+}
+
+// createPipelineRun creates the pipeline run of the given schedule for
+// the given scheduled time. The name of the pipeline run is derived from
+// the scheduled time, so that it is created at most once even if the
+// status of the schedule could not be updated.
+func (c *Controller) createPipelineRun(ctx context.Context, schedule *api.PipelineSchedule, scheduledTime time.Time) (*api.PipelineRun, error) {
+	template := schedule.Spec.Template.DeepCopy()
+	run := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", schedule.GetName(), scheduledTime.Unix()/60),
+			Namespace:   schedule.GetNamespace(),
+			Labels:      template.Metadata.Labels,
+			Annotations: template.Metadata.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(schedule, api.SchemeGroupVersion.WithKind("PipelineSchedule")),
+			},
+		},
+		Spec: template.Spec,
+	}
+	if run.Labels == nil {
+		run.Labels = map[string]string{}
+	}
+	run.Labels[api.LabelPipelineSchedule] = schedule.GetName()
+	slabels.LabelWithInstanceID(run, c.factory.InstanceID())
+
+	_, err := c.factory.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Create(ctx, run, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return nil, errors.Wrapf(err, "failed to create pipeline run %q", run.GetName())
+	}
+	return run, nil
+}
+
+func names(runs []*api.PipelineRun) []string {
+	var result []string
+	for _, run := range runs {
+		result = append(result, run.GetName())
+	}
+	return result
+}
@@ -0,0 +1,276 @@
+package schedulectl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/cron"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// 2022-03-07 is a Monday
+var creationTime = time.Date(2022, 3, 7, 7, 30, 0, 0, time.UTC)
+
+func newTestController(t *testing.T, now time.Time, schedule *api.PipelineSchedule, runs ...*api.PipelineRun) (*Controller, *k8sfake.ClientFactory) {
+	t.Helper()
+	objects := []runtime.Object{schedule}
+	for _, run := range runs {
+		objects = append(objects, run)
+	}
+	cf := k8sfake.NewClientFactory(objects...)
+	informers := cf.StewardInformerFactory().Steward().V1alpha1()
+	assert.NilError(t, informers.PipelineSchedules().Informer().GetStore().Add(schedule))
+	for _, run := range runs {
+		assert.NilError(t, informers.PipelineRuns().Informer().GetStore().Add(run))
+	}
+	controller := NewController(cf, ControllerOpts{})
+	controller.testing = &controllerTesting{nowStub: func() time.Time { return now }}
+	return controller, cf
+}
+
+func newSchedule(spec api.PipelineScheduleSpec) *api.PipelineSchedule {
+	schedule := k8sfake.PipelineSchedule("schedule1", "ns1", spec)
+	schedule.UID = types.UID("uid1")
+	schedule.CreationTimestamp = metav1.NewTime(creationTime)
+	if schedule.Spec.Schedule == "" {
+		schedule.Spec.Schedule = "0 8 * * *"
+	}
+	schedule.Spec.Template.Spec.JenkinsFile.URL = "https://github.com/foo/bar"
+	return schedule
+}
+
+func newScheduledRun(schedule *api.PipelineSchedule, name string, state api.State) *api.PipelineRun {
+	run := k8sfake.PipelineRun(name, schedule.GetNamespace(), api.PipelineSpec{})
+	run.Labels = map[string]string{api.LabelPipelineSchedule: schedule.GetName()}
+	run.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(schedule, api.SchemeGroupVersion.WithKind("PipelineSchedule")),
+	}
+	run.Status.State = state
+	return run
+}
+
+func getSchedule(t *testing.T, cf *k8sfake.ClientFactory) *api.PipelineSchedule {
+	t.Helper()
+	schedule, err := cf.StewardV1alpha1().PipelineSchedules("ns1").Get(context.TODO(), "schedule1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	return schedule
+}
+
+func Test_Controller_syncHandler_CreatesDuePipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	schedule := newSchedule(api.PipelineScheduleSpec{})
+	schedule.Spec.Template.Metadata.Labels = map[string]string{"foo": "bar"}
+	now := time.Date(2022, 3, 7, 8, 0, 30, 0, time.UTC)
+	scheduledTime := time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)
+	examinee, cf := newTestController(t, now, schedule)
+
+	// EXERCISE
+	err := examinee.syncHandler("ns1/schedule1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	run, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(context.TODO(), "schedule1-27444000", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"foo": "bar", api.LabelPipelineSchedule: "schedule1"}, run.GetLabels())
+	assert.Assert(t, metav1.IsControlledBy(run, schedule))
+	assert.Equal(t, "https://github.com/foo/bar", run.Spec.JenkinsFile.URL)
+
+	status := getSchedule(t, cf).Status
+	assert.Assert(t, scheduledTime.Equal(status.LastScheduleTime.Time))
+	assert.Equal(t, "schedule1-27444000", status.LastPipelineRunName)
+	assert.DeepEqual(t, []string{"schedule1-27444000"}, status.Active)
+}
+
+func Test_Controller_syncHandler_DoesNothingIfNotDue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	schedule := newSchedule(api.PipelineScheduleSpec{})
+	now := time.Date(2022, 3, 7, 7, 59, 0, 0, time.UTC)
+	examinee, cf := newTestController(t, now, schedule)
+
+	// EXERCISE
+	err := examinee.syncHandler("ns1/schedule1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(runs.Items))
+	assert.Assert(t, getSchedule(t, cf).Status.LastScheduleTime == nil)
+}
+
+func Test_Controller_syncHandler_ConcurrencyPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                string
+		policy              api.ConcurrencyPolicy
+		expectedCreated     bool
+		expectedAbortIntent bool
+		expectedActive      []string
+	}{
+		{"default", "", true, false, []string{"active1", "schedule1-27444000"}},
+		{"allow", api.ConcurrencyPolicyAllow, true, false, []string{"active1", "schedule1-27444000"}},
+		{"forbid", api.ConcurrencyPolicyForbid, false, false, []string{"active1"}},
+		{"replace", api.ConcurrencyPolicyReplace, true, true, []string{"schedule1-27444000"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule := newSchedule(api.PipelineScheduleSpec{ConcurrencyPolicy: tc.policy})
+			activeRun := newScheduledRun(schedule, "active1", api.StateRunning)
+			finishedRun := newScheduledRun(schedule, "finished1", api.StateFinished)
+			now := time.Date(2022, 3, 7, 8, 0, 30, 0, time.UTC)
+			examinee, cf := newTestController(t, now, schedule, activeRun, finishedRun)
+
+			// EXERCISE
+			err := examinee.syncHandler("ns1/schedule1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			_, err = cf.StewardV1alpha1().PipelineRuns("ns1").Get(context.TODO(), "schedule1-27444000", metav1.GetOptions{})
+			if tc.expectedCreated {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, kerrors.IsNotFound(err))
+			}
+			run, err := cf.StewardV1alpha1().PipelineRuns("ns1").Get(context.TODO(), "active1", metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedAbortIntent, run.Spec.Intent == api.IntentAbort)
+
+			status := getSchedule(t, cf).Status
+			assert.DeepEqual(t, tc.expectedActive, status.Active)
+			assert.Assert(t, status.LastScheduleTime != nil)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_Suspended(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	schedule := newSchedule(api.PipelineScheduleSpec{Suspend: true})
+	now := time.Date(2022, 3, 7, 8, 0, 30, 0, time.UTC)
+	examinee, cf := newTestController(t, now, schedule)
+
+	// EXERCISE
+	err := examinee.syncHandler("ns1/schedule1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.TODO(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(runs.Items))
+	status := getSchedule(t, cf).Status
+	assert.Equal(t, "suspended", status.Message)
+	assert.Assert(t, status.LastScheduleTime == nil)
+}
+
+func Test_Controller_syncHandler_StartingDeadline(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		deadlineSeconds int64
+		expectedCreated bool
+	}{
+		{"within_deadline", 3600, true},
+		{"deadline_exceeded", 60, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			deadline := tc.deadlineSeconds
+			schedule := newSchedule(api.PipelineScheduleSpec{StartingDeadlineSeconds: &deadline})
+			now := time.Date(2022, 3, 7, 8, 30, 0, 0, time.UTC)
+			examinee, cf := newTestController(t, now, schedule)
+
+			// EXERCISE
+			err := examinee.syncHandler("ns1/schedule1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.TODO(), metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedCreated, len(runs.Items) == 1)
+		})
+	}
+}
+
+func Test_Controller_syncHandler_InvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		spec            api.PipelineScheduleSpec
+		expectedMessage string
+	}{
+		{"invalid_schedule", api.PipelineScheduleSpec{Schedule: "0 8 * *"}, "invalid schedule: expected 5 cron fields, got 4 fields"},
+		{"invalid_time_zone", api.PipelineScheduleSpec{TimeZone: "Foo/Bar"}, "invalid time zone: "},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule := newSchedule(tc.spec)
+			now := time.Date(2022, 3, 7, 8, 0, 30, 0, time.UTC)
+			examinee, cf := newTestController(t, now, schedule)
+
+			// EXERCISE
+			err := examinee.syncHandler("ns1/schedule1")
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, is.Contains(getSchedule(t, cf).Status.Message, tc.expectedMessage))
+		})
+	}
+}
+
+func Test_mostRecentScheduleTime(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		earliest time.Time
+		now      time.Time
+		expected time.Time
+	}{
+		{"none", "0 8 * * *", time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 9, 0, 0, 0, time.UTC), time.Time{}},
+		{"one", "0 8 * * *", time.Date(2022, 3, 7, 7, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 9, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)},
+		{"exactly_now", "0 8 * * *", time.Date(2022, 3, 7, 7, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)},
+		{"several_missed", "0 8 * * *", time.Date(2022, 3, 1, 7, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 9, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 8, 0, 0, 0, time.UTC)},
+		{"many_missed", "* * * * *", time.Date(2021, 3, 7, 7, 0, 0, 0, time.UTC), time.Date(2022, 3, 7, 9, 0, 30, 0, time.UTC), time.Date(2022, 3, 7, 9, 0, 0, 0, time.UTC)},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			schedule, err := cron.Parse(tc.expr)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			result := mostRecentScheduleTime(schedule, tc.earliest, tc.now)
+
+			// VERIFY
+			assert.Assert(t, tc.expected.Equal(result), "result: %v", result)
+		})
+	}
+}
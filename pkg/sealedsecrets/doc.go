@@ -0,0 +1,10 @@
+/*
+Package sealedsecrets provides encryption and decryption of secret values
+that clients pass inline in PipelineRun objects.
+
+Values are encrypted with the public key of a key pair that is managed by
+the run controller and stored as Kubernetes secret in the Steward system
+namespace. Only the run controller can decrypt the values using the
+private key.
+*/
+package sealedsecrets
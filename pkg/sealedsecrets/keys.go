@@ -0,0 +1,123 @@
+package sealedsecrets
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// KeySecretName is the name of the secret in the Steward system
+	// namespace that stores the key pair for sealed secrets.
+	KeySecretName = "steward-sealed-secrets-key"
+
+	// KeySecretPrivateKeyField is the data key of the key secret
+	// containing the PEM-encoded private key.
+	KeySecretPrivateKeyField = "private.pem"
+
+	// KeySecretPublicKeyField is the data key of the key secret
+	// containing the PEM-encoded public key. Clients need the public key
+	// to encrypt secret values.
+	KeySecretPublicKeyField = "public.pem"
+)
+
+// keySize is the size in bits of generated RSA keys.
+// It is a variable to allow smaller keys in tests.
+var keySize = 4096
+
+// LoadOrCreateKey returns the private key stored in the key secret.
+// If the key secret does not exist, a new key pair is generated and
+// stored.
+func LoadOrCreateKey(ctx context.Context, secretsClient corev1.SecretInterface) (*rsa.PrivateKey, error) {
+	secret, err := secretsClient.Get(ctx, KeySecretName, metav1.GetOptions{})
+	if err == nil {
+		return parsePrivateKey(secret)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get secret %q", KeySecretName)
+	}
+
+	klog.V(2).Infof("generating new key pair for sealed secrets in secret %q", KeySecretName)
+	secret, err = newKeySecret()
+	if err != nil {
+		return nil, err
+	}
+	created, err := secretsClient.Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, errors.Wrapf(err, "failed to create secret %q", KeySecretName)
+		}
+		// another controller instance has been faster
+		created, err = secretsClient.Get(ctx, KeySecretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get secret %q", KeySecretName)
+		}
+	}
+	return parsePrivateKey(created)
+}
+
+func newKeySecret() (*v1.Secret, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key pair")
+	}
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal public key")
+	}
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: KeySecretName,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			KeySecretPrivateKeyField: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+			}),
+			KeySecretPublicKeyField: pem.EncodeToMemory(&pem.Block{
+				Type:  "PUBLIC KEY",
+				Bytes: publicKeyBytes,
+			}),
+		},
+	}, nil
+}
+
+func parsePrivateKey(secret *v1.Secret) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(secret.Data[KeySecretPrivateKeyField])
+	if block == nil {
+		return nil, errors.Errorf("secret %q does not contain a PEM-encoded private key in field %q", secret.GetName(), KeySecretPrivateKeyField)
+	}
+	privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse private key from secret %q", secret.GetName())
+	}
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM-encoded public key as stored in field
+// KeySecretPublicKeyField of the key secret.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse public key")
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return publicKey, nil
+}
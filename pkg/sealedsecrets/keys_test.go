@@ -0,0 +1,71 @@
+package sealedsecrets
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_LoadOrCreateKey_CreatesKeySecretIfMissing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secretsClient := kubernetes.NewSimpleClientset().CoreV1().Secrets("ns1")
+
+	// EXERCISE
+	key, err := LoadOrCreateKey(ctx, secretsClient)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, key != nil)
+	secret, err := secretsClient.Get(ctx, KeySecretName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	publicKey, err := ParsePublicKey(secret.Data[KeySecretPublicKeyField])
+	assert.NilError(t, err)
+	assert.Assert(t, key.PublicKey.Equal(publicKey))
+}
+
+func Test_LoadOrCreateKey_LoadsExistingKey(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secretsClient := kubernetes.NewSimpleClientset().CoreV1().Secrets("ns1")
+	firstKey, err := LoadOrCreateKey(ctx, secretsClient)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	secondKey, err := LoadOrCreateKey(ctx, secretsClient)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, firstKey.Equal(secondKey))
+}
+
+func Test_LoadOrCreateKey_InvalidKeySecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      KeySecretName,
+			Namespace: "ns1",
+		},
+		Data: map[string][]byte{
+			KeySecretPrivateKeyField: []byte("garbage"),
+		},
+	}
+	secretsClient := kubernetes.NewSimpleClientset(secret).CoreV1().Secrets("ns1")
+
+	// EXERCISE
+	_, err := LoadOrCreateKey(ctx, secretsClient)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "does not contain a PEM-encoded private key")
+}
@@ -0,0 +1,124 @@
+package sealedsecrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// sessionKeyLength is the length in bytes of the random AES key
+	// used to encrypt a single value.
+	sessionKeyLength = 32
+
+	// encryptedSessionKeyLengthSize is the size in bytes of the length
+	// prefix of the encrypted session key.
+	encryptedSessionKeyLengthSize = 2
+)
+
+// Decrypter decrypts sealed values.
+type Decrypter interface {
+	// Decrypt decrypts the given sealed value. The label must be the
+	// same as the one used for encryption.
+	Decrypt(label string, sealedValue string) ([]byte, error)
+}
+
+type decrypter struct {
+	privateKey *rsa.PrivateKey
+}
+
+// NewDecrypter returns a Decrypter using the given private key.
+func NewDecrypter(privateKey *rsa.PrivateKey) Decrypter {
+	return &decrypter{
+		privateKey: privateKey,
+	}
+}
+
+// Seal encrypts the given plaintext with the given public key and
+// returns the base64-encoded result.
+// The label binds the sealed value to a context, e.g. a namespace.
+// A sealed value can only be decrypted with the same label.
+//
+// A random session key is used to encrypt the plaintext with AES-GCM.
+// The session key itself is encrypted with RSA-OAEP. The result has
+// the following binary layout:
+//
+//	[2 bytes length n of encrypted session key (big endian)]
+//	[n bytes encrypted session key]
+//	[remaining bytes AES-GCM ciphertext]
+func Seal(publicKey *rsa.PublicKey, label string, plaintext []byte) (string, error) {
+	sessionKey := make([]byte, sessionKeyLength)
+	if _, err := io.ReadFull(rand.Reader, sessionKey); err != nil {
+		return "", errors.Wrap(err, "failed to generate session key")
+	}
+
+	encryptedSessionKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, sessionKey, []byte(label))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encrypt session key")
+	}
+
+	aead, err := newAEAD(sessionKey)
+	if err != nil {
+		return "", err
+	}
+
+	// the session key is used only once, therefore a zero nonce is safe
+	nonce := make([]byte, aead.NonceSize())
+
+	result := make([]byte, encryptedSessionKeyLengthSize, encryptedSessionKeyLengthSize+len(encryptedSessionKey)+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint16(result, uint16(len(encryptedSessionKey)))
+	result = append(result, encryptedSessionKey...)
+	result = aead.Seal(result, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(result), nil
+}
+
+// Decrypt implements interface Decrypter.
+func (d *decrypter) Decrypt(label string, sealedValue string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(sealedValue)
+	if err != nil {
+		return nil, errors.Wrap(err, "sealed value is not base64-encoded")
+	}
+	if len(data) < encryptedSessionKeyLengthSize {
+		return nil, errors.New("sealed value is too short")
+	}
+	keyLength := int(binary.BigEndian.Uint16(data))
+	data = data[encryptedSessionKeyLengthSize:]
+	if len(data) < keyLength {
+		return nil, errors.New("sealed value is too short")
+	}
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), nil, d.privateKey, data[:keyLength], []byte(label))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt session key")
+	}
+
+	aead, err := newAEAD(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	plaintext, err := aead.Open(nil, nonce, data[keyLength:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt value")
+	}
+	return plaintext, nil
+}
+
+func newAEAD(sessionKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create block cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AEAD cipher")
+	}
+	return aead, nil
+}
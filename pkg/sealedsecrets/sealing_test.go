@@ -0,0 +1,113 @@
+package sealedsecrets
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, keySize)
+	assert.NilError(t, err)
+	return key
+}
+
+func Test_SealAndDecrypt_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	key := newTestKey(t)
+	examinee := NewDecrypter(key)
+	sealedValue, err := Seal(&key.PublicKey, "namespace1", []byte("secret value"))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, err := examinee.Decrypt("namespace1", sealedValue)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "secret value", string(result))
+}
+
+func Test_SealAndDecrypt_EmptyValue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	key := newTestKey(t)
+	examinee := NewDecrypter(key)
+	sealedValue, err := Seal(&key.PublicKey, "namespace1", []byte{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, err := examinee.Decrypt("namespace1", sealedValue)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(result))
+}
+
+func Test_Decrypt_WrongLabel(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	key := newTestKey(t)
+	examinee := NewDecrypter(key)
+	sealedValue, err := Seal(&key.PublicKey, "namespace1", []byte("secret value"))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Decrypt("namespace2", sealedValue)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to decrypt session key")
+}
+
+func Test_Decrypt_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	key := newTestKey(t)
+	otherKey := newTestKey(t)
+	examinee := NewDecrypter(otherKey)
+	sealedValue, err := Seal(&key.PublicKey, "namespace1", []byte("secret value"))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	_, err = examinee.Decrypt("namespace1", sealedValue)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to decrypt session key")
+}
+
+func Test_Decrypt_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	key := newTestKey(t)
+	for _, tc := range []struct {
+		name          string
+		sealedValue   string
+		expectedError string
+	}{
+		{"not base64", "%%%", "sealed value is not base64-encoded"},
+		{"empty", "", "sealed value is too short"},
+		{"key length exceeds data", base64.StdEncoding.EncodeToString([]byte{0xff, 0xff, 1, 2, 3}), "sealed value is too short"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := NewDecrypter(key)
+
+			// EXERCISE
+			_, err := examinee.Decrypt("namespace1", tc.sealedValue)
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
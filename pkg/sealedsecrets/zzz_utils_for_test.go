@@ -0,0 +1,6 @@
+package sealedsecrets
+
+func init() {
+	// speed up key generation in tests
+	keySize = 1024
+}
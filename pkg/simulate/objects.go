@@ -0,0 +1,91 @@
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	stewardscheme "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
+	tektonscheme "github.com/SAP/stewardci-core/pkg/tektonclient/clientset/versioned/scheme"
+	errors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	serializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	yaml "k8s.io/apimachinery/pkg/util/yaml"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
+	klog "k8s.io/klog/v2"
+)
+
+// scheme knows the Kubernetes, Steward and Tekton types relevant for
+// the simulation.
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubernetesscheme.AddToScheme(scheme))
+	utilruntime.Must(stewardscheme.AddToScheme(scheme))
+	utilruntime.Must(tektonscheme.AddToScheme(scheme))
+}
+
+// LoadObjects reads Kubernetes objects from a dump in YAML or JSON format.
+// The dump may contain multiple YAML documents and lists of objects like
+// the output of `kubectl get -o yaml`.
+// Objects of unknown kinds are skipped.
+func LoadObjects(r io.Reader) ([]runtime.Object, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	deserializer := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var result []runtime.Object
+	for {
+		raw := runtime.RawExtension{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return nil, errors.Wrap(err, "failed to parse dump")
+		}
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue
+		}
+		objects, err := decodeObjects(deserializer, raw.Raw)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, objects...)
+	}
+}
+
+// decodeObjects decodes a single object or the items of a list.
+func decodeObjects(deserializer runtime.Decoder, data []byte) ([]runtime.Object, error) {
+	list := struct {
+		metav1.TypeMeta `json:",inline"`
+		Items           []runtime.RawExtension `json:"items"`
+	}{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, errors.Wrap(err, "failed to parse object")
+	}
+	if strings.HasSuffix(list.Kind, "List") && list.Items != nil {
+		var result []runtime.Object
+		for _, item := range list.Items {
+			objects, err := decodeObjects(deserializer, item.Raw)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, objects...)
+		}
+		return result, nil
+	}
+
+	obj, gvk, err := deserializer.Decode(data, nil, nil)
+	if runtime.IsNotRegisteredError(err) {
+		klog.Warningf("skipping object of unknown kind %s %s", list.APIVersion, list.Kind)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode object of kind %s %s", list.APIVersion, list.Kind)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(*gvk)
+	return []runtime.Object{obj}, nil
+}
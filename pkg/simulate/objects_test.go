@@ -0,0 +1,67 @@
+package simulate
+
+import (
+	"strings"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_LoadObjects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	dump := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: ns1
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: steward.sap.com/v1alpha1
+  kind: Tenant
+  metadata:
+    name: tenant1
+    namespace: ns1
+- apiVersion: example.com/v1
+  kind: Unknown
+  metadata:
+    name: unknown1
+---
+{"apiVersion": "steward.sap.com/v1alpha1", "kind": "PipelineRunList", "items": [
+  {"apiVersion": "steward.sap.com/v1alpha1", "kind": "PipelineRun", "metadata": {"name": "run1", "namespace": "ns1"}}
+]}
+`
+
+	// EXERCISE
+	objects, err := LoadObjects(strings.NewReader(dump))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 3)
+	namespace, ok := objects[0].(*corev1.Namespace)
+	assert.Assert(t, ok)
+	assert.Equal(t, namespace.GetName(), "ns1")
+	tenant, ok := objects[1].(*api.Tenant)
+	assert.Assert(t, ok)
+	assert.Equal(t, tenant.GetName(), "tenant1")
+	assert.Equal(t, tenant.GroupVersionKind().Kind, "Tenant")
+	run, ok := objects[2].(*api.PipelineRun)
+	assert.Assert(t, ok)
+	assert.Equal(t, run.GetName(), "run1")
+	assert.Equal(t, run.GroupVersionKind().Group, "steward.sap.com")
+}
+
+func Test_LoadObjects_InvalidDump(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	_, err := LoadObjects(strings.NewReader("kind: [invalid"))
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to parse dump")
+}
@@ -0,0 +1,191 @@
+package simulate
+
+import (
+	"fmt"
+	"sort"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl"
+	"github.com/SAP/stewardci-core/pkg/tenantctl"
+	errors "github.com/pkg/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// Opts are the options of a simulation.
+type Opts struct {
+	// InstanceID is the identifier of the simulated Steward instance.
+	// Only objects labelled with this instance ID are reconciled. If
+	// empty, only objects without instance ID label are reconciled.
+	InstanceID string
+}
+
+// Action is a modification of an object intended by a controller.
+type Action struct {
+	// Verb is the verb of the API request, e.g. `create` or `update`.
+	Verb string
+
+	// Resource is the resource of the modified object, e.g. `namespaces`.
+	Resource string
+
+	// Subresource is the subresource modified, e.g. `status`. Empty if
+	// the object itself is modified.
+	Subresource string
+
+	// Namespace is the namespace of the modified object. Empty for
+	// cluster-scoped objects.
+	Namespace string
+
+	// Name is the name of the modified object. Might be empty, e.g. for
+	// objects created with a generated name.
+	Name string
+}
+
+func (a Action) String() string {
+	resource := a.Resource
+	if a.Subresource != "" {
+		resource = resource + "/" + a.Subresource
+	}
+	name := a.Name
+	if a.Namespace != "" {
+		name = a.Namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s %s", a.Verb, resource, name)
+}
+
+// Result is the result of the simulated reconciliation of one object.
+type Result struct {
+	// Kind is the kind of the reconciled object, i.e. `Tenant` or
+	// `PipelineRun`.
+	Kind string
+
+	// Key is the namespace and name of the reconciled object.
+	Key string
+
+	// Actions are the modifications the controller intended, in the
+	// order of the API requests.
+	Actions []Action
+
+	// Err is the error returned by the reconciliation, if any. The
+	// controller would retry the reconciliation later.
+	Err error
+}
+
+// Simulate reconciles each tenant and each pipeline run contained in the
+// given objects once with fake clients and returns the modifications the
+// controllers intended. Tenants are reconciled before pipeline runs.
+// Reconciliations see the modifications of preceding ones, like in a
+// cluster. Events recorded by the controllers are not included in the
+// results.
+// The system namespace of Steward must be set via environment variable
+// `SYSTEM_NAMESPACE`.
+func Simulate(objects []runtime.Object, opts Opts) ([]Result, error) {
+	factory := fake.NewClientFactory(objects...)
+	factory.SetInstanceID(opts.InstanceID)
+
+	tenantController := tenantctl.NewController(factory, tenantctl.ControllerOpts{})
+	runController := runctl.NewController(factory, runctl.ControllerOpts{})
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.StewardInformerFactory().Start(stopCh)
+	factory.TektonInformerFactory().Start(stopCh)
+	for informerType, synced := range factory.StewardInformerFactory().WaitForCacheSync(stopCh) {
+		if !synced {
+			return nil, errors.Errorf("failed to sync cache of %v", informerType)
+		}
+	}
+	for informerType, synced := range factory.TektonInformerFactory().WaitForCacheSync(stopCh) {
+		if !synced {
+			return nil, errors.Errorf("failed to sync cache of %v", informerType)
+		}
+	}
+
+	clientsets := []actionRecorder{
+		factory.KubernetesClientset(),
+		factory.StewardClientset(),
+		factory.TektonClientset(),
+		factory.DynamicFake(),
+	}
+	var results []Result
+	reconcile := func(kind, key string, reconcileFunc func(string) error) {
+		for _, clientset := range clientsets {
+			clientset.ClearActions()
+		}
+		result := Result{Kind: kind, Key: key}
+		result.Err = reconcileFunc(key)
+		for _, clientset := range clientsets {
+			result.Actions = append(result.Actions, modifications(clientset.Actions())...)
+		}
+		results = append(results, result)
+	}
+
+	for _, key := range keysOfKind(objects, "Tenant") {
+		reconcile("Tenant", key, tenantController.Reconcile)
+	}
+	for _, key := range keysOfKind(objects, "PipelineRun") {
+		reconcile("PipelineRun", key, runController.Reconcile)
+	}
+	return results, nil
+}
+
+// actionRecorder is implemented by the fake clientsets.
+type actionRecorder interface {
+	Actions() []k8stesting.Action
+	ClearActions()
+}
+
+// keysOfKind returns the sorted keys of the Steward objects of the given
+// kind.
+func keysOfKind(objects []runtime.Object, kind string) []string {
+	var keys []string
+	for _, obj := range objects {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Group != api.SchemeGroupVersion.Group || gvk.Kind != kind {
+			continue
+		}
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// modifications converts the given actions to Action values, omitting
+// read-only actions and events.
+func modifications(actions []k8stesting.Action) []Action {
+	var result []Action
+	for _, action := range actions {
+		switch action.GetVerb() {
+		case "get", "list", "watch":
+			continue
+		}
+		if action.GetResource().Resource == "events" {
+			continue
+		}
+		result = append(result, Action{
+			Verb:        action.GetVerb(),
+			Resource:    action.GetResource().Resource,
+			Subresource: action.GetSubresource(),
+			Namespace:   action.GetNamespace(),
+			Name:        actionObjectName(action),
+		})
+	}
+	return result
+}
+
+func actionObjectName(action k8stesting.Action) string {
+	switch a := action.(type) {
+	case interface{ GetName() string }:
+		return a.GetName()
+	case interface{ GetObject() runtime.Object }:
+		if accessor, err := meta.Accessor(a.GetObject()); err == nil {
+			return accessor.GetName()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package simulate
+
+import (
+	"strings"
+	"testing"
+
+	assert "gotest.tools/assert"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_Simulate(t *testing.T) {
+	// SETUP
+	dump := `
+apiVersion: steward.sap.com/v1alpha1
+kind: PipelineRun
+metadata:
+  name: run1
+  namespace: ns1
+spec:
+  templateRef:
+    name: missing1
+---
+apiVersion: steward.sap.com/v1alpha1
+kind: Tenant
+metadata:
+  name: tenant1
+  namespace: ns1
+  labels:
+    steward.sap.com/ignore: ""
+`
+	objects, err := LoadObjects(strings.NewReader(dump))
+	assert.NilError(t, err)
+
+	// EXERCISE
+	results, err := Simulate(objects, Opts{})
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, len(results), 2)
+	assert.Equal(t, results[0].Kind, "Tenant")
+	assert.Equal(t, results[0].Key, "ns1/tenant1")
+	assert.NilError(t, results[0].Err)
+	assert.Equal(t, len(results[0].Actions), 0)
+
+	assert.Equal(t, results[1].Kind, "PipelineRun")
+	assert.Equal(t, results[1].Key, "ns1/run1")
+	assert.NilError(t, results[1].Err)
+	assert.Assert(t, len(results[1].Actions) > 0)
+	for _, action := range results[1].Actions {
+		assert.Assert(t, action.Verb != "get" && action.Verb != "list", action.String())
+		assert.Equal(t, action.Name, "run1", action.String())
+	}
+}
+
+func Test_Action_String(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		action   Action
+		expected string
+	}{
+		{"namespaced", Action{Verb: "create", Resource: "secrets", Namespace: "ns1", Name: "secret1"}, "create secrets ns1/secret1"},
+		{"cluster-scoped", Action{Verb: "delete", Resource: "namespaces", Name: "ns1"}, "delete namespaces ns1"},
+		{"subresource", Action{Verb: "update", Resource: "pipelineruns", Subresource: "status", Namespace: "ns1", Name: "run1"}, "update pipelineruns/status ns1/run1"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := tc.action.String()
+
+			// VERIFY
+			assert.Equal(t, result, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package simulate
+
+import (
+	"context"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	errors "github.com/pkg/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// Snapshot reads the objects relevant for the simulation from a live
+// cluster. It only reads objects and never modifies the cluster.
+// The data of secrets is not read into the snapshot, as the simulation
+// does not need it.
+// ConfigMaps are read from the given system namespace only.
+func Snapshot(ctx context.Context, factory k8s.ClientFactory, systemNamespace string) ([]runtime.Object, error) {
+	listOpts := metav1.ListOptions{}
+	var lists []runtime.Object
+
+	namespaces, err := factory.CoreV1().Namespaces().List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces")
+	}
+	lists = append(lists, namespaces)
+	tenants, err := factory.StewardV1alpha1().Tenants(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tenants")
+	}
+	lists = append(lists, tenants)
+	pipelineRuns, err := factory.StewardV1alpha1().PipelineRuns(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pipeline runs")
+	}
+	lists = append(lists, pipelineRuns)
+	taskRuns, err := factory.TektonV1beta1().TaskRuns(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list Tekton task runs")
+	}
+	lists = append(lists, taskRuns)
+	configMaps, err := factory.CoreV1().ConfigMaps(systemNamespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list config maps in namespace %q", systemNamespace)
+	}
+	lists = append(lists, configMaps)
+	clusterRoles, err := factory.RbacV1().ClusterRoles().List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cluster roles")
+	}
+	lists = append(lists, clusterRoles)
+	roleBindings, err := factory.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list role bindings")
+	}
+	lists = append(lists, roleBindings)
+	secrets, err := factory.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, listOpts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list secrets")
+	}
+	for i := range secrets.Items {
+		secrets.Items[i].Data = nil
+		secrets.Items[i].StringData = nil
+	}
+	lists = append(lists, secrets)
+
+	var result []runtime.Object
+	for _, list := range lists {
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if err := setGroupVersionKind(item); err != nil {
+				return nil, err
+			}
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// setGroupVersionKind sets the type information of the given object,
+// which is missing in objects returned by typed clients.
+func setGroupVersionKind(obj runtime.Object) error {
+	gvks, _, err := scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	return nil
+}
@@ -20,6 +20,32 @@ func LabelAsSystemManaged(obj metav1.Object) {
 	obj.SetLabels(labels)
 }
 
+// LabelWithInstanceID sets label `steward.sap.com/instance-id` at the
+// given object to the given Steward instance ID.
+// If the instance ID is empty, the object is not modified.
+func LabelWithInstanceID(obj metav1.Object, instanceID string) {
+	if obj == nil || instanceID == "" {
+		return
+	}
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[stewardv1alpha1.LabelInstanceID] = instanceID
+	obj.SetLabels(labels)
+}
+
+// InstanceIDSelector returns a label selector (in string representation)
+// that matches objects belonging to the Steward instance with the given
+// ID. If the instance ID is empty, the selector matches objects that
+// do not belong to any specific Steward instance.
+func InstanceIDSelector(instanceID string) string {
+	if instanceID == "" {
+		return "!" + stewardv1alpha1.LabelInstanceID
+	}
+	return stewardv1alpha1.LabelInstanceID + "=" + instanceID
+}
+
 // LabelAsIgnore sets label `steward.sap.com/ignore` at
 // the given object.
 func LabelAsIgnore(obj metav1.Object) {
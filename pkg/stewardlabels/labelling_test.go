@@ -115,6 +115,65 @@ func Test__LabelAsSystemManaged__NilArg(t *testing.T) {
 	LabelAsSystemManaged(nil)
 }
 
+func Test__LabelWithInstanceID(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		preexistingLabels map[string]string
+		instanceID        string
+		expectedLabels    map[string]string
+	}{
+		{
+			name:              "emptyID_noLabels",
+			preexistingLabels: nil,
+			instanceID:        "",
+			expectedLabels:    nil,
+		},
+		{
+			name:              "noLabels",
+			preexistingLabels: nil,
+			instanceID:        "id1",
+			expectedLabels:    map[string]string{stewardv1alpha1.LabelInstanceID: "id1"},
+		},
+		{
+			name:              "otherLabels",
+			preexistingLabels: map[string]string{"some": "value"},
+			instanceID:        "id1",
+			expectedLabels:    map[string]string{"some": "value", stewardv1alpha1.LabelInstanceID: "id1"},
+		},
+		{
+			name:              "otherInstanceID",
+			preexistingLabels: map[string]string{stewardv1alpha1.LabelInstanceID: "id2"},
+			instanceID:        "id1",
+			expectedLabels:    map[string]string{stewardv1alpha1.LabelInstanceID: "id1"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			obj := &DummyObject1{}
+			obj.SetLabels(tc.preexistingLabels)
+
+			// EXERCISE
+			LabelWithInstanceID(obj, tc.instanceID)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedLabels, obj.GetLabels())
+		})
+	}
+}
+
+func Test__LabelWithInstanceID__NilArg(t *testing.T) {
+	// EXERCISE
+	LabelWithInstanceID(nil, "id1")
+}
+
+func Test__InstanceIDSelector(t *testing.T) {
+	assert.Equal(t, "!steward.sap.com/instance-id", InstanceIDSelector(""))
+	assert.Equal(t, "steward.sap.com/instance-id=id1", InstanceIDSelector("id1"))
+}
+
 func Test__LabelAsOwnedByClientNamespace(t *testing.T) {
 	const (
 		ownerName = "owning-client-namespace-1"
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"strconv"
+	"time"
 
 	steward "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
@@ -15,17 +16,27 @@ type clientConfig interface {
 	GetTenantNamespacePrefix() string
 	GetTenantNamespaceSuffixLength() uint8
 	GetTenantRoleName() k8s.RoleName
+	IsTenantNamespaceCreationOnDemand() bool
+	GetTenantHibernationPeriod() time.Duration
+	GetTenantExpirationPolicy() string
+	GetTenantExpirationWarningPeriod() time.Duration
 }
 
 const (
 	tenantNamespaceSuffixLengthDefault uint8 = 6
 	tenantNamespaceSuffixLengthMax     uint8 = 32
+
+	tenantExpirationWarningPeriodDefault = 7 * 24 * time.Hour
 )
 
 type clientConfigImpl struct {
-	tenantNamespacePrefix       string
-	tenantNamespaceSuffixLength int64
-	tenantRoleName              k8s.RoleName
+	tenantNamespacePrefix         string
+	tenantNamespaceSuffixLength   int64
+	tenantRoleName                k8s.RoleName
+	tenantNamespaceOnDemand       bool
+	tenantHibernationPeriod       time.Duration
+	tenantExpirationPolicy        string
+	tenantExpirationWarningPeriod time.Duration
 }
 
 // getClientConfig returns the configurartion of the Steward client.
@@ -35,7 +46,9 @@ func getClientConfig(ctx context.Context, factory k8s.ClientFactory, clientNames
 	}
 
 	newConfig := clientConfigImpl{
-		tenantNamespaceSuffixLength: -1,
+		tenantNamespaceSuffixLength:   -1,
+		tenantExpirationPolicy:        steward.TenantExpirationPolicyHibernate,
+		tenantExpirationWarningPeriod: tenantExpirationWarningPeriodDefault,
 	}
 
 	namespace, err := factory.CoreV1().Namespaces().Get(ctx, clientNamespace, metav1.GetOptions{})
@@ -77,6 +90,62 @@ func getClientConfig(ctx context.Context, factory k8s.ClientFactory, clientNames
 		}
 		newConfig.tenantNamespaceSuffixLength = i
 	}
+
+	value, hasKey = annotations[steward.AnnotationTenantNamespaceCreation]
+	if hasKey {
+		switch value {
+		case "", steward.TenantNamespaceCreationEager:
+			newConfig.tenantNamespaceOnDemand = false
+		case steward.TenantNamespaceCreationOnDemand:
+			newConfig.tenantNamespaceOnDemand = true
+		default:
+			return nil, errors.Errorf(
+				"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+					" should be one of '%s' and '%s'",
+				steward.AnnotationTenantNamespaceCreation, clientNamespace, value,
+				steward.TenantNamespaceCreationEager, steward.TenantNamespaceCreationOnDemand)
+		}
+	}
+
+	value, hasKey = annotations[steward.AnnotationTenantHibernationPeriod]
+	if hasKey && value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, errors.Errorf(
+				"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+					" should be a non-negative duration like '720h'",
+				steward.AnnotationTenantHibernationPeriod, clientNamespace, value)
+		}
+		newConfig.tenantHibernationPeriod = d
+	}
+
+	value, hasKey = annotations[steward.AnnotationTenantExpirationPolicy]
+	if hasKey {
+		switch value {
+		case "", steward.TenantExpirationPolicyHibernate:
+			newConfig.tenantExpirationPolicy = steward.TenantExpirationPolicyHibernate
+		case steward.TenantExpirationPolicyDelete:
+			newConfig.tenantExpirationPolicy = steward.TenantExpirationPolicyDelete
+		default:
+			return nil, errors.Errorf(
+				"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+					" should be one of '%s' and '%s'",
+				steward.AnnotationTenantExpirationPolicy, clientNamespace, value,
+				steward.TenantExpirationPolicyHibernate, steward.TenantExpirationPolicyDelete)
+		}
+	}
+
+	value, hasKey = annotations[steward.AnnotationTenantExpirationWarningPeriod]
+	if hasKey && value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return nil, errors.Errorf(
+				"annotation '%s' on client namespace '%s' has an invalid value: '%s':"+
+					" should be a non-negative duration like '168h'",
+				steward.AnnotationTenantExpirationWarningPeriod, clientNamespace, value)
+		}
+		newConfig.tenantExpirationWarningPeriod = d
+	}
 	return &newConfig, nil
 }
 
@@ -97,3 +166,19 @@ func (c *clientConfigImpl) GetTenantNamespaceSuffixLength() uint8 {
 func (c *clientConfigImpl) GetTenantRoleName() k8s.RoleName {
 	return c.tenantRoleName
 }
+
+func (c *clientConfigImpl) IsTenantNamespaceCreationOnDemand() bool {
+	return c.tenantNamespaceOnDemand
+}
+
+func (c *clientConfigImpl) GetTenantHibernationPeriod() time.Duration {
+	return c.tenantHibernationPeriod
+}
+
+func (c *clientConfigImpl) GetTenantExpirationPolicy() string {
+	return c.tenantExpirationPolicy
+}
+
+func (c *clientConfigImpl) GetTenantExpirationWarningPeriod() time.Duration {
+	return c.tenantExpirationWarningPeriod
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"strconv"
 	"testing"
+	"time"
 
 	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	assert "gotest.tools/assert"
@@ -390,3 +391,195 @@ func Test_getClientConfig_TwoClients(t *testing.T) {
 	assert.Equal(t, uint8(6), rand1)
 	assert.Equal(t, uint8(4), rand2)
 }
+
+func Test_getClientConfig_AnnotationTenantNamespaceCreation(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		annotationValue  *string
+		expectedOnDemand bool
+		expectedErr      string
+	}{
+		{"missing", nil, false, ""},
+		{"empty", stringPtr(""), false, ""},
+		{"eager", stringPtr("eager"), false, ""},
+		{"onDemand", stringPtr("onDemand"), true, ""},
+		{"invalid", stringPtr("lazy"), false, "annotation 'steward.sap.com/tenant-namespace-creation' on client namespace 'Client1' has an invalid value: 'lazy': should be one of 'eager' and 'onDemand'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.annotationValue != nil {
+				annotations["steward.sap.com/tenant-namespace-creation"] = *tc.annotationValue
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "Client1")
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, config == nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedOnDemand, config.IsTenantNamespaceCreationOnDemand())
+			}
+		})
+	}
+}
+
+func Test_getClientConfig_AnnotationTenantHibernationPeriod(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotationValue *string
+		expectedPeriod  time.Duration
+		expectedErr     string
+	}{
+		{"missing", nil, 0, ""},
+		{"empty", stringPtr(""), 0, ""},
+		{"zero", stringPtr("0"), 0, ""},
+		{"valid", stringPtr("720h"), 720 * time.Hour, ""},
+		{"negative", stringPtr("-1h"), 0, "annotation 'steward.sap.com/tenant-hibernation-period' on client namespace 'Client1' has an invalid value: '-1h': should be a non-negative duration like '720h'"},
+		{"invalid", stringPtr("30d"), 0, "annotation 'steward.sap.com/tenant-hibernation-period' on client namespace 'Client1' has an invalid value: '30d': should be a non-negative duration like '720h'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.annotationValue != nil {
+				annotations["steward.sap.com/tenant-hibernation-period"] = *tc.annotationValue
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "Client1")
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, config == nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedPeriod, config.GetTenantHibernationPeriod())
+			}
+		})
+	}
+}
+
+func Test_getClientConfig_AnnotationTenantExpirationPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotationValue *string
+		expectedPolicy  string
+		expectedErr     string
+	}{
+		{"missing", nil, "hibernate", ""},
+		{"empty", stringPtr(""), "hibernate", ""},
+		{"hibernate", stringPtr("hibernate"), "hibernate", ""},
+		{"delete", stringPtr("delete"), "delete", ""},
+		{"invalid", stringPtr("keep"), "", "annotation 'steward.sap.com/tenant-expiration-policy' on client namespace 'Client1' has an invalid value: 'keep': should be one of 'hibernate' and 'delete'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.annotationValue != nil {
+				annotations["steward.sap.com/tenant-expiration-policy"] = *tc.annotationValue
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "Client1")
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, config == nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedPolicy, config.GetTenantExpirationPolicy())
+			}
+		})
+	}
+}
+
+func Test_getClientConfig_AnnotationTenantExpirationWarningPeriod(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotationValue *string
+		expectedPeriod  time.Duration
+		expectedErr     string
+	}{
+		{"missing", nil, 168 * time.Hour, ""},
+		{"empty", stringPtr(""), 168 * time.Hour, ""},
+		{"zero", stringPtr("0"), 0, ""},
+		{"valid", stringPtr("48h"), 48 * time.Hour, ""},
+		{"negative", stringPtr("-1h"), 0, "annotation 'steward.sap.com/tenant-expiration-warning-period' on client namespace 'Client1' has an invalid value: '-1h': should be a non-negative duration like '168h'"},
+		{"invalid", stringPtr("7d"), 0, "annotation 'steward.sap.com/tenant-expiration-warning-period' on client namespace 'Client1' has an invalid value: '7d': should be a non-negative duration like '168h'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			annotations := map[string]string{
+				"steward.sap.com/tenant-namespace-prefix": "testprefix",
+				"steward.sap.com/tenant-role":             "testrole",
+			}
+			if tc.annotationValue != nil {
+				annotations["steward.sap.com/tenant-expiration-warning-period"] = *tc.annotationValue
+			}
+			cf := fake.NewClientFactory(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "Client1",
+						Annotations: annotations,
+					},
+				},
+			)
+
+			// EXERCISE
+			config, err := getClientConfig(ctx, cf, "Client1")
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				assert.Assert(t, config == nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedPeriod, config.GetTenantExpirationWarningPeriod())
+			}
+		})
+	}
+}
+
+func stringPtr(val string) *string { return &val }
@@ -12,11 +12,17 @@ import (
 	stewardapis "github.com/SAP/stewardci-core/pkg/apis/steward"
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	stewardv1alpha1listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	sanitize "github.com/SAP/stewardci-core/pkg/k8s/sanitize"
+	leaderelection "github.com/SAP/stewardci-core/pkg/leaderelection"
 	"github.com/SAP/stewardci-core/pkg/stewardlabels"
 	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
 	metrics "github.com/SAP/stewardci-core/pkg/tenantctl/metrics"
+	throttle "github.com/SAP/stewardci-core/pkg/throttle"
 	utils "github.com/SAP/stewardci-core/pkg/utils"
+	recovery "github.com/SAP/stewardci-core/pkg/utils/recovery"
+	retry "github.com/SAP/stewardci-core/pkg/utils/retry"
 	errors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -26,6 +32,7 @@ import (
 	labels "k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	wait "k8s.io/apimachinery/pkg/util/wait"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	cache "k8s.io/client-go/tools/cache"
 	workqueue "k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
@@ -40,6 +47,10 @@ const (
 	// It is an invalid Kubernetes name to avoid conflicts with real
 	// pipeline runs.
 	heartbeatStimulusKey = "Heartbeat Stimulus"
+
+	// reconcileErrorReasonOther is the reason of failed reconciliations
+	// in the metrics if the ready condition does not have a reason code.
+	reconcileErrorReasonOther = "Other"
 )
 
 // Controller for Steward Tenants
@@ -54,12 +65,30 @@ type Controller struct {
 
 	heartbeatInterval time.Duration
 	heartbeatLogLevel *klog.Level
+
+	throttle *throttle.Throttle
+
+	namespaceDeletionLimiter *throttle.DeletionLimiter
+
+	maxRetries int
+
+	leaderElection *leaderelection.Config
+
+	runRollupWindow    time.Duration
+	pipelineRunSynced  cache.InformerSynced
+	pipelineRunLister  stewardv1alpha1listers.PipelineRunLister
+	tenantsByNamespace func(nsName string) ([]*stewardv1alpha1.Tenant, error)
+
+	networkPoliciesConfigMapName     string
+	networkPoliciesConfigMapInformer cache.SharedIndexInformer
+	networkPoliciesConfigMapLister   corev1listers.ConfigMapNamespaceLister
 }
 
 type controllerTesting struct {
 	createRoleBindingStub          func(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	getClientConfigStub            func(factory k8s.ClientFactory, clientNamespace string) (clientConfig, error)
 	listManagedRoleBindingsStub    func(namespace string) (*rbacv1.RoleBindingList, error)
+	nowStub                        func() time.Time
 	reconcileTenantRoleBindingStub func(tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) (bool, error)
 	updateStatusStub               func(tenant *stewardv1alpha1.Tenant) (*stewardv1alpha1.Tenant, error)
 }
@@ -76,32 +105,119 @@ type ControllerOpts struct {
 	// If nil, heartbeat logging is disabled and heartbeats are only
 	// exposed via metric.
 	HeartbeatLogLevel *klog.Level
+
+	// Throttle is used to throttle the controller under Kubernetes API
+	// server pressure.
+	// If nil, the controller is never throttled.
+	Throttle *throttle.Throttle
+
+	// NamespaceDeletionLimiter limits the rate at which tenant namespaces
+	// of inactive tenants and former tenant namespaces of relocated
+	// tenants are deleted. Deletions exceeding the rate are deferred.
+	// Namespaces of deleted tenants are not limited.
+	// If nil, the rate is not limited.
+	NamespaceDeletionLimiter *throttle.DeletionLimiter
+
+	// RunRollupWindow is the length of the sliding time window for which
+	// finished pipeline runs are counted in the run rollup in the status
+	// of tenants.
+	// If zero or negative, the run rollup is not maintained.
+	RunRollupWindow time.Duration
+
+	// NetworkPoliciesConfigMapName is the name of a ConfigMap in the
+	// system namespace containing NetworkPolicy manifests to be created
+	// in every tenant namespace.
+	// If empty, network policies in tenant namespaces are not managed.
+	NetworkPoliciesConfigMapName string
+
+	// WorkqueueRateLimiter determines the delays of tenants requeued
+	// after failed reconciliations and limits the overall rate of
+	// requeued items.
+	// If nil, client-go's workqueue.DefaultControllerRateLimiter() is used.
+	WorkqueueRateLimiter workqueue.RateLimiter
+
+	// MaxRetries is the maximum number of retries of a failed
+	// reconciliation. If exceeded, the item is dropped from the
+	// workqueue until it gets queued again by the next change or resync.
+	// If zero or negative, failed reconciliations are retried forever.
+	MaxRetries int
+
+	// LeaderElection configures the election of a leader among multiple
+	// replicas of the controller. Only the leader processes tenants.
+	// If nil, leader election is disabled.
+	LeaderElection *leaderelection.Config
 }
 
 // NewController creates new Controller
 func NewController(factory k8s.ClientFactory, opts ControllerOpts) *Controller {
-	informer := factory.StewardInformerFactory().Steward().V1alpha1().Tenants()
-	fetcher := k8s.NewListerBasedTenantFetcher(informer.Lister())
+	informers := k8s.NewTenantInformers(factory)
+	lister := informers.Lister()
+	fetcher := k8s.NewListerBasedTenantFetcher(lister)
+
+	rateLimiter := opts.WorkqueueRateLimiter
+	if rateLimiter == nil {
+		rateLimiter = workqueue.DefaultControllerRateLimiter()
+	}
 
 	controller := &Controller{
 		factory:      factory,
 		fetcher:      fetcher,
-		tenantSynced: informer.Informer().HasSynced,
-		tenantLister: informer.Lister(),
-		workqueue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
+		tenantSynced: informers.HasSynced,
+		tenantLister: lister,
+		workqueue:    workqueue.NewNamedRateLimitingQueue(opts.Throttle.WorkqueueRateLimiter(rateLimiter), metrics.WorkqueueName),
 	}
 
 	controller.heartbeatInterval = opts.HeartbeatInterval
+	controller.throttle = opts.Throttle
+	controller.namespaceDeletionLimiter = opts.NamespaceDeletionLimiter
+	controller.maxRetries = opts.MaxRetries
 	if opts.HeartbeatLogLevel != nil {
 		copyOfValue := *opts.HeartbeatLogLevel
 		controller.heartbeatLogLevel = &copyOfValue
 	}
+	if opts.LeaderElection != nil {
+		copyOfConfig := *opts.LeaderElection
+		controller.leaderElection = &copyOfConfig
+	}
 
-	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+	informers.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    controller.onTenantAdd,
 		UpdateFunc: controller.onTenantUpdate,
 		DeleteFunc: controller.onTenantDelete,
 	})
+
+	if err := informers.AddIndexers(cache.Indexers{k8s.TenantNamespaceIndex: k8s.TenantNamespaceIndexFunc}); err != nil {
+		klog.Errorf("failed to add tenant namespace index: %s", err.Error())
+	}
+	controller.tenantsByNamespace = func(nsName string) ([]*stewardv1alpha1.Tenant, error) {
+		return informers.ByIndex(k8s.TenantNamespaceIndex, nsName)
+	}
+
+	if opts.NetworkPoliciesConfigMapName != "" {
+		controller.networkPoliciesConfigMapName = opts.NetworkPoliciesConfigMapName
+		controller.networkPoliciesConfigMapInformer = newNetworkPoliciesConfigMapInformer(factory, opts.NetworkPoliciesConfigMapName)
+		controller.networkPoliciesConfigMapLister = newNetworkPoliciesConfigMapLister(controller.networkPoliciesConfigMapInformer.GetIndexer())
+		controller.networkPoliciesConfigMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(interface{}) { controller.onNetworkPoliciesConfigMapChange() },
+			UpdateFunc: func(interface{}, interface{}) { controller.onNetworkPoliciesConfigMapChange() },
+			DeleteFunc: func(interface{}) { controller.onNetworkPoliciesConfigMapChange() },
+		})
+	}
+
+	// pipeline runs are cached for the hibernation of inactive tenants
+	// and the run rollup
+	pipelineRunInformers := k8s.NewPipelineRunInformers(factory)
+	controller.pipelineRunSynced = pipelineRunInformers.HasSynced
+	controller.pipelineRunLister = pipelineRunInformers.Lister()
+
+	if opts.RunRollupWindow > 0 {
+		controller.runRollupWindow = opts.RunRollupWindow
+		pipelineRunInformers.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.onPipelineRunAdd,
+			UpdateFunc: controller.onPipelineRunUpdate,
+			DeleteFunc: controller.onPipelineRunDelete,
+		})
+	}
 	return controller
 }
 
@@ -123,10 +239,30 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer c.workqueue.ShutDown()
 
 	klog.V(2).Infof("Sync cache")
-	if ok := cache.WaitForCacheSync(stopCh, c.tenantSynced); !ok {
+	cacheSyncs := []cache.InformerSynced{c.tenantSynced}
+	if c.pipelineRunSynced != nil {
+		cacheSyncs = append(cacheSyncs, c.pipelineRunSynced)
+	}
+	if c.networkPoliciesConfigMapInformer != nil {
+		go c.networkPoliciesConfigMapInformer.Run(stopCh)
+		cacheSyncs = append(cacheSyncs, c.networkPoliciesConfigMapInformer.HasSynced)
+	}
+	if ok := cache.WaitForCacheSync(stopCh, cacheSyncs...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	if c.leaderElection != nil {
+		return leaderelection.Run(c.factory.CoordinationV1(), *c.leaderElection, stopCh, func(stopCh <-chan struct{}) {
+			c.runWorkers(threadiness, stopCh)
+		})
+	}
+	c.runWorkers(threadiness, stopCh)
+	return nil
+}
+
+// runWorkers starts the heartbeat stimulator and the workers and blocks
+// until the stop channel is closed.
+func (c *Controller) runWorkers(threadiness int, stopCh <-chan struct{}) {
 	if c.heartbeatInterval > 0 {
 		klog.V(2).Infof("Starting controller heartbeat stimulator with interval %s", c.heartbeatInterval)
 		go wait.Until(c.heartbeatStimulus, c.heartbeatInterval, stopCh)
@@ -135,18 +271,23 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	}
 
 	klog.V(2).Infof("Start workers")
+	workerLimiter := c.throttle.NewWorkerLimiter(threadiness)
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.Until(func() { c.runWorker(workerLimiter, stopCh) }, time.Second, stopCh)
 	}
 	klog.V(2).Infof("Workers running [%v]", threadiness)
 
 	<-stopCh
 	klog.V(2).Infof("Workers stopped")
-	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(workerLimiter *throttle.WorkerLimiter, stopCh <-chan struct{}) {
+	for workerLimiter.Acquire(stopCh) {
+		more := c.processNextWorkItem()
+		workerLimiter.Release()
+		if !more {
+			return
+		}
 	}
 }
 
@@ -184,12 +325,17 @@ func (c *Controller) processNextWorkItem() bool {
 			// Forget here else we'd go into a loop of attempting to
 			// process a work item that is invalid.
 			c.workqueue.Forget(obj)
-			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", sanitize.Object(obj)))
 			return nil
 		}
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Foo resource to be synced.
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandlerRecovering(key); err != nil {
+			if c.maxRetries > 0 && c.workqueue.NumRequeues(obj) >= c.maxRetries {
+				c.workqueue.Forget(obj)
+				metrics.WorkqueueDroppedKeys.Inc()
+				return fmt.Errorf("error syncing '%s': %s, dropping after %d retries", key, err.Error(), c.maxRetries)
+			}
 			// Put the item back on the workqueue to handle any transient errors.
 			// (The delay in case of multiple retries will increase exponentially)
 			c.workqueue.AddRateLimited(obj)
@@ -210,6 +356,29 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
+// Reconcile reconciles the tenant with the given key once, like a
+// worker of a running controller does. The informer caches must have been
+// synced before. It is meant for tools replaying reconciliations, e.g.
+// with fake clients, and must not be called while the controller runs.
+func (c *Controller) Reconcile(key string) error {
+	return c.syncHandlerRecovering(key)
+}
+
+// syncHandlerRecovering calls syncHandler and converts a panic into a sync
+// error, so that a single object cannot crash the whole controller.
+// The stack trace is logged and the object is requeued like for any other
+// sync error.
+func (c *Controller) syncHandlerRecovering(key string) error {
+	err := recovery.Call(func() error {
+		return c.syncHandler(key)
+	})
+	if panicErr, ok := err.(*recovery.PanicError); ok {
+		klog.Errorf("%s while syncing '%s'\n%s", panicErr.Error(), key, panicErr.Stack)
+		metrics.ControllerPanics.Inc()
+	}
+	return err
+}
+
 func (c *Controller) heartbeatStimulus() {
 	c.workqueue.Add(heartbeatStimulusKey)
 }
@@ -224,7 +393,7 @@ func (c *Controller) heartbeat() {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the tenant resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(key string) (err error) {
 
 	if key == heartbeatStimulusKey {
 		c.heartbeat()
@@ -239,6 +408,10 @@ func (c *Controller) syncHandler(key string) error {
 	}
 
 	if origTenant == nil {
+		if namespace, name, err := cache.SplitMetaNamespaceKey(key); err == nil {
+			metrics.TenantLastSuccessfulReconcile.Forget(namespace, name)
+		}
+		c.namespaceDeletionLimiter.Forget(key)
 		return nil
 	}
 
@@ -249,6 +422,11 @@ func (c *Controller) syncHandler(key string) error {
 
 	tenant := origTenant.DeepCopy()
 
+	start := time.Now()
+	defer func() {
+		c.observeReconcile(tenant, time.Since(start), err)
+	}()
+
 	klog.V(4).Infof(c.formatLog(tenant, "started reconciliation"))
 	if klog.V(4).Enabled() {
 		defer klog.V(4).Infof(c.formatLog(&stewardv1alpha1.Tenant{ObjectMeta: *tenant.ObjectMeta.DeepCopy()}, "finished reconciliation"))
@@ -272,8 +450,17 @@ func (c *Controller) syncHandler(key string) error {
 		if err != nil {
 			return err
 		}
+		err = c.deleteRelocationNamespaces(ctx, config, tenant)
+		if err != nil {
+			return err
+		}
+		err = c.deleteHibernationSnapshot(ctx, tenant)
+		if err != nil {
+			return err
+		}
 		_, err = c.removeFinalizerAndUpdate(ctx, tenant)
 		if err == nil {
+			metrics.TenantLastSuccessfulReconcile.Forget(tenant.GetNamespace(), tenant.GetName())
 			c.syncCount++
 		}
 		return err
@@ -289,6 +476,10 @@ func (c *Controller) syncHandler(key string) error {
 	// do not update the status if there's no change
 	if !equality.Semantic.DeepEqual(origTenant.Status, tenant.Status) {
 		if _, err := c.updateStatus(ctx, tenant); err != nil {
+			if kerrors.IsNotFound(errors.Cause(err)) && reconcileErr == nil {
+				// the tenant has been deleted in the meantime, e.g. because it expired
+				return nil
+			}
 			if !c.isInitialized(origTenant) && c.isInitialized(tenant) {
 				c.deleteTenantNamespace(ctx, tenant.Status.TenantNamespaceName, tenant, config) // clean-up ignoring error
 			}
@@ -300,53 +491,195 @@ func (c *Controller) syncHandler(key string) error {
 		return reconcileErr
 	}
 
+	metrics.TenantLastSuccessfulReconcile.ObserveSuccess(tenant.GetNamespace(), tenant.GetName())
 	c.updateMetrics()
 	c.syncCount++
 	return nil
 }
 
+// observeReconcile records the reconciliation of the given tenant in
+// the metrics.
+func (c *Controller) observeReconcile(tenant *stewardv1alpha1.Tenant, duration time.Duration, err error) {
+	if err == nil {
+		metrics.TenantReconciles.ObserveSuccess(duration)
+		return
+	}
+	metrics.TenantReconciles.ObserveError(duration, reconcileErrorReason(tenant))
+}
+
+// reconcileErrorReason returns the reason of a failed reconciliation of
+// the given tenant to be recorded in the metrics, which is the reason
+// code of the ready condition, or reconcileErrorReasonOther if the ready
+// condition is true or does not have a reason code.
+func reconcileErrorReason(tenant *stewardv1alpha1.Tenant) string {
+	if tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue() {
+		return reconcileErrorReasonOther
+	}
+	if code := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady); code != nil {
+		return string(code.ReasonCode)
+	}
+	return reconcileErrorReasonOther
+}
+
 func (c *Controller) isInitialized(tenant *stewardv1alpha1.Tenant) bool {
 	return tenant.Status.TenantNamespaceName != ""
 }
 
 func (c *Controller) reconcile(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) (err error) {
+	tenant.Status.CreatedBy = tenant.GetAnnotations()[stewardv1alpha1.AnnotationCreatedBy]
+	if c.isInitialized(tenant) && tenant.Status.PlannedTenantNamespaceName == "" {
+		// tenants initialized before the planned name has been introduced
+		tenant.Status.PlannedTenantNamespaceName = tenant.Status.TenantNamespaceName
+	}
+	expired, err := c.reconcileExpiration(ctx, config, tenant)
+	if err != nil || expired {
+		return err
+	}
 	if c.isInitialized(tenant) {
 		err = c.reconcileInitialized(ctx, config, tenant)
 	} else {
 		err = c.reconcileUninitialized(ctx, config, tenant)
 	}
+	if err == nil && c.isRunRollupEnabled() && c.isInitialized(tenant) {
+		err = c.updateRunRollup(tenant)
+	}
 	return
 }
 
 func (c *Controller) reconcileUninitialized(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
 	klog.V(3).Infof(c.formatLog(tenant, "tenant not initialized yet"))
 
-	nsName, err := c.createTenantNamespace(ctx, config, tenant)
+	if nsName := tenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace]; nsName != "" {
+		return c.reconcileUninitializedByAdoption(ctx, config, tenant, nsName)
+	}
+
+	hibernated := c.isHibernated(tenant)
+
+	if hibernated && !c.isTenantNamespaceRequested(tenant) {
+		klog.V(4).Infof(c.formatLog(tenant, "tenant is hibernated"))
+		return nil
+	}
+
+	if err := c.planTenantNamespaceName(ctx, config, tenant); err != nil {
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceCreationFailed, nil)
+		return err
+	}
+
+	if config.IsTenantNamespaceCreationOnDemand() && !c.isTenantNamespaceRequested(tenant) {
+		klog.V(4).Infof(c.formatLog(tenant, "tenant namespace will be created on demand"))
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionTrue,
+			Reason: stewardv1alpha1.StatusReasonTenantNamespaceOnDemand,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceOnDemand, nil)
+		return nil
+	}
+
+	nsName, err := c.createTenantNamespace(ctx, config, tenant, nil)
 	if err != nil {
-		condMsg := "Failed to create a new tenant namespace."
-		tenant.Status.SetCondition(&knativeapis.Condition{
-			Type:    knativeapis.ConditionReady,
-			Status:  corev1.ConditionFalse,
-			Reason:  stewardv1alpha1.StatusReasonFailed,
-			Message: condMsg,
-		})
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceCreationFailed, nil)
 		return err
 	}
 
 	_, err = c.reconcileTenantRoleBinding(ctx, tenant, nsName, config)
 	if err != nil {
-		condMsg := "Failed to initialize a new tenant namespace because the RoleBinding could not be created."
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantRoleBindingCreationFailed, nil)
+		c.deleteTenantNamespace(ctx, nsName, tenant, config) // clean-up ignoring error
+		return err
+	}
+
+	err = c.reconcileTenantNetworkPolicies(ctx, tenant, nsName)
+	if err != nil {
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantNetworkPoliciesFailed, map[string]string{"namespace": nsName})
+		c.deleteTenantNamespace(ctx, nsName, tenant, config) // clean-up ignoring error
+		return err
+	}
+
+	if hibernated {
+		err = c.restoreHibernationSnapshot(ctx, tenant, nsName)
+		if err != nil {
+			tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+				Type:   knativeapis.ConditionReady,
+				Status: corev1.ConditionFalse,
+				Reason: stewardv1alpha1.StatusReasonTenantHibernated,
+			}, stewardv1alpha1.MessageCodeTenantHibernationRestoreFailed, nil)
+			c.deleteTenantNamespace(ctx, nsName, tenant, config) // clean-up ignoring error
+			return err
+		}
+	}
+
+	tenant.Status.TenantNamespaceName = nsName
+
+	if hibernated {
 		tenant.Status.SetCondition(&knativeapis.Condition{
-			Type:    knativeapis.ConditionReady,
-			Status:  corev1.ConditionFalse,
-			Reason:  stewardv1alpha1.StatusReasonFailed,
-			Message: condMsg,
+			Type:   stewardv1alpha1.TenantConditionHibernated,
+			Status: corev1.ConditionFalse,
 		})
-		c.deleteTenantNamespace(ctx, nsName, tenant, config) // clean-up ignoring error
+	}
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+
+	return nil
+}
+
+// isTenantNamespaceRequested returns whether the creation of the tenant
+// namespace has been requested via annotation
+// stewardv1alpha1.AnnotationTenantNamespaceRequested.
+func (c *Controller) isTenantNamespaceRequested(tenant *stewardv1alpha1.Tenant) bool {
+	_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationTenantNamespaceRequested]
+	return requested
+}
+
+// reconcileUninitializedByAdoption initializes the tenant with an existing
+// namespace as tenant namespace. In contrast to a newly created tenant
+// namespace, the namespace is not deleted if the initialization fails.
+func (c *Controller) reconcileUninitializedByAdoption(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, nsName string) error {
+	klog.V(3).Infof(c.formatLogf(tenant, "adopting existing namespace %q as tenant namespace", nsName))
+
+	err := c.checkNamespaceNotAssigned(tenant, nsName)
+	if err == nil {
+		err = c.getNamespaceManager(config).Adopt(ctx, nsName, tenant.GetName())
+	}
+	if err == nil {
+		err = c.checkNamespaceHasNoPipelineRuns(ctx, nsName)
+	}
+	if err == nil {
+		_, err = c.reconcileTenantRoleBinding(ctx, tenant, nsName, config)
+	}
+	if err == nil {
+		err = c.reconcileTenantNetworkPolicies(ctx, tenant, nsName)
+	}
+	if err != nil {
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceAdoptionFailed, map[string]string{"namespace": nsName})
+		err = errors.WithMessagef(err, "failed to adopt namespace %q as tenant namespace", nsName)
+		klog.V(3).Infof(c.formatLog(tenant), err)
 		return err
 	}
 
 	tenant.Status.TenantNamespaceName = nsName
+	tenant.Status.PlannedTenantNamespaceName = nsName
 
 	tenant.Status.SetCondition(&knativeapis.Condition{
 		Type:   knativeapis.ConditionReady,
@@ -356,6 +689,38 @@ func (c *Controller) reconcileUninitialized(ctx context.Context, config clientCo
 	return nil
 }
 
+// checkNamespaceNotAssigned fails if the given namespace is the tenant
+// namespace of another tenant.
+func (c *Controller) checkNamespaceNotAssigned(tenant *stewardv1alpha1.Tenant, nsName string) error {
+	tenants, err := c.tenantsByNamespace(nsName)
+	if err != nil {
+		return errors.WithMessage(err, "failed to look up tenants by tenant namespace")
+	}
+	for _, other := range tenants {
+		if other.GetUID() != tenant.GetUID() {
+			return errors.Errorf(
+				"namespace %q is the tenant namespace of tenant %q in client namespace %q",
+				nsName, other.GetName(), other.GetNamespace(),
+			)
+		}
+	}
+	return nil
+}
+
+// checkNamespaceHasNoPipelineRuns fails if the given namespace contains
+// pipeline runs. Pipeline runs left over in an orphaned tenant namespace
+// would otherwise be picked up under the new tenant.
+func (c *Controller) checkNamespaceHasNoPipelineRuns(ctx context.Context, nsName string) error {
+	list, err := c.factory.StewardV1alpha1().PipelineRuns(nsName).List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list pipeline runs in namespace %q", nsName)
+	}
+	if len(list.Items) > 0 {
+		return errors.Errorf("refused to adopt namespace %q: namespace contains pipeline runs", nsName)
+	}
+	return nil
+}
+
 func (c *Controller) reconcileInitialized(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
 	klog.V(4).Infof(c.formatLog(tenant, "tenant is initialized already"))
 
@@ -368,39 +733,51 @@ func (c *Controller) reconcileInitialized(ctx context.Context, config clientConf
 	}
 
 	if !exists {
-		condMsg := fmt.Sprintf(
-			"The tenant namespace %q does not exist anymore."+
-				" This issue must be analyzed and fixed by an operator.",
-			nsName,
-		)
-		tenant.Status.SetCondition(&knativeapis.Condition{
-			Type:    knativeapis.ConditionReady,
-			Status:  corev1.ConditionFalse,
-			Reason:  stewardv1alpha1.StatusReasonDependentResourceState,
-			Message: condMsg,
-		})
-		err = errors.Errorf("tenant namespace %q does not exist anymore", nsName)
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonDependentResourceState,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceMissing, map[string]string{"namespace": nsName})
+		err = serrors.Mark(errors.Errorf("tenant namespace %q does not exist anymore", nsName), serrors.ErrNamespaceMissing)
 		klog.V(3).Infof(c.formatLog(tenant), err)
 		return err
 	}
 
+	err = c.reconcileRelocation(ctx, config, tenant)
+	if err != nil {
+		return err
+	}
+	nsName = tenant.Status.TenantNamespaceName
+
+	if !c.isRelocating(tenant) {
+		hibernated, err := c.hibernateIfInactive(ctx, config, tenant, nsName)
+		if err != nil || hibernated {
+			return err
+		}
+	}
+
 	needForUpdateDetected, err := c.reconcileTenantRoleBinding(ctx, tenant, nsName, config)
 	if err != nil {
 		if needForUpdateDetected {
-			condMsg := fmt.Sprintf(
-				"The RoleBinding in tenant namespace %q is outdated but could not be updated.",
-				nsName,
-			)
-			tenant.Status.SetCondition(&knativeapis.Condition{
-				Type:    knativeapis.ConditionReady,
-				Status:  corev1.ConditionFalse,
-				Reason:  stewardv1alpha1.StatusReasonDependentResourceState,
-				Message: condMsg,
-			})
+			tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+				Type:   knativeapis.ConditionReady,
+				Status: corev1.ConditionFalse,
+				Reason: stewardv1alpha1.StatusReasonDependentResourceState,
+			}, stewardv1alpha1.MessageCodeTenantRoleBindingUpdateFailed, map[string]string{"namespace": nsName})
 		}
 		return err
 	}
 
+	err = c.reconcileTenantNetworkPolicies(ctx, tenant, nsName)
+	if err != nil {
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonDependentResourceState,
+		}, stewardv1alpha1.MessageCodeTenantNetworkPoliciesFailed, map[string]string{"namespace": nsName})
+		return err
+	}
+
 	tenant.Status.SetCondition(&knativeapis.Condition{
 		Type:   knativeapis.ConditionReady,
 		Status: corev1.ConditionTrue,
@@ -480,15 +857,51 @@ func (c *Controller) checkNamespaceExists(ctx context.Context, name string) (boo
 	return namespace.GetDeletionTimestamp().IsZero(), nil
 }
 
-func (c *Controller) createTenantNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) (string, error) {
+// planTenantNamespaceName determines the name of the tenant namespace
+// to be created for the given uninitialized tenant and persists it as
+// `status.plannedTenantNamespaceName` before the namespace gets created.
+// This way clients know the name in advance, e.g. to prepare DNS entries
+// or policies referring to the namespace.
+// Nothing is done if the name has been planned already.
+func (c *Controller) planTenantNamespaceName(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	if tenant.Status.PlannedTenantNamespaceName != "" {
+		return nil
+	}
+	nsName, err := c.getNamespaceManager(config).PlanName(tenant.GetName())
+	if err != nil {
+		err = errors.WithMessage(err, "failed to plan name of tenant namespace")
+		klog.V(4).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	tenant.Status.PlannedTenantNamespaceName = nsName
+	updatedTenant, err := c.updateStatus(ctx, tenant)
+	if err != nil {
+		tenant.Status.PlannedTenantNamespaceName = ""
+		return err
+	}
+	tenant.ObjectMeta = updatedTenant.ObjectMeta
+	klog.V(4).Infof(c.formatLogf(tenant, "planned tenant namespace %q", nsName))
+	return nil
+}
+
+// createTenantNamespace creates the tenant namespace with the planned
+// name and the given annotations.
+func (c *Controller) createTenantNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, annotations map[string]string) (string, error) {
 	klog.V(4).Infof(c.formatLog(tenant, "creating new tenant namespace"))
 	namespaceManager := c.getNamespaceManager(config)
-	nsName, err := namespaceManager.Create(ctx, tenant.GetName(), nil)
+	nsName := tenant.Status.PlannedTenantNamespaceName
+	err := namespaceManager.CreateWithName(ctx, nsName, tenant.GetName(), annotations)
+	if err != nil && kerrors.IsAlreadyExists(err) && namespaceManager.Adopt(ctx, nsName, tenant.GetName()) == nil {
+		// created by a previous attempt whose status update failed
+		klog.V(4).Infof(c.formatLogf(tenant, "reusing tenant namespace %q created before", nsName))
+		return nsName, nil
+	}
 	if err != nil {
 		err = errors.WithMessage(err, "failed to create new tenant namespace")
 		klog.V(4).Infof(c.formatLog(tenant), err)
 		return "", err
 	}
+	metrics.TenantNamespacesCreated.Inc()
 	return nsName, err
 }
 
@@ -504,6 +917,7 @@ func (c *Controller) deleteTenantNamespace(ctx context.Context, namespace string
 		klog.V(4).Infof(c.formatLog(tenant), err)
 		return err
 	}
+	metrics.TenantNamespacesDeleted.Inc()
 	return nil
 }
 
@@ -543,6 +957,9 @@ func (c *Controller) reconcileTenantRoleBinding(ctx context.Context, tenant *ste
 		Steward". All others will not be touched or taken into account.
 	*/
 
+	// outdated is true if existing role bindings need to be replaced
+	outdated := false
+
 	err = func() error {
 		rbList, err := c.listManagedRoleBindings(ctx, namespace)
 		if err != nil {
@@ -554,6 +971,7 @@ func (c *Controller) reconcileTenantRoleBinding(ctx context.Context, tenant *ste
 
 		if len(rbList.Items) != 1 || !c.isTenantRoleBindingUpToDate(&rbList.Items[0], expectedTenantRB) {
 			needForUpdateDetected = true
+			outdated = len(rbList.Items) > 0
 		}
 
 		if needForUpdateDetected {
@@ -576,6 +994,9 @@ func (c *Controller) reconcileTenantRoleBinding(ctx context.Context, tenant *ste
 			"failed to reconcile the RoleBinding in tenant namespace %q",
 			namespace,
 		)
+		if outdated {
+			err = serrors.Mark(err, serrors.ErrRoleBindingOutdated)
+		}
 		klog.V(4).Infof(c.formatLog(tenant), err)
 	}
 	return
@@ -619,6 +1040,7 @@ func (c *Controller) generateTenantRoleBinding(
 	}
 
 	slabels.LabelAsSystemManaged(roleBinding)
+	slabels.LabelWithInstanceID(roleBinding, c.factory.InstanceID())
 
 	return roleBinding
 }
@@ -689,7 +1111,9 @@ func (c *Controller) deleteRoleBinding(ctx context.Context, roleBinding *rbacv1.
 	roleBindingIfc := c.factory.RbacV1().RoleBindings(namespace)
 	deleteOptions := metav1.NewDeleteOptions(0)
 	deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(roleBinding.GetUID()))
-	err := roleBindingIfc.Delete(ctx, roleBinding.GetName(), *deleteOptions)
+	err := retry.OnError(ctx, retry.DefaultBackoff, retry.IsTransientServerError, func() error {
+		return roleBindingIfc.Delete(ctx, roleBinding.GetName(), *deleteOptions)
+	})
 	if kerrors.IsNotFound(err) {
 		return nil
 	}
@@ -716,13 +1140,14 @@ func (c *Controller) formatLogf(tenant *stewardv1alpha1.Tenant, format string, v
 }
 
 func (c *Controller) updateMetrics() {
-	// TODO determine number of tenants per client
 	list, err := c.tenantLister.List(labels.Everything())
 	if err != nil {
 		klog.Errorf("Cannot update tenant metrics: %s", err.Error())
+		return
 	}
 	count := len(list)
 	metrics.TenantCount.Set(float64(count))
+	metrics.TenantsPerClient.Observe(list)
 }
 
 func (c *Controller) onTenantAdd(obj interface{}) {
@@ -753,6 +1178,21 @@ func (c *Controller) addToQueue(key string, eventType string) {
 	}
 }
 
+// deferNamespaceDeletion returns whether the deletion of a namespace on
+// behalf of the given tenant must be deferred due to the namespace
+// deletion rate limit. If so, the tenant is enqueued again for the time
+// slot reserved for it.
+func (c *Controller) deferNamespaceDeletion(tenant *stewardv1alpha1.Tenant) bool {
+	key := c.getKey(tenant)
+	delay := c.namespaceDeletionLimiter.Delay(key)
+	if delay <= 0 {
+		return false
+	}
+	klog.V(4).Infof(c.formatLogf(tenant, "deferring namespace deletion by %v due to the deletion rate limit", delay))
+	c.workqueue.AddAfter(key, delay)
+	return true
+}
+
 func (c *Controller) getKey(obj interface{}) string {
 	var key string
 	var err error
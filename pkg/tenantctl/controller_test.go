@@ -7,6 +7,7 @@ import (
 	"time"
 
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	k8s "github.com/SAP/stewardci-core/pkg/k8s"
 	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	k8smocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
@@ -15,9 +16,11 @@ import (
 	errors "github.com/pkg/errors"
 	assert "gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workqueue "k8s.io/client-go/util/workqueue"
 	knativeapis "knative.dev/pkg/apis"
 )
 
@@ -68,6 +71,82 @@ func Test_Controller_syncHandler_FailsIfTenantFetchFails(t *testing.T) {
 	assert.Equal(t, fetcherErr, resultErr)
 }
 
+func Test_Controller_processNextWorkItem_RecoversFromPanic(t *testing.T) {
+	// SETUP
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	cf := k8sfake.NewClientFactory( /* no objects exist */ )
+
+	fetcher := k8smocks.NewMockTenantFetcher(mockCtl)
+	fetcher.EXPECT().ByKey(gomock.Not(gomock.Nil()), gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*stewardv1alpha1.Tenant, error) {
+			panic("panic1")
+		}).Times(1)
+
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = fetcher
+	ctl.workqueue.Add("namespace1/tenant1")
+
+	// EXERCISE
+	result := ctl.processNextWorkItem()
+
+	// VERIFY
+	assert.Assert(t, result == true)
+	assert.Equal(t, 1, ctl.workqueue.NumRequeues("namespace1/tenant1"))
+}
+
+func Test_Controller_processNextWorkItem_DropsAfterMaxRetries(t *testing.T) {
+	// SETUP
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	cf := k8sfake.NewClientFactory( /* no objects exist */ )
+
+	fetcher := k8smocks.NewMockTenantFetcher(mockCtl)
+	fetcher.EXPECT().ByKey(gomock.Not(gomock.Nil()), gomock.Any()).
+		Return(nil, errors.New("error1")).Times(1)
+
+	rateLimiter := workqueue.DefaultControllerRateLimiter()
+	ctl := NewController(cf, ControllerOpts{
+		WorkqueueRateLimiter: rateLimiter,
+		MaxRetries:           1,
+	})
+	ctl.fetcher = fetcher
+	rateLimiter.When("namespace1/tenant1") // first retry
+	ctl.workqueue.Add("namespace1/tenant1")
+
+	// EXERCISE
+	result := ctl.processNextWorkItem()
+
+	// VERIFY
+	assert.Assert(t, result == true)
+	assert.Equal(t, 0, ctl.workqueue.NumRequeues("namespace1/tenant1"))
+}
+
+func Test_Controller_syncHandlerRecovering_ConvertsPanicToError(t *testing.T) {
+	// SETUP
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	cf := k8sfake.NewClientFactory( /* no objects exist */ )
+
+	fetcher := k8smocks.NewMockTenantFetcher(mockCtl)
+	fetcher.EXPECT().ByKey(gomock.Not(gomock.Nil()), gomock.Any()).
+		DoAndReturn(func(context.Context, string) (*stewardv1alpha1.Tenant, error) {
+			panic("panic1")
+		}).Times(1)
+
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = fetcher
+
+	// EXERCISE
+	resultErr := ctl.syncHandlerRecovering("namespace1/tenant1")
+
+	// VERIFY
+	assert.Error(t, resultErr, "recovered from panic: panic1")
+}
+
 func Test_Controller_syncHandler_FailsIfClientConfigIsInvalid(t *testing.T) {
 	// SETUP
 	const (
@@ -188,6 +267,7 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 		{
 			nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, tenantNSPrefix, tenantID)
 			assert.Assert(t, is.Regexp(nsNamePattern, tenant.Status.TenantNamespaceName), dump)
+			assert.Equal(t, tenant.Status.TenantNamespaceName, tenant.Status.PlannedTenantNamespaceName, dump)
 		}
 	}
 
@@ -234,6 +314,112 @@ func Test_Controller_syncHandler_UninitializedTenant_GoodCase(t *testing.T) {
 	}
 }
 
+func Test_Controller_syncHandler_UninitializedTenant_NamespaceOnDemand(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+			stewardv1alpha1.AnnotationTenantNamespaceCreation: stewardv1alpha1.TenantNamespaceCreationOnDemand,
+		}),
+		// the tenant
+		k8sfake.Tenant(tenantID, clientNSName),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsTrue(), dump)
+		assert.Equal(t, stewardv1alpha1.StatusReasonTenantNamespaceOnDemand, readyCond.Reason, dump)
+		reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+		assert.Assert(t, reasonCode != nil, dump)
+		assert.Equal(t, stewardv1alpha1.MessageCodeTenantNamespaceOnDemand, reasonCode.ReasonCode, dump)
+		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+		nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, tenantNSPrefix, tenantID)
+		assert.Assert(t, is.Regexp(nsNamePattern, tenant.Status.PlannedTenantNamespaceName), dump)
+	}
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+	)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_NamespaceOnDemand_Requested(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionTrue,
+		Reason: stewardv1alpha1.StatusReasonTenantNamespaceOnDemand,
+	}, stewardv1alpha1.MessageCodeTenantNamespaceOnDemand, nil)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationTenantNamespaceRequested: "",
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+			stewardv1alpha1.AnnotationTenantNamespaceCreation: stewardv1alpha1.TenantNamespaceCreationOnDemand,
+		}),
+		tenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsTrue(), dump)
+		assert.Equal(t, "", readyCond.Reason, dump)
+		assert.Assert(t, tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady) == nil, dump)
+		nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, tenantNSPrefix, tenantID)
+		assert.Assert(t, is.Regexp(nsNamePattern, tenant.Status.TenantNamespaceName), dump)
+	}
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenant.Status.TenantNamespaceName,
+	)
+}
+
 func Test_Controller_syncHandler_UninitializedTenant_FailsOnNamespaceClash(t *testing.T) {
 	// SETUP
 	const (
@@ -281,6 +467,9 @@ func Test_Controller_syncHandler_UninitializedTenant_FailsOnNamespaceClash(t *te
 			assert.Assert(t, readyCond.IsFalse(), dump)
 			assert.Equal(t, stewardv1alpha1.StatusReasonFailed, readyCond.Reason, dump)
 			assert.Equal(t, "Failed to create a new tenant namespace.", readyCond.Message, dump)
+			reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+			assert.Assert(t, reasonCode != nil, dump)
+			assert.Equal(t, stewardv1alpha1.MessageCodeTenantNamespaceCreationFailed, reasonCode.ReasonCode, dump)
 		}
 		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
 	}
@@ -298,6 +487,294 @@ func Test_Controller_syncHandler_UninitializedTenant_FailsOnNamespaceClash(t *te
 	}
 }
 
+func Test_Controller_syncHandler_UninitializedTenant_UsesPlannedNamespaceName(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		plannedNSName  = "prefix1-tenant1-planned"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.Status.PlannedTenantNamespaceName = plannedNSName
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		tenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	assert.Equal(t, plannedNSName, tenant.Status.TenantNamespaceName, dump)
+	assert.Equal(t, plannedNSName, tenant.Status.PlannedTenantNamespaceName, dump)
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		plannedNSName,
+	)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_ReusesNamespaceOfPreviousAttempt(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		plannedNSName  = "prefix1-tenant1-planned"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.Status.PlannedTenantNamespaceName = plannedNSName
+	leftoverNS := k8sfake.Namespace(plannedNSName)
+	leftoverNS.SetLabels(map[string]string{
+		"prefix": tenantNSPrefix,
+		"id":     tenantID,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		tenant,
+		leftoverNS,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue(), dump)
+	assert.Equal(t, plannedNSName, tenant.Status.TenantNamespaceName, dump)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_AdoptsNamespace(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		orphanNSName   = "prefix1-tenant1-abc123"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationAdoptNamespace: orphanNSName,
+	})
+	orphanNS := k8sfake.Namespace(orphanNSName)
+	orphanNS.SetLabels(map[string]string{
+		"prefix": tenantNSPrefix,
+		"id":     tenantID,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		tenant,
+		orphanNS,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsTrue(), dump)
+		assert.Equal(t, orphanNSName, tenant.Status.TenantNamespaceName, dump)
+		assert.Equal(t, orphanNSName, tenant.Status.PlannedTenantNamespaceName, dump)
+	}
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		orphanNSName,
+	)
+
+	// RoleBinding in tenant namespace
+	{
+		roleBindingList, err := cf.RbacV1().RoleBindings(orphanNSName).
+			List(ctx, metav1.ListOptions{LabelSelector: stewardv1alpha1.LabelSystemManaged})
+		assert.NilError(t, err)
+		assert.Assert(t, len(roleBindingList.Items) == 1)
+	}
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_AdoptionFailsOnLabelMismatch(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		foreignNSName  = "foreign1"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationAdoptNamespace: foreignNSName,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		tenant,
+		// a namespace not created by Steward
+		k8sfake.Namespace(foreignNSName),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `failed to adopt namespace "foreign1" as tenant namespace: refused to adopt namespace 'foreign1'`)
+
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsFalse(), dump)
+		reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+		assert.Assert(t, reasonCode != nil, dump)
+		assert.Equal(t, stewardv1alpha1.MessageCodeTenantNamespaceAdoptionFailed, reasonCode.ReasonCode, dump)
+		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	}
+
+	// the namespace is not deleted
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		foreignNSName,
+	)
+}
+
+func Test_Controller_syncHandler_UninitializedTenant_AdoptionFailsIfNamespaceContainsPipelineRuns(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		orphanNSName   = "prefix1-tenant1-abc123"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	tenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationAdoptNamespace: orphanNSName,
+	})
+	orphanNS := k8sfake.Namespace(orphanNSName)
+	orphanNS.SetLabels(map[string]string{
+		"prefix": tenantNSPrefix,
+		"id":     tenantID,
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		tenant,
+		orphanNS,
+		// a pipeline run left over in the orphaned namespace
+		k8sfake.PipelineRun("run1", orphanNSName, stewardv1alpha1.PipelineSpec{}),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `refused to adopt namespace "prefix1-tenant1-abc123": namespace contains pipeline runs`)
+
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	// tenant
+	{
+		dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+		readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		assert.Assert(t, readyCond.IsFalse(), dump)
+		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	}
+
+	// RoleBinding in orphaned namespace NOT created
+	{
+		roleBindingList, err := cf.RbacV1().RoleBindings(orphanNSName).
+			List(ctx, metav1.ListOptions{LabelSelector: stewardv1alpha1.LabelSystemManaged})
+		assert.NilError(t, err)
+		assert.Equal(t, 0, len(roleBindingList.Items))
+	}
+}
+
+func Test_Controller_checkNamespaceNotAssigned(t *testing.T) {
+	// SETUP
+	tenant1 := k8sfake.Tenant("tenant1", "client1")
+	tenant1.SetUID("uid1")
+	tenant2 := k8sfake.Tenant("tenant2", "client1")
+	tenant2.SetUID("uid2")
+	tenant2.Status.TenantNamespaceName = "ns1"
+	cf := k8sfake.NewClientFactory()
+	ctl := NewController(cf, ControllerOpts{})
+	store := cf.StewardInformerFactory().Steward().V1alpha1().Tenants().Informer().GetStore()
+	assert.NilError(t, store.Add(tenant1))
+	assert.NilError(t, store.Add(tenant2))
+
+	// EXERCISE
+	err1 := ctl.checkNamespaceNotAssigned(tenant1, "ns1")
+	err2 := ctl.checkNamespaceNotAssigned(tenant1, "ns2")
+	err3 := ctl.checkNamespaceNotAssigned(tenant2, "ns1")
+
+	// VERIFY
+	assert.Error(t, err1, `namespace "ns1" is the tenant namespace of tenant "tenant2" in client namespace "client1"`)
+	assert.NilError(t, err2)
+	assert.NilError(t, err3)
+}
+
 func Test_Controller_syncHandler_UninitializedTenant_FailsOnErrorWhenSyncingRoleBinding(t *testing.T) {
 	// SETUP
 	const (
@@ -345,6 +822,9 @@ func Test_Controller_syncHandler_UninitializedTenant_FailsOnErrorWhenSyncingRole
 			assert.Assert(t, readyCond.IsFalse(), dump)
 			assert.Equal(t, stewardv1alpha1.StatusReasonFailed, readyCond.Reason, dump)
 			assert.Equal(t, "Failed to initialize a new tenant namespace because the RoleBinding could not be created.", readyCond.Message, dump)
+			reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+			assert.Assert(t, reasonCode != nil, dump)
+			assert.Equal(t, stewardv1alpha1.MessageCodeTenantRoleBindingCreationFailed, reasonCode.ReasonCode, dump)
 		}
 		assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
 	}
@@ -400,6 +880,8 @@ func Test_Controller_syncHandler_InitializedTenant_AddsMissingRoleBinding(t *tes
 			assert.Assert(t, readyCond.IsTrue(), dump)
 		}
 		assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName, dump)
+		// set for tenants initialized before the planned name has been introduced
+		assert.Equal(t, tenantNSName, tenant.Status.PlannedTenantNamespaceName, dump)
 	}
 
 	// tenant namespace
@@ -475,6 +957,7 @@ func Test_Controller_syncHandler_InitializedTenant_FailsOnMissingNamespace(t *te
 	// VERIFY
 	assert.Assert(t, resultErr != nil)
 	assert.Error(t, resultErr, fmt.Sprintf("tenant namespace \"%s\" does not exist anymore", tenantNSName))
+	assert.Assert(t, errors.Is(resultErr, serrors.ErrNamespaceMissing))
 
 	ctx := context.Background()
 	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
@@ -496,6 +979,10 @@ func Test_Controller_syncHandler_InitializedTenant_FailsOnMissingNamespace(t *te
 				readyCond.Message,
 				dump,
 			)
+			reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+			assert.Assert(t, reasonCode != nil, dump)
+			assert.Equal(t, stewardv1alpha1.MessageCodeTenantNamespaceMissing, reasonCode.ReasonCode, dump)
+			assert.DeepEqual(t, map[string]string{"namespace": tenantNSName}, reasonCode.MessageArgs)
 		}
 		assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName, dump)
 	}
@@ -566,6 +1053,9 @@ func Test_Controller_syncHandler_InitializedTenant_FailsOnErrorWhenSyncingRoleBi
 			readyCond.Message,
 			dump,
 		)
+		reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+		assert.Assert(t, reasonCode != nil, dump)
+		assert.Equal(t, stewardv1alpha1.MessageCodeTenantRoleBindingUpdateFailed, reasonCode.ReasonCode, dump)
 
 		assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName, dump)
 	}
@@ -813,7 +1303,11 @@ func Test_Controller_syncHandler_CleanupOnStatusUpdateFailure(t *testing.T) {
 	injectedError := errors.New("ERR1")
 	ctl.testing = &controllerTesting{
 		updateStatusStub: func(tenant *stewardv1alpha1.Tenant) (*stewardv1alpha1.Tenant, error) {
-			assert.Assert(t, tenant.Status.TenantNamespaceName != "", spew.Sdump(tenant.Status))
+			if tenant.Status.TenantNamespaceName == "" {
+				// the planned tenant namespace name is persisted before the namespace is created
+				assert.Assert(t, tenant.Status.PlannedTenantNamespaceName != "", spew.Sdump(tenant.Status))
+				return tenant, nil
+			}
 			return tenant, injectedError
 		},
 	}
@@ -885,6 +1379,7 @@ func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_createRoleBinding
 	injectedError := errors.Errorf("injected error 1")
 
 	examinee := &Controller{
+		factory: k8sfake.NewClientFactory(),
 		testing: &controllerTesting{
 			listManagedRoleBindingsStub: func(string) (*rbacv1.RoleBindingList, error) {
 				return &rbacv1.RoleBindingList{}, nil
@@ -905,6 +1400,58 @@ func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_createRoleBinding
 	))
 	assert.Assert(t, errors.Cause(resultErr) == injectedError)
 	assert.Assert(t, resultUpdateNeeded == true)
+	assert.Assert(t, !errors.Is(resultErr, serrors.ErrRoleBindingOutdated))
+}
+
+func Test_Controller_reconcileTenantRoleBinding_FailsOnErrorIn_createRoleBinding_Outdated(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSName   = "tenantNS1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	tenant := k8sfake.Tenant(tenantID, clientNSName)
+	config := &clientConfigImpl{
+		tenantRoleName: tenantRoleName,
+	}
+
+	injectedError := errors.Errorf("injected error 1")
+
+	examinee := &Controller{
+		factory: k8sfake.NewClientFactory(),
+		testing: &controllerTesting{
+			listManagedRoleBindingsStub: func(string) (*rbacv1.RoleBindingList, error) {
+				return &rbacv1.RoleBindingList{
+					Items: []rbacv1.RoleBinding{
+						{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "rb1",
+								Namespace: tenantNSName,
+							},
+						},
+					},
+				}, nil
+			},
+			createRoleBindingStub: func(*rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
+				return nil, injectedError
+			},
+		},
+	}
+
+	// EXERCISE
+	resultUpdateNeeded, resultErr := examinee.reconcileTenantRoleBinding(ctx, tenant, tenantNSName, config)
+
+	// VERIFY
+	assert.Error(t, resultErr, fmt.Sprintf(
+		"failed to reconcile the RoleBinding in tenant namespace \"%s\": injected error 1",
+		tenantNSName,
+	))
+	assert.Assert(t, errors.Cause(resultErr) == injectedError)
+	assert.Assert(t, resultUpdateNeeded == true)
+	assert.Assert(t, errors.Is(resultErr, serrors.ErrRoleBindingOutdated))
 }
 
 func Test_Controller_listManagedRoleBindings_GoodCase_WithLabelFilter(t *testing.T) {
@@ -1259,3 +1806,40 @@ func waitForNextSync(t *testing.T, controller *Controller, previousSyncCount int
 		sleep("5ms")
 	}
 }
+
+func Test_reconcileErrorReason(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		readyStatus    corev1.ConditionStatus
+		reasonCode     stewardv1alpha1.MessageCode
+		expectedReason string
+	}{
+		{"no_condition", "", "", "Other"},
+		{"false_without_reason_code", corev1.ConditionFalse, "", "Other"},
+		{"false_with_reason_code", corev1.ConditionFalse, stewardv1alpha1.MessageCodeTenantNamespaceCreationFailed, "TenantNamespaceCreationFailed"},
+		{"true", corev1.ConditionTrue, "", "Other"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			tenant := k8sfake.Tenant("tenant1", "client1")
+			if tc.readyStatus != "" {
+				cond := &knativeapis.Condition{
+					Type:   knativeapis.ConditionReady,
+					Status: tc.readyStatus,
+					Reason: stewardv1alpha1.StatusReasonFailed,
+				}
+				if tc.reasonCode != "" {
+					tenant.Status.SetConditionWithReasonCode(cond, tc.reasonCode, nil)
+				} else {
+					tenant.Status.SetCondition(cond)
+				}
+			}
+
+			// EXERCISE
+			result := reconcileErrorReason(tenant)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedReason, result)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package tenantctl
+
+import (
+	"context"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// reconcileExpiration sets the `Expiring` condition of the tenant
+// according to `spec.expirationDate` and the expiration warning period
+// configured for the client.
+// If the expiration date has passed, the tenant is expired according to
+// the expiration policy of the client and the regular reconciliation
+// must be skipped, i.e. no tenant namespace gets created for expired
+// tenants.
+// If the expiration date of an expired and hibernated tenant has been
+// extended, the tenant stays hibernated until a new tenant namespace is
+// requested.
+// Returns whether the tenant is expired.
+func (c *Controller) reconcileExpiration(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) (bool, error) {
+	wasExpired := c.isExpired(tenant)
+	expirationDate := tenant.Spec.ExpirationDate
+	now := c.now()
+
+	switch {
+	case expirationDate == nil:
+		if tenant.Status.GetCondition(stewardv1alpha1.TenantConditionExpiring) != nil {
+			tenant.Status.SetCondition(&knativeapis.Condition{
+				Type:   stewardv1alpha1.TenantConditionExpiring,
+				Status: corev1.ConditionFalse,
+			})
+		}
+	case now.Before(expirationDate.Add(-config.GetTenantExpirationWarningPeriod())):
+		tenant.Status.SetCondition(&knativeapis.Condition{
+			Type:   stewardv1alpha1.TenantConditionExpiring,
+			Status: corev1.ConditionFalse,
+		})
+	case now.Before(expirationDate.Time):
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   stewardv1alpha1.TenantConditionExpiring,
+			Status: corev1.ConditionTrue,
+			Reason: stewardv1alpha1.StatusReasonTenantExpiring,
+		}, stewardv1alpha1.MessageCodeTenantExpiring, expirationArgs(expirationDate))
+	default:
+		return true, c.expire(ctx, config, tenant)
+	}
+
+	if wasExpired && c.isHibernated(tenant) {
+		klog.V(3).Infof(c.formatLog(tenant, "expiration date has been extended, tenant stays hibernated until requested"))
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   stewardv1alpha1.TenantConditionHibernated,
+			Status: corev1.ConditionTrue,
+			Reason: stewardv1alpha1.StatusReasonTenantHibernated,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceOnDemand, nil)
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   knativeapis.ConditionReady,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonTenantHibernated,
+		}, stewardv1alpha1.MessageCodeTenantNamespaceOnDemand, nil)
+	}
+	return false, nil
+}
+
+// expire handles a tenant whose expiration date has passed.
+// With expiration policy stewardv1alpha1.TenantExpirationPolicyDelete the
+// tenant gets deleted. Otherwise it gets hibernated regardless of pipeline
+// run activity, unless its tenant namespace has been adopted. The
+// expiration does not depend on the hibernation period of the client.
+func (c *Controller) expire(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	expirationDate := tenant.Spec.ExpirationDate
+	cause := "expiration date " + expirationDate.UTC().Format(time.RFC3339) + " has passed"
+
+	if config.GetTenantExpirationPolicy() == stewardv1alpha1.TenantExpirationPolicyDelete {
+		klog.V(3).Infof(c.formatLogf(tenant, "deleting tenant: %s", cause))
+		err := c.factory.StewardV1alpha1().Tenants(tenant.GetNamespace()).Delete(ctx, tenant.GetName(), metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			err = errors.WithMessagef(err, "failed to delete expired tenant %q in namespace %q", tenant.GetName(), tenant.GetNamespace())
+			klog.V(3).Infof(c.formatLog(tenant), err)
+			return err
+		}
+		return nil
+	}
+
+	args := expirationArgs(expirationDate)
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionExpiring,
+		Status: corev1.ConditionTrue,
+		Reason: stewardv1alpha1.StatusReasonTenantExpired,
+	}, stewardv1alpha1.MessageCodeTenantExpired, args)
+
+	// adopted namespaces have not been created by us and are never deleted
+	if nsName := tenant.Status.TenantNamespaceName; nsName != "" && tenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace] == "" {
+		hibernated, err := c.hibernate(ctx, config, tenant, nsName, cause, stewardv1alpha1.StatusReasonTenantExpired, stewardv1alpha1.MessageCodeTenantExpired, args)
+		if err != nil || hibernated {
+			return err
+		}
+	}
+
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: stewardv1alpha1.StatusReasonTenantExpired,
+	}, stewardv1alpha1.MessageCodeTenantExpired, args)
+	return nil
+}
+
+// isExpired returns whether the given tenant has been found expired by a
+// previous reconciliation.
+func (c *Controller) isExpired(tenant *stewardv1alpha1.Tenant) bool {
+	condition := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionExpiring)
+	return condition.IsTrue() && condition.Reason == stewardv1alpha1.StatusReasonTenantExpired
+}
+
+// expirationArgs returns the message arguments for the given expiration
+// date.
+func expirationArgs(expirationDate *metav1.Time) map[string]string {
+	return map[string]string{"expirationDate": expirationDate.UTC().Format(time.RFC3339)}
+}
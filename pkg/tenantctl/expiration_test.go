@@ -0,0 +1,310 @@
+package tenantctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	spew "github.com/davecgh/go-spew/spew"
+	assert "gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	knativeapis "knative.dev/pkg/apis"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_Controller_syncHandler_InitializedTenant_Expiration(t *testing.T) {
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		tenantNSName   = "prefix1-tenant1-abc123"
+		tenantUID      = "uid1"
+	)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+
+	for _, tc := range []struct {
+		name                 string
+		policy               string
+		adopted              bool
+		expirationDate       *metav1.Time
+		expectedExpiring     corev1.ConditionStatus
+		expectedReason       string
+		expectedReadyReason  string
+		expectNamespaceKept  bool
+		expectTenantDeleted  bool
+		expectSnapshotExists bool
+	}{
+		{
+			name:                "no expiration date",
+			expirationDate:      nil,
+			expectedExpiring:    "",
+			expectNamespaceKept: true,
+		},
+		{
+			name:                "before warning period",
+			expirationDate:      in(8 * 24 * time.Hour),
+			expectedExpiring:    corev1.ConditionFalse,
+			expectNamespaceKept: true,
+		},
+		{
+			name:                "within warning period",
+			expirationDate:      in(6 * 24 * time.Hour),
+			expectedExpiring:    corev1.ConditionTrue,
+			expectedReason:      stewardv1alpha1.StatusReasonTenantExpiring,
+			expectNamespaceKept: true,
+		},
+		{
+			name:                 "expired with default policy",
+			expirationDate:       in(-time.Minute),
+			expectedExpiring:     corev1.ConditionTrue,
+			expectedReason:       stewardv1alpha1.StatusReasonTenantExpired,
+			expectedReadyReason:  stewardv1alpha1.StatusReasonTenantExpired,
+			expectSnapshotExists: true,
+		},
+		{
+			name:                 "expired with hibernate policy",
+			policy:               stewardv1alpha1.TenantExpirationPolicyHibernate,
+			expirationDate:       in(-time.Minute),
+			expectedExpiring:     corev1.ConditionTrue,
+			expectedReason:       stewardv1alpha1.StatusReasonTenantExpired,
+			expectedReadyReason:  stewardv1alpha1.StatusReasonTenantExpired,
+			expectSnapshotExists: true,
+		},
+		{
+			name:                "expired with adopted namespace",
+			adopted:             true,
+			expirationDate:      in(-time.Minute),
+			expectedExpiring:    corev1.ConditionTrue,
+			expectedReason:      stewardv1alpha1.StatusReasonTenantExpired,
+			expectedReadyReason: stewardv1alpha1.StatusReasonTenantExpired,
+			expectNamespaceKept: true,
+		},
+		{
+			name:                "expired with delete policy",
+			policy:              stewardv1alpha1.TenantExpirationPolicyDelete,
+			expirationDate:      in(-time.Minute),
+			expectNamespaceKept: true,
+			expectTenantDeleted: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+
+			origTenant := k8sfake.Tenant(tenantID, clientNSName)
+			origTenant.SetUID(types.UID(tenantUID))
+			origTenant.Spec.ExpirationDate = tc.expirationDate
+			origTenant.Status.TenantNamespaceName = tenantNSName
+			annotations := map[string]string{
+				stewardv1alpha1.AnnotationTenantNamespaceRequested: "",
+			}
+			if tc.adopted {
+				annotations[stewardv1alpha1.AnnotationAdoptNamespace] = tenantNSName
+			}
+			origTenant.SetAnnotations(annotations)
+
+			tenantNamespace := k8sfake.Namespace(tenantNSName)
+			tenantNamespace.SetLabels(map[string]string{"prefix": tenantNSPrefix})
+
+			cf := k8sfake.NewClientFactory(
+				// the client namespace
+				k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+					stewardv1alpha1.AnnotationTenantNamespacePrefix:  tenantNSPrefix,
+					stewardv1alpha1.AnnotationTenantRole:             tenantRoleName,
+					stewardv1alpha1.AnnotationTenantExpirationPolicy: tc.policy,
+				}),
+				origTenant,
+				tenantNamespace,
+				k8sfake.SecretOpaque("secret1", tenantNSName),
+			)
+
+			ctl := NewController(cf, ControllerOpts{})
+			ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+			ctl.testing = &controllerTesting{
+				nowStub: func() time.Time { return now },
+			}
+
+			// EXERCISE
+			resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+			if tc.expectTenantDeleted {
+				assert.Assert(t, k8serrors.IsNotFound(err))
+				return
+			}
+			assert.NilError(t, err)
+			dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+			expiringCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionExpiring)
+			if tc.expectedExpiring == "" {
+				assert.Assert(t, expiringCond == nil, dump)
+			} else {
+				assert.Assert(t, expiringCond != nil, dump)
+				assert.Equal(t, tc.expectedExpiring, expiringCond.Status, dump)
+				assert.Equal(t, tc.expectedReason, expiringCond.Reason, dump)
+			}
+			if tc.expectedReason != "" {
+				reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionExpiring)
+				assert.Assert(t, reasonCode != nil, dump)
+				assert.DeepEqual(t, map[string]string{"expirationDate": tc.expirationDate.UTC().Format(time.RFC3339)}, reasonCode.MessageArgs)
+			}
+
+			readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+			if tc.expectedReadyReason == "" {
+				assert.Assert(t, readyCond.IsTrue(), dump)
+			} else {
+				assert.Assert(t, readyCond.IsFalse(), dump)
+				assert.Equal(t, tc.expectedReadyReason, readyCond.Reason, dump)
+			}
+
+			_, snapshotErr := cf.CoreV1().Secrets(system.Namespace()).Get(ctx, "steward-hibernation-"+tenantUID, metav1.GetOptions{})
+			if tc.expectSnapshotExists {
+				assert.NilError(t, snapshotErr)
+			} else {
+				assert.Assert(t, k8serrors.IsNotFound(snapshotErr), dump)
+			}
+
+			if tc.expectNamespaceKept {
+				assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName, dump)
+				assertThatExactlyTheseNamespacesExist(t, cf, clientNSName, tenantNSName)
+			} else {
+				assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+				assert.Assert(t, tenant.Status.GetCondition(stewardv1alpha1.TenantConditionHibernated).IsTrue(), dump)
+				_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationTenantNamespaceRequested]
+				assert.Assert(t, !requested, dump)
+				assertThatExactlyTheseNamespacesExist(t, cf, clientNSName)
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_ExpiredTenant_NamespaceRequested(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	expirationDate := metav1.NewTime(now.Add(-time.Hour))
+	origTenant := k8sfake.Tenant(tenantID, clientNSName)
+	origTenant.Spec.ExpirationDate = &expirationDate
+	origTenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationTenantNamespaceRequested: "",
+	})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+			stewardv1alpha1.AnnotationTenantNamespaceCreation: stewardv1alpha1.TenantNamespaceCreationOnDemand,
+		}),
+		origTenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.testing = &controllerTesting{
+		nowStub: func() time.Time { return now },
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsFalse(), dump)
+	assert.Equal(t, stewardv1alpha1.StatusReasonTenantExpired, readyCond.Reason, dump)
+	reasonCode := tenant.Status.GetConditionReasonCode(knativeapis.ConditionReady)
+	assert.Assert(t, reasonCode != nil, dump)
+	assert.Equal(t, stewardv1alpha1.MessageCodeTenantExpired, reasonCode.ReasonCode, dump)
+	assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	assertThatExactlyTheseNamespacesExist(t, cf, clientNSName)
+}
+
+func Test_Controller_syncHandler_ExpiredHibernatedTenant_Extended(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	expirationDate := metav1.NewTime(now.Add(30 * 24 * time.Hour))
+	oldExpirationDate := metav1.NewTime(now.Add(-time.Hour))
+	args := expirationArgs(&oldExpirationDate)
+	origTenant := k8sfake.Tenant(tenantID, clientNSName)
+	origTenant.Spec.ExpirationDate = &expirationDate
+	for _, condType := range []knativeapis.ConditionType{
+		stewardv1alpha1.TenantConditionExpiring,
+		stewardv1alpha1.TenantConditionHibernated,
+	} {
+		origTenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   condType,
+			Status: corev1.ConditionTrue,
+			Reason: stewardv1alpha1.StatusReasonTenantExpired,
+		}, stewardv1alpha1.MessageCodeTenantExpired, args)
+	}
+	origTenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: stewardv1alpha1.StatusReasonTenantExpired,
+	}, stewardv1alpha1.MessageCodeTenantExpired, args)
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		origTenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.testing = &controllerTesting{
+		nowStub: func() time.Time { return now },
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	assert.Assert(t, tenant.Status.GetCondition(stewardv1alpha1.TenantConditionExpiring).IsFalse(), dump)
+	assert.Assert(t, tenant.Status.GetCondition(stewardv1alpha1.TenantConditionHibernated).IsTrue(), dump)
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsFalse(), dump)
+	assert.Equal(t, stewardv1alpha1.StatusReasonTenantHibernated, readyCond.Reason, dump)
+	assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+	assertThatExactlyTheseNamespacesExist(t, cf, clientNSName)
+}
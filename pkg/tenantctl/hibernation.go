@@ -0,0 +1,418 @@
+package tenantctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	metrics "github.com/SAP/stewardci-core/pkg/tenantctl/metrics"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// hibernationSnapshotNamePrefix is the name prefix of the secrets in
+	// the system namespace holding the secrets of hibernated tenants.
+	// The suffix is the UID of the tenant.
+	hibernationSnapshotNamePrefix = "steward-hibernation-"
+
+	// maxHibernationSnapshotBytes is the maximum size of the secrets of
+	// a tenant namespace to be snapshotted. It leaves some room below the
+	// size limit of Kubernetes secrets.
+	maxHibernationSnapshotBytes = 900 * 1024
+
+	// hibernatedObjectKeyPrefix is the prefix of the keys of hibernation
+	// snapshot entries holding objects of the kinds listed in
+	// tenantObjectKinds. The prefix is followed by the kind, a dot and
+	// the object name. Keys of secret entries are plain secret names,
+	// which cannot start with this prefix.
+	hibernatedObjectKeyPrefix = "_"
+)
+
+// hibernatedSecret is a secret of a tenant namespace in a hibernation
+// snapshot.
+type hibernatedSecret struct {
+	Type        corev1.SecretType `json:"type,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Data        map[string][]byte `json:"data,omitempty"`
+}
+
+// hibernateIfInactive hibernates the tenant if there was no pipeline run
+// activity in its tenant namespace for the hibernation period configured
+// for the client.
+// The secrets and the objects of the kinds listed in tenantObjectKinds in
+// the tenant namespace are saved in a snapshot secret in the system
+// namespace before the tenant namespace gets deleted.
+// Afterwards the tenant is uninitialized and a new tenant namespace with
+// the saved objects gets created only if requested via annotation
+// stewardv1alpha1.AnnotationTenantNamespaceRequested.
+// Inactivity is checked against the informer cache first and rechecked
+// against the API server right before the tenant namespace gets deleted.
+// The hibernation is deferred if it exceeds the namespace deletion rate
+// limit.
+// Returns whether the tenant has been hibernated.
+func (c *Controller) hibernateIfInactive(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, nsName string) (bool, error) {
+	period := config.GetTenantHibernationPeriod()
+	if period <= 0 {
+		return false, nil
+	}
+
+	// adopted namespaces have not been created by us and are never deleted
+	if tenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace] != "" {
+		return false, nil
+	}
+
+	since := c.now().Add(-period)
+
+	cachedRuns, err := c.pipelineRunLister.PipelineRuns(nsName).List(labels.Everything())
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to list cached pipeline runs in tenant namespace %q", nsName)
+	}
+	if lastActivity, active := lastPipelineRunActivity(cachedRuns, lastRecordedRunActivity(tenant)); active || lastActivity.After(since) {
+		return false, nil
+	}
+
+	lastActivity, active, err := c.getLastPipelineRunActivity(ctx, tenant, nsName)
+	if err != nil {
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return false, err
+	}
+	if active || lastActivity.After(since) {
+		return false, nil
+	}
+	if c.deferNamespaceDeletion(tenant) {
+		return false, nil
+	}
+
+	args := map[string]string{"namespace": nsName}
+	cause := "no pipeline run activity since " + lastActivity.UTC().Format(time.RFC3339)
+	return c.hibernate(ctx, config, tenant, nsName, cause, stewardv1alpha1.StatusReasonTenantHibernated, stewardv1alpha1.MessageCodeTenantHibernated, args)
+}
+
+// hibernate saves the secrets and the objects of the kinds listed in
+// tenantObjectKinds of the given tenant namespace in a snapshot secret in the system namespace, deletes the tenant namespace and sets
+// the `Hibernated` and ready conditions with the given reason and message.
+// The cause is logged only.
+// The tenant is not hibernated if the saved objects are too large for a
+// snapshot.
+// Returns whether the tenant has been hibernated.
+func (c *Controller) hibernate(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, nsName string, cause string, reason string, code stewardv1alpha1.MessageCode, args map[string]string) (bool, error) {
+	snapshot, err := c.newHibernationSnapshot(ctx, tenant, nsName)
+	if err != nil {
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return false, err
+	}
+	if snapshot == nil {
+		klog.V(3).Infof(c.formatLogf(tenant, "not hibernating tenant: secrets and objects in tenant namespace %q exceed %d bytes", nsName, maxHibernationSnapshotBytes))
+		return false, nil
+	}
+
+	klog.V(3).Infof(c.formatLogf(tenant, "hibernating tenant: %s", cause))
+
+	err = c.saveHibernationSnapshot(ctx, snapshot)
+	if err != nil {
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return false, err
+	}
+
+	// a new request is required to recreate the tenant namespace
+	if c.isTenantNamespaceRequested(tenant) {
+		annotations := map[string]string{}
+		for key, value := range tenant.GetAnnotations() {
+			if key != stewardv1alpha1.AnnotationTenantNamespaceRequested {
+				annotations[key] = value
+			}
+		}
+		tenant.SetAnnotations(annotations)
+		updatedTenant, err := c.update(ctx, tenant)
+		if err != nil {
+			c.deleteHibernationSnapshot(ctx, tenant) // clean-up ignoring error
+			return false, err
+		}
+		tenant.ObjectMeta = updatedTenant.ObjectMeta
+	}
+
+	err = c.getNamespaceManager(config).Delete(ctx, nsName)
+	if err != nil {
+		c.deleteHibernationSnapshot(ctx, tenant) // clean-up ignoring error
+		err = errors.WithMessagef(err, "failed to delete tenant namespace %q of hibernated tenant", nsName)
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return false, err
+	}
+	metrics.TenantNamespacesDeleted.Inc()
+
+	tenant.Status.TenantNamespaceName = ""
+
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionHibernated,
+		Status: corev1.ConditionTrue,
+		Reason: reason,
+	}, code, args)
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: reason,
+	}, code, args)
+
+	return true, nil
+}
+
+// isHibernated returns whether the tenant namespace of the given tenant
+// has been deleted due to inactivity.
+func (c *Controller) isHibernated(tenant *stewardv1alpha1.Tenant) bool {
+	return tenant.Status.GetCondition(stewardv1alpha1.TenantConditionHibernated).IsTrue()
+}
+
+// getLastPipelineRunActivity returns the time of the last pipeline run
+// activity in the given tenant namespace of the given tenant, i.e. the
+// latest creation, start or finish time of all pipeline runs in there or
+// of pipeline runs deleted after their time to live as recorded in the
+// tenant status. If there is no such time, the creation time of the
+// namespace is returned.
+// Output parameter `active` indicates whether there is at least one
+// unfinished pipeline run.
+// The API server is queried directly in pages, bypassing the informer
+// cache.
+func (c *Controller) getLastPipelineRunActivity(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string) (lastActivity time.Time, active bool, err error) {
+	namespace, err := c.factory.CoreV1().Namespaces().Get(ctx, nsName, metav1.GetOptions{})
+	if err != nil {
+		return time.Time{}, false, errors.WithMessagef(err, "failed to get tenant namespace %q", nsName)
+	}
+
+	lastActivity = namespace.GetCreationTimestamp().Time
+	if recorded := lastRecordedRunActivity(tenant); recorded.After(lastActivity) {
+		lastActivity = recorded
+	}
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.factory.StewardV1alpha1().PipelineRuns(nsName).List(ctx, opts)
+	}
+	err = k8s.EachListItem(ctx, list, metav1.ListOptions{}, func(obj runtime.Object) error {
+		var runActive bool
+		lastActivity, runActive = lastPipelineRunActivity([]*stewardv1alpha1.PipelineRun{obj.(*stewardv1alpha1.PipelineRun)}, lastActivity)
+		active = active || runActive
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, false, errors.WithMessagef(err, "failed to list pipeline runs in tenant namespace %q", nsName)
+	}
+	return lastActivity, active, nil
+}
+
+// lastRecordedRunActivity returns the last pipeline run activity of the
+// given tenant recorded in its status or the zero time if there is none.
+func lastRecordedRunActivity(tenant *stewardv1alpha1.Tenant) time.Time {
+	if tenant.Status.LastRunActivity == nil {
+		return time.Time{}
+	}
+	return tenant.Status.LastRunActivity.Time
+}
+
+// lastPipelineRunActivity returns the latest creation, start or finish
+// time of the given pipeline runs, but not earlier than the given time.
+// Output parameter `active` indicates whether there is at least one
+// unfinished pipeline run.
+func lastPipelineRunActivity(runs []*stewardv1alpha1.PipelineRun, notBefore time.Time) (lastActivity time.Time, active bool) {
+	lastActivity = notBefore
+	for _, run := range runs {
+		if run.Status.State != stewardv1alpha1.StateFinished {
+			active = true
+		}
+		for _, t := range []*metav1.Time{&run.ObjectMeta.CreationTimestamp, run.Status.StartedAt, run.Status.FinishedAt} {
+			if t != nil && t.Time.After(lastActivity) {
+				lastActivity = t.Time
+			}
+		}
+	}
+	return lastActivity, active
+}
+
+// hibernationSnapshotName returns the name of the secret in the system
+// namespace holding the secrets of the given hibernated tenant.
+func hibernationSnapshotName(tenant *stewardv1alpha1.Tenant) string {
+	return hibernationSnapshotNamePrefix + string(tenant.GetUID())
+}
+
+// newHibernationSnapshot returns a secret holding all secrets of the given
+// tenant namespace except service account tokens, which are recreated by
+// Kubernetes, and all objects of the kinds listed in tenantObjectKinds.
+// It returns nil if they are too large to fit into a single secret.
+func (c *Controller) newHibernationSnapshot(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string) (*corev1.Secret, error) {
+	list, err := c.factory.CoreV1().Secrets(nsName).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to list secrets in tenant namespace %q", nsName)
+	}
+
+	snapshot := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hibernationSnapshotName(tenant),
+			Namespace: system.Namespace(),
+			Labels: map[string]string{
+				stewardv1alpha1.LabelSystemManaged:        "",
+				stewardv1alpha1.LabelOwnerClientNamespace: tenant.GetNamespace(),
+				stewardv1alpha1.LabelOwnerTenantName:      tenant.GetName(),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+	size := 0
+	for _, secret := range list.Items {
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		value, err := json.Marshal(hibernatedSecret{
+			Type:        secret.Type,
+			Labels:      secret.GetLabels(),
+			Annotations: secret.GetAnnotations(),
+			Data:        secret.Data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		size += len(secret.GetName()) + len(value)
+		if size > maxHibernationSnapshotBytes {
+			return nil, nil
+		}
+		snapshot.Data[secret.GetName()] = value
+	}
+	for _, kind := range tenantObjectKinds {
+		objects, err := kind.list(ctx, c, nsName)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to list objects of kind %s in tenant namespace %q", kind.name, nsName)
+		}
+		for _, obj := range objects {
+			value, err := json.Marshal(obj)
+			if err != nil {
+				return nil, err
+			}
+			key := hibernatedObjectKey(kind.name, obj.Name)
+			size += len(key) + len(value)
+			if size > maxHibernationSnapshotBytes {
+				return nil, nil
+			}
+			snapshot.Data[key] = value
+		}
+	}
+	return snapshot, nil
+}
+
+// hibernatedObjectKey returns the key of the hibernation snapshot entry
+// holding the object with the given kind and name.
+func hibernatedObjectKey(kind, name string) string {
+	return hibernatedObjectKeyPrefix + kind + "." + name
+}
+
+// saveHibernationSnapshot creates the given snapshot secret or replaces
+// the data of an existing one, e.g. left over from a failed hibernation.
+func (c *Controller) saveHibernationSnapshot(ctx context.Context, snapshot *corev1.Secret) error {
+	client := c.factory.CoreV1().Secrets(snapshot.GetNamespace())
+	_, err := client.Create(ctx, snapshot, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		var existing *corev1.Secret
+		existing, err = client.Get(ctx, snapshot.GetName(), metav1.GetOptions{})
+		if err == nil {
+			existing.Data = snapshot.Data
+			_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "failed to save hibernation snapshot %q in namespace %q", snapshot.GetName(), snapshot.GetNamespace())
+	}
+	return nil
+}
+
+// restoreHibernationSnapshot recreates the secrets and other objects saved
+// when hibernating the given tenant in the given new tenant namespace and
+// deletes the snapshot afterwards. Secrets are restored first, the other
+// objects in the order of tenantObjectKinds.
+// It is a no-op if there is no snapshot.
+func (c *Controller) restoreHibernationSnapshot(ctx context.Context, tenant *stewardv1alpha1.Tenant, nsName string) error {
+	name := hibernationSnapshotName(tenant)
+	snapshot, err := c.factory.CoreV1().Secrets(system.Namespace()).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get hibernation snapshot %q in namespace %q", name, system.Namespace())
+	}
+
+	objects := map[string][]tenantObject{}
+	secretCount := 0
+	for secretName, value := range snapshot.Data {
+		if strings.HasPrefix(secretName, hibernatedObjectKeyPrefix) {
+			parts := strings.SplitN(strings.TrimPrefix(secretName, hibernatedObjectKeyPrefix), ".", 2)
+			if len(parts) != 2 || findTenantObjectKind(parts[0]) == nil {
+				return fmt.Errorf("hibernation snapshot %q: invalid entry %q", name, secretName)
+			}
+			kind := parts[0]
+			obj := tenantObject{Name: parts[1]}
+			if err := json.Unmarshal(value, &obj); err != nil {
+				return errors.WithMessagef(err, "hibernation snapshot %q: invalid entry %q", name, secretName)
+			}
+			objects[kind] = append(objects[kind], obj)
+			continue
+		}
+		saved := hibernatedSecret{}
+		if err := json.Unmarshal(value, &saved); err != nil {
+			return errors.WithMessagef(err, "hibernation snapshot %q: invalid entry %q", name, secretName)
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secretName,
+				Namespace:   nsName,
+				Labels:      saved.Labels,
+				Annotations: saved.Annotations,
+			},
+			Type: saved.Type,
+			Data: saved.Data,
+		}
+		_, err := c.factory.CoreV1().Secrets(nsName).Create(ctx, secret, metav1.CreateOptions{})
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			return errors.WithMessagef(err, "failed to restore secret %q in tenant namespace %q", secretName, nsName)
+		}
+		secretCount++
+	}
+	objectCount := 0
+	for _, kind := range tenantObjectKinds {
+		for _, obj := range objects[kind.name] {
+			err := kind.create(ctx, c, nsName, obj)
+			if err != nil && !k8serrors.IsAlreadyExists(err) {
+				return errors.WithMessagef(err, "failed to restore %s %q in tenant namespace %q", kind.name, obj.Name, nsName)
+			}
+			objectCount++
+		}
+	}
+
+	klog.V(3).Infof(c.formatLogf(tenant, "restored %d secrets and %d other objects in tenant namespace %q", secretCount, objectCount, nsName))
+	return c.deleteHibernationSnapshot(ctx, tenant)
+}
+
+// deleteHibernationSnapshot deletes the snapshot secret of the given
+// tenant if it exists.
+func (c *Controller) deleteHibernationSnapshot(ctx context.Context, tenant *stewardv1alpha1.Tenant) error {
+	name := hibernationSnapshotName(tenant)
+	err := c.factory.CoreV1().Secrets(system.Namespace()).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.WithMessagef(err, "failed to delete hibernation snapshot %q in namespace %q", name, system.Namespace())
+	}
+	return nil
+}
+
+func (c *Controller) now() time.Time {
+	if c.testing != nil && c.testing.nowStub != nil {
+		return c.testing.nowStub()
+	}
+	return time.Now()
+}
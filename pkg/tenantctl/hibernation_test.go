@@ -0,0 +1,373 @@
+package tenantctl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	throttle "github.com/SAP/stewardci-core/pkg/throttle"
+	spew "github.com/davecgh/go-spew/spew"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	knativeapis "knative.dev/pkg/apis"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+func Test_Controller_syncHandler_InitializedTenant_Hibernation(t *testing.T) {
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		tenantNSName   = "prefix1-tenant1-abc123"
+		tenantUID      = "uid1"
+	)
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(-d))
+		return &t
+	}
+
+	for _, tc := range []struct {
+		name               string
+		hibernationPeriod  string
+		adopted            bool
+		namespaceCreatedAt *metav1.Time
+		lastRunActivity    *metav1.Time
+		runs               []*stewardv1alpha1.PipelineRun
+		uncachedRuns       []*stewardv1alpha1.PipelineRun
+		rateLimited        bool
+		expectHibernated   bool
+	}{
+		{
+			name:              "disabled",
+			hibernationPeriod: "",
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+			},
+			expectHibernated: false,
+		},
+		{
+			name:              "inactive",
+			hibernationPeriod: "24h",
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+				finishedRun("run2", tenantNSName, ago(25*time.Hour)),
+			},
+			expectHibernated: true,
+		},
+		{
+			name:               "no runs in old namespace",
+			hibernationPeriod:  "24h",
+			namespaceCreatedAt: ago(25 * time.Hour),
+			expectHibernated:   true,
+		},
+		{
+			name:               "no runs left after recent activity",
+			hibernationPeriod:  "24h",
+			namespaceCreatedAt: ago(100 * time.Hour),
+			lastRunActivity:    ago(23 * time.Hour),
+			expectHibernated:   false,
+		},
+		{
+			name:               "no runs left after old activity",
+			hibernationPeriod:  "24h",
+			namespaceCreatedAt: ago(100 * time.Hour),
+			lastRunActivity:    ago(25 * time.Hour),
+			expectHibernated:   true,
+		},
+		{
+			name:               "no runs in new namespace",
+			hibernationPeriod:  "24h",
+			namespaceCreatedAt: ago(23 * time.Hour),
+			expectHibernated:   false,
+		},
+		{
+			name:              "inactive but deletion rate limited",
+			hibernationPeriod: "24h",
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+			},
+			rateLimited:      true,
+			expectHibernated: false,
+		},
+		{
+			name:              "recently finished run",
+			hibernationPeriod: "24h",
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+				finishedRun("run2", tenantNSName, ago(23*time.Hour)),
+			},
+			expectHibernated: false,
+		},
+		{
+			name:              "recently finished run not cached yet",
+			hibernationPeriod: "24h",
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+			},
+			uncachedRuns: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run2", tenantNSName, ago(1*time.Hour)),
+			},
+			expectHibernated: false,
+		},
+		{
+			name:              "unfinished run",
+			hibernationPeriod: "24h",
+			runs: []*stewardv1alpha1.PipelineRun{
+				k8sfake.PipelineRun("run1", tenantNSName, stewardv1alpha1.PipelineSpec{}),
+			},
+			expectHibernated: false,
+		},
+		{
+			name:              "adopted namespace",
+			hibernationPeriod: "24h",
+			adopted:           true,
+			runs: []*stewardv1alpha1.PipelineRun{
+				finishedRun("run1", tenantNSName, ago(100*time.Hour)),
+			},
+			expectHibernated: false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+
+			origTenant := k8sfake.Tenant(tenantID, clientNSName)
+			origTenant.SetUID(types.UID(tenantUID))
+			origTenant.Status.TenantNamespaceName = tenantNSName
+			origTenant.Status.LastRunActivity = tc.lastRunActivity
+			annotations := map[string]string{
+				stewardv1alpha1.AnnotationTenantNamespaceRequested: "",
+			}
+			if tc.adopted {
+				annotations[stewardv1alpha1.AnnotationAdoptNamespace] = tenantNSName
+			}
+			origTenant.SetAnnotations(annotations)
+
+			tenantNamespace := k8sfake.Namespace(tenantNSName)
+			tenantNamespace.SetLabels(map[string]string{"prefix": tenantNSPrefix})
+			if tc.namespaceCreatedAt != nil {
+				tenantNamespace.SetCreationTimestamp(*tc.namespaceCreatedAt)
+			}
+
+			cf := k8sfake.NewClientFactory(
+				// the client namespace
+				k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+					stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+					stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+					stewardv1alpha1.AnnotationTenantHibernationPeriod: tc.hibernationPeriod,
+				}),
+				origTenant,
+				tenantNamespace,
+				k8sfake.SecretOpaque("secret1", tenantNSName),
+				k8sfake.Trigger("trigger1", tenantNSName, stewardv1alpha1.TriggerSpec{SecretName: "secret1"}),
+			)
+			for _, run := range tc.runs {
+				_, err := cf.StewardV1alpha1().PipelineRuns(tenantNSName).Create(ctx, run, metav1.CreateOptions{})
+				assert.NilError(t, err)
+				err = cf.StewardInformerFactory().Steward().V1alpha1().PipelineRuns().Informer().GetStore().Add(run)
+				assert.NilError(t, err)
+			}
+			for _, run := range tc.uncachedRuns {
+				_, err := cf.StewardV1alpha1().PipelineRuns(tenantNSName).Create(ctx, run, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			ctl := NewController(cf, ControllerOpts{})
+			ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+			ctl.testing = &controllerTesting{
+				nowStub: func() time.Time { return now },
+			}
+			if tc.rateLimited {
+				ctl.namespaceDeletionLimiter = throttle.NewDeletionLimiter(0.001, 1)
+				assert.Equal(t, time.Duration(0), ctl.namespaceDeletionLimiter.Delay("other/tenant"))
+			}
+
+			// EXERCISE
+			resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+			assert.NilError(t, err)
+			dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+			readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+			hibernatedCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionHibernated)
+			snapshot, snapshotErr := cf.CoreV1().Secrets(system.Namespace()).Get(ctx, "steward-hibernation-"+tenantUID, metav1.GetOptions{})
+
+			if tc.expectHibernated {
+				assert.Equal(t, "", tenant.Status.TenantNamespaceName, dump)
+				assert.Assert(t, readyCond.IsFalse(), dump)
+				assert.Equal(t, stewardv1alpha1.StatusReasonTenantHibernated, readyCond.Reason, dump)
+				assert.Assert(t, hibernatedCond.IsTrue(), dump)
+				reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionHibernated)
+				assert.Assert(t, reasonCode != nil, dump)
+				assert.Equal(t, stewardv1alpha1.MessageCodeTenantHibernated, reasonCode.ReasonCode, dump)
+				assert.DeepEqual(t, map[string]string{"namespace": tenantNSName}, reasonCode.MessageArgs)
+				_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationTenantNamespaceRequested]
+				assert.Assert(t, !requested, dump)
+				assertThatExactlyTheseNamespacesExist(t, cf, clientNSName)
+				assert.NilError(t, snapshotErr)
+				assert.Assert(t, is.Contains(snapshot.Data, "secret1"))
+				assert.Assert(t, is.Contains(snapshot.Data, "_Trigger.trigger1"))
+			} else {
+				assert.Equal(t, tenantNSName, tenant.Status.TenantNamespaceName, dump)
+				assert.Assert(t, readyCond.IsTrue(), dump)
+				assert.Equal(t, "", readyCond.Reason, dump)
+				assert.Assert(t, hibernatedCond == nil, dump)
+				assert.Assert(t, k8serrors.IsNotFound(snapshotErr), dump)
+				assertThatExactlyTheseNamespacesExist(t, cf, clientNSName, tenantNSName)
+			}
+		})
+	}
+}
+
+func Test_Controller_syncHandler_HibernatedTenant_NotRequested(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	origTenant := hibernatedTenant(tenantID, clientNSName, "oldname1")
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+			stewardv1alpha1.AnnotationTenantHibernationPeriod: "24h",
+		}),
+		origTenant,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, origTenant.Status, tenant.Status)
+	assertThatExactlyTheseNamespacesExist(t, cf, clientNSName)
+}
+
+func Test_Controller_syncHandler_HibernatedTenant_Requested(t *testing.T) {
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+	)
+
+	ctx := context.Background()
+	origTenant := hibernatedTenant(tenantID, clientNSName, "oldname1")
+	origTenant.SetUID("uid1")
+	origTenant.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationTenantNamespaceRequested: "",
+	})
+	savedSecret, err := json.Marshal(hibernatedSecret{
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{"username": []byte("user1")},
+	})
+	assert.NilError(t, err)
+	savedTemplate, err := json.Marshal(tenantObject{
+		Labels: map[string]string{"label1": "value1"},
+		Spec:   json.RawMessage(`{"jenkinsFile":{"repoUrl":"https://github.com/org/repo"}}`),
+	})
+	assert.NilError(t, err)
+	snapshot := k8sfake.SecretOpaque("steward-hibernation-uid1", system.Namespace())
+	snapshot.Data = map[string][]byte{
+		"secret1":                        savedSecret,
+		"_PipelineRunTemplate.template1": savedTemplate,
+	}
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix:   tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:              tenantRoleName,
+			stewardv1alpha1.AnnotationTenantHibernationPeriod: "24h",
+		}),
+		origTenant,
+		snapshot,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+	readyCond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	assert.Assert(t, readyCond.IsTrue(), dump)
+	assert.Equal(t, "", readyCond.Reason, dump)
+	assert.Assert(t, tenant.Status.GetCondition(stewardv1alpha1.TenantConditionHibernated).IsFalse(), dump)
+	assert.Assert(t, tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionHibernated) == nil, dump)
+	nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, tenantNSPrefix, tenantID)
+	assert.Assert(t, is.Regexp(nsNamePattern, tenant.Status.TenantNamespaceName), dump)
+
+	assertThatExactlyTheseNamespacesExist(t, cf,
+		clientNSName,
+		tenant.Status.TenantNamespaceName,
+	)
+
+	secret, err := cf.CoreV1().Secrets(tenant.Status.TenantNamespaceName).Get(ctx, "secret1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, corev1.SecretTypeBasicAuth, secret.Type)
+	assert.DeepEqual(t, map[string][]byte{"username": []byte("user1")}, secret.Data)
+	template, err := cf.StewardV1alpha1().PipelineRunTemplates(tenant.Status.TenantNamespaceName).Get(ctx, "template1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{"label1": "value1"}, template.GetLabels())
+	assert.Equal(t, "https://github.com/org/repo", template.Spec.JenkinsFile.URL)
+	_, err = cf.CoreV1().Secrets(system.Namespace()).Get(ctx, "steward-hibernation-uid1", metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func finishedRun(name, namespace string, finishedAt *metav1.Time) *stewardv1alpha1.PipelineRun {
+	run := k8sfake.PipelineRun(name, namespace, stewardv1alpha1.PipelineSpec{})
+	run.Status.State = stewardv1alpha1.StateFinished
+	run.Status.FinishedAt = finishedAt
+	return run
+}
+
+func hibernatedTenant(name, namespace, oldTenantNSName string) *stewardv1alpha1.Tenant {
+	tenant := k8sfake.Tenant(name, namespace)
+	args := map[string]string{"namespace": oldTenantNSName}
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionHibernated,
+		Status: corev1.ConditionTrue,
+		Reason: stewardv1alpha1.StatusReasonTenantHibernated,
+	}, stewardv1alpha1.MessageCodeTenantHibernated, args)
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionFalse,
+		Reason: stewardv1alpha1.StatusReasonTenantHibernated,
+	}, stewardv1alpha1.MessageCodeTenantHibernated, args)
+	return tenant
+}
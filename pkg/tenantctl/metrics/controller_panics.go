@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ControllerPanics counts the number of panics recovered in
+	// the reconcile loop of the tenant controller.
+	ControllerPanics CounterMetric = &controllerPanics{}
+)
+
+func init() {
+	ControllerPanics.(*controllerPanics).init()
+}
+
+type controllerPanics struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Counter
+}
+
+func (m *controllerPanics) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "controller_panics_total",
+				Help:      "The number of panics recovered while reconciling objects in the tenant controller instance.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *controllerPanics) Inc() {
+	m.metric.Inc()
+}
@@ -1,5 +1,9 @@
 package metrics
 
+import (
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
 // CounterMetric is a monotonic counter metric.
 type CounterMetric interface {
 	Inc()
@@ -9,3 +13,21 @@ type CounterMetric interface {
 type SettableGaugeMetric interface {
 	Set(float64)
 }
+
+// TenantsMetric is a metric that observes the complete set of existing
+// tenants.
+type TenantsMetric interface {
+	Observe(tenants []*stewardapi.Tenant)
+}
+
+// TenantReconcileMetric is a metric that tracks the reconciliation of
+// individual tenants.
+type TenantReconcileMetric interface {
+	// ObserveSuccess records a successful reconciliation of the tenant
+	// with the given client namespace and name.
+	ObserveSuccess(clientNamespace, name string)
+
+	// Forget removes the tenant with the given client namespace and name
+	// from the metric.
+	Forget(clientNamespace, name string)
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// TenantLastSuccessfulReconcile reflects the time elapsed since the
+	// last successful reconciliation of each tenant.
+	TenantLastSuccessfulReconcile TenantReconcileMetric = &tenantLastSuccessfulReconcile{}
+)
+
+func init() {
+	TenantLastSuccessfulReconcile.(*tenantLastSuccessfulReconcile).init()
+}
+
+type tenantKey struct {
+	clientNamespace string
+	name            string
+}
+
+// tenantLastSuccessfulReconcile is a Prometheus collector that computes the
+// elapsed time at collection time, so that the value also grows if a
+// tenant does not get reconciled anymore at all.
+type tenantLastSuccessfulReconcile struct {
+	clock        clock.Clock
+	initOnlyOnce sync.Once
+	desc         *prometheus.Desc
+	mutex        sync.Mutex
+	lastSuccess  map[tenantKey]time.Time
+}
+
+func (m *tenantLastSuccessfulReconcile) init() {
+	m.initOnlyOnce.Do(func() {
+		if m.clock == nil {
+			m.clock = clock.New()
+		}
+		m.lastSuccess = map[tenantKey]time.Time{}
+		m.desc = prometheus.NewDesc(
+			prometheus.BuildFQName("", subsystem, "seconds_since_last_successful_reconcile"),
+			"The time in seconds since the last successful reconciliation of a tenant by this controller instance."+
+				" Tenants not successfully reconciled since the start of the controller instance are not reported.",
+			[]string{
+				"client_namespace",
+				"tenant",
+			},
+			nil,
+		)
+		metrics.Registerer().MustRegister(m)
+	})
+}
+
+func (m *tenantLastSuccessfulReconcile) ObserveSuccess(clientNamespace, name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastSuccess[tenantKey{clientNamespace, name}] = m.clock.Now()
+}
+
+func (m *tenantLastSuccessfulReconcile) Forget(clientNamespace, name string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.lastSuccess, tenantKey{clientNamespace, name})
+}
+
+// Describe implements prometheus.Collector.
+func (m *tenantLastSuccessfulReconcile) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.desc
+}
+
+// Collect implements prometheus.Collector.
+func (m *tenantLastSuccessfulReconcile) Collect(ch chan<- prometheus.Metric) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	now := m.clock.Now()
+	for key, t := range m.lastSuccess {
+		ch <- prometheus.MustNewConstMetric(
+			m.desc,
+			prometheus.GaugeValue,
+			now.Sub(t).Seconds(),
+			key.clientNamespace, key.name,
+		)
+	}
+}
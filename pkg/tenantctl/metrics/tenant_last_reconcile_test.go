@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+)
+
+func Test_TenantLastSuccessfulReconcile_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, TenantLastSuccessfulReconcile.(*tenantLastSuccessfulReconcile).desc != nil)
+}
+
+func Test_tenantLastSuccessfulReconcile(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	reg := prometheus.NewPedanticRegistry()
+	t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Unix(962366400, 0)) // 2000-06-30 12:00:00Z
+
+	examinee := &tenantLastSuccessfulReconcile{
+		clock: mockClock,
+	}
+	examinee.init()
+
+	// EXERCISE
+	examinee.ObserveSuccess("client1", "tenant1")
+	mockClock.Add(10 * time.Second)
+	examinee.ObserveSuccess("client1", "tenant2")
+	examinee.ObserveSuccess("client2", "tenant1")
+	mockClock.Add(5 * time.Second)
+	examinee.Forget("client2", "tenant1")
+
+	// VERIFY
+	assert.DeepEqual(t, gaugeValuesByLabels(t, reg, "steward_tenants_seconds_since_last_successful_reconcile"), map[string]float64{
+		"client_namespace=client1,tenant=tenant1": 15,
+		"client_namespace=client1,tenant=tenant2": 5,
+	})
+}
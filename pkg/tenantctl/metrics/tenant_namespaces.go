@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// TenantNamespacesCreated counts the number of tenant namespaces
+	// created by the tenant controller.
+	TenantNamespacesCreated CounterMetric = &tenantNamespaces{
+		name: "namespaces_created_total",
+		help: "The number of tenant namespaces created by the tenant controller instance.",
+	}
+
+	// TenantNamespacesDeleted counts the number of tenant namespaces
+	// deleted by the tenant controller.
+	TenantNamespacesDeleted CounterMetric = &tenantNamespaces{
+		name: "namespaces_deleted_total",
+		help: "The number of tenant namespaces deleted by the tenant controller instance.",
+	}
+)
+
+func init() {
+	TenantNamespacesCreated.(*tenantNamespaces).init()
+	TenantNamespacesDeleted.(*tenantNamespaces).init()
+}
+
+type tenantNamespaces struct {
+	name         string
+	help         string
+	initOnlyOnce sync.Once
+	metric       prometheus.Counter
+}
+
+func (m *tenantNamespaces) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      m.name,
+				Help:      m.help,
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *tenantNamespaces) Inc() {
+	m.metric.Inc()
+}
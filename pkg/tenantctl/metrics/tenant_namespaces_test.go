@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_TenantNamespacesCreated_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, TenantNamespacesCreated.(*tenantNamespaces).metric != nil)
+}
+
+func Test_TenantNamespacesDeleted_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, TenantNamespacesDeleted.(*tenantNamespaces).metric != nil)
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	reconcileResultSuccess = "success"
+	reconcileResultError   = "error"
+)
+
+var (
+	// TenantReconciles observes the reconciliations of tenants: their
+	// number by result, their duration and the failed ones by reason.
+	TenantReconciles TenantReconcilesMetric = &tenantReconciles{}
+)
+
+// TenantReconcilesMetric is a metric that observes reconciliations of
+// tenants.
+type TenantReconcilesMetric interface {
+	// ObserveSuccess records a successful reconciliation that took the
+	// given duration.
+	ObserveSuccess(duration time.Duration)
+
+	// ObserveError records a failed reconciliation that took the given
+	// duration. The reason identifies the failure, e.g. the reason code
+	// of the ready condition.
+	ObserveError(duration time.Duration, reason string)
+}
+
+func init() {
+	TenantReconciles.(*tenantReconciles).init()
+}
+
+type tenantReconciles struct {
+	initOnlyOnce   sync.Once
+	countMetric    *prometheus.CounterVec
+	durationMetric prometheus.Histogram
+	errorMetric    *prometheus.CounterVec
+}
+
+func (m *tenantReconciles) init() {
+	m.initOnlyOnce.Do(func() {
+		m.countMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "reconciles_total",
+				Help:      "The number of reconciliations of tenants by the tenant controller instance partitioned by result.",
+			},
+			[]string{
+				"result",
+			},
+		)
+		metrics.Registerer().MustRegister(m.countMetric)
+
+		m.durationMetric = prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Subsystem: subsystem,
+				Name:      "reconcile_duration_seconds",
+				Help:      "A histogram of the duration of reconciliations of tenants by the tenant controller instance.",
+				Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+			},
+		)
+		metrics.Registerer().MustRegister(m.durationMetric)
+
+		m.errorMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "reconcile_errors_total",
+				Help:      "The number of failed reconciliations of tenants by the tenant controller instance partitioned by reason.",
+			},
+			[]string{
+				"reason",
+			},
+		)
+		metrics.Registerer().MustRegister(m.errorMetric)
+	})
+}
+
+func (m *tenantReconciles) ObserveSuccess(duration time.Duration) {
+	m.countMetric.WithLabelValues(reconcileResultSuccess).Inc()
+	m.durationMetric.Observe(duration.Seconds())
+}
+
+func (m *tenantReconciles) ObserveError(duration time.Duration, reason string) {
+	m.countMetric.WithLabelValues(reconcileResultError).Inc()
+	m.durationMetric.Observe(duration.Seconds())
+	m.errorMetric.WithLabelValues(reason).Inc()
+}
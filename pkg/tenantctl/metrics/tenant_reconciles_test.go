@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+)
+
+func Test_TenantReconciles_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	examinee := TenantReconciles.(*tenantReconciles)
+	assert.Assert(t, examinee.countMetric != nil)
+	assert.Assert(t, examinee.durationMetric != nil)
+	assert.Assert(t, examinee.errorMetric != nil)
+}
+
+func Test_tenantReconciles_Observe(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	reg := prometheus.NewPedanticRegistry()
+	t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+	examinee := &tenantReconciles{}
+	examinee.init()
+
+	// EXERCISE
+	examinee.ObserveSuccess(250 * time.Millisecond)
+	examinee.ObserveSuccess(500 * time.Millisecond)
+	examinee.ObserveError(time.Second, "TenantNamespaceCreationFailed")
+
+	// VERIFY
+	assert.DeepEqual(t, counterValuesByLabels(t, reg, "steward_tenants_reconciles_total"), map[string]float64{
+		"result=success": 2,
+		"result=error":   1,
+	})
+	assert.DeepEqual(t, counterValuesByLabels(t, reg, "steward_tenants_reconcile_errors_total"), map[string]float64{
+		"reason=TenantNamespaceCreationFailed": 1,
+	})
+	metricFamilies, err := reg.Gather()
+	assert.NilError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() == "steward_tenants_reconcile_duration_seconds" {
+			histogram := metricFamily.GetMetric()[0].GetHistogram()
+			assert.Equal(t, uint64(3), histogram.GetSampleCount())
+			assert.Equal(t, 1.75, histogram.GetSampleSum())
+			return
+		}
+	}
+	t.Fatal("histogram not found")
+}
+
+// counterValuesByLabels gathers the metrics from the given registry and
+// returns the counter values of the metric family with the given name
+// keyed by the comma-separated list of label name-value pairs.
+func counterValuesByLabels(t *testing.T, reg prometheus.Gatherer, name string) map[string]float64 {
+	t.Helper()
+	metricFamilies, err := reg.Gather()
+	assert.NilError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+		result := map[string]float64{}
+		for _, metric := range metricFamily.GetMetric() {
+			key := ""
+			for i, label := range metric.GetLabel() {
+				if i > 0 {
+					key += ","
+				}
+				key += label.GetName() + "=" + label.GetValue()
+			}
+			result[key] = metric.GetCounter().GetValue()
+		}
+		return result
+	}
+	t.Fatalf("metric family %q not found", name)
+	return nil
+}
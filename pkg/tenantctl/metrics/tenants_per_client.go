@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+const (
+	// notReadyReasonUnknown is the reason label value used for tenants
+	// that are not ready without a reason, e.g. because they have not been
+	// reconciled yet.
+	notReadyReasonUnknown = "Unknown"
+)
+
+var (
+	// TenantsPerClient observes all existing tenants and reflects their
+	// state partitioned by client namespace.
+	TenantsPerClient TenantsMetric = &tenantsPerClient{}
+)
+
+func init() {
+	TenantsPerClient.(*tenantsPerClient).init()
+}
+
+type tenantsPerClient struct {
+	initOnlyOnce     sync.Once
+	tenantMetric     *prometheus.GaugeVec
+	namespaceMetric  *prometheus.GaugeVec
+	notReadyMetric   *prometheus.GaugeVec
+	observationMutex sync.Mutex
+}
+
+func (m *tenantsPerClient) init() {
+	m.initOnlyOnce.Do(func() {
+		m.tenantMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "per_client_count",
+				Help:      "The current number of tenants partitioned by client namespace.",
+			},
+			[]string{
+				"client_namespace",
+			},
+		)
+		metrics.Registerer().MustRegister(m.tenantMetric)
+
+		m.namespaceMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "namespaces_per_client_count",
+				Help:      "The current number of tenants with a tenant namespace partitioned by client namespace.",
+			},
+			[]string{
+				"client_namespace",
+			},
+		)
+		metrics.Registerer().MustRegister(m.namespaceMetric)
+
+		m.notReadyMetric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "not_ready_per_client_count",
+				Help:      "The current number of tenants that are not ready partitioned by client namespace and reason of the ready condition.",
+			},
+			[]string{
+				"client_namespace",
+				"reason",
+			},
+		)
+		metrics.Registerer().MustRegister(m.notReadyMetric)
+	})
+}
+
+func (m *tenantsPerClient) Observe(tenants []*stewardapi.Tenant) {
+	type notReadyKey struct{ clientNamespace, reason string }
+
+	tenantCounts := map[string]int{}
+	namespaceCounts := map[string]int{}
+	notReadyCounts := map[notReadyKey]int{}
+
+	for _, tenant := range tenants {
+		clientNamespace := tenant.GetNamespace()
+		tenantCounts[clientNamespace]++
+		if tenant.Status.TenantNamespaceName != "" {
+			namespaceCounts[clientNamespace]++
+		}
+		if reason, ready := readiness(tenant); !ready {
+			notReadyCounts[notReadyKey{clientNamespace, reason}]++
+		}
+	}
+
+	m.observationMutex.Lock()
+	defer m.observationMutex.Unlock()
+
+	// reset to drop client namespaces and reasons that do not occur anymore
+	m.tenantMetric.Reset()
+	m.namespaceMetric.Reset()
+	m.notReadyMetric.Reset()
+
+	for clientNamespace, count := range tenantCounts {
+		m.tenantMetric.WithLabelValues(clientNamespace).Set(float64(count))
+		m.namespaceMetric.WithLabelValues(clientNamespace).Set(float64(namespaceCounts[clientNamespace]))
+	}
+	for key, count := range notReadyCounts {
+		m.notReadyMetric.WithLabelValues(key.clientNamespace, key.reason).Set(float64(count))
+	}
+}
+
+// readiness returns whether the given tenant is ready and if not, the
+// reason of the ready condition.
+func readiness(tenant *stewardapi.Tenant) (reason string, ready bool) {
+	cond := tenant.Status.GetCondition(knativeapis.ConditionReady)
+	if cond.IsTrue() {
+		return "", true
+	}
+	if cond == nil || cond.Reason == "" {
+		return notReadyReasonUnknown, false
+	}
+	return cond.Reason, false
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func Test_TenantsPerClient_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	examinee := TenantsPerClient.(*tenantsPerClient)
+	assert.Assert(t, examinee.tenantMetric != nil)
+	assert.Assert(t, examinee.namespaceMetric != nil)
+	assert.Assert(t, examinee.notReadyMetric != nil)
+}
+
+func Test_tenantsPerClient_Observe(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	reg := prometheus.NewPedanticRegistry()
+	t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+
+	examinee := &tenantsPerClient{}
+	examinee.init()
+
+	// a tenant of a client namespace that vanishes with the second observation
+	examinee.Observe([]*stewardapi.Tenant{
+		newTenantForTest("client0", "tenant1", "ns1", nil),
+	})
+
+	// EXERCISE
+	examinee.Observe([]*stewardapi.Tenant{
+		newTenantForTest("client1", "tenant1", "ns1", &knativeapis.Condition{Status: corev1.ConditionTrue}),
+		newTenantForTest("client1", "tenant2", "ns2", &knativeapis.Condition{Status: corev1.ConditionFalse, Reason: "Failed"}),
+		newTenantForTest("client1", "tenant3", "", &knativeapis.Condition{Status: corev1.ConditionFalse, Reason: "Failed"}),
+		newTenantForTest("client2", "tenant1", "", nil),
+	})
+
+	// VERIFY
+	assert.DeepEqual(t, gaugeValuesByLabels(t, reg, "steward_tenants_per_client_count"), map[string]float64{
+		"client_namespace=client1": 3,
+		"client_namespace=client2": 1,
+	})
+	assert.DeepEqual(t, gaugeValuesByLabels(t, reg, "steward_tenants_namespaces_per_client_count"), map[string]float64{
+		"client_namespace=client1": 2,
+		"client_namespace=client2": 0,
+	})
+	assert.DeepEqual(t, gaugeValuesByLabels(t, reg, "steward_tenants_not_ready_per_client_count"), map[string]float64{
+		"client_namespace=client1,reason=Failed":  2,
+		"client_namespace=client2,reason=Unknown": 1,
+	})
+}
+
+func newTenantForTest(clientNamespace, name, tenantNamespace string, readyCond *knativeapis.Condition) *stewardapi.Tenant {
+	tenant := &stewardapi.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clientNamespace,
+		},
+	}
+	tenant.Status.TenantNamespaceName = tenantNamespace
+	if readyCond != nil {
+		cond := *readyCond
+		cond.Type = knativeapis.ConditionReady
+		tenant.Status.SetCondition(&cond)
+	}
+	return tenant
+}
+
+// gaugeValuesByLabels gathers the metrics from the given registry and
+// returns the gauge values of the metric family with the given name keyed
+// by the comma-separated list of label name-value pairs.
+func gaugeValuesByLabels(t *testing.T, reg prometheus.Gatherer, name string) map[string]float64 {
+	t.Helper()
+	metricFamilies, err := reg.Gather()
+	assert.NilError(t, err)
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != name {
+			continue
+		}
+		result := map[string]float64{}
+		for _, metric := range metricFamily.GetMetric() {
+			key := ""
+			for i, label := range metric.GetLabel() {
+				if i > 0 {
+					key += ","
+				}
+				key += label.GetName() + "=" + label.GetValue()
+			}
+			result[key] = metric.GetGauge().GetValue()
+		}
+		return result
+	}
+	t.Fatalf("metric family %q not found", name)
+	return nil
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// WorkqueueDroppedKeys counts the number of workqueue keys dropped
+	// by the tenant controller after the maximum number of retries.
+	WorkqueueDroppedKeys CounterMetric = &workqueueDroppedKeys{}
+)
+
+func init() {
+	WorkqueueDroppedKeys.(*workqueueDroppedKeys).init()
+}
+
+type workqueueDroppedKeys struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Counter
+}
+
+func (m *workqueueDroppedKeys) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystemForWorkqueue,
+				Name:      "dropped_keys_total",
+				Help:      "The number of workqueue keys dropped by the tenant controller instance after the maximum number of retries.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *workqueueDroppedKeys) Inc() {
+	m.metric.Inc()
+}
@@ -0,0 +1,251 @@
+package tenantctl
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	stewardapis "github.com/SAP/stewardci-core/pkg/apis/steward"
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/ghodss/yaml"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	equality "k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// tenantNetworkPolicyNamePrefix is the name prefix of network
+	// policies in tenant namespaces managed by the tenant controller.
+	// The full name is the prefix followed by the key of the network
+	// policy in the network policies ConfigMap.
+	tenantNetworkPolicyNamePrefix = stewardapis.GroupName + "--tenant-network-policy-"
+)
+
+// newNetworkPoliciesConfigMapInformer returns an informer for the network
+// policies ConfigMap with the given name in the system namespace.
+// It watches this single ConfigMap only, which allows to restrict the
+// permissions of the tenant controller to it.
+func newNetworkPoliciesConfigMapInformer(factory k8s.ClientFactory, name string) cache.SharedIndexInformer {
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return factory.CoreV1().ConfigMaps(system.Namespace()).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return factory.CoreV1().ConfigMaps(system.Namespace()).Watch(context.TODO(), options)
+		},
+	}
+	return cache.NewSharedIndexInformer(listWatch, &corev1.ConfigMap{}, 0, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+}
+
+// newNetworkPoliciesConfigMapLister returns a lister for ConfigMaps in
+// the system namespace backed by the given indexer.
+func newNetworkPoliciesConfigMapLister(indexer cache.Indexer) corev1listers.ConfigMapNamespaceLister {
+	return corev1listers.NewConfigMapLister(indexer).ConfigMaps(system.Namespace())
+}
+
+// onNetworkPoliciesConfigMapChange enqueues all tenants, so that changes
+// of the network policies ConfigMap are applied to all tenant namespaces.
+func (c *Controller) onNetworkPoliciesConfigMapChange() {
+	tenants, err := c.tenantLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list tenants after change of network policies ConfigMap: %s", err.Error())
+		return
+	}
+	klog.V(3).Infof("Network policies ConfigMap changed, enqueueing %d tenants", len(tenants))
+	for _, tenant := range tenants {
+		c.addToQueue(c.getKey(tenant), "Network policies ConfigMap change")
+	}
+}
+
+// reconcileTenantNetworkPolicies ensures that the given tenant namespace
+// contains exactly the network policies configured in the network policies
+// ConfigMap. Outdated network policies get updated, and network policies
+// for entries removed from the ConfigMap get deleted.
+// Network policies not managed by the tenant controller are not touched.
+// If no network policies ConfigMap is configured, nothing is done.
+func (c *Controller) reconcileTenantNetworkPolicies(ctx context.Context, tenant *stewardv1alpha1.Tenant, namespace string) error {
+	if c.networkPoliciesConfigMapName == "" {
+		return nil
+	}
+
+	err := func() error {
+		expectedPolicies, err := c.loadTenantNetworkPolicies(namespace)
+		if err != nil {
+			return err
+		}
+
+		policyIfc := c.factory.NetworkingV1().NetworkPolicies(namespace)
+		listOptions := metav1.ListOptions{
+			LabelSelector: stewardv1alpha1.LabelSystemManaged,
+		}
+		policyList, err := policyIfc.List(ctx, listOptions)
+		if err != nil {
+			return errors.WithMessage(err, "failed to list managed network policies")
+		}
+		existingPolicies := map[string]*networkingv1.NetworkPolicy{}
+		for i := range policyList.Items {
+			policy := &policyList.Items[i]
+			if strings.HasPrefix(policy.GetName(), tenantNetworkPolicyNamePrefix) {
+				existingPolicies[policy.GetName()] = policy
+			}
+		}
+
+		for _, expected := range expectedPolicies {
+			name := expected.GetName()
+			current, exists := existingPolicies[name]
+			delete(existingPolicies, name)
+
+			if !exists {
+				klog.V(4).Infof(c.formatLogf(tenant, "creating network policy %q in tenant namespace %q", name, namespace))
+				if _, err := policyIfc.Create(ctx, expected, metav1.CreateOptions{}); err != nil {
+					return errors.WithMessagef(err, "failed to create network policy %q", name)
+				}
+				continue
+			}
+
+			if c.isTenantNetworkPolicyUpToDate(current, expected) {
+				continue
+			}
+			klog.V(4).Infof(c.formatLogf(tenant, "updating network policy %q in tenant namespace %q", name, namespace))
+			updated := current.DeepCopy()
+			updated.SetLabels(expected.GetLabels())
+			updated.Spec = expected.Spec
+			if _, err := policyIfc.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+				return errors.WithMessagef(err, "failed to update network policy %q", name)
+			}
+		}
+
+		for name, obsolete := range existingPolicies {
+			klog.V(4).Infof(c.formatLogf(tenant, "deleting network policy %q in tenant namespace %q", name, namespace))
+			deleteOptions := metav1.NewDeleteOptions(0)
+			deleteOptions.Preconditions = metav1.NewUIDPreconditions(string(obsolete.GetUID()))
+			err := policyIfc.Delete(ctx, name, *deleteOptions)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return errors.WithMessagef(err, "failed to delete network policy %q", name)
+			}
+		}
+
+		return nil
+	}()
+
+	if err != nil {
+		return errors.WithMessagef(err,
+			"failed to reconcile the network policies in tenant namespace %q",
+			namespace,
+		)
+	}
+	return nil
+}
+
+// loadTenantNetworkPolicies loads the network policies configured in the
+// network policies ConfigMap from the informer cache and returns them as
+// objects to be created in the given tenant namespace, ordered by name.
+// Each entry of the ConfigMap is a NetworkPolicy manifest. The metadata of
+// the manifests is ignored.
+func (c *Controller) loadTenantNetworkPolicies(namespace string) ([]*networkingv1.NetworkPolicy, error) {
+	configMapName := c.networkPoliciesConfigMapName
+	configMap, err := c.networkPoliciesConfigMapLister.Get(configMapName)
+	if err != nil {
+		return nil, errors.WithMessagef(err,
+			"failed to get ConfigMap %q in namespace %q",
+			configMapName, system.Namespace(),
+		)
+	}
+
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*networkingv1.NetworkPolicy, 0, len(keys))
+	for _, key := range keys {
+		wrapError := func(cause error) error {
+			return errors.Wrapf(cause,
+				"invalid configuration: ConfigMap %q in namespace %q: entry %q",
+				configMapName, system.Namespace(), key,
+			)
+		}
+
+		name := tenantNetworkPolicyNamePrefix + key
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			return nil, wrapError(errors.Errorf("invalid key: %s", strings.Join(msgs, "; ")))
+		}
+
+		manifest := &networkingv1.NetworkPolicy{}
+		if err := yaml.Unmarshal([]byte(configMap.Data[key]), manifest); err != nil {
+			return nil, wrapError(err)
+		}
+		if manifest.APIVersion != networkingv1.SchemeGroupVersion.String() || manifest.Kind != "NetworkPolicy" {
+			return nil, wrapError(errors.Errorf(
+				"manifest does not denote a NetworkPolicy of API version %q",
+				networkingv1.SchemeGroupVersion.String(),
+			))
+		}
+
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: manifest.Spec,
+		}
+		setNetworkPolicySpecDefaults(&policy.Spec)
+		slabels.LabelAsSystemManaged(policy)
+		slabels.LabelWithInstanceID(policy, c.factory.InstanceID())
+		result = append(result, policy)
+	}
+	return result, nil
+}
+
+func (c *Controller) isTenantNetworkPolicyUpToDate(current *networkingv1.NetworkPolicy, expected *networkingv1.NetworkPolicy) bool {
+	return true &&
+		equality.Semantic.DeepEqual(expected.GetLabels(), current.GetLabels()) &&
+		equality.Semantic.DeepEqual(expected.Spec, current.Spec)
+}
+
+// setNetworkPolicySpecDefaults sets the same defaults the Kubernetes API
+// server sets for network policies, so that the configured spec can be
+// compared with the spec of existing network policies.
+func setNetworkPolicySpecDefaults(spec *networkingv1.NetworkPolicySpec) {
+	defaultPorts := func(ports []networkingv1.NetworkPolicyPort) {
+		for i := range ports {
+			if ports[i].Protocol == nil {
+				protocol := corev1.ProtocolTCP
+				ports[i].Protocol = &protocol
+			}
+		}
+	}
+	for i := range spec.Ingress {
+		defaultPorts(spec.Ingress[i].Ports)
+	}
+	for i := range spec.Egress {
+		defaultPorts(spec.Egress[i].Ports)
+	}
+
+	if len(spec.PolicyTypes) == 0 {
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(spec.Egress) != 0 {
+			spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+}
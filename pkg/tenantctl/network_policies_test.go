@@ -0,0 +1,306 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardv1alpha1listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"knative.dev/pkg/system"
+	_ "knative.dev/pkg/system/testing"
+)
+
+const (
+	networkPoliciesConfigMapName = "networkPolicies1"
+
+	networkPolicyDenyAll = `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: ignored
+spec:
+  podSelector: {}
+`
+	networkPolicyAllowDNS = `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+spec:
+  podSelector: {}
+  egress:
+  - ports:
+    - port: 53
+      protocol: UDP
+    - port: 53
+`
+)
+
+func newNetworkPoliciesConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      networkPoliciesConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: data,
+	}
+}
+
+// newNetworkPoliciesTestController returns a controller managing network
+// policies whose ConfigMap cache contains the given ConfigMaps.
+func newNetworkPoliciesTestController(t *testing.T, cf *k8sfake.ClientFactory, configMaps ...*corev1.ConfigMap) *Controller {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, configMap := range configMaps {
+		assert.NilError(t, indexer.Add(configMap))
+	}
+	return &Controller{
+		factory:                        cf,
+		networkPoliciesConfigMapName:   networkPoliciesConfigMapName,
+		networkPoliciesConfigMapLister: newNetworkPoliciesConfigMapLister(indexer),
+	}
+}
+
+func newManagedNetworkPolicy(name, namespace string, spec networkingv1.NetworkPolicySpec) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				stewardv1alpha1.LabelSystemManaged: "",
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func listNetworkPolicies(t *testing.T, cf *k8sfake.ClientFactory, namespace string) map[string]networkingv1.NetworkPolicy {
+	t.Helper()
+	list, err := cf.NetworkingV1().NetworkPolicies(namespace).List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	result := map[string]networkingv1.NetworkPolicy{}
+	for _, item := range list.Items {
+		result[item.GetName()] = item
+	}
+	return result
+}
+
+func Test_Controller_reconcileTenantNetworkPolicies_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory()
+	examinee := newNetworkPoliciesTestController(t, cf,
+		newNetworkPoliciesConfigMap(map[string]string{"deny-all": networkPolicyDenyAll}),
+	)
+	examinee.networkPoliciesConfigMapName = ""
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNetworkPolicies(ctx, k8sfake.Tenant("tenant1", "client1"), tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, is.Len(listNetworkPolicies(t, cf, tenantNSName), 0))
+}
+
+func Test_Controller_reconcileTenantNetworkPolicies_Creates(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory()
+	examinee := newNetworkPoliciesTestController(t, cf,
+		newNetworkPoliciesConfigMap(map[string]string{
+			"deny-all":  networkPolicyDenyAll,
+			"allow-dns": networkPolicyAllowDNS,
+		}),
+	)
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNetworkPolicies(ctx, k8sfake.Tenant("tenant1", "client1"), tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	policies := listNetworkPolicies(t, cf, tenantNSName)
+	assert.Assert(t, is.Len(policies, 2))
+
+	denyAll, found := policies["steward.sap.com--tenant-network-policy-deny-all"]
+	assert.Assert(t, found)
+	assert.Assert(t, is.Contains(denyAll.GetLabels(), stewardv1alpha1.LabelSystemManaged))
+	assert.DeepEqual(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, denyAll.Spec.PolicyTypes)
+
+	allowDNS, found := policies["steward.sap.com--tenant-network-policy-allow-dns"]
+	assert.Assert(t, found)
+	assert.DeepEqual(t,
+		[]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		allowDNS.Spec.PolicyTypes,
+	)
+	assert.Equal(t, corev1.ProtocolTCP, *allowDNS.Spec.Egress[0].Ports[1].Protocol)
+}
+
+func Test_Controller_reconcileTenantNetworkPolicies_UpdatesAndDeletes(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	ctx := context.Background()
+	outdatedSpec := networkingv1.NetworkPolicySpec{
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+	}
+	unmanaged := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unmanaged1",
+			Namespace: tenantNSName,
+		},
+	}
+	cf := k8sfake.NewClientFactory(
+		newManagedNetworkPolicy("steward.sap.com--tenant-network-policy-deny-all", tenantNSName, outdatedSpec),
+		newManagedNetworkPolicy("steward.sap.com--tenant-network-policy-removed", tenantNSName, outdatedSpec),
+		newManagedNetworkPolicy("steward.sap.com--configured-xyz", tenantNSName, outdatedSpec),
+		unmanaged,
+	)
+	examinee := newNetworkPoliciesTestController(t, cf,
+		newNetworkPoliciesConfigMap(map[string]string{"deny-all": networkPolicyDenyAll}),
+	)
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNetworkPolicies(ctx, k8sfake.Tenant("tenant1", "client1"), tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	policies := listNetworkPolicies(t, cf, tenantNSName)
+	assert.Assert(t, is.Len(policies, 3))
+
+	denyAll, found := policies["steward.sap.com--tenant-network-policy-deny-all"]
+	assert.Assert(t, found)
+	assert.DeepEqual(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, denyAll.Spec.PolicyTypes)
+
+	_, found = policies["steward.sap.com--tenant-network-policy-removed"]
+	assert.Assert(t, !found)
+
+	// not managed by the tenant controller
+	_, found = policies["steward.sap.com--configured-xyz"]
+	assert.Assert(t, found)
+	_, found = policies["unmanaged1"]
+	assert.Assert(t, found)
+}
+
+func Test_Controller_reconcileTenantNetworkPolicies_UpToDate(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const tenantNSName = "tenantNS1"
+	ctx := context.Background()
+	cf := k8sfake.NewClientFactory()
+	examinee := newNetworkPoliciesTestController(t, cf,
+		newNetworkPoliciesConfigMap(map[string]string{"deny-all": networkPolicyDenyAll}),
+	)
+	tenant := k8sfake.Tenant("tenant1", "client1")
+	assert.NilError(t, examinee.reconcileTenantNetworkPolicies(ctx, tenant, tenantNSName))
+	cf.KubernetesClientset().ClearActions()
+
+	// EXERCISE
+	resultErr := examinee.reconcileTenantNetworkPolicies(ctx, tenant, tenantNSName)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	for _, action := range cf.KubernetesClientset().Actions() {
+		assert.Assert(t, action.GetVerb() == "list", "unexpected action: %v", action)
+	}
+}
+
+func Test_Controller_reconcileTenantNetworkPolicies_Fails(t *testing.T) {
+	t.Parallel()
+
+	const tenantNSName = "tenantNS1"
+
+	for _, tc := range []struct {
+		name            string
+		configMaps      []*corev1.ConfigMap
+		expectedMessage string
+	}{
+		{
+			name:       "ConfigMap missing",
+			configMaps: []*corev1.ConfigMap{},
+			expectedMessage: `failed to reconcile the network policies in tenant namespace "tenantNS1":` +
+				` failed to get ConfigMap "networkPolicies1" in namespace "knative-testing":` +
+				` configmap "networkPolicies1" not found`,
+		},
+		{
+			name: "wrong kind",
+			configMaps: []*corev1.ConfigMap{
+				newNetworkPoliciesConfigMap(map[string]string{
+					"policy1": "apiVersion: v1\nkind: ConfigMap\n",
+				}),
+			},
+			expectedMessage: `failed to reconcile the network policies in tenant namespace "tenantNS1":` +
+				` invalid configuration: ConfigMap "networkPolicies1" in namespace "knative-testing": entry "policy1":` +
+				` manifest does not denote a NetworkPolicy of API version "networking.k8s.io/v1"`,
+		},
+		{
+			name: "invalid key",
+			configMaps: []*corev1.ConfigMap{
+				newNetworkPoliciesConfigMap(map[string]string{
+					"Policy_1": networkPolicyDenyAll,
+				}),
+			},
+			expectedMessage: `failed to reconcile the network policies in tenant namespace "tenantNS1":` +
+				` invalid configuration: ConfigMap "networkPolicies1" in namespace "knative-testing": entry "Policy_1":` +
+				` invalid key: `,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			cf := k8sfake.NewClientFactory()
+			examinee := newNetworkPoliciesTestController(t, cf, tc.configMaps...)
+
+			// EXERCISE
+			resultErr := examinee.reconcileTenantNetworkPolicies(ctx, k8sfake.Tenant("tenant1", "client1"), tenantNSName)
+
+			// VERIFY
+			assert.ErrorContains(t, resultErr, tc.expectedMessage)
+			assert.Assert(t, is.Len(listNetworkPolicies(t, cf, tenantNSName), 0))
+		})
+	}
+}
+
+func Test_Controller_onNetworkPoliciesConfigMapChange_EnqueuesAllTenants(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	tenantIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NilError(t, tenantIndexer.Add(k8sfake.Tenant("tenant1", "client1")))
+	assert.NilError(t, tenantIndexer.Add(k8sfake.Tenant("tenant2", "client2")))
+	examinee := &Controller{
+		tenantLister: stewardv1alpha1listers.NewTenantLister(tenantIndexer),
+		workqueue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	defer examinee.workqueue.ShutDown()
+
+	// EXERCISE
+	examinee.onNetworkPoliciesConfigMapChange()
+
+	// VERIFY
+	keys := []string{}
+	for examinee.workqueue.Len() > 0 {
+		key, _ := examinee.workqueue.Get()
+		keys = append(keys, key.(string))
+		examinee.workqueue.Done(key)
+	}
+	assert.Assert(t, is.Len(keys, 2))
+	assert.Assert(t, is.Contains(keys, "client1/tenant1"))
+	assert.Assert(t, is.Contains(keys, "client2/tenant2"))
+}
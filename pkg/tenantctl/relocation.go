@@ -0,0 +1,351 @@
+package tenantctl
+
+import (
+	"context"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	metrics "github.com/SAP/stewardci-core/pkg/tenantctl/metrics"
+	errors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+// errStopListing stops the iteration over list items without a failure.
+var errStopListing = errors.New("stop listing")
+
+// relocatedNamespaceAnnotations are the keys of the annotations of a
+// tenant namespace that are copied to the new tenant namespace when the
+// tenant gets relocated.
+var relocatedNamespaceAnnotations = []string{
+	stewardv1alpha1.AnnotationMaxConcurrentRuns,
+	stewardv1alpha1.AnnotationDefaultImagePullSecrets,
+}
+
+// reconcileRelocation moves the given initialized tenant to a new tenant
+// namespace if requested via annotation
+// stewardv1alpha1.AnnotationRelocateTenantNamespace.
+// The relocation consists of the following steps. The outcome of each
+// step is persisted in the tenant status, so that a relocation gets
+// resumed after a failure:
+//
+//  1. The name of the new tenant namespace is planned with the current
+//     configuration of the client.
+//  2. The new tenant namespace is created and set up like any new tenant
+//     namespace. The secrets and some annotations of the current tenant
+//     namespace are copied. Afterwards the tenant is switched to the new
+//     tenant namespace and the current one becomes the former tenant
+//     namespace.
+//  3. The former tenant namespace is deleted as soon as it does not
+//     contain unfinished pipeline runs anymore.
+//
+// Condition stewardv1alpha1.TenantConditionRelocating reflects the
+// progress. A failed relocation does not affect the readiness of the
+// tenant, as the current tenant namespace is kept until the tenant has
+// been switched.
+func (c *Controller) reconcileRelocation(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	if !c.isRelocating(tenant) {
+		if !c.isRelocationRequested(tenant) {
+			return nil
+		}
+		// adopted namespaces have not been created by us and are never deleted
+		if tenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace] != "" {
+			tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+				Type:   stewardv1alpha1.TenantConditionRelocating,
+				Status: corev1.ConditionFalse,
+				Reason: stewardv1alpha1.StatusReasonFailed,
+			}, stewardv1alpha1.MessageCodeTenantRelocationRefused, map[string]string{"namespace": tenant.Status.TenantNamespaceName})
+			return nil
+		}
+		if err := c.planRelocation(ctx, config, tenant); err != nil {
+			return err
+		}
+	}
+
+	if tenant.Status.PlannedTenantNamespaceName != tenant.Status.TenantNamespaceName {
+		if err := c.moveToPlannedTenantNamespace(ctx, config, tenant); err != nil {
+			return err
+		}
+	}
+
+	return c.deleteFormerTenantNamespaceIfIdle(ctx, tenant)
+}
+
+// isRelocationRequested returns whether the relocation of the tenant has
+// been requested via annotation
+// stewardv1alpha1.AnnotationRelocateTenantNamespace.
+func (c *Controller) isRelocationRequested(tenant *stewardv1alpha1.Tenant) bool {
+	_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationRelocateTenantNamespace]
+	return requested
+}
+
+// isRelocating returns whether the relocation of the given initialized
+// tenant has been started but not completed yet.
+func (c *Controller) isRelocating(tenant *stewardv1alpha1.Tenant) bool {
+	return tenant.Status.PlannedTenantNamespaceName != tenant.Status.TenantNamespaceName ||
+		tenant.Status.FormerTenantNamespaceName != ""
+}
+
+// planRelocation plans the name of the new tenant namespace of the given
+// tenant and persists it as `status.plannedTenantNamespaceName`.
+func (c *Controller) planRelocation(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	nsName, err := c.getNamespaceManager(config).PlanName(tenant.GetName())
+	if err != nil {
+		err = errors.WithMessage(err, "failed to plan name of new tenant namespace")
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+
+	origStatus := tenant.Status.DeepCopy()
+	tenant.Status.PlannedTenantNamespaceName = nsName
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionRelocating,
+		Status: corev1.ConditionTrue,
+		Reason: stewardv1alpha1.StatusReasonTenantRelocating,
+	}, stewardv1alpha1.MessageCodeTenantRelocating, map[string]string{
+		"namespace":    tenant.Status.TenantNamespaceName,
+		"newNamespace": nsName,
+	})
+	updatedTenant, err := c.updateStatus(ctx, tenant)
+	if err != nil {
+		tenant.Status = *origStatus
+		return err
+	}
+	tenant.ObjectMeta = updatedTenant.ObjectMeta
+	klog.V(3).Infof(c.formatLogf(tenant, "relocating tenant from tenant namespace %q to %q", tenant.Status.TenantNamespaceName, nsName))
+	return nil
+}
+
+// moveToPlannedTenantNamespace sets up the planned tenant namespace of
+// the given tenant as copy of the current one and switches the tenant to
+// it. The switch is persisted immediately, as the former tenant namespace
+// may get deleted afterwards.
+func (c *Controller) moveToPlannedTenantNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	from, to := tenant.Status.TenantNamespaceName, tenant.Status.PlannedTenantNamespaceName
+	args := map[string]string{"namespace": from, "newNamespace": to}
+
+	err := c.setUpRelocatedTenantNamespace(ctx, config, tenant, from)
+	if err == nil {
+		err = c.removeRelocationRequest(ctx, tenant)
+	}
+	if err != nil {
+		tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+			Type:   stewardv1alpha1.TenantConditionRelocating,
+			Status: corev1.ConditionFalse,
+			Reason: stewardv1alpha1.StatusReasonFailed,
+		}, stewardv1alpha1.MessageCodeTenantRelocationFailed, args)
+		err = errors.WithMessagef(err, "failed to relocate tenant to tenant namespace %q", to)
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+
+	origStatus := tenant.Status.DeepCopy()
+	tenant.Status.TenantNamespaceName = to
+	tenant.Status.FormerTenantNamespaceName = from
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionRelocating,
+		Status: corev1.ConditionTrue,
+		Reason: stewardv1alpha1.StatusReasonTenantRelocating,
+	}, stewardv1alpha1.MessageCodeTenantRelocationPending, args)
+	updatedTenant, err := c.updateStatus(ctx, tenant)
+	if err != nil {
+		tenant.Status = *origStatus
+		return err
+	}
+	tenant.ObjectMeta = updatedTenant.ObjectMeta
+	klog.V(3).Infof(c.formatLogf(tenant, "switched tenant from tenant namespace %q to %q", from, to))
+	return nil
+}
+
+// setUpRelocatedTenantNamespace creates the planned tenant namespace of
+// the given tenant if it does not exist yet, sets it up like a new tenant
+// namespace and copies the secrets, the objects of the kinds listed in
+// tenantObjectKinds and the annotations listed in
+// relocatedNamespaceAnnotations from the given current tenant namespace.
+// It can be called repeatedly, e.g. to resume a failed attempt.
+func (c *Controller) setUpRelocatedTenantNamespace(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant, from string) error {
+	current, err := c.factory.CoreV1().Namespaces().Get(ctx, from, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to get tenant namespace %q", from)
+	}
+	annotations := map[string]string{}
+	for _, key := range relocatedNamespaceAnnotations {
+		if value, ok := current.GetAnnotations()[key]; ok {
+			annotations[key] = value
+		}
+	}
+
+	nsName, err := c.createTenantNamespace(ctx, config, tenant, annotations)
+	if err != nil {
+		return err
+	}
+	if _, err = c.reconcileTenantRoleBinding(ctx, tenant, nsName, config); err != nil {
+		return err
+	}
+	if err = c.reconcileTenantNetworkPolicies(ctx, tenant, nsName); err != nil {
+		return err
+	}
+	if err = c.copyTenantSecrets(ctx, tenant, from, nsName); err != nil {
+		return err
+	}
+	return c.copyTenantObjects(ctx, tenant, from, nsName)
+}
+
+// copyTenantSecrets copies all secrets except service account tokens,
+// which are recreated by Kubernetes, from one tenant namespace to another.
+// Secrets existing in the target namespace already are not overwritten.
+func (c *Controller) copyTenantSecrets(ctx context.Context, tenant *stewardv1alpha1.Tenant, from, to string) error {
+	list, err := c.factory.CoreV1().Secrets(from).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list secrets in tenant namespace %q", from)
+	}
+	count := 0
+	for _, secret := range list.Items {
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		copied := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        secret.GetName(),
+				Namespace:   to,
+				Labels:      secret.GetLabels(),
+				Annotations: secret.GetAnnotations(),
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		_, err := c.factory.CoreV1().Secrets(to).Create(ctx, copied, metav1.CreateOptions{})
+		if err != nil && !k8serrors.IsAlreadyExists(err) {
+			return errors.WithMessagef(err, "failed to copy secret %q to tenant namespace %q", secret.GetName(), to)
+		}
+		count++
+	}
+	klog.V(4).Infof(c.formatLogf(tenant, "copied %d secrets from tenant namespace %q to %q", count, from, to))
+	return nil
+}
+
+// removeRelocationRequest removes annotation
+// stewardv1alpha1.AnnotationRelocateTenantNamespace from the given tenant
+// if it is set, so that the tenant is relocated only once per request.
+func (c *Controller) removeRelocationRequest(ctx context.Context, tenant *stewardv1alpha1.Tenant) error {
+	if !c.isRelocationRequested(tenant) {
+		return nil
+	}
+	annotations := map[string]string{}
+	for key, value := range tenant.GetAnnotations() {
+		if key != stewardv1alpha1.AnnotationRelocateTenantNamespace {
+			annotations[key] = value
+		}
+	}
+	origAnnotations := tenant.GetAnnotations()
+	tenant.SetAnnotations(annotations)
+	updatedTenant, err := c.update(ctx, tenant)
+	if err != nil {
+		tenant.SetAnnotations(origAnnotations)
+		return err
+	}
+	tenant.ObjectMeta = updatedTenant.ObjectMeta
+	return nil
+}
+
+// deleteFormerTenantNamespaceIfIdle deletes the former tenant namespace
+// of the given relocated tenant if it does not contain unfinished
+// pipeline runs and completes the relocation.
+// The API server is queried directly in pages, bypassing the informer
+// cache. The deletion is deferred if it exceeds the namespace deletion
+// rate limit.
+func (c *Controller) deleteFormerTenantNamespaceIfIdle(ctx context.Context, tenant *stewardv1alpha1.Tenant) error {
+	former := tenant.Status.FormerTenantNamespaceName
+	if former == "" {
+		return nil
+	}
+
+	list := func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return c.factory.StewardV1alpha1().PipelineRuns(former).List(ctx, opts)
+	}
+	var unfinished string
+	err := k8s.EachListItem(ctx, list, metav1.ListOptions{}, func(obj runtime.Object) error {
+		run := obj.(*stewardv1alpha1.PipelineRun)
+		if run.Status.State != stewardv1alpha1.StateFinished {
+			unfinished = run.GetName()
+			return errStopListing
+		}
+		return nil
+	})
+	if err != nil && err != errStopListing {
+		err = errors.WithMessagef(err, "failed to list pipeline runs in former tenant namespace %q", former)
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	if unfinished != "" {
+		klog.V(4).Infof(c.formatLogf(tenant, "not deleting former tenant namespace %q yet: pipeline run %q is not finished", former, unfinished))
+		return nil
+	}
+	if c.deferNamespaceDeletion(tenant) {
+		return nil
+	}
+
+	if err := c.deleteFormerTenantNamespace(ctx, tenant); err != nil {
+		return err
+	}
+
+	tenant.Status.FormerTenantNamespaceName = ""
+	tenant.Status.SetConditionWithReasonCode(&knativeapis.Condition{
+		Type:   stewardv1alpha1.TenantConditionRelocating,
+		Status: corev1.ConditionFalse,
+		Reason: stewardv1alpha1.StatusReasonTenantRelocated,
+	}, stewardv1alpha1.MessageCodeTenantRelocated, map[string]string{
+		"namespace":    former,
+		"newNamespace": tenant.Status.TenantNamespaceName,
+	})
+	klog.V(3).Infof(c.formatLogf(tenant, "relocation completed: deleted former tenant namespace %q", former))
+	return nil
+}
+
+// deleteFormerTenantNamespace deletes the former tenant namespace of the
+// given relocated tenant if it exists.
+// The former tenant namespace may have been created with another name
+// prefix than configured for the client now.
+func (c *Controller) deleteFormerTenantNamespace(ctx context.Context, tenant *stewardv1alpha1.Tenant) error {
+	nsName := tenant.Status.FormerTenantNamespaceName
+	if nsName == "" {
+		return nil
+	}
+	namespace, err := c.factory.CoreV1().Namespaces().Get(ctx, nsName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err == nil {
+		prefix := k8s.NamespacePrefix(namespace)
+		if prefix == "" {
+			err = errors.Errorf("refused to delete namespace %q: not a Steward namespace", nsName)
+		} else {
+			err = k8s.NewNamespaceManager(c.factory, prefix, 0).Delete(ctx, nsName)
+		}
+	}
+	if err != nil {
+		err = errors.WithMessagef(err, "failed to delete former tenant namespace %q", nsName)
+		klog.V(3).Infof(c.formatLog(tenant), err)
+		return err
+	}
+	metrics.TenantNamespacesDeleted.Inc()
+	return nil
+}
+
+// deleteRelocationNamespaces deletes the tenant namespaces of an
+// uncompleted relocation of the given tenant, i.e. the new tenant
+// namespace the tenant has not been switched to yet and the former
+// tenant namespace.
+func (c *Controller) deleteRelocationNamespaces(ctx context.Context, config clientConfig, tenant *stewardv1alpha1.Tenant) error {
+	planned := tenant.Status.PlannedTenantNamespaceName
+	if c.isInitialized(tenant) && planned != "" && planned != tenant.Status.TenantNamespaceName {
+		if err := c.deleteTenantNamespace(ctx, planned, tenant, config); err != nil {
+			return err
+		}
+	}
+	return c.deleteFormerTenantNamespace(ctx, tenant)
+}
@@ -0,0 +1,311 @@
+package tenantctl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	spew "github.com/davecgh/go-spew/spew"
+	errors "github.com/pkg/errors"
+	assert "gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+const (
+	relocationClientNSName   = "client1"
+	relocationOldNSPrefix    = "oldprefix1"
+	relocationNewNSPrefix    = "newprefix1"
+	relocationTenantID       = "tenant1"
+	relocationTenantRoleName = "tenantClusterRole1"
+	relocationOldNSName      = "oldprefix1-tenant1-abc123"
+)
+
+func Test_Controller_syncHandler_Relocation(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	oldNamespace := k8sfake.NamespaceWithAnnotations(relocationOldNSName, map[string]string{
+		stewardv1alpha1.AnnotationMaxConcurrentRuns:       "3",
+		stewardv1alpha1.AnnotationDefaultImagePullSecrets: "registry1",
+	})
+	oldNamespace.SetLabels(map[string]string{"prefix": relocationOldNSPrefix})
+	secret := k8sfake.SecretOpaque("secret1", relocationOldNSName)
+	secret.Data = map[string][]byte{"key1": []byte("value1")}
+	cf := newRelocationClientFactory(
+		relocationTenant(stewardv1alpha1.AnnotationRelocateTenantNamespace),
+		oldNamespace,
+		secret,
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token1", Namespace: relocationOldNSName},
+			Type:       corev1.SecretTypeServiceAccountToken,
+		},
+		k8sfake.PipelineSchedule("schedule1", relocationOldNSName, stewardv1alpha1.PipelineScheduleSpec{Schedule: "0 2 * * *"}),
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(relocationClientNSName, relocationTenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(relocationClientNSName).Get(ctx, relocationTenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+	newNSName := tenant.Status.TenantNamespaceName
+	nsNamePattern := fmt.Sprintf(`^\Q%s\E-\Q%s\E-[0-9a-z]+$`, relocationNewNSPrefix, relocationTenantID)
+	assert.Assert(t, is.Regexp(nsNamePattern, newNSName), dump)
+	assert.Equal(t, newNSName, tenant.Status.PlannedTenantNamespaceName, dump)
+	assert.Equal(t, "", tenant.Status.FormerTenantNamespaceName, dump)
+	assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue(), dump)
+	relocatingCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionRelocating)
+	assert.Assert(t, relocatingCond.IsFalse(), dump)
+	assert.Equal(t, stewardv1alpha1.StatusReasonTenantRelocated, relocatingCond.Reason, dump)
+	reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionRelocating)
+	assert.Assert(t, reasonCode != nil, dump)
+	assert.Equal(t, stewardv1alpha1.MessageCodeTenantRelocated, reasonCode.ReasonCode, dump)
+	assert.DeepEqual(t, map[string]string{"namespace": relocationOldNSName, "newNamespace": newNSName}, reasonCode.MessageArgs)
+	_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationRelocateTenantNamespace]
+	assert.Assert(t, !requested, dump)
+
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName, newNSName)
+	newNamespace, err := cf.CoreV1().Namespaces().Get(ctx, newNSName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "3", newNamespace.GetAnnotations()[stewardv1alpha1.AnnotationMaxConcurrentRuns])
+	assert.Equal(t, "registry1", newNamespace.GetAnnotations()[stewardv1alpha1.AnnotationDefaultImagePullSecrets])
+
+	secrets, err := cf.CoreV1().Secrets(newNSName).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(secrets.Items))
+	assert.Equal(t, "secret1", secrets.Items[0].GetName())
+	assert.DeepEqual(t, map[string][]byte{"key1": []byte("value1")}, secrets.Items[0].Data)
+
+	schedule, err := cf.StewardV1alpha1().PipelineSchedules(newNSName).Get(ctx, "schedule1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "0 2 * * *", schedule.Spec.Schedule)
+
+	roleBindings, err := cf.RbacV1().RoleBindings(newNSName).List(ctx, metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(roleBindings.Items))
+}
+
+func Test_Controller_syncHandler_Relocation_WaitsForUnfinishedPipelineRuns(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	oldNamespace := k8sfake.Namespace(relocationOldNSName)
+	oldNamespace.SetLabels(map[string]string{"prefix": relocationOldNSPrefix})
+	run := k8sfake.PipelineRun("run1", relocationOldNSName, stewardv1alpha1.PipelineSpec{})
+	run.Status.State = stewardv1alpha1.StateRunning
+	cf := newRelocationClientFactory(
+		relocationTenant(stewardv1alpha1.AnnotationRelocateTenantNamespace),
+		oldNamespace,
+	)
+	run, err := cf.StewardV1alpha1().PipelineRuns(relocationOldNSName).Create(ctx, run, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	tenantKey := makeTenantKey(relocationClientNSName, relocationTenantID)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(relocationClientNSName).Get(ctx, relocationTenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+	newNSName := tenant.Status.TenantNamespaceName
+	assert.Assert(t, newNSName != relocationOldNSName, dump)
+	assert.Equal(t, relocationOldNSName, tenant.Status.FormerTenantNamespaceName, dump)
+	relocatingCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionRelocating)
+	assert.Assert(t, relocatingCond.IsTrue(), dump)
+	assert.Equal(t, stewardv1alpha1.StatusReasonTenantRelocating, relocatingCond.Reason, dump)
+	reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionRelocating)
+	assert.Equal(t, stewardv1alpha1.MessageCodeTenantRelocationPending, reasonCode.ReasonCode, dump)
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName, relocationOldNSName, newNSName)
+
+	// EXERCISE
+	run.Status.State = stewardv1alpha1.StateFinished
+	_, err = cf.StewardV1alpha1().PipelineRuns(relocationOldNSName).UpdateStatus(ctx, run, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	resultErr = ctl.syncHandler(tenantKey)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err = cf.StewardV1alpha1().Tenants(relocationClientNSName).Get(ctx, relocationTenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump = fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+	assert.Equal(t, newNSName, tenant.Status.TenantNamespaceName, dump)
+	assert.Equal(t, "", tenant.Status.FormerTenantNamespaceName, dump)
+	assert.Assert(t, tenant.Status.GetCondition(stewardv1alpha1.TenantConditionRelocating).IsFalse(), dump)
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName, newNSName)
+}
+
+func Test_Controller_syncHandler_Relocation_Failure(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	oldNamespace := k8sfake.Namespace(relocationOldNSName)
+	oldNamespace.SetLabels(map[string]string{"prefix": relocationOldNSPrefix})
+	cf := newRelocationClientFactory(
+		relocationTenant(stewardv1alpha1.AnnotationRelocateTenantNamespace),
+		oldNamespace,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.testing = &controllerTesting{
+		reconcileTenantRoleBindingStub: func(tenant *stewardv1alpha1.Tenant, namespace string, config clientConfig) (bool, error) {
+			if namespace == relocationOldNSName {
+				return false, nil
+			}
+			return true, errors.New("injected error 1")
+		},
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(relocationClientNSName, relocationTenantID))
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, "injected error 1")
+	tenant, err := cf.StewardV1alpha1().Tenants(relocationClientNSName).Get(ctx, relocationTenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+	newNSName := tenant.Status.PlannedTenantNamespaceName
+	assert.Assert(t, newNSName != relocationOldNSName, dump)
+	assert.Equal(t, relocationOldNSName, tenant.Status.TenantNamespaceName, dump)
+	assert.Equal(t, "", tenant.Status.FormerTenantNamespaceName, dump)
+	relocatingCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionRelocating)
+	assert.Assert(t, relocatingCond.IsFalse(), dump)
+	assert.Equal(t, stewardv1alpha1.StatusReasonFailed, relocatingCond.Reason, dump)
+	reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionRelocating)
+	assert.Equal(t, stewardv1alpha1.MessageCodeTenantRelocationFailed, reasonCode.ReasonCode, dump)
+	// request is kept until the tenant has been switched
+	_, requested := tenant.GetAnnotations()[stewardv1alpha1.AnnotationRelocateTenantNamespace]
+	assert.Assert(t, requested, dump)
+	// new namespace is kept to be reused by the next attempt
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName, relocationOldNSName, newNSName)
+}
+
+func Test_Controller_syncHandler_Relocation_RefusedForAdoptedNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	oldNamespace := k8sfake.Namespace(relocationOldNSName)
+	oldNamespace.SetLabels(map[string]string{"prefix": relocationOldNSPrefix})
+	origTenant := relocationTenant(stewardv1alpha1.AnnotationRelocateTenantNamespace)
+	origTenant.GetAnnotations()[stewardv1alpha1.AnnotationAdoptNamespace] = relocationOldNSName
+	cf := newRelocationClientFactory(origTenant, oldNamespace)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(relocationClientNSName, relocationTenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(relocationClientNSName).Get(ctx, relocationTenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	dump := fmt.Sprintf("\n\n%v", spew.Sdump(tenant))
+
+	assert.Equal(t, relocationOldNSName, tenant.Status.TenantNamespaceName, dump)
+	assert.Equal(t, relocationOldNSName, tenant.Status.PlannedTenantNamespaceName, dump)
+	assert.Assert(t, tenant.Status.GetCondition(knativeapis.ConditionReady).IsTrue(), dump)
+	relocatingCond := tenant.Status.GetCondition(stewardv1alpha1.TenantConditionRelocating)
+	assert.Assert(t, relocatingCond.IsFalse(), dump)
+	reasonCode := tenant.Status.GetConditionReasonCode(stewardv1alpha1.TenantConditionRelocating)
+	assert.Equal(t, stewardv1alpha1.MessageCodeTenantRelocationRefused, reasonCode.ReasonCode, dump)
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName, relocationOldNSName)
+}
+
+func Test_Controller_syncHandler_Relocation_CleanupOnDelete(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const newNSName = "newprefix1-tenant1-def456"
+	oldNamespace := k8sfake.Namespace(relocationOldNSName)
+	oldNamespace.SetLabels(map[string]string{"prefix": relocationOldNSPrefix})
+	newNamespace := k8sfake.Namespace(newNSName)
+	newNamespace.SetLabels(map[string]string{"prefix": relocationNewNSPrefix})
+	origTenant := relocationTenant()
+	origTenant.SetFinalizers([]string{k8s.FinalizerName})
+	origTenant.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	origTenant.Status.TenantNamespaceName = newNSName
+	origTenant.Status.PlannedTenantNamespaceName = newNSName
+	origTenant.Status.FormerTenantNamespaceName = relocationOldNSName
+	cf := newRelocationClientFactory(origTenant, oldNamespace, newNamespace)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(relocationClientNSName, relocationTenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assertThatExactlyTheseNamespacesExist(t, cf, relocationClientNSName)
+}
+
+func Test_Controller_deleteFormerTenantNamespace_RefusesNonStewardNamespace(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	tenant := relocationTenant()
+	tenant.Status.FormerTenantNamespaceName = "foreign1"
+	cf := k8sfake.NewClientFactory(k8sfake.Namespace("foreign1"))
+	ctl := NewController(cf, ControllerOpts{})
+
+	// EXERCISE
+	resultErr := ctl.deleteFormerTenantNamespace(ctx, tenant)
+
+	// VERIFY
+	assert.Error(t, resultErr, `failed to delete former tenant namespace "foreign1": refused to delete namespace "foreign1": not a Steward namespace`)
+	assertThatExactlyTheseNamespacesExist(t, cf, "foreign1")
+}
+
+// relocationTenant returns an initialized tenant with tenant namespace
+// relocationOldNSName and the given annotations set to empty values.
+func relocationTenant(annotationKeys ...string) *stewardv1alpha1.Tenant {
+	tenant := k8sfake.Tenant(relocationTenantID, relocationClientNSName)
+	annotations := map[string]string{}
+	for _, key := range annotationKeys {
+		annotations[key] = ""
+	}
+	tenant.SetAnnotations(annotations)
+	tenant.Status.TenantNamespaceName = relocationOldNSName
+	tenant.Status.PlannedTenantNamespaceName = relocationOldNSName
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+	return tenant
+}
+
+// newRelocationClientFactory returns a fake client factory with a client
+// namespace configured with the new tenant namespace prefix and the given
+// objects.
+func newRelocationClientFactory(objects ...runtime.Object) *k8sfake.ClientFactory {
+	objects = append(objects,
+		k8sfake.NamespaceWithAnnotations(relocationClientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: relocationNewNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            relocationTenantRoleName,
+		}),
+	)
+	return k8sfake.NewClientFactory(objects...)
+}
@@ -0,0 +1,109 @@
+package tenantctl
+
+import (
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	errors "github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	cache "k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+func (c *Controller) isRunRollupEnabled() bool {
+	return c.runRollupWindow > 0
+}
+
+func (c *Controller) onPipelineRunAdd(obj interface{}) {
+	c.enqueueTenantOfPipelineRun(obj, "PipelineRun Add")
+}
+
+func (c *Controller) onPipelineRunUpdate(old, new interface{}) {
+	oldRun, ok1 := old.(*stewardv1alpha1.PipelineRun)
+	newRun, ok2 := new.(*stewardv1alpha1.PipelineRun)
+	// only state changes affect the rollup
+	if ok1 && ok2 && oldRun.Status.State == newRun.Status.State && oldRun.Status.Result == newRun.Status.Result {
+		return
+	}
+	c.enqueueTenantOfPipelineRun(new, "PipelineRun Update")
+}
+
+func (c *Controller) onPipelineRunDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	c.enqueueTenantOfPipelineRun(obj, "PipelineRun Delete")
+}
+
+// enqueueTenantOfPipelineRun adds the tenant owning the tenant namespace
+// of the given pipeline run to the work queue.
+func (c *Controller) enqueueTenantOfPipelineRun(obj interface{}, eventType string) {
+	run, ok := obj.(*stewardv1alpha1.PipelineRun)
+	if !ok {
+		return
+	}
+	tenants, err := c.tenantsByNamespace(run.GetNamespace())
+	if err != nil {
+		klog.Errorf("'%s' event - cannot get tenant of namespace %q: %s", eventType, run.GetNamespace(), err.Error())
+		return
+	}
+	for _, tenant := range tenants {
+		c.addToQueue(c.getKey(tenant), eventType)
+	}
+}
+
+// updateRunRollup sets the run rollup in the status of the given tenant
+// from the pipeline runs in its tenant namespace.
+func (c *Controller) updateRunRollup(tenant *stewardv1alpha1.Tenant) error {
+	nsName := tenant.Status.TenantNamespaceName
+	runs, err := c.pipelineRunLister.PipelineRuns(nsName).List(labels.Everything())
+	if err != nil {
+		return errors.WithMessagef(err, "failed to list pipeline runs in tenant namespace %q", nsName)
+	}
+	tenant.Status.RunRollup = computeRunRollup(runs, c.runRollupWindow, c.now(), tenant.Status.RunRollup)
+	return nil
+}
+
+// computeRunRollup returns the run rollup for the given pipeline runs.
+// Finished pipeline runs are counted if they finished within the given
+// window before now. The last run time of the previous rollup is retained
+// if there is no newer pipeline run.
+func computeRunRollup(runs []*stewardv1alpha1.PipelineRun, window time.Duration, now time.Time, previous *stewardv1alpha1.TenantRunRollup) *stewardv1alpha1.TenantRunRollup {
+	rollup := &stewardv1alpha1.TenantRunRollup{
+		Window: metav1.Duration{Duration: window},
+	}
+	if previous != nil && previous.LastRunTime != nil {
+		rollup.LastRunTime = previous.LastRunTime.DeepCopy()
+	}
+
+	windowStart := now.Add(-window)
+	for _, run := range runs {
+		created := run.GetCreationTimestamp()
+		if rollup.LastRunTime == nil || rollup.LastRunTime.Before(&created) {
+			rollup.LastRunTime = created.DeepCopy()
+		}
+
+		switch run.Status.State {
+		case stewardv1alpha1.StateUndefined, stewardv1alpha1.StateNew, stewardv1alpha1.StateQueued:
+			// not started yet
+		case stewardv1alpha1.StateFinished:
+			finishedAt := run.Status.FinishedAt
+			if finishedAt == nil || finishedAt.Time.Before(windowStart) {
+				continue
+			}
+			rollup.FinishedRuns++
+			if run.Status.Result == stewardv1alpha1.ResultSuccess {
+				rollup.SuccessfulRuns++
+			}
+		default:
+			rollup.RunningRuns++
+		}
+	}
+
+	if rollup.FinishedRuns > 0 {
+		rate := rollup.SuccessfulRuns * 100 / rollup.FinishedRuns
+		rollup.SuccessRatePercent = &rate
+	}
+	return rollup
+}
@@ -0,0 +1,265 @@
+package tenantctl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardv1alpha1listers "github.com/SAP/stewardci-core/pkg/client/listers/steward/v1alpha1"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	k8sfake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+func Test_computeRunRollup(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(-d))
+		return &t
+	}
+	int32Ptr := func(i int32) *int32 { return &i }
+	run := func(name string, createdAgo time.Duration, state stewardv1alpha1.State, result stewardv1alpha1.Result, finishedAt *metav1.Time) *stewardv1alpha1.PipelineRun {
+		r := k8sfake.PipelineRun(name, "ns1", stewardv1alpha1.PipelineSpec{})
+		r.SetCreationTimestamp(*ago(createdAgo))
+		r.Status.State = state
+		r.Status.Result = result
+		r.Status.FinishedAt = finishedAt
+		return r
+	}
+	window := 24 * time.Hour
+
+	for _, tc := range []struct {
+		name     string
+		runs     []*stewardv1alpha1.PipelineRun
+		previous *stewardv1alpha1.TenantRunRollup
+		expected *stewardv1alpha1.TenantRunRollup
+	}{
+		{
+			name: "no runs",
+			expected: &stewardv1alpha1.TenantRunRollup{
+				Window: metav1.Duration{Duration: window},
+			},
+		},
+		{
+			name: "no runs, retains last run time",
+			previous: &stewardv1alpha1.TenantRunRollup{
+				LastRunTime:  ago(48 * time.Hour),
+				FinishedRuns: 1,
+			},
+			expected: &stewardv1alpha1.TenantRunRollup{
+				LastRunTime: ago(48 * time.Hour),
+				Window:      metav1.Duration{Duration: window},
+			},
+		},
+		{
+			name: "mixed runs",
+			runs: []*stewardv1alpha1.PipelineRun{
+				run("new", 1*time.Minute, stewardv1alpha1.StateNew, stewardv1alpha1.ResultUndefined, nil),
+				run("queued", 10*time.Minute, stewardv1alpha1.StateQueued, stewardv1alpha1.ResultUndefined, nil),
+				run("running", 1*time.Hour, stewardv1alpha1.StateRunning, stewardv1alpha1.ResultUndefined, nil),
+				run("waiting", 2*time.Hour, stewardv1alpha1.StateWaiting, stewardv1alpha1.ResultUndefined, nil),
+				run("success", 3*time.Hour, stewardv1alpha1.StateFinished, stewardv1alpha1.ResultSuccess, ago(2*time.Hour)),
+				run("failed", 4*time.Hour, stewardv1alpha1.StateFinished, stewardv1alpha1.ResultErrorContent, ago(3*time.Hour)),
+				run("aborted", 5*time.Hour, stewardv1alpha1.StateFinished, stewardv1alpha1.ResultAborted, ago(4*time.Hour)),
+				run("outside window", 30*time.Hour, stewardv1alpha1.StateFinished, stewardv1alpha1.ResultSuccess, ago(25*time.Hour)),
+			},
+			previous: &stewardv1alpha1.TenantRunRollup{
+				LastRunTime: ago(48 * time.Hour),
+			},
+			expected: &stewardv1alpha1.TenantRunRollup{
+				LastRunTime:        ago(1 * time.Minute),
+				RunningRuns:        2,
+				Window:             metav1.Duration{Duration: window},
+				FinishedRuns:       3,
+				SuccessfulRuns:     1,
+				SuccessRatePercent: int32Ptr(33),
+			},
+		},
+		{
+			name: "only runs outside window",
+			runs: []*stewardv1alpha1.PipelineRun{
+				run("run1", 30*time.Hour, stewardv1alpha1.StateFinished, stewardv1alpha1.ResultSuccess, ago(25*time.Hour)),
+			},
+			expected: &stewardv1alpha1.TenantRunRollup{
+				LastRunTime: ago(30 * time.Hour),
+				Window:      metav1.Duration{Duration: window},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := computeRunRollup(tc.runs, window, now, tc.previous)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Controller_enqueueTenantOfPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	tenant1 := k8sfake.Tenant("tenant1", "client1")
+	tenant1.Status.TenantNamespaceName = "tn1"
+	tenant2 := k8sfake.Tenant("tenant2", "client1")
+	tenant2.Status.TenantNamespaceName = "tn2"
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		k8s.TenantNamespaceIndex: k8s.TenantNamespaceIndexFunc,
+	})
+	assert.NilError(t, indexer.Add(tenant1))
+	assert.NilError(t, indexer.Add(tenant2))
+
+	ctl := NewController(k8sfake.NewClientFactory(), ControllerOpts{})
+	ctl.tenantsByNamespace = func(nsName string) ([]*stewardv1alpha1.Tenant, error) {
+		objs, err := indexer.ByIndex(k8s.TenantNamespaceIndex, nsName)
+		if err != nil {
+			return nil, err
+		}
+		var tenants []*stewardv1alpha1.Tenant
+		for _, obj := range objs {
+			tenants = append(tenants, obj.(*stewardv1alpha1.Tenant))
+		}
+		return tenants, nil
+	}
+
+	// EXERCISE
+	ctl.onPipelineRunAdd(k8sfake.PipelineRun("run1", "tn2", stewardv1alpha1.PipelineSpec{}))
+	ctl.onPipelineRunAdd(k8sfake.PipelineRun("run2", "other", stewardv1alpha1.PipelineSpec{}))
+
+	// VERIFY
+	assert.Equal(t, 1, ctl.workqueue.Len())
+	key, _ := ctl.workqueue.Get()
+	assert.Equal(t, "client1/tenant2", key)
+}
+
+func Test_Controller_onPipelineRunUpdate_IgnoresUnchangedState(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctl := NewController(k8sfake.NewClientFactory(), ControllerOpts{})
+	ctl.tenantsByNamespace = func(nsName string) ([]*stewardv1alpha1.Tenant, error) {
+		return []*stewardv1alpha1.Tenant{k8sfake.Tenant("tenant1", "client1")}, nil
+	}
+	oldRun := k8sfake.PipelineRun("run1", "tn1", stewardv1alpha1.PipelineSpec{})
+	oldRun.Status.State = stewardv1alpha1.StateRunning
+	newRun := oldRun.DeepCopy()
+	newRun.Status.Message = "foo"
+
+	// EXERCISE
+	ctl.onPipelineRunUpdate(oldRun, newRun)
+
+	// VERIFY
+	assert.Equal(t, 0, ctl.workqueue.Len())
+}
+
+func Test_Controller_syncHandler_InitializedTenant_UpdatesRunRollup(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		tenantNSName   = "prefix1-tenant1-abc123"
+	)
+
+	ctx := context.Background()
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	finishedAt := metav1.NewTime(now.Add(-1 * time.Hour))
+
+	origTenant := k8sfake.Tenant(tenantID, clientNSName)
+	origTenant.Status.TenantNamespaceName = tenantNSName
+	tenantNamespace := k8sfake.Namespace(tenantNSName)
+	tenantNamespace.SetLabels(map[string]string{"prefix": tenantNSPrefix})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		origTenant,
+		tenantNamespace,
+	)
+
+	run1 := finishedRun("run1", tenantNSName, &finishedAt)
+	run1.Status.Result = stewardv1alpha1.ResultSuccess
+	run2 := k8sfake.PipelineRun("run2", tenantNSName, stewardv1alpha1.PipelineSpec{})
+	run2.Status.State = stewardv1alpha1.StateRunning
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	assert.NilError(t, indexer.Add(run1))
+	assert.NilError(t, indexer.Add(run2))
+
+	ctl := NewController(cf, ControllerOpts{RunRollupWindow: 24 * time.Hour})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+	ctl.pipelineRunLister = stewardv1alpha1listers.NewPipelineRunLister(indexer)
+	ctl.testing = &controllerTesting{
+		nowStub: func() time.Time { return now },
+	}
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	rollup := tenant.Status.RunRollup
+	assert.Assert(t, rollup != nil)
+	assert.Equal(t, int32(1), rollup.RunningRuns)
+	assert.Equal(t, int32(1), rollup.FinishedRuns)
+	assert.Equal(t, int32(1), rollup.SuccessfulRuns)
+	assert.Assert(t, rollup.SuccessRatePercent != nil)
+	assert.Equal(t, int32(100), *rollup.SuccessRatePercent)
+}
+
+func Test_Controller_syncHandler_RunRollupDisabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	const (
+		clientNSName   = "client1"
+		tenantNSPrefix = "prefix1"
+		tenantID       = "tenant1"
+		tenantRoleName = "tenantClusterRole1"
+		tenantNSName   = "prefix1-tenant1-abc123"
+	)
+
+	ctx := context.Background()
+	origTenant := k8sfake.Tenant(tenantID, clientNSName)
+	origTenant.Status.TenantNamespaceName = tenantNSName
+	tenantNamespace := k8sfake.Namespace(tenantNSName)
+	tenantNamespace.SetLabels(map[string]string{"prefix": tenantNSPrefix})
+	cf := k8sfake.NewClientFactory(
+		// the client namespace
+		k8sfake.NamespaceWithAnnotations(clientNSName, map[string]string{
+			stewardv1alpha1.AnnotationTenantNamespacePrefix: tenantNSPrefix,
+			stewardv1alpha1.AnnotationTenantRole:            tenantRoleName,
+		}),
+		origTenant,
+		tenantNamespace,
+	)
+	ctl := NewController(cf, ControllerOpts{})
+	ctl.fetcher = k8s.NewClientBasedTenantFetcher(cf)
+
+	// EXERCISE
+	resultErr := ctl.syncHandler(makeTenantKey(clientNSName, tenantID))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	tenant, err := cf.StewardV1alpha1().Tenants(clientNSName).Get(ctx, tenantID, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, tenant.Status.RunRollup == nil)
+	assert.Assert(t, !ctl.isRunRollupEnabled())
+}
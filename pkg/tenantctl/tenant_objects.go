@@ -0,0 +1,177 @@
+package tenantctl
+
+import (
+	"context"
+	"encoding/json"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	errors "github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// tenantObject is a Steward object of a tenant namespace other than a
+// pipeline run, e.g. a trigger, in a form that can be saved in a
+// hibernation snapshot and recreated in another tenant namespace.
+// Status and system-generated metadata are not retained.
+type tenantObject struct {
+	Name        string            `json:"-"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Spec        json.RawMessage   `json:"spec"`
+}
+
+// tenantObjectKind provides access to the Steward objects of one kind in
+// tenant namespaces.
+type tenantObjectKind struct {
+	// name is the kind of the objects, e.g. `Trigger`.
+	name string
+
+	list   func(ctx context.Context, c *Controller, nsName string) ([]tenantObject, error)
+	create func(ctx context.Context, c *Controller, nsName string, obj tenantObject) error
+}
+
+// tenantObjectKinds are the kinds of Steward objects of tenant namespaces
+// which are saved on hibernation and copied on relocation, in addition
+// to secrets. Pipeline runs are not retained.
+// Templates come first, as triggers refer to them.
+var tenantObjectKinds = []tenantObjectKind{
+	{
+		name: "PipelineRunTemplate",
+		list: func(ctx context.Context, c *Controller, nsName string) ([]tenantObject, error) {
+			list, err := c.factory.StewardV1alpha1().PipelineRunTemplates(nsName).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			result := make([]tenantObject, 0, len(list.Items))
+			for i := range list.Items {
+				obj, err := newTenantObject(&list.Items[i].ObjectMeta, list.Items[i].Spec)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, obj)
+			}
+			return result, nil
+		},
+		create: func(ctx context.Context, c *Controller, nsName string, obj tenantObject) error {
+			template := &stewardv1alpha1.PipelineRunTemplate{ObjectMeta: obj.objectMeta(nsName)}
+			if err := json.Unmarshal(obj.Spec, &template.Spec); err != nil {
+				return err
+			}
+			_, err := c.factory.StewardV1alpha1().PipelineRunTemplates(nsName).Create(ctx, template, metav1.CreateOptions{})
+			return err
+		},
+	},
+	{
+		name: "PipelineSchedule",
+		list: func(ctx context.Context, c *Controller, nsName string) ([]tenantObject, error) {
+			list, err := c.factory.StewardV1alpha1().PipelineSchedules(nsName).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			result := make([]tenantObject, 0, len(list.Items))
+			for i := range list.Items {
+				obj, err := newTenantObject(&list.Items[i].ObjectMeta, list.Items[i].Spec)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, obj)
+			}
+			return result, nil
+		},
+		create: func(ctx context.Context, c *Controller, nsName string, obj tenantObject) error {
+			schedule := &stewardv1alpha1.PipelineSchedule{ObjectMeta: obj.objectMeta(nsName)}
+			if err := json.Unmarshal(obj.Spec, &schedule.Spec); err != nil {
+				return err
+			}
+			_, err := c.factory.StewardV1alpha1().PipelineSchedules(nsName).Create(ctx, schedule, metav1.CreateOptions{})
+			return err
+		},
+	},
+	{
+		name: "Trigger",
+		list: func(ctx context.Context, c *Controller, nsName string) ([]tenantObject, error) {
+			list, err := c.factory.StewardV1alpha1().Triggers(nsName).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			result := make([]tenantObject, 0, len(list.Items))
+			for i := range list.Items {
+				obj, err := newTenantObject(&list.Items[i].ObjectMeta, list.Items[i].Spec)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, obj)
+			}
+			return result, nil
+		},
+		create: func(ctx context.Context, c *Controller, nsName string, obj tenantObject) error {
+			trigger := &stewardv1alpha1.Trigger{ObjectMeta: obj.objectMeta(nsName)}
+			if err := json.Unmarshal(obj.Spec, &trigger.Spec); err != nil {
+				return err
+			}
+			_, err := c.factory.StewardV1alpha1().Triggers(nsName).Create(ctx, trigger, metav1.CreateOptions{})
+			return err
+		},
+	},
+}
+
+// findTenantObjectKind returns the tenant object kind with the given
+// name or nil if there is none.
+func findTenantObjectKind(name string) *tenantObjectKind {
+	for i := range tenantObjectKinds {
+		if tenantObjectKinds[i].name == name {
+			return &tenantObjectKinds[i]
+		}
+	}
+	return nil
+}
+
+// newTenantObject returns the tenant object with the given metadata and
+// spec.
+func newTenantObject(meta *metav1.ObjectMeta, spec interface{}) (tenantObject, error) {
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return tenantObject{}, err
+	}
+	return tenantObject{
+		Name:        meta.GetName(),
+		Labels:      meta.GetLabels(),
+		Annotations: meta.GetAnnotations(),
+		Spec:        specJSON,
+	}, nil
+}
+
+// objectMeta returns the metadata of the object to be created in the
+// given namespace.
+func (o tenantObject) objectMeta(nsName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        o.Name,
+		Namespace:   nsName,
+		Labels:      o.Labels,
+		Annotations: o.Annotations,
+	}
+}
+
+// copyTenantObjects copies all objects of the kinds listed in
+// tenantObjectKinds from one tenant namespace to another.
+// Objects existing in the target namespace already are not overwritten.
+func (c *Controller) copyTenantObjects(ctx context.Context, tenant *stewardv1alpha1.Tenant, from, to string) error {
+	for _, kind := range tenantObjectKinds {
+		objects, err := kind.list(ctx, c, from)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to list objects of kind %s in tenant namespace %q", kind.name, from)
+		}
+		for _, obj := range objects {
+			err := kind.create(ctx, c, to, obj)
+			if err != nil && !k8serrors.IsAlreadyExists(err) {
+				return errors.WithMessagef(err, "failed to copy %s %q to tenant namespace %q", kind.name, obj.Name, to)
+			}
+		}
+		if len(objects) > 0 {
+			klog.V(4).Infof(c.formatLogf(tenant, "copied %d objects of kind %s from tenant namespace %q to %q", len(objects), kind.name, from, to))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package tenantimport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Format is the format of a list of tenants to be imported.
+type Format string
+
+const (
+	// FormatCSV is a CSV list of tenants with a header line. Columns
+	// `namespace` and `name` are required, column `maxConcurrentRuns` is
+	// optional. Other columns are ignored.
+	FormatCSV Format = "csv"
+
+	// FormatJSON is a JSON array of Entry objects.
+	FormatJSON Format = "json"
+)
+
+const (
+	csvColumnNamespace         = "namespace"
+	csvColumnName              = "name"
+	csvColumnMaxConcurrentRuns = "maxConcurrentRuns"
+)
+
+// Entry is a tenant to be imported.
+type Entry struct {
+	// Namespace is the client namespace to create the tenant in.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the tenant.
+	Name string `json:"name"`
+
+	// MaxConcurrentRuns is the value of `spec.maxConcurrentRuns` of the
+	// tenant.
+	MaxConcurrentRuns int32 `json:"maxConcurrentRuns,omitempty"`
+}
+
+// Key returns the key of the tenant in the form `namespace/name`.
+func (e Entry) Key() string {
+	return e.Namespace + "/" + e.Name
+}
+
+// Tenant returns the tenant object to be created for this entry.
+func (e Entry) Tenant() *api.Tenant {
+	return &api.Tenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: e.Namespace,
+			Name:      e.Name,
+		},
+		Spec: api.TenantSpec{
+			MaxConcurrentRuns: e.MaxConcurrentRuns,
+		},
+	}
+}
+
+// validate returns an error if the entry cannot be imported.
+func (e Entry) validate() error {
+	if msgs := validation.IsDNS1123Label(e.Namespace); len(msgs) > 0 {
+		return errors.Errorf("invalid namespace %q: %s", e.Namespace, strings.Join(msgs, "; "))
+	}
+	if msgs := validation.IsDNS1123Subdomain(e.Name); len(msgs) > 0 {
+		return errors.Errorf("invalid name %q: %s", e.Name, strings.Join(msgs, "; "))
+	}
+	if e.MaxConcurrentRuns < 0 {
+		return errors.Errorf("invalid maxConcurrentRuns %d: must not be negative", e.MaxConcurrentRuns)
+	}
+	return nil
+}
+
+// FormatFromFileName returns the format of a file with the given name
+// derived from its extension.
+func FormatFromFileName(fileName string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", errors.Errorf("cannot determine format of file %q: extension must be '.csv' or '.json'", fileName)
+	}
+}
+
+// ParseEntries reads a list of tenants in the given format.
+// All entries are validated and an error is returned if any entry is
+// invalid or occurs more than once, so that an import is not started
+// with partly broken input.
+func ParseEntries(r io.Reader, format Format) ([]Entry, error) {
+	var entries []Entry
+	var err error
+	switch format {
+	case FormatCSV:
+		entries, err = parseCSV(r)
+	case FormatJSON:
+		entries, err = parseJSON(r)
+	default:
+		return nil, errors.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if err := entry.validate(); err != nil {
+			return nil, errors.Wrapf(err, "entry %d", i+1)
+		}
+		if seen[entry.Key()] {
+			return nil, errors.Errorf("entry %d: duplicate tenant %q", i+1, entry.Key())
+		}
+		seen[entry.Key()] = true
+	}
+	return entries, nil
+}
+
+func parseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSV header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(column)] = i
+	}
+	for _, required := range []string{csvColumnNamespace, csvColumnName} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.Errorf("CSV header lacks column %q", required)
+		}
+	}
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []Entry
+	for i := 1; ; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CSV")
+		}
+		entry := Entry{
+			Namespace: field(record, csvColumnNamespace),
+			Name:      field(record, csvColumnName),
+		}
+		if value := field(record, csvColumnMaxConcurrentRuns); value != "" {
+			maxConcurrentRuns, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "entry %d: invalid value of column %q", i, csvColumnMaxConcurrentRuns)
+			}
+			entry.MaxConcurrentRuns = int32(maxConcurrentRuns)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+func parseJSON(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, errors.Wrap(err, "failed to parse JSON")
+	}
+	return entries, nil
+}
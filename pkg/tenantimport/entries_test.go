@@ -0,0 +1,108 @@
+package tenantimport
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_ParseEntries_CSV(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	input := "name,namespace,maxConcurrentRuns,comment\n" +
+		"tenant1, client1, 5, foo\n" +
+		"tenant2,client1,,bar\n" +
+		"tenant1,client2\n"
+
+	// EXERCISE
+	entries, err := ParseEntries(strings.NewReader(input), FormatCSV)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []Entry{
+		{Namespace: "client1", Name: "tenant1", MaxConcurrentRuns: 5},
+		{Namespace: "client1", Name: "tenant2"},
+		{Namespace: "client2", Name: "tenant1"},
+	}, entries)
+}
+
+func Test_ParseEntries_JSON(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	input := `[
+		{"namespace": "client1", "name": "tenant1", "maxConcurrentRuns": 5},
+		{"namespace": "client1", "name": "tenant2"}
+	]`
+
+	// EXERCISE
+	entries, err := ParseEntries(strings.NewReader(input), FormatJSON)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []Entry{
+		{Namespace: "client1", Name: "tenant1", MaxConcurrentRuns: 5},
+		{Namespace: "client1", Name: "tenant2"},
+	}, entries)
+}
+
+func Test_ParseEntries_Empty(t *testing.T) {
+	t.Parallel()
+
+	entries, err := ParseEntries(strings.NewReader(""), FormatCSV)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(entries))
+
+	entries, err = ParseEntries(strings.NewReader("[]"), FormatJSON)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(entries))
+}
+
+func Test_ParseEntries_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		input         string
+		format        Format
+		expectedError string
+	}{
+		{"missing column", "name\ntenant1\n", FormatCSV, `CSV header lacks column "namespace"`},
+		{"invalid number", "namespace,name,maxConcurrentRuns\nclient1,tenant1,many\n", FormatCSV, `entry 1: invalid value of column "maxConcurrentRuns"`},
+		{"negative number", "namespace,name,maxConcurrentRuns\nclient1,tenant1,-1\n", FormatCSV, "entry 1: invalid maxConcurrentRuns -1"},
+		{"empty name", "namespace,name\nclient1,tenant1\nclient1,\n", FormatCSV, `entry 2: invalid name ""`},
+		{"invalid namespace", "namespace,name\nClient1,tenant1\n", FormatCSV, `entry 1: invalid namespace "Client1"`},
+		{"duplicate", "namespace,name\nclient1,tenant1\nclient1,tenant1\n", FormatCSV, `entry 2: duplicate tenant "client1/tenant1"`},
+		{"malformed JSON", `[{"namespace": "client1"`, FormatJSON, "failed to parse JSON"},
+		{"unknown JSON field", `[{"namespace": "client1", "name": "tenant1", "foo": 1}]`, FormatJSON, "failed to parse JSON"},
+		{"unsupported format", "", Format("xml"), `unsupported format "xml"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			_, err := ParseEntries(strings.NewReader(tc.input), tc.format)
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_FormatFromFileName(t *testing.T) {
+	t.Parallel()
+
+	format, err := FormatFromFileName("dir/tenants.CSV")
+	assert.NilError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = FormatFromFileName("tenants.json")
+	assert.NilError(t, err)
+	assert.Equal(t, FormatJSON, format)
+
+	_, err = FormatFromFileName("tenants.txt")
+	assert.ErrorContains(t, err, "cannot determine format")
+}
@@ -0,0 +1,225 @@
+package tenantimport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+const (
+	// DefaultConcurrency is the default number of tenants imported
+	// concurrently.
+	DefaultConcurrency = 10
+
+	// DefaultReadyTimeout is the default maximum time to wait for an
+	// imported tenant to become ready.
+	DefaultReadyTimeout = 5 * time.Minute
+
+	// DefaultPollInterval is the default interval to check whether an
+	// imported tenant is ready.
+	DefaultPollInterval = 2 * time.Second
+)
+
+// Outcome is the outcome of the import of a single tenant.
+type Outcome string
+
+const (
+	// OutcomeCreated means that the tenant has been created and became
+	// ready.
+	OutcomeCreated Outcome = "created"
+
+	// OutcomeExisting means that the tenant existed already and is
+	// ready.
+	OutcomeExisting Outcome = "existing"
+
+	// OutcomeFailed means that the tenant could not be created or did
+	// not become ready in time.
+	OutcomeFailed Outcome = "failed"
+)
+
+// Result is the result of the import of a single tenant.
+type Result struct {
+	Entry   Entry
+	Outcome Outcome
+	// Err is the cause if Outcome is OutcomeFailed.
+	Err error
+	// Duration is the time from the start of the import of the tenant
+	// until it was ready or failed.
+	Duration time.Duration
+}
+
+// ProgressFunc is called once for each tenant whose import has
+// completed. `done` is the number of tenants completed so far including
+// this one, `total` the number of all tenants to be imported.
+// Calls are serialized.
+type ProgressFunc func(result Result, done, total int)
+
+// Opts are the options of an Importer.
+type Opts struct {
+	// Concurrency is the maximum number of tenants imported
+	// concurrently. If zero or negative, DefaultConcurrency is used.
+	Concurrency int
+
+	// ReadyTimeout is the maximum time to wait for a tenant to become
+	// ready. If zero or negative, DefaultReadyTimeout is used.
+	ReadyTimeout time.Duration
+
+	// PollInterval is the interval to check whether a tenant is ready.
+	// If zero or negative, DefaultPollInterval is used.
+	PollInterval time.Duration
+
+	// Progress is called for each completed tenant if not nil.
+	Progress ProgressFunc
+}
+
+// Importer creates tenants in bulk and verifies that they become ready.
+type Importer struct {
+	factory k8s.ClientFactory
+	opts    Opts
+}
+
+// NewImporter creates a new Importer.
+func NewImporter(factory k8s.ClientFactory, opts Opts) *Importer {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.ReadyTimeout <= 0 {
+		opts.ReadyTimeout = DefaultReadyTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	return &Importer{factory: factory, opts: opts}
+}
+
+// Import creates the tenants of the given entries and waits for each of
+// them to become ready. Existing tenants are not modified but verified
+// to be ready, too, so that an interrupted import can simply be
+// repeated.
+// The returned results are in the order of the given entries.
+// Cancelling the context stops the import; tenants not imported yet
+// fail with the error of the context.
+func (i *Importer) Import(ctx context.Context, entries []Entry) []Result {
+	results := make([]Result, len(entries))
+	var progressMutex sync.Mutex
+	done := 0
+	reportProgress := func(result Result) {
+		if i.opts.Progress == nil {
+			return
+		}
+		progressMutex.Lock()
+		defer progressMutex.Unlock()
+		done++
+		i.opts.Progress(result, done, len(entries))
+	}
+
+	semaphore := make(chan struct{}, i.opts.Concurrency)
+	var wg sync.WaitGroup
+	for index, entry := range entries {
+		wg.Add(1)
+		go func(index int, entry Entry) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+				results[index] = i.importTenant(ctx, entry)
+			case <-ctx.Done():
+				results[index] = Result{Entry: entry, Outcome: OutcomeFailed, Err: ctx.Err()}
+			}
+			reportProgress(results[index])
+		}(index, entry)
+	}
+	wg.Wait()
+	return results
+}
+
+func (i *Importer) importTenant(ctx context.Context, entry Entry) Result {
+	start := time.Now()
+	result := Result{Entry: entry, Outcome: OutcomeCreated}
+	fail := func(err error) Result {
+		result.Outcome = OutcomeFailed
+		result.Err = err
+		result.Duration = time.Since(start)
+		klog.V(3).Infof("import of tenant %q failed: %s", entry.Key(), err.Error())
+		return result
+	}
+
+	if ctx.Err() != nil {
+		return fail(ctx.Err())
+	}
+	tenantIfce := i.factory.StewardV1alpha1().Tenants(entry.Namespace)
+	_, err := tenantIfce.Create(ctx, entry.Tenant(), metav1.CreateOptions{})
+	if err != nil {
+		if !k8serrors.IsAlreadyExists(err) {
+			return fail(errors.Wrapf(err, "failed to create tenant %q", entry.Key()))
+		}
+		result.Outcome = OutcomeExisting
+	}
+
+	if err := i.waitForReady(ctx, entry); err != nil {
+		return fail(err)
+	}
+	result.Duration = time.Since(start)
+	klog.V(3).Infof("imported tenant %q (%s) in %v", entry.Key(), result.Outcome, result.Duration)
+	return result
+}
+
+// waitForReady waits until the tenant of the given entry has condition
+// `Ready` with status `True`. A tenant that is not ready (yet) is not
+// considered failed before the timeout, as the tenant controller retries
+// failed reconciliations.
+func (i *Importer) waitForReady(ctx context.Context, entry Entry) error {
+	ctx, cancel := context.WithTimeout(ctx, i.opts.ReadyTimeout)
+	defer cancel()
+
+	tenantIfce := i.factory.StewardV1alpha1().Tenants(entry.Namespace)
+	var lastMessage string
+	err := wait.PollImmediateUntil(i.opts.PollInterval, func() (bool, error) {
+		tenant, err := tenantIfce.Get(ctx, entry.Name, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, errors.Errorf("tenant %q has been deleted", entry.Key())
+			}
+			lastMessage = err.Error()
+			return false, nil
+		}
+		condition := tenant.Status.GetCondition(knativeapis.ConditionReady)
+		if condition == nil {
+			lastMessage = "no ready condition"
+			return false, nil
+		}
+		if condition.Status == corev1.ConditionTrue {
+			return true, nil
+		}
+		lastMessage = condition.Message
+		return false, nil
+	}, ctx.Done())
+	if err == wait.ErrWaitTimeout && ctx.Err() == context.Canceled {
+		return ctx.Err()
+	}
+	if err == wait.ErrWaitTimeout {
+		if lastMessage != "" {
+			return errors.Errorf("tenant %q did not become ready within %v: %s", entry.Key(), i.opts.ReadyTimeout, lastMessage)
+		}
+		return errors.Errorf("tenant %q did not become ready within %v", entry.Key(), i.opts.ReadyTimeout)
+	}
+	return err
+}
+
+// Summarize returns the number of results per outcome.
+func Summarize(results []Result) map[Outcome]int {
+	summary := map[Outcome]int{}
+	for _, result := range results {
+		summary[result.Outcome]++
+	}
+	return summary
+}
@@ -0,0 +1,112 @@
+package tenantimport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	knativeapis "knative.dev/pkg/apis"
+)
+
+func readyTenant(namespace, name string) *api.Tenant {
+	tenant := Entry{Namespace: namespace, Name: name}.Tenant()
+	tenant.TypeMeta = fake.Tenant(name, namespace).TypeMeta
+	setReady(tenant)
+	return tenant
+}
+
+func setReady(tenant *api.Tenant) {
+	tenant.Status.SetCondition(&knativeapis.Condition{
+		Type:   knativeapis.ConditionReady,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// newFakeClientFactory returns a fake client factory where created
+// tenants are ready immediately unless their name is `unready`.
+func newFakeClientFactory(objects ...runtime.Object) *fake.ClientFactory {
+	cf := fake.NewClientFactory(objects...)
+	cf.StewardClientset().PrependReactor("create", "tenants", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		tenant := action.(k8stesting.CreateAction).GetObject().(*api.Tenant)
+		if tenant.GetName() != "unready" {
+			setReady(tenant)
+		}
+		return false, nil, nil
+	})
+	return cf
+}
+
+func Test_Importer_Import(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := newFakeClientFactory(readyTenant("client1", "existing"))
+	var progress []int
+	var mutex sync.Mutex
+	examinee := NewImporter(cf, Opts{
+		Concurrency:  2,
+		ReadyTimeout: 200 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+		Progress: func(result Result, done, total int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			assert.Equal(t, 4, total)
+			progress = append(progress, done)
+		},
+	})
+	entries := []Entry{
+		{Namespace: "client1", Name: "tenant1", MaxConcurrentRuns: 3},
+		{Namespace: "client1", Name: "existing"},
+		{Namespace: "client2", Name: "tenant1"},
+		{Namespace: "client2", Name: "unready"},
+	}
+
+	// EXERCISE
+	results := examinee.Import(ctx, entries)
+
+	// VERIFY
+	assert.Equal(t, 4, len(results))
+	for i, result := range results {
+		assert.DeepEqual(t, entries[i], result.Entry)
+	}
+	assert.Equal(t, OutcomeCreated, results[0].Outcome)
+	assert.Equal(t, OutcomeExisting, results[1].Outcome)
+	assert.Equal(t, OutcomeCreated, results[2].Outcome)
+	assert.Equal(t, OutcomeFailed, results[3].Outcome)
+	assert.ErrorContains(t, results[3].Err, `tenant "client2/unready" did not become ready within 200ms`)
+	assert.DeepEqual(t, []int{1, 2, 3, 4}, progress)
+	assert.DeepEqual(t, map[Outcome]int{OutcomeCreated: 2, OutcomeExisting: 1, OutcomeFailed: 1}, Summarize(results))
+
+	tenant, err := cf.StewardV1alpha1().Tenants("client1").Get(ctx, "tenant1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, int32(3), tenant.Spec.MaxConcurrentRuns)
+}
+
+func Test_Importer_Import_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cf := newFakeClientFactory()
+	examinee := NewImporter(cf, Opts{})
+
+	// EXERCISE
+	results := examinee.Import(ctx, []Entry{{Namespace: "client1", Name: "tenant1"}})
+
+	// VERIFY
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, OutcomeFailed, results[0].Outcome)
+	assert.Equal(t, context.Canceled, results[0].Err)
+	_, err := cf.StewardV1alpha1().Tenants("client1").Get(context.Background(), "tenant1", metav1.GetOptions{})
+	assert.Assert(t, err != nil)
+}
@@ -0,0 +1,79 @@
+package throttle
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// InstrumentClientConfig modifies the given Kubernetes client
+// configuration so that pressure signals get recorded at this throttle.
+// It replaces the client-side rate limiter by a token bucket rate limiter
+// with the QPS and burst values of the configuration, and wraps the
+// transport to observe HTTP responses.
+// Must be called after QPS and burst have been set.
+func (t *Throttle) InstrumentClientConfig(config *rest.Config) {
+	if t == nil {
+		return
+	}
+	config.RateLimiter = &observingRateLimiter{
+		RateLimiter: flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst),
+		throttle:    t,
+	}
+	config.WrapTransport = transport.Wrappers(config.WrapTransport, func(rt http.RoundTripper) http.RoundTripper {
+		return &observingRoundTripper{
+			delegate: rt,
+			throttle: t,
+		}
+	})
+}
+
+// observingRateLimiter is a client-side rate limiter that records a
+// pressure signal if a request had to wait too long.
+type observingRateLimiter struct {
+	flowcontrol.RateLimiter
+	throttle *Throttle
+}
+
+func (r *observingRateLimiter) Accept() {
+	start := r.throttle.clock.Now()
+	r.RateLimiter.Accept()
+	r.observe(start)
+}
+
+func (r *observingRateLimiter) Wait(ctx context.Context) error {
+	start := r.throttle.clock.Now()
+	err := r.RateLimiter.Wait(ctx)
+	r.observe(start)
+	return err
+}
+
+func (r *observingRateLimiter) observe(start time.Time) {
+	if r.throttle.clock.Since(start) >= r.throttle.opts.RateLimiterWaitThreshold {
+		r.throttle.Signal(SignalSourceRateLimiter)
+	}
+}
+
+// observingRoundTripper is an HTTP round tripper that records a pressure
+// signal for each response with status 429 (Too Many Requests).
+type observingRoundTripper struct {
+	delegate http.RoundTripper
+	throttle *Throttle
+}
+
+func (rt *observingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		rt.throttle.Signal(SignalSourceTooManyRequests)
+	}
+	return resp, err
+}
+
+// WrappedRoundTripper implements k8s.io/apimachinery/pkg/util/net.RoundTripperWrapper.
+func (rt *observingRoundTripper) WrappedRoundTripper() http.RoundTripper {
+	return rt.delegate
+}
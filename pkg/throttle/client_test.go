@@ -0,0 +1,107 @@
+package throttle
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/client-go/rest"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func Test_Throttle_InstrumentClientConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, _ := newThrottleForTest()
+	config := &rest.Config{QPS: 7, Burst: 11}
+
+	// EXERCISE
+	examinee.InstrumentClientConfig(config)
+
+	// VERIFY
+	assert.Assert(t, config.RateLimiter != nil)
+	assert.Equal(t, float32(7), config.RateLimiter.QPS())
+	assert.Assert(t, config.WrapTransport != nil)
+	_, ok := config.WrapTransport(http.DefaultTransport).(*observingRoundTripper)
+	assert.Assert(t, ok)
+}
+
+func Test_Throttle_InstrumentClientConfig_Nil(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var examinee *Throttle
+	config := &rest.Config{QPS: 7, Burst: 11}
+
+	// EXERCISE
+	examinee.InstrumentClientConfig(config)
+
+	// VERIFY
+	assert.Assert(t, config.RateLimiter == nil)
+	assert.Assert(t, config.WrapTransport == nil)
+}
+
+func Test_observingRateLimiter_observe(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		wait            time.Duration
+		expectThrottled bool
+	}{
+		{0, false},
+		{999 * time.Millisecond, false},
+		{1 * time.Second, true},
+		{5 * time.Second, true},
+	} {
+		// SETUP
+		throttle, mockClock := newThrottleForTest()
+		examinee := &observingRateLimiter{throttle: throttle}
+		start := mockClock.Now()
+		mockClock.Add(tc.wait)
+
+		// EXERCISE
+		examinee.observe(start)
+
+		// VERIFY
+		assert.Equal(t, tc.expectThrottled, throttle.IsThrottled(), "tc: %+v", tc)
+	}
+}
+
+func Test_observingRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		statusCode      int
+		expectThrottled bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusTooManyRequests, true},
+	} {
+		// SETUP
+		throttle, _ := newThrottleForTest()
+		examinee := &observingRoundTripper{
+			delegate: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: tc.statusCode}, nil
+			}),
+			throttle: throttle,
+		}
+		req, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+		assert.NilError(t, err)
+
+		// EXERCISE
+		resp, err := examinee.RoundTrip(req)
+
+		// VERIFY
+		assert.NilError(t, err)
+		assert.Equal(t, tc.statusCode, resp.StatusCode)
+		assert.Equal(t, tc.expectThrottled, throttle.IsThrottled(), "tc: %+v", tc)
+	}
+}
@@ -0,0 +1,90 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/time/rate"
+)
+
+// DeletionLimiter limits the rate at which clean-up routines delete
+// objects, e.g. finished pipeline runs after their time to live, so
+// that a large backlog of expired objects does not overwhelm the API
+// server, e.g. when such a clean-up is enabled for the first time.
+//
+// Deletions are identified by the workqueue key of the object causing
+// them. A deletion that is not allowed immediately gets a time slot
+// reserved, and the caller should requeue the key for that time. When
+// the key is processed again at or after its time slot, the deletion is
+// allowed without consuming another token. This keeps deferred
+// deletions in order and avoids busy retries of large backlogs.
+//
+// A nil *DeletionLimiter never limits deletions.
+type DeletionLimiter struct {
+	clock   clock.Clock
+	limiter *rate.Limiter
+
+	mutex sync.Mutex
+	slots map[string]time.Time
+}
+
+// NewDeletionLimiter creates a new DeletionLimiter allowing the given
+// number of deletions per second on average and at most the given burst
+// of deletions at once.
+// Returns nil if the rate is zero or negative, i.e. deletions are not
+// limited.
+func NewDeletionLimiter(deletionsPerSecond float64, burst int) *DeletionLimiter {
+	return newDeletionLimiter(deletionsPerSecond, burst, clock.New())
+}
+
+func newDeletionLimiter(deletionsPerSecond float64, burst int, clock clock.Clock) *DeletionLimiter {
+	if deletionsPerSecond <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &DeletionLimiter{
+		clock:   clock,
+		limiter: rate.NewLimiter(rate.Limit(deletionsPerSecond), burst),
+		slots:   map[string]time.Time{},
+	}
+}
+
+// Delay returns the time to wait before the deletion for the given key
+// may be performed. Returns zero if the deletion may be performed
+// immediately, in which case it is accounted for.
+func (l *DeletionLimiter) Delay(key string) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.clock.Now()
+	if slot, found := l.slots[key]; found {
+		if delay := slot.Sub(now); delay > 0 {
+			return delay
+		}
+		delete(l.slots, key)
+		return 0
+	}
+	delay := l.limiter.ReserveN(now, 1).DelayFrom(now)
+	if delay > 0 {
+		l.slots[key] = now.Add(delay)
+	}
+	return delay
+}
+
+// Forget removes the reserved time slot of the given key, e.g. because
+// the object has been deleted otherwise.
+// The token consumed for the time slot is not returned.
+func (l *DeletionLimiter) Forget(key string) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.slots, key)
+}
@@ -0,0 +1,72 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"gotest.tools/assert"
+)
+
+func Test_NewDeletionLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	for _, deletionsPerSecond := range []float64{0, -1} {
+		// EXERCISE
+		examinee := NewDeletionLimiter(deletionsPerSecond, 10)
+
+		// VERIFY
+		assert.Assert(t, examinee == nil)
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run1"))
+		}
+		examinee.Forget("ns1/run1")
+	}
+}
+
+func Test_DeletionLimiter_Delay(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockClock := clock.NewMock()
+	examinee := newDeletionLimiter(2, 2, mockClock)
+
+	// EXERCISE + VERIFY
+
+	// burst
+	assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run1"))
+	assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run2"))
+
+	// time slots are reserved in order
+	assert.Equal(t, 500*time.Millisecond, examinee.Delay("ns1/run3"))
+	assert.Equal(t, 1*time.Second, examinee.Delay("ns1/run4"))
+
+	// the reserved time slot is kept if processed again too early
+	mockClock.Add(200 * time.Millisecond)
+	assert.Equal(t, 300*time.Millisecond, examinee.Delay("ns1/run3"))
+
+	// time slot reached
+	mockClock.Add(300 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run3"))
+	assert.Equal(t, 500*time.Millisecond, examinee.Delay("ns1/run4"))
+
+	// new keys queue up behind reserved time slots
+	assert.Equal(t, 1*time.Second, examinee.Delay("ns1/run5"))
+}
+
+func Test_DeletionLimiter_Forget(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockClock := clock.NewMock()
+	examinee := newDeletionLimiter(1, 1, mockClock)
+	assert.Equal(t, time.Duration(0), examinee.Delay("ns1/run1"))
+	assert.Equal(t, 1*time.Second, examinee.Delay("ns1/run2"))
+
+	// EXERCISE
+	examinee.Forget("ns1/run2")
+
+	// VERIFY
+	assert.Equal(t, 0, len(examinee.slots))
+	assert.Equal(t, 2*time.Second, examinee.Delay("ns1/run2"))
+}
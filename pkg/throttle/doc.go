@@ -0,0 +1,12 @@
+/*
+Package throttle implements the adaptive self-throttling of controllers
+under Kubernetes API server pressure.
+
+API server pressure is detected by observing the Kubernetes client:
+long waits in the client-side rate limiter and HTTP responses with status
+429 (Too Many Requests) are pressure signals. While pressure signals
+occur, controllers reduce the number of concurrently processed work items
+and increase the delays of requeued items. If there have been no pressure
+signals for a while, controllers recover to normal operation.
+*/
+package throttle
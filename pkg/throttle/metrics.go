@@ -0,0 +1,49 @@
+package throttle
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	subsystem = metrics.Subsystem + "_throttle"
+)
+
+var metricsInstance = &throttleMetrics{}
+
+func init() {
+	metricsInstance.init()
+}
+
+type throttleMetrics struct {
+	initOnlyOnce sync.Once
+	active       prometheus.Gauge
+	signals      *prometheus.CounterVec
+}
+
+func (m *throttleMetrics) init() {
+	m.initOnlyOnce.Do(func() {
+		m.active = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "active",
+				Help:      "Whether the controller is currently throttled due to Kubernetes API server pressure (1) or not (0).",
+			},
+		)
+		metrics.Registerer().MustRegister(m.active)
+
+		m.signals = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "pressure_signals_total",
+				Help:      "The total number of detected Kubernetes API server pressure signals partitioned by source.",
+			},
+			[]string{
+				"source",
+			},
+		)
+		metrics.Registerer().MustRegister(m.signals)
+	})
+}
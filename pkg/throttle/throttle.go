@@ -0,0 +1,136 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// SignalSourceRateLimiter identifies pressure signals caused by long
+	// waits in the client-side rate limiter.
+	SignalSourceRateLimiter = "ratelimiter"
+
+	// SignalSourceTooManyRequests identifies pressure signals caused by
+	// HTTP responses with status 429 (Too Many Requests).
+	SignalSourceTooManyRequests = "toomanyrequests"
+)
+
+// Opts stores options for the construction of a Throttle instance.
+type Opts struct {
+	// RateLimiterWaitThreshold is the minimum time a request must wait in
+	// the client-side rate limiter to be considered a pressure signal.
+	RateLimiterWaitThreshold time.Duration
+
+	// RecoveryDelay is the time without pressure signals after which
+	// throttling ends.
+	RecoveryDelay time.Duration
+
+	// ConcurrencyDivisor is the divisor applied to the number of workers
+	// while throttled. The resulting number of workers is at least one.
+	ConcurrencyDivisor int
+
+	// RequeueDelayFactor is the factor applied to the delays of requeued
+	// work items while throttled.
+	RequeueDelayFactor int
+}
+
+// DefaultOpts returns the default options.
+func DefaultOpts() Opts {
+	return Opts{
+		RateLimiterWaitThreshold: 1 * time.Second,
+		RecoveryDelay:            2 * time.Minute,
+		ConcurrencyDivisor:       4,
+		RequeueDelayFactor:       4,
+	}
+}
+
+// Throttle detects Kubernetes API server pressure and decides whether
+// a controller is throttled.
+// A nil *Throttle is valid and never throttles.
+type Throttle struct {
+	clock clock.Clock
+	opts  Opts
+
+	mutex          sync.Mutex
+	lastSignalTime time.Time
+	throttled      bool
+}
+
+// New creates a new Throttle instance.
+func New(opts Opts) *Throttle {
+	return newThrottle(opts, clock.New())
+}
+
+func newThrottle(opts Opts, clock clock.Clock) *Throttle {
+	if opts.ConcurrencyDivisor < 1 {
+		opts.ConcurrencyDivisor = 1
+	}
+	if opts.RequeueDelayFactor < 1 {
+		opts.RequeueDelayFactor = 1
+	}
+	return &Throttle{
+		clock: clock,
+		opts:  opts,
+	}
+}
+
+// Signal records a pressure signal from the given source and starts
+// throttling if not throttled already.
+func (t *Throttle) Signal(source string) {
+	if t == nil {
+		return
+	}
+	metricsInstance.signals.WithLabelValues(source).Inc()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.lastSignalTime = t.clock.Now()
+	if !t.throttled {
+		t.throttled = true
+		metricsInstance.active.Set(1)
+		klog.Warningf("Kubernetes API server pressure detected (%s): throttling controller", source)
+	}
+}
+
+// IsThrottled returns whether the controller should be throttled
+// currently. Throttling ends if there was no pressure signal for the
+// configured recovery delay.
+func (t *Throttle) IsThrottled() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.throttled && t.clock.Since(t.lastSignalTime) >= t.opts.RecoveryDelay {
+		t.throttled = false
+		metricsInstance.active.Set(0)
+		klog.Infof("No Kubernetes API server pressure detected for %s: stop throttling controller", t.opts.RecoveryDelay)
+	}
+	return t.throttled
+}
+
+// concurrency returns the number of workers allowed to process work
+// items concurrently given the configured number of workers.
+func (t *Throttle) concurrency(workers int) int {
+	if !t.IsThrottled() {
+		return workers
+	}
+	limit := workers / t.opts.ConcurrencyDivisor
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+// requeueDelay returns the delay of a requeued work item given the
+// delay without throttling.
+func (t *Throttle) requeueDelay(delay time.Duration) time.Duration {
+	if !t.IsThrottled() {
+		return delay
+	}
+	return delay * time.Duration(t.opts.RequeueDelayFactor)
+}
@@ -0,0 +1,110 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"gotest.tools/assert"
+)
+
+func newThrottleForTest() (*Throttle, *clock.Mock) {
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Unix(962366400, 0)) // 2000-06-30 12:00:00Z
+	opts := Opts{
+		RateLimiterWaitThreshold: 1 * time.Second,
+		RecoveryDelay:            1 * time.Minute,
+		ConcurrencyDivisor:       4,
+		RequeueDelayFactor:       3,
+	}
+	return newThrottle(opts, mockClock), mockClock
+}
+
+func Test_Throttle_Nil(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var examinee *Throttle
+
+	// EXERCISE
+	examinee.Signal(SignalSourceTooManyRequests)
+
+	// VERIFY
+	assert.Assert(t, !examinee.IsThrottled())
+	assert.Equal(t, 10, examinee.concurrency(10))
+	assert.Equal(t, 5*time.Second, examinee.requeueDelay(5*time.Second))
+}
+
+func Test_Throttle_SignalAndRecovery(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, mockClock := newThrottleForTest()
+	assert.Assert(t, !examinee.IsThrottled())
+
+	// EXERCISE and VERIFY
+	examinee.Signal(SignalSourceRateLimiter)
+	assert.Assert(t, examinee.IsThrottled())
+
+	mockClock.Add(50 * time.Second)
+	assert.Assert(t, examinee.IsThrottled())
+
+	// another signal extends throttling
+	examinee.Signal(SignalSourceTooManyRequests)
+	mockClock.Add(50 * time.Second)
+	assert.Assert(t, examinee.IsThrottled())
+
+	mockClock.Add(10 * time.Second)
+	assert.Assert(t, !examinee.IsThrottled())
+}
+
+func Test_Throttle_concurrency(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		workers       int
+		throttled     bool
+		expectedLimit int
+	}{
+		{10, false, 10},
+		{10, true, 2},
+		{4, true, 1},
+		{2, true, 1},
+		{1, true, 1},
+	} {
+		// SETUP
+		examinee, _ := newThrottleForTest()
+		if tc.throttled {
+			examinee.Signal(SignalSourceRateLimiter)
+		}
+
+		// EXERCISE
+		result := examinee.concurrency(tc.workers)
+
+		// VERIFY
+		assert.Equal(t, tc.expectedLimit, result, "tc: %+v", tc)
+	}
+}
+
+func Test_Throttle_requeueDelay(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, _ := newThrottleForTest()
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, 5*time.Second, examinee.requeueDelay(5*time.Second))
+	examinee.Signal(SignalSourceRateLimiter)
+	assert.Equal(t, 15*time.Second, examinee.requeueDelay(5*time.Second))
+}
+
+func Test_newThrottle_NormalizesOpts(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	examinee := newThrottle(Opts{}, clock.NewMock())
+
+	// VERIFY
+	assert.Equal(t, 1, examinee.opts.ConcurrencyDivisor)
+	assert.Equal(t, 1, examinee.opts.RequeueDelayFactor)
+}
@@ -0,0 +1,71 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// workerPollInterval is the interval in which waiting workers check
+// whether they may continue.
+const workerPollInterval = 100 * time.Millisecond
+
+// WorkerLimiter limits the number of workers processing work items
+// concurrently. While throttled, fewer workers than configured may
+// proceed.
+type WorkerLimiter struct {
+	throttle *Throttle
+	workers  func() int
+
+	mutex  sync.Mutex
+	active int
+}
+
+// NewWorkerLimiter creates a WorkerLimiter for the given number of
+// workers.
+func (t *Throttle) NewWorkerLimiter(workers int) *WorkerLimiter {
+	return t.NewDynamicWorkerLimiter(func() int { return workers })
+}
+
+// NewDynamicWorkerLimiter creates a WorkerLimiter for a number of workers
+// that may change over time. The given function is called to get the
+// current number of workers.
+func (t *Throttle) NewDynamicWorkerLimiter(workers func() int) *WorkerLimiter {
+	return &WorkerLimiter{
+		throttle: t,
+		workers:  workers,
+	}
+}
+
+// Acquire blocks until the calling worker may process the next work item
+// and returns true, or returns false if the stop channel got closed.
+// Each successful call must be followed by a call to Release once the
+// work item has been processed.
+func (l *WorkerLimiter) Acquire(stopCh <-chan struct{}) bool {
+	for !l.tryAcquire() {
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(workerPollInterval):
+		}
+	}
+	return true
+}
+
+func (l *WorkerLimiter) tryAcquire() bool {
+	limit := l.throttle.concurrency(l.workers())
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.active >= limit {
+		return false
+	}
+	l.active++
+	return true
+}
+
+// Release signals that the calling worker has finished processing a work
+// item.
+func (l *WorkerLimiter) Release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.active--
+}
@@ -0,0 +1,91 @@
+package throttle
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_WorkerLimiter_NotThrottled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	throttle, _ := newThrottleForTest()
+	examinee := throttle.NewWorkerLimiter(4)
+
+	// EXERCISE and VERIFY
+	for i := 0; i < 4; i++ {
+		assert.Assert(t, examinee.tryAcquire())
+	}
+	assert.Assert(t, !examinee.tryAcquire())
+	examinee.Release()
+	assert.Assert(t, examinee.tryAcquire())
+}
+
+func Test_WorkerLimiter_Throttled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	throttle, _ := newThrottleForTest()
+	examinee := throttle.NewWorkerLimiter(8)
+	for i := 0; i < 3; i++ {
+		assert.Assert(t, examinee.tryAcquire())
+	}
+
+	// EXERCISE
+	throttle.Signal(SignalSourceTooManyRequests)
+
+	// VERIFY
+	// already active workers are not interrupted, but no new ones may proceed
+	// until the number of active workers is below the throttled limit of 2
+	assert.Assert(t, !examinee.tryAcquire())
+	examinee.Release()
+	assert.Assert(t, !examinee.tryAcquire())
+	examinee.Release()
+	assert.Assert(t, examinee.tryAcquire())
+}
+
+func Test_WorkerLimiter_Dynamic(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	throttle, _ := newThrottleForTest()
+	workers := 1
+	examinee := throttle.NewDynamicWorkerLimiter(func() int { return workers })
+
+	// EXERCISE and VERIFY
+	assert.Assert(t, examinee.tryAcquire())
+	assert.Assert(t, !examinee.tryAcquire())
+	workers = 2
+	assert.Assert(t, examinee.tryAcquire())
+	assert.Assert(t, !examinee.tryAcquire())
+}
+
+func Test_WorkerLimiter_NilThrottle(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var throttle *Throttle
+	examinee := throttle.NewWorkerLimiter(1)
+
+	// EXERCISE and VERIFY
+	assert.Assert(t, examinee.tryAcquire())
+	assert.Assert(t, !examinee.tryAcquire())
+}
+
+func Test_WorkerLimiter_Acquire_Stopped(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var throttle *Throttle
+	examinee := throttle.NewWorkerLimiter(1)
+	assert.Assert(t, examinee.Acquire(nil))
+	stopCh := make(chan struct{})
+	close(stopCh)
+
+	// EXERCISE
+	result := examinee.Acquire(stopCh)
+
+	// VERIFY
+	assert.Assert(t, !result)
+}
@@ -0,0 +1,29 @@
+package throttle
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// WorkqueueRateLimiter returns a workqueue rate limiter that increases
+// the delays determined by the given rate limiter while throttled.
+// If the throttle is nil, the given rate limiter is returned.
+func (t *Throttle) WorkqueueRateLimiter(delegate workqueue.RateLimiter) workqueue.RateLimiter {
+	if t == nil {
+		return delegate
+	}
+	return &workqueueRateLimiter{
+		RateLimiter: delegate,
+		throttle:    t,
+	}
+}
+
+type workqueueRateLimiter struct {
+	workqueue.RateLimiter
+	throttle *Throttle
+}
+
+func (r *workqueueRateLimiter) When(item interface{}) time.Duration {
+	return r.throttle.requeueDelay(r.RateLimiter.When(item))
+}
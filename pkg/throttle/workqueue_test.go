@@ -0,0 +1,37 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func Test_Throttle_WorkqueueRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	throttle, _ := newThrottleForTest()
+	examinee := throttle.WorkqueueRateLimiter(workqueue.NewItemExponentialFailureRateLimiter(1*time.Second, 1*time.Hour))
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, 1*time.Second, examinee.When("item1"))
+	throttle.Signal(SignalSourceRateLimiter)
+	assert.Equal(t, 6*time.Second, examinee.When("item1"))
+	assert.Equal(t, 2, examinee.NumRequeues("item1"))
+}
+
+func Test_Throttle_WorkqueueRateLimiter_Nil(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var throttle *Throttle
+	delegate := workqueue.NewItemExponentialFailureRateLimiter(1*time.Second, 1*time.Hour)
+
+	// EXERCISE
+	result := throttle.WorkqueueRateLimiter(delegate)
+
+	// VERIFY
+	assert.Equal(t, delegate, result)
+}
@@ -0,0 +1,46 @@
+package trigger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	errors "github.com/pkg/errors"
+)
+
+const signaturePrefix = "sha256="
+
+// authenticate checks that a webhook request has been sent by the Git
+// hosting service knowing the given webhook secret. GitHub requests must
+// be signed with the secret, GitLab requests must contain the secret as
+// token.
+func authenticate(header http.Header, body []byte, secret []byte) error {
+	if len(secret) == 0 {
+		return errors.New("webhook secret is empty")
+	}
+	if signature := header.Get("X-Hub-Signature-256"); signature != "" {
+		if !strings.HasPrefix(signature, signaturePrefix) {
+			return errors.New("invalid signature format")
+		}
+		actual, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+		if err != nil {
+			return errors.New("invalid signature format")
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		if !hmac.Equal(actual, mac.Sum(nil)) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	}
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		if subtle.ConstantTimeCompare([]byte(token), secret) != 1 {
+			return errors.New("token mismatch")
+		}
+		return nil
+	}
+	return errors.New("request is neither signed nor contains a token")
+}
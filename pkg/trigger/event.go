@@ -0,0 +1,309 @@
+package trigger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	errors "github.com/pkg/errors"
+)
+
+const (
+	// Arguments of created pipeline runs describing the event.
+	argEvent         = "GIT_EVENT"
+	argRepository    = "GIT_REPOSITORY"
+	argRepositoryURL = "GIT_REPOSITORY_URL"
+	argRef           = "GIT_REF"
+	argBranch        = "GIT_BRANCH"
+	argCommit        = "GIT_COMMIT"
+	argPullRequest   = "GIT_PULL_REQUEST"
+	argTargetBranch  = "GIT_TARGET_BRANCH"
+
+	providerGitHub = "github"
+	providerGitLab = "gitlab"
+
+	branchRefPrefix = "refs/heads/"
+	zeroCommit      = "0000000000000000000000000000000000000000"
+)
+
+// Event is a Git webhook event relevant for triggers.
+type Event struct {
+	// Type is the type of the event.
+	Type api.TriggerEventType
+
+	// Provider is the Git hosting service sending the event, i.e.
+	// `github` or `gitlab`.
+	Provider string
+
+	// Repository is the name of the repository including its owner.
+	Repository string
+
+	// RepositoryURL is the clone URL of the repository.
+	RepositoryURL string
+
+	// Ref is the Git ref of the commit, e.g. `refs/heads/main` or
+	// `refs/pull/42/head`.
+	Ref string
+
+	// Branch is the branch of the commit. Empty for tags.
+	Branch string
+
+	// Commit is the SHA of the commit to build.
+	Commit string
+
+	// PullRequest is the number of the pull request. Zero for push
+	// events.
+	PullRequest int
+
+	// TargetBranch is the branch the pull request is to be merged into.
+	// Empty for push events.
+	TargetBranch string
+
+	// URL is the URL of the commit or pull request in the web UI of the
+	// Git hosting service, if available.
+	URL string
+}
+
+// Matches returns whether the event triggers pipeline runs of the given
+// trigger.
+func (e *Event) Matches(spec *api.TriggerSpec) bool {
+	if len(spec.Events) > 0 && !containsEventType(spec.Events, e.Type) {
+		return false
+	}
+	if spec.Repository != "" && !strings.EqualFold(spec.Repository, e.Repository) {
+		return false
+	}
+	if len(spec.Branches) > 0 {
+		branch := e.Branch
+		if e.Type == api.TriggerEventPullRequest {
+			branch = e.TargetBranch
+		}
+		if branch == "" || !matchesAny(spec.Branches, branch) {
+			return false
+		}
+	}
+	return true
+}
+
+// Args returns the arguments of pipeline runs describing the event.
+func (e *Event) Args() map[string]string {
+	args := map[string]string{
+		argEvent:         string(e.Type),
+		argRepository:    e.Repository,
+		argRepositoryURL: e.RepositoryURL,
+		argRef:           e.Ref,
+		argBranch:        e.Branch,
+		argCommit:        e.Commit,
+	}
+	if e.Type == api.TriggerEventPullRequest {
+		args[argPullRequest] = strconv.Itoa(e.PullRequest)
+		args[argTargetBranch] = e.TargetBranch
+	}
+	return args
+}
+
+// TriggeredBy returns the identification of the event as triggering
+// entity of pipeline runs.
+func (e *Event) TriggeredBy() *api.TriggeredBy {
+	if e.Type == api.TriggerEventPullRequest {
+		kind := "pull-request"
+		if e.Provider == providerGitLab {
+			kind = "merge-request"
+		}
+		return &api.TriggeredBy{
+			Type: e.Provider + "-" + kind,
+			ID:   fmt.Sprintf("%s#%d", e.Repository, e.PullRequest),
+			URL:  e.URL,
+		}
+	}
+	return &api.TriggeredBy{
+		Type: e.Provider + "-push",
+		ID:   fmt.Sprintf("%s@%s", e.Repository, e.Commit),
+		URL:  e.URL,
+	}
+}
+
+// ParseEvent parses a webhook request sent by GitHub or GitLab.
+// It returns nil without error for events not triggering pipeline runs,
+// e.g. ping events, closed pull requests and deleted branches.
+func ParseEvent(header http.Header, body []byte) (*Event, error) {
+	if eventType := header.Get("X-GitHub-Event"); eventType != "" {
+		return parseGitHubEvent(eventType, body)
+	}
+	if eventType := header.Get("X-Gitlab-Event"); eventType != "" {
+		return parseGitLabEvent(eventType, body)
+	}
+	return nil, errors.New("unknown webhook sender: neither header X-GitHub-Event nor X-Gitlab-Event is set")
+}
+
+type gitHubRepository struct {
+	FullName string `json:"full_name"`
+	CloneURL string `json:"clone_url"`
+}
+
+func parseGitHubEvent(eventType string, body []byte) (*Event, error) {
+	switch eventType {
+	case "push":
+		payload := struct {
+			Ref        string           `json:"ref"`
+			After      string           `json:"after"`
+			Deleted    bool             `json:"deleted"`
+			Compare    string           `json:"compare"`
+			Repository gitHubRepository `json:"repository"`
+		}{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.Wrap(err, "invalid GitHub push event")
+		}
+		if payload.Deleted || payload.After == zeroCommit {
+			return nil, nil
+		}
+		return &Event{
+			Type:          api.TriggerEventPush,
+			Provider:      providerGitHub,
+			Repository:    payload.Repository.FullName,
+			RepositoryURL: payload.Repository.CloneURL,
+			Ref:           payload.Ref,
+			Branch:        branchOfRef(payload.Ref),
+			Commit:        payload.After,
+			URL:           payload.Compare,
+		}, nil
+	case "pull_request":
+		payload := struct {
+			Action      string `json:"action"`
+			Number      int    `json:"number"`
+			PullRequest struct {
+				HTMLURL string `json:"html_url"`
+				Head    struct {
+					Ref string `json:"ref"`
+					SHA string `json:"sha"`
+				} `json:"head"`
+				Base struct {
+					Ref string `json:"ref"`
+				} `json:"base"`
+			} `json:"pull_request"`
+			Repository gitHubRepository `json:"repository"`
+		}{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.Wrap(err, "invalid GitHub pull_request event")
+		}
+		switch payload.Action {
+		case "opened", "reopened", "synchronize":
+		default:
+			return nil, nil
+		}
+		return &Event{
+			Type:          api.TriggerEventPullRequest,
+			Provider:      providerGitHub,
+			Repository:    payload.Repository.FullName,
+			RepositoryURL: payload.Repository.CloneURL,
+			Ref:           fmt.Sprintf("refs/pull/%d/head", payload.Number),
+			Branch:        payload.PullRequest.Head.Ref,
+			Commit:        payload.PullRequest.Head.SHA,
+			PullRequest:   payload.Number,
+			TargetBranch:  payload.PullRequest.Base.Ref,
+			URL:           payload.PullRequest.HTMLURL,
+		}, nil
+	}
+	return nil, nil
+}
+
+type gitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	GitHTTPURL        string `json:"git_http_url"`
+	WebURL            string `json:"web_url"`
+}
+
+func parseGitLabEvent(eventType string, body []byte) (*Event, error) {
+	switch eventType {
+	case "Push Hook":
+		payload := struct {
+			Ref     string        `json:"ref"`
+			After   string        `json:"after"`
+			Project gitLabProject `json:"project"`
+		}{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.Wrap(err, "invalid GitLab push event")
+		}
+		if payload.After == zeroCommit {
+			return nil, nil
+		}
+		event := &Event{
+			Type:          api.TriggerEventPush,
+			Provider:      providerGitLab,
+			Repository:    payload.Project.PathWithNamespace,
+			RepositoryURL: payload.Project.GitHTTPURL,
+			Ref:           payload.Ref,
+			Branch:        branchOfRef(payload.Ref),
+			Commit:        payload.After,
+		}
+		if payload.Project.WebURL != "" {
+			event.URL = payload.Project.WebURL + "/-/commit/" + payload.After
+		}
+		return event, nil
+	case "Merge Request Hook":
+		payload := struct {
+			Project          gitLabProject `json:"project"`
+			ObjectAttributes struct {
+				IID          int    `json:"iid"`
+				Action       string `json:"action"`
+				SourceBranch string `json:"source_branch"`
+				TargetBranch string `json:"target_branch"`
+				URL          string `json:"url"`
+				LastCommit   struct {
+					ID string `json:"id"`
+				} `json:"last_commit"`
+			} `json:"object_attributes"`
+		}{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, errors.Wrap(err, "invalid GitLab merge request event")
+		}
+		attributes := payload.ObjectAttributes
+		switch attributes.Action {
+		case "open", "reopen", "update":
+		default:
+			return nil, nil
+		}
+		return &Event{
+			Type:          api.TriggerEventPullRequest,
+			Provider:      providerGitLab,
+			Repository:    payload.Project.PathWithNamespace,
+			RepositoryURL: payload.Project.GitHTTPURL,
+			Ref:           fmt.Sprintf("refs/merge-requests/%d/head", attributes.IID),
+			Branch:        attributes.SourceBranch,
+			Commit:        attributes.LastCommit.ID,
+			PullRequest:   attributes.IID,
+			TargetBranch:  attributes.TargetBranch,
+			URL:           attributes.URL,
+		}, nil
+	}
+	return nil, nil
+}
+
+func branchOfRef(ref string) string {
+	if strings.HasPrefix(ref, branchRefPrefix) {
+		return strings.TrimPrefix(ref, branchRefPrefix)
+	}
+	return ""
+}
+
+func containsEventType(list []api.TriggerEventType, eventType api.TriggerEventType) bool {
+	for _, entry := range list {
+		if entry == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
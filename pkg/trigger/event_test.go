@@ -0,0 +1,189 @@
+package trigger
+
+import (
+	"net/http"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	assert "gotest.tools/assert"
+)
+
+const (
+	gitHubPushPayload = `{
+  "ref": "refs/heads/main",
+  "after": "1111111111111111111111111111111111111111",
+  "compare": "https://github.com/org1/repo1/compare/abc...111",
+  "repository": {"full_name": "org1/repo1", "clone_url": "https://github.com/org1/repo1.git"}
+}`
+	gitHubPullRequestPayload = `{
+  "action": "synchronize",
+  "number": 42,
+  "pull_request": {
+    "html_url": "https://github.com/org1/repo1/pull/42",
+    "head": {"ref": "feature1", "sha": "2222222222222222222222222222222222222222"},
+    "base": {"ref": "main"}
+  },
+  "repository": {"full_name": "org1/repo1", "clone_url": "https://github.com/org1/repo1.git"}
+}`
+	gitLabMergeRequestPayload = `{
+  "project": {
+    "path_with_namespace": "group1/repo1",
+    "git_http_url": "https://gitlab.example.com/group1/repo1.git",
+    "web_url": "https://gitlab.example.com/group1/repo1"
+  },
+  "object_attributes": {
+    "iid": 7,
+    "action": "open",
+    "source_branch": "feature1",
+    "target_branch": "main",
+    "url": "https://gitlab.example.com/group1/repo1/-/merge_requests/7",
+    "last_commit": {"id": "3333333333333333333333333333333333333333"}
+  }
+}`
+)
+
+func header(key, value string) http.Header {
+	h := http.Header{}
+	h.Set(key, value)
+	return h
+}
+
+func Test_ParseEvent_GitHubPush(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	event, err := ParseEvent(header("X-GitHub-Event", "push"), []byte(gitHubPushPayload))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, event, &Event{
+		Type:          api.TriggerEventPush,
+		Provider:      "github",
+		Repository:    "org1/repo1",
+		RepositoryURL: "https://github.com/org1/repo1.git",
+		Ref:           "refs/heads/main",
+		Branch:        "main",
+		Commit:        "1111111111111111111111111111111111111111",
+		URL:           "https://github.com/org1/repo1/compare/abc...111",
+	})
+	assert.DeepEqual(t, event.TriggeredBy(), &api.TriggeredBy{
+		Type: "github-push",
+		ID:   "org1/repo1@1111111111111111111111111111111111111111",
+		URL:  "https://github.com/org1/repo1/compare/abc...111",
+	})
+}
+
+func Test_ParseEvent_GitHubPullRequest(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	event, err := ParseEvent(header("X-GitHub-Event", "pull_request"), []byte(gitHubPullRequestPayload))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, event.Args(), map[string]string{
+		"GIT_EVENT":          "pull_request",
+		"GIT_REPOSITORY":     "org1/repo1",
+		"GIT_REPOSITORY_URL": "https://github.com/org1/repo1.git",
+		"GIT_REF":            "refs/pull/42/head",
+		"GIT_BRANCH":         "feature1",
+		"GIT_COMMIT":         "2222222222222222222222222222222222222222",
+		"GIT_PULL_REQUEST":   "42",
+		"GIT_TARGET_BRANCH":  "main",
+	})
+	assert.DeepEqual(t, event.TriggeredBy(), &api.TriggeredBy{
+		Type: "github-pull-request",
+		ID:   "org1/repo1#42",
+		URL:  "https://github.com/org1/repo1/pull/42",
+	})
+}
+
+func Test_ParseEvent_GitLabMergeRequest(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	event, err := ParseEvent(header("X-Gitlab-Event", "Merge Request Hook"), []byte(gitLabMergeRequestPayload))
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, event.Type, api.TriggerEventPullRequest)
+	assert.Equal(t, event.Repository, "group1/repo1")
+	assert.Equal(t, event.Commit, "3333333333333333333333333333333333333333")
+	assert.Equal(t, event.TargetBranch, "main")
+	assert.Equal(t, event.TriggeredBy().Type, "gitlab-merge-request")
+	assert.Equal(t, event.TriggeredBy().ID, "group1/repo1#7")
+}
+
+func Test_ParseEvent_Ignored(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		header  http.Header
+		payload string
+	}{
+		{"github_ping", header("X-GitHub-Event", "ping"), `{}`},
+		{"github_branch_deleted", header("X-GitHub-Event", "push"), `{"ref": "refs/heads/main", "deleted": true}`},
+		{"github_pull_request_closed", header("X-GitHub-Event", "pull_request"), `{"action": "closed", "number": 1}`},
+		{"gitlab_branch_deleted", header("X-Gitlab-Event", "Push Hook"), `{"ref": "refs/heads/main", "after": "0000000000000000000000000000000000000000"}`},
+		{"gitlab_merge_request_merged", header("X-Gitlab-Event", "Merge Request Hook"), `{"object_attributes": {"action": "merge"}}`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			event, err := ParseEvent(tc.header, []byte(tc.payload))
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Assert(t, event == nil)
+		})
+	}
+}
+
+func Test_ParseEvent_UnknownSender(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	_, err := ParseEvent(http.Header{}, []byte(`{}`))
+
+	// VERIFY
+	assert.ErrorContains(t, err, "unknown webhook sender")
+}
+
+func Test_Event_Matches(t *testing.T) {
+	t.Parallel()
+
+	push := &Event{Type: api.TriggerEventPush, Repository: "org1/repo1", Branch: "release/1.0"}
+	pullRequest := &Event{Type: api.TriggerEventPullRequest, Repository: "org1/repo1", Branch: "feature1", TargetBranch: "main"}
+	tag := &Event{Type: api.TriggerEventPush, Repository: "org1/repo1", Ref: "refs/tags/v1"}
+
+	for _, tc := range []struct {
+		name     string
+		event    *Event
+		spec     api.TriggerSpec
+		expected bool
+	}{
+		{"no_filter", push, api.TriggerSpec{}, true},
+		{"event_type_matches", push, api.TriggerSpec{Events: []api.TriggerEventType{api.TriggerEventPush}}, true},
+		{"event_type_mismatch", pullRequest, api.TriggerSpec{Events: []api.TriggerEventType{api.TriggerEventPush}}, false},
+		{"repository_matches_ignoring_case", push, api.TriggerSpec{Repository: "Org1/Repo1"}, true},
+		{"repository_mismatch", push, api.TriggerSpec{Repository: "org1/repo2"}, false},
+		{"branch_pattern_matches", push, api.TriggerSpec{Branches: []string{"main", "release/*"}}, true},
+		{"branch_mismatch", push, api.TriggerSpec{Branches: []string{"main"}}, false},
+		{"pull_request_target_branch_matches", pullRequest, api.TriggerSpec{Branches: []string{"main"}}, true},
+		{"tag_with_branch_filter", tag, api.TriggerSpec{Branches: []string{"*"}}, false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := tc.event.Matches(&tc.spec)
+
+			// VERIFY
+			assert.Equal(t, result, tc.expected)
+		})
+	}
+}
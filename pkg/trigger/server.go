@@ -0,0 +1,129 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// HooksPath is the path prefix of the webhook endpoints. The
+	// endpoint of a trigger is `<HooksPath><namespace>/<name>`.
+	HooksPath = "/hooks/"
+
+	// WebhookSecretKey is the key of the webhook secret in the secret
+	// referenced by a trigger.
+	WebhookSecretKey = "secret"
+
+	// maxBodyBytes is the maximum size of webhook requests, which is
+	// the maximum payload size of GitHub.
+	maxBodyBytes = 25 << 20
+)
+
+// Server is an HTTP handler creating pipeline runs for Git webhook
+// events according to Trigger objects.
+type Server struct {
+	factory k8s.ClientFactory
+}
+
+// NewServer creates a new Server.
+func NewServer(factory k8s.ClientFactory) *Server {
+	return &Server{factory: factory}
+}
+
+// ServeHTTP handles a webhook request for the trigger identified by the
+// request path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, HooksPath), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	trigger, err := s.factory.StewardV1alpha1().Triggers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		klog.Errorf("failed to get trigger %s/%s: %s", namespace, name, err.Error())
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	secret, err := s.factory.CoreV1().Secrets(namespace).Get(ctx, trigger.Spec.SecretName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get webhook secret %q of trigger %s/%s: %s", trigger.Spec.SecretName, namespace, name, err.Error())
+		http.Error(w, "webhook secret not available", http.StatusInternalServerError)
+		return
+	}
+	if err := authenticate(r.Header, body, secret.Data[WebhookSecretKey]); err != nil {
+		klog.V(3).Infof("rejected webhook request for trigger %s/%s: %s", namespace, name, err.Error())
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := ParseEvent(r.Header, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if event == nil || !event.Matches(&trigger.Spec) {
+		fmt.Fprintln(w, "event ignored")
+		return
+	}
+	run, err := s.createPipelineRun(ctx, trigger, event)
+	if err != nil {
+		klog.Errorf("failed to create pipeline run for trigger %s/%s: %s", namespace, name, err.Error())
+		http.Error(w, "failed to create pipeline run", http.StatusInternalServerError)
+		return
+	}
+	klog.V(3).Infof("created pipeline run %s/%s for %s event of trigger %s", namespace, run.GetName(), event.Type, name)
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, "created pipeline run %q\n", run.GetName())
+}
+
+// createPipelineRun creates a pipeline run for the given event referring
+// to the template of the given trigger.
+func (s *Server) createPipelineRun(ctx context.Context, trigger *api.Trigger, event *Event) (*api.PipelineRun, error) {
+	args := map[string]string{}
+	for key, value := range trigger.Spec.Args {
+		args[key] = value
+	}
+	for key, value := range event.Args() {
+		args[key] = value
+	}
+	run := &api.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: trigger.GetName() + "-",
+			Namespace:    trigger.GetNamespace(),
+			Labels:       map[string]string{api.LabelTrigger: api.TriggerLabelValue(trigger.GetName())},
+		},
+		Spec: api.PipelineSpec{
+			TemplateRef: &api.PipelineRunTemplateRef{Name: trigger.Spec.TemplateRef.Name},
+			Args:        args,
+			TriggeredBy: event.TriggeredBy(),
+		},
+	}
+	slabels.LabelWithInstanceID(run, s.factory.InstanceID())
+	return s.factory.StewardV1alpha1().PipelineRuns(trigger.GetNamespace()).Create(ctx, run, metav1.CreateOptions{})
+}
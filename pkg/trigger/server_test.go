@@ -0,0 +1,138 @@
+package trigger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	assert "gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const webhookSecret = "secret1"
+
+func newTestServer() (*Server, *fake.ClientFactory) {
+	secret := fake.SecretOpaque("webhook1", "ns1")
+	secret.Data = map[string][]byte{WebhookSecretKey: []byte(webhookSecret)}
+	cf := fake.NewClientFactory(
+		fake.Trigger("trigger1", "ns1", api.TriggerSpec{
+			TemplateRef: api.PipelineRunTemplateRef{Name: "template1"},
+			SecretName:  "webhook1",
+			Branches:    []string{"main"},
+			Args:        map[string]string{"arg1": "value1", "GIT_COMMIT": "overridden"},
+		}),
+		secret,
+	)
+	return NewServer(cf), cf
+}
+
+func newGitHubRequest(path, eventType, payload, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(payload))
+	req.Header.Set("X-GitHub-Event", eventType)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func Test_Server_CreatesPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server, cf := newTestServer()
+	req := newGitHubRequest("/hooks/ns1/trigger1", "push", gitHubPushPayload, webhookSecret)
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, req)
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusCreated)
+	runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(runs.Items), 1)
+	run := runs.Items[0]
+	assert.Equal(t, run.GetGenerateName(), "trigger1-")
+	assert.Equal(t, run.GetLabels()[api.LabelTrigger], "trigger1")
+	assert.DeepEqual(t, run.Spec.TemplateRef, &api.PipelineRunTemplateRef{Name: "template1"})
+	assert.Equal(t, run.Spec.Args["arg1"], "value1")
+	assert.Equal(t, run.Spec.Args["GIT_COMMIT"], "1111111111111111111111111111111111111111")
+	assert.Equal(t, run.Spec.TriggeredBy.Type, "github-push")
+}
+
+func Test_Server_Rejects(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		path         string
+		secret       string
+		expectedCode int
+	}{
+		{"wrong_method", http.MethodGet, "/hooks/ns1/trigger1", webhookSecret, http.StatusMethodNotAllowed},
+		{"invalid_path", http.MethodPost, "/hooks/ns1", webhookSecret, http.StatusNotFound},
+		{"unknown_trigger", http.MethodPost, "/hooks/ns1/unknown", webhookSecret, http.StatusNotFound},
+		{"wrong_signature", http.MethodPost, "/hooks/ns1/trigger1", "wrong", http.StatusUnauthorized},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			server, cf := newTestServer()
+			req := newGitHubRequest(tc.path, "push", gitHubPushPayload, tc.secret)
+			req.Method = tc.method
+			rec := httptest.NewRecorder()
+
+			// EXERCISE
+			server.ServeHTTP(rec, req)
+
+			// VERIFY
+			assert.Equal(t, rec.Code, tc.expectedCode)
+			runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.Background(), metav1.ListOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, len(runs.Items), 0)
+		})
+	}
+}
+
+func Test_Server_IgnoresNonMatchingEvent(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server, cf := newTestServer()
+	payload := `{"ref": "refs/heads/feature1", "after": "1111111111111111111111111111111111111111", "repository": {"full_name": "org1/repo1"}}`
+	req := newGitHubRequest("/hooks/ns1/trigger1", "push", payload, webhookSecret)
+	rec := httptest.NewRecorder()
+
+	// EXERCISE
+	server.ServeHTTP(rec, req)
+
+	// VERIFY
+	assert.Equal(t, rec.Code, http.StatusOK)
+	runs, err := cf.StewardV1alpha1().PipelineRuns("ns1").List(context.Background(), metav1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(runs.Items), 0)
+}
+
+func Test_authenticate_GitLabToken(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	validErr := authenticate(header("X-Gitlab-Token", webhookSecret), nil, []byte(webhookSecret))
+	invalidErr := authenticate(header("X-Gitlab-Token", "wrong"), nil, []byte(webhookSecret))
+	missingErr := authenticate(http.Header{}, nil, []byte(webhookSecret))
+
+	// VERIFY
+	assert.NilError(t, validErr)
+	assert.ErrorContains(t, invalidErr, "token mismatch")
+	assert.ErrorContains(t, missingErr, "neither signed nor contains a token")
+}
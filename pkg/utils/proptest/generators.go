@@ -0,0 +1,82 @@
+package proptest
+
+import (
+	"math/rand"
+	"strings"
+)
+
+const (
+	lowerAlphaNum = "abcdefghijklmnopqrstuvwxyz0123456789"
+	printable     = lowerAlphaNum + "ABCDEFGHIJKLMNOPQRSTUVWXYZ -_./:~@#%&*+=?!äöü€"
+)
+
+// OneOf returns a randomly chosen element of the given values.
+func OneOf(r *rand.Rand, values ...string) string {
+	return values[r.Intn(len(values))]
+}
+
+// Bool returns true with a probability of 50%.
+func Bool(r *rand.Rand) bool {
+	return r.Intn(2) == 0
+}
+
+// DNSLabel returns a random DNS label with a length of 1 to maxLength
+// characters, which is also a valid name of most Kubernetes objects.
+func DNSLabel(r *rand.Rand, maxLength int) string {
+	length := 1 + r.Intn(maxLength)
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = lowerAlphaNum[r.Intn(len(lowerAlphaNum))]
+	}
+	// inner characters may be hyphens
+	for i := 1; i < length-1; i++ {
+		if r.Intn(8) == 0 {
+			b[i] = '-'
+		}
+	}
+	return string(b)
+}
+
+// String returns a random string with a length of 0 to maxLength
+// characters, including whitespace, punctuation and non-ASCII characters.
+func String(r *rand.Rand, maxLength int) string {
+	runes := []rune(printable)
+	length := r.Intn(maxLength + 1)
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteRune(runes[r.Intn(len(runes))])
+	}
+	return sb.String()
+}
+
+// StringMap returns a map with 0 to maxEntries entries with random
+// DNS label keys and random string values.
+func StringMap(r *rand.Rand, maxEntries int) map[string]string {
+	n := r.Intn(maxEntries + 1)
+	if n == 0 {
+		return nil
+	}
+	result := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		result[DNSLabel(r, 20)] = String(r, 30)
+	}
+	return result
+}
+
+// DNSLabels returns 0 to maxEntries distinct random DNS labels.
+func DNSLabels(r *rand.Rand, maxEntries int) []string {
+	n := r.Intn(maxEntries + 1)
+	if n == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var result []string
+	for len(result) < n {
+		label := DNSLabel(r, 20)
+		if !seen[label] {
+			seen[label] = true
+			result = append(result, label)
+		}
+	}
+	return result
+}
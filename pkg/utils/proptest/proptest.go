@@ -0,0 +1,84 @@
+// Package proptest runs property-based tests: a property is checked for
+// many random inputs generated from a seeded source of randomness, so
+// that edge cases missed by curated table tests are found, while
+// failures remain reproducible via the seed.
+//
+// The number of iterations and the seed can be set via environment
+// variables CountEnvVar and SeedEnvVar, e.g. to check a property more
+// thoroughly or to reproduce a failure:
+//
+//	STEWARD_PROPTEST_SEED=1234 STEWARD_PROPTEST_COUNT=1 go test ./pkg/admission/...
+package proptest
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"testing"
+)
+
+const (
+	// CountEnvVar is the name of the environment variable defining the
+	// number of iterations per property. Default: DefaultCount
+	CountEnvVar = "STEWARD_PROPTEST_COUNT"
+
+	// SeedEnvVar is the name of the environment variable defining the
+	// seed of the first iteration. Each further iteration uses the seed
+	// of the previous one incremented by one.
+	// Default: DefaultSeed
+	SeedEnvVar = "STEWARD_PROPTEST_SEED"
+
+	// DefaultCount is the default number of iterations per property.
+	DefaultCount = 100
+
+	// DefaultSeed is the default seed of the first iteration. It is fixed,
+	// so that test runs are deterministic unless SeedEnvVar is set.
+	DefaultSeed = 1
+)
+
+// Property is a property checked for random inputs generated from r.
+// It fails t if the property does not hold.
+type Property func(t *testing.T, r *rand.Rand)
+
+// Check checks the given property in a subtest per iteration named after
+// the seed of the iteration, which reproduces a failure if set via
+// SeedEnvVar.
+func Check(t *testing.T, property Property) {
+	t.Helper()
+	count, err := envInt(CountEnvVar, DefaultCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, err := envInt(SeedEnvVar, DefaultSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, seed, count, property)
+}
+
+func check(t *testing.T, seed, count int64, property Property) {
+	t.Helper()
+	for i := int64(0); i < count; i++ {
+		iterationSeed := seed + i
+		ok := t.Run(fmt.Sprintf("seed=%d", iterationSeed), func(t *testing.T) {
+			property(t, rand.New(rand.NewSource(iterationSeed)))
+		})
+		if !ok {
+			t.Logf("property failed, reproduce with %s=%d %s=1", SeedEnvVar, iterationSeed, CountEnvVar)
+			return
+		}
+	}
+}
+
+func envInt(name string, defaultValue int64) (int64, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return defaultValue, nil
+	}
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q of environment variable %s: %s", value, name, err.Error())
+	}
+	return result, nil
+}
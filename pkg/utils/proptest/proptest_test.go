@@ -0,0 +1,78 @@
+package proptest
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_check_RunsIterationsWithConsecutiveSeeds(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	var values []int64
+	property := func(t *testing.T, r *rand.Rand) {
+		values = append(values, r.Int63())
+	}
+
+	// EXERCISE
+	check(t, 42, 3, property)
+
+	// VERIFY
+	expected := []int64{
+		rand.New(rand.NewSource(42)).Int63(),
+		rand.New(rand.NewSource(43)).Int63(),
+		rand.New(rand.NewSource(44)).Int63(),
+	}
+	assert.DeepEqual(t, expected, values)
+}
+
+func Test_envInt(t *testing.T) {
+	const name = "STEWARD_PROPTEST_TEST_VAR"
+
+	for _, tc := range []struct {
+		value         string
+		expected      int64
+		expectedError string
+	}{
+		{"", 7, ""},
+		{"12", 12, ""},
+		{"-3", -3, ""},
+		{"abc", 0, `invalid value "abc" of environment variable STEWARD_PROPTEST_TEST_VAR: strconv.ParseInt: parsing "abc": invalid syntax`},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			// SETUP
+			assert.NilError(t, os.Setenv(name, tc.value))
+			defer os.Unsetenv(name)
+
+			// EXERCISE
+			result, err := envInt(name, 7)
+
+			// VERIFY
+
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_DNSLabels_Distinct(t *testing.T) {
+	t.Parallel()
+
+	Check(t, func(t *testing.T, r *rand.Rand) {
+		labels := DNSLabels(r, 10)
+		seen := map[string]bool{}
+		for _, label := range labels {
+			assert.Assert(t, label != "")
+			assert.Assert(t, len(label) <= 63, label)
+			assert.Assert(t, !seen[label], label)
+			seen[label] = true
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package recovery
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error returned by Call if the called function
+// panicked.
+type PanicError struct {
+	// Value is the value passed to `panic()`.
+	Value interface{}
+
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+// let compiler verify interface compliance
+var _ error = (*PanicError)(nil)
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
+// Call calls fn and returns its result. If fn panics, the panic is
+// recovered and returned as *PanicError.
+func Call(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+	return fn()
+}
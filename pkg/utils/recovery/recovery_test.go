@@ -0,0 +1,66 @@
+package recovery
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+)
+
+func Test_Call_NoPanic(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	err1 := errors.New("err1")
+
+	for _, tc := range []struct {
+		name     string
+		result   error
+		expected error
+	}{
+		{"nil", nil, nil},
+		{"error", err1, err1},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := Call(func() error { return tc.result })
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Call_Panic(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := Call(func() error { panic("panic1") })
+
+	// VERIFY
+	assert.Error(t, result, "recovered from panic: panic1")
+	var panicErr *PanicError
+	assert.Assert(t, errors.As(result, &panicErr))
+	assert.Equal(t, "panic1", panicErr.Value)
+	assert.Assert(t, strings.Contains(string(panicErr.Stack), "Test_Call_Panic"))
+}
+
+func Test_Call_PanicWithError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	err1 := errors.New("err1")
+
+	// EXERCISE
+	result := Call(func() error { panic(err1) })
+
+	// VERIFY
+	assert.Error(t, result, "recovered from panic: err1")
+	var panicErr *PanicError
+	assert.Assert(t, errors.As(result, &panicErr))
+	assert.Equal(t, err1, panicErr.Value)
+}
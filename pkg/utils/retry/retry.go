@@ -0,0 +1,183 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Backoff configures the delays between the attempts of a retried
+// operation.
+type Backoff struct {
+	// Duration is the delay before the first retry.
+	Duration time.Duration
+
+	// Factor is the factor the delay is multiplied with after each
+	// retry. Values less than 1 are treated as 1.
+	Factor float64
+
+	// Jitter is the maximum fraction of the delay added randomly to
+	// each delay. If zero, there is no jitter.
+	Jitter float64
+
+	// Cap is the upper limit of a single delay before jitter is
+	// applied. If zero, delays are not limited.
+	Cap time.Duration
+
+	// Steps is the maximum number of attempts including the first one.
+	// If zero or negative, the operation is retried until it succeeds,
+	// fails with a non-retriable error or the context is done.
+	Steps int
+}
+
+// DefaultBackoff is the backoff for operations that fail due to transient
+// problems of the Kubernetes API server.
+// It is equal to `k8s.io/client-go/util/retry.DefaultBackoff`.
+var DefaultBackoff = Backoff{
+	Steps:    4,
+	Duration: 10 * time.Millisecond,
+	Factor:   5.0,
+	Jitter:   0.1,
+}
+
+// DefaultRetry is the backoff for operations that fail due to resource
+// version conflicts and can be repeated immediately with the latest
+// version of the object.
+// It is equal to `k8s.io/client-go/util/retry.DefaultRetry`.
+var DefaultRetry = Backoff{
+	Steps:    5,
+	Duration: 10 * time.Millisecond,
+	Factor:   1.0,
+	Jitter:   0.1,
+}
+
+// Classifier returns whether the given error is retriable.
+type Classifier func(err error) bool
+
+// OnError calls fn until it returns nil or a non-retriable error as
+// determined by the classifier, waiting between attempts according to the
+// backoff. If the attempts are exhausted, the last error is returned.
+// If the context is done while waiting, the last error is returned with
+// the context error as message.
+// A retriable mark set via Retriable is removed from the returned error.
+func OnError(ctx context.Context, backoff Backoff, retriable Classifier, fn func() error) error {
+	delay := backoff.Duration
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !retriable(err) {
+			return unwrapRetriable(err)
+		}
+		if backoff.Steps > 0 && attempt >= backoff.Steps {
+			return unwrapRetriable(err)
+		}
+		if ctxErr := sleep(ctx, jitter(delay, backoff)); ctxErr != nil {
+			return errors.WithMessage(unwrapRetriable(err), ctxErr.Error())
+		}
+		delay = nextDelay(delay, backoff)
+	}
+}
+
+// Do calls fn until it returns nil or an error not marked as retriable
+// via Retriable, waiting between attempts according to the backoff.
+// See OnError for details.
+func Do(ctx context.Context, backoff Backoff, fn func() error) error {
+	return OnError(ctx, backoff, IsRetriable, fn)
+}
+
+// OnConflict calls fn until it returns nil or an error other than a
+// resource version conflict. It uses DefaultRetry as backoff.
+func OnConflict(ctx context.Context, fn func() error) error {
+	return OnError(ctx, DefaultRetry, k8serrors.IsConflict, fn)
+}
+
+// IsTransientAPIError returns whether the given error returned by the
+// Kubernetes API server indicates a transient problem, i.e. the request
+// may succeed if repeated. Resource version conflicts are considered
+// transient, see IsTransientServerError for an alternative.
+func IsTransientAPIError(err error) bool {
+	return k8serrors.IsConflict(err) || IsTransientServerError(err)
+}
+
+// IsTransientServerError returns whether the given error returned by the
+// Kubernetes API server indicates a transient problem of the server.
+// In contrast to IsTransientAPIError, conflicts are not considered
+// transient, which is required for requests with preconditions.
+func IsTransientServerError(err error) bool {
+	return k8serrors.IsInternalError(err) ||
+		k8serrors.IsServerTimeout(err) ||
+		k8serrors.IsServiceUnavailable(err) ||
+		k8serrors.IsTimeout(err) ||
+		k8serrors.IsTooManyRequests(err) ||
+		k8serrors.IsUnexpectedServerError(err)
+}
+
+// retriableError marks an error as retriable.
+type retriableError struct {
+	cause error
+}
+
+func (e *retriableError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *retriableError) Cause() error {
+	return e.cause
+}
+
+func (e *retriableError) Unwrap() error {
+	return e.cause
+}
+
+// Retriable marks the given error as retriable for Do.
+// If err is nil, nil is returned.
+func Retriable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retriableError{cause: err}
+}
+
+// IsRetriable returns whether the given error or an error in its chain has
+// been marked as retriable via Retriable.
+func IsRetriable(err error) bool {
+	var target *retriableError
+	return errors.As(err, &target)
+}
+
+func unwrapRetriable(err error) error {
+	if e, ok := err.(*retriableError); ok {
+		return e.cause
+	}
+	return err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func jitter(d time.Duration, backoff Backoff) time.Duration {
+	if backoff.Jitter > 0 {
+		return wait.Jitter(d, backoff.Jitter)
+	}
+	return d
+}
+
+func nextDelay(d time.Duration, backoff Backoff) time.Duration {
+	if backoff.Factor > 1 {
+		d = time.Duration(float64(d) * backoff.Factor)
+	}
+	if backoff.Cap > 0 && d > backoff.Cap {
+		d = backoff.Cap
+	}
+	return d
+}
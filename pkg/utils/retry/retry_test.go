@@ -0,0 +1,289 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gotest.tools/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testBackoff = Backoff{
+	Duration: time.Millisecond,
+	Factor:   2,
+	Steps:    3,
+}
+
+func Test_OnError(t *testing.T) {
+	t.Parallel()
+
+	errRetriable := errors.New("retriable")
+	errOther := errors.New("other")
+	isRetriable := func(err error) bool { return err == errRetriable }
+
+	for _, tc := range []struct {
+		name             string
+		results          []error
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{"success", []error{nil}, nil, 1},
+		{"success after retries", []error{errRetriable, errRetriable, nil}, nil, 3},
+		{"non-retriable error", []error{errRetriable, errOther}, errOther, 2},
+		{"attempts exhausted", []error{errRetriable, errRetriable, errRetriable, nil}, errRetriable, 3},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			attempts := 0
+			fn := func() error {
+				err := tc.results[attempts]
+				attempts++
+				return err
+			}
+
+			// EXERCISE
+			err := OnError(context.Background(), testBackoff, isRetriable, fn)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+		})
+	}
+}
+
+func Test_OnError_UnlimitedSteps(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	backoff := Backoff{Duration: time.Microsecond}
+	attempts := 0
+	fn := func() error {
+		attempts++
+		if attempts < 10 {
+			return errors.New("retriable")
+		}
+		return nil
+	}
+
+	// EXERCISE
+	err := OnError(context.Background(), backoff, func(error) bool { return true }, fn)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 10, attempts)
+}
+
+func Test_OnError_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx, cancel := context.WithCancel(context.Background())
+	backoff := Backoff{Duration: time.Hour}
+	attempts := 0
+	fn := func() error {
+		attempts++
+		cancel()
+		return errors.New("error1")
+	}
+
+	// EXERCISE
+	err := OnError(ctx, backoff, func(error) bool { return true }, fn)
+
+	// VERIFY
+	assert.Error(t, err, "context canceled: error1")
+	assert.Equal(t, 1, attempts)
+}
+
+func Test_Do(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	errCause := errors.New("cause1")
+	attempts := 0
+	fn := func() error {
+		attempts++
+		return Retriable(errCause)
+	}
+
+	// EXERCISE
+	err := Do(context.Background(), testBackoff, fn)
+
+	// VERIFY
+	assert.Equal(t, errCause, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func Test_Do_NonRetriableError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	errCause := errors.New("cause1")
+	attempts := 0
+	fn := func() error {
+		attempts++
+		return errCause
+	}
+
+	// EXERCISE
+	err := Do(context.Background(), testBackoff, fn)
+
+	// VERIFY
+	assert.Equal(t, errCause, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func Test_OnConflict(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	conflictErr := k8serrors.NewConflict(schema.GroupResource{Resource: "foos"}, "foo1", errors.New("conflict"))
+	attempts := 0
+	fn := func() error {
+		attempts++
+		if attempts == 1 {
+			return conflictErr
+		}
+		return nil
+	}
+
+	// EXERCISE
+	err := OnConflict(context.Background(), fn)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func Test_IsRetriable(t *testing.T) {
+	t.Parallel()
+
+	errCause := errors.New("cause1")
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"unmarked", errCause, false},
+		{"marked", Retriable(errCause), true},
+		{"wrapped marked", errors.Wrap(Retriable(errCause), "wrapper"), true},
+		{"fmt wrapped marked", fmt.Errorf("wrapper: %w", Retriable(errCause)), true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := IsRetriable(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Retriable_Nil(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := Retriable(nil)
+
+	// VERIFY
+	assert.NilError(t, result)
+}
+
+func Test_IsTransientAPIError(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Resource: "foos"}
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"conflict", k8serrors.NewConflict(gr, "foo1", errors.New("e")), true},
+		{"internal error", k8serrors.NewInternalError(errors.New("e")), true},
+		{"server timeout", k8serrors.NewServerTimeout(gr, "get", 1), true},
+		{"service unavailable", k8serrors.NewServiceUnavailable("e"), true},
+		{"timeout", k8serrors.NewTimeoutError("e", 1), true},
+		{"too many requests", k8serrors.NewTooManyRequests("e", 1), true},
+		{"not found", k8serrors.NewNotFound(gr, "foo1"), false},
+		{"forbidden", k8serrors.NewForbidden(gr, "foo1", errors.New("e")), false},
+		{"other", errors.New("e"), false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := IsTransientAPIError(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_IsTransientServerError(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Resource: "foos"}
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"conflict", k8serrors.NewConflict(gr, "foo1", errors.New("e")), false},
+		{"internal error", k8serrors.NewInternalError(errors.New("e")), true},
+		{"too many requests", k8serrors.NewTooManyRequests("e", 1), true},
+		{"not found", k8serrors.NewNotFound(gr, "foo1"), false},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := IsTransientServerError(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_nextDelay(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		delay    time.Duration
+		backoff  Backoff
+		expected time.Duration
+	}{
+		{"no factor", time.Second, Backoff{}, time.Second},
+		{"factor below one", time.Second, Backoff{Factor: 0.5}, time.Second},
+		{"factor", time.Second, Backoff{Factor: 3}, 3 * time.Second},
+		{"capped", time.Second, Backoff{Factor: 3, Cap: 2 * time.Second}, 2 * time.Second},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := nextDelay(tc.delay, tc.backoff)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
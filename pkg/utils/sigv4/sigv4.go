@@ -0,0 +1,83 @@
+// Package sigv4 signs HTTP requests with AWS Signature Version 4 as
+// required by AWS services and S3-compatible object stores.
+package sigv4
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the signing algorithm of AWS Signature Version 4.
+	Algorithm = "AWS4-HMAC-SHA256"
+
+	// signedHeaders are the headers included in request signatures.
+	signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+)
+
+// Credentials are the credentials used to sign requests.
+type Credentials struct {
+	// AccessKeyID is the access key ID.
+	// If empty, requests are not signed.
+	AccessKeyID string
+
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+}
+
+// Sign adds the headers `X-Amz-Date` and `X-Amz-Content-Sha256` to the
+// given request with the given payload and, if an access key ID is
+// given, the `Authorization` header with the signature for the given
+// region and service (e.g. `s3`) at time now.
+// The headers `Host`, `X-Amz-Content-Sha256` and `X-Amz-Date` are signed.
+func Sign(request *http.Request, payload []byte, credentials Credentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payloadHash := sha256.Sum256(payload)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+	if credentials.AccessKeyID == "" {
+		return
+	}
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		"host:" + request.URL.Host,
+		"x-amz-content-sha256:" + payloadHashHex,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHashHex,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join([]string{now.Format("20060102"), region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		Algorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,49 @@
+package sigv4
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_Sign(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	payload := []byte(`{"SecretId":"secret1"}`)
+	request, err := http.NewRequest(http.MethodPost, "https://secretsmanager.eu-central-1.amazonaws.com/", bytes.NewReader(payload))
+	assert.NilError(t, err)
+	now := time.Date(2022, 12, 1, 11, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	// EXERCISE
+	Sign(request, payload, Credentials{AccessKeyID: "key1", SecretAccessKey: "secret1"}, "eu-central-1", "secretsmanager", now)
+
+	// VERIFY
+	assert.Equal(t, "20221201T103000Z", request.Header.Get("X-Amz-Date"))
+	assert.Equal(t, "632a00016982e6c92e70b7debe1117f487110007fa25f84cf06f0538691a2825", request.Header.Get("X-Amz-Content-Sha256"))
+	assert.Equal(t,
+		"AWS4-HMAC-SHA256 Credential=key1/20221201/eu-central-1/secretsmanager/aws4_request,"+
+			" SignedHeaders=host;x-amz-content-sha256;x-amz-date,"+
+			" Signature=b53bb70e1e74f216ba656186662a93dd0a3a0211454c9f4289bc1cc25993db7a",
+		request.Header.Get("Authorization"),
+	)
+}
+
+func Test_Sign_NoAccessKeyID(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	request, err := http.NewRequest(http.MethodGet, "https://s3.example.com/bucket1/key1", nil)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	Sign(request, nil, Credentials{}, "us-east-1", "s3", time.Now())
+
+	// VERIFY
+	assert.Assert(t, request.Header.Get("X-Amz-Date") != "")
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", request.Header.Get("X-Amz-Content-Sha256"))
+	assert.Equal(t, "", request.Header.Get("Authorization"))
+}
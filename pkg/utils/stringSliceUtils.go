@@ -1,5 +1,7 @@
 package utils
 
+import "strings"
+
 // AddStringIfMissing adds a string to a slice of strings
 // The provided slice is not changed
 // returns true, slice with appended string if string was not in the list
@@ -40,3 +42,17 @@ func StringSliceContains(slice []string, elem string) bool {
 	}
 	return false
 }
+
+// SplitList splits a comma-separated list of strings.
+// Whitespace around the elements is removed and empty elements
+// are skipped.
+func SplitList(list string) []string {
+	result := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
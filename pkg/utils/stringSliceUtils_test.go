@@ -87,3 +87,21 @@ func Test_StringSliceContains(t *testing.T) {
 		})
 	}
 }
+
+func Test_SplitList(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected []string
+	}{
+		{"", []string{}},
+		{" , ,", []string{}},
+		{"a", []string{"a"}},
+		{"a,b", []string{"a", "b"}},
+		{" a , b ,, c ", []string{"a", "b", "c"}},
+	} {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			assert.DeepEqual(t, tc.expected, SplitList(tc.input))
+		})
+	}
+}
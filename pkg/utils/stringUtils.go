@@ -3,6 +3,7 @@ package utils
 import (
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 //Trim removes blanks
@@ -23,3 +24,76 @@ func ShortenMessage(message string, length int) (shortenedMessage string) {
 	}
 	return
 }
+
+// TruncationStrategy defines which part of a message is kept if the
+// message gets truncated.
+type TruncationStrategy string
+
+const (
+	// TruncationKeepHead keeps the beginning of a message.
+	TruncationKeepHead TruncationStrategy = "head"
+
+	// TruncationKeepTail keeps the end of a message.
+	TruncationKeepTail TruncationStrategy = "tail"
+
+	// TruncationKeepHeadAndTail keeps the beginning and the end of a
+	// message in equal parts.
+	TruncationKeepHeadAndTail TruncationStrategy = "headAndTail"
+)
+
+// IsValid returns whether the strategy is one of the defined strategies.
+func (s TruncationStrategy) IsValid() bool {
+	switch s {
+	case TruncationKeepHead, TruncationKeepTail, TruncationKeepHeadAndTail:
+		return true
+	}
+	return false
+}
+
+// TruncateMessage shortens the message to at most maxLength bytes
+// if it is longer. The removed part is replaced by marker, which
+// counts towards maxLength. Multi-byte characters are never split.
+// If maxLength is zero or negative, the message is returned unchanged.
+func TruncateMessage(message string, maxLength int, strategy TruncationStrategy, marker string) string {
+	if maxLength <= 0 || len(message) <= maxLength {
+		return message
+	}
+	if len(marker) >= maxLength {
+		return headOf(marker, maxLength)
+	}
+	remaining := maxLength - len(marker)
+	switch strategy {
+	case TruncationKeepHead:
+		return headOf(message, remaining) + marker
+	case TruncationKeepHeadAndTail:
+		headLength := (remaining + 1) / 2
+		return headOf(message, headLength) + marker + tailOf(message, remaining-headLength)
+	default:
+		return marker + tailOf(message, remaining)
+	}
+}
+
+// headOf returns the longest prefix of s with at most n bytes
+// not ending within a multi-byte character.
+func headOf(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// tailOf returns the longest suffix of s with at most n bytes
+// not starting within a multi-byte character.
+func tailOf(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return s[i:]
+}
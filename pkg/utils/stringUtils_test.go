@@ -61,3 +61,49 @@ func Test_ShortenMessage_multipleNewLinesSquashed(t *testing.T) {
 	result := ShortenMessage(" A\n\n\nB ", 1000)
 	assert.Equal(t, "A B", result)
 }
+
+func Test_TruncateMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		message   string
+		maxLength int
+		strategy  TruncationStrategy
+		marker    string
+		expected  string
+	}{
+		{"no limit", "ABCDEFGHIJ", 0, TruncationKeepHead, "~", "ABCDEFGHIJ"},
+		{"short enough", "ABCDEFGHIJ", 10, TruncationKeepHead, "~", "ABCDEFGHIJ"},
+		{"head", "ABCDEFGHIJ", 6, TruncationKeepHead, "~", "ABCDE~"},
+		{"tail", "ABCDEFGHIJ", 6, TruncationKeepTail, "~", "~FGHIJ"},
+		{"head and tail even", "ABCDEFGHIJ", 7, TruncationKeepHeadAndTail, "~", "ABC~HIJ"},
+		{"head and tail odd", "ABCDEFGHIJ", 6, TruncationKeepHeadAndTail, "~", "ABC~IJ"},
+		{"unknown strategy keeps tail", "ABCDEFGHIJ", 6, "foo", "~", "~FGHIJ"},
+		{"marker too long", "ABCDEFGHIJ", 3, TruncationKeepHead, "[truncated]", "[tr"},
+		{"multi-byte head", "äöüäöü", 6, TruncationKeepHead, "~", "äö~"},
+		{"multi-byte tail", "äöüäöü", 6, TruncationKeepTail, "~", "~öü"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := TruncateMessage(tc.message, tc.maxLength, tc.strategy, tc.marker)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+			if tc.maxLength > 0 {
+				assert.Assert(t, len(result) <= tc.maxLength)
+			}
+		})
+	}
+}
+
+func Test_TruncationStrategy_IsValid(t *testing.T) {
+	assert.Assert(t, TruncationKeepHead.IsValid())
+	assert.Assert(t, TruncationKeepTail.IsValid())
+	assert.Assert(t, TruncationKeepHeadAndTail.IsValid())
+	assert.Assert(t, !TruncationStrategy("").IsValid())
+	assert.Assert(t, !TruncationStrategy("foo").IsValid())
+}
@@ -0,0 +1,96 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// ClusterCapabilities describes features of the cluster under test that
+// influence which tests can be executed.
+type ClusterCapabilities struct {
+	// IPFamilies are the IP families of the cluster, e.g. only
+	// `IPv6` for IPv6-only clusters or both `IPv4` and `IPv6` for
+	// dual-stack clusters.
+	IPFamilies []corev1.IPFamily
+}
+
+// HasIPFamily returns whether the cluster supports the given IP family.
+func (c *ClusterCapabilities) HasIPFamily(family corev1.IPFamily) bool {
+	for _, f := range c.IPFamilies {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDualStack returns whether the cluster supports both IPv4 and IPv6.
+func (c *ClusterCapabilities) IsDualStack() bool {
+	return c.HasIPFamily(corev1.IPv4Protocol) && c.HasIPFamily(corev1.IPv6Protocol)
+}
+
+// IsIPv6Only returns whether the cluster supports IPv6 but not IPv4.
+func (c *ClusterCapabilities) IsIPv6Only() bool {
+	return c.HasIPFamily(corev1.IPv6Protocol) && !c.HasIPFamily(corev1.IPv4Protocol)
+}
+
+// DetectClusterCapabilities detects the capabilities of the cluster the
+// client factory in the context is connected to.
+// The IP families are taken from the `kubernetes` service in the
+// `default` namespace, which exists in every cluster.
+func DetectClusterCapabilities(ctx context.Context) (*ClusterCapabilities, error) {
+	service, err := GetClientFactory(ctx).CoreV1().Services(metav1.NamespaceDefault).Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect cluster capabilities: %w", err)
+	}
+	capabilities := &ClusterCapabilities{
+		IPFamilies: ipFamiliesOfService(service),
+	}
+	klog.V(3).Infof("Cluster capabilities: IP families %v", capabilities.IPFamilies)
+	return capabilities, nil
+}
+
+// RequireIPFamily skips the test if the cluster does not support the
+// given IP family.
+func RequireIPFamily(ctx context.Context, t *testing.T, family corev1.IPFamily) {
+	t.Helper()
+	capabilities, err := DetectClusterCapabilities(ctx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !capabilities.HasIPFamily(family) {
+		t.Skipf("cluster does not support IP family %s (has %v)", family, capabilities.IPFamilies)
+	}
+}
+
+// ipFamiliesOfService returns the IP families of the given service.
+// Clusters not supporting dual-stack may not set `spec.ipFamilies`,
+// in which case the families are derived from the cluster IPs.
+func ipFamiliesOfService(service *corev1.Service) []corev1.IPFamily {
+	if len(service.Spec.IPFamilies) > 0 {
+		return service.Spec.IPFamilies
+	}
+	clusterIPs := service.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && service.Spec.ClusterIP != "" {
+		clusterIPs = []string{service.Spec.ClusterIP}
+	}
+	var families []corev1.IPFamily
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip == nil {
+			continue
+		}
+		if ip.To4() != nil {
+			families = append(families, corev1.IPv4Protocol)
+		} else {
+			families = append(families, corev1.IPv6Protocol)
+		}
+	}
+	return families
+}
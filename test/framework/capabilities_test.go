@@ -0,0 +1,91 @@
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func kubernetesService(spec corev1.ServiceSpec) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kubernetes",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: spec,
+	}
+}
+
+func Test_DetectClusterCapabilities(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		spec             corev1.ServiceSpec
+		expectedFamilies []corev1.IPFamily
+		expectedIPv6Only bool
+		expectedDual     bool
+	}{
+		{
+			name:             "IPv4 from families",
+			spec:             corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol}},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+		},
+		{
+			name:             "IPv6-only from families",
+			spec:             corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol}},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			expectedIPv6Only: true,
+		},
+		{
+			name:             "dual-stack from families",
+			spec:             corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			expectedDual:     true,
+		},
+		{
+			name:             "IPv4 from cluster IP",
+			spec:             corev1.ServiceSpec{ClusterIP: "10.0.0.1"},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv4Protocol},
+		},
+		{
+			name:             "IPv6-only from cluster IPs",
+			spec:             corev1.ServiceSpec{ClusterIPs: []string{"fd00::1"}},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+			expectedIPv6Only: true,
+		},
+		{
+			name:             "dual-stack from cluster IPs",
+			spec:             corev1.ServiceSpec{ClusterIPs: []string{"10.0.0.1", "fd00::1"}},
+			expectedFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			expectedDual:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := SetClientFactory(context.Background(), fake.NewClientFactory(kubernetesService(tc.spec)))
+
+			// EXERCISE
+			capabilities, err := DetectClusterCapabilities(ctx)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedFamilies, capabilities.IPFamilies)
+			assert.Equal(t, tc.expectedIPv6Only, capabilities.IsIPv6Only())
+			assert.Equal(t, tc.expectedDual, capabilities.IsDualStack())
+		})
+	}
+}
+
+func Test_DetectClusterCapabilities_NoKubernetesService(t *testing.T) {
+	// SETUP
+	ctx := SetClientFactory(context.Background(), fake.NewClientFactory())
+
+	// EXERCISE
+	_, err := DetectClusterCapabilities(ctx)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to detect cluster capabilities")
+}
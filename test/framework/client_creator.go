@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/SAP/stewardci-core/pkg/k8s"
+	"k8s.io/client-go/rest"
 	klog "k8s.io/klog/v2"
 	knativetest "knative.dev/pkg/test"
 )
@@ -25,6 +26,13 @@ func Setup(t *testing.T) context.Context {
 	if err != nil {
 		panic(err.Error())
 	}
+	return setupWithConfig(t, config, kubeconfig, os.Getenv("STEWARD_TEST_TENANT"))
+}
+
+// setupWithConfig prepares the test environment for the Steward
+// installation the given client config points to.
+func setupWithConfig(t *testing.T, config *rest.Config, kubeconfig string, tenantNamespace string) context.Context {
+	t.Helper()
 	factory := k8s.NewClientFactory(config, resyncPeriod)
 	if factory == nil {
 		t.Fatalf("failed to create client factory for config file '%s'.", kubeconfig)
@@ -33,7 +41,6 @@ func Setup(t *testing.T) context.Context {
 	if testClient == "" {
 		t.Fatalf("environment variable STEWARD_TEST_CLIENT undefined")
 	}
-	tenantNamespace := os.Getenv("STEWARD_TEST_TENANT")
 	ctx := context.Background()
 	ctx = SetNamespace(ctx, testClient)
 	ctx = SetTenantNamespace(ctx, tenantNamespace)
@@ -17,6 +17,7 @@ const (
 	tenantNamespaceKey contextKey = "TenantNamespace"
 	testNameKey        contextKey = "testName"
 	realmUUIDKey       contextKey = "realmUUID"
+	installationKey    contextKey = "installation"
 )
 
 // GetClientFactory returns the client factory from the context
@@ -86,3 +87,20 @@ func GetRealmUUID(ctx context.Context) string {
 func SetRealmUUID(ctx context.Context) context.Context {
 	return context.WithValue(ctx, realmUUIDKey, uuid.New().String())
 }
+
+// GetInstallationName returns the name of the Steward installation under
+// test from the context. It is empty if only a single installation is
+// tested.
+func GetInstallationName(ctx context.Context) string {
+	val := ctx.Value(installationKey)
+	if val == nil {
+		return ""
+	}
+	return val.(string)
+}
+
+// SetInstallationName sets the name of the Steward installation under
+// test to the context
+func SetInstallationName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, installationKey, name)
+}
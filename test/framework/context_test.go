@@ -53,3 +53,10 @@ func Test_Set_GetRealmUUID(t *testing.T) {
 	ctx = SetRealmUUID(ctx)
 	assert.Assert(t, GetRealmUUID(ctx) != "")
 }
+
+func Test_Set_GetInstallationName(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", GetInstallationName(ctx))
+	ctx = SetInstallationName(ctx, "prod")
+	assert.Equal(t, "prod", GetInstallationName(ctx))
+}
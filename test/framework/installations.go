@@ -0,0 +1,158 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// installationsEnvVar is the environment variable listing the Steward
+	// installations to execute tests against (see ParseInstallations).
+	installationsEnvVar = "STEWARD_TEST_INSTALLATIONS"
+
+	// reportEnvVar is the environment variable with the path of the file
+	// the comparison report of multi-installation tests is written to.
+	reportEnvVar = "STEWARD_TEST_REPORT"
+)
+
+// Installation is a Steward installation tests are executed against.
+type Installation struct {
+	// Name identifies the installation in test names and reports,
+	// e.g. `prod` or `rc`.
+	Name string
+
+	// Kubeconfig is the path of the kubeconfig file for the cluster of
+	// the installation.
+	Kubeconfig string
+
+	// Context is the kubeconfig context to be used. If empty, the
+	// current context of the kubeconfig file is used.
+	Context string
+}
+
+// ParseInstallations parses a comma-separated list of Steward
+// installations. Each entry has the format `name=kubeconfig` or
+// `name=kubeconfig#context`, e.g. `prod=/tmp/prod.yaml,rc=/tmp/rc.yaml#rc`.
+// Names must be unique.
+func ParseInstallations(value string) ([]Installation, error) {
+	var installations []Installation
+	names := map[string]bool{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid installation %q: expected format 'name=kubeconfig[#context]'", entry)
+		}
+		installation := Installation{Name: strings.TrimSpace(parts[0])}
+		location := strings.SplitN(parts[1], "#", 2)
+		installation.Kubeconfig = strings.TrimSpace(location[0])
+		if len(location) == 2 {
+			installation.Context = strings.TrimSpace(location[1])
+		}
+		if installation.Name == "" || strings.ContainsAny(installation.Name, "/ ") {
+			return nil, fmt.Errorf("invalid installation %q: name must not be empty or contain '/' or spaces", entry)
+		}
+		if installation.Kubeconfig == "" {
+			return nil, fmt.Errorf("invalid installation %q: kubeconfig must not be empty", entry)
+		}
+		if names[installation.Name] {
+			return nil, fmt.Errorf("duplicate installation name %q", installation.Name)
+		}
+		names[installation.Name] = true
+		installations = append(installations, installation)
+	}
+	return installations, nil
+}
+
+// clientConfig returns the client config for the cluster of the
+// installation.
+func (i Installation) clientConfig() (*rest.Config, error) {
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: i.Kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: i.Context},
+	).ClientConfig()
+}
+
+// SetupInstallation prepares the test environment for the given Steward
+// installation. The test tenant is always created by the tests, as
+// STEWARD_TEST_TENANT can only refer to a tenant of a single installation.
+func SetupInstallation(t *testing.T, installation Installation) context.Context {
+	t.Helper()
+	klog.V(3).Infof("Create Factory for installation %q (config: %s, context: %q)", installation.Name, installation.Kubeconfig, installation.Context)
+	config, err := installation.clientConfig()
+	if err != nil {
+		t.Fatalf("installation %q: failed to load kubeconfig: %s", installation.Name, err.Error())
+	}
+	ctx := setupWithConfig(t, config, installation.Kubeconfig, "")
+	return SetInstallationName(ctx, installation.Name)
+}
+
+// ExecutePipelineRunTestsOnInstallations executes the given test plans
+// concurrently against each Steward installation listed in environment
+// variable STEWARD_TEST_INSTALLATIONS, e.g. the current production
+// version and a release candidate. Afterwards a report comparing the
+// results is logged and written to the file given by environment variable
+// STEWARD_TEST_REPORT, if set.
+// If no installations are listed, the test plans are executed like
+// ExecutePipelineRunTests.
+func ExecutePipelineRunTestsOnInstallations(t *testing.T, testPlans ...TestPlan) {
+	installations, err := ParseInstallations(os.Getenv(installationsEnvVar))
+	if err != nil {
+		t.Fatalf("environment variable %s: %s", installationsEnvVar, err.Error())
+	}
+	if len(installations) == 0 {
+		ExecutePipelineRunTests(t, testPlans...)
+		return
+	}
+	report := executeOnInstallations(t, installations, func(t *testing.T, installation Installation) []TestResult {
+		return executePipelineRunTests(SetupInstallation(t, installation), t, testPlans...)
+	})
+	publishReport(t, report, os.Getenv(reportEnvVar))
+}
+
+// executeOnInstallations calls execute for each of the given installations
+// in a parallel subtest and returns the report comparing the results.
+// Installations whose subtest is aborted have no results in the report.
+func executeOnInstallations(t *testing.T, installations []Installation, execute func(*testing.T, Installation) []TestResult) *ComparisonReport {
+	names := make([]string, len(installations))
+	for i, installation := range installations {
+		names[i] = installation.Name
+	}
+	report := NewComparisonReport(names)
+	// the group returns after all its parallel subtests have finished
+	t.Run("installations", func(t *testing.T) {
+		for _, installation := range installations {
+			installation := installation
+			t.Run(installation.Name, func(t *testing.T) {
+				t.Parallel()
+				report.Add(installation.Name, execute(t, installation))
+			})
+		}
+	})
+	return report
+}
+
+// publishReport logs the given report and writes it to the file with the
+// given path, if not empty.
+func publishReport(t *testing.T, report *ComparisonReport, path string) {
+	t.Helper()
+	content := report.String()
+	t.Logf("Comparison of Steward installations:\n%s", content)
+	if path == "" {
+		return
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Errorf("failed to write comparison report to %q: %s", path, err.Error())
+	}
+}
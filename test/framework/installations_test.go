@@ -0,0 +1,90 @@
+package framework
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_ParseInstallations(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		value    string
+		expected []Installation
+	}{
+		{"empty", "", nil},
+		{"blank entries", " , ,", nil},
+		{"single", "prod=/tmp/prod.yaml", []Installation{
+			{Name: "prod", Kubeconfig: "/tmp/prod.yaml"},
+		}},
+		{"multiple with context", " prod=/tmp/kubeconfig#prod , rc=/tmp/kubeconfig#rc", []Installation{
+			{Name: "prod", Kubeconfig: "/tmp/kubeconfig", Context: "prod"},
+			{Name: "rc", Kubeconfig: "/tmp/kubeconfig", Context: "rc"},
+		}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := ParseInstallations(tc.value)
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_ParseInstallations_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		value         string
+		expectedError string
+	}{
+		{"no kubeconfig", "prod", `invalid installation "prod": expected format 'name=kubeconfig[#context]'`},
+		{"empty name", "=/tmp/prod.yaml", `invalid installation "=/tmp/prod.yaml": name must not be empty or contain '/' or spaces`},
+		{"name with slash", "a/b=/tmp/prod.yaml", `invalid installation "a/b=/tmp/prod.yaml": name must not be empty or contain '/' or spaces`},
+		{"empty kubeconfig", "prod=#ctx", `invalid installation "prod=#ctx": kubeconfig must not be empty`},
+		{"duplicate name", "prod=/tmp/a.yaml,prod=/tmp/b.yaml", `duplicate installation name "prod"`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result, err := ParseInstallations(tc.value)
+
+			// VERIFY
+			assert.Error(t, err, tc.expectedError)
+			assert.Assert(t, result == nil)
+		})
+	}
+}
+
+func Test_executeOnInstallations(t *testing.T) {
+	// SETUP
+	installations := []Installation{
+		{Name: "prod", Kubeconfig: "/tmp/prod.yaml"},
+		{Name: "rc", Kubeconfig: "/tmp/rc.yaml"},
+	}
+	execute := func(t *testing.T, installation Installation) []TestResult {
+		result := TestResult{Name: "test1", Duration: time.Second}
+		if installation.Name == "rc" {
+			result.Err = errors.New("timeout")
+		}
+		return []TestResult{result}
+	}
+
+	// EXERCISE
+	report := executeOnInstallations(t, installations, execute)
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"test1"}, report.Differences())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -22,6 +23,40 @@ type testRun struct {
 	result   error
 	expected string
 	cleanup  bool
+	results  *testResults
+}
+
+// TestResult is the outcome of a single pipeline run test.
+type TestResult struct {
+	// Name is the name of the test.
+	Name string
+
+	// Duration is the time waited for the check of the test.
+	Duration time.Duration
+
+	// Err is the reason why the test failed or nil if it passed.
+	Err error
+}
+
+// testResults collects the results of concurrently executed tests.
+type testResults struct {
+	mutex   sync.Mutex
+	results []TestResult
+}
+
+func (r *testResults) add(result TestResult) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.results = append(r.results, result)
+}
+
+// list returns the collected results sorted by test name.
+func (r *testResults) list() []TestResult {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	results := append([]TestResult(nil), r.results...)
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results
 }
 
 // ExecutePipelineRunTests execute a set of testPlans
@@ -29,10 +64,13 @@ func ExecutePipelineRunTests(t *testing.T, testPlans ...TestPlan) {
 	executePipelineRunTests(Setup(t), t, testPlans...)
 }
 
-func executePipelineRunTests(ctx context.Context, t *testing.T, testPlans ...TestPlan) {
+// executePipelineRunTests executes the given test plans and returns the
+// results of all tests sorted by name.
+func executePipelineRunTests(ctx context.Context, t *testing.T, testPlans ...TestPlan) []TestResult {
 	rollback, ctx := ensureTenant(ctx, t)
 	defer rollback()
 	tnn := GetTenantNamespace(ctx)
+	results := &testResults{}
 	var waitWG sync.WaitGroup
 	for _, testPlan := range testPlans {
 		waitWG.Add(testPlan.Count)
@@ -58,6 +96,7 @@ func executePipelineRunTests(ctx context.Context, t *testing.T, testPlans ...Tes
 				check:    pipelineTest.Check,
 				expected: pipelineTest.Expected,
 				cleanup:  testPlan.Cleanup,
+				results:  results,
 			}
 			if testPlan.ParallelCreation {
 				go func(waitWG *sync.WaitGroup) {
@@ -74,6 +113,7 @@ func executePipelineRunTests(ctx context.Context, t *testing.T, testPlans ...Tes
 		}
 	}
 	waitWG.Wait()
+	return results.list()
 }
 
 func checkResult(run testRun) error {
@@ -96,6 +136,8 @@ func checkResult(run testRun) error {
 func startWait(t *testing.T, run testRun, waitWG *sync.WaitGroup) {
 	ctx := run.ctx
 	pr := GetPipelineRun(ctx)
+	var duration time.Duration
+	var resultErr error
 	defer func() {
 		if run.cleanup {
 			klog.Infof("Test: %q deleting pipelineRun %q", run.name, pr.GetName())
@@ -104,19 +146,24 @@ func startWait(t *testing.T, run testRun, waitWG *sync.WaitGroup) {
 				klog.Errorf("error happened while cleaning up the pipelineRun %q: %q", run.name, err)
 			}
 		}
+		if run.results != nil {
+			run.results.add(TestResult{Name: run.name, Duration: duration, Err: resultErr})
+		}
 		waitWG.Done()
 	}()
 	if run.result != nil {
-		assert.NilError(t, checkResult(run), "Test: %q", run.name)
+		resultErr = checkResult(run)
+		assert.NilError(t, resultErr, "Test: %q", run.name)
 		return
 	}
 
-	assert.NilError(t, ctx.Err(), "Test: %q", run.name)
+	resultErr = ctx.Err()
+	assert.NilError(t, resultErr, "Test: %q", run.name)
 	PipelineRunCheck := CreatePipelineRunCondition(pr, run.check)
-	duration, err := WaitFor(ctx, PipelineRunCheck)
+	duration, run.result = WaitFor(ctx, PipelineRunCheck)
 	klog.Infof("Test: %q waited for %.2f s", run.name, duration.Seconds())
-	run.result = err
-	assert.NilError(t, checkResult(run), "Test: %q", run.name)
+	resultErr = checkResult(run)
+	assert.NilError(t, resultErr, "Test: %q", run.name)
 }
 
 func createPipelineRunTest(pipelineTest PipelineRunTest, run testRun) testRun {
@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ComparisonReport compares the results of the same tests executed
+// against multiple Steward installations.
+type ComparisonReport struct {
+	mutex         sync.Mutex
+	installations []string
+	// results maps test names to installation names to results
+	results map[string]map[string]TestResult
+}
+
+// NewComparisonReport creates an empty report for the installations with
+// the given names. The installations are listed in the given order.
+func NewComparisonReport(installations []string) *ComparisonReport {
+	return &ComparisonReport{
+		installations: append([]string(nil), installations...),
+		results:       map[string]map[string]TestResult{},
+	}
+}
+
+// Add adds the test results of the given installation to the report.
+// It may be called concurrently.
+func (r *ComparisonReport) Add(installation string, results []TestResult) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, result := range results {
+		byInstallation := r.results[result.Name]
+		if byInstallation == nil {
+			byInstallation = map[string]TestResult{}
+			r.results[result.Name] = byInstallation
+		}
+		byInstallation[installation] = result
+	}
+}
+
+// Differences returns the names of the tests whose outcome differs between
+// the installations, sorted by name. A test without result for some
+// installation counts as different.
+func (r *ComparisonReport) Differences() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var differences []string
+	for _, name := range r.testNames() {
+		outcomes := map[string]bool{}
+		for _, installation := range r.installations {
+			outcomes[r.outcome(name, installation)] = true
+		}
+		if len(outcomes) > 1 {
+			differences = append(differences, name)
+		}
+	}
+	return differences
+}
+
+// String returns the report as Markdown table with a row per test and a
+// column per installation, followed by a summary of the differences.
+func (r *ComparisonReport) String() string {
+	differences := r.Differences()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var b strings.Builder
+	b.WriteString("| Test | " + strings.Join(r.installations, " | ") + " |\n")
+	b.WriteString("|---|" + strings.Repeat("---|", len(r.installations)) + "\n")
+	for _, name := range r.testNames() {
+		cells := make([]string, len(r.installations))
+		for i, installation := range r.installations {
+			cells[i] = r.cell(name, installation)
+		}
+		b.WriteString("| " + markdownCell(name) + " | " + strings.Join(cells, " | ") + " |\n")
+	}
+	b.WriteString("\n")
+	if len(differences) == 0 {
+		fmt.Fprintf(&b, "All %d tests have the same outcome on all installations.\n", len(r.results))
+	} else {
+		fmt.Fprintf(&b, "%d of %d tests have different outcomes: %s\n", len(differences), len(r.results), strings.Join(differences, ", "))
+	}
+	return b.String()
+}
+
+// testNames returns the names of all tests sorted by name.
+// The caller must hold the mutex.
+func (r *ComparisonReport) testNames() []string {
+	names := make([]string, 0, len(r.results))
+	for name := range r.results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// outcome returns `passed`, `failed` or `missing` for the given test and
+// installation. The caller must hold the mutex.
+func (r *ComparisonReport) outcome(name, installation string) string {
+	result, found := r.results[name][installation]
+	switch {
+	case !found:
+		return "missing"
+	case result.Err != nil:
+		return "failed"
+	default:
+		return "passed"
+	}
+}
+
+// cell returns the table cell for the given test and installation.
+// The caller must hold the mutex.
+func (r *ComparisonReport) cell(name, installation string) string {
+	result, found := r.results[name][installation]
+	if !found {
+		return "-"
+	}
+	duration := fmt.Sprintf("%.1fs", result.Duration.Seconds())
+	if result.Err != nil {
+		return "FAILED (" + duration + "): " + markdownCell(result.Err.Error())
+	}
+	return "passed (" + duration + ")"
+}
+
+// markdownCell escapes the given text for a Markdown table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	return strings.ReplaceAll(text, "|", "\\|")
+}
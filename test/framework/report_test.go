@@ -0,0 +1,77 @@
+package framework
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func Test_ComparisonReport_Differences(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	report := NewComparisonReport([]string{"prod", "rc"})
+	report.Add("prod", []TestResult{
+		{Name: "both-passed", Duration: time.Second},
+		{Name: "both-failed", Err: errors.New("error1")},
+		{Name: "regression", Duration: time.Second},
+		{Name: "missing", Duration: time.Second},
+	})
+	report.Add("rc", []TestResult{
+		{Name: "both-passed", Duration: 2 * time.Second},
+		{Name: "both-failed", Err: errors.New("error2")},
+		{Name: "regression", Err: errors.New("error3")},
+	})
+
+	// EXERCISE
+	result := report.Differences()
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"missing", "regression"}, result)
+}
+
+func Test_ComparisonReport_String(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	report := NewComparisonReport([]string{"prod", "rc"})
+	report.Add("rc", []TestResult{
+		{Name: "test2", Duration: 1500 * time.Millisecond, Err: errors.New("line1\nfoo|bar")},
+		{Name: "test1", Duration: 2 * time.Second},
+	})
+	report.Add("prod", []TestResult{
+		{Name: "test1", Duration: time.Second},
+	})
+
+	// EXERCISE
+	result := report.String()
+
+	// VERIFY
+	assert.Equal(t, "| Test | prod | rc |\n"+
+		"|---|---|---|\n"+
+		"| test1 | passed (1.0s) | passed (2.0s) |\n"+
+		"| test2 | - | FAILED (1.5s): line1 foo\\|bar |\n"+
+		"\n"+
+		"1 of 2 tests have different outcomes: test2\n", result)
+}
+
+func Test_ComparisonReport_String_NoDifferences(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	report := NewComparisonReport([]string{"prod", "rc"})
+	report.Add("prod", []TestResult{{Name: "test1", Duration: time.Second}})
+	report.Add("rc", []TestResult{{Name: "test1", Duration: time.Second}})
+
+	// EXERCISE
+	result := report.String()
+
+	// VERIFY
+	assert.Equal(t, "| Test | prod | rc |\n"+
+		"|---|---|---|\n"+
+		"| test1 | passed (1.0s) | passed (1.0s) |\n"+
+		"\n"+
+		"All 1 tests have the same outcome on all installations.\n", result)
+}
@@ -0,0 +1,24 @@
+// +build e2e
+
+package integrationtest
+
+import (
+	"testing"
+
+	f "github.com/SAP/stewardci-core/test/framework"
+	corev1 "k8s.io/api/core/v1"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+)
+
+// Test_PipelineRunIPv6 verifies that pipeline runs can access the
+// internet, i.e. the Jenkinsfile repository, on clusters supporting IPv6.
+// On IPv6-only clusters this requires the network policies to allow
+// IPv6 egress.
+func Test_PipelineRunIPv6(t *testing.T) {
+	t.Parallel()
+	f.RequireIPFamily(f.Setup(t), t, corev1.IPv6Protocol)
+	f.ExecutePipelineRunTests(t, f.TestPlan{
+		TestBuilder: PipelineRunOK,
+		Count:       1,
+	})
+}
@@ -17,5 +17,5 @@ func Test_PipelineRunSingle(t *testing.T) {
 			Count: 1,
 		}
 	}
-	f.ExecutePipelineRunTests(t, allTests...)
+	f.ExecutePipelineRunTestsOnInstallations(t, allTests...)
 }